@@ -11,6 +11,7 @@
 
 import 'dart:core' as $core;
 
+import 'package:fixnum/fixnum.dart' as $fixnum;
 import 'package:protobuf/protobuf.dart' as $pb;
 
 class Empty extends $pb.GeneratedMessage {
@@ -95,6 +96,70 @@ class ProAttachInfo extends $pb.GeneratedMessage {
   void clearToken() => clearField(1);
 }
 
+class DistroProToken extends $pb.GeneratedMessage {
+  factory DistroProToken({
+    $core.String? distroName,
+    $core.String? token,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (token != null) {
+      $result.token = token;
+    }
+    return $result;
+  }
+  DistroProToken._() : super();
+  factory DistroProToken.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroProToken.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroProToken', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(2, _omitFieldNames ? '' : 'token')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroProToken clone() => DistroProToken()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroProToken copyWith(void Function(DistroProToken) updates) => super.copyWith((message) => updates(message as DistroProToken)) as DistroProToken;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroProToken create() => DistroProToken._();
+  DistroProToken createEmptyInstance() => create();
+  static $pb.PbList<DistroProToken> createRepeated() => $pb.PbList<DistroProToken>();
+  @$core.pragma('dart2js:noInline')
+  static DistroProToken getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroProToken>(create);
+  static DistroProToken? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get token => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set token($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasToken() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearToken() => clearField(2);
+}
+
 class LandscapeConfig extends $pb.GeneratedMessage {
   factory LandscapeConfig({
     $core.String? config,
@@ -105,12 +170,3913 @@ class LandscapeConfig extends $pb.GeneratedMessage {
     }
     return $result;
   }
-  LandscapeConfig._() : super();
-  factory LandscapeConfig.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
-  factory LandscapeConfig.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+  LandscapeConfig._() : super();
+  factory LandscapeConfig.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory LandscapeConfig.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'LandscapeConfig', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'config')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  LandscapeConfig clone() => LandscapeConfig()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  LandscapeConfig copyWith(void Function(LandscapeConfig) updates) => super.copyWith((message) => updates(message as LandscapeConfig)) as LandscapeConfig;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static LandscapeConfig create() => LandscapeConfig._();
+  LandscapeConfig createEmptyInstance() => create();
+  static $pb.PbList<LandscapeConfig> createRepeated() => $pb.PbList<LandscapeConfig>();
+  @$core.pragma('dart2js:noInline')
+  static LandscapeConfig getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<LandscapeConfig>(create);
+  static LandscapeConfig? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get config => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set config($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasConfig() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearConfig() => clearField(1);
+}
+
+class FleetStatus extends $pb.GeneratedMessage {
+  factory FleetStatus({
+    $core.Iterable<DistroStatus>? distros,
+    ManagedMode? managedMode,
+    $core.int? securityUpdates,
+    $core.int? esmUpdates,
+    $core.Iterable<PreflightProblem>? preflightProblems,
+    WSLAvailability? wslAvailability,
+  }) {
+    final $result = create();
+    if (distros != null) {
+      $result.distros.addAll(distros);
+    }
+    if (managedMode != null) {
+      $result.managedMode = managedMode;
+    }
+    if (securityUpdates != null) {
+      $result.securityUpdates = securityUpdates;
+    }
+    if (esmUpdates != null) {
+      $result.esmUpdates = esmUpdates;
+    }
+    if (preflightProblems != null) {
+      $result.preflightProblems.addAll(preflightProblems);
+    }
+    if (wslAvailability != null) {
+      $result.wslAvailability = wslAvailability;
+    }
+    return $result;
+  }
+  FleetStatus._() : super();
+  factory FleetStatus.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory FleetStatus.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'FleetStatus', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<DistroStatus>(1, _omitFieldNames ? '' : 'distros', $pb.PbFieldType.PM, subBuilder: DistroStatus.create)
+    ..aOM<ManagedMode>(2, _omitFieldNames ? '' : 'managedMode', protoName: 'managedMode', subBuilder: ManagedMode.create)
+    ..a<$core.int>(3, _omitFieldNames ? '' : 'securityUpdates', $pb.PbFieldType.OU3, protoName: 'securityUpdates')
+    ..a<$core.int>(4, _omitFieldNames ? '' : 'esmUpdates', $pb.PbFieldType.OU3, protoName: 'esmUpdates')
+    ..pc<PreflightProblem>(5, _omitFieldNames ? '' : 'preflightProblems', $pb.PbFieldType.PM, protoName: 'preflightProblems', subBuilder: PreflightProblem.create)
+    ..aOM<WSLAvailability>(6, _omitFieldNames ? '' : 'wslAvailability', protoName: 'wslAvailability', subBuilder: WSLAvailability.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  FleetStatus clone() => FleetStatus()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  FleetStatus copyWith(void Function(FleetStatus) updates) => super.copyWith((message) => updates(message as FleetStatus)) as FleetStatus;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static FleetStatus create() => FleetStatus._();
+  FleetStatus createEmptyInstance() => create();
+  static $pb.PbList<FleetStatus> createRepeated() => $pb.PbList<FleetStatus>();
+  @$core.pragma('dart2js:noInline')
+  static FleetStatus getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<FleetStatus>(create);
+  static FleetStatus? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<DistroStatus> get distros => $_getList(0);
+
+  @$pb.TagNumber(2)
+  ManagedMode get managedMode => $_getN(1);
+  @$pb.TagNumber(2)
+  set managedMode(ManagedMode v) { setField(2, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasManagedMode() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearManagedMode() => clearField(2);
+  @$pb.TagNumber(2)
+  ManagedMode ensureManagedMode() => $_ensure(1);
+
+  @$pb.TagNumber(3)
+  $core.int get securityUpdates => $_getIZ(2);
+  @$pb.TagNumber(3)
+  set securityUpdates($core.int v) { $_setUnsignedInt32(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasSecurityUpdates() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearSecurityUpdates() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.int get esmUpdates => $_getIZ(3);
+  @$pb.TagNumber(4)
+  set esmUpdates($core.int v) { $_setUnsignedInt32(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasEsmUpdates() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearEsmUpdates() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.List<PreflightProblem> get preflightProblems => $_getList(4);
+
+  @$pb.TagNumber(6)
+  WSLAvailability get wslAvailability => $_getN(5);
+  @$pb.TagNumber(6)
+  set wslAvailability(WSLAvailability v) { setField(6, v); }
+  @$pb.TagNumber(6)
+  $core.bool hasWslAvailability() => $_has(5);
+  @$pb.TagNumber(6)
+  void clearWslAvailability() => clearField(6);
+  @$pb.TagNumber(6)
+  WSLAvailability ensureWslAvailability() => $_ensure(5);
+}
+
+class WSLAvailability extends $pb.GeneratedMessage {
+  factory WSLAvailability({
+    $core.bool? available,
+    $core.String? since,
+    $core.String? error,
+  }) {
+    final $result = create();
+    if (available != null) {
+      $result.available = available;
+    }
+    if (since != null) {
+      $result.since = since;
+    }
+    if (error != null) {
+      $result.error = error;
+    }
+    return $result;
+  }
+  WSLAvailability._() : super();
+  factory WSLAvailability.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory WSLAvailability.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'WSLAvailability', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOB(1, _omitFieldNames ? '' : 'available')
+    ..aOS(2, _omitFieldNames ? '' : 'since')
+    ..aOS(3, _omitFieldNames ? '' : 'error')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  WSLAvailability clone() => WSLAvailability()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  WSLAvailability copyWith(void Function(WSLAvailability) updates) => super.copyWith((message) => updates(message as WSLAvailability)) as WSLAvailability;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static WSLAvailability create() => WSLAvailability._();
+  WSLAvailability createEmptyInstance() => create();
+  static $pb.PbList<WSLAvailability> createRepeated() => $pb.PbList<WSLAvailability>();
+  @$core.pragma('dart2js:noInline')
+  static WSLAvailability getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<WSLAvailability>(create);
+  static WSLAvailability? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.bool get available => $_getBF(0);
+  @$pb.TagNumber(1)
+  set available($core.bool v) { $_setBool(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasAvailable() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearAvailable() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get since => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set since($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasSince() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearSince() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get error => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set error($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasError() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearError() => clearField(3);
+}
+
+class PreflightProblem extends $pb.GeneratedMessage {
+  factory PreflightProblem({
+    $core.bool? isError,
+    $core.String? check,
+    $core.String? message,
+    $core.String? action,
+    $core.String? remediation,
+  }) {
+    final $result = create();
+    if (isError != null) {
+      $result.isError = isError;
+    }
+    if (check != null) {
+      $result.check = check;
+    }
+    if (message != null) {
+      $result.message = message;
+    }
+    if (action != null) {
+      $result.action = action;
+    }
+    if (remediation != null) {
+      $result.remediation = remediation;
+    }
+    return $result;
+  }
+  PreflightProblem._() : super();
+  factory PreflightProblem.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory PreflightProblem.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'PreflightProblem', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOB(1, _omitFieldNames ? '' : 'isError', protoName: 'isError')
+    ..aOS(2, _omitFieldNames ? '' : 'check')
+    ..aOS(3, _omitFieldNames ? '' : 'message')
+    ..aOS(4, _omitFieldNames ? '' : 'action')
+    ..aOS(5, _omitFieldNames ? '' : 'remediation')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  PreflightProblem clone() => PreflightProblem()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  PreflightProblem copyWith(void Function(PreflightProblem) updates) => super.copyWith((message) => updates(message as PreflightProblem)) as PreflightProblem;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static PreflightProblem create() => PreflightProblem._();
+  PreflightProblem createEmptyInstance() => create();
+  static $pb.PbList<PreflightProblem> createRepeated() => $pb.PbList<PreflightProblem>();
+  @$core.pragma('dart2js:noInline')
+  static PreflightProblem getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<PreflightProblem>(create);
+  static PreflightProblem? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.bool get isError => $_getBF(0);
+  @$pb.TagNumber(1)
+  set isError($core.bool v) { $_setBool(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasIsError() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearIsError() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get check => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set check($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasCheck() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearCheck() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get message => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set message($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasMessage() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearMessage() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.String get action => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set action($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasAction() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearAction() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.String get remediation => $_getSZ(4);
+  @$pb.TagNumber(5)
+  set remediation($core.String v) { $_setString(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasRemediation() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearRemediation() => clearField(5);
+}
+
+class ManagedMode extends $pb.GeneratedMessage {
+  factory ManagedMode({
+    $core.bool? registryReadOnly,
+    $core.bool? subscription,
+    $core.bool? landscape,
+  }) {
+    final $result = create();
+    if (registryReadOnly != null) {
+      $result.registryReadOnly = registryReadOnly;
+    }
+    if (subscription != null) {
+      $result.subscription = subscription;
+    }
+    if (landscape != null) {
+      $result.landscape = landscape;
+    }
+    return $result;
+  }
+  ManagedMode._() : super();
+  factory ManagedMode.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ManagedMode.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ManagedMode', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOB(1, _omitFieldNames ? '' : 'registryReadOnly', protoName: 'registryReadOnly')
+    ..aOB(2, _omitFieldNames ? '' : 'subscription')
+    ..aOB(3, _omitFieldNames ? '' : 'landscape')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ManagedMode clone() => ManagedMode()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ManagedMode copyWith(void Function(ManagedMode) updates) => super.copyWith((message) => updates(message as ManagedMode)) as ManagedMode;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ManagedMode create() => ManagedMode._();
+  ManagedMode createEmptyInstance() => create();
+  static $pb.PbList<ManagedMode> createRepeated() => $pb.PbList<ManagedMode>();
+  @$core.pragma('dart2js:noInline')
+  static ManagedMode getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ManagedMode>(create);
+  static ManagedMode? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.bool get registryReadOnly => $_getBF(0);
+  @$pb.TagNumber(1)
+  set registryReadOnly($core.bool v) { $_setBool(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasRegistryReadOnly() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearRegistryReadOnly() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get subscription => $_getBF(1);
+  @$pb.TagNumber(2)
+  set subscription($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasSubscription() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearSubscription() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.bool get landscape => $_getBF(2);
+  @$pb.TagNumber(3)
+  set landscape($core.bool v) { $_setBool(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasLandscape() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearLandscape() => clearField(3);
+}
+
+class DistroStatus extends $pb.GeneratedMessage {
+  factory DistroStatus({
+    $core.String? name,
+    $core.bool? proAttached,
+    $core.Iterable<$core.String>? proServices,
+    $core.String? lastContact,
+    $core.int? pendingTasks,
+    $core.bool? landscapeRegistered,
+    $core.bool? connected,
+    $core.int? securityUpdates,
+    $core.int? esmUpdates,
+    $core.String? securityChecked,
+    $fixnum.Int64? memoryBytes,
+    $core.double? cpuPercent,
+    $fixnum.Int64? diskBytes,
+    $core.String? resourcesChecked,
+    $core.bool? needsAttention,
+    $core.String? lastTaskError,
+    $core.String? nextRetry,
+    $core.bool? rebootRequired,
+    $core.Iterable<$core.String>? rebootPackages,
+    $core.int? pendingUpdates,
+    $core.bool? awakeOnDemand,
+    $core.String? awakeUntil,
+    $core.Iterable<Label>? labels,
+    $core.String? serviceVersion,
+    $core.String? landscapeScriptUsers,
+  }) {
+    final $result = create();
+    if (name != null) {
+      $result.name = name;
+    }
+    if (proAttached != null) {
+      $result.proAttached = proAttached;
+    }
+    if (proServices != null) {
+      $result.proServices.addAll(proServices);
+    }
+    if (lastContact != null) {
+      $result.lastContact = lastContact;
+    }
+    if (pendingTasks != null) {
+      $result.pendingTasks = pendingTasks;
+    }
+    if (landscapeRegistered != null) {
+      $result.landscapeRegistered = landscapeRegistered;
+    }
+    if (connected != null) {
+      $result.connected = connected;
+    }
+    if (securityUpdates != null) {
+      $result.securityUpdates = securityUpdates;
+    }
+    if (esmUpdates != null) {
+      $result.esmUpdates = esmUpdates;
+    }
+    if (securityChecked != null) {
+      $result.securityChecked = securityChecked;
+    }
+    if (memoryBytes != null) {
+      $result.memoryBytes = memoryBytes;
+    }
+    if (cpuPercent != null) {
+      $result.cpuPercent = cpuPercent;
+    }
+    if (diskBytes != null) {
+      $result.diskBytes = diskBytes;
+    }
+    if (resourcesChecked != null) {
+      $result.resourcesChecked = resourcesChecked;
+    }
+    if (needsAttention != null) {
+      $result.needsAttention = needsAttention;
+    }
+    if (lastTaskError != null) {
+      $result.lastTaskError = lastTaskError;
+    }
+    if (nextRetry != null) {
+      $result.nextRetry = nextRetry;
+    }
+    if (rebootRequired != null) {
+      $result.rebootRequired = rebootRequired;
+    }
+    if (rebootPackages != null) {
+      $result.rebootPackages.addAll(rebootPackages);
+    }
+    if (pendingUpdates != null) {
+      $result.pendingUpdates = pendingUpdates;
+    }
+    if (awakeOnDemand != null) {
+      $result.awakeOnDemand = awakeOnDemand;
+    }
+    if (awakeUntil != null) {
+      $result.awakeUntil = awakeUntil;
+    }
+    if (labels != null) {
+      $result.labels.addAll(labels);
+    }
+    if (serviceVersion != null) {
+      $result.serviceVersion = serviceVersion;
+    }
+    if (landscapeScriptUsers != null) {
+      $result.landscapeScriptUsers = landscapeScriptUsers;
+    }
+    return $result;
+  }
+  DistroStatus._() : super();
+  factory DistroStatus.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroStatus.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroStatus', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'name')
+    ..aOB(2, _omitFieldNames ? '' : 'proAttached', protoName: 'proAttached')
+    ..pPS(3, _omitFieldNames ? '' : 'proServices', protoName: 'proServices')
+    ..aOS(4, _omitFieldNames ? '' : 'lastContact', protoName: 'lastContact')
+    ..a<$core.int>(5, _omitFieldNames ? '' : 'pendingTasks', $pb.PbFieldType.OU3, protoName: 'pendingTasks')
+    ..aOB(6, _omitFieldNames ? '' : 'landscapeRegistered', protoName: 'landscapeRegistered')
+    ..aOB(7, _omitFieldNames ? '' : 'connected')
+    ..a<$core.int>(8, _omitFieldNames ? '' : 'securityUpdates', $pb.PbFieldType.OU3, protoName: 'securityUpdates')
+    ..a<$core.int>(9, _omitFieldNames ? '' : 'esmUpdates', $pb.PbFieldType.OU3, protoName: 'esmUpdates')
+    ..aOS(10, _omitFieldNames ? '' : 'securityChecked', protoName: 'securityChecked')
+    ..a<$fixnum.Int64>(11, _omitFieldNames ? '' : 'memoryBytes', $pb.PbFieldType.OU6, protoName: 'memoryBytes', defaultOrMaker: $fixnum.Int64.ZERO)
+    ..a<$core.double>(12, _omitFieldNames ? '' : 'cpuPercent', $pb.PbFieldType.OD, protoName: 'cpuPercent')
+    ..a<$fixnum.Int64>(13, _omitFieldNames ? '' : 'diskBytes', $pb.PbFieldType.OU6, protoName: 'diskBytes', defaultOrMaker: $fixnum.Int64.ZERO)
+    ..aOS(14, _omitFieldNames ? '' : 'resourcesChecked', protoName: 'resourcesChecked')
+    ..aOB(15, _omitFieldNames ? '' : 'needsAttention', protoName: 'needsAttention')
+    ..aOS(16, _omitFieldNames ? '' : 'lastTaskError', protoName: 'lastTaskError')
+    ..aOS(17, _omitFieldNames ? '' : 'nextRetry', protoName: 'nextRetry')
+    ..aOB(18, _omitFieldNames ? '' : 'rebootRequired', protoName: 'rebootRequired')
+    ..pPS(19, _omitFieldNames ? '' : 'rebootPackages', protoName: 'rebootPackages')
+    ..a<$core.int>(20, _omitFieldNames ? '' : 'pendingUpdates', $pb.PbFieldType.OU3, protoName: 'pendingUpdates')
+    ..aOB(21, _omitFieldNames ? '' : 'awakeOnDemand', protoName: 'awakeOnDemand')
+    ..aOS(22, _omitFieldNames ? '' : 'awakeUntil', protoName: 'awakeUntil')
+    ..pc<Label>(23, _omitFieldNames ? '' : 'labels', $pb.PbFieldType.PM, subBuilder: Label.create)
+    ..aOS(24, _omitFieldNames ? '' : 'serviceVersion', protoName: 'serviceVersion')
+    ..aOS(25, _omitFieldNames ? '' : 'landscapeScriptUsers', protoName: 'landscapeScriptUsers')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroStatus clone() => DistroStatus()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroStatus copyWith(void Function(DistroStatus) updates) => super.copyWith((message) => updates(message as DistroStatus)) as DistroStatus;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroStatus create() => DistroStatus._();
+  DistroStatus createEmptyInstance() => create();
+  static $pb.PbList<DistroStatus> createRepeated() => $pb.PbList<DistroStatus>();
+  @$core.pragma('dart2js:noInline')
+  static DistroStatus getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroStatus>(create);
+  static DistroStatus? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get name => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set name($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get proAttached => $_getBF(1);
+  @$pb.TagNumber(2)
+  set proAttached($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasProAttached() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearProAttached() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.List<$core.String> get proServices => $_getList(2);
+
+  @$pb.TagNumber(4)
+  $core.String get lastContact => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set lastContact($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasLastContact() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearLastContact() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.int get pendingTasks => $_getIZ(4);
+  @$pb.TagNumber(5)
+  set pendingTasks($core.int v) { $_setUnsignedInt32(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasPendingTasks() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearPendingTasks() => clearField(5);
+
+  @$pb.TagNumber(6)
+  $core.bool get landscapeRegistered => $_getBF(5);
+  @$pb.TagNumber(6)
+  set landscapeRegistered($core.bool v) { $_setBool(5, v); }
+  @$pb.TagNumber(6)
+  $core.bool hasLandscapeRegistered() => $_has(5);
+  @$pb.TagNumber(6)
+  void clearLandscapeRegistered() => clearField(6);
+
+  @$pb.TagNumber(7)
+  $core.bool get connected => $_getBF(6);
+  @$pb.TagNumber(7)
+  set connected($core.bool v) { $_setBool(6, v); }
+  @$pb.TagNumber(7)
+  $core.bool hasConnected() => $_has(6);
+  @$pb.TagNumber(7)
+  void clearConnected() => clearField(7);
+
+  @$pb.TagNumber(8)
+  $core.int get securityUpdates => $_getIZ(7);
+  @$pb.TagNumber(8)
+  set securityUpdates($core.int v) { $_setUnsignedInt32(7, v); }
+  @$pb.TagNumber(8)
+  $core.bool hasSecurityUpdates() => $_has(7);
+  @$pb.TagNumber(8)
+  void clearSecurityUpdates() => clearField(8);
+
+  @$pb.TagNumber(9)
+  $core.int get esmUpdates => $_getIZ(8);
+  @$pb.TagNumber(9)
+  set esmUpdates($core.int v) { $_setUnsignedInt32(8, v); }
+  @$pb.TagNumber(9)
+  $core.bool hasEsmUpdates() => $_has(8);
+  @$pb.TagNumber(9)
+  void clearEsmUpdates() => clearField(9);
+
+  @$pb.TagNumber(10)
+  $core.String get securityChecked => $_getSZ(9);
+  @$pb.TagNumber(10)
+  set securityChecked($core.String v) { $_setString(9, v); }
+  @$pb.TagNumber(10)
+  $core.bool hasSecurityChecked() => $_has(9);
+  @$pb.TagNumber(10)
+  void clearSecurityChecked() => clearField(10);
+
+  @$pb.TagNumber(11)
+  $fixnum.Int64 get memoryBytes => $_getI64(10);
+  @$pb.TagNumber(11)
+  set memoryBytes($fixnum.Int64 v) { $_setInt64(10, v); }
+  @$pb.TagNumber(11)
+  $core.bool hasMemoryBytes() => $_has(10);
+  @$pb.TagNumber(11)
+  void clearMemoryBytes() => clearField(11);
+
+  @$pb.TagNumber(12)
+  $core.double get cpuPercent => $_getN(11);
+  @$pb.TagNumber(12)
+  set cpuPercent($core.double v) { $_setDouble(11, v); }
+  @$pb.TagNumber(12)
+  $core.bool hasCpuPercent() => $_has(11);
+  @$pb.TagNumber(12)
+  void clearCpuPercent() => clearField(12);
+
+  @$pb.TagNumber(13)
+  $fixnum.Int64 get diskBytes => $_getI64(12);
+  @$pb.TagNumber(13)
+  set diskBytes($fixnum.Int64 v) { $_setInt64(12, v); }
+  @$pb.TagNumber(13)
+  $core.bool hasDiskBytes() => $_has(12);
+  @$pb.TagNumber(13)
+  void clearDiskBytes() => clearField(13);
+
+  @$pb.TagNumber(14)
+  $core.String get resourcesChecked => $_getSZ(13);
+  @$pb.TagNumber(14)
+  set resourcesChecked($core.String v) { $_setString(13, v); }
+  @$pb.TagNumber(14)
+  $core.bool hasResourcesChecked() => $_has(13);
+  @$pb.TagNumber(14)
+  void clearResourcesChecked() => clearField(14);
+
+  @$pb.TagNumber(15)
+  $core.bool get needsAttention => $_getBF(14);
+  @$pb.TagNumber(15)
+  set needsAttention($core.bool v) { $_setBool(14, v); }
+  @$pb.TagNumber(15)
+  $core.bool hasNeedsAttention() => $_has(14);
+  @$pb.TagNumber(15)
+  void clearNeedsAttention() => clearField(15);
+
+  @$pb.TagNumber(16)
+  $core.String get lastTaskError => $_getSZ(15);
+  @$pb.TagNumber(16)
+  set lastTaskError($core.String v) { $_setString(15, v); }
+  @$pb.TagNumber(16)
+  $core.bool hasLastTaskError() => $_has(15);
+  @$pb.TagNumber(16)
+  void clearLastTaskError() => clearField(16);
+
+  @$pb.TagNumber(17)
+  $core.String get nextRetry => $_getSZ(16);
+  @$pb.TagNumber(17)
+  set nextRetry($core.String v) { $_setString(16, v); }
+  @$pb.TagNumber(17)
+  $core.bool hasNextRetry() => $_has(16);
+  @$pb.TagNumber(17)
+  void clearNextRetry() => clearField(17);
+
+  @$pb.TagNumber(18)
+  $core.bool get rebootRequired => $_getBF(17);
+  @$pb.TagNumber(18)
+  set rebootRequired($core.bool v) { $_setBool(17, v); }
+  @$pb.TagNumber(18)
+  $core.bool hasRebootRequired() => $_has(17);
+  @$pb.TagNumber(18)
+  void clearRebootRequired() => clearField(18);
+
+  @$pb.TagNumber(19)
+  $core.List<$core.String> get rebootPackages => $_getList(18);
+
+  @$pb.TagNumber(20)
+  $core.int get pendingUpdates => $_getIZ(19);
+  @$pb.TagNumber(20)
+  set pendingUpdates($core.int v) { $_setUnsignedInt32(19, v); }
+  @$pb.TagNumber(20)
+  $core.bool hasPendingUpdates() => $_has(19);
+  @$pb.TagNumber(20)
+  void clearPendingUpdates() => clearField(20);
+
+  @$pb.TagNumber(21)
+  $core.bool get awakeOnDemand => $_getBF(20);
+  @$pb.TagNumber(21)
+  set awakeOnDemand($core.bool v) { $_setBool(20, v); }
+  @$pb.TagNumber(21)
+  $core.bool hasAwakeOnDemand() => $_has(20);
+  @$pb.TagNumber(21)
+  void clearAwakeOnDemand() => clearField(21);
+
+  @$pb.TagNumber(22)
+  $core.String get awakeUntil => $_getSZ(21);
+  @$pb.TagNumber(22)
+  set awakeUntil($core.String v) { $_setString(21, v); }
+  @$pb.TagNumber(22)
+  $core.bool hasAwakeUntil() => $_has(21);
+  @$pb.TagNumber(22)
+  void clearAwakeUntil() => clearField(22);
+
+  @$pb.TagNumber(23)
+  $core.List<Label> get labels => $_getList(22);
+
+  @$pb.TagNumber(24)
+  $core.String get serviceVersion => $_getSZ(23);
+  @$pb.TagNumber(24)
+  set serviceVersion($core.String v) { $_setString(23, v); }
+  @$pb.TagNumber(24)
+  $core.bool hasServiceVersion() => $_has(23);
+  @$pb.TagNumber(24)
+  void clearServiceVersion() => clearField(24);
+
+  @$pb.TagNumber(25)
+  $core.String get landscapeScriptUsers => $_getSZ(24);
+  @$pb.TagNumber(25)
+  set landscapeScriptUsers($core.String v) { $_setString(24, v); }
+  @$pb.TagNumber(25)
+  $core.bool hasLandscapeScriptUsers() => $_has(24);
+  @$pb.TagNumber(25)
+  void clearLandscapeScriptUsers() => clearField(25);
+}
+
+class DistroChange extends $pb.GeneratedMessage {
+  factory DistroChange({
+    $core.String? distroName,
+    $core.String? change,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (change != null) {
+      $result.change = change;
+    }
+    return $result;
+  }
+  DistroChange._() : super();
+  factory DistroChange.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroChange.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroChange', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(2, _omitFieldNames ? '' : 'change')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroChange clone() => DistroChange()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroChange copyWith(void Function(DistroChange) updates) => super.copyWith((message) => updates(message as DistroChange)) as DistroChange;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroChange create() => DistroChange._();
+  DistroChange createEmptyInstance() => create();
+  static $pb.PbList<DistroChange> createRepeated() => $pb.PbList<DistroChange>();
+  @$core.pragma('dart2js:noInline')
+  static DistroChange getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroChange>(create);
+  static DistroChange? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get change => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set change($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasChange() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearChange() => clearField(2);
+}
+
+class ExportRequest extends $pb.GeneratedMessage {
+  factory ExportRequest({
+    $core.String? distroName,
+    $core.String? destination,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (destination != null) {
+      $result.destination = destination;
+    }
+    return $result;
+  }
+  ExportRequest._() : super();
+  factory ExportRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ExportRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ExportRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(2, _omitFieldNames ? '' : 'destination')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ExportRequest clone() => ExportRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ExportRequest copyWith(void Function(ExportRequest) updates) => super.copyWith((message) => updates(message as ExportRequest)) as ExportRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ExportRequest create() => ExportRequest._();
+  ExportRequest createEmptyInstance() => create();
+  static $pb.PbList<ExportRequest> createRepeated() => $pb.PbList<ExportRequest>();
+  @$core.pragma('dart2js:noInline')
+  static ExportRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ExportRequest>(create);
+  static ExportRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get destination => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set destination($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasDestination() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearDestination() => clearField(2);
+}
+
+class ExportProgress extends $pb.GeneratedMessage {
+  factory ExportProgress({
+    $fixnum.Int64? bytesWritten,
+    $core.bool? done,
+    $core.String? path,
+  }) {
+    final $result = create();
+    if (bytesWritten != null) {
+      $result.bytesWritten = bytesWritten;
+    }
+    if (done != null) {
+      $result.done = done;
+    }
+    if (path != null) {
+      $result.path = path;
+    }
+    return $result;
+  }
+  ExportProgress._() : super();
+  factory ExportProgress.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ExportProgress.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ExportProgress', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..a<$fixnum.Int64>(1, _omitFieldNames ? '' : 'bytesWritten', $pb.PbFieldType.OU6, protoName: 'bytesWritten', defaultOrMaker: $fixnum.Int64.ZERO)
+    ..aOB(2, _omitFieldNames ? '' : 'done')
+    ..aOS(3, _omitFieldNames ? '' : 'path')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ExportProgress clone() => ExportProgress()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ExportProgress copyWith(void Function(ExportProgress) updates) => super.copyWith((message) => updates(message as ExportProgress)) as ExportProgress;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ExportProgress create() => ExportProgress._();
+  ExportProgress createEmptyInstance() => create();
+  static $pb.PbList<ExportProgress> createRepeated() => $pb.PbList<ExportProgress>();
+  @$core.pragma('dart2js:noInline')
+  static ExportProgress getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ExportProgress>(create);
+  static ExportProgress? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $fixnum.Int64 get bytesWritten => $_getI64(0);
+  @$pb.TagNumber(1)
+  set bytesWritten($fixnum.Int64 v) { $_setInt64(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasBytesWritten() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearBytesWritten() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get done => $_getBF(1);
+  @$pb.TagNumber(2)
+  set done($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasDone() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearDone() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get path => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set path($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasPath() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearPath() => clearField(3);
+}
+
+class BackupSchedule extends $pb.GeneratedMessage {
+  factory BackupSchedule({
+    $core.int? intervalHours,
+    $core.String? destination,
+    $core.int? keep,
+  }) {
+    final $result = create();
+    if (intervalHours != null) {
+      $result.intervalHours = intervalHours;
+    }
+    if (destination != null) {
+      $result.destination = destination;
+    }
+    if (keep != null) {
+      $result.keep = keep;
+    }
+    return $result;
+  }
+  BackupSchedule._() : super();
+  factory BackupSchedule.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory BackupSchedule.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'BackupSchedule', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..a<$core.int>(1, _omitFieldNames ? '' : 'intervalHours', $pb.PbFieldType.OU3, protoName: 'intervalHours')
+    ..aOS(2, _omitFieldNames ? '' : 'destination')
+    ..a<$core.int>(3, _omitFieldNames ? '' : 'keep', $pb.PbFieldType.OU3)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  BackupSchedule clone() => BackupSchedule()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  BackupSchedule copyWith(void Function(BackupSchedule) updates) => super.copyWith((message) => updates(message as BackupSchedule)) as BackupSchedule;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static BackupSchedule create() => BackupSchedule._();
+  BackupSchedule createEmptyInstance() => create();
+  static $pb.PbList<BackupSchedule> createRepeated() => $pb.PbList<BackupSchedule>();
+  @$core.pragma('dart2js:noInline')
+  static BackupSchedule getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<BackupSchedule>(create);
+  static BackupSchedule? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.int get intervalHours => $_getIZ(0);
+  @$pb.TagNumber(1)
+  set intervalHours($core.int v) { $_setUnsignedInt32(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasIntervalHours() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearIntervalHours() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get destination => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set destination($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasDestination() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearDestination() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.int get keep => $_getIZ(2);
+  @$pb.TagNumber(3)
+  set keep($core.int v) { $_setUnsignedInt32(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasKeep() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearKeep() => clearField(3);
+}
+
+class WakeRequest extends $pb.GeneratedMessage {
+  factory WakeRequest({
+    $core.String? distroName,
+    $core.int? keepAwakeMinutes,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (keepAwakeMinutes != null) {
+      $result.keepAwakeMinutes = keepAwakeMinutes;
+    }
+    return $result;
+  }
+  WakeRequest._() : super();
+  factory WakeRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory WakeRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'WakeRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..a<$core.int>(2, _omitFieldNames ? '' : 'keepAwakeMinutes', $pb.PbFieldType.OU3, protoName: 'keepAwakeMinutes')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  WakeRequest clone() => WakeRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  WakeRequest copyWith(void Function(WakeRequest) updates) => super.copyWith((message) => updates(message as WakeRequest)) as WakeRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static WakeRequest create() => WakeRequest._();
+  WakeRequest createEmptyInstance() => create();
+  static $pb.PbList<WakeRequest> createRepeated() => $pb.PbList<WakeRequest>();
+  @$core.pragma('dart2js:noInline')
+  static WakeRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<WakeRequest>(create);
+  static WakeRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.int get keepAwakeMinutes => $_getIZ(1);
+  @$pb.TagNumber(2)
+  set keepAwakeMinutes($core.int v) { $_setUnsignedInt32(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasKeepAwakeMinutes() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearKeepAwakeMinutes() => clearField(2);
+}
+
+class StopRequest extends $pb.GeneratedMessage {
+  factory StopRequest({
+    $core.String? distroName,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    return $result;
+  }
+  StopRequest._() : super();
+  factory StopRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory StopRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'StopRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  StopRequest clone() => StopRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  StopRequest copyWith(void Function(StopRequest) updates) => super.copyWith((message) => updates(message as StopRequest)) as StopRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static StopRequest create() => StopRequest._();
+  StopRequest createEmptyInstance() => create();
+  static $pb.PbList<StopRequest> createRepeated() => $pb.PbList<StopRequest>();
+  @$core.pragma('dart2js:noInline')
+  static StopRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<StopRequest>(create);
+  static StopRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+}
+
+class DistroLabels extends $pb.GeneratedMessage {
+  factory DistroLabels({
+    $core.String? distroName,
+    $core.Iterable<Label>? labels,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (labels != null) {
+      $result.labels.addAll(labels);
+    }
+    return $result;
+  }
+  DistroLabels._() : super();
+  factory DistroLabels.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroLabels.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroLabels', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..pc<Label>(2, _omitFieldNames ? '' : 'labels', $pb.PbFieldType.PM, subBuilder: Label.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroLabels clone() => DistroLabels()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroLabels copyWith(void Function(DistroLabels) updates) => super.copyWith((message) => updates(message as DistroLabels)) as DistroLabels;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroLabels create() => DistroLabels._();
+  DistroLabels createEmptyInstance() => create();
+  static $pb.PbList<DistroLabels> createRepeated() => $pb.PbList<DistroLabels>();
+  @$core.pragma('dart2js:noInline')
+  static DistroLabels getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroLabels>(create);
+  static DistroLabels? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.List<Label> get labels => $_getList(1);
+}
+
+class Label extends $pb.GeneratedMessage {
+  factory Label({
+    $core.String? key,
+    $core.String? value,
+  }) {
+    final $result = create();
+    if (key != null) {
+      $result.key = key;
+    }
+    if (value != null) {
+      $result.value = value;
+    }
+    return $result;
+  }
+  Label._() : super();
+  factory Label.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory Label.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'Label', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'key')
+    ..aOS(2, _omitFieldNames ? '' : 'value')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  Label clone() => Label()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  Label copyWith(void Function(Label) updates) => super.copyWith((message) => updates(message as Label)) as Label;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static Label create() => Label._();
+  Label createEmptyInstance() => create();
+  static $pb.PbList<Label> createRepeated() => $pb.PbList<Label>();
+  @$core.pragma('dart2js:noInline')
+  static Label getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<Label>(create);
+  static Label? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get key => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set key($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasKey() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearKey() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get value => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set value($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasValue() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearValue() => clearField(2);
+}
+
+class CompactRequest extends $pb.GeneratedMessage {
+  factory CompactRequest({
+    $core.String? distroName,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    return $result;
+  }
+  CompactRequest._() : super();
+  factory CompactRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory CompactRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'CompactRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  CompactRequest clone() => CompactRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  CompactRequest copyWith(void Function(CompactRequest) updates) => super.copyWith((message) => updates(message as CompactRequest)) as CompactRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static CompactRequest create() => CompactRequest._();
+  CompactRequest createEmptyInstance() => create();
+  static $pb.PbList<CompactRequest> createRepeated() => $pb.PbList<CompactRequest>();
+  @$core.pragma('dart2js:noInline')
+  static CompactRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<CompactRequest>(create);
+  static CompactRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+}
+
+class CompactResult extends $pb.GeneratedMessage {
+  factory CompactResult({
+    $fixnum.Int64? sizeBefore,
+    $fixnum.Int64? sizeAfter,
+  }) {
+    final $result = create();
+    if (sizeBefore != null) {
+      $result.sizeBefore = sizeBefore;
+    }
+    if (sizeAfter != null) {
+      $result.sizeAfter = sizeAfter;
+    }
+    return $result;
+  }
+  CompactResult._() : super();
+  factory CompactResult.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory CompactResult.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'CompactResult', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..a<$fixnum.Int64>(1, _omitFieldNames ? '' : 'sizeBefore', $pb.PbFieldType.OU6, protoName: 'sizeBefore', defaultOrMaker: $fixnum.Int64.ZERO)
+    ..a<$fixnum.Int64>(2, _omitFieldNames ? '' : 'sizeAfter', $pb.PbFieldType.OU6, protoName: 'sizeAfter', defaultOrMaker: $fixnum.Int64.ZERO)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  CompactResult clone() => CompactResult()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  CompactResult copyWith(void Function(CompactResult) updates) => super.copyWith((message) => updates(message as CompactResult)) as CompactResult;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static CompactResult create() => CompactResult._();
+  CompactResult createEmptyInstance() => create();
+  static $pb.PbList<CompactResult> createRepeated() => $pb.PbList<CompactResult>();
+  @$core.pragma('dart2js:noInline')
+  static CompactResult getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<CompactResult>(create);
+  static CompactResult? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $fixnum.Int64 get sizeBefore => $_getI64(0);
+  @$pb.TagNumber(1)
+  set sizeBefore($fixnum.Int64 v) { $_setInt64(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasSizeBefore() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearSizeBefore() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $fixnum.Int64 get sizeAfter => $_getI64(1);
+  @$pb.TagNumber(2)
+  set sizeAfter($fixnum.Int64 v) { $_setInt64(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasSizeAfter() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearSizeAfter() => clearField(2);
+}
+
+class SparseRequest extends $pb.GeneratedMessage {
+  factory SparseRequest({
+    $core.String? distroName,
+    $core.bool? sparse,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (sparse != null) {
+      $result.sparse = sparse;
+    }
+    return $result;
+  }
+  SparseRequest._() : super();
+  factory SparseRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SparseRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SparseRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOB(2, _omitFieldNames ? '' : 'sparse')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SparseRequest clone() => SparseRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SparseRequest copyWith(void Function(SparseRequest) updates) => super.copyWith((message) => updates(message as SparseRequest)) as SparseRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SparseRequest create() => SparseRequest._();
+  SparseRequest createEmptyInstance() => create();
+  static $pb.PbList<SparseRequest> createRepeated() => $pb.PbList<SparseRequest>();
+  @$core.pragma('dart2js:noInline')
+  static SparseRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SparseRequest>(create);
+  static SparseRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get sparse => $_getBF(1);
+  @$pb.TagNumber(2)
+  set sparse($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasSparse() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearSparse() => clearField(2);
+}
+
+class CompactSchedule extends $pb.GeneratedMessage {
+  factory CompactSchedule({
+    $core.int? intervalHours,
+  }) {
+    final $result = create();
+    if (intervalHours != null) {
+      $result.intervalHours = intervalHours;
+    }
+    return $result;
+  }
+  CompactSchedule._() : super();
+  factory CompactSchedule.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory CompactSchedule.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'CompactSchedule', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..a<$core.int>(1, _omitFieldNames ? '' : 'intervalHours', $pb.PbFieldType.OU3, protoName: 'intervalHours')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  CompactSchedule clone() => CompactSchedule()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  CompactSchedule copyWith(void Function(CompactSchedule) updates) => super.copyWith((message) => updates(message as CompactSchedule)) as CompactSchedule;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static CompactSchedule create() => CompactSchedule._();
+  CompactSchedule createEmptyInstance() => create();
+  static $pb.PbList<CompactSchedule> createRepeated() => $pb.PbList<CompactSchedule>();
+  @$core.pragma('dart2js:noInline')
+  static CompactSchedule getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<CompactSchedule>(create);
+  static CompactSchedule? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.int get intervalHours => $_getIZ(0);
+  @$pb.TagNumber(1)
+  set intervalHours($core.int v) { $_setUnsignedInt32(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasIntervalHours() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearIntervalHours() => clearField(1);
+}
+
+class WSLConfig extends $pb.GeneratedMessage {
+  factory WSLConfig({
+    $core.String? memory,
+    $core.int? processors,
+    $core.String? networkingMode,
+    $core.bool? restartRequired,
+  }) {
+    final $result = create();
+    if (memory != null) {
+      $result.memory = memory;
+    }
+    if (processors != null) {
+      $result.processors = processors;
+    }
+    if (networkingMode != null) {
+      $result.networkingMode = networkingMode;
+    }
+    if (restartRequired != null) {
+      $result.restartRequired = restartRequired;
+    }
+    return $result;
+  }
+  WSLConfig._() : super();
+  factory WSLConfig.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory WSLConfig.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'WSLConfig', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'memory')
+    ..a<$core.int>(2, _omitFieldNames ? '' : 'processors', $pb.PbFieldType.OU3)
+    ..aOS(3, _omitFieldNames ? '' : 'networkingMode', protoName: 'networkingMode')
+    ..aOB(4, _omitFieldNames ? '' : 'restartRequired', protoName: 'restartRequired')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  WSLConfig clone() => WSLConfig()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  WSLConfig copyWith(void Function(WSLConfig) updates) => super.copyWith((message) => updates(message as WSLConfig)) as WSLConfig;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static WSLConfig create() => WSLConfig._();
+  WSLConfig createEmptyInstance() => create();
+  static $pb.PbList<WSLConfig> createRepeated() => $pb.PbList<WSLConfig>();
+  @$core.pragma('dart2js:noInline')
+  static WSLConfig getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<WSLConfig>(create);
+  static WSLConfig? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get memory => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set memory($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasMemory() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearMemory() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.int get processors => $_getIZ(1);
+  @$pb.TagNumber(2)
+  set processors($core.int v) { $_setUnsignedInt32(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasProcessors() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearProcessors() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get networkingMode => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set networkingMode($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasNetworkingMode() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearNetworkingMode() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.bool get restartRequired => $_getBF(3);
+  @$pb.TagNumber(4)
+  set restartRequired($core.bool v) { $_setBool(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasRestartRequired() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearRestartRequired() => clearField(4);
+}
+
+class ResetRequest extends $pb.GeneratedMessage {
+  factory ResetRequest({
+    $core.String? distroName,
+    $core.bool? keepProperties,
+    $core.String? rootfs,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (keepProperties != null) {
+      $result.keepProperties = keepProperties;
+    }
+    if (rootfs != null) {
+      $result.rootfs = rootfs;
+    }
+    return $result;
+  }
+  ResetRequest._() : super();
+  factory ResetRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ResetRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ResetRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOB(2, _omitFieldNames ? '' : 'keepProperties', protoName: 'keepProperties')
+    ..aOS(3, _omitFieldNames ? '' : 'rootfs')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ResetRequest clone() => ResetRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ResetRequest copyWith(void Function(ResetRequest) updates) => super.copyWith((message) => updates(message as ResetRequest)) as ResetRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ResetRequest create() => ResetRequest._();
+  ResetRequest createEmptyInstance() => create();
+  static $pb.PbList<ResetRequest> createRepeated() => $pb.PbList<ResetRequest>();
+  @$core.pragma('dart2js:noInline')
+  static ResetRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ResetRequest>(create);
+  static ResetRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get keepProperties => $_getBF(1);
+  @$pb.TagNumber(2)
+  set keepProperties($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasKeepProperties() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearKeepProperties() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get rootfs => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set rootfs($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasRootfs() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearRootfs() => clearField(3);
+}
+
+class ReloadResult extends $pb.GeneratedMessage {
+  factory ReloadResult({
+    $core.Iterable<ReloadedSetting>? settings,
+  }) {
+    final $result = create();
+    if (settings != null) {
+      $result.settings.addAll(settings);
+    }
+    return $result;
+  }
+  ReloadResult._() : super();
+  factory ReloadResult.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ReloadResult.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ReloadResult', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<ReloadedSetting>(1, _omitFieldNames ? '' : 'settings', $pb.PbFieldType.PM, subBuilder: ReloadedSetting.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ReloadResult clone() => ReloadResult()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ReloadResult copyWith(void Function(ReloadResult) updates) => super.copyWith((message) => updates(message as ReloadResult)) as ReloadResult;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ReloadResult create() => ReloadResult._();
+  ReloadResult createEmptyInstance() => create();
+  static $pb.PbList<ReloadResult> createRepeated() => $pb.PbList<ReloadResult>();
+  @$core.pragma('dart2js:noInline')
+  static ReloadResult getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ReloadResult>(create);
+  static ReloadResult? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<ReloadedSetting> get settings => $_getList(0);
+}
+
+class ReloadedSetting extends $pb.GeneratedMessage {
+  factory ReloadedSetting({
+    $core.String? name,
+    $core.bool? restartRequired,
+    $core.String? error,
+  }) {
+    final $result = create();
+    if (name != null) {
+      $result.name = name;
+    }
+    if (restartRequired != null) {
+      $result.restartRequired = restartRequired;
+    }
+    if (error != null) {
+      $result.error = error;
+    }
+    return $result;
+  }
+  ReloadedSetting._() : super();
+  factory ReloadedSetting.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ReloadedSetting.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ReloadedSetting', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'name')
+    ..aOB(2, _omitFieldNames ? '' : 'restartRequired', protoName: 'restartRequired')
+    ..aOS(3, _omitFieldNames ? '' : 'error')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ReloadedSetting clone() => ReloadedSetting()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ReloadedSetting copyWith(void Function(ReloadedSetting) updates) => super.copyWith((message) => updates(message as ReloadedSetting)) as ReloadedSetting;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ReloadedSetting create() => ReloadedSetting._();
+  ReloadedSetting createEmptyInstance() => create();
+  static $pb.PbList<ReloadedSetting> createRepeated() => $pb.PbList<ReloadedSetting>();
+  @$core.pragma('dart2js:noInline')
+  static ReloadedSetting getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ReloadedSetting>(create);
+  static ReloadedSetting? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get name => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set name($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get restartRequired => $_getBF(1);
+  @$pb.TagNumber(2)
+  set restartRequired($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasRestartRequired() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearRestartRequired() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get error => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set error($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasError() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearError() => clearField(3);
+}
+
+class DistroLogsRequest extends $pb.GeneratedMessage {
+  factory DistroLogsRequest({
+    $core.String? distroName,
+    $core.int? lines,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (lines != null) {
+      $result.lines = lines;
+    }
+    return $result;
+  }
+  DistroLogsRequest._() : super();
+  factory DistroLogsRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroLogsRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroLogsRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..a<$core.int>(2, _omitFieldNames ? '' : 'lines', $pb.PbFieldType.OU3)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroLogsRequest clone() => DistroLogsRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroLogsRequest copyWith(void Function(DistroLogsRequest) updates) => super.copyWith((message) => updates(message as DistroLogsRequest)) as DistroLogsRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroLogsRequest create() => DistroLogsRequest._();
+  DistroLogsRequest createEmptyInstance() => create();
+  static $pb.PbList<DistroLogsRequest> createRepeated() => $pb.PbList<DistroLogsRequest>();
+  @$core.pragma('dart2js:noInline')
+  static DistroLogsRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroLogsRequest>(create);
+  static DistroLogsRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.int get lines => $_getIZ(1);
+  @$pb.TagNumber(2)
+  set lines($core.int v) { $_setUnsignedInt32(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasLines() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearLines() => clearField(2);
+}
+
+class DistroLogs extends $pb.GeneratedMessage {
+  factory DistroLogs({
+    $core.Iterable<LogEntry>? entries,
+  }) {
+    final $result = create();
+    if (entries != null) {
+      $result.entries.addAll(entries);
+    }
+    return $result;
+  }
+  DistroLogs._() : super();
+  factory DistroLogs.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroLogs.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroLogs', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<LogEntry>(1, _omitFieldNames ? '' : 'entries', $pb.PbFieldType.PM, subBuilder: LogEntry.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroLogs clone() => DistroLogs()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroLogs copyWith(void Function(DistroLogs) updates) => super.copyWith((message) => updates(message as DistroLogs)) as DistroLogs;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroLogs create() => DistroLogs._();
+  DistroLogs createEmptyInstance() => create();
+  static $pb.PbList<DistroLogs> createRepeated() => $pb.PbList<DistroLogs>();
+  @$core.pragma('dart2js:noInline')
+  static DistroLogs getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroLogs>(create);
+  static DistroLogs? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<LogEntry> get entries => $_getList(0);
+}
+
+class LogEntry extends $pb.GeneratedMessage {
+  factory LogEntry({
+    $core.String? time,
+    $core.int? priority,
+    $core.String? message,
+  }) {
+    final $result = create();
+    if (time != null) {
+      $result.time = time;
+    }
+    if (priority != null) {
+      $result.priority = priority;
+    }
+    if (message != null) {
+      $result.message = message;
+    }
+    return $result;
+  }
+  LogEntry._() : super();
+  factory LogEntry.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory LogEntry.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'LogEntry', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'time')
+    ..a<$core.int>(2, _omitFieldNames ? '' : 'priority', $pb.PbFieldType.OU3)
+    ..aOS(3, _omitFieldNames ? '' : 'message')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  LogEntry clone() => LogEntry()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  LogEntry copyWith(void Function(LogEntry) updates) => super.copyWith((message) => updates(message as LogEntry)) as LogEntry;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static LogEntry create() => LogEntry._();
+  LogEntry createEmptyInstance() => create();
+  static $pb.PbList<LogEntry> createRepeated() => $pb.PbList<LogEntry>();
+  @$core.pragma('dart2js:noInline')
+  static LogEntry getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<LogEntry>(create);
+  static LogEntry? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get time => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set time($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasTime() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearTime() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.int get priority => $_getIZ(1);
+  @$pb.TagNumber(2)
+  set priority($core.int v) { $_setUnsignedInt32(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasPriority() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearPriority() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get message => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set message($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasMessage() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearMessage() => clearField(3);
+}
+
+class DistroFileRequest extends $pb.GeneratedMessage {
+  factory DistroFileRequest({
+    $core.String? distroName,
+    $core.String? path,
+    $core.String? destination,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (path != null) {
+      $result.path = path;
+    }
+    if (destination != null) {
+      $result.destination = destination;
+    }
+    return $result;
+  }
+  DistroFileRequest._() : super();
+  factory DistroFileRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroFileRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroFileRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(2, _omitFieldNames ? '' : 'path')
+    ..aOS(3, _omitFieldNames ? '' : 'destination')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroFileRequest clone() => DistroFileRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroFileRequest copyWith(void Function(DistroFileRequest) updates) => super.copyWith((message) => updates(message as DistroFileRequest)) as DistroFileRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroFileRequest create() => DistroFileRequest._();
+  DistroFileRequest createEmptyInstance() => create();
+  static $pb.PbList<DistroFileRequest> createRepeated() => $pb.PbList<DistroFileRequest>();
+  @$core.pragma('dart2js:noInline')
+  static DistroFileRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroFileRequest>(create);
+  static DistroFileRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get path => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set path($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasPath() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearPath() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get destination => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set destination($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasDestination() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearDestination() => clearField(3);
+}
+
+class DistroFile extends $pb.GeneratedMessage {
+  factory DistroFile({
+    $core.String? destination,
+    $fixnum.Int64? size,
+    $core.String? sha256,
+    $core.bool? redacted,
+  }) {
+    final $result = create();
+    if (destination != null) {
+      $result.destination = destination;
+    }
+    if (size != null) {
+      $result.size = size;
+    }
+    if (sha256 != null) {
+      $result.sha256 = sha256;
+    }
+    if (redacted != null) {
+      $result.redacted = redacted;
+    }
+    return $result;
+  }
+  DistroFile._() : super();
+  factory DistroFile.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroFile.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroFile', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'destination')
+    ..a<$fixnum.Int64>(2, _omitFieldNames ? '' : 'size', $pb.PbFieldType.OU6, defaultOrMaker: $fixnum.Int64.ZERO)
+    ..aOS(3, _omitFieldNames ? '' : 'sha256')
+    ..aOB(4, _omitFieldNames ? '' : 'redacted')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroFile clone() => DistroFile()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroFile copyWith(void Function(DistroFile) updates) => super.copyWith((message) => updates(message as DistroFile)) as DistroFile;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroFile create() => DistroFile._();
+  DistroFile createEmptyInstance() => create();
+  static $pb.PbList<DistroFile> createRepeated() => $pb.PbList<DistroFile>();
+  @$core.pragma('dart2js:noInline')
+  static DistroFile getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroFile>(create);
+  static DistroFile? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get destination => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set destination($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDestination() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDestination() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $fixnum.Int64 get size => $_getI64(1);
+  @$pb.TagNumber(2)
+  set size($fixnum.Int64 v) { $_setInt64(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasSize() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearSize() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get sha256 => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set sha256($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasSha256() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearSha256() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.bool get redacted => $_getBF(3);
+  @$pb.TagNumber(4)
+  set redacted($core.bool v) { $_setBool(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasRedacted() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearRedacted() => clearField(4);
+}
+
+class DistroTasksRequest extends $pb.GeneratedMessage {
+  factory DistroTasksRequest({
+    $core.String? distroName,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    return $result;
+  }
+  DistroTasksRequest._() : super();
+  factory DistroTasksRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroTasksRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroTasksRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroTasksRequest clone() => DistroTasksRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroTasksRequest copyWith(void Function(DistroTasksRequest) updates) => super.copyWith((message) => updates(message as DistroTasksRequest)) as DistroTasksRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroTasksRequest create() => DistroTasksRequest._();
+  DistroTasksRequest createEmptyInstance() => create();
+  static $pb.PbList<DistroTasksRequest> createRepeated() => $pb.PbList<DistroTasksRequest>();
+  @$core.pragma('dart2js:noInline')
+  static DistroTasksRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroTasksRequest>(create);
+  static DistroTasksRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+}
+
+class DistroTasks extends $pb.GeneratedMessage {
+  factory DistroTasks({
+    $core.Iterable<QueuedTask>? tasks,
+  }) {
+    final $result = create();
+    if (tasks != null) {
+      $result.tasks.addAll(tasks);
+    }
+    return $result;
+  }
+  DistroTasks._() : super();
+  factory DistroTasks.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroTasks.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroTasks', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<QueuedTask>(1, _omitFieldNames ? '' : 'tasks', $pb.PbFieldType.PM, subBuilder: QueuedTask.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroTasks clone() => DistroTasks()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroTasks copyWith(void Function(DistroTasks) updates) => super.copyWith((message) => updates(message as DistroTasks)) as DistroTasks;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroTasks create() => DistroTasks._();
+  DistroTasks createEmptyInstance() => create();
+  static $pb.PbList<DistroTasks> createRepeated() => $pb.PbList<DistroTasks>();
+  @$core.pragma('dart2js:noInline')
+  static DistroTasks getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroTasks>(create);
+  static DistroTasks? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<QueuedTask> get tasks => $_getList(0);
+}
+
+class QueuedTask extends $pb.GeneratedMessage {
+  factory QueuedTask({
+    $core.String? id,
+    $core.String? task,
+    $core.bool? deferred,
+    $core.String? submitted,
+    $core.int? attempts,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    if (task != null) {
+      $result.task = task;
+    }
+    if (deferred != null) {
+      $result.deferred = deferred;
+    }
+    if (submitted != null) {
+      $result.submitted = submitted;
+    }
+    if (attempts != null) {
+      $result.attempts = attempts;
+    }
+    return $result;
+  }
+  QueuedTask._() : super();
+  factory QueuedTask.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory QueuedTask.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'QueuedTask', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..aOS(2, _omitFieldNames ? '' : 'task')
+    ..aOB(3, _omitFieldNames ? '' : 'deferred')
+    ..aOS(4, _omitFieldNames ? '' : 'submitted')
+    ..a<$core.int>(5, _omitFieldNames ? '' : 'attempts', $pb.PbFieldType.OU3)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  QueuedTask clone() => QueuedTask()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  QueuedTask copyWith(void Function(QueuedTask) updates) => super.copyWith((message) => updates(message as QueuedTask)) as QueuedTask;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static QueuedTask create() => QueuedTask._();
+  QueuedTask createEmptyInstance() => create();
+  static $pb.PbList<QueuedTask> createRepeated() => $pb.PbList<QueuedTask>();
+  @$core.pragma('dart2js:noInline')
+  static QueuedTask getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<QueuedTask>(create);
+  static QueuedTask? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get task => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set task($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasTask() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearTask() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.bool get deferred => $_getBF(2);
+  @$pb.TagNumber(3)
+  set deferred($core.bool v) { $_setBool(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasDeferred() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearDeferred() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.String get submitted => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set submitted($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasSubmitted() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearSubmitted() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.int get attempts => $_getIZ(4);
+  @$pb.TagNumber(5)
+  set attempts($core.int v) { $_setUnsignedInt32(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasAttempts() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearAttempts() => clearField(5);
+}
+
+class CancelTaskRequest extends $pb.GeneratedMessage {
+  factory CancelTaskRequest({
+    $core.String? distroName,
+    $core.String? id,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (id != null) {
+      $result.id = id;
+    }
+    return $result;
+  }
+  CancelTaskRequest._() : super();
+  factory CancelTaskRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory CancelTaskRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'CancelTaskRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(2, _omitFieldNames ? '' : 'id')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  CancelTaskRequest clone() => CancelTaskRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  CancelTaskRequest copyWith(void Function(CancelTaskRequest) updates) => super.copyWith((message) => updates(message as CancelTaskRequest)) as CancelTaskRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static CancelTaskRequest create() => CancelTaskRequest._();
+  CancelTaskRequest createEmptyInstance() => create();
+  static $pb.PbList<CancelTaskRequest> createRepeated() => $pb.PbList<CancelTaskRequest>();
+  @$core.pragma('dart2js:noInline')
+  static CancelTaskRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<CancelTaskRequest>(create);
+  static CancelTaskRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get id => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set id($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasId() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearId() => clearField(2);
+}
+
+enum OperationRequest_Request {
+  exportDistro, 
+  resetDistro, 
+  notSet
+}
+
+class OperationRequest extends $pb.GeneratedMessage {
+  factory OperationRequest({
+    ExportRequest? exportDistro,
+    ResetRequest? resetDistro,
+  }) {
+    final $result = create();
+    if (exportDistro != null) {
+      $result.exportDistro = exportDistro;
+    }
+    if (resetDistro != null) {
+      $result.resetDistro = resetDistro;
+    }
+    return $result;
+  }
+  OperationRequest._() : super();
+  factory OperationRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory OperationRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static const $core.Map<$core.int, OperationRequest_Request> _OperationRequest_RequestByTag = {
+    1 : OperationRequest_Request.exportDistro,
+    2 : OperationRequest_Request.resetDistro,
+    0 : OperationRequest_Request.notSet
+  };
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'OperationRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..oo(0, [1, 2])
+    ..aOM<ExportRequest>(1, _omitFieldNames ? '' : 'exportDistro', protoName: 'exportDistro', subBuilder: ExportRequest.create)
+    ..aOM<ResetRequest>(2, _omitFieldNames ? '' : 'resetDistro', protoName: 'resetDistro', subBuilder: ResetRequest.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  OperationRequest clone() => OperationRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  OperationRequest copyWith(void Function(OperationRequest) updates) => super.copyWith((message) => updates(message as OperationRequest)) as OperationRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static OperationRequest create() => OperationRequest._();
+  OperationRequest createEmptyInstance() => create();
+  static $pb.PbList<OperationRequest> createRepeated() => $pb.PbList<OperationRequest>();
+  @$core.pragma('dart2js:noInline')
+  static OperationRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<OperationRequest>(create);
+  static OperationRequest? _defaultInstance;
+
+  OperationRequest_Request whichRequest() => _OperationRequest_RequestByTag[$_whichOneof(0)]!;
+  void clearRequest() => clearField($_whichOneof(0));
+
+  @$pb.TagNumber(1)
+  ExportRequest get exportDistro => $_getN(0);
+  @$pb.TagNumber(1)
+  set exportDistro(ExportRequest v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasExportDistro() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearExportDistro() => clearField(1);
+  @$pb.TagNumber(1)
+  ExportRequest ensureExportDistro() => $_ensure(0);
+
+  @$pb.TagNumber(2)
+  ResetRequest get resetDistro => $_getN(1);
+  @$pb.TagNumber(2)
+  set resetDistro(ResetRequest v) { setField(2, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasResetDistro() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearResetDistro() => clearField(2);
+  @$pb.TagNumber(2)
+  ResetRequest ensureResetDistro() => $_ensure(1);
+}
+
+class OperationID extends $pb.GeneratedMessage {
+  factory OperationID({
+    $core.String? id,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    return $result;
+  }
+  OperationID._() : super();
+  factory OperationID.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory OperationID.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'OperationID', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  OperationID clone() => OperationID()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  OperationID copyWith(void Function(OperationID) updates) => super.copyWith((message) => updates(message as OperationID)) as OperationID;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static OperationID create() => OperationID._();
+  OperationID createEmptyInstance() => create();
+  static $pb.PbList<OperationID> createRepeated() => $pb.PbList<OperationID>();
+  @$core.pragma('dart2js:noInline')
+  static OperationID getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<OperationID>(create);
+  static OperationID? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+}
+
+class Operation extends $pb.GeneratedMessage {
+  factory Operation({
+    $core.String? id,
+    $core.String? kind,
+    $core.String? distroName,
+    $core.String? state,
+    $core.String? error,
+    $fixnum.Int64? progress,
+    $core.String? result,
+    $core.String? started,
+    $core.String? finished,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    if (kind != null) {
+      $result.kind = kind;
+    }
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (state != null) {
+      $result.state = state;
+    }
+    if (error != null) {
+      $result.error = error;
+    }
+    if (progress != null) {
+      $result.progress = progress;
+    }
+    if (result != null) {
+      $result.result = result;
+    }
+    if (started != null) {
+      $result.started = started;
+    }
+    if (finished != null) {
+      $result.finished = finished;
+    }
+    return $result;
+  }
+  Operation._() : super();
+  factory Operation.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory Operation.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'Operation', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..aOS(2, _omitFieldNames ? '' : 'kind')
+    ..aOS(3, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(4, _omitFieldNames ? '' : 'state')
+    ..aOS(5, _omitFieldNames ? '' : 'error')
+    ..a<$fixnum.Int64>(6, _omitFieldNames ? '' : 'progress', $pb.PbFieldType.OU6, defaultOrMaker: $fixnum.Int64.ZERO)
+    ..aOS(7, _omitFieldNames ? '' : 'result')
+    ..aOS(8, _omitFieldNames ? '' : 'started')
+    ..aOS(9, _omitFieldNames ? '' : 'finished')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  Operation clone() => Operation()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  Operation copyWith(void Function(Operation) updates) => super.copyWith((message) => updates(message as Operation)) as Operation;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static Operation create() => Operation._();
+  Operation createEmptyInstance() => create();
+  static $pb.PbList<Operation> createRepeated() => $pb.PbList<Operation>();
+  @$core.pragma('dart2js:noInline')
+  static Operation getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<Operation>(create);
+  static Operation? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get kind => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set kind($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasKind() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearKind() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get distroName => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set distroName($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasDistroName() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearDistroName() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.String get state => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set state($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasState() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearState() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.String get error => $_getSZ(4);
+  @$pb.TagNumber(5)
+  set error($core.String v) { $_setString(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasError() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearError() => clearField(5);
+
+  @$pb.TagNumber(6)
+  $fixnum.Int64 get progress => $_getI64(5);
+  @$pb.TagNumber(6)
+  set progress($fixnum.Int64 v) { $_setInt64(5, v); }
+  @$pb.TagNumber(6)
+  $core.bool hasProgress() => $_has(5);
+  @$pb.TagNumber(6)
+  void clearProgress() => clearField(6);
+
+  @$pb.TagNumber(7)
+  $core.String get result => $_getSZ(6);
+  @$pb.TagNumber(7)
+  set result($core.String v) { $_setString(6, v); }
+  @$pb.TagNumber(7)
+  $core.bool hasResult() => $_has(6);
+  @$pb.TagNumber(7)
+  void clearResult() => clearField(7);
+
+  @$pb.TagNumber(8)
+  $core.String get started => $_getSZ(7);
+  @$pb.TagNumber(8)
+  set started($core.String v) { $_setString(7, v); }
+  @$pb.TagNumber(8)
+  $core.bool hasStarted() => $_has(7);
+  @$pb.TagNumber(8)
+  void clearStarted() => clearField(8);
+
+  @$pb.TagNumber(9)
+  $core.String get finished => $_getSZ(8);
+  @$pb.TagNumber(9)
+  set finished($core.String v) { $_setString(8, v); }
+  @$pb.TagNumber(9)
+  $core.bool hasFinished() => $_has(8);
+  @$pb.TagNumber(9)
+  void clearFinished() => clearField(9);
+}
+
+class Operations extends $pb.GeneratedMessage {
+  factory Operations({
+    $core.Iterable<Operation>? operations,
+  }) {
+    final $result = create();
+    if (operations != null) {
+      $result.operations.addAll(operations);
+    }
+    return $result;
+  }
+  Operations._() : super();
+  factory Operations.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory Operations.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'Operations', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<Operation>(1, _omitFieldNames ? '' : 'operations', $pb.PbFieldType.PM, subBuilder: Operation.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  Operations clone() => Operations()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  Operations copyWith(void Function(Operations) updates) => super.copyWith((message) => updates(message as Operations)) as Operations;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static Operations create() => Operations._();
+  Operations createEmptyInstance() => create();
+  static $pb.PbList<Operations> createRepeated() => $pb.PbList<Operations>();
+  @$core.pragma('dart2js:noInline')
+  static Operations getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<Operations>(create);
+  static Operations? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<Operation> get operations => $_getList(0);
+}
+
+class ConfirmationRequest extends $pb.GeneratedMessage {
+  factory ConfirmationRequest({
+    $core.String? method,
+  }) {
+    final $result = create();
+    if (method != null) {
+      $result.method = method;
+    }
+    return $result;
+  }
+  ConfirmationRequest._() : super();
+  factory ConfirmationRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ConfirmationRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ConfirmationRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'method')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ConfirmationRequest clone() => ConfirmationRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ConfirmationRequest copyWith(void Function(ConfirmationRequest) updates) => super.copyWith((message) => updates(message as ConfirmationRequest)) as ConfirmationRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ConfirmationRequest create() => ConfirmationRequest._();
+  ConfirmationRequest createEmptyInstance() => create();
+  static $pb.PbList<ConfirmationRequest> createRepeated() => $pb.PbList<ConfirmationRequest>();
+  @$core.pragma('dart2js:noInline')
+  static ConfirmationRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ConfirmationRequest>(create);
+  static ConfirmationRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get method => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set method($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasMethod() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearMethod() => clearField(1);
+}
+
+class Confirmation extends $pb.GeneratedMessage {
+  factory Confirmation({
+    $core.String? code,
+    $core.String? expires,
+  }) {
+    final $result = create();
+    if (code != null) {
+      $result.code = code;
+    }
+    if (expires != null) {
+      $result.expires = expires;
+    }
+    return $result;
+  }
+  Confirmation._() : super();
+  factory Confirmation.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory Confirmation.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'Confirmation', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'code')
+    ..aOS(2, _omitFieldNames ? '' : 'expires')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  Confirmation clone() => Confirmation()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  Confirmation copyWith(void Function(Confirmation) updates) => super.copyWith((message) => updates(message as Confirmation)) as Confirmation;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static Confirmation create() => Confirmation._();
+  Confirmation createEmptyInstance() => create();
+  static $pb.PbList<Confirmation> createRepeated() => $pb.PbList<Confirmation>();
+  @$core.pragma('dart2js:noInline')
+  static Confirmation getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<Confirmation>(create);
+  static Confirmation? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get code => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set code($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasCode() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearCode() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get expires => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set expires($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasExpires() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearExpires() => clearField(2);
+}
+
+class DistroWSLSettings extends $pb.GeneratedMessage {
+  factory DistroWSLSettings({
+    $core.String? distroName,
+    $core.String? defaultUser,
+    Switch? interop,
+    Switch? automount,
+    Switch? systemd,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (defaultUser != null) {
+      $result.defaultUser = defaultUser;
+    }
+    if (interop != null) {
+      $result.interop = interop;
+    }
+    if (automount != null) {
+      $result.automount = automount;
+    }
+    if (systemd != null) {
+      $result.systemd = systemd;
+    }
+    return $result;
+  }
+  DistroWSLSettings._() : super();
+  factory DistroWSLSettings.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory DistroWSLSettings.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'DistroWSLSettings', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(2, _omitFieldNames ? '' : 'defaultUser', protoName: 'defaultUser')
+    ..aOM<Switch>(3, _omitFieldNames ? '' : 'interop', subBuilder: Switch.create)
+    ..aOM<Switch>(4, _omitFieldNames ? '' : 'automount', subBuilder: Switch.create)
+    ..aOM<Switch>(5, _omitFieldNames ? '' : 'systemd', subBuilder: Switch.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  DistroWSLSettings clone() => DistroWSLSettings()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  DistroWSLSettings copyWith(void Function(DistroWSLSettings) updates) => super.copyWith((message) => updates(message as DistroWSLSettings)) as DistroWSLSettings;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static DistroWSLSettings create() => DistroWSLSettings._();
+  DistroWSLSettings createEmptyInstance() => create();
+  static $pb.PbList<DistroWSLSettings> createRepeated() => $pb.PbList<DistroWSLSettings>();
+  @$core.pragma('dart2js:noInline')
+  static DistroWSLSettings getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<DistroWSLSettings>(create);
+  static DistroWSLSettings? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get defaultUser => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set defaultUser($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasDefaultUser() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearDefaultUser() => clearField(2);
+
+  @$pb.TagNumber(3)
+  Switch get interop => $_getN(2);
+  @$pb.TagNumber(3)
+  set interop(Switch v) { setField(3, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasInterop() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearInterop() => clearField(3);
+  @$pb.TagNumber(3)
+  Switch ensureInterop() => $_ensure(2);
+
+  @$pb.TagNumber(4)
+  Switch get automount => $_getN(3);
+  @$pb.TagNumber(4)
+  set automount(Switch v) { setField(4, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasAutomount() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearAutomount() => clearField(4);
+  @$pb.TagNumber(4)
+  Switch ensureAutomount() => $_ensure(3);
+
+  @$pb.TagNumber(5)
+  Switch get systemd => $_getN(4);
+  @$pb.TagNumber(5)
+  set systemd(Switch v) { setField(5, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasSystemd() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearSystemd() => clearField(5);
+  @$pb.TagNumber(5)
+  Switch ensureSystemd() => $_ensure(4);
+}
+
+class Switch extends $pb.GeneratedMessage {
+  factory Switch({
+    $core.bool? enabled,
+  }) {
+    final $result = create();
+    if (enabled != null) {
+      $result.enabled = enabled;
+    }
+    return $result;
+  }
+  Switch._() : super();
+  factory Switch.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory Switch.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'Switch', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOB(1, _omitFieldNames ? '' : 'enabled')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  Switch clone() => Switch()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  Switch copyWith(void Function(Switch) updates) => super.copyWith((message) => updates(message as Switch)) as Switch;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static Switch create() => Switch._();
+  Switch createEmptyInstance() => create();
+  static $pb.PbList<Switch> createRepeated() => $pb.PbList<Switch>();
+  @$core.pragma('dart2js:noInline')
+  static Switch getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<Switch>(create);
+  static Switch? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.bool get enabled => $_getBF(0);
+  @$pb.TagNumber(1)
+  set enabled($core.bool v) { $_setBool(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasEnabled() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearEnabled() => clearField(1);
+}
+
+class PauseState extends $pb.GeneratedMessage {
+  factory PauseState({
+    $core.bool? paused,
+  }) {
+    final $result = create();
+    if (paused != null) {
+      $result.paused = paused;
+    }
+    return $result;
+  }
+  PauseState._() : super();
+  factory PauseState.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory PauseState.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'PauseState', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOB(1, _omitFieldNames ? '' : 'paused')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  PauseState clone() => PauseState()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  PauseState copyWith(void Function(PauseState) updates) => super.copyWith((message) => updates(message as PauseState)) as PauseState;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static PauseState create() => PauseState._();
+  PauseState createEmptyInstance() => create();
+  static $pb.PbList<PauseState> createRepeated() => $pb.PbList<PauseState>();
+  @$core.pragma('dart2js:noInline')
+  static PauseState getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<PauseState>(create);
+  static PauseState? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.bool get paused => $_getBF(0);
+  @$pb.TagNumber(1)
+  set paused($core.bool v) { $_setBool(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasPaused() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearPaused() => clearField(1);
+}
+
+class OnboardingState extends $pb.GeneratedMessage {
+  factory OnboardingState({
+    $core.String? step,
+    $core.bool? subscriptionSelected,
+    $core.bool? landscapeConfigured,
+    $core.bool? landscapeSkipped,
+    $core.bool? distroProvisioned,
+  }) {
+    final $result = create();
+    if (step != null) {
+      $result.step = step;
+    }
+    if (subscriptionSelected != null) {
+      $result.subscriptionSelected = subscriptionSelected;
+    }
+    if (landscapeConfigured != null) {
+      $result.landscapeConfigured = landscapeConfigured;
+    }
+    if (landscapeSkipped != null) {
+      $result.landscapeSkipped = landscapeSkipped;
+    }
+    if (distroProvisioned != null) {
+      $result.distroProvisioned = distroProvisioned;
+    }
+    return $result;
+  }
+  OnboardingState._() : super();
+  factory OnboardingState.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory OnboardingState.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'OnboardingState', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'step')
+    ..aOB(2, _omitFieldNames ? '' : 'subscriptionSelected', protoName: 'subscriptionSelected')
+    ..aOB(3, _omitFieldNames ? '' : 'landscapeConfigured', protoName: 'landscapeConfigured')
+    ..aOB(4, _omitFieldNames ? '' : 'landscapeSkipped', protoName: 'landscapeSkipped')
+    ..aOB(5, _omitFieldNames ? '' : 'distroProvisioned', protoName: 'distroProvisioned')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  OnboardingState clone() => OnboardingState()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  OnboardingState copyWith(void Function(OnboardingState) updates) => super.copyWith((message) => updates(message as OnboardingState)) as OnboardingState;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static OnboardingState create() => OnboardingState._();
+  OnboardingState createEmptyInstance() => create();
+  static $pb.PbList<OnboardingState> createRepeated() => $pb.PbList<OnboardingState>();
+  @$core.pragma('dart2js:noInline')
+  static OnboardingState getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<OnboardingState>(create);
+  static OnboardingState? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get step => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set step($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasStep() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearStep() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get subscriptionSelected => $_getBF(1);
+  @$pb.TagNumber(2)
+  set subscriptionSelected($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasSubscriptionSelected() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearSubscriptionSelected() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.bool get landscapeConfigured => $_getBF(2);
+  @$pb.TagNumber(3)
+  set landscapeConfigured($core.bool v) { $_setBool(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasLandscapeConfigured() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearLandscapeConfigured() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.bool get landscapeSkipped => $_getBF(3);
+  @$pb.TagNumber(4)
+  set landscapeSkipped($core.bool v) { $_setBool(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasLandscapeSkipped() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearLandscapeSkipped() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.bool get distroProvisioned => $_getBF(4);
+  @$pb.TagNumber(5)
+  set distroProvisioned($core.bool v) { $_setBool(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasDistroProvisioned() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearDistroProvisioned() => clearField(5);
+}
+
+class NotificationPreference extends $pb.GeneratedMessage {
+  factory NotificationPreference({
+    $core.String? category,
+    $core.bool? enabled,
+  }) {
+    final $result = create();
+    if (category != null) {
+      $result.category = category;
+    }
+    if (enabled != null) {
+      $result.enabled = enabled;
+    }
+    return $result;
+  }
+  NotificationPreference._() : super();
+  factory NotificationPreference.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory NotificationPreference.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'NotificationPreference', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'category')
+    ..aOB(2, _omitFieldNames ? '' : 'enabled')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  NotificationPreference clone() => NotificationPreference()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  NotificationPreference copyWith(void Function(NotificationPreference) updates) => super.copyWith((message) => updates(message as NotificationPreference)) as NotificationPreference;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static NotificationPreference create() => NotificationPreference._();
+  NotificationPreference createEmptyInstance() => create();
+  static $pb.PbList<NotificationPreference> createRepeated() => $pb.PbList<NotificationPreference>();
+  @$core.pragma('dart2js:noInline')
+  static NotificationPreference getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<NotificationPreference>(create);
+  static NotificationPreference? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get category => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set category($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasCategory() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearCategory() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get enabled => $_getBF(1);
+  @$pb.TagNumber(2)
+  set enabled($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasEnabled() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearEnabled() => clearField(2);
+}
+
+class NotificationPreferences extends $pb.GeneratedMessage {
+  factory NotificationPreferences({
+    $core.Iterable<NotificationPreference>? preferences,
+  }) {
+    final $result = create();
+    if (preferences != null) {
+      $result.preferences.addAll(preferences);
+    }
+    return $result;
+  }
+  NotificationPreferences._() : super();
+  factory NotificationPreferences.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory NotificationPreferences.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'NotificationPreferences', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<NotificationPreference>(1, _omitFieldNames ? '' : 'preferences', $pb.PbFieldType.PM, subBuilder: NotificationPreference.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  NotificationPreferences clone() => NotificationPreferences()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  NotificationPreferences copyWith(void Function(NotificationPreferences) updates) => super.copyWith((message) => updates(message as NotificationPreferences)) as NotificationPreferences;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static NotificationPreferences create() => NotificationPreferences._();
+  NotificationPreferences createEmptyInstance() => create();
+  static $pb.PbList<NotificationPreferences> createRepeated() => $pb.PbList<NotificationPreferences>();
+  @$core.pragma('dart2js:noInline')
+  static NotificationPreferences getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<NotificationPreferences>(create);
+  static NotificationPreferences? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<NotificationPreference> get preferences => $_getList(0);
+}
+
+class ApprovalPreference extends $pb.GeneratedMessage {
+  factory ApprovalPreference({
+    $core.String? category,
+    $core.bool? required,
+  }) {
+    final $result = create();
+    if (category != null) {
+      $result.category = category;
+    }
+    if (required != null) {
+      $result.required = required;
+    }
+    return $result;
+  }
+  ApprovalPreference._() : super();
+  factory ApprovalPreference.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ApprovalPreference.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ApprovalPreference', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'category')
+    ..aOB(2, _omitFieldNames ? '' : 'required')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ApprovalPreference clone() => ApprovalPreference()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ApprovalPreference copyWith(void Function(ApprovalPreference) updates) => super.copyWith((message) => updates(message as ApprovalPreference)) as ApprovalPreference;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ApprovalPreference create() => ApprovalPreference._();
+  ApprovalPreference createEmptyInstance() => create();
+  static $pb.PbList<ApprovalPreference> createRepeated() => $pb.PbList<ApprovalPreference>();
+  @$core.pragma('dart2js:noInline')
+  static ApprovalPreference getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ApprovalPreference>(create);
+  static ApprovalPreference? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get category => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set category($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasCategory() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearCategory() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get required => $_getBF(1);
+  @$pb.TagNumber(2)
+  set required($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasRequired() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearRequired() => clearField(2);
+}
+
+class ApprovalPreferences extends $pb.GeneratedMessage {
+  factory ApprovalPreferences({
+    $core.Iterable<ApprovalPreference>? preferences,
+  }) {
+    final $result = create();
+    if (preferences != null) {
+      $result.preferences.addAll(preferences);
+    }
+    return $result;
+  }
+  ApprovalPreferences._() : super();
+  factory ApprovalPreferences.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ApprovalPreferences.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ApprovalPreferences', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<ApprovalPreference>(1, _omitFieldNames ? '' : 'preferences', $pb.PbFieldType.PM, subBuilder: ApprovalPreference.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ApprovalPreferences clone() => ApprovalPreferences()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ApprovalPreferences copyWith(void Function(ApprovalPreferences) updates) => super.copyWith((message) => updates(message as ApprovalPreferences)) as ApprovalPreferences;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ApprovalPreferences create() => ApprovalPreferences._();
+  ApprovalPreferences createEmptyInstance() => create();
+  static $pb.PbList<ApprovalPreferences> createRepeated() => $pb.PbList<ApprovalPreferences>();
+  @$core.pragma('dart2js:noInline')
+  static ApprovalPreferences getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ApprovalPreferences>(create);
+  static ApprovalPreferences? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<ApprovalPreference> get preferences => $_getList(0);
+}
+
+class PendingApproval extends $pb.GeneratedMessage {
+  factory PendingApproval({
+    $core.String? id,
+    $core.String? category,
+    $core.String? description,
+    $core.String? requested,
+    $core.String? expires,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    if (category != null) {
+      $result.category = category;
+    }
+    if (description != null) {
+      $result.description = description;
+    }
+    if (requested != null) {
+      $result.requested = requested;
+    }
+    if (expires != null) {
+      $result.expires = expires;
+    }
+    return $result;
+  }
+  PendingApproval._() : super();
+  factory PendingApproval.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory PendingApproval.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'PendingApproval', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..aOS(2, _omitFieldNames ? '' : 'category')
+    ..aOS(3, _omitFieldNames ? '' : 'description')
+    ..aOS(4, _omitFieldNames ? '' : 'requested')
+    ..aOS(5, _omitFieldNames ? '' : 'expires')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  PendingApproval clone() => PendingApproval()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  PendingApproval copyWith(void Function(PendingApproval) updates) => super.copyWith((message) => updates(message as PendingApproval)) as PendingApproval;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static PendingApproval create() => PendingApproval._();
+  PendingApproval createEmptyInstance() => create();
+  static $pb.PbList<PendingApproval> createRepeated() => $pb.PbList<PendingApproval>();
+  @$core.pragma('dart2js:noInline')
+  static PendingApproval getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<PendingApproval>(create);
+  static PendingApproval? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get category => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set category($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasCategory() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearCategory() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get description => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set description($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasDescription() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearDescription() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.String get requested => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set requested($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasRequested() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearRequested() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.String get expires => $_getSZ(4);
+  @$pb.TagNumber(5)
+  set expires($core.String v) { $_setString(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasExpires() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearExpires() => clearField(5);
+}
+
+class PendingApprovals extends $pb.GeneratedMessage {
+  factory PendingApprovals({
+    $core.Iterable<PendingApproval>? approvals,
+  }) {
+    final $result = create();
+    if (approvals != null) {
+      $result.approvals.addAll(approvals);
+    }
+    return $result;
+  }
+  PendingApprovals._() : super();
+  factory PendingApprovals.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory PendingApprovals.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'PendingApprovals', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<PendingApproval>(1, _omitFieldNames ? '' : 'approvals', $pb.PbFieldType.PM, subBuilder: PendingApproval.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  PendingApprovals clone() => PendingApprovals()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  PendingApprovals copyWith(void Function(PendingApprovals) updates) => super.copyWith((message) => updates(message as PendingApprovals)) as PendingApprovals;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static PendingApprovals create() => PendingApprovals._();
+  PendingApprovals createEmptyInstance() => create();
+  static $pb.PbList<PendingApprovals> createRepeated() => $pb.PbList<PendingApprovals>();
+  @$core.pragma('dart2js:noInline')
+  static PendingApprovals getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<PendingApprovals>(create);
+  static PendingApprovals? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<PendingApproval> get approvals => $_getList(0);
+}
+
+class ApprovalDecision extends $pb.GeneratedMessage {
+  factory ApprovalDecision({
+    $core.String? id,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    return $result;
+  }
+  ApprovalDecision._() : super();
+  factory ApprovalDecision.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ApprovalDecision.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ApprovalDecision', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ApprovalDecision clone() => ApprovalDecision()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ApprovalDecision copyWith(void Function(ApprovalDecision) updates) => super.copyWith((message) => updates(message as ApprovalDecision)) as ApprovalDecision;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ApprovalDecision create() => ApprovalDecision._();
+  ApprovalDecision createEmptyInstance() => create();
+  static $pb.PbList<ApprovalDecision> createRepeated() => $pb.PbList<ApprovalDecision>();
+  @$core.pragma('dart2js:noInline')
+  static ApprovalDecision getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ApprovalDecision>(create);
+  static ApprovalDecision? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+}
+
+class PluginTaskSubmission extends $pb.GeneratedMessage {
+  factory PluginTaskSubmission({
+    $core.String? distroName,
+    $core.String? type,
+    $core.List<$core.int>? payload,
+    $core.bool? deferred,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (type != null) {
+      $result.type = type;
+    }
+    if (payload != null) {
+      $result.payload = payload;
+    }
+    if (deferred != null) {
+      $result.deferred = deferred;
+    }
+    return $result;
+  }
+  PluginTaskSubmission._() : super();
+  factory PluginTaskSubmission.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory PluginTaskSubmission.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'PluginTaskSubmission', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(2, _omitFieldNames ? '' : 'type')
+    ..a<$core.List<$core.int>>(3, _omitFieldNames ? '' : 'payload', $pb.PbFieldType.OY)
+    ..aOB(4, _omitFieldNames ? '' : 'deferred')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  PluginTaskSubmission clone() => PluginTaskSubmission()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  PluginTaskSubmission copyWith(void Function(PluginTaskSubmission) updates) => super.copyWith((message) => updates(message as PluginTaskSubmission)) as PluginTaskSubmission;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static PluginTaskSubmission create() => PluginTaskSubmission._();
+  PluginTaskSubmission createEmptyInstance() => create();
+  static $pb.PbList<PluginTaskSubmission> createRepeated() => $pb.PbList<PluginTaskSubmission>();
+  @$core.pragma('dart2js:noInline')
+  static PluginTaskSubmission getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<PluginTaskSubmission>(create);
+  static PluginTaskSubmission? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get type => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set type($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasType() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearType() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.List<$core.int> get payload => $_getN(2);
+  @$pb.TagNumber(3)
+  set payload($core.List<$core.int> v) { $_setBytes(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasPayload() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearPayload() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.bool get deferred => $_getBF(3);
+  @$pb.TagNumber(4)
+  set deferred($core.bool v) { $_setBool(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasDeferred() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearDeferred() => clearField(4);
+}
+
+class LandscapeDistroOverride extends $pb.GeneratedMessage {
+  factory LandscapeDistroOverride({
+    $core.String? distroName,
+    $core.String? tags,
+    $core.String? computerTitle,
+    $core.String? scriptUsers,
+    $core.String? endpoint,
+  }) {
+    final $result = create();
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (tags != null) {
+      $result.tags = tags;
+    }
+    if (computerTitle != null) {
+      $result.computerTitle = computerTitle;
+    }
+    if (scriptUsers != null) {
+      $result.scriptUsers = scriptUsers;
+    }
+    if (endpoint != null) {
+      $result.endpoint = endpoint;
+    }
+    return $result;
+  }
+  LandscapeDistroOverride._() : super();
+  factory LandscapeDistroOverride.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory LandscapeDistroOverride.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'LandscapeDistroOverride', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..aOS(2, _omitFieldNames ? '' : 'tags')
+    ..aOS(3, _omitFieldNames ? '' : 'computerTitle', protoName: 'computerTitle')
+    ..aOS(4, _omitFieldNames ? '' : 'scriptUsers', protoName: 'scriptUsers')
+    ..aOS(5, _omitFieldNames ? '' : 'endpoint')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  LandscapeDistroOverride clone() => LandscapeDistroOverride()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  LandscapeDistroOverride copyWith(void Function(LandscapeDistroOverride) updates) => super.copyWith((message) => updates(message as LandscapeDistroOverride)) as LandscapeDistroOverride;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static LandscapeDistroOverride create() => LandscapeDistroOverride._();
+  LandscapeDistroOverride createEmptyInstance() => create();
+  static $pb.PbList<LandscapeDistroOverride> createRepeated() => $pb.PbList<LandscapeDistroOverride>();
+  @$core.pragma('dart2js:noInline')
+  static LandscapeDistroOverride getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<LandscapeDistroOverride>(create);
+  static LandscapeDistroOverride? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get distroName => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set distroName($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasDistroName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearDistroName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get tags => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set tags($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasTags() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearTags() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get computerTitle => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set computerTitle($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasComputerTitle() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearComputerTitle() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.String get scriptUsers => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set scriptUsers($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasScriptUsers() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearScriptUsers() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.String get endpoint => $_getSZ(4);
+  @$pb.TagNumber(5)
+  set endpoint($core.String v) { $_setString(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasEndpoint() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearEndpoint() => clearField(5);
+}
+
+class LandscapeEndpoint extends $pb.GeneratedMessage {
+  factory LandscapeEndpoint({
+    $core.String? name,
+    $core.String? config,
+  }) {
+    final $result = create();
+    if (name != null) {
+      $result.name = name;
+    }
+    if (config != null) {
+      $result.config = config;
+    }
+    return $result;
+  }
+  LandscapeEndpoint._() : super();
+  factory LandscapeEndpoint.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory LandscapeEndpoint.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'LandscapeEndpoint', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'name')
+    ..aOS(2, _omitFieldNames ? '' : 'config')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  LandscapeEndpoint clone() => LandscapeEndpoint()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  LandscapeEndpoint copyWith(void Function(LandscapeEndpoint) updates) => super.copyWith((message) => updates(message as LandscapeEndpoint)) as LandscapeEndpoint;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static LandscapeEndpoint create() => LandscapeEndpoint._();
+  LandscapeEndpoint createEmptyInstance() => create();
+  static $pb.PbList<LandscapeEndpoint> createRepeated() => $pb.PbList<LandscapeEndpoint>();
+  @$core.pragma('dart2js:noInline')
+  static LandscapeEndpoint getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<LandscapeEndpoint>(create);
+  static LandscapeEndpoint? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get name => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set name($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get config => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set config($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasConfig() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearConfig() => clearField(2);
+}
+
+class LandscapeDistroOverrides extends $pb.GeneratedMessage {
+  factory LandscapeDistroOverrides({
+    $core.Iterable<LandscapeDistroOverride>? overrides,
+  }) {
+    final $result = create();
+    if (overrides != null) {
+      $result.overrides.addAll(overrides);
+    }
+    return $result;
+  }
+  LandscapeDistroOverrides._() : super();
+  factory LandscapeDistroOverrides.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory LandscapeDistroOverrides.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
 
-  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'LandscapeConfig', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
-    ..aOS(1, _omitFieldNames ? '' : 'config')
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'LandscapeDistroOverrides', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<LandscapeDistroOverride>(1, _omitFieldNames ? '' : 'overrides', $pb.PbFieldType.PM, subBuilder: LandscapeDistroOverride.create)
     ..hasRequiredFields = false
   ;
 
@@ -118,31 +4084,25 @@ class LandscapeConfig extends $pb.GeneratedMessage {
   'Using this can add significant overhead to your binary. '
   'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
   'Will be removed in next major version')
-  LandscapeConfig clone() => LandscapeConfig()..mergeFromMessage(this);
+  LandscapeDistroOverrides clone() => LandscapeDistroOverrides()..mergeFromMessage(this);
   @$core.Deprecated(
   'Using this can add significant overhead to your binary. '
   'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
   'Will be removed in next major version')
-  LandscapeConfig copyWith(void Function(LandscapeConfig) updates) => super.copyWith((message) => updates(message as LandscapeConfig)) as LandscapeConfig;
+  LandscapeDistroOverrides copyWith(void Function(LandscapeDistroOverrides) updates) => super.copyWith((message) => updates(message as LandscapeDistroOverrides)) as LandscapeDistroOverrides;
 
   $pb.BuilderInfo get info_ => _i;
 
   @$core.pragma('dart2js:noInline')
-  static LandscapeConfig create() => LandscapeConfig._();
-  LandscapeConfig createEmptyInstance() => create();
-  static $pb.PbList<LandscapeConfig> createRepeated() => $pb.PbList<LandscapeConfig>();
+  static LandscapeDistroOverrides create() => LandscapeDistroOverrides._();
+  LandscapeDistroOverrides createEmptyInstance() => create();
+  static $pb.PbList<LandscapeDistroOverrides> createRepeated() => $pb.PbList<LandscapeDistroOverrides>();
   @$core.pragma('dart2js:noInline')
-  static LandscapeConfig getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<LandscapeConfig>(create);
-  static LandscapeConfig? _defaultInstance;
+  static LandscapeDistroOverrides getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<LandscapeDistroOverrides>(create);
+  static LandscapeDistroOverrides? _defaultInstance;
 
   @$pb.TagNumber(1)
-  $core.String get config => $_getSZ(0);
-  @$pb.TagNumber(1)
-  set config($core.String v) { $_setString(0, v); }
-  @$pb.TagNumber(1)
-  $core.bool hasConfig() => $_has(0);
-  @$pb.TagNumber(1)
-  void clearConfig() => clearField(1);
+  $core.List<LandscapeDistroOverride> get overrides => $_getList(0);
 }
 
 enum SubscriptionInfo_SubscriptionType {
@@ -352,54 +4312,407 @@ class LandscapeSource extends $pb.GeneratedMessage {
   @$pb.TagNumber(1)
   $core.bool hasNone() => $_has(0);
   @$pb.TagNumber(1)
-  void clearNone() => clearField(1);
+  void clearNone() => clearField(1);
+  @$pb.TagNumber(1)
+  Empty ensureNone() => $_ensure(0);
+
+  @$pb.TagNumber(2)
+  Empty get user => $_getN(1);
+  @$pb.TagNumber(2)
+  set user(Empty v) { setField(2, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasUser() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearUser() => clearField(2);
+  @$pb.TagNumber(2)
+  Empty ensureUser() => $_ensure(1);
+
+  @$pb.TagNumber(3)
+  Empty get organization => $_getN(2);
+  @$pb.TagNumber(3)
+  set organization(Empty v) { setField(3, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasOrganization() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearOrganization() => clearField(3);
+  @$pb.TagNumber(3)
+  Empty ensureOrganization() => $_ensure(2);
+}
+
+class ConfigSources extends $pb.GeneratedMessage {
+  factory ConfigSources({
+    SubscriptionInfo? proSubscription,
+    LandscapeSource? landscapeSource,
+  }) {
+    final $result = create();
+    if (proSubscription != null) {
+      $result.proSubscription = proSubscription;
+    }
+    if (landscapeSource != null) {
+      $result.landscapeSource = landscapeSource;
+    }
+    return $result;
+  }
+  ConfigSources._() : super();
+  factory ConfigSources.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ConfigSources.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ConfigSources', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOM<SubscriptionInfo>(1, _omitFieldNames ? '' : 'proSubscription', protoName: 'proSubscription', subBuilder: SubscriptionInfo.create)
+    ..aOM<LandscapeSource>(2, _omitFieldNames ? '' : 'landscapeSource', protoName: 'landscapeSource', subBuilder: LandscapeSource.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ConfigSources clone() => ConfigSources()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ConfigSources copyWith(void Function(ConfigSources) updates) => super.copyWith((message) => updates(message as ConfigSources)) as ConfigSources;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ConfigSources create() => ConfigSources._();
+  ConfigSources createEmptyInstance() => create();
+  static $pb.PbList<ConfigSources> createRepeated() => $pb.PbList<ConfigSources>();
+  @$core.pragma('dart2js:noInline')
+  static ConfigSources getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ConfigSources>(create);
+  static ConfigSources? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  SubscriptionInfo get proSubscription => $_getN(0);
+  @$pb.TagNumber(1)
+  set proSubscription(SubscriptionInfo v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasProSubscription() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearProSubscription() => clearField(1);
+  @$pb.TagNumber(1)
+  SubscriptionInfo ensureProSubscription() => $_ensure(0);
+
+  @$pb.TagNumber(2)
+  LandscapeSource get landscapeSource => $_getN(1);
+  @$pb.TagNumber(2)
+  set landscapeSource(LandscapeSource v) { setField(2, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasLandscapeSource() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearLandscapeSource() => clearField(2);
+  @$pb.TagNumber(2)
+  LandscapeSource ensureLandscapeSource() => $_ensure(1);
+}
+
+class SubscriptionDetails extends $pb.GeneratedMessage {
+  factory SubscriptionDetails({
+    SubscriptionInfo? active,
+    $core.Iterable<SubscriptionSourceDetails>? sources,
+    $core.bool? registryReadOnly,
+  }) {
+    final $result = create();
+    if (active != null) {
+      $result.active = active;
+    }
+    if (sources != null) {
+      $result.sources.addAll(sources);
+    }
+    if (registryReadOnly != null) {
+      $result.registryReadOnly = registryReadOnly;
+    }
+    return $result;
+  }
+  SubscriptionDetails._() : super();
+  factory SubscriptionDetails.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SubscriptionDetails.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SubscriptionDetails', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOM<SubscriptionInfo>(1, _omitFieldNames ? '' : 'active', subBuilder: SubscriptionInfo.create)
+    ..pc<SubscriptionSourceDetails>(2, _omitFieldNames ? '' : 'sources', $pb.PbFieldType.PM, subBuilder: SubscriptionSourceDetails.create)
+    ..aOB(3, _omitFieldNames ? '' : 'registryReadOnly', protoName: 'registryReadOnly')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SubscriptionDetails clone() => SubscriptionDetails()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SubscriptionDetails copyWith(void Function(SubscriptionDetails) updates) => super.copyWith((message) => updates(message as SubscriptionDetails)) as SubscriptionDetails;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SubscriptionDetails create() => SubscriptionDetails._();
+  SubscriptionDetails createEmptyInstance() => create();
+  static $pb.PbList<SubscriptionDetails> createRepeated() => $pb.PbList<SubscriptionDetails>();
+  @$core.pragma('dart2js:noInline')
+  static SubscriptionDetails getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SubscriptionDetails>(create);
+  static SubscriptionDetails? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  SubscriptionInfo get active => $_getN(0);
+  @$pb.TagNumber(1)
+  set active(SubscriptionInfo v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasActive() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearActive() => clearField(1);
+  @$pb.TagNumber(1)
+  SubscriptionInfo ensureActive() => $_ensure(0);
+
+  @$pb.TagNumber(2)
+  $core.List<SubscriptionSourceDetails> get sources => $_getList(1);
+
+  @$pb.TagNumber(3)
+  $core.bool get registryReadOnly => $_getBF(2);
+  @$pb.TagNumber(3)
+  set registryReadOnly($core.bool v) { $_setBool(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasRegistryReadOnly() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearRegistryReadOnly() => clearField(3);
+}
+
+class SubscriptionSourceDetails extends $pb.GeneratedMessage {
+  factory SubscriptionSourceDetails({
+    SubscriptionInfo? source,
+    $core.bool? hasToken,
+    $core.bool? shadowed,
+    $core.String? lastModified,
+  }) {
+    final $result = create();
+    if (source != null) {
+      $result.source = source;
+    }
+    if (hasToken != null) {
+      $result.hasToken = hasToken;
+    }
+    if (shadowed != null) {
+      $result.shadowed = shadowed;
+    }
+    if (lastModified != null) {
+      $result.lastModified = lastModified;
+    }
+    return $result;
+  }
+  SubscriptionSourceDetails._() : super();
+  factory SubscriptionSourceDetails.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SubscriptionSourceDetails.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SubscriptionSourceDetails', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOM<SubscriptionInfo>(1, _omitFieldNames ? '' : 'source', subBuilder: SubscriptionInfo.create)
+    ..aOB(2, _omitFieldNames ? '' : 'hasToken', protoName: 'hasToken')
+    ..aOB(3, _omitFieldNames ? '' : 'shadowed')
+    ..aOS(4, _omitFieldNames ? '' : 'lastModified', protoName: 'lastModified')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SubscriptionSourceDetails clone() => SubscriptionSourceDetails()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SubscriptionSourceDetails copyWith(void Function(SubscriptionSourceDetails) updates) => super.copyWith((message) => updates(message as SubscriptionSourceDetails)) as SubscriptionSourceDetails;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SubscriptionSourceDetails create() => SubscriptionSourceDetails._();
+  SubscriptionSourceDetails createEmptyInstance() => create();
+  static $pb.PbList<SubscriptionSourceDetails> createRepeated() => $pb.PbList<SubscriptionSourceDetails>();
+  @$core.pragma('dart2js:noInline')
+  static SubscriptionSourceDetails getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SubscriptionSourceDetails>(create);
+  static SubscriptionSourceDetails? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  SubscriptionInfo get source => $_getN(0);
+  @$pb.TagNumber(1)
+  set source(SubscriptionInfo v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasSource() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearSource() => clearField(1);
+  @$pb.TagNumber(1)
+  SubscriptionInfo ensureSource() => $_ensure(0);
+
+  @$pb.TagNumber(2)
+  $core.bool get hasToken => $_getBF(1);
+  @$pb.TagNumber(2)
+  set hasToken($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasHasToken() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearHasToken() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.bool get shadowed => $_getBF(2);
+  @$pb.TagNumber(3)
+  set shadowed($core.bool v) { $_setBool(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasShadowed() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearShadowed() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.String get lastModified => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set lastModified($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasLastModified() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearLastModified() => clearField(4);
+}
+
+class ConfigProblems extends $pb.GeneratedMessage {
+  factory ConfigProblems({
+    $core.Iterable<ConfigProblem>? problems,
+  }) {
+    final $result = create();
+    if (problems != null) {
+      $result.problems.addAll(problems);
+    }
+    return $result;
+  }
+  ConfigProblems._() : super();
+  factory ConfigProblems.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ConfigProblems.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ConfigProblems', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<ConfigProblem>(1, _omitFieldNames ? '' : 'problems', $pb.PbFieldType.PM, subBuilder: ConfigProblem.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ConfigProblems clone() => ConfigProblems()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ConfigProblems copyWith(void Function(ConfigProblems) updates) => super.copyWith((message) => updates(message as ConfigProblems)) as ConfigProblems;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ConfigProblems create() => ConfigProblems._();
+  ConfigProblems createEmptyInstance() => create();
+  static $pb.PbList<ConfigProblems> createRepeated() => $pb.PbList<ConfigProblems>();
+  @$core.pragma('dart2js:noInline')
+  static ConfigProblems getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ConfigProblems>(create);
+  static ConfigProblems? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<ConfigProblem> get problems => $_getList(0);
+}
+
+class ConfigProblem extends $pb.GeneratedMessage {
+  factory ConfigProblem({
+    $core.bool? isError,
+    $core.String? field,
+    $core.String? message,
+  }) {
+    final $result = create();
+    if (isError != null) {
+      $result.isError = isError;
+    }
+    if (field != null) {
+      $result.field = field;
+    }
+    if (message != null) {
+      $result.message = message;
+    }
+    return $result;
+  }
+  ConfigProblem._() : super();
+  factory ConfigProblem.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ConfigProblem.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ConfigProblem', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOB(1, _omitFieldNames ? '' : 'isError', protoName: 'isError')
+    ..aOS(2, _omitFieldNames ? '' : 'field')
+    ..aOS(3, _omitFieldNames ? '' : 'message')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ConfigProblem clone() => ConfigProblem()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ConfigProblem copyWith(void Function(ConfigProblem) updates) => super.copyWith((message) => updates(message as ConfigProblem)) as ConfigProblem;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ConfigProblem create() => ConfigProblem._();
+  ConfigProblem createEmptyInstance() => create();
+  static $pb.PbList<ConfigProblem> createRepeated() => $pb.PbList<ConfigProblem>();
+  @$core.pragma('dart2js:noInline')
+  static ConfigProblem getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ConfigProblem>(create);
+  static ConfigProblem? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.bool get isError => $_getBF(0);
+  @$pb.TagNumber(1)
+  set isError($core.bool v) { $_setBool(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasIsError() => $_has(0);
   @$pb.TagNumber(1)
-  Empty ensureNone() => $_ensure(0);
+  void clearIsError() => clearField(1);
 
   @$pb.TagNumber(2)
-  Empty get user => $_getN(1);
-  @$pb.TagNumber(2)
-  set user(Empty v) { setField(2, v); }
+  $core.String get field => $_getSZ(1);
   @$pb.TagNumber(2)
-  $core.bool hasUser() => $_has(1);
+  set field($core.String v) { $_setString(1, v); }
   @$pb.TagNumber(2)
-  void clearUser() => clearField(2);
+  $core.bool hasField() => $_has(1);
   @$pb.TagNumber(2)
-  Empty ensureUser() => $_ensure(1);
+  void clearField() => clearField(2);
 
   @$pb.TagNumber(3)
-  Empty get organization => $_getN(2);
-  @$pb.TagNumber(3)
-  set organization(Empty v) { setField(3, v); }
+  $core.String get message => $_getSZ(2);
   @$pb.TagNumber(3)
-  $core.bool hasOrganization() => $_has(2);
+  set message($core.String v) { $_setString(2, v); }
   @$pb.TagNumber(3)
-  void clearOrganization() => clearField(3);
+  $core.bool hasMessage() => $_has(2);
   @$pb.TagNumber(3)
-  Empty ensureOrganization() => $_ensure(2);
+  void clearMessage() => clearField(3);
 }
 
-class ConfigSources extends $pb.GeneratedMessage {
-  factory ConfigSources({
-    SubscriptionInfo? proSubscription,
-    LandscapeSource? landscapeSource,
+class FeatureFlags extends $pb.GeneratedMessage {
+  factory FeatureFlags({
+    $core.Iterable<FeatureFlag>? flags,
   }) {
     final $result = create();
-    if (proSubscription != null) {
-      $result.proSubscription = proSubscription;
-    }
-    if (landscapeSource != null) {
-      $result.landscapeSource = landscapeSource;
+    if (flags != null) {
+      $result.flags.addAll(flags);
     }
     return $result;
   }
-  ConfigSources._() : super();
-  factory ConfigSources.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
-  factory ConfigSources.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+  FeatureFlags._() : super();
+  factory FeatureFlags.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory FeatureFlags.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
 
-  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ConfigSources', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
-    ..aOM<SubscriptionInfo>(1, _omitFieldNames ? '' : 'proSubscription', protoName: 'proSubscription', subBuilder: SubscriptionInfo.create)
-    ..aOM<LandscapeSource>(2, _omitFieldNames ? '' : 'landscapeSource', protoName: 'landscapeSource', subBuilder: LandscapeSource.create)
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'FeatureFlags', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..pc<FeatureFlag>(1, _omitFieldNames ? '' : 'flags', $pb.PbFieldType.PM, subBuilder: FeatureFlag.create)
     ..hasRequiredFields = false
   ;
 
@@ -407,44 +4720,117 @@ class ConfigSources extends $pb.GeneratedMessage {
   'Using this can add significant overhead to your binary. '
   'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
   'Will be removed in next major version')
-  ConfigSources clone() => ConfigSources()..mergeFromMessage(this);
+  FeatureFlags clone() => FeatureFlags()..mergeFromMessage(this);
   @$core.Deprecated(
   'Using this can add significant overhead to your binary. '
   'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
   'Will be removed in next major version')
-  ConfigSources copyWith(void Function(ConfigSources) updates) => super.copyWith((message) => updates(message as ConfigSources)) as ConfigSources;
+  FeatureFlags copyWith(void Function(FeatureFlags) updates) => super.copyWith((message) => updates(message as FeatureFlags)) as FeatureFlags;
 
   $pb.BuilderInfo get info_ => _i;
 
   @$core.pragma('dart2js:noInline')
-  static ConfigSources create() => ConfigSources._();
-  ConfigSources createEmptyInstance() => create();
-  static $pb.PbList<ConfigSources> createRepeated() => $pb.PbList<ConfigSources>();
+  static FeatureFlags create() => FeatureFlags._();
+  FeatureFlags createEmptyInstance() => create();
+  static $pb.PbList<FeatureFlags> createRepeated() => $pb.PbList<FeatureFlags>();
   @$core.pragma('dart2js:noInline')
-  static ConfigSources getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ConfigSources>(create);
-  static ConfigSources? _defaultInstance;
+  static FeatureFlags getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<FeatureFlags>(create);
+  static FeatureFlags? _defaultInstance;
 
   @$pb.TagNumber(1)
-  SubscriptionInfo get proSubscription => $_getN(0);
+  $core.List<FeatureFlag> get flags => $_getList(0);
+}
+
+class FeatureFlag extends $pb.GeneratedMessage {
+  factory FeatureFlag({
+    $core.String? name,
+    $core.bool? enabled,
+    $core.bool? isDefault,
+    $core.bool? organization,
+  }) {
+    final $result = create();
+    if (name != null) {
+      $result.name = name;
+    }
+    if (enabled != null) {
+      $result.enabled = enabled;
+    }
+    if (isDefault != null) {
+      $result.isDefault = isDefault;
+    }
+    if (organization != null) {
+      $result.organization = organization;
+    }
+    return $result;
+  }
+  FeatureFlag._() : super();
+  factory FeatureFlag.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory FeatureFlag.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'FeatureFlag', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'name')
+    ..aOB(2, _omitFieldNames ? '' : 'enabled')
+    ..aOB(3, _omitFieldNames ? '' : 'isDefault', protoName: 'isDefault')
+    ..aOB(4, _omitFieldNames ? '' : 'organization')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  FeatureFlag clone() => FeatureFlag()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  FeatureFlag copyWith(void Function(FeatureFlag) updates) => super.copyWith((message) => updates(message as FeatureFlag)) as FeatureFlag;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static FeatureFlag create() => FeatureFlag._();
+  FeatureFlag createEmptyInstance() => create();
+  static $pb.PbList<FeatureFlag> createRepeated() => $pb.PbList<FeatureFlag>();
+  @$core.pragma('dart2js:noInline')
+  static FeatureFlag getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<FeatureFlag>(create);
+  static FeatureFlag? _defaultInstance;
+
   @$pb.TagNumber(1)
-  set proSubscription(SubscriptionInfo v) { setField(1, v); }
+  $core.String get name => $_getSZ(0);
   @$pb.TagNumber(1)
-  $core.bool hasProSubscription() => $_has(0);
+  set name($core.String v) { $_setString(0, v); }
   @$pb.TagNumber(1)
-  void clearProSubscription() => clearField(1);
+  $core.bool hasName() => $_has(0);
   @$pb.TagNumber(1)
-  SubscriptionInfo ensureProSubscription() => $_ensure(0);
+  void clearName() => clearField(1);
 
   @$pb.TagNumber(2)
-  LandscapeSource get landscapeSource => $_getN(1);
-  @$pb.TagNumber(2)
-  set landscapeSource(LandscapeSource v) { setField(2, v); }
+  $core.bool get enabled => $_getBF(1);
   @$pb.TagNumber(2)
-  $core.bool hasLandscapeSource() => $_has(1);
+  set enabled($core.bool v) { $_setBool(1, v); }
   @$pb.TagNumber(2)
-  void clearLandscapeSource() => clearField(2);
+  $core.bool hasEnabled() => $_has(1);
   @$pb.TagNumber(2)
-  LandscapeSource ensureLandscapeSource() => $_ensure(1);
+  void clearEnabled() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.bool get isDefault => $_getBF(2);
+  @$pb.TagNumber(3)
+  set isDefault($core.bool v) { $_setBool(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasIsDefault() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearIsDefault() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.bool get organization => $_getBF(3);
+  @$pb.TagNumber(4)
+  set organization($core.bool v) { $_setBool(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasOrganization() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearOrganization() => clearField(4);
 }
 
 class DistroInfo extends $pb.GeneratedMessage {
@@ -455,6 +4841,12 @@ class DistroInfo extends $pb.GeneratedMessage {
     $core.String? prettyName,
     $core.bool? proAttached,
     $core.String? hostname,
+    $core.String? instanceId,
+    $core.Iterable<$core.String>? proServices,
+    $core.bool? landscapeRegistered,
+    $core.String? serviceVersion,
+    $core.String? bootId,
+    $core.String? landscapeScriptUsers,
   }) {
     final $result = create();
     if (wslName != null) {
@@ -475,6 +4867,24 @@ class DistroInfo extends $pb.GeneratedMessage {
     if (hostname != null) {
       $result.hostname = hostname;
     }
+    if (instanceId != null) {
+      $result.instanceId = instanceId;
+    }
+    if (proServices != null) {
+      $result.proServices.addAll(proServices);
+    }
+    if (landscapeRegistered != null) {
+      $result.landscapeRegistered = landscapeRegistered;
+    }
+    if (serviceVersion != null) {
+      $result.serviceVersion = serviceVersion;
+    }
+    if (bootId != null) {
+      $result.bootId = bootId;
+    }
+    if (landscapeScriptUsers != null) {
+      $result.landscapeScriptUsers = landscapeScriptUsers;
+    }
     return $result;
   }
   DistroInfo._() : super();
@@ -488,6 +4898,12 @@ class DistroInfo extends $pb.GeneratedMessage {
     ..aOS(4, _omitFieldNames ? '' : 'prettyName')
     ..aOB(5, _omitFieldNames ? '' : 'proAttached')
     ..aOS(6, _omitFieldNames ? '' : 'hostname')
+    ..aOS(7, _omitFieldNames ? '' : 'instanceId')
+    ..pPS(8, _omitFieldNames ? '' : 'proServices')
+    ..aOB(9, _omitFieldNames ? '' : 'landscapeRegistered')
+    ..aOS(10, _omitFieldNames ? '' : 'serviceVersion')
+    ..aOS(11, _omitFieldNames ? '' : 'bootId')
+    ..aOS(12, _omitFieldNames ? '' : 'landscapeScriptUsers')
     ..hasRequiredFields = false
   ;
 
@@ -565,6 +4981,54 @@ class DistroInfo extends $pb.GeneratedMessage {
   $core.bool hasHostname() => $_has(5);
   @$pb.TagNumber(6)
   void clearHostname() => clearField(6);
+
+  @$pb.TagNumber(7)
+  $core.String get instanceId => $_getSZ(6);
+  @$pb.TagNumber(7)
+  set instanceId($core.String v) { $_setString(6, v); }
+  @$pb.TagNumber(7)
+  $core.bool hasInstanceId() => $_has(6);
+  @$pb.TagNumber(7)
+  void clearInstanceId() => clearField(7);
+
+  @$pb.TagNumber(8)
+  $core.List<$core.String> get proServices => $_getList(7);
+
+  @$pb.TagNumber(9)
+  $core.bool get landscapeRegistered => $_getBF(8);
+  @$pb.TagNumber(9)
+  set landscapeRegistered($core.bool v) { $_setBool(8, v); }
+  @$pb.TagNumber(9)
+  $core.bool hasLandscapeRegistered() => $_has(8);
+  @$pb.TagNumber(9)
+  void clearLandscapeRegistered() => clearField(9);
+
+  @$pb.TagNumber(10)
+  $core.String get serviceVersion => $_getSZ(9);
+  @$pb.TagNumber(10)
+  set serviceVersion($core.String v) { $_setString(9, v); }
+  @$pb.TagNumber(10)
+  $core.bool hasServiceVersion() => $_has(9);
+  @$pb.TagNumber(10)
+  void clearServiceVersion() => clearField(10);
+
+  @$pb.TagNumber(11)
+  $core.String get bootId => $_getSZ(10);
+  @$pb.TagNumber(11)
+  set bootId($core.String v) { $_setString(10, v); }
+  @$pb.TagNumber(11)
+  $core.bool hasBootId() => $_has(10);
+  @$pb.TagNumber(11)
+  void clearBootId() => clearField(11);
+
+  @$pb.TagNumber(12)
+  $core.String get landscapeScriptUsers => $_getSZ(11);
+  @$pb.TagNumber(12)
+  set landscapeScriptUsers($core.String v) { $_setString(11, v); }
+  @$pb.TagNumber(12)
+  $core.bool hasLandscapeScriptUsers() => $_has(11);
+  @$pb.TagNumber(12)
+  void clearLandscapeScriptUsers() => clearField(12);
 }
 
 class Port extends $pb.GeneratedMessage {
@@ -617,6 +5081,298 @@ class Port extends $pb.GeneratedMessage {
   void clearPort() => clearField(1);
 }
 
+class PluginTask extends $pb.GeneratedMessage {
+  factory PluginTask({
+    $core.String? type,
+    $core.String? distroName,
+    $core.List<$core.int>? payload,
+  }) {
+    final $result = create();
+    if (type != null) {
+      $result.type = type;
+    }
+    if (distroName != null) {
+      $result.distroName = distroName;
+    }
+    if (payload != null) {
+      $result.payload = payload;
+    }
+    return $result;
+  }
+  PluginTask._() : super();
+  factory PluginTask.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory PluginTask.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'PluginTask', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'type')
+    ..aOS(2, _omitFieldNames ? '' : 'distroName', protoName: 'distroName')
+    ..a<$core.List<$core.int>>(3, _omitFieldNames ? '' : 'payload', $pb.PbFieldType.OY)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  PluginTask clone() => PluginTask()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  PluginTask copyWith(void Function(PluginTask) updates) => super.copyWith((message) => updates(message as PluginTask)) as PluginTask;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static PluginTask create() => PluginTask._();
+  PluginTask createEmptyInstance() => create();
+  static $pb.PbList<PluginTask> createRepeated() => $pb.PbList<PluginTask>();
+  @$core.pragma('dart2js:noInline')
+  static PluginTask getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<PluginTask>(create);
+  static PluginTask? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get type => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set type($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasType() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearType() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get distroName => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set distroName($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasDistroName() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearDistroName() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.List<$core.int> get payload => $_getN(2);
+  @$pb.TagNumber(3)
+  set payload($core.List<$core.int> v) { $_setBytes(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasPayload() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearPayload() => clearField(3);
+}
+
+class PluginTaskResult extends $pb.GeneratedMessage {
+  factory PluginTaskResult({
+    $core.String? error,
+    $core.bool? retry,
+  }) {
+    final $result = create();
+    if (error != null) {
+      $result.error = error;
+    }
+    if (retry != null) {
+      $result.retry = retry;
+    }
+    return $result;
+  }
+  PluginTaskResult._() : super();
+  factory PluginTaskResult.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory PluginTaskResult.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'PluginTaskResult', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'error')
+    ..aOB(2, _omitFieldNames ? '' : 'retry')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  PluginTaskResult clone() => PluginTaskResult()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  PluginTaskResult copyWith(void Function(PluginTaskResult) updates) => super.copyWith((message) => updates(message as PluginTaskResult)) as PluginTaskResult;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static PluginTaskResult create() => PluginTaskResult._();
+  PluginTaskResult createEmptyInstance() => create();
+  static $pb.PbList<PluginTaskResult> createRepeated() => $pb.PbList<PluginTaskResult>();
+  @$core.pragma('dart2js:noInline')
+  static PluginTaskResult getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<PluginTaskResult>(create);
+  static PluginTaskResult? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get error => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set error($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasError() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearError() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.bool get retry => $_getBF(1);
+  @$pb.TagNumber(2)
+  set retry($core.bool v) { $_setBool(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasRetry() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearRetry() => clearField(2);
+}
+
+class VersionInfo extends $pb.GeneratedMessage {
+  factory VersionInfo({
+    $core.String? version,
+    $core.String? commit,
+    $core.String? buildDate,
+    $core.Iterable<ProtocolVersion>? protocols,
+  }) {
+    final $result = create();
+    if (version != null) {
+      $result.version = version;
+    }
+    if (commit != null) {
+      $result.commit = commit;
+    }
+    if (buildDate != null) {
+      $result.buildDate = buildDate;
+    }
+    if (protocols != null) {
+      $result.protocols.addAll(protocols);
+    }
+    return $result;
+  }
+  VersionInfo._() : super();
+  factory VersionInfo.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory VersionInfo.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'VersionInfo', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'version')
+    ..aOS(2, _omitFieldNames ? '' : 'commit')
+    ..aOS(3, _omitFieldNames ? '' : 'buildDate', protoName: 'buildDate')
+    ..pc<ProtocolVersion>(4, _omitFieldNames ? '' : 'protocols', $pb.PbFieldType.PM, subBuilder: ProtocolVersion.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  VersionInfo clone() => VersionInfo()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  VersionInfo copyWith(void Function(VersionInfo) updates) => super.copyWith((message) => updates(message as VersionInfo)) as VersionInfo;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static VersionInfo create() => VersionInfo._();
+  VersionInfo createEmptyInstance() => create();
+  static $pb.PbList<VersionInfo> createRepeated() => $pb.PbList<VersionInfo>();
+  @$core.pragma('dart2js:noInline')
+  static VersionInfo getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<VersionInfo>(create);
+  static VersionInfo? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get version => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set version($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasVersion() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearVersion() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get commit => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set commit($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasCommit() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearCommit() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get buildDate => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set buildDate($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasBuildDate() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearBuildDate() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.List<ProtocolVersion> get protocols => $_getList(3);
+}
+
+class ProtocolVersion extends $pb.GeneratedMessage {
+  factory ProtocolVersion({
+    $core.String? name,
+    $core.int? version,
+  }) {
+    final $result = create();
+    if (name != null) {
+      $result.name = name;
+    }
+    if (version != null) {
+      $result.version = version;
+    }
+    return $result;
+  }
+  ProtocolVersion._() : super();
+  factory ProtocolVersion.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory ProtocolVersion.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'ProtocolVersion', package: const $pb.PackageName(_omitMessageNames ? '' : 'agentapi'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'name')
+    ..a<$core.int>(2, _omitFieldNames ? '' : 'version', $pb.PbFieldType.OU3)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  ProtocolVersion clone() => ProtocolVersion()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  ProtocolVersion copyWith(void Function(ProtocolVersion) updates) => super.copyWith((message) => updates(message as ProtocolVersion)) as ProtocolVersion;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static ProtocolVersion create() => ProtocolVersion._();
+  ProtocolVersion createEmptyInstance() => create();
+  static $pb.PbList<ProtocolVersion> createRepeated() => $pb.PbList<ProtocolVersion>();
+  @$core.pragma('dart2js:noInline')
+  static ProtocolVersion getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<ProtocolVersion>(create);
+  static ProtocolVersion? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get name => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set name($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.int get version => $_getIZ(1);
+  @$pb.TagNumber(2)
+  set version($core.int v) { $_setUnsignedInt32(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasVersion() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearVersion() => clearField(2);
+}
+
 
 const _omitFieldNames = $core.bool.fromEnvironment('protobuf.omit_field_names');
 const _omitMessageNames = $core.bool.fromEnvironment('protobuf.omit_message_names');
@@ -25,6 +25,10 @@ class UIClient extends $grpc.Client {
       '/agentapi.UI/ApplyProToken',
       ($0.ProAttachInfo value) => value.writeToBuffer(),
       ($core.List<$core.int> value) => $0.SubscriptionInfo.fromBuffer(value));
+  static final _$applyDistroProToken = $grpc.ClientMethod<$0.DistroProToken, $0.Empty>(
+      '/agentapi.UI/ApplyDistroProToken',
+      ($0.DistroProToken value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
   static final _$applyLandscapeConfig = $grpc.ClientMethod<$0.LandscapeConfig, $0.LandscapeSource>(
       '/agentapi.UI/ApplyLandscapeConfig',
       ($0.LandscapeConfig value) => value.writeToBuffer(),
@@ -41,6 +45,194 @@ class UIClient extends $grpc.Client {
       '/agentapi.UI/NotifyPurchase',
       ($0.Empty value) => value.writeToBuffer(),
       ($core.List<$core.int> value) => $0.SubscriptionInfo.fromBuffer(value));
+  static final _$getSubscriptionDetails = $grpc.ClientMethod<$0.Empty, $0.SubscriptionDetails>(
+      '/agentapi.UI/GetSubscriptionDetails',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.SubscriptionDetails.fromBuffer(value));
+  static final _$validateConfig = $grpc.ClientMethod<$0.Empty, $0.ConfigProblems>(
+      '/agentapi.UI/ValidateConfig',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.ConfigProblems.fromBuffer(value));
+  static final _$getFeatureFlags = $grpc.ClientMethod<$0.Empty, $0.FeatureFlags>(
+      '/agentapi.UI/GetFeatureFlags',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.FeatureFlags.fromBuffer(value));
+  static final _$applyLandscapeDistroOverride = $grpc.ClientMethod<$0.LandscapeDistroOverride, $0.Empty>(
+      '/agentapi.UI/ApplyLandscapeDistroOverride',
+      ($0.LandscapeDistroOverride value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$getLandscapeDistroOverrides = $grpc.ClientMethod<$0.Empty, $0.LandscapeDistroOverrides>(
+      '/agentapi.UI/GetLandscapeDistroOverrides',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.LandscapeDistroOverrides.fromBuffer(value));
+  static final _$getFleetStatus = $grpc.ClientMethod<$0.Empty, $0.FleetStatus>(
+      '/agentapi.UI/GetFleetStatus',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.FleetStatus.fromBuffer(value));
+  static final _$watchDistros = $grpc.ClientMethod<$0.Empty, $0.DistroChange>(
+      '/agentapi.UI/WatchDistros',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.DistroChange.fromBuffer(value));
+  static final _$exportDistro = $grpc.ClientMethod<$0.ExportRequest, $0.ExportProgress>(
+      '/agentapi.UI/ExportDistro',
+      ($0.ExportRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.ExportProgress.fromBuffer(value));
+  static final _$setBackupSchedule = $grpc.ClientMethod<$0.BackupSchedule, $0.Empty>(
+      '/agentapi.UI/SetBackupSchedule',
+      ($0.BackupSchedule value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$getBackupSchedule = $grpc.ClientMethod<$0.Empty, $0.BackupSchedule>(
+      '/agentapi.UI/GetBackupSchedule',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.BackupSchedule.fromBuffer(value));
+  static final _$resetDistro = $grpc.ClientMethod<$0.ResetRequest, $0.Empty>(
+      '/agentapi.UI/ResetDistro',
+      ($0.ResetRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$resetAgent = $grpc.ClientMethod<$0.Empty, $0.Empty>(
+      '/agentapi.UI/ResetAgent',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$reloadConfig = $grpc.ClientMethod<$0.Empty, $0.ReloadResult>(
+      '/agentapi.UI/ReloadConfig',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.ReloadResult.fromBuffer(value));
+  static final _$applyDistroWSLSettings = $grpc.ClientMethod<$0.DistroWSLSettings, $0.Empty>(
+      '/agentapi.UI/ApplyDistroWSLSettings',
+      ($0.DistroWSLSettings value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$setPauseState = $grpc.ClientMethod<$0.PauseState, $0.Empty>(
+      '/agentapi.UI/SetPauseState',
+      ($0.PauseState value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$getPauseState = $grpc.ClientMethod<$0.Empty, $0.PauseState>(
+      '/agentapi.UI/GetPauseState',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.PauseState.fromBuffer(value));
+  static final _$applyLandscapeEndpoint = $grpc.ClientMethod<$0.LandscapeEndpoint, $0.Empty>(
+      '/agentapi.UI/ApplyLandscapeEndpoint',
+      ($0.LandscapeEndpoint value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$setNotificationPreference = $grpc.ClientMethod<$0.NotificationPreference, $0.Empty>(
+      '/agentapi.UI/SetNotificationPreference',
+      ($0.NotificationPreference value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$getNotificationPreferences = $grpc.ClientMethod<$0.Empty, $0.NotificationPreferences>(
+      '/agentapi.UI/GetNotificationPreferences',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.NotificationPreferences.fromBuffer(value));
+  static final _$setApprovalPreference = $grpc.ClientMethod<$0.ApprovalPreference, $0.Empty>(
+      '/agentapi.UI/SetApprovalPreference',
+      ($0.ApprovalPreference value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$getApprovalPreferences = $grpc.ClientMethod<$0.Empty, $0.ApprovalPreferences>(
+      '/agentapi.UI/GetApprovalPreferences',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.ApprovalPreferences.fromBuffer(value));
+  static final _$getPendingApprovals = $grpc.ClientMethod<$0.Empty, $0.PendingApprovals>(
+      '/agentapi.UI/GetPendingApprovals',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.PendingApprovals.fromBuffer(value));
+  static final _$approveTask = $grpc.ClientMethod<$0.ApprovalDecision, $0.Empty>(
+      '/agentapi.UI/ApproveTask',
+      ($0.ApprovalDecision value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$denyTask = $grpc.ClientMethod<$0.ApprovalDecision, $0.Empty>(
+      '/agentapi.UI/DenyTask',
+      ($0.ApprovalDecision value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$submitPluginTask = $grpc.ClientMethod<$0.PluginTaskSubmission, $0.Empty>(
+      '/agentapi.UI/SubmitPluginTask',
+      ($0.PluginTaskSubmission value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$compactDistro = $grpc.ClientMethod<$0.CompactRequest, $0.CompactResult>(
+      '/agentapi.UI/CompactDistro',
+      ($0.CompactRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.CompactResult.fromBuffer(value));
+  static final _$wakeDistro = $grpc.ClientMethod<$0.WakeRequest, $0.Empty>(
+      '/agentapi.UI/WakeDistro',
+      ($0.WakeRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$stopDistro = $grpc.ClientMethod<$0.StopRequest, $0.Empty>(
+      '/agentapi.UI/StopDistro',
+      ($0.StopRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$setDistroLabels = $grpc.ClientMethod<$0.DistroLabels, $0.Empty>(
+      '/agentapi.UI/SetDistroLabels',
+      ($0.DistroLabels value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$setDistroSparse = $grpc.ClientMethod<$0.SparseRequest, $0.Empty>(
+      '/agentapi.UI/SetDistroSparse',
+      ($0.SparseRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$setCompactSchedule = $grpc.ClientMethod<$0.CompactSchedule, $0.Empty>(
+      '/agentapi.UI/SetCompactSchedule',
+      ($0.CompactSchedule value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$getCompactSchedule = $grpc.ClientMethod<$0.Empty, $0.CompactSchedule>(
+      '/agentapi.UI/GetCompactSchedule',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.CompactSchedule.fromBuffer(value));
+  static final _$getWSLConfig = $grpc.ClientMethod<$0.Empty, $0.WSLConfig>(
+      '/agentapi.UI/GetWSLConfig',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.WSLConfig.fromBuffer(value));
+  static final _$setWSLConfig = $grpc.ClientMethod<$0.WSLConfig, $0.WSLConfig>(
+      '/agentapi.UI/SetWSLConfig',
+      ($0.WSLConfig value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.WSLConfig.fromBuffer(value));
+  static final _$updateWSL = $grpc.ClientMethod<$0.Empty, $0.Empty>(
+      '/agentapi.UI/UpdateWSL',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$createOperation = $grpc.ClientMethod<$0.OperationRequest, $0.Operation>(
+      '/agentapi.UI/CreateOperation',
+      ($0.OperationRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Operation.fromBuffer(value));
+  static final _$getOperation = $grpc.ClientMethod<$0.OperationID, $0.Operation>(
+      '/agentapi.UI/GetOperation',
+      ($0.OperationID value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Operation.fromBuffer(value));
+  static final _$cancelOperation = $grpc.ClientMethod<$0.OperationID, $0.Operation>(
+      '/agentapi.UI/CancelOperation',
+      ($0.OperationID value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Operation.fromBuffer(value));
+  static final _$listOperations = $grpc.ClientMethod<$0.Empty, $0.Operations>(
+      '/agentapi.UI/ListOperations',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Operations.fromBuffer(value));
+  static final _$requestConfirmation = $grpc.ClientMethod<$0.ConfirmationRequest, $0.Confirmation>(
+      '/agentapi.UI/RequestConfirmation',
+      ($0.ConfirmationRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Confirmation.fromBuffer(value));
+  static final _$getDistroLogs = $grpc.ClientMethod<$0.DistroLogsRequest, $0.DistroLogs>(
+      '/agentapi.UI/GetDistroLogs',
+      ($0.DistroLogsRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.DistroLogs.fromBuffer(value));
+  static final _$pullDistroFile = $grpc.ClientMethod<$0.DistroFileRequest, $0.DistroFile>(
+      '/agentapi.UI/PullDistroFile',
+      ($0.DistroFileRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.DistroFile.fromBuffer(value));
+  static final _$getDistroTasks = $grpc.ClientMethod<$0.DistroTasksRequest, $0.DistroTasks>(
+      '/agentapi.UI/GetDistroTasks',
+      ($0.DistroTasksRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.DistroTasks.fromBuffer(value));
+  static final _$cancelDistroTask = $grpc.ClientMethod<$0.CancelTaskRequest, $0.Empty>(
+      '/agentapi.UI/CancelDistroTask',
+      ($0.CancelTaskRequest value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.Empty.fromBuffer(value));
+  static final _$getOnboardingState = $grpc.ClientMethod<$0.Empty, $0.OnboardingState>(
+      '/agentapi.UI/GetOnboardingState',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.OnboardingState.fromBuffer(value));
+  static final _$skipLandscapeOnboarding = $grpc.ClientMethod<$0.Empty, $0.OnboardingState>(
+      '/agentapi.UI/SkipLandscapeOnboarding',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.OnboardingState.fromBuffer(value));
+  static final _$getVersion = $grpc.ClientMethod<$0.Empty, $0.VersionInfo>(
+      '/agentapi.UI/GetVersion',
+      ($0.Empty value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.VersionInfo.fromBuffer(value));
 
   UIClient($grpc.ClientChannel channel,
       {$grpc.CallOptions? options,
@@ -52,6 +244,10 @@ class UIClient extends $grpc.Client {
     return $createUnaryCall(_$applyProToken, request, options: options);
   }
 
+  $grpc.ResponseFuture<$0.Empty> applyDistroProToken($0.DistroProToken request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$applyDistroProToken, request, options: options);
+  }
+
   $grpc.ResponseFuture<$0.LandscapeSource> applyLandscapeConfig($0.LandscapeConfig request, {$grpc.CallOptions? options}) {
     return $createUnaryCall(_$applyLandscapeConfig, request, options: options);
   }
@@ -67,6 +263,194 @@ class UIClient extends $grpc.Client {
   $grpc.ResponseFuture<$0.SubscriptionInfo> notifyPurchase($0.Empty request, {$grpc.CallOptions? options}) {
     return $createUnaryCall(_$notifyPurchase, request, options: options);
   }
+
+  $grpc.ResponseFuture<$0.SubscriptionDetails> getSubscriptionDetails($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getSubscriptionDetails, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.ConfigProblems> validateConfig($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$validateConfig, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.FeatureFlags> getFeatureFlags($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getFeatureFlags, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> applyLandscapeDistroOverride($0.LandscapeDistroOverride request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$applyLandscapeDistroOverride, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.LandscapeDistroOverrides> getLandscapeDistroOverrides($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getLandscapeDistroOverrides, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.FleetStatus> getFleetStatus($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getFleetStatus, request, options: options);
+  }
+
+  $grpc.ResponseStream<$0.DistroChange> watchDistros($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createStreamingCall(_$watchDistros, $async.Stream.fromIterable([request]), options: options);
+  }
+
+  $grpc.ResponseStream<$0.ExportProgress> exportDistro($0.ExportRequest request, {$grpc.CallOptions? options}) {
+    return $createStreamingCall(_$exportDistro, $async.Stream.fromIterable([request]), options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> setBackupSchedule($0.BackupSchedule request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$setBackupSchedule, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.BackupSchedule> getBackupSchedule($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getBackupSchedule, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> resetDistro($0.ResetRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$resetDistro, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> resetAgent($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$resetAgent, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.ReloadResult> reloadConfig($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$reloadConfig, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> applyDistroWSLSettings($0.DistroWSLSettings request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$applyDistroWSLSettings, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> setPauseState($0.PauseState request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$setPauseState, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.PauseState> getPauseState($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getPauseState, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> applyLandscapeEndpoint($0.LandscapeEndpoint request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$applyLandscapeEndpoint, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> setNotificationPreference($0.NotificationPreference request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$setNotificationPreference, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.NotificationPreferences> getNotificationPreferences($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getNotificationPreferences, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> setApprovalPreference($0.ApprovalPreference request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$setApprovalPreference, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.ApprovalPreferences> getApprovalPreferences($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getApprovalPreferences, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.PendingApprovals> getPendingApprovals($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getPendingApprovals, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> approveTask($0.ApprovalDecision request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$approveTask, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> denyTask($0.ApprovalDecision request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$denyTask, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> submitPluginTask($0.PluginTaskSubmission request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$submitPluginTask, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.CompactResult> compactDistro($0.CompactRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$compactDistro, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> wakeDistro($0.WakeRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$wakeDistro, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> stopDistro($0.StopRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$stopDistro, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> setDistroLabels($0.DistroLabels request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$setDistroLabels, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> setDistroSparse($0.SparseRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$setDistroSparse, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> setCompactSchedule($0.CompactSchedule request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$setCompactSchedule, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.CompactSchedule> getCompactSchedule($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getCompactSchedule, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.WSLConfig> getWSLConfig($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getWSLConfig, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.WSLConfig> setWSLConfig($0.WSLConfig request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$setWSLConfig, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> updateWSL($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$updateWSL, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Operation> createOperation($0.OperationRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$createOperation, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Operation> getOperation($0.OperationID request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getOperation, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Operation> cancelOperation($0.OperationID request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$cancelOperation, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Operations> listOperations($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$listOperations, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Confirmation> requestConfirmation($0.ConfirmationRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$requestConfirmation, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.DistroLogs> getDistroLogs($0.DistroLogsRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getDistroLogs, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.DistroFile> pullDistroFile($0.DistroFileRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$pullDistroFile, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.DistroTasks> getDistroTasks($0.DistroTasksRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getDistroTasks, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.Empty> cancelDistroTask($0.CancelTaskRequest request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$cancelDistroTask, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.OnboardingState> getOnboardingState($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getOnboardingState, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.OnboardingState> skipLandscapeOnboarding($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$skipLandscapeOnboarding, request, options: options);
+  }
+
+  $grpc.ResponseFuture<$0.VersionInfo> getVersion($0.Empty request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$getVersion, request, options: options);
+  }
 }
 
 @$pb.GrpcServiceName('agentapi.UI')
@@ -81,6 +465,13 @@ abstract class UIServiceBase extends $grpc.Service {
         false,
         ($core.List<$core.int> value) => $0.ProAttachInfo.fromBuffer(value),
         ($0.SubscriptionInfo value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.DistroProToken, $0.Empty>(
+        'ApplyDistroProToken',
+        applyDistroProToken_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.DistroProToken.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
     $addMethod($grpc.ServiceMethod<$0.LandscapeConfig, $0.LandscapeSource>(
         'ApplyLandscapeConfig',
         applyLandscapeConfig_Pre,
@@ -109,12 +500,345 @@ abstract class UIServiceBase extends $grpc.Service {
         false,
         ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
         ($0.SubscriptionInfo value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.SubscriptionDetails>(
+        'GetSubscriptionDetails',
+        getSubscriptionDetails_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.SubscriptionDetails value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.ConfigProblems>(
+        'ValidateConfig',
+        validateConfig_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.ConfigProblems value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.FeatureFlags>(
+        'GetFeatureFlags',
+        getFeatureFlags_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.FeatureFlags value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.LandscapeDistroOverride, $0.Empty>(
+        'ApplyLandscapeDistroOverride',
+        applyLandscapeDistroOverride_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.LandscapeDistroOverride.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.LandscapeDistroOverrides>(
+        'GetLandscapeDistroOverrides',
+        getLandscapeDistroOverrides_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.LandscapeDistroOverrides value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.FleetStatus>(
+        'GetFleetStatus',
+        getFleetStatus_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.FleetStatus value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.DistroChange>(
+        'WatchDistros',
+        watchDistros_Pre,
+        false,
+        true,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.DistroChange value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.ExportRequest, $0.ExportProgress>(
+        'ExportDistro',
+        exportDistro_Pre,
+        false,
+        true,
+        ($core.List<$core.int> value) => $0.ExportRequest.fromBuffer(value),
+        ($0.ExportProgress value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.BackupSchedule, $0.Empty>(
+        'SetBackupSchedule',
+        setBackupSchedule_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.BackupSchedule.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.BackupSchedule>(
+        'GetBackupSchedule',
+        getBackupSchedule_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.BackupSchedule value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.ResetRequest, $0.Empty>(
+        'ResetDistro',
+        resetDistro_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.ResetRequest.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.Empty>(
+        'ResetAgent',
+        resetAgent_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.ReloadResult>(
+        'ReloadConfig',
+        reloadConfig_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.ReloadResult value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.DistroWSLSettings, $0.Empty>(
+        'ApplyDistroWSLSettings',
+        applyDistroWSLSettings_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.DistroWSLSettings.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.PauseState, $0.Empty>(
+        'SetPauseState',
+        setPauseState_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.PauseState.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.PauseState>(
+        'GetPauseState',
+        getPauseState_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.PauseState value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.LandscapeEndpoint, $0.Empty>(
+        'ApplyLandscapeEndpoint',
+        applyLandscapeEndpoint_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.LandscapeEndpoint.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.NotificationPreference, $0.Empty>(
+        'SetNotificationPreference',
+        setNotificationPreference_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.NotificationPreference.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.NotificationPreferences>(
+        'GetNotificationPreferences',
+        getNotificationPreferences_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.NotificationPreferences value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.ApprovalPreference, $0.Empty>(
+        'SetApprovalPreference',
+        setApprovalPreference_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.ApprovalPreference.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.ApprovalPreferences>(
+        'GetApprovalPreferences',
+        getApprovalPreferences_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.ApprovalPreferences value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.PendingApprovals>(
+        'GetPendingApprovals',
+        getPendingApprovals_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.PendingApprovals value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.ApprovalDecision, $0.Empty>(
+        'ApproveTask',
+        approveTask_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.ApprovalDecision.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.ApprovalDecision, $0.Empty>(
+        'DenyTask',
+        denyTask_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.ApprovalDecision.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.PluginTaskSubmission, $0.Empty>(
+        'SubmitPluginTask',
+        submitPluginTask_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.PluginTaskSubmission.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.CompactRequest, $0.CompactResult>(
+        'CompactDistro',
+        compactDistro_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.CompactRequest.fromBuffer(value),
+        ($0.CompactResult value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.WakeRequest, $0.Empty>(
+        'WakeDistro',
+        wakeDistro_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.WakeRequest.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.StopRequest, $0.Empty>(
+        'StopDistro',
+        stopDistro_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.StopRequest.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.DistroLabels, $0.Empty>(
+        'SetDistroLabels',
+        setDistroLabels_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.DistroLabels.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.SparseRequest, $0.Empty>(
+        'SetDistroSparse',
+        setDistroSparse_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.SparseRequest.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.CompactSchedule, $0.Empty>(
+        'SetCompactSchedule',
+        setCompactSchedule_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.CompactSchedule.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.CompactSchedule>(
+        'GetCompactSchedule',
+        getCompactSchedule_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.CompactSchedule value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.WSLConfig>(
+        'GetWSLConfig',
+        getWSLConfig_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.WSLConfig value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.WSLConfig, $0.WSLConfig>(
+        'SetWSLConfig',
+        setWSLConfig_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.WSLConfig.fromBuffer(value),
+        ($0.WSLConfig value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.Empty>(
+        'UpdateWSL',
+        updateWSL_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.OperationRequest, $0.Operation>(
+        'CreateOperation',
+        createOperation_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.OperationRequest.fromBuffer(value),
+        ($0.Operation value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.OperationID, $0.Operation>(
+        'GetOperation',
+        getOperation_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.OperationID.fromBuffer(value),
+        ($0.Operation value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.OperationID, $0.Operation>(
+        'CancelOperation',
+        cancelOperation_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.OperationID.fromBuffer(value),
+        ($0.Operation value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.Operations>(
+        'ListOperations',
+        listOperations_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.Operations value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.ConfirmationRequest, $0.Confirmation>(
+        'RequestConfirmation',
+        requestConfirmation_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.ConfirmationRequest.fromBuffer(value),
+        ($0.Confirmation value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.DistroLogsRequest, $0.DistroLogs>(
+        'GetDistroLogs',
+        getDistroLogs_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.DistroLogsRequest.fromBuffer(value),
+        ($0.DistroLogs value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.DistroFileRequest, $0.DistroFile>(
+        'PullDistroFile',
+        pullDistroFile_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.DistroFileRequest.fromBuffer(value),
+        ($0.DistroFile value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.DistroTasksRequest, $0.DistroTasks>(
+        'GetDistroTasks',
+        getDistroTasks_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.DistroTasksRequest.fromBuffer(value),
+        ($0.DistroTasks value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.CancelTaskRequest, $0.Empty>(
+        'CancelDistroTask',
+        cancelDistroTask_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.CancelTaskRequest.fromBuffer(value),
+        ($0.Empty value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.OnboardingState>(
+        'GetOnboardingState',
+        getOnboardingState_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.OnboardingState value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.OnboardingState>(
+        'SkipLandscapeOnboarding',
+        skipLandscapeOnboarding_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.OnboardingState value) => value.writeToBuffer()));
+    $addMethod($grpc.ServiceMethod<$0.Empty, $0.VersionInfo>(
+        'GetVersion',
+        getVersion_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.Empty.fromBuffer(value),
+        ($0.VersionInfo value) => value.writeToBuffer()));
   }
 
   $async.Future<$0.SubscriptionInfo> applyProToken_Pre($grpc.ServiceCall call, $async.Future<$0.ProAttachInfo> request) async {
     return applyProToken(call, await request);
   }
 
+  $async.Future<$0.Empty> applyDistroProToken_Pre($grpc.ServiceCall call, $async.Future<$0.DistroProToken> request) async {
+    return applyDistroProToken(call, await request);
+  }
+
   $async.Future<$0.LandscapeSource> applyLandscapeConfig_Pre($grpc.ServiceCall call, $async.Future<$0.LandscapeConfig> request) async {
     return applyLandscapeConfig(call, await request);
   }
@@ -131,11 +855,247 @@ abstract class UIServiceBase extends $grpc.Service {
     return notifyPurchase(call, await request);
   }
 
+  $async.Future<$0.SubscriptionDetails> getSubscriptionDetails_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getSubscriptionDetails(call, await request);
+  }
+
+  $async.Future<$0.ConfigProblems> validateConfig_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return validateConfig(call, await request);
+  }
+
+  $async.Future<$0.FeatureFlags> getFeatureFlags_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getFeatureFlags(call, await request);
+  }
+
+  $async.Future<$0.Empty> applyLandscapeDistroOverride_Pre($grpc.ServiceCall call, $async.Future<$0.LandscapeDistroOverride> request) async {
+    return applyLandscapeDistroOverride(call, await request);
+  }
+
+  $async.Future<$0.LandscapeDistroOverrides> getLandscapeDistroOverrides_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getLandscapeDistroOverrides(call, await request);
+  }
+
+  $async.Future<$0.FleetStatus> getFleetStatus_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getFleetStatus(call, await request);
+  }
+
+  $async.Stream<$0.DistroChange> watchDistros_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async* {
+    yield* watchDistros(call, await request);
+  }
+
+  $async.Stream<$0.ExportProgress> exportDistro_Pre($grpc.ServiceCall call, $async.Future<$0.ExportRequest> request) async* {
+    yield* exportDistro(call, await request);
+  }
+
+  $async.Future<$0.Empty> setBackupSchedule_Pre($grpc.ServiceCall call, $async.Future<$0.BackupSchedule> request) async {
+    return setBackupSchedule(call, await request);
+  }
+
+  $async.Future<$0.BackupSchedule> getBackupSchedule_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getBackupSchedule(call, await request);
+  }
+
+  $async.Future<$0.Empty> resetDistro_Pre($grpc.ServiceCall call, $async.Future<$0.ResetRequest> request) async {
+    return resetDistro(call, await request);
+  }
+
+  $async.Future<$0.Empty> resetAgent_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return resetAgent(call, await request);
+  }
+
+  $async.Future<$0.ReloadResult> reloadConfig_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return reloadConfig(call, await request);
+  }
+
+  $async.Future<$0.Empty> applyDistroWSLSettings_Pre($grpc.ServiceCall call, $async.Future<$0.DistroWSLSettings> request) async {
+    return applyDistroWSLSettings(call, await request);
+  }
+
+  $async.Future<$0.Empty> setPauseState_Pre($grpc.ServiceCall call, $async.Future<$0.PauseState> request) async {
+    return setPauseState(call, await request);
+  }
+
+  $async.Future<$0.PauseState> getPauseState_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getPauseState(call, await request);
+  }
+
+  $async.Future<$0.Empty> applyLandscapeEndpoint_Pre($grpc.ServiceCall call, $async.Future<$0.LandscapeEndpoint> request) async {
+    return applyLandscapeEndpoint(call, await request);
+  }
+
+  $async.Future<$0.Empty> setNotificationPreference_Pre($grpc.ServiceCall call, $async.Future<$0.NotificationPreference> request) async {
+    return setNotificationPreference(call, await request);
+  }
+
+  $async.Future<$0.NotificationPreferences> getNotificationPreferences_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getNotificationPreferences(call, await request);
+  }
+
+  $async.Future<$0.Empty> setApprovalPreference_Pre($grpc.ServiceCall call, $async.Future<$0.ApprovalPreference> request) async {
+    return setApprovalPreference(call, await request);
+  }
+
+  $async.Future<$0.ApprovalPreferences> getApprovalPreferences_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getApprovalPreferences(call, await request);
+  }
+
+  $async.Future<$0.PendingApprovals> getPendingApprovals_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getPendingApprovals(call, await request);
+  }
+
+  $async.Future<$0.Empty> approveTask_Pre($grpc.ServiceCall call, $async.Future<$0.ApprovalDecision> request) async {
+    return approveTask(call, await request);
+  }
+
+  $async.Future<$0.Empty> denyTask_Pre($grpc.ServiceCall call, $async.Future<$0.ApprovalDecision> request) async {
+    return denyTask(call, await request);
+  }
+
+  $async.Future<$0.Empty> submitPluginTask_Pre($grpc.ServiceCall call, $async.Future<$0.PluginTaskSubmission> request) async {
+    return submitPluginTask(call, await request);
+  }
+
+  $async.Future<$0.CompactResult> compactDistro_Pre($grpc.ServiceCall call, $async.Future<$0.CompactRequest> request) async {
+    return compactDistro(call, await request);
+  }
+
+  $async.Future<$0.Empty> wakeDistro_Pre($grpc.ServiceCall call, $async.Future<$0.WakeRequest> request) async {
+    return wakeDistro(call, await request);
+  }
+
+  $async.Future<$0.Empty> stopDistro_Pre($grpc.ServiceCall call, $async.Future<$0.StopRequest> request) async {
+    return stopDistro(call, await request);
+  }
+
+  $async.Future<$0.Empty> setDistroLabels_Pre($grpc.ServiceCall call, $async.Future<$0.DistroLabels> request) async {
+    return setDistroLabels(call, await request);
+  }
+
+  $async.Future<$0.Empty> setDistroSparse_Pre($grpc.ServiceCall call, $async.Future<$0.SparseRequest> request) async {
+    return setDistroSparse(call, await request);
+  }
+
+  $async.Future<$0.Empty> setCompactSchedule_Pre($grpc.ServiceCall call, $async.Future<$0.CompactSchedule> request) async {
+    return setCompactSchedule(call, await request);
+  }
+
+  $async.Future<$0.CompactSchedule> getCompactSchedule_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getCompactSchedule(call, await request);
+  }
+
+  $async.Future<$0.WSLConfig> getWSLConfig_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getWSLConfig(call, await request);
+  }
+
+  $async.Future<$0.WSLConfig> setWSLConfig_Pre($grpc.ServiceCall call, $async.Future<$0.WSLConfig> request) async {
+    return setWSLConfig(call, await request);
+  }
+
+  $async.Future<$0.Empty> updateWSL_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return updateWSL(call, await request);
+  }
+
+  $async.Future<$0.Operation> createOperation_Pre($grpc.ServiceCall call, $async.Future<$0.OperationRequest> request) async {
+    return createOperation(call, await request);
+  }
+
+  $async.Future<$0.Operation> getOperation_Pre($grpc.ServiceCall call, $async.Future<$0.OperationID> request) async {
+    return getOperation(call, await request);
+  }
+
+  $async.Future<$0.Operation> cancelOperation_Pre($grpc.ServiceCall call, $async.Future<$0.OperationID> request) async {
+    return cancelOperation(call, await request);
+  }
+
+  $async.Future<$0.Operations> listOperations_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return listOperations(call, await request);
+  }
+
+  $async.Future<$0.Confirmation> requestConfirmation_Pre($grpc.ServiceCall call, $async.Future<$0.ConfirmationRequest> request) async {
+    return requestConfirmation(call, await request);
+  }
+
+  $async.Future<$0.DistroLogs> getDistroLogs_Pre($grpc.ServiceCall call, $async.Future<$0.DistroLogsRequest> request) async {
+    return getDistroLogs(call, await request);
+  }
+
+  $async.Future<$0.DistroFile> pullDistroFile_Pre($grpc.ServiceCall call, $async.Future<$0.DistroFileRequest> request) async {
+    return pullDistroFile(call, await request);
+  }
+
+  $async.Future<$0.DistroTasks> getDistroTasks_Pre($grpc.ServiceCall call, $async.Future<$0.DistroTasksRequest> request) async {
+    return getDistroTasks(call, await request);
+  }
+
+  $async.Future<$0.Empty> cancelDistroTask_Pre($grpc.ServiceCall call, $async.Future<$0.CancelTaskRequest> request) async {
+    return cancelDistroTask(call, await request);
+  }
+
+  $async.Future<$0.OnboardingState> getOnboardingState_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getOnboardingState(call, await request);
+  }
+
+  $async.Future<$0.OnboardingState> skipLandscapeOnboarding_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return skipLandscapeOnboarding(call, await request);
+  }
+
+  $async.Future<$0.VersionInfo> getVersion_Pre($grpc.ServiceCall call, $async.Future<$0.Empty> request) async {
+    return getVersion(call, await request);
+  }
+
   $async.Future<$0.SubscriptionInfo> applyProToken($grpc.ServiceCall call, $0.ProAttachInfo request);
+  $async.Future<$0.Empty> applyDistroProToken($grpc.ServiceCall call, $0.DistroProToken request);
   $async.Future<$0.LandscapeSource> applyLandscapeConfig($grpc.ServiceCall call, $0.LandscapeConfig request);
   $async.Future<$0.Empty> ping($grpc.ServiceCall call, $0.Empty request);
   $async.Future<$0.ConfigSources> getConfigSources($grpc.ServiceCall call, $0.Empty request);
   $async.Future<$0.SubscriptionInfo> notifyPurchase($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.SubscriptionDetails> getSubscriptionDetails($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.ConfigProblems> validateConfig($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.FeatureFlags> getFeatureFlags($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.Empty> applyLandscapeDistroOverride($grpc.ServiceCall call, $0.LandscapeDistroOverride request);
+  $async.Future<$0.LandscapeDistroOverrides> getLandscapeDistroOverrides($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.FleetStatus> getFleetStatus($grpc.ServiceCall call, $0.Empty request);
+  $async.Stream<$0.DistroChange> watchDistros($grpc.ServiceCall call, $0.Empty request);
+  $async.Stream<$0.ExportProgress> exportDistro($grpc.ServiceCall call, $0.ExportRequest request);
+  $async.Future<$0.Empty> setBackupSchedule($grpc.ServiceCall call, $0.BackupSchedule request);
+  $async.Future<$0.BackupSchedule> getBackupSchedule($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.Empty> resetDistro($grpc.ServiceCall call, $0.ResetRequest request);
+  $async.Future<$0.Empty> resetAgent($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.ReloadResult> reloadConfig($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.Empty> applyDistroWSLSettings($grpc.ServiceCall call, $0.DistroWSLSettings request);
+  $async.Future<$0.Empty> setPauseState($grpc.ServiceCall call, $0.PauseState request);
+  $async.Future<$0.PauseState> getPauseState($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.Empty> applyLandscapeEndpoint($grpc.ServiceCall call, $0.LandscapeEndpoint request);
+  $async.Future<$0.Empty> setNotificationPreference($grpc.ServiceCall call, $0.NotificationPreference request);
+  $async.Future<$0.NotificationPreferences> getNotificationPreferences($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.Empty> setApprovalPreference($grpc.ServiceCall call, $0.ApprovalPreference request);
+  $async.Future<$0.ApprovalPreferences> getApprovalPreferences($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.PendingApprovals> getPendingApprovals($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.Empty> approveTask($grpc.ServiceCall call, $0.ApprovalDecision request);
+  $async.Future<$0.Empty> denyTask($grpc.ServiceCall call, $0.ApprovalDecision request);
+  $async.Future<$0.Empty> submitPluginTask($grpc.ServiceCall call, $0.PluginTaskSubmission request);
+  $async.Future<$0.CompactResult> compactDistro($grpc.ServiceCall call, $0.CompactRequest request);
+  $async.Future<$0.Empty> wakeDistro($grpc.ServiceCall call, $0.WakeRequest request);
+  $async.Future<$0.Empty> stopDistro($grpc.ServiceCall call, $0.StopRequest request);
+  $async.Future<$0.Empty> setDistroLabels($grpc.ServiceCall call, $0.DistroLabels request);
+  $async.Future<$0.Empty> setDistroSparse($grpc.ServiceCall call, $0.SparseRequest request);
+  $async.Future<$0.Empty> setCompactSchedule($grpc.ServiceCall call, $0.CompactSchedule request);
+  $async.Future<$0.CompactSchedule> getCompactSchedule($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.WSLConfig> getWSLConfig($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.WSLConfig> setWSLConfig($grpc.ServiceCall call, $0.WSLConfig request);
+  $async.Future<$0.Empty> updateWSL($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.Operation> createOperation($grpc.ServiceCall call, $0.OperationRequest request);
+  $async.Future<$0.Operation> getOperation($grpc.ServiceCall call, $0.OperationID request);
+  $async.Future<$0.Operation> cancelOperation($grpc.ServiceCall call, $0.OperationID request);
+  $async.Future<$0.Operations> listOperations($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.Confirmation> requestConfirmation($grpc.ServiceCall call, $0.ConfirmationRequest request);
+  $async.Future<$0.DistroLogs> getDistroLogs($grpc.ServiceCall call, $0.DistroLogsRequest request);
+  $async.Future<$0.DistroFile> pullDistroFile($grpc.ServiceCall call, $0.DistroFileRequest request);
+  $async.Future<$0.DistroTasks> getDistroTasks($grpc.ServiceCall call, $0.DistroTasksRequest request);
+  $async.Future<$0.Empty> cancelDistroTask($grpc.ServiceCall call, $0.CancelTaskRequest request);
+  $async.Future<$0.OnboardingState> getOnboardingState($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.OnboardingState> skipLandscapeOnboarding($grpc.ServiceCall call, $0.Empty request);
+  $async.Future<$0.VersionInfo> getVersion($grpc.ServiceCall call, $0.Empty request);
 }
 @$pb.GrpcServiceName('agentapi.WSLInstance')
 class WSLInstanceClient extends $grpc.Client {
@@ -171,3 +1131,41 @@ abstract class WSLInstanceServiceBase extends $grpc.Service {
 
   $async.Stream<$0.Port> connected($grpc.ServiceCall call, $async.Stream<$0.DistroInfo> request);
 }
+@$pb.GrpcServiceName('agentapi.TaskPlugin')
+class TaskPluginClient extends $grpc.Client {
+  static final _$executeTask = $grpc.ClientMethod<$0.PluginTask, $0.PluginTaskResult>(
+      '/agentapi.TaskPlugin/ExecuteTask',
+      ($0.PluginTask value) => value.writeToBuffer(),
+      ($core.List<$core.int> value) => $0.PluginTaskResult.fromBuffer(value));
+
+  TaskPluginClient($grpc.ClientChannel channel,
+      {$grpc.CallOptions? options,
+      $core.Iterable<$grpc.ClientInterceptor>? interceptors})
+      : super(channel, options: options,
+        interceptors: interceptors);
+
+  $grpc.ResponseFuture<$0.PluginTaskResult> executeTask($0.PluginTask request, {$grpc.CallOptions? options}) {
+    return $createUnaryCall(_$executeTask, request, options: options);
+  }
+}
+
+@$pb.GrpcServiceName('agentapi.TaskPlugin')
+abstract class TaskPluginServiceBase extends $grpc.Service {
+  $core.String get $name => 'agentapi.TaskPlugin';
+
+  TaskPluginServiceBase() {
+    $addMethod($grpc.ServiceMethod<$0.PluginTask, $0.PluginTaskResult>(
+        'ExecuteTask',
+        executeTask_Pre,
+        false,
+        false,
+        ($core.List<$core.int> value) => $0.PluginTask.fromBuffer(value),
+        ($0.PluginTaskResult value) => value.writeToBuffer()));
+  }
+
+  $async.Future<$0.PluginTaskResult> executeTask_Pre($grpc.ServiceCall call, $async.Future<$0.PluginTask> request) async {
+    return executeTask(call, await request);
+  }
+
+  $async.Future<$0.PluginTaskResult> executeTask($grpc.ServiceCall call, $0.PluginTask request);
+}
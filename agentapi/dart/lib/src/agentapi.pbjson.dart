@@ -34,6 +34,20 @@ const ProAttachInfo$json = {
 final $typed_data.Uint8List proAttachInfoDescriptor = $convert.base64Decode(
     'Cg1Qcm9BdHRhY2hJbmZvEhQKBXRva2VuGAEgASgJUgV0b2tlbg==');
 
+@$core.Deprecated('Use distroProTokenDescriptor instead')
+const DistroProToken$json = {
+  '1': 'DistroProToken',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'token', '3': 2, '4': 1, '5': 9, '10': 'token'},
+  ],
+};
+
+/// Descriptor for `DistroProToken`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroProTokenDescriptor = $convert.base64Decode(
+    'Cg5EaXN0cm9Qcm9Ub2tlbhIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1lEhQKBXRva2'
+    'VuGAIgASgJUgV0b2tlbg==');
+
 @$core.Deprecated('Use landscapeConfigDescriptor instead')
 const LandscapeConfig$json = {
   '1': 'LandscapeConfig',
@@ -46,6 +60,801 @@ const LandscapeConfig$json = {
 final $typed_data.Uint8List landscapeConfigDescriptor = $convert.base64Decode(
     'Cg9MYW5kc2NhcGVDb25maWcSFgoGY29uZmlnGAEgASgJUgZjb25maWc=');
 
+@$core.Deprecated('Use fleetStatusDescriptor instead')
+const FleetStatus$json = {
+  '1': 'FleetStatus',
+  '2': [
+    {'1': 'distros', '3': 1, '4': 3, '5': 11, '6': '.agentapi.DistroStatus', '10': 'distros'},
+    {'1': 'managedMode', '3': 2, '4': 1, '5': 11, '6': '.agentapi.ManagedMode', '10': 'managedMode'},
+    {'1': 'securityUpdates', '3': 3, '4': 1, '5': 13, '10': 'securityUpdates'},
+    {'1': 'esmUpdates', '3': 4, '4': 1, '5': 13, '10': 'esmUpdates'},
+    {'1': 'preflightProblems', '3': 5, '4': 3, '5': 11, '6': '.agentapi.PreflightProblem', '10': 'preflightProblems'},
+    {'1': 'wslAvailability', '3': 6, '4': 1, '5': 11, '6': '.agentapi.WSLAvailability', '10': 'wslAvailability'},
+  ],
+};
+
+/// Descriptor for `FleetStatus`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List fleetStatusDescriptor = $convert.base64Decode(
+    'CgtGbGVldFN0YXR1cxIwCgdkaXN0cm9zGAEgAygLMhYuYWdlbnRhcGkuRGlzdHJvU3RhdHVzUg'
+    'dkaXN0cm9zEjcKC21hbmFnZWRNb2RlGAIgASgLMhUuYWdlbnRhcGkuTWFuYWdlZE1vZGVSC21h'
+    'bmFnZWRNb2RlEigKD3NlY3VyaXR5VXBkYXRlcxgDIAEoDVIPc2VjdXJpdHlVcGRhdGVzEh4KCm'
+    'VzbVVwZGF0ZXMYBCABKA1SCmVzbVVwZGF0ZXMSSAoRcHJlZmxpZ2h0UHJvYmxlbXMYBSADKAsy'
+    'Gi5hZ2VudGFwaS5QcmVmbGlnaHRQcm9ibGVtUhFwcmVmbGlnaHRQcm9ibGVtcxJDCg93c2xBdm'
+    'FpbGFiaWxpdHkYBiABKAsyGS5hZ2VudGFwaS5XU0xBdmFpbGFiaWxpdHlSD3dzbEF2YWlsYWJp'
+    'bGl0eQ==');
+
+@$core.Deprecated('Use wSLAvailabilityDescriptor instead')
+const WSLAvailability$json = {
+  '1': 'WSLAvailability',
+  '2': [
+    {'1': 'available', '3': 1, '4': 1, '5': 8, '10': 'available'},
+    {'1': 'since', '3': 2, '4': 1, '5': 9, '10': 'since'},
+    {'1': 'error', '3': 3, '4': 1, '5': 9, '10': 'error'},
+  ],
+};
+
+/// Descriptor for `WSLAvailability`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List wSLAvailabilityDescriptor = $convert.base64Decode(
+    'Cg9XU0xBdmFpbGFiaWxpdHkSHAoJYXZhaWxhYmxlGAEgASgIUglhdmFpbGFibGUSFAoFc2luY2'
+    'UYAiABKAlSBXNpbmNlEhQKBWVycm9yGAMgASgJUgVlcnJvcg==');
+
+@$core.Deprecated('Use preflightProblemDescriptor instead')
+const PreflightProblem$json = {
+  '1': 'PreflightProblem',
+  '2': [
+    {'1': 'isError', '3': 1, '4': 1, '5': 8, '10': 'isError'},
+    {'1': 'check', '3': 2, '4': 1, '5': 9, '10': 'check'},
+    {'1': 'message', '3': 3, '4': 1, '5': 9, '10': 'message'},
+    {'1': 'action', '3': 4, '4': 1, '5': 9, '10': 'action'},
+    {'1': 'remediation', '3': 5, '4': 1, '5': 9, '10': 'remediation'},
+  ],
+};
+
+/// Descriptor for `PreflightProblem`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List preflightProblemDescriptor = $convert.base64Decode(
+    'ChBQcmVmbGlnaHRQcm9ibGVtEhgKB2lzRXJyb3IYASABKAhSB2lzRXJyb3ISFAoFY2hlY2sYAi'
+    'ABKAlSBWNoZWNrEhgKB21lc3NhZ2UYAyABKAlSB21lc3NhZ2USFgoGYWN0aW9uGAQgASgJUgZh'
+    'Y3Rpb24SIAoLcmVtZWRpYXRpb24YBSABKAlSC3JlbWVkaWF0aW9u');
+
+@$core.Deprecated('Use managedModeDescriptor instead')
+const ManagedMode$json = {
+  '1': 'ManagedMode',
+  '2': [
+    {'1': 'registryReadOnly', '3': 1, '4': 1, '5': 8, '10': 'registryReadOnly'},
+    {'1': 'subscription', '3': 2, '4': 1, '5': 8, '10': 'subscription'},
+    {'1': 'landscape', '3': 3, '4': 1, '5': 8, '10': 'landscape'},
+  ],
+};
+
+/// Descriptor for `ManagedMode`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List managedModeDescriptor = $convert.base64Decode(
+    'CgtNYW5hZ2VkTW9kZRIqChByZWdpc3RyeVJlYWRPbmx5GAEgASgIUhByZWdpc3RyeVJlYWRPbm'
+    'x5EiIKDHN1YnNjcmlwdGlvbhgCIAEoCFIMc3Vic2NyaXB0aW9uEhwKCWxhbmRzY2FwZRgDIAEo'
+    'CFIJbGFuZHNjYXBl');
+
+@$core.Deprecated('Use distroStatusDescriptor instead')
+const DistroStatus$json = {
+  '1': 'DistroStatus',
+  '2': [
+    {'1': 'name', '3': 1, '4': 1, '5': 9, '10': 'name'},
+    {'1': 'proAttached', '3': 2, '4': 1, '5': 8, '10': 'proAttached'},
+    {'1': 'proServices', '3': 3, '4': 3, '5': 9, '10': 'proServices'},
+    {'1': 'lastContact', '3': 4, '4': 1, '5': 9, '10': 'lastContact'},
+    {'1': 'pendingTasks', '3': 5, '4': 1, '5': 13, '10': 'pendingTasks'},
+    {'1': 'landscapeRegistered', '3': 6, '4': 1, '5': 8, '10': 'landscapeRegistered'},
+    {'1': 'connected', '3': 7, '4': 1, '5': 8, '10': 'connected'},
+    {'1': 'securityUpdates', '3': 8, '4': 1, '5': 13, '10': 'securityUpdates'},
+    {'1': 'esmUpdates', '3': 9, '4': 1, '5': 13, '10': 'esmUpdates'},
+    {'1': 'securityChecked', '3': 10, '4': 1, '5': 9, '10': 'securityChecked'},
+    {'1': 'memoryBytes', '3': 11, '4': 1, '5': 4, '10': 'memoryBytes'},
+    {'1': 'cpuPercent', '3': 12, '4': 1, '5': 1, '10': 'cpuPercent'},
+    {'1': 'diskBytes', '3': 13, '4': 1, '5': 4, '10': 'diskBytes'},
+    {'1': 'resourcesChecked', '3': 14, '4': 1, '5': 9, '10': 'resourcesChecked'},
+    {'1': 'needsAttention', '3': 15, '4': 1, '5': 8, '10': 'needsAttention'},
+    {'1': 'lastTaskError', '3': 16, '4': 1, '5': 9, '10': 'lastTaskError'},
+    {'1': 'nextRetry', '3': 17, '4': 1, '5': 9, '10': 'nextRetry'},
+    {'1': 'rebootRequired', '3': 18, '4': 1, '5': 8, '10': 'rebootRequired'},
+    {'1': 'rebootPackages', '3': 19, '4': 3, '5': 9, '10': 'rebootPackages'},
+    {'1': 'pendingUpdates', '3': 20, '4': 1, '5': 13, '10': 'pendingUpdates'},
+    {'1': 'awakeOnDemand', '3': 21, '4': 1, '5': 8, '10': 'awakeOnDemand'},
+    {'1': 'awakeUntil', '3': 22, '4': 1, '5': 9, '10': 'awakeUntil'},
+    {'1': 'labels', '3': 23, '4': 3, '5': 11, '6': '.agentapi.Label', '10': 'labels'},
+    {'1': 'serviceVersion', '3': 24, '4': 1, '5': 9, '10': 'serviceVersion'},
+    {'1': 'landscapeScriptUsers', '3': 25, '4': 1, '5': 9, '10': 'landscapeScriptUsers'},
+  ],
+};
+
+/// Descriptor for `DistroStatus`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroStatusDescriptor = $convert.base64Decode(
+    'CgxEaXN0cm9TdGF0dXMSEgoEbmFtZRgBIAEoCVIEbmFtZRIgCgtwcm9BdHRhY2hlZBgCIAEoCF'
+    'ILcHJvQXR0YWNoZWQSIAoLcHJvU2VydmljZXMYAyADKAlSC3Byb1NlcnZpY2VzEiAKC2xhc3RD'
+    'b250YWN0GAQgASgJUgtsYXN0Q29udGFjdBIiCgxwZW5kaW5nVGFza3MYBSABKA1SDHBlbmRpbm'
+    'dUYXNrcxIwChNsYW5kc2NhcGVSZWdpc3RlcmVkGAYgASgIUhNsYW5kc2NhcGVSZWdpc3RlcmVk'
+    'EhwKCWNvbm5lY3RlZBgHIAEoCFIJY29ubmVjdGVkEigKD3NlY3VyaXR5VXBkYXRlcxgIIAEoDV'
+    'IPc2VjdXJpdHlVcGRhdGVzEh4KCmVzbVVwZGF0ZXMYCSABKA1SCmVzbVVwZGF0ZXMSKAoPc2Vj'
+    'dXJpdHlDaGVja2VkGAogASgJUg9zZWN1cml0eUNoZWNrZWQSIAoLbWVtb3J5Qnl0ZXMYCyABKA'
+    'RSC21lbW9yeUJ5dGVzEh4KCmNwdVBlcmNlbnQYDCABKAFSCmNwdVBlcmNlbnQSHAoJZGlza0J5'
+    'dGVzGA0gASgEUglkaXNrQnl0ZXMSKgoQcmVzb3VyY2VzQ2hlY2tlZBgOIAEoCVIQcmVzb3VyY2'
+    'VzQ2hlY2tlZBImCg5uZWVkc0F0dGVudGlvbhgPIAEoCFIObmVlZHNBdHRlbnRpb24SJAoNbGFz'
+    'dFRhc2tFcnJvchgQIAEoCVINbGFzdFRhc2tFcnJvchIcCgluZXh0UmV0cnkYESABKAlSCW5leH'
+    'RSZXRyeRImCg5yZWJvb3RSZXF1aXJlZBgSIAEoCFIOcmVib290UmVxdWlyZWQSJgoOcmVib290'
+    'UGFja2FnZXMYEyADKAlSDnJlYm9vdFBhY2thZ2VzEiYKDnBlbmRpbmdVcGRhdGVzGBQgASgNUg'
+    '5wZW5kaW5nVXBkYXRlcxIkCg1hd2FrZU9uRGVtYW5kGBUgASgIUg1hd2FrZU9uRGVtYW5kEh4K'
+    'CmF3YWtlVW50aWwYFiABKAlSCmF3YWtlVW50aWwSJwoGbGFiZWxzGBcgAygLMg8uYWdlbnRhcG'
+    'kuTGFiZWxSBmxhYmVscxImCg5zZXJ2aWNlVmVyc2lvbhgYIAEoCVIOc2VydmljZVZlcnNpb24S'
+    'MgoUbGFuZHNjYXBlU2NyaXB0VXNlcnMYGSABKAlSFGxhbmRzY2FwZVNjcmlwdFVzZXJz');
+
+@$core.Deprecated('Use distroChangeDescriptor instead')
+const DistroChange$json = {
+  '1': 'DistroChange',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'change', '3': 2, '4': 1, '5': 9, '10': 'change'},
+  ],
+};
+
+/// Descriptor for `DistroChange`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroChangeDescriptor = $convert.base64Decode(
+    'CgxEaXN0cm9DaGFuZ2USHgoKZGlzdHJvTmFtZRgBIAEoCVIKZGlzdHJvTmFtZRIWCgZjaGFuZ2'
+    'UYAiABKAlSBmNoYW5nZQ==');
+
+@$core.Deprecated('Use exportRequestDescriptor instead')
+const ExportRequest$json = {
+  '1': 'ExportRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'destination', '3': 2, '4': 1, '5': 9, '10': 'destination'},
+  ],
+};
+
+/// Descriptor for `ExportRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List exportRequestDescriptor = $convert.base64Decode(
+    'Cg1FeHBvcnRSZXF1ZXN0Eh4KCmRpc3Ryb05hbWUYASABKAlSCmRpc3Ryb05hbWUSIAoLZGVzdG'
+    'luYXRpb24YAiABKAlSC2Rlc3RpbmF0aW9u');
+
+@$core.Deprecated('Use exportProgressDescriptor instead')
+const ExportProgress$json = {
+  '1': 'ExportProgress',
+  '2': [
+    {'1': 'bytesWritten', '3': 1, '4': 1, '5': 4, '10': 'bytesWritten'},
+    {'1': 'done', '3': 2, '4': 1, '5': 8, '10': 'done'},
+    {'1': 'path', '3': 3, '4': 1, '5': 9, '10': 'path'},
+  ],
+};
+
+/// Descriptor for `ExportProgress`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List exportProgressDescriptor = $convert.base64Decode(
+    'Cg5FeHBvcnRQcm9ncmVzcxIiCgxieXRlc1dyaXR0ZW4YASABKARSDGJ5dGVzV3JpdHRlbhISCg'
+    'Rkb25lGAIgASgIUgRkb25lEhIKBHBhdGgYAyABKAlSBHBhdGg=');
+
+@$core.Deprecated('Use backupScheduleDescriptor instead')
+const BackupSchedule$json = {
+  '1': 'BackupSchedule',
+  '2': [
+    {'1': 'intervalHours', '3': 1, '4': 1, '5': 13, '10': 'intervalHours'},
+    {'1': 'destination', '3': 2, '4': 1, '5': 9, '10': 'destination'},
+    {'1': 'keep', '3': 3, '4': 1, '5': 13, '10': 'keep'},
+  ],
+};
+
+/// Descriptor for `BackupSchedule`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List backupScheduleDescriptor = $convert.base64Decode(
+    'Cg5CYWNrdXBTY2hlZHVsZRIkCg1pbnRlcnZhbEhvdXJzGAEgASgNUg1pbnRlcnZhbEhvdXJzEi'
+    'AKC2Rlc3RpbmF0aW9uGAIgASgJUgtkZXN0aW5hdGlvbhISCgRrZWVwGAMgASgNUgRrZWVw');
+
+@$core.Deprecated('Use wakeRequestDescriptor instead')
+const WakeRequest$json = {
+  '1': 'WakeRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'keepAwakeMinutes', '3': 2, '4': 1, '5': 13, '10': 'keepAwakeMinutes'},
+  ],
+};
+
+/// Descriptor for `WakeRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List wakeRequestDescriptor = $convert.base64Decode(
+    'CgtXYWtlUmVxdWVzdBIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1lEioKEGtlZXBBd2'
+    'FrZU1pbnV0ZXMYAiABKA1SEGtlZXBBd2FrZU1pbnV0ZXM=');
+
+@$core.Deprecated('Use stopRequestDescriptor instead')
+const StopRequest$json = {
+  '1': 'StopRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+  ],
+};
+
+/// Descriptor for `StopRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List stopRequestDescriptor = $convert.base64Decode(
+    'CgtTdG9wUmVxdWVzdBIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1l');
+
+@$core.Deprecated('Use distroLabelsDescriptor instead')
+const DistroLabels$json = {
+  '1': 'DistroLabels',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'labels', '3': 2, '4': 3, '5': 11, '6': '.agentapi.Label', '10': 'labels'},
+  ],
+};
+
+/// Descriptor for `DistroLabels`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroLabelsDescriptor = $convert.base64Decode(
+    'CgxEaXN0cm9MYWJlbHMSHgoKZGlzdHJvTmFtZRgBIAEoCVIKZGlzdHJvTmFtZRInCgZsYWJlbH'
+    'MYAiADKAsyDy5hZ2VudGFwaS5MYWJlbFIGbGFiZWxz');
+
+@$core.Deprecated('Use labelDescriptor instead')
+const Label$json = {
+  '1': 'Label',
+  '2': [
+    {'1': 'key', '3': 1, '4': 1, '5': 9, '10': 'key'},
+    {'1': 'value', '3': 2, '4': 1, '5': 9, '10': 'value'},
+  ],
+};
+
+/// Descriptor for `Label`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List labelDescriptor = $convert.base64Decode(
+    'CgVMYWJlbBIQCgNrZXkYASABKAlSA2tleRIUCgV2YWx1ZRgCIAEoCVIFdmFsdWU=');
+
+@$core.Deprecated('Use compactRequestDescriptor instead')
+const CompactRequest$json = {
+  '1': 'CompactRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+  ],
+};
+
+/// Descriptor for `CompactRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List compactRequestDescriptor = $convert.base64Decode(
+    'Cg5Db21wYWN0UmVxdWVzdBIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1l');
+
+@$core.Deprecated('Use compactResultDescriptor instead')
+const CompactResult$json = {
+  '1': 'CompactResult',
+  '2': [
+    {'1': 'sizeBefore', '3': 1, '4': 1, '5': 4, '10': 'sizeBefore'},
+    {'1': 'sizeAfter', '3': 2, '4': 1, '5': 4, '10': 'sizeAfter'},
+  ],
+};
+
+/// Descriptor for `CompactResult`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List compactResultDescriptor = $convert.base64Decode(
+    'Cg1Db21wYWN0UmVzdWx0Eh4KCnNpemVCZWZvcmUYASABKARSCnNpemVCZWZvcmUSHAoJc2l6ZU'
+    'FmdGVyGAIgASgEUglzaXplQWZ0ZXI=');
+
+@$core.Deprecated('Use sparseRequestDescriptor instead')
+const SparseRequest$json = {
+  '1': 'SparseRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'sparse', '3': 2, '4': 1, '5': 8, '10': 'sparse'},
+  ],
+};
+
+/// Descriptor for `SparseRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List sparseRequestDescriptor = $convert.base64Decode(
+    'Cg1TcGFyc2VSZXF1ZXN0Eh4KCmRpc3Ryb05hbWUYASABKAlSCmRpc3Ryb05hbWUSFgoGc3Bhcn'
+    'NlGAIgASgIUgZzcGFyc2U=');
+
+@$core.Deprecated('Use compactScheduleDescriptor instead')
+const CompactSchedule$json = {
+  '1': 'CompactSchedule',
+  '2': [
+    {'1': 'intervalHours', '3': 1, '4': 1, '5': 13, '10': 'intervalHours'},
+  ],
+};
+
+/// Descriptor for `CompactSchedule`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List compactScheduleDescriptor = $convert.base64Decode(
+    'Cg9Db21wYWN0U2NoZWR1bGUSJAoNaW50ZXJ2YWxIb3VycxgBIAEoDVINaW50ZXJ2YWxIb3Vycw'
+    '==');
+
+@$core.Deprecated('Use wSLConfigDescriptor instead')
+const WSLConfig$json = {
+  '1': 'WSLConfig',
+  '2': [
+    {'1': 'memory', '3': 1, '4': 1, '5': 9, '10': 'memory'},
+    {'1': 'processors', '3': 2, '4': 1, '5': 13, '10': 'processors'},
+    {'1': 'networkingMode', '3': 3, '4': 1, '5': 9, '10': 'networkingMode'},
+    {'1': 'restartRequired', '3': 4, '4': 1, '5': 8, '10': 'restartRequired'},
+  ],
+};
+
+/// Descriptor for `WSLConfig`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List wSLConfigDescriptor = $convert.base64Decode(
+    'CglXU0xDb25maWcSFgoGbWVtb3J5GAEgASgJUgZtZW1vcnkSHgoKcHJvY2Vzc29ycxgCIAEoDV'
+    'IKcHJvY2Vzc29ycxImCg5uZXR3b3JraW5nTW9kZRgDIAEoCVIObmV0d29ya2luZ01vZGUSKAoP'
+    'cmVzdGFydFJlcXVpcmVkGAQgASgIUg9yZXN0YXJ0UmVxdWlyZWQ=');
+
+@$core.Deprecated('Use resetRequestDescriptor instead')
+const ResetRequest$json = {
+  '1': 'ResetRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'keepProperties', '3': 2, '4': 1, '5': 8, '10': 'keepProperties'},
+    {'1': 'rootfs', '3': 3, '4': 1, '5': 9, '10': 'rootfs'},
+  ],
+};
+
+/// Descriptor for `ResetRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List resetRequestDescriptor = $convert.base64Decode(
+    'CgxSZXNldFJlcXVlc3QSHgoKZGlzdHJvTmFtZRgBIAEoCVIKZGlzdHJvTmFtZRImCg5rZWVwUH'
+    'JvcGVydGllcxgCIAEoCFIOa2VlcFByb3BlcnRpZXMSFgoGcm9vdGZzGAMgASgJUgZyb290ZnM=');
+
+@$core.Deprecated('Use reloadResultDescriptor instead')
+const ReloadResult$json = {
+  '1': 'ReloadResult',
+  '2': [
+    {'1': 'settings', '3': 1, '4': 3, '5': 11, '6': '.agentapi.ReloadedSetting', '10': 'settings'},
+  ],
+};
+
+/// Descriptor for `ReloadResult`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List reloadResultDescriptor = $convert.base64Decode(
+    'CgxSZWxvYWRSZXN1bHQSNQoIc2V0dGluZ3MYASADKAsyGS5hZ2VudGFwaS5SZWxvYWRlZFNldH'
+    'RpbmdSCHNldHRpbmdz');
+
+@$core.Deprecated('Use reloadedSettingDescriptor instead')
+const ReloadedSetting$json = {
+  '1': 'ReloadedSetting',
+  '2': [
+    {'1': 'name', '3': 1, '4': 1, '5': 9, '10': 'name'},
+    {'1': 'restartRequired', '3': 2, '4': 1, '5': 8, '10': 'restartRequired'},
+    {'1': 'error', '3': 3, '4': 1, '5': 9, '10': 'error'},
+  ],
+};
+
+/// Descriptor for `ReloadedSetting`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List reloadedSettingDescriptor = $convert.base64Decode(
+    'Cg9SZWxvYWRlZFNldHRpbmcSEgoEbmFtZRgBIAEoCVIEbmFtZRIoCg9yZXN0YXJ0UmVxdWlyZW'
+    'QYAiABKAhSD3Jlc3RhcnRSZXF1aXJlZBIUCgVlcnJvchgDIAEoCVIFZXJyb3I=');
+
+@$core.Deprecated('Use distroLogsRequestDescriptor instead')
+const DistroLogsRequest$json = {
+  '1': 'DistroLogsRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'lines', '3': 2, '4': 1, '5': 13, '10': 'lines'},
+  ],
+};
+
+/// Descriptor for `DistroLogsRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroLogsRequestDescriptor = $convert.base64Decode(
+    'ChFEaXN0cm9Mb2dzUmVxdWVzdBIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1lEhQKBW'
+    'xpbmVzGAIgASgNUgVsaW5lcw==');
+
+@$core.Deprecated('Use distroLogsDescriptor instead')
+const DistroLogs$json = {
+  '1': 'DistroLogs',
+  '2': [
+    {'1': 'entries', '3': 1, '4': 3, '5': 11, '6': '.agentapi.LogEntry', '10': 'entries'},
+  ],
+};
+
+/// Descriptor for `DistroLogs`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroLogsDescriptor = $convert.base64Decode(
+    'CgpEaXN0cm9Mb2dzEiwKB2VudHJpZXMYASADKAsyEi5hZ2VudGFwaS5Mb2dFbnRyeVIHZW50cm'
+    'llcw==');
+
+@$core.Deprecated('Use logEntryDescriptor instead')
+const LogEntry$json = {
+  '1': 'LogEntry',
+  '2': [
+    {'1': 'time', '3': 1, '4': 1, '5': 9, '10': 'time'},
+    {'1': 'priority', '3': 2, '4': 1, '5': 13, '10': 'priority'},
+    {'1': 'message', '3': 3, '4': 1, '5': 9, '10': 'message'},
+  ],
+};
+
+/// Descriptor for `LogEntry`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List logEntryDescriptor = $convert.base64Decode(
+    'CghMb2dFbnRyeRISCgR0aW1lGAEgASgJUgR0aW1lEhoKCHByaW9yaXR5GAIgASgNUghwcmlvcm'
+    'l0eRIYCgdtZXNzYWdlGAMgASgJUgdtZXNzYWdl');
+
+@$core.Deprecated('Use distroFileRequestDescriptor instead')
+const DistroFileRequest$json = {
+  '1': 'DistroFileRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'path', '3': 2, '4': 1, '5': 9, '10': 'path'},
+    {'1': 'destination', '3': 3, '4': 1, '5': 9, '10': 'destination'},
+  ],
+};
+
+/// Descriptor for `DistroFileRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroFileRequestDescriptor = $convert.base64Decode(
+    'ChFEaXN0cm9GaWxlUmVxdWVzdBIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1lEhIKBH'
+    'BhdGgYAiABKAlSBHBhdGgSIAoLZGVzdGluYXRpb24YAyABKAlSC2Rlc3RpbmF0aW9u');
+
+@$core.Deprecated('Use distroFileDescriptor instead')
+const DistroFile$json = {
+  '1': 'DistroFile',
+  '2': [
+    {'1': 'destination', '3': 1, '4': 1, '5': 9, '10': 'destination'},
+    {'1': 'size', '3': 2, '4': 1, '5': 4, '10': 'size'},
+    {'1': 'sha256', '3': 3, '4': 1, '5': 9, '10': 'sha256'},
+    {'1': 'redacted', '3': 4, '4': 1, '5': 8, '10': 'redacted'},
+  ],
+};
+
+/// Descriptor for `DistroFile`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroFileDescriptor = $convert.base64Decode(
+    'CgpEaXN0cm9GaWxlEiAKC2Rlc3RpbmF0aW9uGAEgASgJUgtkZXN0aW5hdGlvbhISCgRzaXplGA'
+    'IgASgEUgRzaXplEhYKBnNoYTI1NhgDIAEoCVIGc2hhMjU2EhoKCHJlZGFjdGVkGAQgASgIUghy'
+    'ZWRhY3RlZA==');
+
+@$core.Deprecated('Use distroTasksRequestDescriptor instead')
+const DistroTasksRequest$json = {
+  '1': 'DistroTasksRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+  ],
+};
+
+/// Descriptor for `DistroTasksRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroTasksRequestDescriptor = $convert.base64Decode(
+    'ChJEaXN0cm9UYXNrc1JlcXVlc3QSHgoKZGlzdHJvTmFtZRgBIAEoCVIKZGlzdHJvTmFtZQ==');
+
+@$core.Deprecated('Use distroTasksDescriptor instead')
+const DistroTasks$json = {
+  '1': 'DistroTasks',
+  '2': [
+    {'1': 'tasks', '3': 1, '4': 3, '5': 11, '6': '.agentapi.QueuedTask', '10': 'tasks'},
+  ],
+};
+
+/// Descriptor for `DistroTasks`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroTasksDescriptor = $convert.base64Decode(
+    'CgtEaXN0cm9UYXNrcxIqCgV0YXNrcxgBIAMoCzIULmFnZW50YXBpLlF1ZXVlZFRhc2tSBXRhc2'
+    'tz');
+
+@$core.Deprecated('Use queuedTaskDescriptor instead')
+const QueuedTask$json = {
+  '1': 'QueuedTask',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '10': 'id'},
+    {'1': 'task', '3': 2, '4': 1, '5': 9, '10': 'task'},
+    {'1': 'deferred', '3': 3, '4': 1, '5': 8, '10': 'deferred'},
+    {'1': 'submitted', '3': 4, '4': 1, '5': 9, '10': 'submitted'},
+    {'1': 'attempts', '3': 5, '4': 1, '5': 13, '10': 'attempts'},
+  ],
+};
+
+/// Descriptor for `QueuedTask`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List queuedTaskDescriptor = $convert.base64Decode(
+    'CgpRdWV1ZWRUYXNrEg4KAmlkGAEgASgJUgJpZBISCgR0YXNrGAIgASgJUgR0YXNrEhoKCGRlZm'
+    'VycmVkGAMgASgIUghkZWZlcnJlZBIcCglzdWJtaXR0ZWQYBCABKAlSCXN1Ym1pdHRlZBIaCghh'
+    'dHRlbXB0cxgFIAEoDVIIYXR0ZW1wdHM=');
+
+@$core.Deprecated('Use cancelTaskRequestDescriptor instead')
+const CancelTaskRequest$json = {
+  '1': 'CancelTaskRequest',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'id', '3': 2, '4': 1, '5': 9, '10': 'id'},
+  ],
+};
+
+/// Descriptor for `CancelTaskRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List cancelTaskRequestDescriptor = $convert.base64Decode(
+    'ChFDYW5jZWxUYXNrUmVxdWVzdBIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1lEg4KAm'
+    'lkGAIgASgJUgJpZA==');
+
+@$core.Deprecated('Use operationRequestDescriptor instead')
+const OperationRequest$json = {
+  '1': 'OperationRequest',
+  '2': [
+    {'1': 'exportDistro', '3': 1, '4': 1, '5': 11, '6': '.agentapi.ExportRequest', '9': 0, '10': 'exportDistro'},
+    {'1': 'resetDistro', '3': 2, '4': 1, '5': 11, '6': '.agentapi.ResetRequest', '9': 0, '10': 'resetDistro'},
+  ],
+  '8': [
+    {'1': 'request'},
+  ],
+};
+
+/// Descriptor for `OperationRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List operationRequestDescriptor = $convert.base64Decode(
+    'ChBPcGVyYXRpb25SZXF1ZXN0Ej0KDGV4cG9ydERpc3RybxgBIAEoCzIXLmFnZW50YXBpLkV4cG'
+    '9ydFJlcXVlc3RIAFIMZXhwb3J0RGlzdHJvEjoKC3Jlc2V0RGlzdHJvGAIgASgLMhYuYWdlbnRh'
+    'cGkuUmVzZXRSZXF1ZXN0SABSC3Jlc2V0RGlzdHJvQgkKB3JlcXVlc3Q=');
+
+@$core.Deprecated('Use operationIDDescriptor instead')
+const OperationID$json = {
+  '1': 'OperationID',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '10': 'id'},
+  ],
+};
+
+/// Descriptor for `OperationID`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List operationIDDescriptor = $convert.base64Decode(
+    'CgtPcGVyYXRpb25JRBIOCgJpZBgBIAEoCVICaWQ=');
+
+@$core.Deprecated('Use operationDescriptor instead')
+const Operation$json = {
+  '1': 'Operation',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '10': 'id'},
+    {'1': 'kind', '3': 2, '4': 1, '5': 9, '10': 'kind'},
+    {'1': 'distroName', '3': 3, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'state', '3': 4, '4': 1, '5': 9, '10': 'state'},
+    {'1': 'error', '3': 5, '4': 1, '5': 9, '10': 'error'},
+    {'1': 'progress', '3': 6, '4': 1, '5': 4, '10': 'progress'},
+    {'1': 'result', '3': 7, '4': 1, '5': 9, '10': 'result'},
+    {'1': 'started', '3': 8, '4': 1, '5': 9, '10': 'started'},
+    {'1': 'finished', '3': 9, '4': 1, '5': 9, '10': 'finished'},
+  ],
+};
+
+/// Descriptor for `Operation`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List operationDescriptor = $convert.base64Decode(
+    'CglPcGVyYXRpb24SDgoCaWQYASABKAlSAmlkEhIKBGtpbmQYAiABKAlSBGtpbmQSHgoKZGlzdH'
+    'JvTmFtZRgDIAEoCVIKZGlzdHJvTmFtZRIUCgVzdGF0ZRgEIAEoCVIFc3RhdGUSFAoFZXJyb3IY'
+    'BSABKAlSBWVycm9yEhoKCHByb2dyZXNzGAYgASgEUghwcm9ncmVzcxIWCgZyZXN1bHQYByABKA'
+    'lSBnJlc3VsdBIYCgdzdGFydGVkGAggASgJUgdzdGFydGVkEhoKCGZpbmlzaGVkGAkgASgJUghm'
+    'aW5pc2hlZA==');
+
+@$core.Deprecated('Use operationsDescriptor instead')
+const Operations$json = {
+  '1': 'Operations',
+  '2': [
+    {'1': 'operations', '3': 1, '4': 3, '5': 11, '6': '.agentapi.Operation', '10': 'operations'},
+  ],
+};
+
+/// Descriptor for `Operations`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List operationsDescriptor = $convert.base64Decode(
+    'CgpPcGVyYXRpb25zEjMKCm9wZXJhdGlvbnMYASADKAsyEy5hZ2VudGFwaS5PcGVyYXRpb25SCm'
+    '9wZXJhdGlvbnM=');
+
+@$core.Deprecated('Use confirmationRequestDescriptor instead')
+const ConfirmationRequest$json = {
+  '1': 'ConfirmationRequest',
+  '2': [
+    {'1': 'method', '3': 1, '4': 1, '5': 9, '10': 'method'},
+  ],
+};
+
+/// Descriptor for `ConfirmationRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List confirmationRequestDescriptor = $convert.base64Decode(
+    'ChNDb25maXJtYXRpb25SZXF1ZXN0EhYKBm1ldGhvZBgBIAEoCVIGbWV0aG9k');
+
+@$core.Deprecated('Use confirmationDescriptor instead')
+const Confirmation$json = {
+  '1': 'Confirmation',
+  '2': [
+    {'1': 'code', '3': 1, '4': 1, '5': 9, '10': 'code'},
+    {'1': 'expires', '3': 2, '4': 1, '5': 9, '10': 'expires'},
+  ],
+};
+
+/// Descriptor for `Confirmation`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List confirmationDescriptor = $convert.base64Decode(
+    'CgxDb25maXJtYXRpb24SEgoEY29kZRgBIAEoCVIEY29kZRIYCgdleHBpcmVzGAIgASgJUgdleH'
+    'BpcmVz');
+
+@$core.Deprecated('Use distroWSLSettingsDescriptor instead')
+const DistroWSLSettings$json = {
+  '1': 'DistroWSLSettings',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'defaultUser', '3': 2, '4': 1, '5': 9, '10': 'defaultUser'},
+    {'1': 'interop', '3': 3, '4': 1, '5': 11, '6': '.agentapi.Switch', '10': 'interop'},
+    {'1': 'automount', '3': 4, '4': 1, '5': 11, '6': '.agentapi.Switch', '10': 'automount'},
+    {'1': 'systemd', '3': 5, '4': 1, '5': 11, '6': '.agentapi.Switch', '10': 'systemd'},
+  ],
+};
+
+/// Descriptor for `DistroWSLSettings`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List distroWSLSettingsDescriptor = $convert.base64Decode(
+    'ChFEaXN0cm9XU0xTZXR0aW5ncxIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1lEiAKC2'
+    'RlZmF1bHRVc2VyGAIgASgJUgtkZWZhdWx0VXNlchIqCgdpbnRlcm9wGAMgASgLMhAuYWdlbnRh'
+    'cGkuU3dpdGNoUgdpbnRlcm9wEi4KCWF1dG9tb3VudBgEIAEoCzIQLmFnZW50YXBpLlN3aXRjaF'
+    'IJYXV0b21vdW50EioKB3N5c3RlbWQYBSABKAsyEC5hZ2VudGFwaS5Td2l0Y2hSB3N5c3RlbWQ=');
+
+@$core.Deprecated('Use switchDescriptor instead')
+const Switch$json = {
+  '1': 'Switch',
+  '2': [
+    {'1': 'enabled', '3': 1, '4': 1, '5': 8, '10': 'enabled'},
+  ],
+};
+
+/// Descriptor for `Switch`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List switchDescriptor = $convert.base64Decode(
+    'CgZTd2l0Y2gSGAoHZW5hYmxlZBgBIAEoCFIHZW5hYmxlZA==');
+
+@$core.Deprecated('Use pauseStateDescriptor instead')
+const PauseState$json = {
+  '1': 'PauseState',
+  '2': [
+    {'1': 'paused', '3': 1, '4': 1, '5': 8, '10': 'paused'},
+  ],
+};
+
+/// Descriptor for `PauseState`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List pauseStateDescriptor = $convert.base64Decode(
+    'CgpQYXVzZVN0YXRlEhYKBnBhdXNlZBgBIAEoCFIGcGF1c2Vk');
+
+@$core.Deprecated('Use onboardingStateDescriptor instead')
+const OnboardingState$json = {
+  '1': 'OnboardingState',
+  '2': [
+    {'1': 'step', '3': 1, '4': 1, '5': 9, '10': 'step'},
+    {'1': 'subscriptionSelected', '3': 2, '4': 1, '5': 8, '10': 'subscriptionSelected'},
+    {'1': 'landscapeConfigured', '3': 3, '4': 1, '5': 8, '10': 'landscapeConfigured'},
+    {'1': 'landscapeSkipped', '3': 4, '4': 1, '5': 8, '10': 'landscapeSkipped'},
+    {'1': 'distroProvisioned', '3': 5, '4': 1, '5': 8, '10': 'distroProvisioned'},
+  ],
+};
+
+/// Descriptor for `OnboardingState`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List onboardingStateDescriptor = $convert.base64Decode(
+    'Cg9PbmJvYXJkaW5nU3RhdGUSEgoEc3RlcBgBIAEoCVIEc3RlcBIyChRzdWJzY3JpcHRpb25TZW'
+    'xlY3RlZBgCIAEoCFIUc3Vic2NyaXB0aW9uU2VsZWN0ZWQSMAoTbGFuZHNjYXBlQ29uZmlndXJl'
+    'ZBgDIAEoCFITbGFuZHNjYXBlQ29uZmlndXJlZBIqChBsYW5kc2NhcGVTa2lwcGVkGAQgASgIUh'
+    'BsYW5kc2NhcGVTa2lwcGVkEiwKEWRpc3Ryb1Byb3Zpc2lvbmVkGAUgASgIUhFkaXN0cm9Qcm92'
+    'aXNpb25lZA==');
+
+@$core.Deprecated('Use notificationPreferenceDescriptor instead')
+const NotificationPreference$json = {
+  '1': 'NotificationPreference',
+  '2': [
+    {'1': 'category', '3': 1, '4': 1, '5': 9, '10': 'category'},
+    {'1': 'enabled', '3': 2, '4': 1, '5': 8, '10': 'enabled'},
+  ],
+};
+
+/// Descriptor for `NotificationPreference`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List notificationPreferenceDescriptor = $convert.base64Decode(
+    'ChZOb3RpZmljYXRpb25QcmVmZXJlbmNlEhoKCGNhdGVnb3J5GAEgASgJUghjYXRlZ29yeRIYCg'
+    'dlbmFibGVkGAIgASgIUgdlbmFibGVk');
+
+@$core.Deprecated('Use notificationPreferencesDescriptor instead')
+const NotificationPreferences$json = {
+  '1': 'NotificationPreferences',
+  '2': [
+    {'1': 'preferences', '3': 1, '4': 3, '5': 11, '6': '.agentapi.NotificationPreference', '10': 'preferences'},
+  ],
+};
+
+/// Descriptor for `NotificationPreferences`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List notificationPreferencesDescriptor = $convert.base64Decode(
+    'ChdOb3RpZmljYXRpb25QcmVmZXJlbmNlcxJCCgtwcmVmZXJlbmNlcxgBIAMoCzIgLmFnZW50YX'
+    'BpLk5vdGlmaWNhdGlvblByZWZlcmVuY2VSC3ByZWZlcmVuY2Vz');
+
+@$core.Deprecated('Use approvalPreferenceDescriptor instead')
+const ApprovalPreference$json = {
+  '1': 'ApprovalPreference',
+  '2': [
+    {'1': 'category', '3': 1, '4': 1, '5': 9, '10': 'category'},
+    {'1': 'required', '3': 2, '4': 1, '5': 8, '10': 'required'},
+  ],
+};
+
+/// Descriptor for `ApprovalPreference`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List approvalPreferenceDescriptor = $convert.base64Decode(
+    'ChJBcHByb3ZhbFByZWZlcmVuY2USGgoIY2F0ZWdvcnkYASABKAlSCGNhdGVnb3J5EhoKCHJlcX'
+    'VpcmVkGAIgASgIUghyZXF1aXJlZA==');
+
+@$core.Deprecated('Use approvalPreferencesDescriptor instead')
+const ApprovalPreferences$json = {
+  '1': 'ApprovalPreferences',
+  '2': [
+    {'1': 'preferences', '3': 1, '4': 3, '5': 11, '6': '.agentapi.ApprovalPreference', '10': 'preferences'},
+  ],
+};
+
+/// Descriptor for `ApprovalPreferences`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List approvalPreferencesDescriptor = $convert.base64Decode(
+    'ChNBcHByb3ZhbFByZWZlcmVuY2VzEj4KC3ByZWZlcmVuY2VzGAEgAygLMhwuYWdlbnRhcGkuQX'
+    'Bwcm92YWxQcmVmZXJlbmNlUgtwcmVmZXJlbmNlcw==');
+
+@$core.Deprecated('Use pendingApprovalDescriptor instead')
+const PendingApproval$json = {
+  '1': 'PendingApproval',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '10': 'id'},
+    {'1': 'category', '3': 2, '4': 1, '5': 9, '10': 'category'},
+    {'1': 'description', '3': 3, '4': 1, '5': 9, '10': 'description'},
+    {'1': 'requested', '3': 4, '4': 1, '5': 9, '10': 'requested'},
+    {'1': 'expires', '3': 5, '4': 1, '5': 9, '10': 'expires'},
+  ],
+};
+
+/// Descriptor for `PendingApproval`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List pendingApprovalDescriptor = $convert.base64Decode(
+    'Cg9QZW5kaW5nQXBwcm92YWwSDgoCaWQYASABKAlSAmlkEhoKCGNhdGVnb3J5GAIgASgJUghjYX'
+    'RlZ29yeRIgCgtkZXNjcmlwdGlvbhgDIAEoCVILZGVzY3JpcHRpb24SHAoJcmVxdWVzdGVkGAQg'
+    'ASgJUglyZXF1ZXN0ZWQSGAoHZXhwaXJlcxgFIAEoCVIHZXhwaXJlcw==');
+
+@$core.Deprecated('Use pendingApprovalsDescriptor instead')
+const PendingApprovals$json = {
+  '1': 'PendingApprovals',
+  '2': [
+    {'1': 'approvals', '3': 1, '4': 3, '5': 11, '6': '.agentapi.PendingApproval', '10': 'approvals'},
+  ],
+};
+
+/// Descriptor for `PendingApprovals`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List pendingApprovalsDescriptor = $convert.base64Decode(
+    'ChBQZW5kaW5nQXBwcm92YWxzEjcKCWFwcHJvdmFscxgBIAMoCzIZLmFnZW50YXBpLlBlbmRpbm'
+    'dBcHByb3ZhbFIJYXBwcm92YWxz');
+
+@$core.Deprecated('Use approvalDecisionDescriptor instead')
+const ApprovalDecision$json = {
+  '1': 'ApprovalDecision',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '10': 'id'},
+  ],
+};
+
+/// Descriptor for `ApprovalDecision`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List approvalDecisionDescriptor = $convert.base64Decode(
+    'ChBBcHByb3ZhbERlY2lzaW9uEg4KAmlkGAEgASgJUgJpZA==');
+
+@$core.Deprecated('Use pluginTaskSubmissionDescriptor instead')
+const PluginTaskSubmission$json = {
+  '1': 'PluginTaskSubmission',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'type', '3': 2, '4': 1, '5': 9, '10': 'type'},
+    {'1': 'payload', '3': 3, '4': 1, '5': 12, '10': 'payload'},
+    {'1': 'deferred', '3': 4, '4': 1, '5': 8, '10': 'deferred'},
+  ],
+};
+
+/// Descriptor for `PluginTaskSubmission`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List pluginTaskSubmissionDescriptor = $convert.base64Decode(
+    'ChRQbHVnaW5UYXNrU3VibWlzc2lvbhIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW1lEh'
+    'IKBHR5cGUYAiABKAlSBHR5cGUSGAoHcGF5bG9hZBgDIAEoDFIHcGF5bG9hZBIaCghkZWZlcnJl'
+    'ZBgEIAEoCFIIZGVmZXJyZWQ=');
+
+@$core.Deprecated('Use landscapeDistroOverrideDescriptor instead')
+const LandscapeDistroOverride$json = {
+  '1': 'LandscapeDistroOverride',
+  '2': [
+    {'1': 'distroName', '3': 1, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'tags', '3': 2, '4': 1, '5': 9, '10': 'tags'},
+    {'1': 'computerTitle', '3': 3, '4': 1, '5': 9, '10': 'computerTitle'},
+    {'1': 'scriptUsers', '3': 4, '4': 1, '5': 9, '10': 'scriptUsers'},
+    {'1': 'endpoint', '3': 5, '4': 1, '5': 9, '10': 'endpoint'},
+  ],
+};
+
+/// Descriptor for `LandscapeDistroOverride`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List landscapeDistroOverrideDescriptor = $convert.base64Decode(
+    'ChdMYW5kc2NhcGVEaXN0cm9PdmVycmlkZRIeCgpkaXN0cm9OYW1lGAEgASgJUgpkaXN0cm9OYW'
+    '1lEhIKBHRhZ3MYAiABKAlSBHRhZ3MSJAoNY29tcHV0ZXJUaXRsZRgDIAEoCVINY29tcHV0ZXJU'
+    'aXRsZRIgCgtzY3JpcHRVc2VycxgEIAEoCVILc2NyaXB0VXNlcnMSGgoIZW5kcG9pbnQYBSABKA'
+    'lSCGVuZHBvaW50');
+
+@$core.Deprecated('Use landscapeEndpointDescriptor instead')
+const LandscapeEndpoint$json = {
+  '1': 'LandscapeEndpoint',
+  '2': [
+    {'1': 'name', '3': 1, '4': 1, '5': 9, '10': 'name'},
+    {'1': 'config', '3': 2, '4': 1, '5': 9, '10': 'config'},
+  ],
+};
+
+/// Descriptor for `LandscapeEndpoint`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List landscapeEndpointDescriptor = $convert.base64Decode(
+    'ChFMYW5kc2NhcGVFbmRwb2ludBISCgRuYW1lGAEgASgJUgRuYW1lEhYKBmNvbmZpZxgCIAEoCV'
+    'IGY29uZmln');
+
+@$core.Deprecated('Use landscapeDistroOverridesDescriptor instead')
+const LandscapeDistroOverrides$json = {
+  '1': 'LandscapeDistroOverrides',
+  '2': [
+    {'1': 'overrides', '3': 1, '4': 3, '5': 11, '6': '.agentapi.LandscapeDistroOverride', '10': 'overrides'},
+  ],
+};
+
+/// Descriptor for `LandscapeDistroOverrides`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List landscapeDistroOverridesDescriptor = $convert.base64Decode(
+    'ChhMYW5kc2NhcGVEaXN0cm9PdmVycmlkZXMSPwoJb3ZlcnJpZGVzGAEgAygLMiEuYWdlbnRhcG'
+    'kuTGFuZHNjYXBlRGlzdHJvT3ZlcnJpZGVSCW92ZXJyaWRlcw==');
+
 @$core.Deprecated('Use subscriptionInfoDescriptor instead')
 const SubscriptionInfo$json = {
   '1': 'SubscriptionInfo',
@@ -104,6 +913,99 @@ final $typed_data.Uint8List configSourcesDescriptor = $convert.base64Decode(
     'NjcmlwdGlvbkluZm9SD3Byb1N1YnNjcmlwdGlvbhJDCg9sYW5kc2NhcGVTb3VyY2UYAiABKAsy'
     'GS5hZ2VudGFwaS5MYW5kc2NhcGVTb3VyY2VSD2xhbmRzY2FwZVNvdXJjZQ==');
 
+@$core.Deprecated('Use subscriptionDetailsDescriptor instead')
+const SubscriptionDetails$json = {
+  '1': 'SubscriptionDetails',
+  '2': [
+    {'1': 'active', '3': 1, '4': 1, '5': 11, '6': '.agentapi.SubscriptionInfo', '10': 'active'},
+    {'1': 'sources', '3': 2, '4': 3, '5': 11, '6': '.agentapi.SubscriptionSourceDetails', '10': 'sources'},
+    {'1': 'registryReadOnly', '3': 3, '4': 1, '5': 8, '10': 'registryReadOnly'},
+  ],
+};
+
+/// Descriptor for `SubscriptionDetails`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List subscriptionDetailsDescriptor = $convert.base64Decode(
+    'ChNTdWJzY3JpcHRpb25EZXRhaWxzEjIKBmFjdGl2ZRgBIAEoCzIaLmFnZW50YXBpLlN1YnNjcm'
+    'lwdGlvbkluZm9SBmFjdGl2ZRI9Cgdzb3VyY2VzGAIgAygLMiMuYWdlbnRhcGkuU3Vic2NyaXB0'
+    'aW9uU291cmNlRGV0YWlsc1IHc291cmNlcxIqChByZWdpc3RyeVJlYWRPbmx5GAMgASgIUhByZW'
+    'dpc3RyeVJlYWRPbmx5');
+
+@$core.Deprecated('Use subscriptionSourceDetailsDescriptor instead')
+const SubscriptionSourceDetails$json = {
+  '1': 'SubscriptionSourceDetails',
+  '2': [
+    {'1': 'source', '3': 1, '4': 1, '5': 11, '6': '.agentapi.SubscriptionInfo', '10': 'source'},
+    {'1': 'hasToken', '3': 2, '4': 1, '5': 8, '10': 'hasToken'},
+    {'1': 'shadowed', '3': 3, '4': 1, '5': 8, '10': 'shadowed'},
+    {'1': 'lastModified', '3': 4, '4': 1, '5': 9, '10': 'lastModified'},
+  ],
+};
+
+/// Descriptor for `SubscriptionSourceDetails`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List subscriptionSourceDetailsDescriptor = $convert.base64Decode(
+    'ChlTdWJzY3JpcHRpb25Tb3VyY2VEZXRhaWxzEjIKBnNvdXJjZRgBIAEoCzIaLmFnZW50YXBpLl'
+    'N1YnNjcmlwdGlvbkluZm9SBnNvdXJjZRIaCghoYXNUb2tlbhgCIAEoCFIIaGFzVG9rZW4SGgoI'
+    'c2hhZG93ZWQYAyABKAhSCHNoYWRvd2VkEiIKDGxhc3RNb2RpZmllZBgEIAEoCVIMbGFzdE1vZG'
+    'lmaWVk');
+
+@$core.Deprecated('Use configProblemsDescriptor instead')
+const ConfigProblems$json = {
+  '1': 'ConfigProblems',
+  '2': [
+    {'1': 'problems', '3': 1, '4': 3, '5': 11, '6': '.agentapi.ConfigProblem', '10': 'problems'},
+  ],
+};
+
+/// Descriptor for `ConfigProblems`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List configProblemsDescriptor = $convert.base64Decode(
+    'Cg5Db25maWdQcm9ibGVtcxIzCghwcm9ibGVtcxgBIAMoCzIXLmFnZW50YXBpLkNvbmZpZ1Byb2'
+    'JsZW1SCHByb2JsZW1z');
+
+@$core.Deprecated('Use configProblemDescriptor instead')
+const ConfigProblem$json = {
+  '1': 'ConfigProblem',
+  '2': [
+    {'1': 'isError', '3': 1, '4': 1, '5': 8, '10': 'isError'},
+    {'1': 'field', '3': 2, '4': 1, '5': 9, '10': 'field'},
+    {'1': 'message', '3': 3, '4': 1, '5': 9, '10': 'message'},
+  ],
+};
+
+/// Descriptor for `ConfigProblem`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List configProblemDescriptor = $convert.base64Decode(
+    'Cg1Db25maWdQcm9ibGVtEhgKB2lzRXJyb3IYASABKAhSB2lzRXJyb3ISFAoFZmllbGQYAiABKA'
+    'lSBWZpZWxkEhgKB21lc3NhZ2UYAyABKAlSB21lc3NhZ2U=');
+
+@$core.Deprecated('Use featureFlagsDescriptor instead')
+const FeatureFlags$json = {
+  '1': 'FeatureFlags',
+  '2': [
+    {'1': 'flags', '3': 1, '4': 3, '5': 11, '6': '.agentapi.FeatureFlag', '10': 'flags'},
+  ],
+};
+
+/// Descriptor for `FeatureFlags`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List featureFlagsDescriptor = $convert.base64Decode(
+    'CgxGZWF0dXJlRmxhZ3MSKwoFZmxhZ3MYASADKAsyFS5hZ2VudGFwaS5GZWF0dXJlRmxhZ1IFZm'
+    'xhZ3M=');
+
+@$core.Deprecated('Use featureFlagDescriptor instead')
+const FeatureFlag$json = {
+  '1': 'FeatureFlag',
+  '2': [
+    {'1': 'name', '3': 1, '4': 1, '5': 9, '10': 'name'},
+    {'1': 'enabled', '3': 2, '4': 1, '5': 8, '10': 'enabled'},
+    {'1': 'isDefault', '3': 3, '4': 1, '5': 8, '10': 'isDefault'},
+    {'1': 'organization', '3': 4, '4': 1, '5': 8, '10': 'organization'},
+  ],
+};
+
+/// Descriptor for `FeatureFlag`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List featureFlagDescriptor = $convert.base64Decode(
+    'CgtGZWF0dXJlRmxhZxISCgRuYW1lGAEgASgJUgRuYW1lEhgKB2VuYWJsZWQYAiABKAhSB2VuYW'
+    'JsZWQSHAoJaXNEZWZhdWx0GAMgASgIUglpc0RlZmF1bHQSIgoMb3JnYW5pemF0aW9uGAQgASgI'
+    'Ugxvcmdhbml6YXRpb24=');
+
 @$core.Deprecated('Use distroInfoDescriptor instead')
 const DistroInfo$json = {
   '1': 'DistroInfo',
@@ -114,6 +1016,12 @@ const DistroInfo$json = {
     {'1': 'pretty_name', '3': 4, '4': 1, '5': 9, '10': 'prettyName'},
     {'1': 'pro_attached', '3': 5, '4': 1, '5': 8, '10': 'proAttached'},
     {'1': 'hostname', '3': 6, '4': 1, '5': 9, '10': 'hostname'},
+    {'1': 'instance_id', '3': 7, '4': 1, '5': 9, '10': 'instanceId'},
+    {'1': 'pro_services', '3': 8, '4': 3, '5': 9, '10': 'proServices'},
+    {'1': 'landscape_registered', '3': 9, '4': 1, '5': 8, '10': 'landscapeRegistered'},
+    {'1': 'service_version', '3': 10, '4': 1, '5': 9, '10': 'serviceVersion'},
+    {'1': 'boot_id', '3': 11, '4': 1, '5': 9, '10': 'bootId'},
+    {'1': 'landscape_script_users', '3': 12, '4': 1, '5': 9, '10': 'landscapeScriptUsers'},
   ],
 };
 
@@ -122,7 +1030,11 @@ final $typed_data.Uint8List distroInfoDescriptor = $convert.base64Decode(
     'CgpEaXN0cm9JbmZvEhkKCHdzbF9uYW1lGAEgASgJUgd3c2xOYW1lEg4KAmlkGAIgASgJUgJpZB'
     'IdCgp2ZXJzaW9uX2lkGAMgASgJUgl2ZXJzaW9uSWQSHwoLcHJldHR5X25hbWUYBCABKAlSCnBy'
     'ZXR0eU5hbWUSIQoMcHJvX2F0dGFjaGVkGAUgASgIUgtwcm9BdHRhY2hlZBIaCghob3N0bmFtZR'
-    'gGIAEoCVIIaG9zdG5hbWU=');
+    'gGIAEoCVIIaG9zdG5hbWUSHwoLaW5zdGFuY2VfaWQYByABKAlSCmluc3RhbmNlSWQSIQoMcHJv'
+    'X3NlcnZpY2VzGAggAygJUgtwcm9TZXJ2aWNlcxIxChRsYW5kc2NhcGVfcmVnaXN0ZXJlZBgJIA'
+    'EoCFITbGFuZHNjYXBlUmVnaXN0ZXJlZBInCg9zZXJ2aWNlX3ZlcnNpb24YCiABKAlSDnNlcnZp'
+    'Y2VWZXJzaW9uEhcKB2Jvb3RfaWQYCyABKAlSBmJvb3RJZBI0ChZsYW5kc2NhcGVfc2NyaXB0X3'
+    'VzZXJzGAwgASgJUhRsYW5kc2NhcGVTY3JpcHRVc2Vycw==');
 
 @$core.Deprecated('Use portDescriptor instead')
 const Port$json = {
@@ -136,3 +1048,63 @@ const Port$json = {
 final $typed_data.Uint8List portDescriptor = $convert.base64Decode(
     'CgRQb3J0EhIKBHBvcnQYASABKA1SBHBvcnQ=');
 
+@$core.Deprecated('Use pluginTaskDescriptor instead')
+const PluginTask$json = {
+  '1': 'PluginTask',
+  '2': [
+    {'1': 'type', '3': 1, '4': 1, '5': 9, '10': 'type'},
+    {'1': 'distroName', '3': 2, '4': 1, '5': 9, '10': 'distroName'},
+    {'1': 'payload', '3': 3, '4': 1, '5': 12, '10': 'payload'},
+  ],
+};
+
+/// Descriptor for `PluginTask`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List pluginTaskDescriptor = $convert.base64Decode(
+    'CgpQbHVnaW5UYXNrEhIKBHR5cGUYASABKAlSBHR5cGUSHgoKZGlzdHJvTmFtZRgCIAEoCVIKZG'
+    'lzdHJvTmFtZRIYCgdwYXlsb2FkGAMgASgMUgdwYXlsb2Fk');
+
+@$core.Deprecated('Use pluginTaskResultDescriptor instead')
+const PluginTaskResult$json = {
+  '1': 'PluginTaskResult',
+  '2': [
+    {'1': 'error', '3': 1, '4': 1, '5': 9, '10': 'error'},
+    {'1': 'retry', '3': 2, '4': 1, '5': 8, '10': 'retry'},
+  ],
+};
+
+/// Descriptor for `PluginTaskResult`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List pluginTaskResultDescriptor = $convert.base64Decode(
+    'ChBQbHVnaW5UYXNrUmVzdWx0EhQKBWVycm9yGAEgASgJUgVlcnJvchIUCgVyZXRyeRgCIAEoCF'
+    'IFcmV0cnk=');
+
+@$core.Deprecated('Use versionInfoDescriptor instead')
+const VersionInfo$json = {
+  '1': 'VersionInfo',
+  '2': [
+    {'1': 'version', '3': 1, '4': 1, '5': 9, '10': 'version'},
+    {'1': 'commit', '3': 2, '4': 1, '5': 9, '10': 'commit'},
+    {'1': 'buildDate', '3': 3, '4': 1, '5': 9, '10': 'buildDate'},
+    {'1': 'protocols', '3': 4, '4': 3, '5': 11, '6': '.agentapi.ProtocolVersion', '10': 'protocols'},
+  ],
+};
+
+/// Descriptor for `VersionInfo`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List versionInfoDescriptor = $convert.base64Decode(
+    'CgtWZXJzaW9uSW5mbxIYCgd2ZXJzaW9uGAEgASgJUgd2ZXJzaW9uEhYKBmNvbW1pdBgCIAEoCV'
+    'IGY29tbWl0EhwKCWJ1aWxkRGF0ZRgDIAEoCVIJYnVpbGREYXRlEjcKCXByb3RvY29scxgEIAMo'
+    'CzIZLmFnZW50YXBpLlByb3RvY29sVmVyc2lvblIJcHJvdG9jb2xz');
+
+@$core.Deprecated('Use protocolVersionDescriptor instead')
+const ProtocolVersion$json = {
+  '1': 'ProtocolVersion',
+  '2': [
+    {'1': 'name', '3': 1, '4': 1, '5': 9, '10': 'name'},
+    {'1': 'version', '3': 2, '4': 1, '5': 13, '10': 'version'},
+  ],
+};
+
+/// Descriptor for `ProtocolVersion`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List protocolVersionDescriptor = $convert.base64Decode(
+    'Cg9Qcm90b2NvbFZlcnNpb24SEgoEbmFtZRgBIAEoCVIEbmFtZRIYCgd2ZXJzaW9uGAIgASgNUg'
+    'd2ZXJzaW9u');
+
@@ -105,16 +105,19 @@ func (x *ProAttachInfo) GetToken() string {
 	return ""
 }
 
-type LandscapeConfig struct {
+// DistroProToken assigns an Ubuntu Pro token to a single distro, such as a FIPS token, instead of the
+// global subscription. It is ignored while the organization provides the subscription.
+type DistroProToken struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Config string `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`
+	Token      string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"` // Empty to attach the distro with the global subscription again.
 }
 
-func (x *LandscapeConfig) Reset() {
-	*x = LandscapeConfig{}
+func (x *DistroProToken) Reset() {
+	*x = DistroProToken{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agentapi_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -122,13 +125,13 @@ func (x *LandscapeConfig) Reset() {
 	}
 }
 
-func (x *LandscapeConfig) String() string {
+func (x *DistroProToken) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LandscapeConfig) ProtoMessage() {}
+func (*DistroProToken) ProtoMessage() {}
 
-func (x *LandscapeConfig) ProtoReflect() protoreflect.Message {
+func (x *DistroProToken) ProtoReflect() protoreflect.Message {
 	mi := &file_agentapi_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -140,35 +143,35 @@ func (x *LandscapeConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LandscapeConfig.ProtoReflect.Descriptor instead.
-func (*LandscapeConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use DistroProToken.ProtoReflect.Descriptor instead.
+func (*DistroProToken) Descriptor() ([]byte, []int) {
 	return file_agentapi_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *LandscapeConfig) GetConfig() string {
+func (x *DistroProToken) GetDistroName() string {
 	if x != nil {
-		return x.Config
+		return x.DistroName
 	}
 	return ""
 }
 
-type SubscriptionInfo struct {
+func (x *DistroProToken) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type LandscapeConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProductId string `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"` // The ID of the Ubuntu Pro for WSL product on the Microsoft Store.
-	// Types that are assignable to SubscriptionType:
-	//
-	//	*SubscriptionInfo_None
-	//	*SubscriptionInfo_User
-	//	*SubscriptionInfo_Organization
-	//	*SubscriptionInfo_MicrosoftStore
-	SubscriptionType isSubscriptionInfo_SubscriptionType `protobuf_oneof:"subscriptionType"`
+	Config string `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 }
 
-func (x *SubscriptionInfo) Reset() {
-	*x = SubscriptionInfo{}
+func (x *LandscapeConfig) Reset() {
+	*x = LandscapeConfig{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agentapi_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -176,13 +179,13 @@ func (x *SubscriptionInfo) Reset() {
 	}
 }
 
-func (x *SubscriptionInfo) String() string {
+func (x *LandscapeConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscriptionInfo) ProtoMessage() {}
+func (*LandscapeConfig) ProtoMessage() {}
 
-func (x *SubscriptionInfo) ProtoReflect() protoreflect.Message {
+func (x *LandscapeConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_agentapi_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -194,96 +197,33 @@ func (x *SubscriptionInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscriptionInfo.ProtoReflect.Descriptor instead.
-func (*SubscriptionInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use LandscapeConfig.ProtoReflect.Descriptor instead.
+func (*LandscapeConfig) Descriptor() ([]byte, []int) {
 	return file_agentapi_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *SubscriptionInfo) GetProductId() string {
+func (x *LandscapeConfig) GetConfig() string {
 	if x != nil {
-		return x.ProductId
+		return x.Config
 	}
 	return ""
 }
 
-func (m *SubscriptionInfo) GetSubscriptionType() isSubscriptionInfo_SubscriptionType {
-	if m != nil {
-		return m.SubscriptionType
-	}
-	return nil
-}
-
-func (x *SubscriptionInfo) GetNone() *Empty {
-	if x, ok := x.GetSubscriptionType().(*SubscriptionInfo_None); ok {
-		return x.None
-	}
-	return nil
-}
-
-func (x *SubscriptionInfo) GetUser() *Empty {
-	if x, ok := x.GetSubscriptionType().(*SubscriptionInfo_User); ok {
-		return x.User
-	}
-	return nil
-}
-
-func (x *SubscriptionInfo) GetOrganization() *Empty {
-	if x, ok := x.GetSubscriptionType().(*SubscriptionInfo_Organization); ok {
-		return x.Organization
-	}
-	return nil
-}
-
-func (x *SubscriptionInfo) GetMicrosoftStore() *Empty {
-	if x, ok := x.GetSubscriptionType().(*SubscriptionInfo_MicrosoftStore); ok {
-		return x.MicrosoftStore
-	}
-	return nil
-}
-
-type isSubscriptionInfo_SubscriptionType interface {
-	isSubscriptionInfo_SubscriptionType()
-}
-
-type SubscriptionInfo_None struct {
-	None *Empty `protobuf:"bytes,2,opt,name=none,proto3,oneof"` // There is no active subscription.
-}
-
-type SubscriptionInfo_User struct {
-	User *Empty `protobuf:"bytes,3,opt,name=user,proto3,oneof"` // The subscription is managed by the user with a pro token from the GUI or the registry.
-}
-
-type SubscriptionInfo_Organization struct {
-	Organization *Empty `protobuf:"bytes,4,opt,name=organization,proto3,oneof"` // The subscription is managed by the sysadmin with a pro token from the registry.
-}
-
-type SubscriptionInfo_MicrosoftStore struct {
-	MicrosoftStore *Empty `protobuf:"bytes,5,opt,name=microsoftStore,proto3,oneof"` // The subscription is managed via the Microsoft store.
-}
-
-func (*SubscriptionInfo_None) isSubscriptionInfo_SubscriptionType() {}
-
-func (*SubscriptionInfo_User) isSubscriptionInfo_SubscriptionType() {}
-
-func (*SubscriptionInfo_Organization) isSubscriptionInfo_SubscriptionType() {}
-
-func (*SubscriptionInfo_MicrosoftStore) isSubscriptionInfo_SubscriptionType() {}
-
-type LandscapeSource struct {
+type FleetStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to LandscapeSourceType:
-	//
-	//	*LandscapeSource_None
-	//	*LandscapeSource_User
-	//	*LandscapeSource_Organization
-	LandscapeSourceType isLandscapeSource_LandscapeSourceType `protobuf_oneof:"landscapeSourceType"`
+	Distros           []*DistroStatus     `protobuf:"bytes,1,rep,name=distros,proto3" json:"distros,omitempty"`                     // Sorted by distro name.
+	ManagedMode       *ManagedMode        `protobuf:"bytes,2,opt,name=managedMode,proto3" json:"managedMode,omitempty"`             // The settings managed by the user's organization.
+	SecurityUpdates   uint32              `protobuf:"varint,3,opt,name=securityUpdates,proto3" json:"securityUpdates,omitempty"`    // The pending security updates of every distro, added up.
+	EsmUpdates        uint32              `protobuf:"varint,4,opt,name=esmUpdates,proto3" json:"esmUpdates,omitempty"`              // The pending Ubuntu Pro-only security updates of every distro, added up.
+	PreflightProblems []*PreflightProblem `protobuf:"bytes,5,rep,name=preflightProblems,proto3" json:"preflightProblems,omitempty"` // The problems with WSL found when the agent started.
+	WslAvailability   *WSLAvailability    `protobuf:"bytes,6,opt,name=wslAvailability,proto3" json:"wslAvailability,omitempty"`     // Whether the agent can use WSL right now.
 }
 
-func (x *LandscapeSource) Reset() {
-	*x = LandscapeSource{}
+func (x *FleetStatus) Reset() {
+	*x = FleetStatus{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agentapi_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -291,13 +231,13 @@ func (x *LandscapeSource) Reset() {
 	}
 }
 
-func (x *LandscapeSource) String() string {
+func (x *FleetStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LandscapeSource) ProtoMessage() {}
+func (*FleetStatus) ProtoMessage() {}
 
-func (x *LandscapeSource) ProtoReflect() protoreflect.Message {
+func (x *FleetStatus) ProtoReflect() protoreflect.Message {
 	mi := &file_agentapi_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -309,72 +249,67 @@ func (x *LandscapeSource) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LandscapeSource.ProtoReflect.Descriptor instead.
-func (*LandscapeSource) Descriptor() ([]byte, []int) {
+// Deprecated: Use FleetStatus.ProtoReflect.Descriptor instead.
+func (*FleetStatus) Descriptor() ([]byte, []int) {
 	return file_agentapi_proto_rawDescGZIP(), []int{4}
 }
 
-func (m *LandscapeSource) GetLandscapeSourceType() isLandscapeSource_LandscapeSourceType {
-	if m != nil {
-		return m.LandscapeSourceType
+func (x *FleetStatus) GetDistros() []*DistroStatus {
+	if x != nil {
+		return x.Distros
 	}
 	return nil
 }
 
-func (x *LandscapeSource) GetNone() *Empty {
-	if x, ok := x.GetLandscapeSourceType().(*LandscapeSource_None); ok {
-		return x.None
+func (x *FleetStatus) GetManagedMode() *ManagedMode {
+	if x != nil {
+		return x.ManagedMode
 	}
 	return nil
 }
 
-func (x *LandscapeSource) GetUser() *Empty {
-	if x, ok := x.GetLandscapeSourceType().(*LandscapeSource_User); ok {
-		return x.User
+func (x *FleetStatus) GetSecurityUpdates() uint32 {
+	if x != nil {
+		return x.SecurityUpdates
 	}
-	return nil
+	return 0
 }
 
-func (x *LandscapeSource) GetOrganization() *Empty {
-	if x, ok := x.GetLandscapeSourceType().(*LandscapeSource_Organization); ok {
-		return x.Organization
+func (x *FleetStatus) GetEsmUpdates() uint32 {
+	if x != nil {
+		return x.EsmUpdates
 	}
-	return nil
-}
-
-type isLandscapeSource_LandscapeSourceType interface {
-	isLandscapeSource_LandscapeSourceType()
-}
-
-type LandscapeSource_None struct {
-	None *Empty `protobuf:"bytes,1,opt,name=none,proto3,oneof"` // There is no active Landscape config data.
+	return 0
 }
 
-type LandscapeSource_User struct {
-	User *Empty `protobuf:"bytes,2,opt,name=user,proto3,oneof"` // The Landscape config is managed by the user, set via the GUI.
+func (x *FleetStatus) GetPreflightProblems() []*PreflightProblem {
+	if x != nil {
+		return x.PreflightProblems
+	}
+	return nil
 }
 
-type LandscapeSource_Organization struct {
-	Organization *Empty `protobuf:"bytes,3,opt,name=organization,proto3,oneof"` // The Landscape config is managedby the sysadmin, set via the registry.
+func (x *FleetStatus) GetWslAvailability() *WSLAvailability {
+	if x != nil {
+		return x.WslAvailability
+	}
+	return nil
 }
 
-func (*LandscapeSource_None) isLandscapeSource_LandscapeSourceType() {}
-
-func (*LandscapeSource_User) isLandscapeSource_LandscapeSourceType() {}
-
-func (*LandscapeSource_Organization) isLandscapeSource_LandscapeSourceType() {}
-
-type ConfigSources struct {
+// WSLAvailability tells whether wsl.exe and the WSL service can be used. While they cannot, the operations
+// of the agent are held back, and they resume on their own once WSL is back.
+type WSLAvailability struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProSubscription *SubscriptionInfo `protobuf:"bytes,1,opt,name=proSubscription,proto3" json:"proSubscription,omitempty"`
-	LandscapeSource *LandscapeSource  `protobuf:"bytes,2,opt,name=landscapeSource,proto3" json:"landscapeSource,omitempty"`
+	Available bool   `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
+	Since     string `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"` // When WSL became unavailable, in RFC 3339 format. Empty while available.
+	Error     string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // Why WSL cannot be used. Empty while available.
 }
 
-func (x *ConfigSources) Reset() {
-	*x = ConfigSources{}
+func (x *WSLAvailability) Reset() {
+	*x = WSLAvailability{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agentapi_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -382,13 +317,13 @@ func (x *ConfigSources) Reset() {
 	}
 }
 
-func (x *ConfigSources) String() string {
+func (x *WSLAvailability) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConfigSources) ProtoMessage() {}
+func (*WSLAvailability) ProtoMessage() {}
 
-func (x *ConfigSources) ProtoReflect() protoreflect.Message {
+func (x *WSLAvailability) ProtoReflect() protoreflect.Message {
 	mi := &file_agentapi_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -400,40 +335,47 @@ func (x *ConfigSources) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConfigSources.ProtoReflect.Descriptor instead.
-func (*ConfigSources) Descriptor() ([]byte, []int) {
+// Deprecated: Use WSLAvailability.ProtoReflect.Descriptor instead.
+func (*WSLAvailability) Descriptor() ([]byte, []int) {
 	return file_agentapi_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *ConfigSources) GetProSubscription() *SubscriptionInfo {
+func (x *WSLAvailability) GetAvailable() bool {
 	if x != nil {
-		return x.ProSubscription
+		return x.Available
 	}
-	return nil
+	return false
 }
 
-func (x *ConfigSources) GetLandscapeSource() *LandscapeSource {
+func (x *WSLAvailability) GetSince() string {
 	if x != nil {
-		return x.LandscapeSource
+		return x.Since
 	}
-	return nil
+	return ""
 }
 
-type DistroInfo struct {
+func (x *WSLAvailability) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// PreflightProblem is an issue with WSL that prevents the agent from managing distros.
+type PreflightProblem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	WslName     string `protobuf:"bytes,1,opt,name=wsl_name,json=wslName,proto3" json:"wsl_name,omitempty"`
-	Id          string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	VersionId   string `protobuf:"bytes,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
-	PrettyName  string `protobuf:"bytes,4,opt,name=pretty_name,json=prettyName,proto3" json:"pretty_name,omitempty"`
-	ProAttached bool   `protobuf:"varint,5,opt,name=pro_attached,json=proAttached,proto3" json:"pro_attached,omitempty"`
-	Hostname    string `protobuf:"bytes,6,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	IsError     bool   `protobuf:"varint,1,opt,name=isError,proto3" json:"isError,omitempty"` // Errors prevent the agent from working. Warnings may do so.
+	Check       string `protobuf:"bytes,2,opt,name=check,proto3" json:"check,omitempty"`      // The name of the check that found the problem.
+	Message     string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Action      string `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`           // The fix: install-wsl, update-wsl, set-default-version or enable-feature.
+	Remediation string `protobuf:"bytes,5,opt,name=remediation,proto3" json:"remediation,omitempty"` // The fix, explained to the user.
 }
 
-func (x *DistroInfo) Reset() {
-	*x = DistroInfo{}
+func (x *PreflightProblem) Reset() {
+	*x = PreflightProblem{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agentapi_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -441,13 +383,13 @@ func (x *DistroInfo) Reset() {
 	}
 }
 
-func (x *DistroInfo) String() string {
+func (x *PreflightProblem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DistroInfo) ProtoMessage() {}
+func (*PreflightProblem) ProtoMessage() {}
 
-func (x *DistroInfo) ProtoReflect() protoreflect.Message {
+func (x *PreflightProblem) ProtoReflect() protoreflect.Message {
 	mi := &file_agentapi_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -459,63 +401,61 @@ func (x *DistroInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DistroInfo.ProtoReflect.Descriptor instead.
-func (*DistroInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use PreflightProblem.ProtoReflect.Descriptor instead.
+func (*PreflightProblem) Descriptor() ([]byte, []int) {
 	return file_agentapi_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *DistroInfo) GetWslName() string {
+func (x *PreflightProblem) GetIsError() bool {
 	if x != nil {
-		return x.WslName
+		return x.IsError
 	}
-	return ""
+	return false
 }
 
-func (x *DistroInfo) GetId() string {
+func (x *PreflightProblem) GetCheck() string {
 	if x != nil {
-		return x.Id
+		return x.Check
 	}
 	return ""
 }
 
-func (x *DistroInfo) GetVersionId() string {
+func (x *PreflightProblem) GetMessage() string {
 	if x != nil {
-		return x.VersionId
+		return x.Message
 	}
 	return ""
 }
 
-func (x *DistroInfo) GetPrettyName() string {
+func (x *PreflightProblem) GetAction() string {
 	if x != nil {
-		return x.PrettyName
+		return x.Action
 	}
 	return ""
 }
 
-func (x *DistroInfo) GetProAttached() bool {
-	if x != nil {
-		return x.ProAttached
-	}
-	return false
-}
-
-func (x *DistroInfo) GetHostname() string {
+func (x *PreflightProblem) GetRemediation() string {
 	if x != nil {
-		return x.Hostname
+		return x.Remediation
 	}
 	return ""
 }
 
-type Port struct {
+// ManagedMode explains which settings the user cannot change because their organization manages them:
+// the Ubuntu Pro subscription and the Landscape configuration. Attempting to change them fails with the
+// FAILED_PRECONDITION status code.
+type ManagedMode struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Port uint32 `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+	RegistryReadOnly bool `protobuf:"varint,1,opt,name=registryReadOnly,proto3" json:"registryReadOnly,omitempty"` // The organization governs the agent via policy: neither the subscription nor the Landscape configuration can be changed.
+	Subscription     bool `protobuf:"varint,2,opt,name=subscription,proto3" json:"subscription,omitempty"`         // The organization provides the Ubuntu Pro subscription.
+	Landscape        bool `protobuf:"varint,3,opt,name=landscape,proto3" json:"landscape,omitempty"`               // The organization provides the Landscape configuration.
 }
 
-func (x *Port) Reset() {
-	*x = Port{}
+func (x *ManagedMode) Reset() {
+	*x = ManagedMode{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agentapi_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -523,13 +463,13 @@ func (x *Port) Reset() {
 	}
 }
 
-func (x *Port) String() string {
+func (x *ManagedMode) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Port) ProtoMessage() {}
+func (*ManagedMode) ProtoMessage() {}
 
-func (x *Port) ProtoReflect() protoreflect.Message {
+func (x *ManagedMode) ProtoReflect() protoreflect.Message {
 	mi := &file_agentapi_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -541,170 +481,5489 @@ func (x *Port) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Port.ProtoReflect.Descriptor instead.
-func (*Port) Descriptor() ([]byte, []int) {
+// Deprecated: Use ManagedMode.ProtoReflect.Descriptor instead.
+func (*ManagedMode) Descriptor() ([]byte, []int) {
 	return file_agentapi_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Port) GetPort() uint32 {
+func (x *ManagedMode) GetRegistryReadOnly() bool {
 	if x != nil {
-		return x.Port
+		return x.RegistryReadOnly
 	}
-	return 0
+	return false
 }
 
-var File_agentapi_proto protoreflect.FileDescriptor
+func (x *ManagedMode) GetSubscription() bool {
+	if x != nil {
+		return x.Subscription
+	}
+	return false
+}
 
-var file_agentapi_proto_rawDesc = []byte{
-	0x0a, 0x0e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x08, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x22, 0x25, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
-	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x29, 0x0a, 0x0f, 0x4c, 0x61,
-	0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a,
-	0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x84, 0x02, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72,
-	0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x04, 0x6e, 0x6f, 0x6e, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
-	0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x6f, 0x6e, 0x65, 0x12,
-	0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00,
-	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69,
-	0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52,
-	0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a,
-	0x0e, 0x6d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x6f, 0x66, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x0e, 0x6d, 0x69, 0x63, 0x72, 0x6f, 0x73,
-	0x6f, 0x66, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x73, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x22, 0xad, 0x01, 0x0a,
-	0x0f, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
-	0x12, 0x25, 0x0a, 0x04, 0x6e, 0x6f, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48,
-	0x00, 0x52, 0x04, 0x6e, 0x6f, 0x6e, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x35,
-	0x0a, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x15, 0x0a, 0x13, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61,
-	0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x22, 0x9a, 0x01, 0x0a,
-	0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x44,
-	0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61,
-	0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x43, 0x0a, 0x0f, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70,
-	0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61,
-	0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x0f, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63,
-	0x61, 0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xb6, 0x01, 0x0a, 0x0a, 0x44, 0x69,
-	0x73, 0x74, 0x72, 0x6f, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x0a, 0x08, 0x77, 0x73, 0x6c, 0x5f,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x77, 0x73, 0x6c, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x74, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x74, 0x74, 0x79, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x5f, 0x61, 0x74, 0x74, 0x61, 0x63,
-	0x68, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x41, 0x74,
-	0x74, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61,
-	0x6d, 0x65, 0x22, 0x1a, 0x0a, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f,
-	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x32, 0xc9,
-	0x02, 0x0a, 0x02, 0x55, 0x49, 0x12, 0x46, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x50, 0x72,
-	0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x17, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
-	0x69, 0x2e, 0x50, 0x72, 0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x66, 0x6f, 0x1a,
-	0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x4e, 0x0a,
-	0x14, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
-	0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x1a, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x61, 0x6e, 0x64,
-	0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x00, 0x12, 0x2a, 0x0a,
-	0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
-	0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x10, 0x47, 0x65, 0x74,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x0f, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x17,
-	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0e, 0x4e, 0x6f, 0x74,
-	0x69, 0x66, 0x79, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x12, 0x0f, 0x2e, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x32, 0x46, 0x0a, 0x0b, 0x57, 0x53,
-	0x4c, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x43, 0x6f, 0x6e,
-	0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
-	0x69, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x0e, 0x2e, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x00, 0x28, 0x01,
-	0x30, 0x01, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x63, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61, 0x6c, 0x2f, 0x75, 0x62, 0x75, 0x6e, 0x74,
-	0x75, 0x2d, 0x70, 0x72, 0x6f, 0x2d, 0x66, 0x6f, 0x72, 0x2d, 0x77, 0x73, 0x6c, 0x2f, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *ManagedMode) GetLandscape() bool {
+	if x != nil {
+		return x.Landscape
+	}
+	return false
 }
 
-var (
-	file_agentapi_proto_rawDescOnce sync.Once
-	file_agentapi_proto_rawDescData = file_agentapi_proto_rawDesc
-)
+type DistroStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_agentapi_proto_rawDescGZIP() []byte {
-	file_agentapi_proto_rawDescOnce.Do(func() {
-		file_agentapi_proto_rawDescData = protoimpl.X.CompressGZIP(file_agentapi_proto_rawDescData)
-	})
-	return file_agentapi_proto_rawDescData
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                  // The name of the distro.
+	ProAttached          bool     `protobuf:"varint,2,opt,name=proAttached,proto3" json:"proAttached,omitempty"`                   // The distro is attached to Ubuntu Pro.
+	ProServices          []string `protobuf:"bytes,3,rep,name=proServices,proto3" json:"proServices,omitempty"`                    // The Ubuntu Pro services enabled in the distro.
+	LastContact          string   `protobuf:"bytes,4,opt,name=lastContact,proto3" json:"lastContact,omitempty"`                    // The last time the distro reported its status, in RFC 3339 format. Empty if unknown.
+	PendingTasks         uint32   `protobuf:"varint,5,opt,name=pendingTasks,proto3" json:"pendingTasks,omitempty"`                 // The number of tasks waiting to run in the distro.
+	LandscapeRegistered  bool     `protobuf:"varint,6,opt,name=landscapeRegistered,proto3" json:"landscapeRegistered,omitempty"`   // The distro is registered in Landscape.
+	Connected            bool     `protobuf:"varint,7,opt,name=connected,proto3" json:"connected,omitempty"`                       // There is an active connection to the distro.
+	SecurityUpdates      uint32   `protobuf:"varint,8,opt,name=securityUpdates,proto3" json:"securityUpdates,omitempty"`           // The number of pending security updates from the standard Ubuntu archive.
+	EsmUpdates           uint32   `protobuf:"varint,9,opt,name=esmUpdates,proto3" json:"esmUpdates,omitempty"`                     // The number of pending security updates only available with Ubuntu Pro.
+	SecurityChecked      string   `protobuf:"bytes,10,opt,name=securityChecked,proto3" json:"securityChecked,omitempty"`           // The last time the pending updates were checked, in RFC 3339 format. Empty if never.
+	MemoryBytes          uint64   `protobuf:"varint,11,opt,name=memoryBytes,proto3" json:"memoryBytes,omitempty"`                  // The resident memory of the processes of the distro.
+	CpuPercent           float64  `protobuf:"fixed64,12,opt,name=cpuPercent,proto3" json:"cpuPercent,omitempty"`                   // The CPU used by the processes of the distro, as a percentage of one CPU.
+	DiskBytes            uint64   `protobuf:"varint,13,opt,name=diskBytes,proto3" json:"diskBytes,omitempty"`                      // The size of the virtual disk of the distro.
+	ResourcesChecked     string   `protobuf:"bytes,14,opt,name=resourcesChecked,proto3" json:"resourcesChecked,omitempty"`         // The last time the resource usage was measured, in RFC 3339 format. Empty if never.
+	NeedsAttention       bool     `protobuf:"varint,15,opt,name=needsAttention,proto3" json:"needsAttention,omitempty"`            // Too many tasks failed in a row: the distro is not woken up to run them until the next retry.
+	LastTaskError        string   `protobuf:"bytes,16,opt,name=lastTaskError,proto3" json:"lastTaskError,omitempty"`               // The error of the last task that failed, if it was one of a series of failures.
+	NextRetry            string   `protobuf:"bytes,17,opt,name=nextRetry,proto3" json:"nextRetry,omitempty"`                       // When the tasks of a distro that needs attention are retried, in RFC 3339 format. Empty otherwise.
+	RebootRequired       bool     `protobuf:"varint,18,opt,name=rebootRequired,proto3" json:"rebootRequired,omitempty"`            // An update only takes effect after the distro is rebooted.
+	RebootPackages       []string `protobuf:"bytes,19,rep,name=rebootPackages,proto3" json:"rebootPackages,omitempty"`             // The packages whose update requires the reboot.
+	PendingUpdates       uint32   `protobuf:"varint,20,opt,name=pendingUpdates,proto3" json:"pendingUpdates,omitempty"`            // The number of packages that can be upgraded.
+	AwakeOnDemand        bool     `protobuf:"varint,21,opt,name=awakeOnDemand,proto3" json:"awakeOnDemand,omitempty"`              // The distro is kept awake by WakeDistro.
+	AwakeUntil           string   `protobuf:"bytes,22,opt,name=awakeUntil,proto3" json:"awakeUntil,omitempty"`                     // When the distro kept awake by WakeDistro is released, in RFC 3339 format. Empty if it lasts until StopDistro.
+	Labels               []*Label `protobuf:"bytes,23,rep,name=labels,proto3" json:"labels,omitempty"`                             // The labels set on the distro, sorted by key.
+	ServiceVersion       string   `protobuf:"bytes,24,opt,name=serviceVersion,proto3" json:"serviceVersion,omitempty"`             // The version of the WSL Pro service of the distro, as reported when it last connected. Empty if unknown.
+	LandscapeScriptUsers string   `protobuf:"bytes,25,opt,name=landscapeScriptUsers,proto3" json:"landscapeScriptUsers,omitempty"` // The users that Landscape scripts may run as in the distro, as it last reported them. Empty if left to the default of the Landscape client.
 }
 
-var file_agentapi_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
-var file_agentapi_proto_goTypes = []interface{}{
-	(*Empty)(nil),            // 0: agentapi.Empty
-	(*ProAttachInfo)(nil),    // 1: agentapi.ProAttachInfo
-	(*LandscapeConfig)(nil),  // 2: agentapi.LandscapeConfig
-	(*SubscriptionInfo)(nil), // 3: agentapi.SubscriptionInfo
-	(*LandscapeSource)(nil),  // 4: agentapi.LandscapeSource
-	(*ConfigSources)(nil),    // 5: agentapi.ConfigSources
-	(*DistroInfo)(nil),       // 6: agentapi.DistroInfo
-	(*Port)(nil),             // 7: agentapi.Port
+func (x *DistroStatus) Reset() {
+	*x = DistroStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_agentapi_proto_depIdxs = []int32{
-	0,  // 0: agentapi.SubscriptionInfo.none:type_name -> agentapi.Empty
-	0,  // 1: agentapi.SubscriptionInfo.user:type_name -> agentapi.Empty
-	0,  // 2: agentapi.SubscriptionInfo.organization:type_name -> agentapi.Empty
-	0,  // 3: agentapi.SubscriptionInfo.microsoftStore:type_name -> agentapi.Empty
-	0,  // 4: agentapi.LandscapeSource.none:type_name -> agentapi.Empty
-	0,  // 5: agentapi.LandscapeSource.user:type_name -> agentapi.Empty
-	0,  // 6: agentapi.LandscapeSource.organization:type_name -> agentapi.Empty
-	3,  // 7: agentapi.ConfigSources.proSubscription:type_name -> agentapi.SubscriptionInfo
-	4,  // 8: agentapi.ConfigSources.landscapeSource:type_name -> agentapi.LandscapeSource
-	1,  // 9: agentapi.UI.ApplyProToken:input_type -> agentapi.ProAttachInfo
-	2,  // 10: agentapi.UI.ApplyLandscapeConfig:input_type -> agentapi.LandscapeConfig
-	0,  // 11: agentapi.UI.Ping:input_type -> agentapi.Empty
-	0,  // 12: agentapi.UI.GetConfigSources:input_type -> agentapi.Empty
-	0,  // 13: agentapi.UI.NotifyPurchase:input_type -> agentapi.Empty
-	6,  // 14: agentapi.WSLInstance.Connected:input_type -> agentapi.DistroInfo
-	3,  // 15: agentapi.UI.ApplyProToken:output_type -> agentapi.SubscriptionInfo
-	4,  // 16: agentapi.UI.ApplyLandscapeConfig:output_type -> agentapi.LandscapeSource
-	0,  // 17: agentapi.UI.Ping:output_type -> agentapi.Empty
-	5,  // 18: agentapi.UI.GetConfigSources:output_type -> agentapi.ConfigSources
-	3,  // 19: agentapi.UI.NotifyPurchase:output_type -> agentapi.SubscriptionInfo
-	7,  // 20: agentapi.WSLInstance.Connected:output_type -> agentapi.Port
-	15, // [15:21] is the sub-list for method output_type
-	9,  // [9:15] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+
+func (x *DistroStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_agentapi_proto_init() }
-func file_agentapi_proto_init() {
-	if File_agentapi_proto != nil {
-		return
+func (*DistroStatus) ProtoMessage() {}
+
+func (x *DistroStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_agentapi_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Empty); i {
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroStatus.ProtoReflect.Descriptor instead.
+func (*DistroStatus) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DistroStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DistroStatus) GetProAttached() bool {
+	if x != nil {
+		return x.ProAttached
+	}
+	return false
+}
+
+func (x *DistroStatus) GetProServices() []string {
+	if x != nil {
+		return x.ProServices
+	}
+	return nil
+}
+
+func (x *DistroStatus) GetLastContact() string {
+	if x != nil {
+		return x.LastContact
+	}
+	return ""
+}
+
+func (x *DistroStatus) GetPendingTasks() uint32 {
+	if x != nil {
+		return x.PendingTasks
+	}
+	return 0
+}
+
+func (x *DistroStatus) GetLandscapeRegistered() bool {
+	if x != nil {
+		return x.LandscapeRegistered
+	}
+	return false
+}
+
+func (x *DistroStatus) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *DistroStatus) GetSecurityUpdates() uint32 {
+	if x != nil {
+		return x.SecurityUpdates
+	}
+	return 0
+}
+
+func (x *DistroStatus) GetEsmUpdates() uint32 {
+	if x != nil {
+		return x.EsmUpdates
+	}
+	return 0
+}
+
+func (x *DistroStatus) GetSecurityChecked() string {
+	if x != nil {
+		return x.SecurityChecked
+	}
+	return ""
+}
+
+func (x *DistroStatus) GetMemoryBytes() uint64 {
+	if x != nil {
+		return x.MemoryBytes
+	}
+	return 0
+}
+
+func (x *DistroStatus) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *DistroStatus) GetDiskBytes() uint64 {
+	if x != nil {
+		return x.DiskBytes
+	}
+	return 0
+}
+
+func (x *DistroStatus) GetResourcesChecked() string {
+	if x != nil {
+		return x.ResourcesChecked
+	}
+	return ""
+}
+
+func (x *DistroStatus) GetNeedsAttention() bool {
+	if x != nil {
+		return x.NeedsAttention
+	}
+	return false
+}
+
+func (x *DistroStatus) GetLastTaskError() string {
+	if x != nil {
+		return x.LastTaskError
+	}
+	return ""
+}
+
+func (x *DistroStatus) GetNextRetry() string {
+	if x != nil {
+		return x.NextRetry
+	}
+	return ""
+}
+
+func (x *DistroStatus) GetRebootRequired() bool {
+	if x != nil {
+		return x.RebootRequired
+	}
+	return false
+}
+
+func (x *DistroStatus) GetRebootPackages() []string {
+	if x != nil {
+		return x.RebootPackages
+	}
+	return nil
+}
+
+func (x *DistroStatus) GetPendingUpdates() uint32 {
+	if x != nil {
+		return x.PendingUpdates
+	}
+	return 0
+}
+
+func (x *DistroStatus) GetAwakeOnDemand() bool {
+	if x != nil {
+		return x.AwakeOnDemand
+	}
+	return false
+}
+
+func (x *DistroStatus) GetAwakeUntil() string {
+	if x != nil {
+		return x.AwakeUntil
+	}
+	return ""
+}
+
+func (x *DistroStatus) GetLabels() []*Label {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *DistroStatus) GetServiceVersion() string {
+	if x != nil {
+		return x.ServiceVersion
+	}
+	return ""
+}
+
+func (x *DistroStatus) GetLandscapeScriptUsers() string {
+	if x != nil {
+		return x.LandscapeScriptUsers
+	}
+	return ""
+}
+
+// DistroChange is streamed by WatchDistros every time a distro is added, updated or removed, so that the UI
+// can fetch the fleet status again instead of polling it.
+type DistroChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`
+	Change     string `protobuf:"bytes,2,opt,name=change,proto3" json:"change,omitempty"` // The kind of change: added, updated or removed.
+}
+
+func (x *DistroChange) Reset() {
+	*x = DistroChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroChange) ProtoMessage() {}
+
+func (x *DistroChange) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroChange.ProtoReflect.Descriptor instead.
+func (*DistroChange) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DistroChange) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *DistroChange) GetChange() string {
+	if x != nil {
+		return x.Change
+	}
+	return ""
+}
+
+type ExportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName  string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`   // The distro to export.
+	Destination string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"` // The directory to export the distro to. Empty to use the default backup directory.
+}
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRequest) ProtoMessage() {}
+
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExportRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+type ExportProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BytesWritten uint64 `protobuf:"varint,1,opt,name=bytesWritten,proto3" json:"bytesWritten,omitempty"` // The size of the tarball written so far.
+	Done         bool   `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`                 // The export finished successfully. Only the last message is done.
+	Path         string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`                  // The path to the tarball. Only set in the last message.
+}
+
+func (x *ExportProgress) Reset() {
+	*x = ExportProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportProgress) ProtoMessage() {}
+
+func (x *ExportProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportProgress.ProtoReflect.Descriptor instead.
+func (*ExportProgress) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExportProgress) GetBytesWritten() uint64 {
+	if x != nil {
+		return x.BytesWritten
+	}
+	return 0
+}
+
+func (x *ExportProgress) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *ExportProgress) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type BackupSchedule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IntervalHours uint32 `protobuf:"varint,1,opt,name=intervalHours,proto3" json:"intervalHours,omitempty"` // The minimum time between two backups of the same distro. Zero to disable periodic backups.
+	Destination   string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`      // The directory to export the distros to. Empty to use the default backup directory.
+	Keep          uint32 `protobuf:"varint,3,opt,name=keep,proto3" json:"keep,omitempty"`                   // How many backups of each distro are kept. Zero to keep them all.
+}
+
+func (x *BackupSchedule) Reset() {
+	*x = BackupSchedule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackupSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupSchedule) ProtoMessage() {}
+
+func (x *BackupSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupSchedule.ProtoReflect.Descriptor instead.
+func (*BackupSchedule) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BackupSchedule) GetIntervalHours() uint32 {
+	if x != nil {
+		return x.IntervalHours
+	}
+	return 0
+}
+
+func (x *BackupSchedule) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *BackupSchedule) GetKeep() uint32 {
+	if x != nil {
+		return x.Keep
+	}
+	return 0
+}
+
+// WakeRequest starts a distro and keeps it awake without submitting any task, e.g. to warm it up before pushing work.
+type WakeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName       string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`              // The distro to wake up.
+	KeepAwakeMinutes uint32 `protobuf:"varint,2,opt,name=keepAwakeMinutes,proto3" json:"keepAwakeMinutes,omitempty"` // How long the distro is kept awake. Zero to keep it awake until StopDistro.
+}
+
+func (x *WakeRequest) Reset() {
+	*x = WakeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WakeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WakeRequest) ProtoMessage() {}
+
+func (x *WakeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WakeRequest.ProtoReflect.Descriptor instead.
+func (*WakeRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WakeRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *WakeRequest) GetKeepAwakeMinutes() uint32 {
+	if x != nil {
+		return x.KeepAwakeMinutes
+	}
+	return 0
+}
+
+// StopRequest releases a distro kept awake by WakeDistro, so that it shuts down once idle.
+type StopRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"` // The distro to release.
+}
+
+func (x *StopRequest) Reset() {
+	*x = StopRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRequest) ProtoMessage() {}
+
+func (x *StopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
+func (*StopRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StopRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+// DistroLabels replaces the labels of a distro. Recurring tasks can target the distros by their labels.
+type DistroLabels struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string   `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"` // The distro to label.
+	Labels     []*Label `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty"`         // The new labels of the distro. Labels with an empty value are removed.
+}
+
+func (x *DistroLabels) Reset() {
+	*x = DistroLabels{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroLabels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroLabels) ProtoMessage() {}
+
+func (x *DistroLabels) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroLabels.ProtoReflect.Descriptor instead.
+func (*DistroLabels) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DistroLabels) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *DistroLabels) GetLabels() []*Label {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type Label struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Label) Reset() {
+	*x = Label{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Label) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Label) ProtoMessage() {}
+
+func (x *Label) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Label.ProtoReflect.Descriptor instead.
+func (*Label) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Label) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Label) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type CompactRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"` // The distro whose virtual disk is compacted. It must be stopped.
+}
+
+func (x *CompactRequest) Reset() {
+	*x = CompactRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CompactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactRequest) ProtoMessage() {}
+
+func (x *CompactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactRequest.ProtoReflect.Descriptor instead.
+func (*CompactRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CompactRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+type CompactResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SizeBefore uint64 `protobuf:"varint,1,opt,name=sizeBefore,proto3" json:"sizeBefore,omitempty"` // The size of the virtual disk before compaction.
+	SizeAfter  uint64 `protobuf:"varint,2,opt,name=sizeAfter,proto3" json:"sizeAfter,omitempty"`   // The size of the virtual disk after compaction.
+}
+
+func (x *CompactResult) Reset() {
+	*x = CompactResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CompactResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactResult) ProtoMessage() {}
+
+func (x *CompactResult) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactResult.ProtoReflect.Descriptor instead.
+func (*CompactResult) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CompactResult) GetSizeBefore() uint64 {
+	if x != nil {
+		return x.SizeBefore
+	}
+	return 0
+}
+
+func (x *CompactResult) GetSizeAfter() uint64 {
+	if x != nil {
+		return x.SizeAfter
+	}
+	return 0
+}
+
+type SparseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"` // The distro whose virtual disk is changed. It must be stopped.
+	Sparse     bool   `protobuf:"varint,2,opt,name=sparse,proto3" json:"sparse,omitempty"`        // Whether WSL gives the unused space of the virtual disk back to Windows on its own.
+}
+
+func (x *SparseRequest) Reset() {
+	*x = SparseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SparseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SparseRequest) ProtoMessage() {}
+
+func (x *SparseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SparseRequest.ProtoReflect.Descriptor instead.
+func (*SparseRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SparseRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *SparseRequest) GetSparse() bool {
+	if x != nil {
+		return x.Sparse
+	}
+	return false
+}
+
+type CompactSchedule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IntervalHours uint32 `protobuf:"varint,1,opt,name=intervalHours,proto3" json:"intervalHours,omitempty"` // The minimum time between two compactions of the same distro. Zero to disable periodic compaction.
+}
+
+func (x *CompactSchedule) Reset() {
+	*x = CompactSchedule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CompactSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactSchedule) ProtoMessage() {}
+
+func (x *CompactSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactSchedule.ProtoReflect.Descriptor instead.
+func (*CompactSchedule) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CompactSchedule) GetIntervalHours() uint32 {
+	if x != nil {
+		return x.IntervalHours
+	}
+	return 0
+}
+
+type WSLConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Memory          string `protobuf:"bytes,1,opt,name=memory,proto3" json:"memory,omitempty"`                    // How much memory the WSL virtual machine can use, such as "8GB". Empty to use the WSL default.
+	Processors      uint32 `protobuf:"varint,2,opt,name=processors,proto3" json:"processors,omitempty"`           // How many logical processors the WSL virtual machine can use. Zero to use the WSL default.
+	NetworkingMode  string `protobuf:"bytes,3,opt,name=networkingMode,proto3" json:"networkingMode,omitempty"`    // One of "nat", "mirrored", "virtioproxy" or "none". Empty to use the WSL default.
+	RestartRequired bool   `protobuf:"varint,4,opt,name=restartRequired,proto3" json:"restartRequired,omitempty"` // Set by the agent: the settings were changed while WSL was running, and only take effect once it restarts.
+}
+
+func (x *WSLConfig) Reset() {
+	*x = WSLConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WSLConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WSLConfig) ProtoMessage() {}
+
+func (x *WSLConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WSLConfig.ProtoReflect.Descriptor instead.
+func (*WSLConfig) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WSLConfig) GetMemory() string {
+	if x != nil {
+		return x.Memory
+	}
+	return ""
+}
+
+func (x *WSLConfig) GetProcessors() uint32 {
+	if x != nil {
+		return x.Processors
+	}
+	return 0
+}
+
+func (x *WSLConfig) GetNetworkingMode() string {
+	if x != nil {
+		return x.NetworkingMode
+	}
+	return ""
+}
+
+func (x *WSLConfig) GetRestartRequired() bool {
+	if x != nil {
+		return x.RestartRequired
+	}
+	return false
+}
+
+type ResetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName     string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`          // The distro to reset.
+	KeepProperties bool   `protobuf:"varint,2,opt,name=keepProperties,proto3" json:"keepProperties,omitempty"` // Keep the properties the agent knows about the distro instead of wiping them.
+	Rootfs         string `protobuf:"bytes,3,opt,name=rootfs,proto3" json:"rootfs,omitempty"`                  // If set, the distro is unregistered and imported again from this tarball before provisioning.
+}
+
+func (x *ResetRequest) Reset() {
+	*x = ResetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetRequest) ProtoMessage() {}
+
+func (x *ResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
+func (*ResetRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ResetRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *ResetRequest) GetKeepProperties() bool {
+	if x != nil {
+		return x.KeepProperties
+	}
+	return false
+}
+
+func (x *ResetRequest) GetRootfs() string {
+	if x != nil {
+		return x.Rootfs
+	}
+	return ""
+}
+
+// ReloadResult tells which settings took effect when the configuration was reloaded.
+type ReloadResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Settings []*ReloadedSetting `protobuf:"bytes,1,rep,name=settings,proto3" json:"settings,omitempty"`
+}
+
+func (x *ReloadResult) Reset() {
+	*x = ReloadResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReloadResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadResult) ProtoMessage() {}
+
+func (x *ReloadResult) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadResult.ProtoReflect.Descriptor instead.
+func (*ReloadResult) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ReloadResult) GetSettings() []*ReloadedSetting {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type ReloadedSetting struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name            string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RestartRequired bool   `protobuf:"varint,2,opt,name=restartRequired,proto3" json:"restartRequired,omitempty"` // The setting only takes effect once the agent restarts.
+	Error           string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`                      // Why the setting could not be applied. Empty if it was, or if it requires a restart.
+}
+
+func (x *ReloadedSetting) Reset() {
+	*x = ReloadedSetting{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReloadedSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadedSetting) ProtoMessage() {}
+
+func (x *ReloadedSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadedSetting.ProtoReflect.Descriptor instead.
+func (*ReloadedSetting) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReloadedSetting) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReloadedSetting) GetRestartRequired() bool {
+	if x != nil {
+		return x.RestartRequired
+	}
+	return false
+}
+
+func (x *ReloadedSetting) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DistroLogsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"` // The distro whose WSL Pro service logs are fetched. It is started if needed.
+	Lines      uint32 `protobuf:"varint,2,opt,name=lines,proto3" json:"lines,omitempty"`          // How many of the most recent entries to fetch. Zero for the default of 200.
+}
+
+func (x *DistroLogsRequest) Reset() {
+	*x = DistroLogsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroLogsRequest) ProtoMessage() {}
+
+func (x *DistroLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroLogsRequest.ProtoReflect.Descriptor instead.
+func (*DistroLogsRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DistroLogsRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *DistroLogsRequest) GetLines() uint32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+type DistroLogs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*LogEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"` // Sorted from oldest to newest.
+}
+
+func (x *DistroLogs) Reset() {
+	*x = DistroLogs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroLogs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroLogs) ProtoMessage() {}
+
+func (x *DistroLogs) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroLogs.ProtoReflect.Descriptor instead.
+func (*DistroLogs) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DistroLogs) GetEntries() []*LogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type LogEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time     string `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`          // In RFC 3339 format.
+	Priority uint32 `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"` // The syslog priority, from 0 (emergency) to 7 (debug).
+	Message  string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`    // Secrets are redacted.
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LogEntry) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *LogEntry) GetPriority() uint32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DistroFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName  string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`   // The distro to copy the file from. It is started if needed.
+	Path        string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`               // The absolute path of the file in the distro, such as a log or a report.
+	Destination string `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"` // The absolute Windows path to copy the file to. It must not exist yet.
+}
+
+func (x *DistroFileRequest) Reset() {
+	*x = DistroFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroFileRequest) ProtoMessage() {}
+
+func (x *DistroFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroFileRequest.ProtoReflect.Descriptor instead.
+func (*DistroFileRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DistroFileRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *DistroFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DistroFileRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+type DistroFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Destination string `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"` // Where the file was copied to.
+	Size        uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`              // In bytes, of the file in the distro.
+	Sha256      string `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`           // The hex-encoded checksum the copy was verified against, before redaction.
+	Redacted    bool   `protobuf:"varint,4,opt,name=redacted,proto3" json:"redacted,omitempty"`      // Whether secrets were redacted from the copy, which then differs from the checksum.
+}
+
+func (x *DistroFile) Reset() {
+	*x = DistroFile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroFile) ProtoMessage() {}
+
+func (x *DistroFile) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroFile.ProtoReflect.Descriptor instead.
+func (*DistroFile) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DistroFile) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *DistroFile) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DistroFile) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *DistroFile) GetRedacted() bool {
+	if x != nil {
+		return x.Redacted
+	}
+	return false
+}
+
+type DistroTasksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"` // The distro whose queued tasks are listed.
+}
+
+func (x *DistroTasksRequest) Reset() {
+	*x = DistroTasksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroTasksRequest) ProtoMessage() {}
+
+func (x *DistroTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroTasksRequest.ProtoReflect.Descriptor instead.
+func (*DistroTasksRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DistroTasksRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+type DistroTasks struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tasks []*QueuedTask `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"` // Regular tasks first, in the order they will run, then deferred ones.
+}
+
+func (x *DistroTasks) Reset() {
+	*x = DistroTasks{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroTasks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroTasks) ProtoMessage() {}
+
+func (x *DistroTasks) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroTasks.ProtoReflect.Descriptor instead.
+func (*DistroTasks) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DistroTasks) GetTasks() []*QueuedTask {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type QueuedTask struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`               // Identifies the task, e.g. to cancel it.
+	Task      string `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`           // A human-readable description of the task.
+	Deferred  bool   `protobuf:"varint,3,opt,name=deferred,proto3" json:"deferred,omitempty"`  // Deferred tasks wait until the distro is started by the user.
+	Submitted string `protobuf:"bytes,4,opt,name=submitted,proto3" json:"submitted,omitempty"` // In RFC 3339 format.
+	Attempts  uint32 `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`  // How many times the task was started, counting retries.
+}
+
+func (x *QueuedTask) Reset() {
+	*x = QueuedTask{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueuedTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueuedTask) ProtoMessage() {}
+
+func (x *QueuedTask) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueuedTask.ProtoReflect.Descriptor instead.
+func (*QueuedTask) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *QueuedTask) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *QueuedTask) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *QueuedTask) GetDeferred() bool {
+	if x != nil {
+		return x.Deferred
+	}
+	return false
+}
+
+func (x *QueuedTask) GetSubmitted() string {
+	if x != nil {
+		return x.Submitted
+	}
+	return ""
+}
+
+func (x *QueuedTask) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+type CancelTaskRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"` // The distro whose task is cancelled.
+	Id         string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`                 // The ID of the task, as listed by GetDistroTasks. Tasks that depend on it are cancelled too.
+}
+
+func (x *CancelTaskRequest) Reset() {
+	*x = CancelTaskRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTaskRequest) ProtoMessage() {}
+
+func (x *CancelTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTaskRequest.ProtoReflect.Descriptor instead.
+func (*CancelTaskRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CancelTaskRequest) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *CancelTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// OperationRequest starts an operation that runs in the background, independently of the client that
+// requested it. Clients can reattach to it by its ID after they restart.
+type OperationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Request:
+	//
+	//	*OperationRequest_ExportDistro
+	//	*OperationRequest_ResetDistro
+	Request isOperationRequest_Request `protobuf_oneof:"request"`
+}
+
+func (x *OperationRequest) Reset() {
+	*x = OperationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationRequest) ProtoMessage() {}
+
+func (x *OperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationRequest.ProtoReflect.Descriptor instead.
+func (*OperationRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{34}
+}
+
+func (m *OperationRequest) GetRequest() isOperationRequest_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (x *OperationRequest) GetExportDistro() *ExportRequest {
+	if x, ok := x.GetRequest().(*OperationRequest_ExportDistro); ok {
+		return x.ExportDistro
+	}
+	return nil
+}
+
+func (x *OperationRequest) GetResetDistro() *ResetRequest {
+	if x, ok := x.GetRequest().(*OperationRequest_ResetDistro); ok {
+		return x.ResetDistro
+	}
+	return nil
+}
+
+type isOperationRequest_Request interface {
+	isOperationRequest_Request()
+}
+
+type OperationRequest_ExportDistro struct {
+	ExportDistro *ExportRequest `protobuf:"bytes,1,opt,name=exportDistro,proto3,oneof"`
+}
+
+type OperationRequest_ResetDistro struct {
+	ResetDistro *ResetRequest `protobuf:"bytes,2,opt,name=resetDistro,proto3,oneof"`
+}
+
+func (*OperationRequest_ExportDistro) isOperationRequest_Request() {}
+
+func (*OperationRequest_ResetDistro) isOperationRequest_Request() {}
+
+type OperationID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *OperationID) Reset() {
+	*x = OperationID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OperationID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationID) ProtoMessage() {}
+
+func (x *OperationID) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationID.ProtoReflect.Descriptor instead.
+func (*OperationID) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *OperationID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Operation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind       string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`             // One of export or reset.
+	DistroName string `protobuf:"bytes,3,opt,name=distroName,proto3" json:"distroName,omitempty"` // The distro the operation acts on.
+	State      string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`           // One of running, succeeded, failed or cancelled.
+	Error      string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`           // Why the operation failed. Empty unless it failed.
+	Progress   uint64 `protobuf:"varint,6,opt,name=progress,proto3" json:"progress,omitempty"`    // The size of the tarball written so far for exports. Zero for the other kinds.
+	Result     string `protobuf:"bytes,7,opt,name=result,proto3" json:"result,omitempty"`         // The path to the tarball for exports. Only set once the operation succeeded.
+	Started    string `protobuf:"bytes,8,opt,name=started,proto3" json:"started,omitempty"`       // When the operation started, in RFC 3339 format.
+	Finished   string `protobuf:"bytes,9,opt,name=finished,proto3" json:"finished,omitempty"`     // When the operation finished, in RFC 3339 format. Empty while it runs.
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *Operation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Operation) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Operation) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *Operation) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Operation) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Operation) GetProgress() uint64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *Operation) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *Operation) GetStarted() string {
+	if x != nil {
+		return x.Started
+	}
+	return ""
+}
+
+func (x *Operation) GetFinished() string {
+	if x != nil {
+		return x.Finished
+	}
+	return ""
+}
+
+type Operations struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Operations []*Operation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"` // Sorted from oldest to newest.
+}
+
+func (x *Operations) Reset() {
+	*x = Operations{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Operations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operations) ProtoMessage() {}
+
+func (x *Operations) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operations.ProtoReflect.Descriptor instead.
+func (*Operations) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *Operations) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+// ConfirmationRequest asks for the code that allows a destructive call, such as ResetDistro or ResetAgent, to go through.
+// The code must be sent in the "x-ubuntu-pro-confirmation" metadata of that call, otherwise it fails with
+// the PERMISSION_DENIED status code.
+type ConfirmationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"` // The name of the call to confirm, such as "ResetDistro".
+}
+
+func (x *ConfirmationRequest) Reset() {
+	*x = ConfirmationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmationRequest) ProtoMessage() {}
+
+func (x *ConfirmationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmationRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmationRequest) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ConfirmationRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+type Confirmation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`       // The code can only be used once, for the call it was requested for.
+	Expires string `protobuf:"bytes,2,opt,name=expires,proto3" json:"expires,omitempty"` // When the code stops being valid, in RFC 3339 format.
+}
+
+func (x *Confirmation) Reset() {
+	*x = Confirmation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Confirmation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Confirmation) ProtoMessage() {}
+
+func (x *Confirmation) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Confirmation.ProtoReflect.Descriptor instead.
+func (*Confirmation) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *Confirmation) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Confirmation) GetExpires() string {
+	if x != nil {
+		return x.Expires
+	}
+	return ""
+}
+
+type DistroWSLSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName  string  `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`   // The distro the settings apply to.
+	DefaultUser string  `protobuf:"bytes,2,opt,name=defaultUser,proto3" json:"defaultUser,omitempty"` // The user WSL logs in as. Empty to leave as is.
+	Interop     *Switch `protobuf:"bytes,3,opt,name=interop,proto3" json:"interop,omitempty"`         // Allow launching Windows executables from the distro. Unset to leave as is.
+	Automount   *Switch `protobuf:"bytes,4,opt,name=automount,proto3" json:"automount,omitempty"`     // Mount the Windows drives in the distro. Unset to leave as is.
+	Systemd     *Switch `protobuf:"bytes,5,opt,name=systemd,proto3" json:"systemd,omitempty"`         // Boot the distro with systemd. Unset to leave as is. The WSL Pro service runs on systemd, so the agent enables it from Windows in the distros that did not connect yet.
+}
+
+func (x *DistroWSLSettings) Reset() {
+	*x = DistroWSLSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroWSLSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroWSLSettings) ProtoMessage() {}
+
+func (x *DistroWSLSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroWSLSettings.ProtoReflect.Descriptor instead.
+func (*DistroWSLSettings) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DistroWSLSettings) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *DistroWSLSettings) GetDefaultUser() string {
+	if x != nil {
+		return x.DefaultUser
+	}
+	return ""
+}
+
+func (x *DistroWSLSettings) GetInterop() *Switch {
+	if x != nil {
+		return x.Interop
+	}
+	return nil
+}
+
+func (x *DistroWSLSettings) GetAutomount() *Switch {
+	if x != nil {
+		return x.Automount
+	}
+	return nil
+}
+
+func (x *DistroWSLSettings) GetSystemd() *Switch {
+	if x != nil {
+		return x.Systemd
+	}
+	return nil
+}
+
+type Switch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+}
+
+func (x *Switch) Reset() {
+	*x = Switch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Switch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Switch) ProtoMessage() {}
+
+func (x *Switch) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Switch.ProtoReflect.Descriptor instead.
+func (*Switch) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Switch) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type PauseState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Paused bool `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"` // No distro is woken up, no task runs and Landscape commands are held back.
+}
+
+func (x *PauseState) Reset() {
+	*x = PauseState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PauseState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseState) ProtoMessage() {}
+
+func (x *PauseState) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseState.ProtoReflect.Descriptor instead.
+func (*PauseState) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PauseState) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type OnboardingState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Step                 string `protobuf:"bytes,1,opt,name=step,proto3" json:"step,omitempty"`                                  // The first step left to complete. One of subscription, landscape, distro or done.
+	SubscriptionSelected bool   `protobuf:"varint,2,opt,name=subscriptionSelected,proto3" json:"subscriptionSelected,omitempty"` // The subscription comes from any source.
+	LandscapeConfigured  bool   `protobuf:"varint,3,opt,name=landscapeConfigured,proto3" json:"landscapeConfigured,omitempty"`   // Landscape is configured from any source.
+	LandscapeSkipped     bool   `protobuf:"varint,4,opt,name=landscapeSkipped,proto3" json:"landscapeSkipped,omitempty"`         // The user chose not to configure Landscape.
+	DistroProvisioned    bool   `protobuf:"varint,5,opt,name=distroProvisioned,proto3" json:"distroProvisioned,omitempty"`       // Any distro connected to the agent.
+}
+
+func (x *OnboardingState) Reset() {
+	*x = OnboardingState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OnboardingState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnboardingState) ProtoMessage() {}
+
+func (x *OnboardingState) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnboardingState.ProtoReflect.Descriptor instead.
+func (*OnboardingState) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *OnboardingState) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *OnboardingState) GetSubscriptionSelected() bool {
+	if x != nil {
+		return x.SubscriptionSelected
+	}
+	return false
+}
+
+func (x *OnboardingState) GetLandscapeConfigured() bool {
+	if x != nil {
+		return x.LandscapeConfigured
+	}
+	return false
+}
+
+func (x *OnboardingState) GetLandscapeSkipped() bool {
+	if x != nil {
+		return x.LandscapeSkipped
+	}
+	return false
+}
+
+func (x *OnboardingState) GetDistroProvisioned() bool {
+	if x != nil {
+		return x.DistroProvisioned
+	}
+	return false
+}
+
+type NotificationPreference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Category string `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"` // One of subscription-expiring, provisioning-failed, distro-adopted or wsl-update.
+	Enabled  bool   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+}
+
+func (x *NotificationPreference) Reset() {
+	*x = NotificationPreference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NotificationPreference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationPreference) ProtoMessage() {}
+
+func (x *NotificationPreference) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationPreference.ProtoReflect.Descriptor instead.
+func (*NotificationPreference) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *NotificationPreference) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *NotificationPreference) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type NotificationPreferences struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Preferences []*NotificationPreference `protobuf:"bytes,1,rep,name=preferences,proto3" json:"preferences,omitempty"`
+}
+
+func (x *NotificationPreferences) Reset() {
+	*x = NotificationPreferences{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NotificationPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationPreferences) ProtoMessage() {}
+
+func (x *NotificationPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationPreferences.ProtoReflect.Descriptor instead.
+func (*NotificationPreferences) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *NotificationPreferences) GetPreferences() []*NotificationPreference {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+type ApprovalPreference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Category string `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`  // One of landscape-install, landscape-uninstall, landscape-set-default, landscape-power or landscape-shutdown-host.
+	Required bool   `protobuf:"varint,2,opt,name=required,proto3" json:"required,omitempty"` // The operations of this category wait for the user to approve them before they run.
+}
+
+func (x *ApprovalPreference) Reset() {
+	*x = ApprovalPreference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApprovalPreference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovalPreference) ProtoMessage() {}
+
+func (x *ApprovalPreference) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovalPreference.ProtoReflect.Descriptor instead.
+func (*ApprovalPreference) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ApprovalPreference) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ApprovalPreference) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+type ApprovalPreferences struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Preferences []*ApprovalPreference `protobuf:"bytes,1,rep,name=preferences,proto3" json:"preferences,omitempty"`
+}
+
+func (x *ApprovalPreferences) Reset() {
+	*x = ApprovalPreferences{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApprovalPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovalPreferences) ProtoMessage() {}
+
+func (x *ApprovalPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovalPreferences.ProtoReflect.Descriptor instead.
+func (*ApprovalPreferences) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ApprovalPreferences) GetPreferences() []*ApprovalPreference {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+// PendingApproval is an operation waiting for the user to approve it. It is denied once it expires.
+type PendingApproval struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Category    string `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"` // What the operation does, for the user to decide.
+	Requested   string `protobuf:"bytes,4,opt,name=requested,proto3" json:"requested,omitempty"`     // When the operation was held back, in RFC 3339 format.
+	Expires     string `protobuf:"bytes,5,opt,name=expires,proto3" json:"expires,omitempty"`         // When the operation is denied if not approved, in RFC 3339 format.
+}
+
+func (x *PendingApproval) Reset() {
+	*x = PendingApproval{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PendingApproval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingApproval) ProtoMessage() {}
+
+func (x *PendingApproval) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingApproval.ProtoReflect.Descriptor instead.
+func (*PendingApproval) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *PendingApproval) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PendingApproval) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *PendingApproval) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PendingApproval) GetRequested() string {
+	if x != nil {
+		return x.Requested
+	}
+	return ""
+}
+
+func (x *PendingApproval) GetExpires() string {
+	if x != nil {
+		return x.Expires
+	}
+	return ""
+}
+
+type PendingApprovals struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Approvals []*PendingApproval `protobuf:"bytes,1,rep,name=approvals,proto3" json:"approvals,omitempty"` // Oldest first.
+}
+
+func (x *PendingApprovals) Reset() {
+	*x = PendingApprovals{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PendingApprovals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingApprovals) ProtoMessage() {}
+
+func (x *PendingApprovals) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingApprovals.ProtoReflect.Descriptor instead.
+func (*PendingApprovals) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *PendingApprovals) GetApprovals() []*PendingApproval {
+	if x != nil {
+		return x.Approvals
+	}
+	return nil
+}
+
+type ApprovalDecision struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // The id of a pending approval.
+}
+
+func (x *ApprovalDecision) Reset() {
+	*x = ApprovalDecision{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApprovalDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovalDecision) ProtoMessage() {}
+
+func (x *ApprovalDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovalDecision.ProtoReflect.Descriptor instead.
+func (*ApprovalDecision) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ApprovalDecision) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// PluginTaskSubmission queues a task of a type declared by a plugin manifest for a distro.
+type PluginTaskSubmission struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`
+	Type       string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`          // The task type, as declared in the plugin manifest.
+	Payload    []byte `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`    // Opaque to the agent: it is passed back to the plugin as is.
+	Deferred   bool   `protobuf:"varint,4,opt,name=deferred,proto3" json:"deferred,omitempty"` // The task waits until the distro is started by other means.
+}
+
+func (x *PluginTaskSubmission) Reset() {
+	*x = PluginTaskSubmission{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginTaskSubmission) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginTaskSubmission) ProtoMessage() {}
+
+func (x *PluginTaskSubmission) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginTaskSubmission.ProtoReflect.Descriptor instead.
+func (*PluginTaskSubmission) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *PluginTaskSubmission) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *PluginTaskSubmission) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PluginTaskSubmission) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *PluginTaskSubmission) GetDeferred() bool {
+	if x != nil {
+		return x.Deferred
+	}
+	return false
+}
+
+type LandscapeDistroOverride struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DistroName    string `protobuf:"bytes,1,opt,name=distroName,proto3" json:"distroName,omitempty"`       // The distro these settings apply to.
+	Tags          string `protobuf:"bytes,2,opt,name=tags,proto3" json:"tags,omitempty"`                   // Comma-separated Landscape tags. Empty to use the global configuration.
+	ComputerTitle string `protobuf:"bytes,3,opt,name=computerTitle,proto3" json:"computerTitle,omitempty"` // The name the distro is registered with. Empty to use the distro name.
+	ScriptUsers   string `protobuf:"bytes,4,opt,name=scriptUsers,proto3" json:"scriptUsers,omitempty"`     // Comma-separated users that can run scripts. Empty to use the global configuration.
+	Endpoint      string `protobuf:"bytes,5,opt,name=endpoint,proto3" json:"endpoint,omitempty"`           // The Landscape endpoint that manages the distro. Empty to use the main one.
+}
+
+func (x *LandscapeDistroOverride) Reset() {
+	*x = LandscapeDistroOverride{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LandscapeDistroOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LandscapeDistroOverride) ProtoMessage() {}
+
+func (x *LandscapeDistroOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LandscapeDistroOverride.ProtoReflect.Descriptor instead.
+func (*LandscapeDistroOverride) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *LandscapeDistroOverride) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *LandscapeDistroOverride) GetTags() string {
+	if x != nil {
+		return x.Tags
+	}
+	return ""
+}
+
+func (x *LandscapeDistroOverride) GetComputerTitle() string {
+	if x != nil {
+		return x.ComputerTitle
+	}
+	return ""
+}
+
+func (x *LandscapeDistroOverride) GetScriptUsers() string {
+	if x != nil {
+		return x.ScriptUsers
+	}
+	return ""
+}
+
+func (x *LandscapeDistroOverride) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+type LandscapeEndpoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`     // The name distros refer to the endpoint with.
+	Config string `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"` // The Landscape config of the endpoint. Empty to remove the endpoint.
+}
+
+func (x *LandscapeEndpoint) Reset() {
+	*x = LandscapeEndpoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LandscapeEndpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LandscapeEndpoint) ProtoMessage() {}
+
+func (x *LandscapeEndpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LandscapeEndpoint.ProtoReflect.Descriptor instead.
+func (*LandscapeEndpoint) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *LandscapeEndpoint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LandscapeEndpoint) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type LandscapeDistroOverrides struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Overrides []*LandscapeDistroOverride `protobuf:"bytes,1,rep,name=overrides,proto3" json:"overrides,omitempty"` // Sorted by distro name.
+}
+
+func (x *LandscapeDistroOverrides) Reset() {
+	*x = LandscapeDistroOverrides{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LandscapeDistroOverrides) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LandscapeDistroOverrides) ProtoMessage() {}
+
+func (x *LandscapeDistroOverrides) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LandscapeDistroOverrides.ProtoReflect.Descriptor instead.
+func (*LandscapeDistroOverrides) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *LandscapeDistroOverrides) GetOverrides() []*LandscapeDistroOverride {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+type SubscriptionInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProductId string `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"` // The ID of the Ubuntu Pro for WSL product on the Microsoft Store.
+	// Types that are assignable to SubscriptionType:
+	//
+	//	*SubscriptionInfo_None
+	//	*SubscriptionInfo_User
+	//	*SubscriptionInfo_Organization
+	//	*SubscriptionInfo_MicrosoftStore
+	SubscriptionType isSubscriptionInfo_SubscriptionType `protobuf_oneof:"subscriptionType"`
+}
+
+func (x *SubscriptionInfo) Reset() {
+	*x = SubscriptionInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscriptionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionInfo) ProtoMessage() {}
+
+func (x *SubscriptionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionInfo.ProtoReflect.Descriptor instead.
+func (*SubscriptionInfo) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *SubscriptionInfo) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (m *SubscriptionInfo) GetSubscriptionType() isSubscriptionInfo_SubscriptionType {
+	if m != nil {
+		return m.SubscriptionType
+	}
+	return nil
+}
+
+func (x *SubscriptionInfo) GetNone() *Empty {
+	if x, ok := x.GetSubscriptionType().(*SubscriptionInfo_None); ok {
+		return x.None
+	}
+	return nil
+}
+
+func (x *SubscriptionInfo) GetUser() *Empty {
+	if x, ok := x.GetSubscriptionType().(*SubscriptionInfo_User); ok {
+		return x.User
+	}
+	return nil
+}
+
+func (x *SubscriptionInfo) GetOrganization() *Empty {
+	if x, ok := x.GetSubscriptionType().(*SubscriptionInfo_Organization); ok {
+		return x.Organization
+	}
+	return nil
+}
+
+func (x *SubscriptionInfo) GetMicrosoftStore() *Empty {
+	if x, ok := x.GetSubscriptionType().(*SubscriptionInfo_MicrosoftStore); ok {
+		return x.MicrosoftStore
+	}
+	return nil
+}
+
+type isSubscriptionInfo_SubscriptionType interface {
+	isSubscriptionInfo_SubscriptionType()
+}
+
+type SubscriptionInfo_None struct {
+	None *Empty `protobuf:"bytes,2,opt,name=none,proto3,oneof"` // There is no active subscription.
+}
+
+type SubscriptionInfo_User struct {
+	User *Empty `protobuf:"bytes,3,opt,name=user,proto3,oneof"` // The subscription is managed by the user with a pro token from the GUI or the registry.
+}
+
+type SubscriptionInfo_Organization struct {
+	Organization *Empty `protobuf:"bytes,4,opt,name=organization,proto3,oneof"` // The subscription is managed by the sysadmin with a pro token from the registry.
+}
+
+type SubscriptionInfo_MicrosoftStore struct {
+	MicrosoftStore *Empty `protobuf:"bytes,5,opt,name=microsoftStore,proto3,oneof"` // The subscription is managed via the Microsoft store.
+}
+
+func (*SubscriptionInfo_None) isSubscriptionInfo_SubscriptionType() {}
+
+func (*SubscriptionInfo_User) isSubscriptionInfo_SubscriptionType() {}
+
+func (*SubscriptionInfo_Organization) isSubscriptionInfo_SubscriptionType() {}
+
+func (*SubscriptionInfo_MicrosoftStore) isSubscriptionInfo_SubscriptionType() {}
+
+type LandscapeSource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to LandscapeSourceType:
+	//
+	//	*LandscapeSource_None
+	//	*LandscapeSource_User
+	//	*LandscapeSource_Organization
+	LandscapeSourceType isLandscapeSource_LandscapeSourceType `protobuf_oneof:"landscapeSourceType"`
+}
+
+func (x *LandscapeSource) Reset() {
+	*x = LandscapeSource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LandscapeSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LandscapeSource) ProtoMessage() {}
+
+func (x *LandscapeSource) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LandscapeSource.ProtoReflect.Descriptor instead.
+func (*LandscapeSource) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{56}
+}
+
+func (m *LandscapeSource) GetLandscapeSourceType() isLandscapeSource_LandscapeSourceType {
+	if m != nil {
+		return m.LandscapeSourceType
+	}
+	return nil
+}
+
+func (x *LandscapeSource) GetNone() *Empty {
+	if x, ok := x.GetLandscapeSourceType().(*LandscapeSource_None); ok {
+		return x.None
+	}
+	return nil
+}
+
+func (x *LandscapeSource) GetUser() *Empty {
+	if x, ok := x.GetLandscapeSourceType().(*LandscapeSource_User); ok {
+		return x.User
+	}
+	return nil
+}
+
+func (x *LandscapeSource) GetOrganization() *Empty {
+	if x, ok := x.GetLandscapeSourceType().(*LandscapeSource_Organization); ok {
+		return x.Organization
+	}
+	return nil
+}
+
+type isLandscapeSource_LandscapeSourceType interface {
+	isLandscapeSource_LandscapeSourceType()
+}
+
+type LandscapeSource_None struct {
+	None *Empty `protobuf:"bytes,1,opt,name=none,proto3,oneof"` // There is no active Landscape config data.
+}
+
+type LandscapeSource_User struct {
+	User *Empty `protobuf:"bytes,2,opt,name=user,proto3,oneof"` // The Landscape config is managed by the user, set via the GUI.
+}
+
+type LandscapeSource_Organization struct {
+	Organization *Empty `protobuf:"bytes,3,opt,name=organization,proto3,oneof"` // The Landscape config is managedby the sysadmin, set via the registry.
+}
+
+func (*LandscapeSource_None) isLandscapeSource_LandscapeSourceType() {}
+
+func (*LandscapeSource_User) isLandscapeSource_LandscapeSourceType() {}
+
+func (*LandscapeSource_Organization) isLandscapeSource_LandscapeSourceType() {}
+
+type ConfigSources struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProSubscription *SubscriptionInfo `protobuf:"bytes,1,opt,name=proSubscription,proto3" json:"proSubscription,omitempty"`
+	LandscapeSource *LandscapeSource  `protobuf:"bytes,2,opt,name=landscapeSource,proto3" json:"landscapeSource,omitempty"`
+}
+
+func (x *ConfigSources) Reset() {
+	*x = ConfigSources{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigSources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigSources) ProtoMessage() {}
+
+func (x *ConfigSources) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigSources.ProtoReflect.Descriptor instead.
+func (*ConfigSources) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ConfigSources) GetProSubscription() *SubscriptionInfo {
+	if x != nil {
+		return x.ProSubscription
+	}
+	return nil
+}
+
+func (x *ConfigSources) GetLandscapeSource() *LandscapeSource {
+	if x != nil {
+		return x.LandscapeSource
+	}
+	return nil
+}
+
+type SubscriptionDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Active           *SubscriptionInfo            `protobuf:"bytes,1,opt,name=active,proto3" json:"active,omitempty"`                      // The subscription currently in use.
+	Sources          []*SubscriptionSourceDetails `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`                    // Every subscription source, sorted from highest to lowest priority.
+	RegistryReadOnly bool                         `protobuf:"varint,3,opt,name=registryReadOnly,proto3" json:"registryReadOnly,omitempty"` // The agent cannot write to the registry.
+}
+
+func (x *SubscriptionDetails) Reset() {
+	*x = SubscriptionDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscriptionDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionDetails) ProtoMessage() {}
+
+func (x *SubscriptionDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionDetails.ProtoReflect.Descriptor instead.
+func (*SubscriptionDetails) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *SubscriptionDetails) GetActive() *SubscriptionInfo {
+	if x != nil {
+		return x.Active
+	}
+	return nil
+}
+
+func (x *SubscriptionDetails) GetSources() []*SubscriptionSourceDetails {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *SubscriptionDetails) GetRegistryReadOnly() bool {
+	if x != nil {
+		return x.RegistryReadOnly
+	}
+	return false
+}
+
+type SubscriptionSourceDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source       *SubscriptionInfo `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`             // The source these details refer to.
+	HasToken     bool              `protobuf:"varint,2,opt,name=hasToken,proto3" json:"hasToken,omitempty"`        // The source provides a pro token.
+	Shadowed     bool              `protobuf:"varint,3,opt,name=shadowed,proto3" json:"shadowed,omitempty"`        // The source provides a pro token, but a higher priority source overrides it.
+	LastModified string            `protobuf:"bytes,4,opt,name=lastModified,proto3" json:"lastModified,omitempty"` // The last time the source changed, in RFC 3339 format. Empty if unknown.
+}
+
+func (x *SubscriptionSourceDetails) Reset() {
+	*x = SubscriptionSourceDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscriptionSourceDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionSourceDetails) ProtoMessage() {}
+
+func (x *SubscriptionSourceDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionSourceDetails.ProtoReflect.Descriptor instead.
+func (*SubscriptionSourceDetails) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SubscriptionSourceDetails) GetSource() *SubscriptionInfo {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *SubscriptionSourceDetails) GetHasToken() bool {
+	if x != nil {
+		return x.HasToken
+	}
+	return false
+}
+
+func (x *SubscriptionSourceDetails) GetShadowed() bool {
+	if x != nil {
+		return x.Shadowed
+	}
+	return false
+}
+
+func (x *SubscriptionSourceDetails) GetLastModified() string {
+	if x != nil {
+		return x.LastModified
+	}
+	return ""
+}
+
+type ConfigProblems struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Problems []*ConfigProblem `protobuf:"bytes,1,rep,name=problems,proto3" json:"problems,omitempty"`
+}
+
+func (x *ConfigProblems) Reset() {
+	*x = ConfigProblems{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigProblems) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigProblems) ProtoMessage() {}
+
+func (x *ConfigProblems) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigProblems.ProtoReflect.Descriptor instead.
+func (*ConfigProblems) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ConfigProblems) GetProblems() []*ConfigProblem {
+	if x != nil {
+		return x.Problems
+	}
+	return nil
+}
+
+type ConfigProblem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IsError bool   `protobuf:"varint,1,opt,name=isError,proto3" json:"isError,omitempty"` // The problem will make some feature fail. Otherwise it is just a warning.
+	Field   string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`      // The name of the offending setting.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`  // A human-readable description of the problem.
+}
+
+func (x *ConfigProblem) Reset() {
+	*x = ConfigProblem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigProblem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigProblem) ProtoMessage() {}
+
+func (x *ConfigProblem) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigProblem.ProtoReflect.Descriptor instead.
+func (*ConfigProblem) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ConfigProblem) GetIsError() bool {
+	if x != nil {
+		return x.IsError
+	}
+	return false
+}
+
+func (x *ConfigProblem) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ConfigProblem) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type FeatureFlags struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Flags []*FeatureFlag `protobuf:"bytes,1,rep,name=flags,proto3" json:"flags,omitempty"`
+}
+
+func (x *FeatureFlags) Reset() {
+	*x = FeatureFlags{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FeatureFlags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeatureFlags) ProtoMessage() {}
+
+func (x *FeatureFlags) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeatureFlags.ProtoReflect.Descriptor instead.
+func (*FeatureFlags) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *FeatureFlags) GetFlags() []*FeatureFlag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+type FeatureFlag struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Enabled      bool   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	IsDefault    bool   `protobuf:"varint,3,opt,name=isDefault,proto3" json:"isDefault,omitempty"`       // Nobody set the flag, so its default value is used.
+	Organization bool   `protobuf:"varint,4,opt,name=organization,proto3" json:"organization,omitempty"` // The flag is set by the sysadmin via the registry.
+}
+
+func (x *FeatureFlag) Reset() {
+	*x = FeatureFlag{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FeatureFlag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeatureFlag) ProtoMessage() {}
+
+func (x *FeatureFlag) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeatureFlag.ProtoReflect.Descriptor instead.
+func (*FeatureFlag) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *FeatureFlag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *FeatureFlag) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *FeatureFlag) GetOrganization() bool {
+	if x != nil {
+		return x.Organization
+	}
+	return false
+}
+
+type DistroInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WslName              string   `protobuf:"bytes,1,opt,name=wsl_name,json=wslName,proto3" json:"wsl_name,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	VersionId            string   `protobuf:"bytes,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	PrettyName           string   `protobuf:"bytes,4,opt,name=pretty_name,json=prettyName,proto3" json:"pretty_name,omitempty"`
+	ProAttached          bool     `protobuf:"varint,5,opt,name=pro_attached,json=proAttached,proto3" json:"pro_attached,omitempty"`
+	Hostname             string   `protobuf:"bytes,6,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	InstanceId           string   `protobuf:"bytes,7,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	ProServices          []string `protobuf:"bytes,8,rep,name=pro_services,json=proServices,proto3" json:"pro_services,omitempty"`
+	LandscapeRegistered  bool     `protobuf:"varint,9,opt,name=landscape_registered,json=landscapeRegistered,proto3" json:"landscape_registered,omitempty"`
+	ServiceVersion       string   `protobuf:"bytes,10,opt,name=service_version,json=serviceVersion,proto3" json:"service_version,omitempty"`
+	BootId               string   `protobuf:"bytes,11,opt,name=boot_id,json=bootId,proto3" json:"boot_id,omitempty"`
+	LandscapeScriptUsers string   `protobuf:"bytes,12,opt,name=landscape_script_users,json=landscapeScriptUsers,proto3" json:"landscape_script_users,omitempty"`
+}
+
+func (x *DistroInfo) Reset() {
+	*x = DistroInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DistroInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistroInfo) ProtoMessage() {}
+
+func (x *DistroInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistroInfo.ProtoReflect.Descriptor instead.
+func (*DistroInfo) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *DistroInfo) GetWslName() string {
+	if x != nil {
+		return x.WslName
+	}
+	return ""
+}
+
+func (x *DistroInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DistroInfo) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *DistroInfo) GetPrettyName() string {
+	if x != nil {
+		return x.PrettyName
+	}
+	return ""
+}
+
+func (x *DistroInfo) GetProAttached() bool {
+	if x != nil {
+		return x.ProAttached
+	}
+	return false
+}
+
+func (x *DistroInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *DistroInfo) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *DistroInfo) GetProServices() []string {
+	if x != nil {
+		return x.ProServices
+	}
+	return nil
+}
+
+func (x *DistroInfo) GetLandscapeRegistered() bool {
+	if x != nil {
+		return x.LandscapeRegistered
+	}
+	return false
+}
+
+func (x *DistroInfo) GetServiceVersion() string {
+	if x != nil {
+		return x.ServiceVersion
+	}
+	return ""
+}
+
+func (x *DistroInfo) GetBootId() string {
+	if x != nil {
+		return x.BootId
+	}
+	return ""
+}
+
+func (x *DistroInfo) GetLandscapeScriptUsers() string {
+	if x != nil {
+		return x.LandscapeScriptUsers
+	}
+	return ""
+}
+
+type Port struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port uint32 `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+}
+
+func (x *Port) Reset() {
+	*x = Port{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Port) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Port) ProtoMessage() {}
+
+func (x *Port) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Port.ProtoReflect.Descriptor instead.
+func (*Port) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *Port) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+type PluginTask struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type       string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	DistroName string `protobuf:"bytes,2,opt,name=distroName,proto3" json:"distroName,omitempty"`
+	Payload    []byte `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (x *PluginTask) Reset() {
+	*x = PluginTask{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginTask) ProtoMessage() {}
+
+func (x *PluginTask) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginTask.ProtoReflect.Descriptor instead.
+func (*PluginTask) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *PluginTask) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PluginTask) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *PluginTask) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type PluginTaskResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`  // Empty if the task succeeded.
+	Retry bool   `protobuf:"varint,2,opt,name=retry,proto3" json:"retry,omitempty"` // The failed task is retried the next time the distro starts, instead of dropped.
+}
+
+func (x *PluginTaskResult) Reset() {
+	*x = PluginTaskResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginTaskResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginTaskResult) ProtoMessage() {}
+
+func (x *PluginTaskResult) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginTaskResult.ProtoReflect.Descriptor instead.
+func (*PluginTaskResult) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *PluginTaskResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PluginTaskResult) GetRetry() bool {
+	if x != nil {
+		return x.Retry
+	}
+	return false
+}
+
+// VersionInfo describes the build of the agent, for compatibility troubleshooting.
+type VersionInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version   string             `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`     // The semantic version.
+	Commit    string             `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`       // The git commit it was built from. Empty if unknown.
+	BuildDate string             `protobuf:"bytes,3,opt,name=buildDate,proto3" json:"buildDate,omitempty"` // When it was built, in RFC 3339 format. Empty if unknown.
+	Protocols []*ProtocolVersion `protobuf:"bytes,4,rep,name=protocols,proto3" json:"protocols,omitempty"` // The versions of the APIs it speaks.
+}
+
+func (x *VersionInfo) Reset() {
+	*x = VersionInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VersionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionInfo) ProtoMessage() {}
+
+func (x *VersionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionInfo.ProtoReflect.Descriptor instead.
+func (*VersionInfo) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *VersionInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetBuildDate() string {
+	if x != nil {
+		return x.BuildDate
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetProtocols() []*ProtocolVersion {
+	if x != nil {
+		return x.Protocols
+	}
+	return nil
+}
+
+type ProtocolVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`        // The name of the API, such as agentapi or wslserviceapi.
+	Version uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // It is increased every time the API changes in a way that breaks compatibility.
+}
+
+func (x *ProtocolVersion) Reset() {
+	*x = ProtocolVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agentapi_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProtocolVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtocolVersion) ProtoMessage() {}
+
+func (x *ProtocolVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_agentapi_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtocolVersion.ProtoReflect.Descriptor instead.
+func (*ProtocolVersion) Descriptor() ([]byte, []int) {
+	return file_agentapi_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ProtocolVersion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProtocolVersion) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_agentapi_proto protoreflect.FileDescriptor
+
+var file_agentapi_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x08, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x25, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x46, 0x0a, 0x0e, 0x44, 0x69,
+	0x73, 0x74, 0x72, 0x6f, 0x50, 0x72, 0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1e, 0x0a, 0x0a,
+	0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x22, 0x29, 0x0a, 0x0f, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0xd1, 0x02,
+	0x0a, 0x0b, 0x46, 0x6c, 0x65, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x30, 0x0a,
+	0x07, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x07, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x73, 0x12,
+	0x37, 0x0a, 0x0b, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x6d, 0x61, 0x6e,
+	0x61, 0x67, 0x65, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x65, 0x63, 0x75,
+	0x72, 0x69, 0x74, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0f, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x73, 0x6d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x65, 0x73, 0x6d, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x12, 0x48, 0x0a, 0x11, 0x70, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x50,
+	0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67,
+	0x68, 0x74, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x52, 0x11, 0x70, 0x72, 0x65, 0x66, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x73, 0x12, 0x43, 0x0a, 0x0f,
+	0x77, 0x73, 0x6c, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x57, 0x53, 0x4c, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x52, 0x0f, 0x77, 0x73, 0x6c, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x22, 0x5b, 0x0a, 0x0f, 0x57, 0x53, 0x4c, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62,
+	0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x96,
+	0x01, 0x0a, 0x10, 0x50, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x50, 0x72, 0x6f, 0x62,
+	0x6c, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14, 0x0a,
+	0x05, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x7b, 0x0a, 0x0b, 0x4d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x79, 0x52, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x10, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x52, 0x65, 0x61, 0x64, 0x4f, 0x6e,
+	0x6c, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63,
+	0x61, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6c, 0x61, 0x6e, 0x64, 0x73,
+	0x63, 0x61, 0x70, 0x65, 0x22, 0xab, 0x07, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f,
+	0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x70, 0x72, 0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x70,
+	0x72, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0b, 0x70, 0x72, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x20, 0x0a,
+	0x0b, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x12,
+	0x22, 0x0a, 0x0c, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x61,
+	0x73, 0x6b, 0x73, 0x12, 0x30, 0x0a, 0x13, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x13, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x73, 0x65,
+	0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1e, 0x0a,
+	0x0a, 0x65, 0x73, 0x6d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0a, 0x65, 0x73, 0x6d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x28, 0x0a,
+	0x0f, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x70, 0x75,
+	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x63,
+	0x70, 0x75, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x73,
+	0x6b, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x64, 0x69,
+	0x73, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x73, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x10, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x6e, 0x65, 0x65, 0x64, 0x73, 0x41, 0x74, 0x74, 0x65,
+	0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6e, 0x65, 0x65,
+	0x64, 0x73, 0x41, 0x74, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x6c,
+	0x61, 0x73, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x65, 0x78, 0x74, 0x52, 0x65, 0x74, 0x72, 0x79, 0x18, 0x11,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x65, 0x78, 0x74, 0x52, 0x65, 0x74, 0x72, 0x79, 0x12,
+	0x26, 0x0a, 0x0e, 0x72, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65,
+	0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x72, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x62, 0x6f, 0x6f,
+	0x74, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0e, 0x72, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12,
+	0x26, 0x0a, 0x0e, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x61, 0x77, 0x61, 0x6b, 0x65,
+	0x4f, 0x6e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x15, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d,
+	0x61, 0x77, 0x61, 0x6b, 0x65, 0x4f, 0x6e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1e, 0x0a,
+	0x0a, 0x61, 0x77, 0x61, 0x6b, 0x65, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x16, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x61, 0x77, 0x61, 0x6b, 0x65, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x27, 0x0a,
+	0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x17, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x06,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x32,
+	0x0a, 0x14, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x6c, 0x61,
+	0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x73, 0x22, 0x46, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x22, 0x51, 0x0a, 0x0d, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64,
+	0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64,
+	0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x5c, 0x0a,
+	0x0e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x22, 0x0a, 0x0c, 0x62, 0x79, 0x74, 0x65, 0x73, 0x57, 0x72, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x62, 0x79, 0x74, 0x65, 0x73, 0x57, 0x72, 0x69, 0x74,
+	0x74, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x6c, 0x0a, 0x0e, 0x42,
+	0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x24, 0x0a,
+	0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x48, 0x6f, 0x75, 0x72, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x48, 0x6f,
+	0x75, 0x72, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x65, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x6b, 0x65, 0x65, 0x70, 0x22, 0x59, 0x0a, 0x0b, 0x57, 0x61, 0x6b,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74,
+	0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69,
+	0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x6b, 0x65, 0x65, 0x70,
+	0x41, 0x77, 0x61, 0x6b, 0x65, 0x4d, 0x69, 0x6e, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x10, 0x6b, 0x65, 0x65, 0x70, 0x41, 0x77, 0x61, 0x6b, 0x65, 0x4d, 0x69, 0x6e,
+	0x75, 0x74, 0x65, 0x73, 0x22, 0x2d, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e,
+	0x61, 0x6d, 0x65, 0x22, 0x57, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x22, 0x2f, 0x0a, 0x05,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x30, 0x0a,
+	0x0e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x22,
+	0x4d, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x22, 0x47,
+	0x0a, 0x0d, 0x53, 0x70, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x70, 0x61, 0x72, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x73, 0x70, 0x61, 0x72, 0x73, 0x65, 0x22, 0x37, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x70, 0x61,
+	0x63, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x48, 0x6f, 0x75, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x48, 0x6f, 0x75, 0x72, 0x73,
+	0x22, 0x95, 0x01, 0x0a, 0x09, 0x57, 0x53, 0x4c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16,
+	0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x28,
+	0x0a, 0x0f, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x6e, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x65,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74,
+	0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69,
+	0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x6b, 0x65, 0x65, 0x70,
+	0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x6b, 0x65, 0x65, 0x70, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x6f, 0x74, 0x66, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x72, 0x6f, 0x6f, 0x74, 0x66, 0x73, 0x22, 0x45, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74,
+	0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x22,
+	0x65, 0x0a, 0x0f, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69,
+	0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0f, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x49, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f,
+	0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64,
+	0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x69, 0x6e, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6e, 0x65,
+	0x73, 0x22, 0x3a, 0x0a, 0x0a, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4c, 0x6f, 0x67, 0x73, 0x12,
+	0x2c, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x6f, 0x67, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x54, 0x0a,
+	0x08, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x69, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x46, 0x69, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74,
+	0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69,
+	0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x20, 0x0a, 0x0b,
+	0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x76,
+	0x0a, 0x0a, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x64, 0x61, 0x63, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x64, 0x61, 0x63, 0x74, 0x65, 0x64, 0x22, 0x34, 0x0a, 0x12, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f,
+	0x54, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a,
+	0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x39, 0x0a, 0x0b,
+	0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x2a, 0x0a, 0x05, 0x74,
+	0x61, 0x73, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x64, 0x54, 0x61, 0x73, 0x6b,
+	0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x22, 0x86, 0x01, 0x0a, 0x0a, 0x51, 0x75, 0x65, 0x75,
+	0x65, 0x64, 0x54, 0x61, 0x73, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65,
+	0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x65,
+	0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73,
+	0x22, 0x43, 0x0a, 0x11, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72,
+	0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x98, 0x01, 0x0a, 0x10, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x65, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0c, 0x65, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x12, 0x3a, 0x0a, 0x0b, 0x72, 0x65, 0x73,
+	0x65, 0x74, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x65, 0x74, 0x44,
+	0x69, 0x73, 0x74, 0x72, 0x6f, 0x42, 0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x1d, 0x0a, 0x0b, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0xe5, 0x01, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x66,
+	0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66,
+	0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x22, 0x41, 0x0a, 0x0a, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x33, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x2d, 0x0a, 0x13, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x22, 0x3c, 0x0a, 0x0c, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x22, 0xdd, 0x01, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x74,
+	0x72, 0x6f, 0x57, 0x53, 0x4c, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a,
+	0x0b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x55, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12,
+	0x2a, 0x0a, 0x07, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6f, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x77, 0x69, 0x74,
+	0x63, 0x68, 0x52, 0x07, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6f, 0x70, 0x12, 0x2e, 0x0a, 0x09, 0x61,
+	0x75, 0x74, 0x6f, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68,
+	0x52, 0x09, 0x61, 0x75, 0x74, 0x6f, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x07, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x52, 0x07,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x22, 0x22, 0x0a, 0x06, 0x53, 0x77, 0x69, 0x74, 0x63,
+	0x68, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22, 0x24, 0x0a, 0x0a, 0x50,
+	0x61, 0x75, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x75,
+	0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65,
+	0x64, 0x22, 0xe5, 0x01, 0x0a, 0x0f, 0x4f, 0x6e, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x12, 0x32, 0x0a, 0x14, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x30, 0x0a,
+	0x13, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x6c, 0x61, 0x6e, 0x64,
+	0x73, 0x63, 0x61, 0x70, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x64, 0x12,
+	0x2a, 0x0a, 0x10, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x6b, 0x69, 0x70,
+	0x70, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x6c, 0x61, 0x6e, 0x64, 0x73,
+	0x63, 0x61, 0x70, 0x65, 0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x12, 0x2c, 0x0a, 0x11, 0x64,
+	0x69, 0x73, 0x74, 0x72, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x22, 0x4e, 0x0a, 0x16, 0x4e, 0x6f, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22, 0x5d, 0x0a, 0x17, 0x4e, 0x6f, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x73, 0x12, 0x42, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0b, 0x70, 0x72, 0x65,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x4c, 0x0a, 0x12, 0x41, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22, 0x55, 0x0a, 0x13, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76,
+	0x61, 0x6c, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x3e, 0x0a,
+	0x0b, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x70,
+	0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65,
+	0x52, 0x0b, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x97, 0x01,
+	0x0a, 0x0f, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61,
+	0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x20, 0x0a,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x22, 0x4b, 0x0a, 0x10, 0x50, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x12, 0x37, 0x0a, 0x09, 0x61,
+	0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x52, 0x09, 0x61, 0x70, 0x70, 0x72, 0x6f,
+	0x76, 0x61, 0x6c, 0x73, 0x22, 0x22, 0x0a, 0x10, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c,
+	0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x80, 0x01, 0x0a, 0x14, 0x50, 0x6c, 0x75,
+	0x67, 0x69, 0x6e, 0x54, 0x61, 0x73, 0x6b, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x64, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x64, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x22, 0xb1, 0x01, 0x0a, 0x17,
+	0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4f,
+	0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72,
+	0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73,
+	0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x63,
+	0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x54, 0x69, 0x74, 0x6c,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x22,
+	0x3f, 0x0a, 0x11, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x45, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x22, 0x5b, 0x0a, 0x18, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x44, 0x69, 0x73,
+	0x74, 0x72, 0x6f, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x3f, 0x0a, 0x09,
+	0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x73,
+	0x63, 0x61, 0x70, 0x65, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x65, 0x52, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x22, 0x84, 0x02,
+	0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64,
+	0x12, 0x25, 0x0a, 0x04, 0x6e, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48,
+	0x00, 0x52, 0x04, 0x6e, 0x6f, 0x6e, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x35,
+	0x0a, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0e, 0x6d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x6f,
+	0x66, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00,
+	0x52, 0x0e, 0x6d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x6f, 0x66, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x42, 0x12, 0x0a, 0x10, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x79, 0x70, 0x65, 0x22, 0xad, 0x01, 0x0a, 0x0f, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61,
+	0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x6e, 0x6f, 0x6e, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x6f, 0x6e, 0x65, 0x12,
+	0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69,
+	0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52,
+	0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x15, 0x0a,
+	0x13, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x22, 0x9a, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x53, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0f, 0x70, 0x72, 0x6f,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x43, 0x0a, 0x0f,
+	0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x0f, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x22, 0xb4, 0x01, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x3d, 0x0a,
+	0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x73, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x10,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x52, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79,
+	0x52, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x22, 0xab, 0x01, 0x0a, 0x19, 0x53, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x44,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x61,
+	0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x68, 0x61,
+	0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x64, 0x6f, 0x77,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x68, 0x61, 0x64, 0x6f, 0x77,
+	0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x69,
+	0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x4d, 0x6f,
+	0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x22, 0x45, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x73, 0x12, 0x33, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x62,
+	0x6c, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x62,
+	0x6c, 0x65, 0x6d, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x73, 0x22, 0x59, 0x0a,
+	0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x12, 0x18,
+	0x0a, 0x07, 0x69, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x69, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3b, 0x0a, 0x0c, 0x46, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x05,
+	0x66, 0x6c, 0x61, 0x67, 0x73, 0x22, 0x7d, 0x0a, 0x0b, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x46, 0x6c, 0x61, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x12, 0x22, 0x0a, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0xa5, 0x03, 0x0a, 0x0a, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x19, 0x0a, 0x08, 0x77, 0x73, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x77, 0x73, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a,
+	0x0b, 0x70, 0x72, 0x65, 0x74, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x74, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21,
+	0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x5f, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x21,
+	0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x12, 0x31, 0x0a, 0x14, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x5f, 0x72,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x13, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a,
+	0x07, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x62, 0x6f, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x16, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63,
+	0x61, 0x70, 0x65, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x73,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x6c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70,
+	0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x22, 0x1a, 0x0a, 0x04,
+	0x50, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x5a, 0x0a, 0x0a, 0x50, 0x6c, 0x75, 0x67,
+	0x69, 0x6e, 0x54, 0x61, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69,
+	0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x22, 0x3e, 0x0a, 0x10, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x54, 0x61,
+	0x73, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14,
+	0x0a, 0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x72,
+	0x65, 0x74, 0x72, 0x79, 0x22, 0x96, 0x01, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x44,
+	0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x44, 0x61, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x22, 0x3f, 0x0a,
+	0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x32, 0xc6,
+	0x1b, 0x0a, 0x02, 0x55, 0x49, 0x12, 0x46, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x50, 0x72,
+	0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x17, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x50, 0x72, 0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x66, 0x6f, 0x1a,
+	0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x42, 0x0a,
+	0x13, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x50, 0x72, 0x6f, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x50, 0x72, 0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x1a, 0x0f,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x12, 0x4e, 0x0a, 0x14, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63,
+	0x61, 0x70, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x1a, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22,
+	0x00, 0x12, 0x2a, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3e, 0x0a,
+	0x10, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x73, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x17, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0x00, 0x12, 0x3f, 0x0a,
+	0x0e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x12,
+	0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x4a,
+	0x0a, 0x16, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1d, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x0e, 0x56, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x0f, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x18, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50,
+	0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x73, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x0f, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x46, 0x6c, 0x61, 0x67, 0x73, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x1c, 0x41, 0x70, 0x70, 0x6c, 0x79,
+	0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4f,
+	0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x12, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x44, 0x69, 0x73, 0x74,
+	0x72, 0x6f, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x54, 0x0a,
+	0x1b, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x44, 0x69, 0x73,
+	0x74, 0x72, 0x6f, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x0f, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x22, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61,
+	0x70, 0x65, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65,
+	0x73, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x46, 0x6c, 0x65, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x46, 0x6c, 0x65, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12,
+	0x3b, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x73, 0x12,
+	0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x16, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x73, 0x74,
+	0x72, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x45, 0x0a, 0x0c,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x12, 0x17, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x40, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x18, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x22, 0x00, 0x12, 0x38, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x65, 0x74,
+	0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x12, 0x16, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x12, 0x30, 0x0a, 0x0a, 0x52, 0x65, 0x73, 0x65, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12,
+	0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x12, 0x48,
+	0x0a, 0x16, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x57, 0x53, 0x4c,
+	0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1b, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x57, 0x53, 0x4c, 0x53, 0x65, 0x74,
+	0x74, 0x69, 0x6e, 0x67, 0x73, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x38, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x50,
+	0x61, 0x75, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x1a,
+	0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x22, 0x00, 0x12, 0x38, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x50, 0x61, 0x75, 0x73, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x50, 0x61, 0x75, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x16,
+	0x41, 0x70, 0x70, 0x6c, 0x79, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x45, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1b, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x19, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50,
+	0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x15,
+	0x53, 0x65, 0x74, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x41, 0x70, 0x70,
+	0x72, 0x6f, 0x76, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73,
+	0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x1d, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x70, 0x70,
+	0x72, 0x6f, 0x76, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73,
+	0x22, 0x00, 0x12, 0x44, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x41, 0x70, 0x70,
+	0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x54, 0x61, 0x73, 0x6b, 0x12, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x44, 0x65, 0x63, 0x69, 0x73,
+	0x69, 0x6f, 0x6e, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x08, 0x44, 0x65, 0x6e, 0x79, 0x54, 0x61,
+	0x73, 0x6b, 0x12, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x70,
+	0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x0f,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x12, 0x45, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x54, 0x61, 0x73, 0x6b, 0x12, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x54, 0x61, 0x73, 0x6b, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x70,
+	0x61, 0x63, 0x74, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x12, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43,
+	0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x12, 0x36,
+	0x0a, 0x0a, 0x57, 0x61, 0x6b, 0x65, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x12, 0x15, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x70, 0x44, 0x69,
+	0x73, 0x74, 0x72, 0x6f, 0x12, 0x15, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3c,
+	0x0a, 0x0f, 0x53, 0x65, 0x74, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x12, 0x16, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x73,
+	0x74, 0x72, 0x6f, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x0f,
+	0x53, 0x65, 0x74, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x53, 0x70, 0x61, 0x72, 0x73, 0x65, 0x12,
+	0x17, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x12, 0x53,
+	0x65, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x12, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6d,
+	0x70, 0x61, 0x63, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x1a, 0x0f, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12,
+	0x42, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x57, 0x53, 0x4c, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x57, 0x53, 0x4c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x0c, 0x53,
+	0x65, 0x74, 0x57, 0x53, 0x4c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x13, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x53, 0x4c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x1a, 0x13, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x57, 0x53, 0x4c, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x57, 0x53, 0x4c, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x3c,
+	0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x15,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x44, 0x1a, 0x13, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0f,
+	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x15, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x1a, 0x13, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x39, 0x0a,
+	0x0e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x13, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1d, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72,
+	0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x44,
+	0x69, 0x73, 0x74, 0x72, 0x6f, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x1b, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4c, 0x6f, 0x67, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4c, 0x6f, 0x67, 0x73, 0x22, 0x00, 0x12, 0x45,
+	0x0a, 0x0e, 0x50, 0x75, 0x6c, 0x6c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x1b, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x73, 0x74,
+	0x72, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x46,
+	0x69, 0x6c, 0x65, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73, 0x74,
+	0x72, 0x6f, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x1c, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x22, 0x00, 0x12, 0x42,
+	0x0a, 0x10, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x54, 0x61,
+	0x73, 0x6b, 0x12, 0x1b, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x22, 0x00, 0x12, 0x42, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x4f, 0x6e, 0x62, 0x6f, 0x61, 0x72, 0x64,
+	0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x6e, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x17, 0x53, 0x6b, 0x69, 0x70, 0x4c, 0x61,
+	0x6e, 0x64, 0x73, 0x63, 0x61, 0x70, 0x65, 0x4f, 0x6e, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x69, 0x6e,
+	0x67, 0x12, 0x0f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4f, 0x6e,
+	0x62, 0x6f, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x00, 0x12,
+	0x36, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0f, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x32, 0x46, 0x0a, 0x0b, 0x57, 0x53, 0x4c, 0x49, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x12, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x44,
+	0x69, 0x73, 0x74, 0x72, 0x6f, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x0e, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x32,
+	0x4f, 0x0a, 0x0a, 0x54, 0x61, 0x73, 0x6b, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x41, 0x0a,
+	0x0b, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x54, 0x61, 0x73, 0x6b, 0x12, 0x14, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x54, 0x61,
+	0x73, 0x6b, 0x1a, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00,
+	0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61, 0x6c, 0x2f, 0x75, 0x62, 0x75, 0x6e, 0x74, 0x75, 0x2d,
+	0x70, 0x72, 0x6f, 0x2d, 0x66, 0x6f, 0x72, 0x2d, 0x77, 0x73, 0x6c, 0x2f, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_agentapi_proto_rawDescOnce sync.Once
+	file_agentapi_proto_rawDescData = file_agentapi_proto_rawDesc
+)
+
+func file_agentapi_proto_rawDescGZIP() []byte {
+	file_agentapi_proto_rawDescOnce.Do(func() {
+		file_agentapi_proto_rawDescData = protoimpl.X.CompressGZIP(file_agentapi_proto_rawDescData)
+	})
+	return file_agentapi_proto_rawDescData
+}
+
+var file_agentapi_proto_msgTypes = make([]protoimpl.MessageInfo, 70)
+var file_agentapi_proto_goTypes = []interface{}{
+	(*Empty)(nil),                     // 0: agentapi.Empty
+	(*ProAttachInfo)(nil),             // 1: agentapi.ProAttachInfo
+	(*DistroProToken)(nil),            // 2: agentapi.DistroProToken
+	(*LandscapeConfig)(nil),           // 3: agentapi.LandscapeConfig
+	(*FleetStatus)(nil),               // 4: agentapi.FleetStatus
+	(*WSLAvailability)(nil),           // 5: agentapi.WSLAvailability
+	(*PreflightProblem)(nil),          // 6: agentapi.PreflightProblem
+	(*ManagedMode)(nil),               // 7: agentapi.ManagedMode
+	(*DistroStatus)(nil),              // 8: agentapi.DistroStatus
+	(*DistroChange)(nil),              // 9: agentapi.DistroChange
+	(*ExportRequest)(nil),             // 10: agentapi.ExportRequest
+	(*ExportProgress)(nil),            // 11: agentapi.ExportProgress
+	(*BackupSchedule)(nil),            // 12: agentapi.BackupSchedule
+	(*WakeRequest)(nil),               // 13: agentapi.WakeRequest
+	(*StopRequest)(nil),               // 14: agentapi.StopRequest
+	(*DistroLabels)(nil),              // 15: agentapi.DistroLabels
+	(*Label)(nil),                     // 16: agentapi.Label
+	(*CompactRequest)(nil),            // 17: agentapi.CompactRequest
+	(*CompactResult)(nil),             // 18: agentapi.CompactResult
+	(*SparseRequest)(nil),             // 19: agentapi.SparseRequest
+	(*CompactSchedule)(nil),           // 20: agentapi.CompactSchedule
+	(*WSLConfig)(nil),                 // 21: agentapi.WSLConfig
+	(*ResetRequest)(nil),              // 22: agentapi.ResetRequest
+	(*ReloadResult)(nil),              // 23: agentapi.ReloadResult
+	(*ReloadedSetting)(nil),           // 24: agentapi.ReloadedSetting
+	(*DistroLogsRequest)(nil),         // 25: agentapi.DistroLogsRequest
+	(*DistroLogs)(nil),                // 26: agentapi.DistroLogs
+	(*LogEntry)(nil),                  // 27: agentapi.LogEntry
+	(*DistroFileRequest)(nil),         // 28: agentapi.DistroFileRequest
+	(*DistroFile)(nil),                // 29: agentapi.DistroFile
+	(*DistroTasksRequest)(nil),        // 30: agentapi.DistroTasksRequest
+	(*DistroTasks)(nil),               // 31: agentapi.DistroTasks
+	(*QueuedTask)(nil),                // 32: agentapi.QueuedTask
+	(*CancelTaskRequest)(nil),         // 33: agentapi.CancelTaskRequest
+	(*OperationRequest)(nil),          // 34: agentapi.OperationRequest
+	(*OperationID)(nil),               // 35: agentapi.OperationID
+	(*Operation)(nil),                 // 36: agentapi.Operation
+	(*Operations)(nil),                // 37: agentapi.Operations
+	(*ConfirmationRequest)(nil),       // 38: agentapi.ConfirmationRequest
+	(*Confirmation)(nil),              // 39: agentapi.Confirmation
+	(*DistroWSLSettings)(nil),         // 40: agentapi.DistroWSLSettings
+	(*Switch)(nil),                    // 41: agentapi.Switch
+	(*PauseState)(nil),                // 42: agentapi.PauseState
+	(*OnboardingState)(nil),           // 43: agentapi.OnboardingState
+	(*NotificationPreference)(nil),    // 44: agentapi.NotificationPreference
+	(*NotificationPreferences)(nil),   // 45: agentapi.NotificationPreferences
+	(*ApprovalPreference)(nil),        // 46: agentapi.ApprovalPreference
+	(*ApprovalPreferences)(nil),       // 47: agentapi.ApprovalPreferences
+	(*PendingApproval)(nil),           // 48: agentapi.PendingApproval
+	(*PendingApprovals)(nil),          // 49: agentapi.PendingApprovals
+	(*ApprovalDecision)(nil),          // 50: agentapi.ApprovalDecision
+	(*PluginTaskSubmission)(nil),      // 51: agentapi.PluginTaskSubmission
+	(*LandscapeDistroOverride)(nil),   // 52: agentapi.LandscapeDistroOverride
+	(*LandscapeEndpoint)(nil),         // 53: agentapi.LandscapeEndpoint
+	(*LandscapeDistroOverrides)(nil),  // 54: agentapi.LandscapeDistroOverrides
+	(*SubscriptionInfo)(nil),          // 55: agentapi.SubscriptionInfo
+	(*LandscapeSource)(nil),           // 56: agentapi.LandscapeSource
+	(*ConfigSources)(nil),             // 57: agentapi.ConfigSources
+	(*SubscriptionDetails)(nil),       // 58: agentapi.SubscriptionDetails
+	(*SubscriptionSourceDetails)(nil), // 59: agentapi.SubscriptionSourceDetails
+	(*ConfigProblems)(nil),            // 60: agentapi.ConfigProblems
+	(*ConfigProblem)(nil),             // 61: agentapi.ConfigProblem
+	(*FeatureFlags)(nil),              // 62: agentapi.FeatureFlags
+	(*FeatureFlag)(nil),               // 63: agentapi.FeatureFlag
+	(*DistroInfo)(nil),                // 64: agentapi.DistroInfo
+	(*Port)(nil),                      // 65: agentapi.Port
+	(*PluginTask)(nil),                // 66: agentapi.PluginTask
+	(*PluginTaskResult)(nil),          // 67: agentapi.PluginTaskResult
+	(*VersionInfo)(nil),               // 68: agentapi.VersionInfo
+	(*ProtocolVersion)(nil),           // 69: agentapi.ProtocolVersion
+}
+var file_agentapi_proto_depIdxs = []int32{
+	8,  // 0: agentapi.FleetStatus.distros:type_name -> agentapi.DistroStatus
+	7,  // 1: agentapi.FleetStatus.managedMode:type_name -> agentapi.ManagedMode
+	6,  // 2: agentapi.FleetStatus.preflightProblems:type_name -> agentapi.PreflightProblem
+	5,  // 3: agentapi.FleetStatus.wslAvailability:type_name -> agentapi.WSLAvailability
+	16, // 4: agentapi.DistroStatus.labels:type_name -> agentapi.Label
+	16, // 5: agentapi.DistroLabels.labels:type_name -> agentapi.Label
+	24, // 6: agentapi.ReloadResult.settings:type_name -> agentapi.ReloadedSetting
+	27, // 7: agentapi.DistroLogs.entries:type_name -> agentapi.LogEntry
+	32, // 8: agentapi.DistroTasks.tasks:type_name -> agentapi.QueuedTask
+	10, // 9: agentapi.OperationRequest.exportDistro:type_name -> agentapi.ExportRequest
+	22, // 10: agentapi.OperationRequest.resetDistro:type_name -> agentapi.ResetRequest
+	36, // 11: agentapi.Operations.operations:type_name -> agentapi.Operation
+	41, // 12: agentapi.DistroWSLSettings.interop:type_name -> agentapi.Switch
+	41, // 13: agentapi.DistroWSLSettings.automount:type_name -> agentapi.Switch
+	41, // 14: agentapi.DistroWSLSettings.systemd:type_name -> agentapi.Switch
+	44, // 15: agentapi.NotificationPreferences.preferences:type_name -> agentapi.NotificationPreference
+	46, // 16: agentapi.ApprovalPreferences.preferences:type_name -> agentapi.ApprovalPreference
+	48, // 17: agentapi.PendingApprovals.approvals:type_name -> agentapi.PendingApproval
+	52, // 18: agentapi.LandscapeDistroOverrides.overrides:type_name -> agentapi.LandscapeDistroOverride
+	0,  // 19: agentapi.SubscriptionInfo.none:type_name -> agentapi.Empty
+	0,  // 20: agentapi.SubscriptionInfo.user:type_name -> agentapi.Empty
+	0,  // 21: agentapi.SubscriptionInfo.organization:type_name -> agentapi.Empty
+	0,  // 22: agentapi.SubscriptionInfo.microsoftStore:type_name -> agentapi.Empty
+	0,  // 23: agentapi.LandscapeSource.none:type_name -> agentapi.Empty
+	0,  // 24: agentapi.LandscapeSource.user:type_name -> agentapi.Empty
+	0,  // 25: agentapi.LandscapeSource.organization:type_name -> agentapi.Empty
+	55, // 26: agentapi.ConfigSources.proSubscription:type_name -> agentapi.SubscriptionInfo
+	56, // 27: agentapi.ConfigSources.landscapeSource:type_name -> agentapi.LandscapeSource
+	55, // 28: agentapi.SubscriptionDetails.active:type_name -> agentapi.SubscriptionInfo
+	59, // 29: agentapi.SubscriptionDetails.sources:type_name -> agentapi.SubscriptionSourceDetails
+	55, // 30: agentapi.SubscriptionSourceDetails.source:type_name -> agentapi.SubscriptionInfo
+	61, // 31: agentapi.ConfigProblems.problems:type_name -> agentapi.ConfigProblem
+	63, // 32: agentapi.FeatureFlags.flags:type_name -> agentapi.FeatureFlag
+	69, // 33: agentapi.VersionInfo.protocols:type_name -> agentapi.ProtocolVersion
+	1,  // 34: agentapi.UI.ApplyProToken:input_type -> agentapi.ProAttachInfo
+	2,  // 35: agentapi.UI.ApplyDistroProToken:input_type -> agentapi.DistroProToken
+	3,  // 36: agentapi.UI.ApplyLandscapeConfig:input_type -> agentapi.LandscapeConfig
+	0,  // 37: agentapi.UI.Ping:input_type -> agentapi.Empty
+	0,  // 38: agentapi.UI.GetConfigSources:input_type -> agentapi.Empty
+	0,  // 39: agentapi.UI.NotifyPurchase:input_type -> agentapi.Empty
+	0,  // 40: agentapi.UI.GetSubscriptionDetails:input_type -> agentapi.Empty
+	0,  // 41: agentapi.UI.ValidateConfig:input_type -> agentapi.Empty
+	0,  // 42: agentapi.UI.GetFeatureFlags:input_type -> agentapi.Empty
+	52, // 43: agentapi.UI.ApplyLandscapeDistroOverride:input_type -> agentapi.LandscapeDistroOverride
+	0,  // 44: agentapi.UI.GetLandscapeDistroOverrides:input_type -> agentapi.Empty
+	0,  // 45: agentapi.UI.GetFleetStatus:input_type -> agentapi.Empty
+	0,  // 46: agentapi.UI.WatchDistros:input_type -> agentapi.Empty
+	10, // 47: agentapi.UI.ExportDistro:input_type -> agentapi.ExportRequest
+	12, // 48: agentapi.UI.SetBackupSchedule:input_type -> agentapi.BackupSchedule
+	0,  // 49: agentapi.UI.GetBackupSchedule:input_type -> agentapi.Empty
+	22, // 50: agentapi.UI.ResetDistro:input_type -> agentapi.ResetRequest
+	0,  // 51: agentapi.UI.ResetAgent:input_type -> agentapi.Empty
+	0,  // 52: agentapi.UI.ReloadConfig:input_type -> agentapi.Empty
+	40, // 53: agentapi.UI.ApplyDistroWSLSettings:input_type -> agentapi.DistroWSLSettings
+	42, // 54: agentapi.UI.SetPauseState:input_type -> agentapi.PauseState
+	0,  // 55: agentapi.UI.GetPauseState:input_type -> agentapi.Empty
+	53, // 56: agentapi.UI.ApplyLandscapeEndpoint:input_type -> agentapi.LandscapeEndpoint
+	44, // 57: agentapi.UI.SetNotificationPreference:input_type -> agentapi.NotificationPreference
+	0,  // 58: agentapi.UI.GetNotificationPreferences:input_type -> agentapi.Empty
+	46, // 59: agentapi.UI.SetApprovalPreference:input_type -> agentapi.ApprovalPreference
+	0,  // 60: agentapi.UI.GetApprovalPreferences:input_type -> agentapi.Empty
+	0,  // 61: agentapi.UI.GetPendingApprovals:input_type -> agentapi.Empty
+	50, // 62: agentapi.UI.ApproveTask:input_type -> agentapi.ApprovalDecision
+	50, // 63: agentapi.UI.DenyTask:input_type -> agentapi.ApprovalDecision
+	51, // 64: agentapi.UI.SubmitPluginTask:input_type -> agentapi.PluginTaskSubmission
+	17, // 65: agentapi.UI.CompactDistro:input_type -> agentapi.CompactRequest
+	13, // 66: agentapi.UI.WakeDistro:input_type -> agentapi.WakeRequest
+	14, // 67: agentapi.UI.StopDistro:input_type -> agentapi.StopRequest
+	15, // 68: agentapi.UI.SetDistroLabels:input_type -> agentapi.DistroLabels
+	19, // 69: agentapi.UI.SetDistroSparse:input_type -> agentapi.SparseRequest
+	20, // 70: agentapi.UI.SetCompactSchedule:input_type -> agentapi.CompactSchedule
+	0,  // 71: agentapi.UI.GetCompactSchedule:input_type -> agentapi.Empty
+	0,  // 72: agentapi.UI.GetWSLConfig:input_type -> agentapi.Empty
+	21, // 73: agentapi.UI.SetWSLConfig:input_type -> agentapi.WSLConfig
+	0,  // 74: agentapi.UI.UpdateWSL:input_type -> agentapi.Empty
+	34, // 75: agentapi.UI.CreateOperation:input_type -> agentapi.OperationRequest
+	35, // 76: agentapi.UI.GetOperation:input_type -> agentapi.OperationID
+	35, // 77: agentapi.UI.CancelOperation:input_type -> agentapi.OperationID
+	0,  // 78: agentapi.UI.ListOperations:input_type -> agentapi.Empty
+	38, // 79: agentapi.UI.RequestConfirmation:input_type -> agentapi.ConfirmationRequest
+	25, // 80: agentapi.UI.GetDistroLogs:input_type -> agentapi.DistroLogsRequest
+	28, // 81: agentapi.UI.PullDistroFile:input_type -> agentapi.DistroFileRequest
+	30, // 82: agentapi.UI.GetDistroTasks:input_type -> agentapi.DistroTasksRequest
+	33, // 83: agentapi.UI.CancelDistroTask:input_type -> agentapi.CancelTaskRequest
+	0,  // 84: agentapi.UI.GetOnboardingState:input_type -> agentapi.Empty
+	0,  // 85: agentapi.UI.SkipLandscapeOnboarding:input_type -> agentapi.Empty
+	0,  // 86: agentapi.UI.GetVersion:input_type -> agentapi.Empty
+	64, // 87: agentapi.WSLInstance.Connected:input_type -> agentapi.DistroInfo
+	66, // 88: agentapi.TaskPlugin.ExecuteTask:input_type -> agentapi.PluginTask
+	55, // 89: agentapi.UI.ApplyProToken:output_type -> agentapi.SubscriptionInfo
+	0,  // 90: agentapi.UI.ApplyDistroProToken:output_type -> agentapi.Empty
+	56, // 91: agentapi.UI.ApplyLandscapeConfig:output_type -> agentapi.LandscapeSource
+	0,  // 92: agentapi.UI.Ping:output_type -> agentapi.Empty
+	57, // 93: agentapi.UI.GetConfigSources:output_type -> agentapi.ConfigSources
+	55, // 94: agentapi.UI.NotifyPurchase:output_type -> agentapi.SubscriptionInfo
+	58, // 95: agentapi.UI.GetSubscriptionDetails:output_type -> agentapi.SubscriptionDetails
+	60, // 96: agentapi.UI.ValidateConfig:output_type -> agentapi.ConfigProblems
+	62, // 97: agentapi.UI.GetFeatureFlags:output_type -> agentapi.FeatureFlags
+	0,  // 98: agentapi.UI.ApplyLandscapeDistroOverride:output_type -> agentapi.Empty
+	54, // 99: agentapi.UI.GetLandscapeDistroOverrides:output_type -> agentapi.LandscapeDistroOverrides
+	4,  // 100: agentapi.UI.GetFleetStatus:output_type -> agentapi.FleetStatus
+	9,  // 101: agentapi.UI.WatchDistros:output_type -> agentapi.DistroChange
+	11, // 102: agentapi.UI.ExportDistro:output_type -> agentapi.ExportProgress
+	0,  // 103: agentapi.UI.SetBackupSchedule:output_type -> agentapi.Empty
+	12, // 104: agentapi.UI.GetBackupSchedule:output_type -> agentapi.BackupSchedule
+	0,  // 105: agentapi.UI.ResetDistro:output_type -> agentapi.Empty
+	0,  // 106: agentapi.UI.ResetAgent:output_type -> agentapi.Empty
+	23, // 107: agentapi.UI.ReloadConfig:output_type -> agentapi.ReloadResult
+	0,  // 108: agentapi.UI.ApplyDistroWSLSettings:output_type -> agentapi.Empty
+	0,  // 109: agentapi.UI.SetPauseState:output_type -> agentapi.Empty
+	42, // 110: agentapi.UI.GetPauseState:output_type -> agentapi.PauseState
+	0,  // 111: agentapi.UI.ApplyLandscapeEndpoint:output_type -> agentapi.Empty
+	0,  // 112: agentapi.UI.SetNotificationPreference:output_type -> agentapi.Empty
+	45, // 113: agentapi.UI.GetNotificationPreferences:output_type -> agentapi.NotificationPreferences
+	0,  // 114: agentapi.UI.SetApprovalPreference:output_type -> agentapi.Empty
+	47, // 115: agentapi.UI.GetApprovalPreferences:output_type -> agentapi.ApprovalPreferences
+	49, // 116: agentapi.UI.GetPendingApprovals:output_type -> agentapi.PendingApprovals
+	0,  // 117: agentapi.UI.ApproveTask:output_type -> agentapi.Empty
+	0,  // 118: agentapi.UI.DenyTask:output_type -> agentapi.Empty
+	0,  // 119: agentapi.UI.SubmitPluginTask:output_type -> agentapi.Empty
+	18, // 120: agentapi.UI.CompactDistro:output_type -> agentapi.CompactResult
+	0,  // 121: agentapi.UI.WakeDistro:output_type -> agentapi.Empty
+	0,  // 122: agentapi.UI.StopDistro:output_type -> agentapi.Empty
+	0,  // 123: agentapi.UI.SetDistroLabels:output_type -> agentapi.Empty
+	0,  // 124: agentapi.UI.SetDistroSparse:output_type -> agentapi.Empty
+	0,  // 125: agentapi.UI.SetCompactSchedule:output_type -> agentapi.Empty
+	20, // 126: agentapi.UI.GetCompactSchedule:output_type -> agentapi.CompactSchedule
+	21, // 127: agentapi.UI.GetWSLConfig:output_type -> agentapi.WSLConfig
+	21, // 128: agentapi.UI.SetWSLConfig:output_type -> agentapi.WSLConfig
+	0,  // 129: agentapi.UI.UpdateWSL:output_type -> agentapi.Empty
+	36, // 130: agentapi.UI.CreateOperation:output_type -> agentapi.Operation
+	36, // 131: agentapi.UI.GetOperation:output_type -> agentapi.Operation
+	36, // 132: agentapi.UI.CancelOperation:output_type -> agentapi.Operation
+	37, // 133: agentapi.UI.ListOperations:output_type -> agentapi.Operations
+	39, // 134: agentapi.UI.RequestConfirmation:output_type -> agentapi.Confirmation
+	26, // 135: agentapi.UI.GetDistroLogs:output_type -> agentapi.DistroLogs
+	29, // 136: agentapi.UI.PullDistroFile:output_type -> agentapi.DistroFile
+	31, // 137: agentapi.UI.GetDistroTasks:output_type -> agentapi.DistroTasks
+	0,  // 138: agentapi.UI.CancelDistroTask:output_type -> agentapi.Empty
+	43, // 139: agentapi.UI.GetOnboardingState:output_type -> agentapi.OnboardingState
+	43, // 140: agentapi.UI.SkipLandscapeOnboarding:output_type -> agentapi.OnboardingState
+	68, // 141: agentapi.UI.GetVersion:output_type -> agentapi.VersionInfo
+	65, // 142: agentapi.WSLInstance.Connected:output_type -> agentapi.Port
+	67, // 143: agentapi.TaskPlugin.ExecuteTask:output_type -> agentapi.PluginTaskResult
+	89, // [89:144] is the sub-list for method output_type
+	34, // [34:89] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
+}
+
+func init() { file_agentapi_proto_init() }
+func file_agentapi_proto_init() {
+	if File_agentapi_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_agentapi_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProAttachInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroProToken); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LandscapeConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FleetStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WSLAvailability); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PreflightProblem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ManagedMode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackupSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WakeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroLabels); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Label); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompactRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompactResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SparseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompactSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WSLConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadedSetting); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroLogsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroLogs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroTasksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroTasks); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueuedTask); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelTaskRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OperationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OperationID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Operation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Operations); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfirmationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -715,8 +5974,8 @@ func file_agentapi_proto_init() {
 				return nil
 			}
 		}
-		file_agentapi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProAttachInfo); i {
+		file_agentapi_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Confirmation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -727,8 +5986,8 @@ func file_agentapi_proto_init() {
 				return nil
 			}
 		}
-		file_agentapi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LandscapeConfig); i {
+		file_agentapi_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DistroWSLSettings); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -739,7 +5998,175 @@ func file_agentapi_proto_init() {
 				return nil
 			}
 		}
-		file_agentapi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_agentapi_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Switch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OnboardingState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationPreference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationPreferences); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApprovalPreference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApprovalPreferences); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PendingApproval); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PendingApprovals); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApprovalDecision); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginTaskSubmission); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LandscapeDistroOverride); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LandscapeEndpoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LandscapeDistroOverrides); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SubscriptionInfo); i {
 			case 0:
 				return &v.state
@@ -751,7 +6178,7 @@ func file_agentapi_proto_init() {
 				return nil
 			}
 		}
-		file_agentapi_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_agentapi_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*LandscapeSource); i {
 			case 0:
 				return &v.state
@@ -763,7 +6190,7 @@ func file_agentapi_proto_init() {
 				return nil
 			}
 		}
-		file_agentapi_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_agentapi_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ConfigSources); i {
 			case 0:
 				return &v.state
@@ -775,7 +6202,79 @@ func file_agentapi_proto_init() {
 				return nil
 			}
 		}
-		file_agentapi_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_agentapi_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscriptionDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscriptionSourceDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigProblems); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigProblem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FeatureFlags); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FeatureFlag); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DistroInfo); i {
 			case 0:
 				return &v.state
@@ -787,7 +6286,7 @@ func file_agentapi_proto_init() {
 				return nil
 			}
 		}
-		file_agentapi_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_agentapi_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Port); i {
 			case 0:
 				return &v.state
@@ -799,14 +6298,66 @@ func file_agentapi_proto_init() {
 				return nil
 			}
 		}
+		file_agentapi_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginTask); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginTaskResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agentapi_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProtocolVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_agentapi_proto_msgTypes[34].OneofWrappers = []interface{}{
+		(*OperationRequest_ExportDistro)(nil),
+		(*OperationRequest_ResetDistro)(nil),
 	}
-	file_agentapi_proto_msgTypes[3].OneofWrappers = []interface{}{
+	file_agentapi_proto_msgTypes[55].OneofWrappers = []interface{}{
 		(*SubscriptionInfo_None)(nil),
 		(*SubscriptionInfo_User)(nil),
 		(*SubscriptionInfo_Organization)(nil),
 		(*SubscriptionInfo_MicrosoftStore)(nil),
 	}
-	file_agentapi_proto_msgTypes[4].OneofWrappers = []interface{}{
+	file_agentapi_proto_msgTypes[56].OneofWrappers = []interface{}{
 		(*LandscapeSource_None)(nil),
 		(*LandscapeSource_User)(nil),
 		(*LandscapeSource_Organization)(nil),
@@ -817,9 +6368,9 @@ func file_agentapi_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_agentapi_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   70,
 			NumExtensions: 0,
-			NumServices:   2,
+			NumServices:   3,
 		},
 		GoTypes:           file_agentapi_proto_goTypes,
 		DependencyIndexes: file_agentapi_proto_depIdxs,
@@ -19,11 +19,59 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	UI_ApplyProToken_FullMethodName        = "/agentapi.UI/ApplyProToken"
-	UI_ApplyLandscapeConfig_FullMethodName = "/agentapi.UI/ApplyLandscapeConfig"
-	UI_Ping_FullMethodName                 = "/agentapi.UI/Ping"
-	UI_GetConfigSources_FullMethodName     = "/agentapi.UI/GetConfigSources"
-	UI_NotifyPurchase_FullMethodName       = "/agentapi.UI/NotifyPurchase"
+	UI_ApplyProToken_FullMethodName                = "/agentapi.UI/ApplyProToken"
+	UI_ApplyDistroProToken_FullMethodName          = "/agentapi.UI/ApplyDistroProToken"
+	UI_ApplyLandscapeConfig_FullMethodName         = "/agentapi.UI/ApplyLandscapeConfig"
+	UI_Ping_FullMethodName                         = "/agentapi.UI/Ping"
+	UI_GetConfigSources_FullMethodName             = "/agentapi.UI/GetConfigSources"
+	UI_NotifyPurchase_FullMethodName               = "/agentapi.UI/NotifyPurchase"
+	UI_GetSubscriptionDetails_FullMethodName       = "/agentapi.UI/GetSubscriptionDetails"
+	UI_ValidateConfig_FullMethodName               = "/agentapi.UI/ValidateConfig"
+	UI_GetFeatureFlags_FullMethodName              = "/agentapi.UI/GetFeatureFlags"
+	UI_ApplyLandscapeDistroOverride_FullMethodName = "/agentapi.UI/ApplyLandscapeDistroOverride"
+	UI_GetLandscapeDistroOverrides_FullMethodName  = "/agentapi.UI/GetLandscapeDistroOverrides"
+	UI_GetFleetStatus_FullMethodName               = "/agentapi.UI/GetFleetStatus"
+	UI_WatchDistros_FullMethodName                 = "/agentapi.UI/WatchDistros"
+	UI_ExportDistro_FullMethodName                 = "/agentapi.UI/ExportDistro"
+	UI_SetBackupSchedule_FullMethodName            = "/agentapi.UI/SetBackupSchedule"
+	UI_GetBackupSchedule_FullMethodName            = "/agentapi.UI/GetBackupSchedule"
+	UI_ResetDistro_FullMethodName                  = "/agentapi.UI/ResetDistro"
+	UI_ResetAgent_FullMethodName                   = "/agentapi.UI/ResetAgent"
+	UI_ReloadConfig_FullMethodName                 = "/agentapi.UI/ReloadConfig"
+	UI_ApplyDistroWSLSettings_FullMethodName       = "/agentapi.UI/ApplyDistroWSLSettings"
+	UI_SetPauseState_FullMethodName                = "/agentapi.UI/SetPauseState"
+	UI_GetPauseState_FullMethodName                = "/agentapi.UI/GetPauseState"
+	UI_ApplyLandscapeEndpoint_FullMethodName       = "/agentapi.UI/ApplyLandscapeEndpoint"
+	UI_SetNotificationPreference_FullMethodName    = "/agentapi.UI/SetNotificationPreference"
+	UI_GetNotificationPreferences_FullMethodName   = "/agentapi.UI/GetNotificationPreferences"
+	UI_SetApprovalPreference_FullMethodName        = "/agentapi.UI/SetApprovalPreference"
+	UI_GetApprovalPreferences_FullMethodName       = "/agentapi.UI/GetApprovalPreferences"
+	UI_GetPendingApprovals_FullMethodName          = "/agentapi.UI/GetPendingApprovals"
+	UI_ApproveTask_FullMethodName                  = "/agentapi.UI/ApproveTask"
+	UI_DenyTask_FullMethodName                     = "/agentapi.UI/DenyTask"
+	UI_SubmitPluginTask_FullMethodName             = "/agentapi.UI/SubmitPluginTask"
+	UI_CompactDistro_FullMethodName                = "/agentapi.UI/CompactDistro"
+	UI_WakeDistro_FullMethodName                   = "/agentapi.UI/WakeDistro"
+	UI_StopDistro_FullMethodName                   = "/agentapi.UI/StopDistro"
+	UI_SetDistroLabels_FullMethodName              = "/agentapi.UI/SetDistroLabels"
+	UI_SetDistroSparse_FullMethodName              = "/agentapi.UI/SetDistroSparse"
+	UI_SetCompactSchedule_FullMethodName           = "/agentapi.UI/SetCompactSchedule"
+	UI_GetCompactSchedule_FullMethodName           = "/agentapi.UI/GetCompactSchedule"
+	UI_GetWSLConfig_FullMethodName                 = "/agentapi.UI/GetWSLConfig"
+	UI_SetWSLConfig_FullMethodName                 = "/agentapi.UI/SetWSLConfig"
+	UI_UpdateWSL_FullMethodName                    = "/agentapi.UI/UpdateWSL"
+	UI_CreateOperation_FullMethodName              = "/agentapi.UI/CreateOperation"
+	UI_GetOperation_FullMethodName                 = "/agentapi.UI/GetOperation"
+	UI_CancelOperation_FullMethodName              = "/agentapi.UI/CancelOperation"
+	UI_ListOperations_FullMethodName               = "/agentapi.UI/ListOperations"
+	UI_RequestConfirmation_FullMethodName          = "/agentapi.UI/RequestConfirmation"
+	UI_GetDistroLogs_FullMethodName                = "/agentapi.UI/GetDistroLogs"
+	UI_PullDistroFile_FullMethodName               = "/agentapi.UI/PullDistroFile"
+	UI_GetDistroTasks_FullMethodName               = "/agentapi.UI/GetDistroTasks"
+	UI_CancelDistroTask_FullMethodName             = "/agentapi.UI/CancelDistroTask"
+	UI_GetOnboardingState_FullMethodName           = "/agentapi.UI/GetOnboardingState"
+	UI_SkipLandscapeOnboarding_FullMethodName      = "/agentapi.UI/SkipLandscapeOnboarding"
+	UI_GetVersion_FullMethodName                   = "/agentapi.UI/GetVersion"
 )
 
 // UIClient is the client API for UI service.
@@ -31,10 +79,58 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type UIClient interface {
 	ApplyProToken(ctx context.Context, in *ProAttachInfo, opts ...grpc.CallOption) (*SubscriptionInfo, error)
+	ApplyDistroProToken(ctx context.Context, in *DistroProToken, opts ...grpc.CallOption) (*Empty, error)
 	ApplyLandscapeConfig(ctx context.Context, in *LandscapeConfig, opts ...grpc.CallOption) (*LandscapeSource, error)
 	Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
 	GetConfigSources(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigSources, error)
 	NotifyPurchase(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SubscriptionInfo, error)
+	GetSubscriptionDetails(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SubscriptionDetails, error)
+	ValidateConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigProblems, error)
+	GetFeatureFlags(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FeatureFlags, error)
+	ApplyLandscapeDistroOverride(ctx context.Context, in *LandscapeDistroOverride, opts ...grpc.CallOption) (*Empty, error)
+	GetLandscapeDistroOverrides(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LandscapeDistroOverrides, error)
+	GetFleetStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FleetStatus, error)
+	WatchDistros(ctx context.Context, in *Empty, opts ...grpc.CallOption) (UI_WatchDistrosClient, error)
+	ExportDistro(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (UI_ExportDistroClient, error)
+	SetBackupSchedule(ctx context.Context, in *BackupSchedule, opts ...grpc.CallOption) (*Empty, error)
+	GetBackupSchedule(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BackupSchedule, error)
+	ResetDistro(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*Empty, error)
+	ResetAgent(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	ReloadConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ReloadResult, error)
+	ApplyDistroWSLSettings(ctx context.Context, in *DistroWSLSettings, opts ...grpc.CallOption) (*Empty, error)
+	SetPauseState(ctx context.Context, in *PauseState, opts ...grpc.CallOption) (*Empty, error)
+	GetPauseState(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PauseState, error)
+	ApplyLandscapeEndpoint(ctx context.Context, in *LandscapeEndpoint, opts ...grpc.CallOption) (*Empty, error)
+	SetNotificationPreference(ctx context.Context, in *NotificationPreference, opts ...grpc.CallOption) (*Empty, error)
+	GetNotificationPreferences(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NotificationPreferences, error)
+	SetApprovalPreference(ctx context.Context, in *ApprovalPreference, opts ...grpc.CallOption) (*Empty, error)
+	GetApprovalPreferences(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ApprovalPreferences, error)
+	GetPendingApprovals(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PendingApprovals, error)
+	ApproveTask(ctx context.Context, in *ApprovalDecision, opts ...grpc.CallOption) (*Empty, error)
+	DenyTask(ctx context.Context, in *ApprovalDecision, opts ...grpc.CallOption) (*Empty, error)
+	SubmitPluginTask(ctx context.Context, in *PluginTaskSubmission, opts ...grpc.CallOption) (*Empty, error)
+	CompactDistro(ctx context.Context, in *CompactRequest, opts ...grpc.CallOption) (*CompactResult, error)
+	WakeDistro(ctx context.Context, in *WakeRequest, opts ...grpc.CallOption) (*Empty, error)
+	StopDistro(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetDistroLabels(ctx context.Context, in *DistroLabels, opts ...grpc.CallOption) (*Empty, error)
+	SetDistroSparse(ctx context.Context, in *SparseRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetCompactSchedule(ctx context.Context, in *CompactSchedule, opts ...grpc.CallOption) (*Empty, error)
+	GetCompactSchedule(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CompactSchedule, error)
+	GetWSLConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*WSLConfig, error)
+	SetWSLConfig(ctx context.Context, in *WSLConfig, opts ...grpc.CallOption) (*WSLConfig, error)
+	UpdateWSL(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	CreateOperation(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (*Operation, error)
+	GetOperation(ctx context.Context, in *OperationID, opts ...grpc.CallOption) (*Operation, error)
+	CancelOperation(ctx context.Context, in *OperationID, opts ...grpc.CallOption) (*Operation, error)
+	ListOperations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Operations, error)
+	RequestConfirmation(ctx context.Context, in *ConfirmationRequest, opts ...grpc.CallOption) (*Confirmation, error)
+	GetDistroLogs(ctx context.Context, in *DistroLogsRequest, opts ...grpc.CallOption) (*DistroLogs, error)
+	PullDistroFile(ctx context.Context, in *DistroFileRequest, opts ...grpc.CallOption) (*DistroFile, error)
+	GetDistroTasks(ctx context.Context, in *DistroTasksRequest, opts ...grpc.CallOption) (*DistroTasks, error)
+	CancelDistroTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetOnboardingState(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OnboardingState, error)
+	SkipLandscapeOnboarding(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OnboardingState, error)
+	GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionInfo, error)
 }
 
 type uIClient struct {
@@ -54,6 +150,15 @@ func (c *uIClient) ApplyProToken(ctx context.Context, in *ProAttachInfo, opts ..
 	return out, nil
 }
 
+func (c *uIClient) ApplyDistroProToken(ctx context.Context, in *DistroProToken, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_ApplyDistroProToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *uIClient) ApplyLandscapeConfig(ctx context.Context, in *LandscapeConfig, opts ...grpc.CallOption) (*LandscapeSource, error) {
 	out := new(LandscapeSource)
 	err := c.cc.Invoke(ctx, UI_ApplyLandscapeConfig_FullMethodName, in, out, opts...)
@@ -90,136 +195,1667 @@ func (c *uIClient) NotifyPurchase(ctx context.Context, in *Empty, opts ...grpc.C
 	return out, nil
 }
 
-// UIServer is the server API for UI service.
-// All implementations must embed UnimplementedUIServer
-// for forward compatibility
-type UIServer interface {
-	ApplyProToken(context.Context, *ProAttachInfo) (*SubscriptionInfo, error)
-	ApplyLandscapeConfig(context.Context, *LandscapeConfig) (*LandscapeSource, error)
-	Ping(context.Context, *Empty) (*Empty, error)
-	GetConfigSources(context.Context, *Empty) (*ConfigSources, error)
-	NotifyPurchase(context.Context, *Empty) (*SubscriptionInfo, error)
-	mustEmbedUnimplementedUIServer()
+func (c *uIClient) GetSubscriptionDetails(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SubscriptionDetails, error) {
+	out := new(SubscriptionDetails)
+	err := c.cc.Invoke(ctx, UI_GetSubscriptionDetails_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ValidateConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigProblems, error) {
+	out := new(ConfigProblems)
+	err := c.cc.Invoke(ctx, UI_ValidateConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetFeatureFlags(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FeatureFlags, error) {
+	out := new(FeatureFlags)
+	err := c.cc.Invoke(ctx, UI_GetFeatureFlags_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ApplyLandscapeDistroOverride(ctx context.Context, in *LandscapeDistroOverride, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_ApplyLandscapeDistroOverride_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetLandscapeDistroOverrides(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LandscapeDistroOverrides, error) {
+	out := new(LandscapeDistroOverrides)
+	err := c.cc.Invoke(ctx, UI_GetLandscapeDistroOverrides_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetFleetStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FleetStatus, error) {
+	out := new(FleetStatus)
+	err := c.cc.Invoke(ctx, UI_GetFleetStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) WatchDistros(ctx context.Context, in *Empty, opts ...grpc.CallOption) (UI_WatchDistrosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UI_ServiceDesc.Streams[0], UI_WatchDistros_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &uIWatchDistrosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UI_WatchDistrosClient interface {
+	Recv() (*DistroChange, error)
+	grpc.ClientStream
+}
+
+type uIWatchDistrosClient struct {
+	grpc.ClientStream
+}
+
+func (x *uIWatchDistrosClient) Recv() (*DistroChange, error) {
+	m := new(DistroChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *uIClient) ExportDistro(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (UI_ExportDistroClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UI_ServiceDesc.Streams[1], UI_ExportDistro_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &uIExportDistroClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UI_ExportDistroClient interface {
+	Recv() (*ExportProgress, error)
+	grpc.ClientStream
+}
+
+type uIExportDistroClient struct {
+	grpc.ClientStream
+}
+
+func (x *uIExportDistroClient) Recv() (*ExportProgress, error) {
+	m := new(ExportProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *uIClient) SetBackupSchedule(ctx context.Context, in *BackupSchedule, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_SetBackupSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetBackupSchedule(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BackupSchedule, error) {
+	out := new(BackupSchedule)
+	err := c.cc.Invoke(ctx, UI_GetBackupSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ResetDistro(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_ResetDistro_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ResetAgent(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_ResetAgent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ReloadConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ReloadResult, error) {
+	out := new(ReloadResult)
+	err := c.cc.Invoke(ctx, UI_ReloadConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ApplyDistroWSLSettings(ctx context.Context, in *DistroWSLSettings, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_ApplyDistroWSLSettings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SetPauseState(ctx context.Context, in *PauseState, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_SetPauseState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetPauseState(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PauseState, error) {
+	out := new(PauseState)
+	err := c.cc.Invoke(ctx, UI_GetPauseState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ApplyLandscapeEndpoint(ctx context.Context, in *LandscapeEndpoint, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_ApplyLandscapeEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SetNotificationPreference(ctx context.Context, in *NotificationPreference, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_SetNotificationPreference_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetNotificationPreferences(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, UI_GetNotificationPreferences_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SetApprovalPreference(ctx context.Context, in *ApprovalPreference, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_SetApprovalPreference_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetApprovalPreferences(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ApprovalPreferences, error) {
+	out := new(ApprovalPreferences)
+	err := c.cc.Invoke(ctx, UI_GetApprovalPreferences_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetPendingApprovals(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PendingApprovals, error) {
+	out := new(PendingApprovals)
+	err := c.cc.Invoke(ctx, UI_GetPendingApprovals_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ApproveTask(ctx context.Context, in *ApprovalDecision, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_ApproveTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) DenyTask(ctx context.Context, in *ApprovalDecision, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_DenyTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SubmitPluginTask(ctx context.Context, in *PluginTaskSubmission, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_SubmitPluginTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) CompactDistro(ctx context.Context, in *CompactRequest, opts ...grpc.CallOption) (*CompactResult, error) {
+	out := new(CompactResult)
+	err := c.cc.Invoke(ctx, UI_CompactDistro_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) WakeDistro(ctx context.Context, in *WakeRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_WakeDistro_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) StopDistro(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_StopDistro_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SetDistroLabels(ctx context.Context, in *DistroLabels, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_SetDistroLabels_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SetDistroSparse(ctx context.Context, in *SparseRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_SetDistroSparse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SetCompactSchedule(ctx context.Context, in *CompactSchedule, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_SetCompactSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetCompactSchedule(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CompactSchedule, error) {
+	out := new(CompactSchedule)
+	err := c.cc.Invoke(ctx, UI_GetCompactSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetWSLConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*WSLConfig, error) {
+	out := new(WSLConfig)
+	err := c.cc.Invoke(ctx, UI_GetWSLConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SetWSLConfig(ctx context.Context, in *WSLConfig, opts ...grpc.CallOption) (*WSLConfig, error) {
+	out := new(WSLConfig)
+	err := c.cc.Invoke(ctx, UI_SetWSLConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) UpdateWSL(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_UpdateWSL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) CreateOperation(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, UI_CreateOperation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetOperation(ctx context.Context, in *OperationID, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, UI_GetOperation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) CancelOperation(ctx context.Context, in *OperationID, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, UI_CancelOperation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) ListOperations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Operations, error) {
+	out := new(Operations)
+	err := c.cc.Invoke(ctx, UI_ListOperations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) RequestConfirmation(ctx context.Context, in *ConfirmationRequest, opts ...grpc.CallOption) (*Confirmation, error) {
+	out := new(Confirmation)
+	err := c.cc.Invoke(ctx, UI_RequestConfirmation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetDistroLogs(ctx context.Context, in *DistroLogsRequest, opts ...grpc.CallOption) (*DistroLogs, error) {
+	out := new(DistroLogs)
+	err := c.cc.Invoke(ctx, UI_GetDistroLogs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) PullDistroFile(ctx context.Context, in *DistroFileRequest, opts ...grpc.CallOption) (*DistroFile, error) {
+	out := new(DistroFile)
+	err := c.cc.Invoke(ctx, UI_PullDistroFile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetDistroTasks(ctx context.Context, in *DistroTasksRequest, opts ...grpc.CallOption) (*DistroTasks, error) {
+	out := new(DistroTasks)
+	err := c.cc.Invoke(ctx, UI_GetDistroTasks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) CancelDistroTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, UI_CancelDistroTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetOnboardingState(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OnboardingState, error) {
+	out := new(OnboardingState)
+	err := c.cc.Invoke(ctx, UI_GetOnboardingState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) SkipLandscapeOnboarding(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OnboardingState, error) {
+	out := new(OnboardingState)
+	err := c.cc.Invoke(ctx, UI_SkipLandscapeOnboarding_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uIClient) GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionInfo, error) {
+	out := new(VersionInfo)
+	err := c.cc.Invoke(ctx, UI_GetVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UIServer is the server API for UI service.
+// All implementations must embed UnimplementedUIServer
+// for forward compatibility
+type UIServer interface {
+	ApplyProToken(context.Context, *ProAttachInfo) (*SubscriptionInfo, error)
+	ApplyDistroProToken(context.Context, *DistroProToken) (*Empty, error)
+	ApplyLandscapeConfig(context.Context, *LandscapeConfig) (*LandscapeSource, error)
+	Ping(context.Context, *Empty) (*Empty, error)
+	GetConfigSources(context.Context, *Empty) (*ConfigSources, error)
+	NotifyPurchase(context.Context, *Empty) (*SubscriptionInfo, error)
+	GetSubscriptionDetails(context.Context, *Empty) (*SubscriptionDetails, error)
+	ValidateConfig(context.Context, *Empty) (*ConfigProblems, error)
+	GetFeatureFlags(context.Context, *Empty) (*FeatureFlags, error)
+	ApplyLandscapeDistroOverride(context.Context, *LandscapeDistroOverride) (*Empty, error)
+	GetLandscapeDistroOverrides(context.Context, *Empty) (*LandscapeDistroOverrides, error)
+	GetFleetStatus(context.Context, *Empty) (*FleetStatus, error)
+	WatchDistros(*Empty, UI_WatchDistrosServer) error
+	ExportDistro(*ExportRequest, UI_ExportDistroServer) error
+	SetBackupSchedule(context.Context, *BackupSchedule) (*Empty, error)
+	GetBackupSchedule(context.Context, *Empty) (*BackupSchedule, error)
+	ResetDistro(context.Context, *ResetRequest) (*Empty, error)
+	ResetAgent(context.Context, *Empty) (*Empty, error)
+	ReloadConfig(context.Context, *Empty) (*ReloadResult, error)
+	ApplyDistroWSLSettings(context.Context, *DistroWSLSettings) (*Empty, error)
+	SetPauseState(context.Context, *PauseState) (*Empty, error)
+	GetPauseState(context.Context, *Empty) (*PauseState, error)
+	ApplyLandscapeEndpoint(context.Context, *LandscapeEndpoint) (*Empty, error)
+	SetNotificationPreference(context.Context, *NotificationPreference) (*Empty, error)
+	GetNotificationPreferences(context.Context, *Empty) (*NotificationPreferences, error)
+	SetApprovalPreference(context.Context, *ApprovalPreference) (*Empty, error)
+	GetApprovalPreferences(context.Context, *Empty) (*ApprovalPreferences, error)
+	GetPendingApprovals(context.Context, *Empty) (*PendingApprovals, error)
+	ApproveTask(context.Context, *ApprovalDecision) (*Empty, error)
+	DenyTask(context.Context, *ApprovalDecision) (*Empty, error)
+	SubmitPluginTask(context.Context, *PluginTaskSubmission) (*Empty, error)
+	CompactDistro(context.Context, *CompactRequest) (*CompactResult, error)
+	WakeDistro(context.Context, *WakeRequest) (*Empty, error)
+	StopDistro(context.Context, *StopRequest) (*Empty, error)
+	SetDistroLabels(context.Context, *DistroLabels) (*Empty, error)
+	SetDistroSparse(context.Context, *SparseRequest) (*Empty, error)
+	SetCompactSchedule(context.Context, *CompactSchedule) (*Empty, error)
+	GetCompactSchedule(context.Context, *Empty) (*CompactSchedule, error)
+	GetWSLConfig(context.Context, *Empty) (*WSLConfig, error)
+	SetWSLConfig(context.Context, *WSLConfig) (*WSLConfig, error)
+	UpdateWSL(context.Context, *Empty) (*Empty, error)
+	CreateOperation(context.Context, *OperationRequest) (*Operation, error)
+	GetOperation(context.Context, *OperationID) (*Operation, error)
+	CancelOperation(context.Context, *OperationID) (*Operation, error)
+	ListOperations(context.Context, *Empty) (*Operations, error)
+	RequestConfirmation(context.Context, *ConfirmationRequest) (*Confirmation, error)
+	GetDistroLogs(context.Context, *DistroLogsRequest) (*DistroLogs, error)
+	PullDistroFile(context.Context, *DistroFileRequest) (*DistroFile, error)
+	GetDistroTasks(context.Context, *DistroTasksRequest) (*DistroTasks, error)
+	CancelDistroTask(context.Context, *CancelTaskRequest) (*Empty, error)
+	GetOnboardingState(context.Context, *Empty) (*OnboardingState, error)
+	SkipLandscapeOnboarding(context.Context, *Empty) (*OnboardingState, error)
+	GetVersion(context.Context, *Empty) (*VersionInfo, error)
+	mustEmbedUnimplementedUIServer()
+}
+
+// UnimplementedUIServer must be embedded to have forward compatible implementations.
+type UnimplementedUIServer struct {
+}
+
+func (UnimplementedUIServer) ApplyProToken(context.Context, *ProAttachInfo) (*SubscriptionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyProToken not implemented")
+}
+func (UnimplementedUIServer) ApplyDistroProToken(context.Context, *DistroProToken) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyDistroProToken not implemented")
+}
+func (UnimplementedUIServer) ApplyLandscapeConfig(context.Context, *LandscapeConfig) (*LandscapeSource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyLandscapeConfig not implemented")
+}
+func (UnimplementedUIServer) Ping(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedUIServer) GetConfigSources(context.Context, *Empty) (*ConfigSources, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigSources not implemented")
+}
+func (UnimplementedUIServer) NotifyPurchase(context.Context, *Empty) (*SubscriptionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NotifyPurchase not implemented")
+}
+func (UnimplementedUIServer) GetSubscriptionDetails(context.Context, *Empty) (*SubscriptionDetails, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscriptionDetails not implemented")
+}
+func (UnimplementedUIServer) ValidateConfig(context.Context, *Empty) (*ConfigProblems, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateConfig not implemented")
+}
+func (UnimplementedUIServer) GetFeatureFlags(context.Context, *Empty) (*FeatureFlags, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeatureFlags not implemented")
+}
+func (UnimplementedUIServer) ApplyLandscapeDistroOverride(context.Context, *LandscapeDistroOverride) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyLandscapeDistroOverride not implemented")
+}
+func (UnimplementedUIServer) GetLandscapeDistroOverrides(context.Context, *Empty) (*LandscapeDistroOverrides, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLandscapeDistroOverrides not implemented")
+}
+func (UnimplementedUIServer) GetFleetStatus(context.Context, *Empty) (*FleetStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFleetStatus not implemented")
+}
+func (UnimplementedUIServer) WatchDistros(*Empty, UI_WatchDistrosServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDistros not implemented")
+}
+func (UnimplementedUIServer) ExportDistro(*ExportRequest, UI_ExportDistroServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportDistro not implemented")
+}
+func (UnimplementedUIServer) SetBackupSchedule(context.Context, *BackupSchedule) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBackupSchedule not implemented")
+}
+func (UnimplementedUIServer) GetBackupSchedule(context.Context, *Empty) (*BackupSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBackupSchedule not implemented")
+}
+func (UnimplementedUIServer) ResetDistro(context.Context, *ResetRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetDistro not implemented")
+}
+func (UnimplementedUIServer) ResetAgent(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetAgent not implemented")
+}
+func (UnimplementedUIServer) ReloadConfig(context.Context, *Empty) (*ReloadResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedUIServer) ApplyDistroWSLSettings(context.Context, *DistroWSLSettings) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyDistroWSLSettings not implemented")
+}
+func (UnimplementedUIServer) SetPauseState(context.Context, *PauseState) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPauseState not implemented")
+}
+func (UnimplementedUIServer) GetPauseState(context.Context, *Empty) (*PauseState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPauseState not implemented")
+}
+func (UnimplementedUIServer) ApplyLandscapeEndpoint(context.Context, *LandscapeEndpoint) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyLandscapeEndpoint not implemented")
+}
+func (UnimplementedUIServer) SetNotificationPreference(context.Context, *NotificationPreference) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNotificationPreference not implemented")
+}
+func (UnimplementedUIServer) GetNotificationPreferences(context.Context, *Empty) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationPreferences not implemented")
+}
+func (UnimplementedUIServer) SetApprovalPreference(context.Context, *ApprovalPreference) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetApprovalPreference not implemented")
+}
+func (UnimplementedUIServer) GetApprovalPreferences(context.Context, *Empty) (*ApprovalPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApprovalPreferences not implemented")
+}
+func (UnimplementedUIServer) GetPendingApprovals(context.Context, *Empty) (*PendingApprovals, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPendingApprovals not implemented")
+}
+func (UnimplementedUIServer) ApproveTask(context.Context, *ApprovalDecision) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveTask not implemented")
+}
+func (UnimplementedUIServer) DenyTask(context.Context, *ApprovalDecision) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenyTask not implemented")
+}
+func (UnimplementedUIServer) SubmitPluginTask(context.Context, *PluginTaskSubmission) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitPluginTask not implemented")
+}
+func (UnimplementedUIServer) CompactDistro(context.Context, *CompactRequest) (*CompactResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompactDistro not implemented")
+}
+func (UnimplementedUIServer) WakeDistro(context.Context, *WakeRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WakeDistro not implemented")
+}
+func (UnimplementedUIServer) StopDistro(context.Context, *StopRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopDistro not implemented")
+}
+func (UnimplementedUIServer) SetDistroLabels(context.Context, *DistroLabels) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDistroLabels not implemented")
+}
+func (UnimplementedUIServer) SetDistroSparse(context.Context, *SparseRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDistroSparse not implemented")
+}
+func (UnimplementedUIServer) SetCompactSchedule(context.Context, *CompactSchedule) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCompactSchedule not implemented")
+}
+func (UnimplementedUIServer) GetCompactSchedule(context.Context, *Empty) (*CompactSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCompactSchedule not implemented")
+}
+func (UnimplementedUIServer) GetWSLConfig(context.Context, *Empty) (*WSLConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWSLConfig not implemented")
+}
+func (UnimplementedUIServer) SetWSLConfig(context.Context, *WSLConfig) (*WSLConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetWSLConfig not implemented")
+}
+func (UnimplementedUIServer) UpdateWSL(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWSL not implemented")
+}
+func (UnimplementedUIServer) CreateOperation(context.Context, *OperationRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOperation not implemented")
+}
+func (UnimplementedUIServer) GetOperation(context.Context, *OperationID) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperation not implemented")
+}
+func (UnimplementedUIServer) CancelOperation(context.Context, *OperationID) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOperation not implemented")
+}
+func (UnimplementedUIServer) ListOperations(context.Context, *Empty) (*Operations, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOperations not implemented")
+}
+func (UnimplementedUIServer) RequestConfirmation(context.Context, *ConfirmationRequest) (*Confirmation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestConfirmation not implemented")
+}
+func (UnimplementedUIServer) GetDistroLogs(context.Context, *DistroLogsRequest) (*DistroLogs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDistroLogs not implemented")
+}
+func (UnimplementedUIServer) PullDistroFile(context.Context, *DistroFileRequest) (*DistroFile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PullDistroFile not implemented")
+}
+func (UnimplementedUIServer) GetDistroTasks(context.Context, *DistroTasksRequest) (*DistroTasks, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDistroTasks not implemented")
+}
+func (UnimplementedUIServer) CancelDistroTask(context.Context, *CancelTaskRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelDistroTask not implemented")
+}
+func (UnimplementedUIServer) GetOnboardingState(context.Context, *Empty) (*OnboardingState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOnboardingState not implemented")
+}
+func (UnimplementedUIServer) SkipLandscapeOnboarding(context.Context, *Empty) (*OnboardingState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SkipLandscapeOnboarding not implemented")
+}
+func (UnimplementedUIServer) GetVersion(context.Context, *Empty) (*VersionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedUIServer) mustEmbedUnimplementedUIServer() {}
+
+// UnsafeUIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UIServer will
+// result in compilation errors.
+type UnsafeUIServer interface {
+	mustEmbedUnimplementedUIServer()
+}
+
+func RegisterUIServer(s grpc.ServiceRegistrar, srv UIServer) {
+	s.RegisterService(&UI_ServiceDesc, srv)
+}
+
+func _UI_ApplyProToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProAttachInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ApplyProToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ApplyProToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ApplyProToken(ctx, req.(*ProAttachInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ApplyDistroProToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DistroProToken)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ApplyDistroProToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ApplyDistroProToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ApplyDistroProToken(ctx, req.(*DistroProToken))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ApplyLandscapeConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LandscapeConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ApplyLandscapeConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ApplyLandscapeConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ApplyLandscapeConfig(ctx, req.(*LandscapeConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).Ping(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetConfigSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetConfigSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetConfigSources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetConfigSources(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_NotifyPurchase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).NotifyPurchase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_NotifyPurchase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).NotifyPurchase(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetSubscriptionDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetSubscriptionDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetSubscriptionDetails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetSubscriptionDetails(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ValidateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ValidateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ValidateConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ValidateConfig(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetFeatureFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetFeatureFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetFeatureFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetFeatureFlags(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ApplyLandscapeDistroOverride_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LandscapeDistroOverride)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ApplyLandscapeDistroOverride(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ApplyLandscapeDistroOverride_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ApplyLandscapeDistroOverride(ctx, req.(*LandscapeDistroOverride))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetLandscapeDistroOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetLandscapeDistroOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetLandscapeDistroOverrides_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetLandscapeDistroOverrides(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetFleetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetFleetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetFleetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetFleetStatus(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_WatchDistros_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UIServer).WatchDistros(m, &uIWatchDistrosServer{stream})
+}
+
+type UI_WatchDistrosServer interface {
+	Send(*DistroChange) error
+	grpc.ServerStream
+}
+
+type uIWatchDistrosServer struct {
+	grpc.ServerStream
+}
+
+func (x *uIWatchDistrosServer) Send(m *DistroChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _UI_ExportDistro_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UIServer).ExportDistro(m, &uIExportDistroServer{stream})
+}
+
+type UI_ExportDistroServer interface {
+	Send(*ExportProgress) error
+	grpc.ServerStream
+}
+
+type uIExportDistroServer struct {
+	grpc.ServerStream
+}
+
+func (x *uIExportDistroServer) Send(m *ExportProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _UI_SetBackupSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupSchedule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SetBackupSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SetBackupSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SetBackupSchedule(ctx, req.(*BackupSchedule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetBackupSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetBackupSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetBackupSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetBackupSchedule(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ResetDistro_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ResetDistro(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ResetDistro_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ResetDistro(ctx, req.(*ResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ResetAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ResetAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ResetAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ResetAgent(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ReloadConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ReloadConfig(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ApplyDistroWSLSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DistroWSLSettings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ApplyDistroWSLSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ApplyDistroWSLSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ApplyDistroWSLSettings(ctx, req.(*DistroWSLSettings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_SetPauseState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseState)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SetPauseState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SetPauseState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SetPauseState(ctx, req.(*PauseState))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetPauseState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetPauseState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetPauseState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetPauseState(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ApplyLandscapeEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LandscapeEndpoint)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ApplyLandscapeEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ApplyLandscapeEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ApplyLandscapeEndpoint(ctx, req.(*LandscapeEndpoint))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_SetNotificationPreference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationPreference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SetNotificationPreference(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SetNotificationPreference_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SetNotificationPreference(ctx, req.(*NotificationPreference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetNotificationPreferences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetNotificationPreferences(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_SetApprovalPreference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApprovalPreference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SetApprovalPreference(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SetApprovalPreference_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SetApprovalPreference(ctx, req.(*ApprovalPreference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetApprovalPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetApprovalPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetApprovalPreferences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetApprovalPreferences(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetPendingApprovals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetPendingApprovals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetPendingApprovals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetPendingApprovals(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_ApproveTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApprovalDecision)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ApproveTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ApproveTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ApproveTask(ctx, req.(*ApprovalDecision))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_DenyTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApprovalDecision)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).DenyTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_DenyTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).DenyTask(ctx, req.(*ApprovalDecision))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_SubmitPluginTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginTaskSubmission)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SubmitPluginTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SubmitPluginTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SubmitPluginTask(ctx, req.(*PluginTaskSubmission))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_CompactDistro_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).CompactDistro(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_CompactDistro_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).CompactDistro(ctx, req.(*CompactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_WakeDistro_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).WakeDistro(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_WakeDistro_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).WakeDistro(ctx, req.(*WakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_StopDistro_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).StopDistro(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_StopDistro_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).StopDistro(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_SetDistroLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DistroLabels)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SetDistroLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SetDistroLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SetDistroLabels(ctx, req.(*DistroLabels))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_SetDistroSparse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SparseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SetDistroSparse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SetDistroSparse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SetDistroSparse(ctx, req.(*SparseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_SetCompactSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompactSchedule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SetCompactSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SetCompactSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SetCompactSchedule(ctx, req.(*CompactSchedule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetCompactSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetCompactSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetCompactSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetCompactSchedule(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetWSLConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetWSLConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetWSLConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetWSLConfig(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_SetWSLConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WSLConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).SetWSLConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_SetWSLConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).SetWSLConfig(ctx, req.(*WSLConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_UpdateWSL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).UpdateWSL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_UpdateWSL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).UpdateWSL(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_CreateOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).CreateOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_CreateOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).CreateOperation(ctx, req.(*OperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UI_GetOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperationID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetOperation(ctx, req.(*OperationID))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedUIServer must be embedded to have forward compatible implementations.
-type UnimplementedUIServer struct {
+func _UI_CancelOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperationID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).CancelOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_CancelOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).CancelOperation(ctx, req.(*OperationID))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedUIServer) ApplyProToken(context.Context, *ProAttachInfo) (*SubscriptionInfo, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ApplyProToken not implemented")
-}
-func (UnimplementedUIServer) ApplyLandscapeConfig(context.Context, *LandscapeConfig) (*LandscapeSource, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ApplyLandscapeConfig not implemented")
-}
-func (UnimplementedUIServer) Ping(context.Context, *Empty) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
-}
-func (UnimplementedUIServer) GetConfigSources(context.Context, *Empty) (*ConfigSources, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetConfigSources not implemented")
+func _UI_ListOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).ListOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_ListOperations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).ListOperations(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedUIServer) NotifyPurchase(context.Context, *Empty) (*SubscriptionInfo, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method NotifyPurchase not implemented")
+
+func _UI_RequestConfirmation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).RequestConfirmation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_RequestConfirmation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).RequestConfirmation(ctx, req.(*ConfirmationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedUIServer) mustEmbedUnimplementedUIServer() {}
 
-// UnsafeUIServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to UIServer will
-// result in compilation errors.
-type UnsafeUIServer interface {
-	mustEmbedUnimplementedUIServer()
+func _UI_GetDistroLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DistroLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).GetDistroLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_GetDistroLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).GetDistroLogs(ctx, req.(*DistroLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterUIServer(s grpc.ServiceRegistrar, srv UIServer) {
-	s.RegisterService(&UI_ServiceDesc, srv)
+func _UI_PullDistroFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DistroFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UIServer).PullDistroFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UI_PullDistroFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UIServer).PullDistroFile(ctx, req.(*DistroFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _UI_ApplyProToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProAttachInfo)
+func _UI_GetDistroTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DistroTasksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UIServer).ApplyProToken(ctx, in)
+		return srv.(UIServer).GetDistroTasks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UI_ApplyProToken_FullMethodName,
+		FullMethod: UI_GetDistroTasks_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UIServer).ApplyProToken(ctx, req.(*ProAttachInfo))
+		return srv.(UIServer).GetDistroTasks(ctx, req.(*DistroTasksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UI_ApplyLandscapeConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LandscapeConfig)
+func _UI_CancelDistroTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UIServer).ApplyLandscapeConfig(ctx, in)
+		return srv.(UIServer).CancelDistroTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UI_ApplyLandscapeConfig_FullMethodName,
+		FullMethod: UI_CancelDistroTask_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UIServer).ApplyLandscapeConfig(ctx, req.(*LandscapeConfig))
+		return srv.(UIServer).CancelDistroTask(ctx, req.(*CancelTaskRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UI_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _UI_GetOnboardingState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UIServer).Ping(ctx, in)
+		return srv.(UIServer).GetOnboardingState(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UI_Ping_FullMethodName,
+		FullMethod: UI_GetOnboardingState_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UIServer).Ping(ctx, req.(*Empty))
+		return srv.(UIServer).GetOnboardingState(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UI_GetConfigSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _UI_SkipLandscapeOnboarding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UIServer).GetConfigSources(ctx, in)
+		return srv.(UIServer).SkipLandscapeOnboarding(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UI_GetConfigSources_FullMethodName,
+		FullMethod: UI_SkipLandscapeOnboarding_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UIServer).GetConfigSources(ctx, req.(*Empty))
+		return srv.(UIServer).SkipLandscapeOnboarding(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UI_NotifyPurchase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _UI_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UIServer).NotifyPurchase(ctx, in)
+		return srv.(UIServer).GetVersion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UI_NotifyPurchase_FullMethodName,
+		FullMethod: UI_GetVersion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UIServer).NotifyPurchase(ctx, req.(*Empty))
+		return srv.(UIServer).GetVersion(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -235,6 +1871,10 @@ var UI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ApplyProToken",
 			Handler:    _UI_ApplyProToken_Handler,
 		},
+		{
+			MethodName: "ApplyDistroProToken",
+			Handler:    _UI_ApplyDistroProToken_Handler,
+		},
 		{
 			MethodName: "ApplyLandscapeConfig",
 			Handler:    _UI_ApplyLandscapeConfig_Handler,
@@ -251,8 +1891,199 @@ var UI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NotifyPurchase",
 			Handler:    _UI_NotifyPurchase_Handler,
 		},
+		{
+			MethodName: "GetSubscriptionDetails",
+			Handler:    _UI_GetSubscriptionDetails_Handler,
+		},
+		{
+			MethodName: "ValidateConfig",
+			Handler:    _UI_ValidateConfig_Handler,
+		},
+		{
+			MethodName: "GetFeatureFlags",
+			Handler:    _UI_GetFeatureFlags_Handler,
+		},
+		{
+			MethodName: "ApplyLandscapeDistroOverride",
+			Handler:    _UI_ApplyLandscapeDistroOverride_Handler,
+		},
+		{
+			MethodName: "GetLandscapeDistroOverrides",
+			Handler:    _UI_GetLandscapeDistroOverrides_Handler,
+		},
+		{
+			MethodName: "GetFleetStatus",
+			Handler:    _UI_GetFleetStatus_Handler,
+		},
+		{
+			MethodName: "SetBackupSchedule",
+			Handler:    _UI_SetBackupSchedule_Handler,
+		},
+		{
+			MethodName: "GetBackupSchedule",
+			Handler:    _UI_GetBackupSchedule_Handler,
+		},
+		{
+			MethodName: "ResetDistro",
+			Handler:    _UI_ResetDistro_Handler,
+		},
+		{
+			MethodName: "ResetAgent",
+			Handler:    _UI_ResetAgent_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _UI_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "ApplyDistroWSLSettings",
+			Handler:    _UI_ApplyDistroWSLSettings_Handler,
+		},
+		{
+			MethodName: "SetPauseState",
+			Handler:    _UI_SetPauseState_Handler,
+		},
+		{
+			MethodName: "GetPauseState",
+			Handler:    _UI_GetPauseState_Handler,
+		},
+		{
+			MethodName: "ApplyLandscapeEndpoint",
+			Handler:    _UI_ApplyLandscapeEndpoint_Handler,
+		},
+		{
+			MethodName: "SetNotificationPreference",
+			Handler:    _UI_SetNotificationPreference_Handler,
+		},
+		{
+			MethodName: "GetNotificationPreferences",
+			Handler:    _UI_GetNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "SetApprovalPreference",
+			Handler:    _UI_SetApprovalPreference_Handler,
+		},
+		{
+			MethodName: "GetApprovalPreferences",
+			Handler:    _UI_GetApprovalPreferences_Handler,
+		},
+		{
+			MethodName: "GetPendingApprovals",
+			Handler:    _UI_GetPendingApprovals_Handler,
+		},
+		{
+			MethodName: "ApproveTask",
+			Handler:    _UI_ApproveTask_Handler,
+		},
+		{
+			MethodName: "DenyTask",
+			Handler:    _UI_DenyTask_Handler,
+		},
+		{
+			MethodName: "SubmitPluginTask",
+			Handler:    _UI_SubmitPluginTask_Handler,
+		},
+		{
+			MethodName: "CompactDistro",
+			Handler:    _UI_CompactDistro_Handler,
+		},
+		{
+			MethodName: "WakeDistro",
+			Handler:    _UI_WakeDistro_Handler,
+		},
+		{
+			MethodName: "StopDistro",
+			Handler:    _UI_StopDistro_Handler,
+		},
+		{
+			MethodName: "SetDistroLabels",
+			Handler:    _UI_SetDistroLabels_Handler,
+		},
+		{
+			MethodName: "SetDistroSparse",
+			Handler:    _UI_SetDistroSparse_Handler,
+		},
+		{
+			MethodName: "SetCompactSchedule",
+			Handler:    _UI_SetCompactSchedule_Handler,
+		},
+		{
+			MethodName: "GetCompactSchedule",
+			Handler:    _UI_GetCompactSchedule_Handler,
+		},
+		{
+			MethodName: "GetWSLConfig",
+			Handler:    _UI_GetWSLConfig_Handler,
+		},
+		{
+			MethodName: "SetWSLConfig",
+			Handler:    _UI_SetWSLConfig_Handler,
+		},
+		{
+			MethodName: "UpdateWSL",
+			Handler:    _UI_UpdateWSL_Handler,
+		},
+		{
+			MethodName: "CreateOperation",
+			Handler:    _UI_CreateOperation_Handler,
+		},
+		{
+			MethodName: "GetOperation",
+			Handler:    _UI_GetOperation_Handler,
+		},
+		{
+			MethodName: "CancelOperation",
+			Handler:    _UI_CancelOperation_Handler,
+		},
+		{
+			MethodName: "ListOperations",
+			Handler:    _UI_ListOperations_Handler,
+		},
+		{
+			MethodName: "RequestConfirmation",
+			Handler:    _UI_RequestConfirmation_Handler,
+		},
+		{
+			MethodName: "GetDistroLogs",
+			Handler:    _UI_GetDistroLogs_Handler,
+		},
+		{
+			MethodName: "PullDistroFile",
+			Handler:    _UI_PullDistroFile_Handler,
+		},
+		{
+			MethodName: "GetDistroTasks",
+			Handler:    _UI_GetDistroTasks_Handler,
+		},
+		{
+			MethodName: "CancelDistroTask",
+			Handler:    _UI_CancelDistroTask_Handler,
+		},
+		{
+			MethodName: "GetOnboardingState",
+			Handler:    _UI_GetOnboardingState_Handler,
+		},
+		{
+			MethodName: "SkipLandscapeOnboarding",
+			Handler:    _UI_SkipLandscapeOnboarding_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _UI_GetVersion_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDistros",
+			Handler:       _UI_WatchDistros_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportDistro",
+			Handler:       _UI_ExportDistro_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "agentapi.proto",
 }
 
@@ -377,3 +2208,93 @@ var WSLInstance_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "agentapi.proto",
 }
+
+const (
+	TaskPlugin_ExecuteTask_FullMethodName = "/agentapi.TaskPlugin/ExecuteTask"
+)
+
+// TaskPluginClient is the client API for TaskPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TaskPluginClient interface {
+	ExecuteTask(ctx context.Context, in *PluginTask, opts ...grpc.CallOption) (*PluginTaskResult, error)
+}
+
+type taskPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaskPluginClient(cc grpc.ClientConnInterface) TaskPluginClient {
+	return &taskPluginClient{cc}
+}
+
+func (c *taskPluginClient) ExecuteTask(ctx context.Context, in *PluginTask, opts ...grpc.CallOption) (*PluginTaskResult, error) {
+	out := new(PluginTaskResult)
+	err := c.cc.Invoke(ctx, TaskPlugin_ExecuteTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TaskPluginServer is the server API for TaskPlugin service.
+// All implementations must embed UnimplementedTaskPluginServer
+// for forward compatibility
+type TaskPluginServer interface {
+	ExecuteTask(context.Context, *PluginTask) (*PluginTaskResult, error)
+	mustEmbedUnimplementedTaskPluginServer()
+}
+
+// UnimplementedTaskPluginServer must be embedded to have forward compatible implementations.
+type UnimplementedTaskPluginServer struct {
+}
+
+func (UnimplementedTaskPluginServer) ExecuteTask(context.Context, *PluginTask) (*PluginTaskResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteTask not implemented")
+}
+func (UnimplementedTaskPluginServer) mustEmbedUnimplementedTaskPluginServer() {}
+
+// UnsafeTaskPluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaskPluginServer will
+// result in compilation errors.
+type UnsafeTaskPluginServer interface {
+	mustEmbedUnimplementedTaskPluginServer()
+}
+
+func RegisterTaskPluginServer(s grpc.ServiceRegistrar, srv TaskPluginServer) {
+	s.RegisterService(&TaskPlugin_ServiceDesc, srv)
+}
+
+func _TaskPlugin_ExecuteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginTask)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskPluginServer).ExecuteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskPlugin_ExecuteTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskPluginServer).ExecuteTask(ctx, req.(*PluginTask))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TaskPlugin_ServiceDesc is the grpc.ServiceDesc for TaskPlugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TaskPlugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "agentapi.TaskPlugin",
+	HandlerType: (*TaskPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExecuteTask",
+			Handler:    _TaskPlugin_ExecuteTask_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "agentapi.proto",
+}
@@ -0,0 +1,5 @@
+package agentapi
+
+// ProtocolVersion is the version of the API served by the agent. It is increased every time a change breaks
+// the compatibility with its clients, so that mismatched components can be told apart.
+const ProtocolVersion = 1
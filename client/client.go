@@ -0,0 +1,313 @@
+// Package client is the supported way for other tools, such as the Landscape client for Windows or the
+// ubuntu-wsl CLI, to talk to the Ubuntu Pro for WSL agent. It wraps the UI service of the agent with typed
+// methods, and takes care of what every client would have to do otherwise:
+//
+//   - finding the running agent through the address it writes in the user profile;
+//   - authenticating with the token of the session of the agent, which it writes in its private directory;
+//   - requesting the confirmation of the destructive calls, which the agent only hands out once the user
+//     approves the call in a prompt it shows;
+//   - retrying the calls while the agent is starting or restarting.
+//
+// The typed methods only cover the calls most tools need. UI is the supported way to make every other call
+// of the UI service, with the same authentication: see its documentation for what it does not handle.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/ubuntu/decorate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultRetries is how many times a call is retried while the agent cannot be reached. With the
+	// default backoff, the calls wait for the agent for about 15 seconds, long enough for it to start.
+	defaultRetries = 5
+
+	// defaultBackoff is how long the first retry waits. The wait doubles with every retry.
+	defaultBackoff = 500 * time.Millisecond
+)
+
+var (
+	// ErrNotRunning is returned when the agent did not write its address, because it is not running.
+	ErrNotRunning = errors.New("the agent is not running")
+
+	// ErrClosed is returned by the calls made after the client is closed.
+	ErrClosed = errors.New("the client is closed")
+)
+
+type options struct {
+	dir      string
+	stateDir string
+	address  string
+	retries  int
+	backoff  time.Duration
+}
+
+// Option is an optional argument for New.
+type Option = func(*options)
+
+// WithDir sets the directory where the agent writes its address. It defaults to the .ubuntupro directory
+// of the user profile.
+func WithDir(dir string) Option {
+	return func(o *options) {
+		o.dir = dir
+	}
+}
+
+// WithStateDir sets the private directory of the agent, where it writes the token of its session. It
+// defaults to the Ubuntu Pro directory of %LocalAppData%, and must be set for portable installs of the
+// agent, which keep their private directory next to their executable.
+func WithStateDir(dir string) Option {
+	return func(o *options) {
+		o.stateDir = dir
+	}
+}
+
+// WithAddress makes the client connect to the agent at this address, instead of the one the agent writes.
+func WithAddress(addr string) Option {
+	return func(o *options) {
+		o.address = addr
+	}
+}
+
+// WithRetries sets how many times a call is retried while the agent cannot be reached, and how long the
+// first retry waits. The wait doubles with every retry. Calls are never retried with zero retries.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = retries
+		o.backoff = backoff
+	}
+}
+
+// Client talks to the UI service of the agent. It is safe for concurrent use.
+type Client struct {
+	dir      string
+	stateDir string
+	address  string
+	retries  int
+	backoff  time.Duration
+
+	// addr is the address conn is connected to. The agent listens on another port every time it starts.
+	addr   string
+	conn   *grpc.ClientConn
+	ui     agentapi.UIClient
+	closed bool
+	mu     sync.Mutex
+}
+
+// New returns a client of the agent. The agent does not need to be running yet: the calls wait for it as
+// long as they are retried.
+//
+// Once done, Close must be called to release the connection.
+func New(args ...Option) (c *Client, err error) {
+	defer decorate.OnError(&err, "could not create agent client")
+
+	opts := options{
+		retries: defaultRetries,
+		backoff: defaultBackoff,
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	if opts.dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("could not find the user profile: %v", err)
+		}
+		opts.dir = filepath.Join(home, common.UserProfileDir)
+	}
+
+	if opts.stateDir == "" {
+		// This is %LocalAppData% on Windows.
+		localAppData, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("could not find the local application data: %v", err)
+		}
+		opts.stateDir = filepath.Join(localAppData, common.LocalAppDataDir)
+	}
+
+	return &Client{
+		dir:      opts.dir,
+		stateDir: opts.stateDir,
+		address:  opts.address,
+		retries:  opts.retries,
+		backoff:  opts.backoff,
+	}, nil
+}
+
+// Close releases the connection to the agent. Calls cannot be made afterwards.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+// UI returns the client of the UI service connected to the agent, to make the calls without a typed
+// method. It is supported as much as the typed methods.
+//
+// The calls made through it are authenticated, but neither confirmed nor retried: a destructive call must
+// be preceded by RequestConfirmation, with the code it returns passed in the outgoing metadata under
+// common.ConfirmationMetadataKey, and a call that fails with codes.Unavailable while the agent restarts
+// must be made again. The client returned may be stale once the agent moves: call UI again before every
+// call rather than keeping it.
+func (c *Client) UI() (agentapi.UIClient, error) {
+	return c.connect()
+}
+
+// connect returns the client of the UI service connected to the current address of the agent. The agent
+// is dialed again if it moved to another address since the last call.
+func (c *Client) connect() (agentapi.UIClient, error) {
+	addr, err := c.agentAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+
+	if c.conn != nil && c.addr == addr {
+		return c.ui, nil
+	}
+
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(tokenCredentials{path: filepath.Join(c.stateDir, common.AuthTokenFileName)}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial agent: %v", err)
+	}
+
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+
+	c.addr = addr
+	c.conn = conn
+	c.ui = agentapi.NewUIClient(conn)
+
+	return c.ui, nil
+}
+
+// agentAddress returns the address the agent listens on.
+func (c *Client) agentAddress() (string, error) {
+	if c.address != "" {
+		return c.address, nil
+	}
+
+	out, err := os.ReadFile(filepath.Join(c.dir, common.ListeningPortFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrNotRunning
+	} else if err != nil {
+		return "", fmt.Errorf("could not read agent address: %v", err)
+	}
+
+	// The agent listens on all interfaces, so only its port is relevant.
+	_, port, err := net.SplitHostPort(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", fmt.Errorf("could not parse agent address: %v", err)
+	}
+
+	return net.JoinHostPort("localhost", port), nil
+}
+
+// call makes the call to the method of the UI service, retrying it while the agent cannot be reached.
+// Destructive calls are confirmed anew for every attempt, as confirmation codes can only be used once.
+func call[T any](ctx context.Context, c *Client, method string, confirm bool, f func(context.Context, agentapi.UIClient) (T, error)) (v T, err error) {
+	defer decorate.OnError(&err, "%s", method)
+
+	backoff := c.backoff
+	for retry := 0; ; retry++ {
+		v, err = attempt(ctx, c, method, confirm, f)
+		if err == nil || retry >= c.retries || !retryable(err) {
+			return v, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return v, err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// attempt makes the call once. Destructive calls block until the user answers the prompt of the agent,
+// and fail with PermissionDenied if they don't approve it.
+func attempt[T any](ctx context.Context, c *Client, method string, confirm bool, f func(context.Context, agentapi.UIClient) (T, error)) (v T, err error) {
+	ui, err := c.connect()
+	if err != nil {
+		return v, err
+	}
+
+	if confirm {
+		conf, err := ui.RequestConfirmation(ctx, &agentapi.ConfirmationRequest{Method: method})
+		if err != nil {
+			return v, fmt.Errorf("could not confirm the call: %w", err)
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, common.ConfirmationMetadataKey, conf.GetCode())
+	}
+
+	return f(ctx, ui)
+}
+
+// retryable returns true if the call failed because the agent could not be reached.
+func retryable(err error) bool {
+	if errors.Is(err, ErrNotRunning) {
+		return true
+	}
+
+	return status.Code(err) == codes.Unavailable
+}
+
+// tokenCredentials sends the token of the session of the agent with every call. It is read anew every
+// time, as the agent generates a new one whenever it starts.
+type tokenCredentials struct {
+	path string
+}
+
+// GetRequestMetadata returns the metadata carrying the token. It is empty without a token file, as the
+// read-only calls can be made without it.
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	out, err := os.ReadFile(t.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read authentication token: %v", err)
+	}
+
+	return map[string]string{common.AuthTokenMetadataKey: strings.TrimSpace(string(out))}, nil
+}
+
+// RequireTransportSecurity returns false, as the agent only listens for local connections.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,269 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/client"
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCalls(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		unavailable int
+		notRunning  bool
+		wrongToken  bool
+
+		wantErr        bool
+		wantNotRunning bool
+	}{
+		"Success": {},
+		"Success retrying while the agent is unavailable": {unavailable: 2},
+
+		"Error when the agent is unavailable for longer than the retries": {unavailable: 10, wantErr: true},
+		"Error when the agent is not running":                             {notRunning: true, wantErr: true, wantNotRunning: true},
+		"Error when the token is not the one of the session":              {wrongToken: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			dir := t.TempDir()
+			agent := &mockAgent{token: "session-token", unavailable: tc.unavailable}
+			if !tc.notRunning {
+				agent.serve(t, dir)
+			}
+
+			if tc.wrongToken {
+				err := os.WriteFile(filepath.Join(dir, common.AuthTokenFileName), []byte("wrong-token"), 0600)
+				require.NoError(t, err, "Setup: could not overwrite the token file")
+			}
+
+			c, err := client.New(client.WithDir(dir), client.WithStateDir(dir), client.WithRetries(3, time.Millisecond))
+			require.NoError(t, err, "New should return no error")
+			defer c.Close()
+
+			err = c.SetPaused(ctx, true)
+			if tc.wantErr {
+				require.Error(t, err, "SetPaused should return an error")
+				require.Equal(t, tc.wantNotRunning, errors.Is(err, client.ErrNotRunning), "Unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err, "SetPaused should return no error")
+
+			paused, err := c.Paused(ctx)
+			require.NoError(t, err, "Paused should return no error")
+			require.True(t, paused, "Paused should report the state set")
+		})
+	}
+}
+
+func TestConfirmedCalls(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	agent := &mockAgent{token: "session-token"}
+	agent.serve(t, dir)
+
+	c, err := client.New(client.WithDir(dir), client.WithStateDir(dir), client.WithRetries(0, 0))
+	require.NoError(t, err, "New should return no error")
+	defer c.Close()
+
+	info, err := c.ApplyProToken(ctx, "pro-token")
+	require.NoError(t, err, "ApplyProToken should return no error")
+	require.NotNil(t, info.GetUser(), "ApplyProToken should return the subscription in use")
+
+	_, err = c.ApplyProToken(ctx, "pro-token")
+	require.NoError(t, err, "ApplyProToken should be confirmed anew every time")
+
+	ui, err := c.UI()
+	require.NoError(t, err, "UI should return no error")
+
+	_, err = ui.ApplyProToken(ctx, &agentapi.ProAttachInfo{Token: "pro-token"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), "Calls made through UI should not be confirmed")
+}
+
+func TestAgentRestart(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	first := &mockAgent{token: "first-token"}
+	stop := first.serve(t, dir)
+
+	c, err := client.New(client.WithDir(dir), client.WithStateDir(dir), client.WithRetries(5, 10*time.Millisecond))
+	require.NoError(t, err, "New should return no error")
+	defer c.Close()
+
+	require.NoError(t, c.Ping(ctx), "Ping should return no error")
+
+	// The agent comes back on another port with another token.
+	stop()
+	second := &mockAgent{token: "second-token"}
+	second.serve(t, dir)
+
+	err = c.SetPaused(ctx, true)
+	require.NoError(t, err, "SetPaused should reach the restarted agent")
+
+	require.True(t, second.isPaused(), "SetPaused should have reached the restarted agent")
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	agent := &mockAgent{token: "session-token"}
+	agent.serve(t, dir)
+
+	c, err := client.New(client.WithDir(dir), client.WithStateDir(dir))
+	require.NoError(t, err, "New should return no error")
+
+	require.NoError(t, c.Ping(context.Background()), "Ping should return no error")
+	require.NoError(t, c.Close(), "Close should return no error")
+
+	err = c.Ping(context.Background())
+	require.ErrorIs(t, err, client.ErrClosed, "Calls should fail once the client is closed")
+}
+
+// mockAgent serves the subset of the UI service used in these tests, checking the token and the
+// confirmations the way the agent does.
+type mockAgent struct {
+	agentapi.UnimplementedUIServer
+
+	token string
+
+	// unavailable is how many calls fail because the agent is not ready yet.
+	unavailable int
+
+	paused        bool
+	confirmations map[string]string
+	mu            sync.Mutex
+}
+
+// serve starts the agent and writes its address and token to dir. It returns a function to stop it.
+func (a *mockAgent) serve(t *testing.T, dir string) (stop func()) {
+	t.Helper()
+
+	a.confirmations = make(map[string]string)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err, "Setup: could not listen")
+
+	err = os.WriteFile(filepath.Join(dir, common.ListeningPortFileName), []byte(lis.Addr().String()), 0600)
+	require.NoError(t, err, "Setup: could not write the address file")
+
+	err = os.WriteFile(filepath.Join(dir, common.AuthTokenFileName), []byte(a.token), 0600)
+	require.NoError(t, err, "Setup: could not write the token file")
+
+	server := grpc.NewServer()
+	agentapi.RegisterUIServer(server, a)
+
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return server.Stop
+}
+
+func (a *mockAgent) isPaused() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.paused
+}
+
+// check fails the call if the agent is not ready yet, or if the call does not carry the token.
+func (a *mockAgent) check(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.unavailable > 0 {
+		a.unavailable--
+		return status.Error(codes.Unavailable, "the agent is starting")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if tokens := md.Get(common.AuthTokenMetadataKey); len(tokens) != 1 || tokens[0] != a.token {
+		return status.Error(codes.Unauthenticated, "invalid authentication token")
+	}
+
+	return nil
+}
+
+func (a *mockAgent) Ping(ctx context.Context, _ *agentapi.Empty) (*agentapi.Empty, error) {
+	return &agentapi.Empty{}, nil
+}
+
+func (a *mockAgent) GetPauseState(ctx context.Context, _ *agentapi.Empty) (*agentapi.PauseState, error) {
+	if err := a.check(ctx); err != nil {
+		return nil, err
+	}
+
+	return &agentapi.PauseState{Paused: a.isPaused()}, nil
+}
+
+func (a *mockAgent) SetPauseState(ctx context.Context, msg *agentapi.PauseState) (*agentapi.Empty, error) {
+	if err := a.check(ctx); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.paused = msg.GetPaused()
+	return &agentapi.Empty{}, nil
+}
+
+func (a *mockAgent) RequestConfirmation(ctx context.Context, msg *agentapi.ConfirmationRequest) (*agentapi.Confirmation, error) {
+	if err := a.check(ctx); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	code := msg.GetMethod() + "-" + time.Now().Format(time.RFC3339Nano)
+	a.confirmations[code] = msg.GetMethod()
+
+	return &agentapi.Confirmation{Code: code}, nil
+}
+
+func (a *mockAgent) ApplyProToken(ctx context.Context, msg *agentapi.ProAttachInfo) (*agentapi.SubscriptionInfo, error) {
+	if err := a.check(ctx); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	got := md.Get(common.ConfirmationMetadataKey)
+	if len(got) != 1 || a.confirmations[got[0]] != "ApplyProToken" {
+		return nil, status.Error(codes.PermissionDenied, "ApplyProToken must be confirmed")
+	}
+	delete(a.confirmations, got[0])
+
+	return &agentapi.SubscriptionInfo{SubscriptionType: &agentapi.SubscriptionInfo_User{User: &agentapi.Empty{}}}, nil
+}
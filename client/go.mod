@@ -0,0 +1,32 @@
+module github.com/canonical/ubuntu-pro-for-wsl/client
+
+go 1.22.0
+
+toolchain go1.22.1
+
+require (
+	github.com/canonical/ubuntu-pro-for-wsl/agentapi v0.0.0-20240306140056-b2552aec01d2
+	github.com/canonical/ubuntu-pro-for-wsl/common v0.0.0-20240306140056-b2552aec01d2
+	github.com/stretchr/testify v1.9.0
+	github.com/ubuntu/decorate v0.0.0-20230905131025-e968fa48a85c
+	google.golang.org/grpc v1.62.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/golang/protobuf v1.5.4 // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	golang.org/x/net v0.21.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240123012728-ef4313101c80 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The client calls the RPCs of the agent API in this tree, which no published version of these modules
+// contains yet: it is built and released together with them.
+replace (
+	github.com/canonical/ubuntu-pro-for-wsl/agentapi => ../agentapi
+	github.com/canonical/ubuntu-pro-for-wsl/common => ../common
+)
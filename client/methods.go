@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+)
+
+// Ping returns an error if the agent cannot be reached.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := call(ctx, c, "Ping", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.Empty, error) {
+		return ui.Ping(ctx, &agentapi.Empty{})
+	})
+	return err
+}
+
+// Version returns how the agent was built and the versions of the APIs it speaks.
+func (c *Client) Version(ctx context.Context) (*agentapi.VersionInfo, error) {
+	return call(ctx, c, "GetVersion", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.VersionInfo, error) {
+		return ui.GetVersion(ctx, &agentapi.Empty{})
+	})
+}
+
+// ApplyProToken replaces the Ubuntu Pro subscription of the machine with the token. An empty token
+// removes the subscription. It returns the subscription in use afterwards.
+func (c *Client) ApplyProToken(ctx context.Context, token string) (*agentapi.SubscriptionInfo, error) {
+	return call(ctx, c, "ApplyProToken", true, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.SubscriptionInfo, error) {
+		return ui.ApplyProToken(ctx, &agentapi.ProAttachInfo{Token: token})
+	})
+}
+
+// SubscriptionDetails returns the subscription in use, and every source it can come from.
+func (c *Client) SubscriptionDetails(ctx context.Context) (*agentapi.SubscriptionDetails, error) {
+	return call(ctx, c, "GetSubscriptionDetails", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.SubscriptionDetails, error) {
+		return ui.GetSubscriptionDetails(ctx, &agentapi.Empty{})
+	})
+}
+
+// ApplyLandscapeConfig replaces the Landscape configuration of the machine. An empty configuration
+// removes it. It returns where the configuration in use afterwards comes from.
+func (c *Client) ApplyLandscapeConfig(ctx context.Context, config string) (*agentapi.LandscapeSource, error) {
+	return call(ctx, c, "ApplyLandscapeConfig", true, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.LandscapeSource, error) {
+		return ui.ApplyLandscapeConfig(ctx, &agentapi.LandscapeConfig{Config: config})
+	})
+}
+
+// ConfigSources returns where the subscription and the Landscape configuration in use come from.
+func (c *Client) ConfigSources(ctx context.Context) (*agentapi.ConfigSources, error) {
+	return call(ctx, c, "GetConfigSources", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.ConfigSources, error) {
+		return ui.GetConfigSources(ctx, &agentapi.Empty{})
+	})
+}
+
+// FleetStatus returns the status of every distro managed by the agent.
+func (c *Client) FleetStatus(ctx context.Context) (*agentapi.FleetStatus, error) {
+	return call(ctx, c, "GetFleetStatus", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.FleetStatus, error) {
+		return ui.GetFleetStatus(ctx, &agentapi.Empty{})
+	})
+}
+
+// WatchDistros streams the changes made to the distros managed by the agent. The stream is not
+// restarted if the agent restarts.
+func (c *Client) WatchDistros(ctx context.Context) (agentapi.UI_WatchDistrosClient, error) {
+	return call(ctx, c, "WatchDistros", false, func(ctx context.Context, ui agentapi.UIClient) (agentapi.UI_WatchDistrosClient, error) {
+		return ui.WatchDistros(ctx, &agentapi.Empty{})
+	})
+}
+
+// WakeDistro starts the distro and keeps it awake for the given minutes, or until StopDistro with zero.
+func (c *Client) WakeDistro(ctx context.Context, distroName string, keepAwakeMinutes uint32) error {
+	_, err := call(ctx, c, "WakeDistro", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.Empty, error) {
+		return ui.WakeDistro(ctx, &agentapi.WakeRequest{DistroName: distroName, KeepAwakeMinutes: keepAwakeMinutes})
+	})
+	return err
+}
+
+// StopDistro releases a distro kept awake by WakeDistro, so that it shuts down once idle.
+func (c *Client) StopDistro(ctx context.Context, distroName string) error {
+	_, err := call(ctx, c, "StopDistro", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.Empty, error) {
+		return ui.StopDistro(ctx, &agentapi.StopRequest{DistroName: distroName})
+	})
+	return err
+}
+
+// Paused returns true if the automatic behaviour of the agent is paused.
+func (c *Client) Paused(ctx context.Context) (bool, error) {
+	state, err := call(ctx, c, "GetPauseState", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.PauseState, error) {
+		return ui.GetPauseState(ctx, &agentapi.Empty{})
+	})
+	return state.GetPaused(), err
+}
+
+// SetPaused pauses or resumes the automatic behaviour of the agent. While paused, no distro is woken
+// up, no task runs and Landscape commands are held back.
+func (c *Client) SetPaused(ctx context.Context, paused bool) error {
+	_, err := call(ctx, c, "SetPauseState", false, func(ctx context.Context, ui agentapi.UIClient) (*agentapi.Empty, error) {
+		return ui.SetPauseState(ctx, &agentapi.PauseState{Paused: paused})
+	})
+	return err
+}
@@ -0,0 +1,56 @@
+// Package buildinfo describes how the components of Ubuntu Pro for WSL were built, so that mismatched
+// components can be told apart when troubleshooting.
+package buildinfo
+
+import "runtime/debug"
+
+// Info describes the build of a component.
+type Info struct {
+	// Version is the semantic version of the component.
+	Version string
+
+	// Commit is the git commit it was built from. It is empty if unknown.
+	Commit string
+
+	// BuildDate is when it was built, in RFC 3339 format. It is empty if unknown.
+	BuildDate string
+}
+
+// New returns the build info of the running binary. The commit and the date that were not set at build
+// time are taken from the version control info embedded by Go, in which case the date is the one of the
+// commit. Builds from a modified tree have their commit suffixed with "-dirty".
+func New(version, commit, buildDate string) Info {
+	info := Info{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	var revision, modified string
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		case "vcs.time":
+			if info.BuildDate == "" {
+				info.BuildDate = s.Value
+			}
+		}
+	}
+
+	if info.Commit == "" && revision != "" {
+		info.Commit = revision
+		if modified == "true" {
+			info.Commit += "-dirty"
+		}
+	}
+
+	return info
+}
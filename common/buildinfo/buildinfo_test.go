@@ -0,0 +1,36 @@
+package buildinfo_test
+
+import (
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/buildinfo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		commit    string
+		buildDate string
+	}{
+		"Success with the values set at build time": {commit: "abc123", buildDate: "2024-03-01T10:00:00Z"},
+		"Success without values set at build time":  {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildinfo.New("1.2.3", tc.commit, tc.buildDate)
+
+			require.Equal(t, "1.2.3", got.Version, "New should keep the version")
+			if tc.commit != "" {
+				require.Equal(t, tc.commit, got.Commit, "New should keep the commit set at build time")
+			}
+			if tc.buildDate != "" {
+				require.Equal(t, tc.buildDate, got.BuildDate, "New should keep the date set at build time")
+			}
+		})
+	}
+}
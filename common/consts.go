@@ -16,6 +16,26 @@ const (
 	// ListeningPortFileName corresponds to the base name of the file hosting the addressing of our GRPC server.
 	ListeningPortFileName = ".address"
 
+	// HvsocketPortFileName corresponds to the base name of the file hosting the vsock port on which our GRPC server
+	// also listens via a Hyper-V socket. It only exists while such a socket is available.
+	HvsocketPortFileName = ".address.hvsocket"
+
+	// TelemetryFileName corresponds to the base name of the file whose presence tells the distros that the
+	// Telemetry feature flag is enabled in the agent, so that they export their traces as well.
+	TelemetryFileName = ".telemetry"
+
+	// AuthTokenFileName corresponds to the base name of the file hosting the token that clients of the UI service
+	// must send with every call. It is generated anew on each run of the agent, and written to its private directory
+	// (under LocalAppDataDir by default) so that it is only readable by the user.
+	AuthTokenFileName = ".auth"
+
+	// AuthTokenMetadataKey is the gRPC metadata key under which clients of the UI service send the token.
+	AuthTokenMetadataKey = "x-ubuntu-pro-token"
+
+	// ConfirmationMetadataKey is the gRPC metadata key under which clients of the UI service send the
+	// confirmation code of a destructive call.
+	ConfirmationMetadataKey = "x-ubuntu-pro-confirmation"
+
 	// MsStoreProductID is the ID of the product in the Microsoft Store
 	//
 	// TODO: Replace with real product ID.
@@ -0,0 +1,180 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+// OverflowPolicy decides what happens to a log when the log buffer is full.
+type OverflowPolicy int
+
+const (
+	// DropNewest discards the incoming log.
+	DropNewest OverflowPolicy = iota
+
+	// DropOldest discards the oldest buffered log to make room for the incoming one.
+	DropOldest
+
+	// Coalesce merges the incoming log with the last buffered one when they have the same level
+	// and message, even if the buffer is not full. Otherwise, the incoming log is discarded.
+	Coalesce
+)
+
+// logEntry is a log waiting in a buffer.
+type logEntry struct {
+	level  logrus.Level
+	caller string
+	msg    string
+
+	// repeats is how many identical logs were coalesced into this one.
+	repeats int
+}
+
+// text returns the message of the entry, mentioning the coalesced logs if any.
+func (e logEntry) text() string {
+	if e.repeats == 0 {
+		return e.msg
+	}
+	return fmt.Sprintf(i18n.G("%s (repeated %d times)"), e.msg, e.repeats+1)
+}
+
+// logBuffer is a bounded queue of logs. Pushing never blocks: when the buffer is full, the overflow
+// policy decides which logs are discarded.
+type logBuffer struct {
+	entries []logEntry
+	size    int
+	policy  OverflowPolicy
+
+	// dropped is the number of logs discarded since the last time the buffer was drained.
+	dropped int
+	closed  bool
+
+	mu   sync.Mutex
+	cond *sync.Cond
+}
+
+func newLogBuffer(size int, policy OverflowPolicy) *logBuffer {
+	b := &logBuffer{
+		size:   size,
+		policy: policy,
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// push adds a log to the buffer. Logs pushed after the buffer is closed are discarded.
+func (b *logBuffer) push(e logEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	defer b.cond.Signal()
+
+	if b.policy == Coalesce && len(b.entries) > 0 {
+		last := &b.entries[len(b.entries)-1]
+		if last.level == e.level && last.msg == e.msg {
+			last.repeats++
+			return
+		}
+	}
+
+	if len(b.entries) < b.size {
+		b.entries = append(b.entries, e)
+		return
+	}
+
+	b.dropped++
+	if b.policy == DropOldest {
+		b.entries = append(b.entries[1:], e)
+	}
+}
+
+// close stops accepting logs. drain returns once the remaining logs are processed.
+func (b *logBuffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.cond.Broadcast()
+}
+
+// drain calls process on every log, in order, until the buffer is closed and empty.
+// A warning mentioning how many logs were discarded is processed before the remaining logs.
+func (b *logBuffer) drain(process func(logEntry)) {
+	for {
+		b.mu.Lock()
+		for len(b.entries) == 0 && b.dropped == 0 && !b.closed {
+			b.cond.Wait()
+		}
+
+		var e logEntry
+		switch {
+		case b.dropped > 0:
+			e = logEntry{
+				level: logrus.WarnLevel,
+				msg:   fmt.Sprintf(i18n.G("%d log messages were dropped"), b.dropped),
+			}
+			b.dropped = 0
+		case len(b.entries) > 0:
+			e = b.entries[0]
+			b.entries = b.entries[1:]
+		default:
+			// Closed and empty.
+			b.mu.Unlock()
+			return
+		}
+		b.mu.Unlock()
+
+		process(e)
+	}
+}
+
+// prioritySender serializes the messages sent on a stream, giving priority to the messages of
+// the RPC over the logs.
+type prioritySender struct {
+	// waiting is the number of RPC messages waiting to be sent.
+	waiting int
+	sending bool
+
+	mu   sync.Mutex
+	cond *sync.Cond
+}
+
+func newPrioritySender() *prioritySender {
+	s := &prioritySender{}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// send calls f once no other message is being sent and, if it is not a priority message,
+// no priority message is waiting.
+func (s *prioritySender) send(priority bool, f func() error) error {
+	s.mu.Lock()
+	if priority {
+		s.waiting++
+		for s.sending {
+			s.cond.Wait()
+		}
+		s.waiting--
+	} else {
+		for s.sending || s.waiting > 0 {
+			s.cond.Wait()
+		}
+	}
+	s.sending = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.sending = false
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	}()
+
+	return f()
+}
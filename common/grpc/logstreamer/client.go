@@ -15,9 +15,12 @@ import (
 
 type opts struct {
 	clientID string
+
+	bufferSize     int
+	overflowPolicy OverflowPolicy
 }
 
-// Option is an optional argument for the StreamClientInterceptor.
+// Option is an optional argument for the StreamClientInterceptor and the StreamServerInterceptor.
 type Option func(*opts)
 
 // WithClientID is an optional argument to override the default client ID.
@@ -27,6 +30,18 @@ func WithClientID(clientID string) Option {
 	}
 }
 
+// WithLogBuffer buffers up to size logs, which are then sent (on the server) or printed (on the client)
+// from a separate goroutine, so that verbose logs never hold back the messages of the stream itself.
+// When the buffer is full, the policy decides which logs are discarded.
+//
+// By default, logs are sent or printed synchronously.
+func WithLogBuffer(size int, policy OverflowPolicy) Option {
+	return func(o *opts) {
+		o.bufferSize = size
+		o.overflowPolicy = policy
+	}
+}
+
 // StreamClientInterceptor allows to tag the client with an unique ID and request the server
 // to stream back to the client logs corresponding to that request to the given logger.
 // It will use ReportCaller value from logger to decide if we print the callstack (first frame outside
@@ -49,22 +64,40 @@ func StreamClientInterceptor(logger *logrus.Logger, args ...Option) grpc.StreamC
 			clientIDKey, o.clientID,
 			clientWantCallerKey, reportCallerMsg)
 		clientStream, err := streamer(ctx, desc, cc, method, opts...)
-		return &logClientStream{
+		ss := &logClientStream{
 			ClientStream: clientStream,
 			logger:       logger,
-		}, err
+		}
+
+		if err == nil && o.bufferSize > 0 {
+			ss.buffer = newLogBuffer(o.bufferSize, o.overflowPolicy)
+			go ss.buffer.drain(func(e logEntry) { ss.print(e.level, e.caller, e.text()) })
+			go func() {
+				// The buffer is also closed when the stream ends, but the caller may not read until then.
+				<-ctx.Done()
+				ss.buffer.close()
+			}()
+		}
+
+		return ss, err
 	}
 }
 
 type logClientStream struct {
 	grpc.ClientStream
 	logger *logrus.Logger
+
+	// buffer holds the logs waiting to be printed. It is nil when logs are printed synchronously.
+	buffer *logBuffer
 }
 
 // RecvMsg is used to intercept log messages from server before hitting the client.
 func (ss *logClientStream) RecvMsg(m interface{}) error {
 	for {
 		if err := ss.ClientStream.RecvMsg(m); err != nil {
+			if ss.buffer != nil {
+				ss.buffer.close()
+			}
 			return err
 		}
 
@@ -91,21 +124,11 @@ func (ss *logClientStream) RecvMsg(m interface{}) error {
 				return fmt.Errorf("client received an invalid debug log level: %s", logMsg.GetLevel())
 			}
 
-			localLoggerMu.Lock()
-			reportCaller := ss.logger.ReportCaller
-			ss.logger.SetReportCaller(false)
-			// We are controlling and unwrapping the caller ourself outside of this package.
-			// As logrus doesn't allow to specify which package to exclude manually, do it there.
-			// https://github.com/sirupsen/logrus/issues/867
-			msg := logMsg.GetMsg()
-			caller := logMsg.GetCaller()
-			if reportCaller && caller != "" {
-				msg = fmt.Sprintf(logFormatWithCaller, caller, msg)
+			if ss.buffer != nil {
+				ss.buffer.push(logEntry{level: level, caller: logMsg.GetCaller(), msg: logMsg.GetMsg()})
+			} else {
+				ss.print(level, logMsg.GetCaller(), logMsg.GetMsg())
 			}
-			ss.logger.Log(level, msg)
-			// Restore if we use direct calls
-			ss.logger.SetReportCaller(reportCaller)
-			localLoggerMu.Unlock()
 
 			// this message doesn’t concern the client, treat next one
 			continue
@@ -115,3 +138,21 @@ func (ss *logClientStream) RecvMsg(m interface{}) error {
 		return nil
 	}
 }
+
+// print logs a message received from the server with the local logger.
+func (ss *logClientStream) print(level logrus.Level, caller, msg string) {
+	localLoggerMu.Lock()
+	defer localLoggerMu.Unlock()
+
+	reportCaller := ss.logger.ReportCaller
+	ss.logger.SetReportCaller(false)
+	// We are controlling and unwrapping the caller ourself outside of this package.
+	// As logrus doesn't allow to specify which package to exclude manually, do it there.
+	// https://github.com/sirupsen/logrus/issues/867
+	if reportCaller && caller != "" {
+		msg = fmt.Sprintf(logFormatWithCaller, caller, msg)
+	}
+	ss.logger.Log(level, msg)
+	// Restore if we use direct calls
+	ss.logger.SetReportCaller(reportCaller)
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer/test"
@@ -275,3 +278,62 @@ func captureLogs(t *testing.T, logger *logrus.Logger) (out func() string) {
 		return buf.String()
 	}
 }
+
+func TestRecvLogMsgWithLogBuffer(t *testing.T) {
+	t.Parallel()
+
+	var out syncBuffer
+	logger := logrus.New()
+	logger.SetOutput(&out)
+	logger.SetLevel(logrus.DebugLevel)
+
+	var logMsgs []*log.Log
+	for _, msg := range []string{"first", "second", "third"} {
+		logMsgs = append(logMsgs, &log.Log{
+			LogHeader: log.LogIdentifier,
+			Level:     logrus.InfoLevel.String(),
+			Msg:       msg,
+		})
+	}
+
+	s := &clientStream{
+		logCalls:       logMsgs,
+		wantErrRecvMsg: io.EOF,
+	}
+
+	streamCreation := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return s, nil
+	}
+
+	c, err := log.StreamClientInterceptor(logger, log.WithLogBuffer(10, log.DropNewest))(context.Background(), nil, nil, "method", streamCreation)
+	require.NoError(t, err, "StreamClient Interceptor should return no error")
+
+	err = c.RecvMsg(&test.EmptyLogTest{})
+	require.ErrorIs(t, err, io.EOF, "RecvMsg should return the error of the stream")
+
+	require.Eventually(t, func() bool { return strings.Count(out.String(), "\n") == len(logMsgs) }, 5*time.Second, 10*time.Millisecond,
+		"All logs should be printed. Got:\n%s", out.String())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	for i, l := range logMsgs {
+		require.Contains(t, lines[i], fmt.Sprintf("msg=%s", l.GetMsg()), "Logs should be printed in order")
+	}
+}
+
+// syncBuffer is a bytes.Buffer that is safe to use concurrently.
+type syncBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -6,3 +6,20 @@ const (
 	ClientIDKey         = clientIDKey
 	ClientWantCallerKey = clientWantCallerKey
 )
+
+type PrioritySender = prioritySender
+
+var NewPrioritySender = newPrioritySender
+
+// Send is a wrapper around send so as to make it accessible to tests.
+func (s *PrioritySender) Send(priority bool, f func() error) error {
+	return s.send(priority, f)
+}
+
+// Waiting returns the number of priority messages waiting to be sent.
+func (s *PrioritySender) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.waiting
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
 	"github.com/sirupsen/logrus"
 )
@@ -151,7 +152,8 @@ func WithoutRemoteSend(ctx context.Context) context.Context {
 }
 
 func log(ctx context.Context, level logrus.Level, args ...interface{}) {
-	msg := fmt.Sprint(args...)
+	// Secrets are hidden before the message reaches any destination, local or remote.
+	msg := common.Redact(fmt.Sprint(args...))
 
 	var callerForRemote bool
 	var sendStream sendStreamFn
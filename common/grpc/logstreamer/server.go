@@ -27,7 +27,14 @@ type logContext struct {
 // It will use serverLogger to log locally the same messages, prefixing by the request ID.
 // It will use ReportCaller value from localLogger to decide if we print the callstack (first frame outside
 // of that package).
-func StreamServerInterceptor(localLogger *logrus.Logger) func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+//
+// The messages of the stream always take precedence over the logs sent on it.
+func StreamServerInterceptor(localLogger *logrus.Logger, args ...Option) func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	var o opts
+	for _, f := range args {
+		f(&o)
+	}
+
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		clientID, withCaller, err := extractMetaFromContext(ss.Context())
 		if err != nil {
@@ -36,6 +43,7 @@ func StreamServerInterceptor(localLogger *logrus.Logger) func(srv interface{}, s
 
 		ssLogs := serverStreamWithLogs{
 			ServerStream: ss,
+			sender:       newPrioritySender(),
 		}
 
 		// create and log request ID
@@ -45,10 +53,39 @@ func StreamServerInterceptor(localLogger *logrus.Logger) func(srv interface{}, s
 		}
 		Infof(context.Background(), i18n.G("New connection from client [[%s]]"), idRequest)
 
+		sendStream := ssLogs.sendLogs
+		if o.bufferSize > 0 {
+			buffer := newLogBuffer(o.bufferSize, o.overflowPolicy)
+			sendStream = func(logLevel, caller, msg string) error {
+				level, err := logrus.ParseLevel(logLevel)
+				if err != nil {
+					return err
+				}
+				buffer.push(logEntry{level: level, caller: caller, msg: msg})
+				return nil
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				buffer.drain(func(e logEntry) {
+					if err := ssLogs.sendLogs(e.level.String(), e.caller, e.text()); err != nil {
+						localLogger.Warningf(localLogFormatWithID, idRequest, i18n.G("couldn't send logs to client"))
+					}
+				})
+			}()
+
+			// Flush the remaining logs before the stream is closed.
+			defer func() {
+				buffer.close()
+				<-done
+			}()
+		}
+
 		// attach stream logger options to context so that we can log locally and remotely from context
 		ssLogs.ctx = context.WithValue(ss.Context(), logContextKey, logContext{
 			idRequest:           idRequest,
-			sendStream:          ssLogs.sendLogs,
+			sendStream:          sendStream,
 			withCallerForRemote: withCaller,
 			localLogger:         localLogger,
 		})
@@ -60,22 +97,31 @@ func StreamServerInterceptor(localLogger *logrus.Logger) func(srv interface{}, s
 type serverStreamWithLogs struct {
 	grpc.ServerStream
 	ctx context.Context
+
+	sender *prioritySender
 }
 
 func (ss serverStreamWithLogs) Context() context.Context {
 	return ss.ctx
 }
 
+// SendMsg sends a message of the stream, with priority over the logs.
+func (ss serverStreamWithLogs) SendMsg(m interface{}) error {
+	return ss.sender.send(true, func() error { return ss.ServerStream.SendMsg(m) })
+}
+
 // sendLogs sends directly to the stream a Log message with dedicated entries.
 // This will be intercepted by the StreamClientInterceptor for every Log message matching
 // its structure, preventing to hit the client.
 // A harcoded header is set to double check and ensure we have Log message.
 func (ss serverStreamWithLogs) sendLogs(logLevel, caller, msg string) error {
-	return ss.SendMsg(&Log{
-		LogHeader: logIdentifier,
-		Level:     logLevel,
-		Caller:    caller,
-		Msg:       msg,
+	return ss.sender.send(false, func() error {
+		return ss.ServerStream.SendMsg(&Log{
+			LogHeader: logIdentifier,
+			Level:     logLevel,
+			Caller:    caller,
+			Msg:       msg,
+		})
 	})
 }
 
@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/sirupsen/logrus"
@@ -201,3 +205,148 @@ func createLogStream(t *testing.T, level logrus.Level, callerForLocal, callerFor
 		return strings.Join(out, "\n")
 	}
 }
+
+func TestStreamServerInterceptorWithLogBuffer(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		policy log.OverflowPolicy
+		msgs   []string
+
+		want []string
+	}{
+		"Buffered logs are sent in order": {policy: log.DropNewest, msgs: []string{"second", "third"}, want: []string{"second", "third"}},
+
+		"DropNewest discards the incoming logs":        {policy: log.DropNewest, msgs: []string{"2", "3", "4", "5", "6"}, want: []string{"3 log messages were dropped", "2", "3"}},
+		"DropOldest discards the oldest buffered logs": {policy: log.DropOldest, msgs: []string{"2", "3", "4", "5", "6"}, want: []string{"3 log messages were dropped", "5", "6"}},
+		"Coalesce merges identical consecutive logs":   {policy: log.Coalesce, msgs: []string{"same", "same", "same", "other"}, want: []string{"same (repeated 3 times)", "other"}},
+		"Coalesce discards different logs when full":   {policy: log.Coalesce, msgs: []string{"2", "3", "4"}, want: []string{"1 log messages were dropped", "2", "3"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			stream := &blockingStream{
+				ctx:     addMetaToContext(context.Background(), false),
+				release: make(chan struct{}),
+			}
+
+			handler := func(srv interface{}, s grpc.ServerStream) error {
+				// The first buffered log blocks the stream, so that the next ones pile up in the buffer.
+				log.Info(s.Context(), "first")
+				require.Eventually(t, func() bool { return len(stream.logs()) == 2 }, 5*time.Second, 10*time.Millisecond,
+					"Setup: the first log should have been sent")
+
+				for _, msg := range tc.msgs {
+					log.Info(s.Context(), msg)
+				}
+
+				close(stream.release)
+				return nil
+			}
+
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			err := log.StreamServerInterceptor(logger, log.WithLogBuffer(2, tc.policy))(struct{}{}, stream, nil, handler)
+			require.NoError(t, err, "StreamServerInterceptor returned an error when expecting none")
+
+			got := stream.logs()
+			require.Len(t, got, len(tc.want)+2, "All logs should have been flushed when the stream ends. Got: %q", got)
+			require.Contains(t, got[0], "Connecting as", "The connection log should be sent first")
+			require.Equal(t, "first", got[1], "The first log should be sent before the others")
+			require.Equal(t, tc.want, got[2:], "Mismatch in the buffered logs")
+		})
+	}
+}
+
+// blockingStream is a stream that records the messages it sends. Sending the second message
+// blocks until release is closed.
+type blockingStream struct {
+	grpc.ServerStream
+	ctx context.Context
+
+	release chan struct{}
+
+	msgs []string
+	mu   sync.Mutex
+}
+
+func (s *blockingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *blockingStream) SendMsg(m interface{}) error {
+	l, ok := m.(*log.Log)
+	if !ok {
+		return fmt.Errorf("expected a log, but got %+v", m)
+	}
+
+	s.mu.Lock()
+	s.msgs = append(s.msgs, l.GetMsg())
+	n := len(s.msgs)
+	s.mu.Unlock()
+
+	if n == 2 {
+		<-s.release
+	}
+	return nil
+}
+
+func (s *blockingStream) logs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.msgs)
+}
+
+func TestPrioritySender(t *testing.T) {
+	t.Parallel()
+
+	s := log.NewPrioritySender()
+
+	var order []string
+	var mu sync.Mutex
+	record := func(msg string) func() error {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, msg)
+			return nil
+		}
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		_ = s.Send(false, func() error {
+			close(started)
+			<-release
+			return record("first log")()
+		})
+	}()
+	<-started
+
+	go func() {
+		defer wg.Done()
+		_ = s.Send(false, record("second log"))
+	}()
+
+	go func() {
+		defer wg.Done()
+		_ = s.Send(true, record("message"))
+	}()
+
+	require.Eventually(t, func() bool { return s.Waiting() == 1 }, 5*time.Second, 10*time.Millisecond,
+		"Setup: the message should be waiting to be sent")
+
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, []string{"first log", "message", "second log"}, order, "Messages should be sent before the waiting logs")
+}
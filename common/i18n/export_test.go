@@ -14,8 +14,9 @@ func WithLoc(loc string) func(l *i18n) {
 	}
 }
 
-// ResetGlobals resets G and GN to their empty func.
+// ResetGlobals resets G and GN to their empty func, and forgets the locale.
 func ResetGlobals() {
+	locale = i18n{}
 	G = func(msgid string) string { return msgid }
 	NG = func(msgid string, msgidPlural string, n uint32) string { return msgid }
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/snapcore/go-gettext"
 )
@@ -22,6 +23,9 @@ type i18n struct {
 var (
 	locale i18n
 
+	// localeMu protects the locale, which can be changed at any time via SetLocale.
+	localeMu sync.RWMutex
+
 	// G is the shorthand for Gettext.
 	G = func(msgid string) string { return msgid }
 	// NG is the shorthand for NGettext.
@@ -45,8 +49,29 @@ func InitI18nDomain(domain string, args ...Option) {
 	locale.bindTextDomain(locale.domain, locale.localeDir)
 	locale.setLocale(locale.loc)
 
-	G = locale.Gettext
-	NG = locale.NGettext
+	G = func(msgid string) string {
+		localeMu.RLock()
+		defer localeMu.RUnlock()
+		return locale.Gettext(msgid)
+	}
+	NG = func(msgid string, msgidPlural string, n uint32) string {
+		localeMu.RLock()
+		defer localeMu.RUnlock()
+		return locale.NGettext(msgid, msgidPlural, n)
+	}
+}
+
+// SetLocale changes the locale of the translations, such as "de_DE" or "de-DE". An empty locale
+// resets it to the system one. It has no effect unless InitI18nDomain was called first.
+func SetLocale(loc string) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+
+	if locale.domain == "" {
+		return
+	}
+
+	locale.setLocale(loc)
 }
 
 // langpackResolver tries to fetch locale mo file path.
@@ -95,6 +120,8 @@ func (l *i18n) setLocale(loc string) {
 	// de_DE.UTF-8, de_DE@euro all need to get simplified
 	loc = strings.Split(loc, "@")[0]
 	loc = strings.Split(loc, ".")[0]
+	// Windows language names use dashes: de-DE
+	loc = strings.ReplaceAll(loc, "-", "_")
 
 	l.Catalog = l.translations.Locale(loc)
 }
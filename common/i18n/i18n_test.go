@@ -65,6 +65,7 @@ func TestTranslations(t *testing.T) {
 		lang       string
 		domain     string
 		loc        string // loc can be set to "-" to ensure it's empty
+		setLocale  string // setLocale is passed to SetLocale after the initialization
 
 		rename map[string]string
 		noinit bool
@@ -75,6 +76,7 @@ func TestTranslations(t *testing.T) {
 		// Locale preferences
 		"en_DK@ is en_DK": {loc: defaultLoc + "@foo"},
 		"en_DK. is en_DK": {loc: defaultLoc + ".foo"},
+		"en-DK is en_DK":  {loc: "en-DK"},
 		"Fallback to en if en_DK isn't present": {
 			want:   "secondary translated singular",
 			rename: map[string]string{filepath.Join(defaultLocaleDir, "en_DK"): filepath.Join(defaultLocaleDir, "other")},
@@ -94,6 +96,11 @@ func TestTranslations(t *testing.T) {
 		"Missing domain":           {domain: "doesntexists", want: "singular"},
 		"Invalid locale directory": {localeDir: "/doesntexists", want: "singular"},
 		"Init wasn't ran":          {noinit: true, want: "singular"},
+
+		// Runtime locale changes
+		"SetLocale changes the locale":             {setLocale: secondaryLoc, want: "secondary translated singular"},
+		"SetLocale accepts Windows language names": {loc: secondaryLoc, setLocale: "en-DK"},
+		"SetLocale has no effect without init":     {noinit: true, setLocale: secondaryLoc, want: "singular"},
 	}
 
 	for name, tc := range tests {
@@ -139,6 +146,9 @@ func TestTranslations(t *testing.T) {
 			if !tc.noinit {
 				i18n.InitI18nDomain(tc.domain, i18n.WithLocaleDir(tc.localeDir), i18n.WithLoc(tc.loc))
 			}
+			if tc.setLocale != "" {
+				i18n.SetLocale(tc.setLocale)
+			}
 			switch len(tc.text) {
 			case 1:
 				assert.Equal(t, tc.want, i18n.G(tc.text[0]))
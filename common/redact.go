@@ -0,0 +1,51 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// proTokenRegex matches Ubuntu Pro tokens, which are alphanumeric strings starting with a C.
+	proTokenRegex = regexp.MustCompile(`\bC[0-9A-Za-z]{19,39}\b`)
+
+	// jwtRegex matches JSON web tokens, such as the ones issued by the Microsoft Store. Their header
+	// is base64-encoded JSON, so it always starts with "eyJ".
+	jwtRegex = regexp.MustCompile(`\beyJ[0-9A-Za-z_-]+\.[0-9A-Za-z_-]+\.[0-9A-Za-z_-]*`)
+
+	// secretFieldRegex matches the value of the fields that hold secrets, such as the registration key in a
+	// Landscape client configuration. Fields are matched in INI form, or in JSON and protobuf text form when
+	// the value is quoted. An escaped newline before the field name is accepted, as in a configuration
+	// formatted with %q.
+	secretFieldRegex = regexp.MustCompile(`(?i)(^|[^0-9A-Za-z_]|\\n)((?:registration_key|ubuntu_?pro_?token|pro_?token|token)(?:[ \t]*=[ \t]*"?|"?:[ \t]*"))([^\s"',\\]+)`)
+)
+
+// IsProToken returns true if the string has the shape of an Ubuntu Pro token. It does not tell
+// whether the token is valid for the contract server.
+func IsProToken(s string) bool {
+	loc := proTokenRegex.FindStringIndex(s)
+	if loc == nil || loc[0] != 0 || loc[1] != len(s) {
+		return false
+	}
+	return strings.ContainsAny(s, "0123456789")
+}
+
+// Redact hides the secrets found in the contents, so that they can be logged or shared: Ubuntu Pro tokens,
+// Landscape registration keys and JSON web tokens. Secrets are obfuscated rather than removed, so that their
+// author can still recognize them.
+func Redact(contents string) string {
+	contents = jwtRegex.ReplaceAllStringFunc(contents, Obfuscate)
+
+	contents = proTokenRegex.ReplaceAllStringFunc(contents, func(match string) string {
+		// Identifiers such as "ConfigurationManager" are not tokens: a token always contains digits.
+		if !strings.ContainsAny(match, "0123456789") {
+			return match
+		}
+		return Obfuscate(match)
+	})
+
+	return secretFieldRegex.ReplaceAllStringFunc(contents, func(match string) string {
+		m := secretFieldRegex.FindStringSubmatch(match)
+		return m[1] + m[2] + Obfuscate(m[3])
+	})
+}
@@ -0,0 +1,4 @@
+package telemetry
+
+// Install allows tests to export spans somewhere other than an OTLP endpoint.
+var Install = install
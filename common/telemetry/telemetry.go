@@ -0,0 +1,88 @@
+// Package telemetry sets up OpenTelemetry tracing, so that requests can be followed across the
+// agent and the distros: from task submission, to waking up the distro, to the gRPC calls, down to
+// the commands run inside the distro.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// instrumentationName is the name of the tracer used by every component of the project.
+const instrumentationName = "github.com/canonical/ubuntu-pro-for-wsl"
+
+// Setup installs a global tracer provider that exports spans via gRPC to the OTLP endpoint,
+// which must be a URL such as http://localhost:4317.
+//
+// If the endpoint is empty, no spans are exported, but the trace context is still propagated
+// across gRPC calls so that other components can export theirs.
+//
+// The returned function flushes any pending span and stops exporting. It must be called before exiting.
+func Setup(ctx context.Context, serviceName, endpoint string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("could not create OTLP exporter for %q: %v", endpoint, err)
+	}
+
+	return install(serviceName, exporter), nil
+}
+
+// install sets the global tracer provider to one that sends all spans to the exporter.
+func install(serviceName string, exporter sdktrace.SpanExporter) (shutdown func(context.Context) error) {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown
+}
+
+// Start creates a span and a context containing it. The span must be ended by calling End.
+// It is a no-op unless Setup was called with an endpoint.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span, recording the error if there is one. Secrets are redacted from the error, as it
+// is exported along with the span.
+// It is meant to be deferred with a pointer to a named error return value.
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		msg := common.Redact((*err).Error())
+		span.RecordError(errors.New(msg))
+		span.SetStatus(codes.Error, msg)
+	}
+	span.End()
+}
+
+// ServerOption returns a gRPC server option to trace incoming calls and propagate their trace context.
+func ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+// DialOption returns a gRPC dial option to trace outgoing calls and propagate their trace context.
+func DialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
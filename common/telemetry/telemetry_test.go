@@ -0,0 +1,123 @@
+package telemetry_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestSetup(t *testing.T) {
+	// Not parallel because we modify the global tracer provider
+
+	testCases := map[string]struct {
+		endpoint string
+	}{
+		"Success without an endpoint": {},
+		"Success with an endpoint":    {endpoint: "http://localhost:4317"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			shutdown, err := telemetry.Setup(ctx, "test-service", tc.endpoint)
+			require.NoError(t, err, "Setup should return no error")
+			require.NotNil(t, shutdown, "Setup should return a shutdown function")
+
+			require.NoError(t, shutdown(ctx), "Shutdown should return no error")
+		})
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	// Not parallel because we modify the global tracer provider
+
+	ctx := context.Background()
+
+	_, err := telemetry.Setup(ctx, "test-service", "")
+	require.NoError(t, err, "Setup: could not set up propagation")
+
+	exporter := keepSpansExporter{tracetest.NewInMemoryExporter()}
+	shutdown := telemetry.Install("test-service", exporter)
+
+	var lc net.ListenConfig
+	lis, err := lc.Listen(ctx, "tcp", "localhost:0")
+	require.NoError(t, err, "Setup: could not listen")
+
+	server := grpc.NewServer(telemetry.ServerOption())
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()), telemetry.DialOption())
+	require.NoError(t, err, "Setup: could not dial server")
+	defer conn.Close()
+
+	func() {
+		ctx, span := telemetry.Start(ctx, "parent")
+		var err error
+		defer telemetry.End(span, &err)
+
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err, "Setup: health check should succeed")
+	}()
+
+	require.NoError(t, shutdown(ctx), "Shutdown should return no error")
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3, "There should be one span for the parent, the client, and the server")
+
+	traceID := spans[0].SpanContext.TraceID()
+	for _, s := range spans {
+		require.Equal(t, traceID, s.SpanContext.TraceID(), "All spans should belong to the same trace, including the server one")
+	}
+}
+
+func TestEndRedactsErrors(t *testing.T) {
+	// Not parallel because we modify the global tracer provider
+
+	ctx := context.Background()
+
+	exporter := keepSpansExporter{tracetest.NewInMemoryExporter()}
+	shutdown := telemetry.Install("test-service", exporter)
+
+	const secret = "C123456789012345678901"
+	func() {
+		_, span := telemetry.Start(ctx, "failing")
+		err := fmt.Errorf("could not attach token %s", secret)
+		telemetry.End(span, &err)
+	}()
+
+	require.NoError(t, shutdown(ctx), "Shutdown should return no error")
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1, "There should be one span")
+
+	require.Equal(t, codes.Error, spans[0].Status.Code, "The span should be marked as failed")
+	require.NotContains(t, spans[0].Status.Description, secret, "The status of the span should not contain the secret")
+	require.NotEmpty(t, spans[0].Events, "The error should be recorded in the span")
+	for _, e := range spans[0].Events {
+		for _, a := range e.Attributes {
+			require.NotContains(t, a.Value.Emit(), secret, "The recorded error should not contain the secret")
+		}
+	}
+}
+
+// keepSpansExporter is an in-memory exporter that does not forget the spans when it is shut down.
+type keepSpansExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keepSpansExporter) Shutdown(context.Context) error {
+	return nil
+}
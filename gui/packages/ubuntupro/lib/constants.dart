@@ -4,6 +4,16 @@ const kDefaultHost = '127.0.0.1';
 /// The name of the file where the Agent's drop its service connection information.
 const kAddrFileName = '.ubuntupro/.address';
 
+/// The name of the file where the Agent drops the token clients must send to be allowed to call it.
+/// It is in the private directory of the Agent, under the local application data, which the distros are not handed.
+const kAuthTokenFileName = 'Ubuntu Pro/.auth';
+
+/// The gRPC metadata key under which the authentication token is sent to the Agent.
+const kAuthTokenMetadataKey = 'x-ubuntu-pro-token';
+
+/// The gRPC metadata key under which the confirmation code of a destructive call is sent to the Agent.
+const kConfirmationMetadataKey = 'x-ubuntu-pro-confirmation';
+
 /// The default border margin.
 const kDefaultMargin = 32.0;
 
@@ -2,16 +2,34 @@ import 'package:agentapi/agentapi.dart';
 import 'package:grpc/grpc.dart';
 import 'package:meta/meta.dart';
 
+import '/constants.dart';
+import 'agent_api_paths.dart';
+
 /// Type aliases for the gRPC message enums which by default have big names.
 typedef SubscriptionType = SubscriptionInfo_SubscriptionType;
 typedef LandscapeSourceType = LandscapeSource_LandscapeSourceType;
 
+/// Creates a UIClient that sends the authentication token of the current agent session with every call.
+/// The token is read anew each time, as the agent generates a new one whenever it restarts.
+UIClient authenticatedUIClient(ClientChannel channel) {
+  final path = agentStateFilePath(kAuthTokenFileName);
+  final token = path == null ? null : readAgentAuthToken(path);
+  if (token == null) {
+    return UIClient(channel);
+  }
+
+  return UIClient(
+    channel,
+    options: CallOptions(metadata: {kAuthTokenMetadataKey: token}),
+  );
+}
+
 /// AgentApiClient hides the gRPC details in a more convenient API.
 class AgentApiClient {
   AgentApiClient({
     required String host,
     required int port,
-    this.stubFactory = UIClient.new,
+    this.stubFactory = authenticatedUIClient,
   }) : _channel = ClientChannel(
           host,
           port: port,
@@ -23,7 +41,7 @@ class AgentApiClient {
   }
 
   /// A factory for UIClient and derived classes objects, only meaningful for testing.
-  /// In production it should always default to [UIClient.new].
+  /// In production it should always default to [authenticatedUIClient].
   @visibleForTesting
   final UIClient Function(ClientChannel) stubFactory;
 
@@ -46,16 +64,35 @@ class AgentApiClient {
   }
 
   /// Dispatches a applyProToken request with the supplied Pro [token].
-  Future<SubscriptionInfo> applyProToken(String token) {
+  /// The call replaces the subscription of the machine, so it's confirmed on behalf of the user who applied it.
+  Future<SubscriptionInfo> applyProToken(String token) async {
     final info = ProAttachInfo();
     info.token = token;
-    return _client.applyProToken(info);
+    return _client.applyProToken(
+      info,
+      options: await _confirm('ApplyProToken'),
+    );
   }
 
-  Future<LandscapeSource> applyLandscapeConfig(String config) {
+  /// The call replaces the Landscape configuration of the machine, so it's confirmed on behalf of the user who applied it.
+  Future<LandscapeSource> applyLandscapeConfig(String config) async {
     final request = LandscapeConfig();
     request.config = config;
-    return _client.applyLandscapeConfig(request);
+    return _client.applyLandscapeConfig(
+      request,
+      options: await _confirm('ApplyLandscapeConfig'),
+    );
+  }
+
+  /// Requests the confirmation code the agent requires for the destructive call named [method].
+  /// The agent asks the user to approve the call in a prompt of its own, so this completes once they answer.
+  Future<CallOptions> _confirm(String method) async {
+    final confirmation = await _client.requestConfirmation(
+      ConfirmationRequest()..method = method,
+    );
+    return CallOptions(
+      metadata: {kConfirmationMetadataKey: confirmation.code},
+    );
   }
 
   /// Attempts to ping the Agent Service at the supplied endpoint
@@ -72,6 +109,29 @@ class AgentApiClient {
   /// It's expected that an updated SubscriptionInfo will be returned.
   Future<SubscriptionInfo> notifyPurchase() => _client.notifyPurchase(Empty());
 
+  /// Streams the changes made to the distros managed by the agent, so that their status can be fetched again only when needed.
+  Stream<DistroChange> watchDistros() => _client.watchDistros(Empty());
+
+  /// Fetches the most recent logs of the WSL Pro service of the distro named [distroName], starting it if needed.
+  Future<DistroLogs> distroLogs(String distroName, {int lines = 0}) =>
+      _client.getDistroLogs(
+        DistroLogsRequest(distroName: distroName, lines: lines),
+      );
+
+  /// Copies the file at [path] in the distro named [distroName] to the Windows path [destination], starting the distro if needed.
+  Future<DistroFile> pullDistroFile(
+    String distroName,
+    String path,
+    String destination,
+  ) =>
+      _client.pullDistroFile(
+        DistroFileRequest(
+          distroName: distroName,
+          path: path,
+          destination: destination,
+        ),
+      );
+
   Stream<ConnectionEvent> get onConnectionChanged =>
       mapGRPCConnectionEvents(_channel.onConnectionStateChanged);
 }
@@ -17,6 +17,18 @@ String? agentAddrFilePath(String filename) {
   return null;
 }
 
+/// Provides the full path of the "[filename]" file
+/// under the local application data, where the Windows Agent keeps its private directory.
+/// Returns null if that directory location cannot be determined from the environment.
+String? agentStateFilePath(String filename) {
+  final localAppData = Environment.instance['LOCALAPPDATA'];
+  if (localAppData != null) {
+    return p.join(localAppData, filename);
+  }
+
+  return null;
+}
+
 enum AgentAddrFileError { nonexistent, isEmpty, formatError, accessDenied }
 
 /// Reads the agent port from the addr file located at the full path [filepath].
@@ -52,3 +64,14 @@ Future<Either<AgentAddrFileError, int>> readAgentPortFromFile(
 
 /// Parses [line] assuming it's from Windows Agent addr file. Returns null on error.
 int? readAgentPortFromLine(String line) => int.tryParse(line.split(':').last);
+
+/// Reads the authentication token the agent wrote to the file located at the full path [filepath].
+/// Returns null if the file cannot be read or is empty.
+String? readAgentAuthToken(String filepath) {
+  try {
+    final token = File(filepath).readAsStringSync().trim();
+    return token.isEmpty ? null : token;
+  } on FileSystemException catch (_) {
+    return null;
+  }
+}
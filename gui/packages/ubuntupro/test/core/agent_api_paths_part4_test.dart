@@ -15,4 +15,10 @@ void main() {
 
     expect(dir, isNull);
   });
+
+  test('no private directory without local app data', () {
+    final path = agentStateFilePath('Ubuntu Pro/.auth');
+
+    expect(path, isNull);
+  });
 }
@@ -5,7 +5,10 @@ import 'package:flutter_test/flutter_test.dart';
 import 'package:ubuntupro/core/agent_api_paths.dart';
 
 void main() {
-  tearDownAll(() => File('./.address').deleteSync());
+  tearDownAll(() {
+    File('./.address').deleteSync();
+    File('./.auth').deleteSync();
+  });
 
   test('read port from line', () {
     const port = 56768;
@@ -88,4 +91,28 @@ void main() {
 
     expect(res, const Left(AgentAddrFileError.formatError));
   });
+
+  test('read auth token', () {
+    const filePath = './.auth';
+    File(filePath).writeAsStringSync('0123abcd\n');
+
+    final res = readAgentAuthToken(filePath);
+
+    expect(res, '0123abcd');
+  });
+
+  test('empty auth token', () {
+    const filePath = './.auth';
+    File(filePath).writeAsStringSync('');
+
+    final res = readAgentAuthToken(filePath);
+
+    expect(res, isNull);
+  });
+
+  test('missing auth token', () {
+    final res = readAgentAuthToken('./.nonexistent-auth');
+
+    expect(res, isNull);
+  });
 }
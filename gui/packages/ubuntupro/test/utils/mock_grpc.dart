@@ -69,6 +69,14 @@ class MockUIClient extends UIClient {
     return MockedResponse(subs);
   }
 
+  @override
+  ResponseFuture<Confirmation> requestConfirmation(
+    ConfirmationRequest request, {
+    CallOptions? options,
+  }) {
+    return MockedResponse(Confirmation(code: 'code-for-${request.method}'));
+  }
+
   @override
   ResponseFuture<ConfigSources> getConfigSources(
     Empty request, {
@@ -5,17 +5,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
 	"runtime"
+	"sync"
+	"sync/atomic"
 
-	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/daemon"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/hoststate"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/paths"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/uiauth"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/simulation"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/statedir"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -38,11 +47,35 @@ type App struct {
 
 	daemon *daemon.Daemon
 
-	ready chan struct{}
+	// endStreams ends the streams that would keep the daemon from stopping gracefully.
+	endStreams func(context.Context)
+
+	// quitting is true once Quit is called, so that no daemon starts after a reset.
+	quitting bool
+	mu       sync.Mutex
+
+	// stopSimulation disconnects the simulated distros, if any.
+	stopSimulation context.CancelFunc
+
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	// baseLogLevel is the log level set from the command line, which the config file can override.
+	baseLogLevel logrus.Level
 }
 
 type daemonConfig struct {
 	Verbosity int
+
+	// OTLPEndpoint is the URL of the OpenTelemetry collector traces are exported to. Empty disables the export.
+	// It is ignored unless the Telemetry feature flag is enabled.
+	OTLPEndpoint string `mapstructure:"otlp_endpoint"`
+
+	// SimulateDistros is how many simulated distros to register and connect to the agent. Zero disables the simulation.
+	SimulateDistros int `mapstructure:"simulate_distros"`
+
+	// RegistryRoot is the path under HK_CURRENT_USER read instead of the Ubuntu Pro key. Empty keeps the default one.
+	RegistryRoot string `mapstructure:"registry_root"`
 }
 
 type options struct {
@@ -53,11 +86,20 @@ type options struct {
 	privateDir string
 
 	registry registrywatcher.Registry
+
+	// prompter asks the user to approve destructive calls. Nil shows the prompt of the agent.
+	prompter uiauth.Prompter
 }
 
 type option func(*options)
 
 // New registers commands and return a new App.
+//
+// The options of the agent are taken, from highest to lowest precedence, from:
+//  1. the command-line flags;
+//  2. the UP4W_* environment variables, e.g. UP4W_STATE_DIR for --state-dir;
+//  3. the registry and the config file, for the settings they hold;
+//  4. the defaults.
 func New(o ...option) *App {
 	a := App{ready: make(chan struct{})}
 	a.rootCmd = cobra.Command{
@@ -71,15 +113,12 @@ func New(o ...option) *App {
 			// command parsing has been successful. Returns to not print usage anymore.
 			a.rootCmd.SilenceUsage = true
 
-			// Parse environment veriables
-			a.viper.SetEnvPrefix("UP4W")
-			a.viper.AutomaticEnv()
-
 			if err := a.viper.Unmarshal(&a.config); err != nil {
 				return fmt.Errorf("unable to decode configuration into struct: %w", err)
 			}
 
 			setVerboseMode(a.config.Verbosity)
+			a.baseLogLevel = logrus.GetLevel()
 			log.Debug(context.Background(), "Debug mode is enabled")
 
 			return nil
@@ -92,55 +131,186 @@ func New(o ...option) *App {
 	}
 	a.viper = viper.New()
 
+	// Parse environment variables. They are read as soon as the App is created, as the log directory
+	// is needed before the flags are parsed.
+	a.viper.SetEnvPrefix("UP4W")
+	a.viper.AutomaticEnv()
+
 	installVerbosityFlag(&a.rootCmd, a.viper)
+	installOTLPEndpointFlag(&a.rootCmd, a.viper)
+	installSimulateDistrosFlag(&a.rootCmd, a.viper)
+	installDirFlags(&a.rootCmd, a.viper)
+	installRegistryRootFlag(&a.rootCmd, a.viper)
 
 	// subcommands
 	a.installVersion()
+	a.installConfig(o...)
+	a.installPause(o...)
+	a.installDB(o...)
+	a.installToken(o...)
 
 	return &a
 }
 
 // serve creates new GRPC services and listen on a TCP socket. This call is blocking until we quit it.
 func (a *App) serve(args ...option) error {
-	ctx := context.TODO()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
 
 	var opt options
 	for _, f := range args {
 		f(&opt)
 	}
 
-	publicDir, err := a.publicDir(opt)
+	p, err := a.paths(opt)
 	if err != nil {
-		close(a.ready)
+		a.markReady()
 		return err
 	}
 
-	log.Debugf(ctx, "Agent public directory: %s", publicDir)
+	log.Debugf(ctx, "Agent public directory: %s", p.Public)
+	log.Debugf(ctx, "Agent private directory: %s", p.State)
 
-	privateDir, err := a.privateDir(opt)
-	if err != nil {
-		close(a.ready)
-		return err
+	var simulated []string
+	if n := a.config.SimulateDistros; n > 0 {
+		simulated = simulation.Names(n)
+		if ctx, err = simulation.Register(ctx, simulated); err != nil {
+			a.markReady()
+			return err
+		}
+		log.Warningf(ctx, "Running with %d simulated distros: WSL is not used", n)
+	}
+
+	// A reset of the agent stops the services, wipes their state and starts them over.
+	for {
+		reset, err := a.serveServices(ctx, p, opt, simulated)
+		if err != nil || !reset {
+			return err
+		}
+
+		if err := statedir.Wipe(ctx, p.State); err != nil {
+			return err
+		}
+
+		log.Info(ctx, "The agent was reset: starting over with a clean state")
 	}
+}
+
+// serveServices creates the GRPC services and serves them until the agent quits or is reset.
+// It returns true if the agent was reset.
+func (a *App) serveServices(ctx context.Context, p paths.Paths, opt options, simulated []string) (reset bool, err error) {
+	defer a.markReady()
 
-	log.Debugf(ctx, "Agent private directory: %s", privateDir)
+	// A verbosity set explicitly takes precedence over the log level in the config file.
+	logLevel := proservices.WithBaseLogLevel(a.baseLogLevel)
+	if a.viper.IsSet("verbosity") {
+		logLevel = proservices.WithExplicitLogLevel(a.baseLogLevel)
+	}
+
+	reg, err := a.registry(opt)
+	if err != nil {
+		return false, err
+	}
 
 	proservice, err := proservices.New(ctx,
-		publicDir,
-		privateDir,
-		proservices.WithRegistry(opt.registry),
+		p,
+		proservices.WithRegistry(reg),
+		proservices.WithPrompter(opt.prompter),
+		logLevel,
 	)
 	if err != nil {
-		close(a.ready)
-		return err
+		return false, err
 	}
 	defer proservice.Stop(ctx)
 
-	a.daemon = daemon.New(ctx, proservice.RegisterGRPCServices, publicDir)
+	shutdownTelemetry, err := a.setupTelemetry(ctx, p, proservice.FeatureEnabled(ctx, features.Telemetry))
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := shutdownTelemetry(context.Background()); err != nil {
+			log.Warningf(ctx, "Could not flush traces: %v", err)
+		}
+	}()
 
-	close(a.ready)
+	d := daemon.New(ctx, proservice.RegisterGRPCServices, p,
+		daemon.WithHvsocket(proservice.FeatureEnabled(ctx, features.NewTransport)))
+
+	a.mu.Lock()
+	if a.quitting {
+		a.mu.Unlock()
+		return false, nil
+	}
+	a.daemon = d
+	a.endStreams = proservice.EndStreams
+	a.mu.Unlock()
+
+	if len(simulated) > 0 && a.stopSimulation == nil {
+		var simCtx context.Context
+		simCtx, a.stopSimulation = context.WithCancel(ctx)
+		go simulation.Run(simCtx, p.PortFile(), simulated)
+	}
+
+	// VPNs and other network changes can leave the connections of the distros stale without either side
+	// noticing until they time out, so the distros are prompted to reconnect right away.
+	netCtx, stopNetWatch := context.WithCancel(ctx)
+	defer stopNetWatch()
+	go hoststate.WatchNetwork(netCtx, func(ctx context.Context) {
+		log.Info(ctx, "The network changed: prompting the distros to reconnect")
+		proservice.DropDistroConnections(ctx)
+		d.RefreshPortFiles(ctx)
+	})
+
+	// The daemon stops once the reset call returned, so that the client gets its answer.
+	var wasReset atomic.Bool
+	go func() {
+		select {
+		case <-netCtx.Done():
+			return
+		case <-proservice.ResetRequested():
+		}
+
+		wasReset.Store(true)
+		proservice.EndStreams(ctx)
+		d.Quit(ctx, false)
+	}()
+
+	a.markReady()
+
+	if err := d.Serve(ctx); err != nil {
+		return false, err
+	}
 
-	return a.daemon.Serve(ctx)
+	return wasReset.Load(), nil
+}
+
+// setupTelemetry sets up the export of traces if the Telemetry feature flag is enabled, and tells the distros
+// whether to export theirs. Otherwise, traces are not exported even if an OTLP endpoint is set, so that
+// telemetry stays opt-in.
+func (a *App) setupTelemetry(ctx context.Context, p paths.Paths, enabled bool) (shutdown func(context.Context) error, err error) {
+	endpoint := a.config.OTLPEndpoint
+	if !enabled {
+		if endpoint != "" {
+			log.Warningf(ctx, "Not exporting traces to %s: the Telemetry feature flag is disabled", endpoint)
+		}
+		endpoint = ""
+	}
+
+	if enabled {
+		err = os.WriteFile(p.TelemetryFile(), nil, 0600)
+	} else if err = os.Remove(p.TelemetryFile()); errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	if err != nil {
+		log.Warningf(ctx, "Could not tell the distros whether telemetry is enabled: %v", err)
+	}
+
+	return telemetry.Setup(ctx, cmdName(), endpoint)
+}
+
+// markReady signals that the daemon is ready, or that it failed to start.
+func (a *App) markReady() {
+	a.readyOnce.Do(func() { close(a.ready) })
 }
 
 // installVerbosityFlag adds the -v and -vv options and returns the reference to it.
@@ -150,6 +320,34 @@ func installVerbosityFlag(cmd *cobra.Command, viper *viper.Viper) *int {
 	return r
 }
 
+// installOTLPEndpointFlag adds the --otlp-endpoint option, which can also be set via the UP4W_OTLP_ENDPOINT environment variable.
+func installOTLPEndpointFlag(cmd *cobra.Command, viper *viper.Viper) {
+	cmd.Flags().String("otlp-endpoint", "", i18n.G("export traces to this OpenTelemetry collector (e.g. http://localhost:4317)"))
+	decorate.LogOnError(viper.BindPFlag("otlp_endpoint", cmd.Flags().Lookup("otlp-endpoint")))
+}
+
+// installSimulateDistrosFlag adds the --simulate-distros option, which can also be set via the UP4W_SIMULATE_DISTROS environment variable.
+func installSimulateDistrosFlag(cmd *cobra.Command, viper *viper.Viper) {
+	cmd.Flags().Int("simulate-distros", 0, i18n.G("register this many simulated distros instead of using WSL (requires a build with the gowslmock tag)"))
+	decorate.LogOnError(viper.BindPFlag("simulate_distros", cmd.Flags().Lookup("simulate-distros")))
+}
+
+// installDirFlags adds the --public-dir and --state-dir options, which can also be set via the UP4W_PUBLIC_DIR and
+// UP4W_STATE_DIR environment variables. They are persistent, as the subcommands also act on the state of the agent.
+func installDirFlags(cmd *cobra.Command, viper *viper.Viper) {
+	cmd.PersistentFlags().String("public-dir", "", i18n.G("directory where the files shared with the GUI and the distros go, instead of the user profile"))
+	decorate.LogOnError(viper.BindPFlag("public_dir", cmd.PersistentFlags().Lookup("public-dir")))
+
+	cmd.PersistentFlags().String("state-dir", "", i18n.G("directory where the state of the agent goes, instead of the local app data"))
+	decorate.LogOnError(viper.BindPFlag("state_dir", cmd.PersistentFlags().Lookup("state-dir")))
+}
+
+// installRegistryRootFlag adds the --registry-root option, which can also be set via the UP4W_REGISTRY_ROOT environment variable.
+func installRegistryRootFlag(cmd *cobra.Command, viper *viper.Viper) {
+	cmd.PersistentFlags().String("registry-root", "", i18n.G(`registry key under HKEY_CURRENT_USER read instead of Software\Canonical\UbuntuPro (e.g. Software\Canonical\UbuntuProQA)`))
+	decorate.LogOnError(viper.BindPFlag("registry_root", cmd.PersistentFlags().Lookup("registry-root")))
+}
+
 // SetVerboseMode change ErrorFormat and logs between very, middly and non verbose.
 func setVerboseMode(level int) {
 	var reportCaller bool
@@ -180,10 +378,25 @@ func (a App) UsageError() bool {
 // Quit gracefully shutdown the service.
 func (a *App) Quit() {
 	a.WaitReady()
-	if a.daemon == nil {
+
+	a.mu.Lock()
+	a.quitting = true
+	d := a.daemon
+	endStreams := a.endStreams
+	a.mu.Unlock()
+
+	if d == nil {
 		return
 	}
-	a.daemon.Quit(context.Background(), false)
+
+	endStreams(context.Background())
+
+	// The simulated distros never hang up by themselves, so the daemon would wait for them forever.
+	if a.stopSimulation != nil {
+		a.stopSimulation()
+	}
+
+	d.Quit(context.Background(), false)
 }
 
 // WaitReady signals when the daemon is ready
@@ -204,42 +417,53 @@ func (a *App) SetArgs(args ...string) {
 
 // PublicDir creates a directory to store public data in.
 func (a *App) PublicDir() (string, error) {
-	// This wrapper is used to have a cleaner public API.
-	return a.publicDir(options{})
+	p, err := a.paths(options{})
+	if err != nil {
+		return "", err
+	}
+	return p.Public, nil
 }
 
-// publicDir is a wrapper around PublicDir to allow overriding its path with an option.
-func (a *App) publicDir(opts options) (string, error) {
-	if opts.publicDir == "" {
-		homeDir := os.Getenv("UserProfile")
-		if homeDir == "" {
-			return "", errors.New("could not create public dir: %UserProfile% is not set")
-		}
-
-		opts.publicDir = filepath.Join(homeDir, common.UserProfileDir)
+// LogDir creates a directory to store the log files in.
+func (a *App) LogDir() (string, error) {
+	p, err := a.paths(options{})
+	if err != nil {
+		return "", err
 	}
+	return p.Log, nil
+}
 
-	if err := os.MkdirAll(opts.publicDir, 0600); err != nil {
-		return "", fmt.Errorf("could not create public dir %s: %v", opts.publicDir, err)
+// paths resolves the directories of the agent, with the option of overriding them. The directories set on the
+// command line or in the environment are read from viper directly, as the log directory is resolved before the
+// configuration is decoded. The flags are not parsed yet at that point, so only the environment applies to it.
+func (a *App) paths(opts options) (paths.Paths, error) {
+	publicDir, stateDir := opts.publicDir, opts.privateDir
+	if a.viper != nil {
+		if publicDir == "" {
+			publicDir = a.viper.GetString("public_dir")
+		}
+		if stateDir == "" {
+			stateDir = a.viper.GetString("state_dir")
+		}
 	}
 
-	return opts.publicDir, nil
+	return paths.Resolve(
+		paths.WithPublicDir(publicDir),
+		paths.WithStateDir(stateDir),
+	)
 }
 
-// privateDir creates a directory to store private data in, with the option of overriding the path.
-func (a *App) privateDir(opts options) (string, error) {
-	if opts.privateDir == "" {
-		localAppData := os.Getenv("LocalAppData")
-		if localAppData == "" {
-			return "", errors.New("could not create private dir: %LocalAppData% is not set")
-		}
-
-		opts.privateDir = filepath.Join(localAppData, common.LocalAppDataDir)
+// registry returns the registry the organization settings are read from, with the Ubuntu Pro key moved to
+// the root set on the command line or in the environment, if any. A root outside the Software key is an error.
+func (a *App) registry(opts options) (registrywatcher.Registry, error) {
+	reg := opts.registry
+	if reg == nil {
+		reg = registry.Windows{}
 	}
 
-	if err := os.MkdirAll(opts.privateDir, 0600); err != nil {
-		return "", fmt.Errorf("could not create private dir %s: %v", opts.privateDir, err)
+	if root := a.config.RegistryRoot; root != "" {
+		return registrywatcher.WithRoot(reg, root)
 	}
 
-	return opts.privateDir, nil
+	return reg, nil
 }
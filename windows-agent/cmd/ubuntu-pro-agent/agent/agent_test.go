@@ -2,6 +2,7 @@ package agent_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -10,10 +11,13 @@ import (
 	"testing"
 	"time"
 
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/cmd/ubuntu-pro-agent/agent"
-	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/testutils"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
 )
 
 func TestHelp(t *testing.T) {
@@ -59,6 +63,479 @@ func TestVersion(t *testing.T) {
 	require.Equal(t, "Dev", fields[1], "Wrong version")
 }
 
+func TestConfigValidate(t *testing.T) {
+	// Not parallel because we capture stdout
+
+	testCases := map[string]struct {
+		configFile string
+
+		wantOutput string
+		wantErr    bool
+	}{
+		"Success with an empty config":    {wantOutput: "The configuration is valid"},
+		"Success with only some warnings": {configFile: "subscription:\n  user: user_token\n  store: store_token\n", wantOutput: "warning: Subscription.User"},
+
+		"Error when the Landscape config is invalid":  {configFile: "subscription:\n  user: user_token\nlandscape:\n  config: \"[client\"\n", wantOutput: "error: Landscape.Config", wantErr: true},
+		"Error when the config file cannot be parsed": {configFile: "\tThis is not YAML!", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			privateDir := t.TempDir()
+			if tc.configFile != "" {
+				err := os.WriteFile(filepath.Join(privateDir, "config"), []byte(tc.configFile), 0600)
+				require.NoError(t, err, "Setup: could not write config file")
+			}
+
+			a := agent.NewForTesting(t, "", privateDir)
+			a.SetArgs("config", "validate")
+
+			getStdout := captureStdout(t)
+
+			err := a.Run()
+			out := getStdout()
+			if tc.wantErr {
+				require.Error(t, err, "Run should return an error when the config is not valid")
+			} else {
+				require.NoError(t, err, "Run should not return an error when the config is valid")
+			}
+
+			require.Contains(t, out, tc.wantOutput, "Unexpected output of config validate")
+		})
+	}
+}
+
+func TestDirOverrides(t *testing.T) {
+	// Not parallel because we capture stdout and modify the environment
+
+	testCases := map[string]struct {
+		flag bool
+		env  bool
+	}{
+		"Success with the directories set on the command line": {flag: true},
+		"Success with the directories set in the environment":  {env: true},
+		"Success with the command line taking precedence":      {flag: true, env: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// The config file is only in the state directory that must be used.
+			publicDir, stateDir := t.TempDir(), t.TempDir()
+			err := os.WriteFile(filepath.Join(stateDir, "config"), []byte("subscription:\n  user: user_token\n  store: store_token\n"), 0600)
+			require.NoError(t, err, "Setup: could not write config file")
+
+			args := []string{"config", "validate"}
+			if tc.flag {
+				args = append(args, "--public-dir", publicDir, "--state-dir", stateDir)
+			}
+
+			switch {
+			case tc.env && tc.flag:
+				t.Setenv("UP4W_PUBLIC_DIR", t.TempDir())
+				t.Setenv("UP4W_STATE_DIR", t.TempDir())
+			case tc.env:
+				t.Setenv("UP4W_PUBLIC_DIR", publicDir)
+				t.Setenv("UP4W_STATE_DIR", stateDir)
+			}
+
+			a := agent.New(agent.WithRegistry(testutils.NewRegistryMock()))
+			a.SetArgs(args...)
+
+			getStdout := captureStdout(t)
+
+			err = a.Run()
+			out := getStdout()
+			require.NoError(t, err, "Run should not return an error when the config is valid")
+			require.Contains(t, out, "warning: Subscription.User", "The config file should have been read from the state directory set")
+		})
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	// Not parallel because we capture stdout
+
+	testCases := map[string]struct {
+		agentNotRunning bool
+
+		wantErr bool
+	}{
+		"Success pausing and resuming the running agent": {},
+
+		"Error when the agent is not running": {agentNotRunning: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			publicDir := t.TempDir()
+			privateDir := t.TempDir()
+
+			if !tc.agentNotRunning {
+				a := agent.NewForTesting(t, publicDir, privateDir)
+				a.SetArgs()
+
+				ch := make(chan error)
+				go func() {
+					ch <- a.Run()
+					close(ch)
+				}()
+				defer func() {
+					a.Quit()
+					require.NoError(t, <-ch, "Run should exit without any errors")
+				}()
+
+				a.WaitReady()
+				require.Eventually(t, func() bool {
+					_, err := os.Stat(filepath.Join(publicDir, common.ListeningPortFileName))
+					return err == nil
+				}, 10*time.Second, 100*time.Millisecond, "Setup: the agent never wrote its address file")
+			}
+
+			configPath := filepath.Join(privateDir, "config")
+
+			for _, cmd := range []string{"pause", "resume"} {
+				c := agent.NewForTesting(t, publicDir, privateDir)
+				c.SetArgs(cmd)
+
+				getStdout := captureStdout(t)
+
+				err := c.Run()
+				out := getStdout()
+				if tc.wantErr {
+					require.Error(t, err, "%s should return an error when the agent is not running", cmd)
+					return
+				}
+				require.NoError(t, err, "%s should return no error. Stdout: %v", cmd, out)
+
+				config, err := os.ReadFile(configPath)
+				require.NoError(t, err, "The config file should have been written")
+				require.Equal(t, cmd == "pause", strings.Contains(string(config), "paused: true"), "Unexpected pause state in the config file after %s", cmd)
+			}
+		})
+	}
+}
+
+func TestConfigReload(t *testing.T) {
+	// Not parallel because we capture stdout
+
+	testCases := map[string]struct {
+		agentNotRunning bool
+
+		wantErr bool
+	}{
+		"Success reloading the configuration of the running agent": {},
+
+		"Error when the agent is not running": {agentNotRunning: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			publicDir := t.TempDir()
+			privateDir := t.TempDir()
+
+			if !tc.agentNotRunning {
+				a := agent.NewForTesting(t, publicDir, privateDir)
+				a.SetArgs()
+
+				ch := make(chan error)
+				go func() {
+					ch <- a.Run()
+					close(ch)
+				}()
+				defer func() {
+					a.Quit()
+					require.NoError(t, <-ch, "Run should exit without any errors")
+				}()
+
+				a.WaitReady()
+				require.Eventually(t, func() bool {
+					_, err := os.Stat(filepath.Join(publicDir, common.ListeningPortFileName))
+					return err == nil
+				}, 10*time.Second, 100*time.Millisecond, "Setup: the agent never wrote its address file")
+			}
+
+			err := os.WriteFile(filepath.Join(privateDir, "config"), []byte("loglevel: info\n"), 0600)
+			require.NoError(t, err, "Setup: could not write config file")
+
+			c := agent.NewForTesting(t, publicDir, privateDir)
+			c.SetArgs("config", "reload")
+
+			getStdout := captureStdout(t)
+
+			err = c.Run()
+			out := getStdout()
+			if tc.wantErr {
+				require.Error(t, err, "config reload should return an error when the agent is not running")
+				return
+			}
+			require.NoError(t, err, "config reload should return no error. Stdout: %v", out)
+
+			require.Contains(t, out, "log-level: applied", "The log level should have been applied")
+			require.Contains(t, out, "ports: requires a restart", "The ports should require a restart")
+		})
+	}
+}
+
+func TestTokenImport(t *testing.T) {
+	// Not parallel because we capture stdout
+
+	//nolint:gosec // This is not a real credential
+	const token = "C1a2b3c4d5e6f7g8h9i0j1k2"
+
+	testCases := map[string]struct {
+		contents        string
+		noFile          bool
+		agentNotRunning bool
+
+		wantErr bool
+	}{
+		"Success importing a token":                 {contents: token + "\n"},
+		"Success ignoring comments and blank lines": {contents: "# Ubuntu Pro token for the fleet\n\n  " + token + "  \n"},
+
+		"Error when the file does not exist":     {noFile: true, wantErr: true},
+		"Error when the file has no token":       {contents: "# Nothing here\n", wantErr: true},
+		"Error when the file has several tokens": {contents: token + "\n" + token + "\n", wantErr: true},
+		"Error when the file has no valid token": {contents: "not-a-token\n", wantErr: true},
+		"Error when the agent is not running":    {contents: token, agentNotRunning: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			publicDir := t.TempDir()
+			privateDir := t.TempDir()
+
+			if !tc.agentNotRunning {
+				a := agent.NewForTesting(t, publicDir, privateDir)
+				a.SetArgs()
+
+				ch := make(chan error)
+				go func() {
+					ch <- a.Run()
+					close(ch)
+				}()
+				defer func() {
+					a.Quit()
+					require.NoError(t, <-ch, "Run should exit without any errors")
+				}()
+
+				a.WaitReady()
+				require.Eventually(t, func() bool {
+					_, err := os.Stat(filepath.Join(publicDir, common.ListeningPortFileName))
+					return err == nil
+				}, 10*time.Second, 100*time.Millisecond, "Setup: the agent never wrote its address file")
+			}
+
+			tokenFile := filepath.Join(t.TempDir(), "pro-token")
+			if !tc.noFile {
+				err := os.WriteFile(tokenFile, []byte(tc.contents), 0600)
+				require.NoError(t, err, "Setup: could not write the token file")
+			}
+
+			c := agent.NewForTesting(t, publicDir, privateDir)
+			c.SetArgs("token", "import", tokenFile)
+
+			getStdout := captureStdout(t)
+
+			err := c.Run()
+			out := getStdout()
+			if tc.wantErr {
+				require.Error(t, err, "token import should return an error")
+				require.NotContains(t, err.Error(), token, "The error should not leak the token")
+				return
+			}
+			require.NoError(t, err, "token import should return no error. Stdout: %v", out)
+			require.NotContains(t, out, token, "The output should not leak the token")
+
+			config, err := os.ReadFile(filepath.Join(privateDir, "config"))
+			require.NoError(t, err, "The config file should have been written")
+			require.Contains(t, string(config), "user: "+token, "The token should have been stored as the user subscription")
+		})
+	}
+}
+
+func TestResetAgent(t *testing.T) {
+	// Not parallel because we capture stdout
+
+	publicDir := t.TempDir()
+	privateDir := t.TempDir()
+
+	a := agent.NewForTesting(t, publicDir, privateDir)
+	a.SetArgs()
+
+	ch := make(chan error)
+	go func() {
+		ch <- a.Run()
+		close(ch)
+	}()
+	defer func() {
+		a.Quit()
+		require.NoError(t, <-ch, "Run should exit without any errors")
+	}()
+
+	a.WaitReady()
+	portFile := filepath.Join(publicDir, common.ListeningPortFileName)
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(portFile)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond, "Setup: the agent never wrote its address file")
+
+	pause := func() {
+		t.Helper()
+
+		c := agent.NewForTesting(t, publicDir, privateDir)
+		c.SetArgs("pause")
+
+		getStdout := captureStdout(t)
+		err := c.Run()
+		out := getStdout()
+		require.NoError(t, err, "pause should return no error. Stdout: %v", out)
+	}
+
+	configPath := filepath.Join(privateDir, "config")
+	isPaused := func() bool {
+		config, err := os.ReadFile(configPath)
+		return err == nil && strings.Contains(string(config), "paused: true")
+	}
+
+	// The state of the agent is changed so that the reset has something to wipe.
+	pause()
+	require.True(t, isPaused(), "Setup: the agent should be paused")
+
+	backup := filepath.Join(privateDir, consts.BackupsDirName, "Ubuntu.tar.gz")
+	require.NoError(t, os.MkdirAll(filepath.Dir(backup), 0700), "Setup: could not create backups directory")
+	require.NoError(t, os.WriteFile(backup, []byte("backup"), 0600), "Setup: could not write backup")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := agent.DialAgent(ctx, publicDir, privateDir)
+	require.NoError(t, err, "Setup: could not dial the agent")
+	defer conn.Close()
+	client := agentapi.NewUIClient(conn)
+
+	_, err = client.ResetAgent(ctx, &agentapi.Empty{})
+	require.Error(t, err, "ResetAgent should fail without a confirmation")
+	require.True(t, isPaused(), "The agent should not be reset without a confirmation")
+
+	confirmation, err := client.RequestConfirmation(ctx, &agentapi.ConfirmationRequest{Method: "ResetAgent"})
+	require.NoError(t, err, "RequestConfirmation should return no error")
+
+	confirmedCtx := metadata.AppendToOutgoingContext(ctx, common.ConfirmationMetadataKey, confirmation.GetCode())
+	_, err = client.ResetAgent(confirmedCtx, &agentapi.Empty{})
+	require.NoError(t, err, "ResetAgent should return no error")
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(portFile)
+		return !isPaused() && err == nil
+	}, 30*time.Second, 100*time.Millisecond, "The agent should start over with a clean state")
+	require.FileExists(t, backup, "The reset should keep the exported distros")
+
+	// The agent keeps serving requests after the reset.
+	require.Eventually(t, func() bool {
+		c := agent.NewForTesting(t, publicDir, privateDir)
+		c.SetArgs("pause")
+		getStdout := captureStdout(t)
+		err := c.Run()
+		getStdout()
+		return err == nil
+	}, 30*time.Second, 500*time.Millisecond, "The agent should serve requests after the reset")
+	require.True(t, isPaused(), "The agent should be paused again after the reset")
+}
+
+func TestDBSnapshotRestore(t *testing.T) {
+	// Not parallel because we capture stdout
+
+	const dump = "- name: Ubuntu\n  guid: '{f0f0f0f0-0000-0000-0000-000000000000}'\n  properties:\n    hostname: TestMachine\n"
+	const tasks = "- type: LandscapeConfigure\n  task:\n    config: \"\"\n    hostagentuid: \"\"\n"
+
+	testCases := map[string]struct {
+		toStdout     bool
+		noSnapshot   bool
+		agentRunning bool
+
+		wantErr bool
+	}{
+		"Success snapshotting into a file": {},
+		"Success snapshotting to stdout":   {toStdout: true},
+
+		"Error when the snapshot file does not exist": {noSnapshot: true, wantErr: true},
+		"Error when the agent is running":             {agentRunning: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			srcDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "distros.db"), []byte(dump), 0600), "Setup: could not write database")
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "Ubuntu.tasks"), []byte(tasks), 0600), "Setup: could not write tasks")
+
+			snapshotPath := filepath.Join(t.TempDir(), "snapshot.yaml")
+
+			a := agent.NewForTesting(t, "", srcDir)
+			if tc.toStdout {
+				a.SetArgs("db", "snapshot")
+			} else {
+				a.SetArgs("db", "snapshot", snapshotPath)
+			}
+
+			getStdout := captureStdout(t)
+			err := a.Run()
+			out := getStdout()
+			require.NoError(t, err, "db snapshot should return no error")
+
+			if tc.toStdout {
+				require.Contains(t, out, "TestMachine", "db snapshot should print the snapshot")
+				require.NoError(t, os.WriteFile(snapshotPath, []byte(out), 0600), "Setup: could not write snapshot")
+			}
+			if tc.noSnapshot {
+				require.NoError(t, os.Remove(snapshotPath), "Setup: could not remove snapshot")
+			}
+
+			publicDir := t.TempDir()
+			dstDir := t.TempDir()
+
+			if tc.agentRunning {
+				a := agent.NewForTesting(t, publicDir, dstDir)
+				a.SetArgs()
+
+				ch := make(chan error)
+				go func() {
+					ch <- a.Run()
+					close(ch)
+				}()
+				defer func() {
+					a.Quit()
+					require.NoError(t, <-ch, "Run should exit without any errors")
+				}()
+
+				a.WaitReady()
+				require.Eventually(t, func() bool {
+					_, err := os.Stat(filepath.Join(publicDir, common.ListeningPortFileName))
+					return err == nil
+				}, 10*time.Second, 100*time.Millisecond, "Setup: the agent never wrote its address file")
+			}
+
+			r := agent.NewForTesting(t, publicDir, dstDir)
+			r.SetArgs("db", "restore", snapshotPath)
+
+			getStdout = captureStdout(t)
+			err = r.Run()
+			out = getStdout()
+			if tc.wantErr {
+				require.Error(t, err, "db restore should return an error")
+				return
+			}
+			require.NoError(t, err, "db restore should return no error. Stdout: %v", out)
+
+			gotDump, err := os.ReadFile(filepath.Join(dstDir, "distros.db"))
+			require.NoError(t, err, "The database should have been restored")
+			require.Contains(t, string(gotDump), "TestMachine", "The database should have been restored with the same distros")
+
+			gotTasks, err := os.ReadFile(filepath.Join(dstDir, "Ubuntu.tasks"))
+			require.NoError(t, err, "The tasks should have been restored")
+			require.Equal(t, tasks, string(gotTasks), "The tasks should have been restored as they were")
+		})
+	}
+}
+
 func TestNoUsageError(t *testing.T) {
 	a := agent.NewForTesting(t, "", "")
 	a.SetArgs("completion", "bash")
@@ -103,6 +580,87 @@ func TestCanQuitTwice(t *testing.T) {
 	a.Quit()
 }
 
+func TestSimulateDistros(t *testing.T) {
+	t.Parallel()
+
+	privateDir := t.TempDir()
+
+	a := agent.NewForTesting(t, "", privateDir)
+	a.SetArgs("--simulate-distros", "2")
+
+	ch := make(chan error)
+	go func() {
+		ch <- a.Run()
+		close(ch)
+	}()
+
+	a.WaitReady()
+
+	// The simulated distros connect to the agent and get recorded in the database.
+	dbPath := filepath.Join(privateDir, consts.DatabaseFileName)
+	require.Eventually(t, func() bool {
+		out, err := os.ReadFile(dbPath)
+		return err == nil && strings.Contains(string(out), "Simulated-001") && strings.Contains(string(out), "Simulated-002")
+	}, 20*time.Second, 500*time.Millisecond, "Simulated distros should be added to the database")
+
+	a.Quit()
+	require.NoError(t, <-ch, "Run should exit without any errors")
+}
+
+func TestTelemetryOptIn(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled bool
+	}{
+		"Telemetry is disabled by default":         {},
+		"Telemetry is enabled by the feature flag": {enabled: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			publicDir := t.TempDir()
+			privateDir := t.TempDir()
+
+			telemetryFile := filepath.Join(publicDir, common.TelemetryFileName)
+			if tc.enabled {
+				err := os.WriteFile(filepath.Join(privateDir, "config"), []byte("features:\n  Telemetry: true\n"), 0600)
+				require.NoError(t, err, "Setup: could not write the config file")
+			} else {
+				// A previous run with telemetry enabled left the file behind.
+				err := os.WriteFile(telemetryFile, nil, 0600)
+				require.NoError(t, err, "Setup: could not write the telemetry file")
+			}
+
+			a := agent.NewForTesting(t, publicDir, privateDir)
+			a.SetArgs("--otlp-endpoint", "http://localhost:4317")
+
+			ch := make(chan error)
+			go func() {
+				ch <- a.Run()
+				close(ch)
+			}()
+
+			a.WaitReady()
+			require.Eventually(t, func() bool {
+				_, err := os.Stat(filepath.Join(publicDir, common.ListeningPortFileName))
+				return err == nil
+			}, 20*time.Second, 100*time.Millisecond, "Setup: the agent should start serving")
+
+			if tc.enabled {
+				require.FileExists(t, telemetryFile, "The distros should be told that telemetry is enabled")
+			} else {
+				require.NoFileExists(t, telemetryFile, "The distros should be told that telemetry is disabled")
+			}
+
+			a.Quit()
+			require.NoError(t, <-ch, "Run should exit without any errors")
+		})
+	}
+}
+
 func TestAppCanQuitWithoutExecute(t *testing.T) {
 	t.Skipf("This test is skipped because it is flaky. There is no way to guarantee Quit has been called before run.")
 
@@ -130,11 +688,14 @@ func TestAppRunFailsOnComponentsCreationAndQuit(t *testing.T) {
 
 		invalidLocalAppData bool
 		invalidUserProfile  bool
+
+		invalidRegistryRoot bool
 	}{
 		"Invalid private directory": {invalidPublicDir: true},
 		"Invalid public directory":  {invalidPrivateDir: true},
 		"Invalid LocalAppData":      {invalidLocalAppData: true},
 		"Invalid UserProfile":       {invalidUserProfile: true},
+		"Invalid registry root":     {invalidRegistryRoot: true},
 	}
 
 	for name, tc := range testCases {
@@ -161,8 +722,13 @@ func TestAppRunFailsOnComponentsCreationAndQuit(t *testing.T) {
 				privateDir = badDir
 			}
 
-			a := agent.New(agent.WithPublicDir(publicDir), agent.WithPrivateDir(privateDir), agent.WithRegistry(registry.NewMock()))
-			a.SetArgs()
+			var args []string
+			if tc.invalidRegistryRoot {
+				args = append(args, "--registry-root", `System\Canonical\UbuntuPro`)
+			}
+
+			a := agent.New(agent.WithPublicDir(publicDir), agent.WithPrivateDir(privateDir), agent.WithRegistry(testutils.NewRegistryMock()))
+			a.SetArgs(args...)
 
 			err := os.WriteFile(badDir, []byte("I'm here to break the service"), 0600)
 			require.NoError(t, err, "Failed to write file")
@@ -198,6 +764,9 @@ func TestPublicDir(t *testing.T) {
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
+			// The private directory is resolved along with the public one.
+			t.Setenv("LocalAppData", t.TempDir())
+
 			dir := t.TempDir()
 			if tc.emptyEnv {
 				t.Setenv("UserProfile", "")
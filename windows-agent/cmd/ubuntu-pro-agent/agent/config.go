@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher"
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/decorate"
+)
+
+// reloadTimeout is how long the reload command waits for the running agent to answer.
+const reloadTimeout = 30 * time.Second
+
+func (a *App) installConfig(o ...option) {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: i18n.G("Inspect the agent configuration"),
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: i18n.G("Reports problems in the configuration and exits"),
+		Args:  cobra.NoArgs,
+		RunE:  func(cmd *cobra.Command, args []string) error { return a.validateConfig(o...) },
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reload",
+		Short: i18n.G("Applies the configuration to the running agent without restarting it"),
+		Long: i18n.G(`Reads the config file and the registry again and applies them to the running agent.
+The settings that only take effect once the agent restarts are listed as such.`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error { return a.reloadConfig(o...) },
+	})
+
+	a.rootCmd.AddCommand(cmd)
+}
+
+// validateConfig prints every problem found in the configuration. It returns an error if any
+// of them would prevent the agent from working properly.
+func (a *App) validateConfig(args ...option) error {
+	ctx := context.TODO()
+
+	var opt options
+	for _, f := range args {
+		f(&opt)
+	}
+
+	dirs, err := a.paths(opt)
+	if err != nil {
+		return err
+	}
+
+	reg, err := a.registry(opt)
+	if err != nil {
+		return err
+	}
+
+	data, err := registrywatcher.ReadRegistry(ctx, reg)
+	if err != nil {
+		return err
+	}
+
+	problems, err := config.ValidateSnapshot(ctx, dirs.State, data)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		fmt.Println(i18n.G("The configuration is valid"))
+		return nil
+	}
+
+	var invalid bool
+	for _, p := range problems {
+		fmt.Println(p)
+		invalid = invalid || p.Severity == config.SeverityError
+	}
+
+	if invalid {
+		return errors.New(i18n.G("the configuration is not valid"))
+	}
+
+	return nil
+}
+
+// reloadConfig asks the running agent to apply its configuration again, and prints what happened to each setting.
+func (a *App) reloadConfig(args ...option) (err error) {
+	defer decorate.OnError(&err, i18n.G("could not reload the configuration"))
+
+	var opt options
+	for _, f := range args {
+		f(&opt)
+	}
+
+	p, err := a.paths(opt)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+	defer cancel()
+
+	conn, err := dialAgent(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	res, err := agentapi.NewUIClient(conn).ReloadConfig(ctx, &agentapi.Empty{})
+	if err != nil {
+		return err
+	}
+
+	for _, s := range res.GetSettings() {
+		switch {
+		case s.GetRestartRequired():
+			fmt.Printf(i18n.G("%s: requires a restart\n"), s.GetName())
+		case s.GetError() != "":
+			fmt.Printf(i18n.G("%s: not applied: %s\n"), s.GetName(), s.GetError())
+		default:
+			fmt.Printf(i18n.G("%s: applied\n"), s.GetName())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/decorate"
+)
+
+// runningCheckTimeout is how long the restore command waits for an agent to answer before
+// assuming that none is running.
+const runningCheckTimeout = 2 * time.Second
+
+func (a *App) installDB(o ...option) {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: i18n.G("Capture and replay the state of the distros"),
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "snapshot [FILE]",
+		Short: i18n.G("Writes the state of every distro to a file, or to the standard output"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var path string
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return a.snapshotDB(path, o...)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "restore FILE",
+		Short: i18n.G("Replaces the state of every distro with a snapshot"),
+		Long: i18n.G(`Replaces the state of every distro with a snapshot generated by the snapshot command.
+The agent must not be running.`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error { return a.restoreDB(args[0], o...) },
+	})
+
+	a.rootCmd.AddCommand(cmd)
+}
+
+// snapshotDB writes the state of every distro to the file, or to the standard output if the path is empty.
+func (a *App) snapshotDB(path string, args ...option) (err error) {
+	defer decorate.OnError(&err, i18n.G("could not snapshot the distros"))
+
+	var opt options
+	for _, f := range args {
+		f(&opt)
+	}
+
+	p, err := a.paths(opt)
+	if err != nil {
+		return err
+	}
+
+	out, err := database.SnapshotDir(p.State)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		fmt.Print(string(out))
+		return nil
+	}
+
+	return os.WriteFile(path, out, 0600)
+}
+
+// restoreDB replaces the state of every distro with the snapshot in the file.
+func (a *App) restoreDB(path string, args ...option) (err error) {
+	defer decorate.OnError(&err, i18n.G("could not restore the distros"))
+
+	var opt options
+	for _, f := range args {
+		f(&opt)
+	}
+
+	p, err := a.paths(opt)
+	if err != nil {
+		return err
+	}
+
+	// The running agent would overwrite the restored state with its own.
+	ctx, cancel := context.WithTimeout(context.Background(), runningCheckTimeout)
+	defer cancel()
+	if conn, err := dialAgent(ctx, p); err == nil {
+		conn.Close()
+		return errors.New(i18n.G("the agent is running, stop it first"))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := database.RestoreDir(p.State, data); err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.G("The distros were restored"))
+	return nil
+}
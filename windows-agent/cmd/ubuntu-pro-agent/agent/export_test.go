@@ -1,10 +1,14 @@
 package agent
 
 import (
+	"context"
 	"testing"
 
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/paths"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher"
-	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/uiauth"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/testutils"
+	"google.golang.org/grpc"
 )
 
 func WithPublicDir(dir string) func(*options) {
@@ -25,7 +29,14 @@ func WithRegistry(r registrywatcher.Registry) func(*options) {
 	}
 }
 
-// NewForTesting creates a new App with overridden paths for the service and daemon caches.
+func WithPrompter(p uiauth.Prompter) func(*options) {
+	return func(o *options) {
+		o.prompter = p
+	}
+}
+
+// NewForTesting creates a new App with overridden paths for the service and daemon caches. Destructive
+// calls are approved without asking, as there is no user to ask.
 func NewForTesting(t *testing.T, publicDir, privateDir string) *App {
 	t.Helper()
 
@@ -37,5 +48,12 @@ func NewForTesting(t *testing.T, publicDir, privateDir string) *App {
 		privateDir = t.TempDir()
 	}
 
-	return New(WithPrivateDir(privateDir), WithPublicDir(publicDir), WithRegistry(registry.NewMock()))
+	approve := func(ctx context.Context, method, description string) (bool, error) { return true, nil }
+
+	return New(WithPrivateDir(privateDir), WithPublicDir(publicDir), WithRegistry(testutils.NewRegistryMock()), WithPrompter(approve))
+}
+
+// DialAgent connects to the agent whose address file is in publicDir and whose token file is in privateDir.
+func DialAgent(ctx context.Context, publicDir, privateDir string) (*grpc.ClientConn, error) {
+	return dialAgent(ctx, paths.Paths{Public: publicDir, State: privateDir})
 }
@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/paths"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/uiauth"
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/decorate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// pauseTimeout is how long the pause and resume commands wait for the running agent to answer.
+const pauseTimeout = 10 * time.Second
+
+func (a *App) installPause(o ...option) {
+	a.rootCmd.AddCommand(&cobra.Command{
+		Use:   "pause",
+		Short: i18n.G("Pauses the automatic behaviour of the running agent"),
+		Long: i18n.G(`Pauses the automatic behaviour of the running agent until it is resumed, even across restarts.
+While paused, no distro is woken up, no task runs and Landscape commands are held back.`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error { return a.setPaused(true, o...) },
+	})
+
+	a.rootCmd.AddCommand(&cobra.Command{
+		Use:   "resume",
+		Short: i18n.G("Resumes the automatic behaviour of the running agent"),
+		Args:  cobra.NoArgs,
+		RunE:  func(cmd *cobra.Command, args []string) error { return a.setPaused(false, o...) },
+	})
+}
+
+// setPaused asks the running agent to pause or resume its automatic behaviour.
+func (a *App) setPaused(paused bool, args ...option) (err error) {
+	defer decorate.OnError(&err, i18n.G("could not reach the running agent"))
+
+	var opt options
+	for _, f := range args {
+		f(&opt)
+	}
+
+	p, err := a.paths(opt)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pauseTimeout)
+	defer cancel()
+
+	conn, err := dialAgent(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := agentapi.NewUIClient(conn).SetPauseState(ctx, &agentapi.PauseState{Paused: paused}); err != nil {
+		return err
+	}
+
+	if paused {
+		fmt.Println(i18n.G("The agent is paused"))
+	} else {
+		fmt.Println(i18n.G("The agent is resumed"))
+	}
+
+	return nil
+}
+
+// dialAgent connects to the agent listening on the address written in its port file, authenticating
+// with the token of its session.
+func dialAgent(ctx context.Context, p paths.Paths) (*grpc.ClientConn, error) {
+	addr, err := os.ReadFile(p.PortFile())
+	if err != nil {
+		return nil, fmt.Errorf("could not read agent address, is the agent running?: %v", err)
+	}
+
+	// The agent listens on all interfaces, so only its port is relevant.
+	_, port, err := net.SplitHostPort(strings.TrimSpace(string(addr)))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse agent address: %v", err)
+	}
+
+	creds, err := uiauth.Credentials(p.State)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort("localhost", port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		creds,
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial agent: %v", err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/decorate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tokenImportTimeout is how long the import command waits for the running agent to answer.
+const tokenImportTimeout = 30 * time.Second
+
+func (a *App) installToken(o ...option) {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: i18n.G("Manage the Ubuntu Pro token of the agent"),
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "import [FILE]",
+		Short: i18n.G("Attaches every distro with the Ubuntu Pro token in a file, or in the standard input"),
+		Long: i18n.G(`Attaches every distro with the Ubuntu Pro token in a file, or in the standard input if the file is
+omitted or is "-". The file holds the token alone on a line. Empty lines and lines starting with "#" are ignored.
+The token is stored as if it had been entered in the GUI. The agent must be running.`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var path string
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return a.importToken(cmd.InOrStdin(), path, o...)
+		},
+	})
+
+	a.rootCmd.AddCommand(cmd)
+}
+
+// importToken sends the Ubuntu Pro token in the file, or in stdin if the path is empty or "-", to the running agent.
+func (a *App) importToken(stdin io.Reader, path string, args ...option) (err error) {
+	defer decorate.OnError(&err, i18n.G("could not import the Ubuntu Pro token"))
+
+	var opt options
+	for _, f := range args {
+		f(&opt)
+	}
+
+	r := stdin
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	token, err := readProToken(r)
+	if err != nil {
+		return err
+	}
+
+	p, err := a.paths(opt)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenImportTimeout)
+	defer cancel()
+
+	conn, err := dialAgent(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = agentapi.NewUIClient(conn).ApplyProToken(ctx, &agentapi.ProAttachInfo{Token: token})
+	if status.Code(err) == codes.FailedPrecondition {
+		return errors.New(i18n.G("the Ubuntu Pro subscription is managed by your organization"))
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Printf(i18n.G("Imported Ubuntu Pro token %s\n"), common.Obfuscate(token))
+	return nil
+}
+
+// readProToken returns the single Ubuntu Pro token in the contents, ignoring empty lines and comments.
+func readProToken(r io.Reader) (string, error) {
+	var tokens []string
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, line)
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("could not read token: %v", err)
+	}
+
+	if len(tokens) == 0 {
+		return "", errors.New("no token found")
+	}
+
+	if len(tokens) > 1 {
+		return "", fmt.Errorf("found %d tokens, expected a single one", len(tokens))
+	}
+
+	// The token is not shown in full, as it is a secret.
+	if !common.IsProToken(tokens[0]) {
+		return "", fmt.Errorf("%s is not an Ubuntu Pro token", common.Obfuscate(tokens[0]))
+	}
+
+	return tokens[0], nil
+}
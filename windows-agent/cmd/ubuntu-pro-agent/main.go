@@ -31,7 +31,7 @@ type app interface {
 	Run() error
 	UsageError() bool
 	Quit()
-	PublicDir() (string, error)
+	LogDir() (string, error)
 }
 
 func run(a app) int {
@@ -61,15 +61,15 @@ func run(a app) int {
 }
 
 func setLoggerOutput(a app) (func(), error) {
-	publicDir, err := a.PublicDir()
+	logDir, err := a.LogDir()
 	if err != nil {
 		return nil, err
 	}
 
-	logFile := filepath.Join(publicDir, "log")
+	logFile := filepath.Join(logDir, "log")
 
 	// Move old log file
-	oldLogFile := filepath.Join(publicDir, "log.old")
+	oldLogFile := filepath.Join(logDir, "log.old")
 	err = os.Rename(logFile, oldLogFile)
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		log.Warnf("Could not archive previous log file: %v", err)
@@ -34,8 +34,8 @@ func (a *myApp) Quit() {
 	close(a.done)
 }
 
-func (a *myApp) PublicDir() (string, error) {
-	if a.tmpDir == "PUBLIC_DIR_ERROR" {
+func (a *myApp) LogDir() (string, error) {
+	if a.tmpDir == "LOG_DIR_ERROR" {
 		return "", errors.New("mock error")
 	}
 	return a.tmpDir, nil
@@ -82,13 +82,13 @@ func TestRun(t *testing.T) {
 			}
 
 			if tc.logDirError {
-				a.tmpDir = "PUBLIC_DIR_ERROR"
+				a.tmpDir = "LOG_DIR_ERROR"
 			}
 
 			var logFile, oldLogFile string
-			publicDir, err := a.PublicDir()
+			logDir, err := a.LogDir()
 			if err == nil {
-				logFile = filepath.Join(publicDir, "log")
+				logFile = filepath.Join(logDir, "log")
 				oldLogFile = logFile + ".old"
 				switch tc.existingLogContent {
 				case "":
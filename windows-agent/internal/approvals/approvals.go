@@ -0,0 +1,230 @@
+// Package approvals holds back the operations the user asked to approve before they run. Such operations
+// wait in a pending state until the user approves or denies them from the GUI, or until they expire.
+package approvals
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+)
+
+// Category is a kind of operation that the user can require to approve.
+type Category string
+
+const (
+	// LandscapeInstall is the installation of a distro requested by Landscape.
+	LandscapeInstall Category = "landscape-install"
+
+	// LandscapeUninstall is the removal of a distro requested by Landscape.
+	LandscapeUninstall Category = "landscape-uninstall"
+
+	// LandscapeSetDefault is the change of the default distro requested by Landscape.
+	LandscapeSetDefault Category = "landscape-set-default"
+
+	// LandscapePower is the start or stop of a distro requested by Landscape.
+	LandscapePower Category = "landscape-power"
+
+	// LandscapeShutdownHost is the shutdown of WSL requested by Landscape.
+	LandscapeShutdownHost Category = "landscape-shutdown-host"
+)
+
+// Categories lists every category of operations that can require an approval.
+var Categories = []Category{LandscapeInstall, LandscapeUninstall, LandscapeSetDefault, LandscapePower, LandscapeShutdownHost}
+
+// defaultExpiry is how long an operation waits for the user to approve it before it is denied.
+const defaultExpiry = 5 * time.Minute
+
+var (
+	// ErrDenied is returned for the operations the user denied.
+	ErrDenied = errors.New("denied by the user")
+
+	// ErrExpired is returned for the operations the user did not approve in time.
+	ErrExpired = errors.New("not approved in time")
+
+	// ErrNotFound is returned when approving or denying an operation that is not pending.
+	ErrNotFound = errors.New("no such pending approval")
+)
+
+// Config tells which categories of operations require an approval.
+type Config interface {
+	ApprovalRequired(category string) (bool, error)
+}
+
+// Request is an operation waiting for the user to approve it.
+type Request struct {
+	ID          string
+	Category    Category
+	Description string
+
+	Requested time.Time
+	Expires   time.Time
+}
+
+// pendingRequest is a request and the channel its decision is sent through.
+type pendingRequest struct {
+	Request
+	decision chan bool
+}
+
+// Approvals keeps track of the operations waiting for an approval.
+type Approvals struct {
+	conf   Config
+	expiry time.Duration
+	clock  clock.Clock
+
+	pending map[string]pendingRequest
+	mu      sync.Mutex
+}
+
+type options struct {
+	expiry time.Duration
+	clock  clock.Clock
+}
+
+// Option is an optional argument for New.
+type Option func(*options)
+
+// WithExpiry overrides how long operations wait for an approval before they are denied.
+func WithExpiry(d time.Duration) Option {
+	return func(o *options) {
+		o.expiry = d
+	}
+}
+
+// WithClock overrides the clock used to expire the requests.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// New creates a tracker of the operations waiting for an approval.
+func New(conf Config, args ...Option) *Approvals {
+	opts := options{
+		expiry: defaultExpiry,
+		clock:  clock.Real(),
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return &Approvals{
+		conf:    conf,
+		expiry:  opts.expiry,
+		clock:   opts.clock,
+		pending: make(map[string]pendingRequest),
+	}
+}
+
+// Wait blocks until the user approves the operation, if its category requires it. It returns ErrDenied
+// or ErrExpired if the operation must not run, or the error of the context if it is done first.
+func (a *Approvals) Wait(ctx context.Context, category Category, description string) error {
+	required, err := a.conf.ApprovalRequired(string(category))
+	if err != nil {
+		// Running an operation the user may have wanted to approve is not an option.
+		return fmt.Errorf("could not check whether %s requires an approval: %v", category, err)
+	}
+
+	if !required {
+		return nil
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("could not generate approval ID: %v", err)
+	}
+
+	now := a.clock.Now()
+	req := pendingRequest{
+		Request: Request{
+			ID:          hex.EncodeToString(b),
+			Category:    category,
+			Description: description,
+			Requested:   now,
+			Expires:     now.Add(a.expiry),
+		},
+		decision: make(chan bool, 1),
+	}
+
+	a.mu.Lock()
+	a.pending[req.ID] = req
+	a.mu.Unlock()
+
+	defer func() {
+		a.mu.Lock()
+		delete(a.pending, req.ID)
+		a.mu.Unlock()
+	}()
+
+	log.Infof(ctx, "Approvals: %s is waiting for the approval of the user", description)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-a.clock.After(a.expiry):
+		log.Warningf(ctx, "Approvals: %s was not approved in time", description)
+		return ErrExpired
+	case approved := <-req.decision:
+		if !approved {
+			log.Infof(ctx, "Approvals: %s was denied", description)
+			return ErrDenied
+		}
+		log.Infof(ctx, "Approvals: %s was approved", description)
+		return nil
+	}
+}
+
+// Pending returns the operations waiting for an approval, oldest first.
+func (a *Approvals) Pending() []Request {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	requests := make([]Request, 0, len(a.pending))
+	for _, r := range a.pending {
+		requests = append(requests, r.Request)
+	}
+
+	slices.SortFunc(requests, func(x, y Request) int {
+		if c := x.Requested.Compare(y.Requested); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.ID, y.ID)
+	})
+
+	return requests
+}
+
+// Approve lets the pending operation run.
+func (a *Approvals) Approve(id string) error {
+	return a.decide(id, true)
+}
+
+// Deny keeps the pending operation from running.
+func (a *Approvals) Deny(id string) error {
+	return a.decide(id, false)
+}
+
+func (a *Approvals) decide(id string, approved bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	req, ok := a.pending[id]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+
+	// Once decided, the request cannot be decided again, even before Wait picks the decision up.
+	delete(a.pending, id)
+	req.decision <- approved
+
+	return nil
+}
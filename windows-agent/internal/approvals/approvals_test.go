@@ -0,0 +1,137 @@
+package approvals_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/approvals"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	const expiry = time.Minute
+
+	testCases := map[string]struct {
+		required []string
+		confErr  bool
+		approve  bool
+		deny     bool
+		expire   bool
+		cancel   bool
+
+		wantPending bool
+		wantErr     error
+		wantAnyErr  bool
+	}{
+		"Success without approval required": {required: []string{string(approvals.LandscapePower)}},
+		"Success once approved":             {required: []string{string(approvals.LandscapeInstall)}, approve: true, wantPending: true},
+
+		"Error when denied":                    {required: []string{string(approvals.LandscapeInstall)}, deny: true, wantPending: true, wantErr: approvals.ErrDenied},
+		"Error when expired":                   {required: []string{string(approvals.LandscapeInstall)}, expire: true, wantPending: true, wantErr: approvals.ErrExpired},
+		"Error when the context is canceled":   {required: []string{string(approvals.LandscapeInstall)}, cancel: true, wantPending: true, wantErr: context.Canceled},
+		"Error when the config cannot be read": {confErr: true, wantAnyErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			clk := clock.NewMock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+			a := approvals.New(&mockConfig{required: tc.required, err: tc.confErr}, approvals.WithClock(clk), approvals.WithExpiry(expiry))
+
+			done := make(chan error)
+			go func() { done <- a.Wait(ctx, approvals.LandscapeInstall, "Installing Ubuntu") }()
+
+			if tc.wantPending {
+				require.Eventually(t, func() bool { return clk.Waiters() == 1 }, 5*time.Second, 10*time.Millisecond,
+					"Wait should start waiting for an approval")
+
+				pending := a.Pending()
+				require.Len(t, pending, 1, "There should be a single pending approval")
+				require.Equal(t, approvals.LandscapeInstall, pending[0].Category, "Pending approval should have the category passed to Wait")
+				require.Equal(t, "Installing Ubuntu", pending[0].Description, "Pending approval should have the description passed to Wait")
+				require.Equal(t, clk.Now().Add(expiry), pending[0].Expires, "Pending approval should expire after the configured expiry")
+
+				switch {
+				case tc.approve:
+					require.NoError(t, a.Approve(pending[0].ID), "Approve should return no errors")
+				case tc.deny:
+					require.NoError(t, a.Deny(pending[0].ID), "Deny should return no errors")
+				case tc.expire:
+					clk.Advance(expiry)
+				case tc.cancel:
+					cancel()
+				}
+			}
+
+			var err error
+			select {
+			case err = <-done:
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "Wait should have returned")
+			}
+
+			require.Empty(t, a.Pending(), "No approval should be pending once Wait returned")
+
+			if tc.wantAnyErr {
+				require.Error(t, err, "Wait should return an error")
+				return
+			}
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr, "Wait should return the expected error")
+				return
+			}
+			require.NoError(t, err, "Wait should return no errors")
+		})
+	}
+}
+
+func TestDecideUnknown(t *testing.T) {
+	t.Parallel()
+
+	a := approvals.New(&mockConfig{})
+
+	require.ErrorIs(t, a.Approve("unknown"), approvals.ErrNotFound, "Approving an unknown request should return ErrNotFound")
+	require.ErrorIs(t, a.Deny("unknown"), approvals.ErrNotFound, "Denying an unknown request should return ErrNotFound")
+}
+
+func TestDecideTwice(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clk := clock.NewMock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	a := approvals.New(&mockConfig{required: []string{string(approvals.LandscapeUninstall)}}, approvals.WithClock(clk))
+
+	done := make(chan error)
+	go func() { done <- a.Wait(ctx, approvals.LandscapeUninstall, "Uninstalling Ubuntu") }()
+
+	require.Eventually(t, func() bool { return clk.Waiters() == 1 }, 5*time.Second, 10*time.Millisecond,
+		"Wait should start waiting for an approval")
+
+	id := a.Pending()[0].ID
+	require.NoError(t, a.Deny(id), "Deny should return no errors")
+	require.ErrorIs(t, a.Approve(id), approvals.ErrNotFound, "A request should not be decided twice")
+
+	require.ErrorIs(t, <-done, approvals.ErrDenied, "The first decision should be the one that applies")
+}
+
+type mockConfig struct {
+	required []string
+	err      bool
+}
+
+func (c *mockConfig) ApprovalRequired(category string) (bool, error) {
+	if c.err {
+		return false, errors.New("mock error")
+	}
+	return slices.Contains(c.required, category), nil
+}
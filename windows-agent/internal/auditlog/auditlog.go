@@ -0,0 +1,83 @@
+// Package auditlog records changes to the host and to the agent state in a file that outlives the
+// agent logs, so that administrators can find out when and why the agent behaved differently.
+package auditlog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/ubuntu/decorate"
+)
+
+// maxSize is the size past which the audit log is rotated. Only one rotated file is kept.
+const maxSize = 1 << 20
+
+// Log is an append-only audit log.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New creates an audit log in the given directory.
+func New(dir string) *Log {
+	return &Log{path: filepath.Join(dir, consts.AuditLogFileName)}
+}
+
+// Record appends an entry to the audit log. Failing to record is not fatal, so errors are only logged.
+func (l *Log) Record(ctx context.Context, event, message string) {
+	if l == nil {
+		return
+	}
+
+	if err := l.record(time.Now(), event, message); err != nil {
+		log.Warningf(ctx, "Audit log: %v", err)
+	}
+}
+
+func (l *Log) record(now time.Time, event, message string) (err error) {
+	defer decorate.OnError(&err, "could not record %s event", event)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.rotate(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Entries are kept to a single line so that the file can be processed line by line.
+	message = strings.ReplaceAll(message, "\n", " ")
+	if _, err := fmt.Fprintf(f, "%s %s: %s\n", now.UTC().Format(time.RFC3339), event, message); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// rotate moves the audit log out of the way when it grows too large.
+func (l *Log) rotate() error {
+	info, err := os.Stat(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if info.Size() < maxSize {
+		return nil
+	}
+
+	return os.Rename(l.path, l.path+".old")
+}
@@ -0,0 +1,80 @@
+package auditlog_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/auditlog"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		existing  string
+		largeLog  bool
+		breakFile bool
+		nilLog    bool
+
+		wantLines   int
+		wantRotated bool
+	}{
+		"Success creating the audit log":       {wantLines: 2},
+		"Success appending to the audit log":   {existing: "2024-01-01T00:00:00Z test: old entry\n", wantLines: 3},
+		"Success rotating a large audit log":   {largeLog: true, wantLines: 2, wantRotated: true},
+		"Success doing nothing with a nil log": {nilLog: true},
+
+		"Error is only logged when the file cannot be written": {breakFile: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, consts.AuditLogFileName)
+
+			if tc.existing != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tc.existing), 0600), "Setup: could not write existing audit log")
+			}
+			if tc.largeLog {
+				require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 2<<20)), 0600), "Setup: could not write large audit log")
+			}
+			if tc.breakFile {
+				require.NoError(t, os.MkdirAll(path, 0700), "Setup: could not create directory to interfere with the audit log")
+			}
+
+			l := auditlog.New(dir)
+			if tc.nilLog {
+				l = nil
+			}
+
+			l.Record(ctx, "hostname", "changed from \"A\" to \"B\"")
+			l.Record(ctx, "hostname", "changed from \"B\"\nto \"C\"")
+
+			if tc.breakFile {
+				return
+			}
+
+			out, err := os.ReadFile(path)
+			if tc.wantLines == 0 {
+				require.ErrorIs(t, err, os.ErrNotExist, "Nothing should have been written")
+				return
+			}
+			require.NoError(t, err, "Audit log should exist")
+
+			lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+			require.Len(t, lines, tc.wantLines, "Unexpected number of entries in the audit log")
+			require.Contains(t, lines[len(lines)-1], `hostname: changed from "B" to "C"`, "Entries should be single-line")
+
+			_, err = os.Stat(path + ".old")
+			require.Equal(t, tc.wantRotated, err == nil, "Unexpected rotation of the audit log")
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		timer   time.Duration
+		advance time.Duration
+		stop    bool
+
+		wantFired   bool
+		wantWaiters int
+	}{
+		"Timer fires once the clock reaches it":   {timer: time.Minute, advance: time.Minute, wantFired: true},
+		"Timer fires once the clock goes past it": {timer: time.Minute, advance: time.Hour, wantFired: true},
+		"Timer with no duration fires right away": {wantFired: true},
+
+		"Timer does not fire before the clock reaches it": {timer: time.Hour, advance: time.Minute, wantWaiters: 1},
+		"Timer does not fire once stopped":                {timer: time.Minute, advance: time.Hour, stop: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := clock.NewMock(start)
+			timer := c.NewTimer(tc.timer)
+
+			if tc.stop {
+				require.True(t, timer.Stop(), "Stop should return true for a pending timer")
+				require.False(t, timer.Stop(), "Stop should return false for a stopped timer")
+			}
+
+			c.Advance(tc.advance)
+			require.Equal(t, start.Add(tc.advance), c.Now(), "Now should have moved forward by the advanced duration")
+			require.Equal(t, tc.wantWaiters, c.Waiters(), "Unexpected number of pending timers")
+
+			select {
+			case got := <-timer.C():
+				require.True(t, tc.wantFired, "Timer should not have fired")
+				require.False(t, got.Before(start.Add(tc.timer)), "Timer should not fire before its deadline")
+			default:
+				require.False(t, tc.wantFired, "Timer should have fired")
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Mock is a clock that only moves forward when Advance is called. It is meant for tests, so that
+// they do not need to sleep.
+type Mock struct {
+	now    time.Time
+	timers []*timerMock
+	mu     sync.Mutex
+}
+
+// NewMock creates a clock stopped at the given time.
+func NewMock(now time.Time) *Mock {
+	return &Mock{now: now}
+}
+
+// Now returns the time the clock is stopped at.
+func (c *Mock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After returns a channel that receives the time once the clock is advanced by the duration.
+func (c *Mock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer creates a timer that fires once the clock is advanced by the duration. A timer with a
+// duration that is not positive fires right away.
+func (c *Mock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &timerMock{
+		clock:    c,
+		deadline: c.now.Add(d),
+		ch:       make(chan time.Time, 1),
+	}
+
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by the duration, firing every timer that is due.
+func (c *Mock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.ch <- c.now
+	}
+	c.timers = pending
+}
+
+// Waiters returns how many timers are waiting for the clock to be advanced. Tests can use it to
+// know when the code under test started waiting.
+func (c *Mock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// stop removes a timer. It returns false if it fired or was stopped already.
+func (c *Mock) stop(t *timerMock) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.timers {
+		if c.timers[i] == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type timerMock struct {
+	clock    *Mock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func (t *timerMock) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *timerMock) Stop() bool {
+	return t.clock.stop(t)
+}
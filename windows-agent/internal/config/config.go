@@ -8,12 +8,17 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/hoststate"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/tasks"
 	"github.com/ubuntu/decorate"
 )
@@ -27,12 +32,37 @@ type Config struct {
 	// disk backing
 	storagePath string
 
+	// registryReadOnly is true when the agent cannot write to the registry.
+	// It is not stored on disk, as it is refreshed every time the registry is read.
+	registryReadOnly bool
+
+	// unknownRegistryFields are the fields in the registry key that the agent does not recognize.
+	unknownRegistryFields []string
+
+	// orgFeatures are the feature flags set in the registry.
+	orgFeatures map[features.Flag]bool
+
+	// orgMaintenanceWindow is the maintenance window set in the registry.
+	orgMaintenanceWindow string
+
+	// host reports whether the host is on battery or on a metered connection.
+	host HostState
+
+	// loadedAt is when the config file was last read or written. The data in memory is reused
+	// instead of reading the file again until cacheTTL has passed. A zero time forces a read.
+	loadedAt time.Time
+	cacheTTL time.Duration
+
+	// clock tells when the config was loaded and modified.
+	clock clock.Clock
+
 	// Sync
 	mu *sync.Mutex
 
 	// observers are notified after any configuration changes.
 	notifyLandsape  LandscapeNotifier
 	notifyUbuntuPro UbuntuProNotifier
+	notifyPause     PauseNotifier
 }
 
 // UbuntuProNotifier is a function that is called when the Ubuntu Pro subscription changes.
@@ -47,17 +77,116 @@ type LandscapeNotifier func(ctx context.Context, config string, uid string)
 type configState struct {
 	Subscription subscription
 	Landscape    landscapeConf
+
+	// Features are the feature flags set by the user.
+	Features map[features.Flag]bool `yaml:",omitempty"`
+
+	// MaintenanceWindow is the daily window during which non-urgent tasks can run, such as "02:00-05:00".
+	MaintenanceWindow string `yaml:",omitempty"`
+
+	// RunOnBattery allows non-urgent tasks to run while the host is on battery.
+	RunOnBattery bool `yaml:",omitempty"`
+
+	// RunOnMetered allows non-urgent tasks to run while the host is on a metered connection.
+	RunOnMetered bool `yaml:",omitempty"`
+
+	// Backup is the schedule for periodic distro backups.
+	Backup BackupSchedule `yaml:",omitempty"`
+
+	// Compact is the schedule for periodic compaction of the virtual disks of the distros.
+	Compact CompactSchedule `yaml:",omitempty"`
+
+	// Recurring are the tasks submitted periodically to every distro.
+	Recurring []RecurringTask `yaml:",omitempty"`
+
+	// WSL contains the settings written to the wsl.conf of each distro, indexed by distro name.
+	WSL map[string]WSLSettings `yaml:",omitempty"`
+
+	// DistroTokens are the Ubuntu Pro tokens assigned to specific distros instead of the
+	// global subscription, indexed by distro name.
+	DistroTokens map[string]string `yaml:",omitempty"`
+
+	// Paused holds back all automatic behaviour of the agent until it is resumed.
+	Paused bool `yaml:",omitempty"`
+
+	// StoreEntitlement is the last known state of the Microsoft Store subscription.
+	StoreEntitlement StoreEntitlement `yaml:",omitempty"`
+
+	// MutedNotifications are the categories of notifications the user opted out of.
+	MutedNotifications []string `yaml:",omitempty"`
+
+	// RequiredApprovals are the categories of operations the user must approve before they run.
+	RequiredApprovals []string `yaml:",omitempty"`
+
+	// LogLevel overrides the verbosity the agent was started with: either "warning", "info" or "debug".
+	// It can be changed without restarting the agent by reloading the configuration.
+	LogLevel string `yaml:",omitempty"`
+
+	// LegacyImported is true once the settings left behind by older tooling were imported.
+	LegacyImported bool `yaml:",omitempty"`
+}
+
+// HostState reports the conditions of the Windows host that hold back non-urgent tasks.
+type HostState interface {
+	OnBattery(context.Context) (bool, error)
+	Metered(context.Context) (bool, error)
+}
+
+// defaultCacheTTL is how long the config file is trusted not to have changed behind the agent's back.
+const defaultCacheTTL = 5 * time.Second
+
+type options struct {
+	host     HostState
+	cacheTTL time.Duration
+	clock    clock.Clock
+}
+
+// Option is an optional argument for New.
+type Option func(*options)
+
+// WithHostState overrides how the state of the Windows host is queried.
+func WithHostState(h HostState) Option {
+	return func(o *options) {
+		o.host = h
+	}
+}
+
+// WithCacheTTL overrides how long the config file is cached for. Zero disables the cache.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.cacheTTL = ttl
+	}
+}
+
+// WithClock overrides the clock used to expire the cached config and to date the changes.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
 }
 
 // New creates and initializes a new Config object.
-func New(ctx context.Context, cachePath string) (m *Config) {
+func New(ctx context.Context, cachePath string, args ...Option) (m *Config) {
+	opts := options{
+		host:     hoststate.New(),
+		cacheTTL: defaultCacheTTL,
+		clock:    clock.Real(),
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+
 	m = &Config{
 		storagePath: filepath.Join(cachePath, "config"),
 		mu:          &sync.Mutex{},
+		host:        opts.host,
+		cacheTTL:    opts.cacheTTL,
+		clock:       opts.clock,
 
 		// No-ops to avoid nil checks
 		notifyUbuntuPro: func(ctx context.Context, token string) {},
 		notifyLandsape:  func(ctx context.Context, config string, uid string) {},
+		notifyPause:     func(ctx context.Context, paused bool) {},
 	}
 
 	return m
@@ -90,6 +219,49 @@ func (c *Config) Subscription() (token string, source Source, err error) {
 	return token, source, nil
 }
 
+// SubscriptionSourceDetails describes the state of one of the sources of Ubuntu Pro tokens.
+type SubscriptionSourceDetails struct {
+	Source Source
+
+	// HasToken is true if this source provides a token.
+	HasToken bool
+
+	// Shadowed is true if this source provides a token but a higher priority source overrides it.
+	Shadowed bool
+
+	// Modified is the last time this source changed. It is the zero time if it is unknown.
+	Modified time.Time
+}
+
+// SubscriptionDetails explains where the active Ubuntu Pro subscription comes from.
+type SubscriptionDetails struct {
+	// Active is the source of the token in use.
+	Active Source
+
+	// Sources contains the details of every source, sorted from highest to lowest priority.
+	Sources []SubscriptionSourceDetails
+
+	// RegistryReadOnly is true if the agent cannot write to the registry.
+	RegistryReadOnly bool
+}
+
+// SubscriptionDetails returns the active subscription source along with the state of every
+// other source, so that users can find out why their token is (or is not) being used.
+func (c *Config) SubscriptionDetails() (details SubscriptionDetails, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return details, fmt.Errorf("config: could not get Ubuntu Pro subscription details: %v", err)
+	}
+
+	_, details.Active = c.configState.Subscription.resolve()
+	details.Sources = c.configState.Subscription.details()
+	details.RegistryReadOnly = c.registryReadOnly
+
+	return details, nil
+}
+
 // ProvisioningTasks returns a slice of all tasks to be submitted upon first contact with a distro.
 func (c *Config) ProvisioningTasks(ctx context.Context, distroName string) ([]task.Task, error) {
 	var taskList []task.Task
@@ -100,13 +272,34 @@ func (c *Config) ProvisioningTasks(ctx context.Context, distroName string) ([]ta
 		return nil, fmt.Errorf("config: could not get provisioning tasks: %v", err)
 	}
 
+	// WSL settings
+	taskList = append(taskList, s.WSL[distroName].Task())
+
 	// Ubuntu Pro attachment
-	proToken, _ := s.Subscription.resolve()
-	taskList = append(taskList, tasks.ProAttachment{Token: proToken})
+	taskList = append(taskList, tasks.ProAttachment{Token: s.distroSubscription(distroName)})
 
 	// Landscape config
-	lconf, _ := s.Landscape.resolve()
-	taskList = append(taskList, tasks.LandscapeConfigure{Config: lconf, HostagentUID: s.Landscape.UID})
+	lconf, uid := s.Landscape.distroConfig(distroName)
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Warningf(ctx, "Config: could not get host name to expand the Landscape configuration: %v", err)
+	}
+
+	lconf, err = LandscapeTemplate{DistroName: distroName, Hostname: hostname, UID: uid}.Expand(lconf)
+	if err != nil {
+		// Registering with a half-expanded configuration would be worse than not registering at all.
+		log.Warningf(ctx, "Config: skipping Landscape configuration of distro %q: %v", distroName, err)
+		return taskList, nil
+	}
+
+	override := s.Landscape.distroOverride(distroName)
+	taskList = append(taskList, tasks.LandscapeConfigure{
+		Config:        lconf,
+		HostagentUID:  uid,
+		Tags:          override.Tags,
+		ComputerTitle: override.ComputerTitle,
+		ScriptUsers:   override.ScriptUsers,
+	})
 
 	return taskList, nil
 }
@@ -127,6 +320,16 @@ func (c *Config) LandscapeClientConfig() (string, Source, error) {
 func (c *Config) SetUserSubscription(ctx context.Context, proToken string) (err error) {
 	defer decorate.OnError(&err, "config: could not set user-provided Ubuntu Pro subscription")
 
+	mode, err := c.ManagedMode()
+	if err != nil {
+		return err
+	}
+
+	// A read-only registry means that the organization governs the agent via policy.
+	if mode.RegistryReadOnly || mode.Subscription {
+		return ManagedError{Setting: "the Ubuntu Pro subscription", Mode: mode}
+	}
+
 	s, err := c.get()
 	if err != nil {
 		return fmt.Errorf("could not get exiting Ubuntu Pro subscription: %v", err)
@@ -136,7 +339,7 @@ func (c *Config) SetUserSubscription(ctx context.Context, proToken string) (err
 		return errors.New("higher priority subscription active")
 	}
 
-	isNew, err := c.set(&c.configState.Subscription.User, proToken)
+	isNew, err := c.set(&c.configState.Subscription.User, proToken, &c.configState.Subscription.UserModified)
 	if err != nil {
 		return err
 	}
@@ -161,7 +364,7 @@ func (c *Config) SetStoreSubscription(ctx context.Context, proToken string) (err
 		return errors.New("higher priority subscription active")
 	}
 
-	isNew, err := c.set(&c.configState.Subscription.Store, proToken)
+	isNew, err := c.set(&c.configState.Subscription.Store, proToken, &c.configState.Subscription.StoreModified)
 	if err != nil {
 		return err
 	}
@@ -175,11 +378,21 @@ func (c *Config) SetStoreSubscription(ctx context.Context, proToken string) (err
 
 // SetUserLandscapeConfig overwrites the value of the user-provided Landscape configuration.
 func (c *Config) SetUserLandscapeConfig(ctx context.Context, landscapeConfig string) error {
-	if _, src := c.Landscape.resolve(); src > SourceUser {
-		return errors.New("attempted to set a user-provided landscape configuration when there already is a higher priority one")
+	mode, err := c.ManagedMode()
+	if err != nil {
+		return fmt.Errorf("config: could not set Landscape configuration: %v", err)
+	}
+
+	// A read-only registry means that the organization governs the agent via policy.
+	if mode.RegistryReadOnly || mode.Landscape {
+		return ManagedError{Setting: "the Landscape configuration", Mode: mode}
 	}
 
-	isNew, err := c.set(&c.Landscape.UserConfig, landscapeConfig)
+	if err := ValidateLandscapeTemplate(landscapeConfig); err != nil {
+		return fmt.Errorf("config: could not set Landscape configuration: %v", err)
+	}
+
+	isNew, err := c.set(&c.Landscape.UserConfig, landscapeConfig, nil)
 	if err != nil {
 		return errors.New("config: could not set Landscape configuration")
 	}
@@ -193,7 +406,7 @@ func (c *Config) SetUserLandscapeConfig(ctx context.Context, landscapeConfig str
 
 // SetLandscapeAgentUID overrides the Landscape agent UID.
 func (c *Config) SetLandscapeAgentUID(uid string) error {
-	if _, err := c.set(&c.Landscape.UID, uid); err != nil {
+	if _, err := c.set(&c.Landscape.UID, uid, nil); err != nil {
 		return fmt.Errorf("config: could not set Landscape agent UID: %v", err)
 	}
 
@@ -211,8 +424,9 @@ func (c *Config) get() (s configState, err error) {
 	return c.configState, nil
 }
 
-// set is a generic method to safely modify the config.
-func (c *Config) set(field *string, value string) (bool, error) {
+// set is a generic method to safely modify the config. If modified is not nil,
+// it is updated with the current time when the value changes.
+func (c *Config) set(field *string, value string, modified *time.Time) (bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -227,8 +441,17 @@ func (c *Config) set(field *string, value string) (bool, error) {
 
 	*field = value
 
+	var oldModified time.Time
+	if modified != nil {
+		oldModified = *modified
+		*modified = c.clock.Now()
+	}
+
 	if err := c.dump(); err != nil {
 		*field = old
+		if modified != nil {
+			*modified = oldModified
+		}
 		return false, err
 	}
 
@@ -255,6 +478,18 @@ func (c *Config) LandscapeAgentUID() (string, error) {
 // RegistryData contains the data that the Ubuntu Pro registry key can provide.
 type RegistryData struct {
 	UbuntuProToken, LandscapeConfig string
+
+	// ReadOnly is true if the agent is not allowed to write to the registry key.
+	ReadOnly bool
+
+	// UnknownFields contains the names of the fields in the registry key that the agent does not use.
+	UnknownFields []string
+
+	// Features contains the feature flags set in the registry.
+	Features map[features.Flag]bool
+
+	// MaintenanceWindow is the maintenance window set in the registry.
+	MaintenanceWindow string
 }
 
 // UpdateRegistryData takes in data from the registry and applies it as necessary.
@@ -272,14 +507,22 @@ func (c *Config) UpdateRegistryData(ctx context.Context, data RegistryData, db *
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// New registry data is a good moment to pick up any external change to the file.
+	c.invalidateCache()
 	if err := c.load(); err != nil {
 		return err
 	}
 
+	c.registryReadOnly = data.ReadOnly
+	c.unknownRegistryFields = data.UnknownFields
+	c.orgFeatures = data.Features
+	c.orgMaintenanceWindow = data.MaintenanceWindow
+
 	// Ubuntu Pro subscription
 	c.configState.Subscription.Organization = data.UbuntuProToken
 	if hasChanged(data.UbuntuProToken, &c.configState.Subscription.Checksum) {
 		log.Debug(ctx, "Config: new Ubuntu Pro subscription received from the registry")
+		c.configState.Subscription.OrganizationModified = c.clock.Now()
 
 		// We must resolve the subscription in case a lower priority token becomes active
 		resolv, _ := c.configState.Subscription.resolve()
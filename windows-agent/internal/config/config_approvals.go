@@ -0,0 +1,59 @@
+package config
+
+import (
+	"slices"
+
+	"github.com/ubuntu/decorate"
+)
+
+// ApprovalRequired returns true if the user asked to approve the given category of operations before they run.
+func (c *Config) ApprovalRequired(category string) (bool, error) {
+	s, err := c.get()
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(s.RequiredApprovals, category), nil
+}
+
+// RequiredApprovals returns the categories of operations the user asked to approve before they run.
+func (c *Config) RequiredApprovals() ([]string, error) {
+	s, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(s.RequiredApprovals), nil
+}
+
+// SetApprovalRequired sets whether the given category of operations must be approved by the user before they run.
+func (c *Config) SetApprovalRequired(category string, required bool) (err error) {
+	defer decorate.OnError(&err, "config: could not set approval preference for %q", category)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	old := c.configState.RequiredApprovals
+	categories := slices.DeleteFunc(slices.Clone(old), func(s string) bool { return s == category })
+	if required {
+		categories = append(categories, category)
+		slices.Sort(categories)
+	}
+
+	if slices.Equal(categories, old) {
+		return nil
+	}
+
+	c.configState.RequiredApprovals = categories
+
+	if err := c.dump(); err != nil {
+		c.configState.RequiredApprovals = old
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ubuntu/decorate"
+)
+
+// BackupSchedule configures the periodic export of every managed distro. A zero interval disables
+// periodic backups.
+type BackupSchedule struct {
+	// Interval is the minimum time between two backups of the same distro. Zero disables the backups.
+	Interval time.Duration `yaml:",omitempty"`
+
+	// Destination is the directory the backups are exported to. If empty, the agent's default
+	// backup directory is used.
+	Destination string `yaml:",omitempty"`
+
+	// Keep is how many backups of each distro are kept. Older backups are removed. Zero keeps them all.
+	Keep int `yaml:",omitempty"`
+}
+
+// minBackupInterval is the shortest interval allowed between backups, to avoid exporting distros
+// over and over.
+const minBackupInterval = time.Hour
+
+// IsZero returns true if no setting of the schedule is set.
+func (b BackupSchedule) IsZero() bool {
+	return b == BackupSchedule{}
+}
+
+// Enabled returns true if periodic backups are enabled, which only depends on the interval.
+func (b BackupSchedule) Enabled() bool {
+	return b.Interval != 0
+}
+
+// validate returns an error if the schedule cannot be used.
+func (b BackupSchedule) validate() error {
+	if b.Keep < 0 {
+		return errors.New("the number of backups to keep cannot be negative")
+	}
+
+	// The destination and the number of backups to keep can be set ahead of enabling the backups.
+	if b.Enabled() && b.Interval < minBackupInterval {
+		return fmt.Errorf("the interval must be at least %s", minBackupInterval)
+	}
+
+	return nil
+}
+
+// BackupSchedule returns the schedule for periodic backups.
+func (c *Config) BackupSchedule() (BackupSchedule, error) {
+	s, err := c.get()
+	if err != nil {
+		return BackupSchedule{}, fmt.Errorf("config: could not get backup schedule: %v", err)
+	}
+
+	return s.Backup, nil
+}
+
+// SetBackupSchedule overwrites the schedule for periodic backups. A zero interval disables them.
+func (c *Config) SetBackupSchedule(b BackupSchedule) (err error) {
+	defer decorate.OnError(&err, "config: could not set backup schedule")
+
+	if err := b.validate(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	old := c.Backup
+	c.Backup = b
+
+	if err := c.dump(); err != nil {
+		c.Backup = old
+		return err
+	}
+
+	return nil
+}
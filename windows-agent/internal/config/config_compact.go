@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ubuntu/decorate"
+)
+
+// CompactSchedule configures the periodic compaction of the virtual disk of every managed distro.
+// The zero value disables periodic compaction.
+type CompactSchedule struct {
+	// Interval is the minimum time between two compactions of the same distro.
+	Interval time.Duration `yaml:",omitempty"`
+}
+
+// minCompactInterval is the shortest interval allowed between compactions, as compacting a disk
+// takes long and keeps the distro from starting.
+const minCompactInterval = 24 * time.Hour
+
+// IsZero returns true if periodic compaction is disabled.
+func (c CompactSchedule) IsZero() bool {
+	return c == CompactSchedule{}
+}
+
+// validate returns an error if the schedule cannot be used.
+func (c CompactSchedule) validate() error {
+	if c.IsZero() {
+		return nil
+	}
+
+	if c.Interval < minCompactInterval {
+		return fmt.Errorf("the interval must be at least %s", minCompactInterval)
+	}
+
+	return nil
+}
+
+// CompactSchedule returns the schedule for periodic disk compaction.
+func (c *Config) CompactSchedule() (CompactSchedule, error) {
+	s, err := c.get()
+	if err != nil {
+		return CompactSchedule{}, fmt.Errorf("config: could not get compaction schedule: %v", err)
+	}
+
+	return s.Compact, nil
+}
+
+// SetCompactSchedule overwrites the schedule for periodic disk compaction. A zero schedule disables it.
+func (c *Config) SetCompactSchedule(s CompactSchedule) (err error) {
+	defer decorate.OnError(&err, "config: could not set compaction schedule")
+
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	old := c.Compact
+	c.Compact = s
+
+	if err := c.dump(); err != nil {
+		c.Compact = old
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/ubuntu/decorate"
+)
+
+// distroSubscriptions returns the Ubuntu Pro tokens assigned to specific distros. They are ignored
+// while the organization provides the subscription, so that its policy applies to every distro.
+func (s configState) distroSubscriptions() map[string]string {
+	if _, src := s.Subscription.resolve(); src == SourceRegistry {
+		return nil
+	}
+	return s.DistroTokens
+}
+
+// distroSubscription returns the Ubuntu Pro token the distro is attached with: the one assigned to
+// it if any, otherwise the global subscription.
+func (s configState) distroSubscription(distroName string) string {
+	if token, ok := s.distroSubscriptions()[distroName]; ok {
+		return token
+	}
+
+	token, _ := s.Subscription.resolve()
+	return token
+}
+
+// DistroSubscription returns the Ubuntu Pro token the specified distro is attached with.
+func (c *Config) DistroSubscription(distroName string) (string, error) {
+	s, err := c.get()
+	if err != nil {
+		return "", fmt.Errorf("config: could not get Ubuntu Pro subscription for distro %q: %v", distroName, err)
+	}
+
+	return s.distroSubscription(distroName), nil
+}
+
+// DistroSubscriptions returns the Ubuntu Pro tokens that override the global subscription, indexed
+// by distro name.
+func (c *Config) DistroSubscriptions() (map[string]string, error) {
+	s, err := c.get()
+	if err != nil {
+		return nil, fmt.Errorf("config: could not get per-distro Ubuntu Pro subscriptions: %v", err)
+	}
+
+	return maps.Clone(s.distroSubscriptions()), nil
+}
+
+// SetDistroSubscription assigns an Ubuntu Pro token to the specified distro instead of the global
+// subscription, such as a FIPS token. An empty token removes the assignment.
+func (c *Config) SetDistroSubscription(ctx context.Context, distroName, proToken string) (err error) {
+	defer decorate.OnError(&err, "config: could not set Ubuntu Pro subscription for distro %q", distroName)
+
+	if distroName == "" {
+		return errors.New("distro name cannot be empty")
+	}
+
+	mode, err := c.ManagedMode()
+	if err != nil {
+		return err
+	}
+
+	if mode.RegistryReadOnly || mode.Subscription {
+		return ManagedError{Setting: "the Ubuntu Pro subscription", Mode: mode}
+	}
+
+	isNew, err := c.setDistroSubscription(distroName, proToken)
+	if err != nil {
+		return err
+	}
+
+	if !isNew {
+		return nil
+	}
+
+	s, err := c.get()
+	if err != nil {
+		return fmt.Errorf("could not notify new subscription: %v", err)
+	}
+
+	// The notification resubmits the subscription to every distro, each with its own token.
+	token, _ := s.Subscription.resolve()
+	c.notifyUbuntuPro(ctx, token)
+
+	return nil
+}
+
+// setDistroSubscription stores the token assigned to the distro and returns true if it changed.
+func (c *Config) setDistroSubscription(distroName, proToken string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return false, err
+	}
+
+	old := c.DistroTokens
+	// Empty tokens are never stored, so a missing assignment is the same as an empty one.
+	if old[distroName] == proToken {
+		return false, nil
+	}
+
+	distros := maps.Clone(old)
+	if distros == nil {
+		distros = make(map[string]string)
+	}
+
+	if proToken == "" {
+		delete(distros, distroName)
+	} else {
+		distros[distroName] = proToken
+	}
+
+	c.DistroTokens = distros
+	if err := c.dump(); err != nil {
+		c.DistroTokens = old
+		return false, err
+	}
+
+	return true, nil
+}
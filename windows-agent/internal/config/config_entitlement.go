@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ubuntu/decorate"
+)
+
+// StoreEntitlement is the last known state of the Microsoft Store subscription. It is kept so that
+// the subscription survives transient outages of the Microsoft Store.
+type StoreEntitlement struct {
+	// JWT is the last user JWT generated by the Microsoft Store.
+	JWT string `yaml:",omitempty"`
+
+	// JWTExpiration is when the JWT expires, according to the clock of this machine.
+	JWTExpiration time.Time `yaml:",omitempty"`
+
+	// SubscriptionExpiration is when the subscription expires. Zero if there is no subscription.
+	SubscriptionExpiration time.Time `yaml:",omitempty"`
+
+	// Checked is the last time the Microsoft Store confirmed the subscription expiration.
+	Checked time.Time `yaml:",omitempty"`
+}
+
+// StoreEntitlement returns the last known state of the Microsoft Store subscription.
+func (c *Config) StoreEntitlement() (StoreEntitlement, error) {
+	s, err := c.get()
+	if err != nil {
+		return StoreEntitlement{}, fmt.Errorf("config: could not get Microsoft Store entitlement: %v", err)
+	}
+
+	return s.StoreEntitlement, nil
+}
+
+// SetStoreEntitlement overwrites the last known state of the Microsoft Store subscription.
+func (c *Config) SetStoreEntitlement(e StoreEntitlement) (err error) {
+	defer decorate.OnError(&err, "config: could not set Microsoft Store entitlement")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	old := c.configState.StoreEntitlement
+	c.configState.StoreEntitlement = e
+
+	if err := c.dump(); err != nil {
+		c.configState.StoreEntitlement = old
+		return err
+	}
+
+	return nil
+}
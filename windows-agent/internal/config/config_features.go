@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
+)
+
+// FeatureFlag is the resolved state of a feature flag.
+type FeatureFlag struct {
+	Flag    features.Flag
+	Enabled bool
+
+	// Source is where the value comes from. SourceNone means that the default value is used.
+	Source Source
+}
+
+// FeatureEnabled returns true if the feature flag is enabled. The registry takes precedence over
+// the config file, and the default value is used if neither sets the flag.
+func (c *Config) FeatureEnabled(flag features.Flag) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return false, fmt.Errorf("config: could not get feature flag %q: %v", flag, err)
+	}
+
+	return c.resolveFeature(flag).Enabled, nil
+}
+
+// FeatureFlags returns the state of every known feature flag, sorted alphabetically.
+func (c *Config) FeatureFlags() ([]FeatureFlag, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("config: could not get feature flags: %v", err)
+	}
+
+	var flags []FeatureFlag
+	for _, f := range features.All() {
+		flags = append(flags, c.resolveFeature(f))
+	}
+
+	return flags, nil
+}
+
+// resolveFeature must be called with the lock held.
+func (c *Config) resolveFeature(flag features.Flag) FeatureFlag {
+	if !flag.Known() {
+		return FeatureFlag{Flag: flag, Source: SourceNone}
+	}
+
+	if v, ok := c.orgFeatures[flag]; ok {
+		return FeatureFlag{Flag: flag, Enabled: v, Source: SourceRegistry}
+	}
+
+	if v, ok := c.configState.Features[flag]; ok {
+		return FeatureFlag{Flag: flag, Enabled: v, Source: SourceUser}
+	}
+
+	return FeatureFlag{Flag: flag, Enabled: flag.Default(), Source: SourceNone}
+}
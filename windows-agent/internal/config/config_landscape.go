@@ -0,0 +1,271 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// LandscapeOverride contains the Landscape settings that apply to a single distro, layered on top of
+// the global Landscape client configuration. Empty fields are not overridden.
+type LandscapeOverride struct {
+	// Tags is a comma-separated list of Landscape tags.
+	Tags string `yaml:",omitempty"`
+
+	// ComputerTitle is the name the distro is registered with, instead of the distro name.
+	ComputerTitle string `yaml:",omitempty"`
+
+	// ScriptUsers is a comma-separated list of the users that Landscape can run scripts as. The
+	// "administrator" user stands for the default user of the distro.
+	ScriptUsers string `yaml:",omitempty"`
+
+	// Endpoint is the name of the Landscape endpoint that manages the distro, instead of the main one.
+	Endpoint string `yaml:",omitempty"`
+}
+
+// LandscapeEndpoint is a Landscape server other than the main one, such as a staging server or
+// the server of another team. The agent keeps a connection to each of them.
+type LandscapeEndpoint struct {
+	// Config is the Landscape client configuration, with the same schema as the main one.
+	Config string
+
+	// UID is the UID assigned to this agent by the endpoint.
+	UID string `yaml:",omitempty"`
+}
+
+// IsZero returns true if the override does not change any setting.
+func (o LandscapeOverride) IsZero() bool {
+	return o == LandscapeOverride{}
+}
+
+// LandscapeDistroOverride returns the Landscape settings that apply only to the specified distro.
+func (c *Config) LandscapeDistroOverride(distroName string) (LandscapeOverride, error) {
+	s, err := c.get()
+	if err != nil {
+		return LandscapeOverride{}, fmt.Errorf("config: could not get Landscape overrides for distro %q: %v", distroName, err)
+	}
+
+	return s.Landscape.distroOverride(distroName), nil
+}
+
+// LandscapeDistroOverrides returns the Landscape settings of every distro that overrides them,
+// indexed by lower-cased distro name.
+func (c *Config) LandscapeDistroOverrides() (map[string]LandscapeOverride, error) {
+	s, err := c.get()
+	if err != nil {
+		return nil, fmt.Errorf("config: could not get Landscape overrides: %v", err)
+	}
+
+	return maps.Clone(s.Landscape.Distros), nil
+}
+
+// SetLandscapeDistroOverride overwrites the Landscape settings that apply only to the specified distro.
+// A zero override removes them.
+func (c *Config) SetLandscapeDistroOverride(ctx context.Context, distroName string, o LandscapeOverride) (err error) {
+	if distroName == "" {
+		return errors.New("config: could not set Landscape overrides: distro name cannot be empty")
+	}
+
+	isNew, err := c.setLandscapeDistroOverride(distroName, o)
+	if err != nil {
+		return fmt.Errorf("config: could not set Landscape overrides for distro %q: %v", distroName, err)
+	}
+
+	if !isNew {
+		return nil
+	}
+
+	s, err := c.get()
+	if err != nil {
+		return fmt.Errorf("config: could not notify new Landscape overrides for distro %q: %v", distroName, err)
+	}
+
+	// The notification resubmits the Landscape configuration to every distro.
+	conf, _ := s.Landscape.resolve()
+	c.notifyLandsape(ctx, conf, s.Landscape.UID)
+
+	return nil
+}
+
+// setLandscapeDistroOverride stores the override and returns true if it changed.
+func (c *Config) setLandscapeDistroOverride(distroName string, o LandscapeOverride) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return false, err
+	}
+
+	// Distro names are case-insensitive, like in the distro database.
+	distroName = strings.ToLower(distroName)
+
+	old := c.Landscape.Distros
+	if old[distroName] == o {
+		return false, nil
+	}
+
+	distros := maps.Clone(old)
+	if distros == nil {
+		distros = make(map[string]LandscapeOverride)
+	}
+
+	if o.IsZero() {
+		delete(distros, distroName)
+	} else {
+		distros[distroName] = o
+	}
+
+	c.Landscape.Distros = distros
+	if err := c.dump(); err != nil {
+		c.Landscape.Distros = old
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LandscapeEndpoints returns the names of the Landscape endpoints other than the main one, sorted.
+func (c *Config) LandscapeEndpoints() ([]string, error) {
+	s, err := c.get()
+	if err != nil {
+		return nil, fmt.Errorf("config: could not get Landscape endpoints: %v", err)
+	}
+
+	names := make([]string, 0, len(s.Landscape.Endpoints))
+	for name := range s.Landscape.Endpoints {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// LandscapeEndpointConfig returns the Landscape client configuration of the endpoint. An empty
+// string is returned if there is no such endpoint.
+func (c *Config) LandscapeEndpointConfig(name string) (string, error) {
+	s, err := c.get()
+	if err != nil {
+		return "", fmt.Errorf("config: could not get configuration of Landscape endpoint %q: %v", name, err)
+	}
+
+	return s.Landscape.Endpoints[name].Config, nil
+}
+
+// LandscapeEndpointUID returns the UID assigned to this agent by the endpoint.
+// An empty string is returned if no UID has been assigned.
+func (c *Config) LandscapeEndpointUID(name string) (string, error) {
+	s, err := c.get()
+	if err != nil {
+		return "", fmt.Errorf("config: could not get UID of Landscape endpoint %q: %v", name, err)
+	}
+
+	return s.Landscape.Endpoints[name].UID, nil
+}
+
+// LandscapeDistroEndpoint returns the name of the Landscape endpoint that manages the distro.
+// An empty string means the main one.
+func (c *Config) LandscapeDistroEndpoint(distroName string) (string, error) {
+	s, err := c.get()
+	if err != nil {
+		return "", fmt.Errorf("config: could not get Landscape endpoint of distro %q: %v", distroName, err)
+	}
+
+	return s.Landscape.distroEndpoint(distroName), nil
+}
+
+// SetLandscapeEndpoint overwrites the Landscape client configuration of the endpoint, creating
+// it if needed. An empty configuration removes the endpoint.
+func (c *Config) SetLandscapeEndpoint(ctx context.Context, name, landscapeConfig string) (err error) {
+	if name == "" {
+		return errors.New("config: could not set Landscape endpoint: name cannot be empty")
+	}
+
+	if err := ValidateLandscapeTemplate(landscapeConfig); err != nil {
+		return fmt.Errorf("config: could not set Landscape endpoint %q: %v", name, err)
+	}
+
+	isNew, err := c.setLandscapeEndpoint(name, func(e LandscapeEndpoint, _ bool) (LandscapeEndpoint, bool, error) {
+		e.Config = landscapeConfig
+		return e, landscapeConfig != "", nil
+	})
+	if err != nil {
+		return fmt.Errorf("config: could not set Landscape endpoint %q: %v", name, err)
+	}
+
+	if !isNew {
+		return nil
+	}
+
+	s, err := c.get()
+	if err != nil {
+		return fmt.Errorf("config: could not notify new Landscape endpoint %q: %v", name, err)
+	}
+
+	// The notification resubmits the Landscape configuration to every distro, and lets the
+	// agent connect to the endpoint.
+	conf, _ := s.Landscape.resolve()
+	c.notifyLandsape(ctx, conf, s.Landscape.UID)
+
+	return nil
+}
+
+// SetLandscapeEndpointUID overrides the UID assigned to this agent by the endpoint.
+func (c *Config) SetLandscapeEndpointUID(name, uid string) error {
+	_, err := c.setLandscapeEndpoint(name, func(e LandscapeEndpoint, exists bool) (LandscapeEndpoint, bool, error) {
+		if !exists {
+			return e, false, errors.New("no such endpoint")
+		}
+		e.UID = uid
+		return e, true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("config: could not set UID of Landscape endpoint %q: %v", name, err)
+	}
+
+	return nil
+}
+
+// setLandscapeEndpoint replaces the endpoint with the result of edit, which receives the current
+// endpoint and whether it exists, and returns the new one and whether it should exist.
+// It returns true if the endpoint changed.
+func (c *Config) setLandscapeEndpoint(name string, edit func(LandscapeEndpoint, bool) (LandscapeEndpoint, bool, error)) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return false, err
+	}
+
+	old := c.Landscape.Endpoints
+	e, exists := old[name]
+	newEndpoint, keep, err := edit(e, exists)
+	if err != nil {
+		return false, err
+	}
+
+	if keep == exists && (!keep || newEndpoint == e) {
+		return false, nil
+	}
+
+	endpoints := maps.Clone(old)
+	if endpoints == nil {
+		endpoints = make(map[string]LandscapeEndpoint)
+	}
+
+	if keep {
+		endpoints[name] = newEndpoint
+	} else {
+		delete(endpoints, name)
+	}
+
+	c.Landscape.Endpoints = endpoints
+	if err := c.dump(); err != nil {
+		c.Landscape.Endpoints = old
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/ubuntu/decorate"
+)
+
+// LegacySettings are the settings left behind by older Ubuntu WSL tooling. Empty fields were not found.
+type LegacySettings struct {
+	UbuntuProToken  string
+	LandscapeConfig string
+}
+
+// LegacyImported returns true if the settings left behind by older tooling were already imported.
+func (c *Config) LegacyImported() (bool, error) {
+	s, err := c.get()
+	if err != nil {
+		return false, fmt.Errorf("config: could not get whether legacy settings were imported: %v", err)
+	}
+
+	return s.LegacyImported, nil
+}
+
+// ImportLegacySettings imports the settings left behind by older tooling as if the user had entered them.
+// Settings the user already has are never overwritten, and the import only happens once: any later call
+// is a no-op, so that settings the user removed do not come back.
+func (c *Config) ImportLegacySettings(ctx context.Context, legacy LegacySettings) (err error) {
+	defer decorate.OnError(&err, "config: could not import legacy settings")
+
+	// We must perform the notifications outside the lock to avoid deadlocks.
+	afterUnlock := []func(){}
+	defer func() {
+		for _, f := range afterUnlock {
+			f()
+		}
+	}()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	if c.configState.LegacyImported {
+		return nil
+	}
+
+	old := c.configState
+
+	if legacy.UbuntuProToken != "" && c.configState.Subscription.User == "" {
+		log.Info(ctx, "Config: importing the Ubuntu Pro subscription left behind by older tooling")
+		c.configState.Subscription.User = legacy.UbuntuProToken
+		c.configState.Subscription.UserModified = c.clock.Now()
+
+		if token, src := c.configState.Subscription.resolve(); src == SourceUser {
+			afterUnlock = append(afterUnlock, func() { c.notifyUbuntuPro(ctx, token) })
+		}
+	}
+
+	if legacy.LandscapeConfig != "" && c.configState.Landscape.UserConfig == "" {
+		if err := ValidateLandscapeTemplate(legacy.LandscapeConfig); err != nil {
+			log.Warningf(ctx, "Config: skipping the Landscape configuration left behind by older tooling: %v", err)
+		} else {
+			log.Info(ctx, "Config: importing the Landscape configuration left behind by older tooling")
+			c.configState.Landscape.UserConfig = legacy.LandscapeConfig
+
+			if conf, src := c.configState.Landscape.resolve(); src == SourceUser {
+				uid := c.configState.Landscape.UID
+				afterUnlock = append(afterUnlock, func() { c.notifyLandsape(ctx, conf, uid) })
+			}
+		}
+	}
+
+	c.configState.LegacyImported = true
+
+	if err := c.dump(); err != nil {
+		c.configState = old
+		afterUnlock = nil
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/ubuntu/decorate"
+)
+
+const day = 24 * time.Hour
+
+// MaintenanceWindow is the daily period of time during which distros can be woken up to run
+// non-urgent tasks. The zero value means that tasks can run at any time.
+type MaintenanceWindow struct {
+	// Start and End are offsets from midnight, in local time. A window whose end is earlier than
+	// its start spans midnight.
+	Start, End time.Duration
+}
+
+// ParseMaintenanceWindow parses a window with the format "HH:MM-HH:MM", such as "02:00-05:00".
+// An empty string is parsed as the zero window.
+func ParseMaintenanceWindow(s string) (w MaintenanceWindow, err error) {
+	defer decorate.OnError(&err, "could not parse maintenance window %q", s)
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return w, nil
+	}
+
+	start, end, found := strings.Cut(s, "-")
+	if !found {
+		return w, errors.New("expected the format HH:MM-HH:MM")
+	}
+
+	if w.Start, err = parseTimeOfDay(start); err != nil {
+		return w, err
+	}
+
+	if w.End, err = parseTimeOfDay(end); err != nil {
+		return w, err
+	}
+
+	if w.Start == w.End {
+		return w, errors.New("the window cannot start and end at the same time")
+	}
+
+	return w, nil
+}
+
+// parseTimeOfDay parses a time with the format HH:MM into the offset from midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: expected the format HH:MM", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// IsZero returns true if the window does not restrict when tasks can run.
+func (w MaintenanceWindow) IsZero() bool {
+	return w == MaintenanceWindow{}
+}
+
+// String implements the fmt.Stringer interface.
+func (w MaintenanceWindow) String() string {
+	if w.IsZero() {
+		return "always"
+	}
+
+	format := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+
+	return format(w.Start) + "-" + format(w.End)
+}
+
+// Check returns true if the window is open at the given time, and how long it takes until it
+// opens or closes.
+func (w MaintenanceWindow) Check(now time.Time) (open bool, next time.Duration) {
+	if w.IsZero() {
+		return true, day
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	offset := now.Sub(midnight)
+
+	// untilOffset is the time until the next occurrence of the target offset.
+	untilOffset := func(target time.Duration) time.Duration {
+		if target > offset {
+			return target - offset
+		}
+		return day - offset + target
+	}
+
+	if w.Start < w.End {
+		open = offset >= w.Start && offset < w.End
+	} else {
+		open = offset >= w.Start || offset < w.End
+	}
+
+	if open {
+		return true, untilOffset(w.End)
+	}
+	return false, untilOffset(w.Start)
+}
+
+// MaintenanceWindow returns the window during which non-urgent tasks can run. The registry
+// takes precedence over the config file.
+func (c *Config) MaintenanceWindow() (w MaintenanceWindow, src Source, err error) {
+	defer decorate.OnError(&err, "config: could not get maintenance window")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return w, SourceNone, err
+	}
+
+	value, src := c.resolveMaintenanceWindow()
+	w, err = ParseMaintenanceWindow(value)
+	if err != nil {
+		return w, src, err
+	}
+
+	return w, src, nil
+}
+
+// resolveMaintenanceWindow must be called with the lock held.
+func (c *Config) resolveMaintenanceWindow() (string, Source) {
+	if c.orgMaintenanceWindow != "" {
+		return c.orgMaintenanceWindow, SourceRegistry
+	}
+
+	if c.configState.MaintenanceWindow != "" {
+		return c.configState.MaintenanceWindow, SourceUser
+	}
+
+	return "", SourceNone
+}
+
+// hostStateRecheck is how often the state of the host is checked again while it holds back tasks.
+const hostStateRecheck = time.Minute
+
+// TaskWindow returns true if non-urgent tasks can run at the given time, and how long it takes
+// until that changes. Tasks are held back outside of the maintenance window, and while the host
+// is on battery or on a metered connection unless the configuration allows it.
+//
+// An invalid maintenance window and failures to query the host do not hold back any task.
+func (c *Config) TaskWindow(now time.Time) (open bool, next time.Duration) {
+	ctx := context.Background()
+
+	w, _, err := c.MaintenanceWindow()
+	if err != nil {
+		log.Warningf(ctx, "%v: tasks can run at any time", err)
+		w = MaintenanceWindow{}
+	}
+
+	open, next = w.Check(now)
+	if !open {
+		return false, next
+	}
+
+	c.mu.Lock()
+	runOnBattery := c.configState.RunOnBattery
+	runOnMetered := c.configState.RunOnMetered
+	c.mu.Unlock()
+
+	if !runOnBattery {
+		if battery, err := c.host.OnBattery(ctx); err != nil {
+			log.Warningf(ctx, "Config: %v", err)
+		} else if battery {
+			log.Debug(ctx, "Config: holding back tasks while the host is on battery")
+			return false, min(next, hostStateRecheck)
+		}
+	}
+
+	if !runOnMetered {
+		if metered, err := c.host.Metered(ctx); err != nil {
+			log.Warningf(ctx, "Config: %v", err)
+		} else if metered {
+			log.Debug(ctx, "Config: holding back tasks while the host is on a metered connection")
+			return false, min(next, hostStateRecheck)
+		}
+	}
+
+	return true, min(next, hostStateRecheck)
+}
@@ -0,0 +1,60 @@
+package config
+
+import "fmt"
+
+// ManagedMode describes which settings are managed by the user's organization, so that the user
+// cannot change them.
+type ManagedMode struct {
+	// RegistryReadOnly is true when the agent cannot write to the registry, meaning that the
+	// organization governs it via policy.
+	RegistryReadOnly bool
+
+	// Subscription is true when the organization provides the Ubuntu Pro subscription.
+	Subscription bool
+
+	// Landscape is true when the organization provides the Landscape configuration.
+	Landscape bool
+}
+
+// Managed returns true if any setting is managed by the organization.
+func (m ManagedMode) Managed() bool {
+	return m != ManagedMode{}
+}
+
+// ManagedError is returned when the user attempts to change a setting managed by their organization.
+type ManagedError struct {
+	// Setting is the name of the setting that could not be changed.
+	Setting string
+
+	// Mode is the managed mode that prevented the change.
+	Mode ManagedMode
+}
+
+// Error implements the error interface.
+func (e ManagedError) Error() string {
+	return fmt.Sprintf("%s is managed by your organization", e.Setting)
+}
+
+// ManagedMode returns which settings are managed by the user's organization.
+func (c *Config) ManagedMode() (ManagedMode, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return ManagedMode{}, fmt.Errorf("config: could not get managed mode: %v", err)
+	}
+
+	return c.managedMode(), nil
+}
+
+// managedMode returns which settings are managed by the organization. The config must be locked.
+func (c *Config) managedMode() ManagedMode {
+	_, subscription := c.configState.Subscription.resolve()
+	_, landscape := c.Landscape.resolve()
+
+	return ManagedMode{
+		RegistryReadOnly: c.registryReadOnly,
+		Subscription:     subscription == SourceRegistry,
+		Landscape:        landscape == SourceRegistry,
+	}
+}
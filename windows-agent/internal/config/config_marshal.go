@@ -1,32 +1,55 @@
 package config
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	"strings"
+	"time"
 
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/ubuntu/decorate"
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	// stagingSuffix is appended to the path of the config file to get the file where new contents are
+	// written before they replace the old ones, so that a failed write never leaves a half-written config.
+	stagingSuffix = ".new"
+
+	// previousSuffix is appended to the path of the config file to get the file that keeps its previous
+	// contents, which are restored if the config file is found corrupted.
+	previousSuffix = ".old"
+
+	// corruptSuffix is appended to the path of the config file to get the file where a corrupted config
+	// is moved to, so that it can be inspected.
+	corruptSuffix = ".corrupt"
+)
+
+// load reads the config file, unless it was read or written recently enough for the data in memory
+// to be up to date. The config must be locked.
 func (c *Config) load() (err error) {
 	defer decorate.OnError(&err, "could not load config from disk")
 
+	if c.cacheValid() {
+		return nil
+	}
+
 	var s configState
 
-	out, err := os.ReadFile(c.storagePath)
-	if errors.Is(err, fs.ErrNotExist) {
-		out = []byte{}
-	} else if err != nil {
-		return fmt.Errorf("could not read config file: %v", err)
+	out, err := c.read()
+	if err != nil {
+		return err
 	}
 
 	if err := yaml.Unmarshal(out, &s); err != nil {
 		return fmt.Errorf("could not umarshal config file: %v", err)
 	}
 
-	// Registry data must not be overridden
+	// Registry data must not be overridden: these are the fields tagged `yaml:"-"`.
 	tokenOrg := c.configState.Subscription.Organization
 	landscapeOrg := c.configState.Landscape.OrgConfig
 
@@ -35,9 +58,33 @@ func (c *Config) load() (err error) {
 	c.configState.Subscription.Organization = tokenOrg
 	c.configState.Landscape.OrgConfig = landscapeOrg
 
+	// The file may have been written by hand or by an older agent with mixed-case distro names.
+	if len(s.Landscape.Distros) > 0 {
+		distros := make(map[string]LandscapeOverride, len(s.Landscape.Distros))
+		for name, o := range s.Landscape.Distros {
+			distros[strings.ToLower(name)] = o
+		}
+		c.configState.Landscape.Distros = distros
+	}
+
+	c.loadedAt = c.clock.Now()
+
 	return nil
 }
 
+// cacheValid returns true if the data in memory can be used without reading the config file.
+func (c *Config) cacheValid() bool {
+	if c.loadedAt.IsZero() {
+		return false
+	}
+	return c.clock.Now().Sub(c.loadedAt) < c.cacheTTL
+}
+
+// invalidateCache forces the next load to read the config file.
+func (c *Config) invalidateCache() {
+	c.loadedAt = time.Time{}
+}
+
 func (c *Config) dump() (err error) {
 	defer decorate.OnError(&err, "could not store config to disk")
 
@@ -46,9 +93,138 @@ func (c *Config) dump() (err error) {
 		return fmt.Errorf("could not marshal config: %v", err)
 	}
 
-	if err := os.WriteFile(c.storagePath, out, 0600); err != nil {
+	if err := c.write(out); err != nil {
+		// The data in memory no longer matches the file.
+		c.invalidateCache()
+		return err
+	}
+
+	c.loadedAt = c.clock.Now()
+
+	return nil
+}
+
+// read returns the contents of the config file. A write that was interrupted halfway is completed, and
+// the previous contents are restored if the config file is corrupted.
+func (c *Config) read() ([]byte, error) {
+	out, err := os.ReadFile(c.storagePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c.completeWrite()
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read config file: %v", err)
+	}
+
+	if parses(out) {
+		return out, nil
+	}
+
+	prev, err := os.ReadFile(c.storagePath + previousSuffix)
+	if err != nil || !parses(prev) {
+		// Nothing to restore: the error is reported when parsing.
+		return out, nil
+	}
+
+	log.Warningf(context.TODO(), "Config: the config file is corrupted: restoring its previous contents and moving it to %s", c.storagePath+corruptSuffix)
+
+	if err := os.Rename(c.storagePath, c.storagePath+corruptSuffix); err != nil {
+		return nil, fmt.Errorf("could not move the corrupted config file aside: %v", err)
+	}
+
+	if err := c.write(prev); err != nil {
+		return nil, err
+	}
+
+	return prev, nil
+}
+
+// completeWrite finishes a write that was interrupted after moving the config file aside, but before
+// moving the new contents in its place. The new contents were already validated at that point.
+func (c *Config) completeWrite() ([]byte, error) {
+	staging := c.storagePath + stagingSuffix
+
+	out, err := os.ReadFile(staging)
+	if err != nil || !parses(out) {
+		// There was no config file yet.
+		return []byte{}, nil
+	}
+
+	// Without previous contents, the write was interrupted before validating the new ones.
+	if _, err := os.Stat(c.storagePath + previousSuffix); err != nil {
+		return []byte{}, nil
+	}
+
+	log.Warning(context.TODO(), "Config: completing a write of the config file that was interrupted")
+
+	if err := os.Rename(staging, c.storagePath); err != nil {
+		return nil, fmt.Errorf("could not complete the interrupted write of the config file: %v", err)
+	}
+
+	return out, nil
+}
+
+// write replaces the contents of the config file without ever leaving it half-written: the contents are
+// written to a staging file and validated, then swapped with the config file, whose contents are kept
+// as the previous ones.
+func (c *Config) write(out []byte) (err error) {
+	staging := c.storagePath + stagingSuffix
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(staging)
+		}
+	}()
+
+	if err := writeSynced(staging, out); err != nil {
+		return fmt.Errorf("could not write config file: %v", err)
+	}
+
+	// A short write would only be noticed when loading the config again.
+	if written, err := os.ReadFile(staging); err != nil {
+		return fmt.Errorf("could not validate config file: %v", err)
+	} else if !bytes.Equal(written, out) {
+		return errors.New("could not validate config file: the contents written do not match")
+	}
+
+	previous := c.storagePath + previousSuffix
+	err = os.Rename(c.storagePath, previous)
+	movedAside := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not keep the previous config file: %v", err)
+	}
+
+	if err := os.Rename(staging, c.storagePath); err != nil {
+		// The config file must not go missing.
+		if movedAside {
+			_ = os.Rename(previous, c.storagePath)
+		}
 		return fmt.Errorf("could not write config file: %v", err)
 	}
 
 	return nil
 }
+
+// writeSynced writes the contents to the file and flushes them to disk.
+func writeSynced(path string, out []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(out); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// parses returns true if the contents are a valid config.
+func parses(out []byte) bool {
+	var s configState
+	return yaml.Unmarshal(out, &s) == nil
+}
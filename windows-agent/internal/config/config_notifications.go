@@ -0,0 +1,59 @@
+package config
+
+import (
+	"slices"
+
+	"github.com/ubuntu/decorate"
+)
+
+// NotificationEnabled returns false if the user opted out of the given category of notifications.
+func (c *Config) NotificationEnabled(category string) (bool, error) {
+	s, err := c.get()
+	if err != nil {
+		return false, err
+	}
+
+	return !slices.Contains(s.MutedNotifications, category), nil
+}
+
+// MutedNotifications returns the categories of notifications the user opted out of.
+func (c *Config) MutedNotifications() ([]string, error) {
+	s, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(s.MutedNotifications), nil
+}
+
+// SetNotificationEnabled opts in or out of the given category of notifications.
+func (c *Config) SetNotificationEnabled(category string, enabled bool) (err error) {
+	defer decorate.OnError(&err, "config: could not set notification preference for %q", category)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	old := c.configState.MutedNotifications
+	muted := slices.DeleteFunc(slices.Clone(old), func(s string) bool { return s == category })
+	if !enabled {
+		muted = append(muted, category)
+		slices.Sort(muted)
+	}
+
+	if slices.Equal(muted, old) {
+		return nil
+	}
+
+	c.configState.MutedNotifications = muted
+
+	if err := c.dump(); err != nil {
+		c.configState.MutedNotifications = old
+		return err
+	}
+
+	return nil
+}
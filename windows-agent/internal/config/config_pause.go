@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+
+	"github.com/ubuntu/decorate"
+)
+
+// PauseNotifier is a function that is called when the agent is paused or resumed.
+type PauseNotifier func(ctx context.Context, paused bool)
+
+// SetPauseNotifier sets the function to be called when the agent is paused or resumed.
+func (c *Config) SetPauseNotifier(notify PauseNotifier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.notifyPause = notify
+}
+
+// Paused returns true if the user paused the automatic behaviour of the agent.
+func (c *Config) Paused() (bool, error) {
+	s, err := c.get()
+	if err != nil {
+		return false, err
+	}
+
+	return s.Paused, nil
+}
+
+// SetPaused pauses or resumes the automatic behaviour of the agent. The state is kept across restarts.
+func (c *Config) SetPaused(ctx context.Context, paused bool) (err error) {
+	defer decorate.OnError(&err, "config: could not set paused state")
+
+	notify, err := c.setPaused(paused)
+	if err != nil {
+		return err
+	}
+
+	// The notifier is called without the lock held, as it may query the config.
+	if notify != nil {
+		notify(ctx, paused)
+	}
+
+	return nil
+}
+
+// setPaused stores the paused state. It returns the notifier to call if the state changed.
+func (c *Config) setPaused(paused bool) (PauseNotifier, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	if c.configState.Paused == paused {
+		return nil, nil
+	}
+
+	c.configState.Paused = paused
+
+	if err := c.dump(); err != nil {
+		c.configState.Paused = !paused
+		return nil, err
+	}
+
+	return c.notifyPause, nil
+}
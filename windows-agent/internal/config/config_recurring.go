@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/cron"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// CatchUpSkip drops the runs of a recurring task that were missed while the machine was
+	// asleep or the agent was not running. It is the default.
+	CatchUpSkip = "skip"
+
+	// CatchUpOnce runs a recurring task once as soon as possible after any number of missed runs.
+	CatchUpOnce = "once"
+)
+
+// RecurringTask is a task submitted to every managed distro on a cron-like schedule.
+type RecurringTask struct {
+	// Name identifies the recurring task, so that its last run can be remembered across restarts.
+	Name string
+
+	// Task is the kind of task to submit, such as "pro-refresh".
+	Task string
+
+	// Schedule is a cron expression with five fields (minute, hour, day of the month, month and
+	// day of the week), or one of @hourly, @daily, @weekly and @monthly.
+	Schedule string
+
+	// CatchUp is what to do when runs were missed: either "skip" or "once". Empty means "skip".
+	CatchUp string `yaml:",omitempty"`
+
+	// Selector restricts the task to the distros with these labels. Empty means every distro.
+	Selector distro.Selector `yaml:",omitempty"`
+}
+
+// validate returns an error if the recurring task cannot be scheduled.
+func (r RecurringTask) validate() error {
+	if r.Name == "" {
+		return errors.New("the name cannot be empty")
+	}
+
+	if r.Task == "" {
+		return errors.New("the kind of task cannot be empty")
+	}
+
+	if _, err := cron.Parse(r.Schedule); err != nil {
+		return err
+	}
+
+	switch r.CatchUp {
+	case "", CatchUpSkip, CatchUpOnce:
+	default:
+		return fmt.Errorf("unknown catch-up policy %q", r.CatchUp)
+	}
+
+	if err := distro.ValidateLabels(r.Selector); err != nil {
+		return fmt.Errorf("invalid selector: %v", err)
+	}
+
+	return nil
+}
+
+// RecurringTasks returns the tasks submitted periodically to every managed distro.
+func (c *Config) RecurringTasks() ([]RecurringTask, error) {
+	s, err := c.get()
+	if err != nil {
+		return nil, fmt.Errorf("config: could not get recurring tasks: %v", err)
+	}
+
+	return slices.Clone(s.Recurring), nil
+}
+
+// SetRecurringTasks overwrites the tasks submitted periodically to every managed distro.
+// An empty list disables them.
+func (c *Config) SetRecurringTasks(recurring []RecurringTask) (err error) {
+	defer decorate.OnError(&err, "config: could not set recurring tasks")
+
+	seen := make(map[string]struct{})
+	for _, r := range recurring {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("recurring task %q: %v", r.Name, err)
+		}
+
+		if _, ok := seen[r.Name]; ok {
+			return fmt.Errorf("recurring task %q is defined more than once", r.Name)
+		}
+		seen[r.Name] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	old := c.Recurring
+	c.Recurring = slices.Clone(recurring)
+
+	if err := c.dump(); err != nil {
+		c.Recurring = old
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Log levels that can be set in the config file.
+const (
+	LogLevelWarning = "warning"
+	LogLevelInfo    = "info"
+	LogLevelDebug   = "debug"
+)
+
+// logLevels are the log levels that can be set in the config file.
+var logLevels = []string{LogLevelWarning, LogLevelInfo, LogLevelDebug}
+
+// LogLevel returns the log level set in the config file, or an empty string if none is set.
+func (c *Config) LogLevel() (string, error) {
+	s, err := c.get()
+	if err != nil {
+		return "", err
+	}
+
+	if s.LogLevel != "" && !slices.Contains(logLevels, s.LogLevel) {
+		return "", fmt.Errorf("config: unknown log level %q", s.LogLevel)
+	}
+
+	return s.LogLevel, nil
+}
+
+// Reload reads the config file again, even if the data in memory would otherwise be trusted for a while.
+// It is meant to pick up the changes made to the file by hand.
+func (c *Config) Reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidateCache()
+	if err := c.load(); err != nil {
+		return fmt.Errorf("config: could not reload: %v", err)
+	}
+
+	return nil
+}
+
+// ReloadedSetting tells whether a setting read again on reload took effect in the running agent.
+type ReloadedSetting struct {
+	Name string
+
+	// RestartRequired is true if the setting only takes effect once the agent restarts.
+	RestartRequired bool
+
+	// Err is why the setting could not be applied, if it could not.
+	Err error
+}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"strings"
+	"time"
+)
+
 // Source indicates the method a configuration parameter was acquired.
 type Source int
 
@@ -23,6 +28,11 @@ type subscription struct {
 	Store        string
 	Organization string `yaml:"-"`
 	Checksum     string
+
+	// Last time each of the sources changed.
+	UserModified         time.Time `yaml:",omitempty"`
+	StoreModified        time.Time `yaml:",omitempty"`
+	OrganizationModified time.Time `yaml:",omitempty"`
 }
 
 func (s subscription) resolve() (string, Source) {
@@ -41,12 +51,65 @@ func (s subscription) resolve() (string, Source) {
 	return "", SourceNone
 }
 
+// details returns the state of every subscription source, sorted from highest to lowest priority.
+func (s subscription) details() []SubscriptionSourceDetails {
+	details := []SubscriptionSourceDetails{
+		{Source: SourceRegistry, HasToken: s.Organization != "", Modified: s.OrganizationModified},
+		{Source: SourceMicrosoftStore, HasToken: s.Store != "", Modified: s.StoreModified},
+		{Source: SourceUser, HasToken: s.User != "", Modified: s.UserModified},
+	}
+
+	// Any source with a token after the first one is shadowed by it.
+	var active bool
+	for i := range details {
+		if !details[i].HasToken {
+			continue
+		}
+		details[i].Shadowed = active
+		active = true
+	}
+
+	return details
+}
+
 type landscapeConf struct {
 	UserConfig string `yaml:"config"`
 	OrgConfig  string `yaml:"-"`
 
 	UID      string
 	Checksum string
+
+	// Distros contains the settings that apply to a single distro, indexed by lower-cased distro name.
+	Distros map[string]LandscapeOverride `yaml:",omitempty"`
+
+	// Endpoints are the Landscape servers other than the main one, indexed by name.
+	Endpoints map[string]LandscapeEndpoint `yaml:",omitempty"`
+}
+
+// distroOverride returns the Landscape settings that apply only to the specified distro.
+// Distro names are case-insensitive.
+func (p landscapeConf) distroOverride(distroName string) LandscapeOverride {
+	return p.Distros[strings.ToLower(distroName)]
+}
+
+// distroEndpoint returns the name of the endpoint the distro is assigned to. Distros assigned to
+// an endpoint that does not exist fall back to the main one, whose name is empty.
+func (p landscapeConf) distroEndpoint(distroName string) string {
+	name := p.distroOverride(distroName).Endpoint
+	if _, ok := p.Endpoints[name]; !ok {
+		return ""
+	}
+	return name
+}
+
+// distroConfig returns the Landscape configuration and UID of the endpoint the distro is assigned to.
+func (p landscapeConf) distroConfig(distroName string) (config, uid string) {
+	if name := p.distroEndpoint(distroName); name != "" {
+		return p.Endpoints[name].Config, p.Endpoints[name].UID
+	}
+
+	conf, _ := p.resolve()
+	return conf, p.UID
 }
 
 func (p landscapeConf) resolve() (string, Source) {
@@ -2,14 +2,22 @@ package config_test
 
 import (
 	"context"
-	"io/fs"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
 	config "github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/tasks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -99,6 +107,512 @@ func TestSubscription(t *testing.T) {
 	}
 }
 
+func TestSubscriptionDetails(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		breakFile        bool
+		settingsState    settingsState
+		registryReadOnly bool
+		setUserToken     bool
+
+		wantActive       config.Source
+		wantHasToken     []config.Source
+		wantShadowed     []config.Source
+		wantUserModified bool
+		wantError        bool
+	}{
+		"Success when there are no tokens": {settingsState: untouched, wantActive: config.SourceNone},
+		"Success when there is a user token": {settingsState: userTokenHasValue, wantActive: config.SourceUser,
+			wantHasToken: []config.Source{config.SourceUser}},
+		"Success when a user token was just set": {settingsState: untouched, setUserToken: true, wantActive: config.SourceUser,
+			wantHasToken: []config.Source{config.SourceUser}, wantUserModified: true},
+		"Success with a read-only registry": {settingsState: userTokenHasValue, registryReadOnly: true, wantActive: config.SourceUser,
+			wantHasToken: []config.Source{config.SourceUser}},
+
+		"Success when a store token shadows a user token": {settingsState: userTokenHasValue | storeTokenHasValue, wantActive: config.SourceMicrosoftStore,
+			wantHasToken: []config.Source{config.SourceMicrosoftStore, config.SourceUser},
+			wantShadowed: []config.Source{config.SourceUser}},
+		"Success when an organization token shadows all other tokens": {settingsState: orgTokenHasValue | userTokenHasValue | storeTokenHasValue, wantActive: config.SourceRegistry,
+			wantHasToken: []config.Source{config.SourceRegistry, config.SourceMicrosoftStore, config.SourceUser},
+			wantShadowed: []config.Source{config.SourceMicrosoftStore, config.SourceUser}},
+
+		"Error when the file cannot be read from": {settingsState: untouched, breakFile: true, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if tc.registryReadOnly {
+				err := conf.UpdateRegistryData(ctx, config.RegistryData{ReadOnly: true}, db)
+				require.NoError(t, err, "Setup: could not set config registry data")
+			}
+
+			before := time.Now()
+			if tc.setUserToken {
+				err := conf.SetUserSubscription(ctx, "user_token")
+				require.NoError(t, err, "Setup: could not set user subscription")
+			}
+
+			details, err := conf.SubscriptionDetails()
+			if tc.wantError {
+				require.Error(t, err, "SubscriptionDetails should return an error")
+				return
+			}
+			require.NoError(t, err, "SubscriptionDetails should return no error")
+
+			require.Equal(t, tc.wantActive, details.Active, "Unexpected active source")
+			require.Equal(t, tc.registryReadOnly, details.RegistryReadOnly, "Unexpected registry read-only status")
+
+			wantOrder := []config.Source{config.SourceRegistry, config.SourceMicrosoftStore, config.SourceUser}
+			require.Len(t, details.Sources, len(wantOrder), "Unexpected number of subscription sources")
+
+			for i, src := range details.Sources {
+				require.Equal(t, wantOrder[i], src.Source, "Sources should be sorted by decreasing priority")
+				require.Equal(t, slices.Contains(tc.wantHasToken, src.Source), src.HasToken, "Unexpected HasToken for source %d", src.Source)
+				require.Equal(t, slices.Contains(tc.wantShadowed, src.Source), src.Shadowed, "Unexpected Shadowed for source %d", src.Source)
+
+				if src.Source == config.SourceUser && tc.wantUserModified {
+					require.False(t, src.Modified.Before(before), "Modification time of the user source should have been updated")
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	const validLandscapeConfig = "[host]\nurl=landscape.canonical.com:8000\n[client]\naccount_name=testuser"
+
+	type problem struct {
+		severity config.Severity
+		field    string
+	}
+
+	testCases := map[string]struct {
+		breakFile     bool
+		settingsState settingsState
+		registryData  config.RegistryData
+		configFile    string
+
+		want      []problem
+		wantError bool
+	}{
+		"Success with an empty config":                  {settingsState: untouched},
+		"Success with a valid config":                   {settingsState: userTokenHasValue, registryData: config.RegistryData{LandscapeConfig: validLandscapeConfig}},
+		"Success when the same token is in two sources": {settingsState: userTokenHasValue, registryData: config.RegistryData{UbuntuProToken: "user_token"}},
+
+		"Warning with unknown registry fields": {settingsState: userTokenHasValue, registryData: config.RegistryData{UnknownFields: []string{"UbuntuProTokn"}},
+			want: []problem{{config.SeverityWarning, "UbuntuProTokn"}}},
+		"Warning with unknown feature flags": {settingsState: untouched, registryData: config.RegistryData{UbuntuProToken: "org_token"},
+			configFile: "features:\n  Telemetry: true\n  NotAFlag: true\n", want: []problem{{config.SeverityWarning, "Features.NotAFlag"}}},
+		"Warning with an unknown log level": {settingsState: untouched, registryData: config.RegistryData{UbuntuProToken: "org_token"},
+			configFile: "loglevel: verbose\n", want: []problem{{config.SeverityWarning, "LogLevel"}}},
+		"Warning when a store token shadows a user token": {settingsState: userTokenHasValue | storeTokenHasValue,
+			want: []problem{{config.SeverityWarning, "Subscription.User"}}},
+		"Warning when an organization token shadows all other tokens": {settingsState: userTokenHasValue | storeTokenHasValue, registryData: config.RegistryData{UbuntuProToken: "org_token"},
+			want: []problem{{config.SeverityWarning, "Subscription.Store"}, {config.SeverityWarning, "Subscription.User"}}},
+
+		"Error when the user Landscape config has no host URL": {settingsState: userTokenHasValue | userLandscapeConfigHasValue,
+			want: []problem{{config.SeverityError, "Landscape.Config"}}},
+		"Error when the organization Landscape config cannot be parsed": {settingsState: userTokenHasValue, registryData: config.RegistryData{LandscapeConfig: "[client"},
+			want: []problem{{config.SeverityError, "LandscapeConfig"}}},
+		"Error when the maintenance window is invalid": {settingsState: userTokenHasValue, registryData: config.RegistryData{MaintenanceWindow: "nights"},
+			want: []problem{{config.SeverityError, "MaintenanceWindow"}}},
+		"Error when the organization Landscape config shadows an invalid user one": {settingsState: userTokenHasValue | userLandscapeConfigHasValue, registryData: config.RegistryData{LandscapeConfig: validLandscapeConfig},
+			want: []problem{{config.SeverityWarning, "Landscape.Config"}, {config.SeverityError, "Landscape.Config"}}},
+		"Error when Landscape is configured without a token": {settingsState: untouched, registryData: config.RegistryData{LandscapeConfig: validLandscapeConfig},
+			want: []problem{{config.SeverityError, "UbuntuProToken"}}},
+
+		"Warning when a distro is assigned to a Landscape endpoint that does not exist": {settingsState: untouched,
+			configFile: "landscape:\n  distros:\n    UBUNTU:\n      endpoint: staging\n", want: []problem{{config.SeverityWarning, "Landscape.Distros.UBUNTU.Endpoint"}}},
+		"Error when a Landscape endpoint has no host URL": {settingsState: untouched,
+			configFile: "landscape:\n  endpoints:\n    staging:\n      config: \"[client]\"\n", want: []problem{{config.SeverityError, "Landscape.Endpoints.staging"}}},
+		"Error when the organization Landscape config has unknown placeholders": {settingsState: userTokenHasValue, registryData: config.RegistryData{LandscapeConfig: validLandscapeConfig + "\ncomputer_title={distro}"},
+			want: []problem{{config.SeverityError, "LandscapeConfig"}}},
+
+		"Error when the file cannot be read from": {settingsState: untouched, breakFile: true, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			_, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, false)
+			if tc.configFile != "" {
+				err := os.WriteFile(filepath.Join(dir, "config"), []byte(tc.configFile), 0600)
+				require.NoError(t, err, "Setup: could not write config file")
+			}
+
+			problems, err := config.ValidateSnapshot(ctx, dir, tc.registryData)
+			if tc.wantError {
+				require.Error(t, err, "ValidateSnapshot should return an error")
+				return
+			}
+			require.NoError(t, err, "ValidateSnapshot should return no error")
+
+			got := make([]problem, 0, len(problems))
+			for _, p := range problems {
+				require.NotEmpty(t, p.Message, "Problems should always have a message")
+				got = append(got, problem{p.Severity, p.Field})
+			}
+
+			if tc.want == nil {
+				tc.want = []problem{}
+			}
+			require.Equal(t, tc.want, got, "Mismatched problems")
+
+			// Validating must not modify the config file.
+			_, err = os.Stat(filepath.Join(dir, "config"))
+			require.Equal(t, tc.settingsState.is(fileExists) || tc.configFile != "", err == nil, "ValidateSnapshot should not create nor remove the config file")
+		})
+	}
+}
+
+func TestFeatureFlags(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		breakFile    bool
+		userFeatures string
+		orgFeatures  map[features.Flag]bool
+
+		wantEnabled []features.Flag
+		wantSources map[features.Flag]config.Source
+		wantError   bool
+	}{
+		"Success with default values":                   {},
+		"Success with a flag set by the user":           {userFeatures: "features:\n  Telemetry: true\n", wantEnabled: []features.Flag{features.Telemetry}, wantSources: map[features.Flag]config.Source{features.Telemetry: config.SourceUser}},
+		"Success with a flag set in the registry":       {orgFeatures: map[features.Flag]bool{features.NewTransport: true}, wantEnabled: []features.Flag{features.NewTransport}, wantSources: map[features.Flag]config.Source{features.NewTransport: config.SourceRegistry}},
+		"Success with the registry overriding the user": {userFeatures: "features:\n  Telemetry: true\n", orgFeatures: map[features.Flag]bool{features.Telemetry: false}, wantSources: map[features.Flag]config.Source{features.Telemetry: config.SourceRegistry}},
+		"Success ignoring unknown flags":                {userFeatures: "features:\n  NotAFlag: true\n"},
+
+		"Error when the file cannot be read from": {breakFile: true, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			_, dir := setUpMockSettings(t, ctx, db, untouched, tc.breakFile, false)
+			if tc.userFeatures != "" {
+				err := os.WriteFile(filepath.Join(dir, "config"), []byte(tc.userFeatures), 0600)
+				require.NoError(t, err, "Setup: could not write config file")
+			}
+
+			conf := config.New(ctx, dir)
+			if tc.orgFeatures != nil {
+				err := conf.UpdateRegistryData(ctx, config.RegistryData{Features: tc.orgFeatures}, db)
+				require.NoError(t, err, "Setup: could not set config registry data")
+			}
+
+			flags, err := conf.FeatureFlags()
+			if tc.wantError {
+				require.Error(t, err, "FeatureFlags should return an error")
+
+				_, err = conf.FeatureEnabled(features.Telemetry)
+				require.Error(t, err, "FeatureEnabled should return an error")
+				return
+			}
+			require.NoError(t, err, "FeatureFlags should return no error")
+			require.Len(t, flags, len(features.All()), "FeatureFlags should return every known flag")
+
+			for _, f := range flags {
+				require.Equal(t, slices.Contains(tc.wantEnabled, f.Flag), f.Enabled, "Unexpected state for flag %q", f.Flag)
+				require.Equal(t, tc.wantSources[f.Flag], f.Source, "Unexpected source for flag %q", f.Flag)
+
+				enabled, err := conf.FeatureEnabled(f.Flag)
+				require.NoError(t, err, "FeatureEnabled should return no error")
+				require.Equal(t, f.Enabled, enabled, "FeatureEnabled should agree with FeatureFlags for flag %q", f.Flag)
+			}
+
+			enabled, err := conf.FeatureEnabled("NotAFlag")
+			require.NoError(t, err, "FeatureEnabled should return no error for unknown flags")
+			require.False(t, enabled, "Unknown flags should never be enabled")
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		before    string
+		after     string
+		breakFile bool
+
+		want            string
+		wantReloadError bool
+		wantError       bool
+	}{
+		"Success picking up a new log level":   {before: "loglevel: info\n", after: "loglevel: debug\n", want: config.LogLevelDebug},
+		"Success picking up a removed setting": {before: "loglevel: info\n", after: "{}\n"},
+		"Success with an unchanged file":       {before: "loglevel: warning\n", after: "loglevel: warning\n", want: config.LogLevelWarning},
+
+		"Error when the log level is unknown":           {before: "loglevel: info\n", after: "loglevel: verbose\n", wantError: true},
+		"Error when the file cannot be read any longer": {before: "loglevel: info\n", breakFile: true, wantReloadError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config")
+
+			err := os.WriteFile(path, []byte(tc.before), 0600)
+			require.NoError(t, err, "Setup: could not write config file")
+
+			// The cache must outlive the test, so that only Reload can pick up the changes.
+			conf := config.New(ctx, dir, config.WithCacheTTL(time.Hour))
+
+			_, err = conf.LogLevel()
+			require.NoError(t, err, "Setup: LogLevel should return no errors")
+
+			if tc.breakFile {
+				require.NoError(t, os.Remove(path), "Setup: could not remove config file")
+				require.NoError(t, os.MkdirAll(path, 0700), "Setup: could not replace config file with a directory")
+			} else {
+				err = os.WriteFile(path, []byte(tc.after), 0600)
+				require.NoError(t, err, "Setup: could not overwrite config file")
+			}
+
+			err = conf.Reload()
+			if tc.wantReloadError {
+				require.Error(t, err, "Reload should return an error")
+				return
+			}
+			require.NoError(t, err, "Reload should return no errors")
+
+			got, err := conf.LogLevel()
+			if tc.wantError {
+				require.Error(t, err, "LogLevel should return an error")
+				return
+			}
+			require.NoError(t, err, "LogLevel should return no errors")
+			require.Equal(t, tc.want, got, "LogLevel should return the value in the reloaded file")
+		})
+	}
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		breakFile  bool
+		userWindow string
+		orgWindow  string
+
+		want       string
+		wantSource config.Source
+		wantError  bool
+	}{
+		"Success with no window":                        {want: "always", wantSource: config.SourceNone},
+		"Success with a window set by the user":         {userWindow: "02:00-05:00", want: "02:00-05:00", wantSource: config.SourceUser},
+		"Success with a window set in the registry":     {orgWindow: "22:30-06:00", want: "22:30-06:00", wantSource: config.SourceRegistry},
+		"Success with the registry overriding the user": {userWindow: "02:00-05:00", orgWindow: "22:30-06:00", want: "22:30-06:00", wantSource: config.SourceRegistry},
+
+		"Error when the file cannot be read from": {breakFile: true, wantError: true},
+		"Error when the window is not valid":      {userWindow: "2am to 5am", wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			_, dir := setUpMockSettings(t, ctx, db, untouched, tc.breakFile, false)
+			if tc.userWindow != "" {
+				err := os.WriteFile(filepath.Join(dir, "config"), []byte("maintenancewindow: "+tc.userWindow+"\n"), 0600)
+				require.NoError(t, err, "Setup: could not write config file")
+			}
+
+			conf := config.New(ctx, dir)
+			if tc.orgWindow != "" {
+				err := conf.UpdateRegistryData(ctx, config.RegistryData{MaintenanceWindow: tc.orgWindow}, db)
+				require.NoError(t, err, "Setup: could not set config registry data")
+			}
+
+			w, src, err := conf.MaintenanceWindow()
+			if tc.wantError {
+				require.Error(t, err, "MaintenanceWindow should return an error")
+
+				open, _ := conf.TaskWindow(time.Now())
+				require.True(t, open, "TaskWindow should not restrict tasks when the window cannot be read")
+				return
+			}
+			require.NoError(t, err, "MaintenanceWindow should return no error")
+			require.Equal(t, tc.want, w.String(), "Unexpected maintenance window")
+			require.Equal(t, tc.wantSource, src, "Unexpected maintenance window source")
+		})
+	}
+}
+
+func TestMaintenanceWindowCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		window string
+		now    string
+
+		wantOpen bool
+		wantNext time.Duration
+		wantErr  bool
+	}{
+		"Open at any time without a window":           {now: "14:00", wantOpen: true, wantNext: 24 * time.Hour},
+		"Open inside the window":                      {window: "02:00-05:00", now: "03:30", wantOpen: true, wantNext: 90 * time.Minute},
+		"Open at the start of the window":             {window: "02:00-05:00", now: "02:00", wantOpen: true, wantNext: 3 * time.Hour},
+		"Closed before the window":                    {window: "02:00-05:00", now: "01:00", wantNext: time.Hour},
+		"Closed after the window":                     {window: "02:00-05:00", now: "05:00", wantNext: 21 * time.Hour},
+		"Open before midnight in an overnight window": {window: "22:00-06:00", now: "23:00", wantOpen: true, wantNext: 7 * time.Hour},
+		"Open after midnight in an overnight window":  {window: "22:00-06:00", now: "01:00", wantOpen: true, wantNext: 5 * time.Hour},
+		"Closed outside of an overnight window":       {window: "22:00-06:00", now: "12:00", wantNext: 10 * time.Hour},
+
+		"Error when the window has no end":          {window: "02:00", wantErr: true},
+		"Error when the time of day is invalid":     {window: "02:00-25:00", wantErr: true},
+		"Error when the window starts when it ends": {window: "02:00-02:00", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w, err := config.ParseMaintenanceWindow(tc.window)
+			if tc.wantErr {
+				require.Error(t, err, "ParseMaintenanceWindow should return an error")
+				return
+			}
+			require.NoError(t, err, "ParseMaintenanceWindow should return no error")
+
+			now, err := time.ParseInLocation("15:04", tc.now, time.Local)
+			require.NoError(t, err, "Setup: could not parse the current time")
+
+			open, next := w.Check(now)
+			require.Equal(t, tc.wantOpen, open, "Unexpected window state")
+			require.Equal(t, tc.wantNext, next, "Unexpected time until the window state changes")
+		})
+	}
+}
+
+func TestTaskWindow(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		configFile string
+		onBattery  bool
+		metered    bool
+		hostErr    bool
+
+		wantOpen bool
+	}{
+		"Open with no restrictions":                     {wantOpen: true},
+		"Open on battery when the user allows it":       {configFile: "runonbattery: true\n", onBattery: true, wantOpen: true},
+		"Open on a metered connection when allowed":     {configFile: "runonmetered: true\n", metered: true, wantOpen: true},
+		"Open when the host state cannot be queried":    {onBattery: true, metered: true, hostErr: true, wantOpen: true},
+		"Closed on battery":                             {onBattery: true},
+		"Closed on a metered connection":                {metered: true},
+		"Closed on a metered connection and on battery": {configFile: "runonbattery: true\n", onBattery: true, metered: true},
+		"Closed outside of the maintenance window":      {configFile: "maintenancewindow: 00:00-00:01\n"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			_, dir := setUpMockSettings(t, ctx, db, untouched, false, false)
+			if tc.configFile != "" {
+				err := os.WriteFile(filepath.Join(dir, "config"), []byte(tc.configFile), 0600)
+				require.NoError(t, err, "Setup: could not write config file")
+			}
+
+			host := hostStateMock{onBattery: tc.onBattery, metered: tc.metered, err: tc.hostErr}
+			conf := config.New(ctx, dir, config.WithHostState(host))
+
+			// Midday is never in the maintenance window of the test cases.
+			now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.Local)
+			open, next := conf.TaskWindow(now)
+			require.Equal(t, tc.wantOpen, open, "Unexpected task window state")
+			require.Positive(t, next, "The task window should always be checked again in the future")
+		})
+	}
+}
+
+type hostStateMock struct {
+	onBattery bool
+	metered   bool
+	err       bool
+}
+
+func (h hostStateMock) OnBattery(context.Context) (bool, error) {
+	if h.err {
+		return false, errors.New("mock error")
+	}
+	return h.onBattery, nil
+}
+
+func (h hostStateMock) Metered(context.Context) (bool, error) {
+	if h.err {
+		return false, errors.New("mock error")
+	}
+	return h.metered, nil
+}
+
 func TestLandscapeConfig(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
@@ -207,31 +721,1156 @@ func TestLandscapeAgentUID(t *testing.T) {
 				return
 			}
 
-			// Test non-default values
-			assert.Equal(t, "landscapeUID1234", v, "LandscapeAgentUID returned an unexpected value")
+			// Test non-default values
+			assert.Equal(t, "landscapeUID1234", v, "LandscapeAgentUID returned an unexpected value")
+		})
+	}
+}
+
+func TestProvisioningTasks(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		settingsState settingsState
+		distroToken   string
+
+		wantToken         string
+		wantLandscapeConf string
+		wantLandscapeUID  string
+
+		wantError bool
+	}{
+		"Success when there is no data":                               {settingsState: untouched},
+		"Success with a token assigned to the distro":                 {settingsState: userTokenHasValue, distroToken: "distro_token", wantToken: "distro_token"},
+		"Success when there is an empty config file":                  {settingsState: fileExists},
+		"Success when the file's pro token field exists but is empty": {settingsState: userTokenExists},
+		"Success with a user token":                                   {settingsState: userTokenHasValue, wantToken: "user_token"},
+		"Success when there is Landscape config":                      {settingsState: userLandscapeConfigHasValue | landscapeUIDHasValue, wantLandscapeConf: "[client]\nuser=JohnDoe", wantLandscapeUID: "landscapeUID1234"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, false, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if tc.distroToken != "" {
+				err := conf.SetDistroSubscription(ctx, "UBUNTU", tc.distroToken)
+				require.NoError(t, err, "Setup: could not assign a token to the distro")
+			}
+
+			gotTasks, err := conf.ProvisioningTasks(ctx, "UBUNTU")
+			if tc.wantError {
+				require.Error(t, err, "ProvisioningTasks should return an error")
+				return
+			}
+			require.NoError(t, err, "ProvisioningTasks should return no error")
+
+			wantTasks := []task.Task{
+				tasks.WSLConfigure{},
+				tasks.ProAttachment{Token: tc.wantToken},
+				tasks.LandscapeConfigure{
+					Config:       tc.wantLandscapeConf,
+					HostagentUID: tc.wantLandscapeUID,
+				},
+			}
+
+			require.ElementsMatch(t, wantTasks, gotTasks, "Unexpected contents returned by ProvisioningTasks")
+		})
+	}
+}
+
+func TestLandscapeTemplate(t *testing.T) {
+	t.Parallel()
+
+	template := config.LandscapeTemplate{DistroName: "Ubuntu-24.04", Hostname: "WINDOWS-PC", UID: "uid1234"}
+
+	testCases := map[string]struct {
+		config string
+
+		want    string
+		wantErr bool
+	}{
+		"Success with an empty config":            {},
+		"Success with no placeholders":            {config: "[client]\naccount_name=testuser", want: "[client]\naccount_name=testuser"},
+		"Success expanding every placeholder":     {config: "[client]\ncomputer_title={distro_name}@{hostname}\ntags={uid}", want: "[client]\ncomputer_title=Ubuntu-24.04@WINDOWS-PC\ntags=uid1234"},
+		"Success expanding repeated placeholders": {config: "[client]\ncomputer_title={distro_name}-{distro_name}", want: "[client]\ncomputer_title=Ubuntu-24.04-Ubuntu-24.04"},
+		"Success ignoring braces in comments":     {config: "# {not_a_placeholder}\n[client]\ncomputer_title={hostname}", want: "# {not_a_placeholder}\n[client]\ncomputer_title=WINDOWS-PC"},
+
+		"Error with unknown placeholders":                   {config: "[client]\ncomputer_title={distro}", wantErr: true},
+		"Error with placeholders out of the client section": {config: "[host]\nurl={hostname}:6554", wantErr: true},
+		"Error with placeholders in an unparsable config":   {config: "[client\ncomputer_title={hostname}", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := config.ValidateLandscapeTemplate(tc.config)
+			got, expandErr := template.Expand(tc.config)
+			if tc.wantErr {
+				require.Error(t, err, "ValidateLandscapeTemplate should return an error")
+				require.Error(t, expandErr, "Expand should return an error")
+				return
+			}
+			require.NoError(t, err, "ValidateLandscapeTemplate should return no error")
+			require.NoError(t, expandErr, "Expand should return no error")
+			require.Equal(t, tc.want, got, "Unexpected expanded config")
+		})
+	}
+}
+
+func TestSetUserSubscription(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		settingsState   settingsState
+		breakFile       bool
+		cannotWriteFile bool
+		emptyToken      bool
+		readOnly        bool
+
+		want        string
+		wantManaged bool
+		wantError   bool
+	}{
+		"Success":                          {settingsState: userTokenHasValue, want: "new_token"},
+		"Success disabling a subscription": {settingsState: userTokenHasValue, emptyToken: true, want: ""},
+
+		"Error when there is a store token active":         {settingsState: storeTokenHasValue, wantError: true},
+		"Error when there is an organization token active": {settingsState: orgTokenHasValue, wantManaged: true, wantError: true},
+		"Error when the registry is read-only":             {settingsState: userTokenHasValue, readOnly: true, wantManaged: true, wantError: true},
+		"Error when the file cannot be opened":             {settingsState: fileExists, breakFile: true, wantError: true},
+		"Error when the file cannot be written":            {settingsState: fileExists, cannotWriteFile: true, wantError: true},
+	}
+
+	//nolint:dupl // This is mostly duplicate with TestSetStoreConfig but de-duplicating with a meta-test worsens readability
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, tc.cannotWriteFile)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			token := "new_token"
+			if tc.emptyToken {
+				token = ""
+			}
+
+			var calledProNotifier int
+			conf.SetUbuntuProNotifier(func(context.Context, string) {
+				calledProNotifier++
+			})
+
+			conf.SetLandscapeNotifier(func(context.Context, string, string) {
+				require.Fail(t, "LandscapeNotifier should not be called")
+			})
+
+			if tc.readOnly {
+				err := conf.UpdateRegistryData(ctx, config.RegistryData{ReadOnly: true}, db)
+				require.NoError(t, err, "Setup: could not set config registry data")
+			}
+
+			err = conf.SetUserSubscription(ctx, token)
+			if tc.wantError {
+				require.Error(t, err, "SetSubscription should return an error")
+				require.Equal(t, tc.wantManaged, errors.As(err, &config.ManagedError{}), "Unexpected error type: %v", err)
+				return
+			}
+			require.NoError(t, err, "SetSubscription should return no error")
+
+			require.Equal(t, 1, calledProNotifier, "ProNotifier should have been called once")
+
+			got, _, err := conf.Subscription()
+			require.NoError(t, err, "ProToken should return no error")
+
+			require.Equal(t, tc.want, got, "ProToken returned an unexpected value for the token")
+
+			// Set the same token again
+			calledProNotifier = 0
+			err = conf.SetUserSubscription(ctx, token)
+			require.NoError(t, err, "SetUserSubscription should return no error")
+			require.Zero(t, calledProNotifier, "ProNotifier should not have been called again")
+		})
+	}
+}
+
+func TestManagedMode(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		settingsState settingsState
+		readOnly      bool
+		breakFile     bool
+
+		want      config.ManagedMode
+		wantError bool
+	}{
+		"Success when nothing is managed":                  {settingsState: userTokenHasValue | userLandscapeConfigHasValue},
+		"Success when the registry is read-only":           {settingsState: userTokenHasValue, readOnly: true, want: config.ManagedMode{RegistryReadOnly: true}},
+		"Success when the organization provides the token": {settingsState: orgTokenHasValue | userTokenHasValue, want: config.ManagedMode{Subscription: true}},
+		"Success when the organization provides Landscape": {settingsState: orgLandscapeConfigHasValue, want: config.ManagedMode{Landscape: true}},
+		"Success when everything is managed": {settingsState: orgTokenHasValue | orgLandscapeConfigHasValue, readOnly: true,
+			want: config.ManagedMode{RegistryReadOnly: true, Subscription: true, Landscape: true}},
+
+		"Error when the file cannot be read": {settingsState: untouched, breakFile: true, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if tc.readOnly {
+				// The registry data is sent as a whole, so the organization's data must be sent again.
+				var d config.RegistryData
+				if tc.settingsState.is(orgTokenHasValue) {
+					d.UbuntuProToken = "org_token"
+				}
+				if tc.settingsState.is(orgLandscapeConfigHasValue) {
+					d.LandscapeConfig = "[client]\nuser=BigOrg"
+				}
+				d.ReadOnly = true
+
+				err := conf.UpdateRegistryData(ctx, d, db)
+				require.NoError(t, err, "Setup: could not set config registry data")
+			}
+
+			got, err := conf.ManagedMode()
+			if tc.wantError {
+				require.Error(t, err, "ManagedMode should return an error")
+				return
+			}
+			require.NoError(t, err, "ManagedMode should return no error")
+
+			require.Equal(t, tc.want, got, "Unexpected managed mode")
+			require.Equal(t, tc.want != config.ManagedMode{}, got.Managed(), "Managed should be true when any setting is managed")
+		})
+	}
+}
+
+func TestSetStoreSubscription(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		settingsState   settingsState
+		breakFile       bool
+		cannotWriteFile bool
+		emptyToken      bool
+
+		want      string
+		wantError bool
+	}{
+		"Success":                          {settingsState: userTokenHasValue, want: "new_token"},
+		"Success disabling a subscription": {settingsState: storeTokenHasValue, emptyToken: true, want: ""},
+		"Success overriding an existing store token": {settingsState: storeTokenHasValue, want: "new_token"},
+
+		"Error when the file cannot be opened":  {settingsState: fileExists, breakFile: true, wantError: true},
+		"Error when the file cannot be written": {settingsState: fileExists, cannotWriteFile: true, wantError: true},
+	}
+
+	//nolint:dupl // This is mostly duplicate with TestSetUserConfig but de-duplicating with a meta-test worsens readability
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, tc.cannotWriteFile)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			token := "new_token"
+			if tc.emptyToken {
+				token = ""
+			}
+
+			var calledProNotifier int
+			conf.SetUbuntuProNotifier(func(context.Context, string) {
+				calledProNotifier++
+			})
+
+			conf.SetLandscapeNotifier(func(context.Context, string, string) {
+				require.Fail(t, "LandscapeNotifier should not be called")
+			})
+
+			err = conf.SetStoreSubscription(ctx, token)
+			if tc.wantError {
+				require.Error(t, err, "SetSubscription should return an error")
+				return
+			}
+			require.NoError(t, err, "SetSubscription should return no error")
+
+			require.Equal(t, 1, calledProNotifier, "ProNotifier should have been called once")
+
+			got, _, err := conf.Subscription()
+			require.NoError(t, err, "ProToken should return no error")
+
+			require.Equal(t, tc.want, got, "ProToken returned an unexpected value for the token")
+
+			// Set the same token again
+			calledProNotifier = 0
+			err = conf.SetStoreSubscription(ctx, token)
+			require.NoError(t, err, "SetStoreSubscription should return no error")
+			require.Zero(t, calledProNotifier, "ProNotifier should not have been called again")
+		})
+	}
+}
+
+func TestSetDistroSubscription(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		settingsState   settingsState
+		emptyDistroName bool
+		previous        string
+		token           string
+		orgTokenAfter   bool
+		breakFile       bool
+		readOnly        bool
+
+		want         string
+		wantNotified bool
+		wantManaged  bool
+		wantError    bool
+	}{
+		"Success":                                     {settingsState: userTokenHasValue, token: "fips_token", want: "fips_token", wantNotified: true},
+		"Success removing the assignment":             {settingsState: userTokenHasValue, previous: "fips_token", want: "user_token", wantNotified: true},
+		"Success when the assignment is the same":     {settingsState: userTokenHasValue, previous: "fips_token", token: "fips_token", want: "fips_token"},
+		"Success with no global subscription":         {settingsState: fileExists, token: "fips_token", want: "fips_token", wantNotified: true},
+		"Success when the organization takes over":    {settingsState: userTokenHasValue, token: "fips_token", orgTokenAfter: true, want: "org_token", wantNotified: true},
+		"Success overriding a store subscription":     {settingsState: storeTokenHasValue, token: "fips_token", want: "fips_token", wantNotified: true},
+		"Error when the distro name is empty":         {settingsState: userTokenHasValue, emptyDistroName: true, token: "fips_token", wantError: true},
+		"Error when there is an organization token":   {settingsState: orgTokenHasValue, token: "fips_token", wantManaged: true, wantError: true},
+		"Error when the registry is read-only":        {settingsState: userTokenHasValue, token: "fips_token", readOnly: true, wantManaged: true, wantError: true},
+		"Error when the configuration cannot be read": {settingsState: fileExists, breakFile: true, token: "fips_token", wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			distroName := "UBUNTU"
+			if tc.emptyDistroName {
+				distroName = ""
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if tc.previous != "" {
+				err := conf.SetDistroSubscription(ctx, distroName, tc.previous)
+				require.NoError(t, err, "Setup: could not assign the previous token")
+			}
+
+			if tc.readOnly {
+				err := conf.UpdateRegistryData(ctx, config.RegistryData{ReadOnly: true}, db)
+				require.NoError(t, err, "Setup: could not set config registry data")
+			}
+
+			var calledProNotifier int
+			conf.SetUbuntuProNotifier(func(context.Context, string) {
+				calledProNotifier++
+			})
+
+			err = conf.SetDistroSubscription(ctx, distroName, tc.token)
+			if tc.wantError {
+				require.Error(t, err, "SetDistroSubscription should return an error")
+				require.Equal(t, tc.wantManaged, errors.As(err, &config.ManagedError{}), "Unexpected error type: %v", err)
+				return
+			}
+			require.NoError(t, err, "SetDistroSubscription should return no error")
+
+			if tc.wantNotified {
+				require.Equal(t, 1, calledProNotifier, "ProNotifier should have been called once")
+			} else {
+				require.Zero(t, calledProNotifier, "ProNotifier should not have been called")
+			}
+
+			if tc.orgTokenAfter {
+				err := conf.UpdateRegistryData(ctx, config.RegistryData{UbuntuProToken: "org_token"}, db)
+				require.NoError(t, err, "Setup: could not set the organization token")
+			}
+
+			got, err := conf.DistroSubscription(distroName)
+			require.NoError(t, err, "DistroSubscription should return no error")
+			require.Equal(t, tc.want, got, "DistroSubscription returned an unexpected token")
+
+			other, err := conf.DistroSubscription("OTHER_DISTRO")
+			require.NoError(t, err, "DistroSubscription should return no error")
+			global, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no error")
+			require.Equal(t, global, other, "Distros without an assigned token should use the global subscription")
+
+			all, err := conf.DistroSubscriptions()
+			require.NoError(t, err, "DistroSubscriptions should return no error")
+			if tc.token == "" || tc.orgTokenAfter {
+				require.Empty(t, all, "DistroSubscriptions should not contain ignored or removed assignments")
+			} else {
+				require.Equal(t, map[string]string{distroName: tc.token}, all, "DistroSubscriptions should contain the assignment")
+			}
+		})
+	}
+}
+
+func TestSetUserLandscapeConfig(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		settingsState   settingsState
+		breakFile       bool
+		readOnly        bool
+		landscapeConfig string
+
+		wantError   bool
+		wantManaged bool
+	}{
+		"Success":                      {settingsState: untouched},
+		"Success with a templated one": {settingsState: untouched, landscapeConfig: "[client]\ncomputer_title={distro_name}-{hostname}"},
+
+		"Error when an organization landscape config is already set": {settingsState: orgLandscapeConfigHasValue, wantManaged: true, wantError: true},
+		"Error when the registry is read-only":                       {settingsState: untouched, readOnly: true, wantManaged: true, wantError: true},
+		"Error when the config has unknown placeholders":             {settingsState: untouched, landscapeConfig: "[client]\ncomputer_title={distro}", wantError: true},
+		"Error when an configuration cannot be read":                 {settingsState: untouched, breakFile: true, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			landscapeConfig := "LANDSCAPE CONFIG"
+			if tc.landscapeConfig != "" {
+				landscapeConfig = tc.landscapeConfig
+			}
+
+			if tc.readOnly {
+				err := conf.UpdateRegistryData(ctx, config.RegistryData{ReadOnly: true}, db)
+				require.NoError(t, err, "Setup: could not set config registry data")
+			}
+
+			var calledLandscapeNotifier int
+			conf.SetUbuntuProNotifier(func(context.Context, string) {
+				require.Fail(t, "UbuntuPro should not be called")
+			})
+
+			conf.SetLandscapeNotifier(func(context.Context, string, string) {
+				calledLandscapeNotifier++
+			})
+
+			err = conf.SetUserLandscapeConfig(ctx, landscapeConfig)
+			if tc.wantError {
+				require.Error(t, err, "SetUserLandscapeConfig should return an error")
+				require.Equal(t, tc.wantManaged, errors.As(err, &config.ManagedError{}), "Unexpected error type: %v", err)
+				return
+			}
+			require.NoError(t, err, "SetUserLandscapeConfig should return no errors")
+
+			got, src, err := conf.LandscapeClientConfig()
+			require.NoError(t, err, "LandscapeClientConfig should return no errors")
+			require.Equal(t, landscapeConfig, got, "Did not get the same value for landscape config as we set")
+			require.Equal(t, config.SourceUser, src, "Did not get the same value for landscape config as we set")
+			require.Equal(t, 1, calledLandscapeNotifier, "LandscapeNotifier should have been called once")
+		})
+	}
+}
+
+func TestSetLandscapeDistroOverride(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	override := config.LandscapeOverride{Tags: "tag1,tag2", ComputerTitle: "My distro", ScriptUsers: "root"}
+
+	testCases := map[string]struct {
+		emptyDistroName   bool
+		previous          config.LandscapeOverride
+		previousLowerCase bool
+		override          config.LandscapeOverride
+		breakFile         bool
+
+		wantNotified bool
+		wantError    bool
+	}{
+		"Success":                               {override: override, wantNotified: true},
+		"Success removing the override":         {previous: override, wantNotified: true},
+		"Success when the override is the same": {previous: override, override: override},
+		"Success replacing the override of the same distro in another case": {previous: config.LandscapeOverride{Tags: "old"}, previousLowerCase: true, override: override, wantNotified: true},
+
+		"Error when the distro name is empty":         {emptyDistroName: true, override: override, wantError: true},
+		"Error when the configuration cannot be read": {breakFile: true, override: override, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			distroName := "UBUNTU"
+			if tc.emptyDistroName {
+				distroName = ""
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userLandscapeConfigHasValue|landscapeUIDHasValue, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if !tc.previous.IsZero() {
+				previousName := distroName
+				if tc.previousLowerCase {
+					previousName = strings.ToLower(distroName)
+				}
+				err := conf.SetLandscapeDistroOverride(ctx, previousName, tc.previous)
+				require.NoError(t, err, "Setup: could not set the previous override")
+			}
+
+			var calledLandscapeNotifier int
+			conf.SetLandscapeNotifier(func(context.Context, string, string) {
+				calledLandscapeNotifier++
+			})
+
+			err = conf.SetLandscapeDistroOverride(ctx, distroName, tc.override)
+			if tc.wantError {
+				require.Error(t, err, "SetLandscapeDistroOverride should return an error")
+				return
+			}
+			require.NoError(t, err, "SetLandscapeDistroOverride should return no errors")
+
+			if tc.wantNotified {
+				require.Equal(t, 1, calledLandscapeNotifier, "LandscapeNotifier should have been called once")
+			} else {
+				require.Zero(t, calledLandscapeNotifier, "LandscapeNotifier should not have been called")
+			}
+
+			// Reload the config from disk to check that the override was stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.LandscapeDistroOverride(distroName)
+			require.NoError(t, err, "LandscapeDistroOverride should return no errors")
+			require.Equal(t, tc.override, got, "Did not get the same override as we set")
+
+			got, err = conf.LandscapeDistroOverride("Ubuntu")
+			require.NoError(t, err, "LandscapeDistroOverride should return no errors")
+			require.Equal(t, tc.override, got, "Distro names should be case-insensitive")
+
+			all, err := conf.LandscapeDistroOverrides()
+			require.NoError(t, err, "LandscapeDistroOverrides should return no errors")
+			if tc.override.IsZero() {
+				require.Empty(t, all, "Removed override should not be listed")
+			} else {
+				require.Equal(t, map[string]config.LandscapeOverride{"ubuntu": tc.override}, all, "Override should be listed once, under the lower-cased distro name")
+			}
+
+			other, err := conf.LandscapeDistroOverride("OTHER")
+			require.NoError(t, err, "LandscapeDistroOverride should return no errors")
+			require.Zero(t, other, "Override should not apply to other distros")
+
+			gotTasks, err := conf.ProvisioningTasks(ctx, distroName)
+			require.NoError(t, err, "ProvisioningTasks should return no error")
+			require.Contains(t, gotTasks, tasks.LandscapeConfigure{
+				Config:        "[client]\nuser=JohnDoe",
+				HostagentUID:  "landscapeUID1234",
+				Tags:          tc.override.Tags,
+				ComputerTitle: tc.override.ComputerTitle,
+				ScriptUsers:   tc.override.ScriptUsers,
+			}, "Provisioning tasks should include the override")
+		})
+	}
+}
+
+func TestSetLandscapeEndpoint(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	const endpointConfig = "[host]\nurl=staging.landscape.example:6554\n[client]\naccount_name=staging"
+
+	testCases := map[string]struct {
+		emptyName bool
+		previous  string
+		config    string
+		breakFile bool
+
+		wantNotified bool
+		wantError    bool
+	}{
+		"Success":                               {config: endpointConfig, wantNotified: true},
+		"Success removing the endpoint":         {previous: endpointConfig, wantNotified: true},
+		"Success when the endpoint is the same": {previous: endpointConfig, config: endpointConfig},
+
+		"Error when the name is empty":                {emptyName: true, config: endpointConfig, wantError: true},
+		"Error when the configuration cannot be read": {breakFile: true, config: endpointConfig, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			endpoint := "staging"
+			if tc.emptyName {
+				endpoint = ""
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userLandscapeConfigHasValue|landscapeUIDHasValue, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if tc.previous != "" {
+				err := conf.SetLandscapeEndpoint(ctx, endpoint, tc.previous)
+				require.NoError(t, err, "Setup: could not set the previous endpoint")
+			}
+
+			var calledLandscapeNotifier int
+			conf.SetLandscapeNotifier(func(context.Context, string, string) {
+				calledLandscapeNotifier++
+			})
+
+			err = conf.SetLandscapeEndpoint(ctx, endpoint, tc.config)
+			if tc.wantError {
+				require.Error(t, err, "SetLandscapeEndpoint should return an error")
+				return
+			}
+			require.NoError(t, err, "SetLandscapeEndpoint should return no errors")
+
+			if tc.wantNotified {
+				require.Equal(t, 1, calledLandscapeNotifier, "LandscapeNotifier should have been called once")
+			} else {
+				require.Zero(t, calledLandscapeNotifier, "LandscapeNotifier should not have been called")
+			}
+
+			// Reload the config from disk to check that the endpoint was stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.LandscapeEndpointConfig(endpoint)
+			require.NoError(t, err, "LandscapeEndpointConfig should return no errors")
+			require.Equal(t, tc.config, got, "Did not get the same endpoint configuration as we set")
+
+			names, err := conf.LandscapeEndpoints()
+			require.NoError(t, err, "LandscapeEndpoints should return no errors")
+			if tc.config == "" {
+				require.Empty(t, names, "Removed endpoint should not be listed")
+			} else {
+				require.Equal(t, []string{endpoint}, names, "Endpoint should be listed")
+			}
+
+			main, _, err := conf.LandscapeClientConfig()
+			require.NoError(t, err, "LandscapeClientConfig should return no errors")
+			require.Equal(t, "[client]\nuser=JohnDoe", main, "The main Landscape configuration should not change")
+		})
+	}
+}
+
+func TestLandscapeDistroEndpoint(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	const endpointConfig = "[host]\nurl=staging.landscape.example:6554\n[client]\naccount_name=staging"
+
+	testCases := map[string]struct {
+		assignTo string
+		noUID    bool
+
+		wantEndpoint string
+		wantConfig   string
+		wantUID      string
+	}{
+		"Success with a distro assigned to an endpoint":   {assignTo: "staging", wantEndpoint: "staging", wantConfig: endpointConfig, wantUID: "stagingUID"},
+		"Success with an endpoint that assigned no UID":   {assignTo: "staging", noUID: true, wantEndpoint: "staging", wantConfig: endpointConfig},
+		"Success with a distro that is not assigned":      {wantConfig: "[client]\nuser=JohnDoe", wantUID: "landscapeUID1234"},
+		"Success with a distro assigned to a missing one": {assignTo: "production", wantConfig: "[client]\nuser=JohnDoe", wantUID: "landscapeUID1234"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userLandscapeConfigHasValue|landscapeUIDHasValue, false, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			err = conf.SetLandscapeEndpoint(ctx, "staging", endpointConfig)
+			require.NoError(t, err, "Setup: could not set the endpoint")
+
+			if !tc.noUID {
+				err = conf.SetLandscapeEndpointUID("staging", "stagingUID")
+				require.NoError(t, err, "SetLandscapeEndpointUID should return no errors")
+			}
+
+			err = conf.SetLandscapeDistroOverride(ctx, "UBUNTU", config.LandscapeOverride{Endpoint: tc.assignTo})
+			require.NoError(t, err, "Setup: could not assign the distro to the endpoint")
+
+			got, err := conf.LandscapeDistroEndpoint("UBUNTU")
+			require.NoError(t, err, "LandscapeDistroEndpoint should return no errors")
+			require.Equal(t, tc.wantEndpoint, got, "Unexpected endpoint for the distro")
+
+			uid, err := conf.LandscapeEndpointUID("staging")
+			require.NoError(t, err, "LandscapeEndpointUID should return no errors")
+			if tc.noUID {
+				require.Empty(t, uid, "Endpoint should not have a UID")
+			} else {
+				require.Equal(t, "stagingUID", uid, "Unexpected UID for the endpoint")
+			}
+
+			err = conf.SetLandscapeEndpointUID("production", "productionUID")
+			require.Error(t, err, "SetLandscapeEndpointUID should return an error when the endpoint does not exist")
+
+			gotTasks, err := conf.ProvisioningTasks(ctx, "UBUNTU")
+			require.NoError(t, err, "ProvisioningTasks should return no error")
+			require.Contains(t, gotTasks, tasks.LandscapeConfigure{
+				Config:       tc.wantConfig,
+				HostagentUID: tc.wantUID,
+			}, "Provisioning tasks should use the configuration of the endpoint of the distro")
+		})
+	}
+}
+
+func TestSetWSLSettings(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	enabled, disabled := true, false
+	settings := config.WSLSettings{DefaultUser: "ubuntu", Interop: &disabled, Automount: &enabled}
+
+	testCases := map[string]struct {
+		previous   config.WSLSettings
+		settings   config.WSLSettings
+		distroName string
+		breakFile  bool
+
+		wantError bool
+	}{
+		"Success":                                {settings: settings},
+		"Success removing the settings":          {previous: settings},
+		"Success when the settings are the same": {previous: settings, settings: settings},
+		"Success changing a single setting":      {previous: settings, settings: config.WSLSettings{DefaultUser: "ubuntu", Interop: &enabled, Automount: &enabled}},
+		"Success enabling systemd":               {previous: settings, settings: config.WSLSettings{DefaultUser: "ubuntu", Interop: &disabled, Automount: &enabled, Systemd: &enabled}},
+
+		"Error when the distro name is empty":         {distroName: "-", settings: settings, wantError: true},
+		"Error when the configuration cannot be read": {breakFile: true, settings: settings, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			distroName := "UBUNTU"
+			if tc.distroName == "-" {
+				distroName = ""
+			}
+
+			if !tc.previous.IsZero() {
+				err := conf.SetWSLSettings(distroName, tc.previous)
+				require.NoError(t, err, "Setup: could not set the previous settings")
+			}
+
+			err = conf.SetWSLSettings(distroName, tc.settings)
+			if tc.wantError {
+				require.Error(t, err, "SetWSLSettings should return an error")
+				return
+			}
+			require.NoError(t, err, "SetWSLSettings should return no errors")
+
+			// Reload the config from disk to check that the settings were stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.WSLSettings(distroName)
+			require.NoError(t, err, "WSLSettings should return no errors")
+			require.Equal(t, tc.settings, got, "Did not get the same settings as we set")
+
+			other, err := conf.WSLSettings("OTHER")
+			require.NoError(t, err, "WSLSettings should return no errors")
+			require.Zero(t, other, "Settings should not apply to other distros")
+
+			gotTasks, err := conf.ProvisioningTasks(ctx, distroName)
+			require.NoError(t, err, "ProvisioningTasks should return no error")
+			require.Contains(t, gotTasks, tasks.WSLConfigure{
+				DefaultUser: tc.settings.DefaultUser,
+				Systemd:     tc.settings.Systemd,
+				Interop:     tc.settings.Interop,
+				Automount:   tc.settings.Automount,
+			}, "Provisioning tasks should include the settings, and only change systemd when they ask for it")
+		})
+	}
+}
+
+func TestSetBackupSchedule(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	schedule := config.BackupSchedule{Interval: 24 * time.Hour, Destination: `C:\Backups`, Keep: 3}
+
+	testCases := map[string]struct {
+		previous  config.BackupSchedule
+		schedule  config.BackupSchedule
+		breakFile bool
+
+		wantError bool
+	}{
+		"Success":                                {schedule: schedule},
+		"Success disabling backups":              {previous: schedule},
+		"Success using the default destination":  {schedule: config.BackupSchedule{Interval: time.Hour}},
+		"Success when the schedule is unchanged": {previous: schedule, schedule: schedule},
+		"Success disabling backups with a destination and a number of backups to keep": {schedule: config.BackupSchedule{Destination: `C:\Backups`, Keep: 3}},
+
+		"Error when the interval is too short":         {schedule: config.BackupSchedule{Interval: time.Minute}, wantError: true},
+		"Error when the number of backups is negative": {schedule: config.BackupSchedule{Interval: time.Hour, Keep: -1}, wantError: true},
+		"Error when the configuration cannot be read":  {breakFile: true, schedule: schedule, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if !tc.previous.IsZero() {
+				err := conf.SetBackupSchedule(tc.previous)
+				require.NoError(t, err, "Setup: could not set the previous schedule")
+			}
+
+			err = conf.SetBackupSchedule(tc.schedule)
+			if tc.wantError {
+				require.Error(t, err, "SetBackupSchedule should return an error")
+				return
+			}
+			require.NoError(t, err, "SetBackupSchedule should return no errors")
+
+			// Reload the config from disk to check that the schedule was stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.BackupSchedule()
+			require.NoError(t, err, "BackupSchedule should return no errors")
+			require.Equal(t, tc.schedule, got, "Did not get the same schedule as we set")
+
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.NotEmpty(t, token, "Setting the backup schedule should not erase other settings")
+		})
+	}
+}
+
+func TestSetCompactSchedule(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	schedule := config.CompactSchedule{Interval: 7 * 24 * time.Hour}
+
+	testCases := map[string]struct {
+		previous  config.CompactSchedule
+		schedule  config.CompactSchedule
+		breakFile bool
+
+		wantError bool
+	}{
+		"Success":                                {schedule: schedule},
+		"Success disabling compaction":           {previous: schedule},
+		"Success when the schedule is unchanged": {previous: schedule, schedule: schedule},
+
+		"Error when the interval is too short":        {schedule: config.CompactSchedule{Interval: time.Hour}, wantError: true},
+		"Error when the configuration cannot be read": {breakFile: true, schedule: schedule, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if !tc.previous.IsZero() {
+				err := conf.SetCompactSchedule(tc.previous)
+				require.NoError(t, err, "Setup: could not set the previous schedule")
+			}
+
+			err = conf.SetCompactSchedule(tc.schedule)
+			if tc.wantError {
+				require.Error(t, err, "SetCompactSchedule should return an error")
+				return
+			}
+			require.NoError(t, err, "SetCompactSchedule should return no errors")
+
+			// Reload the config from disk to check that the schedule was stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.CompactSchedule()
+			require.NoError(t, err, "CompactSchedule should return no errors")
+			require.Equal(t, tc.schedule, got, "Did not get the same schedule as we set")
+
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.NotEmpty(t, token, "Setting the compaction schedule should not erase other settings")
+		})
+	}
+}
+
+func TestSetRecurringTasks(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	refresh := config.RecurringTask{Name: "refresh", Task: "pro-refresh", Schedule: "0 3 * * 0", CatchUp: config.CatchUpOnce}
+	recurring := []config.RecurringTask{refresh, {Name: "hourly refresh", Task: "pro-refresh", Schedule: "@hourly", Selector: distro.Selector{"env": "dev"}}}
+
+	testCases := map[string]struct {
+		previous  []config.RecurringTask
+		recurring []config.RecurringTask
+		breakFile bool
+
+		wantError bool
+	}{
+		"Success":                              {recurring: recurring},
+		"Success removing every task":          {previous: recurring},
+		"Success when the tasks are unchanged": {previous: recurring, recurring: recurring},
+
+		"Error when the name is empty":                {recurring: []config.RecurringTask{{Task: "pro-refresh", Schedule: "@daily"}}, wantError: true},
+		"Error when the kind of task is empty":        {recurring: []config.RecurringTask{{Name: "refresh", Schedule: "@daily"}}, wantError: true},
+		"Error when the schedule is invalid":          {recurring: []config.RecurringTask{{Name: "refresh", Task: "pro-refresh", Schedule: "every day"}}, wantError: true},
+		"Error when the catch-up policy is unknown":   {recurring: []config.RecurringTask{{Name: "refresh", Task: "pro-refresh", Schedule: "@daily", CatchUp: "all"}}, wantError: true},
+		"Error when a name is used twice":             {recurring: []config.RecurringTask{refresh, refresh}, wantError: true},
+		"Error when the selector is invalid":          {recurring: []config.RecurringTask{{Name: "refresh", Task: "pro-refresh", Schedule: "@daily", Selector: distro.Selector{"not a key": "x"}}}, wantError: true},
+		"Error when the configuration cannot be read": {breakFile: true, recurring: recurring, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if tc.previous != nil {
+				err := conf.SetRecurringTasks(tc.previous)
+				require.NoError(t, err, "Setup: could not set the previous recurring tasks")
+			}
+
+			err = conf.SetRecurringTasks(tc.recurring)
+			if tc.wantError {
+				require.Error(t, err, "SetRecurringTasks should return an error")
+				return
+			}
+			require.NoError(t, err, "SetRecurringTasks should return no errors")
+
+			// Reload the config from disk to check that the tasks were stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.RecurringTasks()
+			require.NoError(t, err, "RecurringTasks should return no errors")
+			require.ElementsMatch(t, tc.recurring, got, "Did not get the same recurring tasks as we set")
+
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.NotEmpty(t, token, "Setting the recurring tasks should not erase other settings")
+		})
+	}
+}
+
+func TestSetStoreEntitlement(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	entitlement := config.StoreEntitlement{
+		JWT:                    "JWT_123",
+		JWTExpiration:          time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		SubscriptionExpiration: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		Checked:                time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	testCases := map[string]struct {
+		previous    config.StoreEntitlement
+		entitlement config.StoreEntitlement
+		breakFile   bool
+
+		wantError bool
+	}{
+		"Success":                                   {entitlement: entitlement},
+		"Success clearing the entitlement":          {previous: entitlement},
+		"Success when the entitlement is unchanged": {previous: entitlement, entitlement: entitlement},
+
+		"Error when the configuration cannot be read": {breakFile: true, entitlement: entitlement, wantError: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, tc.breakFile, false)
+			conf := config.New(ctx, dir)
+			setup(t, conf)
+
+			if tc.previous != (config.StoreEntitlement{}) {
+				err := conf.SetStoreEntitlement(tc.previous)
+				require.NoError(t, err, "Setup: could not set the previous entitlement")
+			}
+
+			err = conf.SetStoreEntitlement(tc.entitlement)
+			if tc.wantError {
+				require.Error(t, err, "SetStoreEntitlement should return an error")
+				return
+			}
+			require.NoError(t, err, "SetStoreEntitlement should return no errors")
+
+			// Reload the config from disk to check that the entitlement was stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.StoreEntitlement()
+			require.NoError(t, err, "StoreEntitlement should return no errors")
+			require.Equal(t, tc.entitlement, got, "Did not get the same entitlement as we set")
+
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.NotEmpty(t, token, "Setting the entitlement should not erase other settings")
 		})
 	}
 }
 
-func TestProvisioningTasks(t *testing.T) {
+func TestSetPaused(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
 	}
 
 	testCases := map[string]struct {
-		settingsState settingsState
-
-		wantToken         string
-		wantLandscapeConf string
-		wantLandscapeUID  string
+		previous  bool
+		paused    bool
+		breakFile bool
 
-		wantError bool
+		wantNotified bool
+		wantError    bool
 	}{
-		"Success when there is no data":                               {settingsState: untouched},
-		"Success when there is an empty config file":                  {settingsState: fileExists},
-		"Success when the file's pro token field exists but is empty": {settingsState: userTokenExists},
-		"Success with a user token":                                   {settingsState: userTokenHasValue, wantToken: "user_token"},
-		"Success when there is Landscape config":                      {settingsState: userLandscapeConfigHasValue | landscapeUIDHasValue, wantLandscapeConf: "[client]\nuser=JohnDoe", wantLandscapeUID: "landscapeUID1234"},
+		"Success pausing":                     {paused: true, wantNotified: true},
+		"Success resuming":                    {previous: true, wantNotified: true},
+		"Success when the state is unchanged": {previous: true, paused: true},
+
+		"Error when the configuration cannot be read": {breakFile: true, paused: true, wantError: true},
 	}
 
 	for name, tc := range testCases {
@@ -245,53 +1884,82 @@ func TestProvisioningTasks(t *testing.T) {
 			db, err := database.New(ctx, t.TempDir(), nil)
 			require.NoError(t, err, "Setup: could not create empty database")
 
-			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, false, false)
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, tc.breakFile, false)
 			conf := config.New(ctx, dir)
 			setup(t, conf)
 
-			gotTasks, err := conf.ProvisioningTasks(ctx, "UBUNTU")
+			if tc.previous {
+				err := conf.SetPaused(ctx, true)
+				require.NoError(t, err, "Setup: could not pause the agent")
+			}
+
+			var notified []bool
+			conf.SetPauseNotifier(func(_ context.Context, paused bool) {
+				// The config must be usable from the notifier.
+				got, err := conf.Paused()
+				require.NoError(t, err, "Paused should return no errors from the notifier")
+				require.Equal(t, paused, got, "Notifier should be called after the state is stored")
+
+				notified = append(notified, paused)
+			})
+
+			err = conf.SetPaused(ctx, tc.paused)
 			if tc.wantError {
-				require.Error(t, err, "ProvisioningTasks should return an error")
+				require.Error(t, err, "SetPaused should return an error")
 				return
 			}
-			require.NoError(t, err, "ProvisioningTasks should return no error")
+			require.NoError(t, err, "SetPaused should return no errors")
 
-			wantTasks := []task.Task{
-				tasks.ProAttachment{Token: tc.wantToken},
-				tasks.LandscapeConfigure{
-					Config:       tc.wantLandscapeConf,
-					HostagentUID: tc.wantLandscapeUID,
-				},
+			if tc.wantNotified {
+				require.Equal(t, []bool{tc.paused}, notified, "Notifier should have been called once with the new state")
+			} else {
+				require.Empty(t, notified, "Notifier should not be called when the state does not change")
 			}
 
-			require.ElementsMatch(t, wantTasks, gotTasks, "Unexpected contents returned by ProvisioningTasks")
+			// Reload the config from disk to check that the state was stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.Paused()
+			require.NoError(t, err, "Paused should return no errors")
+			require.Equal(t, tc.paused, got, "Did not get the same state as we set")
+
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.NotEmpty(t, token, "Pausing should not erase other settings")
 		})
 	}
 }
 
-func TestSetUserSubscription(t *testing.T) {
+func TestImportLegacySettings(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
 	}
 
-	testCases := map[string]struct {
-		settingsState   settingsState
-		breakFile       bool
-		cannotWriteFile bool
-		emptyToken      bool
+	const legacyLandscapeConfig = "[host]\nurl=landscape.legacy.example:6554\n[client]\naccount_name=standalone"
 
-		want      string
-		wantError bool
+	testCases := map[string]struct {
+		settingsState  settingsState
+		legacy         config.LegacySettings
+		importedBefore bool
+		breakFile      bool
+
+		wantToken             string
+		wantLandscapeConfig   string
+		wantNotifiedPro       bool
+		wantNotifiedLandscape bool
+		wantError             bool
 	}{
-		"Success":                          {settingsState: userTokenHasValue, want: "new_token"},
-		"Success disabling a subscription": {settingsState: userTokenHasValue, emptyToken: true, want: ""},
-
-		"Error when there is a store token active": {settingsState: storeTokenHasValue, wantError: true},
-		"Error when the file cannot be opened":     {settingsState: fileExists, breakFile: true, wantError: true},
-		"Error when the file cannot be written":    {settingsState: fileExists, cannotWriteFile: true, wantError: true},
+		"Success importing all settings": {settingsState: untouched, legacy: config.LegacySettings{UbuntuProToken: "legacy_token", LandscapeConfig: legacyLandscapeConfig},
+			wantToken: "legacy_token", wantLandscapeConfig: legacyLandscapeConfig, wantNotifiedPro: true, wantNotifiedLandscape: true},
+		"Success when there are no legacy settings":       {settingsState: userTokenHasValue, wantToken: "user_token"},
+		"Success importing only the missing settings":     {settingsState: userTokenHasValue, legacy: config.LegacySettings{UbuntuProToken: "legacy_token", LandscapeConfig: legacyLandscapeConfig}, wantToken: "user_token", wantLandscapeConfig: legacyLandscapeConfig, wantNotifiedLandscape: true},
+		"Success without notifying shadowed settings":     {settingsState: orgTokenHasValue, legacy: config.LegacySettings{UbuntuProToken: "legacy_token"}, wantToken: "org_token"},
+		"Success skipping an invalid Landscape config":    {settingsState: untouched, legacy: config.LegacySettings{UbuntuProToken: "legacy_token", LandscapeConfig: "[client]\ntags={nope}"}, wantToken: "legacy_token", wantNotifiedPro: true},
+		"Success when the settings were imported already": {settingsState: untouched, importedBefore: true, legacy: config.LegacySettings{UbuntuProToken: "legacy_token"}},
+
+		"Error when the configuration cannot be read": {breakFile: true, legacy: config.LegacySettings{UbuntuProToken: "legacy_token"}, wantError: true},
 	}
 
-	//nolint:dupl // This is mostly duplicate with TestSetStoreConfig but de-duplicating with a meta-test worsens readability
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			ctx := context.Background()
@@ -303,70 +1971,79 @@ func TestSetUserSubscription(t *testing.T) {
 			db, err := database.New(ctx, t.TempDir(), nil)
 			require.NoError(t, err, "Setup: could not create empty database")
 
-			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, tc.cannotWriteFile)
+			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, false)
 			conf := config.New(ctx, dir)
 			setup(t, conf)
 
-			token := "new_token"
-			if tc.emptyToken {
-				token = ""
+			if tc.importedBefore {
+				err := conf.ImportLegacySettings(ctx, config.LegacySettings{})
+				require.NoError(t, err, "Setup: could not import empty legacy settings")
 			}
 
-			var calledProNotifier int
-			conf.SetUbuntuProNotifier(func(context.Context, string) {
-				calledProNotifier++
-			})
-
-			conf.SetLandscapeNotifier(func(context.Context, string, string) {
-				require.Fail(t, "LandscapeNotifier should not be called")
-			})
+			var notifiedPro, notifiedLandscape bool
+			conf.SetUbuntuProNotifier(func(context.Context, string) { notifiedPro = true })
+			conf.SetLandscapeNotifier(func(context.Context, string, string) { notifiedLandscape = true })
 
-			err = conf.SetUserSubscription(ctx, token)
+			err = conf.ImportLegacySettings(ctx, tc.legacy)
 			if tc.wantError {
-				require.Error(t, err, "SetSubscription should return an error")
+				require.Error(t, err, "ImportLegacySettings should return an error")
 				return
 			}
-			require.NoError(t, err, "SetSubscription should return no error")
+			require.NoError(t, err, "ImportLegacySettings should return no errors")
 
-			require.Equal(t, 1, calledProNotifier, "ProNotifier should have been called once")
+			require.Equal(t, tc.wantNotifiedPro, notifiedPro, "Unexpected notification of the Ubuntu Pro subscription")
+			require.Equal(t, tc.wantNotifiedLandscape, notifiedLandscape, "Unexpected notification of the Landscape configuration")
 
-			got, _, err := conf.Subscription()
-			require.NoError(t, err, "ProToken should return no error")
+			// Reload the config from disk to check that the settings were stored.
+			conf = config.New(ctx, dir)
+			setup(t, conf)
 
-			require.Equal(t, tc.want, got, "ProToken returned an unexpected value for the token")
+			imported, err := conf.LegacyImported()
+			require.NoError(t, err, "LegacyImported should return no errors")
+			require.True(t, imported, "Legacy settings should be marked as imported")
 
-			// Set the same token again
-			calledProNotifier = 0
-			err = conf.SetUserSubscription(ctx, token)
-			require.NoError(t, err, "SetUserSubscription should return no error")
-			require.Zero(t, calledProNotifier, "ProNotifier should not have been called again")
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.Equal(t, tc.wantToken, token, "Unexpected Ubuntu Pro token")
+
+			landscapeConf, _, err := conf.LandscapeClientConfig()
+			require.NoError(t, err, "LandscapeClientConfig should return no errors")
+			require.Equal(t, tc.wantLandscapeConfig, landscapeConf, "Unexpected Landscape configuration")
+
+			// A second import is a no-op.
+			err = conf.ImportLegacySettings(ctx, config.LegacySettings{UbuntuProToken: "another_token"})
+			require.NoError(t, err, "ImportLegacySettings should return no errors when called again")
+
+			token, _, err = conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.Equal(t, tc.wantToken, token, "Importing twice should not change the Ubuntu Pro token")
 		})
 	}
 }
 
-func TestSetStoreSubscription(t *testing.T) {
+func TestSetNotificationEnabled(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
 	}
 
 	testCases := map[string]struct {
-		settingsState   settingsState
-		breakFile       bool
-		cannotWriteFile bool
-		emptyToken      bool
+		muted     []string
+		category  string
+		enabled   bool
+		breakFile bool
 
-		want      string
+		wantMuted []string
 		wantError bool
 	}{
-		"Success":                          {settingsState: userTokenHasValue, want: "new_token"},
-		"Success disabling a subscription": {settingsState: storeTokenHasValue, emptyToken: true, want: ""},
-		"Success overriding an existing store token": {settingsState: storeTokenHasValue, want: "new_token"},
+		"Success opting out":                       {category: "b", wantMuted: []string{"b"}},
+		"Success opting out of a second category":  {muted: []string{"c"}, category: "a", wantMuted: []string{"a", "c"}},
+		"Success opting out twice":                 {muted: []string{"a"}, category: "a", wantMuted: []string{"a"}},
+		"Success opting back in":                   {muted: []string{"a", "b"}, category: "a", enabled: true, wantMuted: []string{"b"}},
+		"Success opting in to an enabled category": {category: "a", enabled: true},
 
-		"Error when the file cannot be opened":  {settingsState: fileExists, breakFile: true, wantError: true},
-		"Error when the file cannot be written": {settingsState: fileExists, cannotWriteFile: true, wantError: true},
+		"Error when the configuration cannot be read": {breakFile: true, category: "a", wantError: true},
 	}
 
-	//nolint:dupl // This is mostly duplicate with TestSetUserConfig but de-duplicating with a meta-test worsens readability
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			ctx := context.Background()
@@ -378,62 +2055,61 @@ func TestSetStoreSubscription(t *testing.T) {
 			db, err := database.New(ctx, t.TempDir(), nil)
 			require.NoError(t, err, "Setup: could not create empty database")
 
-			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, tc.cannotWriteFile)
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, tc.breakFile, false)
 			conf := config.New(ctx, dir)
 			setup(t, conf)
 
-			token := "new_token"
-			if tc.emptyToken {
-				token = ""
+			for _, category := range tc.muted {
+				err := conf.SetNotificationEnabled(category, false)
+				require.NoError(t, err, "Setup: could not opt out of %q", category)
 			}
 
-			var calledProNotifier int
-			conf.SetUbuntuProNotifier(func(context.Context, string) {
-				calledProNotifier++
-			})
-
-			conf.SetLandscapeNotifier(func(context.Context, string, string) {
-				require.Fail(t, "LandscapeNotifier should not be called")
-			})
-
-			err = conf.SetStoreSubscription(ctx, token)
+			err = conf.SetNotificationEnabled(tc.category, tc.enabled)
 			if tc.wantError {
-				require.Error(t, err, "SetSubscription should return an error")
+				require.Error(t, err, "SetNotificationEnabled should return an error")
 				return
 			}
-			require.NoError(t, err, "SetSubscription should return no error")
+			require.NoError(t, err, "SetNotificationEnabled should return no errors")
 
-			require.Equal(t, 1, calledProNotifier, "ProNotifier should have been called once")
+			// Reload the config from disk to check that the preferences were stored.
+			conf = config.New(ctx, dir)
 
-			got, _, err := conf.Subscription()
-			require.NoError(t, err, "ProToken should return no error")
+			got, err := conf.MutedNotifications()
+			require.NoError(t, err, "MutedNotifications should return no errors")
+			require.Equal(t, tc.wantMuted, got, "Unexpected muted notifications")
 
-			require.Equal(t, tc.want, got, "ProToken returned an unexpected value for the token")
+			enabled, err := conf.NotificationEnabled(tc.category)
+			require.NoError(t, err, "NotificationEnabled should return no errors")
+			require.Equal(t, tc.enabled, enabled, "Notification preference should be the one that was set")
 
-			// Set the same token again
-			calledProNotifier = 0
-			err = conf.SetStoreSubscription(ctx, token)
-			require.NoError(t, err, "SetStoreSubscription should return no error")
-			require.Zero(t, calledProNotifier, "ProNotifier should not have been called again")
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.NotEmpty(t, token, "Setting notification preferences should not erase other settings")
 		})
 	}
 }
 
-func TestSetUserLandscapeConfig(t *testing.T) {
+func TestSetApprovalRequired(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
 	}
 
 	testCases := map[string]struct {
-		settingsState settingsState
-		breakFile     bool
+		required  []string
+		category  string
+		notNeeded bool
+		breakFile bool
 
-		wantError bool
+		wantRequired []string
+		wantError    bool
 	}{
-		"Success": {settingsState: untouched},
+		"Success requiring an approval":                {category: "b", wantRequired: []string{"b"}},
+		"Success requiring a second approval":          {required: []string{"c"}, category: "a", wantRequired: []string{"a", "c"}},
+		"Success requiring an approval twice":          {required: []string{"a"}, category: "a", wantRequired: []string{"a"}},
+		"Success no longer requiring an approval":      {required: []string{"a", "b"}, category: "a", notNeeded: true, wantRequired: []string{"b"}},
+		"Success not requiring an unrequired approval": {category: "a", notNeeded: true},
 
-		"Error when an organization landscape config is already set": {settingsState: orgLandscapeConfigHasValue, wantError: true},
-		"Error when an configuration cannot be read":                 {settingsState: untouched, breakFile: true, wantError: true},
+		"Error when the configuration cannot be read": {breakFile: true, category: "a", wantError: true},
 	}
 
 	for name, tc := range testCases {
@@ -447,33 +2123,36 @@ func TestSetUserLandscapeConfig(t *testing.T) {
 			db, err := database.New(ctx, t.TempDir(), nil)
 			require.NoError(t, err, "Setup: could not create empty database")
 
-			setup, dir := setUpMockSettings(t, ctx, db, tc.settingsState, tc.breakFile, false)
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, tc.breakFile, false)
 			conf := config.New(ctx, dir)
 			setup(t, conf)
 
-			landscapeConfig := "LANDSCAPE CONFIG"
-
-			var calledLandscapeNotifier int
-			conf.SetUbuntuProNotifier(func(context.Context, string) {
-				require.Fail(t, "UbuntuPro should not be called")
-			})
-
-			conf.SetLandscapeNotifier(func(context.Context, string, string) {
-				calledLandscapeNotifier++
-			})
+			for _, category := range tc.required {
+				err := conf.SetApprovalRequired(category, true)
+				require.NoError(t, err, "Setup: could not require approval of %q", category)
+			}
 
-			err = conf.SetUserLandscapeConfig(ctx, landscapeConfig)
+			err = conf.SetApprovalRequired(tc.category, !tc.notNeeded)
 			if tc.wantError {
-				require.Error(t, err, "SetUserLandscapeConfig should return an error")
+				require.Error(t, err, "SetApprovalRequired should return an error")
 				return
 			}
-			require.NoError(t, err, "SetUserLandscapeConfig should return no errors")
+			require.NoError(t, err, "SetApprovalRequired should return no errors")
 
-			got, src, err := conf.LandscapeClientConfig()
-			require.NoError(t, err, "LandscapeClientConfig should return no errors")
-			require.Equal(t, landscapeConfig, got, "Did not get the same value for landscape config as we set")
-			require.Equal(t, config.SourceUser, src, "Did not get the same value for landscape config as we set")
-			require.Equal(t, 1, calledLandscapeNotifier, "LandscapeNotifier should have been called once")
+			// Reload the config from disk to check that the preferences were stored.
+			conf = config.New(ctx, dir)
+
+			got, err := conf.RequiredApprovals()
+			require.NoError(t, err, "RequiredApprovals should return no errors")
+			require.Equal(t, tc.wantRequired, got, "Unexpected required approvals")
+
+			required, err := conf.ApprovalRequired(tc.category)
+			require.NoError(t, err, "ApprovalRequired should return no errors")
+			require.Equal(t, !tc.notNeeded, required, "Approval preference should be the one that was set")
+
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Subscription should return no errors")
+			require.NotEmpty(t, token, "Setting approval preferences should not erase other settings")
 		})
 	}
 }
@@ -544,6 +2223,206 @@ func TestSetLandscapeAgentUID(t *testing.T) {
 	}
 }
 
+func TestConfigCache(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		ttl                time.Duration
+		elapsed            time.Duration
+		updateRegistryData bool
+
+		wantToken string
+	}{
+		"Success using cached data within the TTL":          {ttl: time.Hour, elapsed: time.Minute, wantToken: "user_token"},
+		"Success reading the file when the TTL expires":     {ttl: time.Minute, elapsed: time.Minute, wantToken: "edited_token"},
+		"Success reading the file when caching is disabled": {wantToken: "edited_token"},
+		"Success reading the file after new registry data":  {ttl: time.Hour, updateRegistryData: true, wantToken: "edited_token"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: could not create empty database")
+
+			setup, dir := setUpMockSettings(t, ctx, db, userTokenHasValue, false, false)
+			clk := clock.NewMock(time.Now())
+			conf := config.New(ctx, dir, config.WithCacheTTL(tc.ttl), config.WithClock(clk))
+			setup(t, conf)
+
+			token, _, err := conf.Subscription()
+			require.NoError(t, err, "Setup: Subscription should return no error")
+			require.Equal(t, "user_token", token, "Setup: unexpected token before editing the file")
+
+			// Edit the file behind the config's back.
+			err = os.WriteFile(filepath.Join(dir, "config"), []byte("subscription:\n  user: edited_token\n"), 0600)
+			require.NoError(t, err, "Setup: could not edit config file")
+			clk.Advance(tc.elapsed)
+
+			if tc.updateRegistryData {
+				err := conf.UpdateRegistryData(ctx, config.RegistryData{}, db)
+				require.NoError(t, err, "UpdateRegistryData should return no error")
+			}
+
+			token, _, err = conf.Subscription()
+			require.NoError(t, err, "Subscription should return no error")
+			require.Equal(t, tc.wantToken, token, "Unexpected token")
+		})
+	}
+}
+
+func TestConfigRecovery(t *testing.T) {
+	t.Parallel()
+
+	const (
+		validConfig    = "subscription:\n  user: user_token\n"
+		previousConfig = "subscription:\n  user: previous_token\n"
+		stagedConfig   = "subscription:\n  user: staged_token\n"
+		corruptConfig  = "\tmessage:\n\t\tthis is not YAML!["
+	)
+
+	testCases := map[string]struct {
+		config   string
+		previous string
+		staged   string
+
+		wantToken   string
+		wantCorrupt bool
+		wantErr     bool
+	}{
+		"Success with a valid config":                                  {config: validConfig, wantToken: "user_token"},
+		"Success ignoring a staged config left by a failed write":      {config: validConfig, previous: previousConfig, staged: corruptConfig, wantToken: "user_token"},
+		"Success ignoring a staged config when there is no config yet": {staged: stagedConfig},
+		"Success when the config was removed":                          {previous: previousConfig},
+
+		"Success completing an interrupted write":                          {previous: previousConfig, staged: stagedConfig, wantToken: "staged_token"},
+		"Success restoring the previous config when the file is corrupted": {config: corruptConfig, previous: previousConfig, wantToken: "previous_token", wantCorrupt: true},
+
+		"Error when the file is corrupted and there is no previous config": {config: corruptConfig, wantErr: true},
+		"Error when the file and the previous config are both corrupted":   {config: corruptConfig, previous: corruptConfig, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config")
+
+			for file, contents := range map[string]string{path: tc.config, path + ".old": tc.previous, path + ".new": tc.staged} {
+				if contents == "" {
+					continue
+				}
+				err := os.WriteFile(file, []byte(contents), 0600)
+				require.NoError(t, err, "Setup: could not write config file")
+			}
+
+			conf := config.New(context.Background(), dir)
+
+			token, _, err := conf.Subscription()
+			if tc.wantErr {
+				require.Error(t, err, "Subscription should return an error")
+				return
+			}
+			require.NoError(t, err, "Subscription should return no error")
+			require.Equal(t, tc.wantToken, token, "Unexpected token")
+
+			if tc.wantCorrupt {
+				got, err := os.ReadFile(path + ".corrupt")
+				require.NoError(t, err, "The corrupted config should have been kept")
+				require.Equal(t, corruptConfig, string(got), "The corrupted config should have been kept as is")
+			}
+
+			if tc.wantToken != "" {
+				got, err := os.ReadFile(path)
+				require.NoError(t, err, "The config file should exist")
+				require.Contains(t, string(got), tc.wantToken, "The config file should have been repaired")
+			}
+
+			// Writing keeps the previous contents, if any.
+			_, err = os.Stat(path)
+			hadConfig := err == nil
+
+			err = conf.SetUserSubscription(context.Background(), "new_token")
+			require.NoError(t, err, "SetUserSubscription should return no error")
+
+			got, err := os.ReadFile(path)
+			require.NoError(t, err, "The config file should exist")
+			require.Contains(t, string(got), "new_token", "The config file should contain the new token")
+
+			if hadConfig {
+				got, err = os.ReadFile(path + ".old")
+				require.NoError(t, err, "The previous config file should exist")
+				require.NotContains(t, string(got), "new_token", "The previous config file should not contain the new token")
+			}
+
+			require.NoFileExists(t, path+".new", "The staged config should not be left behind")
+		})
+	}
+}
+
+func TestConfigStateFields(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// The table of fields is generated from the configuration data. Adding a field changes the golden
+	// file, and the checks below ensure that the new field is both written and read back.
+	fields := stateFields(t, reflect.TypeOf(config.State{}), "", false)
+	got := strings.Join(fields, "\n") + "\n"
+	want := testutils.LoadWithUpdateFromGolden(t, got)
+	require.Equal(t, want, got, "The fields of the config do not match the golden file")
+
+	dir := t.TempDir()
+	conf := config.New(ctx, dir)
+
+	var full config.State
+	fillState(t, reflect.ValueOf(&full).Elem(), true)
+	err := conf.SetState(full)
+	require.NoError(t, err, "SetState should return no error")
+
+	out, err := os.ReadFile(filepath.Join(dir, "config"))
+	require.NoError(t, err, "Could not read the config file")
+
+	var doc yaml.Node
+	err = yaml.Unmarshal(out, &doc)
+	require.NoError(t, err, "The config file should be valid YAML")
+
+	written := make(map[string]int)
+	countLeaves(&doc, "", written)
+
+	for _, f := range fields {
+		path, registry := strings.CutSuffix(f, " (registry)")
+		if registry {
+			require.Zerof(t, written[path], "Field %s comes from the registry and should not be written to the config file", path)
+			continue
+		}
+		require.Equalf(t, 1, written[path], "Field %s should be written to the config file exactly once", path)
+		delete(written, path)
+	}
+	require.Empty(t, written, "Every value in the config file should belong to a field")
+
+	// A new instance reads every field back, except for those that come from the registry.
+	var stored config.State
+	fillState(t, reflect.ValueOf(&stored).Elem(), false)
+
+	s, err := config.New(ctx, dir).GetState()
+	require.NoError(t, err, "GetState should return no error")
+	require.Equal(t, stored, s, "Every field written to the config file should be read back")
+
+	// Reading the file again keeps the fields that come from the registry.
+	conf.InvalidateCache()
+	s, err = conf.GetState()
+	require.NoError(t, err, "GetState should return no error")
+	require.Equal(t, full, s, "Reading the config file should not override the fields that come from the registry")
+}
+
 func TestUpdateRegistryData(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
@@ -759,12 +2638,13 @@ func setUpMockSettings(t *testing.T, ctx context.Context, db *database.DistroDB,
 
 	// Mock file config
 	cacheDir := t.TempDir()
-	var filemode fs.FileMode = 0600
 	if fileCannotWrite {
-		filemode = 0444 // read-only
+		// The config file is replaced rather than written to, so it is the staging file that cannot be written.
+		err := os.MkdirAll(filepath.Join(cacheDir, "config.new", "file"), 0700)
+		require.NoError(t, err, "Setup: could not create directory to interfere with config")
 	}
 	if fileBroken {
-		err := os.MkdirAll(filepath.Join(cacheDir, "config"), filemode)
+		err := os.MkdirAll(filepath.Join(cacheDir, "config"), 0600)
 		require.NoError(t, err, "Setup: could not create directory to interfere with config")
 		return setupConfig, cacheDir
 	}
@@ -812,8 +2692,109 @@ func setUpMockSettings(t *testing.T, ctx context.Context, db *database.DistroDB,
 	out, err := yaml.Marshal(fileData)
 	require.NoError(t, err, "Setup: could not marshal fake config")
 
-	err = os.WriteFile(filepath.Join(cacheDir, "config"), out, filemode)
+	err = os.WriteFile(filepath.Join(cacheDir, "config"), out, 0600)
 	require.NoError(t, err, "Setup: could not write config file")
 
 	return setupConfig, cacheDir
 }
+
+// stateFields lists the leaf fields of typ by their path in the config file. The fields that are only
+// provided by the registry are marked as such. Maps are walked through a single key "*" and slices
+// through a single element "[]", which is how fillState fills them.
+func stateFields(t *testing.T, typ reflect.Type, path string, registry bool) (fields []string) {
+	t.Helper()
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return stateFields(t, typ.Elem(), path, registry)
+	case reflect.Map:
+		return stateFields(t, typ.Elem(), path+".*", registry)
+	case reflect.Slice:
+		return stateFields(t, typ.Elem(), path+".[]", registry)
+	case reflect.Struct:
+		if typ == reflect.TypeOf(time.Time{}) {
+			break
+		}
+
+		for i := range typ.NumField() {
+			f := typ.Field(i)
+			require.Truef(t, f.IsExported(), "Field %s in %s must be exported to be stored in the config file", f.Name, path)
+
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			fromRegistry := registry || name == "-"
+			if name == "" || name == "-" {
+				name = strings.ToLower(f.Name)
+			}
+
+			fields = append(fields, stateFields(t, f.Type, strings.TrimPrefix(path+"."+name, "."), fromRegistry)...)
+		}
+		return fields
+	}
+
+	if registry {
+		return []string{path + " (registry)"}
+	}
+	return []string{path}
+}
+
+// fillState sets every leaf field of v to a non-zero value. The fields that are only provided by the
+// registry are left untouched unless withRegistry is true.
+func fillState(t *testing.T, v reflect.Value, withRegistry bool) {
+	t.Helper()
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(42)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillState(t, v.Elem(), withRegistry)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillState(t, v.Index(0), withRegistry)
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		key.SetString("*")
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fillState(t, elem, withRegistry)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, elem)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)))
+			return
+		}
+
+		for i := range v.NumField() {
+			if !withRegistry && v.Type().Field(i).Tag.Get("yaml") == "-" {
+				continue
+			}
+			fillState(t, v.Field(i), withRegistry)
+		}
+	default:
+		require.Failf(t, "Unsupported field", "fillState does not know how to fill a %s: add it to the test", v.Kind())
+	}
+}
+
+// countLeaves counts how many times each value appears in the YAML node, indexed by its path.
+func countLeaves(node *yaml.Node, path string, counts map[string]int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			countLeaves(n, path, counts)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			countLeaves(node.Content[i+1], strings.TrimPrefix(path+"."+node.Content[i].Value, "."), counts)
+		}
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			countLeaves(n, path+".[]", counts)
+		}
+	default:
+		counts[path]++
+	}
+}
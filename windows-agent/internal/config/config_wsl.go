@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/tasks"
+)
+
+// WSLSettings contains the settings of a single distro that are written to its wsl.conf.
+// Empty and nil fields are left as they are in the distro.
+type WSLSettings struct {
+	// DefaultUser is the user that WSL logs in as when the distro is launched.
+	DefaultUser string `yaml:",omitempty"`
+
+	// Interop allows launching Windows executables from the distro.
+	Interop *bool `yaml:",omitempty"`
+
+	// Automount mounts the Windows drives in the distro.
+	Automount *bool `yaml:",omitempty"`
+
+	// Systemd boots the distro with systemd.
+	Systemd *bool `yaml:",omitempty"`
+}
+
+// IsZero returns true if the settings do not change anything.
+func (s WSLSettings) IsZero() bool {
+	return s.equal(WSLSettings{})
+}
+
+// equal returns true if both settings change the same things in the same way.
+func (s WSLSettings) equal(other WSLSettings) bool {
+	eq := func(a, b *bool) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return *a == *b
+	}
+
+	return s.DefaultUser == other.DefaultUser && eq(s.Interop, other.Interop) && eq(s.Automount, other.Automount) && eq(s.Systemd, other.Systemd)
+}
+
+// Task returns the task that applies the settings to the distro.
+//
+// The task is carried out by the WSL-Pro-Service, which runs as a systemd unit: enabling systemd in a
+// distro that boots without it must be done from Windows instead.
+func (s WSLSettings) Task() tasks.WSLConfigure {
+	return tasks.WSLConfigure{
+		DefaultUser: s.DefaultUser,
+		Systemd:     s.Systemd,
+		Interop:     s.Interop,
+		Automount:   s.Automount,
+	}
+}
+
+// WSLSettings returns the WSL settings of the specified distro.
+func (c *Config) WSLSettings(distroName string) (WSLSettings, error) {
+	s, err := c.get()
+	if err != nil {
+		return WSLSettings{}, fmt.Errorf("config: could not get WSL settings for distro %q: %v", distroName, err)
+	}
+
+	return s.WSL[distroName], nil
+}
+
+// SetWSLSettings overwrites the WSL settings of the specified distro. Zero settings remove them.
+func (c *Config) SetWSLSettings(distroName string, settings WSLSettings) (err error) {
+	if distroName == "" {
+		return errors.New("config: could not set WSL settings: distro name cannot be empty")
+	}
+
+	if err := c.setWSLSettings(distroName, settings); err != nil {
+		return fmt.Errorf("config: could not set WSL settings for distro %q: %v", distroName, err)
+	}
+
+	return nil
+}
+
+func (c *Config) setWSLSettings(distroName string, settings WSLSettings) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	old := c.WSL
+	if old[distroName].equal(settings) {
+		return nil
+	}
+
+	distros := maps.Clone(old)
+	if distros == nil {
+		distros = make(map[string]WSLSettings)
+	}
+
+	if settings.IsZero() {
+		delete(distros, distroName)
+	} else {
+		distros[distroName] = settings
+	}
+
+	c.WSL = distros
+	if err := c.dump(); err != nil {
+		c.WSL = old
+		return err
+	}
+
+	return nil
+}
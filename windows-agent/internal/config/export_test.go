@@ -0,0 +1,26 @@
+package config
+
+// State is the configuration data, exposed so that tests can check how every field is stored.
+type State = configState
+
+// SetState replaces the configuration data and writes it to disk.
+func (c *Config) SetState(s State) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.configState = s
+	return c.dump()
+}
+
+// GetState returns the configuration data, reading it from disk if the cache is not valid.
+func (c *Config) GetState() (State, error) {
+	return c.get()
+}
+
+// InvalidateCache forces the next read to load the config file.
+func (c *Config) InvalidateCache() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidateCache()
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// LandscapeTemplate holds the values the placeholders of the Landscape client configuration are
+// expanded to. The configuration can contain these placeholders in the values of section [client]:
+//   - {distro_name}: the name of the distro being configured.
+//   - {hostname}: the hostname of the Windows machine.
+//   - {uid}: the UID assigned to this agent by Landscape.
+type LandscapeTemplate struct {
+	DistroName string
+	Hostname   string
+	UID        string
+}
+
+// placeholderRegex matches a placeholder such as {distro_name}.
+var placeholderRegex = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// values returns the value of every known placeholder, indexed by name.
+func (t LandscapeTemplate) values() map[string]string {
+	return map[string]string{
+		"distro_name": t.DistroName,
+		"hostname":    t.Hostname,
+		"uid":         t.UID,
+	}
+}
+
+// Expand replaces the placeholders of the Landscape client configuration with their values.
+// It fails if the configuration contains placeholders that are unknown or out of section [client].
+func (t LandscapeTemplate) Expand(landscapeConfig string) (string, error) {
+	if err := ValidateLandscapeTemplate(landscapeConfig); err != nil {
+		return "", err
+	}
+
+	values := t.values()
+	return placeholderRegex.ReplaceAllStringFunc(landscapeConfig, func(match string) string {
+		v, ok := values[strings.Trim(match, "{}")]
+		if !ok {
+			// Not a placeholder, for instance in a comment.
+			return match
+		}
+		return v
+	}), nil
+}
+
+// ValidateLandscapeTemplate returns an error if the Landscape client configuration contains
+// placeholders that are unknown or out of section [client].
+func ValidateLandscapeTemplate(landscapeConfig string) error {
+	if !placeholderRegex.MatchString(landscapeConfig) {
+		return nil
+	}
+
+	f, err := ini.Load(strings.NewReader(landscapeConfig))
+	if err != nil {
+		return fmt.Errorf("could not parse Landscape configuration: %v", err)
+	}
+
+	known := LandscapeTemplate{}.values()
+
+	var errs error
+	for _, sec := range f.Sections() {
+		for _, key := range sec.Keys() {
+			for _, m := range placeholderRegex.FindAllStringSubmatch(key.Value(), -1) {
+				if sec.Name() != "client" {
+					errs = errors.Join(errs, fmt.Errorf("placeholder %s in key %s of section [%s]: placeholders are only allowed in section [client]", m[0], key.Name(), sec.Name()))
+					continue
+				}
+				if _, ok := known[m[1]]; !ok {
+					errs = errors.Join(errs, fmt.Errorf("unknown placeholder %s in key %s of section [client]", m[0], key.Name()))
+				}
+			}
+		}
+	}
+
+	return errs
+}
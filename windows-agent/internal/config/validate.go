@@ -0,0 +1,256 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
+	"gopkg.in/ini.v1"
+)
+
+// Severity indicates how serious a configuration problem is.
+type Severity int
+
+const (
+	// SeverityWarning -> the configuration works, but probably not the way the user intended.
+	SeverityWarning Severity = iota
+
+	// SeverityError -> some feature will fail because of the configuration.
+	SeverityError
+)
+
+// String implements the fmt.Stringer interface.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("unknown severity (%d)", int(s))
+	}
+}
+
+// Problem is an issue found when validating the configuration.
+type Problem struct {
+	Severity Severity
+
+	// Source is where the offending value comes from.
+	Source Source
+
+	// Field is the name of the offending setting.
+	Field string
+
+	Message string
+}
+
+// String implements the fmt.Stringer interface.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s: %s", p.Severity, p.Field, p.Message)
+}
+
+// Validate inspects the configuration and returns every problem found, so that they can
+// be reported before any task fails because of them. An error is returned only if the
+// configuration cannot be read.
+func (c *Config) Validate(ctx context.Context) ([]Problem, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("config: could not validate configuration: %v", err)
+	}
+
+	var problems []Problem
+	for _, field := range c.unknownRegistryFields {
+		problems = append(problems, Problem{
+			Severity: SeverityWarning,
+			Source:   SourceRegistry,
+			Field:    field,
+			Message:  "unknown registry field, it will be ignored",
+		})
+	}
+
+	var unknownFlags []features.Flag
+	for flag := range c.configState.Features {
+		if !flag.Known() {
+			unknownFlags = append(unknownFlags, flag)
+		}
+	}
+	slices.Sort(unknownFlags)
+
+	for _, flag := range unknownFlags {
+		problems = append(problems, Problem{
+			Severity: SeverityWarning,
+			Source:   SourceUser,
+			Field:    "Features." + string(flag),
+			Message:  "unknown feature flag, it will be ignored",
+		})
+	}
+
+	for _, w := range []struct {
+		value  string
+		source Source
+		field  string
+	}{
+		{c.orgMaintenanceWindow, SourceRegistry, "MaintenanceWindow"},
+		{c.configState.MaintenanceWindow, SourceUser, "MaintenanceWindow"},
+	} {
+		if _, err := ParseMaintenanceWindow(w.value); err != nil {
+			problems = append(problems, Problem{
+				Severity: SeverityError,
+				Source:   w.source,
+				Field:    w.field,
+				Message:  fmt.Sprintf("%v: tasks will run at any time", err),
+			})
+		}
+	}
+
+	if l := c.configState.LogLevel; l != "" && !slices.Contains(logLevels, l) {
+		problems = append(problems, Problem{
+			Severity: SeverityWarning,
+			Source:   SourceUser,
+			Field:    "LogLevel",
+			Message:  fmt.Sprintf("unknown log level %q, expected one of %s: the verbosity the agent was started with is kept", l, strings.Join(logLevels, ", ")),
+		})
+	}
+
+	problems = append(problems, c.configState.Subscription.validate()...)
+	problems = append(problems, c.configState.Landscape.validate()...)
+
+	if conf, _ := c.configState.Landscape.resolve(); conf != "" {
+		if token, _ := c.configState.Subscription.resolve(); token == "" {
+			problems = append(problems, Problem{
+				Severity: SeverityError,
+				Source:   SourceNone,
+				Field:    "UbuntuProToken",
+				Message:  "Landscape is configured but there is no Ubuntu Pro token: the agent will not connect to Landscape",
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+// ValidateSnapshot validates the configuration stored in cachePath combined with the provided
+// registry data. Unlike UpdateRegistryData, nothing is written to disk and nobody is notified,
+// so it is safe to use while the agent is running.
+func ValidateSnapshot(ctx context.Context, cachePath string, data RegistryData) ([]Problem, error) {
+	c := New(ctx, cachePath)
+
+	c.configState.Subscription.Organization = data.UbuntuProToken
+	c.configState.Landscape.OrgConfig = data.LandscapeConfig
+	c.registryReadOnly = data.ReadOnly
+	c.unknownRegistryFields = data.UnknownFields
+	c.orgFeatures = data.Features
+	c.orgMaintenanceWindow = data.MaintenanceWindow
+
+	return c.Validate(ctx)
+}
+
+// validate reports tokens that are ignored because a higher priority source provides a different one.
+func (s subscription) validate() []Problem {
+	active, activeSrc := s.resolve()
+
+	var problems []Problem
+	for _, src := range []struct {
+		token  string
+		source Source
+		field  string
+	}{
+		{s.Store, SourceMicrosoftStore, "Subscription.Store"},
+		{s.User, SourceUser, "Subscription.User"},
+	} {
+		if src.token == "" || src.source == activeSrc || src.token == active {
+			continue
+		}
+
+		problems = append(problems, Problem{
+			Severity: SeverityWarning,
+			Source:   src.source,
+			Field:    src.field,
+			Message:  "token is ignored because a different token with higher priority is in use",
+		})
+	}
+
+	return problems
+}
+
+// validate reports Landscape configurations that the agent or the distros will not be able to use.
+func (p landscapeConf) validate() []Problem {
+	var problems []Problem
+
+	if p.OrgConfig != "" {
+		problems = append(problems, validateLandscapeConfig(p.OrgConfig, SourceRegistry, "LandscapeConfig")...)
+	}
+
+	if p.UserConfig != "" {
+		if p.OrgConfig != "" && p.OrgConfig != p.UserConfig {
+			problems = append(problems, Problem{
+				Severity: SeverityWarning,
+				Source:   SourceUser,
+				Field:    "Landscape.Config",
+				Message:  "configuration is shadowed by the one in the registry",
+			})
+		}
+		problems = append(problems, validateLandscapeConfig(p.UserConfig, SourceUser, "Landscape.Config")...)
+	}
+
+	for _, name := range sortedKeys(p.Endpoints) {
+		problems = append(problems, validateLandscapeConfig(p.Endpoints[name].Config, SourceUser, fmt.Sprintf("Landscape.Endpoints.%s", name))...)
+	}
+
+	for _, distro := range sortedKeys(p.Distros) {
+		name := p.Distros[distro].Endpoint
+		if _, ok := p.Endpoints[name]; name == "" || ok {
+			continue
+		}
+
+		problems = append(problems, Problem{
+			Severity: SeverityWarning,
+			Source:   SourceUser,
+			Field:    fmt.Sprintf("Landscape.Distros.%s.Endpoint", distro),
+			Message:  fmt.Sprintf("there is no Landscape endpoint named %q: the distro is managed by the main one", name),
+		})
+	}
+
+	return problems
+}
+
+// sortedKeys returns the keys of the map in order, so that problems are reported deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func validateLandscapeConfig(config string, source Source, field string) []Problem {
+	newProblem := func(msg string, args ...any) Problem {
+		return Problem{Severity: SeverityError, Source: source, Field: field, Message: fmt.Sprintf(msg, args...)}
+	}
+
+	f, err := ini.Load(strings.NewReader(config))
+	if err != nil {
+		return []Problem{newProblem("could not parse Landscape configuration: %v", err)}
+	}
+
+	var problems []Problem
+
+	if !f.HasSection("client") {
+		problems = append(problems, newProblem("missing section [client]: distros will not be able to register"))
+	}
+
+	if sec, err := f.GetSection("host"); err != nil || !sec.HasKey("url") {
+		problems = append(problems, newProblem("missing key url in section [host]: the agent will not be able to connect"))
+	}
+
+	if err := ValidateLandscapeTemplate(config); err != nil {
+		problems = append(problems, newProblem("%v: distros will not be able to register", err))
+	}
+
+	return problems
+}
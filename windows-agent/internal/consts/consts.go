@@ -7,6 +7,27 @@ const (
 	// DefaultLogLevel is the default logging level selected without any option.
 	DefaultLogLevel = log.WarnLevel
 
+	// LogBufferSize is how many logs can wait to be sent to a client before they start being discarded.
+	LogBufferSize = 256
+
 	// DatabaseFileName corresponds to the base name of the file containing the database.
 	DatabaseFileName = "distros.db"
+
+	// StateVersionFileName is the name of the file that records the layout version of the private directory.
+	StateVersionFileName = "layout-version"
+
+	// BackupsDirName is the name of the directory where distros are exported to by default.
+	BackupsDirName = "backups"
+
+	// RecurringTasksFileName is the name of the file that records when each recurring task last ran.
+	RecurringTasksFileName = "recurring-tasks.yaml"
+
+	// PluginsDirName is the name of the directory where plugins declare the task types they implement.
+	PluginsDirName = "plugins"
+
+	// AuditLogFileName is the name of the file where changes to the host and the agent state are recorded.
+	AuditLogFileName = "audit.log"
+
+	// OnboardingFileName is the name of the file that records the choices made during the initial setup.
+	OnboardingFileName = "onboarding.yaml"
 )
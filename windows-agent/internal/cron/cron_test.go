@@ -0,0 +1,86 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/cron"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expr string
+
+		wantErr bool
+	}{
+		"Success with every field":        {expr: "30 4 1 1 1"},
+		"Success with wildcards":          {expr: "* * * * *"},
+		"Success with ranges and steps":   {expr: "0-30/10 9-17 * * 1-5"},
+		"Success with lists":              {expr: "0,15,45 0 1,15 * *"},
+		"Success with Sunday as 7":        {expr: "0 0 * * 7"},
+		"Success with a macro":            {expr: "@weekly"},
+		"Success with surrounding spaces": {expr: "  @daily  "},
+
+		"Error with too few fields":     {expr: "0 0 * *", wantErr: true},
+		"Error with too many fields":    {expr: "0 0 * * * *", wantErr: true},
+		"Error with an unknown macro":   {expr: "@yearly", wantErr: true},
+		"Error with a value too big":    {expr: "60 * * * *", wantErr: true},
+		"Error with a value too small":  {expr: "* * 0 * *", wantErr: true},
+		"Error with a reversed range":   {expr: "* 5-1 * * *", wantErr: true},
+		"Error with a zero step":        {expr: "*/0 * * * *", wantErr: true},
+		"Error with a non-number value": {expr: "* * * jan *", wantErr: true},
+		"Error with an empty list item": {expr: "1,,2 * * * *", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := cron.Parse(tc.expr)
+			if tc.wantErr {
+				require.Error(t, err, "Parse should return an error")
+				return
+			}
+			require.NoError(t, err, "Parse should return no error")
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	// A Wednesday.
+	from := time.Date(2024, time.March, 6, 10, 17, 42, 0, time.UTC)
+
+	testCases := map[string]struct {
+		expr string
+
+		want time.Time
+	}{
+		"Every minute":                  {expr: "* * * * *", want: time.Date(2024, time.March, 6, 10, 18, 0, 0, time.UTC)},
+		"Every quarter hour":            {expr: "*/15 * * * *", want: time.Date(2024, time.March, 6, 10, 30, 0, 0, time.UTC)},
+		"Later today":                   {expr: "0 18 * * *", want: time.Date(2024, time.March, 6, 18, 0, 0, 0, time.UTC)},
+		"Tomorrow":                      {expr: "0 9 * * *", want: time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)},
+		"Weekly on Sunday":              {expr: "@weekly", want: time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		"Weekly on Sunday written as 7": {expr: "0 0 * * 7", want: time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		"Next month":                    {expr: "@monthly", want: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		"Next year":                     {expr: "0 0 1 1 *", want: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		"Leap day":                      {expr: "0 0 29 2 *", want: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		"Day of the month or week":      {expr: "0 0 20 * 5", want: time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		"Never":                         {expr: "0 0 31 2 *", want: time.Time{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := cron.Parse(tc.expr)
+			require.NoError(t, err, "Setup: Parse should return no error")
+
+			require.Equal(t, tc.want, s.Next(from), "Unexpected next occurrence")
+		})
+	}
+}
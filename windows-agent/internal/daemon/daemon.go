@@ -3,37 +3,68 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"os"
-	"path/filepath"
+	"strconv"
+	"time"
 
-	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/paths"
 	"github.com/ubuntu/decorate"
 	"google.golang.org/grpc"
 )
 
+// liveListenerTimeout is how long to wait for an answer on the port of a previous run before
+// concluding that nothing listens there anymore.
+const liveListenerTimeout = time.Second
+
 // GRPCServiceRegisterer is a function that the daemon will call everytime we want to build a new GRPC object.
 type GRPCServiceRegisterer func(ctx context.Context) *grpc.Server
 
 // Daemon is a daemon for windows agents with grpc support.
 type Daemon struct {
 	listeningPortFilePath string
+	hvsocketPortFilePath  string
+
+	// hvsocket is true if the requests are served on a Hyper-V socket as well.
+	hvsocket bool
 
 	grpcServer *grpc.Server
 }
 
+type options struct {
+	hvsocket bool
+}
+
+// Option is the function signature used to tweak the daemon creation.
+type Option func(*options)
+
+// WithHvsocket serves the requests on a Hyper-V socket as well as on the TCP one. It is the new transport
+// between the agent and the distros, gated by the NewTransport feature flag.
+func WithHvsocket(enabled bool) Option {
+	return func(o *options) {
+		o.hvsocket = enabled
+	}
+}
+
 // New returns an new, initialized daemon server that is ready to register GRPC services.
 // It hooks up to windows service management handler.
-func New(ctx context.Context, registerGRPCServices GRPCServiceRegisterer, addrDir string) *Daemon {
+func New(ctx context.Context, registerGRPCServices GRPCServiceRegisterer, p paths.Paths, args ...Option) *Daemon {
 	log.Debug(ctx, "Building new daemon")
 
-	listeningPortFilePath := filepath.Join(addrDir, common.ListeningPortFileName)
+	var opts options
+	for _, f := range args {
+		f(&opts)
+	}
 
 	return &Daemon{
-		listeningPortFilePath: listeningPortFilePath,
+		listeningPortFilePath: p.PortFile(),
+		hvsocketPortFilePath:  p.HvsocketPortFile(),
+		hvsocket:              opts.hvsocket,
 		grpcServer:            registerGRPCServices(ctx),
 	}
 }
@@ -42,23 +73,35 @@ func New(ctx context.Context, registerGRPCServices GRPCServiceRegisterer, addrDi
 // Before serving, it writes a file on disk on which port it's listening on for client
 // to be able to reach our server.
 // This file is removed once the server stops listening.
+// When Hyper-V sockets are enabled and available, it serves the same requests on one of them as well, so
+// that the distros can reach it even when the network between Windows and WSL is broken.
 func (d Daemon) Serve(ctx context.Context) (err error) {
 	defer decorate.OnError(&err, i18n.G("Daemon: error while serving"))
 
 	log.Debug(ctx, "Daemon: starting to serve requests")
 
-	// TODO: get a local port only, please :)
-	var cfg net.ListenConfig
-	lis, err := cfg.Listen(ctx, "tcp", "")
+	lis, err := d.listen(ctx)
 	if err != nil {
+		// A port file left behind by a previous run would only send clients to a dead port.
+		d.removePortFile(ctx)
+		d.removeHvsocketPortFile(ctx)
 		return fmt.Errorf("can't listen: %v", err)
 	}
 
 	addr := lis.Addr().String()
 
+	// The Hyper-V socket port file must be written before the TCP one: the distros read it
+	// once they notice that the TCP port file changed.
+	if !d.hvsocket {
+		// A port file left behind while the Hyper-V socket was enabled would send the distros to a dead port.
+		d.removeHvsocketPortFile(ctx)
+	} else if stop := d.serveHvsocket(ctx); stop != nil {
+		defer stop()
+	}
+
 	// Write a file on disk to signal selected ports to clients.
 	// We write it here to signal error when calling service.Start().
-	if err := os.WriteFile(d.listeningPortFilePath, []byte(addr), 0600); err != nil {
+	if err := writePortFile(d.listeningPortFilePath, addr); err != nil {
 		return err
 	}
 	defer os.Remove(d.listeningPortFilePath)
@@ -72,6 +115,136 @@ func (d Daemon) Serve(ctx context.Context) (err error) {
 	return nil
 }
 
+// listen listens on a tcp socket. If a previous run left its port file behind, e.g. because it crashed,
+// its port is reused when possible: the distros that keep dialing it can then reconnect without
+// waiting to read the new port file.
+func (d Daemon) listen(ctx context.Context) (net.Listener, error) {
+	// TODO: get a local port only, please :)
+	var cfg net.ListenConfig
+
+	if port, ok := d.orphanedPort(ctx); ok {
+		lis, err := cfg.Listen(ctx, "tcp", fmt.Sprintf(":%d", port))
+		if err == nil {
+			log.Infof(ctx, "Daemon: recovered port %d from the previous run", port)
+			return lis, nil
+		}
+		log.Warningf(ctx, "Daemon: could not listen on port %d from the previous run, picking another one: %v", port, err)
+	}
+
+	return cfg.Listen(ctx, "tcp", "")
+}
+
+// orphanedPort returns the port found in a port file left behind by a previous run. A port file is not
+// orphaned if something still answers on its port. Either way, the file is overwritten once the daemon
+// listens: it is not removed beforehand so that clients polling it never find it missing.
+func (d Daemon) orphanedPort(ctx context.Context) (port int, ok bool) {
+	out, err := os.ReadFile(d.listeningPortFilePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, false
+	} else if err != nil {
+		log.Warningf(ctx, "Daemon: could not read the port file of the previous run: %v", err)
+		return 0, false
+	}
+
+	_, p, err := net.SplitHostPort(string(out))
+	if err == nil {
+		port, err = strconv.Atoi(p)
+	}
+	if err != nil || port <= 0 {
+		log.Warningf(ctx, "Daemon: ignoring unreadable port file from the previous run: %q", out)
+		return 0, false
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", p), liveListenerTimeout)
+	if err == nil {
+		_ = conn.Close()
+		log.Warningf(ctx, "Daemon: port %d of the previous run is still in use: its port file will be overwritten", port)
+		return 0, false
+	}
+
+	log.Infof(ctx, "Daemon: the previous run did not stop cleanly, found its port file pointing to port %d", port)
+
+	return port, true
+}
+
+// serveHvsocket serves gRPC requests on a Hyper-V socket, and advertises its port in the Hyper-V socket
+// port file. This is best-effort: distros use TCP when the file does not exist. It returns a function
+// that removes the file, or nil if the socket is not served.
+func (d Daemon) serveHvsocket(ctx context.Context) (stop func()) {
+	lis, port, err := listenHvsocket(ctx)
+	if err != nil {
+		log.Infof(ctx, "Daemon: not serving on a Hyper-V socket, distros will connect via TCP: %v", err)
+		d.removeHvsocketPortFile(ctx)
+		return nil
+	}
+
+	if err := writePortFile(d.hvsocketPortFilePath, strconv.FormatUint(uint64(port), 10)); err != nil {
+		log.Warningf(ctx, "Daemon: could not write the Hyper-V socket port file, distros will connect via TCP: %v", err)
+		_ = lis.Close()
+		return nil
+	}
+
+	log.Infof(ctx, "Daemon: serving gRPC requests on Hyper-V socket port %d", port)
+
+	go func() {
+		// The listener is closed when the gRPC server stops.
+		if err := d.grpcServer.Serve(lis); err != nil {
+			log.Warningf(ctx, "Daemon: stopped serving on the Hyper-V socket: %v", err)
+			d.removeHvsocketPortFile(ctx)
+		}
+	}()
+
+	return func() { d.removeHvsocketPortFile(ctx) }
+}
+
+// removeHvsocketPortFile removes the Hyper-V socket port file, if any.
+func (d Daemon) removeHvsocketPortFile(ctx context.Context) {
+	if err := os.Remove(d.hvsocketPortFilePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warningf(ctx, "Daemon: could not remove Hyper-V socket port file: %v", err)
+	}
+}
+
+// removePortFile removes the port file, if any.
+func (d Daemon) removePortFile(ctx context.Context) {
+	if err := os.Remove(d.listeningPortFilePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warningf(ctx, "Daemon: could not remove port file: %v", err)
+	}
+}
+
+// writePortFile writes the address to the port file atomically, so that clients polling it never
+// read a partial address.
+func writePortFile(path, addr string) error {
+	tmp := path + ".new"
+	if err := os.WriteFile(tmp, []byte(addr), 0600); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+// RefreshPortFiles rewrites the port files with the same addresses. The distros reconnect when they
+// notice it, which is useful when their connection may have gone stale, e.g. after a network change.
+func (d Daemon) RefreshPortFiles(ctx context.Context) {
+	for _, path := range []string{d.hvsocketPortFilePath, d.listeningPortFilePath} {
+		addr, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			log.Warningf(ctx, "Daemon: could not refresh port file: %v", err)
+			continue
+		}
+
+		if err := writePortFile(path, string(addr)); err != nil {
+			log.Warningf(ctx, "Daemon: could not refresh port file: %v", err)
+		}
+	}
+}
+
 // Quit gracefully quits listening loop and stops the grpc server.
 // It can drop any existing connexion if force is true.
 func (d Daemon) Quit(ctx context.Context, force bool) {
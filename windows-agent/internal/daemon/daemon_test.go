@@ -12,6 +12,7 @@ import (
 	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/daemon"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/daemon/testdata/grpctestservice"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/paths"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -29,7 +30,7 @@ func TestNew(t *testing.T) {
 		return nil
 	}
 
-	_ = daemon.New(context.Background(), countRegistrations, t.TempDir())
+	_ = daemon.New(context.Background(), countRegistrations, paths.Paths{Public: t.TempDir()})
 	require.Equal(t, 1, regCount, "daemon should register GRPC services only once")
 }
 
@@ -68,7 +69,7 @@ func TestStartQuit(t *testing.T) {
 				return server
 			}
 
-			d := daemon.New(ctx, registerer, addrDir)
+			d := daemon.New(ctx, registerer, paths.Paths{Public: addrDir})
 
 			serveErr := make(chan error)
 			go func() {
@@ -144,6 +145,147 @@ func TestStartQuit(t *testing.T) {
 	}
 }
 
+func TestRecoverPortFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		portStillInUse bool
+
+		wantSamePort bool
+	}{
+		"Success reusing the port of a crashed run": {wantSamePort: true},
+
+		"Success picking another port when the previous run is still serving": {portStillInUse: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			addrDir := t.TempDir()
+			addrPath := filepath.Join(addrDir, common.ListeningPortFileName)
+
+			// Find a free port, and leave it behind in a port file as if the agent had crashed.
+			var cfg net.ListenConfig
+			lis, err := cfg.Listen(ctx, "tcp", "")
+			require.NoError(t, err, "Setup: could not listen")
+			previousAddr := lis.Addr().String()
+			if tc.portStillInUse {
+				defer lis.Close()
+			} else {
+				require.NoError(t, lis.Close(), "Setup: could not stop listening")
+			}
+
+			err = os.WriteFile(addrPath, []byte(previousAddr), 0600)
+			require.NoError(t, err, "Setup: could not write the port file of the previous run")
+
+			registerer := func(context.Context) *grpc.Server {
+				return grpc.NewServer()
+			}
+
+			d := daemon.New(ctx, registerer, paths.Paths{Public: addrDir})
+
+			serveErr := make(chan error)
+			go func() {
+				serveErr <- d.Serve(ctx)
+			}()
+
+			var addr string
+			require.Eventually(t, func() bool {
+				out, err := os.ReadFile(addrPath)
+				require.NoError(t, err, "The port file should never be missing")
+				addr = string(out)
+				return addr != previousAddr || !tc.portStillInUse && serving(addr)
+			}, 5*time.Second, 50*time.Millisecond, "Serve should write its port file")
+
+			_, previousPort, err := net.SplitHostPort(previousAddr)
+			require.NoError(t, err, "Setup: could not parse the previous address")
+			_, port, err := net.SplitHostPort(addr)
+			require.NoError(t, err, "The port file should contain a valid address")
+
+			if tc.wantSamePort {
+				require.Equal(t, previousPort, port, "Serve should reuse the port of the crashed run")
+			} else {
+				require.NotEqual(t, previousPort, port, "Serve should not reuse a port that is still in use")
+			}
+
+			d.Quit(ctx, true)
+			require.NoError(t, <-serveErr, "Serve should return no error when stopped")
+			requireWaitPathDoesNotExist(t, addrPath, "Address file should be removed after quitting the server")
+		})
+	}
+}
+
+func TestRefreshPortFiles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	addrDir := t.TempDir()
+
+	registerer := func(context.Context) *grpc.Server {
+		return grpc.NewServer()
+	}
+
+	d := daemon.New(ctx, registerer, paths.Paths{Public: addrDir})
+
+	// Refreshing before serving must not create the port file.
+	addrPath := filepath.Join(addrDir, common.ListeningPortFileName)
+	d.RefreshPortFiles(ctx)
+	requireWaitPathDoesNotExist(t, addrPath, "RefreshPortFiles should not create a missing port file")
+
+	serveErr := make(chan error)
+	go func() { serveErr <- d.Serve(ctx) }()
+
+	requireWaitPathExists(t, addrPath, "Serve should create an address file")
+	want, err := os.ReadFile(addrPath)
+	require.NoError(t, err, "Address file should be readable")
+
+	past := time.Now().Add(-time.Hour)
+	err = os.Chtimes(addrPath, past, past)
+	require.NoError(t, err, "Setup: could not backdate the port file")
+
+	d.RefreshPortFiles(ctx)
+
+	got, err := os.ReadFile(addrPath)
+	require.NoError(t, err, "Address file should be readable after being refreshed")
+	require.Equal(t, string(want), string(got), "RefreshPortFiles should keep the address")
+
+	info, err := os.Stat(addrPath)
+	require.NoError(t, err, "Address file should exist after being refreshed")
+	require.True(t, info.ModTime().After(past.Add(time.Minute)), "RefreshPortFiles should rewrite the port file")
+
+	d.Quit(ctx, true)
+	require.NoError(t, <-serveErr, "Serve should return no error when stopped")
+}
+
+func TestHvsocketDisabled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	addrDir := t.TempDir()
+
+	registerer := func(context.Context) *grpc.Server {
+		return grpc.NewServer()
+	}
+
+	// A previous run with the new transport enabled left its port file behind.
+	hvsocketPath := filepath.Join(addrDir, common.HvsocketPortFileName)
+	err := os.WriteFile(hvsocketPath, []byte("50000"), 0600)
+	require.NoError(t, err, "Setup: could not write the Hyper-V socket port file")
+
+	d := daemon.New(ctx, registerer, paths.Paths{Public: addrDir}, daemon.WithHvsocket(false))
+
+	serveErr := make(chan error)
+	go func() { serveErr <- d.Serve(ctx) }()
+
+	requireWaitPathExists(t, filepath.Join(addrDir, common.ListeningPortFileName), "Serve should create an address file")
+	require.NoFileExists(t, hvsocketPath, "Serve should remove the Hyper-V socket port file when the new transport is disabled")
+
+	d.Quit(ctx, true)
+	require.NoError(t, <-serveErr, "Serve should return no error when stopped")
+}
+
 func TestServeError(t *testing.T) {
 	t.Parallel()
 
@@ -154,7 +296,7 @@ func TestServeError(t *testing.T) {
 		return grpc.NewServer()
 	}
 
-	d := daemon.New(ctx, registerer, addrDir)
+	d := daemon.New(ctx, registerer, paths.Paths{Public: addrDir})
 	defer d.Quit(ctx, false)
 
 	// Remove parent directory to prevent listening port file to be written
@@ -174,7 +316,7 @@ func TestQuitBeforeServe(t *testing.T) {
 		return grpc.NewServer()
 	}
 
-	d := daemon.New(ctx, registerer, addrDir)
+	d := daemon.New(ctx, registerer, paths.Paths{Public: addrDir})
 	d.Quit(ctx, false)
 
 	err := d.Serve(ctx)
@@ -183,6 +325,21 @@ func TestQuitBeforeServe(t *testing.T) {
 	requireWaitPathDoesNotExist(t, filepath.Join(addrDir, common.ListeningPortFileName), "Port file should not exist after returning from Serve()")
 }
 
+// serving returns true if something answers on the port of the address.
+func serving(addr string) bool {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", port), 100*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
 // grpcPersistentCall will create a persistent GRPC connection to the server.
 // It will return immediately. drop() should be called to ends the connection from
 // the client side. It returns the GRPC error code if any.
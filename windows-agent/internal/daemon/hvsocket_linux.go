@@ -0,0 +1,12 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// listenHvsocket always fails: Hyper-V sockets only exist on Windows.
+func listenHvsocket(ctx context.Context) (net.Listener, uint32, error) {
+	return nil, 0, errors.New("only Windows supports Hyper-V sockets")
+}
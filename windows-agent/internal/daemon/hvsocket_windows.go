@@ -0,0 +1,210 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// https://learn.microsoft.com/en-us/virtualization/hyper-v-on-windows/user-guide/make-integration-service
+const (
+	afHyperV      = 34
+	hvProtocolRaw = 1
+
+	// wsaeAddrInUse is the error returned when binding to a service ID that is already taken,
+	// e.g. by the agent of another user.
+	wsaeAddrInUse = windows.Errno(10048)
+)
+
+// hvsocketBasePort is the first vsock port tried when listening. The next ones are tried in order
+// while they are taken.
+const (
+	hvsocketBasePort     = 0x5550
+	hvsocketPortAttempts = 16
+)
+
+var (
+	ws2        = windows.NewLazySystemDLL("ws2_32.dll")
+	procBind   = ws2.NewProc("bind")
+	procAccept = ws2.NewProc("accept")
+)
+
+// hvGUIDChildren is the VM ID that accepts connections from every child partition, such as the WSL VM.
+var hvGUIDChildren = windows.GUID{
+	Data1: 0x90db8b89,
+	Data2: 0x0d35,
+	Data3: 0x4f79,
+	Data4: [8]byte{0x8c, 0xe9, 0x49, 0xea, 0x0a, 0xc8, 0xb7, 0xcd},
+}
+
+// sockaddrHV is the SOCKADDR_HV structure.
+type sockaddrHV struct {
+	family    uint16
+	reserved  uint16
+	vmID      windows.GUID
+	serviceID windows.GUID
+}
+
+// vsockServiceID returns the ID of the Hyper-V socket service that Linux guests reach by connecting to
+// this vsock port on the host.
+func vsockServiceID(port uint32) windows.GUID {
+	return windows.GUID{
+		Data1: port,
+		Data2: 0xfacb,
+		Data3: 0x11e6,
+		Data4: [8]byte{0xbd, 0x58, 0x64, 0x00, 0x6a, 0x79, 0x86, 0xd3},
+	}
+}
+
+// listenHvsocket listens on a Hyper-V socket that the distros reach via vsock, and returns its vsock port.
+func listenHvsocket(ctx context.Context) (lis net.Listener, port uint32, err error) {
+	for port = hvsocketBasePort; port < hvsocketBasePort+hvsocketPortAttempts; port++ {
+		lis, err = listenHvsocketPort(port)
+		if errors.Is(err, wsaeAddrInUse) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		return lis, port, nil
+	}
+
+	return nil, 0, fmt.Errorf("could not find a free vsock port: %v", err)
+}
+
+func listenHvsocketPort(port uint32) (net.Listener, error) {
+	sock, err := windows.Socket(afHyperV, windows.SOCK_STREAM, hvProtocolRaw)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Hyper-V socket: %w", err)
+	}
+
+	addr := sockaddrHV{family: afHyperV, vmID: hvGUIDChildren, serviceID: vsockServiceID(port)}
+	if r, _, err := procBind.Call(uintptr(sock), uintptr(unsafe.Pointer(&addr)), unsafe.Sizeof(addr)); r != 0 {
+		_ = windows.Closesocket(sock)
+		return nil, fmt.Errorf("could not bind Hyper-V socket to port %d: %w", port, err)
+	}
+
+	if err := windows.Listen(sock, windows.SOMAXCONN); err != nil {
+		_ = windows.Closesocket(sock)
+		return nil, fmt.Errorf("could not listen on Hyper-V socket: %w", err)
+	}
+
+	return &hvsocketListener{sock: sock, addr: hvsocketAddr(addr)}, nil
+}
+
+// hvsocketAddr is the address of a Hyper-V socket.
+type hvsocketAddr sockaddrHV
+
+// Network implements net.Addr.
+func (a hvsocketAddr) Network() string {
+	return "hvsock"
+}
+
+// String implements net.Addr.
+func (a hvsocketAddr) String() string {
+	return fmt.Sprintf("%s:%s", a.vmID, a.serviceID)
+}
+
+// hvsocketListener is a net.Listener on a Hyper-V socket.
+type hvsocketListener struct {
+	sock      windows.Handle
+	addr      hvsocketAddr
+	closeOnce sync.Once
+}
+
+// Accept waits for the next connection. It is unblocked by Close.
+func (l *hvsocketListener) Accept() (net.Conn, error) {
+	var remote sockaddrHV
+	size := int32(unsafe.Sizeof(remote))
+
+	r, _, err := procAccept.Call(uintptr(l.sock), uintptr(unsafe.Pointer(&remote)), uintptr(unsafe.Pointer(&size)))
+	if windows.Handle(r) == windows.InvalidHandle {
+		return nil, fmt.Errorf("could not accept Hyper-V socket connection: %w", err)
+	}
+
+	return &hvsocketConn{sock: windows.Handle(r), local: l.addr, remote: hvsocketAddr(remote)}, nil
+}
+
+// Close stops listening.
+func (l *hvsocketListener) Close() (err error) {
+	l.closeOnce.Do(func() { err = windows.Closesocket(l.sock) })
+	return err
+}
+
+// Addr returns the address the listener is bound to.
+func (l *hvsocketListener) Addr() net.Addr {
+	return l.addr
+}
+
+// hvsocketConn is a connection accepted on a Hyper-V socket. Its reads and writes block, so they are
+// unblocked by Close rather than by deadlines.
+type hvsocketConn struct {
+	sock          windows.Handle
+	local, remote hvsocketAddr
+	closeOnce     sync.Once
+}
+
+func (c *hvsocketConn) Read(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	buf := windows.WSABuf{Len: uint32(len(b)), Buf: &b[0]}
+	var n, flags uint32
+	if err := windows.WSARecv(c.sock, &buf, 1, &n, &flags, nil, nil); err != nil {
+		return 0, err
+	}
+
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return int(n), nil
+}
+
+func (c *hvsocketConn) Write(b []byte) (int, error) {
+	var written int
+	for written < len(b) {
+		buf := windows.WSABuf{Len: uint32(len(b) - written), Buf: &b[written]}
+		var n uint32
+		if err := windows.WSASend(c.sock, &buf, 1, &n, 0, nil, nil); err != nil {
+			return written, err
+		}
+		written += int(n)
+	}
+	return written, nil
+}
+
+func (c *hvsocketConn) Close() (err error) {
+	c.closeOnce.Do(func() { err = windows.Closesocket(c.sock) })
+	return err
+}
+
+func (c *hvsocketConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *hvsocketConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// SetDeadline is a no-op: deadlines are not supported.
+func (c *hvsocketConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+// SetReadDeadline is a no-op: deadlines are not supported.
+func (c *hvsocketConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+// SetWriteDeadline is a no-op: deadlines are not supported.
+func (c *hvsocketConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
@@ -0,0 +1,305 @@
+// Package connection manages the connection from the agent to the Linux-side WSL service of a distro.
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/guid"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"github.com/ubuntu/decorate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// ErrClosed is returned when using a connection that has been closed.
+var ErrClosed = errors.New("connection is closed")
+
+// defaultKeepalive is the keepalive used unless overridden. Pings are not sent more often than
+// what gRPC servers accept by default, so that WSL services of any version tolerate them.
+var defaultKeepalive = keepalive.ClientParameters{
+	Time:    5 * time.Minute,
+	Timeout: 20 * time.Second,
+}
+
+// StateNotifier is called every time the connectivity state of the connection changes. Calls are
+// made in order, and must not use the connection.
+type StateNotifier func(connectivity.State)
+
+// Metadata identifies the distro at the other end of a connection, so that logs and errors can
+// tell which distro they are about.
+type Metadata struct {
+	DistroName     string
+	GUID           guid.GUID
+	ServiceVersion string
+	BootID         string
+
+	// Peer is the address the WSL service connected to the agent from.
+	Peer string
+
+	// ConnectedAt is when the WSL service connected to the agent.
+	ConnectedAt time.Time
+}
+
+// String describes the distro for logs and errors.
+func (m Metadata) String() string {
+	if m.DistroName == "" {
+		return "unknown distro"
+	}
+
+	var details []string
+	if !m.GUID.IsZero() {
+		details = append(details, "GUID "+m.GUID.String())
+	}
+	if m.ServiceVersion != "" {
+		details = append(details, "service version "+m.ServiceVersion)
+	}
+	if m.BootID != "" {
+		details = append(details, "boot "+m.BootID)
+	}
+
+	if len(details) == 0 {
+		return fmt.Sprintf("distro %q", m.DistroName)
+	}
+	return fmt.Sprintf("distro %q (%s)", m.DistroName, strings.Join(details, ", "))
+}
+
+type options struct {
+	keepalive   keepalive.ClientParameters
+	notify      StateNotifier
+	dialOptions []grpc.DialOption
+	metadata    Metadata
+}
+
+// Option is an optional argument for New and Dial.
+type Option func(*options)
+
+// WithKeepalive overrides the keepalive parameters of the connection.
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return func(o *options) {
+		o.keepalive = params
+	}
+}
+
+// WithStateNotifier sets a function to be called every time the connectivity state changes.
+func WithStateNotifier(notify StateNotifier) Option {
+	return func(o *options) {
+		o.notify = notify
+	}
+}
+
+// WithDialOptions adds extra options to use when dialing.
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, dialOptions...)
+	}
+}
+
+// WithMetadata attaches the identity of the distro to the connection.
+func WithMetadata(md Metadata) Option {
+	return func(o *options) {
+		o.metadata = md
+	}
+}
+
+// Connection is a connection to the WSL service of a distro. The underlying gRPC connection is
+// only dialed when it is used, and dialed again if it is shut down.
+type Connection struct {
+	addr string
+	opts options
+
+	conn *grpc.ClientConn
+	// watcherDone is closed when the state of conn is no longer being watched.
+	watcherDone chan struct{}
+	closed      bool
+	mu          sync.Mutex
+}
+
+// New creates a connection to the WSL service at the given address. Nothing is dialed until the
+// connection is used.
+func New(addr string, args ...Option) *Connection {
+	opts := options{
+		keepalive: defaultKeepalive,
+		notify:    func(connectivity.State) {},
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return &Connection{
+		addr: addr,
+		opts: opts,
+	}
+}
+
+// Dial creates a connection to the WSL service at the given address, and waits until it is ready
+// or the context is done.
+func Dial(ctx context.Context, addr string, args ...Option) (c *Connection, err error) {
+	c = New(addr, args...)
+	defer decorate.OnError(&err, "could not dial WSL service of %s at %s", c.opts.metadata, addr)
+
+	conn, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		s := conn.GetState()
+		if s == connectivity.Ready {
+			return c, nil
+		}
+
+		if s == connectivity.Idle {
+			conn.Connect()
+		}
+
+		if !conn.WaitForStateChange(ctx, s) {
+			c.Close()
+			return nil, fmt.Errorf("connection not ready: %v", ctx.Err())
+		}
+	}
+}
+
+// Metadata returns the identity of the distro at the other end of the connection.
+func (c *Connection) Metadata() Metadata {
+	return c.opts.metadata
+}
+
+// Client returns a client to the WSL service, dialing it if needed. See ClientConn.
+func (c *Connection) Client() (wslserviceapi.WSLClient, error) {
+	conn, err := c.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+
+	return wslserviceapi.NewWSLClient(conn), nil
+}
+
+// ClientConn returns the gRPC connection to the WSL service, dialing it if needed. A connection
+// that failed is retried right away instead of waiting for its backoff to expire.
+func (c *Connection) ClientConn() (grpc.ClientConnInterface, error) {
+	conn, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	switch conn.GetState() {
+	case connectivity.Idle:
+		conn.Connect()
+	case connectivity.TransientFailure:
+		conn.ResetConnectBackoff()
+	}
+
+	return conn, nil
+}
+
+// State returns the connectivity state of the connection. A connection that was never dialed is
+// idle, and a closed one is shut down.
+func (c *Connection) State() connectivity.State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return connectivity.Shutdown
+	}
+
+	if c.conn == nil {
+		return connectivity.Idle
+	}
+
+	return c.conn.GetState()
+}
+
+// Close shuts the connection down. Closed connections cannot be used anymore.
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	return c.closeConn()
+}
+
+// get returns the underlying gRPC connection, dialing it if there is none or if it was shut down.
+func (c *Connection) get() (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+
+	if c.conn != nil && c.conn.GetState() != connectivity.Shutdown {
+		return c.conn, nil
+	}
+
+	// Not handling the error: the connection is shut down already.
+	_ = c.closeConn()
+
+	opts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(c.opts.keepalive),
+		telemetry.DialOption(),
+	}, c.opts.dialOptions...)
+
+	// Dialing without blocking does not wait for the connection to be established.
+	conn, err := grpc.DialContext(context.Background(), c.addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial WSL service of %s: %v", c.opts.metadata, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchState(conn, c.opts.notify)
+	}()
+
+	c.conn = conn
+	c.watcherDone = done
+
+	return conn, nil
+}
+
+// closeConn closes the underlying gRPC connection, if any. The connection must be locked.
+func (c *Connection) closeConn() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+
+	// Waiting for the shutdown to be reported keeps notifications in order if the connection is dialed again.
+	<-c.watcherDone
+	c.conn = nil
+
+	// A connection that was closed already is not an error.
+	if err != nil && status.Code(err) != codes.Canceled {
+		return fmt.Errorf("could not close connection: %v", err)
+	}
+
+	return nil
+}
+
+// watchState reports every state change of the connection until it is shut down.
+func watchState(conn *grpc.ClientConn, notify StateNotifier) {
+	s := conn.GetState()
+	notify(s)
+
+	for s != connectivity.Shutdown && conn.WaitForStateChange(context.Background(), s) {
+		s = conn.GetState()
+		notify(s)
+	}
+}
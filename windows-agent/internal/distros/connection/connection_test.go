@@ -0,0 +1,219 @@
+package connection_test
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestDial(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noService bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when there is no service to connect to": {noService: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			addr := reserveAddress(t)
+			if !tc.noService {
+				startService(t, addr)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			c, err := connection.Dial(ctx, addr)
+			if tc.wantErr {
+				require.Error(t, err, "Dial should return an error")
+				return
+			}
+			require.NoError(t, err, "Dial should return no error")
+			defer c.Close()
+
+			require.Equal(t, connectivity.Ready, c.State(), "Connection should be ready after dialing")
+		})
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		metadata connection.Metadata
+
+		want string
+	}{
+		"Distro with its whole identity": {
+			metadata: connection.Metadata{DistroName: "Ubuntu", GUID: "{guid}", ServiceVersion: "1.2.3", BootID: "boot-id"},
+			want:     `distro "Ubuntu" (GUID {guid}, service version 1.2.3, boot boot-id)`,
+		},
+		"Distro with part of its identity": {metadata: connection.Metadata{DistroName: "Ubuntu", BootID: "boot-id"}, want: `distro "Ubuntu" (boot boot-id)`},
+		"Distro with only its name":        {metadata: connection.Metadata{DistroName: "Ubuntu"}, want: `distro "Ubuntu"`},
+		"Unknown distro":                   {metadata: connection.Metadata{GUID: "{guid}"}, want: "unknown distro"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := connection.New(reserveAddress(t), connection.WithMetadata(tc.metadata))
+			defer c.Close()
+
+			require.Equal(t, tc.metadata, c.Metadata(), "Metadata should return the metadata the connection was created with")
+			require.Equal(t, tc.want, c.Metadata().String(), "Metadata should describe the distro")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			_, err := connection.Dial(ctx, reserveAddress(t), connection.WithMetadata(tc.metadata))
+			require.ErrorContains(t, err, tc.want, "Dial errors should tell which distro the connection is for")
+		})
+	}
+}
+
+func TestLazyConnection(t *testing.T) {
+	t.Parallel()
+
+	addr := reserveAddress(t)
+
+	// The service is not running yet, but nothing is dialed so creating the connection succeeds.
+	c := connection.New(addr)
+	defer c.Close()
+	require.Equal(t, connectivity.Idle, c.State(), "Connection should be idle before being used")
+
+	s := startService(t, addr)
+
+	client, err := c.Client()
+	require.NoError(t, err, "Client should return no error")
+
+	_, err = client.Ping(context.Background(), &wslserviceapi.Empty{})
+	require.NoError(t, err, "Ping should reach the service")
+	require.Equal(t, connectivity.Ready, c.State(), "Connection should be ready after being used")
+
+	// Restart the service: the connection must recover when it is used again.
+	s.Stop()
+	require.Eventually(t, func() bool {
+		return c.State() != connectivity.Ready
+	}, 5*time.Second, 10*time.Millisecond, "Connection should notice that the service stopped")
+
+	startService(t, addr)
+
+	require.Eventually(t, func() bool {
+		client, err := c.Client()
+		if err != nil {
+			return false
+		}
+		_, err = client.Ping(context.Background(), &wslserviceapi.Empty{})
+		return err == nil
+	}, 5*time.Second, 100*time.Millisecond, "Connection should reach the restarted service")
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	addr := reserveAddress(t)
+	startService(t, addr)
+
+	c := connection.New(addr)
+
+	_, err := c.Client()
+	require.NoError(t, err, "Setup: Client should return no error")
+
+	require.NoError(t, c.Close(), "Close should return no error")
+	require.NoError(t, c.Close(), "Close should return no error when called twice")
+
+	require.Equal(t, connectivity.Shutdown, c.State(), "Closed connection should be shut down")
+
+	_, err = c.Client()
+	require.ErrorIs(t, err, connection.ErrClosed, "Client should return an error after closing")
+}
+
+func TestStateNotifier(t *testing.T) {
+	t.Parallel()
+
+	addr := reserveAddress(t)
+	startService(t, addr)
+
+	var mu sync.Mutex
+	var states []connectivity.State
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := connection.Dial(ctx, addr, connection.WithStateNotifier(func(s connectivity.State) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, s)
+	}))
+	require.NoError(t, err, "Setup: Dial should return no error")
+
+	// Fast transitions may be reported as one, so the notification can arrive after Dial returns.
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Contains(states, connectivity.Ready)
+	}, 5*time.Second, 10*time.Millisecond, "Notifier should be told the connection is ready")
+
+	require.NoError(t, c.Close(), "Close should return no error")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, connectivity.Shutdown, states[len(states)-1], "The last notification should be the shutdown")
+	require.Less(t, slices.Index(states, connectivity.Ready), len(states)-1, "The connection should be ready before being shut down")
+}
+
+type wslService struct {
+	wslserviceapi.UnimplementedWSLServer
+}
+
+func (wslService) Ping(context.Context, *wslserviceapi.Empty) (*wslserviceapi.Empty, error) {
+	return &wslserviceapi.Empty{}, nil
+}
+
+// reserveAddress returns a local address that nothing is listening to.
+func reserveAddress(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp4", "localhost:0")
+	require.NoError(t, err, "Setup: could not reserve a port")
+
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close(), "Setup: could not release the port")
+
+	return addr
+}
+
+// startService serves a WSL service at the given address until the test ends.
+func startService(t *testing.T, addr string) *grpc.Server {
+	t.Helper()
+
+	lis, err := net.Listen("tcp4", addr)
+	require.NoError(t, err, "Setup: could not listen")
+
+	server := grpc.NewServer()
+	wslserviceapi.RegisterWSLServer(server, wslService{})
+
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return server
+}
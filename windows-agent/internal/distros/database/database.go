@@ -15,9 +15,11 @@ import (
 	"time"
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
 	"github.com/ubuntu/decorate"
 	"gopkg.in/yaml.v3"
 )
@@ -37,14 +39,132 @@ type DistroDB struct {
 
 	storageDir   string
 	provisioning worker.Provisioning
+	schedule     worker.Schedule
+	pauser       worker.Pauser
+	wslWatcher   worker.WSLWatcher
+
+	// onAdoption is called when a distro the database did not know about is added to it.
+	onAdoption func(ctx context.Context, name string)
+
+	// lockWatchdog is how long distros wait for their internal locks before reporting a suspected deadlock.
+	lockWatchdog time.Duration
+
+	// hibernateAfter is how long a distro must be idle before its worker is stopped. Zero disables hibernation.
+	hibernateAfter time.Duration
+
+	// timeouts is how long the distros wait for their Linux side.
+	timeouts timeouts.Policy
+
+	// clock tells the time to the distros and to the startup phase.
+	clock clock.Clock
+
+	// taskHooks are told about the tasks of every distro as they go through its worker.
+	taskHooks []worker.Hooks
+
+	// watchers are sent the changes made to the database. See Watch.
+	watchers   map[*watcher]struct{}
+	watchersMu sync.Mutex
 
 	ctx       context.Context
 	cancelCtx func()
 	once      sync.Once
 
-	// Multiple distros starting at the same time can cause WSL (and the whole machine) to freeze up.
-	// This mutex is used to block multiple distros from starting at the same time.
-	distroStartMu sync.Mutex
+	// Many distros starting at the same time can cause WSL (and the whole machine) to freeze up.
+	// This limiter is used to cap how many distros can be starting at the same time.
+	distroStartMu *startupLimiter
+}
+
+type options struct {
+	maxParallelStartups int
+	schedule            worker.Schedule
+	pauser              worker.Pauser
+	wslWatcher          worker.WSLWatcher
+	onAdoption          func(ctx context.Context, name string)
+	lockWatchdog        time.Duration
+	hibernateAfter      time.Duration
+	timeouts            timeouts.Policy
+	taskHooks           []worker.Hooks
+	clock               clock.Clock
+}
+
+// Option is an optional argument for database.New.
+type Option func(*options)
+
+// WithMaxParallelStartups sets how many distros can be starting at the same time. Defaults to one.
+func WithMaxParallelStartups(n int) Option {
+	return func(o *options) {
+		o.maxParallelStartups = n
+	}
+}
+
+// WithSchedule makes the distros hold back tasks that are not urgent until the schedule allows them to run.
+func WithSchedule(s worker.Schedule) Option {
+	return func(o *options) {
+		o.schedule = s
+	}
+}
+
+// WithPauser makes the distros hold back all tasks while the agent is paused.
+func WithPauser(p worker.Pauser) Option {
+	return func(o *options) {
+		o.pauser = p
+	}
+}
+
+// WithWSLWatcher makes the distros retry the tasks that cannot reach them because WSL is unavailable
+// once it is back, instead of invalidating them. Pass the watcher to WithPauser as well so that no task
+// runs in the meantime.
+func WithWSLWatcher(w worker.WSLWatcher) Option {
+	return func(o *options) {
+		o.wslWatcher = w
+	}
+}
+
+// WithAdoptionNotifier sets a function to be called when a new distro is added to the database, so
+// that the user can be told about it. It is called asynchronously.
+func WithAdoptionNotifier(f func(ctx context.Context, name string)) Option {
+	return func(o *options) {
+		o.onAdoption = f
+	}
+}
+
+// WithLockWatchdog makes the distros report operations that wait for their internal locks for longer
+// than the threshold, which likely means that there is a deadlock. Disabled by default.
+func WithLockWatchdog(threshold time.Duration) Option {
+	return func(o *options) {
+		o.lockWatchdog = threshold
+	}
+}
+
+// WithHibernation makes the distros that have been idle for at least idleFor release the resources
+// of their task processing, until they are needed again. Disabled by default.
+func WithHibernation(idleFor time.Duration) Option {
+	return func(o *options) {
+		o.hibernateAfter = idleFor
+	}
+}
+
+// WithTimeouts overrides how long the distros wait for their Linux side to connect, and how long the
+// RPCs made to it may take.
+func WithTimeouts(policy timeouts.Policy) Option {
+	return func(o *options) {
+		o.timeouts = policy
+	}
+}
+
+// WithClock overrides the clock used to tell whether the distros are idle and whether tasks may run.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithTaskHooks lets observability sinks follow the tasks of every distro as they are queued, run and
+// retried. It can be passed several times: every set of hooks is called.
+func WithTaskHooks(hooks ...worker.Hooks) Option {
+	return func(o *options) {
+		o.taskHooks = append(o.taskHooks, hooks...)
+	}
 }
 
 // New creates a database and populates it with data in the file located
@@ -56,11 +176,24 @@ type DistroDB struct {
 // undefined behaviour.
 //
 // Every certain amount of times, the database wil purge all distros that
-// are no longer registered or that have been marked as unreachable. This
-// cleanup can be triggered on demmand with TriggerCleanup.
-func New(ctx context.Context, storageDir string, provisioning worker.Provisioning) (db *DistroDB, err error) {
+// are no longer registered or that have been marked as unreachable, and
+// hibernate the idle ones. This cleanup can be triggered on demmand with
+// TriggerCleanup.
+func New(ctx context.Context, storageDir string, provisioning worker.Provisioning, args ...Option) (db *DistroDB, err error) {
 	defer decorate.OnError(&err, "could not initialize database")
 
+	opts := options{
+		maxParallelStartups: 1,
+		clock:               clock.Real(),
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	if opts.maxParallelStartups < 1 {
+		return nil, fmt.Errorf("the maximum number of parallel startups must be positive, got %d", opts.maxParallelStartups)
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -73,8 +206,19 @@ func New(ctx context.Context, storageDir string, provisioning worker.Provisionin
 		storageDir:      storageDir,
 		scheduleTrigger: make(chan struct{}),
 		provisioning:    provisioning,
+		schedule:        opts.schedule,
+		pauser:          opts.pauser,
+		wslWatcher:      opts.wslWatcher,
+		onAdoption:      opts.onAdoption,
+		lockWatchdog:    opts.lockWatchdog,
+		hibernateAfter:  opts.hibernateAfter,
+		timeouts:        opts.timeouts,
+		clock:           opts.clock,
+		taskHooks:       opts.taskHooks,
+		watchers:        make(map[*watcher]struct{}),
 		ctx:             ctx,
 		cancelCtx:       cancel,
+		distroStartMu:   newStartupLimiter(opts.maxParallelStartups),
 	}
 
 	if err := db.load(ctx); err != nil {
@@ -93,6 +237,8 @@ func New(ctx context.Context, storageDir string, provisioning worker.Provisionin
 			if err := db.cleanup(ctx); err != nil {
 				log.Errorf(ctx, "Database: failed to clean up potentially unused distros: %v", err)
 			}
+
+			db.hibernateIdle(ctx)
 		}
 	}()
 
@@ -130,6 +276,46 @@ func (db *DistroDB) GetAll() (all []*distro.Distro) {
 	return all
 }
 
+// Select returns the distros whose labels match the selector.
+func (db *DistroDB) Select(sel distro.Selector) (selected []*distro.Distro) {
+	for _, d := range db.GetAll() {
+		if sel.Matches(d.Labels()) {
+			selected = append(selected, d)
+		}
+	}
+
+	return selected
+}
+
+// SetDistroLabels replaces the labels of the distro, and stores them if they changed.
+func (db *DistroDB) SetDistroLabels(name string, labels map[string]string) (err error) {
+	if db.stopped() {
+		panic("SetDistroLabels: database already stopped")
+	}
+
+	defer decorate.OnError(&err, "could not set labels of distro %q", name)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	d, found := db.distros[strings.ToLower(name)]
+	if !found {
+		return errors.New("distro not in database")
+	}
+
+	changed, err := d.SetLabels(labels)
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		return nil
+	}
+
+	db.notify(DistroUpdated, d.Name())
+	return db.dump()
+}
+
 // GetDistroAndUpdateProperties fetches a distro from the database, guranteeing that the
 // returned distro is valid, is in the database, and matches the given properties. If needed:
 // * A pre-existing distro with the same name may be removed from the database.
@@ -150,11 +336,13 @@ func (db *DistroDB) GetDistroAndUpdateProperties(ctx context.Context, name strin
 	if !found {
 		log.Debugf(ctx, "Database: cache miss, creating %q and adding it to the database", name)
 
-		d, err := distro.New(db.ctx, name, props, db.storageDir, &db.distroStartMu, distro.WithProvisioning(db.provisioning))
+		d, err := distro.New(db.ctx, name, props, db.storageDir, db.distroStartMu, distro.WithProvisioning(db.provisioning), distro.WithSchedule(db.schedule), distro.WithPauser(db.pauser), distro.WithWSLWatcher(db.wslWatcher), distro.WithLockWatchdog(db.lockWatchdog), distro.WithClock(db.clock), distro.WithTimeouts(db.timeouts), distro.WithTaskHooks(db.taskHooks...))
 		if err != nil {
 			return nil, err
 		}
 		db.distros[normalizedName] = d
+		db.notifyAdoption(name)
+		db.notify(DistroAdded, d.Name())
 		err = db.dump()
 		return d, err
 	}
@@ -167,12 +355,15 @@ func (db *DistroDB) GetDistroAndUpdateProperties(ctx context.Context, name strin
 
 		go d.Cleanup(ctx)
 		delete(db.distros, normalizedName)
+		db.notify(DistroRemoved, d.Name())
 
-		d, err := distro.New(db.ctx, name, props, db.storageDir, &db.distroStartMu, distro.WithProvisioning(db.provisioning))
+		d, err := distro.New(db.ctx, name, props, db.storageDir, db.distroStartMu, distro.WithProvisioning(db.provisioning), distro.WithSchedule(db.schedule), distro.WithPauser(db.pauser), distro.WithWSLWatcher(db.wslWatcher), distro.WithLockWatchdog(db.lockWatchdog), distro.WithClock(db.clock), distro.WithTimeouts(db.timeouts), distro.WithTaskHooks(db.taskHooks...))
 		if err != nil {
 			return nil, err
 		}
 		db.distros[normalizedName] = d
+		db.notifyAdoption(name)
+		db.notify(DistroAdded, d.Name())
 		err = db.dump()
 		return d, err
 	}
@@ -182,12 +373,101 @@ func (db *DistroDB) GetDistroAndUpdateProperties(ctx context.Context, name strin
 	// Name in database, correct GUID: refresh with latest properties of a valid distro
 	var err error
 	if d.SetProperties(props) {
+		db.notify(DistroUpdated, d.Name())
 		err = db.dump()
 	}
 
 	return d, err
 }
 
+// notifyAdoption lets the adoption notifier know that a new distro was added to the database.
+func (db *DistroDB) notifyAdoption(name string) {
+	if db.onAdoption == nil {
+		return
+	}
+	go db.onAdoption(db.ctx, name)
+}
+
+// Reset discards the agent state of a distro and provisions it again from scratch. Its queued tasks
+// are removed and the provisioning tasks are submitted again. The properties of the distro are
+// wiped unless keepProperties is set.
+//
+// A distro that was registered again with the same name, for instance after being re-imported, is
+// replaced by a new entry.
+func (db *DistroDB) Reset(ctx context.Context, name string, keepProperties bool) (err error) {
+	if db.stopped() {
+		panic("Reset: database already stopped")
+	}
+
+	defer decorate.OnError(&err, "could not reset distro %q", name)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	normalizedName := strings.ToLower(name)
+	d, found := db.distros[normalizedName]
+	if !found {
+		return errors.New("distro not in database")
+	}
+
+	var props distro.Properties
+	if keepProperties {
+		props = d.Properties()
+	}
+
+	// Labels are set by the user rather than reported by the distro, so they survive the reset.
+	labels := d.Labels()
+
+	if !d.IsValid() {
+		log.Debugf(ctx, "Database: reset of a re-registered distro. Distro %q removed and added again", name)
+
+		// The new entry shares the task storage of the old one, so the old worker must be
+		// stopped before its tasks are cleared.
+		d.Cleanup(ctx)
+		if err := d.ClearTasks(); err != nil {
+			log.Warningf(ctx, "Database: %v", err)
+		}
+		delete(db.distros, normalizedName)
+		db.notify(DistroRemoved, d.Name())
+
+		d, err := distro.New(db.ctx, name, props, db.storageDir, db.distroStartMu, distro.WithProvisioning(db.provisioning), distro.WithSchedule(db.schedule), distro.WithPauser(db.pauser), distro.WithWSLWatcher(db.wslWatcher), distro.WithLockWatchdog(db.lockWatchdog), distro.WithClock(db.clock), distro.WithTimeouts(db.timeouts), distro.WithTaskHooks(db.taskHooks...))
+		if err != nil {
+			return errors.Join(err, db.dump())
+		}
+		// The labels were valid on the previous entry.
+		_, _ = d.SetLabels(labels)
+		db.distros[normalizedName] = d
+		db.notify(DistroAdded, d.Name())
+		return db.dump()
+	}
+
+	if err := d.ClearTasks(); err != nil {
+		return err
+	}
+
+	if !keepProperties {
+		d.SetProperties(props)
+		d.SetLastContact(time.Time{})
+		d.SetSecurityStatus(distro.SecurityStatus{})
+		d.SetUpdateStatus(distro.UpdateStatus{})
+		d.SetResourceUsage(distro.ResourceUsage{})
+	}
+
+	if db.provisioning != nil {
+		tasks, err := db.provisioning.ProvisioningTasks(ctx, d.Name())
+		if err != nil {
+			return err
+		}
+
+		if err := d.SubmitTasks(tasks...); err != nil {
+			return err
+		}
+	}
+
+	db.notify(DistroUpdated, d.Name())
+	return db.dump()
+}
+
 // Dump stores the current database state to disk, overriding old dumps.
 // Next time we start the agent, the database will be loaded from this dump.
 func (db *DistroDB) Dump() error {
@@ -201,6 +481,27 @@ func (db *DistroDB) Dump() error {
 	return db.dump()
 }
 
+// DistroUpdated stores the current database state to disk after the status of the distro was changed
+// directly on it, and lets the watchers know. See Watch.
+func (db *DistroDB) DistroUpdated(name string) (err error) {
+	if db.stopped() {
+		panic("DistroUpdated: database already stopped")
+	}
+
+	defer decorate.OnError(&err, "could not store update of distro %q", name)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	d, found := db.distros[strings.ToLower(name)]
+	if !found {
+		return errors.New("distro not in database")
+	}
+
+	db.notify(DistroUpdated, d.Name())
+	return db.dump()
+}
+
 // TriggerCleanup forces the database cleanup loop to skip its current delay and
 // call autoCleanup immediately. It is blocking until the cleanup starts.
 func (db *DistroDB) TriggerCleanup() {
@@ -225,6 +526,7 @@ func (db *DistroDB) cleanup(ctx context.Context) error {
 		log.Infof(ctx, "Database: distro %q became invalid, cleaning up.", d.Name())
 		go d.Cleanup(ctx)
 		delete(db.distros, name)
+		db.notify(DistroRemoved, d.Name())
 		needsDBDump = true
 	}
 	if needsDBDump {
@@ -233,6 +535,20 @@ func (db *DistroDB) cleanup(ctx context.Context) error {
 	return nil
 }
 
+// hibernateIdle stops the workers of the distros that have been idle for long enough.
+func (db *DistroDB) hibernateIdle(ctx context.Context) {
+	if db.hibernateAfter <= 0 {
+		return
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	for _, d := range db.distros {
+		d.Hibernate(ctx, db.hibernateAfter)
+	}
+}
+
 // load reads the database from disk.
 func (db *DistroDB) load(ctx context.Context) (err error) {
 	defer decorate.OnError(&err, "failed to load database from disk")
@@ -257,7 +573,7 @@ func (db *DistroDB) load(ctx context.Context) (err error) {
 	// Initializing distros into database
 	db.distros = make(map[string]*distro.Distro, len(distros))
 	for _, inert := range distros {
-		d, err := inert.newDistro(ctx, db.storageDir, &db.distroStartMu)
+		d, err := inert.newDistro(ctx, db.storageDir, db.distroStartMu, distro.WithSchedule(db.schedule), distro.WithPauser(db.pauser), distro.WithWSLWatcher(db.wslWatcher), distro.WithLockWatchdog(db.lockWatchdog), distro.WithClock(db.clock), distro.WithTimeouts(db.timeouts), distro.WithTaskHooks(db.taskHooks...))
 		if err != nil {
 			log.Warningf(ctx, "Database: read invalid distro from database: %#+v", inert)
 			continue
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"text/template"
 	"time"
@@ -17,6 +18,9 @@ import (
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/guid"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task/tasktestutils"
 	"github.com/stretchr/testify/require"
 	wsl "github.com/ubuntu/gowsl"
 	wslmock "github.com/ubuntu/gowsl/mock"
@@ -291,8 +295,8 @@ func TestDatabaseDump(t *testing.T) {
 				require.NotEqualf(t, -1, idx1, "Database dump should contain distro1 (%s). Dump:\n%s", distro1, dump)
 				require.NotEqualf(t, -1, idx2, "Database dump should contain distro2 (%s). Dump:\n%s", distro2, dump)
 
-				require.Equal(t, sd.data[idx1].GUID, guid1, "Database dump GUID for distro1 should match the one it was constructed with. Dump:\n%s", dump)
-				require.Equal(t, sd.data[idx2].GUID, guid2, "Database dump GUID for distro2 should match the one it was constructed with. Dump:\n%s", dump)
+				require.Equal(t, guid1, sd.data[idx1].GUID.String(), "Database dump GUID for distro1 should match the one it was constructed with. Dump:\n%s", dump)
+				require.Equal(t, guid2, sd.data[idx2].GUID.String(), "Database dump GUID for distro2 should match the one it was constructed with. Dump:\n%s", dump)
 			}
 
 			// Anonymizing
@@ -396,7 +400,8 @@ func TestGetDistroAndUpdateProperties(t *testing.T) {
 				distroID{distroInDB, guids[distroInDB]},
 				distroID{reRegisteredDistro, guids[reRegisteredDistro]})
 
-			db, err := database.New(ctx, dbDir, nil)
+			adopted := make(chan string, 1)
+			db, err := database.New(ctx, dbDir, nil, database.WithAdoptionNotifier(func(_ context.Context, name string) { adopted <- name }))
 			require.NoError(t, err, "Setup: New() should return no error")
 			defer db.Close(ctx)
 
@@ -426,7 +431,7 @@ func TestGetDistroAndUpdateProperties(t *testing.T) {
 			require.NotNil(t, d, "GetDistroAndUpdateProperties should return a non-nil distro when the requested one is registered")
 
 			require.Equal(t, tc.distroName, d.Name(), "GetDistroAndUpdateProperties should return a distro with the same name as requested")
-			require.Equal(t, guids[tc.distroName], d.GUID(), "GetDistroAndUpdateProperties should return a GUID that matches the requested distro's")
+			require.Equal(t, guids[tc.distroName], d.GUID().String(), "GetDistroAndUpdateProperties should return a GUID that matches the requested distro's")
 			require.Equal(t, tc.props, d.Properties(), "GetDistroAndUpdateProperties should return the same properties as requested")
 
 			// Ensure writing one distro does not modify another
@@ -436,10 +441,21 @@ func TestGetDistroAndUpdateProperties(t *testing.T) {
 				require.NotNil(t, d, "GetDistroAndUpdateProperties should return a non-nil distro when the returned error is nil")
 
 				require.Equal(t, distroInDB, d.Name(), "GetDistroAndUpdateProperties should not modify other distros' name")
-				require.Equal(t, guids[distroInDB], d.GUID(), "GetDistroAndUpdateProperties should not modify other distros' GUID")
+				require.Equal(t, guids[distroInDB], d.GUID().String(), "GetDistroAndUpdateProperties should not modify other distros' GUID")
 				require.Equal(t, props[distroInDB], d.Properties(), "GetDistroAndUpdateProperties should not modify other distros' properties")
 			}
 
+			if tc.want == missedAndAdded || tc.want == hitUnregisteredDistro {
+				select {
+				case name := <-adopted:
+					require.Equal(t, tc.distroName, name, "GetDistroAndUpdateProperties should notify about the adopted distro")
+				case <-time.After(5 * time.Second):
+					require.Fail(t, "GetDistroAndUpdateProperties should notify about the adopted distro")
+				}
+			} else {
+				require.Empty(t, adopted, "GetDistroAndUpdateProperties should not notify about distros already in the database")
+			}
+
 			lastDumpModTime := fileModTime(t, dbFile)
 			if tc.wantDbDumpRefreshed {
 				require.True(t, lastDumpModTime.After(initialDumpModTime), "GetDistroAndUpdateProperties should modify the database dump file after writing on the database")
@@ -544,6 +560,309 @@ func TestDatabaseCleanup(t *testing.T) {
 	}
 }
 
+func TestHibernation(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distroName, guid := wsltestutils.RegisterDistro(t, ctx, false)
+
+	testCases := map[string]struct {
+		hibernateAfter time.Duration
+
+		wantHibernating bool
+	}{
+		"Success hibernating idle distros": {hibernateAfter: time.Nanosecond, wantHibernating: true},
+
+		"No hibernation of recently used distros": {hibernateAfter: time.Hour},
+		"No hibernation when disabled":            {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbDir := t.TempDir()
+			databaseFromTemplate(t, dbDir, distroID{distroName, guid})
+
+			db, err := database.New(ctx, dbDir, nil, database.WithHibernation(tc.hibernateAfter))
+			require.NoError(t, err, "Setup: New() should have returned no error")
+			defer db.Close(ctx)
+
+			d, ok := db.Get(distroName)
+			require.True(t, ok, "Setup: Distro %q should have been in the database", distroName)
+
+			db.TriggerCleanup()
+
+			const delay = 500 * time.Millisecond
+			if tc.wantHibernating {
+				require.Eventually(t, d.Hibernating, delay, 10*time.Millisecond, "The idle distro should be hibernating after a cleanup")
+				return
+			}
+
+			time.Sleep(delay)
+			require.False(t, d.Hibernating(), "The distro should not be hibernating after a cleanup")
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	props := distro.Properties{DistroID: "ubuntu", VersionID: "22.04", ProAttached: true, Hostname: "TestMachine"}
+
+	testCases := map[string]struct {
+		keepProperties   bool
+		reregisterDistro bool
+		notInDatabase    bool
+		provisioningErr  bool
+
+		wantErr bool
+	}{
+		"Success wiping the properties":                {},
+		"Success keeping the properties":               {keepProperties: true},
+		"Success with a re-registered distro":          {reregisterDistro: true},
+		"Success keeping the re-registered properties": {reregisterDistro: true, keepProperties: true},
+
+		"Error when the distro is not in the database":         {notInDatabase: true, wantErr: true},
+		"Error when the provisioning tasks cannot be obtained": {provisioningErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, guid := wsltestutils.RegisterDistro(t, ctx, false)
+
+			dbDir := t.TempDir()
+			provisioning := &mockProvisioning{}
+
+			db, err := database.New(ctx, dbDir, provisioning)
+			require.NoError(t, err, "Setup: New() should have returned no error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				d, err := db.GetDistroAndUpdateProperties(ctx, distroName, props)
+				require.NoError(t, err, "Setup: could not add distro to the database")
+				d.SetLastContact(time.Now())
+				_, err = d.SetLabels(map[string]string{"env": "dev"})
+				require.NoError(t, err, "Setup: could not set labels")
+
+				// Deferred tasks are never run in this test, so they must still be queued when Reset is called.
+				err = d.SubmitDeferredTasks(tasktestutils.NewBlockingTask())
+				require.NoError(t, err, "Setup: could not submit task")
+			}
+
+			if tc.reregisterDistro {
+				guid = wsltestutils.ReregisterDistro(t, ctx, distroName, false)
+			}
+
+			provisioning.calls.Store(0)
+			provisioning.err = tc.provisioningErr
+
+			err = db.Reset(ctx, distroName, tc.keepProperties)
+			if tc.wantErr {
+				require.Error(t, err, "Reset should return an error")
+				return
+			}
+			require.NoError(t, err, "Reset should return no error")
+
+			d, ok := db.Get(distroName)
+			require.True(t, ok, "Distro should still be in the database after a reset")
+			require.Equal(t, guid, d.GUID().String(), "Distro should have the GUID it is currently registered with")
+			require.EqualValues(t, 1, provisioning.calls.Load(), "Provisioning tasks should have been submitted again")
+			require.Equal(t, map[string]string{"env": "dev"}, d.Labels(), "Labels should have been kept")
+
+			if tc.keepProperties {
+				require.Equal(t, props, d.Properties(), "Properties should have been kept")
+			} else {
+				require.Zero(t, d.Properties(), "Properties should have been wiped")
+				require.Zero(t, d.LastContact(), "Last contact should have been wiped")
+			}
+
+			out, err := os.ReadFile(filepath.Join(dbDir, distroName+".tasks"))
+			require.NoError(t, err, "Could not read the stored tasks")
+			tasks, err := task.UnmarshalYAML(out)
+			require.NoError(t, err, "Could not parse the stored tasks")
+			require.Empty(t, tasks, "Queued tasks should have been removed")
+		})
+	}
+}
+
+func TestSetDistroLabels(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		labels        map[string]string
+		notInDatabase bool
+
+		wantLabels map[string]string
+		wantErr    bool
+	}{
+		"Success setting labels":  {labels: map[string]string{"env": "dev"}, wantLabels: map[string]string{"env": "dev"}},
+		"Success removing labels": {},
+
+		"Error when the distro is not in the database": {labels: map[string]string{"env": "dev"}, notInDatabase: true, wantErr: true},
+		"Error when the labels are invalid":            {labels: map[string]string{"not a key": "dev"}, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+			otherDistro, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			dbDir := t.TempDir()
+			db, err := database.New(ctx, dbDir, nil)
+			require.NoError(t, err, "Setup: New() should have returned no error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				_, err = db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+			}
+			_, err = db.GetDistroAndUpdateProperties(ctx, otherDistro, distro.Properties{})
+			require.NoError(t, err, "Setup: could not add distro to the database")
+
+			err = db.SetDistroLabels(distroName, tc.labels)
+			if tc.wantErr {
+				require.Error(t, err, "SetDistroLabels should return an error")
+				return
+			}
+			require.NoError(t, err, "SetDistroLabels should return no error")
+
+			// Reload the database from disk to check that the labels were stored.
+			db.Close(ctx)
+			db, err = database.New(ctx, dbDir, nil)
+			require.NoError(t, err, "Setup: could not reload the database")
+			defer db.Close(ctx)
+
+			d, ok := db.Get(distroName)
+			require.True(t, ok, "Distro should still be in the database")
+			if len(tc.wantLabels) == 0 {
+				require.Empty(t, d.Labels(), "Distro should have no labels")
+			} else {
+				require.Equal(t, tc.wantLabels, d.Labels(), "Labels should have been stored")
+			}
+
+			var selected []string
+			for _, d := range db.Select(distro.Selector{"env": "dev"}) {
+				selected = append(selected, d.Name())
+			}
+
+			if len(tc.wantLabels) == 0 {
+				require.Empty(t, selected, "No distro should match the selector")
+				return
+			}
+			require.Equal(t, []string{distroName}, selected, "Only the labelled distro should match the selector")
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	db, err := database.New(ctx, t.TempDir(), nil)
+	require.NoError(t, err, "Setup: New() should have returned no error")
+	defer db.Close(ctx)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := db.Watch(watchCtx)
+	require.NoError(t, err, "Watch should return no error")
+	untilClose, err := db.Watch(ctx)
+	require.NoError(t, err, "Watch should return no error")
+
+	_, err = db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+	require.NoError(t, err, "Setup: could not add distro to the database")
+
+	// Neither the properties nor the labels change.
+	_, err = db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+	require.NoError(t, err, "Setup: could not get distro from the database")
+	require.NoError(t, db.SetDistroLabels(distroName, nil), "Setup: could not set labels")
+
+	_, err = db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{Hostname: "NewHostname"})
+	require.NoError(t, err, "Setup: could not update the properties")
+	require.NoError(t, db.SetDistroLabels(distroName, map[string]string{"env": "dev"}), "Setup: could not set labels")
+	require.NoError(t, db.DistroUpdated(distroName), "DistroUpdated should return no error")
+
+	d, ok := db.Get(distroName)
+	require.True(t, ok, "Setup: distro should be in the database")
+	d.Invalidate(ctx)
+	db.TriggerCleanup()
+
+	want := []database.Event{
+		{Kind: database.DistroAdded, Name: distroName},
+		{Kind: database.DistroUpdated, Name: distroName},
+		{Kind: database.DistroUpdated, Name: distroName},
+		{Kind: database.DistroUpdated, Name: distroName},
+		{Kind: database.DistroRemoved, Name: distroName},
+	}
+
+	for _, ch := range []<-chan database.Event{events, untilClose} {
+		for _, w := range want {
+			select {
+			case got := <-ch:
+				require.Equal(t, w, got, "Events should be received in the order the changes were made")
+			case <-time.After(5 * time.Second):
+				require.Failf(t, "Event not received", "Expected %s event for %q", w.Kind, w.Name)
+			}
+		}
+	}
+
+	require.Error(t, db.DistroUpdated(distroName), "DistroUpdated should return an error for a distro not in the database")
+
+	cancel()
+	requireClosed(t, events, "Watch channel should be closed once its context is done")
+
+	db.Close(ctx)
+	requireClosed(t, untilClose, "Watch channel should be closed once the database is closed")
+	_, err = db.Watch(ctx)
+	require.Error(t, err, "Watch should return an error when used after Close")
+}
+
+// requireClosed fails if the channel is not closed within a few seconds. Pending events are discarded.
+func requireClosed(t *testing.T, ch <-chan database.Event, msg string) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			require.Fail(t, msg)
+		}
+	}
+}
+
+type mockProvisioning struct {
+	err   bool
+	calls atomic.Int32
+}
+
+func (p *mockProvisioning) ProvisioningTasks(ctx context.Context, distroName string) ([]task.Task, error) {
+	p.calls.Add(1)
+	if p.err {
+		return nil, errors.New("mock error")
+	}
+	return nil, nil
+}
+
 // fileModTime returns the ModTime of the provided path. If the path
 // does not exist, the time is reported as Unix 0.
 func fileModTime(t *testing.T, path string) time.Time {
@@ -609,6 +928,6 @@ func (sd *structuredDump) anonymise(t *testing.T) {
 
 	for i := range sd.data {
 		sd.data[i].Name = fmt.Sprintf("%%DISTRONAME%d%%", i)
-		sd.data[i].GUID = fmt.Sprintf("%%GUID%d%%", i)
+		sd.data[i].GUID = guid.GUID(fmt.Sprintf("%%GUID%d%%", i))
 	}
 }
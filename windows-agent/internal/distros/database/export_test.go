@@ -10,7 +10,7 @@ import (
 type SerializableDistro = serializableDistro
 
 // NewDistro is a wrapper around newDistro so as to make it accessible to tests.
-func (in SerializableDistro) NewDistro(ctx context.Context, storageDir string, startupMu *sync.Mutex) (*distro.Distro, error) {
+func (in SerializableDistro) NewDistro(ctx context.Context, storageDir string, startupMu sync.Locker) (*distro.Distro, error) {
 	return in.newDistro(ctx, storageDir, startupMu)
 }
 
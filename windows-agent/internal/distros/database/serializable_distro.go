@@ -3,9 +3,10 @@ package database
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
-	"github.com/google/uuid"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/guid"
 )
 
 // serializableDistro is an helper struct for marshalling and unmarshalling into and
@@ -15,26 +16,49 @@ import (
 // with none of the short-term information or functionality.
 type serializableDistro struct {
 	Name string
-	GUID string
+	GUID guid.GUID
 	distro.Properties
+
+	LastContact    time.Time             `yaml:",omitempty"`
+	SecurityStatus distro.SecurityStatus `yaml:",omitempty"`
+	UpdateStatus   distro.UpdateStatus   `yaml:",omitempty"`
+	Labels         map[string]string     `yaml:",omitempty"`
 }
 
 // newDistro calls distro.New with the name, GUID and properties specified
 // in its inert counterpart.
-func (in serializableDistro) newDistro(ctx context.Context, storageDir string, startupMu *sync.Mutex) (*distro.Distro, error) {
-	GUID, err := uuid.Parse(in.GUID)
+func (in serializableDistro) newDistro(ctx context.Context, storageDir string, startupMu sync.Locker, args ...distro.Option) (*distro.Distro, error) {
+	// Older databases may store the GUID between braces or in uppercase.
+	id, err := guid.Parse(in.GUID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := distro.New(ctx, in.Name, in.Properties, storageDir, startupMu, append(args, distro.WithGUID(id))...)
 	if err != nil {
 		return nil, err
 	}
-	return distro.New(ctx, in.Name, in.Properties, storageDir, startupMu, distro.WithGUID(GUID))
+
+	d.SetLastContact(in.LastContact)
+	d.SetSecurityStatus(in.SecurityStatus)
+	d.SetUpdateStatus(in.UpdateStatus)
+
+	// Labels are validated when they are set: if the database was edited into invalid ones, they are dropped.
+	_, _ = d.SetLabels(in.Labels)
+
+	return d, nil
 }
 
 // newSerializableDistro takes the information in distro.Distro relevant to the database
 // and stores it the helper object.
 func newSerializableDistro(d *distro.Distro) serializableDistro {
 	return serializableDistro{
-		Name:       d.Name(),
-		GUID:       d.GUID(),
-		Properties: d.Properties(),
+		Name:           d.Name(),
+		GUID:           d.GUID(),
+		Properties:     d.Properties(),
+		LastContact:    d.LastContact(),
+		SecurityStatus: d.SecurityStatus(),
+		UpdateStatus:   d.UpdateStatus(),
+		Labels:         d.Labels(),
 	}
 }
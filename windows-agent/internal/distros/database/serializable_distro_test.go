@@ -4,10 +4,12 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/guid"
 	"github.com/stretchr/testify/require"
 	wsl "github.com/ubuntu/gowsl"
 	wslmock "github.com/ubuntu/gowsl/mock"
@@ -40,6 +42,30 @@ func TestSerializableDistroMarshallUnmarshall(t *testing.T) {
 				Hostname:    "Machine122",
 			},
 		},
+		"With security status": {
+			Name: "Ubuntu",
+			GUID: "{12345678-1234-1234-1234-123456789abc}",
+			Properties: distro.Properties{
+				DistroID:  "Ubuntu",
+				VersionID: "98.04",
+				Hostname:  "Machine98",
+			},
+			SecurityStatus: distro.SecurityStatus{
+				SecurityUpdates: 3,
+				ESMUpdates:      19,
+				Checked:         time.Date(2024, 4, 1, 12, 30, 0, 0, time.UTC),
+			},
+		},
+		"With labels": {
+			Name: "Ubuntu",
+			GUID: "{12345678-1234-1234-1234-123456789abc}",
+			Properties: distro.Properties{
+				DistroID:  "Ubuntu",
+				VersionID: "98.04",
+				Hostname:  "Machine98",
+			},
+			Labels: map[string]string{"env": "dev", "example.com/team": "web"},
+		},
 		"Control characters": {
 			Name: "Ubuntu",
 			GUID: "{12345678-1234-1234-1234-123456789abc}",
@@ -105,7 +131,7 @@ func TestSerializableDistroNewDistro(t *testing.T) {
 
 			s := database.SerializableDistro{
 				Name: tc.distro,
-				GUID: tc.guid,
+				GUID: guid.GUID(tc.guid),
 			}
 
 			// This distro is never started, so no need for any global mutex
@@ -150,6 +176,6 @@ func TestNewSerializableDistro(t *testing.T) {
 
 	s := database.NewSerializableDistro(d)
 	require.Equal(t, registeredDistro, s.Name)
-	require.Equal(t, registeredGUID, s.GUID)
+	require.Equal(t, registeredGUID, s.GUID.String())
 	require.Equal(t, props, s.Properties)
 }
@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
+	"github.com/ubuntu/decorate"
+	"gopkg.in/yaml.v3"
+)
+
+// snapshot is the persistent state of every distro in a database, in a single document that can
+// be attached to a bug report or used as a test fixture.
+type snapshot struct {
+	Distros []snapshotDistro
+}
+
+// snapshotDistro is the entry of a distro in the database, along with its queued tasks.
+type snapshotDistro struct {
+	serializableDistro `yaml:",inline"`
+
+	// Tasks is the content of the task storage of the distro.
+	Tasks string `yaml:",omitempty"`
+}
+
+// Snapshot returns the persistent state of every distro in the database: their properties and
+// their queued tasks. It can be fed to Restore to bring a database back to the same state.
+func (db *DistroDB) Snapshot() (out []byte, err error) {
+	if db.stopped() {
+		panic("Snapshot: database already stopped")
+	}
+
+	defer decorate.OnError(&err, "could not snapshot database")
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	names := make([]string, 0, len(db.distros))
+	for n := range db.distros {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var s snapshot
+	for _, n := range names {
+		d := newSerializableDistro(db.distros[n])
+		tasks, err := readTaskFile(db.storageDir, d.Name)
+		if err != nil {
+			return nil, err
+		}
+		s.Distros = append(s.Distros, snapshotDistro{serializableDistro: d, Tasks: tasks})
+	}
+
+	return yaml.Marshal(s)
+}
+
+// Restore replaces the contents of the database with a snapshot generated by Snapshot. The
+// distros in the database are stopped, and the ones in the snapshot are started with the
+// queued tasks they had. Distros in the snapshot that are not registered are skipped.
+func (db *DistroDB) Restore(ctx context.Context, data []byte) (err error) {
+	if db.stopped() {
+		panic("Restore: database already stopped")
+	}
+
+	defer decorate.OnError(&err, "could not restore database")
+
+	var s snapshot
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("could not unmarshal snapshot: %v", err)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	// The workers must be stopped before their task storage is overwritten.
+	for _, d := range db.distros {
+		d.Cleanup(ctx)
+	}
+	db.distros = make(map[string]*distro.Distro, len(s.Distros))
+
+	for _, in := range s.Distros {
+		if err := writeTaskFile(db.storageDir, in.Name, in.Tasks); err != nil {
+			return err
+		}
+
+		d, err := in.newDistro(db.ctx, db.storageDir, db.distroStartMu, distro.WithSchedule(db.schedule), distro.WithPauser(db.pauser), distro.WithWSLWatcher(db.wslWatcher), distro.WithLockWatchdog(db.lockWatchdog), distro.WithTimeouts(db.timeouts), distro.WithTaskHooks(db.taskHooks...))
+		if err != nil {
+			log.Warningf(ctx, "Database: skipping distro %q from snapshot: %v", in.Name, err)
+			continue
+		}
+		db.distros[strings.ToLower(d.Name())] = d
+	}
+
+	return db.dump()
+}
+
+// SnapshotDir is like Snapshot, but it reads the state from the storage directory of a database
+// that is not running, without starting any distro.
+func SnapshotDir(storageDir string) (out []byte, err error) {
+	defer decorate.OnError(&err, "could not snapshot database in %q", storageDir)
+
+	dump, err := os.ReadFile(filepath.Join(storageDir, consts.DatabaseFileName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var distros []serializableDistro
+	if err := yaml.Unmarshal(dump, &distros); err != nil {
+		return nil, fmt.Errorf("could not unmarshal database: %v", err)
+	}
+
+	var s snapshot
+	for _, d := range distros {
+		tasks, err := readTaskFile(storageDir, d.Name)
+		if err != nil {
+			return nil, err
+		}
+		s.Distros = append(s.Distros, snapshotDistro{serializableDistro: d, Tasks: tasks})
+	}
+
+	return yaml.Marshal(s)
+}
+
+// RestoreDir is like Restore, but it writes the state into the storage directory of a database
+// that is not running. Unlike Restore, distros are not checked to be registered.
+func RestoreDir(storageDir string, data []byte) (err error) {
+	defer decorate.OnError(&err, "could not restore database in %q", storageDir)
+
+	var s snapshot
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("could not unmarshal snapshot: %v", err)
+	}
+
+	distros := make([]serializableDistro, 0, len(s.Distros))
+	for _, d := range s.Distros {
+		if err := writeTaskFile(storageDir, d.Name, d.Tasks); err != nil {
+			return err
+		}
+		distros = append(distros, d.serializableDistro)
+	}
+
+	out, err := yaml.Marshal(distros)
+	if err != nil {
+		return fmt.Errorf("could not marshal database: %v", err)
+	}
+
+	storagePath := filepath.Join(storageDir, consts.DatabaseFileName)
+	if err := os.WriteFile(storagePath+".new", out, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(storagePath+".new", storagePath)
+}
+
+// readTaskFile returns the content of the task storage of the distro, or an empty string if there is none.
+func readTaskFile(storageDir, distroName string) (string, error) {
+	out, err := os.ReadFile(worker.TaskFile(storageDir, distroName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("could not read tasks of distro %q: %v", distroName, err)
+	}
+
+	return string(out), nil
+}
+
+// writeTaskFile overwrites the task storage of the distro. An empty content removes it.
+func writeTaskFile(storageDir, distroName, tasks string) error {
+	path := worker.TaskFile(storageDir, distroName)
+
+	if tasks == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("could not remove tasks of distro %q: %v", distroName, err)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(tasks), 0600); err != nil {
+		return fmt.Errorf("could not write tasks of distro %q: %v", distroName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package database_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task/tasktestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	props := distro.Properties{DistroID: "ubuntu", VersionID: "22.04", ProAttached: true, Hostname: "TestMachine"}
+
+	testCases := map[string]struct {
+		offlineSnapshot    bool
+		offlineRestore     bool
+		unregisteredDistro bool
+		badSnapshot        bool
+
+		wantErr bool
+	}{
+		"Success restoring a live database":                {},
+		"Success restoring a live database from its files": {offlineSnapshot: true},
+		"Success restoring into a database directory":      {offlineRestore: true},
+		"Success skipping unregistered distros":            {unregisteredDistro: true},
+
+		"Error when the snapshot cannot be parsed":                  {badSnapshot: true, wantErr: true},
+		"Error when the snapshot cannot be parsed from a directory": {badSnapshot: true, offlineRestore: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, guid := wsltestutils.RegisterDistro(t, ctx, false)
+
+			srcDir := t.TempDir()
+			src, err := database.New(ctx, srcDir, nil)
+			require.NoError(t, err, "Setup: New() should have returned no error")
+			defer src.Close(ctx)
+
+			d, err := src.GetDistroAndUpdateProperties(ctx, distroName, props)
+			require.NoError(t, err, "Setup: could not add distro to the database")
+
+			// Deferred tasks are never run in this test, so they must still be queued when restored.
+			require.NoError(t, d.SubmitDeferredTasks(tasktestutils.NewBlockingTask()), "Setup: could not submit task")
+
+			var snapshot []byte
+			if tc.offlineSnapshot {
+				snapshot, err = database.SnapshotDir(srcDir)
+				require.NoError(t, err, "SnapshotDir should return no error")
+			} else {
+				snapshot, err = src.Snapshot()
+				require.NoError(t, err, "Snapshot should return no error")
+			}
+
+			if tc.unregisteredDistro {
+				unregistered := "distros:\n    - name: NotRegistered\n      guid: '{f0f0f0f0-0000-0000-0000-000000000000}'\n"
+				snapshot = []byte(strings.Replace(string(snapshot), "distros:\n", unregistered, 1))
+			}
+			if tc.badSnapshot {
+				snapshot = []byte("\tThis is not YAML!")
+			}
+
+			dstDir := t.TempDir()
+			var dst *database.DistroDB
+			if tc.offlineRestore {
+				err = database.RestoreDir(dstDir, snapshot)
+				if tc.wantErr {
+					require.Error(t, err, "RestoreDir should return an error")
+					return
+				}
+				require.NoError(t, err, "RestoreDir should return no error")
+
+				dst, err = database.New(ctx, dstDir, nil)
+				require.NoError(t, err, "New() should load the restored database")
+				defer dst.Close(ctx)
+			} else {
+				dst, err = database.New(ctx, dstDir, nil)
+				require.NoError(t, err, "Setup: New() should have returned no error")
+				defer dst.Close(ctx)
+
+				err = dst.Restore(ctx, snapshot)
+				if tc.wantErr {
+					require.Error(t, err, "Restore should return an error")
+					return
+				}
+				require.NoError(t, err, "Restore should return no error")
+			}
+
+			require.ElementsMatch(t, []string{distroName}, dst.DistroNames(), "Only the registered distro should have been restored")
+
+			got, ok := dst.Get(distroName)
+			require.True(t, ok, "Distro should have been restored")
+			require.Equal(t, guid, got.GUID().String(), "Distro should have been restored with the same GUID")
+			require.Equal(t, props, got.Properties(), "Distro should have been restored with the same properties")
+
+			out, err := os.ReadFile(worker.TaskFile(dstDir, distroName))
+			require.NoError(t, err, "Could not read the restored tasks")
+			tasks, err := task.UnmarshalYAML(out)
+			require.NoError(t, err, "Could not parse the restored tasks")
+			require.Len(t, tasks, 1, "Queued tasks should have been restored")
+
+			// The restored database must be indistinguishable from the original one.
+			again, err := dst.Snapshot()
+			require.NoError(t, err, "Snapshot of the restored database should return no error")
+			want, err := src.Snapshot()
+			require.NoError(t, err, "Snapshot of the original database should return no error")
+			require.Equal(t, string(want), string(again), "The restored database should have the same snapshot as the original one")
+
+			_, err = os.Stat(filepath.Join(dstDir, "distros.db"))
+			require.NoError(t, err, "The restored database should have been written to disk")
+
+			dst.Close(ctx)
+			require.Panics(t, func() { _, _ = dst.Snapshot() }, "Snapshot should panic when used after Close")
+			require.Panics(t, func() { _ = dst.Restore(ctx, snapshot) }, "Restore should panic when used after Close")
+		})
+	}
+}
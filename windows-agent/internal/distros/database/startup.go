@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+)
+
+// startupLimiter is a sync.Locker that lets up to a fixed number of holders in at the same time.
+type startupLimiter struct {
+	slots chan struct{}
+}
+
+func newStartupLimiter(n int) *startupLimiter {
+	return &startupLimiter{slots: make(chan struct{}, n)}
+}
+
+// Lock blocks until a slot is free and takes it.
+func (l *startupLimiter) Lock() {
+	l.slots <- struct{}{}
+}
+
+// LockContext is like Lock, but it gives up when the context is cancelled.
+func (l *startupLimiter) LockContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case l.slots <- struct{}{}:
+		return nil
+	}
+}
+
+// Unlock frees the slot taken by the last call to Lock.
+func (l *startupLimiter) Unlock() {
+	<-l.slots
+}
+
+// ProvisioningProgress is the aggregate progress of the startup provisioning phase.
+type ProvisioningProgress struct {
+	// Total is the number of distros with pending tasks when the phase started.
+	Total int
+
+	// Provisioned is the number of distros that were woken up and connected to the agent.
+	Provisioned int
+
+	// Failed is the number of distros that could not be woken up or did not connect in time.
+	Failed int
+}
+
+// Finished returns true when every distro has either been provisioned or failed.
+func (p ProvisioningProgress) Finished() bool {
+	return p.Provisioned+p.Failed == p.Total
+}
+
+func (p ProvisioningProgress) String() string {
+	return fmt.Sprintf("%d/%d distros provisioned, %d failed", p.Provisioned, p.Total, p.Failed)
+}
+
+// Provision wakes up every distro with pending tasks, as many at the same time as the maximum
+// number of parallel startups allows, and keeps each one awake until it connects to the agent so
+// that its worker can process its tasks. Outside of the maintenance window, it does nothing. While
+// the agent is paused, it waits until it is resumed.
+//
+// The report function is called with the aggregate progress every time a distro is done. It can
+// be nil. Provision blocks until all distros are done, the context is cancelled, or the database is
+// closed, and returns the final progress. It is meant to be called in a goroutine.
+func (db *DistroDB) Provision(ctx context.Context, report func(ProvisioningProgress)) ProvisioningProgress {
+	var distros []*distro.Distro
+
+	// Waking distros up is held back until the agent is resumed.
+	if db.pauser != nil {
+		if err := db.waitResumed(ctx); err != nil {
+			return ProvisioningProgress{}
+		}
+	}
+
+	// Outside of the task window, distros are only woken up by their workers when they have urgent tasks.
+	if db.schedule != nil {
+		if open, next := db.schedule.TaskWindow(db.clock.Now()); !open {
+			log.Infof(ctx, "Database: skipping provisioning outside of the maintenance window, which opens in %s", next.Round(time.Minute))
+			return ProvisioningProgress{}
+		}
+	}
+
+	db.mu.RLock()
+	if !db.stopped() {
+		for _, d := range db.distros {
+			if d.PendingTasks() > 0 {
+				distros = append(distros, d)
+			}
+		}
+	}
+	db.mu.RUnlock()
+
+	progress := ProvisioningProgress{Total: len(distros)}
+	if len(distros) == 0 {
+		return progress
+	}
+
+	log.Infof(ctx, "Database: provisioning %d distros", len(distros))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, d := range distros {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := db.provisionDistro(ctx, d)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				log.Warningf(ctx, "Database: distro %q: could not provision: %v", d.Name(), err)
+				progress.Failed++
+			} else {
+				progress.Provisioned++
+			}
+
+			if report != nil {
+				report(progress)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return progress
+}
+
+// provisionDistro wakes up the distro and waits until it connects to the agent.
+func (db *DistroDB) provisionDistro(ctx context.Context, d *distro.Distro) error {
+	if err := d.LockAwake(); err != nil {
+		return fmt.Errorf("could not wake up: %v", err)
+	}
+	//nolint:errcheck // Nothing we can do about it
+	defer d.ReleaseAwake()
+
+	policy := db.timeouts.OrDefault()
+
+	ctx, cancel := context.WithTimeout(ctx, policy.DistroProvisioning)
+	defer cancel()
+
+	for {
+		active, err := d.IsActive()
+		if err != nil {
+			return err
+		}
+		if active {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for the distro to connect: %v", ctx.Err())
+		case <-db.ctx.Done():
+			return fmt.Errorf("stopped waiting for the distro to connect: %v", db.ctx.Err())
+		case <-db.clock.After(policy.ClientWaitTick):
+		}
+	}
+}
+
+// waitResumed blocks until the agent is not paused, the context is cancelled, or the database is closed.
+func (db *DistroDB) waitResumed(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(db.ctx, cancel)
+	defer stop()
+
+	return db.pauser.WaitResumed(ctx)
+}
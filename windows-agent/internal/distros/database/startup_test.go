@@ -0,0 +1,243 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task/tasktestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+)
+
+func TestProvision(t *testing.T) {
+	t.Parallel()
+
+	if !wsl.MockAvailable() {
+		t.Skip("This test can only run with the mock")
+	}
+
+	testCases := map[string]struct {
+		maxParallelStartups int
+		nilReport           bool
+
+		wantProvisioned int
+		wantFailed      int
+	}{
+		"Success provisioning distros one at a time":       {maxParallelStartups: 1, wantProvisioned: 2, wantFailed: 1},
+		"Success provisioning distros in parallel":         {maxParallelStartups: 4, wantProvisioned: 2, wantFailed: 1},
+		"Success without a report function":                {maxParallelStartups: 4, nilReport: true, wantProvisioned: 2, wantFailed: 1},
+		"Success with no distro needing to be provisioned": {maxParallelStartups: 4},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := wsl.WithMock(context.Background(), wslmock.New())
+
+			policy := timeouts.Policy{DistroProvisioning: 5 * time.Second}
+			db, err := database.New(ctx, t.TempDir(), nil, database.WithMaxParallelStartups(tc.maxParallelStartups), database.WithTimeouts(policy))
+			require.NoError(t, err, "Setup: New should return no error")
+			defer db.Close(ctx)
+
+			// One distro without tasks, which is left alone.
+			addDistro(t, ctx, db)
+
+			// Distros with pending tasks: some connect to the agent, some don't.
+			for i := 0; i < tc.wantProvisioned; i++ {
+				d := addDistro(t, ctx, db)
+				require.NoError(t, d.SetConnection(connection.New("localhost:0")), "Setup: could not set the connection")
+				require.NoError(t, d.SubmitTasks(tasktestutils.NewBlockingTask()), "Setup: could not submit task")
+			}
+
+			for i := 0; i < tc.wantFailed; i++ {
+				d := addDistro(t, ctx, db)
+				require.NoError(t, d.SubmitTasks(tasktestutils.NewBlockingTask()), "Setup: could not submit task")
+			}
+
+			var reports []database.ProvisioningProgress
+			report := func(p database.ProvisioningProgress) { reports = append(reports, p) }
+			if tc.nilReport {
+				report = nil
+			}
+
+			got := db.Provision(ctx, report)
+
+			want := database.ProvisioningProgress{
+				Total:       tc.wantProvisioned + tc.wantFailed,
+				Provisioned: tc.wantProvisioned,
+				Failed:      tc.wantFailed,
+			}
+			require.Equal(t, want, got, "Mismatch in the final progress")
+			require.True(t, got.Finished(), "Provisioning should be finished")
+
+			if tc.nilReport {
+				return
+			}
+
+			require.Len(t, reports, want.Total, "Progress should be reported once per distro")
+			if want.Total > 0 {
+				require.Equal(t, want, reports[len(reports)-1], "The last report should match the final progress")
+			}
+		})
+	}
+}
+
+func TestProvisionAfterClose(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	db, err := database.New(ctx, t.TempDir(), nil)
+	require.NoError(t, err, "Setup: New should return no error")
+	db.Close(ctx)
+
+	got := db.Provision(ctx, nil)
+	require.Equal(t, database.ProvisioningProgress{}, got, "Provision should do nothing after Close")
+}
+
+func TestProvisionWhilePaused(t *testing.T) {
+	t.Parallel()
+
+	if !wsl.MockAvailable() {
+		t.Skip("This test can only run with the mock")
+	}
+
+	testCases := map[string]struct {
+		closeDatabase bool
+
+		want database.ProvisioningProgress
+	}{
+		"Success provisioning once resumed": {want: database.ProvisioningProgress{Total: 1, Provisioned: 1}},
+
+		"Stops waiting when the database is closed": {closeDatabase: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := wsl.WithMock(context.Background(), wslmock.New())
+
+			pauser := pause.New(true)
+
+			db, err := database.New(ctx, t.TempDir(), nil, database.WithPauser(pauser))
+			require.NoError(t, err, "Setup: New should return no error")
+			defer db.Close(ctx)
+
+			d := addDistro(t, ctx, db)
+			require.NoError(t, d.SetConnection(connection.New("localhost:0")), "Setup: could not set the connection")
+			require.NoError(t, d.SubmitTasks(tasktestutils.NewBlockingTask()), "Setup: could not submit task")
+
+			done := make(chan database.ProvisioningProgress)
+			go func() { done <- db.Provision(ctx, nil) }()
+
+			select {
+			case <-done:
+				require.Fail(t, "Provision should wait while the agent is paused")
+			case <-time.After(500 * time.Millisecond):
+			}
+
+			if tc.closeDatabase {
+				db.Close(ctx)
+			} else {
+				pauser.Set(false)
+			}
+
+			select {
+			case got := <-done:
+				require.Equal(t, tc.want, got, "Mismatch in the final progress")
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "Provision should have returned")
+			}
+		})
+	}
+}
+
+func TestProvisionTaskWindow(t *testing.T) {
+	t.Parallel()
+
+	if !wsl.MockAvailable() {
+		t.Skip("This test can only run with the mock")
+	}
+
+	inWindow := time.Date(2000, time.January, 1, 3, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		now time.Time
+
+		want database.ProvisioningProgress
+	}{
+		"Success provisioning inside the task window": {now: inWindow, want: database.ProvisioningProgress{Total: 1, Provisioned: 1}},
+
+		"Skips provisioning outside the task window": {now: inWindow.Add(12 * time.Hour)},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := wsl.WithMock(context.Background(), wslmock.New())
+
+			// The window is only open on the day of the mock clock, so that the real time is never in it.
+			schedule := windowSchedule{opens: inWindow.Add(-time.Hour), closes: inWindow.Add(time.Hour)}
+
+			db, err := database.New(ctx, t.TempDir(), nil, database.WithSchedule(schedule), database.WithClock(clock.NewMock(tc.now)))
+			require.NoError(t, err, "Setup: New should return no error")
+			defer db.Close(ctx)
+
+			d := addDistro(t, ctx, db)
+			require.NoError(t, d.SetConnection(connection.New("localhost:0")), "Setup: could not set the connection")
+			require.NoError(t, d.SubmitTasks(tasktestutils.NewBlockingTask()), "Setup: could not submit task")
+
+			got := db.Provision(ctx, nil)
+			require.Equal(t, tc.want, got, "Mismatch in the final progress")
+		})
+	}
+}
+
+func TestNewWithInvalidParallelStartups(t *testing.T) {
+	t.Parallel()
+
+	_, err := database.New(context.Background(), t.TempDir(), nil, database.WithMaxParallelStartups(0))
+	require.Error(t, err, "New should return an error when the maximum number of parallel startups is not positive")
+}
+
+// addDistro registers a distro and adds it to the database.
+func addDistro(t *testing.T, ctx context.Context, db *database.DistroDB) *distro.Distro {
+	t.Helper()
+
+	name, _ := wsltestutils.RegisterDistro(t, ctx, false)
+	d, err := db.GetDistroAndUpdateProperties(ctx, name, distro.Properties{})
+	require.NoError(t, err, "Setup: could not add distro to the database")
+
+	return d
+}
+
+// windowSchedule is a schedule whose task window is open between two instants.
+type windowSchedule struct {
+	opens  time.Time
+	closes time.Time
+}
+
+func (s windowSchedule) TaskWindow(now time.Time) (bool, time.Duration) {
+	if now.Before(s.opens) {
+		return false, s.opens.Sub(now)
+	}
+	if now.Before(s.closes) {
+		return true, s.closes.Sub(now)
+	}
+	return false, time.Hour
+}
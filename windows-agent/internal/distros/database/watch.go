@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// EventKind is the kind of change made to a distro in the database.
+type EventKind int
+
+const (
+	// DistroAdded is sent when a distro is added to the database. A distro that was registered again
+	// replaces its previous entry: DistroRemoved is sent for the old one first.
+	DistroAdded EventKind = iota
+
+	// DistroUpdated is sent when the properties, labels or status of a distro change.
+	DistroUpdated
+
+	// DistroRemoved is sent when a distro is removed from the database, because it is not registered
+	// anymore or it was registered again.
+	DistroRemoved
+)
+
+// String returns the name of the kind of change.
+func (k EventKind) String() string {
+	switch k {
+	case DistroAdded:
+		return "added"
+	case DistroUpdated:
+		return "updated"
+	case DistroRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// Event is a change made to a distro in the database.
+type Event struct {
+	Kind EventKind
+
+	// Name is the name of the distro.
+	Name string
+}
+
+// watcher keeps the events a call to Watch has not sent yet, so that the database never waits for
+// the slow watchers.
+type watcher struct {
+	mu      sync.Mutex
+	pending []Event
+
+	// wake is signalled when events are pushed. It is buffered so that no push is missed.
+	wake chan struct{}
+}
+
+// push adds the event to the ones to send.
+func (w *watcher) push(e Event) {
+	w.mu.Lock()
+	w.pending = append(w.pending, e)
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes the oldest event to send. It returns false if there is none.
+func (w *watcher) pop() (Event, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) == 0 {
+		return Event{}, false
+	}
+
+	e := w.pending[0]
+	w.pending = w.pending[1:]
+	return e, true
+}
+
+// Watch returns a channel that receives the changes made to the database from now on, in order, so
+// that the distros do not need to be scanned to find out what changed. No event is dropped: the ones
+// that are not received yet are kept until they are.
+//
+// The channel is closed when the context is done or the database is closed. Watching a closed
+// database is an error, as it would never change again.
+func (db *DistroDB) Watch(ctx context.Context) (<-chan Event, error) {
+	if db.stopped() {
+		return nil, errors.New("could not watch the database: it is already closed")
+	}
+
+	w := &watcher{wake: make(chan struct{}, 1)}
+
+	db.watchersMu.Lock()
+	db.watchers[w] = struct{}{}
+	db.watchersMu.Unlock()
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		defer func() {
+			db.watchersMu.Lock()
+			delete(db.watchers, w)
+			db.watchersMu.Unlock()
+		}()
+
+		for {
+			e, ok := w.pop()
+			if !ok {
+				select {
+				case <-ctx.Done():
+					return
+				case <-db.ctx.Done():
+					return
+				case <-w.wake:
+				}
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-db.ctx.Done():
+				return
+			case ch <- e:
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// notify sends the event to every watcher.
+func (db *DistroDB) notify(kind EventKind, name string) {
+	db.watchersMu.Lock()
+	defer db.watchersMu.Unlock()
+
+	for w := range db.watchers {
+		w.push(Event{Kind: kind, Name: name})
+	}
+}
@@ -6,17 +6,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/guid"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
 	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
-	"github.com/google/uuid"
 	"github.com/ubuntu/decorate"
 	wsl "github.com/ubuntu/gowsl"
-	"google.golang.org/grpc"
 )
 
 // Distro is a wrapper around gowsl.Distro that tracks both the distroname and
@@ -27,14 +33,41 @@ type Distro struct {
 	identity
 
 	// Properties contains non-volatile information that is stored in the database
-	properties   Properties
-	propertiesMu sync.RWMutex
+	properties     Properties
+	lastContact    time.Time
+	securityStatus SecurityStatus
+	updateStatus   UpdateStatus
+	resourceUsage  ResourceUsage
+	labels         map[string]string
+	propertiesMu   sync.RWMutex
 
 	// invalidated is an internal value if distro can't be contacted through GRPC
 	invalidated atomic.Bool
 
-	worker       workerInterface
+	// worker processes the tasks of the distro. It is nil while the distro hibernates: see Hibernate.
+	worker   workerInterface
+	workerMu sync.RWMutex
+
+	// newWorker recreates the worker when the distro wakes up from hibernation.
+	newWorker func() (workerInterface, error)
+
+	// awakeSince is when the worker was last created. Together with the last contact, it tells for
+	// how long the distro has been idle.
+	awakeSince time.Time
+
+	// clock tells when the distro was awake and in contact, to decide whether it is idle.
+	clock clock.Clock
+
+	// hibernatedHistory is the task history of the workers that were stopped by hibernation.
+	hibernatedHistory []worker.TaskRecord
+
+	// cleanedUp is true once Cleanup was called. The worker is released then and never recreated.
+	cleanedUp bool
+
 	stateManager *stateManager
+
+	// demand keeps the distro awake on request, without any task to run.
+	demand wakeDemand
 }
 
 // workerInterface is an interface that is implements the task processing worker. It is intended
@@ -42,13 +75,25 @@ type Distro struct {
 type workerInterface interface {
 	IsActive() bool
 	Client() wslserviceapi.WSLClient
-	SetConnection(*grpc.ClientConn)
+	SetConnection(*connection.Connection)
+	ReleaseConnection(*connection.Connection) bool
 	SubmitTasks(...task.Task) error
 	SubmitDeferredTasks(...task.Task) error
 	EnqueueDeferredTasks()
+	ClearTasks() error
+	PendingTasks() int
+	TaskInProgress() bool
+	Circuit() worker.CircuitState
+	TaskHistory() []worker.TaskRecord
+	QueuedTasks() []worker.QueuedTask
+	CancelTask(context.Context, string) error
 	Stop(context.Context)
+	StopWithReason(context.Context, worker.CancelReason)
 }
 
+// ErrCleanedUp is returned by the operations that need the worker of a distro once Cleanup was called.
+var ErrCleanedUp = errors.New("distro was cleaned up")
+
 // NotValidError is a type returned when the (distroName, GUID) combination is not in the registry.
 type NotValidError struct{}
 
@@ -57,10 +102,18 @@ func (*NotValidError) Error() string {
 }
 
 type options struct {
-	guid                  uuid.UUID
+	guid                  guid.GUID
 	provisioning          worker.Provisioning
+	schedule              worker.Schedule
+	pauser                worker.Pauser
+	wslWatcher            worker.WSLWatcher
 	taskProcessingContext context.Context
 	newWorkerFunc         func(context.Context, *Distro, string, worker.Provisioning) (workerInterface, error)
+	lockWatchdog          time.Duration
+	deadlockReporter      deadlockReporter
+	timeouts              timeouts.Policy
+	taskHooks             []worker.Hooks
+	clock                 clock.Clock
 }
 
 // Option is an optional argument for distro.New.
@@ -68,9 +121,9 @@ type Option func(*options)
 
 // WithGUID is an optional parameter for distro.New that enforces GUID
 // validation.
-func WithGUID(guid uuid.UUID) Option {
+func WithGUID(id guid.GUID) Option {
 	return func(o *options) {
-		o.guid = guid
+		o.guid = id
 	}
 }
 
@@ -82,6 +135,62 @@ func WithProvisioning(c worker.Provisioning) Option {
 	}
 }
 
+// WithSchedule allows for providing a worker.Schedule. If that is done, tasks that
+// are not urgent will only run when the schedule allows it.
+func WithSchedule(s worker.Schedule) Option {
+	return func(o *options) {
+		o.schedule = s
+	}
+}
+
+// WithPauser allows for providing a worker.Pauser. If that is done, no task will run
+// while the agent is paused.
+func WithPauser(p worker.Pauser) Option {
+	return func(o *options) {
+		o.pauser = p
+	}
+}
+
+// WithWSLWatcher allows for providing a worker.WSLWatcher. If that is done, the tasks that cannot
+// reach the distro because WSL is unavailable are retried once it is back, instead of the distro
+// being invalidated.
+func WithWSLWatcher(w worker.WSLWatcher) Option {
+	return func(o *options) {
+		o.wslWatcher = w
+	}
+}
+
+// WithLockWatchdog enables reporting operations that wait for the internal lock of the distro for
+// longer than the threshold, which likely means that there is a deadlock. A zero threshold disables it.
+func WithLockWatchdog(threshold time.Duration) Option {
+	return func(o *options) {
+		o.lockWatchdog = threshold
+	}
+}
+
+// WithTimeouts allows for overriding how long the worker of the distro waits for it to connect, and
+// how long the RPCs made to it may take.
+func WithTimeouts(policy timeouts.Policy) Option {
+	return func(o *options) {
+		o.timeouts = policy
+	}
+}
+
+// WithClock allows for overriding the clock used to tell for how long the distro has been idle.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithTaskHooks allows for providing worker.Hooks. If that is done, they are told about the tasks of
+// the distro as they are queued, run and retried.
+func WithTaskHooks(hooks ...worker.Hooks) Option {
+	return func(o *options) {
+		o.taskHooks = append(o.taskHooks, hooks...)
+	}
+}
+
 // New creates a new Distro object after searching for a distro with the given name.
 //
 //   - If identity.Name is not registered, a DistroDoesNotExist error is returned.
@@ -89,18 +198,18 @@ func WithProvisioning(c worker.Provisioning) Option {
 //   - Otheriwse, identity.GUID will be validated against the registry. In case of mismatch,
 //     a DistroDoesNotExist error is returned
 //
-//   - To avoid the latter check, you can pass a default-constructed identity.GUID. In that
-//     case, the distro will be created with its currently registered GUID.
-func New(ctx context.Context, name string, props Properties, storageDir string, startupMu *sync.Mutex, args ...Option) (distro *Distro, err error) {
+//   - To avoid the latter check, you can pass the zero identity.GUID. In that case, the
+//     distro will be created with its currently registered GUID.
+func New(ctx context.Context, name string, props Properties, storageDir string, startupMu sync.Locker, args ...Option) (distro *Distro, err error) {
 	decorate.OnError(&err, "could not initialize distro %q", name)
 
-	var nilGUID uuid.UUID
 	opts := options{
-		guid:                  nilGUID,
 		taskProcessingContext: context.Background(),
-		newWorkerFunc: func(ctx context.Context, d *Distro, dir string, provisioning worker.Provisioning) (workerInterface, error) {
-			return worker.New(ctx, d, dir, worker.WithProvisioning(provisioning))
-		},
+		deadlockReporter:      reportDeadlock,
+		clock:                 clock.Real(),
+	}
+	opts.newWorkerFunc = func(ctx context.Context, d *Distro, dir string, provisioning worker.Provisioning) (workerInterface, error) {
+		return worker.New(ctx, d, dir, worker.WithProvisioning(provisioning), worker.WithSchedule(opts.schedule), worker.WithPauser(opts.pauser), worker.WithWSLWatcher(opts.wslWatcher), worker.WithTimeouts(opts.timeouts), worker.WithHooks(opts.taskHooks...))
 	}
 
 	for _, f := range args {
@@ -114,18 +223,18 @@ func New(ctx context.Context, name string, props Properties, storageDir string,
 	}
 
 	// GUID is not initialized.
-	if id.GUID == nilGUID {
+	if id.GUID.IsZero() {
 		d := wsl.NewDistro(ctx, name)
-		guid, err := d.GUID()
+		registered, err := d.GUID()
 		if err == nil {
-			id.GUID = guid
+			id.GUID = guid.FromUUID(registered)
 		} else {
 			return nil, fmt.Errorf("no distro with this name exists: %w", &NotValidError{})
 		}
 	} else {
 		// Check the name/GUID pair is valid.
 		if !id.isValid() {
-			return nil, fmt.Errorf("no distro with this name and GUID %q in registry: %w", id.GUID.String(), &NotValidError{})
+			return nil, fmt.Errorf("no distro with this name and GUID %q in registry: %w", id.GUID, &NotValidError{})
 		}
 	}
 
@@ -139,6 +248,11 @@ func New(ctx context.Context, name string, props Properties, storageDir string,
 		stateManager: &stateManager{
 			distroIdentity: id,
 			startupMu:      startupMu,
+			mu: watchdogMutex{
+				distroName: name,
+				threshold:  opts.lockWatchdog,
+				report:     opts.deadlockReporter,
+			},
 		},
 	}
 
@@ -146,6 +260,13 @@ func New(ctx context.Context, name string, props Properties, storageDir string,
 	if err != nil {
 		return nil, err
 	}
+	distro.clock = opts.clock
+	distro.awakeSince = distro.clock.Now()
+
+	distro.newWorker = func() (workerInterface, error) {
+		// The provisioning tasks were submitted when the distro was first created: they must not be submitted again.
+		return opts.newWorkerFunc(opts.taskProcessingContext, distro, storageDir, nil)
+	}
 
 	return distro, nil
 }
@@ -160,8 +281,8 @@ func (d *Distro) Name() string {
 }
 
 // GUID is a getter for the distro's GUID.
-func (d *Distro) GUID() string {
-	return d.identity.GUID.String()
+func (d *Distro) GUID() guid.GUID {
+	return d.identity.GUID
 }
 
 // Properties is a getter for the distro's Properties.
@@ -178,20 +299,88 @@ func (d *Distro) SetProperties(p Properties) bool {
 	d.propertiesMu.Lock()
 	defer d.propertiesMu.Unlock()
 
-	if d.properties == p {
+	if reflect.DeepEqual(d.properties, p) {
 		return false
 	}
 	d.properties = p
 	return true
 }
 
+// LastContact returns the last time the distro reported its properties. The zero
+// time is returned if it never did.
+func (d *Distro) LastContact() time.Time {
+	d.propertiesMu.RLock()
+	defer d.propertiesMu.RUnlock()
+
+	return d.lastContact
+}
+
+// SetLastContact sets the last time the distro reported its properties.
+func (d *Distro) SetLastContact(t time.Time) {
+	d.propertiesMu.Lock()
+	defer d.propertiesMu.Unlock()
+
+	d.lastContact = t
+}
+
+// SecurityStatus returns the security updates pending in the distro the last time it was checked.
+func (d *Distro) SecurityStatus() SecurityStatus {
+	d.propertiesMu.RLock()
+	defer d.propertiesMu.RUnlock()
+
+	return d.securityStatus
+}
+
+// SetSecurityStatus sets the security updates pending in the distro.
+func (d *Distro) SetSecurityStatus(s SecurityStatus) {
+	d.propertiesMu.Lock()
+	defer d.propertiesMu.Unlock()
+
+	d.securityStatus = s
+}
+
+// UpdateStatus returns whether the distro needed to be rebooted and its pending updates the last time it was checked.
+func (d *Distro) UpdateStatus() UpdateStatus {
+	d.propertiesMu.RLock()
+	defer d.propertiesMu.RUnlock()
+
+	return d.updateStatus
+}
+
+// SetUpdateStatus sets whether the distro needs to be rebooted and its pending updates.
+func (d *Distro) SetUpdateStatus(s UpdateStatus) {
+	d.propertiesMu.Lock()
+	defer d.propertiesMu.Unlock()
+
+	d.updateStatus = s
+}
+
+// ResourceUsage returns the resources used by the distro the last time it was checked.
+func (d *Distro) ResourceUsage() ResourceUsage {
+	d.propertiesMu.RLock()
+	defer d.propertiesMu.RUnlock()
+
+	return d.resourceUsage
+}
+
+// SetResourceUsage sets the resources used by the distro.
+func (d *Distro) SetResourceUsage(u ResourceUsage) {
+	d.propertiesMu.Lock()
+	defer d.propertiesMu.Unlock()
+
+	d.resourceUsage = u
+}
+
 // IsActive returns true when the distro is running, and there exists an active
 // connection to its GRPC service.
 func (d *Distro) IsActive() (bool, error) {
 	if !d.IsValid() {
 		return false, &NotValidError{}
 	}
-	return d.worker.IsActive(), nil
+
+	var active bool
+	d.peekWorker(func(w workerInterface) { active = w.IsActive() })
+	return active, nil
 }
 
 // Client returns the client to the WSL task service.
@@ -200,22 +389,38 @@ func (d *Distro) Client() (wslserviceapi.WSLClient, error) {
 	if !d.IsValid() {
 		return nil, &NotValidError{}
 	}
-	return d.worker.Client(), nil
+
+	var client wslserviceapi.WSLClient
+	d.peekWorker(func(w workerInterface) { client = w.Client() })
+	return client, nil
 }
 
-// SetConnection removes the connection associated with the distro.
-func (d *Distro) SetConnection(conn *grpc.ClientConn) error {
+// SetConnection replaces the connection associated with the distro. A nil connection removes it.
+// Setting a connection wakes the distro up from hibernation.
+func (d *Distro) SetConnection(conn *connection.Connection) error {
 	// Allowing IsValid check to be bypassed when resetting the connection
 	if conn == nil {
-		d.worker.SetConnection(nil)
+		d.peekWorker(func(w workerInterface) { w.SetConnection(nil) })
 		return nil
 	}
 
 	if !d.IsValid() {
 		return &NotValidError{}
 	}
-	d.worker.SetConnection(conn)
-	return nil
+
+	return d.withWorker(func(w workerInterface) error {
+		w.SetConnection(conn)
+		return nil
+	})
+}
+
+// ReleaseConnection removes the connection associated with the distro, unless it was replaced by
+// another one already. This way, a stream that ends after its distro reconnected does not remove
+// the newer connection. It returns true if the connection was removed.
+func (d *Distro) ReleaseConnection(conn *connection.Connection) bool {
+	var released bool
+	d.peekWorker(func(w workerInterface) { released = w.ReleaseConnection(conn) })
+	return released
 }
 
 // SubmitTasks enqueues one or more task on our current worker list.
@@ -224,7 +429,7 @@ func (d *Distro) SubmitTasks(tasks ...task.Task) (err error) {
 	if !d.IsValid() {
 		return &NotValidError{}
 	}
-	return d.worker.SubmitTasks(tasks...)
+	return d.withWorker(func(w workerInterface) error { return w.SubmitTasks(tasks...) })
 }
 
 // SubmitDeferredTasks enqueues one or more task on our current worker list.
@@ -233,22 +438,238 @@ func (d *Distro) SubmitDeferredTasks(tasks ...task.Task) (err error) {
 	if !d.IsValid() {
 		return &NotValidError{}
 	}
-	return d.worker.SubmitDeferredTasks(tasks...)
+	return d.withWorker(func(w workerInterface) error { return w.SubmitDeferredTasks(tasks...) })
 }
 
 // EnqueueDeferredTasks takes all deferred tasks and promotes them
 // to regular tasks.
 func (d *Distro) EnqueueDeferredTasks() {
-	d.worker.EnqueueDeferredTasks()
+	err := d.withWorker(func(w workerInterface) error {
+		w.EnqueueDeferredTasks()
+		return nil
+	})
+	if err != nil {
+		log.Warningf(d.ctx, "Distro %q: could not enqueue deferred tasks: %v", d.Name(), err)
+	}
+}
+
+// ClearTasks removes every queued task, deferred or not. See Worker.ClearTasks for details.
+func (d *Distro) ClearTasks() error {
+	return d.withWorker(func(w workerInterface) error { return w.ClearTasks() })
+}
+
+// QueuedTasks lists the tasks waiting to be executed, deferred ones included. The queues of a
+// hibernating distro live on disk, so it is woken up to read them.
+func (d *Distro) QueuedTasks() (queued []worker.QueuedTask, err error) {
+	err = d.withWorker(func(w workerInterface) error {
+		queued = w.QueuedTasks()
+		return nil
+	})
+	return queued, err
+}
+
+// CancelTask removes the queued task with the given ID, along with the tasks that depend on it.
+func (d *Distro) CancelTask(ctx context.Context, id string) error {
+	return d.withWorker(func(w workerInterface) error { return w.CancelTask(ctx, id) })
+}
+
+// PendingTasks returns the number of non-deferred tasks waiting to be executed, including the one
+// currently being processed. Invalid distros never have pending tasks, nor do hibernating ones.
+func (d *Distro) PendingTasks() int {
+	if !d.IsValid() {
+		return 0
+	}
+
+	var n int
+	d.peekWorker(func(w workerInterface) { n = w.PendingTasks() })
+	return n
+}
+
+// TaskInProgress returns true while a task is being processed.
+func (d *Distro) TaskInProgress() bool {
+	var busy bool
+	d.peekWorker(func(w workerInterface) { busy = w.TaskInProgress() })
+	return busy
+}
+
+// Circuit returns whether the tasks of the distro are held back because too many of them failed in a
+// row, in which case the distro needs the attention of the user.
+func (d *Distro) Circuit() worker.CircuitState {
+	var c worker.CircuitState
+	d.peekWorker(func(w workerInterface) { c = w.Circuit() })
+	return c
+}
+
+// TaskHistory returns the outcome of the last tasks that ran in the distro, from oldest to newest.
+func (d *Distro) TaskHistory() []worker.TaskRecord {
+	d.workerMu.RLock()
+	defer d.workerMu.RUnlock()
+
+	return d.taskHistoryUnsafe()
+}
+
+// taskHistoryUnsafe is TaskHistory without taking the worker lock.
+func (d *Distro) taskHistoryUnsafe() []worker.TaskRecord {
+	history := slices.Clone(d.hibernatedHistory)
+	if d.worker != nil {
+		history = append(history, d.worker.TaskHistory()...)
+	}
+
+	if over := len(history) - worker.HistorySize; over > 0 {
+		history = history[over:]
+	}
+	return history
+}
+
+// Hibernate stops the worker of the distro to release its resources, if the distro has been idle for
+// at least idleFor: not connected, with no task pending nor in progress, and with no task failure to
+// keep track of. Deferred tasks stay on disk. The worker is recreated as soon as it is needed again.
+// It returns true if the distro went into hibernation.
+func (d *Distro) Hibernate(ctx context.Context, idleFor time.Duration) bool {
+	d.workerMu.Lock()
+	defer d.workerMu.Unlock()
+
+	w := d.worker
+	if w == nil || d.cleanedUp {
+		return false
+	}
+
+	if w.IsActive() || w.PendingTasks() > 0 || w.TaskInProgress() || w.Circuit().Failures > 0 {
+		return false
+	}
+
+	idleSince := d.awakeSince
+	if c := d.LastContact(); c.After(idleSince) {
+		idleSince = c
+	}
+
+	if d.clock.Now().Sub(idleSince) < idleFor {
+		return false
+	}
+
+	d.hibernatedHistory = d.taskHistoryUnsafe()
+	w.Stop(ctx)
+	d.worker = nil
+
+	log.Infof(ctx, "Distro %q: hibernating, as it has been idle since %s", d.Name(), idleSince.Format(time.RFC3339))
+	return true
+}
+
+// Hibernating returns true while the worker of the distro is stopped by Hibernate.
+func (d *Distro) Hibernating() bool {
+	d.workerMu.RLock()
+	defer d.workerMu.RUnlock()
+
+	return d.worker == nil && !d.cleanedUp
+}
+
+// peekWorker calls f with the worker, unless the distro is hibernating: queries do not wake it up.
+func (d *Distro) peekWorker(f func(workerInterface)) {
+	d.workerMu.RLock()
+	defer d.workerMu.RUnlock()
+
+	if d.worker == nil {
+		return
+	}
+	f(d.worker)
+}
+
+// withWorker calls f with the worker, waking the distro up from hibernation if needed. The distro
+// cannot go into hibernation while f runs.
+func (d *Distro) withWorker(f func(workerInterface) error) error {
+	for {
+		if err := d.wake(); err != nil {
+			return err
+		}
+
+		d.workerMu.RLock()
+		if d.worker != nil {
+			break
+		}
+		// The distro went back into hibernation in the meantime.
+		d.workerMu.RUnlock()
+	}
+	defer d.workerMu.RUnlock()
+
+	return f(d.worker)
 }
 
-// Cleanup releases all resources associated with the distro.
+// wake recreates the worker if the distro is hibernating.
+func (d *Distro) wake() error {
+	d.workerMu.Lock()
+	defer d.workerMu.Unlock()
+
+	if d.worker != nil {
+		return nil
+	}
+
+	if d.cleanedUp {
+		return ErrCleanedUp
+	}
+
+	w, err := d.newWorker()
+	if err != nil {
+		return fmt.Errorf("could not wake up from hibernation: %v", err)
+	}
+
+	log.Infof(d.ctx, "Distro %q: woke up from hibernation", d.Name())
+
+	d.worker = w
+	d.awakeSince = d.clock.Now()
+	return nil
+}
+
+// Cleanup releases all resources associated with the distro. A task that is interrupted is
+// recorded as cancelled by the distro being unregistered if it is no longer valid, and by the
+// agent shutting down otherwise.
+//
+// Cleanup is idempotent and safe to call concurrently with any other method. It waits for the
+// calls that are using the worker to return. Afterwards, the operations that need the worker
+// return ErrCleanedUp, queries return the values of a distro with no task nor connection, and the
+// distro cannot be kept awake.
 func (d *Distro) Cleanup(ctx context.Context) {
 	if d == nil {
 		return
 	}
-	d.worker.Stop(ctx)
-	d.stateManager.reset()
+
+	reason := worker.CancelAgentShutdown
+	if d.invalidated.Load() {
+		reason = worker.CancelDistroUnregistered
+	}
+
+	d.workerMu.Lock()
+	if d.cleanedUp {
+		d.workerMu.Unlock()
+		return
+	}
+	d.cleanedUp = true
+	if d.worker != nil {
+		// The history outlives the worker, as with hibernation.
+		d.hibernatedHistory = d.taskHistoryUnsafe()
+		d.worker.StopWithReason(ctx, reason)
+		d.worker = nil
+	}
+	d.workerMu.Unlock()
+
+	// The distro cannot be kept awake anymore, so the hold taken on demand is dropped along with the others.
+	d.demand.mu.Lock()
+	if d.demand.timer != nil {
+		d.demand.timer.Stop()
+		d.demand.timer = nil
+	}
+	d.demand.held = false
+	d.demand.until = time.Time{}
+	d.demand.mu.Unlock()
+
+	d.stateManager.close()
+}
+
+// CleanedUp returns true once Cleanup was called.
+func (d *Distro) CleanedUp() bool {
+	d.workerMu.RLock()
+	defer d.workerMu.RUnlock()
+
+	return d.cleanedUp
 }
 
 // Invalidate sets the invalid flag to true. The state of this flag can be read with IsValid.
@@ -310,5 +731,5 @@ func (d *Distro) Uninstall(ctx context.Context) error {
 		return err
 	}
 
-	return distro.Uninstall(ctx)
+	return wslcall.Command(ctx, fmt.Sprintf("uninstalling distro %q", distro.Name()), distro.Uninstall)
 }
@@ -9,25 +9,62 @@ import (
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro/touchdistro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
 	wsl "github.com/ubuntu/gowsl"
 )
 
+// awakeState is the state of the awake lock of a distro.
+type awakeState int
+
+const (
+	// stateAsleep -> nobody is keeping the distro awake.
+	stateAsleep awakeState = iota
+
+	// stateWaking -> the distro is being woken up. Other callers wait for the outcome.
+	stateWaking
+
+	// stateAwake -> the distro is being kept awake.
+	stateAwake
+
+	// stateClosed -> the distro was cleaned up. It cannot be kept awake anymore.
+	stateClosed
+)
+
 // stateManager manages the state (running/stopped) of the distro with an internal counter.
 // The distro is guaranteed to be running so long as the counter is above 0. This counter can
 // be increased or decreased on demand, and is thread-safe.
+//
+// The mutex only protects the fields below and is never held during calls to WSL, so that
+// cleaning up a distro is never blocked by another goroutine waking it up.
 type stateManager struct {
 	distroIdentity identity
 
+	awake    awakeState
 	refcount uint32
-	cancel   func()
 
-	// mu is a mutex for the refcount and the cancel func. We cannot use an atomic because increasing
-	// or decreasing the count entails more operations than simply adding one to this number.
-	mu sync.Mutex
+	// cancel stops waking up the distro, or keeping it awake.
+	cancel func()
 
-	// startupMu protects against multiple distros starting at the same time. This could cause WSL
+	// wakeDone is closed when the wake-up in progress, if any, finishes.
+	wakeDone chan struct{}
+
+	// wakeUps counts the times the distro was woken up, to detect that it was restarted while the
+	// mutex was not held.
+	wakeUps uint64
+
+	// mu is a mutex for the fields above. We cannot use atomics because increasing or decreasing
+	// the count entails more operations than simply adding one to this number.
+	mu watchdogMutex
+
+	// startupMu protects against too many distros starting at the same time. This could cause WSL
 	// (and the whole machine) to freeze up.
-	startupMu *sync.Mutex
+	startupMu sync.Locker
+}
+
+// contextLocker is a sync.Locker whose Lock can be interrupted.
+type contextLocker interface {
+	sync.Locker
+	LockContext(ctx context.Context) error
 }
 
 // state returns the state of the WSL distro, as implemeted by GoWSL.
@@ -37,51 +74,118 @@ func (m *stateManager) state() (s wsl.State, err error) {
 		return s, err
 	}
 
-	return wslDistro.State()
+	return wslcall.Query(m.distroIdentity.ctx, fmt.Sprintf("querying the state of distro %q", wslDistro.Name()), func(context.Context) (wsl.State, error) {
+		return wslDistro.State()
+	})
 }
 
 // lock increases the internal counter. If it was zero, the distro is awaken and locked awake.
 // The context should be used to pass the GoWSL backend, and cancelling it does not override
-// the need to call unlock.
+// the need to call unlock. Cancelling it while waiting for another caller to wake the distro
+// up returns its error.
 //
 //nolint:nolintlint  // Golangci-lint gives false positives only without --build-tags=gowslmock
 func (m *stateManager) lock(ctx context.Context) error {
-	m.mu.Lock()
+	m.mu.Lock("lock")
 	defer m.mu.Unlock()
 
-	if m.refcount > 0 {
-		s, err := m.state()
-		if err != nil {
-			return err
-		}
+	for {
+		switch m.awake {
+		case stateClosed:
+			return ErrCleanedUp
 
-		if s == wsl.Running {
+		case stateWaking:
+			done := m.wakeDone
+			m.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				m.mu.Lock("lock")
+				return ctx.Err()
+			case <-done:
+			}
+			m.mu.Lock("lock")
+
+		case stateAwake:
+			wakeUps := m.wakeUps
+			m.mu.Unlock()
+			s, err := m.state()
+			m.mu.Lock("lock")
+
+			if m.awake != stateAwake || m.wakeUps != wakeUps {
+				// The state changed while querying WSL: start over.
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if s == wsl.Running {
+				m.refcount++
+				return nil
+			}
+
+			// The distro stopped anyway: it must be woken up again.
+			m.cancel()
+			m.cancel = nil
+			m.awake = stateAsleep
+
+		case stateAsleep:
+			if err := m.wakeUp(ctx); err != nil {
+				return err
+			}
 			m.refcount++
 			return nil
 		}
-
-		// Restart distro: need to re-call keepAwake
-		m.cancel()
 	}
+}
 
-	//nolint:staticcheck // False positive. 'cancel' is used in both paths.
+// wakeUp wakes the distro up and keeps it awake. It must be called with the mutex held, which is
+// released while waking up.
+func (m *stateManager) wakeUp(ctx context.Context) error {
+	//nolint:govet // The cancel function is stored and called when the distro is released.
 	ctx, cancel := context.WithCancel(ctx)
-	if err := m.keepAwake(ctx); err != nil {
+	done := make(chan struct{})
+
+	m.awake = stateWaking
+	m.cancel = cancel
+	m.wakeDone = done
+	m.wakeUps++
+
+	m.mu.Unlock()
+	err := m.keepAwake(ctx)
+	m.mu.Lock("wakeUp")
+
+	close(done)
+
+	if m.wakeDone != done {
+		// Released or closed while waking up: the wake-up was cancelled.
 		cancel()
-		return err
+		if m.awake == stateClosed {
+			return ErrCleanedUp
+		}
+		return errors.New("wake-up was cancelled")
 	}
+	m.wakeDone = nil
 
-	m.refcount++
-	m.cancel = cancel
+	if err != nil {
+		cancel()
+		m.awake = stateAsleep
+		m.cancel = nil
+		return err
+	}
 
+	m.awake = stateAwake
 	return nil
 }
 
 // release decreases the internal counter. If it becomes zero, the distro awake lock is released.
 func (m *stateManager) release() error {
-	m.mu.Lock()
+	m.mu.Lock("release")
 	defer m.mu.Unlock()
 
+	if m.awake == stateClosed {
+		return ErrCleanedUp
+	}
+
 	if m.refcount == 0 {
 		return errors.New("excess calls to release")
 	}
@@ -93,23 +197,26 @@ func (m *stateManager) release() error {
 
 	m.cancel()
 	m.cancel = nil
+	m.wakeDone = nil
+	m.awake = stateAsleep
 
 	return nil
 }
 
-// reset returns the count back to zero. Equivalent to unlocking all standing locks.
-func (m *stateManager) reset() {
-	m.mu.Lock()
+// close returns the count back to zero, which is equivalent to unlocking all standing locks, and
+// cancels any wake-up in progress. The distro cannot be kept awake after it is closed.
+func (m *stateManager) close() {
+	m.mu.Lock("close")
 	defer m.mu.Unlock()
 
-	if m.refcount == 0 {
-		m.cancel = nil
-		return
+	if m.cancel != nil {
+		m.cancel()
 	}
 
+	m.awake = stateClosed
 	m.refcount = 0
-	m.cancel()
 	m.cancel = nil
+	m.wakeDone = nil
 }
 
 // keepAwake ensures the distro is started by poking the distro every once in a while.
@@ -118,11 +225,17 @@ func (m *stateManager) reset() {
 //
 // The distro will be running by the time keepAwake returns.
 func (m *stateManager) keepAwake(ctx context.Context) (err error) {
-	m.startupMu.Lock()
+	if l, ok := m.startupMu.(contextLocker); ok {
+		if err := l.LockContext(ctx); err != nil {
+			return fmt.Errorf("could not wake distro up: %v", err)
+		}
+	} else {
+		m.startupMu.Lock()
+	}
 	defer m.startupMu.Unlock()
 
 	// Wake up distro
-	if err := touchdistro.Touch(ctx, m.distroIdentity.Name); err != nil {
+	if err := m.touch(ctx); err != nil {
 		return fmt.Errorf("could not wake distro up: %v", err)
 	}
 
@@ -133,7 +246,7 @@ func (m *stateManager) keepAwake(ctx context.Context) (err error) {
 			case <-ctx.Done():
 				return
 			case <-time.After(5 * time.Second):
-				if err := touchdistro.Touch(ctx, m.distroIdentity.Name); err != nil {
+				if err := m.touch(ctx); err != nil {
 					log.Errorf(ctx, "Distro %q: %v", m.distroIdentity.Name, err)
 				}
 			}
@@ -142,3 +255,10 @@ func (m *stateManager) keepAwake(ctx context.Context) (err error) {
 
 	return nil
 }
+
+// touch sends a short-lived command to the distro, which starts it if needed.
+func (m *stateManager) touch(ctx context.Context) error {
+	return wslcall.Command(ctx, fmt.Sprintf("waking up distro %q", m.distroIdentity.Name), func(ctx context.Context) error {
+		return touchdistro.Touch(ctx, m.distroIdentity.Name)
+	})
+}
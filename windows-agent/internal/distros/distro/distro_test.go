@@ -5,20 +5,21 @@ import (
 	"errors"
 	"os"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/guid"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
 	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
-	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	wsl "github.com/ubuntu/gowsl"
 	wslmock "github.com/ubuntu/gowsl/mock"
-	"google.golang.org/grpc"
 )
 
 func TestMain(m *testing.M) {
@@ -37,7 +38,7 @@ var globalStartupMu sync.Mutex
 //
 // When a mock WSL is used, this concern does not exist so we provide a new
 // mutex for every test so they can run in parallel without interference.
-func startupMutex() *sync.Mutex {
+func startupMutex() sync.Locker {
 	if wsl.MockAvailable() {
 		// No real distros: use a different mutex every test
 		return &sync.Mutex{}
@@ -95,9 +96,9 @@ func TestNew(t *testing.T) {
 
 			var args []distro.Option
 			if tc.withGUID != "" {
-				GUID, err := uuid.Parse(tc.withGUID)
-				require.NoError(t, err, "Setup: could not parse guid %s: %v", GUID, err)
-				args = append(args, distro.WithGUID(GUID))
+				id, err := guid.Parse(tc.withGUID)
+				require.NoError(t, err, "Setup: could not parse guid %s", tc.withGUID)
+				args = append(args, distro.WithGUID(id))
 			}
 
 			if tc.withProvisioning {
@@ -122,7 +123,7 @@ func TestNew(t *testing.T) {
 
 			require.NoError(t, err, "New() should have returned no error")
 			require.Equal(t, tc.distro, d.Name(), "distro.Name should match the one it was constructed with")
-			require.Equal(t, registeredGUID, d.GUID(), "distro.GUID should match the one it was constructed with")
+			require.Equal(t, registeredGUID, d.GUID().String(), "distro.GUID should match the one it was constructed with")
 			require.Equal(t, props, d.Properties(), "distro.Properties should match the one it was constructed with because they were never directly modified")
 		})
 	}
@@ -135,19 +136,19 @@ func TestString(t *testing.T) {
 		ctx = wsl.WithMock(ctx, wslmock.New())
 	}
 
-	name, guid := wsltestutils.RegisterDistro(t, ctx, false)
+	name, registered := wsltestutils.RegisterDistro(t, ctx, false)
 
-	GUID, err := uuid.Parse(guid)
-	require.NoError(t, err, "Setup: could not parse guid %s: %v", GUID, err)
+	id, err := guid.Parse(registered)
+	require.NoError(t, err, "Setup: could not parse guid %s", registered)
 
-	d, err := distro.New(ctx, name, distro.Properties{}, t.TempDir(), startupMutex(), distro.WithGUID(GUID))
+	d, err := distro.New(ctx, name, distro.Properties{}, t.TempDir(), startupMutex(), distro.WithGUID(id))
 	defer d.Cleanup(context.Background())
 
 	require.NoError(t, err, "Setup: unexpected error in distro.New")
 
 	s := d.String()
 	require.Contains(t, s, name, "String() should contain the name of the distro")
-	require.Contains(t, s, guid, "String() should contain the GUID of the distro")
+	require.Contains(t, s, registered, "String() should contain the GUID of the distro")
 }
 
 func TestIsValid(t *testing.T) {
@@ -187,9 +188,9 @@ func TestIsValid(t *testing.T) {
 			// Change values and assert on IsValid
 			d.GetIdentity().Name = tc.distro
 
-			GUID, err := uuid.Parse(tc.guid)
-			require.NoError(t, err, "Setup: could not parse guid %s: %v", GUID, err)
-			d.GetIdentity().GUID = GUID
+			id, err := guid.Parse(tc.guid)
+			require.NoError(t, err, "Setup: could not parse guid %s", tc.guid)
+			d.GetIdentity().GUID = id
 
 			got := d.IsValid()
 			require.Equal(t, tc.want, got, "IsValid should return expected value")
@@ -248,6 +249,193 @@ func TestSetProperties(t *testing.T) {
 	}
 }
 
+func TestSetLabels(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		initial map[string]string
+		labels  map[string]string
+
+		want        map[string]string
+		wantChanged bool
+		wantErr     bool
+	}{
+		"Success setting labels":                 {labels: map[string]string{"env": "dev"}, want: map[string]string{"env": "dev"}, wantChanged: true},
+		"Success replacing labels":               {initial: map[string]string{"env": "dev", "team": "web"}, labels: map[string]string{"env": "prod"}, want: map[string]string{"env": "prod"}, wantChanged: true},
+		"Success dropping labels with no value":  {initial: map[string]string{"env": "dev"}, labels: map[string]string{"env": ""}, wantChanged: true},
+		"Success setting the same labels":        {initial: map[string]string{"env": "dev"}, labels: map[string]string{"env": "dev"}, want: map[string]string{"env": "dev"}},
+		"Success setting no labels on no labels": {},
+
+		"Error with an empty key":                  {labels: map[string]string{"": "dev"}, wantErr: true},
+		"Error with a key with invalid characters": {initial: map[string]string{"env": "dev"}, labels: map[string]string{"env=x": "dev"}, want: map[string]string{"env": "dev"}, wantErr: true},
+		"Error with a value with a comma":          {labels: map[string]string{"env": "dev,prod"}, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			dname, _ := wsltestutils.RegisterDistro(t, ctx, false)
+			d, err := distro.New(ctx, dname, distro.Properties{}, t.TempDir(), startupMutex())
+			require.NoError(t, err, "Setup: distro New should return no errors")
+			defer d.Cleanup(context.Background())
+
+			_, err = d.SetLabels(tc.initial)
+			require.NoError(t, err, "Setup: SetLabels should return no errors")
+
+			changed, err := d.SetLabels(tc.labels)
+			if tc.wantErr {
+				require.Error(t, err, "SetLabels should return an error")
+			} else {
+				require.NoError(t, err, "SetLabels should return no errors")
+				require.Equal(t, tc.wantChanged, changed, "Unexpected return value from SetLabels")
+			}
+
+			got := d.Labels()
+			if len(tc.want) == 0 {
+				require.Empty(t, got, "Distro should have no labels")
+				return
+			}
+			require.Equal(t, tc.want, got, "Unexpected labels")
+		})
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		selector string
+		labels   map[string]string
+
+		want      distro.Selector
+		wantMatch bool
+		wantErr   bool
+	}{
+		"Success with a single label":          {selector: "env=dev", labels: map[string]string{"env": "dev", "team": "web"}, want: distro.Selector{"env": "dev"}, wantMatch: true},
+		"Success with several labels":          {selector: "env=dev, team=web", labels: map[string]string{"env": "dev", "team": "web"}, want: distro.Selector{"env": "dev", "team": "web"}, wantMatch: true},
+		"Success with an empty selector":       {selector: "", labels: map[string]string{"env": "dev"}, want: distro.Selector{}, wantMatch: true},
+		"Success not matching another value":   {selector: "env=dev", labels: map[string]string{"env": "prod"}, want: distro.Selector{"env": "dev"}},
+		"Success not matching a missing label": {selector: "env=dev,team=web", labels: map[string]string{"env": "dev"}, want: distro.Selector{"env": "dev", "team": "web"}},
+		"Success not matching no labels":       {selector: "env=dev", want: distro.Selector{"env": "dev"}},
+
+		"Error with a pair without value separator": {selector: "env", wantErr: true},
+		"Error with a label selected twice":         {selector: "env=dev,env=prod", wantErr: true},
+		"Error with an invalid key":                 {selector: "=dev", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := distro.ParseSelector(tc.selector)
+			if tc.wantErr {
+				require.Error(t, err, "ParseSelector should return an error")
+				return
+			}
+			require.NoError(t, err, "ParseSelector should return no errors")
+			require.Equal(t, tc.want, got, "Unexpected selector")
+			require.Equal(t, tc.wantMatch, got.Matches(tc.labels), "Unexpected match of the labels")
+		})
+	}
+}
+
+func TestWakeFor(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		duration   time.Duration
+		renew      bool
+		noWake     bool
+		invalidate bool
+
+		wantExpired bool
+		wantErr     bool
+	}{
+		"Success keeping the distro awake until stopped":  {},
+		"Success keeping the distro awake for a duration": {duration: time.Second, wantExpired: true},
+		"Success renewing the duration":                   {duration: time.Second, renew: true},
+		"Success stopping a distro that was not woken up": {noWake: true},
+
+		"Error when the distro is not valid": {invalidate: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, true)
+
+			d, err := distro.New(ctx, distroName, distro.Properties{}, t.TempDir(), startupMutex())
+			require.NoError(t, err, "Setup: distro New should return no error")
+			defer d.Cleanup(context.Background())
+
+			if tc.invalidate {
+				d.Invalidate(ctx)
+			}
+
+			if !tc.noWake {
+				err = d.WakeFor(tc.duration)
+				if tc.wantErr {
+					require.Error(t, err, "WakeFor should return an error")
+					held, _ := d.AwakeOnDemand()
+					require.False(t, held, "The distro should not be kept awake after WakeFor failed")
+					return
+				}
+				require.NoError(t, err, "WakeFor should return no error")
+				require.Equal(t, "Running", wsltestutils.DistroState(t, ctx, distroName), "WakeFor should have started the distro")
+
+				held, until := d.AwakeOnDemand()
+				require.True(t, held, "The distro should be kept awake on demand")
+				require.Equal(t, tc.duration == 0, until.IsZero(), "The distro should be kept awake until the duration elapses, if any")
+			}
+
+			if tc.renew {
+				// Renewing without a duration keeps the distro awake past the first one.
+				err = d.WakeFor(0)
+				require.NoError(t, err, "WakeFor should return no error when renewing")
+			}
+
+			if tc.wantExpired {
+				require.Eventually(t, func() bool {
+					held, _ := d.AwakeOnDemand()
+					return !held
+				}, 5*time.Second, 100*time.Millisecond, "The distro should be released once the duration elapses")
+
+				stopped, err := d.StopWake()
+				require.NoError(t, err, "StopWake should return no error")
+				require.False(t, stopped, "StopWake should have nothing to release once the duration elapsed")
+				return
+			}
+
+			if tc.renew {
+				time.Sleep(2 * tc.duration)
+				held, _ := d.AwakeOnDemand()
+				require.True(t, held, "The distro should still be kept awake after renewing the hold")
+			}
+
+			stopped, err := d.StopWake()
+			require.NoError(t, err, "StopWake should return no error")
+			require.Equal(t, !tc.noWake, stopped, "StopWake should only release a distro that was woken up")
+
+			held, _ := d.AwakeOnDemand()
+			require.False(t, held, "The distro should not be kept awake after StopWake")
+		})
+	}
+}
+
 func TestLockReleaseAwake(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
@@ -469,6 +657,190 @@ func TestNoSimultaneousStartups(t *testing.T) {
 	require.Equal(t, "Running", state, "Distro should start after the mutex is released")
 }
 
+func TestConcurrentLockAwake(t *testing.T) {
+	t.Parallel()
+
+	if !wsl.MockAvailable() {
+		t.Skip("Skipped without mocks to avoid starting and stopping real distros in a loop")
+	}
+
+	// This test is meant to be run with -race: its assertions only catch deadlocks and unbalanced counts.
+	const (
+		goroutines = 10
+		iterations = 20
+		timeout    = 30 * time.Second
+	)
+
+	testCases := map[string]struct {
+		cleanupMidway  bool
+		blockedStartup bool
+	}{
+		"Success with concurrent locks and releases":           {},
+		"Success when cleanup races locks and releases":        {cleanupMidway: true},
+		"Success when cleanup races a distro waiting to start": {blockedStartup: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := wsl.WithMock(context.Background(), wslmock.New())
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, true)
+
+			var startupMu sync.Locker = &sync.Mutex{}
+			if tc.blockedStartup {
+				// Pretend that another distro never finishes starting up.
+				blocked := &blockedStartupMutex{}
+				blocked.Lock()
+				startupMu = blocked
+			}
+
+			d, err := distro.New(ctx, distroName, distro.Properties{}, t.TempDir(), startupMu)
+			require.NoError(t, err, "Setup: distro New should return no error")
+			defer d.Cleanup(context.Background())
+
+			var wg sync.WaitGroup
+			errs := make(chan error, goroutines*iterations)
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < iterations; j++ {
+						if err := d.LockAwake(); err != nil {
+							errs <- err
+							continue
+						}
+						// Exercise other methods of the distro at the same time.
+						_ = d.SetProperties(distro.Properties{Hostname: "hostname"})
+						if err := d.ReleaseAwake(); err != nil {
+							errs <- err
+						}
+					}
+				}()
+			}
+
+			if tc.cleanupMidway || tc.blockedStartup {
+				time.Sleep(100 * time.Millisecond)
+				cleanedUp := make(chan struct{})
+				go func() {
+					d.Cleanup(ctx)
+					close(cleanedUp)
+				}()
+
+				select {
+				case <-cleanedUp:
+				case <-time.After(timeout):
+					require.Fail(t, "Cleanup should not be blocked by the other distro operations")
+				}
+			}
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(timeout):
+				require.Fail(t, "LockAwake and ReleaseAwake should not deadlock")
+			}
+			close(errs)
+
+			if tc.cleanupMidway || tc.blockedStartup {
+				require.Error(t, d.LockAwake(), "LockAwake should return an error after Cleanup")
+				return
+			}
+
+			for err := range errs {
+				require.NoError(t, err, "LockAwake and ReleaseAwake should return no error")
+			}
+			require.Error(t, d.ReleaseAwake(), "ReleaseAwake should return an error when called more times than LockAwake")
+		})
+	}
+}
+
+// blockedStartupMutex is a startup mutex whose waits can be interrupted.
+type blockedStartupMutex struct {
+	sync.Mutex
+}
+
+func (m *blockedStartupMutex) LockContext(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestLockWatchdog(t *testing.T) {
+	t.Parallel()
+
+	if !wsl.MockAvailable() {
+		t.Skip("This test is only available for the mock back-end")
+	}
+
+	const threshold = 100 * time.Millisecond
+
+	testCases := map[string]struct {
+		holdFor  time.Duration
+		disabled bool
+
+		wantReport bool
+	}{
+		"Reports a suspected deadlock when the lock is held for too long": {holdFor: 5 * threshold, wantReport: true},
+
+		"Does not report when the lock is released in time": {holdFor: threshold / 10},
+		"Does not report when the watchdog is disabled":     {holdFor: 5 * threshold, disabled: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := wsl.WithMock(context.Background(), wslmock.New())
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, true)
+
+			type report struct{ distroName, waiter, holder string }
+			reports := make(chan report, 1)
+
+			watchdog := threshold
+			if tc.disabled {
+				watchdog = 0
+			}
+
+			d, err := distro.New(ctx, distroName, distro.Properties{}, t.TempDir(), startupMutex(),
+				distro.WithLockWatchdog(watchdog),
+				distro.WithDeadlockReporter(func(distroName, waiter, holder string, waited time.Duration) {
+					select {
+					case reports <- report{distroName, waiter, holder}:
+					default:
+					}
+				}))
+			require.NoError(t, err, "Setup: distro New should return no error")
+			defer d.Cleanup(context.Background())
+
+			release := d.HoldStateLock("testOperation")
+			released := make(chan error)
+			go func() {
+				released <- d.ReleaseAwake()
+			}()
+
+			time.Sleep(tc.holdFor)
+			release()
+			require.Error(t, <-released, "ReleaseAwake should return an error when called more times than LockAwake")
+
+			if !tc.wantReport {
+				require.Empty(t, reports, "No deadlock should have been reported")
+				return
+			}
+
+			require.Len(t, reports, 1, "A suspected deadlock should have been reported")
+			got := <-reports
+			require.Equal(t, distroName, got.distroName, "The report should name the distro")
+			require.Equal(t, "release", got.waiter, "The report should name the operation waiting for the lock")
+			require.Equal(t, "testOperation", got.holder, "The report should name the operation holding the lock")
+		})
+	}
+}
+
 func TestState(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
@@ -647,10 +1019,25 @@ func TestWorkerWrappers(t *testing.T) {
 		"SetConnection succeeds with nil connection on invalid distro": {function: "SetConnection", nilArg: true, wantWorkerCalled: true},
 		"SetConnection errors on invalid distro":                       {function: "SetConnection", invalidDistro: true, wantErr: true},
 
+		"ReleaseConnection succeeds":                   {function: "ReleaseConnection", wantWorkerCalled: true},
+		"ReleaseConnection succeeds on invalid distro": {function: "ReleaseConnection", invalidDistro: true, wantWorkerCalled: true},
+
 		"SubmitTasks succeeds with zero tasks": {function: "SubmitTasks", nilArg: true, wantWorkerCalled: true},
 		"SubmitTasks succeeds with arguments":  {function: "SubmitTasks", wantWorkerCalled: true},
 		"SubmitTasks errors on invalid distro": {function: "SubmitTasks", invalidDistro: true, wantErr: true},
 
+		"TaskHistory succeeds":                   {function: "TaskHistory", wantWorkerCalled: true},
+		"TaskHistory succeeds on invalid distro": {function: "TaskHistory", invalidDistro: true, wantWorkerCalled: true},
+
+		"QueuedTasks succeeds": {function: "QueuedTasks", wantWorkerCalled: true},
+		"CancelTask succeeds":  {function: "CancelTask", wantWorkerCalled: true},
+
+		"TaskInProgress succeeds":                   {function: "TaskInProgress", wantWorkerCalled: true},
+		"TaskInProgress succeeds on invalid distro": {function: "TaskInProgress", invalidDistro: true, wantWorkerCalled: true},
+
+		"Circuit succeeds":                   {function: "Circuit", wantWorkerCalled: true},
+		"Circuit succeeds on invalid distro": {function: "Circuit", invalidDistro: true, wantWorkerCalled: true},
+
 		"Stop succeeds":                 {function: "Stop", wantWorkerCalled: true},
 		"Stop errors on invalid distro": {function: "Stop", invalidDistro: true, wantWorkerCalled: true},
 	}
@@ -665,8 +1052,10 @@ func TestWorkerWrappers(t *testing.T) {
 			defer d.Cleanup(context.Background())
 			require.NoError(t, err, "Setup: distro New should return no error")
 
+			wantStopReason := worker.CancelAgentShutdown
 			if tc.invalidDistro {
 				d.Invalidate(ctx)
+				wantStopReason = worker.CancelDistroUnregistered
 			}
 
 			worker := *w
@@ -682,13 +1071,18 @@ func TestWorkerWrappers(t *testing.T) {
 				funcCalled = worker.clientCalled
 
 			case "SetConnection":
-				var conn *grpc.ClientConn
+				var conn *connection.Connection
 				if !tc.nilArg {
-					conn = &grpc.ClientConn{}
+					conn = connection.New("localhost:0")
 				}
 				err = d.SetConnection(conn)
 				funcCalled = worker.setConnectionCalled
 
+			case "ReleaseConnection":
+				d.ReleaseConnection(connection.New("localhost:0"))
+				funcCalled = worker.releaseConnectionCalled
+				err = nil
+
 			case "SubmitTasks":
 				var t []task.Task
 				if !tc.nilArg {
@@ -697,9 +1091,28 @@ func TestWorkerWrappers(t *testing.T) {
 				err = d.SubmitTasks(t...)
 				funcCalled = worker.submitTasksCalled
 
+			case "TaskHistory":
+				d.TaskHistory()
+				funcCalled = worker.taskHistoryCalled
+			case "QueuedTasks":
+				_, err = d.QueuedTasks()
+				funcCalled = worker.queuedTasksCalled
+			case "CancelTask":
+				err = d.CancelTask(ctx, "some-id")
+				funcCalled = worker.cancelTaskCalled
+			case "TaskInProgress":
+				d.TaskInProgress()
+				funcCalled = worker.taskInProgressCalled
+				err = nil
+			case "Circuit":
+				d.Circuit()
+				funcCalled = worker.circuitCalled
+				err = nil
+
 			case "Stop":
 				d.Cleanup(context.Background())
 				funcCalled = worker.stopCalled
+				require.Equal(t, wantStopReason, worker.stopReason, "Worker should have been stopped with the reason matching the distro validity")
 				err = nil
 			default:
 				require.Fail(t, "Setup: Unexpected tc.function")
@@ -720,6 +1133,196 @@ func TestWorkerWrappers(t *testing.T) {
 	}
 }
 
+func TestHibernate(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	testCases := map[string]struct {
+		idleFor     time.Duration
+		active      bool
+		pending     int
+		inProgress  bool
+		failures    int
+		hibernated  bool
+		cleanedUp   bool
+		wakeFailure bool
+
+		wantHibernate bool
+		wantWakeErr   bool
+	}{
+		"Success hibernating an idle distro":        {wantHibernate: true},
+		"Success waking up a hibernating distro":    {hibernated: true},
+		"Error waking up a distro after cleanup":    {hibernated: true, cleanedUp: true, wantWakeErr: true},
+		"Error waking up when the worker fails":     {hibernated: true, wakeFailure: true, wantWakeErr: true},
+		"No hibernation when recently used":         {idleFor: time.Hour},
+		"No hibernation when the distro is active":  {active: true},
+		"No hibernation with pending tasks":         {pending: 1},
+		"No hibernation with a task in progress":    {inProgress: true},
+		"No hibernation after failed tasks":         {failures: 1},
+		"No hibernation when already hibernating":   {hibernated: true},
+		"No hibernation when the distro is cleaned": {cleanedUp: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var failWake bool
+			var w *mockWorker
+			newMockWorker := func(_ context.Context, _ *distro.Distro, _ string, conf worker.Provisioning) (distro.Worker, error) {
+				if failWake {
+					return nil, errors.New("mock error")
+				}
+				w = &mockWorker{newProvisioning: conf}
+				return w, nil
+			}
+
+			d, err := distro.New(ctx, distroName, distro.Properties{}, t.TempDir(), startupMutex(), distro.WithNewWorker(newMockWorker), distro.WithProvisioning(mockProvisioning{}))
+			require.NoError(t, err, "Setup: distro New should return no error")
+			defer d.Cleanup(context.Background())
+
+			first := w
+			first.history = []worker.TaskRecord{{Task: "previous task"}}
+
+			if tc.hibernated {
+				require.True(t, d.Hibernate(ctx, 0), "Setup: Hibernate should have stopped the worker")
+			}
+			if tc.cleanedUp {
+				d.Cleanup(ctx)
+			}
+			failWake = tc.wakeFailure
+
+			first.active = tc.active
+			first.pending = tc.pending
+			first.inProgress = tc.inProgress
+			first.failures = tc.failures
+
+			got := d.Hibernate(ctx, tc.idleFor)
+			require.Equal(t, tc.wantHibernate, got, "Unexpected return value from Hibernate")
+
+			if tc.wantHibernate {
+				require.True(t, first.stopCalled, "The worker should have been stopped")
+				require.True(t, d.Hibernating(), "The distro should be hibernating")
+			}
+			if !tc.hibernated && !tc.cleanedUp {
+				require.Equal(t, tc.wantHibernate, first.stopCalled, "The worker should only be stopped when hibernating")
+				require.Equal(t, tc.wantHibernate, d.Hibernating(), "The distro should only be hibernating when Hibernate succeeds")
+			}
+
+			if !tc.hibernated {
+				return
+			}
+
+			// Queries must not wake the distro up.
+			active, err := d.IsActive()
+			require.NoError(t, err, "IsActive should return no error while hibernating")
+			require.False(t, active, "A hibernating distro should not be active")
+			require.Zero(t, d.PendingTasks(), "A hibernating distro should have no pending tasks")
+			require.Equal(t, first.history, d.TaskHistory(), "The task history should be kept during hibernation")
+			require.Same(t, first, w, "Queries should not wake the distro up")
+
+			err = d.SubmitTasks()
+			if tc.wantWakeErr {
+				require.Error(t, err, "SubmitTasks should fail when the distro cannot wake up")
+				return
+			}
+			require.NoError(t, err, "SubmitTasks should wake the distro up")
+
+			woken := w
+			require.NotSame(t, first, woken, "A new worker should have been created")
+			require.Nil(t, woken.newProvisioning, "The provisioning tasks should not be submitted again on wake up")
+			require.True(t, woken.submitTasksCalled, "The task should have been submitted to the new worker")
+			require.False(t, d.Hibernating(), "The distro should no longer be hibernating")
+			require.Equal(t, first.history, d.TaskHistory(), "The task history should be kept after waking up")
+		})
+	}
+}
+
+func TestConcurrentCleanup(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	w := &concurrentWorker{}
+	newWorker := func(context.Context, *distro.Distro, string, worker.Provisioning) (distro.Worker, error) {
+		return w, nil
+	}
+
+	d, err := distro.New(ctx, distroName, distro.Properties{}, t.TempDir(), startupMutex(), distro.WithNewWorker(newWorker))
+	require.NoError(t, err, "Setup: distro New should return no error")
+
+	const goroutines = 8
+	const iterations = 100
+	const cleanups = 3
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*iterations*3)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				errs <- d.SubmitTasks()
+				errs <- d.SetConnection(connection.New("localhost:0"))
+				errs <- d.SetConnection(nil)
+			}
+		}()
+	}
+
+	for i := 0; i < cleanups; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.Cleanup(ctx)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			require.ErrorIs(t, err, distro.ErrCleanedUp, "Operations racing with Cleanup should either succeed or return ErrCleanedUp")
+		}
+	}
+
+	require.True(t, d.CleanedUp(), "The distro should be cleaned up")
+	require.Equal(t, int32(1), w.stops.Load(), "The worker should be stopped exactly once")
+	require.Zero(t, w.callsAfterStop.Load(), "The worker should not be used after being stopped")
+
+	// Cleaning up again is a no-op.
+	d.Cleanup(ctx)
+	require.Equal(t, int32(1), w.stops.Load(), "Cleaning up again should not stop the worker again")
+
+	require.ErrorIs(t, d.SubmitTasks(), distro.ErrCleanedUp, "SubmitTasks should fail after Cleanup")
+	require.ErrorIs(t, d.SubmitDeferredTasks(), distro.ErrCleanedUp, "SubmitDeferredTasks should fail after Cleanup")
+	require.ErrorIs(t, d.SetConnection(connection.New("localhost:0")), distro.ErrCleanedUp, "SetConnection should fail after Cleanup")
+	require.ErrorIs(t, d.ClearTasks(), distro.ErrCleanedUp, "ClearTasks should fail after Cleanup")
+	require.ErrorIs(t, d.CancelTask(ctx, "some-id"), distro.ErrCleanedUp, "CancelTask should fail after Cleanup")
+	_, err = d.QueuedTasks()
+	require.ErrorIs(t, err, distro.ErrCleanedUp, "QueuedTasks should fail after Cleanup")
+	require.ErrorIs(t, d.LockAwake(), distro.ErrCleanedUp, "LockAwake should fail after Cleanup")
+	require.ErrorIs(t, d.ReleaseAwake(), distro.ErrCleanedUp, "ReleaseAwake should fail after Cleanup")
+
+	require.NoError(t, d.SetConnection(nil), "Removing the connection after Cleanup should be a no-op")
+	active, err := d.IsActive()
+	require.NoError(t, err, "IsActive should return no error after Cleanup")
+	require.False(t, active, "A cleaned up distro should not be active")
+	require.Zero(t, d.PendingTasks(), "A cleaned up distro should have no pending tasks")
+	require.False(t, d.Hibernating(), "A cleaned up distro should not be hibernating")
+	require.Zero(t, w.callsAfterStop.Load(), "The worker should not be used after being stopped")
+}
+
 func TestUninstall(t *testing.T) {
 	if wsl.MockAvailable() {
 		t.Parallel()
@@ -777,11 +1380,24 @@ type mockWorker struct {
 	newDir          string
 	newProvisioning worker.Provisioning
 
-	isActiveCalled      bool
-	clientCalled        bool
-	setConnectionCalled bool
-	submitTasksCalled   bool
-	stopCalled          bool
+	isActiveCalled          bool
+	clientCalled            bool
+	setConnectionCalled     bool
+	releaseConnectionCalled bool
+	submitTasksCalled       bool
+	taskHistoryCalled       bool
+	queuedTasksCalled       bool
+	cancelTaskCalled        bool
+	taskInProgressCalled    bool
+	circuitCalled           bool
+	stopCalled              bool
+	stopReason              worker.CancelReason
+
+	active     bool
+	pending    int
+	inProgress bool
+	failures   int
+	history    []worker.TaskRecord
 }
 
 func mockWorkerInjector(constructorReturnsError bool) (distro.Option, **mockWorker) {
@@ -805,7 +1421,7 @@ func mockWorkerInjector(constructorReturnsError bool) (distro.Option, **mockWork
 
 func (w *mockWorker) IsActive() bool {
 	w.isActiveCalled = true
-	return false
+	return w.active
 }
 
 func (w *mockWorker) Client() wslserviceapi.WSLClient {
@@ -813,10 +1429,15 @@ func (w *mockWorker) Client() wslserviceapi.WSLClient {
 	return nil
 }
 
-func (w *mockWorker) SetConnection(conn *grpc.ClientConn) {
+func (w *mockWorker) SetConnection(conn *connection.Connection) {
 	w.setConnectionCalled = true
 }
 
+func (w *mockWorker) ReleaseConnection(*connection.Connection) bool {
+	w.releaseConnectionCalled = true
+	return true
+}
+
 func (w *mockWorker) SubmitTasks(...task.Task) error {
 	w.submitTasksCalled = true
 	return nil
@@ -830,8 +1451,85 @@ func (w *mockWorker) EnqueueDeferredTasks() {
 	panic("Not implemented")
 }
 
-func (w *mockWorker) Stop(context.Context) {
+func (w *mockWorker) ClearTasks() error {
+	return nil
+}
+
+func (w *mockWorker) PendingTasks() int {
+	return w.pending
+}
+
+func (w *mockWorker) TaskInProgress() bool {
+	w.taskInProgressCalled = true
+	return w.inProgress
+}
+
+func (w *mockWorker) Circuit() worker.CircuitState {
+	w.circuitCalled = true
+	return worker.CircuitState{Failures: w.failures}
+}
+
+func (w *mockWorker) TaskHistory() []worker.TaskRecord {
+	w.taskHistoryCalled = true
+	return w.history
+}
+
+func (w *mockWorker) QueuedTasks() []worker.QueuedTask {
+	w.queuedTasksCalled = true
+	return nil
+}
+
+func (w *mockWorker) CancelTask(context.Context, string) error {
+	w.cancelTaskCalled = true
+	return nil
+}
+
+func (w *mockWorker) Stop(ctx context.Context) {
+	w.StopWithReason(ctx, worker.CancelAgentShutdown)
+}
+
+func (w *mockWorker) StopWithReason(_ context.Context, reason worker.CancelReason) {
 	w.stopCalled = true
+	w.stopReason = reason
+}
+
+// concurrentWorker is a worker that is safe for concurrent use. It counts how many times it is stopped,
+// and the calls it receives after that.
+type concurrentWorker struct {
+	stopped        atomic.Bool
+	stops          atomic.Int32
+	callsAfterStop atomic.Int32
+}
+
+func (w *concurrentWorker) called() {
+	if w.stopped.Load() {
+		w.callsAfterStop.Add(1)
+	}
+}
+
+func (w *concurrentWorker) IsActive() bool                                { w.called(); return false }
+func (w *concurrentWorker) Client() wslserviceapi.WSLClient               { w.called(); return nil }
+func (w *concurrentWorker) SetConnection(*connection.Connection)          { w.called() }
+func (w *concurrentWorker) ReleaseConnection(*connection.Connection) bool { w.called(); return true }
+func (w *concurrentWorker) SubmitTasks(...task.Task) error                { w.called(); return nil }
+func (w *concurrentWorker) SubmitDeferredTasks(...task.Task) error        { w.called(); return nil }
+func (w *concurrentWorker) EnqueueDeferredTasks()                         { w.called() }
+func (w *concurrentWorker) ClearTasks() error                             { w.called(); return nil }
+func (w *concurrentWorker) PendingTasks() int                             { w.called(); return 0 }
+func (w *concurrentWorker) TaskInProgress() bool                          { w.called(); return false }
+func (w *concurrentWorker) Circuit() worker.CircuitState                  { w.called(); return worker.CircuitState{} }
+func (w *concurrentWorker) TaskHistory() []worker.TaskRecord              { w.called(); return nil }
+func (w *concurrentWorker) QueuedTasks() []worker.QueuedTask              { w.called(); return nil }
+func (w *concurrentWorker) CancelTask(context.Context, string) error      { w.called(); return nil }
+
+func (w *concurrentWorker) Stop(ctx context.Context) {
+	w.StopWithReason(ctx, worker.CancelAgentShutdown)
+}
+
+func (w *concurrentWorker) StopWithReason(context.Context, worker.CancelReason) {
+	w.called()
+	w.stops.Add(1)
+	w.stopped.Store(true)
 }
 
 type mockProvisioning struct{}
@@ -2,6 +2,7 @@ package distro
 
 import (
 	"context"
+	"time"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
 )
@@ -33,3 +34,17 @@ type Identity = identity
 func (d *Distro) GetIdentity() *Identity {
 	return &d.identity
 }
+
+// WithDeadlockReporter overrides how suspected deadlocks are reported by the lock watchdog.
+func WithDeadlockReporter(f func(distroName, waiter, holder string, waited time.Duration)) Option {
+	return func(o *options) {
+		o.deadlockReporter = f
+	}
+}
+
+// HoldStateLock takes the internal lock of the distro on behalf of the operation. The returned
+// function releases it.
+func (d *Distro) HoldStateLock(operation string) (release func()) {
+	d.stateManager.mu.Lock(operation)
+	return d.stateManager.mu.Unlock
+}
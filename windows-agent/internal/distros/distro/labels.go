@@ -0,0 +1,102 @@
+package distro
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+)
+
+// labelKeyRegex matches the label keys: they start with an alphanumeric character, then may
+// contain dots, dashes, underscores and slashes, such as "env" or "example.com/team".
+var labelKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]{0,62}$`)
+
+// maxLabelValueLength is the longest value a label can have.
+const maxLabelValueLength = 256
+
+// ValidateLabels returns an error if any of the labels cannot be assigned to a distro.
+func ValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if !labelKeyRegex.MatchString(k) {
+			return fmt.Errorf("invalid label key %q: it must start with a letter or a digit, and only contain letters, digits, '.', '-', '_' and '/'", k)
+		}
+		if len(v) > maxLabelValueLength {
+			return fmt.Errorf("invalid value for label %q: it is longer than %d characters", k, maxLabelValueLength)
+		}
+		if strings.ContainsAny(v, ",\n") {
+			return fmt.Errorf("invalid value for label %q: it cannot contain commas nor line breaks", k)
+		}
+	}
+	return nil
+}
+
+// Selector picks distros by their labels. A distro matches if it has every label of the selector,
+// with the same value. The empty selector matches every distro.
+type Selector map[string]string
+
+// ParseSelector parses a selector written as comma-separated key=value pairs, such as "env=dev,team=web".
+func ParseSelector(s string) (Selector, error) {
+	sel := Selector{}
+	if strings.TrimSpace(s) == "" {
+		return sel, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid selector %q: %q is not a key=value pair", s, pair)
+		}
+		if _, dup := sel[k]; dup {
+			return nil, fmt.Errorf("invalid selector %q: label %q is selected twice", s, k)
+		}
+		sel[k] = v
+	}
+
+	if err := ValidateLabels(sel); err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %v", s, err)
+	}
+
+	return sel, nil
+}
+
+// Matches returns true if the labels contain every label of the selector.
+func (s Selector) Matches(labels map[string]string) bool {
+	for k, v := range s {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Labels returns the labels assigned to the distro by the user or their organization.
+func (d *Distro) Labels() map[string]string {
+	d.propertiesMu.RLock()
+	defer d.propertiesMu.RUnlock()
+
+	return maps.Clone(d.labels)
+}
+
+// SetLabels replaces the labels of the distro, and returns true if they changed. Labels with an
+// empty value are dropped.
+func (d *Distro) SetLabels(labels map[string]string) (bool, error) {
+	if err := ValidateLabels(labels); err != nil {
+		return false, err
+	}
+
+	labels = maps.Clone(labels)
+	maps.DeleteFunc(labels, func(_, v string) bool { return v == "" })
+	if len(labels) == 0 {
+		labels = nil
+	}
+
+	d.propertiesMu.Lock()
+	defer d.propertiesMu.Unlock()
+
+	if maps.Equal(d.labels, labels) {
+		return false, nil
+	}
+
+	d.labels = labels
+	return true, nil
+}
@@ -4,15 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/guid"
 	wsl "github.com/ubuntu/gowsl"
 )
 
 // identity contains persistent and uniquely identifying information about the distro.
 type identity struct {
 	Name string
-	GUID uuid.UUID
+	GUID guid.GUID
 
 	// This context contains GoWSL's backend
 	ctx context.Context
@@ -28,8 +29,65 @@ type Properties struct {
 	// Instance info
 	Hostname string
 
+	// InstanceID is the identity generated inside the distro. It is stable across
+	// agent reinstalls and is used by Landscape to match the computer.
+	InstanceID string
+
 	// Ubuntu Pro
 	ProAttached bool
+	ProServices []string `yaml:",omitempty"`
+
+	// Landscape
+	LandscapeRegistered bool `yaml:",omitempty"`
+
+	// LandscapeScriptUsers are the users that Landscape scripts may run as, once mapped to the users
+	// of the distro. Empty if left to the default of the Landscape client.
+	LandscapeScriptUsers string `yaml:",omitempty"`
+
+	// ServiceVersion is the version of the WSL Pro service of the distro, as reported when it last connected.
+	ServiceVersion string `yaml:",omitempty"`
+}
+
+// SecurityStatus contains the number of security updates pending in the distro.
+type SecurityStatus struct {
+	// SecurityUpdates is the number of pending updates from the standard security pocket.
+	SecurityUpdates uint32 `yaml:",omitempty"`
+
+	// ESMUpdates is the number of pending updates that are only available with Ubuntu Pro.
+	ESMUpdates uint32 `yaml:",omitempty"`
+
+	// Checked is when the distro was last asked. The zero time means that it never was.
+	Checked time.Time `yaml:",omitempty"`
+}
+
+// UpdateStatus tells whether the distro needs to be rebooted and how many package updates are pending.
+type UpdateStatus struct {
+	// RebootRequired is true when an update only takes effect after the distro is rebooted.
+	RebootRequired bool `yaml:",omitempty"`
+
+	// RebootPackages are the packages whose update requires the reboot.
+	RebootPackages []string `yaml:",omitempty"`
+
+	// PendingUpdates is the number of packages that can be upgraded.
+	PendingUpdates uint32 `yaml:",omitempty"`
+
+	// Checked is when the distro was last asked. The zero time means that it never was.
+	Checked time.Time `yaml:",omitempty"`
+}
+
+// ResourceUsage contains the resources used by the distro. It is volatile: it is not stored in the database.
+type ResourceUsage struct {
+	// MemoryBytes is the resident memory of the processes of the distro.
+	MemoryBytes uint64
+
+	// CPUPercent is the CPU used by the processes of the distro, as a percentage of one CPU.
+	CPUPercent float64
+
+	// DiskBytes is the size of the virtual disk of the distro.
+	DiskBytes uint64
+
+	// Checked is when the distro was last asked. The zero time means that it never was.
+	Checked time.Time
 }
 
 // isValid checks that the properties against the registry.
@@ -45,7 +103,7 @@ func (id identity) isValid() (ok bool) {
 		panic(fmt.Errorf("could not access the Windows Registry: %v", err))
 	}
 
-	if id.GUID != inRegistry {
+	if id.GUID != guid.FromUUID(inRegistry) {
 		// Distro was unregistered and re-registered
 		return false
 	}
@@ -0,0 +1,96 @@
+package distro
+
+import (
+	"sync"
+	"time"
+)
+
+// wakeDemand is the hold on the distro taken by WakeFor, so that it stays awake without any task to run.
+type wakeDemand struct {
+	held bool
+
+	// timer releases the hold once it fires. It is nil while the hold lasts until StopWake is called.
+	timer *time.Timer
+	until time.Time
+
+	mu sync.Mutex
+}
+
+// WakeFor starts the distro and keeps it awake for the duration, or until StopWake is called if the
+// duration is zero. Calling it again while the distro is kept awake replaces the duration.
+//
+// The distro is guaranteed to be running by the time this function returns, otherwise an error is returned.
+func (d *Distro) WakeFor(duration time.Duration) error {
+	d.demand.mu.Lock()
+	defer d.demand.mu.Unlock()
+
+	if !d.demand.held {
+		if err := d.LockAwake(); err != nil {
+			return err
+		}
+		d.demand.held = true
+	}
+
+	if d.demand.timer != nil {
+		d.demand.timer.Stop()
+		d.demand.timer = nil
+	}
+	d.demand.until = time.Time{}
+
+	if duration > 0 {
+		var t *time.Timer
+		t = time.AfterFunc(duration, func() { d.expireWake(t) })
+		d.demand.timer = t
+		d.demand.until = time.Now().Add(duration)
+	}
+
+	return nil
+}
+
+// StopWake releases the distro kept awake by WakeFor, so that it can shut down once idle. It returns
+// false if the distro was not kept awake on demand.
+func (d *Distro) StopWake() (bool, error) {
+	d.demand.mu.Lock()
+	defer d.demand.mu.Unlock()
+
+	if !d.demand.held {
+		return false, nil
+	}
+
+	return true, d.releaseWakeUnsafe()
+}
+
+// AwakeOnDemand returns whether the distro is kept awake by WakeFor and until when. The time is zero
+// if it is kept awake until StopWake is called.
+func (d *Distro) AwakeOnDemand() (held bool, until time.Time) {
+	d.demand.mu.Lock()
+	defer d.demand.mu.Unlock()
+
+	return d.demand.held, d.demand.until
+}
+
+// expireWake releases the distro once the duration passed to WakeFor elapsed, unless the hold was
+// renewed or released since the timer was set.
+func (d *Distro) expireWake(t *time.Timer) {
+	d.demand.mu.Lock()
+	defer d.demand.mu.Unlock()
+
+	if !d.demand.held || d.demand.timer != t {
+		return
+	}
+
+	// The distro may have been cleaned up meanwhile, in which case there is nothing left to release.
+	_ = d.releaseWakeUnsafe()
+}
+
+// releaseWakeUnsafe drops the hold taken by WakeFor. It must be called with the demand mutex held.
+func (d *Distro) releaseWakeUnsafe() error {
+	if d.demand.timer != nil {
+		d.demand.timer.Stop()
+		d.demand.timer = nil
+	}
+	d.demand.held = false
+	d.demand.until = time.Time{}
+
+	return d.ReleaseAwake()
+}
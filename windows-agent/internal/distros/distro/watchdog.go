@@ -0,0 +1,69 @@
+package distro
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+)
+
+// deadlockReporter is called when an operation has waited for a lock for longer than the watchdog
+// threshold. The holder is the operation that held the lock when the threshold was exceeded.
+type deadlockReporter func(distroName, waiter, holder string, waited time.Duration)
+
+// reportDeadlock is the default deadlockReporter. It only logs a warning.
+func reportDeadlock(distroName, waiter, holder string, waited time.Duration) {
+	log.Warningf(context.Background(), "Distro %q: suspected deadlock: %s has been waiting for %s for the lock held by %s", distroName, waiter, waited, holder)
+}
+
+// watchdogMutex is a mutex that reports suspected deadlocks. Every lock is tagged with the name
+// of the operation taking it, so that a waiter that exceeds the threshold can report who is holding
+// it. A zero threshold disables the reports, so the zero value is a plain mutex.
+type watchdogMutex struct {
+	mu sync.Mutex
+
+	distroName string
+	threshold  time.Duration
+	report     deadlockReporter
+
+	// holder is the operation holding mu. It has its own mutex so that waiters can read it.
+	holder   string
+	holderMu sync.Mutex
+}
+
+// Lock locks the mutex on behalf of the operation.
+func (m *watchdogMutex) Lock(operation string) {
+	if m.threshold > 0 {
+		timer := time.AfterFunc(m.threshold, func() {
+			m.report(m.distroName, operation, m.currentHolder(), m.threshold)
+		})
+		defer timer.Stop()
+	}
+
+	m.mu.Lock()
+	m.setHolder(operation)
+}
+
+// Unlock unlocks the mutex.
+func (m *watchdogMutex) Unlock() {
+	m.setHolder("")
+	m.mu.Unlock()
+}
+
+func (m *watchdogMutex) setHolder(operation string) {
+	m.holderMu.Lock()
+	defer m.holderMu.Unlock()
+
+	m.holder = operation
+}
+
+func (m *watchdogMutex) currentHolder() string {
+	m.holderMu.Lock()
+	defer m.holderMu.Unlock()
+
+	if m.holder == "" {
+		return "nobody"
+	}
+	return m.holder
+}
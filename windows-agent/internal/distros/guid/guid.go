@@ -0,0 +1,56 @@
+// Package guid implements the identifier WSL assigns to every registered distro. It is a plain
+// string, so that it can be stored, compared and sent over the wire the same way on every platform.
+package guid
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// GUID identifies a registration of a distro: registering a distro again changes it. It is kept in
+// its canonical form, lowercase and without braces, so that two GUIDs can be compared with ==.
+//
+// The zero value identifies no distro.
+type GUID string
+
+// Parse returns the GUID in the string, with or without braces.
+func Parse(s string) (GUID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("could not parse GUID %q: %v", s, err)
+	}
+
+	return FromUUID(u), nil
+}
+
+// FromUUID returns the GUID matching the UUID, as returned by GoWSL. The nil UUID is the zero GUID.
+func FromUUID(u uuid.UUID) GUID {
+	if u == uuid.Nil {
+		return ""
+	}
+	return GUID(u.String())
+}
+
+// UUID returns the UUID matching the GUID, as expected by GoWSL. The zero GUID is the nil UUID.
+func (g GUID) UUID() (uuid.UUID, error) {
+	if g.IsZero() {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(string(g))
+}
+
+// IsZero returns true if the GUID identifies no distro.
+func (g GUID) IsZero() bool {
+	return g == ""
+}
+
+// String returns the GUID in its canonical form.
+func (g GUID) String() string {
+	return string(g)
+}
+
+// Braced returns the GUID between braces, as found in the names of the registry keys of the distros.
+func (g GUID) Braced() string {
+	return "{" + string(g) + "}"
+}
@@ -0,0 +1,63 @@
+package guid_test
+
+import (
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/guid"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	const want = guid.GUID("12345678-1234-1234-1234-123456789abc")
+
+	testCases := map[string]struct {
+		in string
+
+		wantErr bool
+	}{
+		"Success with a canonical GUID":  {in: "12345678-1234-1234-1234-123456789abc"},
+		"Success with a braced GUID":     {in: "{12345678-1234-1234-1234-123456789abc}"},
+		"Success with an uppercase GUID": {in: "{12345678-1234-1234-1234-123456789ABC}"},
+
+		"Error with an empty string": {in: "", wantErr: true},
+		"Error with a bad GUID":      {in: "{not-a-guid}", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := guid.Parse(tc.in)
+			if tc.wantErr {
+				require.Error(t, err, "Parse should return an error")
+				return
+			}
+			require.NoError(t, err, "Parse should return no errors")
+			require.Equal(t, want, got, "Parse should return the canonical GUID")
+			require.Equal(t, "{12345678-1234-1234-1234-123456789abc}", got.Braced(), "Braced should return the GUID between braces")
+		})
+	}
+}
+
+func TestUUID(t *testing.T) {
+	t.Parallel()
+
+	u := uuid.MustParse("12345678-1234-1234-1234-123456789abc")
+
+	g := guid.FromUUID(u)
+	require.Equal(t, guid.GUID("12345678-1234-1234-1234-123456789abc"), g, "FromUUID should return the canonical GUID")
+	require.False(t, g.IsZero(), "A GUID from a UUID should not be zero")
+
+	got, err := g.UUID()
+	require.NoError(t, err, "UUID should return no errors")
+	require.Equal(t, u, got, "UUID should return the UUID the GUID was created from")
+
+	require.True(t, guid.FromUUID(uuid.Nil).IsZero(), "The nil UUID should be the zero GUID")
+
+	got, err = guid.GUID("").UUID()
+	require.NoError(t, err, "UUID should return no errors for the zero GUID")
+	require.Equal(t, uuid.Nil, got, "The zero GUID should be the nil UUID")
+}
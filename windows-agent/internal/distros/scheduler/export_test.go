@@ -0,0 +1,22 @@
+package scheduler
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+)
+
+// WithTask adds a kind of task that can be scheduled, so that tests can count the submissions.
+func WithTask(kind string, newTask func() task.Task) Option {
+	return func(o *options) {
+		o.kinds = maps.Clone(o.kinds)
+		o.kinds[kind] = newTask
+	}
+}
+
+// SubmitIfDue checks the schedules as if it was the given time.
+func (s *Scheduler) SubmitIfDue(ctx context.Context, now time.Time) {
+	s.submitIfDue(ctx, now)
+}
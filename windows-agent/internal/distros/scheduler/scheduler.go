@@ -0,0 +1,288 @@
+// Package scheduler submits recurring tasks to every managed distro, according to the cron-like
+// schedules defined in the configuration.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/cron"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/tasks"
+	"github.com/ubuntu/decorate"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultCheckInterval is how often the schedules are checked. Schedules have minute precision.
+	defaultCheckInterval = time.Minute
+
+	// missedAfter is how late a run can be before it is considered missed, e.g. because the
+	// machine was asleep when it was due.
+	missedAfter = 10 * time.Minute
+)
+
+// defaultKinds are the kinds of task that can be scheduled, indexed by the name used in the configuration.
+var defaultKinds = map[string]func() task.Task{
+	"pro-refresh": func() task.Task { return tasks.ProRefresh{} },
+}
+
+// Config is an interface to easily allow dependency injection. Should be a config.Config
+// in production.
+type Config interface {
+	RecurringTasks() ([]config.RecurringTask, error)
+}
+
+// Pauser tells the scheduler whether the agent is paused by the user.
+type Pauser interface {
+	Paused() bool
+}
+
+// Scheduler submits the recurring tasks to the distros when they are due.
+type Scheduler struct {
+	ctx  context.Context
+	stop func()
+
+	running chan struct{}
+
+	conf      Config
+	db        *database.DistroDB
+	statePath string
+
+	checkInterval time.Duration
+	kinds         map[string]func() task.Task
+
+	// pauser holds the recurring tasks back while the agent is paused. It may be nil.
+	pauser Pauser
+
+	// lastRun contains when each recurring task was last due, indexed by name. It is persisted
+	// to statePath so that runs are not repeated nor forgotten across restarts.
+	lastRun map[string]time.Time
+	mu      sync.Mutex
+}
+
+type options struct {
+	checkInterval time.Duration
+	pauser        Pauser
+	kinds         map[string]func() task.Task
+}
+
+// Option is an optional argument for the scheduler.
+type Option = func(*options)
+
+// WithCheckInterval overrides how often the schedules are checked.
+func WithCheckInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.checkInterval = d
+	}
+}
+
+// WithPauser holds the recurring tasks back while the agent is paused. The runs missed in the
+// meantime follow the catch-up policy of each task.
+func WithPauser(p Pauser) Option {
+	return func(o *options) {
+		o.pauser = p
+	}
+}
+
+// New creates a scheduler. When each recurring task last ran is stored in the file at statePath.
+func New(ctx context.Context, conf Config, db *database.DistroDB, statePath string, args ...Option) (s *Scheduler, err error) {
+	defer decorate.OnError(&err, "could not create scheduler")
+
+	opts := options{
+		checkInterval: defaultCheckInterval,
+		kinds:         defaultKinds,
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	lastRun, err := load(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		conf:          conf,
+		db:            db,
+		statePath:     statePath,
+		checkInterval: opts.checkInterval,
+		kinds:         opts.kinds,
+		pauser:        opts.pauser,
+		lastRun:       lastRun,
+
+		ctx:     ctx,
+		stop:    func() {},
+		running: make(chan struct{}),
+	}, nil
+}
+
+// Start starts checking the schedules in the background.
+func (s *Scheduler) Start() {
+	s.ctx, s.stop = context.WithCancel(s.ctx)
+	go s.run()
+}
+
+// Stop stops checking the schedules and waits for the scheduler to stop. Tasks already submitted
+// are left in the queues of the distros.
+func (s *Scheduler) Stop() {
+	s.stop()
+	<-s.running
+}
+
+// run is the blocking scheduler loop.
+func (s *Scheduler) run() {
+	defer close(s.running)
+
+	log.Info(s.ctx, "Scheduler: started")
+	defer log.Info(s.ctx, "Scheduler: stopped")
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		s.submitIfDue(s.ctx, time.Now())
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// submitIfDue submits every recurring task that has been due since its last run to all managed distros.
+// Runs that were missed are skipped or caught up once, depending on the policy of the task.
+func (s *Scheduler) submitIfDue(ctx context.Context, now time.Time) {
+	if s.pauser != nil && s.pauser.Paused() {
+		return
+	}
+
+	recurring, err := s.conf.RecurringTasks()
+	if err != nil {
+		log.Warningf(ctx, "Scheduler: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	defined := make(map[string]struct{})
+
+	for _, r := range recurring {
+		defined[r.Name] = struct{}{}
+
+		schedule, err := cron.Parse(r.Schedule)
+		if err != nil {
+			log.Warningf(ctx, "Scheduler: recurring task %q: %v", r.Name, err)
+			continue
+		}
+
+		newTask, ok := s.kinds[r.Task]
+		if !ok {
+			log.Warningf(ctx, "Scheduler: recurring task %q: unknown kind of task %q", r.Name, r.Task)
+			continue
+		}
+
+		last, ok := s.lastRun[r.Name]
+		if !ok {
+			// A new task is first due at its next scheduled time, rather than right away.
+			s.lastRun[r.Name] = now
+			changed = true
+			continue
+		}
+
+		next := schedule.Next(last)
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+
+		s.lastRun[r.Name] = now
+		changed = true
+
+		if now.Sub(next) > missedAfter && r.CatchUp != config.CatchUpOnce {
+			log.Infof(ctx, "Scheduler: recurring task %q: skipping the run missed at %s", r.Name, next.Format(time.RFC3339))
+			continue
+		}
+
+		log.Infof(ctx, "Scheduler: recurring task %q: submitting %s", r.Name, r.Task)
+		s.submit(ctx, newTask, r.Selector)
+	}
+
+	// Removed tasks are forgotten, so that a task added again later is treated as new.
+	for name := range s.lastRun {
+		if _, ok := defined[name]; !ok {
+			delete(s.lastRun, name)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	if err := dump(s.statePath, s.lastRun); err != nil {
+		log.Warningf(ctx, "Scheduler: %v", err)
+	}
+}
+
+// submit submits a new instance of the task to every managed distro matching the selector.
+func (s *Scheduler) submit(ctx context.Context, newTask func() task.Task, sel distro.Selector) {
+	for _, d := range s.db.Select(sel) {
+		if err := d.SubmitTasks(newTask()); err != nil {
+			log.Warningf(ctx, "Scheduler: distro %q: could not submit task: %v", d.Name(), err)
+		}
+	}
+}
+
+// load reads when each recurring task last ran. A missing file means that none ever ran.
+func load(path string) (map[string]time.Time, error) {
+	lastRun := make(map[string]time.Time)
+
+	out, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lastRun, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read the last runs: %v", err)
+	}
+
+	if err := yaml.Unmarshal(out, &lastRun); err != nil {
+		return nil, fmt.Errorf("could not parse the last runs: %v", err)
+	}
+
+	if lastRun == nil {
+		lastRun = make(map[string]time.Time)
+	}
+
+	return lastRun, nil
+}
+
+// dump writes when each recurring task last ran.
+func dump(path string, lastRun map[string]time.Time) error {
+	out, err := yaml.Marshal(lastRun)
+	if err != nil {
+		return fmt.Errorf("could not marshal the last runs: %v", err)
+	}
+
+	tmp := path + ".new"
+	if err := os.WriteFile(tmp, out, 0600); err != nil {
+		return fmt.Errorf("could not write the last runs: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("could not write the last runs: %v", err)
+	}
+
+	return nil
+}
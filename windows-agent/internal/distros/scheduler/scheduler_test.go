@@ -0,0 +1,303 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/scheduler"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+	"gopkg.in/yaml.v3"
+)
+
+// now is the time at which the schedules are checked: Wednesday 6th of March 2024, 10:17:42 UTC.
+var now = time.Date(2024, time.March, 6, 10, 17, 42, 0, time.UTC)
+
+func TestMain(m *testing.M) {
+	task.Register[testTask]()
+
+	exit := m.Run()
+	defer os.Exit(exit)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		state      string
+		stateIsDir bool
+
+		wantErr bool
+	}{
+		"Success without previous runs": {},
+		"Success with previous runs":    {state: "refresh: 2024-03-06T10:16:12Z\n"},
+		"Success with an empty file":    {state: "\n"},
+
+		"Error when the file cannot be read":   {stateIsDir: true, wantErr: true},
+		"Error when the file cannot be parsed": {state: "refresh: [not a time", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			statePath := filepath.Join(t.TempDir(), "recurring.yaml")
+			if tc.state != "" {
+				require.NoError(t, os.WriteFile(statePath, []byte(tc.state), 0600), "Setup: could not write the state file")
+			}
+			if tc.stateIsDir {
+				require.NoError(t, os.MkdirAll(statePath, 0700), "Setup: could not create a directory in place of the state file")
+			}
+
+			_, err := scheduler.New(context.Background(), &mockConfig{}, nil, statePath)
+			if tc.wantErr {
+				require.Error(t, err, "New should return an error")
+				return
+			}
+			require.NoError(t, err, "New should return no error")
+		})
+	}
+}
+
+func TestSubmitIfDue(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	type lastRun int
+	const (
+		lastRunUnchanged lastRun = iota
+		lastRunNow
+		lastRunForgotten
+	)
+
+	testCases := map[string]struct {
+		schedule  string
+		catchUp   string
+		selector  distro.Selector
+		kind      string
+		lastRun   time.Duration
+		neverRan  bool
+		paused    bool
+		configErr bool
+
+		wantSubmissions int
+		wantLastRun     lastRun
+	}{
+		"Success submitting a task that is due":        {schedule: "* * * * *", lastRun: 90 * time.Second, wantSubmissions: 1, wantLastRun: lastRunNow},
+		"Success submitting a task due a while ago":    {schedule: "*/5 * * * *", lastRun: 6 * time.Minute, wantSubmissions: 1, wantLastRun: lastRunNow},
+		"Success catching up once on missed runs":      {schedule: "@daily", catchUp: config.CatchUpOnce, lastRun: 72 * time.Hour, wantSubmissions: 1, wantLastRun: lastRunNow},
+		"Success catching up once on a late run":       {schedule: "@hourly", catchUp: config.CatchUpOnce, lastRun: 50 * time.Minute, wantSubmissions: 1, wantLastRun: lastRunNow},
+		"Success skipping missed runs":                 {schedule: "@daily", lastRun: 72 * time.Hour, wantLastRun: lastRunNow},
+		"Success skipping missed runs explicitly":      {schedule: "@daily", catchUp: config.CatchUpSkip, lastRun: 72 * time.Hour, wantLastRun: lastRunNow},
+		"Success skipping a late run":                  {schedule: "@hourly", lastRun: 50 * time.Minute, wantLastRun: lastRunNow},
+		"Success waiting for the first scheduled time": {schedule: "* * * * *", neverRan: true, wantLastRun: lastRunNow},
+		"Success waiting when the task is not due":     {schedule: "@daily", lastRun: time.Hour},
+		"Success forgetting a task that was removed":   {lastRun: time.Hour, wantLastRun: lastRunForgotten},
+		"Success submitting to the selected distros":   {schedule: "* * * * *", selector: distro.Selector{"env": "dev"}, lastRun: 90 * time.Second, wantSubmissions: 1, wantLastRun: lastRunNow},
+
+		"No submission while the agent is paused":          {schedule: "* * * * *", lastRun: 90 * time.Second, paused: true},
+		"No submission when the schedule is invalid":       {schedule: "every minute", lastRun: 90 * time.Second},
+		"No submission when the kind of task is unknown":   {schedule: "* * * * *", kind: "not-a-task", lastRun: 90 * time.Second},
+		"No submission when the schedule never fires":      {schedule: "0 0 31 2 *", lastRun: 90 * time.Second},
+		"No submission when the config cannot be read":     {schedule: "* * * * *", lastRun: 90 * time.Second, configErr: true},
+		"No submission when the task ran this very minute": {schedule: "* * * * *", lastRun: 30 * time.Second},
+		"No submission when no distro is selected":         {schedule: "* * * * *", selector: distro.Selector{"env": "prod"}, lastRun: 90 * time.Second, wantLastRun: lastRunNow},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db := setupDatabase(t, ctx)
+			for _, d := range db.GetAll() {
+				_, err := d.SetLabels(map[string]string{"env": "dev"})
+				require.NoError(t, err, "Setup: could not set labels")
+			}
+			statePath := filepath.Join(t.TempDir(), "recurring.yaml")
+
+			previous := now.Add(-tc.lastRun)
+			if !tc.neverRan {
+				writeLastRuns(t, statePath, map[string]time.Time{"refresh": previous})
+			}
+
+			if tc.kind == "" {
+				tc.kind = "test-task"
+			}
+
+			conf := &mockConfig{configErr: tc.configErr}
+			if tc.schedule != "" {
+				conf.recurring = []config.RecurringTask{{Name: "refresh", Task: tc.kind, Schedule: tc.schedule, CatchUp: tc.catchUp, Selector: tc.selector}}
+			}
+
+			var submissions int
+			s, err := scheduler.New(ctx, conf, db, statePath,
+				scheduler.WithPauser(pause.New(tc.paused)),
+				scheduler.WithTask("test-task", func() task.Task {
+					submissions++
+					return testTask{}
+				}))
+			require.NoError(t, err, "Setup: New should return no error")
+
+			s.SubmitIfDue(ctx, now)
+
+			require.Equal(t, tc.wantSubmissions, submissions, "Unexpected number of submissions to the distro")
+
+			got := readLastRuns(t, statePath)
+			switch tc.wantLastRun {
+			case lastRunUnchanged:
+				require.Contains(t, got, "refresh", "The last run of the task should still be recorded")
+				require.True(t, previous.Equal(got["refresh"]), "The last run should not have changed")
+			case lastRunNow:
+				require.Contains(t, got, "refresh", "The last run of the task should be recorded")
+				require.True(t, now.Equal(got["refresh"]), "The last run should have been updated")
+			case lastRunForgotten:
+				require.NotContains(t, got, "refresh", "The last run of a removed task should be forgotten")
+			}
+		})
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	db := setupDatabase(t, ctx)
+	statePath := filepath.Join(t.TempDir(), "recurring.yaml")
+	conf := &mockConfig{recurring: []config.RecurringTask{{Name: "refresh", Task: "test-task", Schedule: "@hourly"}}}
+
+	var submissions int
+	newScheduler := func() *scheduler.Scheduler {
+		s, err := scheduler.New(ctx, conf, db, statePath, scheduler.WithTask("test-task", func() task.Task {
+			submissions++
+			return testTask{}
+		}))
+		require.NoError(t, err, "Setup: New should return no error")
+		return s
+	}
+
+	newScheduler().SubmitIfDue(ctx, now)
+	require.Zero(t, submissions, "A new task should not be submitted before its first scheduled time")
+
+	// Restarting must neither repeat nor forget the runs.
+	newScheduler().SubmitIfDue(ctx, now.Add(time.Minute))
+	require.Zero(t, submissions, "The task should not be submitted before it is due")
+
+	newScheduler().SubmitIfDue(ctx, now.Add(45*time.Minute))
+	require.Equal(t, 1, submissions, "The task should be submitted once it is due")
+
+	newScheduler().SubmitIfDue(ctx, now.Add(46*time.Minute))
+	require.Equal(t, 1, submissions, "The task should not be submitted twice for the same run")
+}
+
+func TestStartStop(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	db := setupDatabase(t, ctx)
+	statePath := filepath.Join(t.TempDir(), "recurring.yaml")
+	conf := &mockConfig{recurring: []config.RecurringTask{{Name: "refresh", Task: "pro-refresh", Schedule: "@weekly"}}}
+
+	s, err := scheduler.New(ctx, conf, db, statePath, scheduler.WithCheckInterval(100*time.Millisecond))
+	require.NoError(t, err, "Setup: New should return no error")
+
+	s.Start()
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(statePath)
+		return err == nil
+	}, 5*time.Second, 100*time.Millisecond, "The scheduler should have recorded the new task")
+
+	s.Stop()
+}
+
+// setupDatabase creates a database with a single distro in it.
+//
+//nolint:revive // We've decided testing.T always preceedes the context.
+func setupDatabase(t *testing.T, ctx context.Context) *database.DistroDB {
+	t.Helper()
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	db, err := database.New(ctx, t.TempDir(), nil)
+	require.NoError(t, err, "Setup: database New should not return an error")
+	t.Cleanup(func() { db.Close(ctx) })
+
+	_, err = db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+	require.NoError(t, err, "Setup: could not add distro to the database")
+
+	return db
+}
+
+func writeLastRuns(t *testing.T, path string, lastRun map[string]time.Time) {
+	t.Helper()
+
+	out, err := yaml.Marshal(lastRun)
+	require.NoError(t, err, "Setup: could not marshal the last runs")
+	require.NoError(t, os.WriteFile(path, out, 0600), "Setup: could not write the last runs")
+}
+
+func readLastRuns(t *testing.T, path string) map[string]time.Time {
+	t.Helper()
+
+	lastRun := make(map[string]time.Time)
+
+	out, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lastRun
+	}
+	require.NoError(t, err, "Could not read the last runs")
+	require.NoError(t, yaml.Unmarshal(out, &lastRun), "Could not parse the last runs")
+
+	return lastRun
+}
+
+type mockConfig struct {
+	recurring []config.RecurringTask
+	configErr bool
+}
+
+func (m mockConfig) RecurringTasks() ([]config.RecurringTask, error) {
+	if m.configErr {
+		return nil, errors.New("mock error")
+	}
+	return m.recurring, nil
+}
+
+type testTask struct{}
+
+func (testTask) Execute(context.Context, wslserviceapi.WSLClient) error {
+	return nil
+}
+
+func (testTask) String() string {
+	return "Test task"
+}
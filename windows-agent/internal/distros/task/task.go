@@ -3,6 +3,7 @@ package task
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
@@ -30,6 +31,88 @@ func Is(t, target Task) bool {
 	return t == target
 }
 
+// urgentTask are tasks that implement the Urgent method.
+type urgentTask interface {
+	Task
+	Urgent() bool
+}
+
+// IsUrgent returns true if the task must run as soon as possible, even outside of the maintenance
+// window. A task is urgent if it implements a method Urgent() bool that returns true.
+func IsUrgent(t Task) bool {
+	if T, ok := t.(urgentTask); ok {
+		return T.Urgent()
+	}
+	return false
+}
+
+// sharedTask are tasks that implement the Shared method.
+type sharedTask interface {
+	Task
+	Shared() bool
+}
+
+// IsShared returns true if the task only reads from the distro, so that it can run while another
+// task is changing it. A task is shared if it implements a method Shared() bool that returns true.
+// Otherwise, it is exclusive: it runs on its own.
+func IsShared(t Task) bool {
+	if T, ok := t.(sharedTask); ok {
+		return T.Shared()
+	}
+	return false
+}
+
+// taskWithDependencies are tasks that implement the DependsOn method.
+type taskWithDependencies interface {
+	Task
+	DependsOn() []Task
+}
+
+// Requires returns true if task "t" cannot run before "prerequisite" succeeds.
+//
+// A task declares its prerequisites by implementing a method DependsOn() []Task. The prerequisite
+// is required if it matches any of them according to Is. Dependencies must not be circular.
+func Requires(t, prerequisite Task) bool {
+	T, ok := t.(taskWithDependencies)
+	if !ok {
+		return false
+	}
+
+	for _, dep := range T.DependsOn() {
+		if Is(prerequisite, dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// Submitter enqueues tasks on a distro. See SubmitFollowUps.
+type Submitter interface {
+	SubmitTasks(...Task) error
+}
+
+type submitterKey struct{}
+
+// WithSubmitter returns a context that lets the tasks executed with it submit follow-up tasks via
+// the submitter. It is meant for the worker that executes the tasks.
+func WithSubmitter(ctx context.Context, s Submitter) context.Context {
+	return context.WithValue(ctx, submitterKey{}, s)
+}
+
+// ErrNoSubmitter is returned by SubmitFollowUps when the context does not come from a worker.
+var ErrNoSubmitter = errors.New("follow-up tasks cannot be submitted from this context")
+
+// SubmitFollowUps enqueues tasks on the distro that runs the task being executed with this context,
+// e.g. to report on what the task did. They are queued right away, regardless of the outcome of the
+// current task, and run after it.
+func SubmitFollowUps(ctx context.Context, tasks ...Task) error {
+	s, ok := ctx.Value(submitterKey{}).(Submitter)
+	if !ok {
+		return ErrNoSubmitter
+	}
+	return s.SubmitTasks(tasks...)
+}
+
 // NeedsRetryError is an error that should be emitted by tasks that, in case of failure,
 // should be retried at the next startup sequence.
 type NeedsRetryError struct {
@@ -39,3 +122,8 @@ type NeedsRetryError struct {
 func (e NeedsRetryError) Error() string {
 	return fmt.Sprintf("failed but will be retried: %v", e.SourceErr)
 }
+
+// Unwrap returns the error that caused the task to fail.
+func (e NeedsRetryError) Unwrap() error {
+	return e.SourceErr
+}
@@ -2,8 +2,10 @@ package task_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
@@ -35,6 +37,66 @@ func TestRegistry(t *testing.T) {
 	require.ElementsMatch(t, want, got, "registry should contain only the registered tasks")
 }
 
+func TestRequires(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		task         task.Task
+		prerequisite task.Task
+
+		want bool
+	}{
+		"Task that depends on the prerequisite requires it":    {task: dependentTask{}, prerequisite: emptyTask{}, want: true},
+		"Task that depends on other tasks does not require it": {task: dependentTask{}, prerequisite: testTask{}},
+		"Task without dependencies does not require anything":  {task: emptyTask{}, prerequisite: testTask{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := task.Requires(tc.task, tc.prerequisite)
+			require.Equal(t, tc.want, got, "Requires returned an unexpected value")
+		})
+	}
+}
+
+func TestSubmitFollowUps(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noSubmitter  bool
+		submitterErr bool
+
+		wantErr error
+	}{
+		"Success submitting via the submitter of the context": {},
+
+		"Error when the context has no submitter": {noSubmitter: true, wantErr: task.ErrNoSubmitter},
+		"Error when the submitter fails":          {submitterErr: true, wantErr: errMockSubmitter},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			s := &submitterMock{fail: tc.submitterErr}
+			if !tc.noSubmitter {
+				ctx = task.WithSubmitter(ctx, s)
+			}
+
+			err := task.SubmitFollowUps(ctx, emptyTask{}, testTask{Number: 1})
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr, "SubmitFollowUps should return the expected error")
+				return
+			}
+			require.NoError(t, err, "SubmitFollowUps should return no error")
+			require.Equal(t, []task.Task{emptyTask{}, testTask{Number: 1}}, s.submitted, "The tasks should have been passed to the submitter")
+		})
+	}
+}
+
 //nolint:tparallel // Cannot make test parallel because of BackupRegistry.
 func TestMarshal(t *testing.T) {
 	task.BackupRegistry(t)
@@ -142,6 +204,48 @@ func TestMarsallUnmarshall(t *testing.T) {
 	}
 }
 
+//nolint:tparallel // Cannot make test parallel because of BackupRegistry.
+func TestMarshalUnmarshalQueue(t *testing.T) {
+	task.BackupRegistry(t)
+	task.Register[testTask]()
+	task.Register[emptyTask]()
+
+	testCases := map[string]struct {
+		input task.Queued
+	}{
+		"Queued task with its bookkeeping": {input: task.Queued{
+			Task:      testTask{Message: "Hello, world!", Number: 42},
+			ID:        "2b0e4f2c-6dd5-4c43-a1a8-8bd0f1b4e4f6",
+			Submitted: time.Date(2024, time.March, 6, 10, 17, 42, 0, time.UTC),
+			Attempts:  3,
+		}},
+		"Queued task without bookkeeping": {input: task.Queued{Task: emptyTask{}}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			serial, err := task.MarshalQueueYAML([]task.Queued{tc.input})
+			require.NoError(t, err, "input task should marshal with no errors")
+
+			got, err := task.UnmarshalQueueYAML(serial)
+			require.NoError(t, err, "Registered task should not fail to unmarshal")
+
+			require.Len(t, got, 1, "One and only one task was expected")
+			require.Equal(t, tc.input.Task, got[0].Task, "The task should be the same after marshaling and unmarshaling")
+			require.Equal(t, tc.input.ID, got[0].ID, "The ID should be the same after marshaling and unmarshaling")
+			require.True(t, tc.input.Submitted.Equal(got[0].Submitted), "The submission time should be the same after marshaling and unmarshaling")
+			require.Equal(t, tc.input.Attempts, got[0].Attempts, "The attempts should be the same after marshaling and unmarshaling")
+
+			// Tasks stored with their bookkeeping can still be read as plain tasks.
+			plain, err := task.UnmarshalYAML(serial)
+			require.NoError(t, err, "Queued tasks should unmarshal as plain tasks")
+			require.Equal(t, []task.Task{tc.input.Task}, plain, "The plain task should be the queued one")
+		})
+	}
+}
+
 type testTask struct {
 	Message string
 	Number  uint64
@@ -155,6 +259,15 @@ type emptyTask struct {
 	DummyImplementer `yaml:"-"`
 }
 
+// dependentTask cannot run before an emptyTask succeeds.
+type dependentTask struct {
+	DummyImplementer `yaml:"-"`
+}
+
+func (dependentTask) DependsOn() []task.Task {
+	return []task.Task{emptyTask{}}
+}
+
 type unregisteredTask struct {
 	Score int
 
@@ -167,3 +280,19 @@ type DummyImplementer struct{}
 func (DummyImplementer) Execute(context.Context, wslserviceapi.WSLClient) error {
 	return nil
 }
+
+var errMockSubmitter = errors.New("mock error")
+
+// submitterMock records the tasks it is asked to submit.
+type submitterMock struct {
+	fail      bool
+	submitted []task.Task
+}
+
+func (s *submitterMock) SubmitTasks(tasks ...task.Task) error {
+	if s.fail {
+		return errMockSubmitter
+	}
+	s.submitted = append(s.submitted, tasks...)
+	return nil
+}
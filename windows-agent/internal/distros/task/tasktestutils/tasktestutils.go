@@ -0,0 +1,178 @@
+// Package tasktestutils implements ready-made tasks and assertions to test code that submits
+// tasks to distros, or custom tasks, without having to write a fake task every time.
+package tasktestutils
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	task.Register[*RecordingTask]()
+	task.Register[*FailingTask]()
+	task.Register[*BlockingTask]()
+}
+
+// Recorder is a task that keeps count of its executions.
+type Recorder interface {
+	task.Task
+	Executions() int
+}
+
+// recorder counts the executions of a task. It is meant to be embedded.
+type recorder struct {
+	executions atomic.Int32
+}
+
+// Executions returns how many times the task has been executed.
+func (r *recorder) Executions() int {
+	return int(r.executions.Load())
+}
+
+// RecordingTask is a task that succeeds right away, and records its executions.
+type RecordingTask struct {
+	recorder
+
+	// ID tells tasks apart: two recording tasks are equivalent if they have the same ID.
+	ID string
+}
+
+// MarshalYAML is necessary to avoid races between Execute and the task storage.
+func (t *RecordingTask) MarshalYAML() (interface{}, error) {
+	return struct{ ID string }{ID: t.ID}, nil
+}
+
+// Execute records the execution and returns no error.
+func (t *RecordingTask) Execute(context.Context, wslserviceapi.WSLClient) error {
+	t.executions.Add(1)
+	return nil
+}
+
+func (t *RecordingTask) String() string {
+	return "Recording test task " + t.ID
+}
+
+// Is returns true if the other task is a recording task with the same ID.
+func (t *RecordingTask) Is(other task.Task) bool {
+	o, ok := other.(*RecordingTask)
+	if !ok {
+		return false
+	}
+	return t.ID == o.ID
+}
+
+// FailingTask is a task that returns an error, and records its executions.
+type FailingTask struct {
+	recorder
+
+	// Err is the error returned by Execute. It is not kept in the task storage.
+	Err error
+
+	// Retry makes the error a task.NeedsRetryError, so that the task is retried at the next startup.
+	Retry bool
+
+	// FailTimes is how many executions fail before the task succeeds. Zero means it always fails.
+	FailTimes int
+}
+
+// MarshalYAML is necessary to avoid races between Execute and the task storage.
+func (t *FailingTask) MarshalYAML() (interface{}, error) {
+	return struct {
+		Retry     bool
+		FailTimes int
+	}{Retry: t.Retry, FailTimes: t.FailTimes}, nil
+}
+
+// Execute records the execution and returns the error, unless it has already failed FailTimes times.
+func (t *FailingTask) Execute(context.Context, wslserviceapi.WSLClient) error {
+	n := int(t.executions.Add(1))
+	if t.FailTimes > 0 && n > t.FailTimes {
+		return nil
+	}
+
+	if t.Retry {
+		return task.NeedsRetryError{SourceErr: t.Err}
+	}
+	return t.Err
+}
+
+func (t *FailingTask) String() string {
+	return "Failing test task"
+}
+
+// BlockingTask is a task that runs until it is released or its context is cancelled, and records
+// its executions. Create it with NewBlockingTask.
+type BlockingTask struct {
+	recorder
+
+	release  chan struct{}
+	once     sync.Once
+	canceled atomic.Bool
+}
+
+// NewBlockingTask creates a BlockingTask.
+func NewBlockingTask() *BlockingTask {
+	return &BlockingTask{release: make(chan struct{})}
+}
+
+// MarshalYAML is necessary to avoid races between Execute and the task storage.
+func (t *BlockingTask) MarshalYAML() (interface{}, error) {
+	return struct{}{}, nil
+}
+
+// Execute records the execution and blocks until the task is released or the context is cancelled.
+func (t *BlockingTask) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	t.executions.Add(1)
+
+	// Tasks read from the task storage were not created with NewBlockingTask: their nil channel
+	// blocks forever.
+	select {
+	case <-t.release:
+		return nil
+	case <-ctx.Done():
+		t.canceled.Store(true)
+		return ctx.Err()
+	}
+}
+
+// Release makes the execution in progress, and any later one, return no error.
+func (t *BlockingTask) Release() {
+	if t.release == nil {
+		return
+	}
+	t.once.Do(func() { close(t.release) })
+}
+
+// WasCancelled returns true if an execution was interrupted by its context.
+func (t *BlockingTask) WasCancelled() bool {
+	return t.canceled.Load()
+}
+
+func (t *BlockingTask) String() string {
+	return "Blocking test task"
+}
+
+// WaitForExecution fails the test if the task has not been executed at least n times before the timeout.
+func WaitForExecution(t *testing.T, r Recorder, n int, timeout time.Duration) {
+	t.Helper()
+
+	require.Eventuallyf(t, func() bool {
+		return r.Executions() >= n
+	}, timeout, 10*time.Millisecond, "Task %q should have been executed at least %d times", r, n)
+}
+
+// RequireRetried fails the test if the task has not been executed again after its first execution before the timeout.
+func RequireRetried(t *testing.T, r Recorder, timeout time.Duration) {
+	t.Helper()
+
+	require.Eventuallyf(t, func() bool {
+		return r.Executions() > 1
+	}, timeout, 10*time.Millisecond, "Task %q should have been retried", r)
+}
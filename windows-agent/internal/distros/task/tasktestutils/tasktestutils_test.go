@@ -0,0 +1,114 @@
+package tasktestutils_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task/tasktestutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailingTask(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		retry     bool
+		failTimes int
+
+		wantErrs []bool
+	}{
+		"Always fails":                        {wantErrs: []bool{true, true, true}},
+		"Fails a number of times then passes": {failTimes: 2, wantErrs: []bool{true, true, false}},
+		"Fails with an error to be retried":   {retry: true, wantErrs: []bool{true, true}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tk := &tasktestutils.FailingTask{Err: errors.New("mock error"), Retry: tc.retry, FailTimes: tc.failTimes}
+
+			for i, wantErr := range tc.wantErrs {
+				err := tk.Execute(context.Background(), nil)
+				if !wantErr {
+					require.NoError(t, err, "Execution %d should return no error", i)
+					continue
+				}
+				require.Error(t, err, "Execution %d should return an error", i)
+				require.Equal(t, tc.retry, errors.As(err, &task.NeedsRetryError{}), "Execution %d returned an unexpected kind of error", i)
+			}
+
+			tasktestutils.WaitForExecution(t, tk, len(tc.wantErrs), time.Second)
+			tasktestutils.RequireRetried(t, tk, time.Second)
+		})
+	}
+}
+
+func TestBlockingTask(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		cancel bool
+
+		wantErr bool
+	}{
+		"Returns when released": {},
+
+		"Error when the context is cancelled": {cancel: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tk := tasktestutils.NewBlockingTask()
+			errs := make(chan error)
+			go func() { errs <- tk.Execute(ctx, nil) }()
+
+			tasktestutils.WaitForExecution(t, tk, 1, time.Second)
+			select {
+			case <-errs:
+				require.Fail(t, "Execute should block until released or cancelled")
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			if tc.cancel {
+				cancel()
+			} else {
+				tk.Release()
+			}
+
+			err := <-errs
+			require.Equal(t, tc.cancel, tk.WasCancelled(), "WasCancelled returned an unexpected value")
+			if tc.wantErr {
+				require.Error(t, err, "Execute should return an error")
+				return
+			}
+			require.NoError(t, err, "Execute should return no error")
+		})
+	}
+}
+
+func TestTaskStorage(t *testing.T) {
+	t.Parallel()
+
+	tasks := []task.Task{
+		&tasktestutils.RecordingTask{ID: "A"},
+		&tasktestutils.FailingTask{Retry: true, FailTimes: 2},
+		tasktestutils.NewBlockingTask(),
+	}
+
+	out, err := task.MarshalYAML(tasks)
+	require.NoError(t, err, "MarshalYAML should return no error")
+
+	got, err := task.UnmarshalYAML(out)
+	require.NoError(t, err, "The test tasks should be registered and be read back from the task storage")
+	require.Len(t, got, len(tasks), "All tasks should have been read back")
+	require.True(t, task.Is(got[0], tasks[0]), "The recording task should keep its ID")
+}
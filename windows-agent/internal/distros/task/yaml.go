@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/ubuntu/decorate"
 	"gopkg.in/yaml.v3"
@@ -24,36 +25,85 @@ func Register[T Task]() {
 	}
 }
 
+// Queued is a task waiting in a queue, along with when it was submitted and how many times it ran.
+type Queued struct {
+	Task Task
+
+	// ID identifies the task for as long as it is queued.
+	ID string
+
+	// Submitted is when the task was first submitted.
+	Submitted time.Time
+
+	// Attempts is how many times the task started running.
+	Attempts int
+}
+
 type yamlTaskHelper struct {
 	Task Task
 	Type string
+
+	ID        string    `yaml:",omitempty"`
+	Submitted time.Time `yaml:",omitempty"`
+	Attempts  int       `yaml:",omitempty"`
 }
 
 // MarshalYAML marshals a slice of tasks in YAML format.
 func MarshalYAML(tasks []Task) (out []byte, err error) {
+	queued := make([]Queued, 0, len(tasks))
+	for _, t := range tasks {
+		queued = append(queued, Queued{Task: t})
+	}
+
+	return MarshalQueueYAML(queued)
+}
+
+// UnmarshalYAML unmarshals a slice of tasks from a YAML document.
+func UnmarshalYAML(in []byte) (tasks []Task, err error) {
+	queued, err := UnmarshalQueueYAML(in)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range queued {
+		tasks = append(tasks, q.Task)
+	}
+	return tasks, nil
+}
+
+// MarshalQueueYAML marshals a slice of queued tasks in YAML format.
+func MarshalQueueYAML(queued []Queued) (out []byte, err error) {
 	var tmp []yamlTaskHelper
-	for i := range tasks {
-		t := tasks[i]
+	for _, q := range queued {
 		tmp = append(tmp, yamlTaskHelper{
-			Type: reflect.TypeOf(t).String(),
-			Task: t,
+			Type:      reflect.TypeOf(q.Task).String(),
+			Task:      q.Task,
+			ID:        q.ID,
+			Submitted: q.Submitted,
+			Attempts:  q.Attempts,
 		})
 	}
 
 	return yaml.Marshal(tmp)
 }
 
-// UnmarshalYAML unmarshals a slice of tasks from a YAML document.
-func UnmarshalYAML(in []byte) (tasks []Task, err error) {
+// UnmarshalQueueYAML unmarshals a slice of queued tasks from a YAML document. The bookkeeping of
+// tasks stored without it is left empty.
+func UnmarshalQueueYAML(in []byte) (queued []Queued, err error) {
 	var tmp []yamlTaskHelper
 	if err := yaml.Unmarshal(in, &tmp); err != nil {
 		return nil, err
 	}
 
-	for i := range tmp {
-		tasks = append(tasks, tmp[i].Task)
+	for _, t := range tmp {
+		queued = append(queued, Queued{
+			Task:      t.Task,
+			ID:        t.ID,
+			Submitted: t.Submitted,
+			Attempts:  t.Attempts,
+		})
 	}
-	return tasks, nil
+	return queued, nil
 }
 
 // UnmarshalYAML overrides the unmarshalling behaviour of yamlTaskHelper so that
@@ -62,6 +112,10 @@ func (t *yamlTaskHelper) UnmarshalYAML(node *yaml.Node) error {
 	var tmp struct {
 		Type string
 		Task rawTask
+
+		ID        string
+		Submitted time.Time
+		Attempts  int
 	}
 
 	err := node.Decode(&tmp)
@@ -74,6 +128,10 @@ func (t *yamlTaskHelper) UnmarshalYAML(node *yaml.Node) error {
 		return err
 	}
 
+	t.ID = tmp.ID
+	t.Submitted = tmp.Submitted
+	t.Attempts = tmp.Attempts
+
 	return nil
 }
 
@@ -0,0 +1,26 @@
+// Package vhdx locates the virtual disks of the distros.
+package vhdx
+
+import (
+	"os"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/ubuntu/decorate"
+)
+
+// Size returns the size of the virtual disk of the distro.
+func Size(d *distro.Distro) (size uint64, err error) {
+	defer decorate.OnError(&err, "could not get the size of the virtual disk")
+
+	path, err := Path(d)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(info.Size()), nil
+}
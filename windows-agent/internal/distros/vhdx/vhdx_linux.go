@@ -0,0 +1,12 @@
+package vhdx
+
+import (
+	"errors"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+)
+
+// Path returns the path to the virtual disk of the distro, which only exists on Windows.
+func Path(*distro.Distro) (string, error) {
+	return "", errors.New("virtual disks are not supported on Linux")
+}
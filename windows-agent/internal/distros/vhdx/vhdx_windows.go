@@ -0,0 +1,40 @@
+package vhdx
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/ubuntu/decorate"
+	"golang.org/x/sys/windows/registry"
+)
+
+// lxssKey is where WSL registers the distros, under HKEY_CURRENT_USER.
+const lxssKey = `Software\Microsoft\Windows\CurrentVersion\Lxss`
+
+// Path returns the path to the virtual disk of the distro, as registered by WSL.
+func Path(d *distro.Distro) (path string, err error) {
+	defer decorate.OnError(&err, "could not find the virtual disk of distro %q", d.Name())
+
+	k, err := registry.OpenKey(registry.CURRENT_USER, fmt.Sprintf(`%s\%s`, lxssKey, d.GUID().Braced()), registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer k.Close()
+
+	basePath, _, err := k.GetStringValue("BasePath")
+	if err != nil {
+		return "", err
+	}
+	// The base path may be in extended-length format.
+	basePath = strings.TrimPrefix(basePath, `\\?\`)
+
+	// Distros registered by older versions of WSL do not declare their disk name.
+	name, _, err := k.GetStringValue("VhdFileName")
+	if err != nil {
+		name = "ext4.vhdx"
+	}
+
+	return filepath.Join(basePath, name), nil
+}
@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// longCalls are the RPCs that change the state of the distro. They may take a while, as they run
+// apt or pro, which lock each other out inside of the distro.
+var longCalls = map[string]bool{
+	wslserviceapi.WSL_ApplyProToken_FullMethodName:        true,
+	wslserviceapi.WSL_ApplyLandscapeConfig_FullMethodName: true,
+	wslserviceapi.WSL_ApplyWSLSettings_FullMethodName:     true,
+	wslserviceapi.WSL_ProRefresh_FullMethodName:           true,
+}
+
+// callQueue ensures that only one long-running RPC is made to a distro at a time, regardless of
+// which connection is used. Short RPCs are not queued. Every RPC is given a timeout budget.
+type callQueue struct {
+	slot chan struct{}
+
+	// shortBudget and longBudget are how long the RPCs may take, depending on whether they are
+	// long-running or not. The time spent waiting for the turn of a long-running RPC is not counted.
+	shortBudget time.Duration
+	longBudget  time.Duration
+
+	// budgets are how long the RPCs that are not queued but take longer than a short one may take.
+	budgets map[string]time.Duration
+}
+
+func newCallQueue(policy timeouts.Policy) *callQueue {
+	return &callQueue{
+		slot:        make(chan struct{}, 1),
+		shortBudget: policy.ShortCall,
+		longBudget:  policy.LongCall,
+		budgets: map[string]time.Duration{
+			// It requires reading the apt cache of the distro.
+			wslserviceapi.WSL_GetSecurityStatus_FullMethodName: policy.SecurityStatus,
+		},
+	}
+}
+
+// client returns a WSL client that makes its calls via the queue.
+func (q *callQueue) client(conn grpc.ClientConnInterface) wslserviceapi.WSLClient {
+	return wslserviceapi.NewWSLClient(queuedConn{ClientConnInterface: conn, queue: q})
+}
+
+// acquire waits for the turn of a long-running RPC. Call release when the RPC is done.
+func (q *callQueue) acquire(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case q.slot <- struct{}{}:
+		return nil
+	}
+}
+
+func (q *callQueue) release() {
+	<-q.slot
+}
+
+// queuedConn is a gRPC connection whose unary calls go through a callQueue.
+type queuedConn struct {
+	grpc.ClientConnInterface
+	queue *callQueue
+}
+
+// Invoke makes the RPC once it is its turn, cancelling it if it exceeds its budget.
+func (c queuedConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	budget := c.queue.shortBudget
+	if b, ok := c.queue.budgets[method]; ok {
+		budget = b
+	}
+
+	if longCalls[method] {
+		if err := c.queue.acquire(ctx); err != nil {
+			return err
+		}
+		defer c.queue.release()
+
+		budget = c.queue.longBudget
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	return c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+}
@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+)
+
+var (
+	// circuitThreshold is how many tasks must fail in a row for the circuit to open.
+	circuitThreshold = 5
+
+	// circuitRetryInterval is how long tasks are held back once the circuit is open, before a
+	// single task is let through to check if the distro works again.
+	circuitRetryInterval = 6 * time.Hour
+)
+
+// CircuitState tells whether the tasks of the distro are held back because too many of them failed in a row.
+type CircuitState struct {
+	// Open is true while tasks are held back. The distro needs the attention of the user.
+	Open bool
+
+	// Failures is the number of tasks that failed in a row.
+	Failures int
+
+	// LastError is the error of the last task that failed.
+	LastError error
+
+	// RetryAt is when the next task is let through. It is zero while the circuit is closed.
+	RetryAt time.Time
+}
+
+// circuitBreaker stops the worker from processing tasks after too many of them failed in a row, so
+// that a broken distro is not woken up over and over. While open, a single task is let through
+// every circuitRetryInterval: if it succeeds, the circuit closes again.
+type circuitBreaker struct {
+	clock clock.Clock
+	state CircuitState
+	mu    sync.Mutex
+}
+
+// State returns the state of the circuit.
+func (c *circuitBreaker) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+// wait blocks until the circuit lets a task through, or the context is done.
+func (c *circuitBreaker) wait(ctx context.Context) error {
+	c.mu.Lock()
+	retryAt := c.state.RetryAt
+	c.mu.Unlock()
+
+	d := retryAt.Sub(c.clock.Now())
+	if d <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.clock.After(d):
+		return nil
+	}
+}
+
+// record updates the circuit with the outcome of a task. It returns true if the circuit just opened
+// or stayed open.
+func (c *circuitBreaker) record(taskErr error) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if taskErr == nil {
+		c.state = CircuitState{}
+		return false
+	}
+
+	c.state.Failures++
+	c.state.LastError = taskErr
+
+	if c.state.Failures < circuitThreshold {
+		return false
+	}
+
+	c.state.Open = true
+	c.state.RetryAt = c.clock.Now().Add(circuitRetryInterval)
+	return true
+}
@@ -2,8 +2,20 @@ package worker
 
 import (
 	"fmt"
+	"testing"
+	"time"
 )
 
+// SetCircuitBreaker overrides how many tasks must fail in a row for the circuit to open, and how long
+// tasks are held back then. Tests using it cannot run in parallel.
+func SetCircuitBreaker(t *testing.T, threshold int, retry time.Duration) {
+	t.Helper()
+
+	origThreshold, origRetry := circuitThreshold, circuitRetryInterval
+	circuitThreshold, circuitRetryInterval = threshold, retry
+	t.Cleanup(func() { circuitThreshold, circuitRetryInterval = origThreshold, origRetry })
+}
+
 // CheckQueuedTaskCount checks that the number of tasks in the queue matches expectations.
 func (w *Worker) CheckQueuedTaskCount(want int) error {
 	if got := w.manager.QueueLen(); got != want {
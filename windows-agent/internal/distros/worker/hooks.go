@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+)
+
+// Hooks let observability sinks, such as metrics, tracing or an event bus, follow the tasks of a
+// distro without changes to the worker. Every hook is optional.
+//
+// Hooks are called synchronously by the worker: they must return quickly, and must not call it back.
+type Hooks struct {
+	// OnEnqueue is called when a task is submitted, deferred or not.
+	OnEnqueue func(distro string, t QueuedTask)
+
+	// OnStart is called when a task starts running.
+	OnStart func(ctx context.Context, distro string, t QueuedTask)
+
+	// OnFinish is called when a task that ran is done for good, with how long its last run took. The
+	// error is nil if the task succeeded.
+	OnFinish func(ctx context.Context, distro string, t QueuedTask, took time.Duration, err error)
+
+	// OnRetry is called when a task that ran is put back in the queue, with the error that kept it from
+	// completing.
+	OnRetry func(ctx context.Context, distro string, t QueuedTask, err error)
+}
+
+// hookList calls every set of hooks in the order they were passed to the worker.
+type hookList []Hooks
+
+func (l hookList) enqueue(distro string, queued []*task.Queued, deferred bool) {
+	for _, h := range l {
+		if h.OnEnqueue == nil {
+			continue
+		}
+		for _, t := range queued {
+			h.OnEnqueue(distro, newQueuedTask(t, deferred))
+		}
+	}
+}
+
+func (l hookList) start(ctx context.Context, distro string, t *task.Queued) {
+	for _, h := range l {
+		if h.OnStart != nil {
+			h.OnStart(ctx, distro, newQueuedTask(t, false))
+		}
+	}
+}
+
+func (l hookList) finish(ctx context.Context, distro string, t *task.Queued, took time.Duration, err error) {
+	for _, h := range l {
+		if h.OnFinish != nil {
+			h.OnFinish(ctx, distro, newQueuedTask(t, false), took, err)
+		}
+	}
+}
+
+func (l hookList) retry(ctx context.Context, distro string, t *task.Queued, err error) {
+	for _, h := range l {
+		if h.OnRetry != nil {
+			h.OnRetry(ctx, distro, newQueuedTask(t, false), err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HistorySize is how many finished tasks are remembered per distro.
+const HistorySize = 50
+
+// CancelReason explains why a task stopped before it could complete.
+type CancelReason string
+
+const (
+	// CancelAgentShutdown means that the agent stopped while the task was running.
+	CancelAgentShutdown CancelReason = "agent shutdown"
+
+	// CancelDistroUnregistered means that the distro was unregistered while the task was running.
+	CancelDistroUnregistered CancelReason = "distro unregistered"
+
+	// CancelSuperseded means that the task was replaced by an equivalent one before it ran.
+	CancelSuperseded CancelReason = "superseded by a newer task"
+
+	// CancelTimeout means that the distro took too long to answer the task.
+	CancelTimeout CancelReason = "timed out"
+
+	// CancelUser means that the user cancelled the task before it ran.
+	CancelUser CancelReason = "cancelled by the user"
+)
+
+// CancelledError is returned when a task was cancelled, rather than failing on its own.
+type CancelledError struct {
+	Reason    CancelReason
+	SourceErr error
+}
+
+func (err CancelledError) Error() string {
+	if err.SourceErr == nil {
+		return fmt.Sprintf("task cancelled: %s", err.Reason)
+	}
+	return fmt.Sprintf("task cancelled: %s: %v", err.Reason, err.SourceErr)
+}
+
+func (err CancelledError) Unwrap() error {
+	return err.SourceErr
+}
+
+// cancelReason returns why the task stopped with the given error, if it was cancelled.
+// The reason for cancelling the context is stored as its cause.
+func cancelReason(ctx context.Context, err error) (CancelReason, bool) {
+	if ctx.Err() != nil {
+		var cancelled CancelledError
+		if errors.As(context.Cause(ctx), &cancelled) {
+			return cancelled.Reason, true
+		}
+		return CancelAgentShutdown, true
+	}
+
+	if status.Code(err) == codes.DeadlineExceeded || errors.Is(err, context.DeadlineExceeded) {
+		return CancelTimeout, true
+	}
+
+	return "", false
+}
+
+// PolicyDeniedError is returned when the policy of the distro does not allow the task. It is not retried,
+// as it would be denied again until an administrator of the distro changes the policy.
+type PolicyDeniedError struct {
+	// Call is the call of the WSL Pro service that was denied.
+	Call string
+
+	// Reason is why the policy denies the call, as set by the administrator of the distro. It may be empty.
+	Reason string
+
+	SourceErr error
+}
+
+func (err PolicyDeniedError) Error() string {
+	return fmt.Sprintf("task denied by the policy of the distro: %v", err.SourceErr)
+}
+
+func (err PolicyDeniedError) Unwrap() error {
+	return err.SourceErr
+}
+
+// TaskRecord is the outcome of a task that left the queue for good.
+type TaskRecord struct {
+	// Task is the description of the task.
+	Task string
+
+	// Finished is when the task left the queue.
+	Finished time.Time
+
+	// Err is the error the task stopped with. It is empty if the task succeeded.
+	Err string
+
+	// Cancelled is the reason the task was cancelled. It is empty if the task was not.
+	Cancelled CancelReason
+
+	// PolicyDenied is true if the policy of the distro does not allow the task.
+	PolicyDenied bool
+}
+
+// taskHistory remembers the outcome of the last tasks.
+type taskHistory struct {
+	clock   clock.Clock
+	records []TaskRecord
+	mu      sync.RWMutex
+}
+
+// add records the outcome of a task.
+func (h *taskHistory) add(t task.Task, taskErr error) {
+	r := TaskRecord{
+		Task:     fmt.Sprint(t),
+		Finished: h.clock.Now(),
+	}
+
+	if taskErr != nil {
+		r.Err = taskErr.Error()
+	}
+
+	var cancelled CancelledError
+	if errors.As(taskErr, &cancelled) {
+		r.Cancelled = cancelled.Reason
+	}
+
+	r.PolicyDenied = errors.As(taskErr, &PolicyDeniedError{})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records = append(h.records, r)
+	if over := len(h.records) - HistorySize; over > 0 {
+		h.records = append([]TaskRecord{}, h.records[over:]...)
+	}
+}
+
+// list returns the recorded outcomes, from oldest to newest.
+func (h *taskHistory) list() []TaskRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return append([]TaskRecord{}, h.records...)
+}
@@ -4,12 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io/fs"
-	"os"
 	"sync"
+	"time"
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/google/uuid"
 	"github.com/ubuntu/decorate"
 )
 
@@ -23,20 +24,67 @@ import (
 // which is set to private because it is a freestanding function and we don't
 // want outside packages to be able to use it.
 type taskManager struct {
-	storagePath string
+	storage TaskStorage
+	clock   clock.Clock
 
 	tasks         *taskQueue
 	deferredTasks *taskQueue
 
+	// history remembers the tasks that left the queue for good, and why.
+	history taskHistory
+
 	mu sync.RWMutex
 }
 
+// ErrTaskNotQueued is returned when cancelling a task that is not queued, either because it does not
+// exist, or because it is running or done already.
+var ErrTaskNotQueued = errors.New("task is not queued")
+
+// QueuedTask describes a task waiting in the queue of a distro.
+type QueuedTask struct {
+	// ID identifies the task for as long as it is queued.
+	ID string
+
+	// Task is the description of the task.
+	Task string
+
+	// Deferred is true if the task waits for the distro to be started by someone else.
+	Deferred bool
+
+	// Submitted is when the task was first submitted. It is zero if unknown.
+	Submitted time.Time
+
+	// Attempts is how many times the task started running.
+	Attempts int
+}
+
+func newQueuedTask(t *task.Queued, deferred bool) QueuedTask {
+	return QueuedTask{
+		ID:        t.ID,
+		Task:      fmt.Sprint(t.Task),
+		Deferred:  deferred,
+		Submitted: t.Submitted,
+		Attempts:  t.Attempts,
+	}
+}
+
+// newQueued wraps a task that is being submitted.
+func (tm *taskManager) newQueued(t task.Task) *task.Queued {
+	return &task.Queued{
+		Task:      t,
+		ID:        uuid.NewString(),
+		Submitted: tm.clock.Now(),
+	}
+}
+
 // newTaskManager constructs and initializes a TaskManager.
-func newTaskManager(storagePath string) (*taskManager, error) {
+func newTaskManager(storage TaskStorage, c clock.Clock) (*taskManager, error) {
 	tm := taskManager{
-		storagePath:   storagePath,
+		storage:       storage,
+		clock:         c,
 		tasks:         newTaskQueue(),
 		deferredTasks: newTaskQueue(),
+		history:       taskHistory{clock: c},
 	}
 
 	if err := tm.load(); err != nil {
@@ -66,7 +114,9 @@ func (tm *taskManager) TaskLen() int {
 //
 // If deferred is set to true, task execution is deferred until the next load()
 // Otherwise, it is added to the queue immediately.
-func (tm *taskManager) Submit(deferred bool, tasks ...task.Task) error {
+//
+// The queued tasks are returned even if they could not be stored.
+func (tm *taskManager) Submit(deferred bool, tasks ...task.Task) ([]*task.Queued, error) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
@@ -74,7 +124,7 @@ func (tm *taskManager) Submit(deferred bool, tasks ...task.Task) error {
 }
 
 // submitUnsafe is the thread-unsafe version of Submit.
-func (tm *taskManager) submitUnsafe(deferred bool, tasks ...task.Task) (err error) {
+func (tm *taskManager) submitUnsafe(deferred bool, tasks ...task.Task) (queued []*task.Queued, err error) {
 	defer decorate.OnError(&err, "could not submit task")
 
 	thisQueue := &tm.tasks
@@ -84,105 +134,217 @@ func (tm *taskManager) submitUnsafe(deferred bool, tasks ...task.Task) (err erro
 	}
 
 	for i := range tasks {
-		(*otherQueue).Remove(tasks[i])
-		(*thisQueue).Push(tasks[i])
+		q := tm.newQueued(tasks[i])
+		queued = append(queued, q)
+
+		superseded := (*otherQueue).Remove(tasks[i])
+		superseded = append(superseded, (*thisQueue).Push(q)...)
+
+		for _, old := range superseded {
+			tm.history.add(old.Task, CancelledError{Reason: CancelSuperseded})
+		}
 	}
 
-	return tm.save()
+	return queued, tm.save()
 }
 
 // resubmit submits a task with lowest priority, meaning that it will be overridden
-// by any equivalent already in the queue.
-func (tm *taskManager) resubmit(t task.Task) (err error) {
+// by any equivalent already in the queue. Queued tasks that depend on it are deferred
+// along with it.
+func (tm *taskManager) resubmit(t *task.Queued) (err error) {
 	defer decorate.OnError(&err, "could not re-submit task")
 
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	if tm.tasks.Contains(t) {
+	if tm.tasks.Contains(t.Task) {
 		// No need to resubmit
 		return nil
 	}
 	tm.deferredTasks.PushIfNew(t)
 
+	// Dependents must wait for the task to be retried.
+	for _, dependent := range tm.tasks.RemoveDependents(t.Task) {
+		tm.deferredTasks.PushIfNew(dependent)
+	}
+
+	return tm.save()
+}
+
+// Requeue puts back a task that could not be completed in the queue, unless an equivalent task
+// was submitted in the meantime.
+func (tm *taskManager) Requeue(t *task.Queued) (err error) {
+	defer decorate.OnError(&err, "could not requeue task %s", t.Task)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if !tm.deferredTasks.Contains(t.Task) {
+		tm.tasks.PushIfNew(t)
+	}
+
 	return tm.save()
 }
 
-// NextTask pulls the next task from the queue. If no task is queued, this function blocks until either a task is
-// submitted or the context is cancelled, whichever happens first.
-// The second argument indicates whether a task was pulled or not.
-func (tm *taskManager) NextTask(ctx context.Context) (task.Task, bool) {
-	t := tm.tasks.Pull(ctx)
-	return t, t != nil
+// NextTask pulls the next task accepted by the filter from the queue. A nil filter accepts every task.
+// If no such task is queued, this function blocks until either a task is submitted or the context is
+// cancelled, whichever happens first.
+// The second argument indicates whether a task was pulled or not. Pulling a task counts as an attempt
+// to run it.
+func (tm *taskManager) NextTask(ctx context.Context, accept func(task.Task) bool) (*task.Queued, bool) {
+	t := tm.tasks.Pull(ctx, accept)
+	if t == nil {
+		return nil, false
+	}
+
+	t.Attempts++
+	return t, true
 }
 
 // TaskDone cleans up after a task is completed, and conditionally re-submits failed ones.
-func (tm *taskManager) TaskDone(ctx context.Context, t task.Task, taskResult error) (err error) {
-	decorate.OnError(&err, "task %s", t)
+// Queued tasks that depend on a task that failed for good are dropped.
+func (tm *taskManager) TaskDone(ctx context.Context, t *task.Queued, taskResult error) (err error) {
+	decorate.OnError(&err, "task %s", t.Task)
 
 	if errors.As(taskResult, &task.NeedsRetryError{}) {
 		log.Errorf(ctx, "%v", taskResult) // Error message already mentions resubmission
 		return tm.resubmit(t)
 	}
 
-	if err := tm.save(); err != nil {
-		return fmt.Errorf("cleanup: could not save task queue: %v", err)
+	tm.mu.Lock()
+	var dependents []*task.Queued
+	if taskResult != nil {
+		dependents = tm.tasks.RemoveDependents(t.Task)
+	}
+	// Saving must be done with the lock held, otherwise it races with concurrent submissions.
+	saveErr := tm.save()
+	tm.mu.Unlock()
+
+	tm.history.add(t.Task, taskResult)
+	for _, dependent := range dependents {
+		log.Warningf(ctx, "Task %s: skipped because its prerequisite %s failed", dependent.Task, t.Task)
+		tm.history.add(dependent.Task, fmt.Errorf("skipped because its prerequisite %s failed", t.Task))
+	}
+
+	if saveErr != nil {
+		return fmt.Errorf("cleanup: could not save task queue: %v", saveErr)
 	}
 
 	if taskResult == nil {
 		return nil
 	}
 
+	var cancelled CancelledError
+	if errors.As(taskResult, &cancelled) {
+		log.Warningf(ctx, "cancelled and will not be retried: %v", taskResult)
+		return taskResult
+	}
+
+	if errors.As(taskResult, &PolicyDeniedError{}) {
+		log.Warningf(ctx, "denied and will not be retried: %v", taskResult)
+		return taskResult
+	}
+
 	log.Errorf(ctx, "failed and will not be retried: %v", taskResult)
 	return taskResult
 }
 
+// History returns the outcome of the last tasks that left the queue for good, from oldest to newest.
+func (tm *taskManager) History() []TaskRecord {
+	return tm.history.list()
+}
+
+// Queue returns the tasks waiting to be executed, in order, followed by the deferred ones.
+func (tm *taskManager) Queue() []QueuedTask {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	var queue []QueuedTask
+	for _, t := range tm.tasks.Data() {
+		queue = append(queue, newQueuedTask(t, false))
+	}
+	for _, t := range tm.deferredTasks.Data() {
+		queue = append(queue, newQueuedTask(t, true))
+	}
+
+	return queue
+}
+
+// Cancel removes the queued task with the given ID, deferred or not. The queued tasks that depend on
+// it are dropped as well, as they cannot run without it.
+func (tm *taskManager) Cancel(ctx context.Context, id string) (err error) {
+	defer decorate.OnError(&err, "could not cancel task %q", id)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	t, ok := tm.tasks.RemoveID(id)
+	if !ok {
+		t, ok = tm.deferredTasks.RemoveID(id)
+	}
+	if !ok {
+		return ErrTaskNotQueued
+	}
+
+	dependents := append(tm.tasks.RemoveDependents(t.Task), tm.deferredTasks.RemoveDependents(t.Task)...)
+
+	tm.history.add(t.Task, CancelledError{Reason: CancelUser})
+	for _, dependent := range dependents {
+		log.Warningf(ctx, "Task %s: skipped because its prerequisite %s was cancelled", dependent.Task, t.Task)
+		tm.history.add(dependent.Task, fmt.Errorf("skipped because its prerequisite %s was cancelled", t.Task))
+	}
+
+	return tm.save()
+}
+
 // EnqueueDeferredTasks takes all deferred tasks and promotes them
 // to regular tasks.
 func (tm *taskManager) EnqueueDeferredTasks() {
 	tm.tasks.Absorb(tm.deferredTasks)
 }
 
-// save writes the current task queue (plus deferred tasks) to file.
-func (tm *taskManager) save() (err error) {
-	defer decorate.OnError(&err, "could not save queued tasks to disk")
+// Clear removes every task, deferred or not, and stores the empty queue to disk.
+func (tm *taskManager) Clear() error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
-	tasks := append(tm.tasks.Data(), tm.deferredTasks.Data()...)
+	tm.tasks.Load(make([]*task.Queued, 0))
+	tm.deferredTasks.Load(make([]*task.Queued, 0))
 
-	out, err := task.MarshalYAML(tasks)
-	if err != nil {
-		return err
-	}
+	return tm.save()
+}
 
-	if err = os.WriteFile(tm.storagePath+".new", out, 0600); err != nil {
-		return err
-	}
+// save writes the current task queue (plus deferred tasks) to storage. The task manager must be locked.
+func (tm *taskManager) save() (err error) {
+	defer decorate.OnError(&err, "could not save queued tasks")
 
-	if err = os.Rename(tm.storagePath+".new", tm.storagePath); err != nil {
-		return err
+	var queued []task.Queued
+	for _, t := range append(tm.tasks.Data(), tm.deferredTasks.Data()...) {
+		queued = append(queued, *t)
 	}
 
-	return nil
+	return tm.storage.Save(queued)
 }
 
-// Load loads tasks from file.
+// Load loads tasks from storage.
 func (tm *taskManager) load() (err error) {
-	defer decorate.OnError(&err, "could not load tasks from disk")
+	defer decorate.OnError(&err, "could not load tasks")
 
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	out, err := os.ReadFile(tm.storagePath)
+	queued, err := tm.storage.Load()
 	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return nil
-		}
 		return err
 	}
 
-	var tasks []task.Task
-	if tasks, err = task.UnmarshalYAML(out); err != nil {
-		return err
+	tasks := make([]*task.Queued, 0, len(queued))
+	for _, t := range queued {
+		// Tasks stored by older versions have no ID. Their submission time is unknown.
+		if t.ID == "" {
+			t.ID = uuid.NewString()
+		}
+		tasks = append(tasks, &t)
 	}
 
 	tm.tasks.Load(tasks)
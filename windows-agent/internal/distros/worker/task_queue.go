@@ -16,26 +16,35 @@ import (
 // The queue is thread-safe, but there is no guarantee that awaiting pulls will
 // be served in order of arrival.
 type taskQueue struct {
-	mu   sync.RWMutex
+	mu sync.RWMutex
+
+	// wait is signalled when tasks are pushed. It is buffered so that a push that happens while
+	// a puller is between checking the queue and waiting is not lost, otherwise the puller would
+	// sleep with a non-empty queue until the next push.
 	wait chan struct{}
-	data []task.Task
+	data []*task.Queued
 }
 
 func newTaskQueue() *taskQueue {
 	return &taskQueue{
 		mu:   sync.RWMutex{},
-		wait: make(chan struct{}),
-		data: make([]task.Task, 0),
+		wait: newWaitChannel(),
+		data: make([]*task.Queued, 0),
 	}
 }
 
+// newWaitChannel creates a channel to signal pushes with.
+func newWaitChannel() chan struct{} {
+	return make(chan struct{}, 1)
+}
+
 // Load replaces the existing data with the one in "newData".
-func (q *taskQueue) Load(newData []task.Task) {
+func (q *taskQueue) Load(newData []*task.Queued) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	close(q.wait)
-	q.wait = make(chan struct{})
+	q.wait = newWaitChannel()
 
 	q.data = newData
 }
@@ -51,11 +60,11 @@ func (q *taskQueue) Absorb(other *taskQueue) {
 	transferedData := other.data
 
 	close(other.wait)
-	other.wait = make(chan struct{})
-	other.data = make([]task.Task, 0)
+	other.wait = newWaitChannel()
+	other.data = make([]*task.Queued, 0)
 
 	close(q.wait)
-	q.wait = make(chan struct{})
+	q.wait = newWaitChannel()
 	q.data = append(q.data, transferedData...)
 }
 
@@ -68,20 +77,20 @@ func (q *taskQueue) Len() int {
 }
 
 // Data returns a copy of all the queued tasks.
-func (q *taskQueue) Data() []task.Task {
+func (q *taskQueue) Data() []*task.Queued {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 
-	return append([]task.Task{}, q.data...)
+	return append([]*task.Queued{}, q.data...)
 }
 
-// Push adds a task to the queue. Any existing equivalent tasks are removed.
-func (q *taskQueue) Push(t task.Task) {
+// Push adds a task to the queue. Any existing equivalent tasks are removed and returned.
+func (q *taskQueue) Push(t *task.Queued) (removed []*task.Queued) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	// Remove copies of this task
-	q.data = removeIf(q.data, func(queued task.Task) bool { return task.Is(t, queued) })
+	removed = q.removeEquivalentUnsafe(t.Task)
 
 	// Append task
 	q.data = append(q.data, t)
@@ -91,17 +100,19 @@ func (q *taskQueue) Push(t task.Task) {
 	case q.wait <- struct{}{}:
 	default:
 	}
+
+	return removed
 }
 
 // Push adds a task to the queue unless an equivalent task is queued already.
 // Useful for re-submitting failed tasks.
-func (q *taskQueue) PushIfNew(t task.Task) {
+func (q *taskQueue) PushIfNew(t *task.Queued) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	// Check if this task exists already
 	for _, queued := range q.data {
-		if task.Is(queued, t) {
+		if task.Is(queued.Task, t.Task) {
 			return
 		}
 	}
@@ -122,7 +133,7 @@ func (q *taskQueue) Contains(t task.Task) bool {
 	defer q.mu.RUnlock()
 
 	for _, queued := range q.data {
-		if task.Is(queued, t) {
+		if task.Is(queued.Task, t) {
 			return true
 		}
 	}
@@ -130,20 +141,53 @@ func (q *taskQueue) Contains(t task.Task) bool {
 	return false
 }
 
-// Remove erases all tasks that are equivalent to "t".
-func (q *taskQueue) Remove(t task.Task) {
+// Remove erases all tasks that are equivalent to "t", and returns them.
+func (q *taskQueue) Remove(t task.Task) (removed []*task.Queued) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	q.data = removeIf(q.data, func(queued task.Task) bool { return task.Is(t, queued) })
+	return q.removeEquivalentUnsafe(t)
 }
 
-// Pull pops the first task in the queue. If the queue is empty, this function
-// blocks until a task is Pushed, Loaded or Absorved.
+// RemoveID erases the task with the given ID, and returns it. The second return value is false
+// if no such task is queued.
+func (q *taskQueue) RemoveID(id string) (*task.Queued, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, queued := range q.data {
+		if queued.ID != id {
+			continue
+		}
+
+		q.data = append(q.data[:i:i], q.data[i+1:]...)
+		return queued, true
+	}
+
+	return nil, false
+}
+
+// removeEquivalentUnsafe erases all tasks that are equivalent to "t", and returns them.
+// It must be called with the lock held.
+func (q *taskQueue) removeEquivalentUnsafe(t task.Task) (removed []*task.Queued) {
+	kept := removeIf(q.data, func(queued *task.Queued) bool { return task.Is(t, queued.Task) })
+
+	// The removed tasks are left at the end of the slice by removeIf.
+	if len(kept) < len(q.data) {
+		removed = append(removed, q.data[len(kept):]...)
+	}
+
+	q.data = kept
+	return removed
+}
+
+// Pull pops the first task in the queue that is accepted by the filter. A nil filter accepts
+// every task. If there is no such task, this function blocks until a task is Pushed, Loaded
+// or Absorbed.
 //
 // Concurrent pulls are safe but the order in which they are served in is
 // indeterminate.
-func (q *taskQueue) Pull(ctx context.Context) task.Task {
+func (q *taskQueue) Pull(ctx context.Context, accept func(task.Task) bool) *task.Queued {
 	// Avoid races if the context is cancelled already
 	select {
 	case <-ctx.Done():
@@ -152,7 +196,7 @@ func (q *taskQueue) Pull(ctx context.Context) task.Task {
 	}
 
 	for {
-		if task, ok := q.tryPopFront(); ok {
+		if task, ok := q.tryPop(accept); ok {
 			return task
 		}
 
@@ -170,7 +214,7 @@ func (q *taskQueue) Pull(ctx context.Context) task.Task {
 			// | only entry in the queue. Or an empty Load could
 			// | leave an empty "data" behind.
 			// ↓
-			if task, ok := q.tryPopFront(); ok {
+			if task, ok := q.tryPop(accept); ok {
 				return task
 			}
 			// Solution to race: just try again
@@ -178,23 +222,70 @@ func (q *taskQueue) Pull(ctx context.Context) task.Task {
 	}
 }
 
-// tryPopFront is a helper function not to be used outside. Equivalent to Pull but without
-// waiting. It returns false if the queue is empty.
-func (q *taskQueue) tryPopFront() (task.Task, bool) {
+// tryPop is a helper function not to be used outside. Equivalent to Pull but without
+// waiting. It returns false if no queued task is accepted. Tasks whose prerequisites are
+// still queued are never popped.
+func (q *taskQueue) tryPop(accept func(task.Task) bool) (*task.Queued, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, t := range q.data {
+		if accept != nil && !accept(t.Task) {
+			continue
+		}
+
+		if q.waitsOnPrerequisite(i) {
+			continue
+		}
+
+		q.data = append(q.data[:i:i], q.data[i+1:]...)
+		return t, true
+	}
+
+	return nil, false
+}
+
+// waitsOnPrerequisite returns true if the i-th task requires any other queued task.
+// It must be called with the lock held.
+func (q *taskQueue) waitsOnPrerequisite(i int) bool {
+	for j, other := range q.data {
+		if i != j && task.Requires(q.data[i].Task, other.Task) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveDependents erases all tasks that require "t", either directly or through other
+// queued tasks, and returns them.
+func (q *taskQueue) RemoveDependents(t task.Task) []*task.Queued {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	if len(q.data) == 0 {
-		return nil, false
+	var removed []*task.Queued
+	failed := []task.Task{t}
+
+	for len(failed) > 0 {
+		prerequisite := failed[0]
+		failed = failed[1:]
+
+		kept := make([]*task.Queued, 0, len(q.data))
+		for _, queued := range q.data {
+			if !task.Requires(queued.Task, prerequisite) {
+				kept = append(kept, queued)
+				continue
+			}
+			removed = append(removed, queued)
+			failed = append(failed, queued.Task)
+		}
+		q.data = kept
 	}
 
-	r := q.data[0]
-	q.data = q.data[1:]
-	return r, true
+	return removed
 }
 
 // removeIf removes all elements that satisfy the predicate from the array.
-func removeIf(array []task.Task, predicate func(task.Task) bool) []task.Task {
+func removeIf[T any](array []T, predicate func(T) bool) []T {
 	// Accepts or rejects every entry of the slice, pushing accepted
 	// entries to the end of the accepted region.
 	//
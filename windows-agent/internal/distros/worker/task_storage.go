@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+)
+
+// TaskStorage persists the queued tasks of a distro, so that they survive the agent restarting.
+type TaskStorage interface {
+	// Load returns the stored tasks, in order. It returns no task and no error if nothing was
+	// stored yet.
+	Load() ([]task.Queued, error)
+
+	// Save replaces the stored tasks.
+	Save([]task.Queued) error
+}
+
+// TaskFile returns the path of the file where the tasks of the distro are stored.
+func TaskFile(storageDir, distroName string) string {
+	return filepath.Join(storageDir, distroName+".tasks")
+}
+
+// fileTaskStorage stores the tasks as YAML in a file. It is the storage used unless overridden.
+type fileTaskStorage struct {
+	path string
+}
+
+// Load reads the tasks from the file.
+func (s fileTaskStorage) Load() ([]task.Queued, error) {
+	out, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return task.UnmarshalQueueYAML(out)
+}
+
+// Save writes the tasks to the file, replacing it atomically.
+func (s fileTaskStorage) Save(queued []task.Queued) error {
+	out, err := task.MarshalQueueYAML(queued)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(s.path+".new", out, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(s.path+".new", s.path)
+}
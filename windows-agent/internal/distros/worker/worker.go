@@ -5,15 +5,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
 	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
 	"github.com/ubuntu/decorate"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type distro interface {
@@ -31,20 +39,105 @@ type Worker struct {
 	distro  distro
 	manager *taskManager
 
-	cancel     context.CancelFunc
+	cancel     context.CancelCauseFunc
 	processing chan struct{}
 
-	conn   *grpc.ClientConn
+	// busy counts the tasks being processed: the exclusive one, and the shared ones running alongside it.
+	busy atomic.Int32
+
+	// circuit holds back the tasks after too many of them failed in a row.
+	circuit circuitBreaker
+
+	schedule Schedule
+	pauser   Pauser
+	wsl      WSLWatcher
+
+	conn   *connection.Connection
+	conns  ConnFactory
 	connMu sync.RWMutex
+
+	// connected is closed and replaced every time a connection is set, so that the tasks waiting
+	// for the distro to connect start right away.
+	connected chan struct{}
+	wake      WakeStrategy
+
+	// calls serializes the long-running RPCs made to the distro.
+	calls *callQueue
+
+	// timeouts is how long the worker waits for the distro.
+	timeouts timeouts.Policy
+
+	// hooks are told about the tasks as they go through the worker.
+	hooks hookList
+
+	clock clock.Clock
 }
 
+// WakeStrategy decides when a task that waits for its distro to connect checks whether it did.
+type WakeStrategy int
+
+const (
+	// WakeOnConnection checks as soon as a connection is set, as well as on every tick of the client
+	// wait in case the connection was not usable yet. It is the default.
+	WakeOnConnection WakeStrategy = iota
+
+	// WakeOnTick only checks on every tick of the client wait.
+	WakeOnTick
+)
+
 // Provisioning is an interface which provides provisioning tasks.
 type Provisioning interface {
 	ProvisioningTasks(context.Context, string) ([]task.Task, error)
 }
 
+// Schedule decides when tasks that are not urgent can run.
+type Schedule interface {
+	// TaskWindow returns true if non-urgent tasks can run at the given time, and how long it
+	// takes until that changes.
+	TaskWindow(now time.Time) (open bool, next time.Duration)
+}
+
+// Pauser holds back every task while the agent is paused.
+type Pauser interface {
+	// Paused returns true if the agent is paused.
+	Paused() bool
+
+	// WaitResumed blocks until the agent is not paused, or the context is done.
+	WaitResumed(context.Context) error
+
+	// WaitPaused blocks until the agent is paused, or the context is done.
+	WaitPaused(context.Context) error
+}
+
+// WSLWatcher tells whether WSL itself can be used, as opposed to a single distro.
+type WSLWatcher interface {
+	// Check probes WSL, and returns false if it cannot be used.
+	Check(context.Context) bool
+}
+
+// ConnFactory opens the gRPC connection to the WSL service out of the connection set on the worker.
+type ConnFactory interface {
+	ClientConn(*connection.Connection) (grpc.ClientConnInterface, error)
+}
+
+// dialer is the connection factory used unless overridden: it dials the connection if needed.
+type dialer struct{}
+
+func (dialer) ClientConn(conn *connection.Connection) (grpc.ClientConnInterface, error) {
+	return conn.ClientConn()
+}
+
 type options struct {
 	provisioning Provisioning
+	schedule     Schedule
+	pauser       Pauser
+	wsl          WSLWatcher
+	timeouts     timeouts.Policy
+	wake         WakeStrategy
+	storage      TaskStorage
+	conns        ConnFactory
+	hooks        hookList
+	clock        clock.Clock
 }
 
 // Option is an optional argument for worker.New.
@@ -58,25 +151,114 @@ func WithProvisioning(provisioning Provisioning) Option {
 	}
 }
 
+// WithSchedule is an optional parameter for worker.New that holds back tasks that are not
+// urgent until the schedule allows them to run.
+func WithSchedule(schedule Schedule) Option {
+	return func(o *options) {
+		o.schedule = schedule
+	}
+}
+
+// WithPauser is an optional parameter for worker.New that holds back all tasks while the agent
+// is paused. The task being processed when the agent is paused is not interrupted.
+func WithPauser(pauser Pauser) Option {
+	return func(o *options) {
+		o.pauser = pauser
+	}
+}
+
+// WithWSLWatcher is an optional parameter for worker.New that tells apart the distros that cannot
+// be reached from WSL being unavailable as a whole. In the latter case, the task is retried once WSL
+// is back instead of the distro being invalidated: pass the same watcher to WithPauser for that.
+func WithWSLWatcher(wsl WSLWatcher) Option {
+	return func(o *options) {
+		o.wsl = wsl
+	}
+}
+
+// WithWakeStrategy is an optional parameter for worker.New that overrides when the tasks waiting
+// for the distro to connect check whether it did.
+func WithWakeStrategy(wake WakeStrategy) Option {
+	return func(o *options) {
+		o.wake = wake
+	}
+}
+
+// WithTimeouts is an optional parameter for worker.New that overrides how long the worker waits
+// for the distro to connect, and how long the RPCs made to it may take.
+func WithTimeouts(policy timeouts.Policy) Option {
+	return func(o *options) {
+		o.timeouts = policy
+	}
+}
+
+// WithTaskStorage is an optional parameter for worker.New that overrides where the tasks are stored.
+// The storage directory passed to worker.New is ignored then.
+func WithTaskStorage(storage TaskStorage) Option {
+	return func(o *options) {
+		o.storage = storage
+	}
+}
+
+// WithConnFactory is an optional parameter for worker.New that overrides how the gRPC connection to
+// the WSL service is opened, so that the tasks can run against a fake service.
+func WithConnFactory(conns ConnFactory) Option {
+	return func(o *options) {
+		o.conns = conns
+	}
+}
+
+// WithHooks is an optional parameter for worker.New that lets observability sinks follow the tasks
+// as they are queued, run and retried. It can be passed several times: every set of hooks is called.
+func WithHooks(hooks ...Hooks) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// WithClock is an optional parameter for worker.New that overrides the clock used to time the
+// tasks, schedule them, hold them back and wait for the distro.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
 // New creates a new worker and starts it. Call Stop when you're done to avoid leaking the task execution goroutine.
 func New(ctx context.Context, d distro, storageDir string, args ...Option) (w *Worker, err error) {
 	defer decorate.OnError(&err, "distro %q: could not create worker", d.Name())
 
-	storagePath := filepath.Join(storageDir, d.Name()+".tasks")
-
-	var opts options
+	opts := options{
+		storage: fileTaskStorage{path: TaskFile(storageDir, d.Name())},
+		conns:   dialer{},
+		clock:   clock.Real(),
+	}
 	for _, f := range args {
 		f(&opts)
 	}
 
-	tm, err := newTaskManager(storagePath)
+	tm, err := newTaskManager(opts.storage, opts.clock)
 	if err != nil {
 		return nil, err
 	}
 
+	policy := opts.timeouts.OrDefault()
+
 	w = &Worker{
-		distro:  d,
-		manager: tm,
+		distro:   d,
+		manager:  tm,
+		schedule: opts.schedule,
+		pauser:   opts.pauser,
+		wsl:      opts.wsl,
+		calls:    newCallQueue(policy),
+		timeouts: policy,
+		circuit:  circuitBreaker{clock: opts.clock},
+		conns:    opts.conns,
+		hooks:    opts.hooks,
+		clock:    opts.clock,
+
+		connected: make(chan struct{}),
+		wake:      opts.wake,
 	}
 
 	w.start(ctx)
@@ -107,6 +289,9 @@ func (w *Worker) IsActive() bool {
 
 // Client returns the client to the WSL task service.
 // Client returns nil when no connection is set up.
+//
+// Only one long-running call is made to the distro at a time, regardless of how many clients
+// are in use: the others wait for their turn. Every call is cancelled if it exceeds its budget.
 func (w *Worker) Client() wslserviceapi.WSLClient {
 	w.connMu.RLock()
 	defer w.connMu.RUnlock()
@@ -115,42 +300,96 @@ func (w *Worker) Client() wslserviceapi.WSLClient {
 		return nil
 	}
 
-	return wslserviceapi.NewWSLClient(w.conn)
+	conn, err := w.conns.ClientConn(w.conn)
+	if err != nil {
+		log.Warningf(context.TODO(), "Distro %q: %v", w.distro.Name(), err)
+		return nil
+	}
+
+	return w.calls.client(conn)
 }
 
-// SetConnection removes the connection associated with the distro.
-func (w *Worker) SetConnection(conn *grpc.ClientConn) {
+// SetConnection replaces the connection associated with the distro, closing the previous one.
+// A nil connection removes it.
+func (w *Worker) SetConnection(conn *connection.Connection) {
 	w.connMu.Lock()
 	defer w.connMu.Unlock()
 
 	if w.conn != nil {
+		if conn != nil {
+			log.Infof(context.TODO(), "Distro %q: replacing the connection to the WSL service", w.distro.Name())
+		}
 		if err := w.conn.Close(); err != nil {
 			log.Warningf(context.TODO(), "Distro %q: could not close previous grpc connection: %v", w.distro.Name(), err)
 		}
 	}
 	w.conn = conn
+
+	if conn != nil {
+		close(w.connected)
+		w.connected = make(chan struct{})
+	}
+}
+
+// connectedSignal returns a channel that is closed the next time a connection is set. It is nil,
+// and thus never ready, when the tasks only check for a connection on every tick.
+func (w *Worker) connectedSignal() <-chan struct{} {
+	if w.wake != WakeOnConnection {
+		return nil
+	}
+
+	w.connMu.RLock()
+	defer w.connMu.RUnlock()
+
+	return w.connected
+}
+
+// ReleaseConnection removes the connection and closes it, unless it was replaced by another one
+// already. It returns true if the connection was removed.
+func (w *Worker) ReleaseConnection(conn *connection.Connection) bool {
+	w.connMu.Lock()
+	defer w.connMu.Unlock()
+
+	if conn == nil || w.conn != conn {
+		return false
+	}
+
+	if err := w.conn.Close(); err != nil {
+		log.Warningf(context.TODO(), "Distro %q: could not close grpc connection: %v", w.distro.Name(), err)
+	}
+	w.conn = nil
+
+	return true
 }
 
 // start starts the main task processing goroutine.
 func (w *Worker) start(ctx context.Context) {
 	log.Debugf(ctx, "Distro %q: starting task processing", w.distro.Name())
 
-	ctx, cancel := context.WithCancel(ctx)
+	ctx, cancel := context.WithCancelCause(ctx)
 	w.processing = make(chan struct{})
 	go w.processTasks(ctx)
 	w.cancel = cancel
 }
 
 // Stop stops the main task processing goroutine and wait for it to be done.
+// The task being processed, if any, is recorded as cancelled by the agent shutting down.
 func (w *Worker) Stop(ctx context.Context) {
-	log.Debugf(ctx, "Distro %q: stopping task processing", w.distro.Name())
-	w.cancel()
+	w.StopWithReason(ctx, CancelAgentShutdown)
+}
+
+// StopWithReason is like Stop, but the task being processed, if any, is recorded as cancelled
+// for the given reason.
+func (w *Worker) StopWithReason(ctx context.Context, reason CancelReason) {
+	log.Debugf(ctx, "Distro %q: stopping task processing: %s", w.distro.Name(), reason)
+	w.cancel(CancelledError{Reason: reason})
 	<-w.processing
 	w.SetConnection(nil)
 }
 
 // SubmitTasks enqueues one or more task on our current worker list. The task will wake up
-// the distro and be performed as soon as it reaches the beginning of the queue.
+// the distro and be performed as soon as it reaches the beginning of the queue. Tasks that
+// depend on other queued tasks wait for them to succeed, and are dropped if they fail.
 //
 // It will return an error if the distro has been cleaned up or the task queue is full.
 func (w *Worker) SubmitTasks(tasks ...task.Task) (err error) {
@@ -161,7 +400,7 @@ func (w *Worker) SubmitTasks(tasks ...task.Task) (err error) {
 	}
 
 	log.Infof(context.TODO(), "Distro %q: Submitting tasks %q to queue", w.distro.Name(), tasks)
-	return w.manager.Submit(false, tasks...)
+	return w.submit(false, tasks...)
 }
 
 // SubmitDeferredTasks takes one or more tasks into our current worker list.
@@ -179,7 +418,14 @@ func (w *Worker) SubmitDeferredTasks(tasks ...task.Task) (err error) {
 
 	log.Infof(context.TODO(), "Distro %q: Submitting tasks %q to queue", w.distro.Name(), tasks)
 
-	return w.manager.Submit(true, tasks...)
+	return w.submit(true, tasks...)
+}
+
+// submit adds the tasks to the queue, and lets the hooks know about them.
+func (w *Worker) submit(deferred bool, tasks ...task.Task) error {
+	queued, err := w.manager.Submit(deferred, tasks...)
+	w.hooks.enqueue(w.distro.Name(), queued, deferred)
+	return err
 }
 
 // EnqueueDeferredTasks takes all deferred tasks and promotes them
@@ -188,30 +434,254 @@ func (w *Worker) EnqueueDeferredTasks() {
 	w.manager.EnqueueDeferredTasks()
 }
 
+// ClearTasks removes every queued task, deferred or not. The task currently being processed, if
+// any, is not interrupted.
+func (w *Worker) ClearTasks() (err error) {
+	defer decorate.OnError(&err, "distro %q: could not clear tasks", w.distro.Name())
+
+	log.Infof(context.TODO(), "Distro %q: Clearing task queue", w.distro.Name())
+	return w.manager.Clear()
+}
+
+// QueuedTasks returns the tasks waiting to be executed, in order, followed by the deferred ones.
+// The task currently being processed, if any, is not queued anymore.
+func (w *Worker) QueuedTasks() []QueuedTask {
+	return w.manager.Queue()
+}
+
+// CancelTask removes the queued task with the given ID, along with the queued tasks that depend on
+// it. It returns ErrTaskNotQueued if no such task is queued.
+func (w *Worker) CancelTask(ctx context.Context, id string) (err error) {
+	defer decorate.OnError(&err, "distro %q", w.distro.Name())
+
+	log.Infof(ctx, "Distro %q: cancelling task %q", w.distro.Name(), id)
+	return w.manager.Cancel(ctx, id)
+}
+
+// PendingTasks returns the number of non-deferred tasks waiting to be executed, including the ones
+// currently being processed.
+func (w *Worker) PendingTasks() int {
+	return w.manager.QueueLen() + int(w.busy.Load())
+}
+
+// TaskInProgress returns true while a task is being processed.
+func (w *Worker) TaskInProgress() bool {
+	return w.busy.Load() > 0
+}
+
+// Circuit returns whether the tasks of the distro are held back because too many of them failed in a row.
+func (w *Worker) Circuit() CircuitState {
+	return w.circuit.State()
+}
+
+// TaskHistory returns the outcome of the last tasks that left the queue for good, from oldest to newest.
+func (w *Worker) TaskHistory() []TaskRecord {
+	return w.manager.History()
+}
+
 // processTasks is the main loop for the distro, processing any existing tasks while starting and releasing
 // locks to distro,.
+//
+// Exclusive tasks run one at a time. While one of them runs, shared tasks are processed alongside it, so
+// that reading from the distro does not wait for a long change to complete.
 func (w *Worker) processTasks(ctx context.Context) {
 	defer close(w.processing)
 
 	for {
-		t, ok := w.manager.NextTask(ctx)
+		t, ok := w.nextTask(ctx, nil)
 		if !ok {
 			return
 		}
 
-		resultErr := w.processSingleTask(ctx, t)
-
-		var target unreachableDistroError
-		if errors.As(resultErr, &target) {
-			log.Errorf(ctx, "Distro %q: task %q: distro not reachable: %v", w.distro.Name(), t, target.sourceErr)
-			w.distro.Invalidate(ctx)
+		if task.IsShared(t.Task) {
+			if !w.runTask(ctx, t) {
+				return
+			}
 			continue
 		}
 
-		err := w.manager.TaskDone(ctx, t, resultErr)
-		if err != nil {
+		pullCtx, stopShared := context.WithCancel(ctx)
+		sharedDone := make(chan struct{})
+		go func() {
+			defer close(sharedDone)
+			w.processSharedTasks(ctx, pullCtx, t.Task)
+		}()
+
+		ok = w.runTask(ctx, t)
+
+		// The shared task being processed, if any, is not interrupted.
+		stopShared()
+		<-sharedDone
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// processSharedTasks processes the shared tasks while the exclusive task runs. It stops pulling tasks
+// once pullCtx is done. Shared tasks that require the exclusive task wait for it to complete.
+func (w *Worker) processSharedTasks(ctx, pullCtx context.Context, exclusive task.Task) {
+	accept := func(t task.Task) bool {
+		return task.IsShared(t) && !task.Requires(t, exclusive)
+	}
+
+	for {
+		t, ok := w.nextTask(pullCtx, accept)
+		if !ok {
+			return
+		}
+
+		if !w.runTask(ctx, t) {
+			return
+		}
+	}
+}
+
+// runTask processes the task and handles its outcome. It returns false if the worker is stopping.
+func (w *Worker) runTask(ctx context.Context, t *task.Queued) bool {
+	w.hooks.start(ctx, w.distro.Name(), t)
+	started := w.clock.Now()
+
+	w.busy.Add(1)
+	resultErr := w.processSingleTask(ctx, t.Task)
+	w.busy.Add(-1)
+
+	took := w.clock.Now().Sub(started)
+
+	var target unreachableDistroError
+	if errors.As(resultErr, &target) && w.wsl != nil && !w.wsl.Check(ctx) {
+		log.Warningf(ctx, "Distro %q: task %q: WSL is unavailable, the task will be retried once it is back: %v", w.distro.Name(), t.Task, target.sourceErr)
+
+		if err := w.manager.Requeue(t); err != nil {
+			log.Errorf(ctx, "Distro %q: %v", w.distro.Name(), err)
+		}
+		w.hooks.retry(ctx, w.distro.Name(), t, resultErr)
+		return true
+	}
+
+	if errors.As(resultErr, &target) {
+		log.Errorf(ctx, "Distro %q: task %q: distro not reachable: %v", w.distro.Name(), t.Task, target.sourceErr)
+		w.distro.Invalidate(ctx)
+		w.hooks.finish(ctx, w.distro.Name(), t, took, resultErr)
+		return true
+	}
+
+	var shutdown distroShutdownError
+	if errors.As(resultErr, &shutdown) {
+		log.Warningf(ctx, "Distro %q: task %q: distro was shut down while running the task, it will be retried: %v", w.distro.Name(), t.Task, shutdown.sourceErr)
+
+		// The connection is gone for good, even if it was not noticed yet.
+		w.SetConnection(nil)
+
+		if err := w.manager.Requeue(t); err != nil {
 			log.Errorf(ctx, "Distro %q: %v", w.distro.Name(), err)
 		}
+		w.hooks.retry(ctx, w.distro.Name(), t, resultErr)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-w.clock.After(w.timeouts.ShutdownPause):
+		}
+		return true
+	}
+
+	err := w.manager.TaskDone(ctx, t, resultErr)
+	if err != nil {
+		log.Errorf(ctx, "Distro %q: %v", w.distro.Name(), err)
+	}
+
+	if errors.As(resultErr, &task.NeedsRetryError{}) {
+		w.hooks.retry(ctx, w.distro.Name(), t, resultErr)
+	} else {
+		w.hooks.finish(ctx, w.distro.Name(), t, took, resultErr)
+	}
+
+	// Cancelled and denied tasks say nothing about the health of the distro.
+	if errors.As(resultErr, &CancelledError{}) || errors.As(resultErr, &PolicyDeniedError{}) {
+		return true
+	}
+
+	if w.circuit.record(resultErr) {
+		c := w.circuit.State()
+		log.Warningf(ctx, "Distro %q: %d tasks failed in a row, the distro needs attention. Holding back its tasks until %s. Last error: %v",
+			w.distro.Name(), c.Failures, c.RetryAt.Format(time.RFC3339), c.LastError)
+	}
+
+	return true
+}
+
+// nextTask pulls the next task accepted by the filter that can run now. A nil filter accepts every
+// task. Nothing is pulled while the agent is paused, nor while the circuit is open. Outside of the
+// task window, only urgent tasks are pulled, and the rest are left in the queue until the window opens.
+func (w *Worker) nextTask(ctx context.Context, filter func(task.Task) bool) (*task.Queued, bool) {
+	if err := w.circuit.wait(ctx); err != nil {
+		return nil, false
+	}
+
+	if w.pauser == nil {
+		return w.nextScheduledTask(ctx, filter)
+	}
+
+	for {
+		if err := w.pauser.WaitResumed(ctx); err != nil {
+			return nil, false
+		}
+
+		// Pausing stops the wait for a task. As that is not instantaneous, tasks are also
+		// checked when popped, so that none is pulled while paused.
+		pullCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			if w.pauser.WaitPaused(pullCtx) == nil {
+				cancel()
+			}
+		}()
+
+		t, ok := w.nextScheduledTask(pullCtx, func(t task.Task) bool {
+			return !w.pauser.Paused() && (filter == nil || filter(t))
+		})
+		cancel()
+
+		if ok {
+			return t, true
+		}
+
+		if ctx.Err() != nil {
+			return nil, false
+		}
+	}
+}
+
+// nextScheduledTask pulls the next task accepted by the filter that the schedule allows to run
+// now. A nil filter accepts every task.
+func (w *Worker) nextScheduledTask(ctx context.Context, filter func(task.Task) bool) (*task.Queued, bool) {
+	if w.schedule == nil {
+		return w.manager.NextTask(ctx, filter)
+	}
+
+	for {
+		open, next := w.schedule.TaskWindow(w.clock.Now())
+
+		accept := filter
+		if !open {
+			accept = func(t task.Task) bool {
+				return task.IsUrgent(t) && (filter == nil || filter(t))
+			}
+		}
+
+		// Wait only until the window opens or closes, so that the filter is re-evaluated.
+		pullCtx, cancel := context.WithTimeout(ctx, next)
+		t, ok := w.manager.NextTask(pullCtx, accept)
+		cancel()
+
+		if ok {
+			return t, true
+		}
+
+		if ctx.Err() != nil {
+			return nil, false
+		}
 	}
 }
 
@@ -232,7 +702,31 @@ func (err unreachableDistroError) Error() string {
 	return fmt.Sprintf("distro cannot be reached: %v", err.sourceErr)
 }
 
-func (w *Worker) processSingleTask(ctx context.Context, t task.Task) error {
+// distroShutdownError is returned when the task failed because the connection to the distro was lost
+// while it was running, which happens when the host calls wsl --shutdown or the WSL VM is terminated.
+type distroShutdownError struct {
+	sourceErr error
+}
+
+func (err distroShutdownError) Error() string {
+	return fmt.Sprintf("distro was shut down: %v", err.sourceErr)
+}
+
+// connectionLost returns true if the error is caused by the connection to the distro dropping.
+func connectionLost(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Canceled:
+		return true
+	}
+	return false
+}
+
+func (w *Worker) processSingleTask(ctx context.Context, t task.Task) (err error) {
+	ctx, span := telemetry.Start(ctx, "distro.task",
+		attribute.String("distro", w.distro.Name()),
+		attribute.String("task", common.Redact(fmt.Sprint(t))))
+	defer telemetry.End(span, &err)
+
 	log.Debugf(ctx, "Distro %q: starting task %q", w.distro.Name(), t)
 
 	if !w.distro.IsValid() {
@@ -242,7 +736,10 @@ func (w *Worker) processSingleTask(ctx context.Context, t task.Task) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	if err := w.distro.LockAwake(); err != nil {
+	_, wakeSpan := telemetry.Start(ctx, "distro.wake")
+	err = w.distro.LockAwake()
+	telemetry.End(wakeSpan, &err)
+	if err != nil {
 		return newUnreachableDistroErr(err)
 	}
 	//nolint:errcheck // Nothing we can do about it
@@ -252,18 +749,76 @@ func (w *Worker) processSingleTask(ctx context.Context, t task.Task) error {
 
 	client, err := w.waitForActiveConnection(ctx)
 	if err != nil {
+		if ctx.Err() != nil {
+			reason, _ := cancelReason(ctx, err)
+			return CancelledError{Reason: reason, SourceErr: fmt.Errorf("task %v: could not start task: %v", t, err)}
+		}
 		return fmt.Errorf("task %v: could not start task: %w", t, err)
 	}
 
-	if err := t.Execute(ctx, client); err != nil {
-		return fmt.Errorf("distro %q: task %q failed: %w", w.distro.Name(), t, err)
+	followUps := &followUpSubmitter{worker: w, current: t}
+	defer followUps.close()
+
+	if err := t.Execute(task.WithSubmitter(ctx, followUps), client); err != nil {
+		// A cancelled context means the agent is stopping, rather than the distro.
+		if ctx.Err() == nil && connectionLost(err) {
+			return distroShutdownError{sourceErr: err}
+		}
+
+		// Retrying would only be denied again: it is up to the administrator of the distro to change its policy.
+		if denied, ok := wslserviceapi.AsPolicyDenied(err); ok {
+			return PolicyDeniedError{
+				Call:      denied.GetCall(),
+				Reason:    denied.GetReason(),
+				SourceErr: fmt.Errorf("distro %q: task %q failed: %v", w.distro.Name(), t, err),
+			}
+		}
+
+		err = fmt.Errorf("distro %q: task %q failed: %w", w.distro.Name(), t, err)
+		if reason, ok := cancelReason(ctx, err); ok && !errors.As(err, &task.NeedsRetryError{}) {
+			return CancelledError{Reason: reason, SourceErr: err}
+		}
+		return err
 	}
 
 	log.Debugf(ctx, "Distro %q: task %q: task completed successfully", w.distro.Name(), t)
 	return nil
 }
 
+// followUpSubmitter lets a task submit follow-up tasks to the worker that executes it, for as long as
+// it runs. The tasks go straight to the queue: going through the distro would deadlock if it is being
+// cleaned up, as the cleanup waits for the task to return.
+type followUpSubmitter struct {
+	worker  *Worker
+	current task.Task
+	closed  atomic.Bool
+}
+
+// SubmitTasks submits the follow-up tasks. A task cannot submit itself, or an equivalent task, as it
+// would be executed over and over again.
+func (s *followUpSubmitter) SubmitTasks(tasks ...task.Task) error {
+	if s.closed.Load() {
+		return fmt.Errorf("task %q returned already: it cannot submit follow-up tasks anymore", s.current)
+	}
+
+	for _, t := range tasks {
+		if task.Is(t, s.current) {
+			return fmt.Errorf("task %q cannot submit itself as a follow-up task", s.current)
+		}
+	}
+
+	return s.worker.SubmitTasks(tasks...)
+}
+
+// close stops accepting follow-up tasks.
+func (s *followUpSubmitter) close() {
+	s.closed.Store(true)
+}
+
 func (w *Worker) waitForActiveConnection(ctx context.Context) (client wslserviceapi.WSLClient, err error) {
+	ctx, span := telemetry.Start(ctx, "distro.connect")
+	defer telemetry.End(span, &err)
+
 	log.Debugf(ctx, "Distro %q: ensuring active connection.", w.distro.Name())
 
 	for i := 0; i < 5; i++ {
@@ -294,29 +849,39 @@ func (w *Worker) waitForActiveConnection(ctx context.Context) (client wslservice
 }
 
 // waitForClient waits for a valid GRPC client to connect to. It will retry for a while before
-// erroring out.
+// erroring out. How soon it notices a new connection depends on the wake strategy.
 func (w *Worker) waitForClient(ctx context.Context) (wslserviceapi.WSLClient, error) {
-	timedOutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+	timeout := w.clock.NewTimer(w.timeouts.ClientWait)
+	defer timeout.Stop()
 
-	tickRate := 0 * time.Second
 	for {
-		select {
-		case <-ctx.Done():
-			// Context cancelled means agent teardown.
-			return nil, fmt.Errorf("stopped waiting for client: %v", timedOutCtx.Err())
-		case <-timedOutCtx.Done():
-			// Timeout means the distro is not reachable.
-			return nil, newUnreachableDistroErr(errors.New("timed out waiting for client"))
-		case <-time.After(tickRate):
-			client := w.Client()
-
-			if client == nil {
-				tickRate = time.Second
-				continue
-			}
+		// The signal is taken before looking for a client, so that a connection set in between is not missed.
+		connected := w.connectedSignal()
 
+		if client := w.Client(); client != nil {
 			return client, nil
 		}
+
+		if err := w.waitForClientTick(ctx, timeout, connected); err != nil {
+			return nil, err
+		}
 	}
 }
+
+// waitForClientTick waits until it is time to look for a client again.
+func (w *Worker) waitForClientTick(ctx context.Context, timeout clock.Timer, connected <-chan struct{}) error {
+	tick := w.clock.NewTimer(w.timeouts.ClientWaitTick)
+	defer tick.Stop()
+
+	select {
+	case <-ctx.Done():
+		// Context cancelled means agent teardown.
+		return fmt.Errorf("stopped waiting for client: %v", ctx.Err())
+	case <-timeout.C():
+		// Timeout means the distro is not reachable.
+		return newUnreachableDistroErr(errors.New("timed out waiting for client"))
+	case <-connected:
+	case <-tick.C():
+	}
+	return nil
+}
@@ -19,18 +19,30 @@ import (
 
 	"github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslwatch"
 	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 )
 
 func init() {
 	task.Register[emptyTask]()
+	task.Register[urgentTask]()
+	task.Register[sharedTask]()
+	task.Register[dependentTask]()
+	task.Register[loadTask]()
+	task.Register[chainingTask]()
 }
 
 func TestMain(m *testing.M) {
@@ -400,6 +412,44 @@ func TestSetConnectionOnClosedConnection(t *testing.T) {
 	require.Equal(t, 1, wslInstanceService2.pingCount, "second service should be called once")
 }
 
+func TestReleaseConnection(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	d := &testDistro{
+		name: wsltestutils.RandomDistroName(t),
+	}
+
+	w, err := worker.New(ctx, d, t.TempDir())
+	require.NoError(t, err, "Setup: unexpected error creating the worker")
+	defer w.Stop(ctx)
+
+	wslInstanceService1 := newTestService(t)
+	conn1 := wslInstanceService1.newClientConnection(t)
+
+	wslInstanceService2 := newTestService(t)
+	conn2 := wslInstanceService2.newClientConnection(t)
+
+	require.False(t, w.ReleaseConnection(nil), "ReleaseConnection should not release a nil connection")
+	require.False(t, w.ReleaseConnection(conn1), "ReleaseConnection should not release a connection that was never set")
+
+	w.SetConnection(conn1)
+	w.SetConnection(conn2)
+
+	// The stale connection is released after being replaced: the new one must be kept.
+	require.False(t, w.ReleaseConnection(conn1), "ReleaseConnection should not release a connection that was replaced")
+	require.True(t, w.IsActive(), "IsActive() should return true because the current connection was kept")
+
+	_, err = w.Client().Ping(ctx, &wslserviceapi.Empty{})
+	require.NoError(t, err, "Ping should have been done successfully")
+	require.Equal(t, 1, wslInstanceService2.pingCount, "second service should be called once")
+
+	require.True(t, w.ReleaseConnection(conn2), "ReleaseConnection should release the current connection")
+	require.False(t, w.IsActive(), "IsActive() should return false because the connection was released")
+	require.Equal(t, connectivity.Shutdown, conn2.State(), "Released connection should be closed")
+}
+
 func TestTaskDeferral(t *testing.T) {
 	t.Parallel()
 
@@ -505,6 +555,167 @@ func TestTaskDeferral(t *testing.T) {
 	}
 }
 
+func TestClearTasks(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		breakSave bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error if the task file cannot be written": {breakSave: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			storage := t.TempDir()
+			taskFile := filepath.Join(storage, d.Name()+".tasks")
+
+			w, err := worker.New(ctx, d, storage)
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			wslInstanceService := newTestService(t)
+			conn := wslInstanceService.newClientConnection(t)
+			w.SetConnection(conn)
+
+			// blocker is a task meant to block task processing
+			blocker := newBlockingTask(ctx)
+			defer blocker.complete()
+
+			err = w.SubmitTasks(blocker, emptyTask{ID: uuid.NewString()})
+			require.NoError(t, err, "Setup: SubmitTasks should have succeeded")
+
+			err = w.SubmitDeferredTasks(emptyTask{ID: uuid.NewString()})
+			require.NoError(t, err, "Setup: SubmitDeferredTasks should have succeeded")
+
+			require.Eventually(t, blocker.executing.Load, 10*time.Second, 100*time.Millisecond, "Setup: Blocking task was never popped from queue")
+
+			if tc.breakSave {
+				testutils.ReplaceFileWithDir(t, taskFile, "Setup: could not replace task file with dir to interfere with ClearTasks")
+			}
+
+			err = w.ClearTasks()
+			if tc.wantErr {
+				require.Error(t, err, "ClearTasks should have returned an error")
+				return
+			}
+			require.NoError(t, err, "ClearTasks should have succeeded")
+
+			require.NoError(t, w.CheckTotalTaskCount(0), "All tasks should have been removed")
+			require.Equal(t, 1, w.PendingTasks(), "The task being processed should not have been interrupted")
+
+			out, err := os.ReadFile(taskFile)
+			require.NoError(t, err, "Could not read the task file")
+			tasks, err := task.UnmarshalYAML(out)
+			require.NoError(t, err, "Could not parse the task file")
+			require.Empty(t, tasks, "All tasks should have been removed from storage")
+		})
+	}
+}
+
+func TestCancelTask(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		cancel string
+
+		wantRemaining []string
+		wantCancelled []string
+		wantErr       error
+	}{
+		"Success cancelling a queued task":   {cancel: "plain", wantRemaining: []string{"prerequisite", "dependent", "deferred"}, wantCancelled: []string{"Empty test task"}},
+		"Success cancelling a deferred task": {cancel: "deferred", wantRemaining: []string{"plain", "prerequisite", "dependent"}, wantCancelled: []string{"Empty test task"}},
+		"Success cancelling the dependents of a task": {cancel: "prerequisite", wantRemaining: []string{"plain", "deferred"},
+			wantCancelled: []string{"Test task", "Dependent test task"}},
+
+		"Error when the task is not queued": {cancel: "not-a-task", wantRemaining: []string{"plain", "prerequisite", "dependent", "deferred"}, wantErr: worker.ErrTaskNotQueued},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			w, err := worker.New(ctx, d, t.TempDir())
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			wslInstanceService := newTestService(t)
+			w.SetConnection(wslInstanceService.newClientConnection(t))
+
+			// blocker is a task meant to keep the other tasks in the queue.
+			blocker := newBlockingTask(ctx)
+			defer blocker.complete()
+
+			require.NoError(t, w.SubmitTasks(blocker), "Setup: SubmitTasks should have succeeded")
+			require.Eventually(t, blocker.executing.Load, 10*time.Second, 100*time.Millisecond, "Setup: Blocking task was never popped from queue")
+
+			prerequisite := &testTask{ID: uuid.NewString()}
+			err = w.SubmitTasks(emptyTask{ID: uuid.NewString()}, prerequisite, dependentTask{ID: uuid.NewString(), Prerequisite: prerequisite.ID})
+			require.NoError(t, err, "Setup: SubmitTasks should have succeeded")
+			require.NoError(t, w.SubmitDeferredTasks(emptyTask{ID: uuid.NewString()}), "Setup: SubmitDeferredTasks should have succeeded")
+
+			// The tasks are named after their position in the queue.
+			ids := make(map[string]string)
+			queued := w.QueuedTasks()
+			require.Len(t, queued, 4, "All the tasks but the one in progress should be listed")
+			for i, name := range []string{"plain", "prerequisite", "dependent", "deferred"} {
+				require.NotEmpty(t, queued[i].ID, "Queued tasks should have an ID")
+				require.Equal(t, name == "deferred", queued[i].Deferred, "Only the deferred task should be listed as such")
+				require.False(t, queued[i].Submitted.IsZero(), "Queued tasks should have a submission time")
+				require.Zero(t, queued[i].Attempts, "Queued tasks should not have been attempted yet")
+				ids[name] = queued[i].ID
+			}
+
+			id, ok := ids[tc.cancel]
+			if !ok {
+				id = tc.cancel
+			}
+
+			err = w.CancelTask(ctx, id)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr, "CancelTask should have returned the expected error")
+			} else {
+				require.NoError(t, err, "CancelTask should have succeeded")
+			}
+
+			var remaining []string
+			for _, q := range w.QueuedTasks() {
+				remaining = append(remaining, q.ID)
+			}
+			var wantRemaining []string
+			for _, name := range tc.wantRemaining {
+				wantRemaining = append(wantRemaining, ids[name])
+			}
+			require.Equal(t, wantRemaining, remaining, "Only the cancelled task and its dependents should have left the queue")
+
+			var cancelled []string
+			for _, r := range w.TaskHistory() {
+				cancelled = append(cancelled, r.Task)
+			}
+			require.Equal(t, tc.wantCancelled, cancelled, "The cancelled tasks should be recorded in the history")
+		})
+	}
+}
+
 func TestTaskDeduplication(t *testing.T) {
 	t.Parallel()
 
@@ -522,104 +733,1129 @@ func TestTaskDeduplication(t *testing.T) {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
-			d := &testDistro{
-				name: wsltestutils.RandomDistroName(t),
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			storage := t.TempDir()
+
+			w, err := worker.New(ctx, d, storage)
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			wslInstanceService := newTestService(t)
+			conn := wslInstanceService.newClientConnection(t)
+			w.SetConnection(conn)
+
+			// These are equivalent, they should be de-duplicated
+			blocker := newBlockingTask(ctx)
+
+			var task1 task.Task = emptyTask{ID: "123"}
+			var taskEq task.Task = emptyTask{ID: "123"}
+
+			if tc.taskWithIs {
+				// Different delays to ensure it is the "Is" that is making the comparison
+				task1 = &testTask{ID: "ABC", Delay: time.Second}
+				taskEq = &testTask{ID: "ABC", Delay: 5 * time.Second}
+			}
+
+			err = w.SubmitTasks(blocker)
+			require.NoError(t, err, "SubmitTasks should return no error")
+			require.Eventually(t, blocker.executing.Load, 5*time.Second, 100*time.Millisecond, "Blocker task was never dequeued")
+
+			// Unique task: normal submission
+			err = w.SubmitTasks(task1)
+			require.NoError(t, err, "SubmitTasks should return no error")
+			require.NoError(t, w.CheckQueuedTaskCount(1), "Submitting a task should add it to the queue")
+			require.NoError(t, w.CheckTotalTaskCount(1), "Submitting a task should increase the total task count by one")
+
+			// Unique task: normal submission
+			err = w.SubmitTasks(emptyTask{ID: "hello!"})
+			require.NoError(t, err, "SubmitTasks should return no error")
+			require.NoError(t, w.CheckQueuedTaskCount(2), "Submitting a second task should add it to the queue")
+			require.NoError(t, w.CheckTotalTaskCount(2), "Submitting a second task should increase the total task count by one")
+
+			// Check that re-submitting a task removes the old one
+			err = w.SubmitTasks(taskEq)
+			require.NoError(t, err, "SubmitTasks should return no error")
+			require.NoError(t, w.CheckQueuedTaskCount(2), "Submitting a repeated task should not change the queue size")
+			require.NoError(t, w.CheckTotalTaskCount(2), "Submitting a repeated task should not change the task count")
+
+			// Check that re-submitting a task as deferred removes the old one
+			err = w.SubmitDeferredTasks(taskEq)
+			require.NoError(t, err, "SubmitDeferredTasks should return no error")
+			require.NoError(t, w.CheckQueuedTaskCount(1), "Submitting a repeated deferred task should decrease the queue size by one")
+			require.NoError(t, w.CheckTotalTaskCount(2), "Submitting a repeated deferred task should not change the total task count")
+
+			// Check that re-submitting a deferred task removes the old one
+			// This caused https://warthogs.atlassian.net/browse/UDENG-1848
+			err = w.SubmitTasks(taskEq)
+			require.NoError(t, err, "SubmitTasks should return no error")
+			require.NoError(t, w.CheckQueuedTaskCount(2), "Submitting a task that was already deferred should increase the queue size by one")
+			require.NoError(t, w.CheckTotalTaskCount(2), "Submitting a task that was already deferred should not change the total task count")
+		})
+	}
+}
+
+func TestFailedTaskIsDeferred(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := &testDistro{
+		name: wsltestutils.RandomDistroName(t),
+	}
+
+	storage := t.TempDir()
+
+	w, err := worker.New(ctx, d, storage)
+	require.NoError(t, err, "Setup: unexpected error creating the worker")
+	defer w.Stop(ctx)
+
+	wslInstanceService := newTestService(t)
+	conn := wslInstanceService.newClientConnection(t)
+	w.SetConnection(conn)
+
+	// Submit the failing task
+	failingTask := testTask{Returns: task.NeedsRetryError{SourceErr: errors.New("mock error")}}
+	err = w.SubmitTasks(&failingTask)
+	require.NoError(t, err, "SubmitTasks should return no error")
+
+	require.Eventually(t, func() bool {
+		return failingTask.ExecuteCalls.Load() == 1
+	}, 5*time.Second, 100*time.Millisecond, "Task should have started executing")
+	require.NoError(t, w.CheckQueuedTaskCount(0), "Task should have been popped from the queue")
+
+	require.Eventually(t, func() bool {
+		return w.CheckTotalTaskCount(1) == nil
+	}, 5*time.Second, 100*time.Millisecond, "Failing task should have been re-submitted after failure")
+	require.NoError(t, w.CheckQueuedTaskCount(0), "Task should not have been submitted into the queue, but rather deferred")
+}
+
+func TestTaskIsRequeuedAfterDistroShutdown(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err error
+
+		wantRequeued bool
+	}{
+		"Task is requeued when the connection is lost":           {err: status.Error(codes.Unavailable, "mock connection lost"), wantRequeued: true},
+		"Task is requeued when the connection is closed":         {err: status.Error(codes.Canceled, "mock connection closing"), wantRequeued: true},
+		"Retriable task is requeued when the connection is lost": {err: task.NeedsRetryError{SourceErr: status.Error(codes.Unavailable, "mock connection lost")}, wantRequeued: true},
+
+		"Task is not requeued when it fails for another reason": {err: status.Error(codes.Internal, "mock error")},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			w, err := worker.New(ctx, d, t.TempDir(), worker.WithTimeouts(timeouts.Policy{ShutdownPause: time.Millisecond}))
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			wslInstanceService := newTestService(t)
+			w.SetConnection(wslInstanceService.newClientConnection(t))
+
+			tk := &failOnceTask{Returns: tc.err}
+			err = w.SubmitTasks(tk)
+			require.NoError(t, err, "SubmitTasks should return no error")
+
+			require.Eventually(t, func() bool {
+				return tk.ExecuteCalls.Load() >= 1
+			}, 5*time.Second, 100*time.Millisecond, "Task should have started executing")
+
+			if !tc.wantRequeued {
+				time.Sleep(time.Second)
+				require.Equal(t, int32(1), tk.ExecuteCalls.Load(), "Task should not have been retried")
+				require.NoError(t, w.CheckTotalTaskCount(0), "Task should not have been kept")
+				return
+			}
+
+			// The connection was dropped, so the task can only run again once the distro reconnects.
+			require.Eventually(t, func() bool {
+				return !w.IsActive()
+			}, 5*time.Second, 100*time.Millisecond, "Connection should have been marked as closed")
+			require.Equal(t, int32(1), tk.ExecuteCalls.Load(), "Task should not run again before the distro reconnects")
+
+			w.SetConnection(wslInstanceService.newClientConnection(t))
+
+			require.Eventually(t, func() bool {
+				return tk.ExecuteCalls.Load() == 2
+			}, 5*time.Second, 100*time.Millisecond, "Task should have been retried after the distro reconnected")
+			require.Eventually(t, func() bool {
+				return w.CheckTotalTaskCount(0) == nil
+			}, 5*time.Second, 100*time.Millisecond, "Task should have completed after the retry")
+		})
+	}
+}
+
+func TestTaskHistory(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		taskErr    error
+		stopReason worker.CancelReason
+		supersede  bool
+
+		wantErr    bool
+		wantReason worker.CancelReason
+		wantDenied bool
+	}{
+		"Success is recorded":                  {},
+		"Failure is recorded without a reason": {taskErr: errors.New("mock error"), wantErr: true},
+
+		"Denial by the policy of the distro is recorded":               {taskErr: wslserviceapi.NewPolicyDeniedError("ProRefresh", "mock reason"), wantErr: true, wantDenied: true},
+		"Denial by the policy of the distro is recorded without retry": {taskErr: task.NeedsRetryError{SourceErr: wslserviceapi.NewPolicyDeniedError("ProRefresh", "")}, wantErr: true, wantDenied: true},
+
+		"Cancellation by agent shutdown is recorded":      {stopReason: worker.CancelAgentShutdown, wantErr: true, wantReason: worker.CancelAgentShutdown},
+		"Cancellation by distro unregistered is recorded": {stopReason: worker.CancelDistroUnregistered, wantErr: true, wantReason: worker.CancelDistroUnregistered},
+		"Cancellation by a newer task is recorded":        {supersede: true, wantErr: true, wantReason: worker.CancelSuperseded},
+		"Cancellation by timeout is recorded":             {taskErr: status.Error(codes.DeadlineExceeded, "mock timeout"), wantErr: true, wantReason: worker.CancelTimeout},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			w, err := worker.New(ctx, d, t.TempDir())
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			wslInstanceService := newTestService(t)
+			w.SetConnection(wslInstanceService.newClientConnection(t))
+
+			if tc.supersede {
+				blocker := newBlockingTask(ctx)
+				err = w.SubmitTasks(blocker)
+				require.NoError(t, err, "Setup: SubmitTasks should return no error")
+				require.Eventually(t, blocker.executing.Load, 5*time.Second, 100*time.Millisecond, "Blocker task was never dequeued")
+
+				err = w.SubmitTasks(&testTask{ID: "A"})
+				require.NoError(t, err, "Setup: SubmitTasks should return no error")
+				err = w.SubmitTasks(&testTask{ID: "A"})
+				require.NoError(t, err, "Setup: SubmitTasks should return no error")
+			} else {
+				tk := &testTask{Returns: tc.taskErr}
+				if tc.stopReason != "" {
+					tk.Delay = time.Hour
+				}
+
+				err = w.SubmitTasks(tk)
+				require.NoError(t, err, "Setup: SubmitTasks should return no error")
+				require.Eventually(t, func() bool {
+					return tk.ExecuteCalls.Load() == 1
+				}, 5*time.Second, 100*time.Millisecond, "Task should have started executing")
+
+				if tc.stopReason != "" {
+					w.StopWithReason(ctx, tc.stopReason)
+				}
+			}
+
+			var history []worker.TaskRecord
+			require.Eventually(t, func() bool {
+				history = w.TaskHistory()
+				return len(history) > 0
+			}, 5*time.Second, 100*time.Millisecond, "The task should have been recorded in the history")
+			require.Len(t, history, 1, "Only one task should have been recorded in the history")
+
+			got := history[0]
+			require.Equal(t, "Test task", got.Task, "The task should have been recorded with its description")
+			require.False(t, got.Finished.IsZero(), "The time the task finished should have been recorded")
+			require.Equal(t, tc.wantReason, got.Cancelled, "Unexpected cancellation reason")
+			require.Equal(t, tc.wantDenied, got.PolicyDenied, "Unexpected denial by the policy of the distro")
+			if tc.wantErr {
+				require.NotEmpty(t, got.Err, "The error of the task should have been recorded")
+			} else {
+				require.Empty(t, got.Err, "No error should have been recorded")
+			}
+		})
+	}
+}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		taskErr error
+
+		wantLast string
+		wantErr  bool
+	}{
+		"Success is reported as finished": {wantLast: "finish"},
+		"Failure is reported as finished": {taskErr: errors.New("mock error"), wantLast: "finish", wantErr: true},
+		"Retry is reported as retried":    {taskErr: task.NeedsRetryError{SourceErr: errors.New("mock error")}, wantLast: "retry", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			rec := &hookRecorder{}
+			other := &hookRecorder{}
+
+			w, err := worker.New(ctx, d, t.TempDir(), worker.WithHooks(rec.hooks()), worker.WithHooks(other.hooks()))
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			wslInstanceService := newTestService(t)
+			w.SetConnection(wslInstanceService.newClientConnection(t))
+
+			err = w.SubmitTasks(&testTask{Returns: tc.taskErr})
+			require.NoError(t, err, "SubmitTasks should return no error")
+
+			require.Eventually(t, func() bool {
+				return len(rec.get()) == 3
+			}, 5*time.Second, 100*time.Millisecond, "The task should have been reported as queued, started and done")
+
+			events := rec.get()
+			var kinds []string
+			for _, e := range events {
+				kinds = append(kinds, e.kind)
+				require.Equal(t, d.Name(), e.distro, "The hooks should be told the name of the distro")
+				require.Equal(t, events[0].task.ID, e.task.ID, "Every hook should be told about the same task")
+				require.Equal(t, "Test task", e.task.Task, "The hooks should be told the description of the task")
+			}
+			require.Equal(t, []string{"enqueue", "start", tc.wantLast}, kinds, "The hooks were not called in the expected order")
+			require.Equal(t, 1, events[1].task.Attempts, "The task should be reported as started once")
+
+			if tc.wantErr {
+				require.Error(t, events[2].err, "The error of the task should be reported")
+			} else {
+				require.NoError(t, events[2].err, "No error should be reported")
+			}
+
+			require.Equal(t, events, other.get(), "Every set of hooks should have been called")
+		})
+	}
+}
+
+func TestInjectedDependencies(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		loadErr bool
+		saveErr bool
+
+		wantNewErr    bool
+		wantSubmitErr bool
+	}{
+		"Success running a task without a storage directory nor a WSL service": {},
+
+		"Error when the storage cannot load the tasks": {loadErr: true, wantNewErr: true},
+		"Error when the storage cannot save the tasks": {saveErr: true, wantSubmitErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{name: wsltestutils.RandomDistroName(t)}
+			storage := &storageMock{loadErr: tc.loadErr, saveErr: tc.saveErr}
+			conns := &connFactoryMock{}
+			clk := clock.NewMock(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC))
+
+			w, err := worker.New(ctx, d, "", worker.WithTaskStorage(storage), worker.WithConnFactory(conns), worker.WithClock(clk))
+			if tc.wantNewErr {
+				require.Error(t, err, "New should return an error when the storage cannot load the tasks")
+				return
+			}
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			// The connection is never dialed: the factory replaces it.
+			w.SetConnection(connection.New("localhost:0"))
+
+			tk := emptyTask{ID: uuid.NewString()}
+			err = w.SubmitTasks(tk)
+			if tc.wantSubmitErr {
+				require.Error(t, err, "SubmitTasks should return an error when the storage cannot save the tasks")
+				return
+			}
+			require.NoError(t, err, "SubmitTasks should return no error")
+
+			require.Eventually(t, func() bool {
+				return completedEmptyTasks.Has(tk.ID)
+			}, 5*time.Second, 100*time.Millisecond, "The task should have run")
+			require.Eventually(t, func() bool {
+				return len(w.TaskHistory()) == 1
+			}, 5*time.Second, 100*time.Millisecond, "The task should have been recorded in the history")
+
+			require.Positive(t, conns.opened.Load(), "The task should have used the connection factory")
+			require.Equal(t, clk.Now(), w.TaskHistory()[0].Finished, "The task should have been timed with the injected clock")
+			require.Positive(t, storage.saves.Load(), "The queue should have been saved to the injected storage")
+			require.NoError(t, w.CheckTotalTaskCount(0), "No task should remain in storage")
+		})
+	}
+}
+
+func TestTaskWindow(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := &testDistro{
+		name: wsltestutils.RandomDistroName(t),
+	}
+
+	schedule := &scheduleMock{}
+	w, err := worker.New(ctx, d, t.TempDir(), worker.WithSchedule(schedule))
+	require.NoError(t, err, "Setup: unexpected error creating the worker")
+	defer w.Stop(ctx)
+
+	wslInstanceService := newTestService(t)
+	w.SetConnection(wslInstanceService.newClientConnection(t))
+
+	regular := emptyTask{ID: uuid.NewString()}
+	urgent := urgentTask{ID: uuid.NewString()}
+
+	err = w.SubmitTasks(regular, urgent)
+	require.NoError(t, err, "SubmitTasks should return no error")
+
+	requireEventuallyTaskCompletes(t, emptyTask(urgent), "Urgent task should run outside of the task window")
+
+	time.Sleep(time.Second)
+	require.False(t, completedEmptyTasks.Has(regular.ID), "Regular task should not run outside of the task window")
+	require.NoError(t, w.CheckQueuedTaskCount(1), "Regular task should stay in the queue outside of the task window")
+
+	schedule.open.Store(true)
+	requireEventuallyTaskCompletes(t, regular, "Regular task should run once the task window opens")
+}
+
+func TestPause(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := &testDistro{
+		name: wsltestutils.RandomDistroName(t),
+	}
+
+	pauser := pause.New(true)
+	w, err := worker.New(ctx, d, t.TempDir(), worker.WithPauser(pauser))
+	require.NoError(t, err, "Setup: unexpected error creating the worker")
+	defer w.Stop(ctx)
+
+	wslInstanceService := newTestService(t)
+	w.SetConnection(wslInstanceService.newClientConnection(t))
+
+	regular := emptyTask{ID: uuid.NewString()}
+	urgent := urgentTask{ID: uuid.NewString()}
+
+	err = w.SubmitTasks(regular, urgent)
+	require.NoError(t, err, "SubmitTasks should return no error")
+
+	time.Sleep(time.Second)
+	require.False(t, completedEmptyTasks.Has(regular.ID), "Regular task should not run while paused")
+	require.False(t, completedEmptyTasks.Has(urgent.ID), "Urgent task should not run while paused")
+	require.NoError(t, w.CheckQueuedTaskCount(2), "Tasks should stay in the queue while paused")
+
+	pauser.Set(false)
+	requireEventuallyTaskCompletes(t, regular, "Regular task should run once resumed")
+	requireEventuallyTaskCompletes(t, emptyTask(urgent), "Urgent task should run once resumed")
+
+	// Pausing again holds back the tasks submitted afterwards.
+	pauser.Set(true)
+	next := emptyTask{ID: uuid.NewString()}
+	err = w.SubmitTasks(next)
+	require.NoError(t, err, "SubmitTasks should return no error")
+
+	time.Sleep(time.Second)
+	require.False(t, completedEmptyTasks.Has(next.ID), "Task should not run after pausing again")
+
+	pauser.Set(false)
+	requireEventuallyTaskCompletes(t, next, "Task should run once resumed again")
+}
+
+func TestSharedTasks(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := &testDistro{
+		name: wsltestutils.RandomDistroName(t),
+	}
+
+	w, err := worker.New(ctx, d, t.TempDir())
+	require.NoError(t, err, "Setup: unexpected error creating the worker")
+	defer w.Stop(ctx)
+
+	wslInstanceService := newTestService(t)
+	w.SetConnection(wslInstanceService.newClientConnection(t))
+
+	blocker := newBlockingTask(ctx)
+	defer blocker.complete()
+
+	require.NoError(t, w.SubmitTasks(blocker), "Setup: SubmitTasks should return no error")
+	require.Eventually(t, blocker.executing.Load, 5*time.Second, 100*time.Millisecond, "Setup: Blocking task was never popped from queue")
+
+	exclusive := emptyTask{ID: uuid.NewString()}
+	shared := sharedTask{ID: uuid.NewString()}
+
+	err = w.SubmitTasks(exclusive, shared)
+	require.NoError(t, err, "SubmitTasks should return no error")
+
+	requireEventuallyTaskCompletes(t, emptyTask(shared), "Shared task should run while the exclusive task is running")
+	require.True(t, blocker.executing.Load(), "Shared task should not wait for the exclusive task to complete")
+
+	time.Sleep(time.Second)
+	require.False(t, completedEmptyTasks.Has(exclusive.ID), "Exclusive task should not run while another exclusive task is running")
+	require.True(t, w.TaskInProgress(), "The exclusive task should still be in progress")
+	require.Equal(t, 2, w.PendingTasks(), "The running task and the queued exclusive task should be pending")
+
+	blocker.complete()
+	requireEventuallyTaskCompletes(t, exclusive, "Exclusive task should run once the previous one completes")
+}
+
+func TestWSLUnavailable(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := &testDistro{
+		name: wsltestutils.RandomDistroName(t),
+	}
+	d.unreachable.Store(true)
+
+	var wslDown atomic.Bool
+	wslDown.Store(true)
+	watcher := wslwatch.New(wslwatch.WithProbe(func(context.Context) error {
+		if wslDown.Load() {
+			return errors.New("mock error")
+		}
+		return nil
+	}))
+
+	w, err := worker.New(ctx, d, t.TempDir(), worker.WithPauser(watcher), worker.WithWSLWatcher(watcher))
+	require.NoError(t, err, "Setup: unexpected error creating the worker")
+	defer w.Stop(ctx)
+
+	w.SetConnection(newTestService(t).newClientConnection(t))
+
+	tk := emptyTask{ID: uuid.NewString()}
+	require.NoError(t, w.SubmitTasks(tk), "SubmitTasks should return no error")
+
+	require.Eventually(t, watcher.Paused, 5*time.Second, 10*time.Millisecond, "WSL should be found unavailable when the task cannot reach the distro")
+	require.Eventually(t, func() bool { return w.CheckQueuedTaskCount(1) == nil }, 5*time.Second, 10*time.Millisecond, "Task should be put back in the queue while WSL is unavailable")
+	require.True(t, d.IsValid(), "Distro should not be invalidated while WSL is unavailable")
+	require.False(t, completedEmptyTasks.Has(tk.ID), "Task should not run while WSL is unavailable")
+	require.Empty(t, w.TaskHistory(), "Task should not be recorded as done while WSL is unavailable")
+
+	// WSL is back.
+	wslDown.Store(false)
+	d.unreachable.Store(false)
+	require.True(t, watcher.Check(ctx), "Setup: WSL should be available again")
+
+	requireEventuallyTaskCompletes(t, tk, "Task should run once WSL is back")
+	require.True(t, d.IsValid(), "Distro should still be valid")
+}
+
+//nolint:tparallel // The circuit breaker settings are global, so this test cannot run in parallel.
+func TestCircuitBreaker(t *testing.T) {
+	worker.SetCircuitBreaker(t, 2, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := &testDistro{
+		name: wsltestutils.RandomDistroName(t),
+	}
+
+	clk := clock.NewMock(time.Now())
+	w, err := worker.New(ctx, d, t.TempDir(), worker.WithClock(clk))
+	require.NoError(t, err, "Setup: unexpected error creating the worker")
+	defer w.Stop(ctx)
+
+	wslInstanceService := newTestService(t)
+	w.SetConnection(wslInstanceService.newClientConnection(t))
+
+	runTask := func(tk *testTask) {
+		t.Helper()
+		require.NoError(t, w.SubmitTasks(tk), "Setup: SubmitTasks should return no error")
+		require.Eventually(t, func() bool {
+			return tk.ExecuteCalls.Load() == 1 && w.PendingTasks() == 0
+		}, 5*time.Second, 100*time.Millisecond, "Task should have run")
+	}
+
+	// A success in between failures resets the count.
+	runTask(&testTask{ID: uuid.NewString(), Returns: errors.New("mock error")})
+	runTask(&testTask{ID: uuid.NewString()})
+	runTask(&testTask{ID: uuid.NewString(), Returns: errors.New("mock error")})
+	require.False(t, w.Circuit().Open, "Circuit should not open before enough tasks fail in a row")
+	require.Equal(t, 1, w.Circuit().Failures, "Successful tasks should reset the count of failures")
+
+	runTask(&testTask{ID: uuid.NewString(), Returns: errors.New("mock error")})
+	c := w.Circuit()
+	require.True(t, c.Open, "Circuit should open once enough tasks fail in a row")
+	require.Equal(t, 2, c.Failures, "Circuit should count the failures in a row")
+	require.ErrorContains(t, c.LastError, "mock error", "Circuit should remember the last error")
+	require.Equal(t, clk.Now().Add(time.Hour), c.RetryAt, "Circuit should tell when tasks are retried")
+
+	held := emptyTask{ID: uuid.NewString()}
+	require.NoError(t, w.SubmitTasks(held), "SubmitTasks should return no error")
+
+	require.Eventually(t, func() bool { return clk.Waiters() == 1 }, 5*time.Second, 10*time.Millisecond, "The worker should wait for the circuit to let tasks through")
+	require.False(t, completedEmptyTasks.Has(held.ID), "Task should not run while the circuit is open")
+	require.NoError(t, w.CheckQueuedTaskCount(1), "Task should stay in the queue while the circuit is open")
+
+	clk.Advance(time.Minute)
+	require.False(t, completedEmptyTasks.Has(held.ID), "Task should not run before it is time to retry")
+
+	clk.Advance(time.Hour)
+	requireEventuallyTaskCompletes(t, held, "Task should run once it is time to retry")
+	require.Eventually(t, func() bool { return !w.Circuit().Open }, 5*time.Second, 100*time.Millisecond, "Circuit should close once a task succeeds")
+	require.Zero(t, w.Circuit().Failures, "Circuit should forget the failures once a task succeeds")
+}
+
+func TestTaskDependencies(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		prerequisiteErr error
+
+		wantDependentRuns     bool
+		wantDependentDeferred bool
+	}{
+		"Dependent runs after its prerequisite succeeds": {wantDependentRuns: true},
+
+		"Dependent is dropped when its prerequisite fails":            {prerequisiteErr: errors.New("mock error")},
+		"Dependent is deferred when its prerequisite will be retried": {prerequisiteErr: task.NeedsRetryError{SourceErr: errors.New("mock error")}, wantDependentDeferred: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			w, err := worker.New(ctx, d, t.TempDir())
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			wslInstanceService := newTestService(t)
+			w.SetConnection(wslInstanceService.newClientConnection(t))
+
+			prerequisite := &testTask{ID: uuid.NewString(), Delay: 500 * time.Millisecond, Returns: tc.prerequisiteErr}
+			dependent := dependentTask{ID: uuid.NewString(), Prerequisite: prerequisite.ID}
+
+			// The dependent is submitted first to ensure that the order comes from the dependency.
+			err = w.SubmitTasks(dependent, prerequisite)
+			require.NoError(t, err, "SubmitTasks should return no error")
+
+			if tc.wantDependentRuns {
+				requireEventuallyTaskCompletes(t, emptyTask{ID: dependent.ID}, "Dependent task should run after its prerequisite")
+				require.Equal(t, int32(1), prerequisite.ExecuteCalls.Load(), "Prerequisite should have run before the dependent task")
+				return
+			}
+
+			require.Eventually(t, func() bool {
+				return prerequisite.ExecuteCalls.Load() == 1 && w.CheckQueuedTaskCount(0) == nil
+			}, 5*time.Second, 100*time.Millisecond, "Prerequisite should have run and the dependent task should have left the queue")
+
+			time.Sleep(time.Second)
+			require.False(t, completedEmptyTasks.Has(dependent.ID), "Dependent task should not run when its prerequisite fails")
+
+			if tc.wantDependentDeferred {
+				require.NoError(t, w.CheckTotalTaskCount(2), "Dependent task should have been deferred along with its prerequisite")
+				return
+			}
+			require.NoError(t, w.CheckTotalTaskCount(0), "Dependent task should have been dropped")
+		})
+	}
+}
+
+func TestFollowUpTasks(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		submitSelf bool
+		submitLate bool
+
+		wantFollowUp bool
+		wantErr      bool
+	}{
+		"Success submitting a follow-up task": {wantFollowUp: true},
+
+		"Error when a task submits itself":                {submitSelf: true, wantErr: true},
+		"Error when a task submits after it has returned": {submitLate: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			w, err := worker.New(ctx, d, t.TempDir())
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			wslInstanceService := newTestService(t)
+			w.SetConnection(wslInstanceService.newClientConnection(t))
+
+			chaining := chainingTask{ID: uuid.NewString(), FollowUp: uuid.NewString(), SubmitSelf: tc.submitSelf}
+			require.NoError(t, w.SubmitTasks(chaining), "Setup: SubmitTasks should have succeeded")
+
+			requireEventuallyTaskCompletes(t, emptyTask{ID: chaining.ID}, "Chaining task should have run")
+
+			err = chaining.submitted()
+			if tc.submitLate {
+				require.NoError(t, err, "Setup: submitting during the execution should have succeeded")
+				err = task.SubmitFollowUps(chaining.executionContext(), emptyTask{ID: uuid.NewString()})
+			}
+
+			if tc.wantErr {
+				require.Error(t, err, "Submitting the follow-up task should have failed")
+			} else {
+				require.NoError(t, err, "Submitting the follow-up task should have succeeded")
+			}
+
+			if tc.wantFollowUp {
+				requireEventuallyTaskCompletes(t, emptyTask{ID: chaining.FollowUp}, "Follow-up task should run after the task that submitted it")
+			}
+			require.Eventually(t, func() bool { return w.CheckTotalTaskCount(0) == nil }, 5*time.Second, 100*time.Millisecond,
+				"No task should be left in the queue")
+		})
+	}
+}
+
+func TestCallQueue(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		shortSecondCall bool
+		cancelSecond    bool
+
+		wantSecondQueued bool
+		wantSecondErr    codes.Code
+	}{
+		"Long calls are made one at a time": {wantSecondQueued: true},
+		"Short calls are not queued":        {shortSecondCall: true},
+
+		"Error when a queued call is cancelled": {cancelSecond: true, wantSecondQueued: true, wantSecondErr: codes.Canceled},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			w, err := worker.New(ctx, d, t.TempDir())
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			service := newBlockingService(t)
+			w.SetConnection(service.newClientConnection(t))
+
+			firstErr := make(chan error, 1)
+			go func() {
+				_, err := w.Client().ApplyProToken(ctx, &wslserviceapi.ProAttachInfo{})
+				firstErr <- err
+			}()
+
+			select {
+			case <-service.started:
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "Setup: first call never reached the service")
+			}
+
+			// Each call uses a different client, as different tasks would.
+			secondCtx, secondCancel := context.WithCancel(ctx)
+			defer secondCancel()
+
+			secondErr := make(chan error, 1)
+			go func() {
+				var err error
+				if tc.shortSecondCall {
+					_, err = w.Client().Ping(secondCtx, &wslserviceapi.Empty{})
+				} else {
+					_, err = w.Client().ApplyProToken(secondCtx, &wslserviceapi.ProAttachInfo{})
+				}
+				secondErr <- err
+			}()
+
+			if !tc.wantSecondQueued {
+				select {
+				case err := <-secondErr:
+					require.NoError(t, err, "Second call should return no error")
+				case <-time.After(5 * time.Second):
+					require.Fail(t, "Second call should not wait for the first one")
+				}
+			} else {
+				select {
+				case <-secondErr:
+					require.Fail(t, "Second call should wait for the first one to finish")
+				case <-time.After(500 * time.Millisecond):
+				}
+			}
+
+			if tc.cancelSecond {
+				secondCancel()
+				select {
+				case err := <-secondErr:
+					require.Equal(t, tc.wantSecondErr, status.Code(err), "Unexpected error for the cancelled call: %v", err)
+				case <-time.After(5 * time.Second):
+					require.Fail(t, "Cancelled call should return right away")
+				}
+			}
+
+			close(service.release)
+
+			require.NoError(t, <-firstErr, "First call should return no error")
+			if tc.wantSecondQueued && !tc.cancelSecond {
+				require.NoError(t, <-secondErr, "Second call should return no error once the first one is done")
+			}
+
+			require.Equal(t, int32(1), service.maxRunning.Load(), "Only one long-running call should have reached the service at a time")
+		})
+	}
+}
+
+func TestWakeStrategy(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		wake worker.WakeStrategy
+
+		wantExecuted bool
+	}{
+		"Success starting the task as soon as the distro connects": {wake: worker.WakeOnConnection, wantExecuted: true},
+		"Success waiting for the next tick to start the task":      {wake: worker.WakeOnTick},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			// The tick is long enough for the task to be executed only if it is woken up by the connection.
+			w, err := worker.New(ctx, d, t.TempDir(), worker.WithWakeStrategy(tc.wake), worker.WithTimeouts(timeouts.Policy{
+				ClientWait:     time.Hour,
+				ClientWaitTick: time.Hour,
+			}))
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			ttask := &testTask{}
+			require.NoError(t, w.SubmitTasks(ttask), "Setup: SubmitTasks should return no error")
+
+			// Giving the task time to start waiting for the distro.
+			time.Sleep(200 * time.Millisecond)
+			require.Zero(t, ttask.ExecuteCalls.Load(), "The task should not be executed without a connection")
+
+			w.SetConnection(newTestService(t).newClientConnection(t))
+
+			if !tc.wantExecuted {
+				time.Sleep(time.Second)
+				require.Zero(t, ttask.ExecuteCalls.Load(), "The task should wait for the next tick")
+				return
+			}
+
+			require.Eventually(t, func() bool { return ttask.ExecuteCalls.Load() == 1 }, time.Second, 10*time.Millisecond,
+				"The task should start as soon as the distro connects")
+		})
+	}
+}
+
+func TestClientWaitTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	d := &testDistro{
+		name: wsltestutils.RandomDistroName(t),
+	}
+
+	clk := clock.NewMock(time.Now())
+	w, err := worker.New(ctx, d, t.TempDir(), worker.WithClock(clk))
+	require.NoError(t, err, "Setup: unexpected error creating the worker")
+	defer w.Stop(ctx)
+
+	ttask := &testTask{}
+	require.NoError(t, w.SubmitTasks(ttask), "Setup: SubmitTasks should return no error")
+
+	require.Eventually(t, func() bool { return clk.Waiters() > 0 }, 5*time.Second, 10*time.Millisecond,
+		"The task should wait for the distro to connect")
+	require.True(t, d.IsValid(), "The distro should not be considered unreachable before the client wait times out")
+
+	// The distro never connects. The wait is retried a few times before giving up.
+	require.Eventually(t, func() bool {
+		clk.Advance(timeouts.Default().ClientWait)
+		return !d.IsValid()
+	}, 5*time.Second, 10*time.Millisecond, "The distro should be considered unreachable once the client wait times out")
+	require.Zero(t, ttask.ExecuteCalls.Load(), "The task should not have been executed without a connection")
+}
+
+func TestCallBudget(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		shortCall bool
+	}{
+		"Long call is cancelled when it exceeds its budget":  {},
+		"Short call is cancelled when it exceeds its budget": {shortCall: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			d := &testDistro{
+				name: wsltestutils.RandomDistroName(t),
+			}
+
+			w, err := worker.New(ctx, d, t.TempDir(), worker.WithTimeouts(timeouts.Policy{
+				ShortCall: 500 * time.Millisecond,
+				LongCall:  500 * time.Millisecond,
+			}))
+			require.NoError(t, err, "Setup: unexpected error creating the worker")
+			defer w.Stop(ctx)
+
+			// The service never releases the call.
+			service := newBlockingService(t)
+			w.SetConnection(service.newClientConnection(t))
+
+			if tc.shortCall {
+				_, err = w.Client().SetLocale(ctx, &wslserviceapi.Locale{})
+			} else {
+				_, err = w.Client().ApplyProToken(ctx, &wslserviceapi.ProAttachInfo{})
+			}
+			require.Equal(t, codes.DeadlineExceeded, status.Code(err), "Call should have exceeded its budget: %v", err)
+
+			// A call that was cancelled does not hold the queue.
+			close(service.release)
+			_, err = w.Client().ApplyProToken(ctx, &wslserviceapi.ProAttachInfo{})
+			require.NoError(t, err, "Call after the cancelled one should return no error")
+		})
+	}
+}
+
+func TestTaskProcessingUnderLoad(t *testing.T) {
+	if testing.Short() {
+		t.Skip("This test is too slow for short mode")
+	}
+
+	// Not parallel because it silences the logs, which would otherwise dominate the run time.
+	setLogLevel(t, log.WarnLevel)
+
+	testCases := map[string]struct {
+		distros        int
+		tasksPerDistro int
+		oneAtATime     bool // Waits for every task to complete before submitting the next one
+	}{
+		"Success with one distro and many tasks":  {distros: 1, tasksPerDistro: 2000},
+		"Success with many distros and few tasks": {distros: 300, tasksPerDistro: 5},
+		"Success with many distros and tasks":     {distros: 100, tasksPerDistro: 50},
+		"Success submitting tasks one at a time":  {distros: 1, tasksPerDistro: 500, oneAtATime: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			h := newLoadHarness(ctx, t, tc.distros)
+
+			if tc.oneAtATime {
+				for range tc.tasksPerDistro {
+					h.submit(t, 1)
+					h.requireAllDone(t, 5*time.Second)
+				}
 			}
 
-			storage := t.TempDir()
+			h.submit(t, tc.tasksPerDistro)
+			h.requireAllDone(t, time.Minute)
 
-			w, err := worker.New(ctx, d, storage)
-			require.NoError(t, err, "Setup: unexpected error creating the worker")
-			defer w.Stop(ctx)
+			for _, w := range h.workers {
+				require.NoError(t, w.CheckTotalTaskCount(0), "All tasks should have been processed")
+			}
+		})
+	}
+}
 
-			wslInstanceService := newTestService(t)
-			conn := wslInstanceService.newClientConnection(t)
-			w.SetConnection(conn)
+func BenchmarkTaskProcessing(b *testing.B) {
+	setLogLevel(b, log.WarnLevel)
 
-			// These are equivalent, they should be de-duplicated
-			blocker := newBlockingTask(ctx)
+	for _, distros := range []int{1, 10, 100, 500} {
+		b.Run(fmt.Sprintf("distros=%d", distros), func(b *testing.B) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
-			var task1 task.Task = emptyTask{ID: "123"}
-			var taskEq task.Task = emptyTask{ID: "123"}
+			h := newLoadHarness(ctx, b, distros)
 
-			if tc.taskWithIs {
-				// Different delays to ensure it is the "Is" that is making the comparison
-				task1 = &testTask{ID: "ABC", Delay: time.Second}
-				taskEq = &testTask{ID: "ABC", Delay: 5 * time.Second}
-			}
+			// Each iteration is one task, spread across all distros.
+			b.ResetTimer()
+			h.submitTotal(b, b.N)
+			h.requireAllDone(b, 10*time.Minute)
+			b.StopTimer()
 
-			err = w.SubmitTasks(blocker)
-			require.NoError(t, err, "SubmitTasks should return no error")
-			require.Eventually(t, blocker.executing.Load, 5*time.Second, 100*time.Millisecond, "Blocker task was never dequeued")
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "tasks/s")
+		})
+	}
+}
 
-			// Unique task: normal submission
-			err = w.SubmitTasks(task1)
-			require.NoError(t, err, "SubmitTasks should return no error")
-			require.NoError(t, w.CheckQueuedTaskCount(1), "Submitting a task should add it to the queue")
-			require.NoError(t, w.CheckTotalTaskCount(1), "Submitting a task should increase the total task count by one")
+func BenchmarkSubmitTasks(b *testing.B) {
+	setLogLevel(b, log.WarnLevel)
 
-			// Unique task: normal submission
-			err = w.SubmitTasks(emptyTask{ID: "hello!"})
-			require.NoError(t, err, "SubmitTasks should return no error")
-			require.NoError(t, w.CheckQueuedTaskCount(2), "Submitting a second task should add it to the queue")
-			require.NoError(t, w.CheckTotalTaskCount(2), "Submitting a second task should increase the total task count by one")
+	for _, queued := range []int{0, 100, 1000} {
+		b.Run(fmt.Sprintf("queued=%d", queued), func(b *testing.B) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
-			// Check that re-submitting a task removes the old one
-			err = w.SubmitTasks(taskEq)
-			require.NoError(t, err, "SubmitTasks should return no error")
-			require.NoError(t, w.CheckQueuedTaskCount(2), "Submitting a repeated task should not change the queue size")
-			require.NoError(t, w.CheckTotalTaskCount(2), "Submitting a repeated task should not change the task count")
+			// Without a connection, tasks pile up in the queue instead of being processed.
+			w, err := worker.New(ctx, &testDistro{name: "BenchmarkDistro"}, b.TempDir())
+			require.NoError(b, err, "Setup: worker New() should return no error")
+			defer w.Stop(ctx)
 
-			// Check that re-submitting a task as deferred removes the old one
-			err = w.SubmitDeferredTasks(taskEq)
-			require.NoError(t, err, "SubmitDeferredTasks should return no error")
-			require.NoError(t, w.CheckQueuedTaskCount(1), "Submitting a repeated deferred task should decrease the queue size by one")
-			require.NoError(t, w.CheckTotalTaskCount(2), "Submitting a repeated deferred task should not change the total task count")
+			for i := range queued {
+				err := w.SubmitTasks(loadTask{ID: fmt.Sprintf("queued-%d", i)})
+				require.NoError(b, err, "Setup: SubmitTasks should return no error")
+			}
 
-			// Check that re-submitting a deferred task removes the old one
-			// This caused https://warthogs.atlassian.net/browse/UDENG-1848
-			err = w.SubmitTasks(taskEq)
-			require.NoError(t, err, "SubmitTasks should return no error")
-			require.NoError(t, w.CheckQueuedTaskCount(2), "Submitting a task that was already deferred should increase the queue size by one")
-			require.NoError(t, w.CheckTotalTaskCount(2), "Submitting a task that was already deferred should not change the total task count")
+			b.ResetTimer()
+			for i := range b.N {
+				// Submitting the same tasks over and over keeps the queue length constant.
+				err := w.SubmitTasks(loadTask{ID: fmt.Sprintf("queued-%d", i%max(queued, 1))})
+				require.NoError(b, err, "SubmitTasks should return no error")
+			}
 		})
 	}
 }
 
-func TestFailedTaskIsDeferred(t *testing.T) {
-	t.Parallel()
+// loadHarness is a set of workers whose distros are all connected to the same WSL service,
+// used to generate load on the task engine.
+type loadHarness struct {
+	workers []*worker.Worker
+	done    *atomic.Int64
+	want    int64
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+func newLoadHarness(ctx context.Context, t testing.TB, distros int) *loadHarness {
+	t.Helper()
 
-	d := &testDistro{
-		name: wsltestutils.RandomDistroName(t),
+	service := newTestService(t)
+	h := &loadHarness{done: &atomic.Int64{}}
+
+	for i := range distros {
+		d := &testDistro{name: fmt.Sprintf("LoadDistro-%03d", i)}
+		conn := service.newClientConnection(t)
+
+		w, err := worker.New(ctx, d, t.TempDir())
+		require.NoError(t, err, "Setup: worker New() should return no error")
+		// Cleanups run in reverse order, so the worker closes the connection before the test does.
+		t.Cleanup(func() { w.Stop(ctx) })
+
+		w.SetConnection(conn)
+		h.workers = append(h.workers, w)
 	}
 
-	storage := t.TempDir()
+	return h
+}
 
-	w, err := worker.New(ctx, d, storage)
-	require.NoError(t, err, "Setup: unexpected error creating the worker")
-	defer w.Stop(ctx)
+// submit concurrently submits n tasks to every worker, one at a time.
+func (h *loadHarness) submit(t testing.TB, n int) {
+	t.Helper()
 
-	wslInstanceService := newTestService(t)
-	conn := wslInstanceService.newClientConnection(t)
-	w.SetConnection(conn)
+	h.submitTotal(t, n*len(h.workers))
+}
 
-	// Submit the failing task
-	failingTask := testTask{Returns: task.NeedsRetryError{SourceErr: errors.New("mock error")}}
-	err = w.SubmitTasks(&failingTask)
-	require.NoError(t, err, "SubmitTasks should return no error")
+// submitTotal concurrently submits n tasks in total, spread evenly across all workers.
+func (h *loadHarness) submitTotal(t testing.TB, n int) {
+	t.Helper()
 
-	require.Eventually(t, func() bool {
-		return failingTask.ExecuteCalls.Load() == 1
-	}, 5*time.Second, 100*time.Millisecond, "Task should have started executing")
-	require.NoError(t, w.CheckQueuedTaskCount(0), "Task should have been popped from the queue")
+	h.want += int64(n)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(h.workers))
+	for i, w := range h.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := i; j < n; j += len(h.workers) {
+				if err := w.SubmitTasks(loadTask{ID: strconv.Itoa(j), done: h.done}); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err, "SubmitTasks should return no error")
+	}
+}
+
+// requireAllDone waits until every submitted task has been processed.
+func (h *loadHarness) requireAllDone(t testing.TB, timeout time.Duration) {
+	t.Helper()
 
 	require.Eventually(t, func() bool {
-		return w.CheckTotalTaskCount(1) == nil
-	}, 5*time.Second, 100*time.Millisecond, "Failing task should have been re-submitted after failure")
-	require.NoError(t, w.CheckQueuedTaskCount(0), "Task should not have been submitted into the queue, but rather deferred")
+		return h.done.Load() >= h.want
+	}, timeout, time.Millisecond, "All tasks should complete. Completed %d out of %d", h.done.Load(), h.want)
+}
+
+// setLogLevel changes the log level for the duration of the test. Tests using it cannot run in parallel.
+func setLogLevel(t testing.TB, level log.Level) {
+	t.Helper()
+
+	orig := log.GetLevel()
+	log.SetLevel(level)
+	t.Cleanup(func() { log.SetLevel(orig) })
 }
 
 func requireEventuallyTaskCompletes(t *testing.T, task emptyTask, msg string, args ...any) {
@@ -642,7 +1878,7 @@ func (s *testService) Ping(context.Context, *wslserviceapi.Empty) (*wslserviceap
 }
 
 // newTestService creates a testService and starts serving asyncronously.
-func newTestService(t *testing.T) *testService {
+func newTestService(t testing.TB) *testService {
 	t.Helper()
 
 	server := grpc.NewServer()
@@ -670,7 +1906,69 @@ func newTestService(t *testing.T) *testService {
 	return &service
 }
 
-func (s testService) newClientConnection(t *testing.T) *grpc.ClientConn {
+// blockingService is a WSL service whose ApplyProToken and SetLocale calls block until released.
+type blockingService struct {
+	testService
+
+	started    chan struct{}
+	release    chan struct{}
+	running    atomic.Int32
+	maxRunning atomic.Int32
+}
+
+// newBlockingService creates a blockingService and starts serving asyncronously.
+func newBlockingService(t *testing.T) *blockingService {
+	t.Helper()
+
+	server := grpc.NewServer()
+
+	lis, err := net.Listen("tcp4", "localhost:")
+	require.NoErrorf(t, err, "Setup: could not listen.")
+
+	//nolint:forcetypeassert // We know it is a TCP address.
+	service := &blockingService{
+		testService: testService{port: uint16(lis.Addr().(*net.TCPAddr).Port)},
+		started:     make(chan struct{}, 10),
+		release:     make(chan struct{}),
+	}
+	wslserviceapi.RegisterWSLServer(server, service)
+
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return service
+}
+
+func (s *blockingService) ApplyProToken(ctx context.Context, _ *wslserviceapi.ProAttachInfo) (*wslserviceapi.Empty, error) {
+	return s.block(ctx)
+}
+
+func (s *blockingService) SetLocale(ctx context.Context, _ *wslserviceapi.Locale) (*wslserviceapi.Empty, error) {
+	return s.block(ctx)
+}
+
+func (s *blockingService) block(ctx context.Context) (*wslserviceapi.Empty, error) {
+	n := s.running.Add(1)
+	defer s.running.Add(-1)
+
+	for {
+		m := s.maxRunning.Load()
+		if n <= m || s.maxRunning.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	s.started <- struct{}{}
+
+	select {
+	case <-s.release:
+		return &wslserviceapi.Empty{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s testService) newClientConnection(t testing.TB) *connection.Connection {
 	t.Helper()
 
 	addr := fmt.Sprintf("localhost:%d", s.port)
@@ -678,9 +1976,7 @@ func (s testService) newClientConnection(t *testing.T) *grpc.ClientConn {
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctxTimeout, addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock())
+	conn, err := connection.Dial(ctxTimeout, addr)
 	require.NoError(t, err, "Setup: could not contact the grpc server at %q", addr)
 
 	t.Cleanup(func() { conn.Close() })
@@ -706,6 +2002,179 @@ func (t emptyTask) String() string {
 	return "Empty test task"
 }
 
+// loadTask is a task that does nothing but count how many times it has run.
+type loadTask struct {
+	ID string
+
+	done *atomic.Int64
+}
+
+func (t loadTask) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	if t.done != nil {
+		t.done.Add(1)
+	}
+	return nil
+}
+
+func (t loadTask) String() string {
+	return "Load test task " + t.ID
+}
+
+// urgentTask is like emptyTask, but it runs even outside of the task window.
+type urgentTask struct {
+	ID string
+}
+
+func (t urgentTask) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	completedEmptyTasks.Set(t.ID)
+	return nil
+}
+
+func (t urgentTask) String() string {
+	return "Urgent test task"
+}
+
+func (t urgentTask) Urgent() bool {
+	return true
+}
+
+// sharedTask is like emptyTask, but it runs alongside the exclusive task being processed.
+type sharedTask struct {
+	ID string
+}
+
+func (t sharedTask) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	completedEmptyTasks.Set(t.ID)
+	return nil
+}
+
+func (t sharedTask) String() string {
+	return "Shared test task"
+}
+
+func (t sharedTask) Shared() bool {
+	return true
+}
+
+// dependentTask is like emptyTask, but it cannot run before the testTask with the prerequisite ID succeeds.
+type dependentTask struct {
+	ID           string
+	Prerequisite string
+}
+
+func (t dependentTask) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	completedEmptyTasks.Set(t.ID)
+	return nil
+}
+
+func (t dependentTask) String() string {
+	return "Dependent test task"
+}
+
+func (t dependentTask) DependsOn() []task.Task {
+	return []task.Task{&testTask{ID: t.Prerequisite}}
+}
+
+// chainingResults keeps the outcome of the follow-up submissions of the chaining tasks, and the context
+// they were executed with. As with completedEmptyTasks, a global is needed because tasks may be stored.
+var chainingResults sync.Map
+
+type chainingResult struct {
+	ctx context.Context
+	err error
+}
+
+// chainingTask submits an emptyTask with the FollowUp ID as a follow-up task, or itself if SubmitSelf
+// is set. Then it completes like an emptyTask with its own ID.
+type chainingTask struct {
+	ID         string
+	FollowUp   string
+	SubmitSelf bool
+}
+
+func (t chainingTask) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	var followUp task.Task = emptyTask{ID: t.FollowUp}
+	if t.SubmitSelf {
+		followUp = t
+	}
+
+	err := task.SubmitFollowUps(ctx, followUp)
+	chainingResults.Store(t.ID, chainingResult{ctx: ctx, err: err})
+
+	completedEmptyTasks.Set(t.ID)
+	return nil
+}
+
+func (t chainingTask) String() string {
+	return "Chaining test task"
+}
+
+// submitted returns the error returned when the task submitted its follow-up task.
+func (t chainingTask) submitted() error {
+	r, _ := chainingResults.Load(t.ID)
+	res, _ := r.(chainingResult)
+	return res.err
+}
+
+// executionContext returns the context the task was executed with.
+func (t chainingTask) executionContext() context.Context {
+	r, _ := chainingResults.Load(t.ID)
+	res, _ := r.(chainingResult)
+	return res.ctx
+}
+
+// scheduleMock is a schedule whose task window is opened and closed by the test.
+// storageMock stores the tasks in memory.
+type storageMock struct {
+	loadErr bool
+	saveErr bool
+
+	saves atomic.Int64
+}
+
+func (s *storageMock) Load() ([]task.Queued, error) {
+	if s.loadErr {
+		return nil, errors.New("mock error")
+	}
+	return nil, nil
+}
+
+func (s *storageMock) Save([]task.Queued) error {
+	if s.saveErr {
+		return errors.New("mock error")
+	}
+	s.saves.Add(1)
+	return nil
+}
+
+// connFactoryMock opens connections to a WSL service that accepts every call.
+type connFactoryMock struct {
+	opened atomic.Int64
+}
+
+func (f *connFactoryMock) ClientConn(*connection.Connection) (grpc.ClientConnInterface, error) {
+	f.opened.Add(1)
+	return fakeClientConn{}, nil
+}
+
+type fakeClientConn struct{}
+
+func (fakeClientConn) Invoke(context.Context, string, any, any, ...grpc.CallOption) error {
+	return nil
+}
+
+func (fakeClientConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("streams are not supported")
+}
+
+type scheduleMock struct {
+	open atomic.Bool
+}
+
+func (s *scheduleMock) TaskWindow(time.Time) (bool, time.Duration) {
+	return s.open.Load(), 100 * time.Millisecond
+}
+
 type testTask struct {
 	// ExecuteCalls counts the number of times Execute is called
 	ExecuteCalls atomic.Int32
@@ -758,6 +2227,30 @@ func (t *testTask) Is(other task.Task) bool {
 	return t.ID == o.ID
 }
 
+// failOnceTask is a task that returns an error the first time it is executed, and succeeds afterwards.
+type failOnceTask struct {
+	ExecuteCalls atomic.Int32
+
+	// Returns is the value that the first call to Execute will return
+	Returns error
+}
+
+// MarshalYAML is necessary to avoid races between Execute and Save.
+func (t *failOnceTask) MarshalYAML() (interface{}, error) {
+	return struct{}{}, nil
+}
+
+func (t *failOnceTask) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	if t.ExecuteCalls.Add(1) == 1 {
+		return t.Returns
+	}
+	return nil
+}
+
+func (t *failOnceTask) String() string {
+	return "Fail-once test task"
+}
+
 // blockingTask is a task that blocks execution until complete() is called.
 type blockingTask struct {
 	ctx       context.Context
@@ -802,6 +2295,8 @@ type testDistro struct {
 	// TODO: Is this used?
 	LockAwakeError error // LockAwake will throw this error (unless it is nil)
 
+	unreachable atomic.Bool // LockAwake fails while set, as it does while WSL is unavailable
+
 	// Do not use directly
 	runningRefCount int
 	runningMu       sync.RWMutex
@@ -836,6 +2331,10 @@ func (d *testDistro) LockAwake() error {
 		return err
 	}
 
+	if d.unreachable.Load() {
+		return fmt.Errorf("LockAwake: testDistro %q cannot be reached", d.name)
+	}
+
 	if !d.IsValid() {
 		return fmt.Errorf("LockAwake: testDistro %q is not valid", d.name)
 	}
@@ -899,3 +2398,48 @@ func (c mockProvisioning) ProvisioningTasks(ctx context.Context, distroName stri
 	}
 	return []task.Task{&testTask{}}, nil
 }
+
+// hookEvent is a call to one of the hooks of the worker.
+type hookEvent struct {
+	kind   string
+	distro string
+	task   worker.QueuedTask
+	err    error
+}
+
+// hookRecorder records the calls to the hooks of the worker.
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []hookEvent
+}
+
+func (r *hookRecorder) record(kind, distro string, t worker.QueuedTask, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, hookEvent{kind: kind, distro: distro, task: t, err: err})
+}
+
+func (r *hookRecorder) get() []hookEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]hookEvent{}, r.events...)
+}
+
+func (r *hookRecorder) hooks() worker.Hooks {
+	return worker.Hooks{
+		OnEnqueue: func(distro string, t worker.QueuedTask) {
+			r.record("enqueue", distro, t, nil)
+		},
+		OnStart: func(_ context.Context, distro string, t worker.QueuedTask) {
+			r.record("start", distro, t, nil)
+		},
+		OnFinish: func(_ context.Context, distro string, t worker.QueuedTask, _ time.Duration, err error) {
+			r.record("finish", distro, t, err)
+		},
+		OnRetry: func(_ context.Context, distro string, t worker.QueuedTask, err error) {
+			r.record("retry", distro, t, err)
+		},
+	}
+}
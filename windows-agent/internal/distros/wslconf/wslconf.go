@@ -0,0 +1,49 @@
+// Package wslconf writes to the /etc/wsl.conf of a distro from Windows, for the settings that the
+// WSL Pro service cannot apply from within the distro.
+package wslconf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ubuntu/decorate"
+	"gopkg.in/ini.v1"
+)
+
+// wslConfPath is where WSL reads the settings of a distro from.
+const wslConfPath = "/etc/wsl.conf"
+
+// EnableSystemd makes the distro boot with systemd, leaving any other setting of its wsl.conf untouched.
+// The WSL Pro service runs as a systemd unit, so it cannot do it in a distro that boots without systemd.
+// The change takes effect the next time the distro starts.
+func EnableSystemd(ctx context.Context, distroName string) (err error) {
+	defer decorate.OnError(&err, "could not enable systemd in distro %q", distroName)
+
+	original, err := readConf(ctx, distroName)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %v", wslConfPath, err)
+	}
+
+	data, err := ini.Load(original)
+	if err != nil {
+		return fmt.Errorf("could not parse %s: %v", wslConfPath, err)
+	}
+
+	key := data.Section("boot").Key("systemd")
+	if enabled, err := key.Bool(); err == nil && enabled {
+		return nil
+	}
+	key.SetValue("true")
+
+	w := &bytes.Buffer{}
+	if _, err := data.WriteTo(w); err != nil {
+		return fmt.Errorf("could not write modified settings: %v", err)
+	}
+
+	if err := writeConf(ctx, distroName, w.Bytes()); err != nil {
+		return fmt.Errorf("could not write %s: %v", wslConfPath, err)
+	}
+
+	return nil
+}
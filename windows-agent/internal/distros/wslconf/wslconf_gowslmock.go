@@ -0,0 +1,53 @@
+//go:build gowslmock
+
+package wslconf
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	wsl "github.com/ubuntu/gowsl"
+)
+
+// confs mocks the wsl.conf of every distro.
+var (
+	confs   = make(map[string][]byte)
+	confsMu sync.Mutex
+)
+
+// readConf mocks reading the wsl.conf of the distro. It fails if the distro is not registered.
+func readConf(ctx context.Context, distroName string) ([]byte, error) {
+	if err := checkRegistered(ctx, distroName); err != nil {
+		return nil, err
+	}
+
+	confsMu.Lock()
+	defer confsMu.Unlock()
+
+	return confs[distroName], nil
+}
+
+// writeConf mocks writing the wsl.conf of the distro. It fails if the distro is not registered.
+func writeConf(ctx context.Context, distroName string, contents []byte) error {
+	if err := checkRegistered(ctx, distroName); err != nil {
+		return err
+	}
+
+	confsMu.Lock()
+	defer confsMu.Unlock()
+
+	confs[distroName] = contents
+	return nil
+}
+
+func checkRegistered(ctx context.Context, distroName string) error {
+	registered, err := wsl.NewDistro(ctx, distroName).IsRegistered()
+	if err != nil {
+		return err
+	}
+	if !registered {
+		return errors.New("exit status 1")
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !gowslmock
+
+package wslconf
+
+import (
+	"context"
+)
+
+func readConf(ctx context.Context, distroName string) ([]byte, error) {
+	panic("readConf: this function can only be run on Windows")
+}
+
+func writeConf(ctx context.Context, distroName string, contents []byte) error {
+	panic("writeConf: this function can only be run on Windows")
+}
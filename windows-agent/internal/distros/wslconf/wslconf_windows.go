@@ -0,0 +1,57 @@
+//go:build !gowslmock
+
+package wslconf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// https://learn.microsoft.com/en-us/windows/win32/procthread/process-creation-flags
+//
+// CREATE_NO_WINDOW:
+// The process is a console application that is being run without
+// a console window. Therefore, the console handle for the
+// application is not set.
+const createNoWindow = 0x08000000
+
+// readConf returns the contents of the wsl.conf of the distro, which are empty if there is none.
+func readConf(ctx context.Context, distroName string) ([]byte, error) {
+	cmd := rootCommand(ctx, distroName, fmt.Sprintf("if [ -f %[1]s ]; then cat %[1]s; fi", wslConfPath))
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%v. Output: %s", err, stderr)
+	}
+
+	return out, nil
+}
+
+// writeConf replaces the wsl.conf of the distro with the contents.
+func writeConf(ctx context.Context, distroName string, contents []byte) error {
+	cmd := rootCommand(ctx, distroName, fmt.Sprintf("cat > %[1]s.new && chmod 0644 %[1]s.new && mv %[1]s.new %[1]s", wslConfPath))
+	cmd.Stdin = bytes.NewReader(contents)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v. Output: %s", err, out)
+	}
+
+	return nil
+}
+
+// rootCommand creates a Cmd that runs the shell script as root in the distro, in a way that won't cause a console to start.
+func rootCommand(ctx context.Context, distroName, script string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "wsl.exe", "-u", "root", "-d", distroName, "--", "sh", "-c", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+
+	return cmd
+}
@@ -0,0 +1,52 @@
+// Package features defines the feature flags that gate experimental behaviour in the agent.
+//
+// Flags can be set by the organization via the registry or by the user via the config file.
+// Flags that nobody set take their default value.
+package features
+
+import "slices"
+
+// Flag is the name of a feature flag.
+type Flag string
+
+// Known feature flags.
+const (
+	// NewTransport enables serving the distros on a Hyper-V socket as well as on the TCP one.
+	NewTransport Flag = "NewTransport"
+
+	// Telemetry enables exporting traces and metrics.
+	Telemetry Flag = "Telemetry"
+)
+
+// defaults contains the value of every known flag when nobody has set it.
+var defaults = map[Flag]bool{
+	NewTransport: false,
+	Telemetry:    false,
+}
+
+// All returns every known flag, sorted alphabetically.
+func All() []Flag {
+	flags := make([]Flag, 0, len(defaults))
+	for f := range defaults {
+		flags = append(flags, f)
+	}
+	slices.Sort(flags)
+
+	return flags
+}
+
+// Known returns true if the flag is one of the flags defined in this package.
+func (f Flag) Known() bool {
+	_, ok := defaults[f]
+	return ok
+}
+
+// Default returns the value of the flag when nobody has set it. Unknown flags are always disabled.
+func (f Flag) Default() bool {
+	return defaults[f]
+}
+
+// RegistryField returns the name of the registry field that sets the flag.
+func (f Flag) RegistryField() string {
+	return "Feature" + string(f)
+}
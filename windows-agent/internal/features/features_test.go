@@ -0,0 +1,48 @@
+package features_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	flags := features.All()
+	require.NotEmpty(t, flags, "All should return the known flags")
+	require.True(t, slices.IsSorted(flags), "All should return the flags sorted")
+
+	for _, f := range flags {
+		require.True(t, f.Known(), "Flags returned by All should be known")
+	}
+}
+
+func TestFlag(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		flag features.Flag
+
+		wantKnown         bool
+		wantRegistryField string
+	}{
+		"Success with a known flag":    {flag: features.Telemetry, wantKnown: true, wantRegistryField: "FeatureTelemetry"},
+		"Success with an unknown flag": {flag: "NotAFlag", wantRegistryField: "FeatureNotAFlag"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.wantKnown, tc.flag.Known(), "Mismatched Known")
+			require.Equal(t, tc.wantRegistryField, tc.flag.RegistryField(), "Mismatched RegistryField")
+
+			if !tc.wantKnown {
+				require.False(t, tc.flag.Default(), "Unknown flags should be disabled by default")
+			}
+		})
+	}
+}
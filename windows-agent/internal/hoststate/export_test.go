@@ -0,0 +1,25 @@
+package hoststate
+
+import (
+	"context"
+	"time"
+)
+
+// NewWithQueries creates a Host that runs the provided queries instead of querying Windows.
+func NewWithQueries(onBattery, metered func(context.Context) (bool, error)) *Host {
+	return &Host{
+		onBattery: cachedQuery{query: onBattery},
+		metered:   cachedQuery{query: metered},
+	}
+}
+
+// WatchHostnameWith watches the hostname returned by the provided query instead of the one of the Windows host.
+func WatchHostnameWith(ctx context.Context, query func() (string, error), interval time.Duration, onChange func(ctx context.Context, oldName, newName string)) {
+	watchHostname(ctx, query, interval, onChange)
+}
+
+// WatchNetworkWith watches the addresses returned by the provided query every time a change is notified,
+// instead of the ones of the Windows host.
+func WatchNetworkWith(ctx context.Context, changes <-chan struct{}, query func() ([]string, error), settle time.Duration, onChange func(ctx context.Context)) {
+	watchNetwork(ctx, changes, query, settle, onChange)
+}
@@ -0,0 +1,50 @@
+package hoststate
+
+import (
+	"context"
+	"os"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+)
+
+// hostnameInterval is how often the hostname is checked for changes. Renaming a Windows machine
+// requires a restart to take effect, so there is no point in checking often.
+const hostnameInterval = 5 * time.Minute
+
+// WatchHostname calls onChange every time the hostname of the Windows host changes, until the
+// context is cancelled.
+func WatchHostname(ctx context.Context, onChange func(ctx context.Context, oldName, newName string)) {
+	watchHostname(ctx, os.Hostname, hostnameInterval, onChange)
+}
+
+func watchHostname(ctx context.Context, query func() (string, error), interval time.Duration, onChange func(ctx context.Context, oldName, newName string)) {
+	current, err := query()
+	if err != nil {
+		log.Warningf(ctx, "Could not get the host name: %v", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		name, err := query()
+		if err != nil {
+			log.Warningf(ctx, "Could not get the host name: %v", err)
+			continue
+		}
+
+		if name == current {
+			continue
+		}
+
+		// The first successful query after failing ones is not a change.
+		if current != "" {
+			onChange(ctx, current, name)
+		}
+		current = name
+	}
+}
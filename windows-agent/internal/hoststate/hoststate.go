@@ -0,0 +1,101 @@
+// Package hoststate reports the conditions of the Windows host that make it a bad time to run
+// heavy tasks, such as running on battery or on a metered connection.
+package hoststate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"github.com/ubuntu/decorate"
+)
+
+// cacheDuration is how long a query is trusted before the host is queried again. Querying the
+// network cost is slow, so it must not be done every time a task is about to run.
+const cacheDuration = time.Minute
+
+// Host queries the state of the Windows host and caches the results.
+type Host struct {
+	onBattery cachedQuery
+	metered   cachedQuery
+}
+
+type options struct {
+	timeouts timeouts.Policy
+}
+
+// Option is an optional argument for New.
+type Option func(*options)
+
+// WithTimeouts overrides how long the powershell commands used to query the host may take.
+func WithTimeouts(policy timeouts.Policy) Option {
+	return func(o *options) {
+		o.timeouts = policy
+	}
+}
+
+// New creates a Host that queries the Windows host.
+func New(args ...Option) *Host {
+	var opts options
+	for _, f := range args {
+		f(&opts)
+	}
+
+	policy := opts.timeouts.OrDefault()
+
+	return &Host{
+		onBattery: cachedQuery{query: onBattery},
+		metered:   cachedQuery{query: withTimeout(metered, policy.Powershell)},
+	}
+}
+
+// withTimeout returns a query that is cancelled if it takes longer than the timeout.
+func withTimeout(query func(context.Context) (bool, error), timeout time.Duration) func(context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return query(ctx)
+	}
+}
+
+// OnBattery returns true if the host is running on battery power.
+func (h *Host) OnBattery(ctx context.Context) (b bool, err error) {
+	defer decorate.OnError(&err, "could not query the power status")
+	return h.onBattery.get(ctx)
+}
+
+// Metered returns true if the internet connection of the host is metered.
+func (h *Host) Metered(ctx context.Context) (b bool, err error) {
+	defer decorate.OnError(&err, "could not query the network cost")
+	return h.metered.get(ctx)
+}
+
+// cachedQuery is a boolean query whose result is reused for cacheDuration.
+type cachedQuery struct {
+	query func(context.Context) (bool, error)
+
+	value   bool
+	expires time.Time
+	mu      sync.Mutex
+}
+
+func (q *cachedQuery) get(ctx context.Context) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if time.Now().Before(q.expires) {
+		return q.value, nil
+	}
+
+	v, err := q.query(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	q.value = v
+	q.expires = time.Now().Add(cacheDuration)
+
+	return v, nil
+}
@@ -0,0 +1,18 @@
+package hoststate
+
+import "context"
+
+// onBattery is not supported outside of Windows: the host is assumed to be plugged in.
+func onBattery(context.Context) (bool, error) {
+	return false, nil
+}
+
+// metered is not supported outside of Windows: the connection is assumed not to be metered.
+func metered(context.Context) (bool, error) {
+	return false, nil
+}
+
+// networkChanges is not supported outside of Windows: the network is assumed never to change.
+func networkChanges(context.Context) (<-chan struct{}, error) {
+	return make(chan struct{}), nil
+}
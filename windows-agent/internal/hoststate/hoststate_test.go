@@ -0,0 +1,178 @@
+package hoststate_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/hoststate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHost(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		queryErr bool
+
+		wantErr bool
+	}{
+		"Success caching the query results": {},
+
+		"Error when the host cannot be queried": {queryErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			query := func(context.Context) (bool, error) {
+				calls++
+				if tc.queryErr {
+					return false, errors.New("mock error")
+				}
+				return true, nil
+			}
+
+			h := hoststate.NewWithQueries(query, query)
+
+			for i := 0; i < 3; i++ {
+				battery, err := h.OnBattery(context.Background())
+				if tc.wantErr {
+					require.Error(t, err, "OnBattery should return an error")
+					continue
+				}
+				require.NoError(t, err, "OnBattery should return no error")
+				require.True(t, battery, "OnBattery should return the result of the query")
+			}
+
+			metered, err := h.Metered(context.Background())
+			if tc.wantErr {
+				require.Error(t, err, "Metered should return an error")
+				require.Equal(t, 4, calls, "Failed queries should not be cached")
+				return
+			}
+			require.NoError(t, err, "Metered should return no error")
+			require.True(t, metered, "Metered should return the result of the query")
+
+			require.Equal(t, 2, calls, "Each query should run only once while its result is cached")
+		})
+	}
+}
+
+func TestWatchHostname(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		names []string
+
+		want []string
+	}{
+		"Success reporting nothing when the hostname does not change": {names: []string{"A", "A", "A"}},
+		"Success reporting every change":                              {names: []string{"A", "B", "B", "C"}, want: []string{"A->B", "B->C"}},
+		"Success ignoring failed queries":                             {names: []string{"A", "", "A", "", "B"}, want: []string{"A->B"}},
+		"Success not reporting the first name after failing queries":  {names: []string{"", "", "A", "B"}, want: []string{"A->B"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var i int
+			query := func() (string, error) {
+				if i >= len(tc.names) {
+					cancel()
+					return tc.names[len(tc.names)-1], nil
+				}
+				n := tc.names[i]
+				i++
+				if n == "" {
+					return "", errors.New("mock error")
+				}
+				return n, nil
+			}
+
+			var got []string
+			hoststate.WatchHostnameWith(ctx, query, time.Millisecond, func(_ context.Context, oldName, newName string) {
+				got = append(got, oldName+"->"+newName)
+			})
+
+			require.Equal(t, tc.want, got, "Unexpected hostname changes reported")
+		})
+	}
+}
+
+func TestWatchNetwork(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		addresses []string
+		burst     bool
+
+		want int32
+	}{
+		"Success reporting nothing when the addresses do not change":      {addresses: []string{"A", "A", "A"}},
+		"Success reporting every change":                                  {addresses: []string{"A", "B", "B", "C"}, want: 2},
+		"Success reporting a burst of changes once":                       {addresses: []string{"A", "B"}, burst: true, want: 1},
+		"Success ignoring failed queries":                                 {addresses: []string{"A", "", "A", "", "B"}, want: 1},
+		"Success not reporting the first addresses after failing queries": {addresses: []string{"", "", "A", "B"}, want: 1},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var queries atomic.Int32
+			query := func() ([]string, error) {
+				i := int(queries.Add(1)) - 1
+				if i >= len(tc.addresses) {
+					i = len(tc.addresses) - 1
+				}
+				if tc.addresses[i] == "" {
+					return nil, errors.New("mock error")
+				}
+				return []string{tc.addresses[i]}, nil
+			}
+
+			settle := time.Millisecond
+			if tc.burst {
+				settle = 100 * time.Millisecond
+			}
+
+			var changes atomic.Int32
+			notifications := make(chan struct{})
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				hoststate.WatchNetworkWith(ctx, notifications, query, settle, func(context.Context) { changes.Add(1) })
+			}()
+
+			if tc.burst {
+				for i := 0; i < 5; i++ {
+					notifications <- struct{}{}
+				}
+				require.Eventually(t, func() bool { return queries.Load() == 2 }, time.Second, time.Millisecond, "A burst of notifications should be queried once")
+			} else {
+				for i := 1; i < len(tc.addresses); i++ {
+					notifications <- struct{}{}
+					want := int32(i + 1)
+					require.Eventually(t, func() bool { return queries.Load() == want }, time.Second, time.Millisecond, "Every notification should be queried")
+				}
+			}
+
+			cancel()
+			<-done
+
+			require.Equal(t, tc.want, changes.Load(), "Unexpected number of network changes reported")
+		})
+	}
+}
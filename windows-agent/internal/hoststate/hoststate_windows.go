@@ -0,0 +1,105 @@
+package hoststate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// createNoWindow prevents a console window from popping up when running powershell.
+// https://learn.microsoft.com/en-us/windows/win32/procthread/process-creation-flags
+const createNoWindow = 0x08000000
+
+var procGetSystemPowerStatus = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+var (
+	iphlpapi                         = windows.NewLazySystemDLL("iphlpapi.dll")
+	procNotifyUnicastIPAddressChange = iphlpapi.NewProc("NotifyUnicastIpAddressChange")
+	procCancelMibChangeNotify2       = iphlpapi.NewProc("CancelMibChangeNotify2")
+)
+
+// networkNotifications receives a value when Windows reports that an IP address changed. The callback is
+// created only once because the callbacks created by windows.NewCallback are never released.
+var (
+	networkNotifications = make(chan struct{}, 1)
+	networkCallback      = windows.NewCallback(func(callerContext, row, notificationType uintptr) uintptr {
+		select {
+		case networkNotifications <- struct{}{}:
+		default:
+		}
+		return 0
+	})
+)
+
+// systemPowerStatus mirrors the SYSTEM_POWER_STATUS struct.
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/ns-winbase-system_power_status
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// acLineOffline is the ACLineStatus of a host running on battery.
+const acLineOffline = 0
+
+func onBattery(context.Context) (bool, error) {
+	var status systemPowerStatus
+
+	r, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status)))
+	if r == 0 {
+		return false, err
+	}
+
+	// Unknown status (255) is treated as plugged in.
+	return status.ACLineStatus == acLineOffline, nil
+}
+
+func metered(ctx context.Context) (bool, error) {
+	// The network cost is only exposed via WinRT, which is reachable from powershell.
+	const script = `[void][Windows.Networking.Connectivity.NetworkInformation, Windows, ContentType=WindowsRuntime];` +
+		`$p = [Windows.Networking.Connectivity.NetworkInformation]::GetInternetConnectionProfile();` +
+		`if ($p) { $p.GetConnectionCost().NetworkCostType } else { 'Unknown' }`
+
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoLogo", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("%v. Output: %s", err, out)
+	}
+
+	// Fixed and Variable costs mean the connection is metered. Unrestricted and Unknown mean it is not.
+	switch string(bytes.TrimSpace(out)) {
+	case "Fixed", "Variable":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// networkChanges subscribes to the changes of the IP addresses of the host until the context is cancelled.
+// https://learn.microsoft.com/en-us/windows/win32/api/netioapi/nf-netioapi-notifyunicastipaddresschange
+func networkChanges(ctx context.Context) (<-chan struct{}, error) {
+	var handle windows.Handle
+	if r, _, _ := procNotifyUnicastIPAddressChange.Call(uintptr(windows.AF_UNSPEC), networkCallback, 0, 0, uintptr(unsafe.Pointer(&handle))); r != 0 {
+		return nil, fmt.Errorf("could not subscribe to IP address changes: %v", windows.Errno(r))
+	}
+
+	go func() {
+		<-ctx.Done()
+		_, _, _ = procCancelMibChangeNotify2.Call(uintptr(handle))
+	}()
+
+	return networkNotifications, nil
+}
@@ -0,0 +1,91 @@
+package hoststate
+
+import (
+	"context"
+	"net"
+	"slices"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+)
+
+// networkSettleDelay is how long the network must stay quiet after a change before the addresses are
+// compared. Connecting to a VPN changes many addresses at once.
+const networkSettleDelay = 3 * time.Second
+
+// WatchNetwork calls onChange every time the IP addresses of the Windows host change, e.g. because a
+// VPN connected or disconnected, until the context is cancelled. A burst of changes is reported once.
+func WatchNetwork(ctx context.Context, onChange func(ctx context.Context)) {
+	changes, err := networkChanges(ctx)
+	if err != nil {
+		log.Warningf(ctx, "Could not watch the network for changes: %v", err)
+		return
+	}
+
+	watchNetwork(ctx, changes, hostAddresses, networkSettleDelay, onChange)
+}
+
+func watchNetwork(ctx context.Context, changes <-chan struct{}, query func() ([]string, error), settle time.Duration, onChange func(ctx context.Context)) {
+	current, err := query()
+	if err != nil {
+		log.Warningf(ctx, "Could not get the IP addresses of the host: %v", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+		}
+
+		if !waitQuiet(ctx, changes, settle) {
+			return
+		}
+
+		addrs, err := query()
+		if err != nil {
+			log.Warningf(ctx, "Could not get the IP addresses of the host: %v", err)
+			continue
+		}
+
+		if slices.Equal(addrs, current) {
+			continue
+		}
+
+		// The first successful query after failing ones is not a change.
+		if current != nil {
+			onChange(ctx)
+		}
+		current = addrs
+	}
+}
+
+// waitQuiet waits until no change is notified for the settle delay. It returns false if the context is
+// cancelled first.
+func waitQuiet(ctx context.Context, changes <-chan struct{}, settle time.Duration) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-changes:
+		case <-time.After(settle):
+			return true
+		}
+	}
+}
+
+// hostAddresses returns the sorted IP addresses of the network interfaces of the host.
+func hostAddresses() ([]string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		out = append(out, a.String())
+	}
+	slices.Sort(out)
+
+	return out, nil
+}
@@ -0,0 +1,233 @@
+// Package legacyconfig finds the settings left behind by older Ubuntu WSL tooling, and imports them into
+// the configuration of the agent the first time it runs, so that users don't have to enter them again.
+package legacyconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+	"github.com/ubuntu/decorate"
+)
+
+// registryPath is the registry key where Ubuntu Pro for Windows, as the agent was formerly named,
+// stored its settings.
+const registryPath = `Software\Canonical\UbuntuProForWindows`
+
+//nolint:gosec // These are not credentials
+const (
+	proTokenField        = "ProToken"
+	landscapeConfigField = "LandscapeClientConfig"
+	landscapeURLField    = "LandscapeURL"
+)
+
+// setupPrefsPath is the file where ubuntu-wsl-setup stored its preferences, relative to %AppData%.
+var setupPrefsPath = filepath.Join("Canonical", "ubuntu_wsl_setup", "shared_preferences.json")
+
+//nolint:gosec // These are not credentials
+const (
+	setupProTokenKey     = "flutter.proToken"
+	setupLandscapeURLKey = "flutter.landscapeUrl"
+)
+
+// landscapePort is the port of the Landscape server the Landscape client connects to.
+const landscapePort = "6554"
+
+// Registry is the subset of the Windows registry needed to read the legacy settings.
+type Registry interface {
+	HKCUOpenKey(path string) (registry.Key, error)
+	CloseKey(k registry.Key)
+	ReadValue(k registry.Key, field string) (value string, err error)
+}
+
+// Config is an interface to easily allow dependency injection. Should be a config.Config
+// in production.
+type Config interface {
+	LegacyImported() (bool, error)
+	ImportLegacySettings(ctx context.Context, legacy config.LegacySettings) error
+}
+
+type options struct {
+	registry   Registry
+	appDataDir string
+}
+
+// Option is an optional argument for Import.
+type Option = func(*options)
+
+// WithRegistry allows for overriding the registry back-end.
+func WithRegistry(r Registry) Option {
+	return func(o *options) {
+		if r != nil {
+			o.registry = r
+		}
+	}
+}
+
+// WithAppDataDir overrides the directory where ubuntu-wsl-setup stored its preferences, which is
+// %AppData% by default.
+func WithAppDataDir(dir string) Option {
+	return func(o *options) {
+		o.appDataDir = dir
+	}
+}
+
+// Import finds the settings left behind by older tooling and imports them into the config. Once they
+// are imported, they are not looked for again. If any of the places where they are stored cannot be
+// read, nothing is imported so that the import is attempted again on the next run.
+func Import(ctx context.Context, conf Config, args ...Option) (err error) {
+	defer decorate.OnError(&err, "could not import legacy settings")
+
+	opts := options{
+		registry:   registry.Windows{},
+		appDataDir: os.Getenv("AppData"),
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	imported, err := conf.LegacyImported()
+	if err != nil {
+		return err
+	}
+	if imported {
+		return nil
+	}
+
+	fromRegistry, err := readRegistry(ctx, opts.registry)
+	if err != nil {
+		return err
+	}
+
+	fromSetup, err := readSetupPrefs(ctx, opts.appDataDir)
+	if err != nil {
+		return err
+	}
+
+	// The registry key was written by the predecessor of the agent, so it takes precedence.
+	legacy := config.LegacySettings{
+		UbuntuProToken:  firstNonEmpty(fromRegistry.UbuntuProToken, fromSetup.UbuntuProToken),
+		LandscapeConfig: firstNonEmpty(fromRegistry.LandscapeConfig, fromSetup.LandscapeConfig),
+	}
+
+	if legacy != (config.LegacySettings{}) {
+		log.Info(ctx, "Found settings left behind by older Ubuntu WSL tooling")
+	}
+
+	return conf.ImportLegacySettings(ctx, legacy)
+}
+
+// readRegistry reads the settings stored in the registry key of Ubuntu Pro for Windows.
+func readRegistry(ctx context.Context, reg Registry) (s config.LegacySettings, err error) {
+	defer decorate.OnError(&err, `could not read registry key HKCU\%s`, registryPath)
+
+	k, err := reg.HKCUOpenKey(registryPath)
+	if errors.Is(err, registry.ErrKeyNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	defer reg.CloseKey(k)
+
+	values := make(map[string]string)
+	for _, field := range []string{proTokenField, landscapeConfigField, landscapeURLField} {
+		v, err := reg.ReadValue(k, field)
+		if errors.Is(err, registry.ErrFieldNotExist) {
+			continue
+		}
+		if err != nil {
+			return s, fmt.Errorf("could not read field %q: %v", field, err)
+		}
+		values[field] = strings.TrimSpace(v)
+	}
+
+	s.UbuntuProToken = values[proTokenField]
+	s.LandscapeConfig = values[landscapeConfigField]
+
+	// Only the URL of the Landscape server was stored before the full client configuration was.
+	if s.LandscapeConfig == "" && values[landscapeURLField] != "" {
+		s.LandscapeConfig = landscapeConfig(ctx, values[landscapeURLField])
+	}
+
+	return s, nil
+}
+
+// readSetupPrefs reads the settings stored in the preferences of ubuntu-wsl-setup.
+func readSetupPrefs(ctx context.Context, appDataDir string) (s config.LegacySettings, err error) {
+	if appDataDir == "" {
+		return s, nil
+	}
+
+	path := filepath.Join(appDataDir, setupPrefsPath)
+	defer decorate.OnError(&err, "could not read ubuntu-wsl-setup preferences at %q", path)
+
+	out, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+
+	// The preferences contain values of any JSON type, but the ones we are interested in are strings.
+	var prefs map[string]any
+	if err := json.Unmarshal(out, &prefs); err != nil {
+		return s, fmt.Errorf("could not parse file: %v", err)
+	}
+
+	if token, ok := prefs[setupProTokenKey].(string); ok {
+		s.UbuntuProToken = strings.TrimSpace(token)
+	}
+
+	if u, ok := prefs[setupLandscapeURLKey].(string); ok && strings.TrimSpace(u) != "" {
+		s.LandscapeConfig = landscapeConfig(ctx, u)
+	}
+
+	return s, nil
+}
+
+// landscapeConfig builds the Landscape client configuration to connect to the self-hosted
+// Landscape server at the given URL, the same way the GUI does for a manual configuration.
+// An invalid URL is skipped rather than failing, as it would fail the same way on every run.
+func landscapeConfig(ctx context.Context, rawURL string) string {
+	full := strings.TrimSpace(rawURL)
+	if !strings.Contains(full, "://") {
+		full = "https://" + full
+	}
+
+	u, err := url.Parse(full)
+	if err != nil || u.Hostname() == "" {
+		log.Warningf(ctx, "Skipping legacy Landscape URL %q: it is not a valid URL", rawURL)
+		return ""
+	}
+
+	fqdn := u.Scheme + "://" + u.Hostname()
+
+	return fmt.Sprintf(`[host]
+url = %s:%s
+[client]
+account_name = standalone
+url = %s/message-system
+ping_url = %s/ping
+`, u.Hostname(), landscapePort, fqdn, fqdn)
+}
+
+// firstNonEmpty returns the first of the values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
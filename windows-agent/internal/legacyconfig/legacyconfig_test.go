@@ -0,0 +1,128 @@
+package legacyconfig_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/legacyconfig"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+const wantLandscapeConfig = `[host]
+url = landscape.example.com:6554
+[client]
+account_name = standalone
+url = https://landscape.example.com/message-system
+ping_url = https://landscape.example.com/ping
+`
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		registry   map[string]string
+		setupPrefs string
+
+		importedBefore   bool
+		registryBroken   bool
+		setupPrefsBroken bool
+		configErr        bool
+
+		wantImported bool
+		want         config.LegacySettings
+		wantErr      bool
+	}{
+		"Success when there are no legacy settings": {wantImported: true},
+		"Success importing from the registry": {registry: map[string]string{"ProToken": "legacy_token", "LandscapeClientConfig": "[client]\naccount_name=test"},
+			wantImported: true, want: config.LegacySettings{UbuntuProToken: "legacy_token", LandscapeConfig: "[client]\naccount_name=test"}},
+		"Success importing a Landscape URL from the registry": {registry: map[string]string{"LandscapeURL": "landscape.example.com"},
+			wantImported: true, want: config.LegacySettings{LandscapeConfig: wantLandscapeConfig}},
+		"Success importing from ubuntu-wsl-setup": {setupPrefs: `{"flutter.proToken": "setup_token", "flutter.landscapeUrl": "https://landscape.example.com", "flutter.theme": 1}`,
+			wantImported: true, want: config.LegacySettings{UbuntuProToken: "setup_token", LandscapeConfig: wantLandscapeConfig}},
+		"Success preferring the registry over ubuntu-wsl-setup": {registry: map[string]string{"ProToken": "legacy_token"}, setupPrefs: `{"flutter.proToken": "setup_token", "flutter.landscapeUrl": "landscape.example.com"}`,
+			wantImported: true, want: config.LegacySettings{UbuntuProToken: "legacy_token", LandscapeConfig: wantLandscapeConfig}},
+		"Success skipping an invalid Landscape URL": {setupPrefs: `{"flutter.proToken": "setup_token", "flutter.landscapeUrl": "https://"}`,
+			wantImported: true, want: config.LegacySettings{UbuntuProToken: "setup_token"}},
+		"Success when the settings were imported already": {registry: map[string]string{"ProToken": "legacy_token"}, importedBefore: true},
+
+		"Error when the registry cannot be read":                     {registryBroken: true, wantErr: true},
+		"Error when the ubuntu-wsl-setup preferences are broken":     {setupPrefs: `{"flutter.proToken": `, wantErr: true},
+		"Error when the ubuntu-wsl-setup preferences cannot be read": {setupPrefsBroken: true, wantErr: true},
+		"Error when the config cannot be read":                       {configErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			reg := testutils.NewRegistryMock()
+			if tc.registry != nil {
+				k, err := reg.HKCUCreateKey(`Software\Canonical\UbuntuProForWindows`)
+				require.NoError(t, err, "Setup: could not create legacy registry key")
+				for field, value := range tc.registry {
+					err := reg.WriteValue(k, field, value, strings.Contains(value, "\n"))
+					require.NoError(t, err, "Setup: could not write legacy registry field")
+				}
+				reg.CloseKey(k)
+			}
+			reg.CannotOpen.Store(tc.registryBroken)
+
+			appData := t.TempDir()
+			prefsPath := filepath.Join(appData, "Canonical", "ubuntu_wsl_setup", "shared_preferences.json")
+			if tc.setupPrefs != "" {
+				err := os.MkdirAll(filepath.Dir(prefsPath), 0700)
+				require.NoError(t, err, "Setup: could not create ubuntu-wsl-setup directory")
+				err = os.WriteFile(prefsPath, []byte(tc.setupPrefs), 0600)
+				require.NoError(t, err, "Setup: could not write ubuntu-wsl-setup preferences")
+			}
+			if tc.setupPrefsBroken {
+				err := os.MkdirAll(prefsPath, 0700)
+				require.NoError(t, err, "Setup: could not replace ubuntu-wsl-setup preferences with a directory")
+			}
+
+			conf := &mockConfig{imported: tc.importedBefore, err: tc.configErr}
+
+			err := legacyconfig.Import(ctx, conf, legacyconfig.WithRegistry(reg), legacyconfig.WithAppDataDir(appData))
+			if tc.wantErr {
+				require.Error(t, err, "Import should return an error")
+				require.False(t, conf.gotImport, "Nothing should be imported when an error is returned")
+				return
+			}
+			require.NoError(t, err, "Import should return no errors")
+
+			require.Equal(t, tc.wantImported, conf.gotImport, "Unexpected import of legacy settings")
+			require.Equal(t, tc.want, conf.got, "Unexpected legacy settings imported")
+
+			reg.RequireNoLeaks(t)
+		})
+	}
+}
+
+type mockConfig struct {
+	imported bool
+	err      bool
+
+	gotImport bool
+	got       config.LegacySettings
+}
+
+func (m *mockConfig) LegacyImported() (bool, error) {
+	if m.err {
+		return false, errors.New("mock error")
+	}
+	return m.imported, nil
+}
+
+func (m *mockConfig) ImportLegacySettings(ctx context.Context, legacy config.LegacySettings) error {
+	m.gotImport = true
+	m.got = legacy
+	return nil
+}
@@ -0,0 +1,8 @@
+package notifications
+
+import "context"
+
+// CheckSubscription exposes checkSubscription to tests.
+func (n *Notifier) CheckSubscription(ctx context.Context) {
+	n.checkSubscription(ctx)
+}
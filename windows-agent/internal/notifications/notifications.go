@@ -0,0 +1,197 @@
+// Package notifications raises Windows toast notifications to let the user know about events that
+// need their attention. The user can opt out of each category of notifications.
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+)
+
+// Category is a kind of notification that the user can opt out of.
+type Category string
+
+const (
+	// SubscriptionExpiring notifies that the Ubuntu Pro subscription is about to expire.
+	SubscriptionExpiring Category = "subscription-expiring"
+
+	// ProvisioningFailed notifies that some distros could not be provisioned.
+	ProvisioningFailed Category = "provisioning-failed"
+
+	// DistroAdopted notifies that a new distro started being managed by the agent.
+	DistroAdopted Category = "distro-adopted"
+
+	// WSLUpdate notifies that WSL is about to be updated, which shuts down the distros.
+	WSLUpdate Category = "wsl-update"
+)
+
+// Categories lists every category of notifications.
+var Categories = []Category{SubscriptionExpiring, ProvisioningFailed, DistroAdopted, WSLUpdate}
+
+const (
+	// repeatInterval is how long an identical notification is held back for, so that the user
+	// is not flooded by events that keep happening.
+	repeatInterval = 24 * time.Hour
+
+	// expiryWarning is how long before the subscription expires the user is warned about it.
+	expiryWarning = 7 * 24 * time.Hour
+
+	// expiryCheckInterval is how often the expiration of the subscription is checked.
+	expiryCheckInterval = 6 * time.Hour
+)
+
+// Config is the configuration the notification preferences and the subscription are read from.
+type Config interface {
+	NotificationEnabled(category string) (bool, error)
+	StoreEntitlement() (config.StoreEntitlement, error)
+}
+
+// Toaster shows a toast notification.
+type Toaster func(ctx context.Context, title, body string) error
+
+// Notifier raises notifications unless the user opted out of their category.
+type Notifier struct {
+	conf  Config
+	toast Toaster
+
+	// sent holds when each notification was last raised.
+	sent map[string]time.Time
+	mu   sync.Mutex
+}
+
+type options struct {
+	toaster Toaster
+}
+
+// Option is an optional argument for New.
+type Option func(*options)
+
+// WithToaster overrides how toast notifications are shown.
+func WithToaster(t Toaster) Option {
+	return func(o *options) {
+		o.toaster = t
+	}
+}
+
+// New creates a Notifier.
+func New(conf Config, args ...Option) *Notifier {
+	opts := options{
+		toaster: showToast,
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return &Notifier{
+		conf:  conf,
+		toast: opts.toaster,
+		sent:  make(map[string]time.Time),
+	}
+}
+
+// Notify raises a notification, unless the user opted out of its category or the same
+// notification was raised recently. Failing to notify is not fatal, so errors are only logged.
+func (n *Notifier) Notify(ctx context.Context, category Category, title, body string) {
+	if n == nil {
+		return
+	}
+
+	enabled, err := n.conf.NotificationEnabled(string(category))
+	if err != nil {
+		log.Warningf(ctx, "Notifications: could not read preferences, not notifying: %v", err)
+		return
+	}
+	if !enabled {
+		log.Debugf(ctx, "Notifications: %s: muted by the user: %s", category, title)
+		return
+	}
+
+	if !n.markSent(category, title, body) {
+		log.Debugf(ctx, "Notifications: %s: already notified recently: %s", category, title)
+		return
+	}
+
+	log.Infof(ctx, "Notifications: %s: %s", category, title)
+	if err := n.toast(ctx, title, body); err != nil {
+		log.Warningf(ctx, "Notifications: could not show notification %q: %v", title, err)
+	}
+}
+
+// markSent records that the notification is being raised. It returns false if it was raised
+// within the repeat interval.
+func (n *Notifier) markSent(category Category, title, body string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	key := fmt.Sprintf("%s\x00%s\x00%s", category, title, body)
+	if last, ok := n.sent[key]; ok && time.Since(last) < repeatInterval {
+		return false
+	}
+
+	n.sent[key] = time.Now()
+	return true
+}
+
+// NotifyDistroAdopted notifies that a new distro started being managed by the agent.
+func (n *Notifier) NotifyDistroAdopted(ctx context.Context, distroName string) {
+	n.Notify(ctx, DistroAdopted, "New distro managed by Ubuntu Pro for WSL",
+		fmt.Sprintf("%s is now managed by Ubuntu Pro for WSL.", distroName))
+}
+
+// NotifyProvisioningFailed notifies that some distros could not be provisioned.
+func (n *Notifier) NotifyProvisioningFailed(ctx context.Context, failed int) {
+	if failed == 0 {
+		return
+	}
+
+	n.Notify(ctx, ProvisioningFailed, "Some distros could not be set up",
+		fmt.Sprintf("Ubuntu Pro for WSL could not set up %d distro(s). They will be set up the next time they start.", failed))
+}
+
+// NotifyWSLUpdate notifies that WSL is about to be updated.
+func (n *Notifier) NotifyWSLUpdate(ctx context.Context) {
+	n.Notify(ctx, WSLUpdate, "WSL is being updated",
+		"Ubuntu Pro for WSL is updating WSL. Your distros will shut down, and the ones that were running will start again afterwards.")
+}
+
+// WatchSubscription notifies when the Microsoft Store subscription is about to expire. It checks
+// periodically until the context is cancelled.
+func (n *Notifier) WatchSubscription(ctx context.Context) {
+	for {
+		n.checkSubscription(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(expiryCheckInterval):
+		}
+	}
+}
+
+// checkSubscription notifies if the Microsoft Store subscription expires soon.
+func (n *Notifier) checkSubscription(ctx context.Context) {
+	e, err := n.conf.StoreEntitlement()
+	if err != nil {
+		log.Warningf(ctx, "Notifications: could not check the subscription expiration: %v", err)
+		return
+	}
+
+	expiration := e.SubscriptionExpiration
+	if expiration.IsZero() || time.Until(expiration) > expiryWarning {
+		return
+	}
+
+	if time.Until(expiration) <= 0 {
+		n.Notify(ctx, SubscriptionExpiring, "Your Ubuntu Pro subscription has expired",
+			"Renew it in the Microsoft Store to keep your distros secure.")
+		return
+	}
+
+	n.Notify(ctx, SubscriptionExpiring, "Your Ubuntu Pro subscription is about to expire",
+		fmt.Sprintf("It expires on %s. Renew it in the Microsoft Store to keep your distros secure.", expiration.Local().Format(time.DateOnly)))
+}
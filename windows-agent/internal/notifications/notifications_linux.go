@@ -0,0 +1,13 @@
+package notifications
+
+import (
+	"context"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+)
+
+// showToast is not supported outside of Windows: the notification is only logged.
+func showToast(ctx context.Context, title, body string) error {
+	log.Infof(ctx, "Notification: %s: %s", title, body)
+	return nil
+}
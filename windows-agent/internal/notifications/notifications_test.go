@@ -0,0 +1,160 @@
+package notifications_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/notifications"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		muted       []string
+		prefsErr    bool
+		toastErr    bool
+		notifyTwice bool
+		changeBody  bool
+		wantToasts  int
+	}{
+		"Success notifying":                          {wantToasts: 1},
+		"Success notifying despite other muted ones": {muted: []string{"distro-adopted"}, wantToasts: 1},
+		"Success notifying again with a new body":    {notifyTwice: true, changeBody: true, wantToasts: 2},
+		"Success not failing when the toast fails":   {toastErr: true, wantToasts: 1},
+
+		"Does not notify when the category is muted":          {muted: []string{"provisioning-failed"}},
+		"Does not notify the same notification twice":         {notifyTwice: true, wantToasts: 1},
+		"Does not notify when the preferences cannot be read": {prefsErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			conf := &mockConfig{muted: tc.muted, prefsErr: tc.prefsErr}
+			toaster := &mockToaster{err: tc.toastErr}
+			n := notifications.New(conf, notifications.WithToaster(toaster.show))
+
+			n.Notify(ctx, notifications.ProvisioningFailed, "title", "body")
+			if tc.notifyTwice {
+				body := "body"
+				if tc.changeBody {
+					body = "another body"
+				}
+				n.Notify(ctx, notifications.ProvisioningFailed, "title", body)
+			}
+
+			require.Len(t, toaster.toasts(), tc.wantToasts, "Unexpected number of toasts shown")
+		})
+	}
+}
+
+func TestNotifyNilNotifier(t *testing.T) {
+	t.Parallel()
+
+	var n *notifications.Notifier
+	require.NotPanics(t, func() { n.NotifyDistroAdopted(context.Background(), "Ubuntu") }, "A nil notifier should be a no-op")
+}
+
+func TestCheckSubscription(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expiration     time.Duration
+		noSubscription bool
+		muted          bool
+		entitlementErr bool
+
+		wantTitle string
+	}{
+		"Warns when the subscription expires soon": {expiration: 3 * 24 * time.Hour, wantTitle: "Your Ubuntu Pro subscription is about to expire"},
+		"Warns when the subscription expired":      {expiration: -time.Hour, wantTitle: "Your Ubuntu Pro subscription has expired"},
+
+		"Does not warn when the subscription expires later": {expiration: 30 * 24 * time.Hour},
+		"Does not warn when there is no subscription":       {noSubscription: true},
+		"Does not warn when the notifications are muted":    {expiration: time.Hour, muted: true},
+		"Does not warn when the entitlement cannot be read": {expiration: time.Hour, entitlementErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			conf := &mockConfig{entitlementErr: tc.entitlementErr}
+			if !tc.noSubscription {
+				conf.entitlement.SubscriptionExpiration = time.Now().Add(tc.expiration)
+			}
+			if tc.muted {
+				conf.muted = []string{string(notifications.SubscriptionExpiring)}
+			}
+
+			toaster := &mockToaster{}
+			n := notifications.New(conf, notifications.WithToaster(toaster.show))
+
+			n.CheckSubscription(ctx)
+
+			toasts := toaster.toasts()
+			if tc.wantTitle == "" {
+				require.Empty(t, toasts, "CheckSubscription should not have shown any toast")
+				return
+			}
+			require.Equal(t, []string{tc.wantTitle}, toasts, "CheckSubscription showed unexpected toasts")
+		})
+	}
+}
+
+type mockConfig struct {
+	muted    []string
+	prefsErr bool
+
+	entitlement    config.StoreEntitlement
+	entitlementErr bool
+}
+
+func (m *mockConfig) NotificationEnabled(category string) (bool, error) {
+	if m.prefsErr {
+		return false, errors.New("mock error")
+	}
+	return !slices.Contains(m.muted, category), nil
+}
+
+func (m *mockConfig) StoreEntitlement() (config.StoreEntitlement, error) {
+	if m.entitlementErr {
+		return config.StoreEntitlement{}, errors.New("mock error")
+	}
+	return m.entitlement, nil
+}
+
+type mockToaster struct {
+	err bool
+
+	titles []string
+	mu     sync.Mutex
+}
+
+func (m *mockToaster) show(ctx context.Context, title, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.titles = append(m.titles, title)
+	if m.err {
+		return errors.New("mock error")
+	}
+	return nil
+}
+
+func (m *mockToaster) toasts() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.titles)
+}
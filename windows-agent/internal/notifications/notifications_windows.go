@@ -0,0 +1,42 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// createNoWindow prevents a console window from popping up when running powershell.
+// https://learn.microsoft.com/en-us/windows/win32/procthread/process-creation-flags
+const createNoWindow = 0x08000000
+
+// toastScript shows a toast on behalf of the Ubuntu Pro for WSL app. Toasts are only exposed via
+// WinRT, which is reachable from powershell. The title and body are passed via the environment to
+// avoid escaping them.
+const toastScript = `[void][Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType=WindowsRuntime];` +
+	`[void][Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom, ContentType=WindowsRuntime];` +
+	`$app = (Get-AppxPackage -Name CanonicalGroupLimited.UbuntuPro | Select-Object -First 1).PackageFamilyName;` +
+	`if (-not $app) { throw 'Ubuntu Pro for WSL is not installed' };` +
+	`$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02);` +
+	`$text = $xml.GetElementsByTagName('text');` +
+	`[void]$text.Item(0).AppendChild($xml.CreateTextNode($env:UP4W_TOAST_TITLE));` +
+	`[void]$text.Item(1).AppendChild($xml.CreateTextNode($env:UP4W_TOAST_BODY));` +
+	`$toast = [Windows.UI.Notifications.ToastNotification]::new($xml);` +
+	`[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier("$app!App").Show($toast)`
+
+func showToast(ctx context.Context, title, body string) error {
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	cmd.Env = append(cmd.Environ(), "UP4W_TOAST_TITLE="+title, "UP4W_TOAST_BODY="+body)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not show toast: %v. Output: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
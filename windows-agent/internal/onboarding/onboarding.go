@@ -0,0 +1,210 @@
+// Package onboarding tracks how far the user went through the initial setup of the agent, so that
+// the GUI can resume it exactly where it was left off.
+package onboarding
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/ubuntu/decorate"
+	"gopkg.in/yaml.v3"
+)
+
+// Step is a step of the initial setup. Steps are completed in order.
+type Step string
+
+const (
+	// StepSubscription is where the user selects where the Ubuntu Pro subscription comes from.
+	StepSubscription Step = "subscription"
+
+	// StepLandscape is where the user configures Landscape, or skips it.
+	StepLandscape Step = "landscape"
+
+	// StepDistro is where the user waits for a distro to be provisioned.
+	StepDistro Step = "distro"
+
+	// StepDone means that the initial setup is complete. It is never reopened.
+	StepDone Step = "done"
+)
+
+// Config is an interface to easily allow dependency injection. Should be a config.Config
+// in production.
+type Config interface {
+	Subscription() (string, config.Source, error)
+	LandscapeClientConfig() (string, config.Source, error)
+}
+
+// Status is how far the user went through the initial setup.
+type Status struct {
+	// Step is the first step that is not complete.
+	Step Step
+
+	// SubscriptionSelected is true once the subscription comes from any source.
+	SubscriptionSelected bool
+
+	// LandscapeConfigured is true once Landscape is configured from any source.
+	LandscapeConfigured bool
+
+	// LandscapeSkipped is true if the user chose not to configure Landscape.
+	LandscapeSkipped bool
+
+	// DistroProvisioned is true once any distro connected to the agent.
+	DistroProvisioned bool
+}
+
+// Tracker works out the status of the initial setup from the state of the agent, and remembers
+// the choices that leave no trace elsewhere.
+type Tracker struct {
+	conf      Config
+	db        *database.DistroDB
+	statePath string
+
+	state state
+	mu    sync.Mutex
+}
+
+// state is what the tracker stores on disk.
+type state struct {
+	// LandscapeSkipped is true if the user chose not to configure Landscape.
+	LandscapeSkipped bool `yaml:",omitempty"`
+
+	// Completed is when the initial setup was found complete. It stays complete afterwards, even
+	// if e.g. the subscription is removed.
+	Completed time.Time `yaml:",omitempty"`
+}
+
+// New creates a tracker. The choices made during the initial setup are stored in the file at statePath.
+func New(conf Config, db *database.DistroDB, statePath string) (t *Tracker, err error) {
+	defer decorate.OnError(&err, "could not create onboarding tracker")
+
+	s, err := load(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tracker{
+		conf:      conf,
+		db:        db,
+		statePath: statePath,
+		state:     s,
+	}, nil
+}
+
+// Status returns how far the user went through the initial setup.
+func (t *Tracker) Status() (status Status, err error) {
+	defer decorate.OnError(&err, "could not get onboarding status")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, src, err := t.conf.Subscription()
+	if err != nil {
+		return Status{}, err
+	}
+
+	landscape, _, err := t.conf.LandscapeClientConfig()
+	if err != nil {
+		return Status{}, err
+	}
+
+	status = Status{
+		SubscriptionSelected: src != config.SourceNone,
+		LandscapeConfigured:  landscape != "",
+		LandscapeSkipped:     t.state.LandscapeSkipped,
+		DistroProvisioned:    t.distroProvisioned(),
+	}
+
+	switch {
+	case !t.state.Completed.IsZero():
+		status.Step = StepDone
+	case !status.SubscriptionSelected:
+		status.Step = StepSubscription
+	case !status.LandscapeConfigured && !status.LandscapeSkipped:
+		status.Step = StepLandscape
+	case !status.DistroProvisioned:
+		status.Step = StepDistro
+	default:
+		status.Step = StepDone
+
+		s := t.state
+		s.Completed = time.Now()
+		if err := dump(t.statePath, s); err != nil {
+			return Status{}, err
+		}
+		t.state = s
+	}
+
+	return status, nil
+}
+
+// SkipLandscape records that the user chose not to configure Landscape during the initial setup.
+func (t *Tracker) SkipLandscape() (err error) {
+	defer decorate.OnError(&err, "could not skip Landscape onboarding")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state.LandscapeSkipped {
+		return nil
+	}
+
+	s := t.state
+	s.LandscapeSkipped = true
+	if err := dump(t.statePath, s); err != nil {
+		return err
+	}
+	t.state = s
+
+	return nil
+}
+
+// distroProvisioned returns true if any managed distro connected to the agent at least once.
+func (t *Tracker) distroProvisioned() bool {
+	for _, d := range t.db.GetAll() {
+		if !d.LastContact().IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// load reads the choices made during the initial setup. A missing file means that none was made.
+func load(path string) (s state, err error) {
+	out, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state{}, nil
+	} else if err != nil {
+		return state{}, fmt.Errorf("could not read the onboarding state: %v", err)
+	}
+
+	if err := yaml.Unmarshal(out, &s); err != nil {
+		return state{}, fmt.Errorf("could not parse the onboarding state: %v", err)
+	}
+
+	return s, nil
+}
+
+// dump writes the choices made during the initial setup.
+func dump(path string, s state) error {
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("could not marshal the onboarding state: %v", err)
+	}
+
+	tmp := path + ".new"
+	if err := os.WriteFile(tmp, out, 0600); err != nil {
+		return fmt.Errorf("could not write the onboarding state: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("could not write the onboarding state: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package onboarding_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/onboarding"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		state      string
+		stateIsDir bool
+
+		wantErr bool
+	}{
+		"Success without previous state": {},
+		"Success with previous state":    {state: "landscapeskipped: true\n"},
+		"Success with an empty file":     {state: "\n"},
+
+		"Error when the file cannot be read":   {stateIsDir: true, wantErr: true},
+		"Error when the file cannot be parsed": {state: "landscapeskipped: [not a bool", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			statePath := filepath.Join(t.TempDir(), "onboarding.yaml")
+			if tc.state != "" {
+				require.NoError(t, os.WriteFile(statePath, []byte(tc.state), 0600), "Setup: could not write the state file")
+			}
+			if tc.stateIsDir {
+				require.NoError(t, os.MkdirAll(statePath, 0700), "Setup: could not create a directory in place of the state file")
+			}
+
+			_, err := onboarding.New(&mockConfig{}, nil, statePath)
+			if tc.wantErr {
+				require.Error(t, err, "New should return an error")
+				return
+			}
+			require.NoError(t, err, "New should return no error")
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		subscription   bool
+		landscape      bool
+		skipLandscape  bool
+		provisioned    bool
+		completedSetup bool
+		breakSave      bool
+		configErr      bool
+
+		want    onboarding.Step
+		wantErr bool
+	}{
+		"Success at the subscription step":                 {want: onboarding.StepSubscription},
+		"Success at the subscription step with a distro":   {provisioned: true, landscape: true, want: onboarding.StepSubscription},
+		"Success at the Landscape step":                    {subscription: true, want: onboarding.StepLandscape},
+		"Success at the distro step with Landscape":        {subscription: true, landscape: true, want: onboarding.StepDistro},
+		"Success at the distro step skipping Landscape":    {subscription: true, skipLandscape: true, want: onboarding.StepDistro},
+		"Success completing the setup":                     {subscription: true, landscape: true, provisioned: true, want: onboarding.StepDone},
+		"Success completing the setup skipping Landscape":  {subscription: true, skipLandscape: true, provisioned: true, want: onboarding.StepDone},
+		"Success keeping the setup complete once finished": {completedSetup: true, want: onboarding.StepDone},
+
+		"Error when the config cannot be read":         {configErr: true, wantErr: true},
+		"Error when the completion cannot be recorded": {subscription: true, landscape: true, provisioned: true, breakSave: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db := setupDatabase(t, ctx, tc.provisioned)
+			statePath := filepath.Join(t.TempDir(), "onboarding.yaml")
+
+			conf := &mockConfig{configErr: tc.configErr}
+			if tc.subscription {
+				conf.subscriptionSource = config.SourceUser
+			}
+			if tc.landscape {
+				conf.landscapeConfig = "[client]\nuser=JohnDoe"
+			}
+
+			if tc.completedSetup {
+				// Completing the setup once is enough for it to stay complete.
+				tracker, err := onboarding.New(&mockConfig{subscriptionSource: config.SourceUser, landscapeConfig: "[client]"}, setupDatabase(t, ctx, true), statePath)
+				require.NoError(t, err, "Setup: New should return no error")
+				status, err := tracker.Status()
+				require.NoError(t, err, "Setup: Status should return no error")
+				require.Equal(t, onboarding.StepDone, status.Step, "Setup: the initial setup should be complete")
+			}
+
+			tracker, err := onboarding.New(conf, db, statePath)
+			require.NoError(t, err, "Setup: New should return no error")
+
+			if tc.skipLandscape {
+				require.NoError(t, tracker.SkipLandscape(), "Setup: SkipLandscape should return no error")
+			}
+
+			if tc.breakSave {
+				require.NoError(t, os.MkdirAll(statePath, 0700), "Setup: could not create a directory in place of the state file")
+				require.NoError(t, os.WriteFile(filepath.Join(statePath, "file"), nil, 0600), "Setup: could not write a file in the directory")
+			}
+
+			status, err := tracker.Status()
+			if tc.wantErr {
+				require.Error(t, err, "Status should return an error")
+				return
+			}
+			require.NoError(t, err, "Status should return no error")
+
+			require.Equal(t, tc.want, status.Step, "Unexpected onboarding step")
+			require.Equal(t, tc.subscription, status.SubscriptionSelected, "Unexpected subscription status")
+			require.Equal(t, tc.landscape, status.LandscapeConfigured, "Unexpected Landscape status")
+			require.Equal(t, tc.skipLandscape, status.LandscapeSkipped, "Unexpected Landscape skipping status")
+			require.Equal(t, tc.provisioned, status.DistroProvisioned, "Unexpected distro status")
+
+			// The choices and the completion must survive a restart.
+			tracker, err = onboarding.New(conf, db, statePath)
+			require.NoError(t, err, "New should return no error when reading the state back")
+			again, err := tracker.Status()
+			require.NoError(t, err, "Status should return no error after a restart")
+			require.Equal(t, status, again, "The onboarding status should be the same after a restart")
+		})
+	}
+}
+
+// setupDatabase creates a database with a single distro in it, which has connected to the agent
+// only if provisioned is true.
+//
+//nolint:revive // We've decided testing.T always preceedes the context.
+func setupDatabase(t *testing.T, ctx context.Context, provisioned bool) *database.DistroDB {
+	t.Helper()
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	db, err := database.New(ctx, t.TempDir(), nil)
+	require.NoError(t, err, "Setup: database New should not return an error")
+	t.Cleanup(func() { db.Close(ctx) })
+
+	d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+	require.NoError(t, err, "Setup: could not add distro to the database")
+
+	if provisioned {
+		d.SetLastContact(time.Now())
+	}
+
+	return db
+}
+
+type mockConfig struct {
+	subscriptionSource config.Source
+	landscapeConfig    string
+	configErr          bool
+}
+
+func (m mockConfig) Subscription() (string, config.Source, error) {
+	if m.configErr {
+		return "", config.SourceNone, errors.New("mock error")
+	}
+	if m.subscriptionSource == config.SourceNone {
+		return "", config.SourceNone, nil
+	}
+	return "CJd8MMN8wXSWsv7wJT8c8dDK", m.subscriptionSource, nil
+}
+
+func (m mockConfig) LandscapeClientConfig() (string, config.Source, error) {
+	if m.configErr {
+		return "", config.SourceNone, errors.New("mock error")
+	}
+	if m.landscapeConfig == "" {
+		return "", config.SourceNone, nil
+	}
+	return m.landscapeConfig, config.SourceUser, nil
+}
@@ -0,0 +1,8 @@
+package paths
+
+// WithExecutable overrides the path of the executable, which decides whether the install is portable.
+func WithExecutable(path string) Option {
+	return func(o *options) {
+		o.executable = func() (string, error) { return path, nil }
+	}
+}
@@ -0,0 +1,181 @@
+// Package paths resolves the directories where the agent keeps its files, so that every component
+// agrees on where each file goes.
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+)
+
+const (
+	// PortableMarkerFileName is the name of the file that, next to the executable, makes the install portable:
+	// the state and the logs are kept next to the executable instead of in the user profile.
+	PortableMarkerFileName = "portable"
+
+	// portableStateDirName is the name of the directory, next to the executable, where a portable install keeps its state.
+	portableStateDirName = "state"
+
+	// portableLogDirName is the name of the directory, next to the executable, where a portable install keeps its logs.
+	portableLogDirName = "logs"
+)
+
+// Paths are the directories where the agent keeps its files.
+type Paths struct {
+	// Public is where public data goes. Other components, such as the GUI and the distros, need access to it.
+	Public string
+
+	// State is where private data goes. Only the agent needs to see it.
+	State string
+
+	// Log is where the log files go.
+	Log string
+}
+
+type options struct {
+	publicDir string
+	stateDir  string
+	logDir    string
+
+	executable func() (string, error)
+}
+
+// Option is the function signature used to tweak how the directories are resolved.
+type Option func(*options)
+
+// WithPublicDir overrides the public directory. An empty directory keeps the default one.
+func WithPublicDir(dir string) Option {
+	return func(o *options) {
+		o.publicDir = dir
+	}
+}
+
+// WithStateDir overrides the state directory. An empty directory keeps the default one.
+func WithStateDir(dir string) Option {
+	return func(o *options) {
+		o.stateDir = dir
+	}
+}
+
+// WithLogDir overrides the log directory. An empty directory keeps the default one.
+func WithLogDir(dir string) Option {
+	return func(o *options) {
+		o.logDir = dir
+	}
+}
+
+// Resolve returns the directories of the agent, creating them if needed.
+//
+// By default, public data goes to %UserProfile%, private data goes to %LocalAppData% and the logs
+// go next to the public data. A portable install, which has a marker file next to its executable,
+// keeps its state and logs next to the executable instead. The public directory never moves, as
+// the other components look for it in the user profile.
+func Resolve(args ...Option) (p Paths, err error) {
+	opts := options{executable: os.Executable}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	portableDir, err := portableDir(opts.executable)
+	if err != nil {
+		return p, err
+	}
+
+	p.Public = opts.publicDir
+	if p.Public == "" {
+		homeDir := os.Getenv("UserProfile")
+		if homeDir == "" {
+			return p, errors.New("could not create public dir: %UserProfile% is not set")
+		}
+		p.Public = filepath.Join(homeDir, common.UserProfileDir)
+	}
+
+	p.State = opts.stateDir
+	if p.State == "" && portableDir != "" {
+		p.State = filepath.Join(portableDir, portableStateDirName)
+	} else if p.State == "" {
+		localAppData := os.Getenv("LocalAppData")
+		if localAppData == "" {
+			return p, errors.New("could not create private dir: %LocalAppData% is not set")
+		}
+		p.State = filepath.Join(localAppData, common.LocalAppDataDir)
+	}
+
+	p.Log = opts.logDir
+	if p.Log == "" && portableDir != "" {
+		p.Log = filepath.Join(portableDir, portableLogDirName)
+	} else if p.Log == "" {
+		p.Log = p.Public
+	}
+
+	if err := os.MkdirAll(p.Public, 0600); err != nil {
+		return p, fmt.Errorf("could not create public dir %s: %v", p.Public, err)
+	}
+
+	if err := os.MkdirAll(p.State, 0600); err != nil {
+		return p, fmt.Errorf("could not create private dir %s: %v", p.State, err)
+	}
+
+	if err := os.MkdirAll(p.Log, 0600); err != nil {
+		return p, fmt.Errorf("could not create log dir %s: %v", p.Log, err)
+	}
+
+	return p, nil
+}
+
+// portableDir returns the directory of the executable if the install is portable, and an empty string otherwise.
+func portableDir(executable func() (string, error)) (string, error) {
+	exe, err := executable()
+	if err != nil {
+		return "", fmt.Errorf("could not find the executable: %v", err)
+	}
+
+	dir := filepath.Dir(exe)
+	_, err = os.Stat(filepath.Join(dir, PortableMarkerFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("could not check if the install is portable: %v", err)
+	}
+
+	return dir, nil
+}
+
+// PortFile is the file where the address the agent listens on is written.
+func (p Paths) PortFile() string {
+	return filepath.Join(p.Public, common.ListeningPortFileName)
+}
+
+// HvsocketPortFile is the file where the Hyper-V socket port the agent listens on is written.
+func (p Paths) HvsocketPortFile() string {
+	return filepath.Join(p.Public, common.HvsocketPortFileName)
+}
+
+// TelemetryFile is the file that tells the distros that telemetry is enabled.
+func (p Paths) TelemetryFile() string {
+	return filepath.Join(p.Public, common.TelemetryFileName)
+}
+
+// BackupsDir is the directory where distros are exported to by default.
+func (p Paths) BackupsDir() string {
+	return filepath.Join(p.State, consts.BackupsDirName)
+}
+
+// RecurringTasksFile is the file that records when each recurring task last ran.
+func (p Paths) RecurringTasksFile() string {
+	return filepath.Join(p.State, consts.RecurringTasksFileName)
+}
+
+// OnboardingFile is the file that records the choices made during the initial setup.
+func (p Paths) OnboardingFile() string {
+	return filepath.Join(p.State, consts.OnboardingFileName)
+}
+
+// PluginsDir is the directory where plugins declare the task types they implement.
+func (p Paths) PluginsDir() string {
+	return filepath.Join(p.State, consts.PluginsDirName)
+}
@@ -0,0 +1,116 @@
+package paths_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	// Not parallel because we modify the environment
+
+	testCases := map[string]struct {
+		portable     bool
+		overrideDirs bool
+
+		emptyUserProfile  bool
+		emptyLocalAppData bool
+		breakPublicDir    bool
+		breakStateDir     bool
+		breakLogDir       bool
+
+		wantErr bool
+	}{
+		"Success with the default directories":  {},
+		"Success with a portable install":       {portable: true},
+		"Success overriding the directories":    {overrideDirs: true},
+		"Success overriding a portable install": {portable: true, overrideDirs: true},
+
+		"Success with an empty %LocalAppData% in a portable install": {portable: true, emptyLocalAppData: true},
+
+		"Error when %UserProfile% is empty":                 {emptyUserProfile: true, wantErr: true},
+		"Error when %LocalAppData% is empty":                {emptyLocalAppData: true, wantErr: true},
+		"Error when the public directory cannot be created": {breakPublicDir: true, wantErr: true},
+		"Error when the state directory cannot be created":  {breakStateDir: true, wantErr: true},
+		"Error when the log directory cannot be created":    {overrideDirs: true, breakLogDir: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			localAppData := t.TempDir()
+			exeDir := t.TempDir()
+
+			t.Setenv("UserProfile", home)
+			t.Setenv("LocalAppData", localAppData)
+
+			if tc.emptyUserProfile {
+				t.Setenv("UserProfile", "")
+			}
+			if tc.emptyLocalAppData {
+				t.Setenv("LocalAppData", "")
+			}
+
+			if tc.portable {
+				err := os.WriteFile(filepath.Join(exeDir, paths.PortableMarkerFileName), nil, 0600)
+				require.NoError(t, err, "Setup: could not write the portable marker")
+			}
+
+			args := []paths.Option{paths.WithExecutable(filepath.Join(exeDir, "ubuntu-pro-agent.exe"))}
+
+			want := paths.Paths{
+				Public: filepath.Join(home, common.UserProfileDir),
+				State:  filepath.Join(localAppData, common.LocalAppDataDir),
+				Log:    filepath.Join(home, common.UserProfileDir),
+			}
+
+			if tc.portable {
+				want.State = filepath.Join(exeDir, "state")
+				want.Log = filepath.Join(exeDir, "logs")
+			}
+
+			if tc.overrideDirs {
+				override := t.TempDir()
+				want = paths.Paths{
+					Public: filepath.Join(override, "public"),
+					State:  filepath.Join(override, "state"),
+					Log:    filepath.Join(override, "log"),
+				}
+				args = append(args, paths.WithPublicDir(want.Public), paths.WithStateDir(want.State), paths.WithLogDir(want.Log))
+			}
+
+			if tc.breakPublicDir {
+				err := os.WriteFile(want.Public, []byte("I'm here to break the directory"), 0600)
+				require.NoError(t, err, "Setup: could not write file to interfere with the public directory")
+			}
+			if tc.breakStateDir {
+				err := os.MkdirAll(filepath.Dir(want.State), 0700)
+				require.NoError(t, err, "Setup: could not create the parent of the state directory")
+				err = os.WriteFile(want.State, []byte("I'm here to break the directory"), 0600)
+				require.NoError(t, err, "Setup: could not write file to interfere with the state directory")
+			}
+			if tc.breakLogDir {
+				err := os.WriteFile(want.Log, []byte("I'm here to break the directory"), 0600)
+				require.NoError(t, err, "Setup: could not write file to interfere with the log directory")
+			}
+
+			got, err := paths.Resolve(args...)
+			if tc.wantErr {
+				require.Error(t, err, "Resolve should have returned an error")
+				return
+			}
+			require.NoError(t, err, "Resolve should return no error")
+			require.Equal(t, want, got, "Resolve should have returned the expected directories")
+
+			require.DirExists(t, got.Public, "The public directory should have been created")
+			require.DirExists(t, got.State, "The state directory should have been created")
+			require.DirExists(t, got.Log, "The log directory should have been created")
+
+			require.Equal(t, filepath.Join(got.Public, common.ListeningPortFileName), got.PortFile(), "The port file should be in the public directory")
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Package pause lets the user hold back the automatic behaviour of the agent, such as waking
+// distros up, running their tasks and executing Landscape commands, and resume it later.
+package pause
+
+import (
+	"context"
+	"sync"
+)
+
+// Switch tells whether the agent is paused, and lets components wait until it is resumed.
+type Switch struct {
+	// resumed is closed while the agent is not paused, and pausedCh while it is.
+	resumed  chan struct{}
+	pausedCh chan struct{}
+	mu       sync.Mutex
+}
+
+// New creates a switch in the given state.
+func New(paused bool) *Switch {
+	s := &Switch{
+		resumed:  make(chan struct{}),
+		pausedCh: make(chan struct{}),
+	}
+
+	if paused {
+		close(s.pausedCh)
+	} else {
+		close(s.resumed)
+	}
+
+	return s
+}
+
+// Set pauses or resumes the agent. It returns true if the state changed.
+func (s *Switch) Set(paused bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if paused == s.paused() {
+		return false
+	}
+
+	if paused {
+		s.resumed = make(chan struct{})
+		close(s.pausedCh)
+	} else {
+		s.pausedCh = make(chan struct{})
+		close(s.resumed)
+	}
+
+	return true
+}
+
+// Paused returns true if the agent is paused.
+func (s *Switch) Paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paused()
+}
+
+// WaitResumed blocks until the agent is not paused, or the context is done.
+func (s *Switch) WaitResumed(ctx context.Context) error {
+	s.mu.Lock()
+	resumed := s.resumed
+	s.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitPaused blocks until the agent is paused, or the context is done.
+func (s *Switch) WaitPaused(ctx context.Context) error {
+	s.mu.Lock()
+	paused := s.pausedCh
+	s.mu.Unlock()
+
+	select {
+	case <-paused:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// paused must be called with the lock held.
+func (s *Switch) paused() bool {
+	select {
+	case <-s.resumed:
+		return false
+	default:
+		return true
+	}
+}
+
+// Pauser holds back the automatic behaviour of the agent, such as a Switch does.
+type Pauser interface {
+	Paused() bool
+	WaitResumed(context.Context) error
+	WaitPaused(context.Context) error
+}
+
+// Any combines pausers into one that is paused while any of them is.
+func Any(pausers ...Pauser) Pauser {
+	return anyPauser(pausers)
+}
+
+type anyPauser []Pauser
+
+// Paused returns true if any of the pausers is paused.
+func (a anyPauser) Paused() bool {
+	for _, p := range a {
+		if p.Paused() {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitResumed blocks until none of the pausers is paused, or the context is done.
+func (a anyPauser) WaitResumed(ctx context.Context) error {
+	for {
+		for _, p := range a {
+			if err := p.WaitResumed(ctx); err != nil {
+				return err
+			}
+		}
+
+		// One of them may have been paused again while waiting for the others.
+		if !a.Paused() {
+			return nil
+		}
+	}
+}
+
+// WaitPaused blocks until any of the pausers is paused, or the context is done.
+func (a anyPauser) WaitPaused(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	paused := make(chan struct{}, len(a))
+	for _, p := range a {
+		go func() {
+			if p.WaitPaused(ctx) == nil {
+				paused <- struct{}{}
+			}
+		}()
+	}
+
+	select {
+	case <-paused:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
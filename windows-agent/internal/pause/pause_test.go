@@ -0,0 +1,160 @@
+package pause_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		initiallyPaused bool
+		set             bool
+
+		wantChanged bool
+	}{
+		"Pausing":                  {set: true, wantChanged: true},
+		"Resuming":                 {initiallyPaused: true, wantChanged: true},
+		"Pausing when paused":      {initiallyPaused: true, set: true},
+		"Resuming when not paused": {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := pause.New(tc.initiallyPaused)
+			require.Equal(t, tc.initiallyPaused, s.Paused(), "Switch should start in the requested state")
+
+			changed := s.Set(tc.set)
+			if tc.set {
+				require.NoError(t, s.WaitPaused(context.Background()), "WaitPaused should return right away when paused")
+			} else {
+				require.NoError(t, s.WaitResumed(context.Background()), "WaitResumed should return right away when not paused")
+			}
+			require.Equal(t, tc.wantChanged, changed, "Set should report whether the state changed")
+			require.Equal(t, tc.set, s.Paused(), "Switch should be in the state that was set")
+		})
+	}
+}
+
+func TestWaitResumed(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		paused     bool
+		resume     bool
+		cancelWait bool
+
+		wantErr bool
+	}{
+		"Returns right away when not paused": {},
+		"Returns when resumed":               {paused: true, resume: true},
+
+		"Error when the context is cancelled while paused": {paused: true, cancelWait: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := pause.New(tc.paused)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan error)
+			go func() { done <- s.WaitResumed(ctx) }()
+
+			if tc.paused {
+				select {
+				case <-done:
+					require.Fail(t, "WaitResumed should block while paused")
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+
+			if tc.resume {
+				s.Set(false)
+			}
+			if tc.cancelWait {
+				cancel()
+			}
+
+			var err error
+			select {
+			case err = <-done:
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "WaitResumed should have returned")
+			}
+
+			if tc.wantErr {
+				require.Error(t, err, "WaitResumed should return an error")
+				return
+			}
+			require.NoError(t, err, "WaitResumed should return no error")
+		})
+	}
+}
+
+func TestAny(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		paused []bool
+
+		wantPaused bool
+	}{
+		"Not paused when none is paused":  {paused: []bool{false, false}},
+		"Paused when one is paused":       {paused: []bool{false, true}, wantPaused: true},
+		"Paused when all are paused":      {paused: []bool{true, true}, wantPaused: true},
+		"Not paused when there is none":   {},
+		"Paused when the only one is too": {paused: []bool{true}, wantPaused: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var switches []*pause.Switch
+			var pausers []pause.Pauser
+			for _, p := range tc.paused {
+				s := pause.New(p)
+				switches = append(switches, s)
+				pausers = append(pausers, s)
+			}
+
+			a := pause.Any(pausers...)
+			require.Equal(t, tc.wantPaused, a.Paused(), "Paused should be true if any of the pausers is paused")
+
+			if !tc.wantPaused {
+				require.NoError(t, a.WaitResumed(ctx), "WaitResumed should return right away when none is paused")
+				return
+			}
+			require.NoError(t, a.WaitPaused(ctx), "WaitPaused should return right away when one is paused")
+
+			resumed := make(chan error)
+			go func() { resumed <- a.WaitResumed(ctx) }()
+
+			for _, s := range switches {
+				select {
+				case <-resumed:
+					require.Fail(t, "WaitResumed should block while any of the pausers is paused")
+				case <-time.After(100 * time.Millisecond):
+				}
+				s.Set(false)
+			}
+
+			require.NoError(t, <-resumed, "WaitResumed should return once none is paused")
+			require.False(t, a.Paused(), "Paused should be false once none is paused")
+		})
+	}
+}
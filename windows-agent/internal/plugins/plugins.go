@@ -0,0 +1,165 @@
+// Package plugins lets other components extend the agent with their own task types. A plugin
+// declares the task types it implements in a manifest in the plugins directory, and implements
+// the TaskPlugin gRPC service so that the agent can call it back to execute them.
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/ubuntu/decorate"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest declares a plugin and the task types it implements.
+type Manifest struct {
+	// Name identifies the plugin in the logs.
+	Name string
+
+	// Address is where the plugin serves the TaskPlugin gRPC service, in host:port format. It must be
+	// a loopback address, as the agent calls the plugins without encryption.
+	Address string
+
+	// TaskTypes are the types of task the plugin can execute.
+	TaskTypes []string `yaml:"task_types"`
+}
+
+func (m Manifest) validate() error {
+	if m.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if m.Address == "" {
+		return errors.New("address must not be empty")
+	}
+	host, _, err := net.SplitHostPort(m.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %v", err)
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("address %q is not a loopback address: plugins must run on this machine", m.Address)
+	}
+	if len(m.TaskTypes) == 0 {
+		return errors.New("at least one task type must be declared")
+	}
+	for _, t := range m.TaskTypes {
+		if t == "" {
+			return errors.New("task types must not be empty")
+		}
+	}
+	return nil
+}
+
+// isLoopback returns true if the host is the local machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// loaded is the registry loaded last. The tasks read back from disk after a restart do not know the
+// registry that created them, so they are executed with this one.
+var loaded atomic.Pointer[Registry]
+
+// Registry contains the task types declared by the plugins.
+type Registry struct {
+	plugins map[string]Manifest
+}
+
+// Load reads every manifest in the directory. A missing directory means there are no plugins.
+// Invalid manifests are skipped, and so are the task types already declared by another plugin.
+// The tasks read back from disk are executed with the registry loaded last.
+func Load(ctx context.Context, dir string) (r *Registry, err error) {
+	defer decorate.OnError(&err, "could not load plugins")
+
+	r = &Registry{plugins: make(map[string]Manifest)}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Sorted so that conflicts between plugins are always resolved the same way.
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+
+		m, err := readManifest(filepath.Join(dir, e.Name()))
+		if err != nil {
+			log.Warningf(ctx, "Plugins: skipping manifest %q: %v", e.Name(), err)
+			continue
+		}
+
+		for _, t := range m.TaskTypes {
+			if other, ok := r.plugins[t]; ok {
+				log.Warningf(ctx, "Plugins: skipping task type %q of plugin %q: already declared by plugin %q", t, m.Name, other.Name)
+				continue
+			}
+			r.plugins[t] = m
+		}
+
+		log.Infof(ctx, "Plugins: loaded plugin %q", m.Name)
+	}
+
+	loaded.Store(r)
+	return r, nil
+}
+
+func readManifest(path string) (m Manifest, err error) {
+	out, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+
+	if err := yaml.Unmarshal(out, &m); err != nil {
+		return m, fmt.Errorf("could not unmarshal manifest: %v", err)
+	}
+
+	if err := m.validate(); err != nil {
+		return m, fmt.Errorf("invalid manifest: %v", err)
+	}
+
+	return m, nil
+}
+
+// Task returns a task for the distro, of a type declared by a plugin. The payload is passed to the
+// plugin as is.
+func (r *Registry) Task(distroName, taskType string, payload []byte) (task.Task, error) {
+	m, ok := r.plugins[taskType]
+	if !ok {
+		return nil, fmt.Errorf("no plugin declares task type %q", taskType)
+	}
+
+	return Task{
+		Plugin:     m.Name,
+		Type:       taskType,
+		DistroName: distroName,
+		Payload:    payload,
+		registry:   r,
+	}, nil
+}
+
+// address returns the address of the plugin, as long as it still declares the task type.
+func (r *Registry) address(plugin, taskType string) (string, error) {
+	m, ok := r.plugins[taskType]
+	if !ok || m.Name != plugin {
+		return "", fmt.Errorf("plugin %q no longer declares task type %q", plugin, taskType)
+	}
+	return m.Address, nil
+}
@@ -0,0 +1,172 @@
+package plugins_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/plugins"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noDir   bool
+		dirFile bool
+
+		// wantPlugins maps the task types to the plugin that should execute them.
+		wantPlugins    map[string]string
+		wantUndeclared []string
+		wantErr        bool
+	}{
+		"Success loading manifests": {wantPlugins: map[string]string{"maas.enlist": "maas", "maas.release": "maas", "gui.refresh": "gui"}},
+		"Success skipping invalid manifests": {
+			wantPlugins:    map[string]string{"gui.refresh": "gui"},
+			wantUndeclared: []string{"noaddress.task", "badaddress.task", "remote.task"},
+		},
+		"Success skipping task types declared twice": {wantPlugins: map[string]string{"shared.task": "first", "first.task": "first", "second.task": "second"}},
+		"Success with no plugins directory":          {noDir: true},
+
+		"Error when the plugins directory cannot be read": {dirFile: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			dir := testutils.TestFixturePath(t)
+			if tc.noDir {
+				dir = filepath.Join(t.TempDir(), "plugins")
+			}
+			if tc.dirFile {
+				dir = filepath.Join(t.TempDir(), "plugins")
+				require.NoError(t, os.WriteFile(dir, nil, 0600), "Setup: could not write file in place of the plugins directory")
+			}
+
+			r, err := plugins.Load(ctx, dir)
+			if tc.wantErr {
+				require.Error(t, err, "Load should return an error")
+				return
+			}
+			require.NoError(t, err, "Load should return no error")
+
+			for taskType, plugin := range tc.wantPlugins {
+				got, err := r.Task("myDistro", taskType, nil)
+				require.NoError(t, err, "Task type %q should have been declared", taskType)
+				require.Equal(t, plugin, got.(plugins.Task).Plugin, "Task type %q should be executed by another plugin", taskType)
+			}
+
+			for _, taskType := range append(tc.wantUndeclared, "undeclared.task") {
+				_, err = r.Task("myDistro", taskType, nil)
+				require.Error(t, err, "Task should return an error for undeclared task type %q", taskType)
+			}
+		})
+	}
+}
+
+func TestTaskExecute(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		pluginErr   string
+		pluginRetry bool
+		unreachable bool
+
+		wantErr   bool
+		wantRetry bool
+	}{
+		"Success when the plugin executes the task": {},
+
+		"Error when the plugin fails the task":          {pluginErr: "mock error", wantErr: true},
+		"Error when the plugin asks to retry the task":  {pluginErr: "mock error", pluginRetry: true, wantErr: true, wantRetry: true},
+		"Error to be retried when the plugin is absent": {unreachable: true, wantErr: true, wantRetry: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			plugin := &mockPlugin{err: tc.pluginErr, retry: tc.pluginRetry}
+			addr := servePlugin(t, plugin)
+			if tc.unreachable {
+				addr = unusedAddress(t)
+			}
+
+			dir := t.TempDir()
+			manifest := fmt.Sprintf("name: mock\naddress: %s\ntask_types:\n  - mock.task\n", addr)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "mock.yaml"), []byte(manifest), 0600), "Setup: could not write manifest")
+
+			r, err := plugins.Load(ctx, dir)
+			require.NoError(t, err, "Setup: Load should return no error")
+
+			tk, err := r.Task("myDistro", "mock.task", []byte("hello"))
+			require.NoError(t, err, "Setup: Task should return no error")
+
+			err = tk.Execute(ctx, nil)
+			if !tc.unreachable {
+				require.Equal(t, &agentapi.PluginTask{Type: "mock.task", DistroName: "myDistro", Payload: []byte("hello")},
+					plugin.received, "The plugin should have received the task")
+			}
+
+			if !tc.wantErr {
+				require.NoError(t, err, "Execute should return no error")
+				return
+			}
+			require.Error(t, err, "Execute should return an error")
+			require.Equal(t, tc.wantRetry, errors.As(err, &task.NeedsRetryError{}), "Execute returned an unexpected kind of error")
+		})
+	}
+}
+
+type mockPlugin struct {
+	agentapi.UnimplementedTaskPluginServer
+
+	err   string
+	retry bool
+
+	received *agentapi.PluginTask
+}
+
+func (p *mockPlugin) ExecuteTask(_ context.Context, msg *agentapi.PluginTask) (*agentapi.PluginTaskResult, error) {
+	p.received = &agentapi.PluginTask{Type: msg.GetType(), DistroName: msg.GetDistroName(), Payload: msg.GetPayload()}
+	return &agentapi.PluginTaskResult{Error: p.err, Retry: p.retry}, nil
+}
+
+// servePlugin serves the plugin until the test ends, and returns its address.
+func servePlugin(t *testing.T, p *mockPlugin) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err, "Setup: could not listen")
+
+	server := grpc.NewServer()
+	agentapi.RegisterTaskPluginServer(server, p)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+// unusedAddress returns an address nobody is listening on.
+func unusedAddress(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err, "Setup: could not listen")
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close(), "Setup: could not close listener")
+
+	return addr
+}
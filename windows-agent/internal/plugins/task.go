@@ -0,0 +1,77 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func init() {
+	task.Register[Task]()
+}
+
+// Task is a task whose execution is delegated to the plugin that declared its type. The address
+// of the plugin is looked up in the registry when the task is executed, so that a task read back
+// from disk is sent to the plugin as currently declared, and never to an address stored with it.
+type Task struct {
+	Plugin     string
+	Type       string
+	DistroName string
+	Payload    []byte
+
+	// registry created the task. It is nil once the task is read back from disk.
+	registry *Registry
+}
+
+// Execute calls the plugin back to execute the task. The task is retried at the next startup if
+// the plugin cannot be reached, or if the plugin asks for it. It fails if the plugin no longer declares
+// the type of the task.
+func (t Task) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	r := t.registry
+	if r == nil {
+		r = loaded.Load()
+	}
+	if r == nil {
+		return task.NeedsRetryError{SourceErr: fmt.Errorf("could not reach plugin %q: plugins are not loaded", t.Plugin)}
+	}
+
+	addr, err := r.address(t.Plugin, t.Type)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return task.NeedsRetryError{SourceErr: fmt.Errorf("could not connect to plugin %q: %v", t.Plugin, err)}
+	}
+	defer conn.Close()
+
+	res, err := agentapi.NewTaskPluginClient(conn).ExecuteTask(ctx, &agentapi.PluginTask{
+		Type:       t.Type,
+		DistroName: t.DistroName,
+		Payload:    t.Payload,
+	})
+	if err != nil {
+		return task.NeedsRetryError{SourceErr: fmt.Errorf("could not reach plugin %q: %v", t.Plugin, err)}
+	}
+
+	if res.GetError() == "" {
+		return nil
+	}
+
+	err = fmt.Errorf("plugin %q: %s", t.Plugin, res.GetError())
+	if res.GetRetry() {
+		return task.NeedsRetryError{SourceErr: err}
+	}
+	return err
+}
+
+// String is needed to fulfil Task.
+func (t Task) String() string {
+	return fmt.Sprintf("Plugin task %s (%s)", t.Type, t.Plugin)
+}
@@ -0,0 +1,48 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+)
+
+// ProbeMock is a fake system for the preflight checks.
+type ProbeMock struct {
+	NotInstalled    bool
+	VersionErr      bool
+	VersionOutput   string
+	DefaultVersion  uint32
+	DefaultErr      bool
+	FeatureDisabled bool
+	FeatureErr      bool
+}
+
+func (p ProbeMock) wslVersion(context.Context) (string, error) {
+	if p.NotInstalled {
+		return "", errWSLNotInstalled
+	}
+	if p.VersionErr {
+		return "", errors.New("mock error")
+	}
+	return p.VersionOutput, nil
+}
+
+func (p ProbeMock) defaultVersion() (uint32, error) {
+	if p.DefaultErr {
+		return 0, errors.New("mock error")
+	}
+	return p.DefaultVersion, nil
+}
+
+func (p ProbeMock) featureEnabled(context.Context, string) (bool, error) {
+	if p.FeatureErr {
+		return false, errors.New("mock error")
+	}
+	return !p.FeatureDisabled, nil
+}
+
+// WithProbe replaces the system probe.
+func WithProbe(p ProbeMock) Option {
+	return func(o *options) {
+		o.probe = p
+	}
+}
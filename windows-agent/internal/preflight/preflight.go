@@ -0,0 +1,280 @@
+// Package preflight checks that WSL is usable by the agent, so that missing or outdated
+// components are reported with a way to fix them, instead of failing obscurely the first
+// time a distro is touched.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+)
+
+// MinWSLVersion is the oldest version of WSL the agent supports.
+const MinWSLVersion = "2.0.0"
+
+// Action is a remediation that fixes a problem.
+type Action string
+
+const (
+	// ActionInstallWSL -> WSL must be installed.
+	ActionInstallWSL Action = "install-wsl"
+
+	// ActionUpdateWSL -> WSL must be updated.
+	ActionUpdateWSL Action = "update-wsl"
+
+	// ActionSetDefaultVersion -> WSL 2 must be made the default version for new distros.
+	ActionSetDefaultVersion Action = "set-default-version"
+
+	// ActionEnableFeature -> a Windows optional feature must be enabled.
+	ActionEnableFeature Action = "enable-feature"
+)
+
+// Problem is an issue found by a preflight check.
+type Problem struct {
+	Severity config.Severity
+
+	// Check is the name of the check that found the problem.
+	Check string
+
+	Message string
+
+	// Action is what fixes the problem, and Remediation explains it to the user.
+	Action      Action
+	Remediation string
+}
+
+// String implements the fmt.Stringer interface.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s: %s. %s", p.Severity, p.Check, p.Message, p.Remediation)
+}
+
+// errWSLNotInstalled is returned by the probe when there is no wsl.exe.
+var errWSLNotInstalled = errors.New("wsl.exe not found")
+
+// probe inspects the system. It is an interface to allow dependency injection in tests.
+type probe interface {
+	// wslVersion returns the output of 'wsl --version'.
+	wslVersion(ctx context.Context) (string, error)
+
+	// defaultVersion returns the version of WSL new distros are registered with.
+	defaultVersion() (uint32, error)
+
+	// featureEnabled returns true if the Windows optional feature is enabled.
+	featureEnabled(ctx context.Context, name string) (bool, error)
+}
+
+// Checker runs the preflight checks and keeps the problems found.
+type Checker struct {
+	probe probe
+
+	problems []Problem
+	mu       sync.RWMutex
+}
+
+type options struct {
+	probe probe
+}
+
+// Option is an optional argument for the checker.
+type Option = func(*options)
+
+// New creates a checker. No check is run until Run is called.
+func New(args ...Option) *Checker {
+	opts := options{
+		probe: systemProbe{},
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return &Checker{probe: opts.probe}
+}
+
+// Run runs every check, logs the problems found and keeps them until the next run.
+func (c *Checker) Run(ctx context.Context) []Problem {
+	problems := c.check(ctx)
+
+	for _, p := range problems {
+		if p.Severity == config.SeverityError {
+			log.Errorf(ctx, "Preflight: %s", p)
+			continue
+		}
+		log.Warningf(ctx, "Preflight: %s", p)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.problems = problems
+	return problems
+}
+
+// Problems returns the problems found by the last run.
+func (c *Checker) Problems() []Problem {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.problems
+}
+
+// check runs every check and returns the problems found.
+func (c *Checker) check(ctx context.Context) []Problem {
+	var problems []Problem
+
+	p, ok := c.checkVersion(ctx)
+	if p != nil {
+		problems = append(problems, *p)
+	}
+	if !ok {
+		// The other checks make no sense without an up-to-date WSL.
+		return problems
+	}
+
+	if p := c.checkDefaultVersion(); p != nil {
+		problems = append(problems, *p)
+	}
+
+	if p := c.checkFeature(ctx, "VirtualMachinePlatform", "Virtual Machine Platform"); p != nil {
+		problems = append(problems, *p)
+	}
+
+	return problems
+}
+
+// checkVersion checks that WSL is installed and recent enough. It returns false if it is not.
+func (c *Checker) checkVersion(ctx context.Context) (*Problem, bool) {
+	out, err := c.probe.wslVersion(ctx)
+	if errors.Is(err, errWSLNotInstalled) {
+		return &Problem{
+			Severity:    config.SeverityError,
+			Check:       "wsl-installed",
+			Message:     "WSL is not installed",
+			Action:      ActionInstallWSL,
+			Remediation: "Run 'wsl --install --no-distribution' and restart Windows",
+		}, false
+	} else if err != nil {
+		// Only the WSL that ships with Windows lacks the --version option.
+		return &Problem{
+			Severity:    config.SeverityError,
+			Check:       "wsl-version",
+			Message:     fmt.Sprintf("WSL is too old, the agent requires version %s or later", MinWSLVersion),
+			Action:      ActionUpdateWSL,
+			Remediation: "Run 'wsl --update'",
+		}, false
+	}
+
+	version, err := parseVersion(out)
+	if err != nil {
+		return &Problem{
+			Severity:    config.SeverityWarning,
+			Check:       "wsl-version",
+			Message:     fmt.Sprintf("could not find the version of WSL: %v", err),
+			Action:      ActionUpdateWSL,
+			Remediation: "Run 'wsl --update'",
+		}, true
+	}
+
+	if compareVersions(version, MinWSLVersion) < 0 {
+		return &Problem{
+			Severity:    config.SeverityError,
+			Check:       "wsl-version",
+			Message:     fmt.Sprintf("WSL %s is too old, the agent requires version %s or later", version, MinWSLVersion),
+			Action:      ActionUpdateWSL,
+			Remediation: "Run 'wsl --update'",
+		}, false
+	}
+
+	return nil, true
+}
+
+// checkDefaultVersion checks that new distros are registered with WSL 2.
+func (c *Checker) checkDefaultVersion() *Problem {
+	v, err := c.probe.defaultVersion()
+	if err != nil {
+		return &Problem{
+			Severity:    config.SeverityWarning,
+			Check:       "wsl-default-version",
+			Message:     fmt.Sprintf("could not find the default version of WSL: %v", err),
+			Action:      ActionSetDefaultVersion,
+			Remediation: "Run 'wsl --set-default-version 2'",
+		}
+	}
+
+	if v != 2 {
+		return &Problem{
+			Severity:    config.SeverityWarning,
+			Check:       "wsl-default-version",
+			Message:     fmt.Sprintf("new distros are registered with WSL %d, which the agent does not support", v),
+			Action:      ActionSetDefaultVersion,
+			Remediation: "Run 'wsl --set-default-version 2'",
+		}
+	}
+
+	return nil
+}
+
+// checkFeature checks that a Windows optional feature is enabled.
+func (c *Checker) checkFeature(ctx context.Context, name, displayName string) *Problem {
+	enabled, err := c.probe.featureEnabled(ctx, name)
+	if err != nil {
+		return &Problem{
+			Severity:    config.SeverityWarning,
+			Check:       "windows-features",
+			Message:     fmt.Sprintf("could not find whether the %s feature is enabled: %v", displayName, err),
+			Action:      ActionEnableFeature,
+			Remediation: fmt.Sprintf("Make sure the %s optional feature is enabled", displayName),
+		}
+	}
+
+	if !enabled {
+		return &Problem{
+			Severity:    config.SeverityError,
+			Check:       "windows-features",
+			Message:     fmt.Sprintf("the %s feature is disabled, which WSL 2 requires", displayName),
+			Action:      ActionEnableFeature,
+			Remediation: fmt.Sprintf("Enable the %s optional feature and restart Windows", displayName),
+		}
+	}
+
+	return nil
+}
+
+// versionRegex matches versions such as 2.0.14.0.
+var versionRegex = regexp.MustCompile(`[0-9]+(\.[0-9]+)+`)
+
+// parseVersion finds the version of WSL in the output of 'wsl --version'. The output is
+// localized, but the version of WSL is always the first one listed.
+func parseVersion(out string) (string, error) {
+	v := versionRegex.FindString(out)
+	if v == "" {
+		return "", fmt.Errorf("no version in %q", out)
+	}
+	return v, nil
+}
+
+// compareVersions returns a negative number if a is older than b, a positive one if it is more
+// recent, and zero if they are the same. Missing components count as zero.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
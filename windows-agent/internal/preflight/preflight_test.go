@@ -0,0 +1,85 @@
+package preflight_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/preflight"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		notInstalled    bool
+		versionErr      bool
+		versionOutput   string
+		defaultVersion  uint32
+		defaultErr      bool
+		featureDisabled bool
+		featureErr      bool
+
+		wantActions  []preflight.Action
+		wantSeverity []config.Severity
+	}{
+		"Success":                          {},
+		"Success with a localized output":  {versionOutput: "Version de WSL : 2.1.5.0\nVersion du noyau : 5.15.146.1-2"},
+		"Success with the minimum version": {versionOutput: "WSL version: 2.0.0"},
+
+		"WSL is not installed":                      {notInstalled: true, wantActions: []preflight.Action{preflight.ActionInstallWSL}, wantSeverity: []config.Severity{config.SeverityError}},
+		"WSL is the inbox version":                  {versionErr: true, wantActions: []preflight.Action{preflight.ActionUpdateWSL}, wantSeverity: []config.Severity{config.SeverityError}},
+		"WSL is older than the minimum version":     {versionOutput: "WSL version: 1.2.5.0", wantActions: []preflight.Action{preflight.ActionUpdateWSL}, wantSeverity: []config.Severity{config.SeverityError}},
+		"WSL version cannot be parsed":              {versionOutput: "garbage", wantActions: []preflight.Action{preflight.ActionUpdateWSL}, wantSeverity: []config.Severity{config.SeverityWarning}},
+		"Default version is WSL 1":                  {defaultVersion: 1, wantActions: []preflight.Action{preflight.ActionSetDefaultVersion}, wantSeverity: []config.Severity{config.SeverityWarning}},
+		"Default version cannot be read":            {defaultErr: true, wantActions: []preflight.Action{preflight.ActionSetDefaultVersion}, wantSeverity: []config.Severity{config.SeverityWarning}},
+		"Virtual Machine Platform is disabled":      {featureDisabled: true, wantActions: []preflight.Action{preflight.ActionEnableFeature}, wantSeverity: []config.Severity{config.SeverityError}},
+		"Virtual Machine Platform cannot be probed": {featureErr: true, wantActions: []preflight.Action{preflight.ActionEnableFeature}, wantSeverity: []config.Severity{config.SeverityWarning}},
+
+		"Several problems are reported together":        {defaultVersion: 1, featureDisabled: true, wantActions: []preflight.Action{preflight.ActionSetDefaultVersion, preflight.ActionEnableFeature}, wantSeverity: []config.Severity{config.SeverityWarning, config.SeverityError}},
+		"Other checks are skipped when WSL is outdated": {versionErr: true, defaultVersion: 1, featureDisabled: true, wantActions: []preflight.Action{preflight.ActionUpdateWSL}, wantSeverity: []config.Severity{config.SeverityError}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			if tc.versionOutput == "" {
+				tc.versionOutput = "WSL version: 2.1.5.0\nKernel version: 5.15.146.1-2\nWSLg version: 1.0.60"
+			}
+			if tc.defaultVersion == 0 {
+				tc.defaultVersion = 2
+			}
+
+			c := preflight.New(preflight.WithProbe(preflight.ProbeMock{
+				NotInstalled:    tc.notInstalled,
+				VersionErr:      tc.versionErr,
+				VersionOutput:   tc.versionOutput,
+				DefaultVersion:  tc.defaultVersion,
+				DefaultErr:      tc.defaultErr,
+				FeatureDisabled: tc.featureDisabled,
+				FeatureErr:      tc.featureErr,
+			}))
+
+			require.Empty(t, c.Problems(), "Problems should be empty before the checks are run")
+
+			problems := c.Run(ctx)
+			require.Equal(t, problems, c.Problems(), "Problems should return the problems found by the last run")
+
+			var gotActions []preflight.Action
+			var gotSeverity []config.Severity
+			for _, p := range problems {
+				require.NotEmpty(t, p.Check, "Problems should name the check that found them")
+				require.NotEmpty(t, p.Message, "Problems should have a message")
+				require.NotEmpty(t, p.Remediation, "Problems should explain how to fix them")
+				gotActions = append(gotActions, p.Action)
+				gotSeverity = append(gotSeverity, p.Severity)
+			}
+
+			require.Equal(t, tc.wantActions, gotActions, "Mismatch between the expected and the reported remediation actions")
+			require.Equal(t, tc.wantSeverity, gotSeverity, "Mismatch between the expected and the reported severities")
+		})
+	}
+}
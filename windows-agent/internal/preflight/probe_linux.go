@@ -0,0 +1,18 @@
+package preflight
+
+import "context"
+
+// systemProbe pretends to inspect a host where WSL is ready, as there is no WSL on Linux.
+type systemProbe struct{}
+
+func (systemProbe) wslVersion(context.Context) (string, error) {
+	return "WSL version: " + MinWSLVersion, nil
+}
+
+func (systemProbe) defaultVersion() (uint32, error) {
+	return 2, nil
+}
+
+func (systemProbe) featureEnabled(context.Context, string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// https://learn.microsoft.com/en-us/windows/win32/procthread/process-creation-flags
+//
+// CREATE_NO_WINDOW:
+// The process is a console application that is being run without
+// a console window. Therefore, the console handle for the
+// application is not set.
+const createNoWindow = 0x08000000
+
+// systemProbe inspects the Windows host.
+type systemProbe struct{}
+
+// wslVersion runs 'wsl --version'.
+func (systemProbe) wslVersion(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "wsl.exe", "--version")
+	cmd.Env = append(os.Environ(), "WSL_UTF8=1")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+
+	out, err := cmd.CombinedOutput()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", errWSLNotInstalled
+	} else if err != nil {
+		return "", fmt.Errorf("could not run 'wsl --version': %v. Output: %s", err, out)
+	}
+
+	return string(out), nil
+}
+
+// defaultVersion reads the default version of WSL from the registry. WSL 2 is the default when unset.
+func (systemProbe) defaultVersion() (uint32, error) {
+	k, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Lxss`, registry.QUERY_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return 2, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer k.Close()
+
+	v, _, err := k.GetIntegerValue("DefaultVersion")
+	if errors.Is(err, registry.ErrNotExist) {
+		return 2, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return uint32(v), nil
+}
+
+// featureEnabled asks WMI for the install state of the optional feature, which does not require
+// administrator rights unlike DISM.
+func (systemProbe) featureEnabled(ctx context.Context, name string) (bool, error) {
+	script := fmt.Sprintf("(Get-CimInstance -ClassName Win32_OptionalFeature -Filter \"Name='%s'\").InstallState", name)
+
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("could not query optional feature %q: %v. Output: %s", name, err, out)
+	}
+
+	// https://learn.microsoft.com/en-us/windows/win32/cimwin32prov/win32-optionalfeature
+	// An InstallState of 1 means enabled.
+	return strings.TrimSpace(string(out)) == "1", nil
+}
@@ -0,0 +1,396 @@
+// Package backup implements a service that exports managed distros to tarballs, either on request
+// or periodically according to the backup schedule.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// defaultCheckInterval is how often the backup schedule is checked.
+	defaultCheckInterval = 15 * time.Minute
+
+	// progressInterval is how often the progress of an export is reported.
+	progressInterval = time.Second
+
+	// timestampFormat is the format of the timestamp in the name of the backups.
+	timestampFormat = "20060102T150405Z"
+
+	// partialSuffix is appended to the name of backups that are still being exported.
+	partialSuffix = ".partial"
+)
+
+// Config is an interface to easily allow dependency injection. Should be a config.Config
+// in production.
+type Config interface {
+	BackupSchedule() (config.BackupSchedule, error)
+}
+
+// ProgressFunc is called periodically during an export with the number of bytes written so far.
+type ProgressFunc func(written int64)
+
+// Service exports distros to tarballs with wsl --export.
+type Service struct {
+	ctx  context.Context
+	stop func()
+
+	running chan struct{}
+
+	conf       Config
+	db         *database.DistroDB
+	defaultDir string
+
+	checkInterval time.Duration
+
+	// pauser skips the scheduled backups while the agent is paused. It may be nil.
+	pauser Pauser
+
+	// schedule holds back the scheduled backups like the other non-urgent tasks. It may be nil.
+	schedule Schedule
+
+	// exporting contains the names of the distros being exported.
+	exporting   map[string]struct{}
+	exportingMu sync.Mutex
+}
+
+// Pauser tells the service whether the agent is paused by the user.
+type Pauser interface {
+	Paused() bool
+}
+
+// Schedule decides when tasks that are not urgent, such as the scheduled backups, can run.
+type Schedule interface {
+	// TaskWindow returns true if non-urgent tasks can run at the given time, and how long it
+	// takes until that changes.
+	TaskWindow(now time.Time) (open bool, next time.Duration)
+}
+
+type options struct {
+	checkInterval time.Duration
+	pauser        Pauser
+	schedule      Schedule
+}
+
+// Option is an optional argument for the backup service.
+type Option = func(*options)
+
+// WithCheckInterval overrides how often the backup schedule is checked.
+func WithCheckInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.checkInterval = d
+	}
+}
+
+// WithPauser skips the scheduled backups while the agent is paused. Exports requested
+// explicitly are still carried out.
+func WithPauser(p Pauser) Option {
+	return func(o *options) {
+		o.pauser = p
+	}
+}
+
+// WithSchedule holds back the scheduled backups outside of the maintenance window, and while the
+// host is on battery or on a metered connection, as for the tasks of the distros. Exports requested
+// explicitly are still carried out.
+func WithSchedule(s Schedule) Option {
+	return func(o *options) {
+		o.schedule = s
+	}
+}
+
+// New creates a backup service. Backups with no explicit destination are stored in defaultDir.
+func New(ctx context.Context, conf Config, db *database.DistroDB, defaultDir string, args ...Option) *Service {
+	opts := options{
+		checkInterval: defaultCheckInterval,
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return &Service{
+		conf:          conf,
+		db:            db,
+		defaultDir:    defaultDir,
+		checkInterval: opts.checkInterval,
+		pauser:        opts.pauser,
+		schedule:      opts.schedule,
+		exporting:     make(map[string]struct{}),
+
+		ctx:     ctx,
+		stop:    func() {},
+		running: make(chan struct{}),
+	}
+}
+
+// Start starts running the periodic backups in the background.
+func (s *Service) Start() {
+	s.ctx, s.stop = context.WithCancel(s.ctx)
+	go s.run()
+}
+
+// Stop cancels any scheduled backup in progress and waits for the service to stop.
+func (s *Service) Stop() {
+	s.stop()
+	<-s.running
+}
+
+// run is the blocking backup scheduler.
+func (s *Service) run() {
+	defer close(s.running)
+
+	log.Info(s.ctx, "Backup service: started")
+	defer log.Info(s.ctx, "Backup service: stopped")
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		s.backupIfDue(s.ctx)
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// backupIfDue exports every managed distro whose latest backup is older than the schedule
+// interval, and removes the backups in excess.
+func (s *Service) backupIfDue(ctx context.Context) {
+	if s.pauser != nil && s.pauser.Paused() {
+		return
+	}
+
+	if s.schedule != nil {
+		if open, _ := s.schedule.TaskWindow(time.Now()); !open {
+			log.Debug(ctx, "Backup service: holding back scheduled backups until the schedule allows them")
+			return
+		}
+	}
+
+	schedule, err := s.conf.BackupSchedule()
+	if err != nil {
+		log.Warningf(ctx, "Backup service: %v", err)
+		return
+	}
+
+	if !schedule.Enabled() {
+		return
+	}
+
+	dir := schedule.Destination
+	if dir == "" {
+		dir = s.defaultDir
+	}
+
+	for _, d := range s.db.GetAll() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		backups, err := listBackups(dir, d.Name())
+		if err != nil {
+			log.Warningf(ctx, "Backup service: distro %q: %v", d.Name(), err)
+			continue
+		}
+
+		if len(backups) > 0 && time.Since(backups[len(backups)-1].time) < schedule.Interval {
+			continue
+		}
+
+		log.Infof(ctx, "Backup service: distro %q: starting scheduled backup", d.Name())
+
+		path, err := s.Export(ctx, d.Name(), dir, nil)
+		if err != nil {
+			log.Warningf(ctx, "Backup service: %v", err)
+			continue
+		}
+
+		log.Infof(ctx, "Backup service: distro %q: exported to %s", d.Name(), path)
+
+		if err := prune(dir, d.Name(), schedule.Keep); err != nil {
+			log.Warningf(ctx, "Backup service: distro %q: %v", d.Name(), err)
+		}
+	}
+}
+
+// Export exports the distro to a tarball in the destination directory, and returns the path to
+// it. If the destination is empty, the default backup directory is used. The progress function,
+// if not nil, is called periodically until the export finishes.
+func (s *Service) Export(ctx context.Context, distroName, destination string, progress ProgressFunc) (path string, err error) {
+	defer decorate.OnError(&err, "could not export distro %q", distroName)
+
+	d, ok := s.db.Get(distroName)
+	if !ok {
+		return "", errors.New("distro is not managed by the agent")
+	}
+
+	if !d.IsValid() {
+		return "", errors.New("distro is no longer registered")
+	}
+
+	if !s.startExporting(distroName) {
+		return "", errors.New("an export of this distro is already in progress")
+	}
+	defer s.stopExporting(distroName)
+
+	if destination == "" {
+		destination = s.defaultDir
+	}
+
+	if err := os.MkdirAll(destination, 0700); err != nil {
+		return "", fmt.Errorf("could not create destination directory: %v", err)
+	}
+
+	path = filepath.Join(destination, backupName(distroName, time.Now()))
+	partial := path + partialSuffix
+
+	if progress == nil {
+		progress = func(int64) {}
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- wslExport(ctx, distroName, partial) }()
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				_ = os.Remove(partial)
+				return "", err
+			}
+
+			if err := os.Rename(partial, path); err != nil {
+				_ = os.Remove(partial)
+				return "", fmt.Errorf("could not finalize export: %v", err)
+			}
+
+			progress(fileSize(path))
+			return path, nil
+		case <-ticker.C:
+			progress(fileSize(partial))
+		}
+	}
+}
+
+// startExporting marks the distro as being exported. It returns false if it already was.
+func (s *Service) startExporting(distroName string) bool {
+	s.exportingMu.Lock()
+	defer s.exportingMu.Unlock()
+
+	if _, ok := s.exporting[distroName]; ok {
+		return false
+	}
+
+	s.exporting[distroName] = struct{}{}
+	return true
+}
+
+// stopExporting undoes startExporting.
+func (s *Service) stopExporting(distroName string) {
+	s.exportingMu.Lock()
+	defer s.exportingMu.Unlock()
+
+	delete(s.exporting, distroName)
+}
+
+// fileSize returns the size of a file, or zero if it cannot be read.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// backup is an existing backup of a distro.
+type backup struct {
+	path string
+	time time.Time
+}
+
+// backupName returns the name of the tarball for a backup of the distro taken at time t.
+func backupName(distroName string, t time.Time) string {
+	return fmt.Sprintf("%s-%s.tar", distroName, t.UTC().Format(timestampFormat))
+}
+
+// listBackups returns the backups of a distro in a directory, from oldest to newest.
+func listBackups(dir, distroName string) ([]backup, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not list backups: %v", err)
+	}
+
+	var backups []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		// The timestamp is parsed from the end of the name because distro names may contain dashes.
+		base, ok := strings.CutSuffix(e.Name(), ".tar")
+		if !ok {
+			continue
+		}
+
+		i := strings.LastIndex(base, "-")
+		if i == -1 || base[:i] != distroName {
+			continue
+		}
+
+		t, err := time.Parse(timestampFormat, base[i+1:])
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, backup{path: filepath.Join(dir, e.Name()), time: t})
+	}
+
+	slices.SortFunc(backups, func(a, b backup) int {
+		return a.time.Compare(b.time)
+	})
+
+	return backups, nil
+}
+
+// prune removes the oldest backups of a distro so that only the newest ones are kept.
+// Keeping zero backups means keeping them all.
+func prune(dir, distroName string, keep int) error {
+	if keep == 0 {
+		return nil
+	}
+
+	backups, err := listBackups(dir, distroName)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for i := 0; i < len(backups)-keep; i++ {
+		if err := os.Remove(backups[i].path); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("could not remove old backup: %v", err))
+		}
+	}
+
+	return errs
+}
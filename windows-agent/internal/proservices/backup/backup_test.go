@@ -0,0 +1,230 @@
+package backup_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/backup"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+)
+
+func TestExport(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		defaultDestination bool
+		notInDatabase      bool
+		breakDestination   bool
+
+		wantErr bool
+	}{
+		"Success":                              {},
+		"Success with the default destination": {defaultDestination: true},
+
+		"Error when the distro is not in the database":        {notInDatabase: true, wantErr: true},
+		"Error when the destination directory cannot be made": {breakDestination: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: database New should not return an error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				_, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+			}
+
+			defaultDir := filepath.Join(t.TempDir(), "default")
+			destination := filepath.Join(t.TempDir(), "backups")
+			if tc.defaultDestination {
+				destination = ""
+			}
+
+			if tc.breakDestination {
+				err := os.WriteFile(destination, []byte("I am not a directory"), 0600)
+				require.NoError(t, err, "Setup: could not write file in place of the destination")
+			}
+
+			s := backup.New(ctx, &mockConfig{}, db, defaultDir)
+
+			var progress []int64
+			path, err := s.Export(ctx, distroName, destination, func(written int64) {
+				progress = append(progress, written)
+			})
+			if tc.wantErr {
+				require.Error(t, err, "Export should return an error")
+				return
+			}
+			require.NoError(t, err, "Export should return no error")
+
+			wantDir := destination
+			if tc.defaultDestination {
+				wantDir = defaultDir
+			}
+			require.Equal(t, wantDir, filepath.Dir(path), "Backup should be exported to the destination directory")
+			require.True(t, strings.HasPrefix(filepath.Base(path), distroName+"-"), "Backup name should start with the distro name")
+
+			info, err := os.Stat(path)
+			require.NoError(t, err, "Backup should exist")
+			require.NotEmpty(t, progress, "Progress should have been reported")
+			require.Equal(t, info.Size(), progress[len(progress)-1], "Last progress report should be the size of the backup")
+
+			entries, err := os.ReadDir(wantDir)
+			require.NoError(t, err, "Setup: could not read the destination directory")
+			require.Len(t, entries, 1, "Only the backup should be left in the destination directory")
+		})
+	}
+}
+
+func TestScheduledBackups(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		schedule       config.BackupSchedule
+		recentBackup   bool
+		configErr      bool
+		paused         bool
+		windowClosed   bool
+		oldBackupCount int
+
+		wantBackup    bool
+		wantOldBackup int
+	}{
+		"Success":                            {schedule: config.BackupSchedule{Interval: time.Hour}, wantBackup: true},
+		"Success keeping all old backups":    {schedule: config.BackupSchedule{Interval: time.Hour}, oldBackupCount: 3, wantBackup: true, wantOldBackup: 3},
+		"Success removing the oldest backup": {schedule: config.BackupSchedule{Interval: time.Hour, Keep: 3}, oldBackupCount: 3, wantBackup: true, wantOldBackup: 2},
+
+		"No backup when the schedule is disabled":       {},
+		"No backup when the latest backup is recent":    {schedule: config.BackupSchedule{Interval: time.Hour}, recentBackup: true},
+		"No backup when the schedule cannot be read":    {schedule: config.BackupSchedule{Interval: time.Hour}, configErr: true},
+		"No backup while the agent is paused":           {schedule: config.BackupSchedule{Interval: time.Hour}, paused: true},
+		"No backup while the schedule holds back tasks": {schedule: config.BackupSchedule{Interval: time.Hour}, windowClosed: true},
+		"No backup when the interval is not set":        {schedule: config.BackupSchedule{Keep: 3}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: database New should not return an error")
+			defer db.Close(ctx)
+
+			_, err = db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+			require.NoError(t, err, "Setup: could not add distro to the database")
+
+			dir := t.TempDir()
+			tc.schedule.Destination = dir
+
+			var oldBackups []string
+			for i := range tc.oldBackupCount {
+				oldBackups = append(oldBackups, filepath.Join(dir, distroName+"-2024010"+string(rune('1'+i))+"T000000Z.tar"))
+			}
+			if tc.recentBackup {
+				oldBackups = append(oldBackups, filepath.Join(dir, distroName+"-"+time.Now().UTC().Format("20060102T150405Z")+".tar"))
+			}
+
+			// Backups of other distros and unrelated files must be left alone.
+			unrelated := []string{
+				filepath.Join(dir, distroName+"-suffix-20240101T000000Z.tar"),
+				filepath.Join(dir, "notes.txt"),
+			}
+
+			for _, path := range append(slices.Clone(oldBackups), unrelated...) {
+				err := os.WriteFile(path, []byte("Old backup"), 0600)
+				require.NoError(t, err, "Setup: could not write old backup")
+			}
+
+			conf := &mockConfig{schedule: tc.schedule, scheduleErr: tc.configErr}
+
+			// The default directory is the same so that any backup is noticed, even when the schedule is disabled.
+			s := backup.New(ctx, conf, db, dir, backup.WithCheckInterval(100*time.Millisecond), backup.WithPauser(pause.New(tc.paused)), backup.WithSchedule(mockSchedule{closed: tc.windowClosed}))
+			s.Start()
+			defer s.Stop()
+
+			countBackups := func() int {
+				entries, err := os.ReadDir(dir)
+				require.NoError(t, err, "Could not read the backup directory")
+				return len(entries)
+			}
+
+			want := len(oldBackups) + len(unrelated)
+			if !tc.wantBackup {
+				time.Sleep(time.Second)
+				require.Equal(t, want, countBackups(), "No backup should have been made")
+				return
+			}
+
+			want += 1 + tc.wantOldBackup - tc.oldBackupCount
+			require.Eventually(t, func() bool {
+				return countBackups() == want
+			}, 5*time.Second, 100*time.Millisecond, "A backup should have been made and the backups in excess should have been removed")
+
+			for _, path := range unrelated {
+				require.FileExists(t, path, "Unrelated files should not be removed")
+			}
+
+			// Only the oldest backups are removed.
+			for i, path := range oldBackups {
+				if i < tc.oldBackupCount-tc.wantOldBackup {
+					require.NoFileExists(t, path, "Old backup should have been removed")
+					continue
+				}
+				require.FileExists(t, path, "Recent backup should not have been removed")
+			}
+		})
+	}
+}
+
+type mockSchedule struct {
+	closed bool
+}
+
+func (m mockSchedule) TaskWindow(time.Time) (bool, time.Duration) {
+	return !m.closed, time.Hour
+}
+
+type mockConfig struct {
+	schedule    config.BackupSchedule
+	scheduleErr bool
+}
+
+func (m mockConfig) BackupSchedule() (config.BackupSchedule, error) {
+	if m.scheduleErr {
+		return config.BackupSchedule{}, errors.New("mock error")
+	}
+	return m.schedule, nil
+}
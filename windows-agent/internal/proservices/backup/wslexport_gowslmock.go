@@ -0,0 +1,36 @@
+//go:build gowslmock
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	wsl "github.com/ubuntu/gowsl"
+)
+
+// wslExport mocks running 'wsl --export' by writing a fake tarball.
+func wslExport(ctx context.Context, distroName, path string) error {
+	registered, err := wsl.NewDistro(ctx, distroName).IsRegistered()
+	if err != nil {
+		return fmt.Errorf("could not run 'wsl --export': %v", err)
+	}
+
+	if !registered {
+		return errors.New("could not run 'wsl --export': exit status 1. Output: There is no distribution with the supplied name")
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := os.WriteFile(path, []byte("Mock tarball of "+distroName), 0600); err != nil {
+		return fmt.Errorf("could not run 'wsl --export': %v", err)
+	}
+
+	return nil
+}
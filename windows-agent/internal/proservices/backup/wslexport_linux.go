@@ -0,0 +1,12 @@
+//go:build !gowslmock
+
+package backup
+
+import (
+	"context"
+)
+
+// wslExport is a stub function that panics. Use the gowslmock in order to use it in Linux.
+func wslExport(ctx context.Context, distroName, path string) error {
+	panic("wslExport: this function can only be run on Windows")
+}
@@ -0,0 +1,35 @@
+//go:build !gowslmock
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// wslExport runs 'wsl --export' to export the distro to a tarball.
+func wslExport(ctx context.Context, distroName, path string) error {
+	// https://learn.microsoft.com/en-us/windows/win32/procthread/process-creation-flags
+	//
+	// CREATE_NO_WINDOW:
+	// The process is a console application that is being run without
+	// a console window. Therefore, the console handle for the
+	// application is not set.
+	const createNoWindow = 0x08000000
+
+	cmd := exec.CommandContext(ctx, "wsl.exe", "--export", distroName, path)
+	cmd.Env = append(os.Environ(), "WSL_UTF8=1")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not run 'wsl --export': %v. Output: %s", err, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,323 @@
+// Package compaction implements a service that compacts the virtual disks of the managed distros,
+// either on request or periodically according to the compaction schedule.
+package compaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/vhdx"
+	"github.com/ubuntu/decorate"
+	wsl "github.com/ubuntu/gowsl"
+)
+
+// defaultCheckInterval is how often the compaction schedule is checked.
+const defaultCheckInterval = time.Hour
+
+// Config is an interface to easily allow dependency injection. Should be a config.Config
+// in production.
+type Config interface {
+	CompactSchedule() (config.CompactSchedule, error)
+}
+
+// Pauser tells the service whether the agent is paused by the user.
+type Pauser interface {
+	Paused() bool
+}
+
+// Service compacts the virtual disks of the distros, and manages their sparse mode.
+type Service struct {
+	ctx  context.Context
+	stop func()
+
+	running chan struct{}
+
+	conf Config
+	db   *database.DistroDB
+
+	checkInterval time.Duration
+	diskPath      func(*distro.Distro) (string, error)
+
+	// pauser skips the scheduled compactions while the agent is paused. It may be nil.
+	pauser Pauser
+
+	// busy contains the names of the distros whose disk is being changed.
+	busy map[string]struct{}
+
+	// lastCompacted contains when the disk of each distro was last compacted since the agent started.
+	lastCompacted map[string]time.Time
+	started       time.Time
+
+	mu sync.Mutex
+}
+
+type options struct {
+	checkInterval time.Duration
+	pauser        Pauser
+	diskPath      func(*distro.Distro) (string, error)
+}
+
+// Option is an optional argument for the compaction service.
+type Option = func(*options)
+
+// WithCheckInterval overrides how often the compaction schedule is checked.
+func WithCheckInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.checkInterval = d
+	}
+}
+
+// WithPauser skips the scheduled compactions while the agent is paused. Compactions requested
+// explicitly are still carried out.
+func WithPauser(p Pauser) Option {
+	return func(o *options) {
+		o.pauser = p
+	}
+}
+
+// New creates a compaction service.
+func New(ctx context.Context, conf Config, db *database.DistroDB, args ...Option) *Service {
+	opts := options{
+		checkInterval: defaultCheckInterval,
+		diskPath:      vhdx.Path,
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return &Service{
+		conf:          conf,
+		db:            db,
+		checkInterval: opts.checkInterval,
+		diskPath:      opts.diskPath,
+		pauser:        opts.pauser,
+		busy:          make(map[string]struct{}),
+		lastCompacted: make(map[string]time.Time),
+		started:       time.Now(),
+
+		ctx:     ctx,
+		stop:    func() {},
+		running: make(chan struct{}),
+	}
+}
+
+// Start starts running the periodic compactions in the background.
+func (s *Service) Start() {
+	s.ctx, s.stop = context.WithCancel(s.ctx)
+	go s.run()
+}
+
+// Stop cancels any scheduled compaction in progress and waits for the service to stop.
+func (s *Service) Stop() {
+	s.stop()
+	<-s.running
+}
+
+// run is the blocking compaction scheduler.
+func (s *Service) run() {
+	defer close(s.running)
+
+	log.Info(s.ctx, "Compaction service: started")
+	defer log.Info(s.ctx, "Compaction service: stopped")
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	events, err := s.db.Watch(s.ctx)
+	if err != nil {
+		// Without the events, the distros that are removed are only forgotten when the service restarts.
+		log.Warningf(s.ctx, "Compaction service: %v", err)
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				// The database is closed: nothing is removed from it anymore.
+				events = nil
+			} else if e.Kind == database.DistroRemoved {
+				s.forget(e.Name)
+			}
+			continue
+		case <-ticker.C:
+		}
+
+		s.compactIfDue(s.ctx)
+	}
+}
+
+// forget drops what the service knows about a distro that was removed, so that a distro registered
+// again with the same name starts afresh.
+func (s *Service) forget(distroName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.lastCompacted, distroName)
+}
+
+// compactIfDue compacts the disk of every stopped distro that was not compacted within the
+// schedule interval. Distros that are running are left for a later check.
+func (s *Service) compactIfDue(ctx context.Context) {
+	if s.pauser != nil && s.pauser.Paused() {
+		return
+	}
+
+	schedule, err := s.conf.CompactSchedule()
+	if err != nil {
+		log.Warningf(ctx, "Compaction service: %v", err)
+		return
+	}
+
+	if schedule.IsZero() {
+		return
+	}
+
+	for _, d := range s.db.GetAll() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(s.lastCompaction(d.Name())) < schedule.Interval {
+			continue
+		}
+
+		if state, err := d.State(); err != nil || state != wsl.Stopped {
+			log.Debugf(ctx, "Compaction service: distro %q: skipping scheduled compaction: distro is not stopped", d.Name())
+			continue
+		}
+
+		log.Infof(ctx, "Compaction service: distro %q: starting scheduled compaction", d.Name())
+
+		before, after, err := s.Compact(ctx, d.Name())
+		if err != nil {
+			log.Warningf(ctx, "Compaction service: %v", err)
+			continue
+		}
+
+		log.Infof(ctx, "Compaction service: distro %q: disk compacted from %d to %d bytes", d.Name(), before, after)
+	}
+}
+
+// Compact compacts the virtual disk of the distro, and returns its size before and after. The
+// distro must be stopped: the disk cannot be compacted while it is attached, and the distro
+// cannot start while it is being compacted.
+func (s *Service) Compact(ctx context.Context, distroName string) (before, after uint64, err error) {
+	defer decorate.OnError(&err, "could not compact the disk of distro %q", distroName)
+
+	d, err := s.prepare(distroName)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer s.done(distroName)
+
+	path, err := s.diskPath(d)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	before, err = fileSize(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := optimizeVHD(ctx, path); err != nil {
+		return 0, 0, err
+	}
+
+	after, err = fileSize(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	s.mu.Lock()
+	s.lastCompacted[distroName] = time.Now()
+	s.mu.Unlock()
+
+	return before, after, nil
+}
+
+// SetSparse sets whether WSL gives the unused space of the virtual disk of the distro back to
+// Windows on its own. The distro must be stopped.
+func (s *Service) SetSparse(ctx context.Context, distroName string, sparse bool) (err error) {
+	defer decorate.OnError(&err, "could not set the sparse mode of the disk of distro %q", distroName)
+
+	if _, err := s.prepare(distroName); err != nil {
+		return err
+	}
+	defer s.done(distroName)
+
+	return setSparse(ctx, distroName, sparse)
+}
+
+// prepare checks that the disk of the distro can be changed, and marks it as busy. Call done
+// once the disk is no longer being changed.
+func (s *Service) prepare(distroName string) (*distro.Distro, error) {
+	d, ok := s.db.Get(distroName)
+	if !ok {
+		return nil, errors.New("distro is not managed by the agent")
+	}
+
+	if !d.IsValid() {
+		return nil, errors.New("distro is no longer registered")
+	}
+
+	state, err := d.State()
+	if err != nil {
+		return nil, err
+	}
+
+	if state != wsl.Stopped {
+		return nil, fmt.Errorf("distro must be stopped, but it is %s", state)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.busy[distroName]; ok {
+		return nil, errors.New("the disk of this distro is already being changed")
+	}
+
+	s.busy[distroName] = struct{}{}
+	return d, nil
+}
+
+// done undoes prepare.
+func (s *Service) done(distroName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.busy, distroName)
+}
+
+// lastCompaction returns when the disk of the distro was last compacted. Distros that were not
+// compacted since the agent started are considered compacted at startup, so that restarting the
+// agent does not compact every disk.
+func (s *Service) lastCompaction(distroName string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.lastCompacted[distroName]; ok {
+		return t
+	}
+	return s.started
+}
+
+// fileSize returns the size of a file.
+func fileSize(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("could not get the size of the virtual disk: %v", err)
+	}
+	return uint64(info.Size()), nil
+}
@@ -0,0 +1,232 @@
+package compaction_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/compaction"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+)
+
+const diskSize = 1 << 20
+
+func TestCompact(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		notInDatabase bool
+		running       bool
+		noDisk        bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when the distro is not in the database": {notInDatabase: true, wantErr: true},
+		"Error when the distro is running":             {running: true, wantErr: true},
+		"Error when the disk cannot be found":          {noDisk: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, distroName, disk := setupDistro(t, ctx, !tc.notInDatabase, tc.running)
+			if tc.noDisk {
+				require.NoError(t, os.Remove(disk), "Setup: could not remove the mock disk")
+			}
+
+			s := compaction.New(ctx, &mockConfig{}, db, compaction.WithDiskPath(mockDiskPath(disk)))
+
+			before, after, err := s.Compact(ctx, distroName)
+			if tc.wantErr {
+				require.Error(t, err, "Compact should return an error")
+				if !tc.noDisk {
+					requireDiskSize(t, disk, diskSize, "The disk should not have been compacted")
+				}
+				return
+			}
+			require.NoError(t, err, "Compact should return no error")
+
+			require.Equal(t, uint64(diskSize), before, "Unexpected disk size before compaction")
+			require.Equal(t, uint64(diskSize/2), after, "Unexpected disk size after compaction")
+			requireDiskSize(t, disk, diskSize/2, "The disk should have been compacted")
+		})
+	}
+}
+
+func TestSetSparse(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		notInDatabase bool
+		running       bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when the distro is not in the database": {notInDatabase: true, wantErr: true},
+		"Error when the distro is running":             {running: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, distroName, disk := setupDistro(t, ctx, !tc.notInDatabase, tc.running)
+
+			s := compaction.New(ctx, &mockConfig{}, db, compaction.WithDiskPath(mockDiskPath(disk)))
+
+			err := s.SetSparse(ctx, distroName, true)
+			if tc.wantErr {
+				require.Error(t, err, "SetSparse should return an error")
+				return
+			}
+			require.NoError(t, err, "SetSparse should return no error")
+		})
+	}
+}
+
+func TestScheduledCompaction(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		schedule      config.CompactSchedule
+		running       bool
+		configErr     bool
+		paused        bool
+		compactedOnce bool
+
+		wantCompaction bool
+	}{
+		"Success": {schedule: config.CompactSchedule{Interval: time.Millisecond}, wantCompaction: true},
+
+		"No compaction when the schedule is disabled":      {},
+		"No compaction when the distro is running":         {schedule: config.CompactSchedule{Interval: time.Millisecond}, running: true},
+		"No compaction when the schedule cannot be read":   {schedule: config.CompactSchedule{Interval: time.Millisecond}, configErr: true},
+		"No compaction while the agent is paused":          {schedule: config.CompactSchedule{Interval: time.Millisecond}, paused: true},
+		"No compaction when the disk was compacted lately": {schedule: config.CompactSchedule{Interval: time.Hour}, compactedOnce: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, distroName, disk := setupDistro(t, ctx, true, tc.running)
+
+			conf := &mockConfig{schedule: tc.schedule, scheduleErr: tc.configErr}
+			s := compaction.New(ctx, conf, db,
+				compaction.WithDiskPath(mockDiskPath(disk)),
+				compaction.WithCheckInterval(100*time.Millisecond),
+				compaction.WithPauser(pause.New(tc.paused)))
+
+			want := int64(diskSize)
+			if tc.compactedOnce {
+				_, _, err := s.Compact(ctx, distroName)
+				require.NoError(t, err, "Setup: could not compact the disk")
+				want /= 2
+			}
+
+			s.Start()
+			defer s.Stop()
+
+			if !tc.wantCompaction {
+				time.Sleep(time.Second)
+				requireDiskSize(t, disk, want, "The disk should not have been compacted")
+				return
+			}
+
+			require.Eventually(t, func() bool {
+				info, err := os.Stat(disk)
+				return err == nil && info.Size() == want/2
+			}, 5*time.Second, 100*time.Millisecond, "The disk should have been compacted")
+		})
+	}
+}
+
+// setupDistro registers a distro with a mock disk, and adds it to a new database if requested.
+// The distro is kept awake if it must be running.
+//
+//nolint:revive // We've decided testing.T always preceedes the context.
+func setupDistro(t *testing.T, ctx context.Context, inDatabase, running bool) (db *database.DistroDB, distroName, disk string) {
+	t.Helper()
+
+	distroName, _ = wsltestutils.RegisterDistro(t, ctx, false)
+
+	db, err := database.New(ctx, t.TempDir(), nil)
+	require.NoError(t, err, "Setup: database New should not return an error")
+	t.Cleanup(func() { db.Close(ctx) })
+
+	if inDatabase {
+		d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+		require.NoError(t, err, "Setup: could not add distro to the database")
+
+		if running {
+			require.NoError(t, d.LockAwake(), "Setup: could not keep the distro awake")
+			//nolint:errcheck // Nothing we can do about it
+			t.Cleanup(func() { d.ReleaseAwake() })
+		}
+	}
+
+	disk = filepath.Join(t.TempDir(), "ext4.vhdx")
+	require.NoError(t, os.WriteFile(disk, make([]byte, diskSize), 0600), "Setup: could not write the mock disk")
+
+	return db, distroName, disk
+}
+
+// mockDiskPath returns a function that finds the mock disk for any distro.
+func mockDiskPath(disk string) func(*distro.Distro) (string, error) {
+	return func(*distro.Distro) (string, error) {
+		return disk, nil
+	}
+}
+
+func requireDiskSize(t *testing.T, disk string, want int64, msg string) {
+	t.Helper()
+
+	info, err := os.Stat(disk)
+	require.NoError(t, err, "Could not stat the mock disk")
+	require.Equal(t, want, info.Size(), msg)
+}
+
+type mockConfig struct {
+	schedule    config.CompactSchedule
+	scheduleErr bool
+}
+
+func (m mockConfig) CompactSchedule() (config.CompactSchedule, error) {
+	if m.scheduleErr {
+		return config.CompactSchedule{}, errors.New("mock error")
+	}
+	return m.schedule, nil
+}
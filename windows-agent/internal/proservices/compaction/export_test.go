@@ -0,0 +1,11 @@
+package compaction
+
+import "github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+
+// WithDiskPath overrides how the virtual disk of a distro is found, as there are no virtual disks
+// in the tests.
+func WithDiskPath(f func(*distro.Distro) (string, error)) Option {
+	return func(o *options) {
+		o.diskPath = f
+	}
+}
@@ -0,0 +1,46 @@
+//go:build gowslmock
+
+package compaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	wsl "github.com/ubuntu/gowsl"
+)
+
+// optimizeVHD mocks running Optimize-VHD by halving the size of the disk.
+func optimizeVHD(ctx context.Context, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("could not run Optimize-VHD: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := os.Truncate(path, info.Size()/2); err != nil {
+		return fmt.Errorf("could not run Optimize-VHD: %v", err)
+	}
+
+	return nil
+}
+
+// setSparse mocks running 'wsl --manage --set-sparse'.
+func setSparse(ctx context.Context, distroName string, sparse bool) error {
+	registered, err := wsl.NewDistro(ctx, distroName).IsRegistered()
+	if err != nil {
+		return fmt.Errorf("could not run 'wsl --manage --set-sparse': %v", err)
+	}
+
+	if !registered {
+		return errors.New("could not run 'wsl --manage --set-sparse': exit status 1. Output: There is no distribution with the supplied name")
+	}
+
+	return nil
+}
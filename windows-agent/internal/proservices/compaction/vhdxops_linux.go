@@ -0,0 +1,17 @@
+//go:build !gowslmock
+
+package compaction
+
+import (
+	"context"
+)
+
+// optimizeVHD is a stub function that panics. Use the gowslmock in order to use it in Linux.
+func optimizeVHD(ctx context.Context, path string) error {
+	panic("optimizeVHD: this function can only be run on Windows")
+}
+
+// setSparse is a stub function that panics. Use the gowslmock in order to use it in Linux.
+func setSparse(ctx context.Context, distroName string, sparse bool) error {
+	panic("setSparse: this function can only be run on Windows")
+}
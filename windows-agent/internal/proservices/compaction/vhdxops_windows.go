@@ -0,0 +1,56 @@
+//go:build !gowslmock
+
+package compaction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// https://learn.microsoft.com/en-us/windows/win32/procthread/process-creation-flags
+//
+// CREATE_NO_WINDOW:
+// The process is a console application that is being run without
+// a console window. Therefore, the console handle for the
+// application is not set.
+const createNoWindow = 0x08000000
+
+// optimizeVHD runs Optimize-VHD to compact the virtual disk. It requires the Hyper-V module
+// for PowerShell and administrator rights.
+func optimizeVHD(ctx context.Context, path string) error {
+	// Single quotes are escaped by doubling them in PowerShell literal strings.
+	script := fmt.Sprintf("Optimize-VHD -Path '%s' -Mode Full", strings.ReplaceAll(path, "'", "''"))
+
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not run Optimize-VHD: %v. Output: %s", err, out)
+	}
+
+	return nil
+}
+
+// setSparse runs 'wsl --manage --set-sparse' to set the sparse mode of the virtual disk.
+func setSparse(ctx context.Context, distroName string, sparse bool) error {
+	cmd := exec.CommandContext(ctx, "wsl.exe", "--manage", distroName, "--set-sparse", strconv.FormatBool(sparse))
+	cmd.Env = append(os.Environ(), "WSL_UTF8=1")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not run 'wsl --manage --set-sparse': %v. Output: %s", err, out)
+	}
+
+	return nil
+}
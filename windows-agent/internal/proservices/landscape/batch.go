@@ -0,0 +1,87 @@
+package landscape
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+)
+
+// infoBatcher gathers the requests to send updated info to Landscape made within a window, so that a
+// burst of changes, such as WSL stopping every distro at once, is sent in a single message.
+type infoBatcher struct {
+	ctx    context.Context
+	clock  clock.Clock
+	window time.Duration
+
+	// pending is closed once the pending batch is sent or flushed. It is nil if there is no batch.
+	pending chan struct{}
+	mu      sync.Mutex
+}
+
+func newInfoBatcher(ctx context.Context, c clock.Clock, window time.Duration) *infoBatcher {
+	return &infoBatcher{
+		ctx:    ctx,
+		clock:  c,
+		window: window,
+	}
+}
+
+// add adds a request to the pending batch, starting a new batch if there is none. The batch is sent
+// with send once the window elapses, unless it is flushed first. The window is not extended by the
+// requests that join the batch, so that a steady stream of changes still gets sent.
+func (b *infoBatcher) add(send func(context.Context)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending != nil {
+		return
+	}
+
+	batch := make(chan struct{})
+	b.pending = batch
+
+	timer := b.clock.NewTimer(b.window)
+	go func() {
+		defer timer.Stop()
+
+		select {
+		case <-b.ctx.Done():
+		case <-batch:
+		case <-timer.C():
+		}
+
+		// The batch is not sent if it was flushed, nor once the service stopped.
+		if b.end(batch) && b.ctx.Err() == nil {
+			send(b.ctx)
+		}
+	}()
+}
+
+// flush drops the pending batch, because the info is about to be sent right away.
+func (b *infoBatcher) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending == nil {
+		return
+	}
+
+	close(b.pending)
+	b.pending = nil
+}
+
+// end ends the batch. It returns false if the batch had already been flushed.
+func (b *infoBatcher) end(batch chan struct{}) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending != batch {
+		return false
+	}
+
+	close(batch)
+	b.pending = nil
+	return true
+}
@@ -0,0 +1,76 @@
+package landscape_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/landscape"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoBatcher(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		requests int
+		flush    bool
+		stop     bool
+
+		wantSent int32
+	}{
+		"Success sending a single request":            {requests: 1, wantSent: 1},
+		"Success sending a burst of requests at once": {requests: 10, wantSent: 1},
+
+		"Flushed batch is not sent":                {requests: 3, flush: true},
+		"Batch is not sent once the service stops": {requests: 3, stop: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			const window = time.Minute
+			clk := clock.NewMock(time.Now())
+			b := landscape.NewInfoBatcher(ctx, clk, window)
+
+			var sent atomic.Int32
+			send := func(context.Context) { sent.Add(1) }
+
+			for range tc.requests {
+				b.Add(send)
+			}
+
+			if tc.flush {
+				b.Flush()
+			}
+			if tc.stop {
+				cancel()
+			}
+
+			clk.Advance(window - time.Second)
+			require.Never(t, func() bool { return sent.Load() != 0 }, 100*time.Millisecond, 10*time.Millisecond,
+				"The batch should not be sent before the window elapses")
+
+			clk.Advance(time.Second)
+			if tc.wantSent == 0 {
+				require.Never(t, func() bool { return sent.Load() != 0 }, 100*time.Millisecond, 10*time.Millisecond,
+					"The batch should not be sent")
+				return
+			}
+			require.Eventually(t, func() bool { return sent.Load() == tc.wantSent }, time.Second, 10*time.Millisecond,
+				"The batch should be sent once the window elapses")
+
+			// Requests made once the batch is sent start a new one.
+			b.Add(send)
+			clk.Advance(window)
+			require.Eventually(t, func() bool { return sent.Load() == tc.wantSent+1 }, time.Second, 10*time.Millisecond,
+				"A new batch should be sent once the previous one was sent")
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package landscape
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	landscapeapi "github.com/canonical/landscape-hostagent-api"
+)
+
+// ErrCommandCancelled is the cause of the cancellation of a command that was cancelled on request.
+var ErrCommandCancelled = errors.New("command cancelled")
+
+// commandTracker keeps track of the commands in flight, so that they can be cancelled.
+type commandTracker struct {
+	mu       sync.Mutex
+	inflight map[string]context.CancelCauseFunc
+}
+
+func newCommandTracker() *commandTracker {
+	return &commandTracker{inflight: make(map[string]context.CancelCauseFunc)}
+}
+
+// track returns a context for the command that is done when the timeout expires or when the
+// command is cancelled. Call done once the command completed.
+func (t *commandTracker) track(ctx context.Context, target string, timeout time.Duration) (cmdCtx context.Context, done func()) {
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	t.mu.Lock()
+	t.inflight[target] = cancel
+	t.mu.Unlock()
+
+	return ctx, func() {
+		// Commands are executed one at a time, so the entry cannot belong to another command.
+		t.mu.Lock()
+		delete(t.inflight, target)
+		t.mu.Unlock()
+
+		cancel(nil)
+		cancelTimeout()
+	}
+}
+
+// cancel cancels the command in flight for the target. It returns false if there is none.
+func (t *commandTracker) cancel(target string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cancel, ok := t.inflight[target]
+	if !ok {
+		return false
+	}
+
+	cancel(ErrCommandCancelled)
+	delete(t.inflight, target)
+	return true
+}
+
+// commandTarget returns the distro a command acts on. Commands that act on the host have no target.
+func commandTarget(command *landscapeapi.Command) string {
+	switch cmd := command.GetCmd().(type) {
+	case *landscapeapi.Command_Start_:
+		return cmd.Start.GetId()
+	case *landscapeapi.Command_Stop_:
+		return cmd.Stop.GetId()
+	case *landscapeapi.Command_Install_:
+		return cmd.Install.GetId()
+	case *landscapeapi.Command_Uninstall_:
+		return cmd.Uninstall.GetId()
+	case *landscapeapi.Command_SetDefault_:
+		return cmd.SetDefault.GetId()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,56 @@
+package landscape_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/landscape"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandTracker(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		timeout      time.Duration
+		cancelTarget string
+		done         bool
+
+		wantCancelled bool
+		wantCause     error
+	}{
+		"Command is cancelled on request":     {timeout: time.Hour, cancelTarget: "Ubuntu", wantCancelled: true, wantCause: landscape.ErrCommandCancelled},
+		"Command is cancelled once timed out": {timeout: time.Millisecond, wantCause: context.DeadlineExceeded},
+
+		"Command is not cancelled for another distro":  {timeout: time.Hour, cancelTarget: "Debian"},
+		"Command is not cancelled once it is complete": {timeout: time.Hour, cancelTarget: "Ubuntu", done: true, wantCause: context.Canceled},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tracker := landscape.NewCommandTracker()
+
+			ctx, done := tracker.Track(context.Background(), "Ubuntu", tc.timeout)
+			defer done()
+
+			if tc.done {
+				done()
+			}
+
+			if tc.cancelTarget != "" {
+				require.Equal(t, tc.wantCancelled, tracker.Cancel(tc.cancelTarget), "Cancel should only return true if it cancelled a command")
+			}
+
+			if tc.wantCause == nil {
+				require.NoError(t, ctx.Err(), "The context of the command should not be done")
+				return
+			}
+
+			require.Eventually(t, func() bool { return ctx.Err() != nil }, time.Second, time.Millisecond, "The context of the command should be done")
+			require.ErrorIs(t, context.Cause(ctx), tc.wantCause, "Unexpected cause for the end of the command")
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	landscapeapi "github.com/canonical/landscape-hostagent-api"
 	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
 	"github.com/ubuntu/decorate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
@@ -21,6 +22,7 @@ import (
 // Landscape, and redirects the received commands to the executor.
 type connection struct {
 	settings connectionSettings
+	timeouts timeouts.Policy
 
 	ctx    context.Context
 	cancel func()
@@ -37,12 +39,16 @@ type connection struct {
 type connectionSettings struct {
 	url             string
 	certificatePath string
+	caBundlePath    string
+	pinnedKeys      string
 }
 
 func newConnectionSettings(c landscapeHostConf) connectionSettings {
 	return connectionSettings{
 		url:             c.hostagentURL,
 		certificatePath: c.sslPublicKey,
+		caBundlePath:    c.sslCABundle,
+		pinnedKeys:      c.sslPinnedKeys,
 	}
 }
 
@@ -61,17 +67,18 @@ func newConnection(ctx context.Context, d serviceData) (conn *connection, err er
 
 	conn = &connection{
 		settings: newConnectionSettings(conf),
+		timeouts: d.timeoutPolicy(),
 		ctx:      ctx,
 		cancel:   cancel,
 	}
 
-	creds, err := transportCredentials(conn.settings.certificatePath)
+	creds, err := transportCredentials(conn.settings)
 	if err != nil {
 		return nil, err
 	}
 
 	// A context to control only the Dial (only needed for this function)
-	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	dialCtx, cancel := context.WithTimeout(ctx, conn.timeouts.LandscapeDial)
 	defer cancel()
 
 	log.Info(ctx, "Landscape: connecting")
@@ -146,16 +153,16 @@ func handshake(ctx context.Context, d serviceData, conn *connection) (err error)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
-	ctx, cancel := context.WithTimeout(conn.ctx, time.Minute)
+	ctx, cancel := context.WithTimeout(conn.ctx, conn.timeouts.LandscapeHandshake)
 	defer cancel()
 
 	for {
 		select {
 		case <-ctx.Done():
 			conn.disconnect()
-			// Avoid races where the UID arrives just after cancelling the context
-			err := conf.SetLandscapeAgentUID("")
-			return fmt.Errorf("Landscape server did not respond with a client UID: %v", err)
+			// The UID is only written by the executor: a UID arriving just after the timeout
+			// was still assigned by the server, and the next handshake picks it up.
+			return errors.New("Landscape server did not respond with a client UID")
 		case <-ticker.C:
 		}
 
@@ -219,6 +226,14 @@ func (conn *connection) receiveCommands(e executor) error {
 			return fmt.Errorf("could not receive commands: %v", err)
 		}
 
+		// Assigning the host is part of the handshake, so it is never held back.
+		if _, ok := command.GetCmd().(*landscapeapi.Command_AssignHost_); !ok {
+			if err := e.waitResumed(); err != nil {
+				log.Infof(conn.ctx, "Landscape: dropping command %s received while paused: %v", commandString(command), err)
+				return nil
+			}
+		}
+
 		// Removing the cancel context so that the command is executed even if the connection is lost.
 		ctx := context.WithoutCancel(conn.ctx)
 
@@ -226,6 +241,11 @@ func (conn *connection) receiveCommands(e executor) error {
 			log.Errorf(conn.ctx, "Landscape: %v", err)
 		}
 
+		// The server only assigns a new UID during the handshake.
+		if revokesUID(command) {
+			return errors.New("the server revoked the UID of the host: connecting again to be assigned a new one")
+		}
+
 		// Ping back the server with the updated info
 		info, err := newHostAgentInfo(conn.ctx, e.serviceData)
 		if err != nil {
@@ -238,6 +258,12 @@ func (conn *connection) receiveCommands(e executor) error {
 	}
 }
 
+// revokesUID returns true if the command revokes the UID assigned to the host.
+func revokesUID(command *landscapeapi.Command) bool {
+	cmd, ok := command.GetCmd().(*landscapeapi.Command_AssignHost_)
+	return ok && cmd.AssignHost.GetUid() == ""
+}
+
 // sendInfo takes a HostagentInfo message and forwards it to the Landscape server.
 func (conn *connection) sendInfo(info *landscapeapi.HostAgentInfo) (err error) {
 	defer decorate.OnError(&err, "could not send updated info to Landscape")
@@ -250,8 +276,20 @@ func (conn *connection) sendInfo(info *landscapeapi.HostAgentInfo) (err error) {
 	logInfo := *info
 	logInfo.Token = common.Obfuscate(logInfo.GetToken())
 	log.Debugf(conn.ctx, "Landscape: sending info: %+v", logInfo) //nolint:govet
-	if err := conn.grpcClient.Send(info); err != nil {
-		return fmt.Errorf("could not send message: %v", err)
+
+	sent := make(chan error, 1)
+	go func() { sent <- conn.grpcClient.Send(info) }()
+
+	select {
+	case err := <-sent:
+		if err != nil {
+			return fmt.Errorf("could not send message: %v", err)
+		}
+	case <-time.After(conn.timeouts.LandscapeSend):
+		// A stuck stream cannot be trusted anymore. Cancelling the connection ends the stream, which
+		// disconnects so that the service reconnects.
+		conn.cancel()
+		return errors.New("could not send message: timed out")
 	}
 
 	return nil
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 )
 
 // Controller is a light-weight structure used to send certain instructions to
@@ -14,12 +16,14 @@ type Controller struct {
 }
 
 // SendUpdatedInfo sends a message to the Landscape server with updated
-// info about the machine and the distros.
+// info about the machine and the distros. The queued updates are sent along.
 func (c Controller) SendUpdatedInfo(ctx context.Context) error {
 	if c.isDisabled() {
 		return nil
 	}
 
+	c.infoBatch().flush()
+
 	if connected := c.tryReconnect(ctx); !connected {
 		return errors.New("could not connect to Landscape")
 	}
@@ -32,6 +36,24 @@ func (c Controller) SendUpdatedInfo(ctx context.Context) error {
 	return c.sendInfo(info)
 }
 
+// QueueUpdatedInfo sends a message to the Landscape server with updated info about the machine and
+// the distros once the updates queued within a short window are gathered, so that a burst of changes
+// is sent in a single message. Use SendUpdatedInfo for the changes that must be sent right away.
+func (c Controller) QueueUpdatedInfo(ctx context.Context) {
+	if c.isDisabled() {
+		return
+	}
+
+	c.infoBatch().add(func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, c.timeoutPolicy().LandscapeSend)
+		defer cancel()
+
+		if err := c.SendUpdatedInfo(ctx); err != nil {
+			log.Debugf(ctx, "Landscape: could not send the queued updated info: %v", err)
+		}
+	})
+}
+
 // Reconnect makes Landscape drop its current connection and start a new one.
 // Blocks until the new connection is available (or failed).
 func (c Controller) Reconnect(ctx context.Context) (succcess bool) {
@@ -42,6 +64,17 @@ func (c Controller) Reconnect(ctx context.Context) (succcess bool) {
 	return c.forceReconnect(ctx)
 }
 
+// CancelCommand aborts the command being executed on the distro, such as a stuck install. An empty
+// distro name cancels the command acting on the host. It returns false if there is no such command.
+func (c Controller) CancelCommand(ctx context.Context, distroName string) bool {
+	if !c.commands().cancel(distroName) {
+		return false
+	}
+
+	log.Infof(ctx, "Landscape: cancelled the command in flight for %q", distroName)
+	return true
+}
+
 // tryReconnect sends a "please, connect" signal to the Landscape client and blocks until
 // this connection is established, or until the context is canceled. Returns true if the
 // connection was successfully established.
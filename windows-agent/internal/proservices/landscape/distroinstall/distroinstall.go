@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
 	"github.com/ubuntu/decorate"
 	"github.com/ubuntu/gowsl"
 )
@@ -34,7 +35,9 @@ func InstallFromExecutable(ctx context.Context, d gowsl.Distro) error {
 func CreateUser(ctx context.Context, d gowsl.Distro, userName string, userFullName string) (uid uint32, err error) {
 	defer decorate.OnError(&err, "could not create user %q", userName)
 
-	if r, err := d.IsRegistered(); err != nil {
+	if r, err := wslcall.Query(ctx, fmt.Sprintf("checking if distro %q is registered", d.Name()), func(context.Context) (bool, error) {
+		return d.IsRegistered()
+	}); err != nil {
 		return 0, err
 	} else if !r {
 		return 0, errors.New("not registered")
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os/user"
+	"time"
 
 	landscapeapi "github.com/canonical/landscape-hostagent-api"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/approvals"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/landscape/distroinstall"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
 	"github.com/ubuntu/gowsl"
 )
 
@@ -19,6 +22,19 @@ type executor struct {
 
 func (e executor) exec(ctx context.Context, command *landscapeapi.Command) (err error) {
 	log.Infof(ctx, "Landcape: received command %s", commandString(command))
+
+	// The wait for the approval of the user does not count towards the timeout of the command.
+	if category, ok := commandCategory(command); ok {
+		if err := e.waitApproved(ctx, category, fmt.Sprintf("Landscape command %s", commandString(command))); err != nil {
+			return fmt.Errorf("could not execute command %s: %v", commandString(command), err)
+		}
+	}
+
+	// The context reaches the tasks and the WSL calls of the command, so that they are aborted
+	// when it times out or is cancelled.
+	ctx, done := e.commands().track(ctx, commandTarget(command), e.commandTimeout(command))
+	defer done()
+
 	err = func() error {
 		switch cmd := command.GetCmd().(type) {
 		case *landscapeapi.Command_AssignHost_:
@@ -40,7 +56,9 @@ func (e executor) exec(ctx context.Context, command *landscapeapi.Command) (err
 		}
 	}()
 
-	if err != nil {
+	if cause := context.Cause(ctx); err != nil && cause != nil {
+		return fmt.Errorf("could not execute command %s: %v: %v", commandString(command), cause, err)
+	} else if err != nil {
 		return fmt.Errorf("could not execute command %s: %v", commandString(command), err)
 	}
 	log.Infof(ctx, "Landcape: completed command %s", commandString(command))
@@ -48,6 +66,33 @@ func (e executor) exec(ctx context.Context, command *landscapeapi.Command) (err
 	return nil
 }
 
+// commandTimeout returns how long the command may take before it is cancelled.
+func (e executor) commandTimeout(command *landscapeapi.Command) time.Duration {
+	if _, ok := command.GetCmd().(*landscapeapi.Command_Install_); ok {
+		return e.timeoutPolicy().LandscapeInstall
+	}
+	return e.timeoutPolicy().LandscapeCommand
+}
+
+// commandCategory returns the category of operations the command belongs to, so that the user can be
+// asked to approve it. Assigning the host is part of the handshake, so it never needs an approval.
+func commandCategory(command *landscapeapi.Command) (approvals.Category, bool) {
+	switch command.GetCmd().(type) {
+	case *landscapeapi.Command_Start_, *landscapeapi.Command_Stop_:
+		return approvals.LandscapePower, true
+	case *landscapeapi.Command_Install_:
+		return approvals.LandscapeInstall, true
+	case *landscapeapi.Command_Uninstall_:
+		return approvals.LandscapeUninstall, true
+	case *landscapeapi.Command_SetDefault_:
+		return approvals.LandscapeSetDefault, true
+	case *landscapeapi.Command_ShutdownHost_:
+		return approvals.LandscapeShutdownHost, true
+	default:
+		return "", false
+	}
+}
+
 func commandString(command *landscapeapi.Command) string {
 	switch cmd := command.GetCmd().(type) {
 	case *landscapeapi.Command_AssignHost_:
@@ -69,18 +114,25 @@ func commandString(command *landscapeapi.Command) string {
 	}
 }
 
+// assignHost stores the UID the server assigned to the host, and registers the distros again with it
+// when it changes. An empty UID revokes the current one: the host is assigned a new UID on the next
+// handshake, as the connection is dropped right after the command.
 func (e executor) assignHost(ctx context.Context, cmd *landscapeapi.Command_AssignHost) error {
 	conf := e.config()
 
-	if uid, err := conf.LandscapeAgentUID(); err != nil {
-		log.Warningf(ctx, "Possibly overriding current landscape client UID: could not read current Landscape UID: %v", err)
-	} else if uid != "" {
-		log.Warning(ctx, "Overriding current landscape client UID")
-	}
-
 	uid := cmd.GetUid()
 	if uid == "" {
-		return errors.New("UID is empty")
+		log.Warning(ctx, "Landscape: the server revoked the UID of the host")
+		return conf.SetLandscapeAgentUID("")
+	}
+
+	if current, err := conf.LandscapeAgentUID(); err != nil {
+		log.Warningf(ctx, "Possibly overriding current landscape client UID: could not read current Landscape UID: %v", err)
+	} else if current == uid {
+		// The distros are already registered with this UID.
+		return nil
+	} else if current != "" {
+		log.Warning(ctx, "Overriding current landscape client UID")
 	}
 
 	if err := conf.SetLandscapeAgentUID(uid); err != nil {
@@ -92,7 +144,7 @@ func (e executor) assignHost(ctx context.Context, cmd *landscapeapi.Command_Assi
 		return err
 	}
 
-	distributeConfig(ctx, e.database(), landscapeConf, uid)
+	distributeConfig(ctx, e, landscapeConf, uid)
 
 	return nil
 }
@@ -104,6 +156,10 @@ func (e executor) start(ctx context.Context, cmd *landscapeapi.Command_Start) (e
 		return fmt.Errorf("distro %q not in database", cmd.GetId())
 	}
 
+	if err := checkManaged(e, d.Name()); err != nil {
+		return err
+	}
+
 	return d.LockAwake()
 }
 
@@ -114,6 +170,10 @@ func (e executor) stop(ctx context.Context, cmd *landscapeapi.Command_Stop) (err
 		return fmt.Errorf("distro %q not in database", cmd.GetId())
 	}
 
+	if err := checkManaged(e, d.Name()); err != nil {
+		return err
+	}
+
 	return d.ReleaseAwake()
 }
 
@@ -123,7 +183,9 @@ func (e executor) install(ctx context.Context, cmd *landscapeapi.Command_Install
 	}
 
 	distro := gowsl.NewDistro(ctx, cmd.GetId())
-	if registered, err := distro.IsRegistered(); err != nil {
+	if registered, err := wslcall.Query(ctx, fmt.Sprintf("checking if distro %q is registered", distro.Name()), func(context.Context) (bool, error) {
+		return distro.IsRegistered()
+	}); err != nil {
 		return err
 	} else if registered {
 		return errors.New("already installed")
@@ -138,7 +200,7 @@ func (e executor) install(ctx context.Context, cmd *landscapeapi.Command_Install
 			return
 		}
 		// Avoid error states by cleaning up on error
-		err := distro.Uninstall(ctx)
+		err := wslcall.Command(ctx, fmt.Sprintf("uninstalling distro %q", distro.Name()), distro.Uninstall)
 		if err != nil {
 			log.Warningf(ctx, "Landscape Install: failed to clean up %q after failed Install: %v", distro.Name(), err)
 		}
@@ -177,15 +239,25 @@ func (e executor) uninstall(ctx context.Context, cmd *landscapeapi.Command_Unins
 		return fmt.Errorf("distro %q not in database", cmd.GetId())
 	}
 
+	if err := checkManaged(e, d.Name()); err != nil {
+		return err
+	}
+
 	return d.Uninstall(ctx)
 }
 
 func (e executor) setDefault(ctx context.Context, cmd *landscapeapi.Command_SetDefault) error {
+	if err := checkManaged(e, cmd.GetId()); err != nil {
+		return err
+	}
+
 	d := gowsl.NewDistro(ctx, cmd.GetId())
-	return d.SetAsDefault()
+	return wslcall.Command(ctx, fmt.Sprintf("setting distro %q as default", d.Name()), func(context.Context) error {
+		return d.SetAsDefault()
+	})
 }
 
 //nolint:unparam // cmd is not used, but kep here for consistency with other commands.
 func (e executor) shutdownHost(ctx context.Context, cmd *landscapeapi.Command_ShutdownHost) error {
-	return gowsl.Shutdown(ctx)
+	return wslcall.Command(ctx, "shutting down WSL", gowsl.Shutdown)
 }
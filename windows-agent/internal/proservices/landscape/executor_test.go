@@ -4,14 +4,18 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
 	landscapeapi "github.com/canonical/landscape-hostagent-api"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/mocks/landscape/landscapemockservice"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/approvals"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/landscape"
 	"github.com/stretchr/testify/require"
 	wsl "github.com/ubuntu/gowsl"
@@ -27,10 +31,16 @@ func TestAssignHost(t *testing.T) {
 	t.Parallel()
 
 	testCases := map[string]struct {
+		sameUID bool
+		noUID   bool
 		confErr bool
-		wantErr bool
+
+		wantNoConfigure bool
+		wantErr         bool
 	}{
-		"Success ": {},
+		"Success":                                 {},
+		"Success when the UID does not change":    {sameUID: true, wantNoConfigure: true},
+		"Success when the server revokes the UID": {noUID: true},
 
 		"Error when config returns an error": {confErr: true, wantErr: true},
 	}
@@ -39,37 +49,84 @@ func TestAssignHost(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			testReceiveCommand(t, distroSettings{},
+			var oldUID string
+			testReceiveCommand(t, distroSettings{install: true},
 				// Test setup
 				func(testBed *commandTestBed) *landscapeapi.Command {
 					if tc.confErr {
 						testBed.conf.setLandscapeUIDErr = true
 					}
 
+					testBed.conf.mu.Lock()
+					oldUID = testBed.conf.landscapeAgentUID
+					testBed.conf.mu.Unlock()
+
+					uid := "HostUID123"
+					if tc.sameUID {
+						uid = oldUID
+					} else if tc.noUID {
+						uid = ""
+					}
+
 					return &landscapeapi.Command{
-						Cmd: &landscapeapi.Command_AssignHost_{AssignHost: &landscapeapi.Command_AssignHost{Uid: "HostUID123"}},
+						Cmd: &landscapeapi.Command_AssignHost_{AssignHost: &landscapeapi.Command_AssignHost{Uid: uid}},
 					}
 				},
 				// Test assertions
 				func(testBed *commandTestBed) {
-					const maxTimeout = time.Second
+					const maxTimeout = 10 * time.Second
 					if tc.wantErr {
-						time.Sleep(maxTimeout)
+						time.Sleep(time.Second)
 						require.NotEqual(t, "HostUID123", testBed.conf.landscapeAgentUID, "Landscape UID should not have been assigned")
 						return
 					}
 
+					d, ok := testBed.db.Get(testBed.distro.Name())
+					require.True(t, ok, "Setup: distro should be in the database")
+
+					if tc.wantNoConfigure {
+						time.Sleep(time.Second)
+						require.Equal(t, oldUID, testBed.conf.landscapeAgentUID, "Landscape UID should not have changed")
+						require.False(t, landscapeConfigureQueued(t, d), "The distros should not have been registered again with the same UID")
+						return
+					}
+
 					require.Eventually(t, func() bool {
 						testBed.conf.mu.Lock()
 						defer testBed.conf.mu.Unlock()
 
+						if tc.noUID {
+							return testBed.conf.landscapeAgentUID != "" && testBed.conf.landscapeAgentUID != oldUID
+						}
 						return testBed.conf.landscapeAgentUID == "HostUID123"
-					}, maxTimeout, 100*time.Millisecond, "Landscape client should have overridden the initial UID sent by the server")
+					}, maxTimeout, 100*time.Millisecond, "Landscape client should have been assigned the new UID")
+
+					if tc.noUID {
+						require.Eventually(t, func() bool {
+							return !testBed.serverService.IsConnected(oldUID)
+						}, maxTimeout, 100*time.Millisecond, "The connection with the revoked UID should have been dropped")
+					}
+
+					require.Eventually(t, func() bool {
+						return landscapeConfigureQueued(t, d)
+					}, maxTimeout, 100*time.Millisecond, "The distros should have been registered again with the new UID")
 				})
 		})
 	}
 }
 
+// landscapeConfigureQueued returns true if the distro has a LandscapeConfigure task queued.
+func landscapeConfigureQueued(t *testing.T, d *distro.Distro) bool {
+	t.Helper()
+
+	queued, err := d.QueuedTasks()
+	require.NoError(t, err, "QueuedTasks should return no error")
+
+	return slices.ContainsFunc(queued, func(q worker.QueuedTask) bool {
+		return q.Task == "LandscapeConfigure"
+	})
+}
+
 func TestReceiveCommandStartStop(t *testing.T) {
 	// The Start and Stop tests are almost identical so they are merged into a single table.
 
@@ -85,6 +142,7 @@ func TestReceiveCommandStartStop(t *testing.T) {
 	testCases := map[string]struct {
 		dontRegisterDistro bool
 		wslErr             bool
+		managedElsewhere   bool
 		cmd                command
 
 		wantState wsl.State
@@ -98,6 +156,8 @@ func TestReceiveCommandStartStop(t *testing.T) {
 
 		"Error with Start when WSL returns error": {cmd: start, wslErr: true, wantErr: true},
 		"Error with Stop when WSL returns error":  {cmd: stop, wslErr: true, wantErr: true},
+
+		"Error with Start when the distro is managed by another endpoint": {cmd: start, managedElsewhere: true, wantState: wsl.Running, wantErr: true},
 	}
 
 	for name, tc := range testCases {
@@ -111,6 +171,13 @@ func TestReceiveCommandStartStop(t *testing.T) {
 						testBed.wslMock.WslLaunchInteractiveError = true
 					}
 
+					if tc.managedElsewhere {
+						testBed.conf.mu.Lock()
+						testBed.conf.endpoints = map[string]*mockEndpoint{"staging": {}}
+						testBed.conf.distroEndpoints = map[string]string{testBed.distro.Name(): "staging"}
+						testBed.conf.mu.Unlock()
+					}
+
 					if tc.cmd == start {
 						return &landscapeapi.Command{
 							Cmd: &landscapeapi.Command_Start_{Start: &landscapeapi.Command_Start{Id: testBed.distro.Name()}},
@@ -139,6 +206,59 @@ func TestReceiveCommandStartStop(t *testing.T) {
 	}
 }
 
+func TestCommandsWhilePaused(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		assignHost bool
+	}{
+		"Commands are held back until the agent is resumed": {},
+		"Assigning the host is not held back":               {assignHost: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testReceiveCommand(t, distroSettings{install: true},
+				// Test setup
+				func(testBed *commandTestBed) *landscapeapi.Command {
+					testBed.pauser.Set(true)
+
+					if tc.assignHost {
+						return &landscapeapi.Command{
+							Cmd: &landscapeapi.Command_AssignHost_{AssignHost: &landscapeapi.Command_AssignHost{Uid: "HostUID123"}},
+						}
+					}
+
+					return &landscapeapi.Command{
+						Cmd: &landscapeapi.Command_Start_{Start: &landscapeapi.Command_Start{Id: testBed.distro.Name()}},
+					}
+				},
+				// Test assertions
+				func(testBed *commandTestBed) {
+					if tc.assignHost {
+						require.Eventually(t, func() bool {
+							testBed.conf.mu.Lock()
+							defer testBed.conf.mu.Unlock()
+
+							return testBed.conf.landscapeAgentUID == "HostUID123"
+						}, 5*time.Second, 100*time.Millisecond, "Landscape client should have been assigned the UID while paused")
+						return
+					}
+
+					ok, _ := checkEventuallyState(t, testBed.distro, wsl.Running, 3*time.Second, 500*time.Millisecond)
+					require.False(t, ok, "Distro should not start while the agent is paused")
+
+					testBed.pauser.Set(false)
+
+					ok, state := checkEventuallyState(t, testBed.distro, wsl.Running, 10*time.Second, time.Second)
+					require.True(t, ok, "Distro should start once the agent is resumed. Last state: %q", state)
+				})
+		})
+	}
+}
+
 func TestInstall(t *testing.T) {
 	t.Parallel()
 
@@ -389,6 +509,68 @@ func TestSetShutdownHost(t *testing.T) {
 	}
 }
 
+func TestCommandsNeedingApproval(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		notRequired bool
+		deny        bool
+
+		wantRunning bool
+	}{
+		"Commands run once approved":                {wantRunning: true},
+		"Commands run when no approval is required": {notRequired: true, wantRunning: true},
+
+		"Commands do not run when denied": {deny: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testReceiveCommand(t, distroSettings{install: true},
+				// Test setup
+				func(testBed *commandTestBed) *landscapeapi.Command {
+					if !tc.notRequired {
+						testBed.conf.mu.Lock()
+						testBed.conf.requiredApprovals = []string{string(approvals.LandscapePower)}
+						testBed.conf.mu.Unlock()
+					}
+
+					return &landscapeapi.Command{
+						Cmd: &landscapeapi.Command_Start_{Start: &landscapeapi.Command_Start{Id: testBed.distro.Name()}},
+					}
+				},
+				// Test assertions
+				func(testBed *commandTestBed) {
+					if !tc.notRequired {
+						require.Eventually(t, func() bool { return len(testBed.approvals.Pending()) == 1 },
+							5*time.Second, 100*time.Millisecond, "Command should wait for the approval of the user")
+
+						ok, _ := checkEventuallyState(t, testBed.distro, wsl.Running, 3*time.Second, 500*time.Millisecond)
+						require.False(t, ok, "Distro should not start before the command is approved")
+
+						request := testBed.approvals.Pending()[0]
+						require.Equal(t, approvals.LandscapePower, request.Category, "Command should wait for the approval of its category")
+
+						if tc.deny {
+							require.NoError(t, testBed.approvals.Deny(request.ID), "Setup: Deny should return no errors")
+						} else {
+							require.NoError(t, testBed.approvals.Approve(request.ID), "Setup: Approve should return no errors")
+						}
+					}
+
+					ok, state := checkEventuallyState(t, testBed.distro, wsl.Running, 10*time.Second, time.Second)
+					if !tc.wantRunning {
+						require.False(t, ok, "Distro should not start once the command is denied")
+						return
+					}
+					require.True(t, ok, "Distro should start once the command is approved. Last state: %q", state)
+				})
+		})
+	}
+}
+
 // commandTestBed is a bag of data with all the necessary utils to run executor tests.
 type commandTestBed struct {
 	ctx context.Context
@@ -400,6 +582,9 @@ type commandTestBed struct {
 	serverService *landscapemockservice.Service
 	clientService *landscape.Service
 
+	pauser    *pause.Switch
+	approvals *approvals.Approvals
+
 	wslMock *wslmock.Backend
 }
 
@@ -465,7 +650,10 @@ func testReceiveCommand(t *testing.T, distrosettings distroSettings, testSetup f
 	tb.db = db
 
 	// Set up Landscape client
-	clientService, err := landscape.New(ctx, tb.conf, tb.db, landscape.WithHostname("HOSTNAME"))
+	tb.pauser = pause.New(false)
+	tb.approvals = approvals.New(tb.conf)
+
+	clientService, err := landscape.New(ctx, tb.conf, tb.db, landscape.WithHostname("HOSTNAME"), landscape.WithPauser(tb.pauser), landscape.WithApprover(tb.approvals))
 	require.NoError(t, err, "Landscape NewClient should not return an error")
 
 	err = clientService.Connect()
@@ -1,5 +1,12 @@
 package landscape
 
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+)
+
 // WithHostname allows tests to override the hostname.
 func WithHostname(hostname string) Option {
 	return func(o *options) {
@@ -11,3 +18,39 @@ func WithHostname(hostname string) Option {
 func (s *Service) Connected() bool {
 	return s.connected()
 }
+
+// CommandTracker keeps track of the commands in flight.
+type CommandTracker = commandTracker
+
+// NewCommandTracker creates an empty tracker of the commands in flight.
+func NewCommandTracker() *CommandTracker {
+	return newCommandTracker()
+}
+
+// Track starts tracking a command acting on the target.
+func (t *CommandTracker) Track(ctx context.Context, target string, timeout time.Duration) (context.Context, func()) {
+	return t.track(ctx, target, timeout)
+}
+
+// Cancel cancels the command in flight for the target.
+func (t *CommandTracker) Cancel(target string) bool {
+	return t.cancel(target)
+}
+
+// InfoBatcher gathers the requests to send updated info made within a window.
+type InfoBatcher = infoBatcher
+
+// NewInfoBatcher creates a batcher with no pending batch.
+func NewInfoBatcher(ctx context.Context, c clock.Clock, window time.Duration) *InfoBatcher {
+	return newInfoBatcher(ctx, c, window)
+}
+
+// Add adds a request to the pending batch.
+func (b *InfoBatcher) Add(send func(context.Context)) {
+	b.add(send)
+}
+
+// Flush drops the pending batch.
+func (b *InfoBatcher) Flush() {
+	b.flush()
+}
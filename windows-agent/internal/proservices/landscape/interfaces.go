@@ -1,8 +1,12 @@
 package landscape
 
 import (
+	"context"
+
 	landscapeapi "github.com/canonical/landscape-hostagent-api"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/approvals"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
 )
 
 // These interfaces exist to limit the coupling between components,
@@ -14,6 +18,12 @@ type serviceData interface {
 	config() Config
 	database() *database.DistroDB
 	hostname() string
+	endpoint() string
+	waitResumed() error
+	waitApproved(ctx context.Context, category approvals.Category, description string) error
+	timeoutPolicy() timeouts.Policy
+	commands() *commandTracker
+	infoBatch() *infoBatcher
 }
 
 // serviceConn is an internal interface to manage the connection to the Landscape service.
@@ -3,13 +3,18 @@ package landscape_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -20,6 +25,7 @@ import (
 	"github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/mocks/landscape/landscapemockservice"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
@@ -80,6 +86,7 @@ func TestConnect(t *testing.T) {
 		uid          string
 
 		wantErr           bool
+		wantErrMsg        string
 		wantNotConnected  bool
 		wantDistroSkipped bool
 		wantSingleMessage bool
@@ -87,6 +94,8 @@ func TestConnect(t *testing.T) {
 		"Success":                         {},
 		"Success in non-first contact":    {uid: "123", wantSingleMessage: true},
 		"Success with an SSL certificate": {requireCertificate: true},
+		"Success with a CA bundle":        {requireCertificate: true},
+		"Success with a pinned key":       {requireCertificate: true},
 
 		// These tests are for the error cases when the error is logged but not returned
 		"Silent error when the config is empty":                   {wantNotConnected: true},
@@ -103,6 +112,10 @@ func TestConnect(t *testing.T) {
 		"Error when the config cannot be parsed":               {wantErr: true},
 		"Error when the SSL certificate cannot be read":        {wantErr: true},
 		"Error when the SSL certificate is not valid":          {wantErr: true},
+		"Error when the CA bundle cannot be read":              {requireCertificate: true, wantErr: true},
+		"Error when a pinned key is not valid":                 {requireCertificate: true, wantErr: true},
+		"Error when the server certificate is not trusted":     {requireCertificate: true, wantErr: true, wantErrMsg: "add its CA to ssl_ca_bundle"},
+		"Error when the server key is not pinned":              {requireCertificate: true, wantErr: true, wantErrMsg: "does not match any of the keys in ssl_pinned_keys"},
 	}
 
 	for name, tc := range testCases {
@@ -177,6 +190,7 @@ func TestConnect(t *testing.T) {
 			err = service.Connect()
 			if tc.wantErr {
 				require.Error(t, err, "Connect should return an error")
+				require.ErrorContains(t, err, tc.wantErrMsg, "Connect should explain what went wrong")
 				require.False(t, service.Connected(), "Connected should have returned false after failing to connect")
 				return
 			}
@@ -252,11 +266,13 @@ func TestSendUpdatedInfo(t *testing.T) {
 		disconnectBeforeSend bool
 		distroIsRunning      bool
 		distroIsUnregistered bool
+		hostnameChange       bool
 
 		wantErr           bool
 		wantDistroSkipped bool
 	}{
 		"Success with a stopped distro":                       {},
+		"Success when the hostname changes":                   {hostnameChange: true},
 		"Success with a running distro":                       {distroIsRunning: true},
 		"Success when the distro State cannot be retreived":   {stateErr: true, wantDistroSkipped: true},
 		"Success when the default distro cannot be retreived": {breakWSLRegistry: true, dontRegisterDistro: true, wantDistroSkipped: true},
@@ -404,12 +420,17 @@ func TestSendUpdatedInfo(t *testing.T) {
 				require.NoError(t, err, "Setup: could not terminate distro")
 			}
 
-			err = ctl.SendUpdatedInfo(ctx)
-			if tc.wantErr {
-				require.Error(t, err, "SendUpdatedInfo should have returned an error")
-				return
+			if tc.hostnameChange {
+				wantHostname = "NEW_HOSTNAME"
+				service.NotifyHostnameChange(ctx, wantHostname)
+			} else {
+				err = ctl.SendUpdatedInfo(ctx)
+				if tc.wantErr {
+					require.Error(t, err, "SendUpdatedInfo should have returned an error")
+					return
+				}
+				require.NoError(t, err, "SendUpdatedInfo should send no error")
 			}
-			require.NoError(t, err, "SendUpdatedInfo should send no error")
 
 			// Asserting on the second SendUpdatedInfo
 			require.Eventually(t, func() bool {
@@ -503,7 +524,11 @@ func TestAutoReconnection(t *testing.T) {
 
 			const hostname = "HOSTNAME"
 
-			service, err := landscape.New(ctx, conf, db, landscape.WithHostname(hostname))
+			// The backoff between reconnection attempts only elapses when the clock is advanced.
+			clk := clock.NewMock(time.Now())
+			advance := func() { clk.Advance(10 * time.Minute) }
+
+			service, err := landscape.New(ctx, conf, db, landscape.WithHostname(hostname), landscape.WithClock(clk))
 			require.NoError(t, err, "Landscape NewClient should not return an error")
 			defer service.Stop(ctx)
 
@@ -537,11 +562,15 @@ func TestAutoReconnection(t *testing.T) {
 			go server.Serve(lis)
 			defer server.Stop()
 
-			select {
-			case <-time.After(20 * time.Second):
-				require.Fail(t, "SendUpdatedInfo should have returned")
-			case err = <-ch:
-			}
+			require.Eventually(t, func() bool {
+				advance()
+				select {
+				case err = <-ch:
+					return true
+				default:
+					return false
+				}
+			}, 20*time.Second, 100*time.Millisecond, "SendUpdatedInfo should have returned")
 
 			if tc.wantErr {
 				require.Error(t, err, "SendUpdatedInfo should have returned an error")
@@ -564,6 +593,7 @@ func TestAutoReconnection(t *testing.T) {
 
 			// Detecting reconnection
 			require.Eventually(t, func() bool {
+				advance()
 				return mockService.IsConnected(uid)
 			}, 10*time.Second, 100*time.Millisecond, "Client should have reconnected after the stream is dropped")
 
@@ -582,12 +612,9 @@ func TestAutoReconnection(t *testing.T) {
 			defer server.Stop()
 
 			require.Eventually(t, func() bool {
+				advance()
 				return service.Connected()
-			}, 60*time.Second, 500*time.Millisecond, "Client should have reconnected after restarting the server")
-			// Seems a bit long of a timeout, but the wait-time doubles after each failed attempt,
-			// so after 6 failed attempts, we're waiting for 64 seconds.
-			//
-			// In local testing I have not seen it go beyond 16 seconds, but I'd rather avoid flaky tests.
+			}, 10*time.Second, 100*time.Millisecond, "Client should have reconnected after restarting the server")
 		})
 	}
 }
@@ -785,10 +812,11 @@ func executeLandscapeConfigTemplate(t *testing.T, in string, certPath string, ur
 	tmpl := template.Must(template.New(t.Name()).Parse(in))
 
 	data := struct {
-		CertPath, HostURL string
+		CertPath, HostURL, KeyPin string
 	}{
 		CertPath: certPath,
 		HostURL:  url.String(),
+		KeyPin:   keyPin(t, filepath.Join(certPath, "cert.pem")),
 	}
 
 	out := bytes.Buffer{}
@@ -798,6 +826,26 @@ func executeLandscapeConfigTemplate(t *testing.T, in string, certPath string, ur
 	return out.String()
 }
 
+// keyPin returns the base64-encoded SHA-256 hash of the public key of the certificate, or an
+// empty string if there is no certificate.
+func keyPin(t *testing.T, certPath string) string {
+	t.Helper()
+
+	out, err := os.ReadFile(certPath)
+	if err != nil {
+		return ""
+	}
+
+	block, _ := pem.Decode(out)
+	require.NotNil(t, block, "Setup: could not decode certificate")
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err, "Setup: could not parse certificate")
+
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
 //nolint:revive // Context goes after testing.T
 func setUpLandscapeMock(t *testing.T, ctx context.Context, addr string, certPath string) (lis net.Listener, server *grpc.Server, service *landscapemockservice.Service) {
 	t.Helper()
@@ -847,6 +895,13 @@ type mockConfig struct {
 	landscapeClientConfig string
 	landscapeAgentUID     string
 
+	// endpoints contains the config of the Landscape endpoints other than the main one.
+	endpoints map[string]*mockEndpoint
+	// distroEndpoints maps distros to the endpoint they are assigned to.
+	distroEndpoints map[string]string
+	// requiredApprovals are the categories of commands that must be approved before they run.
+	requiredApprovals []string
+
 	proTokenErr        bool
 	landscapeConfigErr bool
 	landscapeUIDErr    bool
@@ -865,6 +920,10 @@ func (m *mockConfig) LandscapeClientConfig() (string, config.Source, error) {
 	return m.landscapeClientConfig, config.SourceUser, nil
 }
 
+func (m *mockConfig) LandscapeDistroOverride(distroName string) (config.LandscapeOverride, error) {
+	return config.LandscapeOverride{}, nil
+}
+
 func (m *mockConfig) ProvisioningTasks(ctx context.Context, distroName string) ([]task.Task, error) {
 	return nil, nil
 }
@@ -903,3 +962,80 @@ func (m *mockConfig) SetLandscapeAgentUID(uid string) error {
 	m.landscapeAgentUID = uid
 	return nil
 }
+
+func (m *mockConfig) ApprovalRequired(category string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Contains(m.requiredApprovals, category), nil
+}
+
+type mockEndpoint struct {
+	config string
+	uid    string
+}
+
+func (m *mockConfig) LandscapeDistroEndpoint(distroName string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	endpoint := m.distroEndpoints[distroName]
+	if _, ok := m.endpoints[endpoint]; !ok {
+		return "", nil
+	}
+	return endpoint, nil
+}
+
+func (m *mockConfig) LandscapeEndpoints() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var names []string
+	for name := range m.endpoints {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func (m *mockConfig) LandscapeEndpointConfig(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.endpoints[name]
+	if !ok {
+		return "", fmt.Errorf("no such endpoint %q", name)
+	}
+	return e.config, nil
+}
+
+func (m *mockConfig) LandscapeEndpointUID(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.endpoints[name]
+	if !ok {
+		return "", fmt.Errorf("no such endpoint %q", name)
+	}
+	return e.uid, nil
+}
+
+func (m *mockConfig) SetLandscapeEndpointUID(name, uid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.endpoints[name]
+	if !ok {
+		return fmt.Errorf("no such endpoint %q", name)
+	}
+	e.uid = uid
+	return nil
+}
+
+func (m *mockConfig) endpointUID(name string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.endpoints[name].uid
+}
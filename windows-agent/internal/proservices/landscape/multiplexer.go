@@ -0,0 +1,310 @@
+package landscape
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+)
+
+// EndpointConfig is a Config that also provides the Landscape endpoints other than the main one.
+type EndpointConfig interface {
+	Config
+
+	LandscapeEndpoints() ([]string, error)
+	LandscapeEndpointConfig(name string) (string, error)
+	LandscapeEndpointUID(name string) (string, error)
+	SetLandscapeEndpointUID(name, uid string) error
+}
+
+// Multiplexer keeps one Landscape service, and hence one hostagent stream, per Landscape endpoint:
+// the main one from the Landscape client configuration, plus every other endpoint in the config.
+// Each service only reports and manages the distros assigned to its endpoint.
+type Multiplexer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	conf EndpointConfig
+	db   *database.DistroDB
+	args []Option
+
+	main *Service
+
+	// endpoints contains the services of the endpoints other than the main one, indexed by name.
+	endpoints map[string]*Service
+	stopped   bool
+	mu        sync.Mutex
+
+	// syncMu serializes the creation and removal of endpoint services.
+	syncMu sync.Mutex
+
+	// watchOnce starts watching the database the first time the endpoints are connected to.
+	watchOnce sync.Once
+}
+
+// NewMultiplexer creates the Landscape services of every endpoint. The options apply to all of them.
+func NewMultiplexer(ctx context.Context, conf EndpointConfig, db *database.DistroDB, args ...Option) (*Multiplexer, error) {
+	main, err := New(ctx, conf, db, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Multiplexer{
+		ctx:       ctx,
+		cancel:    cancel,
+		conf:      conf,
+		db:        db,
+		args:      args,
+		main:      main,
+		endpoints: make(map[string]*Service),
+	}, nil
+}
+
+// Connect connects to every Landscape endpoint. Only the errors of the main endpoint are returned.
+// The other endpoints log their errors and keep retrying in the background, like the main one does.
+// From then on, the endpoints are sent updated info every time a distro is added, updated or removed.
+func (m *Multiplexer) Connect() error {
+	m.mu.Lock()
+	stopped := m.stopped
+	m.mu.Unlock()
+
+	// Connecting happens in the background at startup, so the agent may have been stopped already.
+	if stopped {
+		return errors.New("could not connect to Landscape: the service was stopped")
+	}
+
+	m.syncEndpoints(m.ctx)
+	m.watchOnce.Do(func() { go m.watchDatabase(m.ctx) })
+	return m.main.Connect()
+}
+
+// watchDatabase queues updated info for the connected endpoints every time the distros in the database
+// change, until the context is done. A burst of changes, such as the cleanup of many distros, is sent
+// at once. The endpoints that are not connected are sent the info when they connect anyway.
+func (m *Multiplexer) watchDatabase(ctx context.Context) {
+	events, err := m.db.Watch(ctx)
+	if err != nil {
+		log.Warningf(ctx, "Landscape: not sending updated info when the distros change: %v", err)
+		return
+	}
+
+	for e := range events {
+		log.Debugf(ctx, "Landscape: distro %q was %s", e.Name, e.Kind)
+
+		for _, s := range m.services() {
+			if !s.connected() {
+				continue
+			}
+			s.Controller().QueueUpdatedInfo(ctx)
+		}
+	}
+}
+
+// Stop disconnects from every Landscape endpoint.
+func (m *Multiplexer) Stop(ctx context.Context) {
+	m.cancel()
+
+	m.mu.Lock()
+	m.stopped = true
+	endpoints := m.endpoints
+	m.endpoints = nil
+	m.mu.Unlock()
+
+	for _, s := range endpoints {
+		s.Stop(ctx)
+	}
+
+	m.main.Stop(ctx)
+}
+
+// SendUpdatedInfo sends every Landscape endpoint updated info about the machine and the distros
+// assigned to it.
+func (m *Multiplexer) SendUpdatedInfo(ctx context.Context) error {
+	var errs error
+	for name, s := range m.services() {
+		if err := s.Controller().SendUpdatedInfo(ctx); err != nil {
+			errs = errors.Join(errs, endpointError(name, err))
+		}
+	}
+
+	return errs
+}
+
+// QueueUpdatedInfo sends every Landscape endpoint updated info about the machine and the distros
+// assigned to it, once the updates queued within a short window are gathered.
+func (m *Multiplexer) QueueUpdatedInfo(ctx context.Context) {
+	for _, s := range m.services() {
+		s.Controller().QueueUpdatedInfo(ctx)
+	}
+}
+
+// CancelCommand aborts the command being executed on the distro by whichever endpoint sent it. It
+// returns false if no endpoint is executing a command on the distro.
+func (m *Multiplexer) CancelCommand(ctx context.Context, distroName string) bool {
+	var cancelled bool
+	for _, s := range m.services() {
+		if s.Controller().CancelCommand(ctx, distroName) {
+			cancelled = true
+		}
+	}
+
+	return cancelled
+}
+
+// NotifyUbuntuProUpdate is called when the Ubuntu Pro token changes. It will trigger a reconnection
+// to the endpoints that need it.
+func (m *Multiplexer) NotifyUbuntuProUpdate(ctx context.Context, token string) {
+	for _, s := range m.services() {
+		s.NotifyUbuntuProUpdate(ctx, token)
+	}
+}
+
+// NotifyConfigUpdate is called when the configuration changes. Every endpoint submits its
+// configuration to the distros assigned to it, and reconnects if needed. Endpoints that were added
+// or removed are connected to or disconnected from in the background, after which every endpoint is
+// sent its list of distros again, as distros may have been assigned to another endpoint.
+func (m *Multiplexer) NotifyConfigUpdate(ctx context.Context, landscapeConf, agentUID string) {
+	for name, s := range m.services() {
+		if name == "" {
+			s.NotifyConfigUpdate(ctx, landscapeConf, agentUID)
+			continue
+		}
+
+		conf, _, err := s.config().LandscapeClientConfig()
+		if err != nil {
+			log.Warningf(ctx, "Landscape: %v", endpointError(name, err))
+			continue
+		}
+
+		uid, err := s.config().LandscapeAgentUID()
+		if err != nil {
+			log.Warningf(ctx, "Landscape: %v", endpointError(name, err))
+			continue
+		}
+
+		s.NotifyConfigUpdate(ctx, conf, uid)
+	}
+
+	go func() {
+		m.syncEndpoints(m.ctx)
+		if err := m.SendUpdatedInfo(m.ctx); err != nil {
+			log.Debugf(m.ctx, "Landscape: could not send updated info after a config change: %v", err)
+		}
+	}()
+}
+
+// NotifyHostnameChange is called when the hostname of the Windows host changes. Every endpoint is
+// sent the new hostname, and submits its configuration to the distros assigned to it again.
+func (m *Multiplexer) NotifyHostnameChange(ctx context.Context, hostname string) {
+	for _, s := range m.services() {
+		s.NotifyHostnameChange(ctx, hostname)
+	}
+}
+
+// services returns the services of every endpoint, indexed by name. The main one has an empty name.
+func (m *Multiplexer) services() map[string]*Service {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	services := map[string]*Service{"": m.main}
+	for name, s := range m.endpoints {
+		services[name] = s
+	}
+
+	return services
+}
+
+// syncEndpoints creates a service for every endpoint in the config, and stops the services of the
+// endpoints that are no longer in it.
+func (m *Multiplexer) syncEndpoints(ctx context.Context) {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+
+	names, err := m.conf.LandscapeEndpoints()
+	if err != nil {
+		log.Warningf(ctx, "Landscape: could not sync endpoints: %v", err)
+		return
+	}
+
+	var added []string
+	var removed []*Service
+
+	m.mu.Lock()
+	for name, s := range m.endpoints {
+		if !slices.Contains(names, name) {
+			removed = append(removed, s)
+			delete(m.endpoints, name)
+		}
+	}
+	for _, name := range names {
+		if _, ok := m.endpoints[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, s := range removed {
+		log.Infof(ctx, "Landscape: disconnecting from removed endpoint %q", s.endpoint())
+		s.Stop(ctx)
+	}
+
+	for _, name := range added {
+		s, err := newService(m.ctx, endpointConfig{EndpointConfig: m.conf, name: name}, m.db, name, m.args...)
+		if err != nil {
+			log.Warningf(ctx, "Landscape: %v", endpointError(name, err))
+			continue
+		}
+
+		// Connecting can take a while, so it is done without holding the lock.
+		if err := s.Connect(); err != nil {
+			log.Warningf(ctx, "Landscape: %v", endpointError(name, err))
+		}
+
+		m.mu.Lock()
+		if m.stopped {
+			m.mu.Unlock()
+			s.Stop(ctx)
+			return
+		}
+		m.endpoints[name] = s
+		m.mu.Unlock()
+	}
+}
+
+// endpointError adds the name of the endpoint to the error, unless it is the main one.
+func endpointError(name string, err error) error {
+	if name == "" {
+		return err
+	}
+	return fmt.Errorf("endpoint %q: %v", name, err)
+}
+
+// endpointConfig presents the configuration of an endpoint as if it were the main one.
+type endpointConfig struct {
+	EndpointConfig
+	name string
+}
+
+func (c endpointConfig) LandscapeClientConfig() (string, config.Source, error) {
+	conf, err := c.LandscapeEndpointConfig(c.name)
+	if err != nil {
+		return "", config.SourceNone, err
+	}
+
+	return conf, config.SourceUser, nil
+}
+
+func (c endpointConfig) LandscapeAgentUID() (string, error) {
+	return c.LandscapeEndpointUID(c.name)
+}
+
+func (c endpointConfig) SetLandscapeAgentUID(uid string) error {
+	return c.SetLandscapeEndpointUID(c.name, uid)
+}
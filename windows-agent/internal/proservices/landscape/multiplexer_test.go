@@ -0,0 +1,134 @@
+package landscape_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/mocks/landscape/landscapemockservice"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/landscape"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+)
+
+func TestMultiplexer(t *testing.T) {
+	t.Parallel()
+
+	if !wsl.MockAvailable() {
+		t.Skip("This test can only run with the mock")
+	}
+
+	testCases := map[string]struct {
+		assignTo       string
+		removeEndpoint bool
+
+		wantInMain bool
+	}{
+		"Distro assigned to an endpoint is only reported to it":       {assignTo: "staging"},
+		"Distro assigned to a missing endpoint is reported to main":   {assignTo: "missing", wantInMain: true},
+		"Distro without an endpoint is reported to main":              {wantInMain: true},
+		"Removing an endpoint hands its distros back to the main one": {assignTo: "staging", removeEndpoint: true, wantInMain: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctx = wsl.WithMock(ctx, wslmock.New())
+
+			mainLis, mainServer, mainService := setUpLandscapeMock(t, ctx, "localhost:", "")
+			stagingLis, stagingServer, stagingService := setUpLandscapeMock(t, ctx, "localhost:", "")
+
+			//nolint:errcheck // We don't care about these errors
+			go mainServer.Serve(mainLis)
+			defer mainServer.Stop()
+
+			//nolint:errcheck // We don't care about these errors
+			go stagingServer.Serve(stagingLis)
+			defer stagingServer.Stop()
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			conf := &mockConfig{
+				proToken:              "TOKEN",
+				landscapeClientConfig: executeLandscapeConfigTemplate(t, defaultLandscapeConfig, "", mainLis.Addr()),
+				endpoints: map[string]*mockEndpoint{
+					"staging": {config: executeLandscapeConfigTemplate(t, defaultLandscapeConfig, "", stagingLis.Addr())},
+				},
+				distroEndpoints: map[string]string{distroName: tc.assignTo},
+			}
+
+			db, err := database.New(ctx, t.TempDir(), conf)
+			require.NoError(t, err, "Setup: database New should not return an error")
+
+			_, err = db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{Hostname: "TestMachine"})
+			require.NoError(t, err, "Setup: GetDistroAndUpdateProperties should return no errors")
+
+			m, err := landscape.NewMultiplexer(ctx, conf, db, landscape.WithHostname("HOSTNAME"))
+			require.NoError(t, err, "NewMultiplexer should return no errors")
+			defer m.Stop(ctx)
+
+			err = m.Connect()
+			require.NoError(t, err, "Connect should return no errors")
+
+			require.Eventually(t, func() bool {
+				return conf.landscapeAgentUID != "" && mainService.IsConnected(conf.landscapeAgentUID)
+			}, 10*time.Second, 100*time.Millisecond, "The main endpoint should have connected")
+
+			stagingUID := conf.endpointUID("staging")
+			require.Eventually(t, func() bool {
+				stagingUID = conf.endpointUID("staging")
+				return stagingUID != "" && stagingService.IsConnected(stagingUID)
+			}, 10*time.Second, 100*time.Millisecond, "The staging endpoint should have connected")
+
+			if tc.removeEndpoint {
+				conf.mu.Lock()
+				delete(conf.endpoints, "staging")
+				conf.mu.Unlock()
+
+				m.NotifyConfigUpdate(ctx, conf.landscapeClientConfig, conf.landscapeAgentUID)
+
+				require.Eventually(t, func() bool {
+					return !stagingService.IsConnected(stagingUID)
+				}, 10*time.Second, 100*time.Millisecond, "The removed endpoint should have been disconnected from")
+			}
+
+			err = m.SendUpdatedInfo(ctx)
+			require.NoError(t, err, "SendUpdatedInfo should return no errors")
+
+			require.Eventually(t, func() bool {
+				return reportsDistro(mainService, distroName) == tc.wantInMain
+			}, 10*time.Second, 100*time.Millisecond, "The main endpoint should only be reported the distros assigned to it")
+
+			if tc.removeEndpoint {
+				return
+			}
+
+			require.Eventually(t, func() bool {
+				return reportsDistro(stagingService, distroName) != tc.wantInMain
+			}, 10*time.Second, 100*time.Millisecond, "The staging endpoint should only be reported the distros assigned to it")
+		})
+	}
+}
+
+// reportsDistro returns whether the last message received by the Landscape server lists the distro.
+func reportsDistro(service *landscapemockservice.Service, distroName string) bool {
+	messages := service.MessageLog()
+	if len(messages) == 0 {
+		return false
+	}
+
+	for _, instance := range messages[len(messages)-1].Instances {
+		if instance.ID == distroName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -12,8 +12,11 @@ import (
 
 	landscapeapi "github.com/canonical/landscape-hostagent-api"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/approvals"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
 	"github.com/ubuntu/decorate"
 	"google.golang.org/grpc/connectivity"
 )
@@ -30,8 +33,31 @@ type Service struct {
 	db   *database.DistroDB
 	conf Config
 
+	// endpointName is the name of the Landscape endpoint the service connects to. It is empty
+	// for the main one.
+	endpointName string
+
+	// pauser holds back commands while the agent is paused. It may be nil.
+	pauser Pauser
+
+	// approver holds back the commands the user must approve. It may be nil.
+	approver Approver
+
+	// timeouts is how long the service waits for the Landscape server.
+	timeouts timeouts.Policy
+
+	// clock times the backoff between reconnection attempts.
+	clock clock.Clock
+
+	// inflight tracks the commands being executed, so that they can be cancelled.
+	inflight *commandTracker
+
+	// batcher gathers the updated info queued within a window into a single message.
+	batcher *infoBatcher
+
 	// Cached hostName
-	hostName string
+	hostName   string
+	hostNameMu sync.RWMutex
 
 	// Connection
 	conn   *connection
@@ -51,19 +77,75 @@ type Config interface {
 
 	LandscapeAgentUID() (string, error)
 	SetLandscapeAgentUID(string) error
+
+	LandscapeDistroOverride(distroName string) (config.LandscapeOverride, error)
+	LandscapeDistroEndpoint(distroName string) (string, error)
+}
+
+// Pauser tells the service when the agent is paused by the user.
+type Pauser interface {
+	WaitResumed(ctx context.Context) error
+}
+
+// Approver holds back the categories of operations the user asked to approve before they run.
+type Approver interface {
+	Wait(ctx context.Context, category approvals.Category, description string) error
 }
 
 type options struct {
 	hostname string
+	pauser   Pauser
+	approver Approver
+	timeouts timeouts.Policy
+	clock    clock.Clock
 }
 
 // Option is an optional argument for NewClient.
 type Option = func(*options)
 
+// WithPauser makes the service hold back the commands it receives while the agent is paused.
+// The connection to Landscape is kept alive, and the commands are executed in order once resumed.
+func WithPauser(p Pauser) Option {
+	return func(o *options) {
+		o.pauser = p
+	}
+}
+
+// WithApprover makes the service wait for the user to approve the commands they asked to approve
+// before executing them. Commands that are denied or not approved in time are not executed.
+func WithApprover(a Approver) Option {
+	return func(o *options) {
+		o.approver = a
+	}
+}
+
+// WithTimeouts overrides how long the service waits to connect to the Landscape server, and for
+// the messages sent to it.
+func WithTimeouts(policy timeouts.Policy) Option {
+	return func(o *options) {
+		o.timeouts = policy
+	}
+}
+
+// WithClock overrides the clock used to back off between reconnection attempts.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
 // New creates a new Landscape service object.
 func New(ctx context.Context, conf Config, db *database.DistroDB, args ...Option) (s *Service, err error) {
+	return newService(ctx, conf, db, "", args...)
+}
+
+// newService creates a Landscape service object connecting to the named endpoint, or to the
+// main one if the name is empty.
+func newService(ctx context.Context, conf Config, db *database.DistroDB, endpointName string, args ...Option) (s *Service, err error) {
 	defer decorate.OnError(&err, "could not initizalize Landscape service")
-	var opts options
+	opts := options{
+		clock: clock.Real(),
+	}
 
 	for _, f := range args {
 		f(&opts)
@@ -78,14 +160,22 @@ func New(ctx context.Context, conf Config, db *database.DistroDB, args ...Option
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
+	policy := opts.timeouts.OrDefault()
 
 	s = &Service{
-		ctx:         ctx,
-		cancel:      cancel,
-		conf:        conf,
-		db:          db,
-		hostName:    opts.hostname,
-		connRetrier: newRetryConnection(),
+		ctx:          ctx,
+		cancel:       cancel,
+		conf:         conf,
+		db:           db,
+		endpointName: endpointName,
+		hostName:     opts.hostname,
+		pauser:       opts.pauser,
+		approver:     opts.approver,
+		timeouts:     policy,
+		clock:        opts.clock,
+		inflight:     newCommandTracker(),
+		batcher:      newInfoBatcher(ctx, opts.clock, policy.LandscapeInfoBatch),
+		connRetrier:  newRetryConnection(),
 	}
 
 	return s, nil
@@ -131,9 +221,9 @@ func (s *Service) keepConnected() error {
 				var waitCh <-chan time.Time
 
 				if !s.disabled.Load() {
-					cooldown := time.NewTimer(wait)
+					cooldown := s.clock.NewTimer(wait)
 					defer cooldown.Stop()
-					waitCh = cooldown.C
+					waitCh = cooldown.C()
 					if wait > minWait {
 						log.Infof(s.ctx, "Landscape will attempt to connect in %s", wait)
 					}
@@ -147,9 +237,9 @@ func (s *Service) keepConnected() error {
 					// We use the cooldown to see if the connection is long-lived.
 					// Short-lived connections will be considered a failure.
 					// This avoids spamming the server with short-lived connections.
-					cooldown := time.NewTimer(wait)
+					cooldown := s.clock.NewTimer(wait)
 					defer cooldown.Stop()
-					waitCh = cooldown.C
+					waitCh = cooldown.C()
 				}
 
 				// Retrial petitions are all satisfied.
@@ -289,7 +379,7 @@ func (s *Service) NotifyUbuntuProUpdate(ctx context.Context, token string) {
 
 // NotifyConfigUpdate is called when the configuration changes. It will trigger a reconnection if needed.
 func (s *Service) NotifyConfigUpdate(ctx context.Context, landscapeConf, agentUID string) {
-	distributeConfig(ctx, s.db, landscapeConf, agentUID)
+	distributeConfig(ctx, s, landscapeConf, agentUID)
 	s.reconnectIfNewSettings(ctx)
 }
 
@@ -361,9 +451,73 @@ func (s *Service) database() *database.DistroDB {
 }
 
 func (s *Service) hostname() string {
+	s.hostNameMu.RLock()
+	defer s.hostNameMu.RUnlock()
+
 	return s.hostName
 }
 
+// NotifyHostnameChange is called when the hostname of the Windows host changes. The new hostname is
+// sent to Landscape, and the configuration is submitted again to the distros, as it may depend on
+// the hostname.
+func (s *Service) NotifyHostnameChange(ctx context.Context, hostname string) {
+	s.hostNameMu.Lock()
+	s.hostName = hostname
+	s.hostNameMu.Unlock()
+
+	conf, _, err := s.conf.LandscapeClientConfig()
+	if err != nil {
+		log.Warningf(ctx, "Landscape: could not refresh the distro configuration after a hostname change: %v", err)
+	} else if uid, err := s.conf.LandscapeAgentUID(); err != nil {
+		log.Warningf(ctx, "Landscape: could not refresh the distro configuration after a hostname change: %v", err)
+	} else if conf != "" {
+		distributeConfig(ctx, s, conf, uid)
+	}
+
+	if err := s.Controller().SendUpdatedInfo(ctx); err != nil {
+		log.Debugf(ctx, "Landscape: could not send updated info after a hostname change: %v", err)
+	}
+}
+
+func (s *Service) endpoint() string {
+	return s.endpointName
+}
+
+// waitResumed blocks until the agent is not paused, or the service is stopped.
+func (s *Service) waitResumed() error {
+	if s.pauser == nil {
+		return nil
+	}
+	return s.pauser.WaitResumed(s.ctx)
+}
+
+// waitApproved blocks until the user approves the operation, if they asked to approve its category.
+// It returns an error if the operation must not run, or if the service is stopped first.
+func (s *Service) waitApproved(ctx context.Context, category approvals.Category, description string) error {
+	if s.approver == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(s.ctx, cancel)
+	defer stop()
+
+	return s.approver.Wait(ctx, category, description)
+}
+
+func (s *Service) timeoutPolicy() timeouts.Policy {
+	return s.timeouts
+}
+
+func (s *Service) commands() *commandTracker {
+	return s.inflight
+}
+
+func (s *Service) infoBatch() *infoBatcher {
+	return s.batcher
+}
+
 func (s *Service) connected() bool {
 	s.connMu.RLock()
 	defer s.connMu.RUnlock()
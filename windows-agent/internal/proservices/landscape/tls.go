@@ -0,0 +1,184 @@
+package landscape
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ubuntu/decorate"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// pinPrefix is the optional prefix of pinned keys, as used by curl's --pinnedpubkey.
+const pinPrefix = "sha256//"
+
+// transportCredentials builds the credentials to connect to Landscape with, out of these settings
+// from the Landscape config:
+//   - ssl_public_key in the [client] section: the CA of the server, shared with the Landscape client
+//     in the distros. When set, the system CAs are not trusted.
+//   - ssl_ca_bundle in the [host] section: a PEM file with CAs to trust on top of the other ones.
+//   - ssl_pinned_keys in the [host] section: a comma-separated list of base64-encoded SHA-256 hashes
+//     of public keys. The server certificate chain must contain one of them.
+//
+// If none of them is specified, an insecure credential is returned.
+// If any of them is specified but erroneous, an error is returned.
+func transportCredentials(s connectionSettings) (cred credentials.TransportCredentials, err error) {
+	defer decorate.OnError(&err, "Landscape credentials")
+
+	if s.certificatePath == "" && s.caBundlePath == "" && s.pinnedKeys == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	roots, err := rootCAs(s.certificatePath, s.caBundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pins, err := parsePinnedKeys(s.pinnedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	v := certificateVerifier{roots: roots, pins: pins}
+
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// The standard verification is replaced by VerifyConnection, which does the same checks
+		// but explains certificate problems in terms of the Landscape config.
+		InsecureSkipVerify: true, //nolint:gosec // The certificate is verified in VerifyConnection.
+		VerifyConnection:   v.verify,
+	}), nil
+}
+
+// rootCAs returns the pool of trusted CAs. A nil pool means that the system CAs are trusted.
+func rootCAs(sslPublicKeyPath, caBundlePath string) (*x509.CertPool, error) {
+	var pool *x509.CertPool
+
+	if sslPublicKeyPath != "" {
+		pool = x509.NewCertPool()
+		if err := appendCertsFromFile(pool, sslPublicKeyPath); err != nil {
+			return nil, fmt.Errorf("could not load SSL public key file: %v", err)
+		}
+	}
+
+	if caBundlePath == "" {
+		return pool, nil
+	}
+
+	if pool == nil {
+		p, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("could not load the system CAs: %v", err)
+		}
+		pool = p
+	}
+
+	if err := appendCertsFromFile(pool, caBundlePath); err != nil {
+		return nil, fmt.Errorf("could not load CA bundle: %v", err)
+	}
+
+	return pool, nil
+}
+
+// appendCertsFromFile adds the PEM-encoded certificates in the file to the pool.
+func appendCertsFromFile(pool *x509.CertPool, path string) error {
+	certs, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if ok := pool.AppendCertsFromPEM(certs); !ok {
+		return fmt.Errorf("no valid certificate found in %s", path)
+	}
+
+	return nil
+}
+
+// parsePinnedKeys parses a comma-separated list of base64-encoded SHA-256 hashes, each of them
+// optionally prefixed with "sha256//".
+func parsePinnedKeys(keys string) ([][sha256.Size]byte, error) {
+	var pins [][sha256.Size]byte
+
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		hash, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(key, pinPrefix))
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("invalid pinned key %q: expected the base64-encoded SHA-256 hash of a public key", key)
+		}
+
+		pins = append(pins, [sha256.Size]byte(hash))
+	}
+
+	return pins, nil
+}
+
+// certificateVerifier verifies the certificate of the Landscape server.
+type certificateVerifier struct {
+	roots *x509.CertPool
+	pins  [][sha256.Size]byte
+}
+
+// verify checks that the server certificate is issued by a trusted CA for the server name, and that
+// its chain contains one of the pinned keys, if any.
+func (v certificateVerifier) verify(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("the Landscape server did not present any certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	chains, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         v.roots,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return certificateError(err)
+	}
+
+	if len(v.pins) == 0 {
+		return nil
+	}
+
+	for _, chain := range chains {
+		for _, cert := range chain {
+			if slices.Contains(v.pins, sha256.Sum256(cert.RawSubjectPublicKeyInfo)) {
+				return nil
+			}
+		}
+	}
+
+	return errors.New("the Landscape server certificate does not match any of the keys in ssl_pinned_keys")
+}
+
+// certificateError explains a certificate verification error in terms of the Landscape config.
+func certificateError(err error) error {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return fmt.Errorf("the Landscape server certificate is signed by an untrusted authority, add its CA to ssl_ca_bundle: %v", err)
+	case errors.As(err, &hostname):
+		return fmt.Errorf("the Landscape server certificate is not valid for %q, check the url in the [host] section: %v", hostname.Host, err)
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return fmt.Errorf("the Landscape server certificate has expired or is not valid yet, check the clock of this machine: %v", err)
+	default:
+		return fmt.Errorf("the Landscape server certificate is not valid: %v", err)
+	}
+}
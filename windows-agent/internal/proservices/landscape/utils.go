@@ -2,29 +2,27 @@ package landscape
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 	"sync"
 
 	landscapeapi "github.com/canonical/landscape-hostagent-api"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
-	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/tasks"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
 	"github.com/ubuntu/decorate"
 	"github.com/ubuntu/gowsl"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
 	"gopkg.in/ini.v1"
 )
 
 // landscapeHostConf is the subset of the landscape configuration relevant to the agent.
 type landscapeHostConf struct {
 	sslPublicKey    string
+	sslCABundle     string
+	sslPinnedKeys   string
 	accountName     string
 	registrationKey string
 	hostagentURL    string
@@ -53,7 +51,7 @@ func newHostAgentInfo(ctx context.Context, c serviceData) (info *landscapeapi.Ho
 		return info, err
 	}
 
-	distros := c.database().GetAll()
+	distros := managedDistros(ctx, c)
 	var instances []*landscapeapi.HostAgentInfo_InstanceInfo
 	for _, d := range distros {
 		instanceInfo, err := newInstanceInfo(d)
@@ -89,10 +87,17 @@ func newHostAgentInfo(ctx context.Context, c serviceData) (info *landscapeapi.Ho
 		info.RegistrationKey = &conf.registrationKey
 	}
 
-	if defaultDistro, ok, err := gowsl.DefaultDistro(ctx); err != nil {
+	defaultDistro, err := wslcall.Query(ctx, "querying the default distro", func(ctx context.Context) (*gowsl.Distro, error) {
+		d, ok, err := gowsl.DefaultDistro(ctx)
+		if !ok {
+			return nil, err
+		}
+		return &d, err
+	})
+	if err != nil {
 		log.Warningf(ctx, "Landscape: could not get default distro: %v", err)
 		return info, nil
-	} else if ok {
+	} else if defaultDistro != nil {
 		n := defaultDistro.Name()
 		info.DefaultInstanceId = &n
 	}
@@ -100,33 +105,6 @@ func newHostAgentInfo(ctx context.Context, c serviceData) (info *landscapeapi.Ho
 	return info, nil
 }
 
-// transportCredentials reads the Landscape client config to check if a SSL public key is specified.
-//
-// If this credential is not specified, an insecure credential is returned.
-// If the credential is specified but erroneous, an error is returned.
-func transportCredentials(sslPublicKeyPath string) (cred credentials.TransportCredentials, err error) {
-	defer decorate.OnError(&err, "Landscape credentials")
-
-	if sslPublicKeyPath == "" {
-		return insecure.NewCredentials(), nil
-	}
-
-	cert, err := os.ReadFile(sslPublicKeyPath)
-	if err != nil {
-		return nil, fmt.Errorf("could not load SSL public key file: %v", err)
-	}
-
-	certPool := x509.NewCertPool()
-	if ok := certPool.AppendCertsFromPEM(cert); !ok {
-		return nil, fmt.Errorf("failed to add server CA's certificate: %v", err)
-	}
-
-	return credentials.NewTLS(&tls.Config{
-		RootCAs:    certPool,
-		MinVersion: tls.VersionTLS12,
-	}), nil
-}
-
 // newLandscapeHostConf extracts the information relevant to the agent from the LandscapeConfig
 // configuration data.
 // Any missing necessary value will result in a noConfigError.
@@ -187,6 +165,16 @@ func newLandscapeHostConf(config Config) (conf landscapeHostConf, err error) {
 	}
 	conf.hostagentURL = urlKey.String()
 
+	k, err := sec.GetKey("ssl_ca_bundle")
+	if err == nil {
+		conf.sslCABundle = k.String()
+	}
+
+	k, err = sec.GetKey("ssl_pinned_keys")
+	if err == nil {
+		conf.sslPinnedKeys = k.String()
+	}
+
 	return conf, nil
 }
 
@@ -217,6 +205,8 @@ func newInstanceInfo(d *distro.Distro) (info *landscapeapi.HostAgentInfo_Instanc
 		return nil, fmt.Errorf("unknown state %q", state)
 	}
 
+	// The hostagent API has no field for the instance ID of the distro: Landscape matches the distro
+	// with its computer through the wsl_instance_id key the WSL Pro service writes in client.conf.
 	properties := d.Properties()
 	info = &landscapeapi.HostAgentInfo_InstanceInfo{
 		Id:            d.Name(),
@@ -228,12 +218,58 @@ func newInstanceInfo(d *distro.Distro) (info *landscapeapi.HostAgentInfo_Instanc
 	return info, nil
 }
 
-func distributeConfig(ctx context.Context, db *database.DistroDB, landscapeConf string, hostAgentUID string) {
+// managedDistros returns the distros assigned to the Landscape endpoint of the service.
+func managedDistros(ctx context.Context, d serviceData) []*distro.Distro {
+	var distros []*distro.Distro
+	for _, distro := range d.database().GetAll() {
+		if err := checkManaged(d, distro.Name()); err != nil {
+			log.Debugf(ctx, "Landscape: skipping distro %q: %v", distro.Name(), err)
+			continue
+		}
+		distros = append(distros, distro)
+	}
+
+	return distros
+}
+
+// checkManaged returns an error if the distro is not assigned to the Landscape endpoint of the service.
+func checkManaged(d serviceData, distroName string) error {
+	endpoint, err := d.config().LandscapeDistroEndpoint(distroName)
+	if err != nil {
+		return err
+	}
+
+	if endpoint != d.endpoint() {
+		return fmt.Errorf("distro %q is managed by another Landscape endpoint", distroName)
+	}
+
+	return nil
+}
+
+// distributeConfig submits the Landscape configuration to every distro managed by the service, along with
+// its distro-specific overrides.
+func distributeConfig(ctx context.Context, d serviceData, landscapeConf string, hostAgentUID string) {
+	conf := d.config()
+
 	var err error
-	for _, distro := range db.GetAll() {
+	for _, distro := range managedDistros(ctx, d) {
+		override, e := conf.LandscapeDistroOverride(distro.Name())
+		if e != nil {
+			log.Warningf(ctx, "Landscape: distro %q: ignoring distro-specific overrides: %v", distro.Name(), e)
+		}
+
+		distroConf, e := config.LandscapeTemplate{DistroName: distro.Name(), Hostname: d.hostname(), UID: hostAgentUID}.Expand(landscapeConf)
+		if e != nil {
+			log.Warningf(ctx, "Landscape: distro %q: skipping configuration: %v", distro.Name(), e)
+			continue
+		}
+
 		t := tasks.LandscapeConfigure{
-			Config:       landscapeConf,
-			HostagentUID: hostAgentUID,
+			Config:        distroConf,
+			HostagentUID:  hostAgentUID,
+			Tags:          override.Tags,
+			ComputerTitle: override.ComputerTitle,
+			ScriptUsers:   override.ScriptUsers,
 		}
 		err = errors.Join(err, distro.SubmitTasks(t))
 	}
@@ -0,0 +1,255 @@
+// Package operations keeps track of the long-running operations requested by the GUI. They run in the
+// background, independently of the client that requested them, so that clients can reattach to them
+// by their ID after they restart.
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/google/uuid"
+)
+
+// defaultRetention is how long finished operations are remembered.
+const defaultRetention = 24 * time.Hour
+
+// State is the state of an operation.
+type State string
+
+const (
+	// StateRunning is the state of an operation that has not finished yet.
+	StateRunning State = "running"
+	// StateSucceeded is the state of an operation that finished without errors.
+	StateSucceeded State = "succeeded"
+	// StateFailed is the state of an operation that finished with an error.
+	StateFailed State = "failed"
+	// StateCancelled is the state of an operation that was cancelled before it finished.
+	StateCancelled State = "cancelled"
+)
+
+var (
+	// ErrNotFound is returned when there is no operation with the requested ID.
+	ErrNotFound = errors.New("operation not found")
+
+	// ErrBusy is returned when the distro already has an operation running.
+	ErrBusy = errors.New("distro has an operation running already")
+)
+
+// Func is the work carried out by an operation. It reports its progress with the progress function,
+// and returns the result of the operation, if any.
+type Func func(ctx context.Context, progress func(uint64)) (result string, err error)
+
+// Operation is a snapshot of an operation.
+type Operation struct {
+	ID         string
+	Kind       string
+	DistroName string
+	State      State
+
+	// Err is the reason the operation failed. It is nil unless the state is StateFailed.
+	Err error
+
+	Progress uint64
+	Result   string
+
+	Started  time.Time
+	Finished time.Time
+}
+
+// operation is an operation tracked by the manager.
+type operation struct {
+	Operation
+	cancel func()
+}
+
+// Manager runs the operations and keeps track of them.
+type Manager struct {
+	ctx  context.Context
+	stop func()
+
+	retention time.Duration
+	ops       map[string]*operation
+
+	mu sync.Mutex
+	wg sync.WaitGroup
+}
+
+type options struct {
+	retention time.Duration
+}
+
+// Option is an optional argument for the operations manager.
+type Option = func(*options)
+
+// WithRetention overrides how long finished operations are remembered.
+func WithRetention(d time.Duration) Option {
+	return func(o *options) {
+		o.retention = d
+	}
+}
+
+// New creates an operations manager. The operations are cancelled when the context is cancelled.
+func New(ctx context.Context, args ...Option) *Manager {
+	opts := options{
+		retention: defaultRetention,
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Manager{
+		ctx:       ctx,
+		stop:      cancel,
+		retention: opts.retention,
+		ops:       make(map[string]*operation),
+	}
+}
+
+// Stop cancels the operations in progress and waits for them to return.
+func (m *Manager) Stop() {
+	// Cancelling with the lock held ensures no operation starts after waiting begins.
+	m.mu.Lock()
+	m.stop()
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// Start starts running an operation in the background. Only one operation may run on each distro at
+// a time.
+func (m *Manager) Start(kind, distroName string, f Func) (Operation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.ctx.Err(); err != nil {
+		return Operation{}, fmt.Errorf("could not start operation: %v", err)
+	}
+
+	m.prune()
+
+	for _, op := range m.ops {
+		if op.DistroName == distroName && op.State == StateRunning {
+			return Operation{}, fmt.Errorf("%w: %s %s", ErrBusy, op.Kind, op.ID)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+
+	op := &operation{
+		Operation: Operation{
+			ID:         uuid.NewString(),
+			Kind:       kind,
+			DistroName: distroName,
+			State:      StateRunning,
+			Started:    time.Now(),
+		},
+		cancel: cancel,
+	}
+	m.ops[op.ID] = op
+
+	log.Infof(ctx, "Operations: started %s operation %s on distro %q", kind, op.ID, distroName)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer cancel()
+
+		result, err := f(ctx, func(p uint64) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			op.Progress = p
+		})
+
+		m.finish(ctx, op, result, err)
+	}()
+
+	return op.Operation, nil
+}
+
+// finish records the outcome of an operation.
+func (m *Manager) finish(ctx context.Context, op *operation, result string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op.Finished = time.Now()
+
+	switch {
+	case err == nil:
+		op.State = StateSucceeded
+		op.Result = result
+	case ctx.Err() != nil:
+		op.State = StateCancelled
+	default:
+		op.State = StateFailed
+		op.Err = err
+	}
+
+	log.Infof(ctx, "Operations: %s operation %s on distro %q %s", op.Kind, op.ID, op.DistroName, op.State)
+}
+
+// Get returns the operation with the given ID.
+func (m *Manager) Get(id string) (Operation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune()
+
+	op, ok := m.ops[id]
+	if !ok {
+		return Operation{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return op.Operation, nil
+}
+
+// Cancel requests the operation with the given ID to stop. It does not wait for it to do so: the
+// operation remains in the running state until it returns. Cancelling a finished operation is a no-op.
+func (m *Manager) Cancel(id string) (Operation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op, ok := m.ops[id]
+	if !ok {
+		return Operation{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	op.cancel()
+	return op.Operation, nil
+}
+
+// List returns all the operations, sorted from oldest to newest.
+func (m *Manager) List() []Operation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune()
+
+	ops := make([]Operation, 0, len(m.ops))
+	for _, op := range m.ops {
+		ops = append(ops, op.Operation)
+	}
+
+	slices.SortFunc(ops, func(a, b Operation) int {
+		return a.Started.Compare(b.Started)
+	})
+
+	return ops
+}
+
+// prune forgets the operations that finished longer than the retention period ago.
+// The caller must hold the lock.
+func (m *Manager) prune() {
+	for id, op := range m.ops {
+		if op.State != StateRunning && time.Since(op.Finished) > m.retention {
+			delete(m.ops, id)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package operations_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/operations"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperation(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fail   bool
+		cancel bool
+
+		wantState  operations.State
+		wantResult string
+	}{
+		"Success": {wantState: operations.StateSucceeded, wantResult: "result"},
+
+		"Operation fails":        {fail: true, wantState: operations.StateFailed},
+		"Operation is cancelled": {cancel: true, wantState: operations.StateCancelled},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			m := operations.New(ctx)
+			defer m.Stop()
+
+			proceed := make(chan struct{})
+			op, err := m.Start("export", "testDistro", func(ctx context.Context, progress func(uint64)) (string, error) {
+				progress(42)
+
+				select {
+				case <-ctx.Done():
+					return "", ctx.Err()
+				case <-proceed:
+				}
+
+				if tc.fail {
+					return "", errors.New("mock error")
+				}
+				return "result", nil
+			})
+			require.NoError(t, err, "Start should return no error")
+			require.NotEmpty(t, op.ID, "Operation should have an ID")
+			require.Equal(t, operations.StateRunning, op.State, "Operation should be running after it starts")
+
+			require.Eventually(t, func() bool {
+				got, err := m.Get(op.ID)
+				return err == nil && got.Progress == 42
+			}, time.Second, 10*time.Millisecond, "Progress of the operation should be reported")
+
+			if tc.cancel {
+				_, err := m.Cancel(op.ID)
+				require.NoError(t, err, "Cancel should return no error")
+			} else {
+				close(proceed)
+			}
+
+			require.Eventually(t, func() bool {
+				got, err := m.Get(op.ID)
+				return err == nil && got.State != operations.StateRunning
+			}, time.Second, 10*time.Millisecond, "Operation should finish")
+
+			got, err := m.Get(op.ID)
+			require.NoError(t, err, "Get should return no error")
+
+			require.Equal(t, tc.wantState, got.State, "Unexpected state of the operation")
+			require.Equal(t, tc.wantResult, got.Result, "Unexpected result of the operation")
+			require.Equal(t, tc.fail, got.Err != nil, "Error should only be set when the operation fails")
+			require.False(t, got.Finished.IsZero(), "Finish time should be set")
+		})
+	}
+}
+
+func TestStartBusy(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := operations.New(ctx)
+	defer m.Stop()
+
+	block := func(ctx context.Context, _ func(uint64)) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	_, err := m.Start("reset", "testDistro", block)
+	require.NoError(t, err, "Start should return no error")
+
+	_, err = m.Start("export", "testDistro", block)
+	require.ErrorIs(t, err, operations.ErrBusy, "Start should fail when the distro has an operation running")
+
+	_, err = m.Start("export", "otherDistro", block)
+	require.NoError(t, err, "Start should allow operations on other distros")
+}
+
+func TestGetAndCancelNotFound(t *testing.T) {
+	t.Parallel()
+
+	m := operations.New(context.Background())
+	defer m.Stop()
+
+	_, err := m.Get("not-an-id")
+	require.ErrorIs(t, err, operations.ErrNotFound, "Get should fail when the operation does not exist")
+
+	_, err = m.Cancel("not-an-id")
+	require.ErrorIs(t, err, operations.ErrNotFound, "Cancel should fail when the operation does not exist")
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		retention time.Duration
+
+		wantLen int
+	}{
+		"Finished operations are kept": {retention: time.Hour, wantLen: 3},
+
+		"Finished operations are forgotten after the retention period": {retention: time.Nanosecond, wantLen: 1},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := operations.New(context.Background(), operations.WithRetention(tc.retention))
+			defer m.Stop()
+
+			done := func(context.Context, func(uint64)) (string, error) { return "", nil }
+			block := func(ctx context.Context, _ func(uint64)) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+
+			var ids []string
+			for _, distro := range []string{"distro1", "distro2"} {
+				op, err := m.Start("reset", distro, done)
+				require.NoError(t, err, "Setup: Start should return no error")
+				ids = append(ids, op.ID)
+
+				require.Eventually(t, func() bool {
+					op, err := m.Get(op.ID)
+					return err == nil && op.State == operations.StateSucceeded
+				}, time.Second, 10*time.Millisecond, "Setup: operation should finish")
+			}
+
+			op, err := m.Start("export", "distro3", block)
+			require.NoError(t, err, "Setup: Start should return no error")
+			ids = append(ids, op.ID)
+
+			// Give the retention period time to expire.
+			time.Sleep(10 * time.Millisecond)
+
+			got := m.List()
+			require.Len(t, got, tc.wantLen, "Unexpected number of operations listed")
+
+			wantIDs := ids[len(ids)-tc.wantLen:]
+			for i := range got {
+				require.Equal(t, wantIDs[i], got[i].ID, "Operations should be sorted from oldest to newest")
+			}
+		})
+	}
+}
+
+func TestStop(t *testing.T) {
+	t.Parallel()
+
+	m := operations.New(context.Background())
+
+	op, err := m.Start("export", "testDistro", func(ctx context.Context, _ func(uint64)) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	require.NoError(t, err, "Setup: Start should return no error")
+
+	m.Stop()
+
+	got, err := m.Get(op.ID)
+	require.NoError(t, err, "Get should return no error after stopping")
+	require.Equal(t, operations.StateCancelled, got.State, "Running operations should be cancelled when stopping")
+
+	_, err = m.Start("export", "testDistro", nil)
+	require.Error(t, err, "Start should fail after stopping")
+}
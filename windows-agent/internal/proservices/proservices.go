@@ -3,35 +3,108 @@ package proservices
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	agent_api "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/grpc/interceptorschain"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logconnections"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/approvals"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/auditlog"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/scheduler"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/hoststate"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/legacyconfig"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/notifications"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/onboarding"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/paths"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/plugins"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/preflight"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/backup"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/compaction"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/landscape"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/operations"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/ui"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/uiauth"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/wslinstance"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/wslupdate"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/statedir"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/ubuntupro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslconfig"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslwatch"
 	"github.com/sirupsen/logrus"
 	wsl "github.com/ubuntu/gowsl"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// maxParallelStartups is how many distros the agent can start at the same time.
+const maxParallelStartups = 4
+
+// hibernateAfter is how long a distro must go without connecting nor receiving tasks before the
+// resources of its task processing are released.
+const hibernateAfter = 7 * 24 * time.Hour
+
+// The components whose readiness is reported through the health service. The API is served as soon
+// as the services are created, while these components get ready in the background.
+const (
+	// HealthSubscription is ready once the subscription was checked against the Microsoft Store.
+	HealthSubscription = "subscription"
+
+	// HealthLandscape is ready once the first attempt to connect to Landscape is over, whatever its outcome.
+	HealthLandscape = "landscape"
+
+	// HealthDistros is ready once the distros with pending tasks have been provisioned.
+	HealthDistros = "distros"
 )
 
 // Manager is the orchestrator of GRPC API services and business logic.
 type Manager struct {
 	uiService          ui.Service
 	wslInstanceService wslinstance.Service
-	landscapeService   *landscape.Service
+	landscapeService   *landscape.Multiplexer
 	registryWatcher    *registrywatcher.Service
+	backupService      *backup.Service
+	compactionService  *compaction.Service
+	scheduler          *scheduler.Scheduler
+	operations         *operations.Manager
+	uiAuth             *uiauth.Authenticator
 	db                 *database.DistroDB
+	health             *health.Server
+	conf               *config.Config
+
+	// stopRefresh stops refreshing the Microsoft Store entitlement, watching the host and the startup
+	// steps that run in the background.
+	stopRefresh context.CancelFunc
+
+	// startup tracks the startup steps that run in the background.
+	startup *sync.WaitGroup
+
+	// reset is closed once a reset of the agent is requested.
+	reset *resetSignal
 }
 
 // options are the configurable functional options for the daemon.
 type options struct {
 	registry registrywatcher.Registry
+	timeouts timeouts.Policy
+	logLevel logrus.Level
+	prompter uiauth.Prompter
+
+	// explicitLogLevel is true if logLevel takes precedence over the config file.
+	explicitLogLevel bool
 }
 
 // Option is the function signature we are passing to tweak the daemon creation.
@@ -44,11 +117,43 @@ func WithRegistry(registry registrywatcher.Registry) func(o *options) {
 	}
 }
 
+// WithTimeouts overrides how long the services wait for the distros, the Landscape server and the
+// Windows host. Unset timeouts keep their default.
+func WithTimeouts(policy timeouts.Policy) func(o *options) {
+	return func(o *options) {
+		o.timeouts = policy
+	}
+}
+
+// WithPrompter overrides how the user is asked to approve destructive calls to the UI service.
+func WithPrompter(p uiauth.Prompter) func(o *options) {
+	return func(o *options) {
+		o.prompter = p
+	}
+}
+
+// WithBaseLogLevel sets the log level the agent was started with, which is restored when the config file
+// stops overriding it. It defaults to the log level at the time the services are created.
+func WithBaseLogLevel(level logrus.Level) func(o *options) {
+	return func(o *options) {
+		o.logLevel = level
+	}
+}
+
+// WithExplicitLogLevel sets the log level the agent was started with, and makes it take precedence over the
+// config file, because it was set explicitly on the command line or in the environment.
+func WithExplicitLogLevel(level logrus.Level) func(o *options) {
+	return func(o *options) {
+		o.logLevel = level
+		o.explicitLogLevel = true
+	}
+}
+
 // New returns a new GRPC services manager.
 // It instantiates both ui and wsl instance services.
 //
 // Once done, Stop must be called to deallocate resources.
-func New(ctx context.Context, publicDir, privateDir string, args ...Option) (s Manager, err error) {
+func New(ctx context.Context, p paths.Paths, args ...Option) (s Manager, err error) {
 	log.Debug(ctx, "Building new GRPC services manager")
 
 	defer func() {
@@ -58,8 +163,16 @@ func New(ctx context.Context, publicDir, privateDir string, args ...Option) (s M
 		}
 	}()
 
+	s.reset = newResetSignal()
+
+	s.health = health.NewServer()
+	for _, component := range []string{HealthSubscription, HealthLandscape, HealthDistros} {
+		s.health.SetServingStatus(component, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	s.startup = &sync.WaitGroup{}
+
 	// Apply given options.
-	var opts options
+	opts := options{logLevel: logrus.GetLevel()}
 	for _, f := range args {
 		f(&opts)
 	}
@@ -70,9 +183,65 @@ func New(ctx context.Context, publicDir, privateDir string, args ...Option) (s M
 	//[GitHub](https://github.com/canonical/ubuntu-pro-for-wsl/pull/438)
 	InitWSLAPI()
 
-	conf := config.New(ctx, privateDir)
+	// The state left behind by a previous release must be migrated before anyone reads it.
+	if err := statedir.Migrate(ctx, p.State); err != nil {
+		return s, err
+	}
+
+	// Only the clients that can read the token file in the private directory can drive the agent.
+	var authOpts []uiauth.Option
+	if opts.prompter != nil {
+		authOpts = append(authOpts, uiauth.WithPrompter(opts.prompter))
+	}
+
+	uiAuth, err := uiauth.New(p.State, authOpts...)
+	if err != nil {
+		return s, err
+	}
+	s.uiAuth = uiAuth
+
+	policy := opts.timeouts.OrDefault()
+
+	// Every call to WSL made on behalf of the services gives up once it takes longer than the policy allows.
+	ctx = wslcall.WithTimeouts(ctx, policy)
+
+	// The config, the database and the WSL instance service share a clock, so that the times they record
+	// can be compared with each other.
+	clk := clock.Real()
+
+	conf := config.New(ctx, p.State, config.WithHostState(hoststate.New(hoststate.WithTimeouts(policy))), config.WithClock(clk))
+	s.conf = conf
+
+	paused, err := conf.Paused()
+	if err != nil {
+		log.Warningf(ctx, "Could not read whether the agent is paused, assuming it is not: %v", err)
+	}
+	pauser := pause.New(paused)
+	if paused {
+		log.Info(ctx, "The agent is paused: no automatic action will be taken until it is resumed")
+	}
+
+	// While WSL is unavailable, the distros and Landscape hold back their operations as if the agent were paused.
+	wslWatcher := wslwatch.New()
+	holdBack := pause.Any(pauser, wslWatcher)
 
-	db, err := database.New(ctx, privateDir, conf)
+	notifier := notifications.New(conf)
+	approver := approvals.New(conf)
+
+	// Plugins are optional: the agent works the same without them. They are loaded before the database,
+	// so that the plugin tasks read back from disk can find the address of their plugin.
+	taskPlugins, pluginsErr := plugins.Load(ctx, p.PluginsDir())
+
+	db, err := database.New(ctx, p.State, conf,
+		database.WithMaxParallelStartups(maxParallelStartups),
+		database.WithSchedule(conf),
+		database.WithPauser(holdBack),
+		database.WithWSLWatcher(wslWatcher),
+		database.WithAdoptionNotifier(notifier.NotifyDistroAdopted),
+		database.WithLockWatchdog(policy.LockWatchdog),
+		database.WithHibernation(hibernateAfter),
+		database.WithTimeouts(policy),
+		database.WithClock(clk))
 	if err != nil {
 		return s, err
 	}
@@ -82,21 +251,46 @@ func New(ctx context.Context, publicDir, privateDir string, args ...Option) (s M
 	s.registryWatcher = &w
 
 	s.uiService = ui.New(ctx, conf, s.db)
+	s.uiService.SetTimeouts(policy)
+	s.uiService.SetConfirmer(s.uiAuth)
+	s.uiService.SetWSLWatcher(wslWatcher)
+	s.uiService.SetApprovals(approver)
+
+	reload := reloader{conf: conf, registry: s.registryWatcher, pauser: pauser, baseLogLevel: opts.logLevel, explicitLogLevel: opts.explicitLogLevel}
+	s.uiService.SetReloader(reload)
+	if err := reload.applyLogLevel(ctx); err != nil {
+		log.Warningf(ctx, "Could not apply the log level of the config file: %v", err)
+	}
 
-	landscape, err := landscape.New(ctx, conf, s.db)
+	landscape, err := landscape.NewMultiplexer(ctx, conf, s.db, landscape.WithPauser(holdBack), landscape.WithApprover(approver), landscape.WithTimeouts(policy))
 	if err != nil {
 		return s, err
 	}
 	s.landscapeService = landscape
 
-	wslInstanceService, err := wslinstance.New(ctx, s.db, s.landscapeService.Controller())
+	s.uiService.SetResetter(resetter{
+		db:            s.db,
+		landscape:     s.landscapeService,
+		registry:      s.registryWatcher,
+		signal:        s.reset,
+		pauser:        holdBack,
+		schedule:      conf,
+		detachTimeout: policy.ResetDetach,
+	})
+
+	wslInstanceService, err := wslinstance.New(ctx, s.db, s.landscapeService, wslinstance.WithTimeouts(policy), wslinstance.WithClock(clk))
 	if err != nil {
 		return s, err
 	}
 	s.wslInstanceService = wslInstanceService
 
 	conf.SetUbuntuProNotifier(func(ctx context.Context, token string) {
-		ubuntupro.Distribute(ctx, s.db, token)
+		distroTokens, err := conf.DistroSubscriptions()
+		if err != nil {
+			log.Warningf(ctx, "could not get per-distro Ubuntu Pro tokens, the global one is sent to every distro: %v", err)
+		}
+
+		ubuntupro.Distribute(ctx, s.db, token, distroTokens)
 		landscape.NotifyUbuntuProUpdate(ctx, token)
 	})
 
@@ -104,24 +298,128 @@ func New(ctx context.Context, publicDir, privateDir string, args ...Option) (s M
 		landscape.NotifyConfigUpdate(ctx, conf, uid)
 	})
 
+	conf.SetPauseNotifier(func(ctx context.Context, paused bool) {
+		setPaused(ctx, pauser, paused)
+	})
+
+	// Settings left behind by older tooling are imported once notifications are set up, so that the distros get them.
+	if err := legacyconfig.Import(ctx, conf, legacyconfig.WithRegistry(opts.registry)); err != nil {
+		log.Warningf(ctx, "%v", err)
+	}
+
 	// All notifications have been set up: starting the registry watcher before any services.
 	s.registryWatcher.Start()
 
-	if err := ubuntupro.FetchFromMicrosoftStore(ctx, conf, s.db); err != nil {
+	// The entitlement is kept fresh so that transient Microsoft Store outages don't drop the subscription.
+	refreshCtx, stopRefresh := context.WithCancel(ctx)
+	s.stopRefresh = stopRefresh
+	go ubuntupro.RefreshStoreEntitlement(refreshCtx, conf)
+	go notifier.WatchSubscription(refreshCtx)
+	go wslWatcher.Run(refreshCtx)
+
+	// Landscape and the distro configurations depend on the hostname, so changes must be propagated.
+	audit := auditlog.New(p.State)
+	go hoststate.WatchHostname(refreshCtx, func(ctx context.Context, oldName, newName string) {
+		log.Infof(ctx, "The hostname changed from %q to %q", oldName, newName)
+		audit.Record(ctx, "hostname", fmt.Sprintf("changed from %q to %q", oldName, newName))
+		landscape.NotifyHostnameChange(ctx, newName)
+	})
+
+	// The backup and compaction services are only created once nothing else can fail, so that Stop never waits on them
+	// without it having started.
+	s.backupService = backup.New(ctx, conf, s.db, p.BackupsDir(), backup.WithPauser(holdBack), backup.WithSchedule(conf))
+	s.uiService.SetExporter(s.backupService)
+	s.compactionService = compaction.New(ctx, conf, s.db, compaction.WithPauser(holdBack))
+	s.uiService.SetCompactor(s.compactionService)
+	// Recurring tasks are optional: a broken record of their last runs must not keep the agent from starting.
+	if sched, err := scheduler.New(ctx, conf, s.db, p.RecurringTasksFile(), scheduler.WithPauser(holdBack)); err != nil {
+		log.Warningf(ctx, "Recurring tasks are disabled: %v", err)
+	} else {
+		s.scheduler = sched
+	}
+	// A broken record of the initial setup must not keep the agent from starting: the GUI starts it over instead.
+	if tracker, err := onboarding.New(conf, s.db, p.OnboardingFile()); err != nil {
+		log.Warningf(ctx, "Onboarding state is not available: %v", err)
+	} else {
+		s.uiService.SetOnboarding(tracker)
+	}
+	s.operations = operations.New(ctx)
+	s.uiService.SetOperations(s.operations)
+
+	if path, err := wslconfig.DefaultPath(); err != nil {
 		log.Warningf(ctx, "%v", err)
+	} else {
+		s.uiService.SetWSLConfigFile(wslconfig.New(ctx, path))
 	}
 
-	if err := s.landscapeService.Connect(); err != nil {
-		log.Warningf(ctx, err.Error())
+	// The preflight checks run in the background because querying Windows is slow. Until they finish, no problem is reported.
+	checker := preflight.New()
+	s.uiService.SetPreflight(checker)
+	go checker.Run(ctx)
+
+	s.uiService.SetWSLUpdater(wslupdate.New(s.db,
+		wslupdate.WithPauser(pauser),
+		wslupdate.WithNotifier(notifier.NotifyWSLUpdate),
+		wslupdate.WithPreflight(checker)))
+
+	if pluginsErr != nil {
+		log.Warningf(ctx, "%v", pluginsErr)
+	} else {
+		s.uiService.SetTaskPlugins(taskPlugins)
+	}
+	s.backupService.Start()
+	s.compactionService.Start()
+	if s.scheduler != nil {
+		s.scheduler.Start()
 	}
 
+	// The steps that wait on the network or on the distros run in the background, so that the API is
+	// responsive right away. The health service reports when each of them is done.
+	s.inBackground(HealthSubscription, func() {
+		if err := ubuntupro.FetchFromMicrosoftStore(refreshCtx, conf, s.db); err != nil {
+			log.Warningf(ctx, "%v", err)
+		}
+	})
+
+	s.inBackground(HealthLandscape, func() {
+		if err := s.landscapeService.Connect(); err != nil {
+			log.Warningf(ctx, err.Error())
+		}
+	})
+
+	// Distros with pending tasks get provisioned in parallel rather than one worker at a time.
+	s.inBackground(HealthDistros, func() {
+		p := s.db.Provision(refreshCtx, func(p database.ProvisioningProgress) {
+			log.Infof(ctx, "Startup provisioning: %s", p)
+		})
+		if p.Total > 0 {
+			log.Infof(ctx, "Startup provisioning finished: %s", p)
+		}
+		notifier.NotifyProvisioningFailed(ctx, p.Failed)
+	})
+
 	return s, nil
 }
 
+// inBackground runs a startup step without holding back the API, and reports the component as ready
+// through the health service once the step is done.
+func (m Manager) inBackground(component string, step func()) {
+	m.startup.Add(1)
+	go func() {
+		defer m.startup.Done()
+		step()
+		m.health.SetServingStatus(component, healthpb.HealthCheckResponse_SERVING)
+	}()
+}
+
 // Stop deallocates resources in the services.
 func (m Manager) Stop(ctx context.Context) {
 	log.Info(ctx, "Stopping GRPC services manager")
 
+	if m.stopRefresh != nil {
+		m.stopRefresh()
+	}
+
 	if m.landscapeService != nil {
 		m.landscapeService.Stop(ctx)
 	}
@@ -130,23 +428,96 @@ func (m Manager) Stop(ctx context.Context) {
 		m.registryWatcher.Stop()
 	}
 
+	if m.backupService != nil {
+		m.backupService.Stop()
+	}
+
+	if m.compactionService != nil {
+		m.compactionService.Stop()
+	}
+
+	// The startup steps are done with the services before they are released.
+	if m.startup != nil {
+		m.startup.Wait()
+	}
+
+	if m.health != nil {
+		m.health.Shutdown()
+	}
+
+	if m.scheduler != nil {
+		m.scheduler.Stop()
+	}
+
+	// Operations are stopped before the database is closed, as they may be using it.
+	if m.operations != nil {
+		m.operations.Stop()
+	}
+
 	if m.db != nil {
 		m.db.Close(ctx)
 	}
+
+	if m.uiAuth != nil {
+		if err := m.uiAuth.Remove(); err != nil {
+			log.Warningf(ctx, "Could not remove the authentication token file: %v", err)
+		}
+	}
 }
 
-// RegisterGRPCServices returns a new grpc Server with the 2 api services attached to it.
-// It also gets the correct middlewares hooked in.
+// setPaused pauses or resumes the automatic behaviour of the agent, logging the change if there is one.
+func setPaused(ctx context.Context, pauser *pause.Switch, paused bool) {
+	if !pauser.Set(paused) {
+		return
+	}
+	if paused {
+		log.Info(ctx, "The agent was paused: no automatic action will be taken until it is resumed")
+	} else {
+		log.Info(ctx, "The agent was resumed")
+	}
+}
+
+// FeatureEnabled returns true if the feature flag is enabled, as set by the organization or the user.
+// Flags that cannot be read are considered disabled, so that experimental behaviour stays opt-in.
+func (m Manager) FeatureEnabled(ctx context.Context, flag features.Flag) bool {
+	enabled, err := m.conf.FeatureEnabled(flag)
+	if err != nil {
+		log.Warningf(ctx, "Could not read feature flag %s, assuming it is disabled: %v", flag, err)
+		return false
+	}
+	return enabled
+}
+
+// DropDistroConnections ends the connections of every distro, so that they connect again. This is
+// useful when they may have gone stale, e.g. after a network change.
+func (m Manager) DropDistroConnections(ctx context.Context) {
+	m.wslInstanceService.DropConnections(ctx)
+}
+
+// EndStreams ends the streams that only the clients hang up, the connections of the distros and the
+// watches of the UI, so that the daemon can stop gracefully.
+func (m Manager) EndStreams(ctx context.Context) {
+	m.wslInstanceService.DropConnections(ctx)
+	m.uiService.StopWatches(ctx)
+}
+
+// RegisterGRPCServices returns a new grpc Server with the 2 api services and the health service attached to it.
+// It also gets the correct middlewares hooked in. Calls to the UI service are rejected unless they carry
+// the authentication token of the session.
 func (m Manager) RegisterGRPCServices(ctx context.Context) *grpc.Server {
 	log.Debug(ctx, "Registering GRPC services")
 
-	grpcServer := grpc.NewServer(grpc.StreamInterceptor(
-		interceptorschain.StreamServer(
-			log.StreamServerInterceptor(logrus.StandardLogger()),
+	grpcServer := grpc.NewServer(
+		telemetry.ServerOption(),
+		grpc.UnaryInterceptor(m.uiAuth.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(interceptorschain.StreamServer(
+			m.uiAuth.StreamServerInterceptor(),
+			log.StreamServerInterceptor(logrus.StandardLogger(), log.WithLogBuffer(consts.LogBufferSize, log.Coalesce)),
 			logconnections.StreamServerInterceptor(),
 		)))
 	agent_api.RegisterUIServer(grpcServer, &m.uiService)
 	agent_api.RegisterWSLInstanceServer(grpcServer, &m.wslInstanceService)
+	healthpb.RegisterHealthServer(grpcServer, m.health)
 
 	return grpcServer
 }
@@ -155,5 +526,7 @@ func (m Manager) RegisterGRPCServices(ctx context.Context) *grpc.Server {
 // with the MS Store API, thus it must be called as early as possible.
 func InitWSLAPI() {
 	d := wsl.NewDistro(context.Background(), "Whatever")
-	_, _ = d.GetConfiguration()
+	_, _ = wslcall.Query(context.Background(), "initializing the WSL API", func(context.Context) (wsl.Configuration, error) {
+		return d.GetConfiguration()
+	})
 }
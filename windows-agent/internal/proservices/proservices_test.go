@@ -2,15 +2,21 @@ package proservices_test
 
 import (
 	"context"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/paths"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices"
-	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/testutils"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestMain(m *testing.M) {
@@ -26,13 +32,15 @@ func TestNew(t *testing.T) {
 	testCases := map[string]struct {
 		breakConfig      bool
 		breakNewDistroDB bool
+		newerLayout      bool
 
 		wantErr bool
 	}{
 		"Success when the subscription stays empty":               {},
 		"Success when the config cannot check if it is read-only": {breakConfig: true},
 
-		"Error when database cannot create its dump file": {breakNewDistroDB: true, wantErr: true},
+		"Error when database cannot create its dump file":              {breakNewDistroDB: true, wantErr: true},
+		"Error when the state was written by a newer version of agent": {newerLayout: true, wantErr: true},
 	}
 
 	for name, tc := range testCases {
@@ -43,7 +51,7 @@ func TestNew(t *testing.T) {
 			publicDir := t.TempDir()
 			privateDir := t.TempDir()
 
-			reg := registry.NewMock()
+			reg := testutils.NewRegistryMock()
 			k, err := reg.HKCUCreateKey("Software/Canonical/UbuntuPro")
 			require.NoError(t, err, "Setup: could not create Ubuntu Pro registry key")
 			reg.CloseKey(k)
@@ -54,7 +62,12 @@ func TestNew(t *testing.T) {
 				require.NoError(t, err, "Setup: could not write directory where database wants to put a file")
 			}
 
-			s, err := proservices.New(ctx, publicDir, privateDir, proservices.WithRegistry(reg))
+			if tc.newerLayout {
+				err := os.WriteFile(filepath.Join(privateDir, consts.StateVersionFileName), []byte("9999\n"), 0600)
+				require.NoError(t, err, "Setup: could not write a newer layout version")
+			}
+
+			s, err := proservices.New(ctx, paths.Paths{Public: publicDir, State: privateDir}, proservices.WithRegistry(reg))
 			if err == nil {
 				defer s.Stop(ctx)
 			}
@@ -74,7 +87,7 @@ func TestRegisterGRPCServices(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
 
-	ps, err := proservices.New(ctx, t.TempDir(), t.TempDir(), proservices.WithRegistry(registry.NewMock()))
+	ps, err := proservices.New(ctx, paths.Paths{Public: t.TempDir(), State: t.TempDir()}, proservices.WithRegistry(testutils.NewRegistryMock()))
 	require.NoError(t, err, "Setup: New should return no error")
 	defer ps.Stop(ctx)
 
@@ -87,5 +100,43 @@ func TestRegisterGRPCServices(t *testing.T) {
 	_, ok = info["agentapi.WSLInstance"]
 	require.True(t, ok, "WSLInstance service should be registered after calling RegisterGRPCServices")
 
-	require.Lenf(t, info, 2, "Info should contain exactly two elements")
+	_, ok = info["grpc.health.v1.Health"]
+	require.True(t, ok, "Health service should be registered after calling RegisterGRPCServices")
+
+	require.Lenf(t, info, 3, "Info should contain exactly three elements")
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	ps, err := proservices.New(ctx, paths.Paths{Public: t.TempDir(), State: t.TempDir()}, proservices.WithRegistry(testutils.NewRegistryMock()))
+	require.NoError(t, err, "Setup: New should return no error")
+	defer ps.Stop(ctx)
+
+	server := ps.RegisterGRPCServices(ctx)
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err, "Setup: could not listen")
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err, "Setup: could not dial the services")
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err, "Checking the health of the agent should return no error")
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus(), "The agent should be serving as soon as the services are created")
+
+	// There is neither a Landscape config nor a Microsoft Store subscription, so every component gets ready quickly.
+	for _, component := range []string{proservices.HealthSubscription, proservices.HealthLandscape, proservices.HealthDistros} {
+		require.Eventuallyf(t, func() bool {
+			resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: component})
+			return err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING
+		}, 10*time.Second, 100*time.Millisecond, "Component %q should be reported as ready", component)
+	}
 }
@@ -14,15 +14,14 @@ var (
 	// ErrFieldNotExist is returned when attempting to read a key field that does not exist.
 	ErrFieldNotExist = errors.New("the field does not exist")
 
+	// ErrUnexpectedType is returned when attempting to read a field with a reader for a different type.
+	ErrUnexpectedType = errors.New("the field has an unexpected type")
+
 	// ErrAccessDenied is printed when an action is blocked by the key's security descriptor.
 	//
 	// It is NOT used when the action is blocked by access rights such READ or WRITE.
 	// In that case we allow the syscall error to bubble up, as we don't need to catch it.
 	ErrAccessDenied = errors.New("access denied")
-
-	// ErrMock is the error returned when everything went fine but the mock
-	// setup requested an error to be thrown.
-	ErrMock = errors.New("error triggered by mock setup")
 )
 
 // Event is a void pointer to a Windows event.
@@ -13,6 +13,11 @@ func (Windows) HKCUCreateKey(path string) (Key, error) {
 	panic("the Windows registry is not available on Linux")
 }
 
+// HKCUOpenKeyWrite opens an existing key in the specified path under the HK_CURRENT_USER registry with write permissions.
+func (Windows) HKCUOpenKeyWrite(path string) (Key, error) {
+	panic("the Windows registry is not available on Linux")
+}
+
 // CloseKey releases a key.
 func (Windows) CloseKey(k Key) {
 	panic("the Windows registry is not available on Linux")
@@ -23,11 +28,26 @@ func (Windows) ReadValue(k Key, field string) (string, error) {
 	panic("the Windows registry is not available on Linux")
 }
 
+// ReadDWORD returns the value of the specified REG_DWORD field in the specified key.
+func (Windows) ReadDWORD(k Key, field string) (uint32, error) {
+	panic("the Windows registry is not available on Linux")
+}
+
+// ReadValueNames returns the names of all the fields in the specified key.
+func (Windows) ReadValueNames(k Key) ([]string, error) {
+	panic("the Windows registry is not available on Linux")
+}
+
 // WriteValue writes the value to the specified field in the specified key.
 func (Windows) WriteValue(k Key, field, value string, multiLine bool) error {
 	panic("the Windows registry is not available on Linux")
 }
 
+// DeleteValue removes the specified field from the specified key.
+func (Windows) DeleteValue(k Key, field string) error {
+	panic("the Windows registry is not available on Linux")
+}
+
 // RegNotifyChangeKeyValue creates an event and attaches it to a registry key.
 // Modifying that key or its children will trigger the event.
 // This trigger can be detected by WaitForSingleObject.
@@ -38,6 +38,18 @@ func (Windows) HKCUCreateKey(path string) (Key, error) {
 	return Key(key), err
 }
 
+// HKCUOpenKeyWrite opens an existing key in the specified path under the HK_CURRENT_USER registry with write permissions.
+func (Windows) HKCUOpenKeyWrite(path string) (Key, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, path, registry.READ|registry.WRITE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return 0, ErrKeyNotExist
+	}
+	if errors.Is(err, syscall.Errno(5)) { // Access is denied
+		return 0, ErrAccessDenied
+	}
+	return Key(key), err
+}
+
 // CloseKey releases a key.
 func (Windows) CloseKey(k Key) {
 	// The error is not actionable, so no point in reporting it
@@ -71,6 +83,36 @@ func (Windows) ReadValue(k Key, field string) (string, error) {
 	return "", errs
 }
 
+// ReadDWORD returns the value of the specified REG_DWORD field in the specified key.
+func (Windows) ReadDWORD(k Key, field string) (uint32, error) {
+	value, valtype, err := registry.Key(k).GetIntegerValue(field)
+	if err != nil {
+		return 0, convertReadError(err)
+	}
+	if valtype != registry.DWORD {
+		return 0, ErrUnexpectedType
+	}
+
+	return uint32(value), nil
+}
+
+// convertReadError converts the errors returned by the registry readers into cross-platform errors.
+func convertReadError(err error) error {
+	if errors.Is(err, registry.ErrNotExist) {
+		return ErrFieldNotExist
+	}
+	if errors.Is(err, registry.ErrUnexpectedType) {
+		return ErrUnexpectedType
+	}
+	return err
+}
+
+// ReadValueNames returns the names of all the fields in the specified key.
+func (Windows) ReadValueNames(k Key) ([]string, error) {
+	// A negative count means all of them.
+	return registry.Key(k).ReadValueNames(-1)
+}
+
 // WriteValue writes the value to the specified field in the specified key.
 func (Windows) WriteValue(k Key, field, value string, multiLine bool) error {
 	var err error
@@ -80,10 +122,24 @@ func (Windows) WriteValue(k Key, field, value string, multiLine bool) error {
 		err = registry.Key(k).SetStringValue(field, value)
 	}
 
+	return convertWriteError(err)
+}
+
+// DeleteValue removes the specified field from the specified key.
+func (Windows) DeleteValue(k Key, field string) error {
+	err := registry.Key(k).DeleteValue(field)
+	if errors.Is(err, registry.ErrNotExist) {
+		return ErrFieldNotExist
+	}
+	return convertWriteError(err)
+}
+
+// convertWriteError converts the errors returned by the registry writers into cross-platform errors.
+func convertWriteError(err error) error {
 	if errors.Is(err, registry.ErrNotExist) {
 		return ErrKeyNotExist
 	}
-	if errors.Is(err, syscall.Errno(5)) {
+	if errors.Is(err, syscall.Errno(5)) { // Access is denied
 		return ErrAccessDenied
 	}
 	return err
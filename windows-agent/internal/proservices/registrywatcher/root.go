@@ -0,0 +1,46 @@
+package registrywatcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+)
+
+// WithRoot returns the registry with the Ubuntu Pro key moved to root, a path under HK_CURRENT_USER
+// such as Software\Canonical\UbuntuProQA. It keeps the settings of QA and development installs apart
+// from the ones of the user. The root must be under the Software key, which is watched while the
+// key does not exist: any other root is an error.
+func WithRoot(r Registry, root string) (Registry, error) {
+	// Registry paths are case-insensitive.
+	if len(root) <= len(registryParentPath) || !strings.EqualFold(root[:len(registryParentPath)], registryParentPath) {
+		return nil, fmt.Errorf("registry root %q is not a key under %s", root, registryParentPath)
+	}
+
+	return rootedRegistry{Registry: r, root: root}, nil
+}
+
+// rootedRegistry opens the key at root whenever the Ubuntu Pro key is requested.
+type rootedRegistry struct {
+	Registry
+	root string
+}
+
+func (r rootedRegistry) HKCUOpenKey(path string) (registry.Key, error) {
+	return r.Registry.HKCUOpenKey(r.translate(path))
+}
+
+func (r rootedRegistry) HKCUCreateKey(path string) (registry.Key, error) {
+	return r.Registry.HKCUCreateKey(r.translate(path))
+}
+
+func (r rootedRegistry) HKCUOpenKeyWrite(path string) (registry.Key, error) {
+	return r.Registry.HKCUOpenKeyWrite(r.translate(path))
+}
+
+func (r rootedRegistry) translate(path string) string {
+	if path == registryPath {
+		return r.root
+	}
+	return path
+}
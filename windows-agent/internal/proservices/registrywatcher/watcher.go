@@ -5,11 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
 	"github.com/ubuntu/decorate"
 )
@@ -41,9 +44,13 @@ const registryParentPath = `Software\`
 type Registry interface {
 	HKCUOpenKey(path string) (registry.Key, error)
 	HKCUCreateKey(path string) (registry.Key, error)
+	HKCUOpenKeyWrite(path string) (registry.Key, error)
 	CloseKey(k registry.Key)
 	ReadValue(k registry.Key, field string) (value string, err error)
+	ReadDWORD(k registry.Key, field string) (value uint32, err error)
+	ReadValueNames(k registry.Key) (fields []string, err error)
 	WriteValue(k registry.Key, field, value string, multiline bool) (err error)
+	DeleteValue(k registry.Key, field string) (err error)
 
 	// Win32 stuff: not strictly registry but not worth separating out
 	RegNotifyChangeKeyValue(k registry.Key) (registry.Event, error)
@@ -230,24 +237,39 @@ func (s *Service) waitForSingleObject(ctx context.Context, event registry.Event)
 // This function is syntax sugar for Start, so we log the errors instead of having
 // the caller deal with them.
 func (s *Service) readThenPushRegistryData(ctx context.Context) {
-	data, err := loadRegistry(s.registry)
-	if err != nil {
+	if err := s.Refresh(ctx); err != nil {
 		log.Warningf(ctx, "Registry watcher: %v", err)
-		return
+	}
+}
+
+// Refresh reads the registry and pushes the read data to the config right away, without
+// waiting for a change to be detected.
+func (s *Service) Refresh(ctx context.Context) error {
+	data, err := loadRegistry(ctx, s.registry)
+	if err != nil {
+		return err
 	}
 
 	if err := s.conf.UpdateRegistryData(ctx, data, s.db); err != nil {
-		log.Warningf(ctx, "Registry watcher: could not push new registry data: %v", err)
+		return fmt.Errorf("could not push new registry data: %v", err)
 	}
+
+	return nil
 }
 
 //nolint:gosec // These are not credentials
 const (
-	ubuntuProTokenField  = "UbuntuProToken"
-	landscapeConfigField = "LandscapeConfig"
+	ubuntuProTokenField    = "UbuntuProToken"
+	landscapeConfigField   = "LandscapeConfig"
+	maintenanceWindowField = "MaintenanceWindow"
 )
 
-func loadRegistry(reg Registry) (data config.RegistryData, err error) {
+// ReadRegistry reads the contents of the Ubuntu Pro registry key once, without pushing them anywhere.
+func ReadRegistry(ctx context.Context, reg Registry) (config.RegistryData, error) {
+	return loadRegistry(ctx, reg)
+}
+
+func loadRegistry(ctx context.Context, reg Registry) (data config.RegistryData, err error) {
 	defer decorate.OnError(&err, "could not read registry")
 
 	k, err := reg.HKCUOpenKey(registryPath)
@@ -270,12 +292,88 @@ func loadRegistry(reg Registry) (data config.RegistryData, err error) {
 		return data, err
 	}
 
+	window, err := readFromRegistry(reg, k, maintenanceWindowField)
+	if err != nil {
+		return data, err
+	}
+
+	flags, err := readFeatureFlags(ctx, reg, k)
+	if err != nil {
+		return data, err
+	}
+
 	return config.RegistryData{
-		UbuntuProToken:  proToken,
-		LandscapeConfig: conf,
+		UbuntuProToken:    proToken,
+		LandscapeConfig:   conf,
+		ReadOnly:          isReadOnly(reg),
+		UnknownFields:     unknownFields(ctx, reg, k),
+		Features:          flags,
+		MaintenanceWindow: window,
 	}, nil
 }
 
+// readFeatureFlags returns the feature flags set in the registry as DWORD fields.
+// Any non-zero value enables the flag. Flags that are not set, or not set as a DWORD, are omitted.
+func readFeatureFlags(ctx context.Context, reg Registry, k registry.Key) (map[features.Flag]bool, error) {
+	flags := make(map[features.Flag]bool)
+
+	for _, f := range features.All() {
+		v, err := reg.ReadDWORD(k, f.RegistryField())
+		if errors.Is(err, registry.ErrFieldNotExist) {
+			continue
+		}
+		if errors.Is(err, registry.ErrUnexpectedType) {
+			// A mistyped flag must not prevent the rest of the settings from being applied.
+			log.Warningf(ctx, "Registry watcher: ignoring field %q: feature flags must be DWORD values", f.RegistryField())
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read field %q: %v", f.RegistryField(), err)
+		}
+
+		flags[f] = v != 0
+	}
+
+	return flags, nil
+}
+
+// unknownFields returns the names of the fields in the key that the agent does not use.
+// They are only reported to the user, so failing to list them does not prevent reading the registry.
+func unknownFields(ctx context.Context, reg Registry, k registry.Key) []string {
+	names, err := reg.ReadValueNames(k)
+	if err != nil {
+		log.Warningf(ctx, "Registry watcher: could not list the fields to find the unknown ones: %v", err)
+		return nil
+	}
+
+	known := []string{ubuntuProTokenField, landscapeConfigField, maintenanceWindowField}
+	for _, f := range features.All() {
+		known = append(known, f.RegistryField())
+	}
+
+	var unknown []string
+	for _, name := range names {
+		if slices.Contains(known, name) {
+			continue
+		}
+		unknown = append(unknown, name)
+	}
+
+	return unknown
+}
+
+// isReadOnly returns true if the agent is denied write access to the registry key.
+// A key that does not exist is not read-only: checking it must not create it.
+func isReadOnly(reg Registry) bool {
+	k, err := reg.HKCUOpenKeyWrite(registryPath)
+	if err != nil {
+		return errors.Is(err, registry.ErrAccessDenied)
+	}
+	reg.CloseKey(k)
+
+	return false
+}
+
 func readFromRegistry(r Registry, key registry.Key, field string) (string, error) {
 	value, err := r.ReadValue(key, field)
 	if errors.Is(err, registry.ErrFieldNotExist) {
@@ -305,6 +403,52 @@ func setDefaultRegistry(r Registry) (err error) {
 	return err
 }
 
+// ClearDefaults removes the values the agent created in the registry with empty contents, so that a reset
+// leaves the registry as it found it. The values the organization set are kept, as they are not the
+// agent's to clear.
+func (s *Service) ClearDefaults() (err error) {
+	defer decorate.OnError(&err, "could not clear default contents")
+
+	// The key is not created if it does not exist.
+	k, err := s.registry.HKCUOpenKeyWrite(registryPath)
+	if errors.Is(err, registry.ErrKeyNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf(`could not open registry key HKCU\%s with write permissions: %v`, registryPath, err)
+	}
+	defer s.registry.CloseKey(k)
+
+	for _, field := range []string{ubuntuProTokenField, landscapeConfigField} {
+		err = errors.Join(err, deleteIfEmpty(s.registry, k, field))
+	}
+
+	return err
+}
+
+func deleteIfEmpty(r Registry, k registry.Key, field string) (err error) {
+	defer decorate.OnError(&err, "could not clear field %q", field)
+
+	value, err := r.ReadValue(k, field)
+	if errors.Is(err, registry.ErrFieldNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read value: %v", err)
+	}
+
+	if strings.TrimSpace(value) != "" {
+		// The value was set by the organization, not by the agent.
+		return nil
+	}
+
+	if err := r.DeleteValue(k, field); err != nil && !errors.Is(err, registry.ErrFieldNotExist) {
+		return fmt.Errorf("could not delete value: %v", err)
+	}
+
+	return nil
+}
+
 func createIfNotExist(r Registry, k registry.Key, field string, multiline bool) (err error) {
 	defer decorate.OnError(&err, "could not initialize field %q", field)
 
@@ -9,8 +9,10 @@ import (
 
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/testutils"
 	"github.com/stretchr/testify/require"
 	wsl "github.com/ubuntu/gowsl"
 	wslmock "github.com/ubuntu/gowsl/mock"
@@ -31,6 +33,14 @@ func TestRegistryWatcher(t *testing.T) {
 
 	testCases := map[string]struct {
 		startEmptyRegistry        bool
+		readOnlyRegistry          bool
+		unknownField              bool
+		featureFlag               bool
+		mistypedFeatureFlag       bool
+		breakListValues           bool
+		slowRegistry              bool
+		deleteKeyMidRun           bool
+		breakWriteValue           bool
 		breakCreateKey            bool
 		breakOpenKey              bool
 		breakReadValue            bool
@@ -41,8 +51,16 @@ func TestRegistryWatcher(t *testing.T) {
 		wantCannotRead  bool
 	}{
 		"Success": {},
-		"Success with an empty starting registry":                      {startEmptyRegistry: true},
-		"Success with an empty starting registry and broken CreateKey": {startEmptyRegistry: true, breakCreateKey: true, wantKeyNotExist: true},
+		"Success with an empty starting registry":                       {startEmptyRegistry: true},
+		"Success with an empty starting registry and broken CreateKey":  {startEmptyRegistry: true, breakCreateKey: true, wantKeyNotExist: true},
+		"Success with a read-only registry":                             {readOnlyRegistry: true},
+		"Success with an unknown field in the registry":                 {unknownField: true},
+		"Success with a feature flag in the registry":                   {featureFlag: true},
+		"Success ignoring a feature flag that is not a DWORD":           {mistypedFeatureFlag: true},
+		"Success when the fields cannot be listed":                      {unknownField: true, breakListValues: true},
+		"Success with a slow registry":                                  {slowRegistry: true},
+		"Success when the key is deleted while watching":                {deleteKeyMidRun: true},
+		"Success with an empty starting registry and broken WriteValue": {startEmptyRegistry: true, breakWriteValue: true},
 
 		"Success after not being able to open keys":       {breakOpenKey: true, wantCannotRead: true},
 		"Success after not being able to read from keys":  {breakReadValue: true, wantCannotRead: true},
@@ -63,7 +81,7 @@ func TestRegistryWatcher(t *testing.T) {
 			db, err := database.New(ctx, t.TempDir(), nil)
 			require.NoError(t, err, "Setup: could not create empty DB")
 
-			reg := registry.NewMock()
+			reg := testutils.NewRegistryMock()
 			defer reg.RequireNoLeaks(t)
 
 			var startingProToken, startingLandscapeConfig string
@@ -81,6 +99,21 @@ func TestRegistryWatcher(t *testing.T) {
 
 					err = reg.WriteValue(k, "LandscapeConfig", startingLandscapeConfig, true)
 					require.NoError(t, err, "Setup: could not write LandscapeConfig into the registry")
+
+					if tc.featureFlag {
+						err = reg.WriteDWORD(k, "FeatureTelemetry", 1)
+						require.NoError(t, err, "Setup: could not write feature flag into the registry")
+					}
+
+					if tc.mistypedFeatureFlag {
+						err = reg.WriteValue(k, "FeatureTelemetry", "1", false)
+						require.NoError(t, err, "Setup: could not write mistyped feature flag into the registry")
+					}
+
+					if tc.unknownField {
+						err = reg.WriteValue(k, "UbuntuProTokn", "typo", false)
+						require.NoError(t, err, "Setup: could not write unknown field into the registry")
+					}
 				}()
 			}
 
@@ -90,9 +123,21 @@ func TestRegistryWatcher(t *testing.T) {
 			if tc.breakCreateKey {
 				reg.CannotCreate.Store(true)
 			}
+			if tc.readOnlyRegistry {
+				reg.ReadOnly.Store(true)
+			}
 			if tc.breakReadValue {
 				reg.CannotRead.Store(true)
 			}
+			if tc.breakListValues {
+				reg.CannotList.Store(true)
+			}
+			if tc.breakWriteValue {
+				reg.CannotWrite.Store(true)
+			}
+			if tc.slowRegistry {
+				reg.SetReadDelay(500 * time.Millisecond)
+			}
 			if tc.breakNotifyChangeKeyValue {
 				reg.CannotWatch.Store(true)
 			}
@@ -125,6 +170,21 @@ func TestRegistryWatcher(t *testing.T) {
 				require.Equal(t, wantMsgLen, conf.ReceivedLen(), "Registry watcher should have updated the config")
 				require.Equal(t, startingProToken, conf.LatestReceived().UbuntuProToken, "Ubuntu Pro token config should have contained the registry value")
 				require.Equal(t, startingLandscapeConfig, conf.LatestReceived().LandscapeConfig, "Landscape config should have contained the registry value")
+				require.Equal(t, tc.readOnlyRegistry, conf.LatestReceived().ReadOnly, "Registry read-only status should have matched the registry permissions")
+
+				var wantUnknown []string
+				if tc.unknownField && !tc.breakListValues {
+					wantUnknown = []string{"UbuntuProTokn"}
+				}
+				require.Equal(t, wantUnknown, conf.LatestReceived().UnknownFields, "Unknown registry fields should have been reported")
+
+				wantFeatures := map[features.Flag]bool{}
+				if tc.featureFlag {
+					wantFeatures[features.Telemetry] = true
+				}
+				if !tc.startEmptyRegistry {
+					require.Equal(t, wantFeatures, conf.LatestReceived().Features, "Feature flags should have matched the registry")
+				}
 			}
 
 			// The watcher makes a redundant config push when it starts watching, except if readValue was broken.
@@ -136,6 +196,18 @@ func TestRegistryWatcher(t *testing.T) {
 				require.Equal(t, startingLandscapeConfig, conf.LatestReceived().LandscapeConfig, "Landscape config should have contained the registry value")
 			}
 
+			if tc.deleteKeyMidRun {
+				wantMsgLen = conf.ReceivedLen() + 1
+				reg.DeleteUbuntuProKey()
+
+				require.Eventually(t, func() bool { return conf.ReceivedLen() >= wantMsgLen },
+					maxUpdateTime, 100*time.Millisecond, "Registry watcher should have updated the config after deleting the key")
+				require.Empty(t, conf.LatestReceived().UbuntuProToken, "Ubuntu Pro token config should be empty after deleting the key")
+				require.Empty(t, conf.LatestReceived().LandscapeConfig, "Landscape config should be empty after deleting the key")
+
+				startingLandscapeConfig = ""
+			}
+
 			wantMsgLen = conf.ReceivedLen() + 1
 
 			if tc.breakCreateKey {
@@ -143,6 +215,13 @@ func TestRegistryWatcher(t *testing.T) {
 				// We need to do this because we need to pretend a user changed the registry.
 				reg.CannotCreate.Store(false)
 			}
+			if tc.readOnlyRegistry {
+				// Same as above: the registry is only read-only for the agent.
+				reg.ReadOnly.Store(false)
+			}
+			if tc.breakWriteValue {
+				reg.CannotWrite.Store(false)
+			}
 
 			k, err := reg.HKCUCreateKey("Software/Canonical/UbuntuPro")
 			require.NoError(t, err, "Setup: could not create key")
@@ -169,6 +248,157 @@ func TestRegistryWatcher(t *testing.T) {
 	}
 }
 
+func TestReadRegistryReadOnly(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		keyExists        bool
+		readOnlyRegistry bool
+
+		wantReadOnly bool
+	}{
+		"Success with a writable key":         {keyExists: true},
+		"Success with a read-only key":        {keyExists: true, readOnlyRegistry: true, wantReadOnly: true},
+		"Success when the key does not exist": {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reg := testutils.NewRegistryMock()
+			defer reg.RequireNoLeaks(t)
+
+			if tc.keyExists {
+				k, err := reg.HKCUCreateKey(`Software\Canonical\UbuntuPro`)
+				require.NoError(t, err, "Setup: could not create the key")
+				reg.CloseKey(k)
+			}
+			reg.ReadOnly.Store(tc.readOnlyRegistry)
+
+			data, err := registrywatcher.ReadRegistry(context.Background(), reg)
+			require.NoError(t, err, "ReadRegistry should return no error")
+			require.Equal(t, tc.wantReadOnly, data.ReadOnly, "ReadRegistry should have reported whether the key is read-only")
+			require.Equal(t, tc.keyExists, reg.UbuntuProKeyExists(), "ReadRegistry should not create the key")
+		})
+	}
+}
+
+func TestWithRoot(t *testing.T) {
+	t.Parallel()
+
+	const root = `Software\Canonical\UbuntuProQA`
+
+	mock := testutils.NewRegistryMock()
+	defer mock.RequireNoLeaks(t)
+
+	// The settings of the user are left untouched.
+	k, err := mock.HKCUCreateKey(`Software\Canonical\UbuntuPro`)
+	require.NoError(t, err, "Setup: could not create the key of the user")
+	err = mock.WriteValue(k, "UbuntuProToken", "UserToken", false)
+	mock.CloseKey(k)
+	require.NoError(t, err, "Setup: could not write the token of the user")
+
+	reg := &movedRegistry{RegistryMock: mock, from: root}
+	rooted, err := registrywatcher.WithRoot(reg, root)
+	require.NoError(t, err, "WithRoot should accept a key under the Software key")
+
+	data, err := registrywatcher.ReadRegistry(context.Background(), rooted)
+	require.NoError(t, err, "ReadRegistry should return no error")
+	require.Equal(t, "UserToken", data.UbuntuProToken, "ReadRegistry should read the key at the root")
+	require.False(t, data.ReadOnly, "ReadRegistry should check the write access to the key at the root")
+	for _, path := range reg.opened {
+		require.Equal(t, root, path, "Only the key at the root should have been opened")
+	}
+}
+
+func TestWithRootRejectsKeysOutsideSoftware(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"Error when the root is not under the Software key": `System\Canonical\UbuntuPro`,
+		"Error when the root only shares a prefix":          `SoftwareCanonical\UbuntuPro`,
+		"Error when the root is the Software key itself":    `Software\`,
+	}
+
+	for name, root := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := registrywatcher.WithRoot(testutils.NewRegistryMock(), root)
+			require.Error(t, err, "WithRoot should reject the root %q", root)
+		})
+	}
+}
+
+func TestClearDefaults(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noKey      bool
+		orgToken   string
+		cannotOpen bool
+		readOnly   bool
+
+		wantErr bool
+	}{
+		"Success removing the values created by the agent":   {},
+		"Success keeping the values set by the organization": {orgToken: "OrgToken"},
+		"Success when the key does not exist":                {noKey: true},
+
+		"Error when the key cannot be opened":     {cannotOpen: true, wantErr: true},
+		"Error when the values cannot be removed": {readOnly: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reg := testutils.NewRegistryMock()
+			defer reg.RequireNoLeaks(t)
+
+			if !tc.noKey {
+				k, err := reg.HKCUCreateKey(`Software\Canonical\UbuntuPro`)
+				require.NoError(t, err, "Setup: could not create key")
+				require.NoError(t, reg.WriteValue(k, "UbuntuProToken", tc.orgToken, false), "Setup: could not write UbuntuProToken")
+				require.NoError(t, reg.WriteValue(k, "LandscapeConfig", "", true), "Setup: could not write LandscapeConfig")
+				reg.CloseKey(k)
+			}
+
+			reg.CannotOpen.Store(tc.cannotOpen)
+			reg.ReadOnly.Store(tc.readOnly)
+
+			w := registrywatcher.New(context.Background(), &mockConfig{}, nil, registrywatcher.WithRegistry(reg))
+			err := w.ClearDefaults()
+			if tc.wantErr {
+				require.Error(t, err, "ClearDefaults should return an error")
+				return
+			}
+			require.NoError(t, err, "ClearDefaults should return no error")
+
+			if tc.noKey {
+				require.False(t, reg.UbuntuProKeyExists(), "ClearDefaults should not create the key")
+				return
+			}
+
+			k, err := reg.HKCUOpenKey(`Software\Canonical\UbuntuPro`)
+			require.NoError(t, err, "Setup: could not open key")
+			defer reg.CloseKey(k)
+
+			_, err = reg.ReadValue(k, "LandscapeConfig")
+			require.ErrorIs(t, err, registry.ErrFieldNotExist, "ClearDefaults should remove the empty LandscapeConfig")
+
+			token, err := reg.ReadValue(k, "UbuntuProToken")
+			if tc.orgToken == "" {
+				require.ErrorIs(t, err, registry.ErrFieldNotExist, "ClearDefaults should remove the empty UbuntuProToken")
+				return
+			}
+			require.NoError(t, err, "ClearDefaults should keep the UbuntuProToken set by the organization")
+			require.Equal(t, tc.orgToken, token, "ClearDefaults should not change the UbuntuProToken set by the organization")
+		})
+	}
+}
+
 type mockConfig struct {
 	err      bool
 	received []config.RegistryData
@@ -209,3 +439,36 @@ func (conf *mockConfig) LatestReceived() config.RegistryData {
 
 	return conf.received[len(conf.received)-1]
 }
+
+// movedRegistry is a registry mock where the Ubuntu Pro key lives at another path. It records the
+// paths opened.
+type movedRegistry struct {
+	*testutils.RegistryMock
+	from string
+
+	opened []string
+}
+
+func (r *movedRegistry) HKCUOpenKey(path string) (registry.Key, error) {
+	r.opened = append(r.opened, path)
+	if path != r.from {
+		return 0, registry.ErrKeyNotExist
+	}
+	return r.RegistryMock.HKCUOpenKey(`Software\Canonical\UbuntuPro`)
+}
+
+func (r *movedRegistry) HKCUCreateKey(path string) (registry.Key, error) {
+	r.opened = append(r.opened, path)
+	if path != r.from {
+		return 0, registry.ErrAccessDenied
+	}
+	return r.RegistryMock.HKCUCreateKey(`Software\Canonical\UbuntuPro`)
+}
+
+func (r *movedRegistry) HKCUOpenKeyWrite(path string) (registry.Key, error) {
+	r.opened = append(r.opened, path)
+	if path != r.from {
+		return 0, registry.ErrAccessDenied
+	}
+	return r.RegistryMock.HKCUOpenKeyWrite(`Software\Canonical\UbuntuPro`)
+}
@@ -0,0 +1,111 @@
+package proservices
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher"
+	"github.com/sirupsen/logrus"
+)
+
+// Names of the settings reported when the configuration is reloaded.
+const (
+	settingFeatureFlags = "feature-flags"
+	settingLogLevel     = "log-level"
+	settingPaused       = "paused"
+	settingPorts        = "ports"
+	settingProxy        = "proxy"
+	settingTelemetry    = "telemetry"
+)
+
+// reloader applies the configuration to the running agent on request of the UI service.
+type reloader struct {
+	conf     *config.Config
+	registry *registrywatcher.Service
+	pauser   *pause.Switch
+
+	// baseLogLevel is the log level the agent was started with. It applies when the config file sets none.
+	baseLogLevel logrus.Level
+
+	// explicitLogLevel is true if the base log level was set explicitly, so that the config file cannot override it.
+	explicitLogLevel bool
+}
+
+// ReloadConfig reads the config file and the registry again, and applies the settings that can change while the
+// agent runs. It reports what happened to every setting, including those that require a restart.
+//
+// Most settings are read every time they are used, so reading the config file again is enough for them
+// to take effect. Only the ones cached by the running services need to be pushed to them.
+func (r reloader) ReloadConfig(ctx context.Context) ([]config.ReloadedSetting, error) {
+	log.Info(ctx, "Reloading the configuration")
+
+	if err := r.conf.Reload(); err != nil {
+		return nil, err
+	}
+
+	// The registry watcher pushes the changes as they happen, but a failed watch may have missed some.
+	var registryErr error
+	if err := r.registry.Refresh(ctx); err != nil {
+		registryErr = fmt.Errorf("the registry could not be read, the organization settings may be outdated: %v", err)
+	}
+
+	settings := []config.ReloadedSetting{
+		// The features gated by the flags are set up when the agent starts.
+		{Name: settingFeatureFlags, Err: registryErr, RestartRequired: true},
+		{Name: settingLogLevel, Err: r.applyLogLevel(ctx)},
+		{Name: settingPaused, Err: r.applyPaused(ctx)},
+		// The daemon picks its ports when it starts listening.
+		{Name: settingPorts, RestartRequired: true},
+		// The proxy is read from the environment the first time a connection is made.
+		{Name: settingProxy, RestartRequired: true},
+		// The trace exporter is set up when the services start.
+		{Name: settingTelemetry, RestartRequired: true},
+	}
+
+	for _, s := range settings {
+		if s.Err != nil {
+			log.Warningf(ctx, "Reloading the configuration: %s: %v", s.Name, s.Err)
+		}
+	}
+
+	return settings, nil
+}
+
+// applyLogLevel sets the log level in the config file, or restores the one the agent was started with if none is set
+// or if it was set explicitly.
+func (r reloader) applyLogLevel(ctx context.Context) error {
+	name, err := r.conf.LogLevel()
+	if err != nil {
+		return err
+	}
+
+	level := r.baseLogLevel
+	if name != "" && r.explicitLogLevel {
+		log.Debugf(ctx, "Log level %s in the config file ignored: the verbosity was set on the command line or in the environment", name)
+	} else if name != "" {
+		if level, err = logrus.ParseLevel(name); err != nil {
+			return err
+		}
+	}
+
+	if logrus.GetLevel() != level {
+		logrus.SetLevel(level)
+		log.Infof(ctx, "Log level set to %s", level)
+	}
+
+	return nil
+}
+
+// applyPaused pauses or resumes the agent as set in the config file.
+func (r reloader) applyPaused(ctx context.Context) error {
+	paused, err := r.conf.Paused()
+	if err != nil {
+		return err
+	}
+
+	setPaused(ctx, r.pauser, paused)
+	return nil
+}
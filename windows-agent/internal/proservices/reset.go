@@ -0,0 +1,178 @@
+package proservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/tasks"
+)
+
+// resetPollInterval is how often a reset checks whether the distros are detached.
+const resetPollInterval = time.Second
+
+// resetSignal is closed once a reset of the agent is requested.
+type resetSignal struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+func newResetSignal() *resetSignal {
+	return &resetSignal{ch: make(chan struct{})}
+}
+
+func (r *resetSignal) fire() {
+	r.once.Do(func() { close(r.ch) })
+}
+
+// landscapeStopper disconnects the host from Landscape.
+type landscapeStopper interface {
+	Stop(ctx context.Context)
+}
+
+// registryCleaner removes the values the agent created in the registry.
+type registryCleaner interface {
+	ClearDefaults() error
+}
+
+// taskPauser tells whether the tasks of every distro are held back, such as while the agent is paused.
+type taskPauser interface {
+	Paused() bool
+}
+
+// taskSchedule tells whether the tasks that are not urgent can run now.
+type taskSchedule interface {
+	TaskWindow(now time.Time) (open bool, next time.Duration)
+}
+
+// resetter resets the agent on request of the UI service.
+type resetter struct {
+	db        *database.DistroDB
+	landscape landscapeStopper
+	registry  registryCleaner
+	signal    *resetSignal
+
+	// pauser and schedule hold back the tasks of the distros. They may be nil.
+	pauser   taskPauser
+	schedule taskSchedule
+
+	// detachTimeout is how long the distros have to be detached.
+	detachTimeout time.Duration
+}
+
+// ResetAgent detaches every distro from Ubuntu Pro and Landscape, then requests the agent to start over:
+// whoever runs the services must stop them, wipe the state directory and create them again once
+// ResetRequested is closed.
+//
+// If some distros cannot be detached in time, the reset is abandoned and they are listed in the error, so
+// that no distro is left attached to a subscription the agent forgot about. So are the distros whose tasks
+// are held back, by a pause, the schedule or their failures, as they would not be detached. Otherwise, the host is
+// disconnected from Landscape and the values the agent created in the registry are removed before the
+// state is wiped. The values the organization set in the registry are kept, as they are not the agent's
+// to clear: they apply again after the reset.
+func (r resetter) ResetAgent(ctx context.Context) error {
+	log.Info(ctx, "Resetting the agent: detaching every distro")
+
+	var err error
+	for _, d := range r.db.GetAll() {
+		err = errors.Join(err, d.SubmitTasks(tasks.ProAttachment{}, tasks.LandscapeConfigure{}))
+	}
+	if err != nil {
+		return fmt.Errorf("could not detach distros: %v", err)
+	}
+
+	detachCtx, cancel := context.WithTimeout(ctx, r.detachTimeout)
+	defer cancel()
+
+	r.db.Provision(detachCtx, nil)
+	pending, held := r.waitDetached(detachCtx)
+
+	var reasons []string
+	if len(pending) > 0 {
+		reasons = append(reasons, fmt.Sprintf("could not detach distros %s in time", strings.Join(pending, ", ")))
+	}
+	if len(held) > 0 {
+		reasons = append(reasons, fmt.Sprintf("the tasks of distros %s are held back", strings.Join(held, ", ")))
+	}
+	if len(reasons) > 0 {
+		return fmt.Errorf("%s: the agent was not reset", strings.Join(reasons, "; "))
+	}
+
+	log.Info(ctx, "Resetting the agent: disconnecting from Landscape")
+	r.landscape.Stop(ctx)
+
+	if err := r.registry.ClearDefaults(); err != nil {
+		log.Warningf(ctx, "Resetting the agent: %v", err)
+	}
+
+	log.Info(ctx, "Resetting the agent: restarting with a clean state")
+	r.signal.fire()
+
+	return nil
+}
+
+// waitDetached waits until no distro has pending tasks, or the context is done. It returns the names
+// of the distros whose tasks are still pending, and the ones whose tasks are held back with the reason
+// why. The latter are not waited for, as their tasks would not run before the context is done.
+func (r resetter) waitDetached(ctx context.Context) (pending, held []string) {
+	for {
+		pending, held = pending[:0], held[:0]
+		for _, d := range r.db.GetAll() {
+			if d.PendingTasks() == 0 {
+				continue
+			}
+
+			if reason := r.heldBack(d); reason != "" {
+				held = append(held, fmt.Sprintf("%s (%s)", d.Name(), reason))
+				continue
+			}
+
+			pending = append(pending, d.Name())
+		}
+
+		if len(pending) == 0 {
+			slices.Sort(held)
+			return nil, held
+		}
+
+		select {
+		case <-ctx.Done():
+			slices.Sort(pending)
+			slices.Sort(held)
+			return pending, held
+		case <-time.After(resetPollInterval):
+		}
+	}
+}
+
+// heldBack returns why the tasks of the distro are held back, or an empty string if they are not.
+func (r resetter) heldBack(d *distro.Distro) string {
+	if r.pauser != nil && r.pauser.Paused() {
+		return "the agent is paused or WSL is unavailable"
+	}
+
+	if d.Circuit().Open {
+		return "too many of its tasks failed in a row"
+	}
+
+	// Detaching from Ubuntu Pro is urgent, but detaching from Landscape is not.
+	if r.schedule != nil {
+		if open, _ := r.schedule.TaskWindow(time.Now()); !open {
+			return "outside of the maintenance window, or while the host is on battery or on a metered connection"
+		}
+	}
+
+	return ""
+}
+
+// ResetRequested is closed once a reset of the agent is requested.
+func (m Manager) ResetRequested() <-chan struct{} {
+	return m.reset.ch
+}
@@ -2,27 +2,154 @@
 package ui
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/buildinfo"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/approvals"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/wslconf"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/notifications"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/onboarding"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/preflight"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/backup"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/operations"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/uiauth"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/ubuntupro"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/ubuntupro/contracts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslconfig"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslwatch"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
 	"github.com/ubuntu/decorate"
+	wsl "github.com/ubuntu/gowsl"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Config is a provider for the subscription configuration.
 type Config interface {
 	SetUserSubscription(ctx context.Context, token string) error
+	SetDistroSubscription(ctx context.Context, distroName, token string) error
 	SetStoreSubscription(ctx context.Context, token string) error
+	StoreEntitlement() (config.StoreEntitlement, error)
+	SetStoreEntitlement(e config.StoreEntitlement) error
 	Subscription() (string, config.Source, error)
+	SubscriptionDetails() (config.SubscriptionDetails, error)
 	SetUserLandscapeConfig(ctx context.Context, token string) error
 	LandscapeClientConfig() (string, config.Source, error)
+	Validate(ctx context.Context) ([]config.Problem, error)
+	FeatureFlags() ([]config.FeatureFlag, error)
+	SetLandscapeDistroOverride(ctx context.Context, distroName string, o config.LandscapeOverride) error
+	LandscapeDistroOverrides() (map[string]config.LandscapeOverride, error)
+	SetLandscapeEndpoint(ctx context.Context, name, conf string) error
+	SetBackupSchedule(b config.BackupSchedule) error
+	BackupSchedule() (config.BackupSchedule, error)
+	SetCompactSchedule(c config.CompactSchedule) error
+	CompactSchedule() (config.CompactSchedule, error)
+	SetWSLSettings(distroName string, settings config.WSLSettings) error
+	ManagedMode() (config.ManagedMode, error)
+	SetPaused(ctx context.Context, paused bool) error
+	Paused() (bool, error)
+	SetNotificationEnabled(category string, enabled bool) error
+	NotificationEnabled(category string) (bool, error)
+	SetApprovalRequired(category string, required bool) error
+	ApprovalRequired(category string) (bool, error)
+}
+
+// Exporter exports distros to tarballs.
+type Exporter interface {
+	Export(ctx context.Context, distroName, destination string, progress backup.ProgressFunc) (string, error)
+}
+
+// Compactor compacts the virtual disks of the distros, and manages their sparse mode.
+type Compactor interface {
+	Compact(ctx context.Context, distroName string) (before, after uint64, err error)
+	SetSparse(ctx context.Context, distroName string, sparse bool) error
+}
+
+// WSLConfigFile reads and updates the global WSL settings of the user.
+type WSLConfigFile interface {
+	Settings() (wslconfig.Settings, error)
+	SetSettings(wslconfig.Settings) error
+	RestartRequired() bool
+}
+
+// WSLUpdater updates WSL itself.
+type WSLUpdater interface {
+	Update(ctx context.Context) error
+}
+
+// Preflight reports the problems with WSL found when the agent started.
+type Preflight interface {
+	Problems() []preflight.Problem
+}
+
+// WSLWatcher tells whether the agent can use WSL right now.
+type WSLWatcher interface {
+	Status() wslwatch.Status
+}
+
+// Operations runs long-running operations in the background and keeps track of them.
+type Operations interface {
+	Start(kind, distroName string, f operations.Func) (operations.Operation, error)
+	Get(id string) (operations.Operation, error)
+	Cancel(id string) (operations.Operation, error)
+	List() []operations.Operation
+}
+
+// Confirmer asks the user to approve destructive calls and hands out the codes that confirm them.
+type Confirmer interface {
+	RequestConfirmation(ctx context.Context, method string) (code string, expires time.Time, err error)
+}
+
+// Resetter detaches every distro and starts the agent over with a clean state.
+type Resetter interface {
+	ResetAgent(ctx context.Context) error
+}
+
+// Reloader reads the configuration again and applies it to the running agent.
+type Reloader interface {
+	ReloadConfig(ctx context.Context) ([]config.ReloadedSetting, error)
+}
+
+// Approvals holds back the operations the user must approve before they run.
+type Approvals interface {
+	Pending() []approvals.Request
+	Approve(id string) error
+	Deny(id string) error
+}
+
+// Onboarding tracks how far the user went through the initial setup.
+type Onboarding interface {
+	Status() (onboarding.Status, error)
+	SkipLandscape() error
+}
+
+// TaskPlugins creates the tasks of the types declared by plugins.
+type TaskPlugins interface {
+	Task(distroName, taskType string, payload []byte) (task.Task, error)
 }
 
 // Service it the UI GRPC service implementation.
@@ -33,9 +160,61 @@ type Service struct {
 	// contractsArgs allows for overriding the contract server's behaviour.
 	contractsArgs []contracts.Option
 
+	// timeouts is how long the service waits for the distros. Unset timeouts keep their default.
+	timeouts timeouts.Policy
+
+	// exporter is nil until SetExporter is called.
+	exporter Exporter
+
+	// plugins is nil until SetTaskPlugins is called.
+	plugins TaskPlugins
+
+	// compactor is nil until SetCompactor is called.
+	compactor Compactor
+
+	// wslConfig is nil until SetWSLConfigFile is called.
+	wslConfig WSLConfigFile
+
+	// wslUpdater is nil until SetWSLUpdater is called.
+	wslUpdater WSLUpdater
+
+	// preflight is nil until SetPreflight is called.
+	preflight Preflight
+
+	// wslWatcher is nil until SetWSLWatcher is called.
+	wslWatcher WSLWatcher
+
+	// operations is nil until SetOperations is called.
+	operations Operations
+
+	// confirmer is nil until SetConfirmer is called.
+	confirmer Confirmer
+
+	// onboarding is nil until SetOnboarding is called.
+	onboarding Onboarding
+
+	// resetter is nil until SetResetter is called.
+	resetter Resetter
+
+	// approvals is nil until SetApprovals is called.
+	approvals Approvals
+
+	// reloader is nil until SetReloader is called.
+	reloader Reloader
+
+	// shutdown is shared by the copies of the service, so that StopWatches reaches every stream.
+	shutdown *shutdownSignal
+
 	agentapi.UnimplementedUIServer
 }
 
+// shutdownSignal tells the streams watching the distros to end, as they only end once the UI stops
+// watching otherwise.
+type shutdownSignal struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
 // New returns a new service handling the UI API.
 func New(ctx context.Context, config Config, db *database.DistroDB, args ...contracts.Option) (s Service) {
 	log.Debug(ctx, "Building gRPC UI service")
@@ -44,9 +223,87 @@ func New(ctx context.Context, config Config, db *database.DistroDB, args ...cont
 		db:            db,
 		config:        config,
 		contractsArgs: args,
+		shutdown:      &shutdownSignal{ch: make(chan struct{})},
 	}
 }
 
+// StopWatches ends the streams watching the distros, and the ones that start afterwards, so that the
+// daemon can stop gracefully.
+func (s *Service) StopWatches(ctx context.Context) {
+	log.Info(ctx, "UI service: ending the streams watching the distros")
+	s.shutdown.once.Do(func() { close(s.shutdown.ch) })
+}
+
+// SetTimeouts sets how long the service waits for the distros.
+func (s *Service) SetTimeouts(policy timeouts.Policy) {
+	s.timeouts = policy
+}
+
+// SetExporter sets the exporter used to export distros on request.
+func (s *Service) SetExporter(e Exporter) {
+	s.exporter = e
+}
+
+// SetTaskPlugins sets the plugins whose tasks can be submitted on request.
+func (s *Service) SetTaskPlugins(p TaskPlugins) {
+	s.plugins = p
+}
+
+// SetCompactor sets the compactor used to change the virtual disks of the distros on request.
+func (s *Service) SetCompactor(c Compactor) {
+	s.compactor = c
+}
+
+// SetWSLConfigFile sets the file used to read and update the global WSL settings on request.
+func (s *Service) SetWSLConfigFile(f WSLConfigFile) {
+	s.wslConfig = f
+}
+
+// SetWSLUpdater sets the updater used to update WSL on request.
+func (s *Service) SetWSLUpdater(u WSLUpdater) {
+	s.wslUpdater = u
+}
+
+// SetPreflight sets the checker whose problems are reported in the fleet status.
+func (s *Service) SetPreflight(p Preflight) {
+	s.preflight = p
+}
+
+// SetWSLWatcher sets the watcher whose status is reported in the fleet status.
+func (s *Service) SetWSLWatcher(w WSLWatcher) {
+	s.wslWatcher = w
+}
+
+// SetOperations sets the manager used to run long-running operations on request.
+func (s *Service) SetOperations(o Operations) {
+	s.operations = o
+}
+
+// SetConfirmer sets the confirmer that hands out the codes that confirm destructive calls.
+func (s *Service) SetConfirmer(c Confirmer) {
+	s.confirmer = c
+}
+
+// SetOnboarding sets the tracker that reports how far the user went through the initial setup.
+func (s *Service) SetOnboarding(o Onboarding) {
+	s.onboarding = o
+}
+
+// SetResetter sets the resetter used to start the agent over on request.
+func (s *Service) SetResetter(r Resetter) {
+	s.resetter = r
+}
+
+// SetApprovals sets the tracker of the operations waiting for the approval of the user.
+func (s *Service) SetApprovals(a Approvals) {
+	s.approvals = a
+}
+
+// SetReloader sets the reloader used to apply the configuration without restarting the agent.
+func (s *Service) SetReloader(r Reloader) {
+	s.reloader = r
+}
+
 // ApplyProToken handles the gRPC call to pro attach all distros using a token provided by the GUI.
 func (s *Service) ApplyProToken(ctx context.Context, info *agentapi.ProAttachInfo) (_ *agentapi.SubscriptionInfo, err error) {
 	defer decorate.LogOnError(err)
@@ -55,7 +312,10 @@ func (s *Service) ApplyProToken(ctx context.Context, info *agentapi.ProAttachInf
 	token := info.GetToken()
 	log.Infof(ctx, "UI service: received token %s", common.Obfuscate(token))
 
-	if err := s.config.SetUserSubscription(ctx, token); err != nil {
+	if err := s.config.SetUserSubscription(ctx, token); errors.As(err, &config.ManagedError{}) {
+		// The GUI tells this error apart to explain why the subscription cannot be changed.
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -72,12 +332,32 @@ func (s *Service) ApplyProToken(ctx context.Context, info *agentapi.ProAttachInf
 	return subs, nil
 }
 
+// ApplyDistroProToken handles the gRPC call to assign an Ubuntu Pro token to a single distro.
+// Every distro is then attached again with its own token.
+func (s *Service) ApplyDistroProToken(ctx context.Context, msg *agentapi.DistroProToken) (_ *agentapi.Empty, err error) {
+	defer decorate.LogOnError(err)
+	defer decorate.OnError(&err, "UI service: ApplyDistroProToken")
+
+	log.Infof(ctx, "UI service: received token %s for distro %q", common.Obfuscate(msg.GetToken()), msg.GetDistroName())
+
+	if err := s.config.SetDistroSubscription(ctx, msg.GetDistroName(), msg.GetToken()); errors.As(err, &config.ManagedError{}) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
 // ApplyLandscapeConfig handles the gRPC call to set landscape configuration.
 func (s *Service) ApplyLandscapeConfig(ctx context.Context, landscapeConfig *agentapi.LandscapeConfig) (*agentapi.LandscapeSource, error) {
 	c := landscapeConfig.GetConfig()
 
 	err := s.config.SetUserLandscapeConfig(ctx, c)
-	if err != nil {
+	if errors.As(err, &config.ManagedError{}) {
+		// The GUI tells this error apart to explain why the configuration cannot be changed.
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -122,67 +402,1729 @@ func (s *Service) GetConfigSources(ctx context.Context, empty *agentapi.Empty) (
 	return src, nil
 }
 
-func (s *Service) getSubscriptionSource() (*agentapi.SubscriptionInfo, error) {
-	info := &agentapi.SubscriptionInfo{}
+// GetSubscriptionDetails handles the gRPC call to explain where the active subscription comes from,
+// and which other subscription sources are being overridden.
+func (s *Service) GetSubscriptionDetails(ctx context.Context, empty *agentapi.Empty) (*agentapi.SubscriptionDetails, error) {
+	log.Info(ctx, "UI service: received GetSubscriptionDetails message")
 
-	_, source, err := s.config.Subscription()
+	details, err := s.config.SubscriptionDetails()
 	if err != nil {
+		err = fmt.Errorf("UI service: GetSubscriptionDetails: %v", err)
+		log.Warningf(ctx, "%v", err)
 		return nil, err
 	}
 
-	switch source {
-	case config.SourceNone:
-		info.SubscriptionType = &agentapi.SubscriptionInfo_None{}
-	case config.SourceUser:
-		info.SubscriptionType = &agentapi.SubscriptionInfo_User{}
-	case config.SourceRegistry:
-		info.SubscriptionType = &agentapi.SubscriptionInfo_Organization{}
-	case config.SourceMicrosoftStore:
-		info.SubscriptionType = &agentapi.SubscriptionInfo_MicrosoftStore{}
-	default:
-		return nil, fmt.Errorf("unrecognized subscription source: %d", source)
+	active, err := subscriptionInfo(details.Active)
+	if err != nil {
+		err = fmt.Errorf("UI service: GetSubscriptionDetails: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
 	}
 
-	return info, nil
+	resp := &agentapi.SubscriptionDetails{
+		Active:           active,
+		RegistryReadOnly: details.RegistryReadOnly,
+	}
+
+	for _, src := range details.Sources {
+		info, err := subscriptionInfo(src.Source)
+		if err != nil {
+			err = fmt.Errorf("UI service: GetSubscriptionDetails: %v", err)
+			log.Warningf(ctx, "%v", err)
+			return nil, err
+		}
+
+		var modified string
+		if !src.Modified.IsZero() {
+			modified = src.Modified.UTC().Format(time.RFC3339)
+		}
+
+		resp.Sources = append(resp.Sources, &agentapi.SubscriptionSourceDetails{
+			Source:       info,
+			HasToken:     src.HasToken,
+			Shadowed:     src.Shadowed,
+			LastModified: modified,
+		})
+	}
+
+	log.Debugf(ctx, "UI service: responding GetSubscriptionDetails with %v", resp)
+	return resp, nil
 }
 
-func (s *Service) getLandscapeConfigSource() (*agentapi.LandscapeSource, error) {
-	src := &agentapi.LandscapeSource{}
+// ValidateConfig handles the gRPC call to report any problems found in the configuration.
+func (s *Service) ValidateConfig(ctx context.Context, empty *agentapi.Empty) (*agentapi.ConfigProblems, error) {
+	log.Info(ctx, "UI service: received ValidateConfig message")
 
-	_, source, err := s.config.LandscapeClientConfig()
+	problems, err := s.config.Validate(ctx)
 	if err != nil {
+		err = fmt.Errorf("UI service: ValidateConfig: %v", err)
+		log.Warningf(ctx, "%v", err)
 		return nil, err
 	}
 
-	switch source {
-	case config.SourceNone:
-		src.LandscapeSourceType = &agentapi.LandscapeSource_None{}
-	case config.SourceUser:
-		src.LandscapeSourceType = &agentapi.LandscapeSource_User{}
-	case config.SourceRegistry:
-		src.LandscapeSourceType = &agentapi.LandscapeSource_Organization{}
-	default:
-		return nil, fmt.Errorf("unrecognized Landscape source: %d", source)
+	resp := &agentapi.ConfigProblems{}
+	for _, p := range problems {
+		resp.Problems = append(resp.Problems, &agentapi.ConfigProblem{
+			IsError: p.Severity == config.SeverityError,
+			Field:   p.Field,
+			Message: p.Message,
+		})
 	}
 
-	return src, nil
+	log.Debugf(ctx, "UI service: responding ValidateConfig with %d problems", len(resp.GetProblems()))
+	return resp, nil
 }
 
-// NotifyPurchase handles the client notification of a successful purchase through MS Store.
-func (s *Service) NotifyPurchase(ctx context.Context, empty *agentapi.Empty) (info *agentapi.SubscriptionInfo, errs error) {
-	log.Info(ctx, "UI service: received NotifyPurchase message")
+// GetFeatureFlags handles the gRPC call to report the state of every feature flag.
+func (s *Service) GetFeatureFlags(ctx context.Context, empty *agentapi.Empty) (*agentapi.FeatureFlags, error) {
+	log.Info(ctx, "UI service: received GetFeatureFlags message")
 
-	if err := ubuntupro.FetchFromMicrosoftStore(ctx, s.config, s.db, s.contractsArgs...); err != nil {
-		log.Warningf(ctx, "UI service: NotifyPurchase: %v", err)
-		errs = errors.Join(errs, err)
+	flags, err := s.config.FeatureFlags()
+	if err != nil {
+		err = fmt.Errorf("UI service: GetFeatureFlags: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
 	}
 
-	info, err := s.getSubscriptionSource()
+	resp := &agentapi.FeatureFlags{}
+	for _, f := range flags {
+		resp.Flags = append(resp.Flags, &agentapi.FeatureFlag{
+			Name:         string(f.Flag),
+			Enabled:      f.Enabled,
+			IsDefault:    f.Source == config.SourceNone,
+			Organization: f.Source == config.SourceRegistry,
+		})
+	}
+
+	log.Debugf(ctx, "UI service: responding GetFeatureFlags with %v", resp)
+	return resp, nil
+}
+
+// ApplyLandscapeDistroOverride handles the gRPC call to set the Landscape settings that apply to a single distro.
+func (s *Service) ApplyLandscapeDistroOverride(ctx context.Context, msg *agentapi.LandscapeDistroOverride) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received ApplyLandscapeDistroOverride message for distro %q", msg.GetDistroName())
+
+	o := config.LandscapeOverride{
+		Tags:          msg.GetTags(),
+		ComputerTitle: msg.GetComputerTitle(),
+		ScriptUsers:   msg.GetScriptUsers(),
+		Endpoint:      msg.GetEndpoint(),
+	}
+
+	if err := s.config.SetLandscapeDistroOverride(ctx, msg.GetDistroName(), o); err != nil {
+		err = fmt.Errorf("UI service: ApplyLandscapeDistroOverride: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// ApplyDistroWSLSettings handles the gRPC call to set the default user and the wsl.conf options
+// of a distro. The settings are stored so that they are applied again whenever the distro is provisioned.
+func (s *Service) ApplyDistroWSLSettings(ctx context.Context, msg *agentapi.DistroWSLSettings) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received ApplyDistroWSLSettings message for distro %q", msg.GetDistroName())
+
+	settings := config.WSLSettings{
+		DefaultUser: msg.GetDefaultUser(),
+	}
+
+	if msg.GetInterop() != nil {
+		settings.Interop = &msg.GetInterop().Enabled
+	}
+
+	if msg.GetAutomount() != nil {
+		settings.Automount = &msg.GetAutomount().Enabled
+	}
+
+	if msg.GetSystemd() != nil {
+		settings.Systemd = &msg.GetSystemd().Enabled
+	}
+
+	if err := s.config.SetWSLSettings(msg.GetDistroName(), settings); err != nil {
+		err = fmt.Errorf("UI service: ApplyDistroWSLSettings: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	// Distros that are not managed yet will get the settings when they are provisioned.
+	d, ok := s.db.Get(msg.GetDistroName())
+	if !ok {
+		// They may not be managed because they boot without systemd, which the WSL-Pro-Service needs to run:
+		// it must be enabled from Windows for them to be provisioned at all.
+		if settings.Systemd == nil || !*settings.Systemd {
+			return &agentapi.Empty{}, nil
+		}
+
+		err := wslcall.Command(ctx, fmt.Sprintf("enabling systemd in distro %q", msg.GetDistroName()), func(ctx context.Context) error {
+			return wslconf.EnableSystemd(ctx, msg.GetDistroName())
+		})
+		if err != nil {
+			err = fmt.Errorf("UI service: ApplyDistroWSLSettings: %v", err)
+			log.Warningf(ctx, "%v", err)
+			return nil, err
+		}
+
+		return &agentapi.Empty{}, nil
+	}
+
+	if err := d.SubmitTasks(settings.Task()); err != nil {
+		err = fmt.Errorf("UI service: ApplyDistroWSLSettings: could not submit settings to distro: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// GetFleetStatus handles the gRPC call to return the status of every distro managed by the agent,
+// sorted by distro name.
+func (s *Service) GetFleetStatus(ctx context.Context, empty *agentapi.Empty) (*agentapi.FleetStatus, error) {
+	log.Info(ctx, "UI service: received GetFleetStatus message")
+
+	mode, err := s.config.ManagedMode()
 	if err != nil {
-		log.Warningf(ctx, "UI service: NotifyPurchase: %v", err)
-		errs = errors.Join(errs, err)
+		err = fmt.Errorf("UI service: GetFleetStatus: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
 	}
 
-	log.Debugf(ctx, "UI service: responding NotifyPurchase with info: %v", info)
-	return info, errs
+	distros := s.db.GetAll()
+	slices.SortFunc(distros, func(a, b *distro.Distro) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	resp := &agentapi.FleetStatus{
+		ManagedMode: &agentapi.ManagedMode{
+			RegistryReadOnly: mode.RegistryReadOnly,
+			Subscription:     mode.Subscription,
+			Landscape:        mode.Landscape,
+		},
+	}
+
+	for _, d := range distros {
+		props := d.Properties()
+
+		var lastContact string
+		if t := d.LastContact(); !t.IsZero() {
+			lastContact = t.UTC().Format(time.RFC3339)
+		}
+
+		security := d.SecurityStatus()
+		var securityChecked string
+		if !security.Checked.IsZero() {
+			securityChecked = security.Checked.UTC().Format(time.RFC3339)
+		}
+
+		updates := d.UpdateStatus()
+
+		resources := d.ResourceUsage()
+		var resourcesChecked string
+		if !resources.Checked.IsZero() {
+			resourcesChecked = resources.Checked.UTC().Format(time.RFC3339)
+		}
+
+		// Distros that are no longer valid are not connected.
+		connected, _ := d.IsActive()
+
+		circuit := d.Circuit()
+		var lastTaskError, nextRetry string
+		if circuit.LastError != nil {
+			lastTaskError = circuit.LastError.Error()
+		}
+		if circuit.Open {
+			nextRetry = circuit.RetryAt.UTC().Format(time.RFC3339)
+		}
+
+		awakeOnDemand, until := d.AwakeOnDemand()
+		var awakeUntil string
+		if !until.IsZero() {
+			awakeUntil = until.UTC().Format(time.RFC3339)
+		}
+
+		var labels []*agentapi.Label
+		for k, v := range d.Labels() {
+			labels = append(labels, &agentapi.Label{Key: k, Value: v})
+		}
+		slices.SortFunc(labels, func(a, b *agentapi.Label) int { return strings.Compare(a.GetKey(), b.GetKey()) })
+
+		resp.Distros = append(resp.Distros, &agentapi.DistroStatus{
+			Name:                 d.Name(),
+			ProAttached:          props.ProAttached,
+			ProServices:          props.ProServices,
+			LastContact:          lastContact,
+			PendingTasks:         uint32(d.PendingTasks()),
+			LandscapeRegistered:  props.LandscapeRegistered,
+			Connected:            connected,
+			SecurityUpdates:      security.SecurityUpdates,
+			EsmUpdates:           security.ESMUpdates,
+			SecurityChecked:      securityChecked,
+			MemoryBytes:          resources.MemoryBytes,
+			CpuPercent:           resources.CPUPercent,
+			DiskBytes:            resources.DiskBytes,
+			ResourcesChecked:     resourcesChecked,
+			NeedsAttention:       circuit.Open,
+			LastTaskError:        lastTaskError,
+			NextRetry:            nextRetry,
+			RebootRequired:       updates.RebootRequired,
+			RebootPackages:       updates.RebootPackages,
+			PendingUpdates:       updates.PendingUpdates,
+			AwakeOnDemand:        awakeOnDemand,
+			AwakeUntil:           awakeUntil,
+			Labels:               labels,
+			ServiceVersion:       props.ServiceVersion,
+			LandscapeScriptUsers: props.LandscapeScriptUsers,
+		})
+
+		resp.SecurityUpdates += security.SecurityUpdates
+		resp.EsmUpdates += security.ESMUpdates
+	}
+
+	if s.preflight != nil {
+		for _, p := range s.preflight.Problems() {
+			resp.PreflightProblems = append(resp.PreflightProblems, &agentapi.PreflightProblem{
+				IsError:     p.Severity == config.SeverityError,
+				Check:       p.Check,
+				Message:     p.Message,
+				Action:      string(p.Action),
+				Remediation: p.Remediation,
+			})
+		}
+	}
+
+	if s.wslWatcher != nil {
+		status := s.wslWatcher.Status()
+		resp.WslAvailability = &agentapi.WSLAvailability{Available: status.Available}
+		if !status.Available {
+			resp.WslAvailability.Since = status.Since.UTC().Format(time.RFC3339)
+			resp.WslAvailability.Error = status.Err.Error()
+		}
+	}
+
+	log.Debugf(ctx, "UI service: responding GetFleetStatus with %v", resp)
+	return resp, nil
+}
+
+// WatchDistros handles the gRPC call to stream the changes made to the distros managed by the agent,
+// until the UI stops watching or the agent shuts down.
+func (s *Service) WatchDistros(empty *agentapi.Empty, stream agentapi.UI_WatchDistrosServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	log.Info(ctx, "UI service: received WatchDistros message")
+
+	events, err := s.db.Watch(ctx)
+	if err != nil {
+		err = fmt.Errorf("UI service: WatchDistros: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return err
+	}
+
+	for {
+		select {
+		case <-s.shutdown.ch:
+			return status.Error(codes.Unavailable, "UI service: WatchDistros: the agent is shutting down")
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			err := stream.Send(&agentapi.DistroChange{DistroName: e.Name, Change: e.Kind.String()})
+			if err != nil {
+				err = fmt.Errorf("UI service: WatchDistros: could not send change of distro %q: %v", e.Name, err)
+				log.Warningf(ctx, "%v", err)
+				return err
+			}
+		}
+	}
+}
+
+// GetLandscapeDistroOverrides handles the gRPC call to return the Landscape settings of every distro that overrides them.
+func (s *Service) GetLandscapeDistroOverrides(ctx context.Context, empty *agentapi.Empty) (*agentapi.LandscapeDistroOverrides, error) {
+	log.Info(ctx, "UI service: received GetLandscapeDistroOverrides message")
+
+	overrides, err := s.config.LandscapeDistroOverrides()
+	if err != nil {
+		err = fmt.Errorf("UI service: GetLandscapeDistroOverrides: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	resp := &agentapi.LandscapeDistroOverrides{}
+	for _, name := range names {
+		o := overrides[name]
+		resp.Overrides = append(resp.Overrides, &agentapi.LandscapeDistroOverride{
+			DistroName:    name,
+			Tags:          o.Tags,
+			ComputerTitle: o.ComputerTitle,
+			ScriptUsers:   o.ScriptUsers,
+			Endpoint:      o.Endpoint,
+		})
+	}
+
+	log.Debugf(ctx, "UI service: responding GetLandscapeDistroOverrides with %v", resp)
+	return resp, nil
+}
+
+// ExportDistro handles the gRPC call to export a distro to a tarball. The progress of the export is
+// streamed back, and the last message contains the path to the tarball.
+func (s *Service) ExportDistro(req *agentapi.ExportRequest, stream agentapi.UI_ExportDistroServer) error {
+	ctx := stream.Context()
+	log.Infof(ctx, "UI service: received ExportDistro message for distro %q", req.GetDistroName())
+
+	if s.exporter == nil {
+		err := errors.New("UI service: ExportDistro: exporting distros is not available")
+		log.Warningf(ctx, "%v", err)
+		return err
+	}
+
+	var size int64
+	path, err := s.exporter.Export(ctx, req.GetDistroName(), req.GetDestination(), func(written int64) {
+		size = written
+
+		// Progress is informative only: failing to report it does not stop the export.
+		if err := stream.Send(&agentapi.ExportProgress{BytesWritten: uint64(written)}); err != nil {
+			log.Warningf(ctx, "UI service: ExportDistro: could not report progress: %v", err)
+		}
+	})
+	if err != nil {
+		err = fmt.Errorf("UI service: ExportDistro: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return err
+	}
+
+	return stream.Send(&agentapi.ExportProgress{BytesWritten: uint64(size), Done: true, Path: path})
+}
+
+// SetBackupSchedule handles the gRPC call to set the schedule for periodic distro backups.
+func (s *Service) SetBackupSchedule(ctx context.Context, msg *agentapi.BackupSchedule) (*agentapi.Empty, error) {
+	log.Info(ctx, "UI service: received SetBackupSchedule message")
+
+	b := config.BackupSchedule{
+		Interval:    time.Duration(msg.GetIntervalHours()) * time.Hour,
+		Destination: msg.GetDestination(),
+		Keep:        int(msg.GetKeep()),
+	}
+
+	if err := s.config.SetBackupSchedule(b); err != nil {
+		err = fmt.Errorf("UI service: SetBackupSchedule: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// ResetDistro handles the gRPC call to discard the agent state of a distro and provision it again.
+// If a rootfs is provided, the distro is unregistered and imported again from it first.
+func (s *Service) ResetDistro(ctx context.Context, req *agentapi.ResetRequest) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received ResetDistro message for distro %q", req.GetDistroName())
+
+	if err := s.resetDistro(ctx, req); err != nil {
+		err = fmt.Errorf("UI service: ResetDistro: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// ResetAgent handles the gRPC call to start the agent over: every distro is detached from Ubuntu Pro and
+// Landscape, then the agent restarts with a clean state.
+func (s *Service) ResetAgent(ctx context.Context, empty *agentapi.Empty) (*agentapi.Empty, error) {
+	log.Info(ctx, "UI service: received ResetAgent message")
+
+	if s.resetter == nil {
+		err := errors.New("UI service: ResetAgent: resetting the agent is not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := s.resetter.ResetAgent(ctx); err != nil {
+		err = fmt.Errorf("UI service: ResetAgent: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// ReloadConfig handles the gRPC call to read the configuration again and apply it without restarting the agent.
+// The settings that cannot change while the agent runs are reported as requiring a restart.
+func (s *Service) ReloadConfig(ctx context.Context, empty *agentapi.Empty) (*agentapi.ReloadResult, error) {
+	log.Info(ctx, "UI service: received ReloadConfig message")
+
+	if s.reloader == nil {
+		err := errors.New("UI service: ReloadConfig: reloading the configuration is not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	settings, err := s.reloader.ReloadConfig(ctx)
+	if err != nil {
+		err = fmt.Errorf("UI service: ReloadConfig: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	resp := &agentapi.ReloadResult{}
+	for _, setting := range settings {
+		var errMsg string
+		if setting.Err != nil {
+			errMsg = setting.Err.Error()
+		}
+
+		resp.Settings = append(resp.Settings, &agentapi.ReloadedSetting{
+			Name:            setting.Name,
+			RestartRequired: setting.RestartRequired,
+			Error:           errMsg,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *Service) resetDistro(ctx context.Context, req *agentapi.ResetRequest) error {
+	name := req.GetDistroName()
+
+	if _, ok := s.db.Get(name); !ok {
+		return fmt.Errorf("distro %q is not managed by the agent", name)
+	}
+
+	if rootfs := req.GetRootfs(); rootfs != "" {
+		if err := reimport(ctx, name, rootfs); err != nil {
+			return err
+		}
+	}
+
+	return s.db.Reset(ctx, name, req.GetKeepProperties())
+}
+
+// reimport unregisters the distro and registers it again from the rootfs tarball.
+func reimport(ctx context.Context, name, rootfs string) (err error) {
+	defer decorate.OnError(&err, "could not re-import distro %q", name)
+
+	// Checking the tarball beforehand avoids unregistering a distro that cannot be imported again.
+	if _, err := os.Stat(rootfs); err != nil {
+		return fmt.Errorf("could not access rootfs: %v", err)
+	}
+
+	d := wsl.NewDistro(ctx, name)
+	if err := wslcall.Command(ctx, fmt.Sprintf("unregistering distro %q", name), func(context.Context) error { return d.Unregister() }); err != nil {
+		return err
+	}
+
+	return wslcall.Command(ctx, fmt.Sprintf("registering distro %q", name), func(context.Context) error { return d.Register(rootfs) })
+}
+
+// defaultLogLines is how many journal entries of a distro are fetched when the client does not say.
+const defaultLogLines = 200
+
+// GetDistroLogs handles the gRPC call to fetch the most recent logs of the WSL Pro service of a distro,
+// so that they can be viewed without opening a shell in it. The distro is started if needed.
+func (s *Service) GetDistroLogs(ctx context.Context, req *agentapi.DistroLogsRequest) (*agentapi.DistroLogs, error) {
+	log.Infof(ctx, "UI service: received GetDistroLogs message for distro %q", req.GetDistroName())
+
+	logs, err := s.distroLogs(ctx, req)
+	if err != nil {
+		// The status code tells the GUI whether the distro is missing or its service is too old.
+		err = status.Errorf(status.Code(err), "UI service: GetDistroLogs: %s", status.Convert(err).Message())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (s *Service) distroLogs(ctx context.Context, req *agentapi.DistroLogsRequest) (*agentapi.DistroLogs, error) {
+	d, ok := s.db.Get(req.GetDistroName())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "distro %q is not managed by the agent", req.GetDistroName())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeouts.OrDefault().DistroLogs)
+	defer cancel()
+
+	// The distro is kept awake until its logs are fetched.
+	client, release, err := awakeClient(ctx, d, "GetDistroLogs")
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	lines := req.GetLines()
+	if lines == 0 {
+		lines = defaultLogLines
+	}
+
+	stream, err := client.StreamJournal(ctx, &wslserviceapi.JournalRequest{Lines: lines})
+	if err != nil {
+		return nil, fmt.Errorf("could not request the logs: %v", err)
+	}
+
+	logs := &agentapi.DistroLogs{}
+	for {
+		e, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if status.Code(err) == codes.Unimplemented {
+			return nil, status.Error(codes.FailedPrecondition, "the WSL Pro service of the distro is too old to send its logs")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not receive the logs: %v", err)
+		}
+
+		logs.Entries = append(logs.Entries, &agentapi.LogEntry{
+			Time:     e.GetTime(),
+			Priority: e.GetPriority(),
+			Message:  common.Redact(e.GetMessage()),
+		})
+	}
+
+	return logs, nil
+}
+
+// awakeClient returns the client to the WSL Pro service of the distro, starting the distro if it is not running.
+// The release function must be called once the client is no longer needed, so that the distro can stop again.
+func awakeClient(ctx context.Context, d *distro.Distro, call string) (client wslserviceapi.WSLClient, release func(), err error) {
+	client, err = d.Client()
+	if err != nil {
+		return nil, nil, err
+	}
+	if client != nil {
+		return client, func() {}, nil
+	}
+
+	if err := d.LockAwake(); err != nil {
+		return nil, nil, fmt.Errorf("could not start distro: %v", err)
+	}
+	release = func() {
+		if err := d.ReleaseAwake(); err != nil {
+			log.Warningf(ctx, "UI service: %s: could not release distro %q: %v", call, d.Name(), err)
+		}
+	}
+
+	if client, err = waitForClient(ctx, d); err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	return client, release, nil
+}
+
+// waitForClient waits for the WSL Pro service of the distro to connect to the agent, until the context is done.
+func waitForClient(ctx context.Context, d *distro.Distro) (wslserviceapi.WSLClient, error) {
+	for {
+		client, err := d.Client()
+		if err != nil {
+			return nil, err
+		}
+		if client != nil {
+			return client, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("the WSL Pro service of the distro did not connect: %v", ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// distroFileTimeout is how long copying a file out of a distro can take, including starting it.
+const distroFileTimeout = 10 * time.Minute
+
+// distroFileAttempts is how many times copying a file out of a distro is attempted, resuming from
+// where the previous attempt was interrupted.
+const distroFileAttempts = 3
+
+// errChecksumMismatch is returned when a file copied out of a distro does not match its checksum.
+var errChecksumMismatch = errors.New("the file copied does not match its checksum")
+
+// PullDistroFile handles the gRPC call to copy a file out of a distro, such as a log or a report, so that
+// it can be collected without opening a shell in it. The distro is started if needed.
+func (s *Service) PullDistroFile(ctx context.Context, req *agentapi.DistroFileRequest) (*agentapi.DistroFile, error) {
+	log.Infof(ctx, "UI service: received PullDistroFile message for %s in distro %q", req.GetPath(), req.GetDistroName())
+
+	f, err := s.pullDistroFile(ctx, req)
+	if err != nil {
+		// The status code tells the GUI whether the distro or the file is missing, or the service is too old.
+		err = status.Errorf(status.Code(err), "UI service: PullDistroFile: %s", status.Convert(err).Message())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (s *Service) pullDistroFile(ctx context.Context, req *agentapi.DistroFileRequest) (*agentapi.DistroFile, error) {
+	dest := req.GetDestination()
+	if !filepath.IsAbs(dest) {
+		return nil, status.Errorf(codes.InvalidArgument, "destination %q must be an absolute path", dest)
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "destination %q already exists", dest)
+	}
+
+	d, ok := s.db.Get(req.GetDistroName())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "distro %q is not managed by the agent", req.GetDistroName())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, distroFileTimeout)
+	defer cancel()
+
+	// The distro is kept awake until the file is copied.
+	client, release, err := awakeClient(ctx, d, "PullDistroFile")
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// What was received is kept next to the destination, so that an interrupted copy can be resumed.
+	partial := dest + ".part"
+
+	for attempt := 1; ; attempt++ {
+		header, err := receiveDistroFile(ctx, client, req.GetPath(), partial)
+		if status.Code(err) == codes.Unimplemented {
+			return nil, status.Error(codes.FailedPrecondition, "the WSL Pro service of the distro is too old to send files")
+		}
+
+		// Only interrupted transfers and files that changed while they were copied are worth another attempt.
+		retry := status.Code(err) == codes.Unavailable || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, errChecksumMismatch)
+		if err != nil && retry && attempt < distroFileAttempts && ctx.Err() == nil {
+			log.Warningf(ctx, "UI service: PullDistroFile: attempt %d failed, resuming: %v", attempt, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Files such as logs and reports are meant to be shared, so the secrets they contain are redacted.
+		redacted, err := redactFile(partial, dest)
+		if err != nil {
+			return nil, fmt.Errorf("could not move the file to its destination: %v", err)
+		}
+
+		return &agentapi.DistroFile{
+			Destination: dest,
+			Size:        header.GetSize(),
+			Sha256:      header.GetSha256(),
+			Redacted:    redacted,
+		}, nil
+	}
+}
+
+// receiveDistroFile copies a file out of the distro into the partial file, resuming after what the partial file has.
+// Once the whole file is received, it is verified against its checksum.
+func receiveDistroFile(ctx context.Context, client wslserviceapi.WSLClient, path, partial string) (header *wslserviceapi.FileHeader, err error) {
+	f, err := os.OpenFile(partial, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open partial file: %v", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("could not stat partial file: %v", err)
+	}
+	offset := uint64(info.Size())
+
+	stream, err := client.PullFile(ctx, &wslserviceapi.FileRequest{Path: path, Offset: offset})
+	if err != nil {
+		return nil, fmt.Errorf("could not request the file: %w", err)
+	}
+
+	first, err := stream.Recv()
+	if status.Code(err) == codes.OutOfRange {
+		// The file may have shrunk since the partial file was written: it is copied again from the start.
+		_ = os.Remove(partial)
+		return nil, fmt.Errorf("%w: could not resume at offset %d: %v", errChecksumMismatch, offset, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	header = first.GetHeader()
+	if header == nil {
+		return nil, errors.New("the first message was not the header of the file")
+	}
+
+	received := offset
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := f.Write(chunk.GetData())
+		received += uint64(n)
+		if err != nil {
+			return nil, fmt.Errorf("could not write partial file: %v", err)
+		}
+	}
+
+	if received < header.GetSize() {
+		return nil, fmt.Errorf("received %d of the %d bytes of the file: %w", received, header.GetSize(), io.ErrUnexpectedEOF)
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("could not close partial file: %v", err)
+	}
+
+	if err := verifyChecksum(partial, header.GetSha256()); err != nil {
+		// Resuming would only keep the corrupted data.
+		_ = os.Remove(partial)
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// binarySniffSize is how many bytes at the start of a file are looked for a NUL byte to tell whether it is binary.
+const binarySniffSize = 8000
+
+// redactFile moves the file at src to dst, hiding the secrets found in it on the way. Binary files, such as
+// archives, are moved untouched, as they cannot be redacted without corrupting them. It returns true if any
+// secret was redacted.
+func redactFile(src, dst string) (redacted bool, err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return false, err
+	}
+	defer in.Close()
+
+	r := bufio.NewReader(in)
+	head, err := r.Peek(binarySniffSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	if bytes.IndexByte(head, 0) != -1 {
+		// The file must be closed before it can be moved on Windows.
+		in.Close()
+		return false, os.Rename(src, dst)
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(dst)
+		}
+	}()
+	defer out.Close()
+
+	// Secrets never span several lines, so the file is redacted line by line to handle files of any size.
+	for {
+		line, readErr := r.ReadString('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return false, readErr
+		}
+
+		clean := common.Redact(line)
+		if clean != line {
+			redacted = true
+		}
+		if _, err := out.WriteString(clean); err != nil {
+			return false, err
+		}
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return false, err
+	}
+
+	in.Close()
+	return redacted, os.Remove(src)
+}
+
+// verifyChecksum returns errChecksumMismatch if the SHA-256 checksum of the file is not the expected one.
+func verifyChecksum(path, want string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open file: %v", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("could not compute checksum: %v", err)
+	}
+
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("%w: got %s, expected %s", errChecksumMismatch, got, want)
+	}
+
+	return nil
+}
+
+// GetDistroTasks handles the gRPC call to list the tasks waiting to be executed in a distro.
+func (s *Service) GetDistroTasks(ctx context.Context, req *agentapi.DistroTasksRequest) (*agentapi.DistroTasks, error) {
+	log.Infof(ctx, "UI service: received GetDistroTasks message for distro %q", req.GetDistroName())
+
+	d, ok := s.db.Get(req.GetDistroName())
+	if !ok {
+		err := status.Errorf(codes.NotFound, "UI service: GetDistroTasks: distro %q is not managed by the agent", req.GetDistroName())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	queued, err := d.QueuedTasks()
+	if err != nil {
+		err = fmt.Errorf("UI service: GetDistroTasks: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	resp := &agentapi.DistroTasks{}
+	for _, q := range queued {
+		resp.Tasks = append(resp.Tasks, &agentapi.QueuedTask{
+			Id:        q.ID,
+			Task:      q.Task,
+			Deferred:  q.Deferred,
+			Submitted: q.Submitted.Format(time.RFC3339),
+			Attempts:  uint32(q.Attempts),
+		})
+	}
+
+	return resp, nil
+}
+
+// CancelDistroTask handles the gRPC call to cancel a task waiting to be executed in a distro. The
+// tasks that depend on it are cancelled too.
+func (s *Service) CancelDistroTask(ctx context.Context, req *agentapi.CancelTaskRequest) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received CancelDistroTask message for task %q of distro %q", req.GetId(), req.GetDistroName())
+
+	d, ok := s.db.Get(req.GetDistroName())
+	if !ok {
+		err := status.Errorf(codes.NotFound, "UI service: CancelDistroTask: distro %q is not managed by the agent", req.GetDistroName())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := d.CancelTask(ctx, req.GetId()); err != nil {
+		code := codes.Unknown
+		if errors.Is(err, worker.ErrTaskNotQueued) {
+			// The task may have started or finished in the meantime.
+			code = codes.NotFound
+		}
+		err = status.Errorf(code, "UI service: CancelDistroTask: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// GetBackupSchedule handles the gRPC call to return the schedule for periodic distro backups.
+func (s *Service) GetBackupSchedule(ctx context.Context, empty *agentapi.Empty) (*agentapi.BackupSchedule, error) {
+	log.Info(ctx, "UI service: received GetBackupSchedule message")
+
+	b, err := s.config.BackupSchedule()
+	if err != nil {
+		err = fmt.Errorf("UI service: GetBackupSchedule: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	resp := &agentapi.BackupSchedule{
+		IntervalHours: uint32(b.Interval / time.Hour),
+		Destination:   b.Destination,
+		Keep:          uint32(b.Keep),
+	}
+
+	log.Debugf(ctx, "UI service: responding GetBackupSchedule with %v", resp)
+	return resp, nil
+}
+
+// SetPauseState handles the gRPC call to pause or resume the automatic behaviour of the agent.
+func (s *Service) SetPauseState(ctx context.Context, msg *agentapi.PauseState) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received SetPauseState message (paused: %t)", msg.GetPaused())
+
+	if err := s.config.SetPaused(ctx, msg.GetPaused()); err != nil {
+		err = fmt.Errorf("UI service: SetPauseState: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// GetPauseState handles the gRPC call to return whether the automatic behaviour of the agent is paused.
+func (s *Service) GetPauseState(ctx context.Context, empty *agentapi.Empty) (*agentapi.PauseState, error) {
+	log.Info(ctx, "UI service: received GetPauseState message")
+
+	paused, err := s.config.Paused()
+	if err != nil {
+		err = fmt.Errorf("UI service: GetPauseState: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.PauseState{Paused: paused}, nil
+}
+
+// GetOnboardingState handles the gRPC call to report how far the user went through the initial setup,
+// so that the GUI can resume it where it was left off.
+func (s *Service) GetOnboardingState(ctx context.Context, empty *agentapi.Empty) (*agentapi.OnboardingState, error) {
+	log.Info(ctx, "UI service: received GetOnboardingState message")
+
+	resp, err := s.onboardingState()
+	if err != nil {
+		err = fmt.Errorf("UI service: GetOnboardingState: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	log.Debugf(ctx, "UI service: responding GetOnboardingState with step %s", resp.GetStep())
+	return resp, nil
+}
+
+// SkipLandscapeOnboarding handles the gRPC call to move past the Landscape step of the initial setup
+// without configuring it.
+func (s *Service) SkipLandscapeOnboarding(ctx context.Context, empty *agentapi.Empty) (*agentapi.OnboardingState, error) {
+	log.Info(ctx, "UI service: received SkipLandscapeOnboarding message")
+
+	if s.onboarding == nil {
+		err := errors.New("UI service: SkipLandscapeOnboarding: onboarding is not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := s.onboarding.SkipLandscape(); err != nil {
+		err = fmt.Errorf("UI service: SkipLandscapeOnboarding: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	resp, err := s.onboardingState()
+	if err != nil {
+		err = fmt.Errorf("UI service: SkipLandscapeOnboarding: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *Service) onboardingState() (*agentapi.OnboardingState, error) {
+	if s.onboarding == nil {
+		return nil, errors.New("onboarding is not available")
+	}
+
+	status, err := s.onboarding.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	return &agentapi.OnboardingState{
+		Step:                 string(status.Step),
+		SubscriptionSelected: status.SubscriptionSelected,
+		LandscapeConfigured:  status.LandscapeConfigured,
+		LandscapeSkipped:     status.LandscapeSkipped,
+		DistroProvisioned:    status.DistroProvisioned,
+	}, nil
+}
+
+// SetNotificationPreference handles the gRPC call to opt in or out of a category of notifications.
+func (s *Service) SetNotificationPreference(ctx context.Context, msg *agentapi.NotificationPreference) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received SetNotificationPreference message (%s: %t)", msg.GetCategory(), msg.GetEnabled())
+
+	if !slices.Contains(notifications.Categories, notifications.Category(msg.GetCategory())) {
+		err := fmt.Errorf("UI service: SetNotificationPreference: unknown notification category %q", msg.GetCategory())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := s.config.SetNotificationEnabled(msg.GetCategory(), msg.GetEnabled()); err != nil {
+		err = fmt.Errorf("UI service: SetNotificationPreference: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// GetNotificationPreferences handles the gRPC call to return which categories of notifications are enabled.
+func (s *Service) GetNotificationPreferences(ctx context.Context, empty *agentapi.Empty) (*agentapi.NotificationPreferences, error) {
+	log.Info(ctx, "UI service: received GetNotificationPreferences message")
+
+	resp := &agentapi.NotificationPreferences{}
+	for _, category := range notifications.Categories {
+		enabled, err := s.config.NotificationEnabled(string(category))
+		if err != nil {
+			err = fmt.Errorf("UI service: GetNotificationPreferences: %v", err)
+			log.Warningf(ctx, "%v", err)
+			return nil, err
+		}
+
+		resp.Preferences = append(resp.Preferences, &agentapi.NotificationPreference{
+			Category: string(category),
+			Enabled:  enabled,
+		})
+	}
+
+	return resp, nil
+}
+
+// SetApprovalPreference handles the gRPC call to require or not the approval of the user for a category of operations.
+func (s *Service) SetApprovalPreference(ctx context.Context, msg *agentapi.ApprovalPreference) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received SetApprovalPreference message (%s: %t)", msg.GetCategory(), msg.GetRequired())
+
+	if !slices.Contains(approvals.Categories, approvals.Category(msg.GetCategory())) {
+		err := fmt.Errorf("UI service: SetApprovalPreference: unknown approval category %q", msg.GetCategory())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := s.config.SetApprovalRequired(msg.GetCategory(), msg.GetRequired()); err != nil {
+		err = fmt.Errorf("UI service: SetApprovalPreference: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// GetApprovalPreferences handles the gRPC call to return which categories of operations require the approval of the user.
+func (s *Service) GetApprovalPreferences(ctx context.Context, empty *agentapi.Empty) (*agentapi.ApprovalPreferences, error) {
+	log.Info(ctx, "UI service: received GetApprovalPreferences message")
+
+	resp := &agentapi.ApprovalPreferences{}
+	for _, category := range approvals.Categories {
+		required, err := s.config.ApprovalRequired(string(category))
+		if err != nil {
+			err = fmt.Errorf("UI service: GetApprovalPreferences: %v", err)
+			log.Warningf(ctx, "%v", err)
+			return nil, err
+		}
+
+		resp.Preferences = append(resp.Preferences, &agentapi.ApprovalPreference{
+			Category: string(category),
+			Required: required,
+		})
+	}
+
+	return resp, nil
+}
+
+// GetPendingApprovals handles the gRPC call to return the operations waiting for the approval of the user.
+func (s *Service) GetPendingApprovals(ctx context.Context, empty *agentapi.Empty) (*agentapi.PendingApprovals, error) {
+	log.Info(ctx, "UI service: received GetPendingApprovals message")
+
+	resp := &agentapi.PendingApprovals{}
+	if s.approvals == nil {
+		return resp, nil
+	}
+
+	for _, r := range s.approvals.Pending() {
+		resp.Approvals = append(resp.Approvals, &agentapi.PendingApproval{
+			Id:          r.ID,
+			Category:    string(r.Category),
+			Description: r.Description,
+			Requested:   r.Requested.Format(time.RFC3339),
+			Expires:     r.Expires.Format(time.RFC3339),
+		})
+	}
+
+	return resp, nil
+}
+
+// ApproveTask handles the gRPC call to let a pending operation run.
+func (s *Service) ApproveTask(ctx context.Context, msg *agentapi.ApprovalDecision) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received ApproveTask message for %q", msg.GetId())
+
+	if err := s.decide(msg.GetId(), true); err != nil {
+		err = status.Errorf(approvalCode(err), "UI service: ApproveTask: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// DenyTask handles the gRPC call to keep a pending operation from running.
+func (s *Service) DenyTask(ctx context.Context, msg *agentapi.ApprovalDecision) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received DenyTask message for %q", msg.GetId())
+
+	if err := s.decide(msg.GetId(), false); err != nil {
+		err = status.Errorf(approvalCode(err), "UI service: DenyTask: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+func (s *Service) decide(id string, approved bool) error {
+	if s.approvals == nil {
+		return fmt.Errorf("%w: %q", approvals.ErrNotFound, id)
+	}
+
+	if approved {
+		return s.approvals.Approve(id)
+	}
+	return s.approvals.Deny(id)
+}
+
+// approvalCode returns the gRPC code of an error approving or denying an operation.
+func approvalCode(err error) codes.Code {
+	if errors.Is(err, approvals.ErrNotFound) {
+		// The operation may have expired in the meantime.
+		return codes.NotFound
+	}
+	return codes.Unknown
+}
+
+// ApplyLandscapeEndpoint handles the gRPC call to add, modify or remove a Landscape endpoint other than the main one.
+func (s *Service) ApplyLandscapeEndpoint(ctx context.Context, msg *agentapi.LandscapeEndpoint) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received ApplyLandscapeEndpoint message for endpoint %q", msg.GetName())
+
+	if err := s.config.SetLandscapeEndpoint(ctx, msg.GetName(), msg.GetConfig()); err != nil {
+		err = fmt.Errorf("UI service: ApplyLandscapeEndpoint: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// SubmitPluginTask handles the gRPC call to queue a task of a type declared by a plugin for a distro.
+func (s *Service) SubmitPluginTask(ctx context.Context, msg *agentapi.PluginTaskSubmission) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received SubmitPluginTask message (%s) for distro %q", msg.GetType(), msg.GetDistroName())
+
+	if err := s.submitPluginTask(msg); err != nil {
+		err = fmt.Errorf("UI service: SubmitPluginTask: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+func (s *Service) submitPluginTask(msg *agentapi.PluginTaskSubmission) error {
+	if s.plugins == nil {
+		return errors.New("plugins are not available")
+	}
+
+	d, ok := s.db.Get(msg.GetDistroName())
+	if !ok {
+		return fmt.Errorf("distro %q is not managed by the agent", msg.GetDistroName())
+	}
+
+	t, err := s.plugins.Task(d.Name(), msg.GetType(), msg.GetPayload())
+	if err != nil {
+		return err
+	}
+
+	if msg.GetDeferred() {
+		return d.SubmitDeferredTasks(t)
+	}
+	return d.SubmitTasks(t)
+}
+
+// WakeDistro handles the gRPC call to start a distro and keep it awake for a while, without submitting any task.
+func (s *Service) WakeDistro(ctx context.Context, req *agentapi.WakeRequest) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received WakeDistro message for distro %q", req.GetDistroName())
+
+	d, ok := s.db.Get(req.GetDistroName())
+	if !ok {
+		err := status.Errorf(codes.NotFound, "UI service: WakeDistro: distro %q is not managed by the agent", req.GetDistroName())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := d.WakeFor(time.Duration(req.GetKeepAwakeMinutes()) * time.Minute); err != nil {
+		err = fmt.Errorf("UI service: WakeDistro: could not start distro %q: %v", d.Name(), err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// StopDistro handles the gRPC call to release a distro kept awake by WakeDistro, so that it shuts down once idle.
+func (s *Service) StopDistro(ctx context.Context, req *agentapi.StopRequest) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received StopDistro message for distro %q", req.GetDistroName())
+
+	d, ok := s.db.Get(req.GetDistroName())
+	if !ok {
+		err := status.Errorf(codes.NotFound, "UI service: StopDistro: distro %q is not managed by the agent", req.GetDistroName())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	released, err := d.StopWake()
+	if err != nil {
+		err = fmt.Errorf("UI service: StopDistro: could not release distro %q: %v", d.Name(), err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if !released {
+		log.Infof(ctx, "UI service: StopDistro: distro %q was not kept awake on demand: nothing to do", d.Name())
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// SetDistroLabels handles the gRPC call to replace the labels of a distro.
+func (s *Service) SetDistroLabels(ctx context.Context, req *agentapi.DistroLabels) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received SetDistroLabels message for distro %q", req.GetDistroName())
+
+	if _, ok := s.db.Get(req.GetDistroName()); !ok {
+		err := status.Errorf(codes.NotFound, "UI service: SetDistroLabels: distro %q is not managed by the agent", req.GetDistroName())
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	labels := make(map[string]string, len(req.GetLabels()))
+	for _, l := range req.GetLabels() {
+		labels[l.GetKey()] = l.GetValue()
+	}
+
+	if err := distro.ValidateLabels(labels); err != nil {
+		err = status.Errorf(codes.InvalidArgument, "UI service: SetDistroLabels: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := s.db.SetDistroLabels(req.GetDistroName(), labels); err != nil {
+		err = fmt.Errorf("UI service: SetDistroLabels: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// CompactDistro handles the gRPC call to compact the virtual disk of a stopped distro.
+func (s *Service) CompactDistro(ctx context.Context, req *agentapi.CompactRequest) (*agentapi.CompactResult, error) {
+	log.Infof(ctx, "UI service: received CompactDistro message for distro %q", req.GetDistroName())
+
+	if s.compactor == nil {
+		err := errors.New("UI service: CompactDistro: compacting disks is not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	before, after, err := s.compactor.Compact(ctx, req.GetDistroName())
+	if err != nil {
+		err = fmt.Errorf("UI service: CompactDistro: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.CompactResult{SizeBefore: before, SizeAfter: after}, nil
+}
+
+// SetDistroSparse handles the gRPC call to set whether WSL gives the unused space of the virtual
+// disk of a stopped distro back to Windows on its own.
+func (s *Service) SetDistroSparse(ctx context.Context, req *agentapi.SparseRequest) (*agentapi.Empty, error) {
+	log.Infof(ctx, "UI service: received SetDistroSparse message for distro %q", req.GetDistroName())
+
+	if s.compactor == nil {
+		err := errors.New("UI service: SetDistroSparse: changing disks is not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := s.compactor.SetSparse(ctx, req.GetDistroName(), req.GetSparse()); err != nil {
+		err = fmt.Errorf("UI service: SetDistroSparse: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// SetCompactSchedule handles the gRPC call to set the schedule for periodic disk compaction.
+func (s *Service) SetCompactSchedule(ctx context.Context, msg *agentapi.CompactSchedule) (*agentapi.Empty, error) {
+	log.Info(ctx, "UI service: received SetCompactSchedule message")
+
+	c := config.CompactSchedule{Interval: time.Duration(msg.GetIntervalHours()) * time.Hour}
+
+	if err := s.config.SetCompactSchedule(c); err != nil {
+		err = fmt.Errorf("UI service: SetCompactSchedule: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// GetCompactSchedule handles the gRPC call to return the schedule for periodic disk compaction.
+func (s *Service) GetCompactSchedule(ctx context.Context, empty *agentapi.Empty) (*agentapi.CompactSchedule, error) {
+	log.Info(ctx, "UI service: received GetCompactSchedule message")
+
+	c, err := s.config.CompactSchedule()
+	if err != nil {
+		err = fmt.Errorf("UI service: GetCompactSchedule: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	resp := &agentapi.CompactSchedule{IntervalHours: uint32(c.Interval / time.Hour)}
+
+	log.Debugf(ctx, "UI service: responding GetCompactSchedule with %v", resp)
+	return resp, nil
+}
+
+// GetWSLConfig handles the gRPC call to return the global WSL settings of the user.
+func (s *Service) GetWSLConfig(ctx context.Context, empty *agentapi.Empty) (*agentapi.WSLConfig, error) {
+	log.Info(ctx, "UI service: received GetWSLConfig message")
+
+	resp, err := s.wslConfigState()
+	if err != nil {
+		err = fmt.Errorf("UI service: GetWSLConfig: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	log.Debugf(ctx, "UI service: responding GetWSLConfig with %v", resp)
+	return resp, nil
+}
+
+// SetWSLConfig handles the gRPC call to overwrite the global WSL settings of the user. The response
+// tells whether WSL must restart for them to take effect.
+func (s *Service) SetWSLConfig(ctx context.Context, msg *agentapi.WSLConfig) (*agentapi.WSLConfig, error) {
+	log.Info(ctx, "UI service: received SetWSLConfig message")
+
+	resp, err := s.setWSLConfig(msg)
+	if err != nil {
+		err = fmt.Errorf("UI service: SetWSLConfig: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *Service) setWSLConfig(msg *agentapi.WSLConfig) (*agentapi.WSLConfig, error) {
+	if s.wslConfig == nil {
+		return nil, errors.New("global WSL settings are not available")
+	}
+
+	err := s.wslConfig.SetSettings(wslconfig.Settings{
+		Memory:         msg.GetMemory(),
+		Processors:     msg.GetProcessors(),
+		NetworkingMode: msg.GetNetworkingMode(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.wslConfigState()
+}
+
+// UpdateWSL handles the gRPC call to update WSL. The distros are shut down in the process, and the
+// ones that were running are started again afterwards. It only returns once WSL is updated.
+func (s *Service) UpdateWSL(ctx context.Context, empty *agentapi.Empty) (*agentapi.Empty, error) {
+	log.Info(ctx, "UI service: received UpdateWSL message")
+
+	if s.wslUpdater == nil {
+		err := errors.New("UI service: UpdateWSL: updating WSL is not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	if err := s.wslUpdater.Update(ctx); err != nil {
+		err = fmt.Errorf("UI service: UpdateWSL: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return &agentapi.Empty{}, nil
+}
+
+// CreateOperation handles the gRPC call to start a long-running operation in the background. It
+// returns as soon as the operation starts, and the operation carries on even if the client goes away.
+func (s *Service) CreateOperation(ctx context.Context, req *agentapi.OperationRequest) (*agentapi.Operation, error) {
+	log.Info(ctx, "UI service: received CreateOperation message")
+
+	if s.operations == nil {
+		err := errors.New("UI service: CreateOperation: operations are not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	var kind, distroName string
+	var f operations.Func
+
+	switch r := req.GetRequest().(type) {
+	case *agentapi.OperationRequest_ExportDistro:
+		if s.exporter == nil {
+			err := errors.New("UI service: CreateOperation: exporting distros is not available")
+			log.Warningf(ctx, "%v", err)
+			return nil, err
+		}
+
+		kind, distroName = "export", r.ExportDistro.GetDistroName()
+		f = func(ctx context.Context, progress func(uint64)) (string, error) {
+			return s.exporter.Export(ctx, distroName, r.ExportDistro.GetDestination(), func(written int64) {
+				progress(uint64(written))
+			})
+		}
+	case *agentapi.OperationRequest_ResetDistro:
+		kind, distroName = "reset", r.ResetDistro.GetDistroName()
+		f = func(ctx context.Context, _ func(uint64)) (string, error) {
+			return "", s.resetDistro(ctx, r.ResetDistro)
+		}
+	default:
+		err := fmt.Errorf("UI service: CreateOperation: unrecognized operation request: %T", r)
+		log.Warningf(ctx, "%v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	op, err := s.operations.Start(kind, distroName, f)
+	if err != nil {
+		err = fmt.Errorf("UI service: CreateOperation: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, operationError(err)
+	}
+
+	return operationInfo(op), nil
+}
+
+// GetOperation handles the gRPC call to return the state of a long-running operation, so that clients
+// can reattach to it.
+func (s *Service) GetOperation(ctx context.Context, req *agentapi.OperationID) (*agentapi.Operation, error) {
+	log.Debugf(ctx, "UI service: received GetOperation message for operation %s", req.GetId())
+
+	if s.operations == nil {
+		err := errors.New("UI service: GetOperation: operations are not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	op, err := s.operations.Get(req.GetId())
+	if err != nil {
+		err = fmt.Errorf("UI service: GetOperation: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, operationError(err)
+	}
+
+	return operationInfo(op), nil
+}
+
+// CancelOperation handles the gRPC call to cancel a long-running operation. It does not wait for the
+// operation to stop.
+func (s *Service) CancelOperation(ctx context.Context, req *agentapi.OperationID) (*agentapi.Operation, error) {
+	log.Infof(ctx, "UI service: received CancelOperation message for operation %s", req.GetId())
+
+	if s.operations == nil {
+		err := errors.New("UI service: CancelOperation: operations are not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	op, err := s.operations.Cancel(req.GetId())
+	if err != nil {
+		err = fmt.Errorf("UI service: CancelOperation: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, operationError(err)
+	}
+
+	return operationInfo(op), nil
+}
+
+// ListOperations handles the gRPC call to return the operations that are running or finished recently.
+func (s *Service) ListOperations(ctx context.Context, empty *agentapi.Empty) (*agentapi.Operations, error) {
+	log.Debug(ctx, "UI service: received ListOperations message")
+
+	if s.operations == nil {
+		err := errors.New("UI service: ListOperations: operations are not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	resp := &agentapi.Operations{}
+	for _, op := range s.operations.List() {
+		resp.Operations = append(resp.Operations, operationInfo(op))
+	}
+
+	return resp, nil
+}
+
+// RequestConfirmation handles the gRPC call to obtain the code that lets a destructive call go through.
+// The call blocks until the user approves or denies the destructive call in the prompt shown by the agent.
+func (s *Service) RequestConfirmation(ctx context.Context, req *agentapi.ConfirmationRequest) (*agentapi.Confirmation, error) {
+	log.Infof(ctx, "UI service: received RequestConfirmation message for %s", req.GetMethod())
+
+	if s.confirmer == nil {
+		err := errors.New("UI service: RequestConfirmation: confirmations are not available")
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	code, expires, err := s.confirmer.RequestConfirmation(ctx, req.GetMethod())
+	if errors.Is(err, uiauth.ErrDenied) {
+		err = fmt.Errorf("UI service: RequestConfirmation: %v", err)
+		log.Infof(ctx, "%v", err)
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		err = fmt.Errorf("UI service: RequestConfirmation: %v", err)
+		log.Warningf(ctx, "%v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &agentapi.Confirmation{Code: code, Expires: expires.Format(time.RFC3339)}, nil
+}
+
+// operationInfo converts an operation into its API representation.
+func operationInfo(op operations.Operation) *agentapi.Operation {
+	info := &agentapi.Operation{
+		Id:         op.ID,
+		Kind:       op.Kind,
+		DistroName: op.DistroName,
+		State:      string(op.State),
+		Progress:   op.Progress,
+		Result:     op.Result,
+		Started:    op.Started.Format(time.RFC3339),
+	}
+
+	if op.Err != nil {
+		info.Error = op.Err.Error()
+	}
+
+	if !op.Finished.IsZero() {
+		info.Finished = op.Finished.Format(time.RFC3339)
+	}
+
+	return info
+}
+
+// operationError attaches the gRPC status code matching an error returned by the operations manager.
+func operationError(err error) error {
+	switch {
+	case errors.Is(err, operations.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, operations.ErrBusy):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
+
+// wslConfigState returns the global WSL settings, and whether WSL must restart to apply them.
+func (s *Service) wslConfigState() (*agentapi.WSLConfig, error) {
+	if s.wslConfig == nil {
+		return nil, errors.New("global WSL settings are not available")
+	}
+
+	settings, err := s.wslConfig.Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	return &agentapi.WSLConfig{
+		Memory:          settings.Memory,
+		Processors:      settings.Processors,
+		NetworkingMode:  settings.NetworkingMode,
+		RestartRequired: s.wslConfig.RestartRequired(),
+	}, nil
+}
+
+func (s *Service) getSubscriptionSource() (*agentapi.SubscriptionInfo, error) {
+	_, source, err := s.config.Subscription()
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptionInfo(source)
+}
+
+// subscriptionInfo converts a config source into its API representation.
+func subscriptionInfo(source config.Source) (*agentapi.SubscriptionInfo, error) {
+	info := &agentapi.SubscriptionInfo{}
+
+	switch source {
+	case config.SourceNone:
+		info.SubscriptionType = &agentapi.SubscriptionInfo_None{}
+	case config.SourceUser:
+		info.SubscriptionType = &agentapi.SubscriptionInfo_User{}
+	case config.SourceRegistry:
+		info.SubscriptionType = &agentapi.SubscriptionInfo_Organization{}
+	case config.SourceMicrosoftStore:
+		info.SubscriptionType = &agentapi.SubscriptionInfo_MicrosoftStore{}
+	default:
+		return nil, fmt.Errorf("unrecognized subscription source: %d", source)
+	}
+
+	return info, nil
+}
+
+func (s *Service) getLandscapeConfigSource() (*agentapi.LandscapeSource, error) {
+	src := &agentapi.LandscapeSource{}
+
+	_, source, err := s.config.LandscapeClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch source {
+	case config.SourceNone:
+		src.LandscapeSourceType = &agentapi.LandscapeSource_None{}
+	case config.SourceUser:
+		src.LandscapeSourceType = &agentapi.LandscapeSource_User{}
+	case config.SourceRegistry:
+		src.LandscapeSourceType = &agentapi.LandscapeSource_Organization{}
+	default:
+		return nil, fmt.Errorf("unrecognized Landscape source: %d", source)
+	}
+
+	return src, nil
+}
+
+// NotifyPurchase handles the client notification of a successful purchase through MS Store.
+func (s *Service) NotifyPurchase(ctx context.Context, empty *agentapi.Empty) (info *agentapi.SubscriptionInfo, errs error) {
+	log.Info(ctx, "UI service: received NotifyPurchase message")
+
+	if err := ubuntupro.FetchFromMicrosoftStore(ctx, s.config, s.db, s.contractsArgs...); err != nil {
+		log.Warningf(ctx, "UI service: NotifyPurchase: %v", err)
+		errs = errors.Join(errs, err)
+	}
+
+	info, err := s.getSubscriptionSource()
+	if err != nil {
+		log.Warningf(ctx, "UI service: NotifyPurchase: %v", err)
+		errs = errors.Join(errs, err)
+	}
+
+	log.Debugf(ctx, "UI service: responding NotifyPurchase with info: %v", info)
+	return info, errs
+}
+
+// GetVersion handles the gRPC call to report how the agent was built and the versions of the APIs it
+// speaks, for compatibility troubleshooting.
+func (s *Service) GetVersion(ctx context.Context, empty *agentapi.Empty) (*agentapi.VersionInfo, error) {
+	log.Info(ctx, "UI service: received GetVersion message")
+
+	info := buildinfo.New(consts.Version, consts.Commit, consts.BuildDate)
+
+	return &agentapi.VersionInfo{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildDate: info.BuildDate,
+		Protocols: []*agentapi.ProtocolVersion{
+			{Name: "agentapi", Version: agentapi.ProtocolVersion},
+			{Name: "wslserviceapi", Version: wslserviceapi.ProtocolVersion},
+		},
+	}, nil
 }
@@ -1,26 +1,51 @@
 package ui_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/mocks/contractserver/contractsmockserver"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/approvals"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task/tasktestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/worker"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/features"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/onboarding"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/preflight"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/backup"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/operations"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/ui"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/uiauth"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/ubuntupro/contracts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslconfig"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
 	"github.com/stretchr/testify/require"
 	wsl "github.com/ubuntu/gowsl"
 	wslmock "github.com/ubuntu/gowsl/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestNew(t *testing.T) {
@@ -55,15 +80,18 @@ func TestAttachPro(t *testing.T) {
 		token               string
 		breakConfig         bool
 		higherPriorityToken bool
+		readOnlyRegistry    bool
 
-		wantErr bool
+		wantManaged bool
+		wantErr     bool
 	}{
 		"No panic due empty token":          {token: ""},
 		"Success with an empty database":    {token: "funny_token"},
 		"Success with a non-empty database": {token: "whatever_token", distros: []string{distro1, distro2}},
 
 		"Error when the config cannot write":                  {breakConfig: true, wantErr: true},
-		"Error when there already is a higher priority token": {higherPriorityToken: true, wantErr: true},
+		"Error when there already is a higher priority token": {higherPriorityToken: true, wantManaged: true, wantErr: true},
+		"Error when the registry is read-only":                {readOnlyRegistry: true, wantManaged: true, wantErr: true},
 	}
 
 	for name, tc := range testCases {
@@ -95,10 +123,15 @@ func TestAttachPro(t *testing.T) {
 
 			conf := config.New(ctx, dir)
 
+			var data config.RegistryData
 			if tc.higherPriorityToken {
-				err = conf.UpdateRegistryData(ctx, config.RegistryData{
-					UbuntuProToken: "organization_token",
-				}, db)
+				data.UbuntuProToken = "organization_token"
+			}
+			if tc.readOnlyRegistry {
+				data.ReadOnly = true
+			}
+			if tc.higherPriorityToken || tc.readOnlyRegistry {
+				err = conf.UpdateRegistryData(ctx, data, db)
 				require.NoError(t, err, "Setup: could not make registry read registry settings")
 			}
 
@@ -110,6 +143,7 @@ func TestAttachPro(t *testing.T) {
 			var wantToken string
 			if tc.wantErr {
 				require.Error(t, err, "Unexpected success in ApplyProToken")
+				require.Equal(t, tc.wantManaged, status.Code(err) == codes.FailedPrecondition, "Unexpected status code: %v", err)
 				return
 			}
 			require.NoError(t, err, "Adding the task to existing distros should succeed.")
@@ -122,6 +156,146 @@ func TestAttachPro(t *testing.T) {
 	}
 }
 
+// Subtests are parallel but the test itself is not due to the calls to RegisterDistro.
+//
+//nolint:tparallel
+func TestGetFleetStatus(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distro1, _ := wsltestutils.RegisterDistro(t, ctx, false)
+	distro2, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	// Distro names are random, so the expected order must be computed.
+	sorted := []string{distro1, distro2}
+	slices.Sort(sorted)
+
+	lastContact := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		distros        []string
+		managedMode    config.ManagedMode
+		managedModeErr bool
+		preflight      []preflight.Problem
+
+		wantNames []string
+		wantErr   bool
+	}{
+		"Success with an empty database":      {},
+		"Success with a non-empty database":   {distros: []string{sorted[1], sorted[0]}, wantNames: sorted},
+		"Success with a managed subscription": {managedMode: config.ManagedMode{RegistryReadOnly: true, Subscription: true}},
+		"Success with preflight problems": {preflight: []preflight.Problem{
+			{Severity: config.SeverityError, Check: "wsl-version", Message: "WSL is too old", Action: preflight.ActionUpdateWSL, Remediation: "Run 'wsl --update'"},
+			{Severity: config.SeverityWarning, Check: "wsl-default-version", Message: "WSL 1 is the default", Action: preflight.ActionSetDefaultVersion, Remediation: "Run 'wsl --set-default-version 2'"},
+		}},
+
+		"Error when the managed mode cannot be read": {managedModeErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			for _, name := range tc.distros {
+				props := distro.Properties{}
+				if name == distro1 {
+					props = distro.Properties{ProAttached: true, ProServices: []string{"esm-infra", "usg"}, LandscapeRegistered: true, LandscapeScriptUsers: "root,ubuntu", ServiceVersion: "1.2.3"}
+				}
+
+				d, err := db.GetDistroAndUpdateProperties(ctx, name, props)
+				require.NoError(t, err, "Setup: could not add %q to database", name)
+				defer d.Cleanup(ctx)
+
+				if name == distro1 {
+					d.SetLastContact(lastContact)
+					d.SetResourceUsage(distro.ResourceUsage{MemoryBytes: 1 << 30, CPUPercent: 12.5, DiskBytes: 8 << 30, Checked: lastContact})
+				}
+				d.SetSecurityStatus(distro.SecurityStatus{SecurityUpdates: 2, ESMUpdates: 5, Checked: lastContact})
+				d.SetUpdateStatus(distro.UpdateStatus{RebootRequired: true, RebootPackages: []string{"linux-base"}, PendingUpdates: 7, Checked: lastContact})
+			}
+
+			uiService := ui.New(ctx, &mockConfig{managedMode: tc.managedMode, managedModeErr: tc.managedModeErr}, db)
+			if tc.preflight != nil {
+				uiService.SetPreflight(mockPreflight{problems: tc.preflight})
+			}
+
+			got, err := uiService.GetFleetStatus(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetFleetStatus should return an error")
+				return
+			}
+			require.NoError(t, err, "GetFleetStatus should return no errors")
+
+			wantMode := &agentapi.ManagedMode{
+				RegistryReadOnly: tc.managedMode.RegistryReadOnly,
+				Subscription:     tc.managedMode.Subscription,
+				Landscape:        tc.managedMode.Landscape,
+			}
+			require.True(t, proto.Equal(wantMode, got.GetManagedMode()), "Unexpected managed mode: %v", got.GetManagedMode())
+
+			require.Equal(t, uint32(2*len(tc.distros)), got.GetSecurityUpdates(), "Security updates should be added up across the fleet")
+			require.Equal(t, uint32(5*len(tc.distros)), got.GetEsmUpdates(), "ESM updates should be added up across the fleet")
+
+			var gotNames []string
+			for _, d := range got.GetDistros() {
+				gotNames = append(gotNames, d.GetName())
+				require.False(t, d.GetConnected(), "Distro %q should not be connected", d.GetName())
+				require.Zero(t, d.GetPendingTasks(), "Distro %q should have no pending tasks", d.GetName())
+				require.Equal(t, uint32(2), d.GetSecurityUpdates(), "Distro %q has an unexpected number of security updates", d.GetName())
+				require.Equal(t, uint32(5), d.GetEsmUpdates(), "Distro %q has an unexpected number of ESM updates", d.GetName())
+				require.Equal(t, "2024-03-01T12:30:00Z", d.GetSecurityChecked(), "Distro %q has an unexpected security check time", d.GetName())
+				require.True(t, d.GetRebootRequired(), "Distro %q should require a reboot", d.GetName())
+				require.Equal(t, []string{"linux-base"}, d.GetRebootPackages(), "Distro %q has unexpected packages requiring a reboot", d.GetName())
+				require.Equal(t, uint32(7), d.GetPendingUpdates(), "Distro %q has an unexpected number of pending updates", d.GetName())
+				require.False(t, d.GetNeedsAttention(), "Distro %q should not need attention", d.GetName())
+				require.Empty(t, d.GetLastTaskError(), "Distro %q should have no failed task", d.GetName())
+				require.Empty(t, d.GetNextRetry(), "Distro %q should have no retry scheduled", d.GetName())
+
+				if d.GetName() != distro1 {
+					require.False(t, d.GetProAttached(), "Distro %q should not be pro-attached", d.GetName())
+					require.Empty(t, d.GetProServices(), "Distro %q should have no Pro services", d.GetName())
+					require.Empty(t, d.GetLastContact(), "Distro %q should have no last contact", d.GetName())
+					require.False(t, d.GetLandscapeRegistered(), "Distro %q should not be registered in Landscape", d.GetName())
+					require.Empty(t, d.GetLandscapeScriptUsers(), "Distro %q should have no Landscape script users", d.GetName())
+					require.Empty(t, d.GetResourcesChecked(), "Distro %q should have no resource usage", d.GetName())
+					require.Empty(t, d.GetServiceVersion(), "Distro %q should have no service version", d.GetName())
+					continue
+				}
+
+				require.True(t, d.GetProAttached(), "Distro %q should be pro-attached", d.GetName())
+				require.Equal(t, []string{"esm-infra", "usg"}, d.GetProServices(), "Distro %q has unexpected Pro services", d.GetName())
+				require.Equal(t, "2024-03-01T12:30:00Z", d.GetLastContact(), "Distro %q has an unexpected last contact", d.GetName())
+				require.True(t, d.GetLandscapeRegistered(), "Distro %q should be registered in Landscape", d.GetName())
+				require.Equal(t, "root,ubuntu", d.GetLandscapeScriptUsers(), "Distro %q has unexpected Landscape script users", d.GetName())
+				require.Equal(t, uint64(1<<30), d.GetMemoryBytes(), "Distro %q has an unexpected memory usage", d.GetName())
+				require.InDelta(t, 12.5, d.GetCpuPercent(), 1e-9, "Distro %q has an unexpected CPU usage", d.GetName())
+				require.Equal(t, uint64(8<<30), d.GetDiskBytes(), "Distro %q has an unexpected disk usage", d.GetName())
+				require.Equal(t, "2024-03-01T12:30:00Z", d.GetResourcesChecked(), "Distro %q has an unexpected resource check time", d.GetName())
+				require.Equal(t, "1.2.3", d.GetServiceVersion(), "Distro %q has an unexpected service version", d.GetName())
+			}
+
+			require.Equal(t, tc.wantNames, gotNames, "Distros should be listed sorted by name")
+
+			require.Len(t, got.GetPreflightProblems(), len(tc.preflight), "Every preflight problem should be reported")
+			for i, p := range got.GetPreflightProblems() {
+				want := tc.preflight[i]
+				require.Equal(t, want.Severity == config.SeverityError, p.GetIsError(), "Preflight problem %d has an unexpected severity", i)
+				require.Equal(t, want.Check, p.GetCheck(), "Preflight problem %d has an unexpected check", i)
+				require.Equal(t, want.Message, p.GetMessage(), "Preflight problem %d has an unexpected message", i)
+				require.Equal(t, string(want.Action), p.GetAction(), "Preflight problem %d has an unexpected action", i)
+				require.Equal(t, want.Remediation, p.GetRemediation(), "Preflight problem %d has an unexpected remediation", i)
+			}
+		})
+	}
+}
+
 var (
 	subsNone         = &agentapi.SubscriptionInfo_None{}
 	subsOrganization = &agentapi.SubscriptionInfo_Organization{}
@@ -185,6 +359,188 @@ func TestGetConfigSources(t *testing.T) {
 	}
 }
 
+func TestGetSubscriptionDetails(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		config mockConfig
+
+		wantActive   interface{}
+		wantSources  []interface{}
+		wantShadowed []bool
+		wantReadOnly bool
+		wantErr      bool
+	}{
+		"Success with no subscription": {
+			config:      mockConfig{details: config.SubscriptionDetails{Active: config.SourceNone}},
+			wantActive:  subsNone,
+			wantSources: []interface{}{},
+		},
+		"Success with an organization subscription shadowing a user one": {
+			config: mockConfig{details: config.SubscriptionDetails{
+				Active: config.SourceRegistry,
+				Sources: []config.SubscriptionSourceDetails{
+					{Source: config.SourceRegistry, HasToken: true, Modified: modified},
+					{Source: config.SourceMicrosoftStore},
+					{Source: config.SourceUser, HasToken: true, Shadowed: true},
+				},
+			}},
+			wantActive:   subsOrganization,
+			wantSources:  []interface{}{subsOrganization, subsStore, subsUser},
+			wantShadowed: []bool{false, false, true},
+		},
+		"Success with a read-only registry": {
+			config:       mockConfig{details: config.SubscriptionDetails{Active: config.SourceNone, RegistryReadOnly: true}},
+			wantActive:   subsNone,
+			wantSources:  []interface{}{},
+			wantReadOnly: true,
+		},
+
+		"Error when the subscription details cannot be retrieved": {config: mockConfig{subscriptionErr: true}, wantErr: true},
+		"Error when the subscription source is not recognized":    {config: mockConfig{returnBadSource: true}, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			config := tc.config
+			service := ui.New(ctx, &config, db)
+
+			details, err := service.GetSubscriptionDetails(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetSubscriptionDetails should return an error")
+				return
+			}
+			require.NoError(t, err, "GetSubscriptionDetails should return no errors")
+
+			require.IsType(t, tc.wantActive, details.GetActive().GetSubscriptionType(), "Mismatched active subscription types")
+			require.Equal(t, tc.wantReadOnly, details.GetRegistryReadOnly(), "Mismatched registry read-only status")
+
+			require.Len(t, details.GetSources(), len(tc.wantSources), "Mismatched number of subscription sources")
+			for i, src := range details.GetSources() {
+				require.IsType(t, tc.wantSources[i], src.GetSource().GetSubscriptionType(), "Mismatched subscription source types")
+				require.Equal(t, tc.wantShadowed[i], src.GetShadowed(), "Mismatched shadowed status")
+
+				want := tc.config.details.Sources[i]
+				require.Equal(t, want.HasToken, src.GetHasToken(), "Mismatched token presence")
+				if want.Modified.IsZero() {
+					require.Empty(t, src.GetLastModified(), "Unknown modification times should be empty")
+					continue
+				}
+				require.Equal(t, "2024-03-01T12:00:00Z", src.GetLastModified(), "Mismatched modification time")
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		config mockConfig
+
+		wantIsError []bool
+		wantErr     bool
+	}{
+		"Success with a valid config": {},
+		"Success with problems": {
+			config: mockConfig{problems: []config.Problem{
+				{Severity: config.SeverityWarning, Source: config.SourceRegistry, Field: "Unknown", Message: "unknown field"},
+				{Severity: config.SeverityError, Source: config.SourceUser, Field: "Landscape.Config", Message: "missing section"},
+			}},
+			wantIsError: []bool{false, true},
+		},
+
+		"Error when the config cannot be validated": {config: mockConfig{validateErr: true}, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			config := tc.config
+			service := ui.New(ctx, &config, db)
+
+			resp, err := service.ValidateConfig(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "ValidateConfig should return an error")
+				return
+			}
+			require.NoError(t, err, "ValidateConfig should return no errors")
+
+			require.Len(t, resp.GetProblems(), len(tc.wantIsError), "Mismatched number of problems")
+			for i, p := range resp.GetProblems() {
+				require.Equal(t, tc.wantIsError[i], p.GetIsError(), "Mismatched problem severity")
+				require.Equal(t, tc.config.problems[i].Field, p.GetField(), "Mismatched problem field")
+				require.Equal(t, tc.config.problems[i].Message, p.GetMessage(), "Mismatched problem message")
+			}
+		})
+	}
+}
+
+func TestGetFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		config mockConfig
+
+		want    []*agentapi.FeatureFlag
+		wantErr bool
+	}{
+		"Success with no flags": {},
+		"Success with flags from every source": {
+			config: mockConfig{featureFlags: []config.FeatureFlag{
+				{Flag: features.NewTransport, Enabled: true, Source: config.SourceRegistry},
+				{Flag: features.Telemetry, Enabled: true, Source: config.SourceUser},
+				{Flag: features.Flag("Unset"), Enabled: false, Source: config.SourceNone},
+			}},
+			want: []*agentapi.FeatureFlag{
+				{Name: "NewTransport", Enabled: true, Organization: true},
+				{Name: "Telemetry", Enabled: true},
+				{Name: "Unset", Enabled: false, IsDefault: true},
+			},
+		},
+
+		"Error when the flags cannot be retrieved": {config: mockConfig{featureFlagsErr: true}, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			config := tc.config
+			service := ui.New(ctx, &config, db)
+
+			resp, err := service.GetFeatureFlags(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetFeatureFlags should return an error")
+				return
+			}
+			require.NoError(t, err, "GetFeatureFlags should return no errors")
+
+			require.Len(t, resp.GetFlags(), len(tc.want), "Mismatched number of feature flags")
+			for i, f := range resp.GetFlags() {
+				require.Equal(t, tc.want[i].GetName(), f.GetName(), "Mismatched flag name")
+				require.Equal(t, tc.want[i].GetEnabled(), f.GetEnabled(), "Mismatched flag state")
+				require.Equal(t, tc.want[i].GetIsDefault(), f.GetIsDefault(), "Mismatched default status")
+				require.Equal(t, tc.want[i].GetOrganization(), f.GetOrganization(), "Mismatched organization status")
+			}
+		})
+	}
+}
+
 func TestNotifyPurchase(t *testing.T) {
 	t.Parallel()
 
@@ -246,13 +602,14 @@ func TestApplyLandscapeConfig(t *testing.T) {
 		landscapeSource           config.Source
 		returnBadSource           bool
 
-		wantErr bool
-		want    interface{}
+		wantErr     bool
+		wantManaged bool
+		want        interface{}
 	}{
 		"Success": {want: lsUser},
 
 		"Error when setting the config returns error":  {setUserLandscapeConfigErr: true, wantErr: true},
-		"Error when attempting to override org config": {landscapeSource: config.SourceRegistry, wantErr: true},
+		"Error when attempting to override org config": {landscapeSource: config.SourceRegistry, wantErr: true, wantManaged: true},
 		"Error when Landscape source is incoherent":    {returnBadSource: true, wantErr: true},
 	}
 
@@ -284,6 +641,7 @@ func TestApplyLandscapeConfig(t *testing.T) {
 			got, err := uiService.ApplyLandscapeConfig(ctx, msg)
 			if tc.wantErr {
 				require.Error(t, err, "ApplyLandscapeConfig should return an error")
+				require.Equal(t, tc.wantManaged, status.Code(err) == codes.FailedPrecondition, "Unexpected status code: %v", err)
 				return
 			}
 			require.NoError(t, err, "ApplyLandscapeConfig should return no errors")
@@ -294,97 +652,2803 @@ func TestApplyLandscapeConfig(t *testing.T) {
 	}
 }
 
-type mockConfig struct {
-	setUserSubscriptionErr    bool // Config errors out in SetUserSubscription function
-	subscriptionErr           bool // Config errors out in Subscription function
-	setUserLandscapeConfigErr bool // Config errors out in SetUserLandscapeConfig function
-	landscapeErr              bool // Config errors out in LandscapeClientConfig function
+func TestApplyLandscapeDistroOverride(t *testing.T) {
+	t.Parallel()
 
-	token           string        // stores the configured Pro token
-	proSource       config.Source // stores the configured subscription source.
-	landscapeSource config.Source // stores the configured landscape source.
+	testCases := map[string]struct {
+		setOverrideErr bool
 
-	returnBadSource    bool
-	gotLandscapeConfig string
-}
+		wantErr bool
+	}{
+		"Success": {},
 
-func (m *mockConfig) SetUserSubscription(ctx context.Context, token string) error {
-	if m.setUserSubscriptionErr {
-		return errors.New("SetUserSubscription: mock error")
+		"Error when setting the override returns error": {setOverrideErr: true, wantErr: true},
 	}
-	m.token = token
-	m.proSource = config.SourceUser
-	return nil
-}
 
-func (m *mockConfig) SetStoreSubscription(ctx context.Context, token string) error {
-	m.token = token
-	m.proSource = config.SourceMicrosoftStore
-	return nil
-}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
 
-func (m *mockConfig) SetUserLandscapeConfig(ctx context.Context, landscapeConfig string) error {
-	if m.setUserLandscapeConfigErr {
-		return errors.New("mock error")
-	}
+			ctx := context.Background()
 
-	if m.landscapeSource == config.SourceRegistry {
-		return errors.New("mock error cannot overwrite organization's configuration data")
-	}
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
 
-	m.gotLandscapeConfig = landscapeConfig
-	m.landscapeSource = config.SourceUser
+			conf := &mockConfig{
+				setLandscapeDistroOverrideErr: tc.setOverrideErr,
+			}
 
-	return nil
-}
+			uiService := ui.New(context.Background(), conf, db)
 
-func (m mockConfig) Subscription() (string, config.Source, error) {
-	if m.subscriptionErr {
-		return "", config.SourceNone, errors.New("Subscription error")
-	}
-	if m.returnBadSource {
-		return "", config.Source(100000), nil
+			msg := &agentapi.LandscapeDistroOverride{
+				DistroName:    "Ubuntu-22.04",
+				Tags:          "tag1,tag2",
+				ComputerTitle: "My distro",
+				ScriptUsers:   "root,landscape",
+				Endpoint:      "staging",
+			}
+
+			_, err = uiService.ApplyLandscapeDistroOverride(ctx, msg)
+			if tc.wantErr {
+				require.Error(t, err, "ApplyLandscapeDistroOverride should return an error")
+				return
+			}
+			require.NoError(t, err, "ApplyLandscapeDistroOverride should return no errors")
+
+			want := config.LandscapeOverride{Tags: "tag1,tag2", ComputerTitle: "My distro", ScriptUsers: "root,landscape", Endpoint: "staging"}
+			require.Equal(t, "Ubuntu-22.04", conf.gotOverrideDistro, "Config received an unexpected distro name")
+			require.Equal(t, want, conf.gotOverride, "Config received unexpected Landscape overrides")
+		})
 	}
-	return m.token, m.proSource, nil
 }
 
-func (m mockConfig) LandscapeClientConfig() (string, config.Source, error) {
-	if m.landscapeErr {
-		return "", config.SourceNone, errors.New("LandscapeClientConfig error")
+func TestApplyLandscapeEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		setEndpointErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when setting the endpoint returns error": {setEndpointErr: true, wantErr: true},
 	}
-	if m.returnBadSource {
-		return "", config.Source(100000), nil
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{
+				setLandscapeEndpointErr: tc.setEndpointErr,
+			}
+
+			uiService := ui.New(context.Background(), conf, db)
+
+			msg := &agentapi.LandscapeEndpoint{
+				Name:   "staging",
+				Config: "[host]\nurl=staging.landscape.example.com:6554",
+			}
+
+			_, err = uiService.ApplyLandscapeEndpoint(ctx, msg)
+			if tc.wantErr {
+				require.Error(t, err, "ApplyLandscapeEndpoint should return an error")
+				return
+			}
+			require.NoError(t, err, "ApplyLandscapeEndpoint should return no errors")
+
+			require.Equal(t, "staging", conf.gotEndpointName, "Config received an unexpected endpoint name")
+			require.Equal(t, msg.GetConfig(), conf.gotEndpointConfig, "Config received an unexpected endpoint config")
+		})
 	}
-	return "[host]", m.landscapeSource, nil
 }
 
-//nolint:revive // Testing t comes before the context.
-func setupMockContracts(t *testing.T, ctx context.Context) (opts []contracts.Option, stop func()) {
-	t.Helper()
-
-	csSettings := contractsmockserver.DefaultSettings()
-	server := contractsmockserver.NewServer(csSettings)
+// Subtests are parallel but the test itself is not due to the calls to RegisterDistro.
+//
+//nolint:tparallel
+func TestApplyDistroProToken(t *testing.T) {
+	t.Parallel()
 
-	err := server.Serve(ctx, "localhost:0")
-	require.NoError(t, err, "Setup: Server should return no error")
+	testCases := map[string]struct {
+		setErr  bool
+		managed bool
 
-	csAddr, err := url.Parse(fmt.Sprintf("http://%s", server.Address()))
-	require.NoError(t, err, "Setup: Server URL should have been parsed with no issues")
+		wantErr     bool
+		wantManaged bool
+	}{
+		"Success": {},
 
-	opts = []contracts.Option{
-		contracts.WithProURL(csAddr),
-		contracts.WithMockMicrosoftStore(mockMSStore{}),
+		"Error when the organization provides the subscription": {managed: true, wantErr: true, wantManaged: true},
+		"Error when setting the token returns error":            {setErr: true, wantErr: true},
 	}
 
-	return opts, func() { _ = server.Stop() }
-}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
 
-type mockMSStore struct{}
+			ctx := context.Background()
 
-func (s mockMSStore) GenerateUserJWT(azureADToken string) (jwt string, err error) {
-	return "JWT", nil
-}
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
 
-func (s mockMSStore) GetSubscriptionExpirationDate() (tm time.Time, err error) {
-	return time.Now().Add(time.Hour), nil
+			conf := &mockConfig{
+				setDistroSubscriptionErr: tc.setErr,
+				managedMode:              config.ManagedMode{Subscription: tc.managed},
+			}
+
+			uiService := ui.New(ctx, conf, db)
+
+			msg := &agentapi.DistroProToken{DistroName: "Ubuntu", Token: "fips_token"}
+
+			_, err = uiService.ApplyDistroProToken(ctx, msg)
+			if tc.wantErr {
+				require.Error(t, err, "ApplyDistroProToken should return an error")
+				require.Equal(t, tc.wantManaged, status.Code(err) == codes.FailedPrecondition, "Unexpected status code: %v", err)
+				return
+			}
+			require.NoError(t, err, "ApplyDistroProToken should return no errors")
+
+			require.Equal(t, "Ubuntu", conf.gotTokenDistro, "Config received an unexpected distro name")
+			require.Equal(t, "fips_token", conf.gotDistroToken, "Config received an unexpected token")
+		})
+	}
+}
+
+func TestWakeStopDistro(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		keepAwakeMinutes uint32
+		notInDatabase    bool
+		stopOnly         bool
+
+		wantErr bool
+	}{
+		"Success keeping the distro awake until stopped":  {},
+		"Success keeping the distro awake for a while":    {keepAwakeMinutes: 30},
+		"Success stopping a distro that was not woken up": {stopOnly: true},
+
+		"Error when the distro is not in the database": {notInDatabase: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			t.Parallel()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+				defer d.Cleanup(ctx)
+			}
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			if !tc.stopOnly {
+				_, err = uiService.WakeDistro(ctx, &agentapi.WakeRequest{DistroName: distroName, KeepAwakeMinutes: tc.keepAwakeMinutes})
+				if tc.wantErr {
+					require.Error(t, err, "WakeDistro should return an error")
+					require.Equal(t, codes.NotFound, status.Code(err), "Unexpected status code: %v", err)
+
+					_, err = uiService.StopDistro(ctx, &agentapi.StopRequest{DistroName: distroName})
+					require.Error(t, err, "StopDistro should return an error")
+					return
+				}
+				require.NoError(t, err, "WakeDistro should return no errors")
+
+				fleet, err := uiService.GetFleetStatus(ctx, &agentapi.Empty{})
+				require.NoError(t, err, "GetFleetStatus should return no errors")
+				require.Len(t, fleet.GetDistros(), 1, "There should be a single distro")
+
+				got := fleet.GetDistros()[0]
+				require.True(t, got.GetAwakeOnDemand(), "The distro should be reported as kept awake")
+				require.Equal(t, tc.keepAwakeMinutes == 0, got.GetAwakeUntil() == "", "The distro should only report when it is released if it is kept awake for a while")
+			}
+
+			_, err = uiService.StopDistro(ctx, &agentapi.StopRequest{DistroName: distroName})
+			require.NoError(t, err, "StopDistro should return no errors")
+
+			fleet, err := uiService.GetFleetStatus(ctx, &agentapi.Empty{})
+			require.NoError(t, err, "GetFleetStatus should return no errors")
+			require.False(t, fleet.GetDistros()[0].GetAwakeOnDemand(), "The distro should no longer be kept awake")
+		})
+	}
+}
+
+func TestSetDistroLabels(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		labels        []*agentapi.Label
+		notInDatabase bool
+
+		wantLabels []*agentapi.Label
+		wantCode   codes.Code
+	}{
+		"Success setting labels": {
+			labels:     []*agentapi.Label{{Key: "team", Value: "web"}, {Key: "env", Value: "dev"}},
+			wantLabels: []*agentapi.Label{{Key: "env", Value: "dev"}, {Key: "team", Value: "web"}},
+		},
+		"Success removing labels with an empty value": {
+			labels:     []*agentapi.Label{{Key: "env", Value: ""}, {Key: "team", Value: "web"}},
+			wantLabels: []*agentapi.Label{{Key: "team", Value: "web"}},
+		},
+		"Success clearing every label": {},
+
+		"Error when the distro is not in the database": {notInDatabase: true, wantCode: codes.NotFound},
+		"Error when a label key is invalid":            {labels: []*agentapi.Label{{Key: "-env", Value: "dev"}}, wantCode: codes.InvalidArgument},
+		"Error when a label value is invalid":          {labels: []*agentapi.Label{{Key: "env", Value: "dev,prod"}}, wantCode: codes.InvalidArgument},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			t.Parallel()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+				defer d.Cleanup(ctx)
+
+				_, err = d.SetLabels(map[string]string{"env": "prod", "owner": "alice"})
+				require.NoError(t, err, "Setup: could not set the initial labels")
+			}
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			_, err = uiService.SetDistroLabels(ctx, &agentapi.DistroLabels{DistroName: distroName, Labels: tc.labels})
+			if tc.wantCode != codes.OK {
+				require.Error(t, err, "SetDistroLabels should return an error")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected status code: %v", err)
+				return
+			}
+			require.NoError(t, err, "SetDistroLabels should return no errors")
+
+			fleet, err := uiService.GetFleetStatus(ctx, &agentapi.Empty{})
+			require.NoError(t, err, "GetFleetStatus should return no errors")
+			require.Len(t, fleet.GetDistros(), 1, "There should be a single distro")
+
+			got := fleet.GetDistros()[0].GetLabels()
+			require.Len(t, got, len(tc.wantLabels), "The distro should report the labels that were set, and those only")
+			for i, want := range tc.wantLabels {
+				require.Equal(t, want.GetKey(), got[i].GetKey(), "The labels should be sorted by key")
+				require.Equal(t, want.GetValue(), got[i].GetValue(), "The label should have the value that was set")
+			}
+		})
+	}
+}
+
+func TestApplyDistroWSLSettings(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	testCases := map[string]struct {
+		notInDatabase  bool
+		unregistered   bool
+		systemd        bool
+		setSettingsErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+		"Success when the distro is not in the db":                   {notInDatabase: true},
+		"Success enabling systemd in a distro that is not in the db": {notInDatabase: true, systemd: true},
+		"Success enabling systemd in a distro that is in the db":     {systemd: true},
+
+		"Error when setting the settings returns error":                  {setSettingsErr: true, wantErr: true},
+		"Error when systemd cannot be enabled in a distro not in the db": {notInDatabase: true, unregistered: true, systemd: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+				defer d.Cleanup(ctx)
+			}
+
+			conf := &mockConfig{
+				setWSLSettingsErr: tc.setSettingsErr,
+			}
+
+			uiService := ui.New(ctx, conf, db)
+
+			name := distroName
+			if tc.unregistered {
+				name = wsltestutils.RandomDistroName(t)
+			}
+
+			msg := &agentapi.DistroWSLSettings{
+				DistroName:  name,
+				DefaultUser: "ubuntu",
+				Interop:     &agentapi.Switch{Enabled: false},
+			}
+			if tc.systemd {
+				msg.Systemd = &agentapi.Switch{Enabled: true}
+			}
+
+			_, err = uiService.ApplyDistroWSLSettings(ctx, msg)
+			if tc.wantErr {
+				require.Error(t, err, "ApplyDistroWSLSettings should return an error")
+				return
+			}
+			require.NoError(t, err, "ApplyDistroWSLSettings should return no errors")
+
+			disabled := false
+			want := config.WSLSettings{DefaultUser: "ubuntu", Interop: &disabled}
+			if tc.systemd {
+				enabled := true
+				want.Systemd = &enabled
+			}
+			require.Equal(t, distroName, conf.gotWSLDistro, "Config received an unexpected distro name")
+			require.Equal(t, want, conf.gotWSLSettings, "Config received unexpected WSL settings")
+
+			if tc.notInDatabase {
+				return
+			}
+
+			status, err := uiService.GetFleetStatus(ctx, &agentapi.Empty{})
+			require.NoError(t, err, "GetFleetStatus should return no errors")
+			require.Len(t, status.GetDistros(), 1, "There should be a single distro")
+			require.Equal(t, uint32(1), status.GetDistros()[0].GetPendingTasks(), "The settings should have been submitted to the distro")
+		})
+	}
+}
+
+func TestExportDistro(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noExporter bool
+		exportErr  bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when there is no exporter":     {noExporter: true, wantErr: true},
+		"Error when the export returns error": {exportErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+
+			exporter := &mockExporter{exportErr: tc.exportErr}
+			if !tc.noExporter {
+				uiService.SetExporter(exporter)
+			}
+
+			stream := &mockExportStream{ctx: ctx}
+			err = uiService.ExportDistro(&agentapi.ExportRequest{DistroName: "Ubuntu", Destination: `C:\Backups`}, stream)
+			if tc.wantErr {
+				require.Error(t, err, "ExportDistro should return an error")
+				return
+			}
+			require.NoError(t, err, "ExportDistro should return no errors")
+
+			require.Equal(t, "Ubuntu", exporter.gotDistro, "Exporter received an unexpected distro name")
+			require.Equal(t, `C:\Backups`, exporter.gotDestination, "Exporter received an unexpected destination")
+
+			want := []*agentapi.ExportProgress{
+				{BytesWritten: 0},
+				{BytesWritten: 1024},
+				{BytesWritten: 2048},
+				{BytesWritten: 2048, Done: true, Path: filepath.Join(`C:\Backups`, "Ubuntu.tar")},
+			}
+			require.Len(t, stream.sent, len(want), "Unexpected number of progress messages")
+			for i := range want {
+				require.True(t, proto.Equal(want[i], stream.sent[i]), "Unexpected progress message at position %d: %v", i, stream.sent[i])
+			}
+		})
+	}
+}
+
+func TestWatchDistros(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		sendErr     bool
+		closedDB    bool
+		stopWatches bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when the change cannot be sent": {sendErr: true, wantErr: true},
+		"Error when the database is closed":    {closedDB: true, wantErr: true},
+		"Error when the agent shuts down":      {stopWatches: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+
+			if tc.closedDB {
+				db.Close(ctx)
+			}
+
+			watchCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			stream := &mockWatchStream{ctx: watchCtx, sent: make(chan *agentapi.DistroChange, 10), sendErr: tc.sendErr}
+			done := make(chan error)
+			go func() { done <- uiService.WatchDistros(&agentapi.Empty{}, stream) }()
+
+			// Let the watch start before changing the database.
+			time.Sleep(100 * time.Millisecond)
+
+			if tc.closedDB {
+				select {
+				case err := <-done:
+					require.Error(t, err, "WatchDistros should return an error when the database is closed")
+				case <-time.After(5 * time.Second):
+					require.Fail(t, "WatchDistros should have returned")
+				}
+				return
+			}
+
+			_, err = db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+			require.NoError(t, err, "Setup: could not add distro to the database")
+			require.NoError(t, db.SetDistroLabels(distroName, map[string]string{"env": "dev"}), "Setup: could not set labels")
+
+			if tc.stopWatches {
+				uiService.StopWatches(ctx)
+			}
+
+			if tc.wantErr {
+				select {
+				case err := <-done:
+					require.Error(t, err, "WatchDistros should return an error")
+				case <-time.After(5 * time.Second):
+					require.Fail(t, "WatchDistros should have returned")
+				}
+				return
+			}
+
+			for _, want := range []string{"added", "updated"} {
+				select {
+				case got := <-stream.sent:
+					require.Equal(t, distroName, got.GetDistroName(), "Unexpected distro name")
+					require.Equal(t, want, got.GetChange(), "Unexpected change")
+				case <-time.After(5 * time.Second):
+					require.Failf(t, "Change not sent", "Expected the distro to be %s", want)
+				}
+			}
+
+			cancel()
+			select {
+			case err := <-done:
+				require.NoError(t, err, "WatchDistros should return no error once the UI stops watching")
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "WatchDistros should have returned once the UI stopped watching")
+			}
+		})
+	}
+}
+
+func TestCompactDistro(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noCompactor bool
+		compactErr  bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when there is no compactor":        {noCompactor: true, wantErr: true},
+		"Error when the compaction returns error": {compactErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+
+			compactor := &mockCompactor{compactErr: tc.compactErr}
+			if !tc.noCompactor {
+				uiService.SetCompactor(compactor)
+			}
+
+			got, err := uiService.CompactDistro(ctx, &agentapi.CompactRequest{DistroName: "Ubuntu"})
+			if tc.wantErr {
+				require.Error(t, err, "CompactDistro should return an error")
+				return
+			}
+			require.NoError(t, err, "CompactDistro should return no errors")
+
+			require.Equal(t, "Ubuntu", compactor.gotDistro, "Compactor received an unexpected distro name")
+			require.True(t, proto.Equal(&agentapi.CompactResult{SizeBefore: 4096, SizeAfter: 1024}, got), "Unexpected compaction result: %v", got)
+		})
+	}
+}
+
+func TestSetDistroSparse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noCompactor  bool
+		setSparseErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when there is no compactor":                 {noCompactor: true, wantErr: true},
+		"Error when setting the sparse mode returns error": {setSparseErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+
+			compactor := &mockCompactor{setSparseErr: tc.setSparseErr}
+			if !tc.noCompactor {
+				uiService.SetCompactor(compactor)
+			}
+
+			_, err = uiService.SetDistroSparse(ctx, &agentapi.SparseRequest{DistroName: "Ubuntu", Sparse: true})
+			if tc.wantErr {
+				require.Error(t, err, "SetDistroSparse should return an error")
+				return
+			}
+			require.NoError(t, err, "SetDistroSparse should return no errors")
+
+			require.Equal(t, "Ubuntu", compactor.gotDistro, "Compactor received an unexpected distro name")
+			require.True(t, compactor.gotSparse, "Compactor received an unexpected sparse mode")
+		})
+	}
+}
+
+func TestGetWSLConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noFile          bool
+		settingsErr     bool
+		restartRequired bool
+
+		want    *agentapi.WSLConfig
+		wantErr bool
+	}{
+		"Success":                          {want: &agentapi.WSLConfig{Memory: "8GB", Processors: 2, NetworkingMode: "mirrored"}},
+		"Success when a restart is needed": {restartRequired: true, want: &agentapi.WSLConfig{Memory: "8GB", Processors: 2, NetworkingMode: "mirrored", RestartRequired: true}},
+
+		"Error when there is no file":            {noFile: true, wantErr: true},
+		"Error when the settings cannot be read": {settingsErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+			if !tc.noFile {
+				uiService.SetWSLConfigFile(&mockWSLConfigFile{
+					settings:        wslconfig.Settings{Memory: "8GB", Processors: 2, NetworkingMode: "mirrored"},
+					settingsErr:     tc.settingsErr,
+					restartRequired: tc.restartRequired,
+				})
+			}
+
+			got, err := uiService.GetWSLConfig(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetWSLConfig should return an error")
+				return
+			}
+			require.NoError(t, err, "GetWSLConfig should return no errors")
+			require.True(t, proto.Equal(tc.want, got), "Unexpected global WSL settings: %v", got)
+		})
+	}
+}
+
+func TestSetWSLConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noFile         bool
+		setSettingsErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when there is no file":                   {noFile: true, wantErr: true},
+		"Error when setting the settings returns error": {setSettingsErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+			f := &mockWSLConfigFile{setSettingsErr: tc.setSettingsErr}
+			if !tc.noFile {
+				uiService.SetWSLConfigFile(f)
+			}
+
+			got, err := uiService.SetWSLConfig(ctx, &agentapi.WSLConfig{Memory: "4GB", Processors: 1, NetworkingMode: "nat", RestartRequired: false})
+			if tc.wantErr {
+				require.Error(t, err, "SetWSLConfig should return an error")
+				return
+			}
+			require.NoError(t, err, "SetWSLConfig should return no errors")
+
+			require.Equal(t, wslconfig.Settings{Memory: "4GB", Processors: 1, NetworkingMode: "nat"}, f.settings, "WSLConfigFile received unexpected settings")
+			require.True(t, proto.Equal(&agentapi.WSLConfig{Memory: "4GB", Processors: 1, NetworkingMode: "nat", RestartRequired: true}, got), "Unexpected response: %v", got)
+		})
+	}
+}
+
+func TestUpdateWSL(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noUpdater bool
+		updateErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when there is no updater":   {noUpdater: true, wantErr: true},
+		"Error when WSL cannot be updated": {updateErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+			updater := &mockWSLUpdater{updateErr: tc.updateErr}
+			if !tc.noUpdater {
+				uiService.SetWSLUpdater(updater)
+			}
+
+			_, err = uiService.UpdateWSL(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "UpdateWSL should return an error")
+				return
+			}
+			require.NoError(t, err, "UpdateWSL should return no errors")
+			require.True(t, updater.updated, "WSL should have been updated")
+		})
+	}
+}
+
+func TestOperations(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noOperations bool
+		noExporter   bool
+		emptyRequest bool
+		exportErr    bool
+		unknownID    bool
+
+		wantCreateCode codes.Code
+		wantGetCode    codes.Code
+		wantState      string
+	}{
+		"Success": {wantState: "succeeded"},
+
+		"Operation fails when the export returns error": {exportErr: true, wantState: "failed"},
+
+		"Error when there are no operations":      {noOperations: true, wantCreateCode: codes.Unknown},
+		"Error when there is no exporter":         {noExporter: true, wantCreateCode: codes.Unknown},
+		"Error when the request is empty":         {emptyRequest: true, wantCreateCode: codes.InvalidArgument},
+		"Error when the operation does not exist": {unknownID: true, wantGetCode: codes.NotFound},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+
+			ops := operations.New(ctx)
+			defer ops.Stop()
+			if !tc.noOperations {
+				uiService.SetOperations(ops)
+			}
+
+			exporter := &mockExporter{exportErr: tc.exportErr}
+			if !tc.noExporter {
+				uiService.SetExporter(exporter)
+			}
+
+			req := &agentapi.OperationRequest{}
+			if !tc.emptyRequest {
+				req.Request = &agentapi.OperationRequest_ExportDistro{
+					ExportDistro: &agentapi.ExportRequest{DistroName: "Ubuntu", Destination: `C:\Backups`},
+				}
+			}
+
+			op, err := uiService.CreateOperation(ctx, req)
+			if tc.wantCreateCode != codes.OK {
+				require.Error(t, err, "CreateOperation should return an error")
+				require.Equal(t, tc.wantCreateCode, status.Code(err), "CreateOperation returned an unexpected status code")
+				return
+			}
+			require.NoError(t, err, "CreateOperation should return no errors")
+			require.NotEmpty(t, op.GetId(), "CreateOperation should return the ID of the operation")
+			require.Equal(t, "export", op.GetKind(), "Unexpected kind of operation")
+			require.Equal(t, "Ubuntu", op.GetDistroName(), "Unexpected distro of the operation")
+
+			id := op.GetId()
+			if tc.unknownID {
+				id = "not-an-id"
+			}
+
+			require.Eventually(t, func() bool {
+				op, err = uiService.GetOperation(ctx, &agentapi.OperationID{Id: id})
+				return err != nil || op.GetState() != "running"
+			}, 5*time.Second, 10*time.Millisecond, "Operation should finish")
+
+			if tc.wantGetCode != codes.OK {
+				require.Error(t, err, "GetOperation should return an error")
+				require.Equal(t, tc.wantGetCode, status.Code(err), "GetOperation returned an unexpected status code")
+
+				_, err = uiService.CancelOperation(ctx, &agentapi.OperationID{Id: id})
+				require.Equal(t, tc.wantGetCode, status.Code(err), "CancelOperation returned an unexpected status code")
+				return
+			}
+			require.NoError(t, err, "GetOperation should return no errors")
+
+			require.Equal(t, tc.wantState, op.GetState(), "Unexpected state of the operation")
+			require.NotEmpty(t, op.GetFinished(), "Finish time of the operation should be set")
+			if tc.exportErr {
+				require.NotEmpty(t, op.GetError(), "Error of the failed operation should be set")
+				require.Empty(t, op.GetResult(), "Result of the failed operation should be empty")
+			} else {
+				require.Empty(t, op.GetError(), "Error of the successful operation should be empty")
+				require.Equal(t, filepath.Join(`C:\Backups`, "Ubuntu.tar"), op.GetResult(), "Unexpected result of the operation")
+				require.Equal(t, uint64(2048), op.GetProgress(), "Unexpected progress of the operation")
+			}
+
+			list, err := uiService.ListOperations(ctx, &agentapi.Empty{})
+			require.NoError(t, err, "ListOperations should return no errors")
+			require.Len(t, list.GetOperations(), 1, "ListOperations should return the operation")
+			require.True(t, proto.Equal(op, list.GetOperations()[0]), "ListOperations returned an unexpected operation")
+
+			// A second operation can start once the first one finished.
+			_, err = uiService.CreateOperation(ctx, req)
+			require.NoError(t, err, "CreateOperation should return no errors once the previous operation finished")
+		})
+	}
+}
+
+// Subtests are parallel but the test itself is not due to the calls to RegisterDistro.
+//
+//nolint:tparallel
+func TestRequestConfirmation(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noConfirmer   bool
+		confirmErr    bool
+		confirmDenied bool
+
+		wantCode codes.Code
+	}{
+		"Success": {},
+
+		"Error when there is no confirmer":        {noConfirmer: true, wantCode: codes.Unknown},
+		"Error when the call cannot be confirmed": {confirmErr: true, wantCode: codes.InvalidArgument},
+		"Error when the user denies the call":     {confirmDenied: true, wantCode: codes.PermissionDenied},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+			if !tc.noConfirmer {
+				uiService.SetConfirmer(&mockConfirmer{err: tc.confirmErr, denied: tc.confirmDenied})
+			}
+
+			got, err := uiService.RequestConfirmation(ctx, &agentapi.ConfirmationRequest{Method: "ResetDistro"})
+			if tc.wantCode != codes.OK {
+				require.Error(t, err, "RequestConfirmation should return an error")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected status code")
+				return
+			}
+			require.NoError(t, err, "RequestConfirmation should return no errors")
+			require.Equal(t, "code-for-ResetDistro", got.GetCode(), "Unexpected confirmation code")
+
+			_, err = time.Parse(time.RFC3339, got.GetExpires())
+			require.NoError(t, err, "The expiration of the code should be in RFC 3339 format")
+		})
+	}
+}
+
+func TestResetAgent(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noResetter bool
+		resetErr   bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when there is no resetter": {noResetter: true, wantErr: true},
+		"Error when the reset fails":      {resetErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			resetter := &mockResetter{err: tc.resetErr}
+			if !tc.noResetter {
+				uiService.SetResetter(resetter)
+			}
+
+			_, err = uiService.ResetAgent(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "ResetAgent should return an error")
+				return
+			}
+			require.NoError(t, err, "ResetAgent should return no errors")
+			require.True(t, resetter.called, "ResetAgent should have reset the agent")
+		})
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noReloader bool
+		settings   []config.ReloadedSetting
+		reloadErr  bool
+
+		wantErr bool
+	}{
+		"Success": {settings: []config.ReloadedSetting{
+			{Name: "log-level"},
+			{Name: "paused", Err: errors.New("mock error")},
+			{Name: "ports", RestartRequired: true},
+		}},
+
+		"Error when there is no reloader": {noReloader: true, wantErr: true},
+		"Error when the reload fails":     {reloadErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+			if !tc.noReloader {
+				uiService.SetReloader(&mockReloader{settings: tc.settings, err: tc.reloadErr})
+			}
+
+			got, err := uiService.ReloadConfig(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "ReloadConfig should return an error")
+				return
+			}
+			require.NoError(t, err, "ReloadConfig should return no errors")
+			require.Len(t, got.GetSettings(), len(tc.settings), "ReloadConfig should report every setting")
+
+			for i, want := range tc.settings {
+				s := got.GetSettings()[i]
+				require.Equal(t, want.Name, s.GetName(), "Unexpected setting name")
+				require.Equal(t, want.RestartRequired, s.GetRestartRequired(), "Unexpected restart requirement for %q", want.Name)
+				require.Equal(t, want.Err != nil, s.GetError() != "", "Only the settings that could not be applied should report an error")
+			}
+		})
+	}
+}
+
+func TestResetDistro(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		keepProperties bool
+		withRootfs     bool
+		missingRootfs  bool
+		notInDatabase  bool
+
+		mockOnly bool
+		wantErr  bool
+	}{
+		"Success wiping the properties":    {},
+		"Success keeping the properties":   {keepProperties: true},
+		"Success re-importing the distro":  {withRootfs: true, mockOnly: true},
+		"Success re-importing and keeping": {withRootfs: true, keepProperties: true, mockOnly: true},
+
+		"Error when the distro is not in the database": {notInDatabase: true, wantErr: true},
+		"Error when the rootfs does not exist":         {withRootfs: true, missingRootfs: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.mockOnly && !wsl.MockAvailable() {
+				t.Skip("This test can only run with the mock")
+			}
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			t.Parallel()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			props := distro.Properties{Hostname: "testMachine", ProAttached: true}
+			if !tc.notInDatabase {
+				_, err := db.GetDistroAndUpdateProperties(ctx, distroName, props)
+				require.NoError(t, err, "Setup: could not add distro to the database")
+			}
+
+			var rootfs string
+			if tc.withRootfs {
+				rootfs = filepath.Join(t.TempDir(), "rootfs.tar.gz")
+			}
+			if tc.withRootfs && !tc.missingRootfs {
+				err := os.WriteFile(rootfs, []byte("I am a rootfs"), 0600)
+				require.NoError(t, err, "Setup: could not write the rootfs")
+			}
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			_, err = uiService.ResetDistro(ctx, &agentapi.ResetRequest{
+				DistroName:     distroName,
+				KeepProperties: tc.keepProperties,
+				Rootfs:         rootfs,
+			})
+
+			registered, regErr := wsl.NewDistro(ctx, distroName).IsRegistered()
+			require.NoError(t, regErr, "could not check if the distro is registered")
+			require.True(t, registered, "The distro should still be registered")
+
+			if tc.wantErr {
+				require.Error(t, err, "ResetDistro should return an error")
+				return
+			}
+			require.NoError(t, err, "ResetDistro should return no errors")
+
+			d, ok := db.Get(distroName)
+			require.True(t, ok, "The distro should still be in the database")
+			require.True(t, d.IsValid(), "The distro in the database should be valid")
+
+			if tc.keepProperties {
+				require.Equal(t, props, d.Properties(), "The properties of the distro should have been kept")
+				return
+			}
+			require.Equal(t, distro.Properties{}, d.Properties(), "The properties of the distro should have been wiped")
+		})
+	}
+}
+
+func TestGetDistroLogs(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	//nolint:gosec // This is not a real token
+	const token = "C1a2b3c4d5e6f7g8h9i0j1k2"
+
+	testCases := map[string]struct {
+		notInDatabase bool
+		oldService    bool
+		journalErr    bool
+		lines         uint32
+
+		wantLines uint32
+		wantCode  codes.Code
+	}{
+		"Success":                            {wantLines: 200},
+		"Success fetching a number of lines": {lines: 10, wantLines: 10},
+
+		"Error when the distro is not in the database": {notInDatabase: true, wantCode: codes.NotFound},
+		"Error when the WSL Pro service is too old":    {oldService: true, wantCode: codes.FailedPrecondition},
+		"Error when the journal cannot be read":        {journalErr: true, wantCode: codes.Unknown},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			t.Parallel()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+
+				service := &journalServiceMock{
+					oldService: tc.oldService,
+					journalErr: tc.journalErr,
+					entries: []*wslserviceapi.JournalEntry{
+						{Time: "2024-03-01T10:00:00Z", Priority: 6, Message: "Connecting to the Windows agent"},
+						{Time: "2024-03-01T10:00:01Z", Priority: 4, Message: "could not attach with token " + token},
+					},
+				}
+
+				var cfg net.ListenConfig
+				lis, err := cfg.Listen(ctx, "tcp", "localhost:0")
+				require.NoError(t, err, "Setup: could not listen")
+
+				server := grpc.NewServer()
+				wslserviceapi.RegisterWSLServer(server, service)
+				go func() { _ = server.Serve(lis) }()
+				defer server.Stop()
+
+				conn := connection.New(lis.Addr().String())
+				defer conn.Close()
+				require.NoError(t, d.SetConnection(conn), "Setup: could not set the connection")
+
+				defer func() {
+					if tc.wantCode == codes.OK {
+						require.Equal(t, tc.wantLines, service.gotLines, "Unexpected number of lines requested")
+					}
+				}()
+			}
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			got, err := uiService.GetDistroLogs(ctx, &agentapi.DistroLogsRequest{DistroName: distroName, Lines: tc.lines})
+			if tc.wantCode != codes.OK {
+				require.Error(t, err, "GetDistroLogs should return an error")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected status code")
+				return
+			}
+			require.NoError(t, err, "GetDistroLogs should return no errors")
+
+			require.Len(t, got.GetEntries(), 2, "Unexpected number of log entries")
+			require.Equal(t, "Connecting to the Windows agent", got.GetEntries()[0].GetMessage(), "Unexpected message of the first entry")
+			require.Equal(t, uint32(4), got.GetEntries()[1].GetPriority(), "Unexpected priority of the second entry")
+			require.NotContains(t, got.GetEntries()[1].GetMessage(), token, "Secrets in the logs should be redacted")
+		})
+	}
+}
+
+func TestPullDistroFile(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	logLine := []byte("Apr 01 10:00:00 ubuntu wsl-pro-service: Connecting to the Windows agent\n")
+	secret := []byte("Apr 01 10:00:00 ubuntu pro: attaching with token=C123456789012345678901\n")
+	binary := append([]byte("archive\x00"), secret...)
+
+	testCases := map[string]struct {
+		contents          []byte
+		notInDatabase     bool
+		oldService        bool
+		missingFile       bool
+		interruptions     int
+		corrupt           bool
+		relativeDest      bool
+		destinationExists bool
+		partial           int
+
+		wantCode codes.Code
+	}{
+		"Success":                              {},
+		"Success resuming an interrupted copy": {interruptions: 1},
+		"Success resuming a previous copy":     {partial: 10},
+		"Success when the file changed":        {corrupt: true},
+		"Success redacting secrets":            {contents: secret},
+		"Success copying a binary file whole":  {contents: binary},
+
+		"Error when the distro is not in the database": {notInDatabase: true, wantCode: codes.NotFound},
+		"Error when the WSL Pro service is too old":    {oldService: true, wantCode: codes.FailedPrecondition},
+		"Error when the file does not exist":           {missingFile: true, wantCode: codes.NotFound},
+		"Error when the destination is relative":       {relativeDest: true, wantCode: codes.InvalidArgument},
+		"Error when the destination exists":            {destinationExists: true, wantCode: codes.AlreadyExists},
+		"Error when the copy is interrupted too often": {interruptions: 3, wantCode: codes.Unavailable},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			t.Parallel()
+
+			contents := tc.contents
+			if contents == nil {
+				contents = logLine
+			}
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			dest := filepath.Join(t.TempDir(), "example.log")
+			if tc.relativeDest {
+				dest = "example.log"
+			}
+			if tc.destinationExists {
+				err := os.WriteFile(dest, []byte("old contents"), 0600)
+				require.NoError(t, err, "Setup: could not write the destination")
+			}
+			if tc.partial != 0 {
+				err := os.WriteFile(dest+".part", contents[:tc.partial], 0600)
+				require.NoError(t, err, "Setup: could not write the partial file")
+			}
+
+			if !tc.notInDatabase {
+				d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+
+				service := &fileServiceMock{
+					oldService:    tc.oldService,
+					missingFile:   tc.missingFile,
+					interruptions: tc.interruptions,
+					corrupt:       tc.corrupt,
+					contents:      contents,
+				}
+
+				var cfg net.ListenConfig
+				lis, err := cfg.Listen(ctx, "tcp", "localhost:0")
+				require.NoError(t, err, "Setup: could not listen")
+
+				server := grpc.NewServer()
+				wslserviceapi.RegisterWSLServer(server, service)
+				go func() { _ = server.Serve(lis) }()
+				defer server.Stop()
+
+				conn := connection.New(lis.Addr().String())
+				defer conn.Close()
+				require.NoError(t, d.SetConnection(conn), "Setup: could not set the connection")
+
+				defer func() {
+					if tc.partial != 0 && tc.wantCode == codes.OK {
+						service.mu.Lock()
+						defer service.mu.Unlock()
+						require.Equal(t, uint64(tc.partial), service.firstOffset, "The copy should have resumed after the partial file")
+					}
+				}()
+			}
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			got, err := uiService.PullDistroFile(ctx, &agentapi.DistroFileRequest{DistroName: distroName, Path: "/var/log/example.log", Destination: dest})
+			if tc.wantCode != codes.OK {
+				require.Error(t, err, "PullDistroFile should return an error")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected status code")
+				return
+			}
+			require.NoError(t, err, "PullDistroFile should return no errors")
+
+			sum := sha256.Sum256(contents)
+			require.Equal(t, dest, got.GetDestination(), "PullDistroFile should report the destination")
+			require.Equal(t, uint64(len(contents)), got.GetSize(), "PullDistroFile should report the size of the file")
+			require.Equal(t, hex.EncodeToString(sum[:]), got.GetSha256(), "PullDistroFile should report the checksum of the file")
+
+			copied, err := os.ReadFile(dest)
+			require.NoError(t, err, "The file should have been copied to its destination")
+			require.NoFileExists(t, dest+".part", "The partial file should have been moved to the destination")
+
+			if bytes.Equal(contents, secret) {
+				require.True(t, got.GetRedacted(), "PullDistroFile should report that secrets were redacted")
+				require.Equal(t, common.Redact(string(contents)), string(copied), "The secrets of the file should have been redacted")
+				require.NotContains(t, string(copied), "C123456789012345678901", "The copy should not contain the secret")
+				return
+			}
+			require.False(t, got.GetRedacted(), "PullDistroFile should not report a redaction when there was none")
+			require.Equal(t, contents, copied, "The file should have been copied whole")
+		})
+	}
+}
+
+func TestDistroTasks(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		notInDatabase bool
+		unknownID     bool
+
+		wantCode codes.Code
+	}{
+		"Success": {},
+
+		"Error when the distro is not in the database": {notInDatabase: true, wantCode: codes.NotFound},
+		"Error when the task is not queued":            {unknownID: true, wantCode: codes.NotFound},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			t.Parallel()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+
+				// Deferred tasks stay in the queue until the distro is started by the user.
+				err = d.SubmitDeferredTasks(&tasktestutils.RecordingTask{ID: "first"}, &tasktestutils.RecordingTask{ID: "second"})
+				require.NoError(t, err, "Setup: could not submit the tasks")
+			}
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			got, err := uiService.GetDistroTasks(ctx, &agentapi.DistroTasksRequest{DistroName: distroName})
+			if tc.notInDatabase {
+				require.Error(t, err, "GetDistroTasks should return an error")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected status code")
+				return
+			}
+			require.NoError(t, err, "GetDistroTasks should return no errors")
+			require.Len(t, got.GetTasks(), 2, "Unexpected number of queued tasks")
+
+			for _, q := range got.GetTasks() {
+				require.NotEmpty(t, q.GetId(), "Queued tasks should have an ID")
+				require.True(t, q.GetDeferred(), "Queued tasks should be listed as deferred")
+				_, err := time.Parse(time.RFC3339, q.GetSubmitted())
+				require.NoError(t, err, "The submission time should be in RFC 3339 format")
+			}
+
+			id := got.GetTasks()[0].GetId()
+			if tc.unknownID {
+				id = "not-a-task"
+			}
+
+			_, err = uiService.CancelDistroTask(ctx, &agentapi.CancelTaskRequest{DistroName: distroName, Id: id})
+			if tc.wantCode != codes.OK {
+				require.Error(t, err, "CancelDistroTask should return an error")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected status code")
+				return
+			}
+			require.NoError(t, err, "CancelDistroTask should return no errors")
+
+			got, err = uiService.GetDistroTasks(ctx, &agentapi.DistroTasksRequest{DistroName: distroName})
+			require.NoError(t, err, "GetDistroTasks should return no errors")
+			require.Len(t, got.GetTasks(), 1, "The cancelled task should have left the queue")
+			require.NotEqual(t, id, got.GetTasks()[0].GetId(), "The task left in the queue should not be the cancelled one")
+		})
+	}
+}
+
+func TestSetBackupSchedule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		setScheduleErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when setting the schedule returns error": {setScheduleErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{setBackupScheduleErr: tc.setScheduleErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			_, err = uiService.SetBackupSchedule(ctx, &agentapi.BackupSchedule{IntervalHours: 24, Destination: `C:\Backups`, Keep: 3})
+			if tc.wantErr {
+				require.Error(t, err, "SetBackupSchedule should return an error")
+				return
+			}
+			require.NoError(t, err, "SetBackupSchedule should return no errors")
+
+			want := config.BackupSchedule{Interval: 24 * time.Hour, Destination: `C:\Backups`, Keep: 3}
+			require.Equal(t, want, conf.backupSchedule, "Config received an unexpected backup schedule")
+
+			got, err := uiService.GetBackupSchedule(ctx, &agentapi.Empty{})
+			require.NoError(t, err, "GetBackupSchedule should return no errors")
+			require.True(t, proto.Equal(&agentapi.BackupSchedule{IntervalHours: 24, Destination: `C:\Backups`, Keep: 3}, got), "Unexpected backup schedule: %v", got)
+		})
+	}
+}
+
+func TestGetBackupSchedule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		schedule    config.BackupSchedule
+		scheduleErr bool
+
+		want    *agentapi.BackupSchedule
+		wantErr bool
+	}{
+		"Success with backups disabled": {want: &agentapi.BackupSchedule{}},
+		"Success with backups enabled": {
+			schedule: config.BackupSchedule{Interval: 48 * time.Hour},
+			want:     &agentapi.BackupSchedule{IntervalHours: 48},
+		},
+
+		"Error when the schedule cannot be read": {scheduleErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{backupSchedule: tc.schedule, backupScheduleErr: tc.scheduleErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			got, err := uiService.GetBackupSchedule(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetBackupSchedule should return an error")
+				return
+			}
+			require.NoError(t, err, "GetBackupSchedule should return no errors")
+			require.True(t, proto.Equal(tc.want, got), "Unexpected backup schedule: %v", got)
+		})
+	}
+}
+
+func TestSetCompactSchedule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		setScheduleErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when setting the schedule returns error": {setScheduleErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{setCompactScheduleErr: tc.setScheduleErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			_, err = uiService.SetCompactSchedule(ctx, &agentapi.CompactSchedule{IntervalHours: 168})
+			if tc.wantErr {
+				require.Error(t, err, "SetCompactSchedule should return an error")
+				return
+			}
+			require.NoError(t, err, "SetCompactSchedule should return no errors")
+
+			want := config.CompactSchedule{Interval: 168 * time.Hour}
+			require.Equal(t, want, conf.compactSchedule, "Config received an unexpected compaction schedule")
+
+			got, err := uiService.GetCompactSchedule(ctx, &agentapi.Empty{})
+			require.NoError(t, err, "GetCompactSchedule should return no errors")
+			require.True(t, proto.Equal(&agentapi.CompactSchedule{IntervalHours: 168}, got), "Unexpected compaction schedule: %v", got)
+		})
+	}
+}
+
+func TestGetCompactSchedule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		schedule    config.CompactSchedule
+		scheduleErr bool
+
+		want    *agentapi.CompactSchedule
+		wantErr bool
+	}{
+		"Success with compaction disabled": {want: &agentapi.CompactSchedule{}},
+		"Success with compaction enabled": {
+			schedule: config.CompactSchedule{Interval: 48 * time.Hour},
+			want:     &agentapi.CompactSchedule{IntervalHours: 48},
+		},
+
+		"Error when the schedule cannot be read": {scheduleErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{compactSchedule: tc.schedule, compactScheduleErr: tc.scheduleErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			got, err := uiService.GetCompactSchedule(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetCompactSchedule should return an error")
+				return
+			}
+			require.NoError(t, err, "GetCompactSchedule should return no errors")
+			require.True(t, proto.Equal(tc.want, got), "Unexpected compaction schedule: %v", got)
+		})
+	}
+}
+
+func TestSetPauseState(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		paused      bool
+		setPauseErr bool
+
+		wantErr bool
+	}{
+		"Success pausing":  {paused: true},
+		"Success resuming": {},
+
+		"Error when setting the pause state returns error": {paused: true, setPauseErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{paused: !tc.paused, setPausedErr: tc.setPauseErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			_, err = uiService.SetPauseState(ctx, &agentapi.PauseState{Paused: tc.paused})
+			if tc.wantErr {
+				require.Error(t, err, "SetPauseState should return an error")
+				require.Equal(t, !tc.paused, conf.paused, "Config should not have changed the pause state")
+				return
+			}
+			require.NoError(t, err, "SetPauseState should return no errors")
+			require.Equal(t, tc.paused, conf.paused, "Config received an unexpected pause state")
+
+			got, err := uiService.GetPauseState(ctx, &agentapi.Empty{})
+			require.NoError(t, err, "GetPauseState should return no errors")
+			require.Equal(t, tc.paused, got.GetPaused(), "Unexpected pause state")
+		})
+	}
+}
+
+func TestGetPauseState(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		paused    bool
+		pausedErr bool
+
+		wantErr bool
+	}{
+		"Success when not paused": {},
+		"Success when paused":     {paused: true},
+
+		"Error when the pause state cannot be read": {pausedErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{paused: tc.paused, pausedErr: tc.pausedErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			got, err := uiService.GetPauseState(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetPauseState should return an error")
+				return
+			}
+			require.NoError(t, err, "GetPauseState should return no errors")
+			require.Equal(t, tc.paused, got.GetPaused(), "Unexpected pause state")
+		})
+	}
+}
+
+func TestGetVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db, err := database.New(ctx, t.TempDir(), nil)
+	require.NoError(t, err, "Setup: empty database New() should return no error")
+	defer db.Close(ctx)
+
+	uiService := ui.New(context.Background(), &mockConfig{}, db)
+
+	got, err := uiService.GetVersion(ctx, &agentapi.Empty{})
+	require.NoError(t, err, "GetVersion should return no errors")
+
+	require.Equal(t, consts.Version, got.GetVersion(), "GetVersion should report the version of the agent")
+
+	protocols := make(map[string]uint32)
+	for _, p := range got.GetProtocols() {
+		protocols[p.GetName()] = p.GetVersion()
+	}
+	want := map[string]uint32{"agentapi": agentapi.ProtocolVersion, "wslserviceapi": wslserviceapi.ProtocolVersion}
+	require.Equal(t, want, protocols, "GetVersion should report the version of every API the agent speaks")
+}
+
+func TestOnboarding(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		skip         bool
+		noOnboarding bool
+		statusErr    bool
+		skipErr      bool
+
+		wantStep string
+		wantErr  bool
+	}{
+		"Success getting the state":  {wantStep: "landscape"},
+		"Success skipping Landscape": {skip: true, wantStep: "distro"},
+
+		"Error when there is no onboarding tracker":     {noOnboarding: true, wantErr: true},
+		"Error skipping without an onboarding tracker":  {skip: true, noOnboarding: true, wantErr: true},
+		"Error when the status cannot be computed":      {statusErr: true, wantErr: true},
+		"Error when Landscape cannot be skipped":        {skip: true, skipErr: true, wantErr: true},
+		"Error when the status cannot be computed back": {skip: true, statusErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(context.Background(), &mockConfig{}, db)
+			if !tc.noOnboarding {
+				uiService.SetOnboarding(&mockOnboarding{statusErr: tc.statusErr, skipErr: tc.skipErr})
+			}
+
+			var got *agentapi.OnboardingState
+			if tc.skip {
+				got, err = uiService.SkipLandscapeOnboarding(ctx, &agentapi.Empty{})
+			} else {
+				got, err = uiService.GetOnboardingState(ctx, &agentapi.Empty{})
+			}
+			if tc.wantErr {
+				require.Error(t, err, "The onboarding call should return an error")
+				return
+			}
+			require.NoError(t, err, "The onboarding call should return no errors")
+
+			require.Equal(t, tc.wantStep, got.GetStep(), "Unexpected onboarding step")
+			require.True(t, got.GetSubscriptionSelected(), "The subscription should be reported as selected")
+			require.Equal(t, tc.skip, got.GetLandscapeSkipped(), "Landscape should only be reported as skipped after skipping it")
+		})
+	}
+}
+
+func TestSetNotificationPreference(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		category                  string
+		enabled                   bool
+		muted                     []string
+		setNotificationEnabledErr bool
+
+		wantMuted []string
+		wantErr   bool
+	}{
+		"Success muting a category":                  {category: "distro-adopted", wantMuted: []string{"distro-adopted"}},
+		"Success unmuting a category":                {category: "distro-adopted", enabled: true, muted: []string{"distro-adopted"}, wantMuted: []string{}},
+		"Success keeps other categories as they are": {category: "provisioning-failed", muted: []string{"distro-adopted"}, wantMuted: []string{"distro-adopted", "provisioning-failed"}},
+
+		"Error when the category is unknown":         {category: "not-a-category", muted: []string{"distro-adopted"}, wantMuted: []string{"distro-adopted"}, wantErr: true},
+		"Error when the preference cannot be stored": {category: "distro-adopted", setNotificationEnabledErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{mutedNotifications: tc.muted, setNotificationEnabledErr: tc.setNotificationEnabledErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			_, err = uiService.SetNotificationPreference(ctx, &agentapi.NotificationPreference{Category: tc.category, Enabled: tc.enabled})
+			if tc.wantErr {
+				require.Error(t, err, "SetNotificationPreference should return an error")
+				require.ElementsMatch(t, tc.wantMuted, conf.mutedNotifications, "Config should not have changed the muted notifications")
+				return
+			}
+			require.NoError(t, err, "SetNotificationPreference should return no errors")
+			require.ElementsMatch(t, tc.wantMuted, conf.mutedNotifications, "Unexpected muted notifications")
+		})
+	}
+}
+
+func TestGetNotificationPreferences(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		muted                  []string
+		notificationEnabledErr bool
+
+		wantDisabled []string
+		wantErr      bool
+	}{
+		"Success with every category enabled": {},
+		"Success with some categories muted":  {muted: []string{"distro-adopted", "subscription-expiring"}, wantDisabled: []string{"distro-adopted", "subscription-expiring"}},
+
+		"Error when the preferences cannot be read": {notificationEnabledErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{mutedNotifications: tc.muted, notificationEnabledErr: tc.notificationEnabledErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			got, err := uiService.GetNotificationPreferences(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetNotificationPreferences should return an error")
+				return
+			}
+			require.NoError(t, err, "GetNotificationPreferences should return no errors")
+
+			var gotCategories, gotDisabled []string
+			for _, p := range got.GetPreferences() {
+				gotCategories = append(gotCategories, p.GetCategory())
+				if !p.GetEnabled() {
+					gotDisabled = append(gotDisabled, p.GetCategory())
+				}
+			}
+			require.ElementsMatch(t, []string{"subscription-expiring", "provisioning-failed", "distro-adopted", "wsl-update"}, gotCategories, "Every category should be listed")
+			require.ElementsMatch(t, tc.wantDisabled, gotDisabled, "Unexpected disabled categories")
+		})
+	}
+}
+
+func TestSetApprovalPreference(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		category               string
+		required               bool
+		requiredApprovals      []string
+		setApprovalRequiredErr bool
+
+		wantRequired []string
+		wantErr      bool
+	}{
+		"Success requiring an approval":              {category: "landscape-install", required: true, wantRequired: []string{"landscape-install"}},
+		"Success no longer requiring an approval":    {category: "landscape-install", requiredApprovals: []string{"landscape-install"}, wantRequired: []string{}},
+		"Success keeps other categories as they are": {category: "landscape-power", required: true, requiredApprovals: []string{"landscape-install"}, wantRequired: []string{"landscape-install", "landscape-power"}},
+
+		"Error when the category is unknown":         {category: "not-a-category", required: true, requiredApprovals: []string{"landscape-install"}, wantRequired: []string{"landscape-install"}, wantErr: true},
+		"Error when the preference cannot be stored": {category: "landscape-install", required: true, setApprovalRequiredErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{requiredApprovals: tc.requiredApprovals, setApprovalRequiredErr: tc.setApprovalRequiredErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			_, err = uiService.SetApprovalPreference(ctx, &agentapi.ApprovalPreference{Category: tc.category, Required: tc.required})
+			if tc.wantErr {
+				require.Error(t, err, "SetApprovalPreference should return an error")
+				require.ElementsMatch(t, tc.wantRequired, conf.requiredApprovals, "Config should not have changed the required approvals")
+				return
+			}
+			require.NoError(t, err, "SetApprovalPreference should return no errors")
+			require.ElementsMatch(t, tc.wantRequired, conf.requiredApprovals, "Unexpected required approvals")
+		})
+	}
+}
+
+func TestGetApprovalPreferences(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		requiredApprovals   []string
+		approvalRequiredErr bool
+
+		wantRequired []string
+		wantErr      bool
+	}{
+		"Success with no approval required":    {},
+		"Success with some approvals required": {requiredApprovals: []string{"landscape-install", "landscape-shutdown-host"}, wantRequired: []string{"landscape-install", "landscape-shutdown-host"}},
+
+		"Error when the preferences cannot be read": {approvalRequiredErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{requiredApprovals: tc.requiredApprovals, approvalRequiredErr: tc.approvalRequiredErr}
+			uiService := ui.New(context.Background(), conf, db)
+
+			got, err := uiService.GetApprovalPreferences(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetApprovalPreferences should return an error")
+				return
+			}
+			require.NoError(t, err, "GetApprovalPreferences should return no errors")
+
+			var gotCategories, gotRequired []string
+			for _, p := range got.GetPreferences() {
+				gotCategories = append(gotCategories, p.GetCategory())
+				if p.GetRequired() {
+					gotRequired = append(gotRequired, p.GetCategory())
+				}
+			}
+			require.ElementsMatch(t, []string{"landscape-install", "landscape-uninstall", "landscape-set-default", "landscape-power", "landscape-shutdown-host"},
+				gotCategories, "Every category should be listed")
+			require.ElementsMatch(t, tc.wantRequired, gotRequired, "Unexpected required categories")
+		})
+	}
+}
+
+func TestGetPendingApprovals(t *testing.T) {
+	t.Parallel()
+
+	requested := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		noApprovals bool
+		pending     []approvals.Request
+
+		wantIDs []string
+	}{
+		"Success with pending approvals":    {pending: []approvals.Request{{ID: "a"}, {ID: "b"}}, wantIDs: []string{"a", "b"}},
+		"Success with no pending approvals": {},
+		"Success with no approvals tracker": {noApprovals: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			for i := range tc.pending {
+				tc.pending[i].Category = approvals.LandscapeInstall
+				tc.pending[i].Description = "Installing Ubuntu"
+				tc.pending[i].Requested = requested
+				tc.pending[i].Expires = requested.Add(5 * time.Minute)
+			}
+			if !tc.noApprovals {
+				uiService.SetApprovals(&mockApprovals{pending: tc.pending})
+			}
+
+			got, err := uiService.GetPendingApprovals(ctx, &agentapi.Empty{})
+			require.NoError(t, err, "GetPendingApprovals should return no errors")
+
+			var gotIDs []string
+			for _, a := range got.GetApprovals() {
+				gotIDs = append(gotIDs, a.GetId())
+				require.Equal(t, "landscape-install", a.GetCategory(), "Pending approval should have the category of the request")
+				require.Equal(t, "Installing Ubuntu", a.GetDescription(), "Pending approval should have the description of the request")
+				require.Equal(t, "2024-05-01T12:00:00Z", a.GetRequested(), "Pending approval should have the time of the request")
+				require.Equal(t, "2024-05-01T12:05:00Z", a.GetExpires(), "Pending approval should have the expiry of the request")
+			}
+			require.Equal(t, tc.wantIDs, gotIDs, "Unexpected pending approvals")
+		})
+	}
+}
+
+func TestApproveDenyTask(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		deny        bool
+		id          string
+		noApprovals bool
+		decideErr   bool
+
+		wantCode codes.Code
+	}{
+		"Success approving": {id: "a"},
+		"Success denying":   {id: "a", deny: true},
+
+		"Error when the approval is not pending":   {id: "unknown", wantCode: codes.NotFound},
+		"Error when there is no approvals tracker": {id: "a", noApprovals: true, wantCode: codes.NotFound},
+		"Error when the decision fails":            {id: "a", decideErr: true, wantCode: codes.Unknown},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+
+			a := &mockApprovals{pending: []approvals.Request{{ID: "a"}}, err: tc.decideErr}
+			if !tc.noApprovals {
+				uiService.SetApprovals(a)
+			}
+
+			if tc.deny {
+				_, err = uiService.DenyTask(ctx, &agentapi.ApprovalDecision{Id: tc.id})
+			} else {
+				_, err = uiService.ApproveTask(ctx, &agentapi.ApprovalDecision{Id: tc.id})
+			}
+
+			if tc.wantCode != codes.OK {
+				require.Error(t, err, "Deciding on the approval should return an error")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected error code")
+				return
+			}
+			require.NoError(t, err, "Deciding on the approval should return no errors")
+
+			if tc.deny {
+				require.Equal(t, []string{tc.id}, a.denied, "The approval should have been denied")
+				require.Empty(t, a.approved, "The approval should not have been approved")
+				return
+			}
+			require.Equal(t, []string{tc.id}, a.approved, "The approval should have been approved")
+			require.Empty(t, a.denied, "The approval should not have been denied")
+		})
+	}
+}
+
+func TestSubmitPluginTask(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		deferred      bool
+		noPlugins     bool
+		notInDatabase bool
+		taskType      string
+
+		wantErr bool
+	}{
+		"Success submitting a task":          {},
+		"Success submitting a deferred task": {deferred: true},
+
+		"Error when plugins are not available":         {noPlugins: true, wantErr: true},
+		"Error when the distro is not in the database": {notInDatabase: true, wantErr: true},
+		"Error when no plugin declares the task type":  {taskType: "undeclared.task", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			t.Parallel()
+
+			dir := t.TempDir()
+			db, err := database.New(ctx, dir, nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			if !tc.notInDatabase {
+				_, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+				require.NoError(t, err, "Setup: could not add distro to the database")
+			}
+
+			uiService := ui.New(ctx, &mockConfig{}, db)
+			if !tc.noPlugins {
+				uiService.SetTaskPlugins(mockTaskPlugins{"mock.task"})
+			}
+
+			if tc.taskType == "" {
+				tc.taskType = "mock.task"
+			}
+
+			_, err = uiService.SubmitPluginTask(ctx, &agentapi.PluginTaskSubmission{
+				DistroName: distroName,
+				Type:       tc.taskType,
+				Payload:    []byte("hello"),
+				Deferred:   tc.deferred,
+			})
+			if tc.wantErr {
+				require.Error(t, err, "SubmitPluginTask should return an error")
+				return
+			}
+			require.NoError(t, err, "SubmitPluginTask should return no errors")
+
+			// The distro never connects in this test, so the task must still be queued.
+			out, err := os.ReadFile(worker.TaskFile(dir, distroName))
+			require.NoError(t, err, "Could not read the queued tasks")
+			require.Contains(t, string(out), "mock.task", "The task should have been queued")
+		})
+	}
+}
+
+// mockTaskPlugins declares the task types in the slice.
+type mockTaskPlugins []string
+
+func (m mockTaskPlugins) Task(distroName, taskType string, payload []byte) (task.Task, error) {
+	if !slices.Contains(m, taskType) {
+		return nil, fmt.Errorf("no plugin declares task type %q", taskType)
+	}
+	return &tasktestutils.RecordingTask{ID: taskType}, nil
+}
+
+func TestGetLandscapeDistroOverrides(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		overrides    map[string]config.LandscapeOverride
+		overridesErr bool
+
+		want    []*agentapi.LandscapeDistroOverride
+		wantErr bool
+	}{
+		"Success with no overrides": {},
+		"Success with overrides sorted by distro name": {
+			overrides: map[string]config.LandscapeOverride{
+				"Ubuntu-22.04": {Tags: "tag1,tag2"},
+				"Ubuntu":       {ComputerTitle: "My distro", ScriptUsers: "root", Endpoint: "staging"},
+			},
+			want: []*agentapi.LandscapeDistroOverride{
+				{DistroName: "Ubuntu", ComputerTitle: "My distro", ScriptUsers: "root", Endpoint: "staging"},
+				{DistroName: "Ubuntu-22.04", Tags: "tag1,tag2"},
+			},
+		},
+
+		"Error when the overrides cannot be read": {overridesErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := database.New(ctx, t.TempDir(), nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			conf := &mockConfig{
+				landscapeDistroOverrides:    tc.overrides,
+				landscapeDistroOverridesErr: tc.overridesErr,
+			}
+
+			uiService := ui.New(context.Background(), conf, db)
+
+			got, err := uiService.GetLandscapeDistroOverrides(ctx, &agentapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetLandscapeDistroOverrides should return an error")
+				return
+			}
+			require.NoError(t, err, "GetLandscapeDistroOverrides should return no errors")
+
+			require.Len(t, got.GetOverrides(), len(tc.want), "Unexpected number of overrides")
+			for i := range tc.want {
+				require.True(t, proto.Equal(tc.want[i], got.GetOverrides()[i]), "Unexpected override at position %d: %v", i, got.GetOverrides()[i])
+			}
+		})
+	}
+}
+
+type mockConfig struct {
+	setUserSubscriptionErr    bool // Config errors out in SetUserSubscription function
+	subscriptionErr           bool // Config errors out in Subscription function
+	setUserLandscapeConfigErr bool // Config errors out in SetUserLandscapeConfig function
+	landscapeErr              bool // Config errors out in LandscapeClientConfig function
+
+	token           string        // stores the configured Pro token
+	proSource       config.Source // stores the configured subscription source.
+	landscapeSource config.Source // stores the configured landscape source.
+
+	details config.SubscriptionDetails // stores the subscription details
+
+	storeEntitlement config.StoreEntitlement // stores the Microsoft Store entitlement
+
+	problems    []config.Problem // stores the problems returned by Validate
+	validateErr bool             // Config errors out in Validate function
+
+	featureFlags    []config.FeatureFlag // stores the feature flags
+	featureFlagsErr bool                 // Config errors out in FeatureFlags function
+
+	returnBadSource    bool
+	gotLandscapeConfig string
+
+	setLandscapeDistroOverrideErr bool                     // Config errors out in SetLandscapeDistroOverride function
+	gotOverrideDistro             string                   // stores the distro whose Landscape overrides were set
+	gotOverride                   config.LandscapeOverride // stores the Landscape overrides that were set
+
+	setLandscapeEndpointErr bool   // Config errors out in SetLandscapeEndpoint function
+	gotEndpointName         string // stores the name of the Landscape endpoint that was set
+	gotEndpointConfig       string // stores the config of the Landscape endpoint that was set
+
+	landscapeDistroOverrides    map[string]config.LandscapeOverride // stores the Landscape overrides of every distro
+	landscapeDistroOverridesErr bool                                // Config errors out in LandscapeDistroOverrides function
+
+	backupSchedule       config.BackupSchedule // stores the backup schedule
+	setBackupScheduleErr bool                  // Config errors out in SetBackupSchedule function
+	backupScheduleErr    bool                  // Config errors out in BackupSchedule function
+
+	compactSchedule       config.CompactSchedule // stores the compaction schedule
+	setCompactScheduleErr bool                   // Config errors out in SetCompactSchedule function
+	compactScheduleErr    bool                   // Config errors out in CompactSchedule function
+
+	paused       bool // stores whether the agent is paused
+	setPausedErr bool // Config errors out in SetPaused function
+	pausedErr    bool // Config errors out in Paused function
+
+	mutedNotifications        []string // stores the categories of notifications that are muted
+	setNotificationEnabledErr bool     // Config errors out in SetNotificationEnabled function
+	notificationEnabledErr    bool     // Config errors out in NotificationEnabled function
+
+	requiredApprovals      []string // stores the categories of operations that require an approval
+	setApprovalRequiredErr bool     // Config errors out in SetApprovalRequired function
+	approvalRequiredErr    bool     // Config errors out in ApprovalRequired function
+
+	managedMode    config.ManagedMode // stores the managed mode
+	managedModeErr bool               // Config errors out in ManagedMode function
+
+	setWSLSettingsErr bool               // Config errors out in SetWSLSettings function
+	gotWSLDistro      string             // stores the distro whose WSL settings were set
+	gotWSLSettings    config.WSLSettings // stores the WSL settings that were set
+
+	setDistroSubscriptionErr bool   // Config errors out in SetDistroSubscription function
+	gotTokenDistro           string // stores the distro whose Pro token was set
+	gotDistroToken           string // stores the Pro token that was set for the distro
+}
+
+func (m *mockConfig) SetUserSubscription(ctx context.Context, token string) error {
+	if m.setUserSubscriptionErr {
+		return errors.New("SetUserSubscription: mock error")
+	}
+	m.token = token
+	m.proSource = config.SourceUser
+	return nil
+}
+
+func (m *mockConfig) SetStoreSubscription(ctx context.Context, token string) error {
+	m.token = token
+	m.proSource = config.SourceMicrosoftStore
+	return nil
+}
+
+func (m *mockConfig) StoreEntitlement() (config.StoreEntitlement, error) {
+	return m.storeEntitlement, nil
+}
+
+func (m *mockConfig) SetStoreEntitlement(e config.StoreEntitlement) error {
+	m.storeEntitlement = e
+	return nil
+}
+
+func (m *mockConfig) SetUserLandscapeConfig(ctx context.Context, landscapeConfig string) error {
+	if m.setUserLandscapeConfigErr {
+		return errors.New("mock error")
+	}
+
+	if m.landscapeSource == config.SourceRegistry {
+		return config.ManagedError{Setting: "the Landscape configuration", Mode: config.ManagedMode{Landscape: true}}
+	}
+
+	m.gotLandscapeConfig = landscapeConfig
+	m.landscapeSource = config.SourceUser
+
+	return nil
+}
+
+func (m *mockConfig) SetLandscapeDistroOverride(ctx context.Context, distroName string, o config.LandscapeOverride) error {
+	if m.setLandscapeDistroOverrideErr {
+		return errors.New("mock error")
+	}
+
+	m.gotOverrideDistro = distroName
+	m.gotOverride = o
+
+	return nil
+}
+
+func (m *mockConfig) SetLandscapeEndpoint(ctx context.Context, name, conf string) error {
+	if m.setLandscapeEndpointErr {
+		return errors.New("mock error")
+	}
+
+	m.gotEndpointName = name
+	m.gotEndpointConfig = conf
+
+	return nil
+}
+
+func (m mockConfig) LandscapeDistroOverrides() (map[string]config.LandscapeOverride, error) {
+	if m.landscapeDistroOverridesErr {
+		return nil, errors.New("LandscapeDistroOverrides error")
+	}
+	return m.landscapeDistroOverrides, nil
+}
+
+func (m *mockConfig) SetBackupSchedule(b config.BackupSchedule) error {
+	if m.setBackupScheduleErr {
+		return errors.New("mock error")
+	}
+	m.backupSchedule = b
+	return nil
+}
+
+func (m *mockConfig) SetCompactSchedule(c config.CompactSchedule) error {
+	if m.setCompactScheduleErr {
+		return errors.New("mock error")
+	}
+	m.compactSchedule = c
+	return nil
+}
+
+func (m mockConfig) CompactSchedule() (config.CompactSchedule, error) {
+	if m.compactScheduleErr {
+		return config.CompactSchedule{}, errors.New("CompactSchedule error")
+	}
+	return m.compactSchedule, nil
+}
+
+func (m *mockConfig) SetPaused(ctx context.Context, paused bool) error {
+	if m.setPausedErr {
+		return errors.New("mock error")
+	}
+	m.paused = paused
+	return nil
+}
+
+func (m mockConfig) Paused() (bool, error) {
+	if m.pausedErr {
+		return false, errors.New("Paused error")
+	}
+	return m.paused, nil
+}
+
+func (m *mockConfig) SetNotificationEnabled(category string, enabled bool) error {
+	if m.setNotificationEnabledErr {
+		return errors.New("mock error")
+	}
+	m.mutedNotifications = slices.DeleteFunc(m.mutedNotifications, func(s string) bool { return s == category })
+	if !enabled {
+		m.mutedNotifications = append(m.mutedNotifications, category)
+	}
+	return nil
+}
+
+func (m mockConfig) NotificationEnabled(category string) (bool, error) {
+	if m.notificationEnabledErr {
+		return false, errors.New("NotificationEnabled error")
+	}
+	return !slices.Contains(m.mutedNotifications, category), nil
+}
+
+func (m *mockConfig) SetApprovalRequired(category string, required bool) error {
+	if m.setApprovalRequiredErr {
+		return errors.New("mock error")
+	}
+	m.requiredApprovals = slices.DeleteFunc(m.requiredApprovals, func(s string) bool { return s == category })
+	if required {
+		m.requiredApprovals = append(m.requiredApprovals, category)
+	}
+	return nil
+}
+
+func (m mockConfig) ApprovalRequired(category string) (bool, error) {
+	if m.approvalRequiredErr {
+		return false, errors.New("ApprovalRequired error")
+	}
+	return slices.Contains(m.requiredApprovals, category), nil
+}
+
+func (m mockConfig) ManagedMode() (config.ManagedMode, error) {
+	if m.managedModeErr {
+		return config.ManagedMode{}, errors.New("ManagedMode error")
+	}
+	return m.managedMode, nil
+}
+
+func (m *mockConfig) SetDistroSubscription(ctx context.Context, distroName, token string) error {
+	if m.setDistroSubscriptionErr {
+		return errors.New("SetDistroSubscription: mock error")
+	}
+	if m.managedMode.Subscription {
+		return config.ManagedError{Setting: "the Ubuntu Pro subscription", Mode: m.managedMode}
+	}
+	m.gotTokenDistro = distroName
+	m.gotDistroToken = token
+	return nil
+}
+
+func (m *mockConfig) SetWSLSettings(distroName string, settings config.WSLSettings) error {
+	if m.setWSLSettingsErr {
+		return errors.New("mock error")
+	}
+	m.gotWSLDistro = distroName
+	m.gotWSLSettings = settings
+	return nil
+}
+
+func (m mockConfig) BackupSchedule() (config.BackupSchedule, error) {
+	if m.backupScheduleErr {
+		return config.BackupSchedule{}, errors.New("BackupSchedule error")
+	}
+	return m.backupSchedule, nil
+}
+
+func (m mockConfig) Subscription() (string, config.Source, error) {
+	if m.subscriptionErr {
+		return "", config.SourceNone, errors.New("Subscription error")
+	}
+	if m.returnBadSource {
+		return "", config.Source(100000), nil
+	}
+	return m.token, m.proSource, nil
+}
+
+func (m mockConfig) SubscriptionDetails() (config.SubscriptionDetails, error) {
+	if m.subscriptionErr {
+		return config.SubscriptionDetails{}, errors.New("SubscriptionDetails error")
+	}
+	if m.returnBadSource {
+		return config.SubscriptionDetails{Active: config.Source(100000)}, nil
+	}
+	return m.details, nil
+}
+
+func (m mockConfig) Validate(ctx context.Context) ([]config.Problem, error) {
+	if m.validateErr {
+		return nil, errors.New("Validate error")
+	}
+	return m.problems, nil
+}
+
+func (m mockConfig) FeatureFlags() ([]config.FeatureFlag, error) {
+	if m.featureFlagsErr {
+		return nil, errors.New("FeatureFlags error")
+	}
+	return m.featureFlags, nil
+}
+
+func (m mockConfig) LandscapeClientConfig() (string, config.Source, error) {
+	if m.landscapeErr {
+		return "", config.SourceNone, errors.New("LandscapeClientConfig error")
+	}
+	if m.returnBadSource {
+		return "", config.Source(100000), nil
+	}
+	return "[host]", m.landscapeSource, nil
+}
+
+//nolint:revive // Testing t comes before the context.
+func setupMockContracts(t *testing.T, ctx context.Context) (opts []contracts.Option, stop func()) {
+	t.Helper()
+
+	csSettings := contractsmockserver.DefaultSettings()
+	server := contractsmockserver.NewServer(csSettings)
+
+	err := server.Serve(ctx, "localhost:0")
+	require.NoError(t, err, "Setup: Server should return no error")
+
+	csAddr, err := url.Parse(fmt.Sprintf("http://%s", server.Address()))
+	require.NoError(t, err, "Setup: Server URL should have been parsed with no issues")
+
+	opts = []contracts.Option{
+		contracts.WithProURL(csAddr),
+		contracts.WithMockMicrosoftStore(mockMSStore{}),
+	}
+
+	return opts, func() { _ = server.Stop() }
+}
+
+type mockExporter struct {
+	exportErr bool // Exporter errors out in Export function
+
+	gotDistro      string // stores the name of the exported distro
+	gotDestination string // stores the destination of the export
+}
+
+func (m *mockExporter) Export(ctx context.Context, distroName, destination string, progress backup.ProgressFunc) (string, error) {
+	m.gotDistro = distroName
+	m.gotDestination = destination
+
+	progress(0)
+	if m.exportErr {
+		return "", errors.New("mock error")
+	}
+	progress(1024)
+	progress(2048)
+
+	return filepath.Join(destination, distroName+".tar"), nil
+}
+
+type mockCompactor struct {
+	compactErr   bool // Compactor errors out in Compact function
+	setSparseErr bool // Compactor errors out in SetSparse function
+
+	gotDistro string // stores the name of the distro whose disk was changed
+	gotSparse bool   // stores the sparse mode that was set
+}
+
+func (m *mockCompactor) Compact(ctx context.Context, distroName string) (uint64, uint64, error) {
+	m.gotDistro = distroName
+	if m.compactErr {
+		return 0, 0, errors.New("mock error")
+	}
+	return 4096, 1024, nil
+}
+
+func (m *mockCompactor) SetSparse(ctx context.Context, distroName string, sparse bool) error {
+	m.gotDistro = distroName
+	if m.setSparseErr {
+		return errors.New("mock error")
+	}
+	m.gotSparse = sparse
+	return nil
+}
+
+type mockWSLUpdater struct {
+	updateErr bool // WSLUpdater errors out in Update function
+
+	updated bool // stores whether WSL was updated
+}
+
+func (m *mockWSLUpdater) Update(context.Context) error {
+	if m.updateErr {
+		return errors.New("mock error")
+	}
+	m.updated = true
+	return nil
+}
+
+type mockPreflight struct {
+	problems []preflight.Problem // stores the problems found by the checks
+}
+
+func (m mockPreflight) Problems() []preflight.Problem {
+	return m.problems
+}
+
+type mockWSLConfigFile struct {
+	settingsErr     bool // WSLConfigFile errors out in Settings function
+	setSettingsErr  bool // WSLConfigFile errors out in SetSettings function
+	restartRequired bool // stores whether WSL must restart
+
+	settings wslconfig.Settings // stores the global WSL settings
+}
+
+func (m *mockWSLConfigFile) Settings() (wslconfig.Settings, error) {
+	if m.settingsErr {
+		return wslconfig.Settings{}, errors.New("mock error")
+	}
+	return m.settings, nil
+}
+
+func (m *mockWSLConfigFile) SetSettings(s wslconfig.Settings) error {
+	if m.setSettingsErr {
+		return errors.New("mock error")
+	}
+	m.settings = s
+	m.restartRequired = true
+	return nil
+}
+
+func (m *mockWSLConfigFile) RestartRequired() bool {
+	return m.restartRequired
+}
+
+// mockExportStream is a UI_ExportDistroServer that stores the messages it is sent.
+type mockWatchStream struct {
+	grpc.ServerStream
+
+	ctx     context.Context
+	sent    chan *agentapi.DistroChange
+	sendErr bool
+}
+
+func (m *mockWatchStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockWatchStream) Send(msg *agentapi.DistroChange) error {
+	if m.sendErr {
+		return errors.New("mock error")
+	}
+	m.sent <- msg
+	return nil
+}
+
+type mockExportStream struct {
+	grpc.ServerStream
+
+	ctx  context.Context
+	sent []*agentapi.ExportProgress
+}
+
+func (m *mockExportStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockExportStream) Send(msg *agentapi.ExportProgress) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+type mockMSStore struct{}
+
+func (s mockMSStore) GenerateUserJWT(azureADToken string) (jwt string, err error) {
+	return "JWT", nil
+}
+
+func (s mockMSStore) GetSubscriptionExpirationDate() (tm time.Time, err error) {
+	return time.Now().Add(time.Hour), nil
+}
+
+type mockConfirmer struct {
+	err    bool
+	denied bool
+}
+
+func (m *mockConfirmer) RequestConfirmation(ctx context.Context, method string) (string, time.Time, error) {
+	if m.err {
+		return "", time.Time{}, errors.New("mock error")
+	}
+	if m.denied {
+		return "", time.Time{}, uiauth.ErrDenied
+	}
+	return "code-for-" + method, time.Now().Add(time.Minute), nil
+}
+
+type mockResetter struct {
+	err bool
+
+	called bool
+}
+
+func (m *mockResetter) ResetAgent(ctx context.Context) error {
+	if m.err {
+		return errors.New("mock error")
+	}
+	m.called = true
+	return nil
+}
+
+type mockReloader struct {
+	settings []config.ReloadedSetting
+	err      bool
+}
+
+func (m *mockReloader) ReloadConfig(ctx context.Context) ([]config.ReloadedSetting, error) {
+	if m.err {
+		return nil, errors.New("mock error")
+	}
+	return m.settings, nil
+}
+
+type mockApprovals struct {
+	pending []approvals.Request
+	err     bool
+
+	approved []string
+	denied   []string
+}
+
+func (m *mockApprovals) Pending() []approvals.Request {
+	return m.pending
+}
+
+func (m *mockApprovals) Approve(id string) error {
+	if err := m.decide(id); err != nil {
+		return err
+	}
+	m.approved = append(m.approved, id)
+	return nil
+}
+
+func (m *mockApprovals) Deny(id string) error {
+	if err := m.decide(id); err != nil {
+		return err
+	}
+	m.denied = append(m.denied, id)
+	return nil
+}
+
+func (m *mockApprovals) decide(id string) error {
+	if m.err {
+		return errors.New("mock error")
+	}
+	if !slices.ContainsFunc(m.pending, func(r approvals.Request) bool { return r.ID == id }) {
+		return approvals.ErrNotFound
+	}
+	return nil
+}
+
+type mockOnboarding struct {
+	skipped   bool
+	statusErr bool
+	skipErr   bool
+}
+
+func (m *mockOnboarding) Status() (onboarding.Status, error) {
+	if m.statusErr {
+		return onboarding.Status{}, errors.New("mock error")
+	}
+
+	status := onboarding.Status{Step: onboarding.StepLandscape, SubscriptionSelected: true, LandscapeSkipped: m.skipped}
+	if m.skipped {
+		status.Step = onboarding.StepDistro
+	}
+	return status, nil
+}
+
+func (m *mockOnboarding) SkipLandscape() error {
+	if m.skipErr {
+		return errors.New("mock error")
+	}
+	m.skipped = true
+	return nil
+}
+
+type fileServiceMock struct {
+	wslserviceapi.UnimplementedWSLServer
+
+	oldService    bool
+	missingFile   bool
+	interruptions int
+	corrupt       bool
+	contents      []byte
+
+	calls       int
+	firstOffset uint64
+	mu          sync.Mutex
+}
+
+func (s *fileServiceMock) PullFile(msg *wslserviceapi.FileRequest, stream wslserviceapi.WSL_PullFileServer) error {
+	if s.oldService {
+		return s.UnimplementedWSLServer.PullFile(msg, stream)
+	}
+	if s.missingFile {
+		return status.Error(codes.NotFound, "mock error")
+	}
+
+	s.mu.Lock()
+	s.calls++
+	calls := s.calls
+	if calls == 1 {
+		s.firstOffset = msg.GetOffset()
+	}
+	s.mu.Unlock()
+
+	contents := s.contents
+	if s.corrupt && calls == 1 {
+		// The file changed since its checksum was computed.
+		contents = append([]byte("x"), contents[1:]...)
+	}
+
+	sum := sha256.Sum256(s.contents)
+	header := &wslserviceapi.FileHeader{Path: msg.GetPath(), Size: uint64(len(s.contents)), Sha256: hex.EncodeToString(sum[:]), Offset: msg.GetOffset()}
+	if err := stream.Send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Header{Header: header}}); err != nil {
+		return err
+	}
+
+	data := contents[msg.GetOffset():]
+	if calls <= s.interruptions {
+		// Only part of the file is sent before the transfer is interrupted.
+		if err := stream.Send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Data{Data: data[:len(data)/2]}}); err != nil {
+			return err
+		}
+		return status.Error(codes.Unavailable, "mock interruption")
+	}
+
+	return stream.Send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Data{Data: data}})
+}
+
+type journalServiceMock struct {
+	wslserviceapi.UnimplementedWSLServer
+
+	oldService bool
+	journalErr bool
+	entries    []*wslserviceapi.JournalEntry
+
+	gotLines uint32
+}
+
+func (s *journalServiceMock) StreamJournal(msg *wslserviceapi.JournalRequest, stream wslserviceapi.WSL_StreamJournalServer) error {
+	if s.oldService {
+		return s.UnimplementedWSLServer.StreamJournal(msg, stream)
+	}
+	if s.journalErr {
+		return errors.New("mock error")
+	}
+
+	s.gotLines = msg.GetLines()
+	for _, e := range s.entries {
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,14 @@
+package uiauth
+
+import "time"
+
+// ExpireConfirmations makes every confirmation code handed out so far expire.
+func (a *Authenticator) ExpireConfirmations() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for code, conf := range a.confirmations {
+		conf.expires = time.Now().Add(-time.Second)
+		a.confirmations[code] = conf
+	}
+}
@@ -0,0 +1,13 @@
+package uiauth
+
+import (
+	"context"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+)
+
+// askUser is not supported outside of Windows: there is no user to ask, so the call is not approved.
+func askUser(ctx context.Context, method, description string) (bool, error) {
+	log.Warningf(ctx, "Cannot ask the user to approve %s: denying it", method)
+	return false, nil
+}
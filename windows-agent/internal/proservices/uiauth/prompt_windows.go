@@ -0,0 +1,44 @@
+package uiauth
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// askUser shows a message box on the desktop of the user, asking them to approve the call. Clients cannot
+// answer it on behalf of the user, as it is shown by the agent itself.
+func askUser(ctx context.Context, method, description string) (bool, error) {
+	text, err := windows.UTF16PtrFromString(fmt.Sprintf("An application is asking Ubuntu Pro for WSL to %s (%s).\n\nDo you want to allow it?", description, method))
+	if err != nil {
+		return false, err
+	}
+
+	caption, err := windows.UTF16PtrFromString("Ubuntu Pro for WSL")
+	if err != nil {
+		return false, err
+	}
+
+	type answer struct {
+		ret int32
+		err error
+	}
+
+	// The message box blocks until the user answers it, so the call is abandoned if the client gives up.
+	ch := make(chan answer, 1)
+	go func() {
+		ret, err := windows.MessageBox(0, text, caption, windows.MB_YESNO|windows.MB_ICONWARNING|windows.MB_DEFBUTTON2|windows.MB_TOPMOST|windows.MB_SETFOREGROUND)
+		ch <- answer{ret: ret, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case a := <-ch:
+		if a.ret == 0 {
+			return false, fmt.Errorf("could not show message box: %v", a.err)
+		}
+		return a.ret == windows.IDYES, nil
+	}
+}
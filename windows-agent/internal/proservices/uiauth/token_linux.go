@@ -0,0 +1,8 @@
+package uiauth
+
+import "os"
+
+// writePrivate writes the data to a file that only the current user can access.
+func writePrivate(path string, data []byte) error {
+	return os.WriteFile(path, data, 0600)
+}
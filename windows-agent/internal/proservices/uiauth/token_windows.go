@@ -0,0 +1,53 @@
+package uiauth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// writePrivate writes the data to a new file that only the current user can access. File modes are not
+// enforced on Windows, so the file is created with an explicit DACL granting access to the current user
+// alone. The DACL is protected, so that the file does not inherit the entries of its directory.
+func writePrivate(path string, data []byte) error {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return fmt.Errorf("could not find the current user: %v", err)
+	}
+
+	sd, err := windows.SecurityDescriptorFromString(fmt.Sprintf("D:P(A;;FA;;;%s)", user.User.Sid))
+	if err != nil {
+		return fmt.Errorf("could not create security descriptor: %v", err)
+	}
+
+	// The security attributes are ignored when an existing file is overwritten.
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+
+	sa := windows.SecurityAttributes{
+		Length:             uint32(unsafe.Sizeof(windows.SecurityAttributes{})),
+		SecurityDescriptor: sd,
+	}
+
+	h, err := windows.CreateFile(name, windows.GENERIC_WRITE, 0, &sa, windows.CREATE_NEW, windows.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %v", path, err)
+	}
+
+	f := os.NewFile(uintptr(h), path)
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
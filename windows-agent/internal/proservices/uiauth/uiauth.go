@@ -0,0 +1,364 @@
+// Package uiauth authenticates the clients of the UI service. A random token is generated for each session
+// of the agent and written to a file in the private directory of the agent, which only the user can access.
+// Clients must send it back with every call to the UI service.
+//
+// The token keeps other local users, and the processes running without access to the files of the user, from
+// driving the agent. It does not keep out the processes running as the user: they can read the token file.
+// That includes the processes of the distros, which reach the files of the user through /mnt/c. The token file
+// is kept out of the directory shared with the distros all the same, so that it is not handed to them.
+//
+// Calls are split in classes: read-only calls are open to any client on the loopback interface, and
+// destructive calls also require a single-use confirmation code, requested right before the call. The
+// code is only handed out once the user approves the call in a prompt shown by the agent itself, so that
+// clients holding the token cannot confirm destructive calls on their own.
+//
+// The distros are trusted with the read-only calls when WSL runs in mirrored networking mode: their
+// processes then reach the agent from the loopback interface too, so they can make them without the token.
+// This does not give them anything they could not get otherwise, as they can read the token file.
+package uiauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/ubuntu/decorate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// uiMethodPrefix is the prefix of the full method names of the UI service. The WSLInstance service is
+// not authenticated this way, as the distros are not handed the token.
+const uiMethodPrefix = "/agentapi.UI/"
+
+// tokenSize is the amount of random bytes in a token.
+const tokenSize = 32
+
+// confirmationTTL is how long a confirmation code can be used after it is requested.
+const confirmationTTL = 2 * time.Minute
+
+// Class is the access a call to the UI service requires.
+type Class int
+
+const (
+	// User calls change the behaviour of the agent: clients must send the token of the session.
+	User Class = iota
+	// ReadOnly calls only report the state of the agent: any client on the loopback interface can make them,
+	// including the distros when WSL runs in mirrored networking mode.
+	ReadOnly
+	// Admin calls destroy data or replace the subscription or management of the machine: on top of
+	// the token of the session, clients must send a confirmation code requested for that very call.
+	Admin
+)
+
+// readOnlyMethods are the calls of the UI service that don't change anything.
+var readOnlyMethods = map[string]bool{
+	"Ping":                        true,
+	"GetConfigSources":            true,
+	"GetSubscriptionDetails":      true,
+	"ValidateConfig":              true,
+	"GetFeatureFlags":             true,
+	"GetLandscapeDistroOverrides": true,
+	"GetFleetStatus":              true,
+	"GetBackupSchedule":           true,
+	"GetPauseState":               true,
+	"GetNotificationPreferences":  true,
+	"GetApprovalPreferences":      true,
+	"GetPendingApprovals":         true,
+	"GetCompactSchedule":          true,
+	"GetWSLConfig":                true,
+	"GetOperation":                true,
+	"GetDistroTasks":              true,
+	"GetOnboardingState":          true,
+	"ListOperations":              true,
+	"GetVersion":                  true,
+}
+
+// adminMethods maps the calls of the UI service that destroy data or replace the subscription or
+// management of the machine to what they do, as shown to the user in the prompt to approve them.
+// CreateOperation is only one of them when it resets a distro.
+var adminMethods = map[string]string{
+	"ApplyProToken":                "replace the Ubuntu Pro subscription of this machine",
+	"ApplyDistroProToken":          "replace the Ubuntu Pro subscription of a distro",
+	"ApplyLandscapeConfig":         "replace the Landscape configuration of this machine",
+	"ApplyLandscapeEndpoint":       "change the Landscape server this machine is managed by",
+	"ApplyLandscapeDistroOverride": "change the Landscape server or settings of a distro",
+	"ResetDistro":                  "reset a distro, deleting its data",
+	"ResetAgent":                   "reset Ubuntu Pro for WSL, detaching every distro",
+	"SetWSLConfig":                 "change the WSL configuration of this machine",
+	"UpdateWSL":                    "update WSL, shutting down every distro",
+	"CreateOperation":              "reset a distro, deleting its data",
+}
+
+// ErrDenied is returned when the user did not approve the call a confirmation was requested for.
+var ErrDenied = errors.New("the user did not approve the call")
+
+// Prompter asks the user whether the call to the method, described by what it does, should go through.
+type Prompter func(ctx context.Context, method, description string) (approved bool, err error)
+
+// ClassOf returns the class of the call to the method of the UI service, named without its service
+// prefix. The request is used to tell destructive operations apart, and may be nil.
+func ClassOf(method string, req any) Class {
+	if readOnlyMethods[method] {
+		return ReadOnly
+	}
+
+	if method == "CreateOperation" {
+		if r, ok := req.(*agentapi.OperationRequest); ok && r.GetResetDistro() == nil {
+			return User
+		}
+	}
+
+	if _, ok := adminMethods[method]; ok {
+		return Admin
+	}
+
+	return User
+}
+
+// Authenticator checks that the calls to the UI service carry the token of the session, and the
+// confirmation of destructive calls.
+type Authenticator struct {
+	token  string
+	path   string
+	prompt Prompter
+
+	// confirmations maps the codes that were handed out to the method they confirm.
+	confirmations map[string]confirmation
+	mu            sync.Mutex
+}
+
+type confirmation struct {
+	method  string
+	expires time.Time
+}
+
+type options struct {
+	prompt Prompter
+}
+
+// Option is the function signature used to tweak the authenticator.
+type Option func(*options)
+
+// WithPrompter overrides how the user is asked to approve destructive calls.
+func WithPrompter(p Prompter) Option {
+	return func(o *options) {
+		o.prompt = p
+	}
+}
+
+// New generates the token of the session and writes it to the token file in dir, which must be the private
+// directory of the agent. The file is only accessible by the user.
+//
+// Once done, Remove must be called to delete the token file.
+func New(dir string, args ...Option) (a *Authenticator, err error) {
+	defer decorate.OnError(&err, "could not set up UI authentication")
+
+	opts := options{prompt: askUser}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	a = &Authenticator{
+		token:         hex.EncodeToString(b),
+		path:          filepath.Join(dir, common.AuthTokenFileName),
+		prompt:        opts.prompt,
+		confirmations: make(map[string]confirmation),
+	}
+
+	// The file is written to a temporary path first so that clients never read a partial token.
+	tmp := a.path + ".tmp"
+	if err := writePrivate(tmp, []byte(a.token)); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmp, a.path); err != nil {
+		_ = os.Remove(tmp)
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Remove deletes the token file, so that the token of a past session is not left behind.
+func (a *Authenticator) Remove() error {
+	if err := os.Remove(a.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// UnaryServerInterceptor rejects the unary calls to the UI service that don't carry the token of the session.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.check(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects the streaming calls to the UI service that don't carry the token of the session.
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.check(ss.Context(), info.FullMethod, nil); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// RequestConfirmation asks the user to approve the call to the method and, once they do, hands out a
+// single-use code that lets the next call to the method go through. Only destructive methods can be
+// confirmed. ErrDenied is returned if the user does not approve the call.
+func (a *Authenticator) RequestConfirmation(ctx context.Context, method string) (code string, expires time.Time, err error) {
+	description, ok := adminMethods[method]
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("%q does not need a confirmation", method)
+	}
+
+	approved, err := a.prompt(ctx, method, description)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("could not ask the user to approve %s: %v", method, err)
+	}
+	if !approved {
+		return "", time.Time{}, fmt.Errorf("%s: %w", method, ErrDenied)
+	}
+
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, fmt.Errorf("could not generate confirmation code: %v", err)
+	}
+
+	code = hex.EncodeToString(b)
+	expires = time.Now().Add(confirmationTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Codes that were never used are dropped as they expire, so that they don't pile up.
+	for c, conf := range a.confirmations {
+		if time.Now().After(conf.expires) {
+			delete(a.confirmations, c)
+		}
+	}
+	a.confirmations[code] = confirmation{method: method, expires: expires}
+
+	return code, expires, nil
+}
+
+// check returns an Unauthenticated error if the method belongs to the UI service and the
+// metadata in the context does not carry the token of the session, unless the call is read-only and
+// comes from the loopback interface. Destructive calls fail with PermissionDenied unless they carry a
+// valid confirmation code as well.
+func (a *Authenticator) check(ctx context.Context, fullMethod string, req any) error {
+	method, ok := strings.CutPrefix(fullMethod, uiMethodPrefix)
+	if !ok {
+		return nil
+	}
+
+	class := ClassOf(method, req)
+	if class == ReadOnly && fromLoopback(ctx) {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing authentication token")
+	}
+
+	if !a.validToken(md.Get(common.AuthTokenMetadataKey)) {
+		return status.Error(codes.Unauthenticated, "invalid authentication token")
+	}
+
+	if class == Admin && !a.confirm(method, md.Get(common.ConfirmationMetadataKey)) {
+		return status.Errorf(codes.PermissionDenied, "%s must be confirmed: request a confirmation code first", method)
+	}
+
+	return nil
+}
+
+// fromLoopback returns true if the peer of the call is on the loopback interface. The agent listens on
+// every interface, so that the distros can reach it, but only local clients can skip the token. In mirrored
+// networking mode, the distros share the loopback interface of Windows, so they count as local clients.
+func fromLoopback(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	addr, ok := p.Addr.(*net.TCPAddr)
+	return ok && addr.IP.IsLoopback()
+}
+
+// validToken returns true if any of the tokens is the token of the session.
+func (a *Authenticator) validToken(tokens []string) bool {
+	for _, got := range tokens {
+		if subtle.ConstantTimeCompare([]byte(got), []byte(a.token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// confirm consumes the confirmation code among candidates that was handed out for the method. It returns
+// false if there is none, or if it expired.
+func (a *Authenticator) confirm(method string, candidates []string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, code := range candidates {
+		conf, ok := a.confirmations[code]
+		if !ok || conf.method != method {
+			continue
+		}
+
+		delete(a.confirmations, code)
+		return time.Now().Before(conf.expires)
+	}
+
+	return false
+}
+
+// Credentials returns the dial option that makes a client send the token found in the token file in dir
+// with every call.
+func Credentials(dir string) (grpc.DialOption, error) {
+	out, err := os.ReadFile(filepath.Join(dir, common.AuthTokenFileName))
+	if err != nil {
+		return nil, fmt.Errorf("could not read authentication token: %v", err)
+	}
+
+	return grpc.WithPerRPCCredentials(tokenCredentials(strings.TrimSpace(string(out)))), nil
+}
+
+// tokenCredentials attaches the token to the metadata of every call.
+type tokenCredentials string
+
+// GetRequestMetadata returns the metadata carrying the token.
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{common.AuthTokenMetadataKey: string(t)}, nil
+}
+
+// RequireTransportSecurity returns false, as the agent only listens for local connections.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
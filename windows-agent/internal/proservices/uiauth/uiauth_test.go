@@ -0,0 +1,312 @@
+package uiauth_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/uiauth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		breakDir bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when the token file cannot be written": {breakDir: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if tc.breakDir {
+				dir = filepath.Join(dir, "not-a-directory")
+				err := os.WriteFile(dir, nil, 0600)
+				require.NoError(t, err, "Setup: could not write file in place of the directory")
+			}
+
+			a, err := uiauth.New(dir)
+			if tc.wantErr {
+				require.Error(t, err, "New should return an error")
+				return
+			}
+			require.NoError(t, err, "New should return no error")
+
+			token, err := os.ReadFile(filepath.Join(dir, common.AuthTokenFileName))
+			require.NoError(t, err, "The token file should have been written")
+			require.NotEmpty(t, token, "The token file should not be empty")
+
+			other, err := uiauth.New(t.TempDir())
+			require.NoError(t, err, "Setup: could not create a second authenticator")
+			t.Cleanup(func() { _ = other.Remove() })
+			otherToken, err := os.ReadFile(filepath.Join(dir, common.AuthTokenFileName))
+			require.NoError(t, err, "The token file should still be there")
+			require.Equal(t, token, otherToken, "Creating another authenticator should not change the token file of the first one")
+
+			require.NoError(t, a.Remove(), "Remove should return no error")
+			require.NoFileExists(t, filepath.Join(dir, common.AuthTokenFileName), "Remove should delete the token file")
+			require.NoError(t, a.Remove(), "Remove should return no error when the token file is gone already")
+		})
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	reset := &agentapi.OperationRequest{Request: &agentapi.OperationRequest_ResetDistro{ResetDistro: &agentapi.ResetRequest{}}}
+	export := &agentapi.OperationRequest{Request: &agentapi.OperationRequest_ExportDistro{ExportDistro: &agentapi.ExportRequest{}}}
+
+	testCases := map[string]struct {
+		method     string
+		req        any
+		stream     bool
+		noMD       bool
+		badToken   bool
+		emptyToken bool
+		remote     bool
+
+		confirmFor     string
+		confirmExpired bool
+		confirmTwice   bool
+
+		wantCode codes.Code
+	}{
+		"Success with the token of the session":             {method: "/agentapi.UI/SetPauseState"},
+		"Success with the token of the session on a stream": {method: "/agentapi.UI/ExportDistro", stream: true},
+		"Success without token outside of the UI":           {method: "/agentapi.WSLInstance/Connected", noMD: true},
+		"Success without token on a read-only call":         {method: "/agentapi.UI/GetFleetStatus", noMD: true},
+		"Success confirming a destructive call":             {method: "/agentapi.UI/ResetDistro", confirmFor: "ResetDistro"},
+		"Success confirming a destructive operation":        {method: "/agentapi.UI/CreateOperation", req: reset, confirmFor: "CreateOperation"},
+		"Success without confirming a harmless operation":   {method: "/agentapi.UI/CreateOperation", req: export},
+
+		"Success with the token on a read-only call from the network": {method: "/agentapi.UI/GetFleetStatus", remote: true},
+
+		"Error without metadata":                                     {method: "/agentapi.UI/SetPauseState", noMD: true, wantCode: codes.Unauthenticated},
+		"Error without token on a read-only call from the network":   {method: "/agentapi.UI/GetDistroTasks", noMD: true, remote: true, wantCode: codes.Unauthenticated},
+		"Error on a distro subscription change without confirmation": {method: "/agentapi.UI/ApplyDistroProToken", wantCode: codes.PermissionDenied},
+		"Error with the wrong token":                                 {method: "/agentapi.UI/SetPauseState", badToken: true, wantCode: codes.Unauthenticated},
+		"Error with an empty token":                                  {method: "/agentapi.UI/SetPauseState", emptyToken: true, wantCode: codes.Unauthenticated},
+		"Error on a stream without token":                            {method: "/agentapi.UI/ExportDistro", stream: true, noMD: true, wantCode: codes.Unauthenticated},
+		"Error confirming a destructive call without token":          {method: "/agentapi.UI/ResetDistro", noMD: true, confirmFor: "ResetDistro", wantCode: codes.Unauthenticated},
+		"Error on a destructive call without confirmation":           {method: "/agentapi.UI/ApplyProToken", wantCode: codes.PermissionDenied},
+		"Error on a destructive operation without confirmation":      {method: "/agentapi.UI/CreateOperation", req: reset, wantCode: codes.PermissionDenied},
+		"Error with the confirmation of another call":                {method: "/agentapi.UI/ApplyProToken", confirmFor: "ResetDistro", wantCode: codes.PermissionDenied},
+		"Error with an expired confirmation":                         {method: "/agentapi.UI/ResetDistro", confirmFor: "ResetDistro", confirmExpired: true, wantCode: codes.PermissionDenied},
+		"Error reusing a confirmation":                               {method: "/agentapi.UI/ResetDistro", confirmFor: "ResetDistro", confirmTwice: true, wantCode: codes.PermissionDenied},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			a, err := uiauth.New(dir, uiauth.WithPrompter(approve))
+			require.NoError(t, err, "Setup: New should return no error")
+			t.Cleanup(func() { _ = a.Remove() })
+
+			token, err := os.ReadFile(filepath.Join(dir, common.AuthTokenFileName))
+			require.NoError(t, err, "Setup: could not read token file")
+
+			md := metadata.MD{}
+			sent := string(token)
+			if tc.badToken {
+				sent = "not-the-token"
+			}
+			if tc.emptyToken {
+				sent = ""
+			}
+			md.Set(common.AuthTokenMetadataKey, sent)
+
+			if tc.confirmFor != "" {
+				code, expires, err := a.RequestConfirmation(context.Background(), tc.confirmFor)
+				require.NoError(t, err, "Setup: RequestConfirmation should return no error")
+				require.True(t, expires.After(time.Now()), "Setup: the confirmation code should not be expired")
+				if tc.confirmExpired {
+					a.ExpireConfirmations()
+				}
+				md.Set(common.ConfirmationMetadataKey, code)
+			}
+
+			addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}
+			if tc.remote {
+				addr = &net.TCPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 50000}
+			}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if !tc.noMD {
+				ctx = metadata.NewIncomingContext(ctx, md)
+			}
+
+			call := func() (called bool, err error) {
+				if tc.stream {
+					err = a.StreamServerInterceptor()(nil, serverStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tc.method},
+						func(srv any, stream grpc.ServerStream) error {
+							called = true
+							return nil
+						})
+					return called, err
+				}
+
+				_, err = a.UnaryServerInterceptor()(ctx, tc.req, &grpc.UnaryServerInfo{FullMethod: tc.method},
+					func(ctx context.Context, req any) (any, error) {
+						called = true
+						return nil, nil
+					})
+				return called, err
+			}
+
+			if tc.confirmTwice {
+				called, err := call()
+				require.NoError(t, err, "Setup: the first confirmed call should be accepted")
+				require.True(t, called, "Setup: the handler should be called on the first confirmed call")
+			}
+
+			called, err := call()
+			if tc.wantCode != codes.OK {
+				require.Error(t, err, "The interceptor should reject the call")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected status code for the rejected call")
+				require.False(t, called, "The handler should not be called when the call is rejected")
+				return
+			}
+			require.NoError(t, err, "The interceptor should accept the call")
+			require.True(t, called, "The handler should be called when the call is accepted")
+		})
+	}
+}
+
+func TestRequestConfirmation(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		method      string
+		deny        bool
+		promptError bool
+
+		wantErr    bool
+		wantDenied bool
+	}{
+		"Success on a destructive call":           {method: "ResetDistro"},
+		"Success on a distro subscription change": {method: "ApplyDistroProToken"},
+
+		"Error on a read-only call":               {method: "GetFleetStatus", wantErr: true},
+		"Error on a call that is not destructive": {method: "SetPauseState", wantErr: true},
+		"Error when the user denies the call":     {method: "ResetDistro", deny: true, wantErr: true, wantDenied: true},
+		"Error when the user cannot be asked":     {method: "ResetDistro", promptError: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var asked []string
+			prompt := func(ctx context.Context, method, description string) (bool, error) {
+				asked = append(asked, method)
+				require.NotEmpty(t, description, "The user should be told what the call does")
+				if tc.promptError {
+					return false, errors.New("mock error")
+				}
+				return !tc.deny, nil
+			}
+
+			a, err := uiauth.New(t.TempDir(), uiauth.WithPrompter(prompt))
+			require.NoError(t, err, "Setup: New should return no error")
+			t.Cleanup(func() { _ = a.Remove() })
+
+			first, _, err := a.RequestConfirmation(context.Background(), tc.method)
+			if tc.wantErr {
+				require.Error(t, err, "RequestConfirmation should return an error")
+				require.Equal(t, tc.wantDenied, errors.Is(err, uiauth.ErrDenied), "Unexpected denial of the call")
+				return
+			}
+			require.NoError(t, err, "RequestConfirmation should return no error")
+
+			second, _, err := a.RequestConfirmation(context.Background(), tc.method)
+			require.NoError(t, err, "RequestConfirmation should return no error when called again")
+			require.NotEqual(t, first, second, "RequestConfirmation should return a new code every time")
+			require.Equal(t, []string{tc.method, tc.method}, asked, "The user should be asked every time")
+		})
+	}
+}
+
+func TestClassOf(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		method string
+		req    any
+
+		want uiauth.Class
+	}{
+		"Read-only call":             {method: "GetFleetStatus", want: uiauth.ReadOnly},
+		"Call changing a setting":    {method: "SetPauseState", want: uiauth.User},
+		"Approval of a task":         {method: "ApproveTask", want: uiauth.User},
+		"Unknown call":               {method: "SomeNewCall", want: uiauth.User},
+		"Destructive call":           {method: "ResetDistro", want: uiauth.Admin},
+		"Agent reset":                {method: "ResetAgent", want: uiauth.Admin},
+		"Subscription change":        {method: "ApplyProToken", want: uiauth.Admin},
+		"Distro subscription change": {method: "ApplyDistroProToken", want: uiauth.Admin},
+		"Distro Landscape change":    {method: "ApplyLandscapeDistroOverride", want: uiauth.Admin},
+		"Operation without request":  {method: "CreateOperation", want: uiauth.Admin},
+		"Export operation": {method: "CreateOperation",
+			req: &agentapi.OperationRequest{Request: &agentapi.OperationRequest_ExportDistro{ExportDistro: &agentapi.ExportRequest{}}}, want: uiauth.User},
+		"Reset operation": {method: "CreateOperation",
+			req: &agentapi.OperationRequest{Request: &agentapi.OperationRequest_ResetDistro{ResetDistro: &agentapi.ResetRequest{}}}, want: uiauth.Admin},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, uiauth.ClassOf(tc.method, tc.req), "Unexpected class for %s", tc.method)
+		})
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	_, err := uiauth.Credentials(dir)
+	require.Error(t, err, "Credentials should return an error when there is no token file")
+
+	a, err := uiauth.New(dir)
+	require.NoError(t, err, "Setup: New should return no error")
+	t.Cleanup(func() { _ = a.Remove() })
+
+	opt, err := uiauth.Credentials(dir)
+	require.NoError(t, err, "Credentials should return no error")
+	require.NotNil(t, opt, "Credentials should return a dial option")
+}
+
+// approve approves every call, as the user would.
+func approve(ctx context.Context, method, description string) (bool, error) {
+	return true, nil
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
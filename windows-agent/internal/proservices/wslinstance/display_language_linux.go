@@ -0,0 +1,11 @@
+package wslinstance
+
+import "os"
+
+// displayLanguage returns the locale of the user, as there is no Windows display language on Linux.
+func displayLanguage() (string, error) {
+	if loc := os.Getenv("LC_MESSAGES"); loc != "" {
+		return loc, nil
+	}
+	return os.Getenv("LANG"), nil
+}
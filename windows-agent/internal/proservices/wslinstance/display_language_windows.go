@@ -0,0 +1,21 @@
+package wslinstance
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// displayLanguage returns the name of the Windows display language of the user, such as "de-DE".
+func displayLanguage() (string, error) {
+	langs, err := windows.GetUserPreferredUILanguages(windows.MUI_LANGUAGE_NAME)
+	if err != nil {
+		return "", err
+	}
+
+	if len(langs) == 0 {
+		return "", errors.New("no display language is set")
+	}
+
+	return langs[0], nil
+}
@@ -6,20 +6,40 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/vhdx"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
 	"github.com/ubuntu/decorate"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
+// securityStatusInterval is how often a connected distro is asked for its pending security updates.
+const securityStatusInterval = 6 * time.Hour
+
+// updateStatusInterval is how often a connected distro is asked whether it needs a reboot and how many
+// updates are pending. Reading them is cheap, and a reboot may be needed after any unattended upgrade.
+const updateStatusInterval = 30 * time.Minute
+
+// resourceUsageInterval is how often a connected distro is asked for the resources it uses.
+const resourceUsageInterval = 5 * time.Minute
+
 // LandscapeController is the  controller for the Landscape client proservice.
 type LandscapeController interface {
 	SendUpdatedInfo(context.Context) error
+	QueueUpdatedInfo(context.Context)
 }
 
 // Service is the WSL Instance GRPC service implementation.
@@ -28,13 +48,136 @@ type Service struct {
 
 	db        *database.DistroDB
 	landscape LandscapeController
+	timeouts  timeouts.Policy
+
+	// clock dates the contacts with the distros and the statuses they report, and times their polling.
+	clock clock.Clock
+
+	// drops is shared by the copies of the service, so that DropConnections reaches every stream.
+	drops *dropSignal
+
+	// clients is shared by the copies of the service, so that every stream can be looked up.
+	clients *clientRegistry
+}
+
+// connectedClient is a distro connected to the agent, and the connection the agent made back to it.
+type connectedClient struct {
+	distro *distro.Distro
+	conn   *connection.Connection
+}
+
+// clientRegistry maps the address of the connected WSL services to their distro.
+type clientRegistry struct {
+	mu     sync.RWMutex
+	byPeer map[string]connectedClient
+}
+
+// add registers the client connected from the peer address, replacing any previous one.
+func (r *clientRegistry) add(peer string, c connectedClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byPeer[peer] = c
+}
+
+// remove unregisters the client connected from the peer address, unless it was replaced already.
+func (r *clientRegistry) remove(peer string, conn *connection.Connection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.byPeer[peer].conn == conn {
+		delete(r.byPeer, peer)
+	}
+}
+
+// get returns the client connected from the peer address.
+func (r *clientRegistry) get(peer string) (connectedClient, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.byPeer[peer]
+	return c, ok
+}
+
+// dropSignal tells the active streams to end. Its channel is closed and replaced on every drop, so
+// that the streams that start afterwards are not affected.
+type dropSignal struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// wait returns a channel that is closed on the next drop.
+func (d *dropSignal) wait() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ch
+}
+
+// broadcast closes the channel returned by wait so far.
+func (d *dropSignal) broadcast() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	close(d.ch)
+	d.ch = make(chan struct{})
+}
+
+type options struct {
+	timeouts timeouts.Policy
+	clock    clock.Clock
+}
+
+// Option is an optional argument for New.
+type Option func(*options)
+
+// WithTimeouts overrides how long the service waits to connect to the distros, and to update Landscape.
+func WithTimeouts(policy timeouts.Policy) Option {
+	return func(o *options) {
+		o.timeouts = policy
+	}
+}
+
+// WithClock overrides the clock used to date the contacts with the distros and the statuses they report,
+// and to time their polling. Pass the clock of the database, so that it can tell for how long a distro
+// has been idle.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
 }
 
 // New returns a new service handling WSL Instance API.
-func New(ctx context.Context, db *database.DistroDB, landscape LandscapeController) (s Service, err error) {
+func New(ctx context.Context, db *database.DistroDB, landscape LandscapeController, args ...Option) (s Service, err error) {
 	log.Debug(ctx, "Building new GRPC WSLInstance server")
 
-	return Service{db: db, landscape: landscape}, nil
+	opts := options{
+		clock: clock.Real(),
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return Service{
+		db:        db,
+		landscape: landscape,
+		timeouts:  opts.timeouts.OrDefault(),
+		clock:     opts.clock,
+		drops:     &dropSignal{ch: make(chan struct{})},
+		clients:   &clientRegistry{byPeer: make(map[string]connectedClient)},
+	}, nil
+}
+
+// DropConnections ends the streams of every connected distro, e.g. because a network change may have
+// left them stale. The distros connect again with a new stream.
+func (s *Service) DropConnections(ctx context.Context) {
+	log.Info(ctx, "WSLInstance service: dropping the connections of all distros")
+	s.drops.broadcast()
+}
+
+// GetDistroByClient returns the distro whose WSL service connected to the agent from the given
+// address, along with the metadata of its connection.
+func (s *Service) GetDistroByClient(peerAddr string) (d *distro.Distro, md connection.Metadata, ok bool) {
+	c, ok := s.clients.get(peerAddr)
+	if !ok {
+		return nil, md, false
+	}
+	return c.distro, c.conn.Metadata(), true
 }
 
 // Connected establishes a connection with a WSL instance and keeps its properties
@@ -42,16 +185,21 @@ func New(ctx context.Context, db *database.DistroDB, landscape LandscapeControll
 func (s *Service) Connected(stream agentapi.WSLInstance_ConnectedServer) (err error) {
 	ctx := stream.Context()
 
+	var peerAddr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerAddr = p.Addr.String()
+	}
+
 	info, err := stream.Recv()
 	if err != nil {
-		return fmt.Errorf("WSLInstance service: incomplete handshake: did not receive info from WSL distro: %v", err)
+		return fmt.Errorf("WSLInstance service: incomplete handshake: did not receive info from WSL distro at %q: %v", peerAddr, err)
 	}
 
 	distroName := info.GetWslName()
 
 	props, err := propsFromInfo(info)
 	if err != nil {
-		return fmt.Errorf("invalid DistroInfo: %v", err)
+		return fmt.Errorf("invalid DistroInfo from %q: %v", peerAddr, err)
 	}
 
 	log.Debugf(ctx, "received properties: %v", props)
@@ -60,48 +208,100 @@ func (s *Service) Connected(stream agentapi.WSLInstance_ConnectedServer) (err er
 	if err != nil {
 		return err
 	}
+	d.SetLastContact(s.clock.Now())
+
+	md := connection.Metadata{
+		DistroName:     d.Name(),
+		GUID:           d.GUID(),
+		ServiceVersion: info.GetServiceVersion(),
+		BootID:         info.GetBootId(),
+		Peer:           peerAddr,
+		ConnectedAt:    s.clock.Now(),
+	}
+
+	log.Infof(ctx, "WSLInstance service: %s connected from %q", md, peerAddr)
 
 	// Load deferred tasks
 	d.EnqueueDeferredTasks()
 
-	// Update landscape when connecting and disconnecting
-	s.landscapeSendUpdatedInfo(ctx)
-	defer s.landscapeSendUpdatedInfo(ctx)
+	// Update landscape when connecting and disconnecting. Many distros may connect or disconnect at
+	// once, e.g. when WSL shuts down, so the updates are batched.
+	s.landscape.QueueUpdatedInfo(ctx)
+	defer s.landscape.QueueUpdatedInfo(ctx)
 
-	conn, err := newWslServiceConn(ctx, d.Name(), stream)
+	conn, err := newWslServiceConn(ctx, md, stream, s.timeouts.DistroDial)
 	if err != nil {
 		return fmt.Errorf("could not connect to Linux-side WSL service: %v", err)
 	}
 
+	// A distro that reconnects (e.g. after its service or WSL restarted) keeps the same Distro object,
+	// so its task worker is reused: only the connection is swapped.
 	if err := d.SetConnection(conn); err != nil {
-		return err
+		return fmt.Errorf("could not set the connection of %s: %v", md, err)
 	}
 
-	//nolint:errcheck // We don't care about this error because we're cleaning up
-	defer d.SetConnection(nil)
+	// If the distro reconnected in the meantime, the newer connection must be left in place.
+	defer func() {
+		if !d.ReleaseConnection(conn) {
+			log.Debugf(ctx, "WSLInstance service (%s): connection was replaced by a newer one", md)
+		}
+	}()
+
+	s.clients.add(peerAddr, connectedClient{distro: d, conn: conn})
+	defer s.clients.remove(peerAddr, conn)
 
 	log.Debug(ctx, "connection to Linux-side WSL service established")
 
-	// Blocking connection for the lifetime of the WSL service.
+	if client, err := d.Client(); err == nil && client != nil {
+		sendDisplayLanguage(ctx, client, s.timeouts.ShortCall)
+	}
+
+	// The stream context is cancelled when the connection ends, which stops the watch.
+	s.watchStatus(ctx, d)
+
+	// Blocking connection for the lifetime of the WSL service, unless the connections are dropped.
+	// Returning ends the stream, which unblocks the receiving goroutine.
+	dropped := s.drops.wait()
+	recvErr := make(chan error, 1)
+	go func() { recvErr <- s.receiveInfo(ctx, stream, d) }()
+
+	select {
+	case err := <-recvErr:
+		return fmt.Errorf("stream of %s ended: %v", md, err)
+	case <-dropped:
+		return status.Errorf(codes.Unavailable, "connection of %s dropped by the agent", md)
+	}
+}
+
+// receiveInfo keeps the properties of the distro up-to-date with the info it sends, until the stream ends.
+func (s *Service) receiveInfo(ctx context.Context, stream agentapi.WSLInstance_ConnectedServer, d *distro.Distro) error {
 	for {
 		info, err := stream.Recv()
 		if err != nil {
 			return fmt.Errorf("could not receive info: %v", err)
 		}
 
-		props, err = propsFromInfo(info)
+		props, err := propsFromInfo(info)
 		if err != nil {
 			return fmt.Errorf("invalid DistroInfo: %v", err)
 		}
 		log.Infof(ctx, "Updated properties to %+v", props)
+		d.SetLastContact(s.clock.Now())
 
+		old := d.Properties()
 		if d.SetProperties(props) {
-			if err := s.db.Dump(); err != nil {
+			if err := s.db.DistroUpdated(d.Name()); err != nil {
 				log.Warningf(ctx, "updating properties: %v", err)
 			}
 		}
 
-		s.landscapeSendUpdatedInfo(ctx)
+		// Landscape is told right away when a distro gets attached to Pro or registered, as it may be
+		// waiting for it to manage the distro.
+		if old.ProAttached != props.ProAttached || old.LandscapeRegistered != props.LandscapeRegistered {
+			s.landscapeSendUpdatedInfo(ctx)
+			continue
+		}
+		s.landscape.QueueUpdatedInfo(ctx)
 	}
 }
 
@@ -111,13 +311,15 @@ type portSender interface {
 
 const maxConnectionAttempts = 5
 
-func newWslServiceConn(ctx context.Context, distroName string, send portSender) (conn *grpc.ClientConn, err error) {
+func newWslServiceConn(ctx context.Context, md connection.Metadata, send portSender, dialTimeout time.Duration) (conn *connection.Connection, err error) {
+	distroName := md.DistroName
+
 	log.Debugf(ctx, "WSLInstance service (%s): reserving a port", distroName)
 	for i := 0; i < maxConnectionAttempts && conn == nil; i++ {
 		if err != nil {
 			log.Warningf(ctx, "WSLInstance service (%s): retrying to reserve a port: %v", distroName, err)
 		}
-		conn, err = func() (conn *grpc.ClientConn, err error) {
+		conn, err = func() (conn *connection.Connection, err error) {
 			// Port reservation.
 			lis, err := net.Listen("tcp4", "localhost:")
 			if err != nil {
@@ -143,14 +345,14 @@ func newWslServiceConn(ctx context.Context, distroName string, send portSender)
 			addr := fmt.Sprintf("localhost:%d", p)
 			log.Debugf(ctx, "WSLInstance service (%s): connecting to Linux-side WSL service via %s", distroName, addr)
 
-			ctxTimeout, cancel := context.WithTimeout(ctx, 2*time.Second)
+			ctxTimeout, cancel := context.WithTimeout(ctx, dialTimeout)
 			defer cancel()
 
-			conn, err = grpc.DialContext(ctxTimeout, addr,
-				grpc.WithTransportCredentials(insecure.NewCredentials()),
-				grpc.WithBlock())
+			conn, err = connection.Dial(ctxTimeout, addr, connection.WithMetadata(md), connection.WithStateNotifier(func(s connectivity.State) {
+				log.Debugf(ctx, "WSLInstance service (%s): connection to Linux-side WSL service is %s", distroName, s)
+			}))
 			if err != nil {
-				return nil, fmt.Errorf("could not dial WSL service: %v", err)
+				return nil, err
 			}
 
 			// This will signal the task worker that we are ready to process tasks.
@@ -161,6 +363,117 @@ func newWslServiceConn(ctx context.Context, distroName string, send portSender)
 	return conn, err
 }
 
+// sendDisplayLanguage tells the Linux-side WSL service which language to translate its messages to.
+// Failing to do so is not fatal: the messages will be in the locale of the distro.
+func sendDisplayLanguage(ctx context.Context, client wslserviceapi.WSLClient, timeout time.Duration) {
+	lang, err := displayLanguage()
+	if err != nil {
+		log.Warningf(ctx, "could not get the display language: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err := client.SetLocale(ctx, &wslserviceapi.Locale{Name: lang}); err != nil {
+		log.Warningf(ctx, "could not send the display language %q to the Linux-side WSL service: %v", lang, err)
+	}
+}
+
+// poll asks the distro for a status with rpc when it connects, and then every interval for as long as the
+// context is not cancelled. Each answer is passed to set, along with the time it was received. On failure,
+// set is not called, so that the last known status is kept.
+func poll[M any](ctx context.Context, s *Service, d *distro.Distro, what string, interval, timeout time.Duration,
+	rpc func(wslserviceapi.WSLClient, context.Context, *wslserviceapi.Empty, ...grpc.CallOption) (M, error),
+	set func(ctx context.Context, msg M, checked time.Time)) {
+	for {
+		if msg, ok := pollOnce(ctx, d, what, timeout, rpc); ok {
+			set(ctx, msg, s.clock.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(interval):
+		}
+	}
+}
+
+// pollOnce asks the distro for a status with rpc. It returns false if the distro did not answer.
+func pollOnce[M any](ctx context.Context, d *distro.Distro, what string, timeout time.Duration,
+	rpc func(wslserviceapi.WSLClient, context.Context, *wslserviceapi.Empty, ...grpc.CallOption) (M, error)) (msg M, ok bool) {
+	client, err := d.Client()
+	if err != nil || client == nil {
+		log.Debugf(ctx, "WSLInstance service (%s): not checking the %s: distro is not connected", d.Name(), what)
+		return msg, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	msg, err = rpc(client, ctx, &wslserviceapi.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		log.Debugf(ctx, "WSLInstance service (%s): the Linux-side WSL service is too old to report its %s", d.Name(), what)
+		return msg, false
+	} else if err != nil {
+		log.Warningf(ctx, "WSLInstance service (%s): could not get the %s: %v", d.Name(), what, err)
+		return msg, false
+	}
+
+	return msg, true
+}
+
+// watchStatus polls the connected distro for its security status, update status and resource usage.
+func (s *Service) watchStatus(ctx context.Context, d *distro.Distro) {
+	// Computing the pending security updates requires reading the apt cache, which can take a while.
+	go poll(ctx, s, d, "security status", securityStatusInterval, s.timeouts.SecurityStatus,
+		wslserviceapi.WSLClient.GetSecurityStatus,
+		func(ctx context.Context, msg *wslserviceapi.SecurityStatus, checked time.Time) {
+			d.SetSecurityStatus(distro.SecurityStatus{
+				SecurityUpdates: msg.GetSecurityUpdates(),
+				ESMUpdates:      msg.GetEsmUpdates(),
+				Checked:         checked,
+			})
+			s.storeStatus(ctx, d, "security status")
+		})
+
+	go poll(ctx, s, d, "update status", updateStatusInterval, s.timeouts.ShortCall,
+		wslserviceapi.WSLClient.GetUpdateStatus,
+		func(ctx context.Context, msg *wslserviceapi.UpdateStatus, checked time.Time) {
+			d.SetUpdateStatus(distro.UpdateStatus{
+				RebootRequired: msg.GetRebootRequired(),
+				RebootPackages: msg.GetRebootPackages(),
+				PendingUpdates: msg.GetPendingUpdates(),
+				Checked:        checked,
+			})
+			s.storeStatus(ctx, d, "update status")
+		})
+
+	// The usage is not stored in the database, as it is outdated by the next startup anyway.
+	go poll(ctx, s, d, "resource usage", resourceUsageInterval, s.timeouts.ShortCall,
+		wslserviceapi.WSLClient.GetResourceUsage,
+		func(ctx context.Context, msg *wslserviceapi.ResourceUsage, checked time.Time) {
+			disk, err := vhdx.Size(d)
+			if err != nil {
+				log.Debugf(ctx, "WSLInstance service (%s): %v", d.Name(), err)
+			}
+
+			d.SetResourceUsage(distro.ResourceUsage{
+				MemoryBytes: msg.GetMemoryBytes(),
+				CPUPercent:  msg.GetCpuPercent(),
+				DiskBytes:   disk,
+				Checked:     checked,
+			})
+		})
+}
+
+// storeStatus writes the status of the distro to the database. On failure, it is kept in memory only.
+func (s *Service) storeStatus(ctx context.Context, d *distro.Distro, what string) {
+	if err := s.db.DistroUpdated(d.Name()); err != nil {
+		log.Warningf(ctx, "WSLInstance service (%s): could not store the %s: %v", d.Name(), what, err)
+	}
+}
+
 func getPort(lis net.Listener) (int, error) {
 	_, port, err := net.SplitHostPort(lis.Addr().String())
 	if err != nil {
@@ -178,11 +491,16 @@ func propsFromInfo(info *agentapi.DistroInfo) (props distro.Properties, err erro
 	}
 
 	return distro.Properties{
-		DistroID:    info.GetId(),
-		VersionID:   info.GetVersionId(),
-		PrettyName:  info.GetPrettyName(),
-		ProAttached: info.GetProAttached(),
-		Hostname:    info.GetHostname(),
+		DistroID:             info.GetId(),
+		VersionID:            info.GetVersionId(),
+		PrettyName:           info.GetPrettyName(),
+		ProAttached:          info.GetProAttached(),
+		ProServices:          info.GetProServices(),
+		Hostname:             info.GetHostname(),
+		InstanceID:           info.GetInstanceId(),
+		LandscapeRegistered:  info.GetLandscapeRegistered(),
+		LandscapeScriptUsers: info.GetLandscapeScriptUsers(),
+		ServiceVersion:       info.GetServiceVersion(),
 	}, nil
 }
 
@@ -190,7 +508,7 @@ func propsFromInfo(info *agentapi.DistroInfo) (props distro.Properties, err erro
 // log in the case error.
 func (s *Service) landscapeSendUpdatedInfo(ctx context.Context) {
 	go func() {
-		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, s.timeouts.LandscapeSend)
 		defer cancel()
 
 		if err := s.landscape.SendUpdatedInfo(ctx); err != nil {
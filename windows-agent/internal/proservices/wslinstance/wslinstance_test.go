@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
+	"reflect"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -13,6 +15,9 @@ import (
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/connection"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
@@ -23,7 +28,9 @@ import (
 	wsl "github.com/ubuntu/gowsl"
 	wslmock "github.com/ubuntu/gowsl/mock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 func TestMain(m *testing.M) {
@@ -160,7 +167,7 @@ func TestConnected(t *testing.T) {
 				require.NoError(t, err, "Setup: could not get pre-existing distro into database")
 
 				// Submit a deferred task to check if it is reloaded
-				err = d.SubmitDeferredTasks(testTask{ID: d.GUID()})
+				err = d.SubmitDeferredTasks(testTask{ID: d.GUID().String()})
 				require.NoError(t, err, "Setup: submitting a deferred task should succeed")
 			}
 
@@ -200,6 +207,7 @@ func TestConnected(t *testing.T) {
 				PrettyName:  "Ubuntu 22.04.1 LTS",
 				ProAttached: false,
 				Hostname:    "TestMachine",
+				InstanceId:  "8f3c1ad2-5b7e-4c1e-9a6d-2f4b8e0c7d91",
 			}
 			wsl.sendInfo(t, info)
 
@@ -221,6 +229,9 @@ func TestConnected(t *testing.T) {
 			// Ensure we got matching properties on the agent side.
 			props := propsFromInfo(t, info)
 			require.Equal(t, props, d.Properties(), "Distro properties should match those sent via the SendInfo.")
+			require.Eventually(t, func() bool {
+				return !d.LastContact().IsZero()
+			}, time.Second, 10*time.Millisecond, "Distro last contact should be set after the first contact")
 
 			// Ensure landscape sent an update
 			const landscapeTimeout = 15 * time.Second
@@ -264,7 +275,7 @@ func TestConnected(t *testing.T) {
 
 				if tc.distroAlreadyInDatabase {
 					require.Eventually(t, func() bool {
-						return completedTeskTasks.Has(d.GUID())
+						return completedTeskTasks.Has(d.GUID().String())
 					}, 10*time.Second, 100*time.Millisecond, "Deferred task should have been loaded after contact")
 				}
 			}
@@ -283,6 +294,9 @@ func TestConnected(t *testing.T) {
 
 			// Send new info with changing parameter.
 			info.ProAttached = true
+			info.ProServices = []string{"esm-infra", "usg"}
+			info.LandscapeRegistered = true
+			firstContact := d.LastContact()
 			wsl.sendInfo(t, info)
 
 			// We have sent info for a second time
@@ -296,8 +310,11 @@ func TestConnected(t *testing.T) {
 			// One of the property should have changed.
 			props = propsFromInfo(t, info)
 			require.Eventually(t, func() bool {
-				return d.Properties() == props
+				return reflect.DeepEqual(d.Properties(), props)
 			}, time.Second, 10*time.Millisecond, "Distro properties should be refreshed after every call to SendInfo to the control stream")
+			require.Eventually(t, func() bool {
+				return d.LastContact().After(firstContact)
+			}, time.Second, 10*time.Millisecond, "Distro last contact should be refreshed after every call to SendInfo to the control stream")
 
 			// The database has been updated after the second info
 			now = afterPropertiesRefreshed
@@ -318,6 +335,458 @@ func TestConnected(t *testing.T) {
 	}
 }
 
+func TestReconnection(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	provisioning := &provisioningMock{}
+	db, err := database.New(ctx, t.TempDir(), provisioning)
+	require.NoError(t, err, "Setup: empty database New() should return no error")
+	defer db.Close(ctx)
+
+	srv, err := newWrappedService(ctx, db, &landscapeCtlMock{})
+	require.NoError(t, err, "Setup: wslinstance New() should never return an error")
+
+	grpcServer, ctrlAddr := serveWSLInstance(t, ctx, srv)
+	defer grpcServer.Stop()
+
+	info := &agentapi.DistroInfo{WslName: distroName, Id: "ubuntu", VersionId: "22.04"}
+
+	// First connection.
+	oldWsl := newWslDistroMock(t, ctx, ctrlAddr)
+	defer oldWsl.stopClient()
+	go oldWsl.serve(false)
+	oldWsl.sendInfo(t, info)
+
+	var d *distro.Distro
+	require.Eventually(t, func() bool {
+		var ok bool
+		d, ok = db.Get(distroName)
+		if !ok {
+			return false
+		}
+		active, err := d.IsActive()
+		return err == nil && active
+	}, 15*time.Second, 10*time.Millisecond, "Distro should become active after its first connection")
+
+	// The service reconnects while the previous stream is still open, as the agent is not told
+	// right away that the previous service is gone.
+	newWsl := newWslDistroMock(t, ctx, ctrlAddr)
+	defer newWsl.stopClient()
+	go newWsl.serve(false)
+	defer newWsl.stopServer()
+	newWsl.sendInfo(t, info)
+
+	oldWsl.stopServer()
+	requireReachesService(t, d, "Distro should be connected to the new service")
+
+	// The previous stream ends after the new connection was set.
+	oldWsl.stopClient()
+	_, stopped := srv.wait(5 * time.Second)
+	require.True(t, stopped, "Connected should return when the previous stream ends")
+
+	requireReachesService(t, d, "Distro should still be connected to the new service after the previous stream ended")
+
+	got, ok := db.Get(distroName)
+	require.True(t, ok, "Distro should still be in the database")
+	require.Same(t, d, got, "Reconnecting should not replace the distro")
+	require.Len(t, db.GetAll(), 1, "Reconnecting should not add a distro to the database")
+	require.Equal(t, int32(1), provisioning.count.Load(), "Reconnecting should not provision the distro again")
+}
+
+func TestDropConnections(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	db, err := database.New(ctx, t.TempDir(), &provisioningMock{})
+	require.NoError(t, err, "Setup: empty database New() should return no error")
+	defer db.Close(ctx)
+
+	srv, err := newWrappedService(ctx, db, &landscapeCtlMock{})
+	require.NoError(t, err, "Setup: wslinstance New() should never return an error")
+
+	grpcServer, ctrlAddr := serveWSLInstance(t, ctx, srv)
+	defer grpcServer.Stop()
+
+	info := &agentapi.DistroInfo{WslName: distroName, Id: "ubuntu", VersionId: "22.04"}
+
+	wslMock := newWslDistroMock(t, ctx, ctrlAddr)
+	defer wslMock.stopClient()
+	go wslMock.serve(false)
+	defer wslMock.stopServer()
+	wslMock.sendInfo(t, info)
+
+	require.Eventually(t, func() bool {
+		d, ok := db.Get(distroName)
+		if !ok {
+			return false
+		}
+		active, err := d.IsActive()
+		return err == nil && active
+	}, 15*time.Second, 10*time.Millisecond, "Distro should become active after connecting")
+
+	srv.DropConnections(ctx)
+
+	err, stopped := srv.wait(5 * time.Second)
+	require.True(t, stopped, "Connected should return when the connections are dropped")
+	require.Equal(t, codes.Unavailable, status.Code(err), "Connected should tell the distro that it may reconnect")
+
+	d, ok := db.Get(distroName)
+	require.True(t, ok, "Distro should still be in the database")
+	client, err := d.Client()
+	require.NoError(t, err, "Client should return no error")
+	require.Nil(t, client, "Dropping the connections should release the connection of the distro")
+}
+
+func TestGetDistroByClient(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	db, err := database.New(ctx, t.TempDir(), &provisioningMock{})
+	require.NoError(t, err, "Setup: empty database New() should return no error")
+	defer db.Close(ctx)
+
+	srv, err := newWrappedService(ctx, db, &landscapeCtlMock{})
+	require.NoError(t, err, "Setup: wslinstance New() should never return an error")
+
+	grpcServer, ctrlAddr := serveWSLInstance(t, ctx, srv)
+	defer grpcServer.Stop()
+
+	info := &agentapi.DistroInfo{WslName: distroName, Id: "ubuntu", VersionId: "22.04", ServiceVersion: "1.2.3", BootId: "boot-id"}
+
+	wslMock := newWslDistroMock(t, ctx, ctrlAddr)
+	defer wslMock.stopClient()
+	go wslMock.serve(false)
+	defer wslMock.stopServer()
+	wslMock.sendInfo(t, info)
+
+	var d *distro.Distro
+	var md connection.Metadata
+	require.Eventually(t, func() bool {
+		var ok bool
+		d, md, ok = srv.GetDistroByClient(wslMock.localAddr)
+		return ok
+	}, 15*time.Second, 10*time.Millisecond, "GetDistroByClient should find the distro once it is connected")
+
+	want, ok := db.Get(distroName)
+	require.True(t, ok, "Distro should be in the database")
+	require.Same(t, want, d, "GetDistroByClient should return the distro connected from the address")
+
+	require.Equal(t, distroName, md.DistroName, "Metadata should contain the name of the distro")
+	require.Equal(t, d.GUID(), md.GUID, "Metadata should contain the GUID of the distro")
+	require.Equal(t, "1.2.3", md.ServiceVersion, "Metadata should contain the version of the WSL service")
+	require.Equal(t, "boot-id", md.BootID, "Metadata should contain the boot ID of the distro")
+	require.Equal(t, wslMock.localAddr, md.Peer, "Metadata should contain the address the distro connected from")
+	require.False(t, md.ConnectedAt.IsZero(), "Metadata should contain when the distro connected")
+
+	_, _, ok = srv.GetDistroByClient("localhost:1")
+	require.False(t, ok, "GetDistroByClient should not find distros that did not connect from the address")
+
+	wslMock.stopClient()
+	_, stopped := srv.wait(5 * time.Second)
+	require.True(t, stopped, "Connected should return when the stream ends")
+
+	_, _, ok = srv.GetDistroByClient(wslMock.localAddr)
+	require.False(t, ok, "GetDistroByClient should not find distros that disconnected")
+}
+
+func TestSecurityStatus(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		unimplemented bool
+		reportErr     bool
+
+		wantStatus bool
+	}{
+		"Success storing the security status": {wantStatus: true},
+
+		"No status when the Linux-side service is too old":      {unimplemented: true},
+		"No status when the Linux-side service fails to get it": {reportErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if wsl.MockAvailable() {
+				t.Parallel()
+			}
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			dbDir := t.TempDir()
+			db, err := database.New(ctx, dbDir, nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			now := time.Date(2024, time.April, 1, 12, 30, 0, 0, time.UTC)
+			srv, err := newWrappedService(ctx, db, &landscapeCtlMock{}, wslinstance.WithClock(clock.NewMock(now)))
+			require.NoError(t, err, "Setup: wslinstance New() should never return an error")
+
+			grpcServer, ctrlAddr := serveWSLInstance(t, ctx, srv)
+			defer grpcServer.Stop()
+
+			wsl := newWslDistroMock(t, ctx, ctrlAddr)
+			defer wsl.stopClient()
+
+			wsl.service = &wslServiceMock{securityStatusErr: tc.reportErr}
+			if !tc.unimplemented {
+				wsl.service.securityStatus = &wslserviceapi.SecurityStatus{SecurityUpdates: 3, EsmUpdates: 19}
+			}
+
+			go wsl.serve(false)
+			defer wsl.stopServer()
+
+			wsl.sendInfo(t, &agentapi.DistroInfo{WslName: distroName, Id: "ubuntu", VersionId: "22.04"})
+
+			var d *distro.Distro
+			require.Eventually(t, func() bool {
+				var ok bool
+				d, ok = db.Get(distroName)
+				return ok
+			}, 10*time.Second, 10*time.Millisecond, "Distro should have been added to the database")
+
+			if !tc.wantStatus {
+				require.Eventually(t, func() bool { return wsl.service.calls.Load() > 0 }, 10*time.Second, 10*time.Millisecond,
+					"The distro should have been asked for its security status")
+				require.Zero(t, d.SecurityStatus(), "No security status should have been stored")
+				return
+			}
+
+			require.Eventually(t, func() bool {
+				return !d.SecurityStatus().Checked.IsZero()
+			}, 10*time.Second, 10*time.Millisecond, "The security status should have been stored")
+
+			require.Equal(t, now, d.LastContact(), "The last contact should be dated by the clock of the service")
+
+			got := d.SecurityStatus()
+			require.Equal(t, now, got.Checked, "The status should be dated by the clock of the service")
+			require.Equal(t, uint32(3), got.SecurityUpdates, "Unexpected number of security updates")
+			require.Equal(t, uint32(19), got.ESMUpdates, "Unexpected number of ESM updates")
+
+			out, err := os.ReadFile(filepath.Join(dbDir, consts.DatabaseFileName))
+			require.NoError(t, err, "Could not read the database file")
+			require.Contains(t, string(out), "esmupdates: 19", "The security status should have been stored in the database")
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		unimplemented bool
+		reportErr     bool
+
+		wantStatus bool
+	}{
+		"Success storing the update status": {wantStatus: true},
+
+		"No status when the Linux-side service is too old":      {unimplemented: true},
+		"No status when the Linux-side service fails to get it": {reportErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if wsl.MockAvailable() {
+				t.Parallel()
+			}
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			dbDir := t.TempDir()
+			db, err := database.New(ctx, dbDir, nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			now := time.Date(2024, time.April, 1, 12, 30, 0, 0, time.UTC)
+			srv, err := newWrappedService(ctx, db, &landscapeCtlMock{}, wslinstance.WithClock(clock.NewMock(now)))
+			require.NoError(t, err, "Setup: wslinstance New() should never return an error")
+
+			grpcServer, ctrlAddr := serveWSLInstance(t, ctx, srv)
+			defer grpcServer.Stop()
+
+			wsl := newWslDistroMock(t, ctx, ctrlAddr)
+			defer wsl.stopClient()
+
+			wsl.service = &wslServiceMock{updateStatusErr: tc.reportErr}
+			if !tc.unimplemented {
+				wsl.service.updateStatus = &wslserviceapi.UpdateStatus{RebootRequired: true, RebootPackages: []string{"linux-base"}, PendingUpdates: 7}
+			}
+
+			go wsl.serve(false)
+			defer wsl.stopServer()
+
+			wsl.sendInfo(t, &agentapi.DistroInfo{WslName: distroName, Id: "ubuntu", VersionId: "22.04"})
+
+			var d *distro.Distro
+			require.Eventually(t, func() bool {
+				var ok bool
+				d, ok = db.Get(distroName)
+				return ok
+			}, 10*time.Second, 10*time.Millisecond, "Distro should have been added to the database")
+
+			if !tc.wantStatus {
+				require.Eventually(t, func() bool { return wsl.service.updateStatusCalls.Load() > 0 }, 10*time.Second, 10*time.Millisecond,
+					"The distro should have been asked for its update status")
+				require.Zero(t, d.UpdateStatus(), "No update status should have been stored")
+				return
+			}
+
+			require.Eventually(t, func() bool {
+				return !d.UpdateStatus().Checked.IsZero()
+			}, 10*time.Second, 10*time.Millisecond, "The update status should have been stored")
+
+			got := d.UpdateStatus()
+			require.Equal(t, now, got.Checked, "The status should be dated by the clock of the service")
+			require.True(t, got.RebootRequired, "The distro should require a reboot")
+			require.Equal(t, []string{"linux-base"}, got.RebootPackages, "Unexpected packages requiring a reboot")
+			require.Equal(t, uint32(7), got.PendingUpdates, "Unexpected number of pending updates")
+
+			out, err := os.ReadFile(filepath.Join(dbDir, consts.DatabaseFileName))
+			require.NoError(t, err, "Could not read the database file")
+			require.Contains(t, string(out), "pendingupdates: 7", "The update status should have been stored in the database")
+		})
+	}
+}
+
+func TestResourceUsage(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	testCases := map[string]struct {
+		unimplemented bool
+		reportErr     bool
+
+		wantStatus bool
+	}{
+		"Success storing the resource usage": {wantStatus: true},
+
+		"No usage when the Linux-side service is too old":      {unimplemented: true},
+		"No usage when the Linux-side service fails to get it": {reportErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if wsl.MockAvailable() {
+				t.Parallel()
+			}
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			dbDir := t.TempDir()
+			db, err := database.New(ctx, dbDir, nil)
+			require.NoError(t, err, "Setup: empty database New() should return no error")
+			defer db.Close(ctx)
+
+			now := time.Date(2024, time.April, 1, 12, 30, 0, 0, time.UTC)
+			srv, err := newWrappedService(ctx, db, &landscapeCtlMock{}, wslinstance.WithClock(clock.NewMock(now)))
+			require.NoError(t, err, "Setup: wslinstance New() should never return an error")
+
+			grpcServer, ctrlAddr := serveWSLInstance(t, ctx, srv)
+			defer grpcServer.Stop()
+
+			wsl := newWslDistroMock(t, ctx, ctrlAddr)
+			defer wsl.stopClient()
+
+			wsl.service = &wslServiceMock{resourceUsageErr: tc.reportErr}
+			if !tc.unimplemented {
+				wsl.service.resourceUsage = &wslserviceapi.ResourceUsage{MemoryBytes: 1 << 30, CpuPercent: 12.5}
+			}
+
+			go wsl.serve(false)
+			defer wsl.stopServer()
+
+			wsl.sendInfo(t, &agentapi.DistroInfo{WslName: distroName, Id: "ubuntu", VersionId: "22.04"})
+
+			var d *distro.Distro
+			require.Eventually(t, func() bool {
+				var ok bool
+				d, ok = db.Get(distroName)
+				return ok
+			}, 10*time.Second, 10*time.Millisecond, "Distro should have been added to the database")
+
+			if !tc.wantStatus {
+				require.Eventually(t, func() bool { return wsl.service.resourceUsageCalls.Load() > 0 }, 10*time.Second, 10*time.Millisecond,
+					"The distro should have been asked for its resource usage")
+				require.Zero(t, d.ResourceUsage(), "No resource usage should have been stored")
+				return
+			}
+
+			require.Eventually(t, func() bool {
+				return !d.ResourceUsage().Checked.IsZero()
+			}, 10*time.Second, 10*time.Millisecond, "The resource usage should have been stored")
+
+			got := d.ResourceUsage()
+			require.Equal(t, now, got.Checked, "The status should be dated by the clock of the service")
+			require.Equal(t, uint64(1<<30), got.MemoryBytes, "Unexpected memory usage")
+			require.InDelta(t, 12.5, got.CPUPercent, 1e-9, "Unexpected CPU usage")
+		})
+	}
+}
+
+// requireReachesService asserts that the distro is connected to a Linux-side service that answers.
+func requireReachesService(t *testing.T, d *distro.Distro, msg string) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		client, err := d.Client()
+		if err != nil || client == nil {
+			return false
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// The mock service is unimplemented: getting that error back means it was reached.
+		_, err = client.Ping(ctx, &wslserviceapi.Empty{})
+		return status.Code(err) == codes.Unimplemented
+	}, 10*time.Second, 100*time.Millisecond, msg)
+}
+
 // testLoggerInterceptor replaces the logging middleware by printing the return
 // error of Connected to the test Log.
 //
@@ -341,8 +810,8 @@ type wrappedService struct {
 
 // newWrappedService is a wrapper around wslinstance.New. It initializes the monitoring
 // around the service.
-func newWrappedService(ctx context.Context, db *database.DistroDB, landscape *landscapeCtlMock) (s wrappedService, err error) {
-	inst, err := wslinstance.New(ctx, db, landscape)
+func newWrappedService(ctx context.Context, db *database.DistroDB, landscape *landscapeCtlMock, args ...wslinstance.Option) (s wrappedService, err error) {
+	inst, err := wslinstance.New(ctx, db, landscape, args...)
 	return wrappedService{
 		Service: inst,
 		Errch:   make(chan error),
@@ -393,7 +862,7 @@ func serveWSLInstance(t *testing.T, ctx context.Context, srv wrappedService) (se
 // landscapeCtlMock mocks the landscape client.
 //
 // disconnected and err are inputs to manipulate mock behaviour.
-// updateCount is used to assert that the SendUpdatedInfo function has been called.
+// updateCount is used to assert that the SendUpdatedInfo or QueueUpdatedInfo functions have been called.
 type landscapeCtlMock struct {
 	disconnected bool
 	err          bool
@@ -414,14 +883,36 @@ func (c *landscapeCtlMock) SendUpdatedInfo(ctx context.Context) error {
 	return nil
 }
 
+func (c *landscapeCtlMock) QueueUpdatedInfo(ctx context.Context) {
+	if c.disconnected {
+		return
+	}
+
+	c.updateCount.Add(1)
+}
+
+// provisioningMock counts how many times the provisioning tasks are requested.
+type provisioningMock struct {
+	count atomic.Int32
+}
+
+func (p *provisioningMock) ProvisioningTasks(context.Context, string) ([]task.Task, error) {
+	p.count.Add(1)
+	return nil, nil
+}
+
 // wslDistroMock mocks the actions performed by the Linux-side client and services.
 type wslDistroMock struct {
 	grpcServer *grpc.Server
+	service    *wslServiceMock
 	ctrlStream agentapi.WSLInstance_ConnectedClient
 
 	errorDuringServe chan error
 
 	clientStop func()
+
+	// localAddr is the address the control stream is connected from.
+	localAddr string
 }
 
 // newWslDistroMock creates a wslDistroMock, establishing a connection to the control stream.
@@ -435,7 +926,15 @@ func newWslDistroMock(t *testing.T, ctx context.Context, ctrlAddr string) (mock
 		errorDuringServe: make(chan error),
 	}
 
-	ctrlConn, err := grpc.DialContext(ctx, ctrlAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	ctrlConn, err := grpc.DialContext(ctx, ctrlAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+			if err == nil {
+				mock.localAddr = conn.LocalAddr().String()
+			}
+			return conn, err
+		}))
 	require.NoError(t, err, "wslDistroMock: could not dial control address")
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -485,7 +984,11 @@ func (m *wslDistroMock) serve(wantErrNeverReceivePort bool) {
 
 		log.Printf("wslDistroMock: Listening to: %s", addr)
 
-		wslserviceapi.RegisterWSLServer(m.grpcServer, &wslserviceapi.UnimplementedWSLServer{})
+		service := m.service
+		if service == nil {
+			service = &wslServiceMock{}
+		}
+		wslserviceapi.RegisterWSLServer(m.grpcServer, service)
 
 		_ = m.grpcServer.Serve(lis)
 		return nil
@@ -495,6 +998,58 @@ func (m *wslDistroMock) serve(wantErrNeverReceivePort bool) {
 	close(m.errorDuringServe)
 }
 
+// wslServiceMock is a Linux-side service where every call fails as unimplemented, except for
+// GetSecurityStatus when a security status is set, GetUpdateStatus when an update status is set,
+// and GetResourceUsage when a resource usage is set.
+type wslServiceMock struct {
+	wslserviceapi.UnimplementedWSLServer
+
+	securityStatus    *wslserviceapi.SecurityStatus
+	securityStatusErr bool
+	calls             atomic.Int32
+
+	updateStatus      *wslserviceapi.UpdateStatus
+	updateStatusErr   bool
+	updateStatusCalls atomic.Int32
+
+	resourceUsage      *wslserviceapi.ResourceUsage
+	resourceUsageErr   bool
+	resourceUsageCalls atomic.Int32
+}
+
+func (s *wslServiceMock) GetSecurityStatus(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.SecurityStatus, error) {
+	s.calls.Add(1)
+	if s.securityStatusErr {
+		return nil, errors.New("mock error")
+	}
+	if s.securityStatus == nil {
+		return s.UnimplementedWSLServer.GetSecurityStatus(ctx, msg)
+	}
+	return s.securityStatus, nil
+}
+
+func (s *wslServiceMock) GetUpdateStatus(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.UpdateStatus, error) {
+	s.updateStatusCalls.Add(1)
+	if s.updateStatusErr {
+		return nil, errors.New("mock error")
+	}
+	if s.updateStatus == nil {
+		return s.UnimplementedWSLServer.GetUpdateStatus(ctx, msg)
+	}
+	return s.updateStatus, nil
+}
+
+func (s *wslServiceMock) GetResourceUsage(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.ResourceUsage, error) {
+	s.resourceUsageCalls.Add(1)
+	if s.resourceUsageErr {
+		return nil, errors.New("mock error")
+	}
+	if s.resourceUsage == nil {
+		return s.UnimplementedWSLServer.GetResourceUsage(ctx, msg)
+	}
+	return s.resourceUsage, nil
+}
+
 // requireNoServeError checks if serve has asyncronously returned an error.
 func (m *wslDistroMock) requireNoServeError(t *testing.T) {
 	t.Helper()
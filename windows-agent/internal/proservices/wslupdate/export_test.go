@@ -0,0 +1,21 @@
+package wslupdate
+
+import (
+	"context"
+	"time"
+)
+
+// WithUpdateFunc overrides how WSL is updated.
+func WithUpdateFunc(f func(context.Context) error) Option {
+	return func(o *options) {
+		o.update = f
+	}
+}
+
+// WithDrainTimeout overrides how long the tasks in progress are waited for, and how often they are checked.
+func WithDrainTimeout(timeout, interval time.Duration) Option {
+	return func(o *options) {
+		o.drainTimeout = timeout
+		o.drainInterval = interval
+	}
+}
@@ -0,0 +1,12 @@
+//go:build gowslmock
+
+package wslupdate
+
+import (
+	"context"
+)
+
+// updateWSL mocks running 'wsl --update', which succeeds unless the context is cancelled.
+func updateWSL(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,12 @@
+//go:build !gowslmock
+
+package wslupdate
+
+import (
+	"context"
+)
+
+// updateWSL is a stub function that panics. Use the gowslmock in order to use it in Linux.
+func updateWSL(ctx context.Context) error {
+	panic("updateWSL: this function can only be run on Windows")
+}
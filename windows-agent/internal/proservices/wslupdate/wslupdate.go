@@ -0,0 +1,224 @@
+// Package wslupdate updates WSL itself on request, coordinating with the distros: their tasks are
+// drained first, the user is warned, and the distros that were running are started again afterwards
+// so that their wsl-pro-service reconnects to the agent.
+package wslupdate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/preflight"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
+	"github.com/ubuntu/decorate"
+	wsl "github.com/ubuntu/gowsl"
+)
+
+const (
+	// defaultDrainTimeout is how long the tasks in progress are waited for before updating anyway.
+	defaultDrainTimeout = 5 * time.Minute
+
+	// defaultDrainInterval is how often the distros are checked while draining their tasks.
+	defaultDrainInterval = time.Second
+)
+
+// Pauser holds back the tasks of every distro while WSL is updated.
+type Pauser interface {
+	// Set pauses or resumes the agent. It returns true if the state changed.
+	Set(paused bool) bool
+}
+
+// Preflight checks WSL again after it is updated, so that the problems it solved are no longer reported.
+type Preflight interface {
+	Run(ctx context.Context) []preflight.Problem
+}
+
+// Updater updates WSL.
+type Updater struct {
+	db *database.DistroDB
+
+	pauser    Pauser
+	notify    func(context.Context)
+	preflight Preflight
+	update    func(context.Context) error
+
+	drainTimeout  time.Duration
+	drainInterval time.Duration
+
+	// mu is held while an update is in progress.
+	mu sync.Mutex
+}
+
+type options struct {
+	pauser        Pauser
+	notify        func(context.Context)
+	preflight     Preflight
+	update        func(context.Context) error
+	drainTimeout  time.Duration
+	drainInterval time.Duration
+}
+
+// Option is an optional argument for the updater.
+type Option = func(*options)
+
+// WithPauser holds back the tasks of every distro while WSL is updated. Otherwise, only the tasks
+// in progress are waited for.
+func WithPauser(p Pauser) Option {
+	return func(o *options) {
+		o.pauser = p
+	}
+}
+
+// WithNotifier warns the user before WSL is updated.
+func WithNotifier(notify func(context.Context)) Option {
+	return func(o *options) {
+		o.notify = notify
+	}
+}
+
+// WithPreflight runs the preflight checks again after WSL is updated.
+func WithPreflight(p Preflight) Option {
+	return func(o *options) {
+		o.preflight = p
+	}
+}
+
+// New creates an updater.
+func New(db *database.DistroDB, args ...Option) *Updater {
+	opts := options{
+		notify:        func(context.Context) {},
+		update:        updateWSL,
+		drainTimeout:  defaultDrainTimeout,
+		drainInterval: defaultDrainInterval,
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return &Updater{
+		db:            db,
+		pauser:        opts.pauser,
+		notify:        opts.notify,
+		preflight:     opts.preflight,
+		update:        opts.update,
+		drainTimeout:  opts.drainTimeout,
+		drainInterval: opts.drainInterval,
+	}
+}
+
+// Update updates WSL. No task starts while it is being updated, and the tasks in progress are
+// waited for up to a timeout: the ones that are still running are interrupted by the shutdown of
+// WSL and retried afterwards. Only one update can be in progress at a time.
+func (u *Updater) Update(ctx context.Context) (err error) {
+	defer decorate.OnError(&err, "could not update WSL")
+
+	if !u.mu.TryLock() {
+		return errors.New("WSL is already being updated")
+	}
+	defer u.mu.Unlock()
+
+	if u.pauser != nil && u.pauser.Set(true) {
+		log.Info(ctx, "WSL update: holding back tasks")
+		defer u.pauser.Set(false)
+	}
+
+	if err := u.drain(ctx); err != nil {
+		return err
+	}
+
+	u.notify(ctx)
+
+	running := u.runningDistros()
+
+	log.Info(ctx, "WSL update: shutting down WSL")
+	if err := wslcall.Command(ctx, "shutting down WSL", wsl.Shutdown); err != nil {
+		return err
+	}
+
+	// The distros must be started again whether the update succeeds or not.
+	defer u.wake(ctx, running)
+
+	log.Info(ctx, "WSL update: updating WSL")
+	if err := u.update(ctx); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "WSL update: WSL was updated")
+
+	if u.preflight != nil {
+		u.preflight.Run(ctx)
+	}
+
+	return nil
+}
+
+// drain waits until no distro is processing a task, or until the drain timeout. It only returns
+// an error if the context is cancelled.
+func (u *Updater) drain(ctx context.Context) error {
+	timeout := time.After(u.drainTimeout)
+
+	for {
+		var busy []string
+		for _, d := range u.db.GetAll() {
+			if d.TaskInProgress() {
+				busy = append(busy, d.Name())
+			}
+		}
+
+		if len(busy) == 0 {
+			return nil
+		}
+
+		log.Debugf(ctx, "WSL update: waiting for the tasks in progress in %v", busy)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			log.Warningf(ctx, "WSL update: tasks still in progress after %s, they will be retried after the update: %v", u.drainTimeout, busy)
+			return nil
+		case <-time.After(u.drainInterval):
+		}
+	}
+}
+
+// runningDistros returns the managed distros that are running.
+func (u *Updater) runningDistros() []*distro.Distro {
+	var running []*distro.Distro
+	for _, d := range u.db.GetAll() {
+		if s, err := d.State(); err == nil && s == wsl.Running {
+			running = append(running, d)
+		}
+	}
+	return running
+}
+
+// wake starts the distros again so that their wsl-pro-service reconnects to the agent. They are
+// allowed to stop again as soon as WSL would normally stop them.
+func (u *Updater) wake(ctx context.Context, distros []*distro.Distro) {
+	for _, d := range distros {
+		if err := wakeOnce(d); err != nil {
+			log.Warningf(ctx, "WSL update: could not start distro %q again: %v", d.Name(), err)
+			continue
+		}
+		log.Infof(ctx, "WSL update: started distro %q again", d.Name())
+	}
+}
+
+func wakeOnce(d *distro.Distro) error {
+	if err := d.LockAwake(); err != nil {
+		return err
+	}
+
+	if err := d.ReleaseAwake(); err != nil {
+		return fmt.Errorf("could not release the distro: %v", err)
+	}
+
+	return nil
+}
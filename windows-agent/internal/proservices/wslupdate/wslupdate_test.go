@@ -0,0 +1,184 @@
+package wslupdate_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/database"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/distro"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/preflight"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/wslupdate"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+)
+
+func TestUpdate(t *testing.T) {
+	if wsl.MockAvailable() {
+		t.Parallel()
+	}
+
+	testCases := map[string]struct {
+		running        bool
+		taskInProgress bool
+		paused         bool
+		updateErr      bool
+		cancelCtx      bool
+
+		wantRunning bool
+		wantUpdated bool
+		wantErr     bool
+	}{
+		"Success":                                   {wantUpdated: true},
+		"Success starts running distros again":      {running: true, wantRunning: true, wantUpdated: true},
+		"Success after the drain timeout":           {taskInProgress: true, wantUpdated: true},
+		"Success keeps the agent paused if it was":  {paused: true, wantUpdated: true},
+		"Error when WSL cannot be updated":          {updateErr: true, wantErr: true},
+		"Error starts running distros again anyway": {running: true, updateErr: true, wantRunning: true, wantErr: true},
+		"Error when the context is cancelled":       {taskInProgress: true, cancelCtx: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if wsl.MockAvailable() {
+				t.Parallel()
+				ctx = wsl.WithMock(ctx, wslmock.New())
+			}
+
+			db, d := setupDistro(t, ctx)
+
+			if tc.running {
+				wslDistro := wsl.NewDistro(ctx, d.Name())
+				require.NoError(t, wslDistro.Shell(wsl.WithCommand("exit 0")), "Setup: could not start the distro")
+				//nolint:errcheck // Nothing we can do about it
+				t.Cleanup(func() { wslDistro.Terminate() })
+			}
+
+			if tc.taskInProgress {
+				// Without a wsl-pro-service to connect to, the task never gets past waiting for it.
+				require.NoError(t, d.SubmitTasks(&blockingTask{}), "Setup: could not submit the task")
+				require.Eventually(t, d.TaskInProgress, 5*time.Second, 10*time.Millisecond, "Setup: the task should have started")
+			}
+
+			pauser := pause.New(tc.paused)
+
+			var updated, notified atomic.Bool
+			update := func(context.Context) error {
+				updated.Store(true)
+				require.True(t, pauser.Paused(), "Tasks should be held back while WSL is updated")
+				if tc.updateErr {
+					return errors.New("mock error")
+				}
+				return nil
+			}
+
+			checker := &mockPreflight{}
+			u := wslupdate.New(db,
+				wslupdate.WithPauser(pauser),
+				wslupdate.WithNotifier(func(context.Context) { notified.Store(true) }),
+				wslupdate.WithPreflight(checker),
+				wslupdate.WithUpdateFunc(update),
+				wslupdate.WithDrainTimeout(time.Second, 10*time.Millisecond))
+
+			if tc.cancelCtx {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, 100*time.Millisecond)
+				defer cancel()
+			}
+
+			err := u.Update(ctx)
+			require.Equal(t, tc.paused, pauser.Paused(), "The agent should be left paused or resumed as it was")
+
+			if tc.wantErr {
+				require.Error(t, err, "Update should return an error")
+			} else {
+				require.NoError(t, err, "Update should return no error")
+			}
+
+			require.Equal(t, !tc.cancelCtx, notified.Load(), "The user should be warned before WSL is updated")
+			require.Equal(t, !tc.cancelCtx, updated.Load(), "WSL should be updated once the tasks are drained")
+			require.Equal(t, tc.wantUpdated, checker.runs.Load() == 1, "The preflight checks should run again only after a successful update")
+
+			state, err := d.State()
+			require.NoError(t, err, "State should return no error")
+			if tc.wantRunning {
+				require.Equal(t, wsl.Running, state, "Distros that were running should be started again")
+			} else if !tc.taskInProgress {
+				require.Equal(t, wsl.Stopped, state, "Distros that were not running should not be started")
+			}
+		})
+	}
+}
+
+func TestUpdateIsExclusive(t *testing.T) {
+	ctx := context.Background()
+	if wsl.MockAvailable() {
+		t.Parallel()
+		ctx = wsl.WithMock(ctx, wslmock.New())
+	}
+
+	db, _ := setupDistro(t, ctx)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	u := wslupdate.New(db, wslupdate.WithUpdateFunc(func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+
+	done := make(chan error)
+	go func() { done <- u.Update(ctx) }()
+	<-started
+
+	require.Error(t, u.Update(ctx), "Update should return an error while another update is in progress")
+
+	close(release)
+	require.NoError(t, <-done, "The first update should return no error")
+}
+
+func setupDistro(t *testing.T, ctx context.Context) (*database.DistroDB, *distro.Distro) {
+	t.Helper()
+
+	distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+	db, err := database.New(ctx, t.TempDir(), nil)
+	require.NoError(t, err, "Setup: database New should not return an error")
+	t.Cleanup(func() { db.Close(ctx) })
+
+	d, err := db.GetDistroAndUpdateProperties(ctx, distroName, distro.Properties{})
+	require.NoError(t, err, "Setup: could not add distro to the database")
+
+	return db, d
+}
+
+type mockPreflight struct {
+	runs atomic.Int32
+}
+
+func (m *mockPreflight) Run(context.Context) []preflight.Problem {
+	m.runs.Add(1)
+	return nil
+}
+
+// blockingTask is a task that never completes.
+type blockingTask struct{}
+
+func (blockingTask) Execute(ctx context.Context, _ wslserviceapi.WSLClient) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingTask) String() string {
+	return "Blocking task"
+}
+
+var _ task.Task = blockingTask{}
@@ -0,0 +1,276 @@
+// Package simulation implements fake distros that connect to the agent the same way as the
+// WSL-Pro-Service of a real distro would, so that the agent can be exercised without WSL.
+//
+// The distros are registered in the GoWSL mock, so the agent must be built with the gowslmock tag.
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	wsl "github.com/ubuntu/gowsl"
+	"github.com/ubuntu/gowsl/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// retryInterval is how long a simulated distro waits before connecting to the agent again.
+const retryInterval = time.Second
+
+// Names returns the names of n simulated distros.
+func Names(n int) []string {
+	names := make([]string, 0, n)
+	for i := range n {
+		names = append(names, fmt.Sprintf("Simulated-%03d", i+1))
+	}
+	return names
+}
+
+// Register registers the simulated distros in a GoWSL mock, and returns a context that makes every
+// WSL call use that mock. It fails if the agent was not built with the gowslmock tag.
+func Register(ctx context.Context, names []string) (context.Context, error) {
+	if !wsl.MockAvailable() {
+		return nil, errors.New("simulated distros require an agent built with the gowslmock tag")
+	}
+
+	m := mock.New()
+	for _, name := range names {
+		if err := m.WslRegisterDistribution(name, `C:\simulated\rootfs.tar.gz`); err != nil {
+			return nil, fmt.Errorf("could not register simulated distro %s: %v", name, err)
+		}
+	}
+
+	return wsl.WithMock(ctx, m), nil
+}
+
+// Run connects the simulated distros to the agent whose address is written in addrFile, and keeps
+// them connected until the context is cancelled. It waits for the address file to exist.
+func Run(ctx context.Context, addrFile string, names []string) {
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			NewDistro(name).Run(ctx, addrFile)
+		}()
+	}
+	wg.Wait()
+}
+
+// Distro is a simulated distro. It pretends to carry out the tasks it receives, and reports the
+// outcome to the agent as a real distro would.
+type Distro struct {
+	wslserviceapi.UnimplementedWSLServer
+
+	mu     sync.Mutex
+	info   *agentapi.DistroInfo
+	stream agentapi.WSLInstance_ConnectedClient
+}
+
+// NewDistro creates a simulated distro with the given name. It is not connected to the agent until Run is called.
+func NewDistro(name string) *Distro {
+	return &Distro{
+		info: &agentapi.DistroInfo{
+			WslName:    name,
+			Id:         "ubuntu",
+			VersionId:  "24.04",
+			PrettyName: "Ubuntu 24.04 LTS (simulated)",
+			Hostname:   strings.ToLower(name),
+			InstanceId: uuid.NewString(),
+		},
+	}
+}
+
+// Run connects to the agent and serves its requests until the context is cancelled, connecting again
+// every time the connection is lost.
+func (d *Distro) Run(ctx context.Context, addrFile string) {
+	for {
+		if err := d.serve(ctx, addrFile); err != nil && ctx.Err() == nil {
+			log.Debugf(ctx, "Simulated distro %s: %v", d.info.GetWslName(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// serve connects to the agent and serves its requests until the connection is lost.
+func (d *Distro) serve(ctx context.Context, addrFile string) error {
+	addr, err := os.ReadFile(addrFile)
+	if err != nil {
+		return fmt.Errorf("could not read agent address: %v", err)
+	}
+
+	// The agent listens on all interfaces, so only its port is relevant.
+	_, port, err := net.SplitHostPort(strings.TrimSpace(string(addr)))
+	if err != nil {
+		return fmt.Errorf("could not parse agent address: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The agent streams its logs back to its clients. These are discarded because the simulated
+	// distros run in the agent's own process, so the logs are already in its output.
+	discard := logrus.New()
+	discard.SetOutput(io.Discard)
+
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort("localhost", port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(log.StreamClientInterceptor(discard, log.WithClientID(d.info.GetWslName()))),
+	)
+	if err != nil {
+		return fmt.Errorf("could not dial agent: %v", err)
+	}
+	defer conn.Close()
+
+	stream, err := agentapi.NewWSLInstanceClient(conn).Connected(ctx)
+	if err != nil {
+		return fmt.Errorf("could not connect to agent: %v", err)
+	}
+
+	if err := d.setStream(stream); err != nil {
+		return err
+	}
+	defer d.setStream(nil) //nolint:errcheck // Nothing is sent when the stream is nil.
+
+	msg, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("did not receive a port from the agent: %v", err)
+	}
+
+	lis, err := net.Listen("tcp4", fmt.Sprintf("localhost:%d", msg.GetPort()))
+	if err != nil {
+		return fmt.Errorf("could not listen to the port sent by the agent: %v", err)
+	}
+
+	server := grpc.NewServer()
+	wslserviceapi.RegisterWSLServer(server, d)
+
+	go func() {
+		// Serve only returns once the server is stopped.
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	log.Infof(ctx, "Simulated distro %s: connected to the agent", d.info.GetWslName())
+
+	// The agent never sends more than one port, so this only returns when the connection is lost.
+	for {
+		if _, err := stream.Recv(); err != nil {
+			return fmt.Errorf("connection to the agent lost: %v", err)
+		}
+	}
+}
+
+// setStream replaces the stream to the agent, sending the distro info through it if it is not nil.
+func (d *Distro) setStream(stream agentapi.WSLInstance_ConnectedClient) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stream = stream
+	return d.sendInfo()
+}
+
+// update modifies the distro info and reports it to the agent.
+func (d *Distro) update(f func(*agentapi.DistroInfo)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f(d.info)
+	return d.sendInfo()
+}
+
+// sendInfo reports the distro info to the agent. The distro must be locked.
+func (d *Distro) sendInfo() error {
+	if d.stream == nil {
+		return nil
+	}
+
+	if err := d.stream.Send(d.info); err != nil {
+		return fmt.Errorf("could not send distro info: %v", err)
+	}
+	return nil
+}
+
+// ApplyProToken pretends to pro-attach the distro, or to detach it if the token is empty.
+func (d *Distro) ApplyProToken(ctx context.Context, msg *wslserviceapi.ProAttachInfo) (*wslserviceapi.Empty, error) {
+	err := d.update(func(info *agentapi.DistroInfo) {
+		info.ProAttached = msg.GetToken() != ""
+		info.ProServices = nil
+		if info.GetProAttached() {
+			info.ProServices = []string{"esm-apps", "esm-infra"}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &wslserviceapi.Empty{}, nil
+}
+
+// ApplyLandscapeConfig pretends to register the distro in Landscape, or to disable Landscape if
+// the configuration is empty.
+func (d *Distro) ApplyLandscapeConfig(ctx context.Context, msg *wslserviceapi.LandscapeConfig) (*wslserviceapi.LandscapeConfigResult, error) {
+	err := d.update(func(info *agentapi.DistroInfo) {
+		info.LandscapeRegistered = msg.GetConfiguration() != ""
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &wslserviceapi.LandscapeConfigResult{}, nil
+}
+
+// GetLandscapeConfigHash reports no hash, so that the agent always sends the config to simulated distros.
+func (d *Distro) GetLandscapeConfigHash(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.LandscapeConfigHash, error) {
+	return &wslserviceapi.LandscapeConfigHash{}, nil
+}
+
+// Ping replies to keep-alive requests.
+func (d *Distro) Ping(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.Empty, error) {
+	return &wslserviceapi.Empty{}, nil
+}
+
+// SetLocale accepts any locale.
+func (d *Distro) SetLocale(ctx context.Context, msg *wslserviceapi.Locale) (*wslserviceapi.Empty, error) {
+	return &wslserviceapi.Empty{}, nil
+}
+
+// ApplyWSLSettings accepts any settings except a default user, as simulated distros have no users.
+func (d *Distro) ApplyWSLSettings(ctx context.Context, msg *wslserviceapi.WSLSettings) (*wslserviceapi.Empty, error) {
+	if msg.GetDefaultUser() != "" {
+		return nil, errors.New("simulated distros have no users")
+	}
+	return &wslserviceapi.Empty{}, nil
+}
+
+// GetSecurityStatus reports a fixed number of pending updates, so that the fleet has something to aggregate.
+func (d *Distro) GetSecurityStatus(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.SecurityStatus, error) {
+	return &wslserviceapi.SecurityStatus{SecurityUpdates: 2, EsmUpdates: 5}, nil
+}
+
+// GetUpdateStatus reports a fixed number of pending updates and no need to reboot.
+func (d *Distro) GetUpdateStatus(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.UpdateStatus, error) {
+	return &wslserviceapi.UpdateStatus{PendingUpdates: 12}, nil
+}
+
+// GetResourceUsage reports a fixed resource usage, so that the fleet has something to show.
+func (d *Distro) GetResourceUsage(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.ResourceUsage, error) {
+	return &wslserviceapi.ResourceUsage{MemoryBytes: 64 << 20, CpuPercent: 0.5}, nil
+}
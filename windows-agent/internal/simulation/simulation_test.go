@@ -0,0 +1,208 @@
+package simulation_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/simulation"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, simulation.Names(0), "Names should return no names for zero distros")
+	require.Equal(t, []string{"Simulated-001", "Simulated-002"}, simulation.Names(2), "Unexpected simulated distro names")
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	names := simulation.Names(3)
+
+	ctx, err := simulation.Register(context.Background(), names)
+	require.NoError(t, err, "Register should return no error")
+
+	for _, name := range names {
+		registered, err := wsl.NewDistro(ctx, name).IsRegistered()
+		require.NoError(t, err, "IsRegistered should return no error")
+		require.True(t, registered, "Simulated distro %q should be registered", name)
+	}
+}
+
+func TestDistro(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		token     string
+		landscape string
+
+		wantAttached   bool
+		wantLandscape  bool
+		wantServicesOn bool
+	}{
+		"Success attaching":               {token: "TOKEN", wantAttached: true, wantServicesOn: true},
+		"Success registering":             {landscape: "[client]", wantLandscape: true},
+		"Success detaching and disabling": {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			agent := newFakeAgent(t)
+			d := simulation.NewDistro("SimulatedDistro")
+
+			// The distro must keep retrying until the agent writes its address.
+			go d.Run(ctx, agent.addrFile)
+			agent.writeAddress(t)
+
+			info := agent.receive(t)
+			require.Equal(t, "SimulatedDistro", info.GetWslName(), "Unexpected distro name")
+			require.False(t, info.GetProAttached(), "Simulated distro should start detached")
+
+			port := agent.sendPort(t)
+			client := dialDistro(t, port)
+
+			_, err := client.ApplyProToken(ctx, &wslserviceapi.ProAttachInfo{Token: tc.token})
+			require.NoError(t, err, "ApplyProToken should return no error")
+
+			info = agent.receive(t)
+			require.Equal(t, tc.wantAttached, info.GetProAttached(), "Unexpected pro-attachment status")
+			require.Equal(t, tc.wantServicesOn, len(info.GetProServices()) > 0, "Unexpected pro services")
+
+			_, err = client.ApplyLandscapeConfig(ctx, &wslserviceapi.LandscapeConfig{Configuration: tc.landscape})
+			require.NoError(t, err, "ApplyLandscapeConfig should return no error")
+
+			info = agent.receive(t)
+			require.Equal(t, tc.wantLandscape, info.GetLandscapeRegistered(), "Unexpected Landscape registration status")
+
+			_, err = client.Ping(ctx, &wslserviceapi.Empty{})
+			require.NoError(t, err, "Ping should return no error")
+
+			_, err = client.ApplyWSLSettings(ctx, &wslserviceapi.WSLSettings{DefaultUser: "ubuntu"})
+			require.Error(t, err, "ApplyWSLSettings should return an error when setting a default user")
+		})
+	}
+}
+
+// fakeAgent is a WSLInstance server that forwards the streams it receives to the test.
+type fakeAgent struct {
+	agentapi.UnimplementedWSLInstanceServer
+
+	addr     string
+	addrFile string
+	streams  chan agentapi.WSLInstance_ConnectedServer
+	stream   agentapi.WSLInstance_ConnectedServer
+	done     chan struct{}
+}
+
+func newFakeAgent(t *testing.T) *fakeAgent {
+	t.Helper()
+
+	lis, err := net.Listen("tcp4", "localhost:0")
+	require.NoError(t, err, "Setup: could not listen")
+
+	a := &fakeAgent{
+		addr:     lis.Addr().String(),
+		addrFile: filepath.Join(t.TempDir(), common.ListeningPortFileName),
+		streams:  make(chan agentapi.WSLInstance_ConnectedServer),
+		done:     make(chan struct{}),
+	}
+
+	server := grpc.NewServer()
+	agentapi.RegisterWSLInstanceServer(server, a)
+
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(func() {
+		close(a.done)
+		server.Stop()
+	})
+
+	return a
+}
+
+func (a *fakeAgent) Connected(stream agentapi.WSLInstance_ConnectedServer) error {
+	select {
+	case a.streams <- stream:
+	case <-a.done:
+		return nil
+	}
+
+	select {
+	case <-stream.Context().Done():
+	case <-a.done:
+	}
+	return nil
+}
+
+func (a *fakeAgent) writeAddress(t *testing.T) {
+	t.Helper()
+
+	// Wait for a retry so that the distro must cope with the address file missing.
+	time.Sleep(100 * time.Millisecond)
+
+	err := os.WriteFile(a.addrFile, []byte(a.addr), 0600)
+	require.NoError(t, err, "Setup: could not write address file")
+
+	select {
+	case a.stream = <-a.streams:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "Simulated distro did not connect to the agent")
+	}
+}
+
+func (a *fakeAgent) receive(t *testing.T) *agentapi.DistroInfo {
+	t.Helper()
+
+	info, err := a.stream.Recv()
+	require.NoError(t, err, "Could not receive distro info")
+	return info
+}
+
+func (a *fakeAgent) sendPort(t *testing.T) uint32 {
+	t.Helper()
+
+	lis, err := net.Listen("tcp4", "localhost:0")
+	require.NoError(t, err, "Setup: could not reserve a port")
+
+	//nolint:forcetypeassert // We know it is a TCP address.
+	port := uint32(lis.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, lis.Close(), "Setup: could not release the port")
+
+	err = a.stream.Send(&agentapi.Port{Port: port})
+	require.NoError(t, err, "Could not send port to the distro")
+
+	return port
+}
+
+func dialDistro(t *testing.T, port uint32) wslserviceapi.WSLClient {
+	t.Helper()
+
+	conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err, "Could not dial simulated distro")
+	t.Cleanup(func() { conn.Close() })
+
+	client := wslserviceapi.NewWSLClient(conn)
+
+	require.Eventually(t, func() bool {
+		_, err := client.Ping(context.Background(), &wslserviceapi.Empty{})
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond, "Simulated distro never started serving")
+
+	return client
+}
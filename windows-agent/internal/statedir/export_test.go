@@ -0,0 +1,11 @@
+package statedir
+
+// Migration is a migration between two consecutive layout versions.
+type Migration = migration
+
+// WithMigrations overrides the list of migrations.
+func WithMigrations(m ...Migration) Option {
+	return func(o *options) {
+		o.migrations = m
+	}
+}
@@ -0,0 +1,146 @@
+// Package statedir keeps track of the layout of the agent's private directory, and migrates the state
+// left behind by previous releases to the current layout.
+package statedir
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/ubuntu/decorate"
+)
+
+// migration transforms the state directory from one layout version to the next one.
+type migration func(ctx context.Context, dir string) error
+
+// migrations contains every migration, in order. Migration i transforms layout version i into
+// version i+1, so the current layout version is the number of migrations.
+//
+// Migrations must never be removed nor reordered: the state of any previous release must be
+// migrated by running all the migrations from its version onwards.
+var migrations = []migration{
+	// Releases prior to the versioning of the state directory already store the database, the
+	// task queues and the config (with the checksums of the registry data) in the current layout,
+	// so there is nothing to transform.
+	func(context.Context, string) error { return nil },
+}
+
+type options struct {
+	migrations []migration
+}
+
+// Option is an optional argument for Migrate.
+type Option = func(*options)
+
+// Migrate brings the state directory to the current layout version, running the pending
+// migrations in order. The version is recorded after every migration, so that a failed migration
+// is resumed on the next start.
+//
+// It fails without modifying anything if the directory was written by a newer release of the
+// agent, as its layout cannot be understood.
+func Migrate(ctx context.Context, dir string, args ...Option) (err error) {
+	defer decorate.OnError(&err, "could not migrate state directory")
+
+	opts := options{
+		migrations: migrations,
+	}
+
+	for _, f := range args {
+		f(&opts)
+	}
+
+	current := len(opts.migrations)
+
+	version, err := readVersion(dir)
+	if err != nil {
+		return err
+	}
+
+	if version > current {
+		return fmt.Errorf("the state directory has layout version %d, but this agent only supports up to version %d: downgrades are not supported", version, current)
+	}
+
+	for v := version; v < current; v++ {
+		log.Infof(ctx, "State directory: migrating layout from version %d to %d", v, v+1)
+
+		if err := opts.migrations[v](ctx, dir); err != nil {
+			return fmt.Errorf("migration from layout version %d to %d: %v", v, v+1, err)
+		}
+
+		if err := writeVersion(dir, v+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// kept are the entries of the state directory that Wipe leaves alone: they hold the data of the user
+// and the extensions installed by the administrator rather than the state of the agent.
+var kept = []string{consts.BackupsDirName, consts.PluginsDirName}
+
+// Wipe removes the state of the agent from the directory, so that the next start is a fresh one.
+// Exported distros and plugins are kept. The agent must not be using the directory.
+func Wipe(ctx context.Context, dir string) (err error) {
+	defer decorate.OnError(&err, "could not wipe state directory")
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if slices.Contains(kept, e.Name()) {
+			continue
+		}
+
+		log.Debugf(ctx, "State directory: removing %s", e.Name())
+		err = errors.Join(err, os.RemoveAll(filepath.Join(dir, e.Name())))
+	}
+
+	return err
+}
+
+// readVersion returns the layout version of the state directory. A directory without a version
+// marker has version zero.
+func readVersion(dir string) (int, error) {
+	out, err := os.ReadFile(filepath.Join(dir, consts.StateVersionFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("could not read layout version: %v", err)
+	}
+
+	v, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("could not parse layout version %q", out)
+	}
+
+	return v, nil
+}
+
+// writeVersion atomically records the layout version of the state directory.
+func writeVersion(dir string, v int) error {
+	path := filepath.Join(dir, consts.StateVersionFileName)
+	tmp := path + ".new"
+
+	if err := os.WriteFile(tmp, []byte(fmt.Sprintf("%d\n", v)), 0600); err != nil {
+		return fmt.Errorf("could not write layout version: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("could not write layout version: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package statedir_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/consts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/statedir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		version       string
+		failMigration int
+		breakVersion  bool
+
+		wantRan     []int
+		wantVersion string
+		wantErr     bool
+	}{
+		"Success with a directory without version":    {wantRan: []int{0, 1, 2}, wantVersion: "3\n"},
+		"Success migrating from a previous version":   {version: "1\n", wantRan: []int{1, 2}, wantVersion: "3\n"},
+		"Success when the version is current":         {version: "3\n", wantVersion: "3\n"},
+		"Success resuming after a migration failure":  {version: "2", wantRan: []int{2}, wantVersion: "3\n"},
+		"Success with surrounding spaces in the file": {version: " 2 \n", wantRan: []int{2}, wantVersion: "3\n"},
+
+		"Error when the version is newer":              {version: "4\n", wantVersion: "4\n", wantErr: true},
+		"Error when the version cannot be parsed":      {version: "three", wantVersion: "three", wantErr: true},
+		"Error when the version is negative":           {version: "-1", wantVersion: "-1", wantErr: true},
+		"Error when the version cannot be read":        {breakVersion: true, wantErr: true},
+		"Error when a migration fails":                 {version: "1\n", failMigration: 2, wantRan: []int{1, 2}, wantVersion: "2\n", wantErr: true},
+		"Error when the first pending migration fails": {failMigration: 1, wantRan: []int{0, 1}, wantVersion: "1\n", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dir := t.TempDir()
+			path := filepath.Join(dir, consts.StateVersionFileName)
+
+			if tc.version != "" {
+				err := os.WriteFile(path, []byte(tc.version), 0600)
+				require.NoError(t, err, "Setup: could not write version file")
+			}
+
+			if tc.breakVersion {
+				testutils.ReplaceFileWithDir(t, path, "Setup: could not replace version file with a directory")
+			}
+
+			var ran []int
+			var m []statedir.Migration
+			for i := range 3 {
+				m = append(m, func(_ context.Context, d string) error {
+					require.Equal(t, dir, d, "Migration should receive the state directory")
+					ran = append(ran, i)
+					if tc.failMigration == i && i != 0 {
+						return errors.New("mock error")
+					}
+					return nil
+				})
+			}
+
+			err := statedir.Migrate(ctx, dir, statedir.WithMigrations(m...))
+			if tc.wantErr {
+				require.Error(t, err, "Migrate should return an error")
+			} else {
+				require.NoError(t, err, "Migrate should return no error")
+			}
+
+			require.Equal(t, tc.wantRan, ran, "Unexpected migrations ran")
+
+			if tc.breakVersion {
+				return
+			}
+
+			got, err := os.ReadFile(path)
+			require.NoError(t, err, "Could not read version file")
+			require.Equal(t, tc.wantVersion, string(got), "Unexpected version recorded")
+		})
+	}
+}
+
+func TestMigrateCurrentLayout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	err := statedir.Migrate(context.Background(), dir)
+	require.NoError(t, err, "Migrate should return no error on an empty directory")
+
+	// A second start must find the directory up to date.
+	err = statedir.Migrate(context.Background(), dir)
+	require.NoError(t, err, "Migrate should return no error on a migrated directory")
+
+	require.FileExists(t, filepath.Join(dir, consts.StateVersionFileName), "The layout version should be recorded")
+}
+
+func TestWipe(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noDir bool
+	}{
+		"Success":                            {},
+		"Success when there is no directory": {noDir: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := filepath.Join(t.TempDir(), "state")
+			if !tc.noDir {
+				for _, sub := range []string{consts.BackupsDirName, consts.PluginsDirName, "queues"} {
+					err := os.MkdirAll(filepath.Join(dir, sub), 0700)
+					require.NoError(t, err, "Setup: could not create %s", sub)
+					err = os.WriteFile(filepath.Join(dir, sub, "file"), []byte("contents"), 0600)
+					require.NoError(t, err, "Setup: could not write file in %s", sub)
+				}
+				for _, f := range []string{consts.DatabaseFileName, consts.StateVersionFileName, "config"} {
+					err := os.WriteFile(filepath.Join(dir, f), []byte("contents"), 0600)
+					require.NoError(t, err, "Setup: could not write %s", f)
+				}
+			}
+
+			err := statedir.Wipe(context.Background(), dir)
+			require.NoError(t, err, "Wipe should return no error")
+
+			if tc.noDir {
+				require.NoDirExists(t, dir, "Wipe should not create the directory")
+				return
+			}
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err, "The directory should still exist")
+
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Name())
+			}
+			require.ElementsMatch(t, []string{consts.BackupsDirName, consts.PluginsDirName}, got, "Wipe should only keep the backups and the plugins")
+			require.FileExists(t, filepath.Join(dir, consts.BackupsDirName, "file"), "Wipe should keep the exported distros")
+		})
+	}
+}
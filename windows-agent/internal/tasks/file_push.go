@@ -0,0 +1,140 @@
+package tasks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	task.Register[FilePush]()
+}
+
+// fileChunkSize is how much of the file is sent in every message, well below the gRPC message size limit.
+const fileChunkSize = 1024 * 1024
+
+// FilePush is a task that copies a file from Windows into the distro, such as a certificate or a package.
+// The file is sent in chunks. If the transfer is interrupted, it is retried from where it stopped.
+type FilePush struct {
+	// Source is the path of the file on Windows.
+	Source string
+
+	// Destination is the absolute path of the file in the distro.
+	Destination string
+
+	// Mode is the permission bits of the file in the distro. Zero means 0644.
+	Mode uint32 `yaml:",omitempty"`
+}
+
+// Execute sends the file to the target WSL-Pro-Service.
+func (t FilePush) Execute(ctx context.Context, client wslserviceapi.WSLClient) (err error) {
+	f, err := os.Open(t.Source)
+	if err != nil {
+		// Retrying would not make the file appear.
+		return fmt.Errorf("could not open %s: %v", t.Source, err)
+	}
+	defer f.Close()
+
+	header, err := t.header(f)
+	if err != nil {
+		return err
+	}
+
+	st, err := client.GetFileTransferStatus(ctx, header)
+	if status.Code(err) == codes.Unimplemented {
+		return errors.New("the WSL Pro service of the distro is too old to receive files")
+	} else if err != nil {
+		return task.NeedsRetryError{SourceErr: fmt.Errorf("could not get the status of the transfer: %w", err)}
+	}
+
+	header.Offset = st.GetReceived()
+	if _, err := f.Seek(int64(header.Offset), io.SeekStart); err != nil {
+		return fmt.Errorf("could not resume reading %s at offset %d: %v", t.Source, header.Offset, err)
+	}
+
+	st, err = t.push(ctx, client, header, f)
+	if err != nil {
+		// What was sent so far is kept by the distro, so the retry resumes from there.
+		return task.NeedsRetryError{SourceErr: err}
+	}
+
+	if !st.GetComplete() {
+		return task.NeedsRetryError{SourceErr: fmt.Errorf("only %d of the %d bytes of %s were received", st.GetReceived(), header.GetSize(), t.Source)}
+	}
+
+	return nil
+}
+
+// header describes the file, reading it whole to compute its checksum.
+func (t FilePush) header(f *os.File) (*wslserviceapi.FileHeader, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", t.Source, err)
+	}
+
+	return &wslserviceapi.FileHeader{
+		Path:   t.Destination,
+		Size:   uint64(size),
+		Sha256: hex.EncodeToString(h.Sum(nil)),
+		Mode:   t.Mode,
+	}, nil
+}
+
+// push streams the file to the distro from the offset in the header, which the reader must be at.
+func (t FilePush) push(ctx context.Context, client wslserviceapi.WSLClient, header *wslserviceapi.FileHeader, r io.Reader) (*wslserviceapi.FileTransferStatus, error) {
+	stream, err := client.PushFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not start the transfer: %w", err)
+	}
+
+	if err := stream.Send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Header{Header: header}}); err != nil {
+		return nil, fmt.Errorf("could not send the header of the file: %w", err)
+	}
+
+	buff := make([]byte, fileChunkSize)
+	for {
+		n, err := r.Read(buff)
+		if n > 0 {
+			if err := stream.Send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Data{Data: buff[:n]}}); err != nil {
+				// The distro may have answered with the reason why the transfer was aborted.
+				_, recvErr := stream.CloseAndRecv()
+				return nil, fmt.Errorf("could not send chunk: %w", errors.Join(err, recvErr))
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %v", t.Source, err)
+		}
+	}
+
+	st, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("could not complete the transfer: %w", err)
+	}
+
+	return st, nil
+}
+
+// String returns the name of the task.
+func (t FilePush) String() string {
+	return fmt.Sprintf("FilePush to %s", t.Destination)
+}
+
+// Is is a custom comparator. FilePush tasks to the same destination are considered equivalent: the newer
+// file overrides the old one.
+func (t FilePush) Is(other task.Task) bool {
+	o, ok := other.(FilePush)
+	return ok && o.Destination == t.Destination
+}
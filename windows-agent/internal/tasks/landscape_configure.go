@@ -1,10 +1,17 @@
 package tasks
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strings"
 
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
 	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/ini.v1"
 )
 
 func init() {
@@ -14,9 +21,15 @@ func init() {
 // LandscapeConfigure is a task that registers/disables Landscape in a distro:
 // - to register: send the config to register with.
 // - to disable: send an empty config.
+//
+// The distro-specific settings are layered on top of the config. Empty ones are not overridden.
 type LandscapeConfigure struct {
 	Config       string
 	HostagentUID string
+
+	Tags          string `yaml:",omitempty"`
+	ComputerTitle string `yaml:",omitempty"`
+	ScriptUsers   string `yaml:",omitempty"`
 }
 
 // Execute sends the config to the target WSL-Pro-Service so that the distro can be
@@ -26,18 +39,50 @@ func (t LandscapeConfigure) Execute(ctx context.Context, client wslserviceapi.WS
 
 	// We only attach if there is a UID. Otherwise we detach.
 	if t.HostagentUID != "" {
+		conf, err := t.distroConfig()
+		if err != nil {
+			return err
+		}
+
 		msg.HostagentUID = t.HostagentUID
-		msg.Configuration = t.Config
+		msg.Configuration = conf
+		msg.ComputerTitle = t.ComputerTitle
+
+		// Applying the config restarts landscape-client, which is worth avoiding across a fleet.
+		if t.alreadyApplied(ctx, client, &msg) {
+			return nil
+		}
 	}
 
-	// First value is a dummy message, we ignore it. We only care about success/failure.
-	_, err := client.ApplyLandscapeConfig(ctx, &msg)
+	res, err := client.ApplyLandscapeConfig(ctx, &msg)
 	if err != nil {
 		return task.NeedsRetryError{SourceErr: err}
 	}
+
+	// The distro maps the script users to its own users, and leaves out the ones it does not have. It
+	// reports them in its info as well, so that they are known even when the config is not sent again.
+	if users := res.GetScriptUsers(); users != "" {
+		log.Infof(ctx, "LandscapeConfigure: Landscape scripts may run as %s", users)
+	}
 	return nil
 }
 
+// alreadyApplied returns true if the distro reports that the config it applied last is the same as
+// the message. Any failure to tell is taken as a difference, so that the config is sent anyway.
+func (t LandscapeConfigure) alreadyApplied(ctx context.Context, client wslserviceapi.WSLClient, msg *wslserviceapi.LandscapeConfig) bool {
+	applied, err := client.GetLandscapeConfigHash(ctx, &wslserviceapi.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		// Older versions of the WSL Pro service cannot tell which config they applied.
+		return false
+	} else if err != nil {
+		log.Debugf(ctx, "LandscapeConfigure: could not get the hash of the config applied: %v", err)
+		return false
+	}
+
+	hash := applied.GetHash()
+	return hash != "" && hash == wslserviceapi.HashLandscapeConfig(msg)
+}
+
 // String returns the name of the task.
 func (t LandscapeConfigure) String() string {
 	return "LandscapeConfigure"
@@ -49,3 +94,35 @@ func (t LandscapeConfigure) Is(other task.Task) bool {
 	_, ok := other.(LandscapeConfigure)
 	return ok
 }
+
+// DependsOn declares that Landscape is configured only after Ubuntu Pro is attached, if both are queued.
+func (t LandscapeConfigure) DependsOn() []task.Task {
+	return []task.Task{ProAttachment{}}
+}
+
+// distroConfig returns the config with the distro-specific settings layered on top.
+func (t LandscapeConfigure) distroConfig() (string, error) {
+	if t.Config == "" || (t.Tags == "" && t.ScriptUsers == "") {
+		return t.Config, nil
+	}
+
+	data, err := ini.Load(strings.NewReader(t.Config))
+	if err != nil {
+		return "", fmt.Errorf("could not parse Landscape config: %v", err)
+	}
+
+	client := data.Section("client")
+	if t.Tags != "" {
+		client.Key("tags").SetValue(t.Tags)
+	}
+	if t.ScriptUsers != "" {
+		client.Key("script_users").SetValue(t.ScriptUsers)
+	}
+
+	w := &bytes.Buffer{}
+	if _, err := data.WriteTo(w); err != nil {
+		return "", fmt.Errorf("could not write Landscape config: %v", err)
+	}
+
+	return w.String(), nil
+}
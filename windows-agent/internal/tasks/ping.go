@@ -52,3 +52,9 @@ func (t *Ping) Execute(ctx context.Context, client wslserviceapi.WSLClient) (err
 func (t Ping) String() string {
 	return "Ping"
 }
+
+// Shared marks the task as shared, as it only checks that the distro answers. It does not need to wait
+// for the task changing the distro, if any, to complete.
+func (t Ping) Shared() bool {
+	return true
+}
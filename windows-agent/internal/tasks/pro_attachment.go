@@ -40,3 +40,9 @@ func (t ProAttachment) Is(other task.Task) bool {
 	_, ok := other.(ProAttachment)
 	return ok
 }
+
+// Urgent marks the task as urgent, so that changes to the subscription are applied even outside
+// of the maintenance window.
+func (t ProAttachment) Urgent() bool {
+	return true
+}
@@ -0,0 +1,44 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	task.Register[ProRefresh]()
+}
+
+// ProRefresh is a task that refreshes the Ubuntu Pro contract and configuration of a distro.
+// It is meant to be submitted periodically, so a failure is not retried: the next run will try again.
+type ProRefresh struct{}
+
+// Execute is needed to fulfil Task.
+func (t ProRefresh) Execute(ctx context.Context, client wslserviceapi.WSLClient) error {
+	_, err := client.ProRefresh(ctx, &wslserviceapi.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		// Older versions of the WSL Pro service cannot refresh: there is nothing to do about it.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not refresh Ubuntu Pro: %w", err)
+	}
+	return nil
+}
+
+// String is needed to fulfil Task.
+func (t ProRefresh) String() string {
+	return "ProRefresh"
+}
+
+// Is is a custom comparator. All ProRefresh tasks are considered equivalent, so that a pending
+// refresh is not queued twice.
+func (t ProRefresh) Is(other task.Task) bool {
+	_, ok := other.(ProRefresh)
+	return ok
+}
@@ -0,0 +1,48 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/distros/task"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+)
+
+func init() {
+	task.Register[WSLConfigure]()
+}
+
+// WSLConfigure is a task that writes the default user and the WSL options to the distro's wsl.conf.
+// Empty and nil fields are left as they are. The changes take effect the next time the distro starts.
+type WSLConfigure struct {
+	DefaultUser string `yaml:",omitempty"`
+	Systemd     *bool  `yaml:",omitempty"`
+	Interop     *bool  `yaml:",omitempty"`
+	Automount   *bool  `yaml:",omitempty"`
+}
+
+// Execute sends the settings to the target WSL-Pro-Service.
+func (t WSLConfigure) Execute(ctx context.Context, client wslserviceapi.WSLClient) error {
+	msg := wslserviceapi.WSLSettings{
+		DefaultUser: t.DefaultUser,
+		Systemd:     t.Systemd,
+		Interop:     t.Interop,
+		Automount:   t.Automount,
+	}
+
+	if _, err := client.ApplyWSLSettings(ctx, &msg); err != nil {
+		return task.NeedsRetryError{SourceErr: err}
+	}
+	return nil
+}
+
+// String returns the name of the task.
+func (t WSLConfigure) String() string {
+	return "WSLConfigure"
+}
+
+// Is is a custom comparator. All WSLConfigure tasks are considered equivalent, as they always
+// carry the complete settings of the distro: newer instructions override old ones.
+func (t WSLConfigure) Is(other task.Task) bool {
+	_, ok := other.(WSLConfigure)
+	return ok
+}
@@ -0,0 +1,586 @@
+// Package testutils provides test doubles shared by the tests of the different
+// packages of the agent.
+package testutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+	"github.com/stretchr/testify/require"
+)
+
+// ErrRegistryMock is the error returned when everything went fine but the mock
+// setup requested an error to be thrown.
+var ErrRegistryMock = errors.New("error triggered by mock setup")
+
+// RegistryMock is a fake registry stored in memory. It only contains the UbuntuPro key, its
+// parent Software key, and the key where the predecessor of the agent stored its settings.
+//
+// Its exported fields can be modified at any moment to script failures.
+type RegistryMock struct {
+	// software is the parent of the ubuntuPro key. It has no fields, and it can only be watched.
+	software key
+
+	// ubuntuPro is the key where all the data is stored.
+	ubuntuPro key
+
+	// legacy is the key where Ubuntu Pro for Windows stored its settings. It is never watched.
+	legacy key
+
+	// keyHandles contains the handles to the keys. The Win32API returns void pointers to the
+	// key handles, and we mimic this behaviour so we can fit the interface. The user of this
+	// library will have a "pointer", which is just a key into this map.
+	keyHandles mockedHeap[registry.Key, *keyHandle]
+
+	// eventsHandles contains the eventsHandles. The Win32API returns void pointers to the eventsHandles, and we
+	// mimic this behaviour so we can fit the interface. The user of this  library will have
+	// a "pointer", which is just a key into this map.
+	eventHandles mockedHeap[registry.Event, *eventHandle]
+
+	// readDelay is how long every read takes, in nanoseconds.
+	readDelay atomic.Int64
+
+	// Settings to break the registry
+	ReadOnly     atomic.Bool // Creating the key is denied.
+	CannotWrite  atomic.Bool // Writing to the key is denied.
+	CannotCreate atomic.Bool
+	CannotOpen   atomic.Bool
+	CannotRead   atomic.Bool
+	CannotList   atomic.Bool // Listing the fields of the key fails.
+	CannotWatch  atomic.Bool
+	CannotWait   atomic.Bool
+}
+
+// key mocks a registry key.
+type key struct {
+	mu     *sync.RWMutex
+	exists bool
+
+	// data contains the fields of the key. Values are string (REG_SZ),
+	// []string (REG_MULTI_SZ) or uint32 (REG_DWORD).
+	data   map[string]any
+	events []registry.Event
+}
+
+func newKey(exists bool) key {
+	return key{
+		mu:     &sync.RWMutex{},
+		exists: exists,
+		data:   make(map[string]any),
+		events: make([]registry.Event, 0),
+	}
+}
+
+func (r *RegistryMock) notify(k *key) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	// Trigger all events
+	r.eventHandles.mu.Lock()
+	for _, event := range k.events {
+		if e, ok := r.eventHandles.data[event]; ok {
+			e.trigger()
+		}
+	}
+	r.eventHandles.mu.Unlock()
+
+	// Reset the list
+	k.events = make([]registry.Event, 0)
+}
+
+// notifyUbuntuPro triggers the events of the UbuntuPro key and its parent, as
+// the parent is watched recursively.
+func (r *RegistryMock) notifyUbuntuPro() {
+	r.notify(&r.ubuntuPro)
+	r.notify(&r.software)
+}
+
+func (r *RegistryMock) setValue(k *key, field string, value any) error {
+	defer r.notifyUbuntuPro()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.exists {
+		return registry.ErrKeyNotExist
+	}
+
+	k.data[field] = value
+	return nil
+}
+
+func (r *RegistryMock) getValue(k *key, field string) (any, error) {
+	time.Sleep(time.Duration(r.readDelay.Load()))
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.exists {
+		return nil, registry.ErrKeyNotExist
+	}
+
+	d, ok := k.data[field]
+	if !ok {
+		return nil, registry.ErrFieldNotExist
+	}
+
+	return d, nil
+}
+
+// keyHandle represents the object Win32 callers get when opening a key.
+// Note that the Win32 API returns a HANDLE (i.e. a typedef'd void*), so this
+// struct represents the structure that HANDLE points to.
+type keyHandle struct {
+	key      *key
+	readOnly bool
+
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+}
+
+// eventHandle represents the object Win32 callers get when creating an event.
+// Note that the Win32 API returns a HANDLE (i.e. a typedef'd void*), so this
+// struct represents the structure that HANDLE points to.
+type eventHandle struct {
+	ctx     context.Context
+	trigger func()
+}
+
+// NewRegistryMock initializes a mocked registry.
+func NewRegistryMock() *RegistryMock {
+	if !testing.Testing() {
+		panic("This registry function should be used by tests only")
+	}
+
+	// We initialize the Software key, as we consider that to be the minimal
+	// "sane" Windows install.
+	m := &RegistryMock{
+		software:  newKey(true),
+		ubuntuPro: newKey(false),
+		legacy:    newKey(false),
+	}
+
+	m.keyHandles.data = make(map[registry.Key]*keyHandle)
+	m.eventHandles.data = make(map[registry.Event]*eventHandle)
+
+	return m
+}
+
+// UbuntuProKeyExists returns whether the UbuntuPro key exists in the mock registry.
+func (r *RegistryMock) UbuntuProKeyExists() bool {
+	r.ubuntuPro.mu.Lock()
+	defer r.ubuntuPro.mu.Unlock()
+
+	return r.ubuntuPro.exists
+}
+
+// DeleteUbuntuProKey removes the UbuntuPro key and all its fields, as a user could do
+// while the agent is running. Open handles to the key become invalid.
+func (r *RegistryMock) DeleteUbuntuProKey() {
+	defer r.notifyUbuntuPro()
+
+	r.ubuntuPro.mu.Lock()
+	defer r.ubuntuPro.mu.Unlock()
+
+	r.ubuntuPro.exists = false
+	r.ubuntuPro.data = make(map[string]any)
+}
+
+// SetReadDelay makes every read from the registry take the specified time.
+func (r *RegistryMock) SetReadDelay(d time.Duration) {
+	r.readDelay.Store(int64(d))
+}
+
+// RequireNoLeaks is a test helper to ensure we freed all allocations.
+func (r *RegistryMock) RequireNoLeaks(t *testing.T) {
+	t.Helper()
+	require.Empty(t, r.keyHandles.data, "registry mock: leaking registry key handles")
+	require.Empty(t, r.eventHandles.data, "registry mock: leaking event handles")
+}
+
+// HKCUOpenKey mocks opening a key in the specified path under the HK_CURRENT_USER registry.
+func (r *RegistryMock) HKCUOpenKey(path string) (registry.Key, error) {
+	if r.CannotOpen.Load() {
+		return 0, ErrRegistryMock
+	}
+
+	k := r.keyAt(path)
+
+	k.mu.Lock()
+	exists := k.exists
+	k.mu.Unlock()
+
+	if !exists {
+		return 0, registry.ErrKeyNotExist
+	}
+
+	return r.openKey(k, true), nil
+}
+
+// HKCUCreateKey opens a key in the specified path under the HK_CURRENT_USER registry with write permissions.
+// The key is created if it did not exist.
+func (r *RegistryMock) HKCUCreateKey(path string) (registry.Key, error) {
+	if r.CannotCreate.Load() {
+		return 0, ErrRegistryMock
+	}
+
+	if r.ReadOnly.Load() {
+		return 0, registry.ErrAccessDenied
+	}
+
+	k := r.keyAt(path)
+
+	k.mu.Lock()
+	created := !k.exists
+	k.exists = true
+	k.mu.Unlock()
+
+	if created {
+		// Creating a subkey triggers the events of the parent.
+		r.notify(&r.software)
+	}
+
+	return r.openKey(k, false), nil
+}
+
+// HKCUOpenKeyWrite mocks opening an existing key in the specified path under the HK_CURRENT_USER registry
+// with write permissions.
+func (r *RegistryMock) HKCUOpenKeyWrite(path string) (registry.Key, error) {
+	if r.CannotOpen.Load() {
+		return 0, ErrRegistryMock
+	}
+
+	k := r.keyAt(path)
+
+	k.mu.Lock()
+	exists := k.exists
+	k.mu.Unlock()
+
+	if !exists {
+		return 0, registry.ErrKeyNotExist
+	}
+
+	if r.ReadOnly.Load() {
+		return 0, registry.ErrAccessDenied
+	}
+
+	return r.openKey(k, false), nil
+}
+
+// keyAt returns the key stored at the specified path. It panics if the path is outside of the mocked keys.
+func (r *RegistryMock) keyAt(path string) *key {
+	path = strings.TrimRight(strings.ReplaceAll(path, `\`, "/"), "/")
+
+	switch path {
+	case "Software":
+		return &r.software
+	case "Software/Canonical/UbuntuPro":
+		return &r.ubuntuPro
+	case "Software/Canonical/UbuntuProForWindows":
+		return &r.legacy
+	default:
+		panic(fmt.Sprintf("Attempting to access key outside of UbuntuPro: %q", path))
+	}
+}
+
+func (r *RegistryMock) openKey(k *key, readOnly bool) registry.Key {
+	return r.keyHandles.alloc(&keyHandle{
+		key:      k,
+		readOnly: readOnly,
+	})
+}
+
+// CloseKey mocks releasing a key, triggering any associated events.
+func (r *RegistryMock) CloseKey(ptr registry.Key) {
+	defer r.keyHandles.free(ptr)
+
+	r.keyHandles.mu.Lock()
+	defer r.keyHandles.mu.Unlock()
+
+	handle, ok := r.keyHandles.data[ptr]
+
+	if !ok {
+		return
+	}
+
+	if handle.cancelCtx != nil {
+		handle.cancelCtx()
+	}
+}
+
+// CloseEvent mocks releasing an event.
+func (r *RegistryMock) CloseEvent(ptr registry.Event) {
+	r.eventHandles.free(ptr)
+}
+
+// ReadValue returns the value of the specified field in the specified key.
+func (r *RegistryMock) ReadValue(ptr registry.Key, field string) (value string, err error) {
+	v, err := r.readValue(ptr, field)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case []string:
+		return strings.Join(v, "\n"), nil
+	default:
+		return "", registry.ErrUnexpectedType
+	}
+}
+
+// ReadDWORD returns the value of the specified REG_DWORD field in the specified key.
+func (r *RegistryMock) ReadDWORD(ptr registry.Key, field string) (uint32, error) {
+	v, err := r.readValue(ptr, field)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := v.(uint32)
+	if !ok {
+		return 0, registry.ErrUnexpectedType
+	}
+
+	return n, nil
+}
+
+func (r *RegistryMock) readValue(ptr registry.Key, field string) (any, error) {
+	handle, err := r.readableHandle(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getValue(handle.key, field)
+}
+
+// readableHandle returns the handle behind the pointer, or an error if the mock setup forbids reading.
+func (r *RegistryMock) readableHandle(ptr registry.Key) (*keyHandle, error) {
+	if ptr == 0 {
+		return nil, errors.New("null key")
+	}
+
+	if r.CannotRead.Load() {
+		return nil, ErrRegistryMock
+	}
+
+	r.keyHandles.mu.Lock()
+	handle, ok := r.keyHandles.data[ptr]
+	r.keyHandles.mu.Unlock()
+
+	if !ok {
+		return nil, registry.ErrKeyNotExist
+	}
+
+	return handle, nil
+}
+
+// ReadValueNames returns the names of all the fields in the specified key.
+func (r *RegistryMock) ReadValueNames(ptr registry.Key) ([]string, error) {
+	handle, err := r.readableHandle(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.CannotList.Load() {
+		return nil, ErrRegistryMock
+	}
+
+	time.Sleep(time.Duration(r.readDelay.Load()))
+
+	handle.key.mu.Lock()
+	defer handle.key.mu.Unlock()
+
+	if !handle.key.exists {
+		return nil, registry.ErrKeyNotExist
+	}
+
+	names := make([]string, 0, len(handle.key.data))
+	for name := range handle.key.data {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// RegNotifyChangeKeyValue creates an event and attaches it to a registry key.
+// Modifying that key or its children will trigger the event.
+// This trigger can be detected by WaitForSingleObject.
+func (r *RegistryMock) RegNotifyChangeKeyValue(ptr registry.Key) (registry.Event, error) {
+	if r.CannotWatch.Load() {
+		return 0, ErrRegistryMock
+	}
+
+	r.keyHandles.mu.Lock()
+	defer r.keyHandles.mu.Unlock()
+
+	handle, ok := r.keyHandles.data[ptr]
+	if !ok {
+		return 0, registry.ErrKeyNotExist
+	}
+
+	if handle.ctx != nil {
+		return 0, errors.New("cannot have more than one listener per key handle")
+	}
+
+	handle.ctx, handle.cancelCtx = context.WithCancel(context.Background())
+
+	// Create event
+	evHandle := r.newEvent(handle.ctx)
+
+	// Attach event to key
+	handle.key.mu.Lock()
+	defer handle.key.mu.Unlock()
+
+	handle.key.events = append(handle.key.events, evHandle)
+
+	return evHandle, nil
+}
+
+// WaitForSingleObject waits until the event is triggered. This is a blocking function.
+func (r *RegistryMock) WaitForSingleObject(handle registry.Event) error {
+	if r.CannotWait.Load() {
+		return ErrRegistryMock
+	}
+
+	r.eventHandles.mu.Lock()
+	event, ok := r.eventHandles.data[handle]
+	r.eventHandles.mu.Unlock()
+
+	if !ok {
+		return errors.New("invalid event")
+	}
+
+	<-event.ctx.Done()
+	return nil
+}
+
+// WriteValue is used to write a value into the registry.
+func (r *RegistryMock) WriteValue(ptr registry.Key, field, value string, multiString bool) error {
+	if strings.Contains(value, "\n") && !multiString {
+		return fmt.Errorf("mock error: value contains newline, but multiString is false: %q", value)
+	}
+
+	if multiString {
+		return r.writeValue(ptr, field, strings.Split(value, "\n"))
+	}
+
+	return r.writeValue(ptr, field, value)
+}
+
+// WriteDWORD writes the value to the specified REG_DWORD field in the specified key. The agent never
+// writes DWORDs: this lets tests set up the fields it reads with ReadDWORD.
+func (r *RegistryMock) WriteDWORD(ptr registry.Key, field string, value uint32) error {
+	return r.writeValue(ptr, field, value)
+}
+
+// DeleteValue removes the specified field from the specified key.
+func (r *RegistryMock) DeleteValue(ptr registry.Key, field string) error {
+	r.keyHandles.mu.Lock()
+	defer r.keyHandles.mu.Unlock()
+
+	handle, ok := r.keyHandles.data[ptr]
+	if !ok {
+		return registry.ErrKeyNotExist
+	}
+
+	if handle.readOnly || r.CannotWrite.Load() {
+		return registry.ErrAccessDenied
+	}
+
+	if handle.key != &r.ubuntuPro {
+		panic("Attempting to delete from a key other than UbuntuPro")
+	}
+
+	defer r.notifyUbuntuPro()
+
+	k := handle.key
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.exists {
+		return registry.ErrKeyNotExist
+	}
+
+	if _, ok := k.data[field]; !ok {
+		return registry.ErrFieldNotExist
+	}
+
+	delete(k.data, field)
+	return nil
+}
+
+func (r *RegistryMock) writeValue(ptr registry.Key, field string, value any) error {
+	r.keyHandles.mu.Lock()
+	defer r.keyHandles.mu.Unlock()
+
+	handle, ok := r.keyHandles.data[ptr]
+
+	if !ok {
+		return registry.ErrKeyNotExist
+	}
+
+	if handle.readOnly || r.CannotWrite.Load() {
+		return registry.ErrAccessDenied
+	}
+
+	if handle.key != &r.ubuntuPro {
+		panic("Attempting to write to a key other than UbuntuPro")
+	}
+
+	return r.setValue(handle.key, field, value)
+}
+
+func (r *RegistryMock) newEvent(ctx context.Context) registry.Event {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return r.eventHandles.alloc(&eventHandle{
+		ctx:     ctx,
+		trigger: cancel,
+	})
+}
+
+// Mocks memory access mapping a uintptr and real data.
+type mockedHeap[KeyType ~uintptr, DataType any] struct {
+	mu   sync.Mutex
+	data map[KeyType]DataType
+}
+
+func (h *mockedHeap[P, D]) alloc(data D) P {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Generate a random uintptr
+	var ptr P
+	for {
+		//nolint:gosec // No need for a secure random number as this is test code
+		ptr = P(rand.Int63())
+		if ptr == 0 {
+			continue
+		}
+		if _, ok := h.data[ptr]; ok {
+			continue
+		}
+		break
+	}
+
+	h.data[ptr] = data
+	return ptr
+}
+
+func (h *mockedHeap[P, D]) free(ptr P) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.data, ptr)
+}
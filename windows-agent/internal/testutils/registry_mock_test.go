@@ -0,0 +1,101 @@
+package testutils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/proservices/registrywatcher/registry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+const ubuntuProKey = `Software\Canonical\UbuntuPro`
+
+func TestRegistryMockDWORD(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		stored     any
+		readOnly   bool
+		cannotRead bool
+
+		want         uint32
+		wantWriteErr error
+		wantErr      error
+	}{
+		"Success reading a DWORD":       {stored: uint32(42), want: 42},
+		"Success reading a zero DWORD":  {stored: uint32(0), want: 0},
+		"Success reading the max DWORD": {stored: ^uint32(0), want: ^uint32(0)},
+
+		"Error writing through a read-only key":  {stored: uint32(42), readOnly: true, wantWriteErr: registry.ErrAccessDenied},
+		"Error reading a string as a DWORD":      {stored: "42", wantErr: registry.ErrUnexpectedType},
+		"Error reading a multi-string as DWORD":  {stored: []string{"4", "2"}, wantErr: registry.ErrUnexpectedType},
+		"Error reading a missing field":          {wantErr: registry.ErrFieldNotExist},
+		"Error reading when the registry breaks": {stored: uint32(42), cannotRead: true, wantErr: testutils.ErrRegistryMock},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reg := testutils.NewRegistryMock()
+			defer reg.RequireNoLeaks(t)
+
+			k := openKey(t, reg, tc.readOnly)
+			defer reg.CloseKey(k)
+
+			err := store(reg, k, "Field", tc.stored)
+			if tc.wantWriteErr != nil {
+				require.ErrorIs(t, err, tc.wantWriteErr, "Writing should have returned the expected error")
+				return
+			}
+			require.NoError(t, err, "Writing should return no error")
+
+			reg.CannotRead.Store(tc.cannotRead)
+
+			got, err := reg.ReadDWORD(k, "Field")
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr, "ReadDWORD should have returned the expected error")
+				return
+			}
+			require.NoError(t, err, "ReadDWORD should return no error")
+			require.Equal(t, tc.want, got, "ReadDWORD should return the value written")
+
+			_, err = reg.ReadValue(k, "Field")
+			require.ErrorIs(t, err, registry.ErrUnexpectedType, "ReadValue should not read a DWORD as a string")
+		})
+	}
+}
+
+// openKey opens the UbuntuPro key, creating it first. The key is opened with write permissions unless readOnly is set.
+func openKey(t *testing.T, reg *testutils.RegistryMock, readOnly bool) registry.Key {
+	t.Helper()
+
+	k, err := reg.HKCUCreateKey(ubuntuProKey)
+	require.NoError(t, err, "Setup: could not create the UbuntuPro key")
+	if !readOnly {
+		return k
+	}
+	reg.CloseKey(k)
+
+	k, err = reg.HKCUOpenKey(ubuntuProKey)
+	require.NoError(t, err, "Setup: could not open the UbuntuPro key")
+	return k
+}
+
+// store writes the value with the writer matching its type: uint32 as REG_DWORD, string as REG_SZ, and
+// []string as a multi-line REG_MULTI_SZ. A nil value is not written.
+func store(reg *testutils.RegistryMock, k registry.Key, field string, value any) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case uint32:
+		return reg.WriteDWORD(k, field, v)
+	case string:
+		return reg.WriteValue(k, field, v, false)
+	case []string:
+		return reg.WriteValue(k, field, strings.Join(v, "\n"), true)
+	default:
+		panic("unsupported value type")
+	}
+}
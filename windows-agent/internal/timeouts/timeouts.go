@@ -0,0 +1,144 @@
+// Package timeouts gathers how long the agent waits for the operations that may hang, so that they
+// are all tuned in one place and injected into the components that need them.
+package timeouts
+
+import "time"
+
+// Policy contains how long the agent waits for each kind of operation. A zero field means that
+// the default is used: see Default.
+type Policy struct {
+	// DistroDial is how long the agent waits for the connection to the WSL Pro service of a
+	// distro to be ready.
+	DistroDial time.Duration
+
+	// ClientWait is how long a task waits for its distro to connect to the agent before the
+	// distro is considered unreachable.
+	ClientWait time.Duration
+
+	// ClientWaitTick is how often a task, or the startup phase, that waits for a distro checks whether
+	// it connected.
+	ClientWaitTick time.Duration
+
+	// DistroProvisioning is how long the startup phase waits for a distro to connect to the agent
+	// once it has been woken up.
+	DistroProvisioning time.Duration
+
+	// ShortCall is how long an RPC made to a distro may take, unless it is a long call.
+	ShortCall time.Duration
+
+	// LongCall is how long an RPC that changes the state of a distro may take, not counting the
+	// time spent waiting for its turn.
+	LongCall time.Duration
+
+	// SecurityStatus is how long a distro may take to report its pending security updates, which
+	// requires reading its apt cache.
+	SecurityStatus time.Duration
+
+	// DistroLogs is how long fetching the logs of a distro may take, including starting it.
+	DistroLogs time.Duration
+
+	// LockWatchdog is how long an operation on a distro may wait for its internal lock before a
+	// suspected deadlock is logged.
+	LockWatchdog time.Duration
+
+	// LandscapeDial is how long the agent waits to connect to the Landscape server.
+	LandscapeDial time.Duration
+
+	// LandscapeHandshake is how long the agent waits for the Landscape server to assign it a UID.
+	LandscapeHandshake time.Duration
+
+	// LandscapeSend is how long sending the host information to the Landscape server may take.
+	LandscapeSend time.Duration
+
+	// LandscapeInfoBatch is how long the changes to the distros are gathered before the updated host
+	// information is sent to the Landscape server, so that a burst of changes is sent in a single message.
+	LandscapeInfoBatch time.Duration
+
+	// LandscapeCommand is how long a command received from the Landscape server may take, unless it
+	// is an install.
+	LandscapeCommand time.Duration
+
+	// LandscapeInstall is how long installing a distro on behalf of the Landscape server may take.
+	LandscapeInstall time.Duration
+
+	// Powershell is how long a powershell command run by the agent may take.
+	Powershell time.Duration
+
+	// WSLQuery is how long a call that reads the state of WSL, such as the state of a distro, may take.
+	WSLQuery time.Duration
+
+	// WSLCommand is how long a call that changes the state of WSL, such as registering a distro, may take.
+	WSLCommand time.Duration
+
+	// WSLSlowCall is how long a call to WSL may take before it is logged as slow.
+	WSLSlowCall time.Duration
+
+	// ResetDetach is how long a reset of the agent waits for the distros to be detached before giving up.
+	ResetDetach time.Duration
+
+	// ShutdownPause is how long a distro waits before running more tasks after it was shut down while
+	// running one, so that the task is not retried while WSL is still shutting down.
+	ShutdownPause time.Duration
+}
+
+// Default returns the policy used unless it is overridden.
+func Default() Policy {
+	return Policy{
+		DistroDial:         2 * time.Second,
+		ClientWait:         30 * time.Second,
+		ClientWaitTick:     time.Second,
+		DistroProvisioning: 2 * time.Minute,
+		ShortCall:          30 * time.Second,
+		LongCall:           10 * time.Minute,
+		SecurityStatus:     2 * time.Minute,
+		DistroLogs:         time.Minute,
+		LockWatchdog:       time.Minute,
+		LandscapeDial:      10 * time.Second,
+		LandscapeHandshake: time.Minute,
+		LandscapeSend:      10 * time.Second,
+		LandscapeInfoBatch: 2 * time.Second,
+		LandscapeCommand:   10 * time.Minute,
+		LandscapeInstall:   time.Hour,
+		Powershell:         30 * time.Second,
+		WSLQuery:           30 * time.Second,
+		WSLCommand:         10 * time.Minute,
+		WSLSlowCall:        5 * time.Second,
+		ResetDetach:        2 * time.Minute,
+		ShutdownPause:      5 * time.Second,
+	}
+}
+
+// OrDefault returns a copy of the policy where every zero field is replaced by its default.
+func (p Policy) OrDefault() Policy {
+	def := Default()
+
+	orDefault(&p.DistroDial, def.DistroDial)
+	orDefault(&p.ClientWait, def.ClientWait)
+	orDefault(&p.ClientWaitTick, def.ClientWaitTick)
+	orDefault(&p.DistroProvisioning, def.DistroProvisioning)
+	orDefault(&p.ShortCall, def.ShortCall)
+	orDefault(&p.LongCall, def.LongCall)
+	orDefault(&p.SecurityStatus, def.SecurityStatus)
+	orDefault(&p.DistroLogs, def.DistroLogs)
+	orDefault(&p.LockWatchdog, def.LockWatchdog)
+	orDefault(&p.LandscapeDial, def.LandscapeDial)
+	orDefault(&p.LandscapeHandshake, def.LandscapeHandshake)
+	orDefault(&p.LandscapeSend, def.LandscapeSend)
+	orDefault(&p.LandscapeInfoBatch, def.LandscapeInfoBatch)
+	orDefault(&p.LandscapeCommand, def.LandscapeCommand)
+	orDefault(&p.LandscapeInstall, def.LandscapeInstall)
+	orDefault(&p.Powershell, def.Powershell)
+	orDefault(&p.WSLQuery, def.WSLQuery)
+	orDefault(&p.WSLCommand, def.WSLCommand)
+	orDefault(&p.WSLSlowCall, def.WSLSlowCall)
+	orDefault(&p.ResetDetach, def.ResetDetach)
+	orDefault(&p.ShutdownPause, def.ShutdownPause)
+
+	return p
+}
+
+func orDefault(d *time.Duration, def time.Duration) {
+	if *d <= 0 {
+		*d = def
+	}
+}
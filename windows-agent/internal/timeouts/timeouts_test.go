@@ -0,0 +1,39 @@
+package timeouts_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrDefault(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		policy timeouts.Policy
+
+		want timeouts.Policy
+	}{
+		"Success with an empty policy": {want: timeouts.Default()},
+		"Success keeping the overridden timeouts": {
+			policy: timeouts.Policy{ClientWait: time.Hour, Powershell: time.Millisecond},
+			want: func() timeouts.Policy {
+				p := timeouts.Default()
+				p.ClientWait = time.Hour
+				p.Powershell = time.Millisecond
+				return p
+			}(),
+		},
+		"Success replacing negative timeouts": {policy: timeouts.Policy{LongCall: -time.Second}, want: timeouts.Default()},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, tc.policy.OrDefault(), "OrDefault should only replace the unset timeouts")
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"time"
 
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/storeapi/go-wrapper/microsoftstore"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/ubuntupro/contractclient"
 	"github.com/ubuntu/decorate"
@@ -17,6 +18,7 @@ import (
 type options struct {
 	proURL         *url.URL
 	microsoftStore MicrosoftStore
+	cache          EntitlementCache
 }
 
 // Option is an optional argument for ProToken.
@@ -53,8 +55,8 @@ func (msftStoreDLL) GetSubscriptionExpirationDate() (tm time.Time, err error) {
 	return microsoftstore.GetSubscriptionExpirationDate()
 }
 
-// ValidSubscription returns true if there is a subscription via the Microsoft Store and it is not expired.
-func ValidSubscription(args ...Option) (bool, error) {
+// newOptions applies the optional arguments on top of the defaults.
+func newOptions(args ...Option) options {
 	opts := options{
 		microsoftStore: msftStoreDLL{},
 	}
@@ -63,18 +65,61 @@ func ValidSubscription(args ...Option) (bool, error) {
 		f(&opts)
 	}
 
+	return opts
+}
+
+// contractClient returns a client for the Ubuntu Pro contract server.
+func (o options) contractClient() (*contractclient.Client, error) {
+	proURL := o.proURL
+	if proURL == nil {
+		url, err := defaultProBackendURL()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse contract server URL: %v", err)
+		}
+		proURL = url
+	}
+
+	return contractclient.New(proURL, &http.Client{Timeout: 30 * time.Second}), nil
+}
+
+// ValidSubscription returns true if there is a subscription via the Microsoft Store and it is not expired.
+// If the Microsoft Store cannot be reached, the cached subscription expiration is used, if any.
+func ValidSubscription(ctx context.Context, args ...Option) (bool, error) {
+	return validSubscription(ctx, newOptions(args...))
+}
+
+func validSubscription(ctx context.Context, opts options) (bool, error) {
+	now := time.Now()
+
 	expiration, err := opts.microsoftStore.GetSubscriptionExpirationDate()
 	if err != nil {
 		var target microsoftstore.StoreAPIError
 		if errors.As(err, &target) && target == microsoftstore.ErrNotSubscribed {
 			// ValidSubscription -> false: we are not subscribed
+			e := opts.loadEntitlement(ctx)
+			e.SubscriptionExpiration = time.Time{}
+			e.Checked = now
+			opts.storeEntitlement(ctx, e)
 			return false, nil
 		}
 
-		return false, err
+		// The Microsoft Store could not be reached: the last known expiration is used for a while.
+		e := opts.loadEntitlement(ctx)
+		if e.Checked.IsZero() || now.Sub(e.Checked) > maxEntitlementAge {
+			return false, err
+		}
+
+		log.Warningf(ctx, "Contracts: using the cached Microsoft Store subscription expiration: %v", err)
+		expiration = e.SubscriptionExpiration
+	} else {
+		e := opts.loadEntitlement(ctx)
+		e.SubscriptionExpiration = expiration
+		e.Checked = now
+		opts.storeEntitlement(ctx, e)
 	}
 
-	if expiration.Before(time.Now()) {
+	// The expiration is given by the Microsoft Store's clock, so the clock skew is tolerated.
+	if expiration.Add(clockSkew).Before(now) {
 		// ValidSubscription -> false: the subscription is expired
 		return false, nil
 	}
@@ -89,31 +134,14 @@ func ValidSubscription(args ...Option) (bool, error) {
 func NewProToken(ctx context.Context, args ...Option) (token string, err error) {
 	defer decorate.OnError(&err, "couldn't get a Microsoft-Store-provided Ubuntu Pro token")
 
-	opts := options{
-		microsoftStore: msftStoreDLL{},
-	}
-
-	for _, f := range args {
-		f(&opts)
-	}
-
-	if opts.proURL == nil {
-		url, err := defaultProBackendURL()
-		if err != nil {
-			return "", fmt.Errorf("could not parse contract server URL: %v", err)
-		}
-		opts.proURL = url
-	}
-
-	contractClient := contractclient.New(opts.proURL, &http.Client{Timeout: 30 * time.Second})
-	msftStore := opts.microsoftStore
+	opts := newOptions(args...)
 
-	adToken, err := contractClient.GetServerAccessToken(ctx)
+	contractClient, err := opts.contractClient()
 	if err != nil {
 		return "", err
 	}
 
-	storeToken, err := msftStore.GenerateUserJWT(adToken)
+	storeToken, err := userJWT(ctx, opts, contractClient.GetServerAccessToken)
 	if err != nil {
 		return "", err
 	}
@@ -2,14 +2,18 @@ package contracts_test
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/mocks/contractserver/contractsmockserver"
 	"github.com/canonical/ubuntu-pro-for-wsl/storeapi/go-wrapper/microsoftstore"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
 	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/ubuntupro/contracts"
 	"github.com/stretchr/testify/require"
 )
@@ -31,13 +35,26 @@ func TestProToken(t *testing.T) {
 		getServerAccessTokenErr bool
 		getProTokenErr          bool
 
-		wantErr bool
-	}{
-		"Success": {},
+		// Entitlement cache
+		cachedJWT     cachedJWTState
+		storeClockLag time.Duration
 
-		"Error when the store's GenerateUserJWT fails":                {jwtError: true, wantErr: true},
-		"Error when the contract server's GetServerAccessToken fails": {getServerAccessTokenErr: true, wantErr: true},
-		"Error when the contract server's GetProToken fails":          {getProTokenErr: true, wantErr: true},
+		wantCachedJWT string
+		wantErr       bool
+	}{
+		"Success":                     {},
+		"Success caching the new JWT": {wantCachedJWT: "new"},
+		"Success caching the new JWT when the clocks are skewed":      {storeClockLag: 2 * time.Hour, wantCachedJWT: "new"},
+		"Success reusing the cached JWT":                              {cachedJWT: jwtFresh, jwtError: true, wantCachedJWT: "cached"},
+		"Success renewing the cached JWT when it is about to expire":  {cachedJWT: jwtExpiring, wantCachedJWT: "new"},
+		"Success renewing the cached JWT when it is expired":          {cachedJWT: jwtExpired, wantCachedJWT: "new"},
+		"Success using the cached JWT when the store cannot renew it": {cachedJWT: jwtExpiring, jwtError: true, wantCachedJWT: "cached"},
+
+		"Error when the store's GenerateUserJWT fails":                       {jwtError: true, wantErr: true},
+		"Error when the store's GenerateUserJWT fails and the JWT expired":   {cachedJWT: jwtExpired, jwtError: true, wantErr: true},
+		"Error when the contract server's GetServerAccessToken fails":        {getServerAccessTokenErr: true, wantErr: true},
+		"Error when the contract server's GetProToken fails":                 {getProTokenErr: true, wantErr: true},
+		"Error when the contract server's GetProToken fails with cached JWT": {cachedJWT: jwtFresh, getProTokenErr: true, wantErr: true},
 	}
 
 	for name, tc := range testCases {
@@ -53,6 +70,26 @@ func TestProToken(t *testing.T) {
 				jwtErr:         tc.jwtError,
 			}
 
+			// The Microsoft Store clock lags behind, but the JWT is valid for an hour nonetheless.
+			issued := time.Now().Add(-tc.storeClockLag)
+			newJWT := makeJWT(t, issued, issued.Add(time.Hour))
+
+			var cache *mockCache
+			if tc.wantCachedJWT != "" || tc.cachedJWT != jwtNone {
+				store.jwt = newJWT
+				cache = &mockCache{}
+			}
+
+			cachedJWT := makeJWT(t, time.Now(), time.Now().Add(time.Hour))
+			switch tc.cachedJWT {
+			case jwtFresh:
+				cache.entitlement.JWT, cache.entitlement.JWTExpiration = cachedJWT, time.Now().Add(time.Hour)
+			case jwtExpiring:
+				cache.entitlement.JWT, cache.entitlement.JWTExpiration = cachedJWT, time.Now().Add(time.Minute)
+			case jwtExpired:
+				cache.entitlement.JWT, cache.entitlement.JWTExpiration = cachedJWT, time.Now().Add(-time.Minute)
+			}
+
 			settings := contractsmockserver.DefaultSettings()
 
 			settings.Token.OnSuccess.Value = azureADToken
@@ -71,7 +108,12 @@ func TestProToken(t *testing.T) {
 			url, err := url.Parse(fmt.Sprintf("http://%s", addr))
 			require.NoError(t, err, "Setup: Server URL should have been parsed with no issues")
 
-			token, err := contracts.NewProToken(ctx, contracts.WithProURL(url), contracts.WithMockMicrosoftStore(store))
+			args := []contracts.Option{contracts.WithProURL(url), contracts.WithMockMicrosoftStore(store)}
+			if cache != nil {
+				args = append(args, contracts.WithEntitlementCache(cache))
+			}
+
+			token, err := contracts.NewProToken(ctx, args...)
 			if tc.wantErr {
 				require.Error(t, err, "ProToken should return an error")
 				return
@@ -79,6 +121,20 @@ func TestProToken(t *testing.T) {
 			require.NoError(t, err, "ProToken should return no error")
 
 			require.Equal(t, ubuntuProToken, token, "Unexpected value for the pro token")
+
+			if tc.wantCachedJWT == "" {
+				return
+			}
+
+			got := cache.entitlement
+			if tc.wantCachedJWT == "cached" {
+				require.Equal(t, cachedJWT, got.JWT, "The cached JWT should have been kept")
+				return
+			}
+
+			require.Equal(t, newJWT, got.JWT, "The new JWT should have been cached")
+			require.WithinDuration(t, time.Now().Add(time.Hour), got.JWTExpiration, time.Minute,
+				"The JWT expiration should follow its lifetime, regardless of the clock of the Microsoft Store")
 		})
 	}
 }
@@ -90,6 +146,7 @@ func TestValidSubscription(t *testing.T) {
 	const (
 		subscribed subscriptionStatus = iota
 		expired
+		justExpired
 		unsubscribed
 	)
 
@@ -97,14 +154,21 @@ func TestValidSubscription(t *testing.T) {
 		status        subscriptionStatus
 		expirationErr bool
 
+		cachedExpiration time.Time
+		cacheAge         time.Duration
+
 		want    bool
 		wantErr bool
 	}{
-		"Succcess when the current subscription is active":  {status: subscribed, want: true},
-		"Succcess when the current subscription is expired": {status: expired, want: false},
-		"Success when there is no subscription":             {status: unsubscribed, want: false},
+		"Succcess when the current subscription is active":               {status: subscribed, want: true},
+		"Succcess when the current subscription is expired":              {status: expired, want: false},
+		"Success when there is no subscription":                          {status: unsubscribed, want: false},
+		"Success when the subscription expired within the clock skew":    {status: justExpired, want: true},
+		"Success using the cached expiration when the store is down":     {expirationErr: true, cachedExpiration: time.Now().Add(time.Hour), cacheAge: time.Hour, want: true},
+		"Success when the cached subscription expired and store is down": {expirationErr: true, cachedExpiration: time.Now().Add(-time.Hour), cacheAge: time.Hour, want: false},
 
 		"Error when subscription validity cannot be ascertained": {status: subscribed, expirationErr: true, wantErr: true},
+		"Error when the cached expiration is too old":            {expirationErr: true, cachedExpiration: time.Now().Add(time.Hour), cacheAge: 30 * 24 * time.Hour, wantErr: true},
 	}
 
 	for name, tc := range testCases {
@@ -118,6 +182,8 @@ func TestValidSubscription(t *testing.T) {
 				store.expirationDate = time.Now().Add(time.Hour * 24 * 365) // Next year
 			case expired:
 				store.expirationDate = time.Now().Add(-time.Hour * 24 * 365) // Last year
+			case justExpired:
+				store.expirationDate = time.Now().Add(-time.Minute)
 			case unsubscribed:
 				store.notSubscribed = true
 			}
@@ -126,7 +192,13 @@ func TestValidSubscription(t *testing.T) {
 				store.expirationDateErr = true
 			}
 
-			got, err := contracts.ValidSubscription(contracts.WithMockMicrosoftStore(store))
+			cache := &mockCache{}
+			if !tc.cachedExpiration.IsZero() {
+				cache.entitlement.SubscriptionExpiration = tc.cachedExpiration
+				cache.entitlement.Checked = time.Now().Add(-tc.cacheAge)
+			}
+
+			got, err := contracts.ValidSubscription(context.Background(), contracts.WithMockMicrosoftStore(store), contracts.WithEntitlementCache(cache))
 			if tc.wantErr {
 				require.Error(t, err, "contracts.ValidSubscription should have returned an error")
 				return
@@ -134,10 +206,158 @@ func TestValidSubscription(t *testing.T) {
 
 			require.NoError(t, err, "contracts.ValidSubscription should have returned no error")
 			require.Equal(t, tc.want, got, "Unexpected return from ValidSubscription")
+
+			if tc.expirationErr {
+				return
+			}
+
+			require.WithinDuration(t, time.Now(), cache.entitlement.Checked, time.Minute, "The check should have been cached")
+			require.Equal(t, store.expirationDate, cache.entitlement.SubscriptionExpiration, "The subscription expiration should have been cached")
+		})
+	}
+}
+
+func TestRefreshEntitlement(t *testing.T) {
+	t.Parallel()
+
+	//nolint:gosec // These are not real tokens
+	const azureADToken = "AZURE_AD_TOKEN"
+
+	testCases := map[string]struct {
+		noCache            bool
+		noCachedExpiration bool
+		cachedJWT          cachedJWTState
+		jwtErr             bool
+		expirationErr      bool
+
+		wantNextIn time.Duration
+		wantNewJWT bool
+		wantErr    bool
+	}{
+		"Success without a cache":                        {noCache: true, jwtErr: true, expirationErr: true, wantNextIn: 6 * time.Hour},
+		"Success keeping a fresh JWT":                    {cachedJWT: jwtFresh, jwtErr: true, wantNextIn: 45 * time.Minute},
+		"Success renewing a JWT about to expire":         {cachedJWT: jwtExpiring, wantNextIn: 45 * time.Minute, wantNewJWT: true},
+		"Success renewing an expired JWT":                {cachedJWT: jwtExpired, wantNextIn: 45 * time.Minute, wantNewJWT: true},
+		"Success generating a JWT when none is cached":   {wantNextIn: 45 * time.Minute, wantNewJWT: true},
+		"Success using the cache when the store is down": {cachedJWT: jwtFresh, expirationErr: true, wantNextIn: 45 * time.Minute},
+
+		"Error when the JWT cannot be renewed":              {cachedJWT: jwtExpiring, jwtErr: true, wantErr: true},
+		"Error when the subscription cannot be ascertained": {cachedJWT: jwtFresh, expirationErr: true, noCachedExpiration: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			newJWT := makeJWT(t, time.Now(), time.Now().Add(time.Hour))
+			store := mockMSStore{
+				expirationDate:    time.Now().Add(24 * 365 * time.Hour), // Next year
+				expirationDateErr: tc.expirationErr,
+
+				jwt:            newJWT,
+				jwtWantADToken: azureADToken,
+				jwtErr:         tc.jwtErr,
+			}
+
+			settings := contractsmockserver.DefaultSettings()
+			settings.Token.OnSuccess.Value = azureADToken
+
+			server := contractsmockserver.NewServer(settings)
+			err := server.Serve(ctx, "localhost:0")
+			require.NoError(t, err, "Setup: Server should return no error")
+			//nolint:errcheck // Nothing we can do about it
+			defer server.Stop()
+
+			url, err := url.Parse(fmt.Sprintf("http://%s", server.Address()))
+			require.NoError(t, err, "Setup: Server URL should have been parsed with no issues")
+
+			cache := &mockCache{}
+			if !tc.noCachedExpiration {
+				cache.entitlement.SubscriptionExpiration = store.expirationDate
+				cache.entitlement.Checked = time.Now().Add(-time.Hour)
+			}
+
+			cachedJWT := makeJWT(t, time.Now(), time.Now().Add(time.Hour))
+			switch tc.cachedJWT {
+			case jwtFresh:
+				cache.entitlement.JWT, cache.entitlement.JWTExpiration = cachedJWT, time.Now().Add(time.Hour)
+			case jwtExpiring:
+				cache.entitlement.JWT, cache.entitlement.JWTExpiration = cachedJWT, time.Now().Add(time.Minute)
+			case jwtExpired:
+				cache.entitlement.JWT, cache.entitlement.JWTExpiration = cachedJWT, time.Now().Add(-time.Minute)
+			}
+
+			args := []contracts.Option{contracts.WithProURL(url), contracts.WithMockMicrosoftStore(store)}
+			if !tc.noCache {
+				args = append(args, contracts.WithEntitlementCache(cache))
+			}
+
+			next, err := contracts.RefreshEntitlement(ctx, args...)
+			if tc.wantErr {
+				require.Error(t, err, "RefreshEntitlement should return an error")
+				return
+			}
+			require.NoError(t, err, "RefreshEntitlement should return no error")
+
+			require.WithinDuration(t, time.Now().Add(tc.wantNextIn), next, time.Minute, "Unexpected time for the next refresh")
+
+			if tc.noCache {
+				return
+			}
+
+			if tc.wantNewJWT {
+				require.Equal(t, newJWT, cache.entitlement.JWT, "The JWT should have been renewed")
+			} else {
+				require.Equal(t, cachedJWT, cache.entitlement.JWT, "The JWT should not have been renewed")
+			}
 		})
 	}
 }
 
+// cachedJWTState is the state of the JWT in the entitlement cache.
+type cachedJWTState int
+
+const (
+	jwtNone cachedJWTState = iota
+	jwtFresh
+	jwtExpiring
+	jwtExpired
+)
+
+// makeJWT returns an unsigned JWT with the given issue and expiration times.
+func makeJWT(t *testing.T, issued, expires time.Time) string {
+	t.Helper()
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	claims, err := json.Marshal(map[string]int64{"iat": issued.Unix(), "exp": expires.Unix()})
+	require.NoError(t, err, "Setup: could not marshal JWT claims")
+
+	return fmt.Sprintf("%s.%s.", header, base64.RawURLEncoding.EncodeToString(claims))
+}
+
+type mockCache struct {
+	entitlement config.StoreEntitlement
+	mu          sync.Mutex
+}
+
+func (c *mockCache) StoreEntitlement() (config.StoreEntitlement, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entitlement, nil
+}
+
+func (c *mockCache) SetStoreEntitlement(e config.StoreEntitlement) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entitlement = e
+	return nil
+}
+
 type mockMSStore struct {
 	jwt            string
 	jwtWantADToken string
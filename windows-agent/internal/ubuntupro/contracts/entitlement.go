@@ -0,0 +1,199 @@
+package contracts
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/config"
+)
+
+const (
+	// clockSkew is how far apart the clocks of this machine and of the Microsoft Store are tolerated to be.
+	clockSkew = 5 * time.Minute
+
+	// jwtRefreshMargin is how long before its expiration a user JWT is renewed.
+	jwtRefreshMargin = 15 * time.Minute
+
+	// entitlementCheckInterval is how often the subscription expiration is checked against the Microsoft Store.
+	entitlementCheckInterval = 6 * time.Hour
+
+	// maxEntitlementAge is how long the last known subscription expiration is trusted for while the
+	// Microsoft Store cannot be reached.
+	maxEntitlementAge = 7 * 24 * time.Hour
+)
+
+// EntitlementCache stores the last known state of the Microsoft Store subscription.
+type EntitlementCache interface {
+	StoreEntitlement() (config.StoreEntitlement, error)
+	SetStoreEntitlement(config.StoreEntitlement) error
+}
+
+// WithEntitlementCache caches the user JWT and the subscription expiration, so that they can be used
+// while the Microsoft Store is unreachable.
+func WithEntitlementCache(cache EntitlementCache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// RefreshEntitlement renews the cached user JWT if it is about to expire, and checks the subscription
+// expiration against the Microsoft Store. It returns when the entitlement should be refreshed next.
+// It does nothing without an entitlement cache.
+func RefreshEntitlement(ctx context.Context, args ...Option) (next time.Time, err error) {
+	opts := newOptions(args...)
+
+	next = time.Now().Add(entitlementCheckInterval)
+	if opts.cache == nil {
+		return next, nil
+	}
+
+	if _, err := validSubscription(ctx, opts); err != nil {
+		return time.Time{}, err
+	}
+
+	jwtDue, err := renewUserJWT(ctx, opts)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !jwtDue.IsZero() && jwtDue.Before(next) {
+		next = jwtDue
+	}
+
+	return next, nil
+}
+
+// renewUserJWT generates a new user JWT if the cached one is about to expire. It returns when the
+// JWT should be renewed next, or the zero time if its expiration is unknown.
+func renewUserJWT(ctx context.Context, opts options) (time.Time, error) {
+	e := opts.loadEntitlement(ctx)
+	if e.JWT != "" && time.Now().Add(jwtRefreshMargin).Before(e.JWTExpiration) {
+		return e.JWTExpiration.Add(-jwtRefreshMargin), nil
+	}
+
+	contractClient, err := opts.contractClient()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	adToken, err := contractClient.GetServerAccessToken(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if _, err := generateUserJWT(ctx, opts, adToken); err != nil {
+		return time.Time{}, err
+	}
+
+	e = opts.loadEntitlement(ctx)
+	if e.JWTExpiration.IsZero() {
+		return time.Time{}, nil
+	}
+
+	return e.JWTExpiration.Add(-jwtRefreshMargin), nil
+}
+
+// userJWT returns a user JWT from the Microsoft Store. The cached one is reused unless it is about
+// to expire. If the Microsoft Store cannot generate a new one, the cached one is used until it expires.
+func userJWT(ctx context.Context, opts options, getADToken func(context.Context) (string, error)) (string, error) {
+	e := opts.loadEntitlement(ctx)
+	if e.JWT != "" && time.Now().Add(jwtRefreshMargin).Before(e.JWTExpiration) {
+		return e.JWT, nil
+	}
+
+	jwt, err := func() (string, error) {
+		adToken, err := getADToken(ctx)
+		if err != nil {
+			return "", err
+		}
+		return generateUserJWT(ctx, opts, adToken)
+	}()
+
+	if err != nil && e.JWT != "" && time.Now().Before(e.JWTExpiration) {
+		log.Warningf(ctx, "Contracts: using the cached Microsoft Store JWT: could not renew it: %v", err)
+		return e.JWT, nil
+	}
+
+	return jwt, err
+}
+
+// generateUserJWT asks the Microsoft Store for a new user JWT, and caches it.
+func generateUserJWT(ctx context.Context, opts options, adToken string) (string, error) {
+	jwt, err := opts.microsoftStore.GenerateUserJWT(adToken)
+	if err != nil {
+		return "", err
+	}
+
+	e := opts.loadEntitlement(ctx)
+	e.JWT = jwt
+	e.JWTExpiration = jwtExpiration(jwt, time.Now())
+
+	// A JWT without a known expiration cannot be reused safely.
+	if e.JWTExpiration.IsZero() {
+		e.JWT = ""
+	}
+
+	opts.storeEntitlement(ctx, e)
+
+	return jwt, nil
+}
+
+// jwtExpiration returns when the JWT expires according to the local clock, or the zero time if the
+// JWT does not state its expiration.
+//
+// If the JWT states when it was issued, its expiration is computed from its lifetime, which is not
+// affected by the difference between the local clock and the issuer's. Otherwise, the tolerated clock
+// skew is subtracted from its expiration.
+func jwtExpiration(jwt string, now time.Time) time.Time {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		IssuedAt  int64 `json:"iat"`
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+
+	if claims.IssuedAt != 0 && claims.IssuedAt < claims.ExpiresAt {
+		return now.Add(time.Duration(claims.ExpiresAt-claims.IssuedAt) * time.Second)
+	}
+
+	return time.Unix(claims.ExpiresAt, 0).Add(-clockSkew)
+}
+
+func (o options) loadEntitlement(ctx context.Context) config.StoreEntitlement {
+	if o.cache == nil {
+		return config.StoreEntitlement{}
+	}
+
+	e, err := o.cache.StoreEntitlement()
+	if err != nil {
+		log.Warningf(ctx, "Contracts: ignoring the cached Microsoft Store entitlement: %v", err)
+		return config.StoreEntitlement{}
+	}
+
+	return e
+}
+
+func (o options) storeEntitlement(ctx context.Context, e config.StoreEntitlement) {
+	if o.cache == nil {
+		return
+	}
+
+	if err := o.cache.SetStoreEntitlement(e); err != nil {
+		log.Warningf(ctx, "Contracts: could not cache the Microsoft Store entitlement: %v", err)
+	}
+}
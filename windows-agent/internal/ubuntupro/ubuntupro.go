@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
@@ -15,15 +16,17 @@ import (
 	"github.com/ubuntu/decorate"
 )
 
-// Distribute sends the current subscription token to all distros.
-func Distribute(ctx context.Context, db *database.DistroDB, ubuntuProToken string) {
-	task := tasks.ProAttachment{
-		Token: ubuntuProToken,
-	}
-
+// Distribute sends the current subscription token to all distros. Distros with a token of their own
+// in distroTokens are sent that one instead.
+func Distribute(ctx context.Context, db *database.DistroDB, ubuntuProToken string, distroTokens map[string]string) {
 	var err error
 	for _, distro := range db.GetAll() {
-		err = errors.Join(err, distro.SubmitTasks(task))
+		token, ok := distroTokens[distro.Name()]
+		if !ok {
+			token = ubuntuProToken
+		}
+
+		err = errors.Join(err, distro.SubmitTasks(tasks.ProAttachment{Token: token}))
 	}
 
 	if err != nil {
@@ -35,6 +38,8 @@ func Distribute(ctx context.Context, db *database.DistroDB, ubuntuProToken strin
 type Config interface {
 	Subscription() (string, config.Source, error)
 	SetStoreSubscription(context.Context, string) error
+	StoreEntitlement() (config.StoreEntitlement, error)
+	SetStoreEntitlement(config.StoreEntitlement) error
 }
 
 // FetchFromMicrosoftStore contacts Ubuntu Pro's contract server and the Microsoft Store
@@ -42,6 +47,8 @@ type Config interface {
 func FetchFromMicrosoftStore(ctx context.Context, conf Config, db *database.DistroDB, args ...contracts.Option) (err error) {
 	defer decorate.OnError(&err, "config: could not validate subscription against Microsoft Store")
 
+	args = append([]contracts.Option{contracts.WithEntitlementCache(conf)}, args...)
+
 	_, src, err := conf.Subscription()
 	if err != nil {
 		return fmt.Errorf("could not get current subscription status: %v", err)
@@ -50,7 +57,7 @@ func FetchFromMicrosoftStore(ctx context.Context, conf Config, db *database.Dist
 	// Shortcut to avoid spamming the contract server
 	// We don't need to request a new token if we have a non-expired one
 	if src == config.SourceMicrosoftStore {
-		valid, err := contracts.ValidSubscription(args...)
+		valid, err := contracts.ValidSubscription(ctx, args...)
 		if err != nil {
 			return fmt.Errorf("could not obtain current subscription status: %v", err)
 		}
@@ -82,3 +89,55 @@ func FetchFromMicrosoftStore(ctx context.Context, conf Config, db *database.Dist
 
 	return nil
 }
+
+const (
+	// minRefreshRetry and maxRefreshRetry bound the wait before retrying a failed entitlement refresh.
+	minRefreshRetry = time.Minute
+	maxRefreshRetry = time.Hour
+
+	// refreshInterval is how often the subscription source is checked when it is not the Microsoft Store.
+	refreshInterval = 6 * time.Hour
+)
+
+// RefreshStoreEntitlement keeps the cached Microsoft Store entitlement fresh until the context is
+// cancelled, so that it can bridge transient outages of the Microsoft Store. It only refreshes the
+// entitlement while the subscription comes from the Microsoft Store.
+func RefreshStoreEntitlement(ctx context.Context, conf Config, args ...contracts.Option) {
+	args = append([]contracts.Option{contracts.WithEntitlementCache(conf)}, args...)
+
+	var wait, retry time.Duration
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		next, err := refreshStoreEntitlement(ctx, conf, args...)
+		if err != nil {
+			retry = min(max(2*retry, minRefreshRetry), maxRefreshRetry)
+			log.Warningf(ctx, "Could not refresh the Microsoft Store entitlement, retrying in %s: %v", retry, err)
+			wait = retry
+			continue
+		}
+
+		retry = 0
+		wait = time.Until(next)
+	}
+}
+
+// refreshStoreEntitlement refreshes the entitlement if the subscription comes from the Microsoft Store.
+// It returns when it should be called next.
+func refreshStoreEntitlement(ctx context.Context, conf Config, args ...contracts.Option) (time.Time, error) {
+	_, src, err := conf.Subscription()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not get current subscription status: %v", err)
+	}
+
+	if src != config.SourceMicrosoftStore {
+		return time.Now().Add(refreshInterval), nil
+	}
+
+	return contracts.RefreshEntitlement(ctx, args...)
+}
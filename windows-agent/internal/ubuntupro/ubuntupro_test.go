@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -28,11 +29,13 @@ func TestDistribute(t *testing.T) {
 	testCases := map[string]struct {
 		distroIsDead bool
 		breakConfig  bool
+		distroToken  bool
 
 		wantErr bool
 	}{
 		"Success": {},
-		"Success when a task cannot be submitted": {distroIsDead: true},
+		"Success with a token assigned to the distro": {distroToken: true},
+		"Success when a task cannot be submitted":     {distroIsDead: true},
 	}
 
 	for name, tc := range testCases {
@@ -56,7 +59,12 @@ func TestDistribute(t *testing.T) {
 				dist.Invalidate(ctx)
 			}
 
-			ubuntupro.Distribute(ctx, db, "super_token")
+			var distroTokens map[string]string
+			if tc.distroToken {
+				distroTokens = map[string]string{distroName: "distro_token"}
+			}
+
+			ubuntupro.Distribute(ctx, db, "super_token", distroTokens)
 		})
 	}
 }
@@ -82,13 +90,15 @@ func TestFetchFromMicrosoftStore(t *testing.T) {
 
 		msStoreJWTErr        bool
 		msStoreExpirationErr bool
+		cachedSubscription   bool
 
 		wantToken string
 		wantErr   bool
 	}{
 		"Success": {wantToken: proToken},
-		"Success when there is a store token already":  {alreadyHaveToken: true, wantToken: oldProToken},
-		"Success when there is an expired store token": {alreadyHaveToken: true, subscriptionExpired: true, wantToken: proToken},
+		"Success when there is a store token already":                         {alreadyHaveToken: true, wantToken: oldProToken},
+		"Success when there is an expired store token":                        {alreadyHaveToken: true, subscriptionExpired: true, wantToken: proToken},
+		"Success when the store is down but the cached subscription is valid": {alreadyHaveToken: true, msStoreExpirationErr: true, cachedSubscription: true, wantToken: oldProToken},
 
 		// Config errors
 		"Error when the current subscription cannot be obtained": {breakSubscription: true, wantErr: true},
@@ -116,6 +126,13 @@ func TestFetchFromMicrosoftStore(t *testing.T) {
 				conf.storeProToken = oldProToken
 			}
 
+			if tc.cachedSubscription {
+				conf.entitlement = config.StoreEntitlement{
+					SubscriptionExpiration: time.Now().Add(24 * time.Hour),
+					Checked:                time.Now().Add(-time.Hour),
+				}
+			}
+
 			// Set up the mock Microsoft store
 			store := mockMSStore{
 				expirationDate:    time.Now().Add(24 * 365 * time.Hour), // Next year
@@ -156,6 +173,76 @@ func TestFetchFromMicrosoftStore(t *testing.T) {
 	}
 }
 
+func TestRefreshStoreEntitlement(t *testing.T) {
+	t.Parallel()
+
+	//nolint:gosec // This is not a real token
+	const azureADToken = "AZURE_AD_TOKEN_789"
+
+	testCases := map[string]struct {
+		notFromStore bool
+
+		wantRefreshed bool
+	}{
+		"Refreshes the entitlement when the subscription comes from the store": {wantRefreshed: true},
+		"Does nothing when the subscription does not come from the store":      {notFromStore: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf := &mockConfig{}
+			if !tc.notFromStore {
+				conf.storeProToken = "STORE_PRO_TOKEN"
+			}
+
+			store := mockMSStore{
+				expirationDate: time.Now().Add(24 * 365 * time.Hour), // Next year
+				jwt:            "JWT_123",
+			}
+
+			csSettings := contractsmockserver.DefaultSettings()
+			csSettings.Token.OnSuccess.Value = azureADToken
+			server := contractsmockserver.NewServer(csSettings)
+			err := server.Serve(ctx, "localhost:0")
+			require.NoError(t, err, "Setup: Server should return no error")
+			//nolint:errcheck // Nothing we can do about it
+			defer server.Stop()
+
+			csAddr, err := url.Parse(fmt.Sprintf("http://%s", server.Address()))
+			require.NoError(t, err, "Setup: Server URL should have been parsed with no issues")
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				ubuntupro.RefreshStoreEntitlement(ctx, conf, contracts.WithProURL(csAddr), contracts.WithMockMicrosoftStore(store))
+			}()
+
+			refreshed := func() bool {
+				e, err := conf.StoreEntitlement()
+				return err == nil && !e.Checked.IsZero()
+			}
+
+			if tc.wantRefreshed {
+				require.Eventually(t, refreshed, 5*time.Second, 100*time.Millisecond, "The entitlement should have been refreshed")
+			} else {
+				require.Never(t, refreshed, time.Second, 100*time.Millisecond, "The entitlement should not have been refreshed")
+			}
+
+			cancel()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "RefreshStoreEntitlement should have returned after the context was cancelled")
+			}
+		})
+	}
+}
+
 type mockMSStore struct {
 	jwt    string
 	jwtErr bool
@@ -182,12 +269,15 @@ func (s mockMSStore) GetSubscriptionExpirationDate() (tm time.Time, err error) {
 
 type mockConfig struct {
 	storeProToken string
+	entitlement   config.StoreEntitlement
 
 	subscriptionErr     bool
 	setStoreProTokenErr bool
+
+	mu sync.Mutex
 }
 
-func (c mockConfig) Subscription() (string, config.Source, error) {
+func (c *mockConfig) Subscription() (string, config.Source, error) {
 	if c.subscriptionErr {
 		return "", config.SourceNone, errors.New("mock config Subscription: mock error")
 	}
@@ -207,3 +297,18 @@ func (c *mockConfig) SetStoreSubscription(ctx context.Context, token string) err
 	c.storeProToken = token
 	return nil
 }
+
+func (c *mockConfig) StoreEntitlement() (config.StoreEntitlement, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entitlement, nil
+}
+
+func (c *mockConfig) SetStoreEntitlement(e config.StoreEntitlement) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entitlement = e
+	return nil
+}
@@ -0,0 +1,94 @@
+// Package wslcall runs the calls made to WSL through GoWSL with a timeout, and logs the slow ones.
+// wsl.exe sometimes hangs for minutes: the callers stop waiting for it instead of hanging along.
+//
+// The timeouts are carried by the context, the same way the GoWSL backend is, so that they reach every
+// call without being passed around.
+package wslcall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrTimeout is returned when WSL did not respond in time.
+var ErrTimeout = errors.New("WSL did not respond in time")
+
+type timeoutsKey struct{}
+
+// WithTimeouts returns a context whose calls to WSL use the timeouts of the policy. Unset timeouts
+// keep their default.
+func WithTimeouts(ctx context.Context, policy timeouts.Policy) context.Context {
+	return context.WithValue(ctx, timeoutsKey{}, policy.OrDefault())
+}
+
+func policyOf(ctx context.Context) timeouts.Policy {
+	if p, ok := ctx.Value(timeoutsKey{}).(timeouts.Policy); ok {
+		return p
+	}
+	return timeouts.Default()
+}
+
+// Query runs a call that reads the state of WSL, such as the state of a distro. It returns ErrTimeout
+// if the call takes longer than the query timeout. The name describes the call in the logs, such as
+// "querying the state of distro Ubuntu".
+func Query[T any](ctx context.Context, name string, call func(context.Context) (T, error)) (T, error) {
+	return run(ctx, name, policyOf(ctx).WSLQuery, call)
+}
+
+// Command runs a call that changes the state of WSL, such as registering a distro or running a command
+// in it. It returns ErrTimeout if the call takes longer than the command timeout.
+func Command(ctx context.Context, name string, call func(context.Context) error) error {
+	_, err := run(ctx, name, policyOf(ctx).WSLCommand, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	})
+	return err
+}
+
+// run runs the call, waiting for it until the timeout expires or the context is done. The calls that
+// take a context are cancelled then, while the others are left to return in the background.
+func run[T any](ctx context.Context, name string, timeout time.Duration, call func(context.Context) (T, error)) (v T, err error) {
+	ctx, span := telemetry.Start(ctx, "wsl.call", attribute.String("call", name))
+	defer telemetry.End(span, &err)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		v   T
+		err error
+	}
+
+	slow := policyOf(ctx).WSLSlowCall
+	start := time.Now()
+	done := make(chan result, 1)
+
+	go func() {
+		v, err := call(ctx)
+		done <- result{v, err}
+
+		if took := time.Since(start); took > timeout {
+			log.Warningf(context.WithoutCancel(ctx), "WSL: %s returned after %s, once the caller stopped waiting", name, took.Round(time.Millisecond))
+		}
+	}()
+
+	select {
+	case r := <-done:
+		if took := time.Since(start); took > slow {
+			log.Warningf(ctx, "WSL: %s was slow: it took %s", name, took.Round(time.Millisecond))
+		}
+		return r.v, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Errorf(ctx, "WSL: %s timed out after %s", name, timeout)
+			return v, fmt.Errorf("%s: %w", name, ErrTimeout)
+		}
+		return v, fmt.Errorf("%s: %v", name, ctx.Err())
+	}
+}
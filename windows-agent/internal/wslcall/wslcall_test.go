@@ -0,0 +1,100 @@
+package wslcall_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/timeouts"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		callErr      error
+		hang         bool
+		ignoreCtx    bool
+		cancelParent bool
+
+		wantTimeout bool
+		wantErr     bool
+	}{
+		"Success returning the result of the call": {},
+
+		"Error when the call fails":                                   {callErr: errors.New("mock error"), wantErr: true},
+		"Error when the call times out":                               {hang: true, wantTimeout: true, wantErr: true},
+		"Error when the call times out without honouring the context": {hang: true, ignoreCtx: true, wantTimeout: true, wantErr: true},
+		"Error when the context is cancelled":                         {hang: true, cancelParent: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			timeout := 200 * time.Millisecond
+			if tc.cancelParent {
+				timeout = time.Hour
+			}
+			ctx = wslcall.WithTimeouts(ctx, timeouts.Policy{WSLQuery: timeout})
+
+			// The hung calls that ignore the context are released once the test is done.
+			release := make(chan struct{})
+			defer close(release)
+
+			if tc.cancelParent {
+				go func() {
+					time.Sleep(100 * time.Millisecond)
+					cancel()
+				}()
+			}
+
+			start := time.Now()
+			got, err := wslcall.Query(ctx, "mock call", func(ctx context.Context) (string, error) {
+				if !tc.hang {
+					return "result", tc.callErr
+				}
+				if tc.ignoreCtx {
+					<-release
+					return "", nil
+				}
+				<-ctx.Done()
+				return "", ctx.Err()
+			})
+			require.Less(t, time.Since(start), 10*time.Second, "Query should not wait for a call that hangs")
+
+			if tc.wantErr {
+				require.Error(t, err, "Query should return an error")
+				require.Equal(t, tc.wantTimeout, errors.Is(err, wslcall.ErrTimeout), "Unexpected timeout error: %v", err)
+				return
+			}
+			require.NoError(t, err, "Query should return no error")
+			require.Equal(t, "result", got, "Query should return the result of the call")
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx := wslcall.WithTimeouts(context.Background(), timeouts.Policy{WSLQuery: time.Millisecond, WSLCommand: time.Hour})
+
+	// Commands do not use the timeout of the queries.
+	err := wslcall.Command(ctx, "mock command", func(context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+	require.NoError(t, err, "Command should return no error")
+
+	err = wslcall.Command(ctx, "mock command", func(context.Context) error {
+		return errors.New("mock error")
+	})
+	require.Error(t, err, "Command should return the error of the call")
+	require.NotErrorIs(t, err, wslcall.ErrTimeout, "Command should not report a timeout")
+}
@@ -0,0 +1,224 @@
+// Package wslconfig reads and updates the global WSL settings of the user, stored in their
+// .wslconfig file. The settings apply to every distro, and only take effect once WSL restarts.
+package wslconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
+	"github.com/ubuntu/decorate"
+	wsl "github.com/ubuntu/gowsl"
+	"gopkg.in/ini.v1"
+)
+
+// section is the section of .wslconfig that configures the WSL 2 virtual machine.
+const section = "wsl2"
+
+// networkingModes are the networking modes supported by WSL.
+var networkingModes = []string{"nat", "mirrored", "virtioproxy", "none"}
+
+// memoryRegex matches sizes such as "8GB", as understood by WSL.
+var memoryRegex = regexp.MustCompile(`(?i)^[0-9]+(B|KB|MB|GB|TB)$`)
+
+// Settings are the global WSL settings managed by the agent. Empty values leave the decision to WSL.
+type Settings struct {
+	// Memory is how much memory the WSL virtual machine can use, such as "8GB".
+	Memory string
+
+	// Processors is how many logical processors the WSL virtual machine can use.
+	Processors uint32
+
+	// NetworkingMode is how the WSL virtual machine connects to the network, such as "mirrored".
+	NetworkingMode string
+}
+
+// validate returns an error if WSL would not understand the settings.
+func (s Settings) validate() error {
+	if s.Memory != "" && !memoryRegex.MatchString(s.Memory) {
+		return fmt.Errorf("memory %q must be a number followed by a unit, such as 8GB", s.Memory)
+	}
+
+	if n := runtime.NumCPU(); s.Processors > uint32(n) {
+		return fmt.Errorf("cannot use %d processors: the machine only has %d", s.Processors, n)
+	}
+
+	if s.NetworkingMode != "" && !slices.Contains(networkingModes, strings.ToLower(s.NetworkingMode)) {
+		return fmt.Errorf("networking mode %q must be one of %s", s.NetworkingMode, strings.Join(networkingModes, ", "))
+	}
+
+	return nil
+}
+
+// File is the .wslconfig file of the user.
+type File struct {
+	ctx  context.Context
+	path string
+
+	// restartRequired is true after a change while WSL was running, until WSL is seen stopped.
+	restartRequired bool
+
+	mu sync.Mutex
+}
+
+// DefaultPath returns the path to the .wslconfig file of the current user.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find the .wslconfig file: %v", err)
+	}
+	return filepath.Join(home, ".wslconfig"), nil
+}
+
+// New creates a File to manage the .wslconfig at the path.
+func New(ctx context.Context, path string) *File {
+	return &File{
+		ctx:  ctx,
+		path: path,
+	}
+}
+
+// Settings returns the global WSL settings. A missing file means WSL uses its defaults.
+func (f *File) Settings() (s Settings, err error) {
+	defer decorate.OnError(&err, "could not read the global WSL settings")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := f.load()
+	if err != nil {
+		return s, err
+	}
+
+	sec := file.Section(section)
+	s.Memory = sec.Key("memory").String()
+	s.NetworkingMode = sec.Key("networkingMode").String()
+
+	if v := sec.Key("processors").String(); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return s, fmt.Errorf("could not parse the number of processors %q: %v", v, err)
+		}
+		s.Processors = uint32(n)
+	}
+
+	return s, nil
+}
+
+// SetSettings overwrites the global WSL settings. Empty values are removed from the file, so that
+// WSL uses its defaults. Any other setting in the file is left untouched.
+func (f *File) SetSettings(s Settings) (err error) {
+	defer decorate.OnError(&err, "could not set the global WSL settings")
+
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	sec := file.Section(section)
+	setOrDelete(sec, "memory", s.Memory)
+	setOrDelete(sec, "networkingMode", strings.ToLower(s.NetworkingMode))
+	if s.Processors == 0 {
+		setOrDelete(sec, "processors", "")
+	} else {
+		setOrDelete(sec, "processors", strconv.FormatUint(uint64(s.Processors), 10))
+	}
+
+	var out strings.Builder
+	if _, err := file.WriteTo(&out); err != nil {
+		return fmt.Errorf("could not marshal the settings: %v", err)
+	}
+
+	if err := os.WriteFile(f.path, []byte(out.String()), 0600); err != nil {
+		return fmt.Errorf("could not write %s: %v", f.path, err)
+	}
+
+	// The settings only take effect the next time WSL starts.
+	if running, err := f.wslRunning(); err != nil || running {
+		f.restartRequired = true
+	}
+
+	return nil
+}
+
+// RestartRequired returns true if the settings were changed while WSL was running, and WSL has not
+// been stopped since. The new settings only take effect once WSL restarts.
+func (f *File) RestartRequired() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.restartRequired {
+		return false
+	}
+
+	if running, err := f.wslRunning(); err == nil && !running {
+		f.restartRequired = false
+	}
+
+	return f.restartRequired
+}
+
+// load parses the file, or returns an empty one if it does not exist.
+func (f *File) load() (*ini.File, error) {
+	out, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ini.Empty(), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", f.path, err)
+	}
+
+	file, err := ini.Load(out)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", f.path, err)
+	}
+
+	return file, nil
+}
+
+// wslRunning returns true if any distro is running, which keeps the WSL virtual machine up.
+func (f *File) wslRunning() (bool, error) {
+	distros, err := wslcall.Query(f.ctx, "listing the registered distros", wsl.RegisteredDistros)
+	if err != nil {
+		return false, err
+	}
+
+	for _, d := range distros {
+		state, err := wslcall.Query(f.ctx, fmt.Sprintf("querying the state of distro %q", d.Name()), func(context.Context) (wsl.State, error) {
+			return d.State()
+		})
+		if err != nil {
+			return false, err
+		}
+		if state == wsl.Running {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// setOrDelete sets the key in the section, or removes it if the value is empty.
+func setOrDelete(sec *ini.Section, key, value string) {
+	if value == "" {
+		sec.DeleteKey(key)
+		return
+	}
+	sec.Key(key).SetValue(value)
+}
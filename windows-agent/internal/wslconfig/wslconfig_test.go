@@ -0,0 +1,156 @@
+package wslconfig_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/wsltestutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslconfig"
+	"github.com/stretchr/testify/require"
+	wsl "github.com/ubuntu/gowsl"
+	wslmock "github.com/ubuntu/gowsl/mock"
+)
+
+func TestSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noFile    bool
+		breakFile bool
+
+		want    wslconfig.Settings
+		wantErr bool
+	}{
+		"Success reading settings":      {want: wslconfig.Settings{Memory: "8GB", Processors: 2, NetworkingMode: "mirrored"}},
+		"Success with no wsl2 section":  {},
+		"Success when there is no file": {noFile: true},
+
+		"Error when processors are not a number": {wantErr: true},
+		"Error when the file cannot be read":     {breakFile: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(testutils.TestFixturePath(t), ".wslconfig")
+			if tc.noFile || tc.breakFile {
+				path = filepath.Join(t.TempDir(), ".wslconfig")
+			}
+			if tc.breakFile {
+				require.NoError(t, os.Mkdir(path, 0700), "Setup: could not create a directory in place of the file")
+			}
+
+			got, err := wslconfig.New(context.Background(), path).Settings()
+			if tc.wantErr {
+				require.Error(t, err, "Settings should return an error")
+				return
+			}
+			require.NoError(t, err, "Settings should return no error")
+			require.Equal(t, tc.want, got, "Unexpected settings")
+		})
+	}
+}
+
+func TestSetSettings(t *testing.T) {
+	t.Parallel()
+
+	settings := wslconfig.Settings{Memory: "4GB", Processors: 1, NetworkingMode: "Mirrored"}
+
+	testCases := map[string]struct {
+		settings  wslconfig.Settings
+		noFile    bool
+		breakFile bool
+
+		wantErr bool
+	}{
+		"Success overwriting settings": {settings: settings},
+		"Success removing settings":    {},
+		"Success creating the file":    {settings: settings, noFile: true},
+
+		"Error when the memory has no unit":         {settings: wslconfig.Settings{Memory: "4096"}, wantErr: true},
+		"Error when there are too many processors":  {settings: wslconfig.Settings{Processors: uint32(runtime.NumCPU() + 1)}, wantErr: true},
+		"Error when the networking mode is unknown": {settings: wslconfig.Settings{NetworkingMode: "tunnel"}, wantErr: true},
+		"Error when the file cannot be written":     {settings: settings, breakFile: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), ".wslconfig")
+			if !tc.noFile && !tc.breakFile {
+				fixture := filepath.Join(testutils.TestFixturePath(t), ".wslconfig")
+				if _, err := os.Stat(fixture); err == nil {
+					out, err := os.ReadFile(fixture)
+					require.NoError(t, err, "Setup: could not read fixture")
+					require.NoError(t, os.WriteFile(path, out, 0600), "Setup: could not write .wslconfig")
+				}
+			}
+			if tc.breakFile {
+				require.NoError(t, os.Mkdir(path, 0700), "Setup: could not create a directory in place of the file")
+			}
+
+			f := wslconfig.New(context.Background(), path)
+
+			err := f.SetSettings(tc.settings)
+			if tc.wantErr {
+				require.Error(t, err, "SetSettings should return an error")
+				return
+			}
+			require.NoError(t, err, "SetSettings should return no error")
+
+			out, err := os.ReadFile(path)
+			require.NoError(t, err, "Could not read .wslconfig")
+
+			want := testutils.LoadWithUpdateFromGolden(t, string(out))
+			require.Equal(t, want, string(out), ".wslconfig does not match the golden file")
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	if !wsl.MockAvailable() {
+		t.Skip("This test can only run with the mock, as it needs to keep WSL running")
+	}
+	t.Parallel()
+
+	testCases := map[string]struct {
+		running bool
+		stopped bool
+
+		want bool
+	}{
+		"Restart required when WSL is running":          {running: true, want: true},
+		"No restart required when WSL is not running":   {},
+		"No restart required once WSL has been stopped": {running: true, stopped: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := wsl.WithMock(context.Background(), wslmock.New())
+
+			distroName, _ := wsltestutils.RegisterDistro(t, ctx, false)
+
+			d := wsl.NewDistro(ctx, distroName)
+			if tc.running {
+				require.NoError(t, d.Shell(wsl.WithCommand("exit 0")), "Setup: could not wake the distro up")
+				require.Equal(t, "Running", wsltestutils.DistroState(t, ctx, distroName), "Setup: the distro should be running")
+			}
+
+			f := wslconfig.New(ctx, filepath.Join(t.TempDir(), ".wslconfig"))
+			require.NoError(t, f.SetSettings(wslconfig.Settings{Memory: "4GB"}), "Setup: could not set the settings")
+
+			if tc.stopped {
+				require.NoError(t, d.Terminate(), "Setup: could not stop the distro")
+			}
+
+			require.Equal(t, tc.want, f.RestartRequired(), "Unexpected restart requirement")
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package wslwatch keeps track of whether WSL can be used. While wsl.exe or the WSL service are
+// unavailable, such as during a Windows update or after the service crashed, the operations of the
+// agent are held back instead of failing, and they resume once WSL works again.
+package wslwatch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/pause"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslcall"
+	wsl "github.com/ubuntu/gowsl"
+)
+
+// defaultInterval is how often WSL is probed while it is unavailable.
+const defaultInterval = 30 * time.Second
+
+// probeDistroName is the distro queried to check that wsl.exe works. It does not need to exist.
+const probeDistroName = "ubuntu-pro-for-wsl-probe"
+
+// Probe returns an error if WSL cannot be used.
+type Probe func(context.Context) error
+
+// Status tells whether WSL can be used.
+type Status struct {
+	Available bool
+
+	// Since is when WSL became unavailable. It is zero while it is available.
+	Since time.Time
+
+	// Err is why WSL cannot be used. It is nil while it is available.
+	Err error
+}
+
+// Watcher tracks whether WSL can be used. While it cannot, the watcher is paused: it can be used
+// wherever the pause of the agent is, so that operations wait for WSL to be back.
+type Watcher struct {
+	probe    Probe
+	interval time.Duration
+	clock    clock.Clock
+
+	// unavailable is paused while WSL cannot be used.
+	unavailable *pause.Switch
+
+	since time.Time
+	err   error
+	mu    sync.Mutex
+}
+
+type options struct {
+	probe    Probe
+	interval time.Duration
+	clock    clock.Clock
+}
+
+// Option is an optional argument for New.
+type Option func(*options)
+
+// WithProbe overrides how WSL is checked.
+func WithProbe(p Probe) Option {
+	return func(o *options) {
+		o.probe = p
+	}
+}
+
+// WithInterval overrides how often WSL is probed while it is unavailable.
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithClock overrides the clock used to time the probes.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// New creates a watcher that assumes WSL is available until a check says otherwise.
+func New(args ...Option) *Watcher {
+	opts := options{
+		probe:    probeWSL,
+		interval: defaultInterval,
+		clock:    clock.Real(),
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	return &Watcher{
+		probe:       opts.probe,
+		interval:    opts.interval,
+		clock:       opts.clock,
+		unavailable: pause.New(false),
+	}
+}
+
+// Check probes WSL and updates the status accordingly. It returns true if WSL can be used.
+func (w *Watcher) Check(ctx context.Context) bool {
+	err := w.probe(ctx)
+	if ctx.Err() != nil {
+		// An interrupted probe says nothing about WSL.
+		return w.Status().Available
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err != nil {
+		if w.unavailable.Set(true) {
+			w.since = w.clock.Now()
+			log.Warningf(ctx, "WSL is unavailable, operations are held back until it is back: %v", err)
+		}
+		w.err = err
+		return false
+	}
+
+	if w.unavailable.Set(false) {
+		log.Infof(ctx, "WSL is available again after %s: resuming operations", w.clock.Now().Sub(w.since).Round(time.Second))
+		w.since = time.Time{}
+		w.err = nil
+	}
+	return true
+}
+
+// Run probes WSL every so often while it is unavailable, until the context is done.
+func (w *Watcher) Run(ctx context.Context) {
+	for {
+		if err := w.unavailable.WaitPaused(ctx); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.clock.After(w.interval):
+		}
+
+		w.Check(ctx)
+	}
+}
+
+// Status returns whether WSL can be used, and why not.
+func (w *Watcher) Status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return Status{
+		Available: !w.unavailable.Paused(),
+		Since:     w.since,
+		Err:       w.err,
+	}
+}
+
+// Paused returns true while WSL cannot be used.
+func (w *Watcher) Paused() bool {
+	return w.unavailable.Paused()
+}
+
+// WaitResumed blocks until WSL can be used, or the context is done.
+func (w *Watcher) WaitResumed(ctx context.Context) error {
+	return w.unavailable.WaitResumed(ctx)
+}
+
+// WaitPaused blocks until WSL cannot be used, or the context is done.
+func (w *Watcher) WaitPaused(ctx context.Context) error {
+	return w.unavailable.WaitPaused(ctx)
+}
+
+// probeWSL queries wsl.exe for the state of a distro, which fails if wsl.exe or the WSL service
+// cannot be reached.
+func probeWSL(ctx context.Context) error {
+	_, err := wslcall.Query(ctx, "probing WSL", func(context.Context) (wsl.State, error) {
+		return wsl.NewDistro(ctx, probeDistroName).State()
+	})
+	return err
+}
@@ -0,0 +1,128 @@
+package wslwatch_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/clock"
+	"github.com/canonical/ubuntu-pro-for-wsl/windows-agent/internal/wslwatch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		probeErrs []bool
+
+		wantAvailable bool
+		wantSince     bool
+	}{
+		"Success when WSL works":                   {probeErrs: []bool{false}, wantAvailable: true},
+		"Success when WSL works again":             {probeErrs: []bool{true, false}, wantAvailable: true},
+		"Success before any check":                 {wantAvailable: true},
+		"WSL is unavailable when the probe fails":  {probeErrs: []bool{true}, wantSince: true},
+		"WSL is unavailable when the probe breaks": {probeErrs: []bool{false, true}, wantSince: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+			clk := clock.NewMock(start)
+			probe := &probeMock{}
+
+			w := wslwatch.New(wslwatch.WithProbe(probe.probe), wslwatch.WithClock(clk))
+
+			var got bool
+			for _, fail := range tc.probeErrs {
+				probe.fail.Store(fail)
+				got = w.Check(ctx)
+				clk.Advance(time.Minute)
+			}
+			if len(tc.probeErrs) > 0 {
+				require.Equal(t, tc.wantAvailable, got, "Check should return whether WSL is available")
+			}
+
+			status := w.Status()
+			require.Equal(t, tc.wantAvailable, status.Available, "Status should tell whether WSL is available")
+			require.Equal(t, !tc.wantAvailable, w.Paused(), "The watcher should be paused while WSL is unavailable")
+
+			if !tc.wantSince {
+				require.Zero(t, status.Since, "Status should not tell since when WSL is unavailable while it is available")
+				require.NoError(t, status.Err, "Status should not have an error while WSL is available")
+				return
+			}
+			require.Equal(t, start.Add(time.Duration(len(tc.probeErrs)-1)*time.Minute), status.Since, "Status should tell when WSL became unavailable")
+			require.Error(t, status.Err, "Status should tell why WSL is unavailable")
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clock.NewMock(time.Now())
+	probe := &probeMock{}
+	probe.fail.Store(true)
+
+	const interval = time.Minute
+	w := wslwatch.New(wslwatch.WithProbe(probe.probe), wslwatch.WithClock(clk), wslwatch.WithInterval(interval))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx)
+	}()
+
+	require.False(t, w.Check(ctx), "Setup: WSL should be unavailable")
+
+	// WSL is probed every interval until it is back.
+	for i := 1; i <= 3; i++ {
+		require.Eventually(t, func() bool { return clk.Waiters() == 1 }, 5*time.Second, 10*time.Millisecond, "Run should wait before probing WSL again")
+		clk.Advance(interval)
+		require.Eventually(t, func() bool { return probe.calls.Load() == int64(i+1) }, 5*time.Second, 10*time.Millisecond, "Run should probe WSL once the interval elapses")
+	}
+
+	probe.fail.Store(false)
+	require.Eventually(t, func() bool { return clk.Waiters() == 1 }, 5*time.Second, 10*time.Millisecond, "Run should wait before probing WSL again")
+	clk.Advance(interval)
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer waitCancel()
+	require.NoError(t, w.WaitResumed(waitCtx), "WSL should be available once the probe succeeds")
+
+	// Nothing is probed while WSL is available.
+	calls := probe.calls.Load()
+	clk.Advance(time.Hour)
+	require.Never(t, func() bool { return probe.calls.Load() != calls }, 500*time.Millisecond, 50*time.Millisecond, "Run should not probe WSL while it is available")
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Run should return once the context is cancelled")
+	}
+}
+
+type probeMock struct {
+	fail  atomic.Bool
+	calls atomic.Int64
+}
+
+func (p *probeMock) probe(context.Context) error {
+	p.calls.Add(1)
+	if p.fail.Load() {
+		return errors.New("mock error")
+	}
+	return nil
+}
@@ -7,3 +7,9 @@ func WithSystem(s system.System) func(*options) {
 		o.system = s
 	}
 }
+
+func WithSystemdNotifier(notify func(unsetEnvironment bool, state string) (bool, error)) func(*options) {
+	return func(o *options) {
+		o.systemdSdNotifier = notify
+	}
+}
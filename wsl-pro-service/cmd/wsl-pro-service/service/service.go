@@ -3,14 +3,22 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/consts"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/daemon"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/system"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/wslinstanceservice"
+	systemd "github.com/coreos/go-systemd/daemon"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -20,6 +28,10 @@ import (
 // cmdName is the binary name for the service.
 const cmdName = "wsl-pro-service"
 
+// quitTimeout is how long Quit waits for active requests before dropping them. When the host calls
+// wsl --shutdown, the distro is only given a few seconds to stop before being terminated.
+const quitTimeout = 5 * time.Second
+
 // App encapsulate commands and options of the daemon, which can be controlled by env variables and config files.
 type App struct {
 	rootCmd cobra.Command
@@ -33,10 +45,27 @@ type App struct {
 
 type daemonConfig struct {
 	Verbosity int
+
+	// LogLevel is the name of the log level to use when no verbosity flag is passed, such as "info" or "debug".
+	LogLevel string `mapstructure:"log_level"`
+
+	// AgentAddress is the address of the Windows Agent. Empty means reading it from the port file written by the agent.
+	AgentAddress string `mapstructure:"agent_address"`
+
+	// ConnectionTimeout is how long to wait for the Windows Agent on each connection attempt. Zero means no timeout.
+	ConnectionTimeout time.Duration `mapstructure:"connection_timeout"`
+
+	// Disabled prevents the service from connecting to the Windows Agent.
+	Disabled bool
+
+	// OTLPEndpoint is the URL of the OpenTelemetry collector traces are exported to. Empty disables the export.
+	// It is ignored unless the Telemetry feature flag is enabled in the Windows Agent.
+	OTLPEndpoint string `mapstructure:"otlp_endpoint"`
 }
 
 type options struct {
-	system system.System
+	system            system.System
+	systemdSdNotifier func(unsetEnvironment bool, state string) (bool, error)
 }
 
 type option func(*options)
@@ -59,13 +88,29 @@ func New(o ...option) *App {
 			a.viper.SetEnvPrefix("UP4W")
 			a.viper.AutomaticEnv()
 
+			configFound, err := a.readConfigFile()
+			if err != nil {
+				return err
+			}
+
 			if err := a.viper.Unmarshal(&a.config); err != nil {
 				return fmt.Errorf("unable to decode configuration into struct: %w", err)
 			}
 
 			setVerboseMode(a.config.Verbosity)
+			if a.config.Verbosity == 0 && a.config.LogLevel != "" {
+				level, err := logrus.ParseLevel(a.config.LogLevel)
+				if err != nil {
+					return fmt.Errorf("invalid log level in configuration: %v", err)
+				}
+				logrus.SetLevel(level)
+			}
 			log.Debug(context.Background(), "Debug mode is enabled")
 
+			if configFound {
+				log.Debugf(context.Background(), "Using configuration file %q", a.viper.ConfigFileUsed())
+			}
+
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -77,6 +122,8 @@ func New(o ...option) *App {
 	a.viper = viper.New()
 
 	installVerbosityFlag(&a.rootCmd, a.viper)
+	installOTLPEndpointFlag(&a.rootCmd, a.viper)
+	installConfigFlag(&a.rootCmd, a.viper)
 
 	// subcommands
 	a.installVersion()
@@ -86,19 +133,63 @@ func New(o ...option) *App {
 
 // serve creates new GRPC services and listen on a TCP socket. This call is blocking until we quit it.
 func (a *App) serve(args ...option) (err error) {
-	ctx := context.Background()
+	// The service acts on the distro as a whole on its own behalf. The calls from the agent are only
+	// granted the privileges they declare.
+	ctx := system.WithPrivilege(context.Background(), system.PrivilegeRoot)
 
 	opt := options{
-		system: system.New(),
+		system:            system.New(),
+		systemdSdNotifier: systemd.SdNotify,
 	}
 	for _, f := range args {
 		f(&opt)
 	}
 
+	if a.config.Disabled {
+		log.Warning(ctx, "WSL Pro Service is disabled by its configuration file: not connecting to the Windows Agent")
+		systemdNotifyNothingToDo(ctx, opt.systemdSdNotifier)
+		close(a.ready)
+		return nil
+	}
+
+	if wsl, err := opt.system.IsWSL(); err != nil {
+		log.Warningf(ctx, "Could not detect whether the system runs under WSL: %v", err)
+	} else if !wsl {
+		log.Warning(ctx, "WSL Pro Service is not running under WSL: there is no Windows Agent to connect to")
+		systemdNotifyNothingToDo(ctx, opt.systemdSdNotifier)
+		close(a.ready)
+		return nil
+	}
+
+	endpoint := a.config.OTLPEndpoint
+	if endpoint != "" && !telemetryEnabled(ctx, &opt.system) {
+		log.Warningf(ctx, "Not exporting traces to %s: the Telemetry feature flag is disabled in the Windows Agent", endpoint)
+		endpoint = ""
+	}
+
+	shutdownTelemetry, err := telemetry.Setup(ctx, cmdName, endpoint)
+	if err != nil {
+		close(a.ready)
+		return err
+	}
+	defer func() {
+		if err := shutdownTelemetry(context.Background()); err != nil {
+			log.Warningf(ctx, "Could not flush traces: %v", err)
+		}
+	}()
+
 	srv := wslinstanceservice.New(opt.system)
 
 	// Connect with the agent.
-	a.daemon, err = daemon.New(ctx, srv.RegisterGRPCService, opt.system)
+	var daemonOpts []daemon.Option
+	if a.config.AgentAddress != "" {
+		daemonOpts = append(daemonOpts, daemon.WithAgentAddress(a.config.AgentAddress))
+	}
+	if a.config.ConnectionTimeout != 0 {
+		daemonOpts = append(daemonOpts, daemon.WithConnectionTimeout(a.config.ConnectionTimeout))
+	}
+
+	a.daemon, err = daemon.New(ctx, srv.RegisterGRPCService, opt.system, daemonOpts...)
 	if err != nil {
 		close(a.ready)
 		return fmt.Errorf("could not create daemon: %v", err)
@@ -109,6 +200,14 @@ func (a *App) serve(args ...option) (err error) {
 	return a.daemon.Serve()
 }
 
+// systemdNotifyNothingToDo tells systemd that the service started and is stopping on purpose. The unit is
+// of type notify: exiting without sending READY=1 counts as a failure, which systemd would restart.
+func systemdNotifyNothingToDo(ctx context.Context, notify func(unsetEnvironment bool, state string) (bool, error)) {
+	if _, err := notify(false, "READY=1\nSTOPPING=1"); err != nil {
+		log.Warningf(ctx, "Could not tell systemd that there is nothing to do: %v", err)
+	}
+}
+
 // installVerbosityFlag adds the -v and -vv options and returns the reference to it.
 func installVerbosityFlag(cmd *cobra.Command, viper *viper.Viper) *int {
 	r := cmd.PersistentFlags().CountP("verbosity", "v", i18n.G("issue INFO (-v), DEBUG (-vv) or DEBUG with caller (-vvv) output"))
@@ -116,6 +215,51 @@ func installVerbosityFlag(cmd *cobra.Command, viper *viper.Viper) *int {
 	return r
 }
 
+// telemetryEnabled returns true if the Windows Agent tells the distros that the Telemetry feature flag is
+// enabled, so that telemetry stays opt-in.
+func telemetryEnabled(ctx context.Context, sys *system.System) bool {
+	home, err := sys.UserProfileDir(ctx)
+	if err != nil {
+		log.Warningf(ctx, "Could not find whether telemetry is enabled, assuming it is not: %v", err)
+		return false
+	}
+
+	_, err = os.Stat(filepath.Join(home, common.UserProfileDir, common.TelemetryFileName))
+	return err == nil
+}
+
+// installOTLPEndpointFlag adds the --otlp-endpoint option, which can also be set via the UP4W_OTLP_ENDPOINT environment variable.
+func installOTLPEndpointFlag(cmd *cobra.Command, viper *viper.Viper) {
+	cmd.Flags().String("otlp-endpoint", "", i18n.G("export traces to this OpenTelemetry collector (e.g. http://localhost:4317)"))
+	decorate.LogOnError(viper.BindPFlag("otlp_endpoint", cmd.Flags().Lookup("otlp-endpoint")))
+}
+
+// installConfigFlag adds the --config option, which can also be set via the UP4W_CONFIG environment variable.
+func installConfigFlag(cmd *cobra.Command, viper *viper.Viper) {
+	cmd.PersistentFlags().StringP("config", "c", consts.DefaultConfigPath, i18n.G("use a specific configuration file"))
+	decorate.LogOnError(viper.BindPFlag("config", cmd.PersistentFlags().Lookup("config")))
+}
+
+// readConfigFile loads the YAML configuration file into viper. A missing file is not an error,
+// in which case it returns false.
+func (a *App) readConfigFile() (found bool, err error) {
+	path := a.viper.GetString("config")
+	if path == "" {
+		return false, nil
+	}
+
+	a.viper.SetConfigFile(path)
+	a.viper.SetConfigType("yaml")
+
+	if err := a.viper.ReadInConfig(); errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("could not read configuration file %q: %v", path, err)
+	}
+
+	return true, nil
+}
+
 // SetVerboseMode change ErrorFormat and logs between very, middly and non verbose.
 func setVerboseMode(level int) {
 	var reportCaller bool
@@ -143,13 +287,26 @@ func (a App) UsageError() bool {
 	return !a.rootCmd.SilenceUsage
 }
 
-// Quit gracefully shutdown the service.
+// Quit gracefully shutdown the service. Active requests that do not finish in time are dropped.
 func (a *App) Quit() {
 	a.WaitReady()
 	if a.daemon == nil {
 		return
 	}
-	a.daemon.Quit(context.Background(), false)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.daemon.Quit(context.Background(), false)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(quitTimeout):
+		log.Warningf(context.Background(), "Active requests did not finish in %s: dropping them", quitTimeout)
+		a.daemon.Quit(context.Background(), true)
+		<-done
+	}
 }
 
 // WaitReady signals when the daemon is ready
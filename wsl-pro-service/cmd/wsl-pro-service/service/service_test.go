@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -167,6 +168,72 @@ func TestAppRunFailsOnComponentsCreationAndQuit(t *testing.T) {
 	require.Error(t, err, "Run should exit with an error")
 }
 
+func TestConfigFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		config string
+
+		wantErr bool
+	}{
+		"Success exiting early when the service is disabled": {config: "disabled: true\nlog_level: info\nconnection_timeout: 10s"},
+
+		"Error when the config file cannot be parsed":  {config: "disabled: [true", wantErr: true},
+		"Error when the log level is invalid":          {config: "disabled: true\nlog_level: chatty", wantErr: true},
+		"Error when the connection timeout is invalid": {config: "disabled: true\nconnection_timeout: forever", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sys, _ := testutils.MockSystem(t)
+
+			path := filepath.Join(t.TempDir(), "wsl-pro.conf")
+			err := os.WriteFile(path, []byte(tc.config), 0600)
+			require.NoError(t, err, "Setup: could not write config file")
+
+			var notified []string
+			notify := func(_ bool, state string) (bool, error) {
+				notified = append(notified, state)
+				return true, nil
+			}
+
+			a := service.New(service.WithSystem(sys), service.WithSystemdNotifier(notify))
+			a.SetArgs("--config", path)
+
+			err = a.Run()
+			if tc.wantErr {
+				require.Error(t, err, "Run should return an error")
+				return
+			}
+			require.NoError(t, err, "Run should return no error when the service is disabled")
+			require.Equal(t, []string{"READY=1\nSTOPPING=1"}, notified, "Run should tell systemd that it stops on purpose, so that it is not restarted")
+		})
+	}
+}
+
+func TestExitsWhenNotUnderWSL(t *testing.T) {
+	t.Parallel()
+
+	sys, mock := testutils.MockSystem(t)
+	err := os.WriteFile(mock.Path("/proc/sys/kernel/osrelease"), []byte("6.5.0-14-generic\n"), 0600)
+	require.NoError(t, err, "Setup: could not overwrite osrelease file")
+
+	var notified []string
+	notify := func(_ bool, state string) (bool, error) {
+		notified = append(notified, state)
+		return true, nil
+	}
+
+	a := service.New(service.WithSystem(sys), service.WithSystemdNotifier(notify))
+	a.SetArgs()
+
+	err = a.Run()
+	require.NoError(t, err, "Run should exit without error when not running under WSL")
+	require.Equal(t, []string{"READY=1\nSTOPPING=1"}, notified, "Run should tell systemd that it stops on purpose, so that it is not restarted")
+}
+
 func TestAppGetRootCmd(t *testing.T) {
 	t.Parallel()
 
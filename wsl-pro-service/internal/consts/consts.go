@@ -8,4 +8,10 @@ import (
 const (
 	// DefaultLogLevel is the default logging level selected without any option.
 	DefaultLogLevel = log.WarnLevel
+
+	// LogBufferSize is how many logs can wait to be sent or printed before they start being discarded.
+	LogBufferSize = 256
+
+	// DefaultConfigPath is the path of the configuration file read when no other one is specified.
+	DefaultConfigPath = "/etc/wsl-pro.conf"
 )
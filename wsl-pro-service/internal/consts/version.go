@@ -2,3 +2,11 @@ package consts
 
 // Version is the version name for Ubuntu Pro for WSL.
 var Version = "Dev"
+
+// Commit is the git commit the binary was built from. When not set at build time, it is taken from the
+// version control info embedded by Go.
+var Commit = ""
+
+// BuildDate is when the binary was built, in RFC 3339 format. When not set at build time, it is the
+// date of the commit.
+var BuildDate = ""
@@ -5,25 +5,77 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/system"
 	"github.com/ubuntu/decorate"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 )
 
 // ControlStream manages the connection to the control stream served by the Windows Agent.
 type ControlStream struct {
-	system   system.System
-	addrPath string
-	session  session
-	port     int
+	system       system.System
+	addrPath     string
+	hvsocketPath string
+	session      session
+	port         int
+
+	// agentAddress overrides the address read from the port file when not empty.
+	agentAddress string
+	dialTimeout  time.Duration
+
+	// portFile is the version of the port file read on the last connection attempt.
+	portFile portFileVersion
+}
+
+// portFileVersion identifies a version of the port file. The modification time tells apart two
+// runs of the agent that happen to listen on the same port.
+type portFileVersion struct {
+	contents string
+	modTime  time.Time
+}
+
+// portFilePollInterval is how often the port file is read while waiting for it to change.
+const portFilePollInterval = time.Second
+
+// hvsocketDialTimeout is how long connecting via the Hyper-V socket may take before falling back to TCP.
+const hvsocketDialTimeout = 2 * time.Second
+
+// dialHvsocket connects to the vsock port of the Windows host. It is a variable so that tests can replace it.
+var dialHvsocket = dialVsock
+
+type options struct {
+	agentAddress string
+	dialTimeout  time.Duration
+}
+
+// Option is an optional argument for New.
+type Option func(*options)
+
+// WithAgentAddress makes the control stream connect to this address instead of the one
+// written by the Windows Agent in the port file.
+func WithAgentAddress(addr string) Option {
+	return func(o *options) {
+		o.agentAddress = addr
+	}
+}
+
+// WithDialTimeout makes Connect fail if the Windows Agent cannot be reached in this amount of time.
+// A zero timeout means no timeout.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.dialTimeout = timeout
+	}
 }
 
 // SystemError is an error caused by a misconfiguration of the system, rather than
@@ -41,15 +93,23 @@ func (err SystemError) Error() string {
 }
 
 // New creates an idle control stream object.
-func New(ctx context.Context, s system.System) (ControlStream, error) {
+func New(ctx context.Context, s system.System, args ...Option) (ControlStream, error) {
+	var opts options
+	for _, f := range args {
+		f(&opts)
+	}
+
 	home, err := s.UserProfileDir(ctx)
 	if err != nil {
 		return ControlStream{}, fmt.Errorf("could not find address file: could not find $env:UserProfile: %v", err)
 	}
 
 	return ControlStream{
-		addrPath: filepath.Join(home, common.UserProfileDir, common.ListeningPortFileName),
-		system:   s,
+		addrPath:     filepath.Join(home, common.UserProfileDir, common.ListeningPortFileName),
+		hvsocketPath: filepath.Join(home, common.UserProfileDir, common.HvsocketPortFileName),
+		system:       s,
+		agentAddress: opts.agentAddress,
+		dialTimeout:  opts.dialTimeout,
 	}, nil
 }
 
@@ -57,18 +117,13 @@ func New(ctx context.Context, s system.System) (ControlStream, error) {
 func (cs *ControlStream) Connect(ctx context.Context) (err error) {
 	defer decorate.OnError(&err, "could not connect to Windows Agent via the control stream")
 
-	ctrlAddr, err := cs.address(ctx)
-	if err != nil {
-		return fmt.Errorf("could not get address: %w", err)
-	}
-
 	distroName, err := cs.system.WslDistroName(ctx)
 	if err != nil {
 		log.Warningf(ctx, "Controlstream: assigning arbitrary connection ID because of error: %v", err)
 		distroName = ""
 	}
 
-	session, err := newSession(ctx, ctrlAddr, distroName)
+	session, err := cs.dial(ctx, distroName)
 	if err != nil {
 		return err
 	}
@@ -88,6 +143,57 @@ func (cs *ControlStream) Connect(ctx context.Context) (err error) {
 	return nil
 }
 
+// dial starts a session with the Windows Agent. The Hyper-V socket is preferred when the agent serves one,
+// because it does not depend on the network between Windows and WSL, which VPNs and mirrored networking
+// can break. TCP is used otherwise.
+func (cs *ControlStream) dial(ctx context.Context, distroName string) (session, error) {
+	if port, ok := cs.hvsocketPort(ctx); ok {
+		dialer := func(ctx context.Context, _ string) (net.Conn, error) { return dialHvsocket(ctx, port) }
+
+		s, err := newSession(ctx, fmt.Sprintf("vsock:%d", port), distroName, hvsocketDialTimeout,
+			grpc.WithContextDialer(dialer), grpc.FailOnNonTempDialError(true))
+		if err == nil {
+			// The port file is still the one watched for changes by AddressChanged.
+			if v, err := readPortFile(cs.addrPath); err == nil {
+				cs.portFile = v
+			}
+			return s, nil
+		}
+		log.Warningf(ctx, "Control stream: could not connect via the Hyper-V socket, falling back to TCP: %v", err)
+	}
+
+	ctrlAddr, err := cs.address(ctx)
+	if err != nil {
+		return session{}, fmt.Errorf("could not get address: %w", err)
+	}
+
+	return newSession(ctx, ctrlAddr, distroName, cs.dialTimeout)
+}
+
+// hvsocketPort returns the vsock port of the Hyper-V socket served by the Windows Agent, if any.
+// It is never used when the address was set via WithAgentAddress.
+func (cs *ControlStream) hvsocketPort(ctx context.Context) (uint32, bool) {
+	if cs.agentAddress != "" {
+		return 0, false
+	}
+
+	out, err := os.ReadFile(cs.hvsocketPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, false
+	} else if err != nil {
+		log.Warningf(ctx, "Control stream: could not read the Hyper-V socket port file: %v", err)
+		return 0, false
+	}
+
+	port, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 32)
+	if err != nil || port == 0 {
+		log.Warningf(ctx, "Control stream: ignoring invalid Hyper-V socket port file: %q", out)
+		return 0, false
+	}
+
+	return uint32(port), true
+}
+
 func (cs *ControlStream) handshake(ctx context.Context, session session) (port int, err error) {
 	defer decorate.OnError(&err, "could not complete handshake")
 
@@ -120,19 +226,24 @@ func (cs *ControlStream) Disconnect() {
 }
 
 // address fetches the address of the control stream from the Windows filesystem.
-func (cs ControlStream) address(ctx context.Context) (string, error) {
+// The address set via WithAgentAddress takes precedence.
+func (cs *ControlStream) address(ctx context.Context) (string, error) {
+	if cs.agentAddress != "" {
+		return cs.agentAddress, nil
+	}
+
 	windowsLocalhost, err := cs.system.WindowsHostAddress(ctx)
 	if err != nil {
 		return "", SystemError{err}
 	}
 
 	// Parse the port from the file written by the windows agent.
-	addr, err := os.ReadFile(cs.addrPath)
+	cs.portFile, err = readPortFile(cs.addrPath)
 	if err != nil {
 		return "", fmt.Errorf("could not read agent port file %q: %v", cs.addrPath, err)
 	}
 
-	port, err := splitPort(string(addr))
+	port, err := splitPort(cs.portFile.contents)
 	if err != nil {
 		return "", err
 	}
@@ -143,6 +254,21 @@ func (cs ControlStream) address(ctx context.Context) (string, error) {
 	return address, nil
 }
 
+// readPortFile reads the port file written by the Windows Agent.
+func readPortFile(path string) (portFileVersion, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return portFileVersion{}, err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return portFileVersion{}, err
+	}
+
+	return portFileVersion{contents: string(contents), modTime: info.ModTime()}, nil
+}
+
 // splitPort splits the port from the address, and validates that the port is a strictly positive integer.
 func splitPort(addr string) (p int, err error) {
 	defer decorate.OnError(&err, "could not parse port from %q", addr)
@@ -178,6 +304,43 @@ func (cs ControlStream) Send(info *agentapi.DistroInfo) error {
 	return cs.session.send(info)
 }
 
+// AddressChanged returns a channel that is closed once the port file is rewritten after the last
+// connection attempt, e.g. because the Windows Agent restarted after a crash. It never closes when the address was set via WithAgentAddress.
+// Cancel the context to release resources.
+func (cs ControlStream) AddressChanged(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{})
+
+	if cs.agentAddress != "" {
+		return ch
+	}
+
+	go func() {
+		ticker := time.NewTicker(portFilePollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// A missing file is not a change: the agent removes it while restarting.
+			v, err := readPortFile(cs.addrPath)
+			if err != nil {
+				continue
+			}
+
+			if v.contents != cs.portFile.contents || !v.modTime.Equal(cs.portFile.modTime) {
+				close(ch)
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
 // Done returns a channel that blocks for as long as the connection to the stream lasts.
 // Cancel the context to release resources.
 func (cs ControlStream) Done(ctx context.Context) <-chan struct{} {
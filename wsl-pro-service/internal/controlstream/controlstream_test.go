@@ -7,10 +7,12 @@ import (
 	"io/fs"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	"github.com/canonical/ubuntu-pro-for-wsl/common"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/controlstream"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/testutils"
 	log "github.com/sirupsen/logrus"
@@ -43,7 +45,7 @@ func TestNew(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(testutils.RootContext())
 			defer cancel()
 
 			sys, mock := testutils.MockSystem(t)
@@ -84,6 +86,8 @@ func TestConnect(t *testing.T) {
 		portFile              dataFileState
 		breakWindowsLocalhost bool
 		breakWSlDistroName    bool
+		overrideAddress       bool
+		dialTimeout           time.Duration
 
 		agentDoesntRecv   bool
 		agentSendsNoPort  bool
@@ -91,7 +95,9 @@ func TestConnect(t *testing.T) {
 
 		wantErr bool
 	}{
-		"Success": {},
+		"Success":                              {},
+		"Success with a dial timeout":          {dialTimeout: 5 * time.Second},
+		"Success overriding the agent address": {overrideAddress: true, portFile: dataFileNotExist},
 
 		// Port file errors
 		"No connection because port file does not exist":             {portFile: dataFileNotExist, wantErr: true},
@@ -116,7 +122,7 @@ func TestConnect(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(testutils.RootContext())
 			defer cancel()
 
 			system, mock := testutils.MockSystem(t)
@@ -137,6 +143,9 @@ func TestConnect(t *testing.T) {
 			portFile := mock.DefaultAddrFile()
 			_, agentMetaData := testutils.MockWindowsAgent(t, ctx, portFile, agentArgs...)
 
+			agentAddr, err := os.ReadFile(portFile)
+			require.NoError(t, err, "Setup: could not read the agent address")
+
 			switch tc.portFile {
 			case dataFileGood:
 			case dataFileNotExist:
@@ -168,7 +177,15 @@ func TestConnect(t *testing.T) {
 				require.Fail(t, "Test setup error", "Unexpected enum value %d for portFile state", tc.portFile)
 			}
 
-			cs, err := controlstream.New(ctx, system)
+			var csArgs []controlstream.Option
+			if tc.overrideAddress {
+				csArgs = append(csArgs, controlstream.WithAgentAddress(string(agentAddr)))
+			}
+			if tc.dialTimeout != 0 {
+				csArgs = append(csArgs, controlstream.WithDialTimeout(tc.dialTimeout))
+			}
+
+			cs, err := controlstream.New(ctx, system, csArgs...)
 			require.NoError(t, err, "New should return no error")
 
 			if tc.breakWSlDistroName {
@@ -214,6 +231,71 @@ func TestConnect(t *testing.T) {
 	}
 }
 
+//nolint:tparallel // The Hyper-V socket dialer is global, so this test cannot run in parallel.
+func TestConnectViaHvsocket(t *testing.T) {
+	testCases := map[string]struct {
+		hvsocketPortFile string
+		breakHvsocket    bool
+		overrideAddress  bool
+
+		wantHvsocketPort uint32
+	}{
+		"Success via the Hyper-V socket":                            {hvsocketPortFile: "1234", wantHvsocketPort: 1234},
+		"Success via TCP when there is no Hyper-V socket port file": {},
+
+		"Success falling back to TCP when the Hyper-V socket is unreachable":    {hvsocketPortFile: "1234", breakHvsocket: true, wantHvsocketPort: 1234},
+		"Success falling back to TCP when the Hyper-V socket port is invalid":   {hvsocketPortFile: "not a port"},
+		"Success ignoring the Hyper-V socket when overriding the agent address": {hvsocketPortFile: "1234", overrideAddress: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(testutils.RootContext())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+
+			portFile := mock.DefaultAddrFile()
+			_, agentMetaData := testutils.MockWindowsAgent(t, ctx, portFile)
+
+			agentAddr, err := os.ReadFile(portFile)
+			require.NoError(t, err, "Setup: could not read the agent address")
+
+			if tc.hvsocketPortFile != "" {
+				err := os.WriteFile(filepath.Join(filepath.Dir(portFile), common.HvsocketPortFileName), []byte(tc.hvsocketPortFile), 0600)
+				require.NoError(t, err, "Setup: could not write the Hyper-V socket port file")
+			}
+
+			// The mock agent only listens on TCP, so the Hyper-V socket is emulated by dialing it.
+			var dialedPort uint32
+			controlstream.SetHvsocketDialer(t, func(ctx context.Context, port uint32) (net.Conn, error) {
+				dialedPort = port
+				if tc.breakHvsocket {
+					return nil, errors.New("mock error")
+				}
+				var d net.Dialer
+				return d.DialContext(ctx, "tcp", string(agentAddr))
+			})
+
+			var csArgs []controlstream.Option
+			if tc.overrideAddress {
+				csArgs = append(csArgs, controlstream.WithAgentAddress(string(agentAddr)))
+			}
+
+			cs, err := controlstream.New(ctx, system, csArgs...)
+			require.NoError(t, err, "New should return no error")
+
+			err = cs.Connect(ctx)
+			require.NoError(t, err, "Connect should have returned no error")
+			defer cs.Disconnect()
+
+			require.Equal(t, tc.wantHvsocketPort, dialedPort, "Mismatch in the vsock port dialed")
+			require.Equal(t, int32(1), agentMetaData.ConnectionCount.Load(), "The agent should have received one connection")
+			require.Equal(t, agentMetaData.ReservedPort.Load(), uint32(cs.ReservedPort()), "The Windows agent and the Daemon should agree on the reserved port")
+		})
+	}
+}
+
 func TestSend(t *testing.T) {
 	t.Parallel()
 
@@ -235,7 +317,7 @@ func TestSend(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(testutils.RootContext())
 			defer cancel()
 
 			system, mock := testutils.MockSystem(t)
@@ -298,7 +380,7 @@ func TestReconnection(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(testutils.RootContext())
 			defer cancel()
 
 			system, mock := testutils.MockSystem(t)
@@ -337,6 +419,88 @@ func TestReconnection(t *testing.T) {
 	}
 }
 
+func TestAddressChanged(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		noPortFile   bool
+		agentAddress bool
+
+		rewrite     bool
+		sameAddress bool
+		remove      bool
+
+		wantChanged bool
+	}{
+		"Success noticing a new address":                    {rewrite: true, wantChanged: true},
+		"Success noticing a new run on the same address":    {rewrite: true, sameAddress: true, wantChanged: true},
+		"Success noticing a port file that was missing":     {noPortFile: true, rewrite: true, wantChanged: true},
+		"Success ignoring a port file that was not changed": {},
+		"Success ignoring a port file that was removed":     {remove: true},
+		"Success ignoring the port file with an address":    {agentAddress: true, rewrite: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(testutils.RootContext())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+			portFile := mock.DefaultAddrFile()
+
+			// Nobody listens on this port, as if the agent had crashed.
+			const deadAddress = "localhost:1"
+			if !tc.noPortFile {
+				require.NoError(t, os.WriteFile(portFile, []byte(deadAddress), 0600), "Setup: could not write the port file")
+			}
+
+			opts := []controlstream.Option{controlstream.WithDialTimeout(100 * time.Millisecond)}
+			if tc.agentAddress {
+				opts = append(opts, controlstream.WithAgentAddress(deadAddress))
+			}
+
+			cs, err := controlstream.New(ctx, system, opts...)
+			require.NoError(t, err, "Setup: New should return no error")
+
+			err = cs.Connect(ctx)
+			require.Error(t, err, "Setup: Connect should fail when the agent is not running")
+
+			changed := cs.AddressChanged(ctx)
+
+			if tc.rewrite {
+				addr := "localhost:2"
+				if tc.sameAddress {
+					addr = deadAddress
+				}
+				require.NoError(t, os.WriteFile(portFile, []byte(addr), 0600), "Setup: could not rewrite the port file")
+				later := time.Now().Add(time.Minute)
+				require.NoError(t, os.Chtimes(portFile, later, later), "Setup: could not change the modification time of the port file")
+			}
+
+			if tc.remove {
+				require.NoError(t, os.Remove(portFile), "Setup: could not remove the port file")
+			}
+
+			if !tc.wantChanged {
+				select {
+				case <-changed:
+					require.Fail(t, "AddressChanged should not notify a change")
+				case <-time.After(3 * time.Second):
+				}
+				return
+			}
+
+			select {
+			case <-changed:
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "AddressChanged should have notified the change")
+			}
+		})
+	}
+}
+
 func TestWithProMock(t *testing.T)     { testutils.ProMock(t) }
 func TestWithWslPathMock(t *testing.T) { testutils.WslPathMock(t) }
 func TestWithWslInfoMock(t *testing.T) { testutils.WslInfoMock(t) }
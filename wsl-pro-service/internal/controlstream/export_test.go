@@ -0,0 +1,17 @@
+package controlstream
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+// SetHvsocketDialer overrides how the control stream connects to the vsock port of the Windows host.
+// Tests using it cannot run in parallel.
+func SetHvsocketDialer(t *testing.T, dial func(ctx context.Context, port uint32) (net.Conn, error)) {
+	t.Helper()
+
+	orig := dialHvsocket
+	dialHvsocket = dial
+	t.Cleanup(func() { dialHvsocket = orig })
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/grpc/interceptorschain"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
+	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/consts"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -21,13 +24,29 @@ type session struct {
 }
 
 // newSession starts a connection to the control stream. Call close to release resources.
-func newSession(ctx context.Context, address, clientID string) (s session, err error) {
+// A non-zero dialTimeout makes it wait for the connection to be established, and fail if it
+// takes longer than that. Extra dial options, such as a custom dialer, are appended to the default ones.
+func newSession(ctx context.Context, address, clientID string, dialTimeout time.Duration, extraOpts ...grpc.DialOption) (s session, err error) {
 	log.Infof(ctx, "Connecting to control stream at %q", address)
 
-	s.conn, err = grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()),
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		telemetry.DialOption(),
 		grpc.WithStreamInterceptor(interceptorschain.StreamClient(
-			log.StreamClientInterceptor(logrus.StandardLogger(), log.WithClientID(clientID)),
-		)))
+			log.StreamClientInterceptor(logrus.StandardLogger(), log.WithClientID(clientID), log.WithLogBuffer(consts.LogBufferSize, log.Coalesce)),
+		)),
+	}
+	dialOpts = append(dialOpts, extraOpts...)
+
+	dialCtx := ctx
+	if dialTimeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, dialTimeout)
+		defer cancel()
+		dialOpts = append(dialOpts, grpc.WithBlock())
+	}
+
+	s.conn, err = grpc.DialContext(dialCtx, address, dialOpts...)
 
 	if err != nil {
 		return session{}, fmt.Errorf("could not dial: %v", err)
@@ -0,0 +1,107 @@
+package controlstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/ubuntu/decorate"
+	"golang.org/x/sys/unix"
+)
+
+// vsockPollInterval is how often a pending vsock connection is checked while waiting for it to complete.
+const vsockPollInterval = 50 * time.Millisecond
+
+// vsockAddr is the address of a virtual socket.
+type vsockAddr struct {
+	cid  uint32
+	port uint32
+}
+
+// Network implements net.Addr.
+func (a vsockAddr) Network() string {
+	return "vsock"
+}
+
+// String implements net.Addr.
+func (a vsockAddr) String() string {
+	return fmt.Sprintf("vm(%d):%d", a.cid, a.port)
+}
+
+// vsockConn is a connection over a virtual socket. The file provides reads, writes and deadlines
+// via the runtime poller.
+type vsockConn struct {
+	*os.File
+	local, remote vsockAddr
+}
+
+func (c vsockConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c vsockConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// dialVsock connects to this vsock port of the Windows host. Windows sees it as a connection to the
+// Hyper-V socket whose service ID is derived from the port.
+func dialVsock(ctx context.Context, port uint32) (conn net.Conn, err error) {
+	defer decorate.OnError(&err, "could not connect to vsock port %d of the host", port)
+
+	fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not create socket: %v", err)
+	}
+
+	remote := vsockAddr{cid: unix.VMADDR_CID_HOST, port: port}
+
+	err = unix.Connect(fd, &unix.SockaddrVM{CID: remote.cid, Port: remote.port})
+	if errors.Is(err, unix.EINPROGRESS) {
+		err = waitConnected(ctx, fd)
+	}
+	if err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+
+	var local vsockAddr
+	if sa, err := unix.Getsockname(fd); err == nil {
+		if vm, ok := sa.(*unix.SockaddrVM); ok {
+			local = vsockAddr{cid: vm.CID, port: vm.Port}
+		}
+	}
+
+	// The socket is non-blocking, so the file registers it with the runtime poller.
+	return vsockConn{File: os.NewFile(uintptr(fd), remote.String()), local: local, remote: remote}, nil
+}
+
+// waitConnected waits for the pending connection of the non-blocking socket to complete.
+func waitConnected(ctx context.Context, fd int) error {
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLOUT}}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := unix.Poll(fds, int(vsockPollInterval.Milliseconds()))
+		if errors.Is(err, unix.EINTR) || n == 0 {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not wait for the connection: %v", err)
+		}
+
+		errno, err := unix.GetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_ERROR)
+		if err != nil {
+			return fmt.Errorf("could not get the connection status: %v", err)
+		}
+		if errno != 0 {
+			return unix.Errno(errno)
+		}
+		return nil
+	}
+}
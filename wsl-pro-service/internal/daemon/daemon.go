@@ -45,8 +45,12 @@ const (
 	serviceStatusStopped  = "Stopped"
 )
 
+// errAgentAddressChanged is returned by serveOnce when the Windows Agent rewrote its port file while connected.
+var errAgentAddressChanged = errors.New("the address of the Windows Agent changed")
+
 type options struct {
 	systemdSdNotifier systemdSdNotifier
+	ctrlStreamOpts    []controlstream.Option
 }
 
 type systemdSdNotifier func(unsetEnvironment bool, state string) (bool, error)
@@ -54,6 +58,22 @@ type systemdSdNotifier func(unsetEnvironment bool, state string) (bool, error)
 // Option is the function signature used to tweak the daemon creation.
 type Option func(*options)
 
+// WithAgentAddress makes the daemon connect to the Windows Agent at this address instead of
+// the one found in the port file.
+func WithAgentAddress(addr string) Option {
+	return func(o *options) {
+		o.ctrlStreamOpts = append(o.ctrlStreamOpts, controlstream.WithAgentAddress(addr))
+	}
+}
+
+// WithConnectionTimeout makes every attempt to connect to the Windows Agent fail if it takes
+// longer than the timeout. A zero timeout means no timeout.
+func WithConnectionTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.ctrlStreamOpts = append(o.ctrlStreamOpts, controlstream.WithDialTimeout(timeout))
+	}
+}
+
 // GRPCServiceRegisterer is a function that the daemon will call everytime we want to build a new GRPC object.
 type GRPCServiceRegisterer func(context.Context, wslinstanceservice.ControlStreamClient) *grpc.Server
 
@@ -72,7 +92,7 @@ func New(ctx context.Context, registerGRPCService GRPCServiceRegisterer, s syste
 		f(&opts)
 	}
 
-	ctrlStream, err := controlstream.New(ctx, s)
+	ctrlStream, err := controlstream.New(ctx, s, opts.ctrlStreamOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +154,13 @@ func (d *Daemon) Serve() (err error) {
 		if err == nil {
 			return nil
 		}
+		if errors.Is(err, errAgentAddressChanged) {
+			// The agent rewrites its port file to prompt the distros to reconnect, e.g. after a network
+			// change: the old connection may be stale already, so there is no point in waiting.
+			log.Info(d.ctx, "The address of the Windows Agent changed: reconnecting")
+			delay = minDelay
+			continue
+		}
 		var target controlstream.SystemError
 		if errors.As(err, &target) {
 			// Irrecoverable errors: broken /etc/resolv.conf, broken pro status, etc
@@ -147,15 +174,25 @@ func (d *Daemon) Serve() (err error) {
 			return err
 		}
 
+		// The agent writes a new port file when it restarts, e.g. after a crash: there is no point
+		// in waiting out the delay then.
+		watchCtx, stopWatching := context.WithCancel(d.ctx)
 		select {
 		case <-d.ctx.Done():
+			stopWatching()
 			return d.ctx.Err()
 		case <-time.After(delay):
+		case <-d.ctrlStream.AddressChanged(watchCtx):
+			log.Info(d.ctx, "The address of the Windows Agent changed")
+			delay = minDelay
 		case <-forceStopCtx.Done():
+			stopWatching()
 			return nil
 		case <-gracefulStopCtx.Done():
+			stopWatching()
 			return nil
 		}
+		stopWatching()
 
 		log.Infof(d.ctx, "Retrying connection to control stream")
 		if err := d.systemdNotifyStatus(d.ctx, serviceStatusRetrying); err != nil {
@@ -196,6 +233,8 @@ func (d *Daemon) serveOnce(gracefulStopCtx, forceStopCtx context.Context) error
 		return nil
 	case <-d.ctrlStream.Done(ctx):
 		return errors.New("lost connection to Windows Agent")
+	case <-d.ctrlStream.AddressChanged(ctx):
+		return errAgentAddressChanged
 	}
 }
 
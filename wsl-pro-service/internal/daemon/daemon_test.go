@@ -40,7 +40,7 @@ func TestNew(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(testutils.RootContext())
 			defer cancel()
 
 			sys, mock := testutils.MockSystem(t)
@@ -102,7 +102,7 @@ func TestServe(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(testutils.RootContext())
 			defer cancel()
 
 			system, mock := testutils.MockSystem(t)
@@ -196,7 +196,7 @@ func TestServeAndQuit(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(testutils.RootContext())
 			defer cancel()
 
 			system, mock := testutils.MockSystem(t)
@@ -280,7 +280,7 @@ func TestReconnection(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(testutils.RootContext())
 			defer cancel()
 
 			system, mock := testutils.MockSystem(t)
@@ -347,6 +347,47 @@ func TestReconnection(t *testing.T) {
 	}
 }
 
+func TestReconnectionOnAddressChange(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(testutils.RootContext())
+	defer cancel()
+
+	system, mock := testutils.MockSystem(t)
+	portFile := mock.DefaultAddrFile()
+
+	registerer := func(ctx context.Context, ctrl wslinstanceservice.ControlStreamClient) *grpc.Server {
+		// No need for a real GRPC service
+		return grpc.NewServer()
+	}
+
+	systemd := SystemdSdNotifierMock{returns: true}
+
+	d, err := daemon.New(ctx, registerer, system, daemon.WithSystemdNotifier(systemd.notify))
+	require.NoError(t, err, "New should return no error")
+	defer d.Quit(ctx, true)
+
+	server, agentData := testutils.MockWindowsAgent(t, ctx, portFile)
+	defer server.Stop()
+
+	//nolint:errcheck // We don't really care
+	go d.Serve()
+
+	require.Eventually(t, func() bool {
+		return agentData.BackConnectionCount.Load() != 0
+	}, time.Minute, time.Second, "Service should eventually connect to the agent")
+	require.Equal(t, int32(1), agentData.ConnectionCount.Load(), "Service should have connected to the control stream")
+
+	// The agent rewrites its port file with the same address when the network changes.
+	future := time.Now().Add(time.Hour)
+	err = os.Chtimes(portFile, future, future)
+	require.NoError(t, err, "Setup: could not touch the port file")
+
+	require.Eventually(t, func() bool {
+		return agentData.ConnectionCount.Load() == 2
+	}, 20*time.Second, 100*time.Millisecond, "Service should reconnect to the control stream once the port file is rewritten")
+}
+
 type SystemdSdNotifierMock struct {
 	returns   bool
 	returnErr bool
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"syscall"
 )
 
 type realBackend struct{}
@@ -42,6 +43,16 @@ func (b realBackend) WslinfoExecutable(ctx context.Context, args ...string) *exe
 	return exec.CommandContext(ctx, "wslinfo", args...)
 }
 
+// JournalctlExecutable returns the full command to run the journalctl executable with the provided arguments.
+func (b realBackend) JournalctlExecutable(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, "journalctl", args...)
+}
+
+// UseraddExecutable returns the full command to run the useradd executable with the provided arguments.
+func (b realBackend) UseraddExecutable(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, "useradd", args...)
+}
+
 func (b realBackend) CmdExe(ctx context.Context, path string, args ...string) *exec.Cmd {
 	cmd := exec.CommandContext(ctx, path, args...)
 
@@ -50,3 +61,15 @@ func (b realBackend) CmdExe(ctx context.Context, path string, args ...string) *e
 
 	return cmd
 }
+
+// DropPrivileges makes the command run as the user. Only root can switch users: otherwise the
+// command already runs unprivileged, as the service does.
+func (b realBackend) DropPrivileges(cmd *exec.Cmd, u UserAccount) {
+	if os.Geteuid() == 0 {
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Credential: &syscall.Credential{Uid: u.UID, Gid: u.GID},
+		}
+	}
+
+	cmd.Env = append(cmd.Environ(), "HOME="+u.Home, "USER="+u.Name, "LOGNAME="+u.Name)
+}
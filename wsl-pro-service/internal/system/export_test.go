@@ -1,9 +1,29 @@
 package system
 
-const LandscapeConfigPath = landscapeConfigPath
+import "time"
+
+const (
+	LandscapeConfigPath = landscapeConfigPath
+	InstanceIDPath      = instanceIDPath
+	WSLConfPath         = wslConfPath
+	ProTokenHashPath    = proTokenHashPath
+	PolicyPath          = policyPath
+	TransfersDir        = transfersDir
+
+	LandscapeConfigHashPath = landscapeConfigHashPath
+
+	RebootRequiredPath     = rebootRequiredPath
+	RebootRequiredPkgsPath = rebootRequiredPkgsPath
+	UpdatesAvailablePath   = updatesAvailablePath
+)
 
 func (s *System) CmdExeCache() *string {
 	return &s.cmdExe
 }
 
 type RealBackend = realBackend
+
+func init() {
+	// Measuring the CPU usage of the mock processes for a whole second would only slow the tests down.
+	cpuSamplingPeriod = 10 * time.Millisecond
+}
@@ -0,0 +1,305 @@
+package system
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// transfersDir is where the files being received are kept until they are complete, so that
+	// interrupted transfers can be resumed.
+	transfersDir = "/var/lib/wsl-pro-service/transfers"
+
+	// defaultFileMode is the mode of the files received without one.
+	defaultFileMode os.FileMode = 0644
+)
+
+// ErrOffsetPastEnd is returned when a file is opened at an offset past its end, such as when
+// resuming the transfer of a file that shrank since.
+var ErrOffsetPastEnd = errors.New("offset is past the end of the file")
+
+// FileHeader describes a file transferred between the agent and the distro.
+type FileHeader struct {
+	// Path is the absolute path of the file in the distro.
+	Path string
+
+	// Size is the size of the whole file, in bytes.
+	Size uint64
+
+	// SHA256 is the hex-encoded checksum of the whole file. It identifies the transfer.
+	SHA256 string
+
+	// Mode is the permission bits of the file. Zero means defaultFileMode.
+	Mode os.FileMode
+}
+
+// validate returns an error if the header cannot describe a file the distro can receive.
+func (h FileHeader) validate() error {
+	if !filepath.IsAbs(h.Path) || filepath.Clean(h.Path) != h.Path {
+		return fmt.Errorf("path %q must be absolute and clean", h.Path)
+	}
+
+	// The checksum names the partial file, so it must not be able to point elsewhere.
+	if b, err := hex.DecodeString(h.SHA256); err != nil || len(b) != sha256.Size || hex.EncodeToString(b) != h.SHA256 {
+		return fmt.Errorf("checksum %q is not a lowercase hex-encoded SHA-256 checksum", h.SHA256)
+	}
+
+	if h.Mode&^os.ModePerm != 0 {
+		return fmt.Errorf("mode %o has more than permission bits", h.Mode)
+	}
+
+	return nil
+}
+
+// FileTransferReceived returns how many bytes of the file the distro has received so far, so that the
+// transfer can be resumed from there.
+func (s *System) FileTransferReceived(h FileHeader) (received uint64, err error) {
+	defer decorate.OnError(&err, "could not get the status of the transfer of %s", h.Path)
+
+	if err := h.validate(); err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(s.partialFilePath(h))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	// A partial file longer than the whole file cannot be resumed.
+	if uint64(info.Size()) > h.Size {
+		return 0, nil
+	}
+
+	return uint64(info.Size()), nil
+}
+
+// partialFilePath is where the file is kept until it is complete.
+func (s *System) partialFilePath(h FileHeader) string {
+	return s.backend.Path(transfersDir, h.SHA256+".part")
+}
+
+// FileTransfer is a file being received from the agent. The chunks are written in order with Write,
+// and Close writes the file to its path once all of them are received.
+type FileTransfer struct {
+	header   FileHeader
+	partial  string
+	final    string
+	f        *os.File
+	received uint64
+}
+
+// ResumeFileTransfer starts receiving the file, or resumes receiving it from the offset. The offset
+// must not be past what FileTransferReceived reports: anything received after it is discarded.
+func (s *System) ResumeFileTransfer(ctx context.Context, h FileHeader, offset uint64) (t *FileTransfer, err error) {
+	defer decorate.OnError(&err, "could not receive %s", h.Path)
+
+	// The file can be written anywhere in the distro.
+	if err := checkPrivilege(ctx, PrivilegeRoot); err != nil {
+		return nil, err
+	}
+
+	if err := h.validate(); err != nil {
+		return nil, err
+	}
+
+	if offset > h.Size {
+		return nil, fmt.Errorf("offset %d is past the size of the file (%d bytes)", offset, h.Size)
+	}
+
+	if err := os.MkdirAll(s.backend.Path(transfersDir), 0700); err != nil {
+		return nil, fmt.Errorf("could not create transfers directory: %v", err)
+	}
+
+	partial := s.partialFilePath(h)
+	f, err := os.OpenFile(partial, os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open partial file: %v", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("could not stat partial file: %v", err)
+	}
+
+	if offset > uint64(info.Size()) {
+		_ = f.Close()
+		return nil, fmt.Errorf("cannot resume at offset %d: only %d bytes were received", offset, info.Size())
+	}
+
+	if err := f.Truncate(int64(offset)); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("could not discard what was received after offset %d: %v", offset, err)
+	}
+
+	if _, err := f.Seek(int64(offset), io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("could not seek to offset %d: %v", offset, err)
+	}
+
+	return &FileTransfer{
+		header:   h,
+		partial:  partial,
+		final:    s.backend.Path(h.Path),
+		f:        f,
+		received: offset,
+	}, nil
+}
+
+// Received returns how many bytes of the file were received so far.
+func (t *FileTransfer) Received() uint64 {
+	return t.received
+}
+
+// Complete returns true once the whole file was received.
+func (t *FileTransfer) Complete() bool {
+	return t.received == t.header.Size
+}
+
+// Write appends the chunk to the file. It fails if the file would grow larger than its header says.
+func (t *FileTransfer) Write(p []byte) (int, error) {
+	if t.received+uint64(len(p)) > t.header.Size {
+		return 0, fmt.Errorf("received more than the %d bytes of %s", t.header.Size, t.header.Path)
+	}
+
+	n, err := t.f.Write(p)
+	t.received += uint64(n)
+	if err != nil {
+		return n, fmt.Errorf("could not write %s: %v", t.header.Path, err)
+	}
+
+	return n, nil
+}
+
+// Close stops receiving the file. If it is complete, its checksum is verified and it is written to its
+// path. Otherwise, what was received is kept so that the transfer can be resumed.
+func (t *FileTransfer) Close() (err error) {
+	defer decorate.OnError(&err, "could not receive %s", t.header.Path)
+
+	if err := t.f.Close(); err != nil {
+		return fmt.Errorf("could not close partial file: %v", err)
+	}
+
+	if !t.Complete() {
+		return nil
+	}
+
+	if err := t.verify(); err != nil {
+		// Resuming would only keep the corrupted data.
+		_ = os.Remove(t.partial)
+		return err
+	}
+
+	mode := t.header.Mode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+
+	if err := os.Chmod(t.partial, mode); err != nil {
+		return fmt.Errorf("could not set mode: %v", err)
+	}
+
+	//nolint:gosec // The parent directories are not secret, as with any other directory created by a package.
+	if err := os.MkdirAll(filepath.Dir(t.final), 0755); err != nil {
+		return fmt.Errorf("could not create parent directory: %v", err)
+	}
+
+	// Renaming is atomic, so the file is never seen half-written.
+	if err := os.Rename(t.partial, t.final); err != nil {
+		return fmt.Errorf("could not move file to its path: %v", err)
+	}
+
+	return nil
+}
+
+// verify returns an error if the checksum of the file received does not match the header.
+func (t *FileTransfer) verify() error {
+	f, err := os.Open(t.partial)
+	if err != nil {
+		return fmt.Errorf("could not open partial file: %v", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("could not compute checksum: %v", err)
+	}
+
+	if got := hex.EncodeToString(h.Sum(nil)); got != t.header.SHA256 {
+		return fmt.Errorf("checksum mismatch: got %s, expected %s", got, t.header.SHA256)
+	}
+
+	return nil
+}
+
+// OpenFileFrom opens a file of the distro to be sent to the agent, positioned at the offset so that an
+// interrupted transfer can be resumed. The header describes the whole file, with the checksum the agent
+// can verify it against once it is received.
+func (s *System) OpenFileFrom(ctx context.Context, path string, offset uint64) (h FileHeader, r io.ReadCloser, err error) {
+	defer decorate.OnError(&err, "could not send %s", path)
+
+	// Any file of the distro can be read.
+	if err := checkPrivilege(ctx, PrivilegeRoot); err != nil {
+		return h, nil, err
+	}
+
+	if !filepath.IsAbs(path) || filepath.Clean(path) != path {
+		return h, nil, fmt.Errorf("path %q must be absolute and clean", path)
+	}
+
+	f, err := os.Open(s.backend.Path(path))
+	if err != nil {
+		return h, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return h, nil, err
+	}
+
+	// Devices and pipes could be read forever, and directories not at all.
+	if !info.Mode().IsRegular() {
+		_ = f.Close()
+		return h, nil, fmt.Errorf("%s is not a regular file", path)
+	}
+
+	if offset > uint64(info.Size()) {
+		_ = f.Close()
+		return h, nil, fmt.Errorf("%w: offset %d, size %d", ErrOffsetPastEnd, offset, info.Size())
+	}
+
+	sum := sha256.New()
+	if _, err := io.CopyN(sum, f, info.Size()); err != nil {
+		_ = f.Close()
+		return h, nil, fmt.Errorf("could not compute checksum: %v", err)
+	}
+
+	if _, err := f.Seek(int64(offset), io.SeekStart); err != nil {
+		_ = f.Close()
+		return h, nil, fmt.Errorf("could not seek to offset %d: %v", offset, err)
+	}
+
+	h = FileHeader{
+		Path:   path,
+		Size:   uint64(info.Size()),
+		SHA256: hex.EncodeToString(sum.Sum(nil)),
+		Mode:   info.Mode().Perm(),
+	}
+
+	// A file that grows while it is sent, such as a log, is cut at the size it had when it was opened.
+	return h, struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, info.Size()-int64(offset)), f}, nil
+}
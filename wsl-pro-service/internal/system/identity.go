@@ -0,0 +1,73 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// instanceIDPath is the file where the distro's unique identity is stored.
+	// It lives inside the distro so that it survives agent reinstalls and
+	// the loss of the agent's database.
+	instanceIDPath = "/var/lib/wsl-pro-service/instance_id"
+
+	// bootIDPath is the file where the kernel exposes the identifier of the current boot.
+	bootIDPath = "/proc/sys/kernel/random/boot_id"
+)
+
+// InstanceID returns the unique identifier of this distro. If it has not been
+// generated yet, a new one is created and persisted.
+func (s *System) InstanceID() (id string, err error) {
+	defer decorate.OnError(&err, "could not obtain distro instance ID")
+
+	path := s.backend.Path(instanceIDPath)
+
+	out, err := os.ReadFile(path)
+	if err == nil {
+		if id, err := uuid.Parse(strings.TrimSpace(string(out))); err == nil {
+			return id.String(), nil
+		}
+		// Invalid contents: we generate a new one.
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("could not read %s: %v", instanceIDPath, err)
+	}
+
+	id = uuid.NewString()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return "", fmt.Errorf("could not create directory: %v", err)
+	}
+
+	tmp := path + ".new"
+	//nolint:gosec // Other users must be able to read the distro identity.
+	if err := os.WriteFile(tmp, []byte(id+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("could not write to file: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+
+	return id, nil
+}
+
+// BootID returns the identifier of the current boot of the distro, which changes every time
+// the distro is restarted.
+func (s *System) BootID() (id string, err error) {
+	defer decorate.OnError(&err, "could not obtain boot ID")
+
+	out, err := os.ReadFile(s.backend.Path(bootIDPath))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,105 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ubuntu/decorate"
+)
+
+// serviceUnit is the systemd unit the WSL Pro service runs as.
+const serviceUnit = "wsl-pro.service"
+
+// JournalEntry is an entry of the journal of the WSL Pro service.
+type JournalEntry struct {
+	Time time.Time
+	// Priority is the syslog priority, from 0 (emergency) to 7 (debug).
+	Priority uint32
+	Message  string
+}
+
+// Journal returns the most recent entries of the journal of the WSL Pro service, from oldest to newest.
+func (s System) Journal(ctx context.Context, lines int) (entries []JournalEntry, err error) {
+	defer decorate.OnError(&err, "could not read the journal")
+
+	cmd := s.backend.JournalctlExecutable(ctx, "--unit", serviceUnit, "--lines", strconv.Itoa(lines), "--output", "json", "--no-pager")
+	out, err := runCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every entry is a JSON object on its own line.
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+
+		e, err := parseJournalEntry(sc.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// parseJournalEntry parses an entry printed by journalctl in JSON format. All the fields are strings,
+// except for messages with non-printable characters, which are arrays of bytes.
+func parseJournalEntry(line []byte) (e JournalEntry, err error) {
+	var fields struct {
+		Timestamp string          `json:"__REALTIME_TIMESTAMP"`
+		Priority  string          `json:"PRIORITY"`
+		Message   json.RawMessage `json:"MESSAGE"`
+	}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return e, fmt.Errorf("could not parse entry: %v. Entry: %s", err, line)
+	}
+
+	usec, err := strconv.ParseInt(fields.Timestamp, 10, 64)
+	if err != nil {
+		return e, fmt.Errorf("could not parse timestamp %q: %v", fields.Timestamp, err)
+	}
+	e.Time = time.UnixMicro(usec)
+
+	// Entries without a priority are logged at the info level.
+	e.Priority = 6
+	if fields.Priority != "" {
+		p, err := strconv.ParseUint(fields.Priority, 10, 32)
+		if err != nil {
+			return e, fmt.Errorf("could not parse priority %q: %v", fields.Priority, err)
+		}
+		e.Priority = uint32(p)
+	}
+
+	if len(fields.Message) == 0 {
+		return e, nil
+	}
+
+	if err := json.Unmarshal(fields.Message, &e.Message); err == nil {
+		return e, nil
+	}
+
+	var raw []byte
+	var nums []int
+	if err := json.Unmarshal(fields.Message, &nums); err != nil {
+		return e, fmt.Errorf("could not parse message: %v", err)
+	}
+	for _, n := range nums {
+		raw = append(raw, byte(n))
+	}
+	e.Message = string(raw)
+
+	return e, nil
+}
@@ -3,9 +3,15 @@ package system
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
@@ -15,39 +21,108 @@ import (
 
 const (
 	landscapeConfigPath = "/etc/landscape/client.conf"
+
+	// landscapeConfigHashPath is where the hash of the last config applied is recorded, along with
+	// the hash of the config file it was written to.
+	landscapeConfigHashPath = "/var/lib/wsl-pro-service/landscape_config_hash"
+
+	// administratorScriptUser is the user that Landscape scripts run as when they need administrative
+	// rights. It stands for the default user of the distro, which is a sudoer.
+	administratorScriptUser = "administrator"
+
+	// allScriptUsers lets Landscape scripts run as any user.
+	allScriptUsers = "ALL"
 )
 
+// validUserName matches the user names that useradd accepts by default.
+var validUserName = regexp.MustCompile(`^[a-z_][a-z0-9_-]*\$?$`)
+
 // LandscapeEnable registers the current distro to Landscape with the specified config.
-func (s *System) LandscapeEnable(ctx context.Context, landscapeConfig string, hostagentUID string) (err error) {
+// The distro is registered with the given computer title, or with its name if the title is empty.
+// It returns the users that Landscape scripts may run as in this distro, which is empty if the config
+// leaves it to the Landscape client.
+func (s *System) LandscapeEnable(ctx context.Context, landscapeConfig, hostagentUID, computerTitle string) (scriptUsers string, err error) {
 	// Decorating here to avoid stuttering the URL (url package prints it as well)
 	defer decorate.OnError(&err, "could not register distro to Landscape")
 
-	if landscapeConfig, err = modifyConfig(ctx, s, landscapeConfig, hostagentUID); err != nil {
-		return err
+	// Whatever the outcome, the distro can no longer be assumed to be configured as recorded.
+	if err := s.SetLandscapeConfigHash(""); err != nil {
+		return "", err
+	}
+
+	if landscapeConfig, scriptUsers, err = modifyConfig(ctx, s, landscapeConfig, hostagentUID, computerTitle); err != nil {
+		return "", err
 	}
 
 	if err := s.writeConfig(landscapeConfig); err != nil {
-		return err
+		return "", err
 	}
 
 	cmd := s.backend.LandscapeConfigExecutable(ctx, "--config", landscapeConfigPath, "--silent")
-	if _, err := runCommand(cmd); err != nil {
-		return fmt.Errorf("could not enable Landscape: %v", err)
+	if _, err := runCommand(ctx, cmd); err != nil {
+		return "", fmt.Errorf("could not enable Landscape: %v", err)
 	}
 
-	return nil
+	return scriptUsers, nil
 }
 
 // LandscapeDisable unregisters the current distro from Landscape.
 func (s *System) LandscapeDisable(ctx context.Context) (err error) {
+	if err := s.SetLandscapeConfigHash(""); err != nil {
+		return err
+	}
+
 	cmd := s.backend.LandscapeConfigExecutable(ctx, "--disable")
-	if _, err := runCommand(cmd); err != nil {
+	if _, err := runCommand(ctx, cmd); err != nil {
 		return fmt.Errorf("could not disable Landscape:%v", err)
 	}
 
 	return nil
 }
 
+// LandscapeRegistered returns whether the current distro is registered to Landscape.
+func (s *System) LandscapeRegistered(ctx context.Context) (registered bool, err error) {
+	defer decorate.OnError(&err, "could not query Landscape registration")
+
+	// landscape-config exits with an error when the distro is not registered, so
+	// we rely on the output instead.
+	cmd := s.backend.LandscapeConfigExecutable(ctx, "--is-registered")
+	out, cmdErr := runCommand(ctx, cmd)
+
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(key) != "Registered" {
+			continue
+		}
+		return strings.EqualFold(strings.TrimSpace(value), "true"), nil
+	}
+
+	if cmdErr != nil {
+		return false, cmdErr
+	}
+	return false, fmt.Errorf("unexpected output: %s", out)
+}
+
+// LandscapeScriptUsers returns the users that Landscape scripts may run as in this distro, as written
+// in the Landscape client config. It is empty if the config leaves it to the Landscape client.
+func (s *System) LandscapeScriptUsers() (users string, err error) {
+	defer decorate.OnError(&err, "could not read Landscape script users")
+
+	out, err := os.ReadFile(s.backend.Path(landscapeConfigPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	data, err := ini.Load(out)
+	if err != nil {
+		return "", fmt.Errorf("could not parse config: %v", err)
+	}
+
+	return data.Section("client").Key("script_users").String(), nil
+}
+
 func (s *System) writeConfig(landscapeConfig string) (err error) {
 	defer decorate.OnError(&err, "could not write Landscape configuration")
 
@@ -72,41 +147,55 @@ func (s *System) writeConfig(landscapeConfig string) (err error) {
 }
 
 // modifyConfig overrides parameters in the configuration to adapt them to the current distro.
-func modifyConfig(ctx context.Context, s *System, landscapeConfig string, hostagentUID string) (string, error) {
+// It returns the modified configuration and the users that Landscape scripts may run as.
+func modifyConfig(ctx context.Context, s *System, landscapeConfig, hostagentUID, computerTitle string) (_ string, scriptUsers string, err error) {
 	if landscapeConfig == "" {
-		return "", nil
+		return "", "", nil
 	}
 
 	r := strings.NewReader(landscapeConfig)
 	data, err := ini.Load(r)
 	if err != nil {
-		return "", fmt.Errorf("could not parse config: %v", err)
+		return "", "", fmt.Errorf("could not parse config: %v", err)
 	}
 
 	data.DeleteSection("host")
 
-	distroName, err := s.WslDistroName(ctx)
-	if err != nil {
-		return "", err
+	if computerTitle == "" {
+		if computerTitle, err = s.WslDistroName(ctx); err != nil {
+			return "", "", err
+		}
 	}
-	if err := overrideKey(ctx, data, "client", "computer_title", distroName); err != nil {
-		return "", err
+	if err := overrideKey(ctx, data, "client", "computer_title", computerTitle); err != nil {
+		return "", "", err
 	}
 
 	if err := overrideKey(ctx, data, "client", "hostagent_uid", hostagentUID); err != nil {
-		return "", err
+		return "", "", err
+	}
+
+	instanceID, err := s.InstanceID()
+	if err != nil {
+		return "", "", err
+	}
+	if err := overrideKey(ctx, data, "client", "wsl_instance_id", instanceID); err != nil {
+		return "", "", err
+	}
+
+	if scriptUsers, err = overrideScriptUsers(ctx, s, data); err != nil {
+		return "", "", fmt.Errorf("could not override script users: %v", err)
 	}
 
 	if err := overrideSSLCertificate(ctx, s, data); err != nil {
-		return "", fmt.Errorf("could not override SSL certificate path: %v", err)
+		return "", "", fmt.Errorf("could not override SSL certificate path: %v", err)
 	}
 
 	w := &bytes.Buffer{}
 	if _, err := data.WriteTo(w); err != nil {
-		return "", fmt.Errorf("could not write modified config: %v", err)
+		return "", "", fmt.Errorf("could not write modified config: %v", err)
 	}
 
-	return w.String(), nil
+	return w.String(), scriptUsers, nil
 }
 
 // overrideKey sets a key to a particular value.
@@ -130,6 +219,81 @@ func overrideKey(ctx context.Context, data *ini.File, section, key, value string
 	return nil
 }
 
+// overrideScriptUsers maps the users that Landscape scripts may run as to the users of the distro:
+// the administrator is the default user, and the users that do not exist are created or left out, as
+// the policy of the distro says. It returns the users that scripts may run as.
+func overrideScriptUsers(ctx context.Context, s *System, data *ini.File) (string, error) {
+	sec, err := data.GetSection("client")
+	if err != nil || !sec.HasKey("script_users") {
+		// The Landscape client uses its default.
+		return "", nil
+	}
+
+	policy, err := s.Policy()
+	if err != nil {
+		return "", err
+	}
+
+	var users []string
+	for _, name := range strings.Split(sec.Key("script_users").String(), ",") {
+		name = strings.TrimSpace(name)
+
+		switch {
+		case name == "":
+			continue
+		case name == allScriptUsers:
+			// Any user will do: there is none to check.
+		case strings.EqualFold(name, administratorScriptUser):
+			u, err := s.DefaultUser()
+			if err != nil {
+				log.Warningf(ctx, "Landscape scripts cannot run as %s: %v", name, err)
+				continue
+			}
+			name = u.Name
+		default:
+			if err := s.ensureScriptUser(ctx, name, policy.CreateScriptUsers); err != nil {
+				log.Warningf(ctx, "Landscape scripts cannot run as %q: %v", name, err)
+				continue
+			}
+		}
+
+		if !slices.Contains(users, name) {
+			users = append(users, name)
+		}
+	}
+
+	if len(users) == 0 {
+		log.Warning(ctx, "None of the users that Landscape scripts may run as is available: the Landscape client uses its default.")
+		sec.DeleteKey("script_users")
+		return "", nil
+	}
+
+	scriptUsers := strings.Join(users, ",")
+	sec.Key("script_users").SetValue(scriptUsers)
+
+	return scriptUsers, nil
+}
+
+// ensureScriptUser returns an error if the user does not exist, unless it is allowed to create it.
+func (s *System) ensureScriptUser(ctx context.Context, name string, create bool) error {
+	if !validUserName.MatchString(name) {
+		return errors.New("invalid user name")
+	}
+
+	err := s.checkUserExists(name)
+	if err == nil || !create {
+		return err
+	}
+
+	cmd := s.backend.UseraddExecutable(ctx, "--create-home", name)
+	if _, err := runCommand(ctx, cmd); err != nil {
+		return fmt.Errorf("could not create user: %v", err)
+	}
+
+	log.Infof(ctx, "Created user %q for Landscape scripts to run as", name)
+	return nil
+}
+
 // overrideComputerTitle converts the ssl_public_key field in the Landscape config
 // from a Windows path to a Linux path.
 func overrideSSLCertificate(ctx context.Context, s *System, data *ini.File) error {
@@ -151,7 +315,7 @@ func overrideSSLCertificate(ctx context.Context, s *System, data *ini.File) erro
 	pathWindows := k.String()
 
 	cmd := s.backend.WslpathExecutable(ctx, "-ua", pathWindows)
-	out, err := runCommand(cmd)
+	out, err := runCommand(ctx, cmd)
 	if err != nil {
 		return fmt.Errorf("could not translate SSL certificate path %q to a WSL path: %v", pathWindows, err)
 	}
@@ -161,3 +325,82 @@ func overrideSSLCertificate(ctx context.Context, s *System, data *ini.File) erro
 
 	return nil
 }
+
+// SetLandscapeConfigHash records the hash of the config that was just applied, so that the agent can
+// skip sending it again. An empty hash removes the record.
+func (s *System) SetLandscapeConfigHash(hash string) (err error) {
+	defer decorate.OnError(&err, "could not record Landscape configuration hash")
+
+	path := s.backend.Path(landscapeConfigHashPath)
+
+	if hash == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	fileHash, err := s.landscapeConfigFileHash()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("could not create directory: %v", err)
+	}
+
+	tmp := path + ".new"
+	if err := os.WriteFile(tmp, []byte(hash+" "+fileHash+"\n"), 0600); err != nil {
+		return fmt.Errorf("could not write to file: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	return nil
+}
+
+// LandscapeConfigHash returns the hash of the last config applied. It is empty if none was, or if
+// the config file was modified since.
+func (s *System) LandscapeConfigHash() (hash string, err error) {
+	defer decorate.OnError(&err, "could not read Landscape configuration hash")
+
+	out, err := os.ReadFile(s.backend.Path(landscapeConfigHashPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	hash, recorded, found := strings.Cut(strings.TrimSpace(string(out)), " ")
+	if !found {
+		// A corrupted record is as good as none: the config is sent again.
+		return "", nil
+	}
+
+	fileHash, err := s.landscapeConfigFileHash()
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if fileHash != recorded {
+		return "", nil
+	}
+
+	return hash, nil
+}
+
+// landscapeConfigFileHash returns the hash of the contents of the Landscape client config file.
+func (s *System) landscapeConfigFileHash() (string, error) {
+	out, err := os.ReadFile(s.backend.Path(landscapeConfigPath))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(out)
+	return hex.EncodeToString(sum[:]), nil
+}
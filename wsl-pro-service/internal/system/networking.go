@@ -42,7 +42,7 @@ func (s *System) WindowsHostAddress(ctx context.Context) (ip net.IP, err error)
 func (s *System) networkingMode(ctx context.Context) (string, error) {
 	cmd := s.backend.WslinfoExecutable(ctx, "--networking-mode", "-n")
 
-	out, err := runCommand(cmd)
+	out, err := runCommand(ctx, cmd)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,99 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ubuntu/decorate"
+	"gopkg.in/ini.v1"
+)
+
+// policyPath is where the administrator of the distro restricts what the agent may do in it, e.g.:
+//
+//	[calls]
+//	allow = ApplyProToken, ProRefresh
+//	deny = ApplyLandscapeConfig
+//	reason = Landscape is managed by the IT department
+//
+//	[landscape]
+//	create_script_users = true
+//
+// Calls are named as in the WSL Pro service API. Without the file, all calls are allowed and no users
+// are created.
+const policyPath = "/etc/wsl-pro-service/policy.conf"
+
+// Policy restricts the calls that the agent may make to the WSL Pro service.
+type Policy struct {
+	// allow is the list of calls allowed. All calls are allowed if it is nil.
+	allow map[string]bool
+
+	// deny is the list of calls denied. It takes precedence over allow.
+	deny map[string]bool
+
+	// Reason explains why calls are denied. It is reported to the agent along with the denial.
+	Reason string
+
+	// CreateScriptUsers is true if the users that Landscape scripts may run as are created when they do
+	// not exist. Otherwise, they are left out of the Landscape config.
+	CreateScriptUsers bool
+}
+
+// Allows returns true if the policy lets the agent make the call.
+func (p Policy) Allows(call string) bool {
+	if p.deny[call] {
+		return false
+	}
+	if p.allow == nil {
+		return true
+	}
+	return p.allow[call]
+}
+
+// Policy returns the policy set by the administrator of the distro. It is read every time, so that changes
+// take effect without restarting the service.
+func (s *System) Policy() (p Policy, err error) {
+	defer decorate.OnError(&err, "could not read the policy of the distro")
+
+	out, err := os.ReadFile(s.backend.Path(policyPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return Policy{}, nil
+	} else if err != nil {
+		return p, fmt.Errorf("could not read %s: %v", policyPath, err)
+	}
+
+	data, err := ini.Load(out)
+	if err != nil {
+		return p, fmt.Errorf("could not parse %s: %v", policyPath, err)
+	}
+
+	calls := data.Section("calls")
+
+	if calls.HasKey("allow") {
+		p.allow = policyList(calls.Key("allow").String())
+	}
+	p.deny = policyList(calls.Key("deny").String())
+	p.Reason = calls.Key("reason").String()
+
+	landscape := data.Section("landscape")
+
+	if landscape.HasKey("create_script_users") {
+		if p.CreateScriptUsers, err = landscape.Key("create_script_users").Bool(); err != nil {
+			return p, fmt.Errorf("could not parse create_script_users in %s: %v", policyPath, err)
+		}
+	}
+
+	return p, nil
+}
+
+// policyList parses a comma-separated list of calls.
+func policyList(value string) map[string]bool {
+	calls := make(map[string]bool)
+	for _, c := range strings.Split(value, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			calls[c] = true
+		}
+	}
+	return calls
+}
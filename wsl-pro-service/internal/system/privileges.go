@@ -0,0 +1,156 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/ubuntu/decorate"
+	"gopkg.in/ini.v1"
+)
+
+// Privilege is what an operation needs to be allowed to do in the distro.
+type Privilege int
+
+const (
+	// PrivilegeUser operations act on behalf of the default user of the distro, such as calling into Windows.
+	// They run as that user, even when the service runs as root.
+	PrivilegeUser Privilege = iota
+
+	// PrivilegeRoot operations change the distro as a whole, such as attaching it to Ubuntu Pro or registering it to Landscape.
+	PrivilegeRoot
+)
+
+// String implements the fmt.Stringer interface.
+func (p Privilege) String() string {
+	switch p {
+	case PrivilegeUser:
+		return "user"
+	case PrivilegeRoot:
+		return "root"
+	default:
+		return fmt.Sprintf("unknown privilege (%d)", int(p))
+	}
+}
+
+// defaultUID is the UID WSL gives to the user created when the distro is installed.
+const defaultUID = 1000
+
+// UserAccount is a user of the distro that operations can run as.
+type UserAccount struct {
+	Name string
+	UID  uint32
+	GID  uint32
+	Home string
+}
+
+type privilegeKey struct{}
+
+// WithPrivilege returns a context in which only the operations requiring at most this privilege can run.
+// A context without any privilege only grants user privileges: root must be granted explicitly.
+func WithPrivilege(ctx context.Context, p Privilege) context.Context {
+	return context.WithValue(ctx, privilegeKey{}, p)
+}
+
+// checkPrivilege returns an error if the context does not grant the privilege.
+func checkPrivilege(ctx context.Context, required Privilege) error {
+	granted, ok := ctx.Value(privilegeKey{}).(Privilege)
+	if !ok {
+		granted = PrivilegeUser
+	}
+
+	if required > granted {
+		return fmt.Errorf("operation requires %s privileges, but only %s privileges were granted", required, granted)
+	}
+
+	return nil
+}
+
+// DefaultUser returns the user that WSL logs in as: the default user in /etc/wsl.conf if there is
+// one, otherwise the user created when the distro was installed.
+func (s *System) DefaultUser() (u UserAccount, err error) {
+	defer decorate.OnError(&err, "could not find the default user of the distro")
+
+	name, err := s.wslConfDefaultUser()
+	if err != nil {
+		return u, err
+	}
+
+	f, err := os.Open(s.backend.Path(passwdPath))
+	if err != nil {
+		return u, fmt.Errorf("could not read users: %v", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		// Fields: name | password | UID | GID | GECOS | home | shell
+		fields := strings.Split(sc.Text(), ":")
+		if len(fields) < 7 {
+			continue
+		}
+
+		uid, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		gid, err := strconv.ParseUint(fields[3], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		if name != "" && fields[0] != name {
+			continue
+		}
+		if name == "" && uid != defaultUID {
+			continue
+		}
+
+		return UserAccount{Name: fields[0], UID: uint32(uid), GID: uint32(gid), Home: fields[5]}, nil
+	}
+
+	if err := sc.Err(); err != nil {
+		return u, fmt.Errorf("could not read users: %v", err)
+	}
+
+	if name != "" {
+		return u, fmt.Errorf("user %q does not exist", name)
+	}
+	return u, fmt.Errorf("there is no user with UID %d", defaultUID)
+}
+
+// wslConfDefaultUser returns the default user set in /etc/wsl.conf, if any.
+func (s *System) wslConfDefaultUser() (string, error) {
+	out, err := os.ReadFile(s.backend.Path(wslConfPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("could not read %s: %v", wslConfPath, err)
+	}
+
+	data, err := ini.Load(out)
+	if err != nil {
+		return "", fmt.Errorf("could not parse %s: %v", wslConfPath, err)
+	}
+
+	return data.Section("user").Key("default").String(), nil
+}
+
+// runCommandAsUser runs the command as the default user of the distro, and returns its trimmed stdout.
+// It behaves like runCommand otherwise.
+func (s *System) runCommandAsUser(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	u, err := s.DefaultUser()
+	if err != nil {
+		return nil, err
+	}
+
+	s.backend.DropPrivileges(cmd, u)
+
+	return run(ctx, cmd)
+}
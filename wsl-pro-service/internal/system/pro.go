@@ -2,30 +2,88 @@ package system
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/ubuntu/decorate"
 )
 
-// ProStatus returns whether this distro is pro-attached.
-func (s System) ProStatus(ctx context.Context) (attached bool, err error) {
+const (
+	// proTokenHashPath is the file where the hash of the token the distro is attached with is stored.
+	// Only the hash is stored so that the token cannot be read back from it.
+	proTokenHashPath = "/var/lib/wsl-pro-service/pro_token_hash"
+)
+
+// ProStatus returns whether this distro is pro-attached, and the Ubuntu Pro services enabled in it.
+func (s System) ProStatus(ctx context.Context) (attached bool, services []string, err error) {
 	defer decorate.OnError(&err, "pro status")
 
 	cmd := s.backend.ProExecutable(ctx, "status", "--format=json")
-	out, err := runCommand(cmd)
+	out, err := runCommand(ctx, cmd)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 
-	var attachedStatus struct {
+	var status struct {
 		Attached bool
+		Services []struct {
+			Name   string
+			Status string
+		}
+	}
+	if err = json.Unmarshal(out, &status); err != nil {
+		return false, nil, fmt.Errorf("could not parse output: %v. Output: %s", err, string(out))
+	}
+
+	for _, service := range status.Services {
+		if service.Status == "enabled" {
+			services = append(services, service.Name)
+		}
+	}
+
+	return status.Attached, services, nil
+}
+
+// SecurityStatus contains the number of security updates pending in the distro.
+type SecurityStatus struct {
+	// SecurityUpdates is the number of pending updates from the standard security pocket.
+	SecurityUpdates int
+	// ESMUpdates is the number of pending updates that are only available with Ubuntu Pro.
+	ESMUpdates int
+}
+
+// ProSecurityStatus returns the number of security updates pending in this distro.
+func (s System) ProSecurityStatus(ctx context.Context) (status SecurityStatus, err error) {
+	defer decorate.OnError(&err, "pro security-status")
+
+	cmd := s.backend.ProExecutable(ctx, "security-status", "--format=json")
+	out, err := runCommand(ctx, cmd)
+	if err != nil {
+		return status, err
+	}
+
+	var report struct {
+		Summary struct {
+			StandardSecurityUpdates int `json:"num_standard_security_updates"`
+			ESMInfraUpdates         int `json:"num_esm_infra_updates"`
+			ESMAppsUpdates          int `json:"num_esm_apps_updates"`
+		}
 	}
-	if err = json.Unmarshal(out, &attachedStatus); err != nil {
-		return false, fmt.Errorf("could not parse output: %v. Output: %s", err, string(out))
+	if err = json.Unmarshal(out, &report); err != nil {
+		return status, fmt.Errorf("could not parse output: %v. Output: %s", err, string(out))
 	}
 
-	return attachedStatus.Attached, nil
+	return SecurityStatus{
+		SecurityUpdates: report.Summary.StandardSecurityUpdates,
+		ESMUpdates:      report.Summary.ESMInfraUpdates + report.Summary.ESMAppsUpdates,
+	}, nil
 }
 
 // ProAttach attaches the current distro to Ubuntu Pro.
@@ -40,20 +98,88 @@ func (s *System) ProAttach(ctx context.Context, token string) (err error) {
 	*/
 
 	cmd := s.backend.ProExecutable(ctx, "attach", token, "--format=json")
-	if _, err := runCommand(cmd); err != nil {
+	if _, err := runCommand(ctx, cmd); err != nil {
+		return err
+	}
+
+	if err := s.writeProTokenHash(token); err != nil {
+		return fmt.Errorf("attached, but could not record the token: %v", err)
+	}
+
+	return nil
+}
+
+// ProAttachedWith returns true if the distro is pro-attached with the given token, in which case
+// attaching again would be a no-op.
+func (s *System) ProAttachedWith(ctx context.Context, token string) (attached bool, err error) {
+	defer decorate.OnError(&err, "could not check pro attachment")
+
+	out, err := os.ReadFile(s.backend.Path(proTokenHashPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if strings.TrimSpace(string(out)) != hashProToken(token) {
+		return false, nil
+	}
+
+	// The distro could have been detached without the service knowing, e.g. by the user.
+	attached, _, err = s.ProStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return attached, nil
+}
+
+// writeProTokenHash records the hash of the token the distro is attached with.
+func (s *System) writeProTokenHash(token string) error {
+	path := s.backend.Path(proTokenHashPath)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("could not create directory: %v", err)
+	}
+
+	tmp := path + ".new"
+	if err := os.WriteFile(tmp, []byte(hashProToken(token)+"\n"), 0600); err != nil {
+		return fmt.Errorf("could not write to file: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.RemoveAll(tmp)
 		return err
 	}
 
 	return nil
 }
 
+// removeProTokenHash forgets the token the distro was attached with.
+func (s *System) removeProTokenHash() error {
+	if err := os.Remove(s.backend.Path(proTokenHashPath)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func hashProToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
 // ProDetach detaches the current distro from Ubuntu Pro.
 // If the distro was already detached, nothing is done.
 func (s *System) ProDetach(ctx context.Context) (err error) {
 	defer decorate.OnError(&err, "pro detach")
 
+	// Whatever the outcome, the distro can no longer be assumed to be attached with the recorded token.
+	if err := s.removeProTokenHash(); err != nil {
+		return fmt.Errorf("could not forget the attached token: %v", err)
+	}
+
 	cmd := s.backend.ProExecutable(ctx, "detach", "--assume-yes", "--format=json")
-	out, detachErr := runCommand(cmd)
+	out, detachErr := runCommand(ctx, cmd)
 	if detachErr != nil {
 		// check that the error is not that the machine is already detached
 		var detachedError struct {
@@ -78,3 +204,15 @@ func (s *System) ProDetach(ctx context.Context) (err error) {
 	}
 	return nil
 }
+
+// ProRefresh refreshes the contract, configuration and messages of Ubuntu Pro.
+func (s *System) ProRefresh(ctx context.Context) (err error) {
+	defer decorate.OnError(&err, "pro refresh")
+
+	cmd := s.backend.ProExecutable(ctx, "refresh")
+	if _, err := runCommand(ctx, cmd); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ubuntu/decorate"
+)
+
+// cpuSamplingPeriod is how long the CPU usage is measured for.
+var cpuSamplingPeriod = time.Second
+
+// clockTicks is the number of clock ticks per second in /proc, known as USER_HZ. It is 100 on
+// every architecture Ubuntu supports.
+const clockTicks = 100
+
+// ResourceUsage is the memory and CPU used by the processes of this distro.
+type ResourceUsage struct {
+	// MemoryBytes is the resident memory of the processes.
+	MemoryBytes uint64
+	// CPUPercent is the CPU used by the processes during the sampling period, as a percentage of one CPU.
+	CPUPercent float64
+}
+
+// processStat is the resource usage of a process, as reported by /proc/[pid]/stat.
+type processStat struct {
+	cpuTicks uint64
+	rssPages uint64
+}
+
+// ResourceUsage measures the memory and CPU used by the processes of this distro. Every distro
+// has its own process namespace, so only the processes of this distro are taken into account.
+// It takes cpuSamplingPeriod to measure the CPU usage.
+func (s System) ResourceUsage(ctx context.Context) (usage ResourceUsage, err error) {
+	defer decorate.OnError(&err, "could not measure resource usage")
+
+	before, err := s.processStats()
+	if err != nil {
+		return usage, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return usage, ctx.Err()
+	case <-time.After(cpuSamplingPeriod):
+	}
+
+	after, err := s.processStats()
+	if err != nil {
+		return usage, err
+	}
+
+	var ticks uint64
+	for pid, st := range after {
+		usage.MemoryBytes += st.rssPages * uint64(os.Getpagesize())
+
+		// Processes that were not there before cannot be measured.
+		if prev, ok := before[pid]; ok && st.cpuTicks >= prev.cpuTicks {
+			ticks += st.cpuTicks - prev.cpuTicks
+		}
+	}
+
+	usage.CPUPercent = float64(ticks) / clockTicks / cpuSamplingPeriod.Seconds() * 100
+	return usage, nil
+}
+
+// processStats returns the resource usage of every process, indexed by PID.
+func (s System) processStats() (map[int]processStat, error) {
+	entries, err := os.ReadDir(s.backend.Path("/proc"))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[int]processStat)
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			// Not a process.
+			continue
+		}
+
+		out, err := os.ReadFile(s.backend.Path("/proc", e.Name(), "stat"))
+		if errors.Is(err, os.ErrNotExist) {
+			// The process exited.
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		st, err := parseProcessStat(string(out))
+		if err != nil {
+			return nil, fmt.Errorf("process %d: %v", pid, err)
+		}
+		stats[pid] = st
+	}
+
+	return stats, nil
+}
+
+// parseProcessStat parses the contents of /proc/[pid]/stat.
+func parseProcessStat(stat string) (st processStat, err error) {
+	// The command name is between parentheses, and it may contain spaces and parentheses itself.
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return st, errors.New("could not find the end of the command name")
+	}
+
+	// Fields after the command name, starting with the state (field 3 in proc(5)).
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 22 {
+		return st, fmt.Errorf("expected at least 24 fields, got %d", len(fields)+2)
+	}
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return st, fmt.Errorf("could not parse utime: %v", err)
+	}
+
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return st, fmt.Errorf("could not parse stime: %v", err)
+	}
+
+	rss, err := strconv.ParseUint(fields[21], 10, 64)
+	if err != nil {
+		return st, fmt.Errorf("could not parse rss: %v", err)
+	}
+
+	return processStat{cpuTicks: utime + stime, rssPages: rss}, nil
+}
@@ -9,9 +9,13 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
+	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/consts"
 	"github.com/ubuntu/decorate"
 	"gopkg.in/ini.v1"
 )
@@ -39,8 +43,13 @@ type Backend interface {
 	LandscapeConfigExecutable(ctx context.Context, args ...string) *exec.Cmd
 	WslpathExecutable(ctx context.Context, args ...string) *exec.Cmd
 	WslinfoExecutable(ctx context.Context, args ...string) *exec.Cmd
+	JournalctlExecutable(ctx context.Context, args ...string) *exec.Cmd
+	UseraddExecutable(ctx context.Context, args ...string) *exec.Cmd
 
 	CmdExe(ctx context.Context, path string, args ...string) *exec.Cmd
+
+	// DropPrivileges makes the command run as the user instead of as the service.
+	DropPrivileges(cmd *exec.Cmd, u UserAccount)
 }
 
 type options struct {
@@ -79,20 +88,51 @@ func (s System) Info(ctx context.Context) (*agentapi.DistroInfo, error) {
 		return nil, err
 	}
 
-	pro, err := s.ProStatus(ctx)
+	pro, services, err := s.ProStatus(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("could not obtain pro status: %v", err)
 	}
 
+	// Landscape may not be installed, so this is not fatal.
+	landscapeRegistered, err := s.LandscapeRegistered(ctx)
+	if err != nil {
+		log.Warningf(ctx, "%v", err)
+	}
+
+	// The script users are only informative, so this is not fatal either.
+	var scriptUsers string
+	if landscapeRegistered {
+		if scriptUsers, err = s.LandscapeScriptUsers(); err != nil {
+			log.Warningf(ctx, "%v", err)
+		}
+	}
+
 	hostname, err := s.backend.Hostname()
 	if err != nil {
 		return nil, fmt.Errorf("could not obtain hostname: %v", err)
 	}
 
+	instanceID, err := s.InstanceID()
+	if err != nil {
+		return nil, err
+	}
+
+	// The boot ID only helps identifying the connection, so this is not fatal.
+	bootID, err := s.BootID()
+	if err != nil {
+		log.Warningf(ctx, "%v", err)
+	}
+
 	info := &agentapi.DistroInfo{
-		WslName:     distroName,
-		ProAttached: pro,
-		Hostname:    hostname,
+		WslName:              distroName,
+		ProAttached:          pro,
+		ProServices:          services,
+		Hostname:             hostname,
+		InstanceId:           instanceID,
+		LandscapeRegistered:  landscapeRegistered,
+		LandscapeScriptUsers: scriptUsers,
+		ServiceVersion:       consts.Version,
+		BootId:               bootID,
 	}
 
 	if err := s.fillOsRelease(info); err != nil {
@@ -128,6 +168,20 @@ func (s System) fillOsRelease(info *agentapi.DistroInfo) error {
 	return nil
 }
 
+// IsWSL returns true when the system is running under WSL. Like systemd's ConditionVirtualization=wsl,
+// it relies on the kernel release mentioning Microsoft or WSL.
+func (s System) IsWSL() (bool, error) {
+	const fileName = "/proc/sys/kernel/osrelease"
+
+	out, err := os.ReadFile(s.backend.Path(fileName))
+	if err != nil {
+		return false, fmt.Errorf("could not read %s: %v", fileName, err)
+	}
+
+	release := strings.ToLower(string(out))
+	return strings.Contains(release, "microsoft") || strings.Contains(release, "wsl"), nil
+}
+
 // WslDistroName obtains the name of the current WSL distro from these sources
 // 1. From environment variable WSL_DISTRO_NAME, as long as it is not empty
 // 2. From the Windows path to the distro's root ("\\wsl.localhost\<DISTRO_NAME>\").
@@ -146,7 +200,7 @@ func (s *System) WslDistroName(ctx context.Context) (name string, err error) {
 	}
 
 	cmd := s.backend.WslpathExecutable(ctx, "-w", "/")
-	out, err := runCommand(cmd)
+	out, err := runCommand(ctx, cmd)
 	if err != nil {
 		return "", fmt.Errorf("could not get distro root path: %v. Output: %s", err, string(out))
 	}
@@ -173,8 +227,10 @@ func (s *System) UserProfileDir(ctx context.Context) (wslPath string, err error)
 		return wslPath, err
 	}
 
+	// Windows processes launched from the distro run as the Windows user, but only the distro's
+	// default user is meant to reach them: there is no reason for root to.
 	cmd := s.backend.CmdExe(ctx, cmdExe, "/C", "echo %UserProfile%")
-	winHome, err := runCommand(cmd)
+	winHome, err := s.runCommandAsUser(ctx, cmd)
 	if err != nil {
 		return wslPath, err
 	}
@@ -183,7 +239,7 @@ func (s *System) UserProfileDir(ctx context.Context) (wslPath string, err error)
 	// It must be converted to linux ( /mnt/c/Users/... )
 
 	cmd = s.backend.WslpathExecutable(ctx, "-ua", string(winHome))
-	winHomeLinux, err := runCommand(cmd)
+	winHomeLinux, err := s.runCommandAsUser(ctx, cmd)
 	if err != nil {
 		return wslPath, err
 	}
@@ -201,16 +257,30 @@ func (s *System) UserProfileDir(ctx context.Context) (wslPath string, err error)
 	return wslPath, nil
 }
 
-// runCommand is a helper that runs a command and returns stdout.
+// runCommand is a helper that runs a command as root and returns stdout.
 // The first return value is the always trimmed stdout, even in case of error.
 // In case of error, both Stdout and Stderr are included in the error message.
-func runCommand(cmd *exec.Cmd) ([]byte, error) {
+// It fails without running the command if the context does not grant root privileges.
+func runCommand(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	if err := checkPrivilege(ctx, PrivilegeRoot); err != nil {
+		return nil, fmt.Errorf("%s: %v", cmd.Path, err)
+	}
+
+	return run(ctx, cmd)
+}
+
+// run runs the command with the privileges it was set up with, and returns its trimmed stdout.
+// The arguments are not traced, as they may contain secrets such as the Pro token.
+func run(ctx context.Context, cmd *exec.Cmd) (_ []byte, err error) {
+	_, span := telemetry.Start(ctx, "command "+filepath.Base(cmd.Path))
+	defer telemetry.End(span, &err)
+
 	var stdout, stderr bytes.Buffer
 
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err = cmd.Run()
 	out := bytes.TrimSpace(stdout.Bytes())
 	if err != nil {
 		return out, fmt.Errorf("%s: error: %v.\n    Stdout: %s\n    Stderr: %s", cmd.Path, err, out, stderr.String())
@@ -2,10 +2,15 @@ package system_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	commontestutils "github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/system"
@@ -31,11 +36,22 @@ func TestInfo(t *testing.T) {
 		proStatusCommand mockBehaviour
 		osRelease        mockBehaviour
 
-		hostnameErr bool
+		hostnameErr   bool
+		instanceIDErr bool
+		noBootID      bool
+
+		landscapeRegistered bool
+		landscapeStatusErr  bool
+		scriptUsers         string
 
 		wantErr bool
 	}{
 		"Success": {},
+		"Success when the distro is in Landscape":                 {landscapeRegistered: true},
+		"Success reporting the script users":                      {landscapeRegistered: true, scriptUsers: "root,ubuntu"},
+		"Success ignoring the script users when not in Landscape": {scriptUsers: "root,ubuntu"},
+		"Success when Landscape cannot be queried":                {landscapeStatusErr: true},
+		"Success when the boot ID cannot be read":                 {noBootID: true},
 
 		"Error when WslDistroName fails": {badWslDistroName: true, wantErr: true},
 
@@ -45,13 +61,14 @@ func TestInfo(t *testing.T) {
 		"Error when /etc/os-release cannot be read":       {osRelease: mockError, wantErr: true},
 		"Error whem /etc/os-release returns bad contents": {osRelease: mockBadOutput, wantErr: true},
 
-		"Error when hostname cannot be obtained": {hostnameErr: true, wantErr: true},
+		"Error when hostname cannot be obtained":    {hostnameErr: true, wantErr: true},
+		"Error when instance ID cannot be obtained": {instanceIDErr: true, wantErr: true},
 	}
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			ctx := context.Background()
+			ctx := testutils.RootContext()
 
 			system, mock := testutils.MockSystem(t)
 			mock.SetControlArg(testutils.ProStatusAttached)
@@ -65,6 +82,16 @@ func TestInfo(t *testing.T) {
 				mock.DistroHostname = nil
 			}
 
+			if tc.instanceIDErr {
+				commontestutils.ReplaceFileWithDir(t, mock.Path("/var/lib/wsl-pro-service/instance_id"), "Setup: could not create directory to interfere with instance ID file")
+			}
+
+			wantBootID := testutils.DefaultBootID
+			if tc.noBootID {
+				require.NoError(t, os.Remove(mock.Path("/proc/sys/kernel/random/boot_id")), "Setup: could not remove boot ID file")
+				wantBootID = ""
+			}
+
 			switch tc.proStatusCommand {
 			case mockOK:
 			case mockError:
@@ -86,6 +113,24 @@ func TestInfo(t *testing.T) {
 				require.Failf(t, "Unknown enum value for osRelease", "Value: %d", tc.osRelease)
 			}
 
+			if tc.landscapeRegistered {
+				mock.SetControlArg(testutils.LandscapeRegistered)
+			}
+
+			if tc.landscapeStatusErr {
+				mock.SetControlArg(testutils.LandscapeStatusErr)
+			}
+
+			var wantScriptUsers string
+			if tc.scriptUsers != "" {
+				configPath := mock.Path(system.LandscapeConfigPath)
+				require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0750), "Setup: could not create Landscape config directory")
+				require.NoError(t, os.WriteFile(configPath, []byte("[client]\nscript_users = "+tc.scriptUsers+"\n"), 0600), "Setup: could not write Landscape config")
+				if tc.landscapeRegistered {
+					wantScriptUsers = tc.scriptUsers
+				}
+			}
+
 			info, err := system.Info(ctx)
 			if tc.wantErr {
 				require.Error(t, err, "Expected Info() to return an error")
@@ -99,6 +144,117 @@ func TestInfo(t *testing.T) {
 			assert.Equal(t, "Ubuntu 22.04.1 LTS", info.GetPrettyName(), "PrettyName does not match expected value")
 			assert.Equal(t, "TEST_DISTRO_HOSTNAME", info.GetHostname(), "Hostname does not match expected value")
 			assert.True(t, info.GetProAttached(), "ProAttached does not match expected value")
+			assert.Equal(t, []string{"esm-infra", "usg"}, info.GetProServices(), "ProServices does not match expected value")
+			assert.Equal(t, tc.landscapeRegistered, info.GetLandscapeRegistered(), "LandscapeRegistered does not match expected value")
+			assert.Equal(t, wantScriptUsers, info.GetLandscapeScriptUsers(), "LandscapeScriptUsers does not match expected value")
+			assert.Equal(t, testutils.DefaultInstanceID, info.GetInstanceId(), "InstanceId does not match expected value")
+			assert.Equal(t, wantBootID, info.GetBootId(), "BootId does not match expected value")
+			assert.NotEmpty(t, info.GetServiceVersion(), "ServiceVersion should not be empty")
+		})
+	}
+}
+
+func TestInstanceID(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fileState mockBehaviour
+		noFile    bool
+
+		wantNew bool
+		wantErr bool
+	}{
+		"Success reading an existing instance ID":        {},
+		"Success generating an instance ID when missing": {noFile: true, wantNew: true},
+		"Success regenerating an invalid instance ID":    {fileState: mockBadOutput, wantNew: true},
+
+		"Error when the instance ID cannot be read": {fileState: mockError, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+			path := mock.Path(system.InstanceIDPath)
+
+			if tc.noFile {
+				require.NoError(t, os.RemoveAll(filepath.Dir(path)), "Setup: could not remove instance ID directory")
+			}
+
+			switch tc.fileState {
+			case mockOK:
+			case mockError:
+				commontestutils.ReplaceFileWithDir(t, path, "Setup: could not create directory to interfere with instance ID file")
+			case mockBadOutput:
+				err := os.WriteFile(path, []byte("not-a-uuid"), 0600)
+				require.NoError(t, err, "Setup: could not overwrite instance ID file")
+			default:
+				require.Failf(t, "Unknown enum value for fileState", "Value: %d", tc.fileState)
+			}
+
+			id, err := s.InstanceID()
+			if tc.wantErr {
+				require.Error(t, err, "InstanceID should have returned an error")
+				return
+			}
+			require.NoError(t, err, "InstanceID should have returned no errors")
+
+			if !tc.wantNew {
+				require.Equal(t, testutils.DefaultInstanceID, id, "InstanceID should have returned the stored identity")
+				return
+			}
+
+			require.NotEqual(t, testutils.DefaultInstanceID, id, "InstanceID should have generated a new identity")
+
+			out, err := os.ReadFile(path)
+			require.NoError(t, err, "InstanceID should have written the identity file")
+			require.Equal(t, id, strings.TrimSpace(string(out)), "Stored identity does not match the returned one")
+
+			again, err := s.InstanceID()
+			require.NoError(t, err, "InstanceID should have returned no errors on the second call")
+			require.Equal(t, id, again, "InstanceID should be stable across calls")
+		})
+	}
+}
+
+func TestIsWSL(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		osRelease string
+		breakFile bool
+
+		want    bool
+		wantErr bool
+	}{
+		"Success under WSL 2":                         {osRelease: "5.15.133.1-microsoft-standard-WSL2", want: true},
+		"Success under WSL 1":                         {osRelease: "4.4.0-22621-Microsoft", want: true},
+		"Success outside of WSL":                      {osRelease: "6.5.0-14-generic", want: false},
+		"Error when the kernel release is unreadable": {breakFile: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+			path := mock.Path("/proc/sys/kernel/osrelease")
+
+			if tc.breakFile {
+				commontestutils.ReplaceFileWithDir(t, path, "Setup: could not create directory to interfere with osrelease file")
+			} else {
+				err := os.WriteFile(path, []byte(tc.osRelease+"\n"), 0600)
+				require.NoError(t, err, "Setup: could not write osrelease file")
+			}
+
+			got, err := s.IsWSL()
+			if tc.wantErr {
+				require.Error(t, err, "IsWSL should have returned an error")
+				return
+			}
+			require.NoError(t, err, "IsWSL should have returned no errors")
+			require.Equal(t, tc.want, got, "IsWSL returned an unexpected value")
 		})
 	}
 }
@@ -125,7 +281,7 @@ func TestWslDistroName(t *testing.T) {
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			ctx := context.Background()
+			ctx := testutils.RootContext()
 
 			system, mock := testutils.MockSystem(t)
 
@@ -170,6 +326,7 @@ func TestUserProfileDir(t *testing.T) {
 		wslpathErr        bool
 		wslpathBadOutput  bool
 		overrideProcMount bool
+		noDefaultUser     bool
 
 		wantErr bool
 	}{
@@ -185,6 +342,7 @@ func TestUserProfileDir(t *testing.T) {
 		"Error on cmd.exe error":                                               {cmdExeErr: true, wantErr: true},
 		"Error on wslpath error":                                               {wslpathErr: true, wantErr: true},
 		"Error when wslpath returns a bad path":                                {wslpathBadOutput: true, wantErr: true},
+		"Error when there is no default user to run cmd.exe as":                {noDefaultUser: true, wantErr: true},
 	}
 
 	for name, tc := range testCases {
@@ -213,8 +371,12 @@ func TestUserProfileDir(t *testing.T) {
 			if tc.cmdExeNotExist {
 				os.RemoveAll(cmdExePath)
 			}
+			if tc.noDefaultUser {
+				err := os.WriteFile(mock.Path("/etc/passwd"), []byte("root:x:0:0:root:/root:/bin/bash\n"), 0600)
+				require.NoError(t, err, "Setup: could not write /etc/passwd")
+			}
 
-			got, err := system.UserProfileDir(context.Background())
+			got, err := system.UserProfileDir(testutils.RootContext())
 			if tc.wantErr {
 				require.Error(t, err, "Expected UserProfile to return an error")
 				return
@@ -231,6 +393,375 @@ func TestUserProfileDir(t *testing.T) {
 	}
 }
 
+func TestDefaultUser(t *testing.T) {
+	t.Parallel()
+
+	const passwd = `root:x:0:0:root:/root:/bin/bash
+daemon:x:1:1:daemon:/usr/sbin:/usr/sbin/nologin
+ubuntu:x:1000:1000:Ubuntu:/home/ubuntu:/bin/bash
+alice:x:1001:1002:Alice:/home/alice:/bin/bash
+`
+
+	testCases := map[string]struct {
+		wslConf    string
+		passwd     string
+		breakFiles bool
+
+		want    system.UserAccount
+		wantErr bool
+	}{
+		"Success with the user created on install":     {passwd: passwd, want: system.UserAccount{Name: "ubuntu", UID: 1000, GID: 1000, Home: "/home/ubuntu"}},
+		"Success with the default user in wsl.conf":    {passwd: passwd, wslConf: "[user]\ndefault=alice\n", want: system.UserAccount{Name: "alice", UID: 1001, GID: 1002, Home: "/home/alice"}},
+		"Success with wsl.conf without a default user": {passwd: passwd, wslConf: "[boot]\nsystemd=true\n", want: system.UserAccount{Name: "ubuntu", UID: 1000, GID: 1000, Home: "/home/ubuntu"}},
+
+		"Error when there is no user created on install": {passwd: "root:x:0:0:root:/root:/bin/bash\n", wantErr: true},
+		"Error when the user in wsl.conf does not exist": {passwd: passwd, wslConf: "[user]\ndefault=bob\n", wantErr: true},
+		"Error when wsl.conf cannot be parsed":           {passwd: passwd, wslConf: "[user", wantErr: true},
+		"Error when the users cannot be read":            {breakFiles: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+
+			if tc.breakFiles {
+				commontestutils.ReplaceFileWithDir(t, mock.Path("/etc/passwd"), "Setup: could not create directory to interfere with /etc/passwd")
+			} else {
+				err := os.WriteFile(mock.Path("/etc/passwd"), []byte(tc.passwd), 0600)
+				require.NoError(t, err, "Setup: could not write /etc/passwd")
+			}
+
+			if tc.wslConf != "" {
+				err := os.WriteFile(mock.Path(system.WSLConfPath), []byte(tc.wslConf), 0600)
+				require.NoError(t, err, "Setup: could not write wsl.conf")
+			}
+
+			got, err := s.DefaultUser()
+			if tc.wantErr {
+				require.Error(t, err, "DefaultUser should return an error")
+				return
+			}
+			require.NoError(t, err, "DefaultUser should return no errors")
+			require.Equal(t, tc.want, got, "DefaultUser returned an unexpected user")
+		})
+	}
+}
+
+func TestPrivileges(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		privilege   system.Privilege
+		noPrivilege bool
+
+		wantRootErr bool
+	}{
+		"Success running everything with root privileges":    {privilege: system.PrivilegeRoot},
+		"Error running root operations with user privileges": {privilege: system.PrivilegeUser, wantRootErr: true},
+		"Error running root operations without privileges":   {noPrivilege: true, wantRootErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, _ := testutils.MockSystem(t)
+			ctx := system.WithPrivilege(context.Background(), tc.privilege)
+			if tc.noPrivilege {
+				ctx = context.Background()
+			}
+
+			// Reaching Windows only requires user privileges.
+			_, err := s.UserProfileDir(ctx)
+			require.NoError(t, err, "UserProfileDir should run with %s privileges", tc.privilege)
+
+			err = s.ProRefresh(ctx)
+			if tc.wantRootErr {
+				require.Error(t, err, "ProRefresh should not run without root privileges")
+				return
+			}
+			require.NoError(t, err, "ProRefresh should run with root privileges")
+		})
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		policy      string
+		noPolicy    bool
+		breakPolicy bool
+
+		wantAllowed []string
+		wantDenied  []string
+		wantReason  string
+		wantCreate  bool
+		wantErr     bool
+	}{
+		"Success allowing everything without a policy":   {noPolicy: true, wantAllowed: []string{"ApplyProToken", "ApplyLandscapeConfig"}},
+		"Success allowing everything with an empty file": {policy: "", wantAllowed: []string{"ApplyProToken", "ApplyLandscapeConfig"}},
+		"Success with an allowlist":                      {policy: "[calls]\nallow = ApplyProToken, ProRefresh\n", wantAllowed: []string{"ApplyProToken", "ProRefresh"}, wantDenied: []string{"ApplyLandscapeConfig"}},
+		"Success with an empty allowlist":                {policy: "[calls]\nallow =\n", wantDenied: []string{"ApplyProToken", "ApplyLandscapeConfig"}},
+		"Success with a denylist":                        {policy: "[calls]\ndeny = ApplyLandscapeConfig\n", wantAllowed: []string{"ApplyProToken"}, wantDenied: []string{"ApplyLandscapeConfig"}},
+		"Success with the denylist taking precedence":    {policy: "[calls]\nallow = ApplyProToken, ApplyLandscapeConfig\ndeny = ApplyLandscapeConfig\n", wantAllowed: []string{"ApplyProToken"}, wantDenied: []string{"ApplyLandscapeConfig", "ProRefresh"}},
+		"Success with a reason":                          {policy: "[calls]\ndeny = ApplyLandscapeConfig\nreason = Managed by IT\n", wantDenied: []string{"ApplyLandscapeConfig"}, wantReason: "Managed by IT"},
+		"Success creating the script users":              {policy: "[landscape]\ncreate_script_users = true\n", wantAllowed: []string{"ApplyLandscapeConfig"}, wantCreate: true},
+
+		"Error when the policy cannot be read":                {breakPolicy: true, wantErr: true},
+		"Error when the policy cannot be parsed":              {policy: "[calls", wantErr: true},
+		"Error when the script users policy is not a boolean": {policy: "[landscape]\ncreate_script_users = sometimes\n", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+			path := mock.Path(system.PolicyPath)
+
+			if tc.breakPolicy {
+				commontestutils.ReplaceFileWithDir(t, path, "Setup: could not create directory to interfere with the policy")
+			} else if !tc.noPolicy {
+				err := os.MkdirAll(filepath.Dir(path), 0700)
+				require.NoError(t, err, "Setup: could not create the policy directory")
+				err = os.WriteFile(path, []byte(tc.policy), 0600)
+				require.NoError(t, err, "Setup: could not write the policy")
+			}
+
+			p, err := s.Policy()
+			if tc.wantErr {
+				require.Error(t, err, "Policy should return an error")
+				return
+			}
+			require.NoError(t, err, "Policy should return no errors")
+
+			for _, call := range tc.wantAllowed {
+				require.True(t, p.Allows(call), "Policy should allow %s", call)
+			}
+			for _, call := range tc.wantDenied {
+				require.False(t, p.Allows(call), "Policy should deny %s", call)
+			}
+			require.Equal(t, tc.wantReason, p.Reason, "Policy returned an unexpected reason")
+			require.Equal(t, tc.wantCreate, p.CreateScriptUsers, "Policy returned an unexpected script users policy")
+		})
+	}
+}
+
+func TestFileTransfer(t *testing.T) {
+	t.Parallel()
+
+	contents := []byte("Hello, world! This file is sent in chunks.")
+	sum := sha256.Sum256(contents)
+	checksum := hex.EncodeToString(sum[:])
+
+	testCases := map[string]struct {
+		path         string
+		checksum     string
+		mode         os.FileMode
+		received     int
+		offset       uint64
+		corrupted    bool
+		privilege    system.Privilege
+		stopAt       int
+		breakPartial bool
+
+		wantMode     os.FileMode
+		wantReceived uint64
+		wantErr      bool
+		wantCloseErr bool
+	}{
+		"Success":                                 {wantMode: 0644},
+		"Success with a mode":                     {mode: 0600, wantMode: 0600},
+		"Success resuming an interrupted one":     {received: 10, offset: 10, wantMode: 0644},
+		"Success resuming from an earlier offset": {received: 20, offset: 5, wantMode: 0644},
+		"Success keeping an incomplete transfer":  {stopAt: 15, wantReceived: 15},
+
+		"Error when the path is relative":               {path: "relative/file", wantErr: true},
+		"Error when the path is not clean":              {path: "/etc/../etc/file", wantErr: true},
+		"Error when the checksum is not a SHA-256 one":  {checksum: "../../etc/shadow", wantErr: true},
+		"Error when the mode has more than permissions": {mode: os.ModeSetuid | 0755, wantErr: true},
+		"Error when resuming past what was received":    {received: 10, offset: 11, wantErr: true},
+		"Error when running with user privileges":       {privilege: system.PrivilegeUser, wantErr: true},
+		"Error when the partial file cannot be opened":  {breakPartial: true, wantErr: true},
+		"Error when the checksum does not match":        {corrupted: true, wantCloseErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+
+			ctx := testutils.RootContext()
+			if tc.privilege != system.PrivilegeRoot {
+				ctx = system.WithPrivilege(ctx, tc.privilege)
+			}
+
+			if tc.path == "" {
+				tc.path = "/usr/local/share/ca-certificates/example.crt"
+			}
+			if tc.checksum == "" {
+				tc.checksum = checksum
+			}
+			if tc.stopAt == 0 {
+				tc.stopAt = len(contents)
+			}
+
+			h := system.FileHeader{Path: tc.path, Size: uint64(len(contents)), SHA256: tc.checksum, Mode: tc.mode}
+			partial := mock.Path(system.TransfersDir, checksum+".part")
+
+			if tc.received > 0 {
+				err := os.MkdirAll(filepath.Dir(partial), 0700)
+				require.NoError(t, err, "Setup: could not create the transfers directory")
+				err = os.WriteFile(partial, contents[:tc.received], 0600)
+				require.NoError(t, err, "Setup: could not write the partial file")
+
+				got, err := s.FileTransferReceived(h)
+				require.NoError(t, err, "FileTransferReceived should return no errors")
+				require.Equal(t, uint64(tc.received), got, "FileTransferReceived should report what was received")
+			}
+
+			if tc.breakPartial {
+				commontestutils.ReplaceFileWithDir(t, partial, "Setup: could not create directory to interfere with the partial file")
+			}
+
+			transfer, err := s.ResumeFileTransfer(ctx, h, tc.offset)
+			if tc.wantErr {
+				require.Error(t, err, "ResumeFileTransfer should return an error")
+				return
+			}
+			require.NoError(t, err, "ResumeFileTransfer should return no errors")
+
+			data := append([]byte{}, contents[tc.offset:tc.stopAt]...)
+			if tc.corrupted {
+				data[0]++
+			}
+
+			// Writing in small chunks, as the agent would.
+			for len(data) > 0 {
+				n := min(len(data), 7)
+				_, err := transfer.Write(data[:n])
+				require.NoError(t, err, "Write should return no errors")
+				data = data[n:]
+			}
+
+			if tc.stopAt == len(contents) {
+				_, err = transfer.Write([]byte("too much"))
+				require.Error(t, err, "Write should not accept more than the size of the file")
+			}
+
+			err = transfer.Close()
+			if tc.wantCloseErr {
+				require.Error(t, err, "Close should return an error")
+				require.NoFileExists(t, partial, "The corrupted partial file should have been removed")
+				require.NoFileExists(t, mock.Path(tc.path), "The corrupted file should not have been written to its path")
+				return
+			}
+			require.NoError(t, err, "Close should return no errors")
+
+			if tc.wantReceived != 0 {
+				require.False(t, transfer.Complete(), "The transfer should not be complete")
+				require.NoFileExists(t, mock.Path(tc.path), "The incomplete file should not have been written to its path")
+
+				got, err := s.FileTransferReceived(h)
+				require.NoError(t, err, "FileTransferReceived should return no errors")
+				require.Equal(t, tc.wantReceived, got, "FileTransferReceived should report what was received")
+				return
+			}
+
+			require.True(t, transfer.Complete(), "The transfer should be complete")
+			require.NoFileExists(t, partial, "The partial file should have been moved")
+
+			got, err := os.ReadFile(mock.Path(tc.path))
+			require.NoError(t, err, "The file should have been written to its path")
+			require.Equal(t, contents, got, "The file should have been received whole")
+
+			info, err := os.Stat(mock.Path(tc.path))
+			require.NoError(t, err, "Could not stat the file received")
+			require.Equal(t, tc.wantMode, info.Mode().Perm(), "The file should have the requested mode")
+		})
+	}
+}
+
+func TestOpenFileFrom(t *testing.T) {
+	t.Parallel()
+
+	contents := []byte("Hello, world! This file is sent in chunks.")
+	sum := sha256.Sum256(contents)
+
+	testCases := map[string]struct {
+		path       string
+		offset     uint64
+		privilege  system.Privilege
+		notRegular bool
+
+		wantErr bool
+	}{
+		"Success":                {},
+		"Success from an offset": {offset: 10},
+		"Success from the end":   {offset: uint64(len(contents))},
+
+		"Error when the path is relative":         {path: "var/log/example.log", wantErr: true},
+		"Error when the file does not exist":      {path: "/var/log/missing.log", wantErr: true},
+		"Error when the file is not regular":      {notRegular: true, wantErr: true},
+		"Error when the offset is past the end":   {offset: uint64(len(contents)) + 1, wantErr: true},
+		"Error when running with user privileges": {privilege: system.PrivilegeUser, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+
+			ctx := testutils.RootContext()
+			if tc.privilege != system.PrivilegeRoot {
+				ctx = system.WithPrivilege(ctx, tc.privilege)
+			}
+
+			const path = "/var/log/example.log"
+			if tc.path == "" {
+				tc.path = path
+			}
+
+			if tc.notRegular {
+				err := os.MkdirAll(mock.Path(path), 0700)
+				require.NoError(t, err, "Setup: could not create a directory in place of the file")
+			} else {
+				err := os.MkdirAll(filepath.Dir(mock.Path(path)), 0700)
+				require.NoError(t, err, "Setup: could not create the parent directory")
+				err = os.WriteFile(mock.Path(path), contents, 0640)
+				require.NoError(t, err, "Setup: could not write the file")
+			}
+
+			h, r, err := s.OpenFileFrom(ctx, tc.path, tc.offset)
+			if tc.wantErr {
+				require.Error(t, err, "OpenFileFrom should return an error")
+				return
+			}
+			require.NoError(t, err, "OpenFileFrom should return no errors")
+			defer r.Close()
+
+			want := system.FileHeader{Path: path, Size: uint64(len(contents)), SHA256: hex.EncodeToString(sum[:]), Mode: 0640}
+			require.Equal(t, want, h, "OpenFileFrom should describe the whole file")
+
+			// Growing the file must not change what is sent.
+			f, err := os.OpenFile(mock.Path(path), os.O_APPEND|os.O_WRONLY, 0)
+			require.NoError(t, err, "Setup: could not open the file to grow it")
+			_, err = f.WriteString("more lines")
+			require.NoError(t, err, "Setup: could not grow the file")
+			f.Close()
+
+			got, err := io.ReadAll(r)
+			require.NoError(t, err, "Reading the file should return no errors")
+			require.Equal(t, contents[tc.offset:], got, "The file should be read from the offset up to the size it was opened with")
+		})
+	}
+}
+
 func overrideProcMount(t *testing.T, mock *testutils.SystemMock) {
 	t.Helper()
 
@@ -260,10 +791,11 @@ func TestProStatus(t *testing.T) {
 		proMock  mockBehaviour
 		attached bool
 
-		wantErr bool
+		wantServices []string
+		wantErr      bool
 	}{
 		"success on unattached distro": {},
-		"success on attached distro":   {attached: true},
+		"success on attached distro":   {attached: true, wantServices: []string{"esm-infra", "usg"}},
 
 		"error on 'pro attach' returning bad output": {proMock: mockBadOutput, wantErr: true},
 		"error on 'pro attach' error":                {proMock: mockError, wantErr: true},
@@ -288,7 +820,7 @@ func TestProStatus(t *testing.T) {
 				mock.SetControlArg(testutils.ProStatusAttached)
 			}
 
-			got, err := system.ProStatus(context.Background())
+			got, services, err := system.ProStatus(testutils.RootContext())
 			if tc.wantErr {
 				require.Error(t, err, "Expected ProStatus to return an error")
 				return
@@ -296,6 +828,217 @@ func TestProStatus(t *testing.T) {
 			require.NoError(t, err, "Expected ProStatus to return no errors")
 
 			require.Equal(t, tc.attached, got, "Unexpected return from ProStatus")
+			require.Equal(t, tc.wantServices, services, "Unexpected services returned by ProStatus")
+		})
+	}
+}
+
+func TestProSecurityStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		proMock mockBehaviour
+
+		want    system.SecurityStatus
+		wantErr bool
+	}{
+		"Success": {want: system.SecurityStatus{SecurityUpdates: 3, ESMUpdates: 19}},
+
+		"Error when 'pro security-status' returns bad output": {proMock: mockBadOutput, wantErr: true},
+		"Error when 'pro security-status' fails":              {proMock: mockError, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+			switch tc.proMock {
+			case mockOK:
+			case mockBadOutput:
+				mock.SetControlArg(testutils.ProSecurityStatusBadJSON)
+			case mockError:
+				mock.SetControlArg(testutils.ProSecurityStatusErr)
+			default:
+				require.Fail(t, "Unknown enum value for proMock", "Value: %d", tc.proMock)
+			}
+
+			got, err := s.ProSecurityStatus(testutils.RootContext())
+			if tc.wantErr {
+				require.Error(t, err, "Expected ProSecurityStatus to return an error")
+				return
+			}
+			require.NoError(t, err, "Expected ProSecurityStatus to return no errors")
+
+			require.Equal(t, tc.want, got, "Unexpected return from ProSecurityStatus")
+		})
+	}
+}
+
+func TestResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	// The fields after the command name, up to and including rss (field 24 in proc(5)).
+	const statFields = "S 1 1 1 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 1 0 5 1000 %s"
+
+	testCases := map[string]struct {
+		processes map[string]string
+
+		wantMemoryPages uint64
+		wantErr         bool
+	}{
+		"Success with no processes": {},
+		"Success adding up the memory of every process": {processes: map[string]string{
+			"1":   "1 (init) " + fmt.Sprintf(statFields, "300"),
+			"42":  "42 (my (weird) cmd) " + fmt.Sprintf(statFields, "12"),
+			"net": "not a process",
+		}, wantMemoryPages: 312},
+
+		"Error when a process stat has too few fields":  {processes: map[string]string{"1": "1 (init) S 1 1"}, wantErr: true},
+		"Error when a process stat has no command name": {processes: map[string]string{"1": "1 init " + fmt.Sprintf(statFields, "300")}, wantErr: true},
+		"Error when a process stat has a bad rss":       {processes: map[string]string{"1": "1 (init) " + fmt.Sprintf(statFields, "lots")}, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+			for pid, stat := range tc.processes {
+				dir := mock.Path("/proc", pid)
+				require.NoError(t, os.MkdirAll(dir, 0750), "Setup: could not create mock process directory")
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0600), "Setup: could not write mock process stat")
+			}
+
+			got, err := s.ResourceUsage(testutils.RootContext())
+			if tc.wantErr {
+				require.Error(t, err, "Expected ResourceUsage to return an error")
+				return
+			}
+			require.NoError(t, err, "Expected ResourceUsage to return no errors")
+
+			require.Equal(t, tc.wantMemoryPages*uint64(os.Getpagesize()), got.MemoryBytes, "Unexpected memory usage")
+			// The mock processes do not use any CPU between samples.
+			require.Zero(t, got.CPUPercent, "Unexpected CPU usage")
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		rebootRequired      bool
+		rebootPkgs          string
+		updatesAvailable    string
+		breakRebootPkgs     bool
+		breakUpdatesSummary bool
+
+		want    system.UpdateStatus
+		wantErr bool
+	}{
+		"Success with nothing to do": {},
+		"Success when a reboot is required": {rebootRequired: true, rebootPkgs: "linux-base\nlibc6\nlinux-base\n",
+			want: system.UpdateStatus{RebootRequired: true, RebootPackages: []string{"linux-base", "libc6"}}},
+		"Success when a reboot is required by unknown packages": {rebootRequired: true, want: system.UpdateStatus{RebootRequired: true}},
+		"Success with pending updates": {updatesAvailable: "\n12 updates can be applied immediately.\n3 of these updates are standard security updates.\n",
+			want: system.UpdateStatus{PendingUpdates: 12}},
+		"Success with a single pending update":                          {updatesAvailable: "1 update can be applied immediately.\n", want: system.UpdateStatus{PendingUpdates: 1}},
+		"Success with an empty updates summary":                         {updatesAvailable: "\n"},
+		"Success ignoring the reboot packages if no reboot is required": {rebootPkgs: "libc6\n"},
+
+		"Error when the reboot packages cannot be read": {rebootRequired: true, breakRebootPkgs: true, wantErr: true},
+		"Error when the updates summary cannot be read": {breakUpdatesSummary: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+
+			write := func(path, contents string) {
+				require.NoError(t, os.MkdirAll(filepath.Dir(mock.Path(path)), 0750), "Setup: could not create parent directory")
+				require.NoError(t, os.WriteFile(mock.Path(path), []byte(contents), 0600), "Setup: could not write %s", path)
+			}
+
+			if tc.rebootRequired {
+				write(system.RebootRequiredPath, "*** System restart required ***\n")
+			}
+			if tc.rebootPkgs != "" {
+				write(system.RebootRequiredPkgsPath, tc.rebootPkgs)
+			}
+			if tc.updatesAvailable != "" {
+				write(system.UpdatesAvailablePath, tc.updatesAvailable)
+			}
+			if tc.breakRebootPkgs {
+				require.NoError(t, os.MkdirAll(mock.Path(system.RebootRequiredPkgsPath), 0750), "Setup: could not create a directory in place of the reboot packages")
+			}
+			if tc.breakUpdatesSummary {
+				require.NoError(t, os.MkdirAll(mock.Path(system.UpdatesAvailablePath), 0750), "Setup: could not create a directory in place of the updates summary")
+			}
+
+			got, err := s.UpdateStatus()
+			if tc.wantErr {
+				require.Error(t, err, "UpdateStatus should return an error")
+				return
+			}
+			require.NoError(t, err, "UpdateStatus should return no error")
+			require.Equal(t, tc.want, got, "Unexpected update status")
+		})
+	}
+}
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		journalctlErr     bool
+		journalctlBadJSON bool
+		journalctlEmpty   bool
+
+		wantEntries int
+		wantErr     bool
+	}{
+		"Success":                       {wantEntries: 3},
+		"Success with an empty journal": {journalctlEmpty: true},
+
+		"Error when journalctl returns an error":   {journalctlErr: true, wantErr: true},
+		"Error when journalctl returns bad output": {journalctlBadJSON: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+			if tc.journalctlErr {
+				mock.SetControlArg(testutils.JournalctlErr)
+			}
+			if tc.journalctlBadJSON {
+				mock.SetControlArg(testutils.JournalctlBadJSON)
+			}
+			if tc.journalctlEmpty {
+				mock.SetControlArg(testutils.JournalctlEmpty)
+			}
+
+			got, err := s.Journal(testutils.RootContext(), 100)
+			if tc.wantErr {
+				require.Error(t, err, "Expected Journal to return an error")
+				return
+			}
+			require.NoError(t, err, "Expected Journal to return no errors")
+			require.Len(t, got, tc.wantEntries, "Unexpected number of journal entries")
+
+			if tc.wantEntries == 0 {
+				return
+			}
+
+			require.Equal(t, time.UnixMicro(1700000000000000), got[0].Time, "Unexpected time of the first entry")
+			require.Equal(t, uint32(6), got[0].Priority, "Unexpected priority of the first entry")
+			require.Equal(t, "Connecting to the Windows agent", got[0].Message, "Unexpected message of the first entry")
+			require.Equal(t, "Could not\a", got[1].Message, "Messages with non-printable characters should be decoded")
+			require.Equal(t, uint32(6), got[2].Priority, "Entries without priority should be logged at the info level")
 		})
 	}
 }
@@ -316,17 +1059,79 @@ func TestProAttach(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			system, mock := testutils.MockSystem(t)
+			s, mock := testutils.MockSystem(t)
 			if tc.proErr {
 				mock.SetControlArg(testutils.ProAttachErr)
 			}
 
-			err := system.ProAttach(context.Background(), "1000")
+			err := s.ProAttach(testutils.RootContext(), "1000")
 			if tc.wantErr {
 				require.Error(t, err, "Expected ProAttach to return an error")
+				require.NoFileExists(t, mock.Path(system.ProTokenHashPath), "The token should not be recorded when attaching fails")
 				return
 			}
 			require.NoError(t, err, "Expected ProAttach to return no errors")
+
+			out, err := os.ReadFile(mock.Path(system.ProTokenHashPath))
+			require.NoError(t, err, "The token hash file should be readable")
+			require.NotContains(t, string(out), "1000", "The token should not be stored in plain text")
+		})
+	}
+}
+
+func TestProAttachedWith(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		attachedWith string
+		detachAfter  bool
+		notAttached  bool
+		proStatusErr bool
+
+		want    bool
+		wantErr bool
+	}{
+		"Success when attached with the same token": {attachedWith: "1000", want: true},
+		"Success when attached with another token":  {attachedWith: "2000"},
+		"Success when never attached":               {},
+		"Success when detached since":               {attachedWith: "1000", detachAfter: true},
+		"Success when detached by someone else":     {attachedWith: "1000", notAttached: true},
+
+		"Error when pro status fails": {attachedWith: "1000", proStatusErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutils.RootContext()
+			s, mock := testutils.MockSystem(t)
+
+			if tc.attachedWith != "" {
+				err := s.ProAttach(ctx, tc.attachedWith)
+				require.NoError(t, err, "Setup: ProAttach should return no errors")
+			}
+
+			if tc.detachAfter {
+				err := s.ProDetach(ctx)
+				require.NoError(t, err, "Setup: ProDetach should return no errors")
+			}
+
+			if !tc.notAttached {
+				mock.SetControlArg(testutils.ProStatusAttached)
+			}
+
+			if tc.proStatusErr {
+				mock.SetControlArg(testutils.ProStatusErr)
+			}
+
+			got, err := s.ProAttachedWith(ctx, "1000")
+			if tc.wantErr {
+				require.Error(t, err, "Expected ProAttachedWith to return an error")
+				return
+			}
+			require.NoError(t, err, "Expected ProAttachedWith to return no errors")
+			require.Equal(t, tc.want, got, "Unexpected attachment state")
 		})
 	}
 }
@@ -376,7 +1181,7 @@ func TestProDetach(t *testing.T) {
 				require.Fail(t, "Unknown enum value for detachResult", "Value: %d", tc.detachResult)
 			}
 
-			err := system.ProDetach(context.Background())
+			err := system.ProDetach(testutils.RootContext())
 			if tc.wantErr {
 				require.Error(t, err, "Expected ProStatus to return an error")
 				return
@@ -386,6 +1191,78 @@ func TestProDetach(t *testing.T) {
 	}
 }
 
+func TestProRefresh(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		refreshErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when pro refresh fails": {refreshErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			system, mock := testutils.MockSystem(t)
+			if tc.refreshErr {
+				mock.SetControlArg(testutils.ProRefreshErr)
+			}
+
+			err := system.ProRefresh(testutils.RootContext())
+			if tc.wantErr {
+				require.Error(t, err, "Expected ProRefresh to return an error")
+				return
+			}
+			require.NoError(t, err, "Expected ProRefresh to return no errors")
+		})
+	}
+}
+
+func TestLandscapeRegistered(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		registered bool
+		statusErr  bool
+
+		wantErr bool
+	}{
+		"Success when the distro is registered":     {registered: true},
+		"Success when the distro is not registered": {},
+
+		"Error when landscape-config fails": {statusErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+
+			if tc.registered {
+				mock.SetControlArg(testutils.LandscapeRegistered)
+			}
+
+			if tc.statusErr {
+				mock.SetControlArg(testutils.LandscapeStatusErr)
+			}
+
+			got, err := s.LandscapeRegistered(testutils.RootContext())
+			if tc.wantErr {
+				require.Error(t, err, "LandscapeRegistered should have returned an error")
+				return
+			}
+			require.NoError(t, err, "LandscapeRegistered should have succeeded")
+			require.Equal(t, tc.registered, got, "Unexpected return from LandscapeRegistered")
+		})
+	}
+}
+
 func TestLandscapeEnable(t *testing.T) {
 	t.Parallel()
 
@@ -393,12 +1270,25 @@ func TestLandscapeEnable(t *testing.T) {
 		breakWriteConfig     bool
 		breakLandscapeConfig bool
 		breakWSLPath         bool
+		breakUseradd         bool
 
-		wantErr bool
+		computerTitle     string
+		createScriptUsers bool
+
+		wantScriptUsers string
+		wantErr         bool
 	}{
 		"Success":                                    {},
 		"Success overriding computer_title":          {},
 		"Success overriding the SSL certficate path": {},
+		"Success with a custom computer_title":       {computerTitle: "My custom title"},
+
+		"Success mapping the script users":                            {wantScriptUsers: "ubuntu,root,ALL"},
+		"Success leaving out the script users that do not exist":      {wantScriptUsers: "root"},
+		"Success leaving out invalid script users":                    {createScriptUsers: true, wantScriptUsers: "root"},
+		"Success creating the script users that do not exist":         {createScriptUsers: true, wantScriptUsers: "landscape-runner,ubuntu"},
+		"Success leaving out the script users that cannot be created": {createScriptUsers: true, breakUseradd: true, wantScriptUsers: "root"},
+		"Success falling back to the default script users":            {},
 
 		"Error when the file cannot be parsed":                   {wantErr: true},
 		"Error when the config file cannot be written":           {breakWriteConfig: true, wantErr: true},
@@ -410,7 +1300,7 @@ func TestLandscapeEnable(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx := context.Background()
+			ctx := testutils.RootContext()
 			s, mock := testutils.MockSystem(t)
 
 			if tc.breakWriteConfig {
@@ -426,15 +1316,28 @@ func TestLandscapeEnable(t *testing.T) {
 				mock.SetControlArg(testutils.WslpathErr)
 			}
 
+			if tc.breakUseradd {
+				mock.SetControlArg(testutils.UseraddErr)
+			}
+
+			if tc.createScriptUsers {
+				path := mock.Path(system.PolicyPath)
+				err := os.MkdirAll(filepath.Dir(path), 0700)
+				require.NoError(t, err, "Setup: could not create the policy directory")
+				err = os.WriteFile(path, []byte("[landscape]\ncreate_script_users = true\n"), 0600)
+				require.NoError(t, err, "Setup: could not write the policy")
+			}
+
 			config, err := os.ReadFile(filepath.Join(commontestutils.TestFixturePath(t), "landscape.conf"))
 			require.NoError(t, err, "Setup: could not load fixture")
 
-			err = s.LandscapeEnable(ctx, string(config), "landscapeUID1234")
+			scriptUsers, err := s.LandscapeEnable(ctx, string(config), "landscapeUID1234", tc.computerTitle)
 			if tc.wantErr {
 				require.Error(t, err, "LandscapeEnable should have returned an error")
 				return
 			}
 			require.NoError(t, err, "LandscapeEnable should have succeeded")
+			require.Equal(t, tc.wantScriptUsers, scriptUsers, "LandscapeEnable returned unexpected script users")
 
 			exeProof := s.Path("/.landscape-enabled")
 			require.FileExists(t, exeProof, "Landscape executable never ran")
@@ -451,6 +1354,123 @@ func TestLandscapeEnable(t *testing.T) {
 	}
 }
 
+func TestLandscapeConfigHash(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		hash         string
+		noConfig     bool
+		modifyConfig bool
+		corruptHash  bool
+		breakHash    bool
+
+		want       string
+		wantSetErr bool
+		wantGetErr bool
+	}{
+		"Success returning the recorded hash":             {hash: "123abc", want: "123abc"},
+		"Success returning no hash when none is recorded": {},
+		"Success removing the recorded hash":              {hash: ""},
+
+		"Success returning no hash when the config was modified":  {hash: "123abc", modifyConfig: true},
+		"Success returning no hash when the config was removed":   {hash: "123abc", noConfig: true},
+		"Success returning no hash when the record is corrupted":  {hash: "123abc", corruptHash: true},
+		"Error recording a hash when there is no config file":     {hash: "123abc", noConfig: true, wantSetErr: true},
+		"Error returning the hash when the record cannot be read": {breakHash: true, wantGetErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+
+			configPath := mock.Path(system.LandscapeConfigPath)
+			hashPath := mock.Path(system.LandscapeConfigHashPath)
+
+			if !tc.wantSetErr || !tc.noConfig {
+				require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0750), "Setup: could not create Landscape config directory")
+				require.NoError(t, os.WriteFile(configPath, []byte("[client]\nurl=www.example.com\n"), 0600), "Setup: could not write Landscape config")
+			}
+
+			err := s.SetLandscapeConfigHash(tc.hash)
+			if tc.wantSetErr {
+				require.Error(t, err, "SetLandscapeConfigHash should return an error")
+				return
+			}
+			require.NoError(t, err, "SetLandscapeConfigHash should return no error")
+
+			if tc.modifyConfig {
+				require.NoError(t, os.WriteFile(configPath, []byte("[client]\nurl=www.example.org\n"), 0600), "Setup: could not modify Landscape config")
+			}
+			if tc.noConfig {
+				require.NoError(t, os.Remove(configPath), "Setup: could not remove Landscape config")
+			}
+			if tc.corruptHash {
+				require.NoError(t, os.WriteFile(hashPath, []byte("garbage"), 0600), "Setup: could not corrupt Landscape config hash")
+			}
+			if tc.breakHash {
+				require.NoError(t, os.MkdirAll(hashPath, 0750), "Setup: could not create a directory in place of the Landscape config hash")
+			}
+
+			got, err := s.LandscapeConfigHash()
+			if tc.wantGetErr {
+				require.Error(t, err, "LandscapeConfigHash should return an error")
+				return
+			}
+			require.NoError(t, err, "LandscapeConfigHash should return no error")
+			require.Equal(t, tc.want, got, "LandscapeConfigHash returned an unexpected hash")
+		})
+	}
+}
+
+func TestLandscapeScriptUsers(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		config      string
+		noConfig    bool
+		breakConfig bool
+
+		want    string
+		wantErr bool
+	}{
+		"Success returning the script users":                 {config: "[client]\nscript_users = root,ubuntu\n", want: "root,ubuntu"},
+		"Success returning no users when left to default":    {config: "[client]\nurl=www.example.com\n"},
+		"Success returning no users when there is no config": {noConfig: true},
+
+		"Error when the config cannot be read":   {breakConfig: true, wantErr: true},
+		"Error when the config cannot be parsed": {config: "This file has the wrong syntax", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := testutils.MockSystem(t)
+
+			configPath := mock.Path(system.LandscapeConfigPath)
+			require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0750), "Setup: could not create Landscape config directory")
+
+			switch {
+			case tc.noConfig:
+			case tc.breakConfig:
+				require.NoError(t, os.MkdirAll(configPath, 0750), "Setup: could not create a directory in place of the Landscape config")
+			default:
+				require.NoError(t, os.WriteFile(configPath, []byte(tc.config), 0600), "Setup: could not write Landscape config")
+			}
+
+			got, err := s.LandscapeScriptUsers()
+			if tc.wantErr {
+				require.Error(t, err, "LandscapeScriptUsers should return an error")
+				return
+			}
+			require.NoError(t, err, "LandscapeScriptUsers should return no error")
+			require.Equal(t, tc.want, got, "LandscapeScriptUsers returned unexpected users")
+		})
+	}
+}
+
 func TestWindowsHostAddress(t *testing.T) {
 	t.Parallel()
 
@@ -528,7 +1548,7 @@ func TestWindowsHostAddress(t *testing.T) {
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			ctx := context.Background()
+			ctx := testutils.RootContext()
 
 			sys, mock := testutils.MockSystem(t)
 
@@ -570,7 +1590,7 @@ func TestLandscapeDisable(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			ctx := context.Background()
+			ctx := testutils.RootContext()
 			s, mock := testutils.MockSystem(t)
 
 			if tc.breakLandscapeConfig {
@@ -592,7 +1612,7 @@ func TestLandscapeDisable(t *testing.T) {
 func TestRealBackend(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
+	ctx := testutils.RootContext()
 	b := system.RealBackend{}
 
 	// Asserting generated commands
@@ -616,6 +1636,10 @@ func TestRealBackend(t *testing.T) {
 	assertBasePath(t, "wslinfo", winfo.Path, "WslinfoExecutable did not return the expected command")
 	assert.Equal(t, []string{"wslinfo", "arg1", "arg2"}, winfo.Args, "WslinfoExecutable did not return the expected arguments")
 
+	jctl := b.JournalctlExecutable(ctx, "arg1", "arg2")
+	assertBasePath(t, "journalctl", jctl.Path, "JournalctlExecutable did not return the expected command")
+	assert.Equal(t, []string{"journalctl", "arg1", "arg2"}, jctl.Args, "JournalctlExecutable did not return the expected arguments")
+
 	cmd := b.CmdExe(ctx, "/mnt/c/WINDOWS/whatever/cmd.exe", "arg1", "arg2")
 	assert.Equal(t, "/mnt/c/WINDOWS/whatever", cmd.Dir, "CmdExe did not set the expected directory")
 	assert.Equal(t, "/mnt/c/WINDOWS/whatever/cmd.exe", cmd.Path, "CmdExe did not return the expected command")
@@ -634,3 +1658,81 @@ func TestWithLandscapeConfigMock(t *testing.T) { testutils.LandscapeConfigMock(t
 func TestWithWslPathMock(t *testing.T)         { testutils.WslPathMock(t) }
 func TestWithWslInfoMock(t *testing.T)         { testutils.WslInfoMock(t) }
 func TestWithCmdExeMock(t *testing.T)          { testutils.CmdExeMock(t) }
+func TestWithJournalctlMock(t *testing.T)      { testutils.JournalctlMock(t) }
+func TestWithUseraddMock(t *testing.T)         { testutils.UseraddMock(t) }
+
+func TestApplyWSLSettings(t *testing.T) {
+	t.Parallel()
+
+	enabled, disabled := true, false
+
+	testCases := map[string]struct {
+		settings      system.WSLSettings
+		existingFile  bool
+		breakWSLConf  bool
+		breakPasswd   bool
+		wantUnchanged bool
+
+		wantErr bool
+	}{
+		"Success writing a new file":          {settings: system.WSLSettings{DefaultUser: "ubuntu", Systemd: &enabled, Interop: &disabled, Automount: &enabled}},
+		"Success keeping the other settings":  {settings: system.WSLSettings{Systemd: &enabled, Interop: &disabled}, existingFile: true},
+		"Success overriding the default user": {settings: system.WSLSettings{DefaultUser: "ubuntu"}, existingFile: true},
+		"Success when nothing changes":        {settings: system.WSLSettings{Systemd: &enabled}, existingFile: true, wantUnchanged: true},
+		"Success with no settings":            {existingFile: true, wantUnchanged: true},
+
+		"Error when the default user does not exist": {settings: system.WSLSettings{DefaultUser: "nobody-here"}, wantErr: true},
+		"Error when the users cannot be read":        {settings: system.WSLSettings{DefaultUser: "ubuntu"}, breakPasswd: true, wantErr: true},
+		"Error when wsl.conf cannot be read":         {settings: system.WSLSettings{Systemd: &enabled}, breakWSLConf: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutils.RootContext()
+			s, mock := testutils.MockSystem(t)
+
+			passwd := "root:x:0:0:root:/root:/bin/bash\nubuntu:x:1000:1000:Ubuntu:/home/ubuntu:/bin/bash\n"
+			err := os.WriteFile(mock.Path("/etc/passwd"), []byte(passwd), 0600)
+			require.NoError(t, err, "Setup: could not write /etc/passwd")
+
+			if tc.breakPasswd {
+				commontestutils.ReplaceFileWithDir(t, mock.Path("/etc/passwd"), "Setup: could not create directory to interfere with /etc/passwd")
+			}
+
+			path := mock.Path(system.WSLConfPath)
+
+			var original []byte
+			if tc.existingFile {
+				original, err = os.ReadFile(filepath.Join(commontestutils.TestFixturePath(t), "wsl.conf"))
+				require.NoError(t, err, "Setup: could not load fixture")
+
+				err = os.WriteFile(path, original, 0600)
+				require.NoError(t, err, "Setup: could not write wsl.conf")
+			}
+
+			if tc.breakWSLConf {
+				commontestutils.ReplaceFileWithDir(t, path, "Setup: could not create directory to interfere with wsl.conf")
+			}
+
+			err = s.ApplyWSLSettings(ctx, tc.settings)
+			if tc.wantErr {
+				require.Error(t, err, "ApplyWSLSettings should have returned an error")
+				return
+			}
+			require.NoError(t, err, "ApplyWSLSettings should have succeeded")
+
+			out, err := os.ReadFile(path)
+			require.NoError(t, err, "wsl.conf should be readable")
+
+			if tc.wantUnchanged {
+				require.Equal(t, string(original), string(out), "wsl.conf should not have changed")
+				return
+			}
+
+			want := commontestutils.LoadWithUpdateFromGolden(t, string(out))
+			require.Equal(t, want, string(out), "wsl.conf does not match the expected settings")
+		})
+	}
+}
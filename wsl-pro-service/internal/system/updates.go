@@ -0,0 +1,85 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// rebootRequiredPath is created by the packages whose update only takes effect after a reboot.
+	rebootRequiredPath = "/var/run/reboot-required"
+
+	// rebootRequiredPkgsPath lists the packages that created rebootRequiredPath, one per line.
+	rebootRequiredPkgsPath = "/var/run/reboot-required.pkgs"
+
+	// updatesAvailablePath is the summary of the pending updates written by update-notifier.
+	updatesAvailablePath = "/var/lib/update-notifier/updates-available"
+)
+
+// pendingUpdatesRegex finds the number of pending updates in the summary written by update-notifier.
+var pendingUpdatesRegex = regexp.MustCompile(`(\d+) updates? can be applied immediately`)
+
+// UpdateStatus tells whether this distro needs to be rebooted and how many package updates are pending.
+type UpdateStatus struct {
+	// RebootRequired is true when an update only takes effect after a reboot.
+	RebootRequired bool
+	// RebootPackages are the packages that require the reboot.
+	RebootPackages []string
+	// PendingUpdates is the number of packages that can be upgraded.
+	PendingUpdates int
+}
+
+// UpdateStatus reads whether this distro needs to be rebooted and how many package updates are pending
+// from the state left behind by apt and update-notifier. A distro without update-notifier has no
+// pending updates.
+func (s System) UpdateStatus() (status UpdateStatus, err error) {
+	defer decorate.OnError(&err, "could not get update status")
+
+	if _, err := os.Stat(s.backend.Path(rebootRequiredPath)); err == nil {
+		status.RebootRequired = true
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return status, err
+	}
+
+	if status.RebootRequired {
+		out, err := os.ReadFile(s.backend.Path(rebootRequiredPkgsPath))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return status, err
+		}
+
+		// The same package is listed once per trigger.
+		for _, line := range strings.Split(string(out), "\n") {
+			pkg := strings.TrimSpace(line)
+			if pkg != "" && !slices.Contains(status.RebootPackages, pkg) {
+				status.RebootPackages = append(status.RebootPackages, pkg)
+			}
+		}
+	}
+
+	out, err := os.ReadFile(s.backend.Path(updatesAvailablePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+
+	// update-notifier leaves the summary empty when there is nothing to update.
+	m := pendingUpdatesRegex.FindSubmatch(out)
+	if m == nil {
+		return status, nil
+	}
+
+	if status.PendingUpdates, err = strconv.Atoi(string(m[1])); err != nil {
+		return status, fmt.Errorf("could not parse the number of pending updates: %v", err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,116 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/ubuntu/decorate"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	wslConfPath = "/etc/wsl.conf"
+	passwdPath  = "/etc/passwd"
+)
+
+// WSLSettings are the settings of the distro that are managed via /etc/wsl.conf.
+// Empty and nil fields are left as they are.
+type WSLSettings struct {
+	DefaultUser string
+	Systemd     *bool
+	Interop     *bool
+	Automount   *bool
+}
+
+// ApplyWSLSettings writes the settings to /etc/wsl.conf, leaving any other setting untouched.
+// WSL reads this file when the distro starts, so the changes only take effect after a restart.
+func (s *System) ApplyWSLSettings(ctx context.Context, settings WSLSettings) (err error) {
+	defer decorate.OnError(&err, "could not apply WSL settings")
+
+	if settings.DefaultUser != "" {
+		if err := s.checkUserExists(settings.DefaultUser); err != nil {
+			return err
+		}
+	}
+
+	path := s.backend.Path(wslConfPath)
+
+	original, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not read %s: %v", wslConfPath, err)
+	}
+
+	data, err := ini.Load(original)
+	if err != nil {
+		return fmt.Errorf("could not parse %s: %v", wslConfPath, err)
+	}
+
+	if settings.DefaultUser != "" {
+		data.Section("user").Key("default").SetValue(settings.DefaultUser)
+	}
+
+	setBool := func(section, key string, value *bool) {
+		if value != nil {
+			data.Section(section).Key(key).SetValue(strconv.FormatBool(*value))
+		}
+	}
+
+	setBool("boot", "systemd", settings.Systemd)
+	setBool("interop", "enabled", settings.Interop)
+	setBool("automount", "enabled", settings.Automount)
+
+	w := &bytes.Buffer{}
+	if _, err := data.WriteTo(w); err != nil {
+		return fmt.Errorf("could not write modified settings: %v", err)
+	}
+
+	if bytes.Equal(w.Bytes(), original) {
+		return nil
+	}
+
+	tmp := path + ".new"
+
+	//nolint:gosec // WSL needs to be able to read it, and it contains no secrets.
+	if err := os.WriteFile(tmp, w.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not write to file: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	log.Infof(ctx, "WSL settings changed. They will take effect the next time the distro starts.")
+
+	return nil
+}
+
+// checkUserExists returns an error if there is no user with this name in /etc/passwd.
+func (s *System) checkUserExists(name string) error {
+	f, err := os.Open(s.backend.Path(passwdPath))
+	if err != nil {
+		return fmt.Errorf("could not read users: %v", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		user, _, _ := strings.Cut(sc.Text(), ":")
+		if user == name {
+			return nil
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("could not read users: %v", err)
+	}
+
+	return fmt.Errorf("user %q does not exist", name)
+}
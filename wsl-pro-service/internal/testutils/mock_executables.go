@@ -57,6 +57,9 @@ var (
 	//go:embed filesystem_defaults/resolv.conf
 	defaultResolvConfContents []byte
 
+	//go:embed filesystem_defaults/passwd
+	defaultPasswdContents []byte
+
 	//go:embed filesystem_defaults/proc.mounts
 	defaultProcMountsContents []byte
 
@@ -64,6 +67,12 @@ var (
 	defaultProcNetRouteContents []byte
 )
 
+// DefaultInstanceID is the distro identity written in the mocked filesystem.
+const DefaultInstanceID = "29b8f2a4-8a51-4b0f-9c3a-6a4d9d8e1f70"
+
+// DefaultBootID is the boot identifier written in the mocked filesystem.
+const DefaultBootID = "5c3e1f0a-7d42-4e8b-a1c9-0f6b2d8e4a13"
+
 // controlArg Mock-controlling constants.
 type controlArg string
 
@@ -76,14 +85,21 @@ const (
 
 	ProAttachErr = "UP4W_PRO_ATTACH_ERR"
 
+	ProSecurityStatusErr     = "UP4W_PRO_SECURITY_STATUS_ERR"
+	ProSecurityStatusBadJSON = "UP4W_PRO_SECURITY_STATUS_BAD_JSON"
+
 	ProDetachBadJSON = "UP4W_PRO_DETACH_BAD_JSON"
 
 	ProDetachErrAlreadyDetached = "UP4W_PRO_DETACH_ERR_ALREADY_DETACHED"
 	ProDetachErrGeneric         = "UP4W_PRO_DETACH_ERR_GENERIC"
 	ProDetachErrNoReason        = "UP4W_PRO_DETACH_ERR_UNKNOWN"
 
+	ProRefreshErr = "UP4W_PRO_REFRESH_ERR"
+
 	LandscapeEnableErr  = "UP4W_LANDSCAPE_ENABLE_ERR"
 	LandscapeDisableErr = "UP4W_LANDSCAPE_DISABLE_ERR"
+	LandscapeRegistered = "UP4W_LANDSCAPE_REGISTERED"
+	LandscapeStatusErr  = "UP4W_LANDSCAPE_STATUS_ERR"
 
 	WslpathErr       = "UP4W_WSLPATH_ERR"
 	WslpathBadOutput = "UP4W_WSLPATH_BAD_OUTPUT"
@@ -93,6 +109,12 @@ const (
 	WslInfoErr   = "UP4W_WSLINFO_ERR"
 	WslInfoIsNAT = "UP4W_WSLINFO_IS_NAT"
 
+	JournalctlErr     = "UP4W_JOURNALCTL_ERR"
+	JournalctlBadJSON = "UP4W_JOURNALCTL_BAD_JSON"
+	JournalctlEmpty   = "UP4W_JOURNALCTL_EMPTY"
+
+	UseraddErr = "UP4W_USERADD_ERR"
+
 	// FileSystemRoot contains the path to the mocked filesystem root.
 	FileSystemRoot = "UP4W_FILE_SYSTEM_ROOT"
 )
@@ -104,6 +126,9 @@ const (
 	// We cannot rely on WSL_DISTRO_NAME because one of the mock options disables it.
 	wslpathDistroName = "UP4W_WSLPATH_DISTRONAME"
 
+	// runAsUser tells the mock executables which user they would run as. It is empty for root.
+	runAsUser = "UP4W_RUN_AS"
+
 	// mockExecutable is an environement variable used so the mock executables now they need to
 	// be executed instead of being ignored as faux tests.
 	mockExecutable = "UP4W_MOCK_EXECUTABLE"
@@ -125,6 +150,11 @@ func MockSystem(t *testing.T) (system.System, *SystemMock) {
 	return system.New(system.WithTestBackend(mock)), mock
 }
 
+// RootContext returns a context granting root privileges, as the service grants them to itself.
+func RootContext() context.Context {
+	return system.WithPrivilege(context.Background(), system.PrivilegeRoot)
+}
+
 // DefaultAddrFile is the location where a mocked system will expect the addr file to be located,
 // and its containing directory will be created in New().
 func (m *SystemMock) DefaultAddrFile() string {
@@ -227,17 +257,34 @@ func (m *SystemMock) WslinfoExecutable(ctx context.Context, args ...string) *exe
 	return m.mockExec(ctx, "TestWithWslInfoMock", args...)
 }
 
+// JournalctlExecutable mocks `journalctl $args...`.
+func (m *SystemMock) JournalctlExecutable(ctx context.Context, args ...string) *exec.Cmd {
+	return m.mockExec(ctx, "TestWithJournalctlMock", args...)
+}
+
+// UseraddExecutable mocks `useradd $args...`.
+func (m *SystemMock) UseraddExecutable(ctx context.Context, args ...string) *exec.Cmd {
+	return m.mockExec(ctx, "TestWithUseraddMock", args...)
+}
+
 // CmdExe mocks `cmd.exe $args...`.
 func (m *SystemMock) CmdExe(ctx context.Context, path string, args ...string) *exec.Cmd {
 	return m.mockExec(ctx, "TestWithCmdExeMock", args...)
 }
 
+// DropPrivileges mocks running the command as the user, by telling the mock executable who it runs as.
+func (m *SystemMock) DropPrivileges(cmd *exec.Cmd, u system.UserAccount) {
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", runAsUser, u.Name))
+}
+
 type exitCode int
 
 const (
 	exitOk       exitCode = 0  // Mock returns 0
 	exitBadUsage exitCode = 5  // Mock was misused
 	exitError    exitCode = 99 // Mock returns error as instructed
+
+	exitNotRegistered exitCode = 5 // landscape-config --is-registered on an unregistered distro
 )
 
 // ProMock mocks the executable for `pro`.
@@ -268,7 +315,25 @@ func ProMock(t *testing.T) {
 				return exitOk
 			}
 
-			fmt.Fprintf(os.Stdout, `{"attached": %t, "anotherfield": "potato"}%s`, envExists(ProStatusAttached), "\n")
+			if !envExists(ProStatusAttached) {
+				fmt.Fprintln(os.Stdout, `{"attached": false, "anotherfield": "potato", "services": []}`)
+				return exitOk
+			}
+
+			fmt.Fprintln(os.Stdout, `{"attached": true, "anotherfield": "potato", "services": [{"name": "esm-infra", "status": "enabled"}, {"name": "livepatch", "status": "n/a"}, {"name": "usg", "status": "enabled"}]}`)
+			return exitOk
+
+		case "security-status":
+			if envExists(ProSecurityStatusErr) {
+				return exitError
+			}
+
+			if envExists(ProSecurityStatusBadJSON) {
+				fmt.Fprintln(os.Stdout, "invalid\nJSON")
+				return exitOk
+			}
+
+			fmt.Fprintln(os.Stdout, `{"_schema_version": "0.1", "packages": [], "summary": {"num_installed_packages": 612, "num_standard_security_updates": 3, "num_esm_infra_updates": 7, "num_esm_apps_updates": 12, "num_esm_infra_packages": 2, "num_esm_apps_packages": 0, "reboot_required": "no"}}`)
 			return exitOk
 
 		case "attach":
@@ -303,6 +368,13 @@ func ProMock(t *testing.T) {
 			}
 
 			return exitOk
+
+		case "refresh":
+			if envExists(ProRefreshErr) {
+				fmt.Fprintln(os.Stderr, "This error is produced by a mock instructed to fail on pro refresh")
+				return exitError
+			}
+			return exitOk
 		default:
 			fmt.Fprintf(os.Stderr, "Unknown verb %q", argv[0])
 			return exitBadUsage
@@ -327,6 +399,21 @@ func LandscapeConfigMock(t *testing.T) {
 			fmt.Fprintln(os.Stderr, "Mock expected arguments")
 			return exitBadUsage
 		case 1:
+			if argv[0] == "--is-registered" {
+				if envExists(LandscapeStatusErr) {
+					fmt.Fprintln(os.Stderr, "Is-registered: Mock error")
+					return exitError
+				}
+
+				if !envExists(LandscapeRegistered) {
+					fmt.Fprintln(os.Stdout, "Registered:    False")
+					return exitNotRegistered
+				}
+
+				fmt.Fprintln(os.Stdout, "Registered:    True")
+				return exitOk
+			}
+
 			// landscape-config --disable
 			if argv[0] != "--disable" {
 				fmt.Fprintf(os.Stderr, "Mock not implemented for arg %q\n", argv[0])
@@ -526,6 +613,98 @@ func WslInfoMock(t *testing.T) {
 	})
 }
 
+// JournalctlMock mocks the executable for `journalctl`.
+// Add it to your package_test with:
+//
+//	func TestWithJournalctlMock(t *testing.T) { testutils.JournalctlMock(t) }
+//
+//nolint:thelper // This is a faux test used to mock the executable `journalctl`
+func JournalctlMock(t *testing.T) {
+	if t.Name() != "TestWithJournalctlMock" {
+		panic("The JournalctlMock faux test must be named TestWithJournalctlMock")
+	}
+
+	mockMain(t, func(argv []string) exitCode {
+		want := []string{"--unit", "wsl-pro.service", "--lines", "", "--output", "json", "--no-pager"}
+		if len(argv) != len(want) {
+			fmt.Fprintf(os.Stderr, "Mock not implemented for args %q\n", argv)
+			return exitBadUsage
+		}
+
+		for i := range want {
+			if want[i] != "" && argv[i] != want[i] {
+				fmt.Fprintf(os.Stderr, "Mock not implemented for args %q\n", argv)
+				return exitBadUsage
+			}
+		}
+
+		if envExists(JournalctlErr) {
+			return exitError
+		}
+
+		if envExists(JournalctlBadJSON) {
+			fmt.Fprintln(os.Stdout, "invalid\nJSON")
+			return exitOk
+		}
+
+		if envExists(JournalctlEmpty) {
+			return exitOk
+		}
+
+		fmt.Fprintln(os.Stdout, `{"__REALTIME_TIMESTAMP": "1700000000000000", "PRIORITY": "6", "MESSAGE": "Connecting to the Windows agent"}`)
+		fmt.Fprintln(os.Stdout, `{"__REALTIME_TIMESTAMP": "1700000001000000", "PRIORITY": "4", "MESSAGE": [67, 111, 117, 108, 100, 32, 110, 111, 116, 7]}`)
+		fmt.Fprintln(os.Stdout, `{"__REALTIME_TIMESTAMP": "1700000002000000", "MESSAGE": "Connected"}`)
+		return exitOk
+	})
+}
+
+// UseraddMock mocks the executable for `useradd`.
+// Add it to your package_test with:
+//
+//	func TestWithUseraddMock(t *testing.T) { testutils.UseraddMock(t) }
+//
+//nolint:thelper // This is a faux test used to mock the executable `useradd`
+func UseraddMock(t *testing.T) {
+	if t.Name() != "TestWithUseraddMock" {
+		panic("The UseraddMock faux test must be named TestWithUseraddMock")
+	}
+
+	mockMain(t, func(argv []string) exitCode {
+		if len(argv) != 2 || argv[0] != "--create-home" {
+			fmt.Fprintf(os.Stderr, "Mock not implemented for args %q\n", argv)
+			return exitBadUsage
+		}
+
+		if envExists(UseraddErr) {
+			fmt.Fprintln(os.Stderr, "Useradd: Mock error")
+			return exitError
+		}
+
+		root := os.Getenv(FileSystemRoot)
+		if root == "" {
+			fmt.Fprintf(os.Stderr, "Missing environment variable %s\n", FileSystemRoot)
+			return exitBadUsage
+		}
+
+		f, err := os.OpenFile(filepath.Join(root, "etc/passwd"), os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not open users: %v\n", err)
+			return exitError
+		}
+
+		name := argv[1]
+		_, err = fmt.Fprintf(f, "%s:x:1001:1001::/home/%s:/bin/sh\n", name, name)
+		f.Close()
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not add user: %v\n", err)
+			return exitError
+		}
+
+		return exitOk
+	})
+}
+
 // CmdExeMock mocks the executable for `cmd.exe`.
 // Add it to your package_test with:
 //
@@ -557,6 +736,12 @@ func CmdExeMock(t *testing.T) {
 			return exitError
 		}
 
+		// Windows is only reached on behalf of the default user of the distro.
+		if os.Getenv(runAsUser) == "" {
+			fmt.Fprintln(os.Stderr, "cmd.exe must not run as root")
+			return exitError
+		}
+
 		fmt.Fprintln(os.Stdout, windowsUserProfileDir)
 		return exitOk
 	})
@@ -612,6 +797,16 @@ func mockFilesystemRoot(t *testing.T) (rootDir string) {
 	err = os.WriteFile(filepath.Join(rootDir, "etc/os-release"), defaultOsReleaseContents, 0600)
 	require.NoError(t, err, "Setup: could not write mock /etc/os-release")
 
+	err = os.WriteFile(filepath.Join(rootDir, "etc/passwd"), defaultPasswdContents, 0600)
+	require.NoError(t, err, "Setup: could not write mock /etc/passwd")
+
+	// Mock /var/lib/
+	err = os.MkdirAll(filepath.Join(rootDir, "var/lib/wsl-pro-service"), 0750)
+	require.NoError(t, err, "Setup: could not create mock /var/lib/wsl-pro-service/")
+
+	err = os.WriteFile(filepath.Join(rootDir, "var/lib/wsl-pro-service/instance_id"), []byte(DefaultInstanceID+"\n"), 0600)
+	require.NoError(t, err, "Setup: could not write mock /var/lib/wsl-pro-service/instance_id")
+
 	// Mock /proc/
 	err = os.MkdirAll(filepath.Join(rootDir, "/proc"), 0750)
 	require.NoError(t, err, "Setup: could not create mock /proc/")
@@ -625,6 +820,18 @@ func mockFilesystemRoot(t *testing.T) (rootDir string) {
 	err = os.WriteFile(filepath.Join(rootDir, "/proc/net/route"), defaultProcNetRouteContents, 0600)
 	require.NoError(t, err, "Setup: could not write mock /proc/mounts")
 
+	err = os.MkdirAll(filepath.Join(rootDir, "/proc/sys/kernel/random"), 0750)
+	require.NoError(t, err, "Setup: could not create mock /proc/sys/kernel/random/")
+
+	err = os.WriteFile(filepath.Join(rootDir, "/proc/sys/kernel/random/boot_id"), []byte(DefaultBootID+"\n"), 0600)
+	require.NoError(t, err, "Setup: could not write mock /proc/sys/kernel/random/boot_id")
+
+	err = os.MkdirAll(filepath.Join(rootDir, "/proc/sys/kernel"), 0750)
+	require.NoError(t, err, "Setup: could not create mock /proc/sys/kernel/")
+
+	err = os.WriteFile(filepath.Join(rootDir, "/proc/sys/kernel/osrelease"), []byte("5.15.133.1-microsoft-standard-WSL2\n"), 0600)
+	require.NoError(t, err, "Setup: could not write mock /proc/sys/kernel/osrelease")
+
 	// Mock Windows FS
 	portDir := filepath.Join(rootDir, defaultAddrFile)
 	err = os.MkdirAll(filepath.Dir(portDir), 0750)
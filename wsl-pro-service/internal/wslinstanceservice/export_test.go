@@ -0,0 +1,4 @@
+package wslinstanceservice
+
+// RequiredPrivileges are the privileges declared by each call.
+var RequiredPrivileges = requiredPrivileges
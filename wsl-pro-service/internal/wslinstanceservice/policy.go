@@ -0,0 +1,51 @@
+package wslinstanceservice
+
+import (
+	"context"
+	"path"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"google.golang.org/grpc"
+)
+
+// checkPolicy returns a PolicyDenied error if the policy of the distro does not allow the method.
+// Ping is always allowed, as the agent needs it to tell whether the distro is reachable at all.
+func (s *Service) checkPolicy(ctx context.Context, method string) error {
+	call := path.Base(method)
+	if method == wslserviceapi.WSL_Ping_FullMethodName {
+		return nil
+	}
+
+	p, err := s.system.Policy()
+	if err != nil {
+		// A policy that cannot be read may be meant to be restrictive, so nothing goes through.
+		log.Warningf(ctx, "%s: denied: %v", call, err)
+		return wslserviceapi.NewPolicyDeniedError(call, "the policy of the distro could not be read")
+	}
+
+	if !p.Allows(call) {
+		log.Infof(ctx, "%s: denied by the policy of the distro", call)
+		return wslserviceapi.NewPolicyDeniedError(call, p.Reason)
+	}
+
+	return nil
+}
+
+// policyUnaryInterceptor rejects the unary calls that the policy of the distro does not allow.
+func (s *Service) policyUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.checkPolicy(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// policyStreamInterceptor rejects the streaming calls that the policy of the distro does not allow.
+func (s *Service) policyStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkPolicy(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
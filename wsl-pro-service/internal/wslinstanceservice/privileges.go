@@ -0,0 +1,76 @@
+package wslinstanceservice
+
+import (
+	"context"
+
+	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/system"
+	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requiredPrivileges declares what each call needs to be allowed to do in the distro. The service runs
+// as root, but a call can only run the operations its declaration allows. Calls that are not declared
+// are rejected, so that new calls do not get root privileges by accident.
+var requiredPrivileges = map[string]system.Privilege{
+	wslserviceapi.WSL_Ping_FullMethodName:                   system.PrivilegeUser,
+	wslserviceapi.WSL_SetLocale_FullMethodName:              system.PrivilegeUser,
+	wslserviceapi.WSL_GetVersion_FullMethodName:             system.PrivilegeUser,
+	wslserviceapi.WSL_GetResourceUsage_FullMethodName:       system.PrivilegeUser,
+	wslserviceapi.WSL_GetUpdateStatus_FullMethodName:        system.PrivilegeUser,
+	wslserviceapi.WSL_ApplyProToken_FullMethodName:          system.PrivilegeRoot,
+	wslserviceapi.WSL_ProRefresh_FullMethodName:             system.PrivilegeRoot,
+	wslserviceapi.WSL_GetSecurityStatus_FullMethodName:      system.PrivilegeRoot,
+	wslserviceapi.WSL_ApplyLandscapeConfig_FullMethodName:   system.PrivilegeRoot,
+	wslserviceapi.WSL_GetLandscapeConfigHash_FullMethodName: system.PrivilegeRoot,
+	wslserviceapi.WSL_ApplyWSLSettings_FullMethodName:       system.PrivilegeRoot,
+	wslserviceapi.WSL_StreamJournal_FullMethodName:          system.PrivilegeRoot,
+	wslserviceapi.WSL_GetFileTransferStatus_FullMethodName:  system.PrivilegeRoot,
+	wslserviceapi.WSL_PushFile_FullMethodName:               system.PrivilegeRoot,
+	wslserviceapi.WSL_PullFile_FullMethodName:               system.PrivilegeRoot,
+}
+
+// withRequiredPrivilege returns a context that only grants the privilege declared for the method.
+func withRequiredPrivilege(ctx context.Context, method string) (context.Context, error) {
+	p, ok := requiredPrivileges[method]
+	if !ok {
+		err := status.Errorf(codes.PermissionDenied, "%s does not declare the privileges it requires", method)
+		log.Warningf(ctx, "%v", err)
+		return nil, err
+	}
+
+	return system.WithPrivilege(ctx, p), nil
+}
+
+// privilegesUnaryInterceptor limits each unary call to the privilege it declares.
+func privilegesUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := withRequiredPrivilege(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// privilegesStreamInterceptor limits each streaming call to the privilege it declares.
+func privilegesStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := withRequiredPrivilege(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, privilegedServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// privilegedServerStream is a server stream whose context carries the privilege of the call.
+type privilegedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the call, carrying its privilege.
+func (ss privilegedServerStream) Context() context.Context {
+	return ss.ctx
+}
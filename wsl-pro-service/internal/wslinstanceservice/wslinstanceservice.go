@@ -5,19 +5,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	"github.com/canonical/ubuntu-pro-for-wsl/common"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/buildinfo"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/grpc/interceptorschain"
 	"github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logconnections"
 	log "github.com/canonical/ubuntu-pro-for-wsl/common/grpc/logstreamer"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/i18n"
+	"github.com/canonical/ubuntu-pro-for-wsl/common/telemetry"
+	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/consts"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/system"
 	"github.com/canonical/ubuntu-pro-for-wsl/wslserviceapi"
 	"github.com/sirupsen/logrus"
 	"github.com/ubuntu/decorate"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// fileChunkSize is how much of a file is sent in every message, well below the gRPC message size limit.
+const fileChunkSize = 1024 * 1024
+
 // ControlStreamClient is the client to the stream between the Windows Agent and the WSL instance service.
 type ControlStreamClient interface {
 	Send(*agentapi.DistroInfo) error
@@ -39,15 +51,20 @@ func New(s system.System) *Service {
 }
 
 // RegisterGRPCService returns a new grpc Server with the 2 api services attached to it.
-// It also gets the correct middlewares hooked in.
+// It also gets the correct middlewares hooked in: each call is limited to the privileges it declares,
+// and the calls that the policy of the distro does not allow are rejected.
 func (s *Service) RegisterGRPCService(ctx context.Context, ctrlStream ControlStreamClient) *grpc.Server {
 	log.Debug(ctx, "Registering gRPC WSL instance service")
 	s.ctrlStream = ctrlStream
 
-	grpcServer := grpc.NewServer(grpc.StreamInterceptor(
-		interceptorschain.StreamServer(
-			log.StreamServerInterceptor(logrus.StandardLogger()),
+	grpcServer := grpc.NewServer(
+		telemetry.ServerOption(),
+		grpc.ChainUnaryInterceptor(privilegesUnaryInterceptor, s.policyUnaryInterceptor),
+		grpc.StreamInterceptor(interceptorschain.StreamServer(
+			log.StreamServerInterceptor(logrus.StandardLogger(), log.WithLogBuffer(consts.LogBufferSize, log.Coalesce)),
 			logconnections.StreamServerInterceptor(),
+			privilegesStreamInterceptor,
+			s.policyStreamInterceptor,
 		)))
 
 	wslserviceapi.RegisterWSLServer(grpcServer, s)
@@ -71,6 +88,14 @@ func (s *Service) ApplyProToken(ctx context.Context, info *wslserviceapi.ProAtta
 		log.Info(ctx, "ApplyProToken: Received empty token: detaching")
 	} else {
 		log.Infof(ctx, "ApplyProToken: Received token %q: attaching", common.Obfuscate(info.GetToken()))
+
+		// Re-attaching with the same token would only cause churn.
+		if attached, err := s.system.ProAttachedWith(ctx, info.GetToken()); err != nil {
+			log.Warningf(ctx, "ApplyProToken: %v", err)
+		} else if attached {
+			log.Infof(ctx, "ApplyProToken: already attached with token %q: nothing to do", common.Obfuscate(info.GetToken()))
+			return &wslserviceapi.Empty{}, nil
+		}
 	}
 
 	if err := s.system.ProDetach(ctx); err != nil {
@@ -101,25 +126,329 @@ func (s *Service) sendInfo(ctx context.Context) error {
 	return nil
 }
 
-// ApplyLandscapeConfig serves LandscapeConfig messages sent by the agent.
-func (s *Service) ApplyLandscapeConfig(ctx context.Context, msg *wslserviceapi.LandscapeConfig) (empty *wslserviceapi.Empty, err error) {
+// ApplyLandscapeConfig serves LandscapeConfig messages sent by the agent. It reports back the users
+// that Landscape scripts may run as once registered.
+func (s *Service) ApplyLandscapeConfig(ctx context.Context, msg *wslserviceapi.LandscapeConfig) (res *wslserviceapi.LandscapeConfigResult, err error) {
 	defer decorate.OnError(&err, "WSL service")
 
+	defer func() {
+		// The registration and the script users are part of the system info: the agent gets them
+		// even when it does not apply the config again. Failing to send them is not worth failing the call.
+		if e := s.sendInfo(ctx); e != nil {
+			log.Warningf(ctx, "ApplyLandscapeConfig: could not send update via control stream: %v", e)
+		}
+	}()
+
 	conf := msg.GetConfiguration()
 	if conf == "" {
 		log.Info(ctx, "ApplyLandscapeConfig: received empty config: disabling")
 		if err := s.system.LandscapeDisable(ctx); err != nil {
 			return nil, err
 		}
-		return &wslserviceapi.Empty{}, nil
+		return &wslserviceapi.LandscapeConfigResult{}, nil
 	}
 
 	uid := msg.GetHostagentUID()
 
 	log.Infof(ctx, "ApplyLandscapeConfig: received config: registering")
-	if err := s.system.LandscapeEnable(ctx, conf, uid); err != nil {
+	scriptUsers, err := s.system.LandscapeEnable(ctx, conf, uid, msg.GetComputerTitle())
+	if err != nil {
+		return nil, err
+	}
+
+	// Not recording the hash only means that the same config will be applied again next time.
+	if err := s.system.SetLandscapeConfigHash(wslserviceapi.HashLandscapeConfig(msg)); err != nil {
+		log.Warningf(ctx, "ApplyLandscapeConfig: %v", err)
+	}
+
+	return &wslserviceapi.LandscapeConfigResult{ScriptUsers: scriptUsers}, nil
+}
+
+// GetLandscapeConfigHash serves GetLandscapeConfigHash messages sent by the agent, reporting the
+// hash of the Landscape config applied last, so that the agent does not send the same one again.
+func (s *Service) GetLandscapeConfigHash(ctx context.Context, msg *wslserviceapi.Empty) (hash *wslserviceapi.LandscapeConfigHash, err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	h, err := s.system.LandscapeConfigHash()
+	if err != nil {
+		return nil, err
+	}
+
+	return &wslserviceapi.LandscapeConfigHash{Hash: h}, nil
+}
+
+// SetLocale serves SetLocale messages sent by the agent, so that the messages generated in
+// this distro are translated to the Windows display language.
+func (s *Service) SetLocale(ctx context.Context, msg *wslserviceapi.Locale) (*wslserviceapi.Empty, error) {
+	log.Infof(ctx, "SetLocale: received locale %q", msg.GetName())
+	i18n.SetLocale(msg.GetName())
+
+	return &wslserviceapi.Empty{}, nil
+}
+
+// GetVersion serves GetVersion messages sent by the agent, reporting how this service was built and the
+// versions of the APIs it speaks.
+func (s *Service) GetVersion(ctx context.Context, msg *wslserviceapi.Empty) (*wslserviceapi.VersionInfo, error) {
+	info := buildinfo.New(consts.Version, consts.Commit, consts.BuildDate)
+
+	return &wslserviceapi.VersionInfo{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildDate: info.BuildDate,
+		Protocols: []*wslserviceapi.ProtocolVersion{
+			{Name: "agentapi", Version: agentapi.ProtocolVersion},
+			{Name: "wslserviceapi", Version: wslserviceapi.ProtocolVersion},
+		},
+	}, nil
+}
+
+// ApplyWSLSettings serves WSLSettings messages sent by the agent.
+func (s *Service) ApplyWSLSettings(ctx context.Context, msg *wslserviceapi.WSLSettings) (empty *wslserviceapi.Empty, err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	log.Infof(ctx, "ApplyWSLSettings: received settings: %v", msg)
+
+	settings := system.WSLSettings{
+		DefaultUser: msg.GetDefaultUser(),
+		Systemd:     msg.Systemd,
+		Interop:     msg.Interop,
+		Automount:   msg.Automount,
+	}
+
+	if err := s.system.ApplyWSLSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return &wslserviceapi.Empty{}, nil
+}
+
+// GetSecurityStatus serves GetSecurityStatus messages sent by the agent, reporting the number of
+// security updates pending in this distro.
+func (s *Service) GetSecurityStatus(ctx context.Context, msg *wslserviceapi.Empty) (status *wslserviceapi.SecurityStatus, err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	st, err := s.system.ProSecurityStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf(ctx, "GetSecurityStatus: %d security updates and %d ESM updates pending", st.SecurityUpdates, st.ESMUpdates)
+
+	return &wslserviceapi.SecurityStatus{
+		SecurityUpdates: uint32(st.SecurityUpdates),
+		EsmUpdates:      uint32(st.ESMUpdates),
+	}, nil
+}
+
+// GetUpdateStatus serves GetUpdateStatus messages sent by the agent, reporting whether this distro
+// needs to be rebooted and how many package updates are pending.
+func (s *Service) GetUpdateStatus(ctx context.Context, msg *wslserviceapi.Empty) (status *wslserviceapi.UpdateStatus, err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	st, err := s.system.UpdateStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf(ctx, "GetUpdateStatus: reboot required: %t, %d updates pending", st.RebootRequired, st.PendingUpdates)
+
+	return &wslserviceapi.UpdateStatus{
+		RebootRequired: st.RebootRequired,
+		RebootPackages: st.RebootPackages,
+		PendingUpdates: uint32(st.PendingUpdates),
+	}, nil
+}
+
+// ProRefresh serves ProRefresh messages sent by the agent, refreshing the Ubuntu Pro contract
+// and configuration of this distro.
+func (s *Service) ProRefresh(ctx context.Context, msg *wslserviceapi.Empty) (empty *wslserviceapi.Empty, err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	if err := s.system.ProRefresh(ctx); err != nil {
 		return nil, err
 	}
 
+	log.Debug(ctx, "ProRefresh: Ubuntu Pro was refreshed")
+
 	return &wslserviceapi.Empty{}, nil
 }
+
+// GetResourceUsage serves GetResourceUsage messages sent by the agent, reporting the memory and CPU
+// used by the processes of this distro.
+func (s *Service) GetResourceUsage(ctx context.Context, msg *wslserviceapi.Empty) (usage *wslserviceapi.ResourceUsage, err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	u, err := s.system.ResourceUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf(ctx, "GetResourceUsage: %d bytes of memory and %.1f%% CPU used", u.MemoryBytes, u.CPUPercent)
+
+	return &wslserviceapi.ResourceUsage{
+		MemoryBytes: u.MemoryBytes,
+		CpuPercent:  u.CPUPercent,
+	}, nil
+}
+
+// StreamJournal serves StreamJournal messages sent by the agent, sending the most recent entries of the
+// journal of this service so that they can be viewed from Windows.
+func (s *Service) StreamJournal(msg *wslserviceapi.JournalRequest, stream wslserviceapi.WSL_StreamJournalServer) (err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	ctx := stream.Context()
+
+	entries, err := s.system.Journal(ctx, int(msg.GetLines()))
+	if err != nil {
+		return err
+	}
+
+	log.Debugf(ctx, "StreamJournal: sending %d journal entries", len(entries))
+
+	for _, e := range entries {
+		if err := stream.Send(&wslserviceapi.JournalEntry{
+			Time:     e.Time.Format(time.RFC3339),
+			Priority: e.Priority,
+			Message:  e.Message,
+		}); err != nil {
+			return fmt.Errorf("could not send journal entry: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// GetFileTransferStatus serves GetFileTransferStatus messages sent by the agent, reporting how much of
+// the file was received so far, so that an interrupted PushFile can be resumed.
+func (s *Service) GetFileTransferStatus(ctx context.Context, msg *wslserviceapi.FileHeader) (st *wslserviceapi.FileTransferStatus, err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	received, err := s.system.FileTransferReceived(fileHeader(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf(ctx, "GetFileTransferStatus: %d of the %d bytes of %s received", received, msg.GetSize(), msg.GetPath())
+
+	return &wslserviceapi.FileTransferStatus{Received: received}, nil
+}
+
+// PushFile serves PushFile messages sent by the agent, writing the file they carry in chunks to the distro.
+// What was received is kept if the transfer is interrupted, so that it can be resumed.
+func (s *Service) PushFile(stream wslserviceapi.WSL_PushFileServer) (err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("could not receive the header of the file: %v", err)
+	}
+
+	msg := first.GetHeader()
+	if msg == nil {
+		return status.Error(codes.InvalidArgument, "the first message must be the header of the file")
+	}
+
+	log.Infof(ctx, "PushFile: receiving %s (%d bytes) from offset %d", msg.GetPath(), msg.GetSize(), msg.GetOffset())
+
+	t, err := s.system.ResumeFileTransfer(ctx, fileHeader(msg), msg.GetOffset())
+	if err != nil {
+		return err
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errors.Join(fmt.Errorf("could not receive chunk: %v", err), t.Close())
+		}
+
+		if chunk.GetHeader() != nil {
+			return errors.Join(status.Error(codes.InvalidArgument, "only the first message can be the header of the file"), t.Close())
+		}
+
+		if _, err := t.Write(chunk.GetData()); err != nil {
+			return errors.Join(err, t.Close())
+		}
+	}
+
+	if err := t.Close(); err != nil {
+		return err
+	}
+
+	if t.Complete() {
+		log.Infof(ctx, "PushFile: %s received", msg.GetPath())
+	} else {
+		log.Infof(ctx, "PushFile: %d of the %d bytes of %s received", t.Received(), msg.GetSize(), msg.GetPath())
+	}
+
+	return stream.SendAndClose(&wslserviceapi.FileTransferStatus{
+		Received: t.Received(),
+		Complete: t.Complete(),
+	})
+}
+
+// PullFile serves PullFile messages sent by the agent, sending it a file of the distro in chunks. The file
+// can be sent from an offset, so that the agent can resume an interrupted transfer.
+func (s *Service) PullFile(msg *wslserviceapi.FileRequest, stream wslserviceapi.WSL_PullFileServer) (err error) {
+	defer decorate.OnError(&err, "WSL service")
+
+	ctx := stream.Context()
+
+	log.Infof(ctx, "PullFile: sending %s from offset %d", msg.GetPath(), msg.GetOffset())
+
+	h, r, err := s.system.OpenFileFrom(ctx, msg.GetPath(), msg.GetOffset())
+	if errors.Is(err, os.ErrNotExist) {
+		return status.Error(codes.NotFound, err.Error())
+	} else if errors.Is(err, system.ErrOffsetPastEnd) {
+		return status.Error(codes.OutOfRange, err.Error())
+	} else if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	header := &wslserviceapi.FileHeader{
+		Path:   h.Path,
+		Size:   h.Size,
+		Sha256: h.SHA256,
+		Mode:   uint32(h.Mode),
+		Offset: msg.GetOffset(),
+	}
+
+	if err := stream.Send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Header{Header: header}}); err != nil {
+		return fmt.Errorf("could not send the header of the file: %v", err)
+	}
+
+	buff := make([]byte, fileChunkSize)
+	for {
+		n, err := r.Read(buff)
+		if n > 0 {
+			if err := stream.Send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Data{Data: buff[:n]}}); err != nil {
+				return fmt.Errorf("could not send chunk: %v", err)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not read %s: %v", msg.GetPath(), err)
+		}
+	}
+
+	log.Debugf(ctx, "PullFile: %s sent", msg.GetPath())
+
+	return nil
+}
+
+// fileHeader converts the header of a file from the API to the one of the system.
+func fileHeader(msg *wslserviceapi.FileHeader) system.FileHeader {
+	return system.FileHeader{
+		Path:   msg.GetPath(),
+		Size:   msg.GetSize(),
+		SHA256: msg.GetSha256(),
+		Mode:   os.FileMode(msg.GetMode()),
+	}
+}
@@ -2,13 +2,20 @@ package wslinstanceservice_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	agentapi "github.com/canonical/ubuntu-pro-for-wsl/agentapi/go"
 	commontestutils "github.com/canonical/ubuntu-pro-for-wsl/common/testutils"
+	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/consts"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/system"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/testutils"
 	"github.com/canonical/ubuntu-pro-for-wsl/wsl-pro-service/internal/wslinstanceservice"
@@ -16,7 +23,9 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 func TestMain(m *testing.M) {
@@ -37,6 +46,7 @@ func TestApplyProToken(t *testing.T) {
 
 	testCases := map[string]struct {
 		token             string
+		attachedWith      string
 		proStatusErr      bool
 		getSystemErr      bool
 		proDetachErr      detachResult
@@ -45,14 +55,17 @@ func TestApplyProToken(t *testing.T) {
 
 		wantErr bool
 	}{
-		"success attaching attached machine":     {token: "123"},
-		"success attaching non-attached machine": {token: "123", proDetachErr: detachAlreadyDetached},
-		"success detaching attached machine":     {},
-		"success detaching non-attached machine": {proDetachErr: detachAlreadyDetached},
+		"success attaching attached machine":                                {token: "123"},
+		"success attaching non-attached machine":                            {token: "123", proDetachErr: detachAlreadyDetached},
+		"success detaching attached machine":                                {},
+		"success detaching non-attached machine":                            {proDetachErr: detachAlreadyDetached},
+		"success skipping attach when already attached with the same token": {token: "123", attachedWith: "123", attachErr: true, proDetachErr: detachErr},
+		"success attaching machine attached with another token":             {token: "123", attachedWith: "456"},
 
 		// Attach/detach errors
-		"Error calling pro attach": {token: "123", attachErr: true, wantErr: true},
-		"Error detaching pro":      {proDetachErr: detachErr, wantErr: true},
+		"Error calling pro attach":                                  {token: "123", attachErr: true, wantErr: true},
+		"Error calling pro attach when attached with another token": {token: "123", attachedWith: "456", attachErr: true, wantErr: true},
+		"Error detaching pro":                                       {proDetachErr: detachErr, wantErr: true},
 
 		// System info
 		"Error calling pro status":         {proStatusErr: true, wantErr: true},
@@ -73,7 +86,9 @@ func TestApplyProToken(t *testing.T) {
 				VersionId:   "22.04",
 				PrettyName:  "Ubuntu 22.04.1 LTS",
 				ProAttached: true,
+				ProServices: []string{"esm-infra", "usg"},
 				Hostname:    "TEST_DISTRO_HOSTNAME",
+				InstanceId:  testutils.DefaultInstanceID,
 			}
 
 			ctrlClient, controlService := newCtrlStream(t, ctx)
@@ -81,6 +96,11 @@ func TestApplyProToken(t *testing.T) {
 
 			system, mock := testutils.MockSystem(t)
 
+			if tc.attachedWith != "" {
+				err := system.ProAttach(testutils.RootContext(), tc.attachedWith)
+				require.NoError(t, err, "Setup: ProAttach should return no errors")
+			}
+
 			if tc.getSystemErr {
 				os.Remove(mock.Path("etc/os-release"))
 			}
@@ -131,14 +151,18 @@ func TestApplyLandscapeConfig(t *testing.T) {
 
 	testCases := map[string]struct {
 		emptyConfig bool
+		scriptUsers string
+		registered  bool
 		enableErr   bool
 		disableErr  bool
 
-		wantErr bool
+		wantScriptUsers string
+		wantErr         bool
 	}{
 		// Enable
-		"Success enabling":  {},
-		"Success disabling": {emptyConfig: true},
+		"Success enabling":                   {},
+		"Success disabling":                  {emptyConfig: true},
+		"Success reporting the script users": {scriptUsers: "administrator", registered: true, wantScriptUsers: "ubuntu"},
 
 		"Error enabling when landscape-config fails":            {enableErr: true, wantErr: true},
 		"Error disabling when landscape-config --disable fails": {emptyConfig: true, disableErr: true, wantErr: true},
@@ -161,22 +185,35 @@ func TestApplyLandscapeConfig(t *testing.T) {
 				mock.SetControlArg(testutils.LandscapeDisableErr)
 			}
 
-			ctrlClient, _ := newCtrlStream(t, ctx)
+			if tc.registered {
+				mock.SetControlArg(testutils.LandscapeRegistered)
+			}
+
+			ctrlClient, controlService := newCtrlStream(t, ctx)
 			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
 
 			var config string
 			if !tc.emptyConfig {
 				config = "[hello]\nworld: true"
 			}
+			if tc.scriptUsers != "" {
+				config += "\n[client]\nscript_users = " + tc.scriptUsers
+			}
 
-			empty, err := wslClient.ApplyLandscapeConfig(ctx, &wslserviceapi.LandscapeConfig{Configuration: config, HostagentUID: "landscapeHostagent1234"})
+			res, err := wslClient.ApplyLandscapeConfig(ctx, &wslserviceapi.LandscapeConfig{Configuration: config, HostagentUID: "landscapeHostagent1234"})
 			if tc.wantErr {
 				require.Error(t, err, "ApplyLandscapeConfig call should return an error")
 				return
 			}
 			require.NoError(t, err, "ApplyLandscapeConfig call should return no error")
 
-			require.NotNil(t, empty, "ApplyLandscapeConfig should not return a nil response")
+			require.NotNil(t, res, "ApplyLandscapeConfig should not return a nil response")
+			require.Equal(t, tc.wantScriptUsers, res.GetScriptUsers(), "ApplyLandscapeConfig returned unexpected script users")
+
+			info, err := controlService.recv()
+			require.NoError(t, err, "ctrlClient should receive an info sent from the wslinstanceservice")
+			require.Equal(t, tc.registered, info.GetLandscapeRegistered(), "System info sent to agent has unexpected Landscape registration")
+			require.Equal(t, tc.wantScriptUsers, info.GetLandscapeScriptUsers(), "System info sent to agent has unexpected script users")
 
 			if tc.emptyConfig {
 				require.FileExists(t, mock.Path("/.landscape-disabled"), "Landscape executable was not called to disable")
@@ -195,6 +232,623 @@ func TestApplyLandscapeConfig(t *testing.T) {
 	}
 }
 
+func TestGetLandscapeConfigHash(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		applyConfig bool
+		breakHash   bool
+
+		wantErr bool
+	}{
+		"Success with no config applied": {},
+		"Success after applying config":  {applyConfig: true},
+
+		"Error when the hash cannot be read": {breakHash: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			msg := &wslserviceapi.LandscapeConfig{Configuration: "[hello]\nworld: true", HostagentUID: "landscapeHostagent1234"}
+			var want string
+			if tc.applyConfig {
+				_, err := wslClient.ApplyLandscapeConfig(ctx, msg)
+				require.NoError(t, err, "Setup: ApplyLandscapeConfig call should return no error")
+				want = wslserviceapi.HashLandscapeConfig(msg)
+			}
+
+			if tc.breakHash {
+				require.NoError(t, os.MkdirAll(mock.Path("/var/lib/wsl-pro-service/landscape_config_hash"), 0750), "Setup: could not create a directory in place of the hash")
+			}
+
+			got, err := wslClient.GetLandscapeConfigHash(ctx, &wslserviceapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetLandscapeConfigHash call should return an error")
+				return
+			}
+			require.NoError(t, err, "GetLandscapeConfigHash call should return no error")
+			require.Equal(t, want, got.GetHash(), "GetLandscapeConfigHash should return the hash of the config applied last")
+		})
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		locale string
+	}{
+		"Success with a Windows language name": {locale: "de-DE"},
+		"Success with an empty locale":         {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, _ := testutils.MockSystem(t)
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			empty, err := wslClient.SetLocale(ctx, &wslserviceapi.Locale{Name: tc.locale})
+			require.NoError(t, err, "SetLocale call should return no error")
+			require.NotNil(t, empty, "SetLocale should not return a nil response")
+		})
+	}
+}
+
+func TestGetVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	system, _ := testutils.MockSystem(t)
+
+	ctrlClient, _ := newCtrlStream(t, ctx)
+	wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+	got, err := wslClient.GetVersion(ctx, &wslserviceapi.Empty{})
+	require.NoError(t, err, "GetVersion call should return no error")
+
+	require.Equal(t, consts.Version, got.GetVersion(), "GetVersion should report the version of the service")
+
+	protocols := make(map[string]uint32)
+	for _, p := range got.GetProtocols() {
+		protocols[p.GetName()] = p.GetVersion()
+	}
+	want := map[string]uint32{"agentapi": agentapi.ProtocolVersion, "wslserviceapi": wslserviceapi.ProtocolVersion}
+	require.Equal(t, want, protocols, "GetVersion should report the version of every API the service speaks")
+}
+
+func TestApplyWSLSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		defaultUser string
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when the default user does not exist": {defaultUser: "nobody-here", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			enabled := true
+			empty, err := wslClient.ApplyWSLSettings(ctx, &wslserviceapi.WSLSettings{DefaultUser: tc.defaultUser, Systemd: &enabled})
+			if tc.wantErr {
+				require.Error(t, err, "ApplyWSLSettings call should return an error")
+				return
+			}
+			require.NoError(t, err, "ApplyWSLSettings call should return no error")
+			require.NotNil(t, empty, "ApplyWSLSettings should not return a nil response")
+
+			out, err := os.ReadFile(mock.Path("/etc/wsl.conf"))
+			require.NoError(t, err, "Could not read wsl.conf")
+			require.Contains(t, string(out), "systemd = true", "wsl.conf should enable systemd")
+		})
+	}
+}
+
+func TestGetSecurityStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		breakSecurityStatus bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when pro security-status fails": {breakSecurityStatus: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+			if tc.breakSecurityStatus {
+				mock.SetControlArg(testutils.ProSecurityStatusErr)
+			}
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			status, err := wslClient.GetSecurityStatus(ctx, &wslserviceapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetSecurityStatus call should return an error")
+				return
+			}
+			require.NoError(t, err, "GetSecurityStatus call should return no error")
+
+			require.Equal(t, uint32(3), status.GetSecurityUpdates(), "Unexpected number of security updates")
+			require.Equal(t, uint32(19), status.GetEsmUpdates(), "Unexpected number of ESM updates")
+		})
+	}
+}
+
+func TestProRefresh(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		breakRefresh bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when pro refresh fails": {breakRefresh: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+			if tc.breakRefresh {
+				mock.SetControlArg(testutils.ProRefreshErr)
+			}
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			_, err := wslClient.ProRefresh(ctx, &wslserviceapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "ProRefresh call should return an error")
+				return
+			}
+			require.NoError(t, err, "ProRefresh call should return no error")
+		})
+	}
+}
+
+func TestGetResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		breakProcessStat bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when a process stat cannot be parsed": {breakProcessStat: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+			stat := "1 (init) S 0 1 1 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 1 0 5 1000 300"
+			if tc.breakProcessStat {
+				stat = "1 (init) S"
+			}
+			require.NoError(t, os.MkdirAll(mock.Path("/proc/1"), 0750), "Setup: could not create mock process directory")
+			require.NoError(t, os.WriteFile(mock.Path("/proc/1/stat"), []byte(stat), 0600), "Setup: could not write mock process stat")
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			usage, err := wslClient.GetResourceUsage(ctx, &wslserviceapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetResourceUsage call should return an error")
+				return
+			}
+			require.NoError(t, err, "GetResourceUsage call should return no error")
+
+			require.Equal(t, uint64(300*os.Getpagesize()), usage.GetMemoryBytes(), "Unexpected memory usage")
+			require.Zero(t, usage.GetCpuPercent(), "Unexpected CPU usage")
+		})
+	}
+}
+
+func TestGetUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		breakUpdatesSummary bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when the updates summary cannot be read": {breakUpdatesSummary: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+			require.NoError(t, os.MkdirAll(mock.Path("/var/run"), 0750), "Setup: could not create mock run directory")
+			require.NoError(t, os.WriteFile(mock.Path("/var/run/reboot-required"), nil, 0600), "Setup: could not write mock reboot flag")
+			require.NoError(t, os.WriteFile(mock.Path("/var/run/reboot-required.pkgs"), []byte("linux-base\n"), 0600), "Setup: could not write mock reboot packages")
+
+			summary := mock.Path("/var/lib/update-notifier/updates-available")
+			if tc.breakUpdatesSummary {
+				require.NoError(t, os.MkdirAll(summary, 0750), "Setup: could not create a directory in place of the updates summary")
+			} else {
+				require.NoError(t, os.MkdirAll(filepath.Dir(summary), 0750), "Setup: could not create mock update-notifier directory")
+				require.NoError(t, os.WriteFile(summary, []byte("7 updates can be applied immediately.\n"), 0600), "Setup: could not write mock updates summary")
+			}
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			status, err := wslClient.GetUpdateStatus(ctx, &wslserviceapi.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "GetUpdateStatus call should return an error")
+				return
+			}
+			require.NoError(t, err, "GetUpdateStatus call should return no error")
+
+			require.True(t, status.GetRebootRequired(), "The distro should require a reboot")
+			require.Equal(t, []string{"linux-base"}, status.GetRebootPackages(), "Unexpected packages requiring a reboot")
+			require.Equal(t, uint32(7), status.GetPendingUpdates(), "Unexpected number of pending updates")
+		})
+	}
+}
+
+func TestStreamJournal(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		journalctlErr bool
+
+		wantErr bool
+	}{
+		"Success": {},
+
+		"Error when the journal cannot be read": {journalctlErr: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+			if tc.journalctlErr {
+				mock.SetControlArg(testutils.JournalctlErr)
+			}
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			stream, err := wslClient.StreamJournal(ctx, &wslserviceapi.JournalRequest{Lines: 10})
+			require.NoError(t, err, "StreamJournal call should open the stream")
+
+			var got []*wslserviceapi.JournalEntry
+			for {
+				e, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if tc.wantErr {
+					require.Error(t, err, "StreamJournal should return an error")
+					return
+				}
+				require.NoError(t, err, "StreamJournal should return no error")
+				got = append(got, e)
+			}
+			require.False(t, tc.wantErr, "StreamJournal should have returned an error")
+
+			require.Len(t, got, 3, "Unexpected number of journal entries")
+			require.Equal(t, "Connecting to the Windows agent", got[0].GetMessage(), "Unexpected message of the first entry")
+			require.Equal(t, uint32(4), got[1].GetPriority(), "Unexpected priority of the second entry")
+
+			_, err = time.Parse(time.RFC3339, got[0].GetTime())
+			require.NoError(t, err, "The time of the entries should be in RFC 3339 format")
+		})
+	}
+}
+
+func TestPushFile(t *testing.T) {
+	t.Parallel()
+
+	contents := []byte("-----BEGIN CERTIFICATE-----\nMOCK\n-----END CERTIFICATE-----\n")
+	sum := sha256.Sum256(contents)
+
+	testCases := map[string]struct {
+		interruptAt int
+		noHeader    bool
+		twoHeaders  bool
+		badChecksum bool
+
+		wantErr bool
+	}{
+		"Success":                             {},
+		"Success resuming an interrupted one": {interruptAt: 20},
+
+		"Error when the first message is not the header": {noHeader: true, wantErr: true},
+		"Error when the header is sent twice":            {twoHeaders: true, wantErr: true},
+		"Error when the checksum does not match":         {badChecksum: true, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			header := &wslserviceapi.FileHeader{
+				Path:   "/usr/local/share/ca-certificates/example.crt",
+				Size:   uint64(len(contents)),
+				Sha256: hex.EncodeToString(sum[:]),
+			}
+			if tc.badChecksum {
+				wrong := sha256.Sum256([]byte("something else"))
+				header.Sha256 = hex.EncodeToString(wrong[:])
+			}
+
+			// push sends the file from the offset of the header up to the end.
+			push := func(end int) (*wslserviceapi.FileTransferStatus, error) {
+				stream, err := wslClient.PushFile(ctx)
+				require.NoError(t, err, "PushFile call should open the stream")
+
+				send := func(chunk *wslserviceapi.FileChunk) {
+					// EOF means that the service already answered: its error is returned by CloseAndRecv.
+					if err := stream.Send(chunk); !errors.Is(err, io.EOF) {
+						require.NoError(t, err, "Setup: could not send chunk")
+					}
+				}
+
+				if !tc.noHeader {
+					send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Header{Header: header}})
+				}
+				if tc.twoHeaders {
+					send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Header{Header: header}})
+				}
+				send(&wslserviceapi.FileChunk{Content: &wslserviceapi.FileChunk_Data{Data: contents[header.GetOffset():end]}})
+
+				return stream.CloseAndRecv()
+			}
+
+			if tc.interruptAt != 0 {
+				st, err := push(tc.interruptAt)
+				require.NoError(t, err, "PushFile should accept an incomplete file")
+				require.False(t, st.GetComplete(), "PushFile should not report an incomplete file as complete")
+
+				st, err = wslClient.GetFileTransferStatus(ctx, header)
+				require.NoError(t, err, "GetFileTransferStatus should return no error")
+				require.Equal(t, uint64(tc.interruptAt), st.GetReceived(), "GetFileTransferStatus should report what was received")
+				header.Offset = st.GetReceived()
+			}
+
+			st, err := push(len(contents))
+			if tc.wantErr {
+				require.Error(t, err, "PushFile should return an error")
+				require.NoFileExists(t, mock.Path(header.GetPath()), "The file should not have been written")
+				return
+			}
+			require.NoError(t, err, "PushFile should return no error")
+			require.True(t, st.GetComplete(), "PushFile should report the file as complete")
+			require.Equal(t, uint64(len(contents)), st.GetReceived(), "PushFile should report the whole file as received")
+
+			got, err := os.ReadFile(mock.Path(header.GetPath()))
+			require.NoError(t, err, "The file should have been written")
+			require.Equal(t, contents, got, "The file should have been received whole")
+		})
+	}
+}
+
+func TestPullFile(t *testing.T) {
+	t.Parallel()
+
+	contents := []byte("Apr 01 10:00:00 ubuntu wsl-pro-service: Connecting to the Windows agent\n")
+	sum := sha256.Sum256(contents)
+
+	testCases := map[string]struct {
+		path   string
+		offset uint64
+
+		wantCode codes.Code
+	}{
+		"Success":                {},
+		"Success from an offset": {offset: 10},
+
+		"Error when the file does not exist":    {path: "/var/log/missing.log", wantCode: codes.NotFound},
+		"Error when the offset is past the end": {offset: uint64(len(contents)) + 1, wantCode: codes.OutOfRange},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+
+			const path = "/var/log/example.log"
+			if tc.path == "" {
+				tc.path = path
+			}
+
+			err := os.MkdirAll(filepath.Dir(mock.Path(path)), 0700)
+			require.NoError(t, err, "Setup: could not create the parent directory")
+			err = os.WriteFile(mock.Path(path), contents, 0600)
+			require.NoError(t, err, "Setup: could not write the file")
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			stream, err := wslClient.PullFile(ctx, &wslserviceapi.FileRequest{Path: tc.path, Offset: tc.offset})
+			require.NoError(t, err, "PullFile call should open the stream")
+
+			first, err := stream.Recv()
+			if tc.wantCode != codes.OK {
+				require.Error(t, err, "PullFile should return an error")
+				require.Equal(t, tc.wantCode, status.Code(err), "Unexpected status code")
+				return
+			}
+			require.NoError(t, err, "PullFile should send the header")
+
+			header := first.GetHeader()
+			require.NotNil(t, header, "The first message should be the header")
+			require.Equal(t, uint64(len(contents)), header.GetSize(), "The header should have the size of the whole file")
+			require.Equal(t, hex.EncodeToString(sum[:]), header.GetSha256(), "The header should have the checksum of the whole file")
+			require.Equal(t, tc.offset, header.GetOffset(), "The header should have the offset the file is sent from")
+
+			var got []byte
+			for {
+				chunk, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err, "PullFile should return no error")
+				require.Nil(t, chunk.GetHeader(), "Only the first message should be the header")
+				got = append(got, chunk.GetData()...)
+			}
+
+			require.Equal(t, contents[tc.offset:], got, "PullFile should send the file from the offset")
+		})
+	}
+}
+
+func TestRequiredPrivileges(t *testing.T) {
+	t.Parallel()
+
+	var methods []string
+	for _, m := range wslserviceapi.WSL_ServiceDesc.Methods {
+		methods = append(methods, fmt.Sprintf("/%s/%s", wslserviceapi.WSL_ServiceDesc.ServiceName, m.MethodName))
+	}
+	for _, s := range wslserviceapi.WSL_ServiceDesc.Streams {
+		methods = append(methods, fmt.Sprintf("/%s/%s", wslserviceapi.WSL_ServiceDesc.ServiceName, s.StreamName))
+	}
+
+	for _, m := range methods {
+		require.Contains(t, wslinstanceservice.RequiredPrivileges, m, "Every call should declare the privileges it requires")
+	}
+	require.Len(t, wslinstanceservice.RequiredPrivileges, len(methods), "Only the calls of the service should be declared")
+}
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		policy      string
+		breakPolicy bool
+
+		wantDenied bool
+		wantReason string
+	}{
+		"Success without a policy":                     {},
+		"Success when the policy allows the call":      {policy: "[calls]\nallow = ProRefresh\n"},
+		"Success pinging when the policy denies it":    {policy: "[calls]\nallow =\n"},
+		"Error when the policy does not list the call": {policy: "[calls]\nallow = ApplyProToken\n", wantDenied: true},
+		"Error when the policy denies the call":        {policy: "[calls]\ndeny = ProRefresh\nreason = Managed by IT\n", wantDenied: true, wantReason: "Managed by IT"},
+		"Error when the policy cannot be read":         {breakPolicy: true, wantDenied: true, wantReason: "the policy of the distro could not be read"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			system, mock := testutils.MockSystem(t)
+			path := mock.Path("/etc/wsl-pro-service/policy.conf")
+
+			if tc.breakPolicy {
+				commontestutils.ReplaceFileWithDir(t, path, "Setup: could not create directory to interfere with the policy")
+			} else if tc.policy != "" {
+				err := os.MkdirAll(filepath.Dir(path), 0700)
+				require.NoError(t, err, "Setup: could not create the policy directory")
+				err = os.WriteFile(path, []byte(tc.policy), 0600)
+				require.NoError(t, err, "Setup: could not write the policy")
+			}
+
+			ctrlClient, _ := newCtrlStream(t, ctx)
+			wslClient := setupWSLInstanceService(t, ctx, ctrlClient, system)
+
+			_, err := wslClient.Ping(ctx, &wslserviceapi.Empty{})
+			require.NoError(t, err, "Ping should always be allowed")
+
+			_, err = wslClient.ProRefresh(ctx, &wslserviceapi.Empty{})
+			if !tc.wantDenied {
+				require.NoError(t, err, "ProRefresh call should be allowed")
+				return
+			}
+			require.Error(t, err, "ProRefresh call should be denied")
+
+			denied, ok := wslserviceapi.AsPolicyDenied(err)
+			require.True(t, ok, "ProRefresh should be denied by the policy")
+			require.Equal(t, "ProRefresh", denied.GetCall(), "The denial should name the call")
+			require.Equal(t, tc.wantReason, denied.GetReason(), "The denial should carry the reason of the policy")
+		})
+	}
+}
+
 //nolint:revive // We've decided testing.T always preceedes the context.
 func setupWSLInstanceService(t *testing.T, ctx context.Context, ctrlClient wslinstanceservice.ControlStreamClient, s system.System) wslserviceapi.WSLClient {
 	t.Helper()
@@ -282,3 +936,5 @@ func TestWithLandscapeConfigMock(t *testing.T) { testutils.LandscapeConfigMock(t
 func TestWithWslPathMock(t *testing.T)         { testutils.WslPathMock(t) }
 func TestWithWslInfoMock(t *testing.T)         { testutils.WslInfoMock(t) }
 func TestWithCmdExeMock(t *testing.T)          { testutils.CmdExeMock(t) }
+func TestWithJournalctlMock(t *testing.T)      { testutils.JournalctlMock(t) }
+func TestWithUseraddMock(t *testing.T)         { testutils.UseraddMock(t) }
@@ -0,0 +1,24 @@
+package wslserviceapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// HashLandscapeConfig returns a hash of everything the distro uses from the message to configure
+// Landscape, so that the agent and the distro can tell whether the distro is already configured
+// with it. An empty configuration, which disables Landscape, has an empty hash.
+func HashLandscapeConfig(msg *LandscapeConfig) string {
+	if msg.GetConfiguration() == "" {
+		return ""
+	}
+
+	h := sha256.New()
+	// Length-prefixing the fields keeps them from being shifted into one another.
+	for _, field := range []string{msg.GetConfiguration(), msg.GetHostagentUID(), msg.GetComputerTitle()} {
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
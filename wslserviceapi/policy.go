@@ -0,0 +1,43 @@
+package wslserviceapi
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewPolicyDeniedError returns the error the WSL Pro service answers with when the policy of the distro
+// does not allow the call. It has the PERMISSION_DENIED status code and carries the PolicyDenied details,
+// so that the agent can tell it apart from other failures. See AsPolicyDenied.
+func NewPolicyDeniedError(call, reason string) error {
+	msg := fmt.Sprintf("%s is not allowed by the policy of the distro", call)
+	if reason != "" {
+		msg = fmt.Sprintf("%s: %s", msg, reason)
+	}
+
+	st, err := status.New(codes.PermissionDenied, msg).WithDetails(&PolicyDenied{Call: call, Reason: reason})
+	if err != nil {
+		// Only happens if the details cannot be marshalled, but the status code is still meaningful.
+		return status.Error(codes.PermissionDenied, msg)
+	}
+
+	return st.Err()
+}
+
+// AsPolicyDenied returns the details of the error if it was returned because the policy of the distro
+// does not allow the call, and false otherwise.
+func AsPolicyDenied(err error) (*PolicyDenied, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.PermissionDenied {
+		return nil, false
+	}
+
+	for _, d := range st.Details() {
+		if denied, ok := d.(*PolicyDenied); ok {
+			return denied, true
+		}
+	}
+
+	return nil, false
+}
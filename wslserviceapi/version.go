@@ -0,0 +1,5 @@
+package wslserviceapi
+
+// ProtocolVersion is the version of the API served by the WSL Pro service. It is increased every time a
+// change breaks the compatibility with the agent, so that mismatched components can be told apart.
+const ProtocolVersion = 1
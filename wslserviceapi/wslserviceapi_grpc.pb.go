@@ -19,9 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	WSL_ApplyProToken_FullMethodName        = "/wslserviceapi.WSL/ApplyProToken"
-	WSL_Ping_FullMethodName                 = "/wslserviceapi.WSL/Ping"
-	WSL_ApplyLandscapeConfig_FullMethodName = "/wslserviceapi.WSL/ApplyLandscapeConfig"
+	WSL_ApplyProToken_FullMethodName          = "/wslserviceapi.WSL/ApplyProToken"
+	WSL_Ping_FullMethodName                   = "/wslserviceapi.WSL/Ping"
+	WSL_ApplyLandscapeConfig_FullMethodName   = "/wslserviceapi.WSL/ApplyLandscapeConfig"
+	WSL_SetLocale_FullMethodName              = "/wslserviceapi.WSL/SetLocale"
+	WSL_ApplyWSLSettings_FullMethodName       = "/wslserviceapi.WSL/ApplyWSLSettings"
+	WSL_GetSecurityStatus_FullMethodName      = "/wslserviceapi.WSL/GetSecurityStatus"
+	WSL_GetResourceUsage_FullMethodName       = "/wslserviceapi.WSL/GetResourceUsage"
+	WSL_StreamJournal_FullMethodName          = "/wslserviceapi.WSL/StreamJournal"
+	WSL_ProRefresh_FullMethodName             = "/wslserviceapi.WSL/ProRefresh"
+	WSL_GetUpdateStatus_FullMethodName        = "/wslserviceapi.WSL/GetUpdateStatus"
+	WSL_GetLandscapeConfigHash_FullMethodName = "/wslserviceapi.WSL/GetLandscapeConfigHash"
+	WSL_GetFileTransferStatus_FullMethodName  = "/wslserviceapi.WSL/GetFileTransferStatus"
+	WSL_PushFile_FullMethodName               = "/wslserviceapi.WSL/PushFile"
+	WSL_PullFile_FullMethodName               = "/wslserviceapi.WSL/PullFile"
+	WSL_GetVersion_FullMethodName             = "/wslserviceapi.WSL/GetVersion"
 )
 
 // WSLClient is the client API for WSL service.
@@ -30,7 +42,19 @@ const (
 type WSLClient interface {
 	ApplyProToken(ctx context.Context, in *ProAttachInfo, opts ...grpc.CallOption) (*Empty, error)
 	Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
-	ApplyLandscapeConfig(ctx context.Context, in *LandscapeConfig, opts ...grpc.CallOption) (*Empty, error)
+	ApplyLandscapeConfig(ctx context.Context, in *LandscapeConfig, opts ...grpc.CallOption) (*LandscapeConfigResult, error)
+	SetLocale(ctx context.Context, in *Locale, opts ...grpc.CallOption) (*Empty, error)
+	ApplyWSLSettings(ctx context.Context, in *WSLSettings, opts ...grpc.CallOption) (*Empty, error)
+	GetSecurityStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SecurityStatus, error)
+	GetResourceUsage(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ResourceUsage, error)
+	StreamJournal(ctx context.Context, in *JournalRequest, opts ...grpc.CallOption) (WSL_StreamJournalClient, error)
+	ProRefresh(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	GetUpdateStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UpdateStatus, error)
+	GetLandscapeConfigHash(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LandscapeConfigHash, error)
+	GetFileTransferStatus(ctx context.Context, in *FileHeader, opts ...grpc.CallOption) (*FileTransferStatus, error)
+	PushFile(ctx context.Context, opts ...grpc.CallOption) (WSL_PushFileClient, error)
+	PullFile(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (WSL_PullFileClient, error)
+	GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionInfo, error)
 }
 
 type wSLClient struct {
@@ -59,8 +83,8 @@ func (c *wSLClient) Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption
 	return out, nil
 }
 
-func (c *wSLClient) ApplyLandscapeConfig(ctx context.Context, in *LandscapeConfig, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *wSLClient) ApplyLandscapeConfig(ctx context.Context, in *LandscapeConfig, opts ...grpc.CallOption) (*LandscapeConfigResult, error) {
+	out := new(LandscapeConfigResult)
 	err := c.cc.Invoke(ctx, WSL_ApplyLandscapeConfig_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -68,13 +92,204 @@ func (c *wSLClient) ApplyLandscapeConfig(ctx context.Context, in *LandscapeConfi
 	return out, nil
 }
 
+func (c *wSLClient) SetLocale(ctx context.Context, in *Locale, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, WSL_SetLocale_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wSLClient) ApplyWSLSettings(ctx context.Context, in *WSLSettings, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, WSL_ApplyWSLSettings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wSLClient) GetSecurityStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SecurityStatus, error) {
+	out := new(SecurityStatus)
+	err := c.cc.Invoke(ctx, WSL_GetSecurityStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wSLClient) GetResourceUsage(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ResourceUsage, error) {
+	out := new(ResourceUsage)
+	err := c.cc.Invoke(ctx, WSL_GetResourceUsage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wSLClient) StreamJournal(ctx context.Context, in *JournalRequest, opts ...grpc.CallOption) (WSL_StreamJournalClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WSL_ServiceDesc.Streams[0], WSL_StreamJournal_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &wSLStreamJournalClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WSL_StreamJournalClient interface {
+	Recv() (*JournalEntry, error)
+	grpc.ClientStream
+}
+
+type wSLStreamJournalClient struct {
+	grpc.ClientStream
+}
+
+func (x *wSLStreamJournalClient) Recv() (*JournalEntry, error) {
+	m := new(JournalEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *wSLClient) ProRefresh(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, WSL_ProRefresh_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wSLClient) GetUpdateStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UpdateStatus, error) {
+	out := new(UpdateStatus)
+	err := c.cc.Invoke(ctx, WSL_GetUpdateStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wSLClient) GetLandscapeConfigHash(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LandscapeConfigHash, error) {
+	out := new(LandscapeConfigHash)
+	err := c.cc.Invoke(ctx, WSL_GetLandscapeConfigHash_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wSLClient) GetFileTransferStatus(ctx context.Context, in *FileHeader, opts ...grpc.CallOption) (*FileTransferStatus, error) {
+	out := new(FileTransferStatus)
+	err := c.cc.Invoke(ctx, WSL_GetFileTransferStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wSLClient) PushFile(ctx context.Context, opts ...grpc.CallOption) (WSL_PushFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WSL_ServiceDesc.Streams[1], WSL_PushFile_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &wSLPushFileClient{stream}
+	return x, nil
+}
+
+type WSL_PushFileClient interface {
+	Send(*FileChunk) error
+	CloseAndRecv() (*FileTransferStatus, error)
+	grpc.ClientStream
+}
+
+type wSLPushFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *wSLPushFileClient) Send(m *FileChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *wSLPushFileClient) CloseAndRecv() (*FileTransferStatus, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(FileTransferStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *wSLClient) PullFile(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (WSL_PullFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WSL_ServiceDesc.Streams[2], WSL_PullFile_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &wSLPullFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WSL_PullFileClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type wSLPullFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *wSLPullFileClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *wSLClient) GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionInfo, error) {
+	out := new(VersionInfo)
+	err := c.cc.Invoke(ctx, WSL_GetVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WSLServer is the server API for WSL service.
 // All implementations must embed UnimplementedWSLServer
 // for forward compatibility
 type WSLServer interface {
 	ApplyProToken(context.Context, *ProAttachInfo) (*Empty, error)
 	Ping(context.Context, *Empty) (*Empty, error)
-	ApplyLandscapeConfig(context.Context, *LandscapeConfig) (*Empty, error)
+	ApplyLandscapeConfig(context.Context, *LandscapeConfig) (*LandscapeConfigResult, error)
+	SetLocale(context.Context, *Locale) (*Empty, error)
+	ApplyWSLSettings(context.Context, *WSLSettings) (*Empty, error)
+	GetSecurityStatus(context.Context, *Empty) (*SecurityStatus, error)
+	GetResourceUsage(context.Context, *Empty) (*ResourceUsage, error)
+	StreamJournal(*JournalRequest, WSL_StreamJournalServer) error
+	ProRefresh(context.Context, *Empty) (*Empty, error)
+	GetUpdateStatus(context.Context, *Empty) (*UpdateStatus, error)
+	GetLandscapeConfigHash(context.Context, *Empty) (*LandscapeConfigHash, error)
+	GetFileTransferStatus(context.Context, *FileHeader) (*FileTransferStatus, error)
+	PushFile(WSL_PushFileServer) error
+	PullFile(*FileRequest, WSL_PullFileServer) error
+	GetVersion(context.Context, *Empty) (*VersionInfo, error)
 	mustEmbedUnimplementedWSLServer()
 }
 
@@ -88,9 +303,45 @@ func (UnimplementedWSLServer) ApplyProToken(context.Context, *ProAttachInfo) (*E
 func (UnimplementedWSLServer) Ping(context.Context, *Empty) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
-func (UnimplementedWSLServer) ApplyLandscapeConfig(context.Context, *LandscapeConfig) (*Empty, error) {
+func (UnimplementedWSLServer) ApplyLandscapeConfig(context.Context, *LandscapeConfig) (*LandscapeConfigResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ApplyLandscapeConfig not implemented")
 }
+func (UnimplementedWSLServer) SetLocale(context.Context, *Locale) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLocale not implemented")
+}
+func (UnimplementedWSLServer) ApplyWSLSettings(context.Context, *WSLSettings) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyWSLSettings not implemented")
+}
+func (UnimplementedWSLServer) GetSecurityStatus(context.Context, *Empty) (*SecurityStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSecurityStatus not implemented")
+}
+func (UnimplementedWSLServer) GetResourceUsage(context.Context, *Empty) (*ResourceUsage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResourceUsage not implemented")
+}
+func (UnimplementedWSLServer) StreamJournal(*JournalRequest, WSL_StreamJournalServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamJournal not implemented")
+}
+func (UnimplementedWSLServer) ProRefresh(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProRefresh not implemented")
+}
+func (UnimplementedWSLServer) GetUpdateStatus(context.Context, *Empty) (*UpdateStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpdateStatus not implemented")
+}
+func (UnimplementedWSLServer) GetLandscapeConfigHash(context.Context, *Empty) (*LandscapeConfigHash, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLandscapeConfigHash not implemented")
+}
+func (UnimplementedWSLServer) GetFileTransferStatus(context.Context, *FileHeader) (*FileTransferStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileTransferStatus not implemented")
+}
+func (UnimplementedWSLServer) PushFile(WSL_PushFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method PushFile not implemented")
+}
+func (UnimplementedWSLServer) PullFile(*FileRequest, WSL_PullFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method PullFile not implemented")
+}
+func (UnimplementedWSLServer) GetVersion(context.Context, *Empty) (*VersionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
 func (UnimplementedWSLServer) mustEmbedUnimplementedWSLServer() {}
 
 // UnsafeWSLServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +409,236 @@ func _WSL_ApplyLandscapeConfig_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WSL_SetLocale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Locale)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).SetLocale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_SetLocale_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).SetLocale(ctx, req.(*Locale))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WSL_ApplyWSLSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WSLSettings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).ApplyWSLSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_ApplyWSLSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).ApplyWSLSettings(ctx, req.(*WSLSettings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WSL_GetSecurityStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).GetSecurityStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_GetSecurityStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).GetSecurityStatus(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WSL_GetResourceUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).GetResourceUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_GetResourceUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).GetResourceUsage(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WSL_StreamJournal_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JournalRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WSLServer).StreamJournal(m, &wSLStreamJournalServer{stream})
+}
+
+type WSL_StreamJournalServer interface {
+	Send(*JournalEntry) error
+	grpc.ServerStream
+}
+
+type wSLStreamJournalServer struct {
+	grpc.ServerStream
+}
+
+func (x *wSLStreamJournalServer) Send(m *JournalEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WSL_ProRefresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).ProRefresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_ProRefresh_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).ProRefresh(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WSL_GetUpdateStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).GetUpdateStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_GetUpdateStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).GetUpdateStatus(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WSL_GetLandscapeConfigHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).GetLandscapeConfigHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_GetLandscapeConfigHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).GetLandscapeConfigHash(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WSL_GetFileTransferStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileHeader)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).GetFileTransferStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_GetFileTransferStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).GetFileTransferStatus(ctx, req.(*FileHeader))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WSL_PushFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WSLServer).PushFile(&wSLPushFileServer{stream})
+}
+
+type WSL_PushFileServer interface {
+	SendAndClose(*FileTransferStatus) error
+	Recv() (*FileChunk, error)
+	grpc.ServerStream
+}
+
+type wSLPushFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *wSLPushFileServer) SendAndClose(m *FileTransferStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *wSLPushFileServer) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _WSL_PullFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WSLServer).PullFile(m, &wSLPullFileServer{stream})
+}
+
+type WSL_PullFileServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type wSLPullFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *wSLPullFileServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WSL_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WSLServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WSL_GetVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WSLServer).GetVersion(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WSL_ServiceDesc is the grpc.ServiceDesc for WSL service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,7 +658,59 @@ var WSL_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ApplyLandscapeConfig",
 			Handler:    _WSL_ApplyLandscapeConfig_Handler,
 		},
+		{
+			MethodName: "SetLocale",
+			Handler:    _WSL_SetLocale_Handler,
+		},
+		{
+			MethodName: "ApplyWSLSettings",
+			Handler:    _WSL_ApplyWSLSettings_Handler,
+		},
+		{
+			MethodName: "GetSecurityStatus",
+			Handler:    _WSL_GetSecurityStatus_Handler,
+		},
+		{
+			MethodName: "GetResourceUsage",
+			Handler:    _WSL_GetResourceUsage_Handler,
+		},
+		{
+			MethodName: "ProRefresh",
+			Handler:    _WSL_ProRefresh_Handler,
+		},
+		{
+			MethodName: "GetUpdateStatus",
+			Handler:    _WSL_GetUpdateStatus_Handler,
+		},
+		{
+			MethodName: "GetLandscapeConfigHash",
+			Handler:    _WSL_GetLandscapeConfigHash_Handler,
+		},
+		{
+			MethodName: "GetFileTransferStatus",
+			Handler:    _WSL_GetFileTransferStatus_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _WSL_GetVersion_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamJournal",
+			Handler:       _WSL_StreamJournal_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PushFile",
+			Handler:       _WSL_PushFile_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PullFile",
+			Handler:       _WSL_PullFile_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "wslserviceapi.proto",
 }